@@ -9,10 +9,7 @@ import (
 )
 
 func main() {
-	if err := app.Run(os.Args, os.Stdin); err != nil {
-		if skipExit := fsterr.Process(err, os.Args, os.Stdout); skipExit {
-			return
-		}
-		os.Exit(1)
+	if code := fsterr.ExitCode(app.Run(os.Args, os.Stdin), os.Args, os.Stdout); code != 0 {
+		os.Exit(code)
 	}
 }
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"crypto/ed25519"
 	"net/http"
 
@@ -15,6 +16,13 @@ type HTTPClient interface {
 	Do(*http.Request) (*http.Response, error)
 }
 
+// Resolver models a concrete net.Resolver. It's a consumer contract for
+// commands which inspect DNS, so that tests can avoid real lookups.
+type Resolver interface {
+	LookupCNAME(ctx context.Context, host string) (string, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
 // Interface models the methods of the Fastly API client that we use.
 // It exists to allow for easier testing, in combination with Mock.
 type Interface interface {
@@ -344,6 +352,7 @@ type Interface interface {
 	ListConfigStoreServices(i *fastly.ListConfigStoreServicesInput) ([]*fastly.Service, error)
 	UpdateConfigStore(i *fastly.UpdateConfigStoreInput) (*fastly.ConfigStore, error)
 
+	BatchModifyConfigStoreItems(i *fastly.BatchModifyConfigStoreItemsInput) error
 	CreateConfigStoreItem(i *fastly.CreateConfigStoreItemInput) (*fastly.ConfigStoreItem, error)
 	DeleteConfigStoreItem(i *fastly.DeleteConfigStoreItemInput) error
 	GetConfigStoreItem(i *fastly.GetConfigStoreItemInput) (*fastly.ConfigStoreItem, error)
@@ -389,9 +398,15 @@ type Interface interface {
 	ListConditions(i *fastly.ListConditionsInput) ([]*fastly.Condition, error)
 	UpdateCondition(i *fastly.UpdateConditionInput) (*fastly.Condition, error)
 
+	ListCacheSettings(i *fastly.ListCacheSettingsInput) ([]*fastly.CacheSetting, error)
+	ListGzips(i *fastly.ListGzipsInput) ([]*fastly.Gzip, error)
+	ListHeaders(i *fastly.ListHeadersInput) ([]*fastly.Header, error)
+
 	GetProduct(i *fastly.ProductEnablementInput) (*fastly.ProductEnablement, error)
 	EnableProduct(i *fastly.ProductEnablementInput) (*fastly.ProductEnablement, error)
 	DisableProduct(i *fastly.ProductEnablementInput) error
+
+	GetAPIEvents(i *fastly.GetAPIEventsFilterInput) (fastly.GetAPIEventsResponse, error)
 }
 
 // RealtimeStatsInterface is the subset of go-fastly's realtime stats API used here.
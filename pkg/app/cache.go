@@ -0,0 +1,26 @@
+package app
+
+import (
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/scope"
+)
+
+// configureCache applies --no-cache to the on-disk cache of API listings, and
+// reports the cached responses that are reused in verbose mode.
+//
+// A mutating command (see scope.Required) revalidates the listings it reads,
+// so a stale listing can't decide e.g. which service is updated. It's never
+// reset, so a workflow that runs a mutating step revalidates from then on.
+func configureCache(data *global.Data, command argparser.Command, commandName string) {
+	if data.APICache == nil {
+		return
+	}
+	data.APICache.Disabled = data.Flags.NoCache
+	if scope.Required(commandName, command) != "" {
+		data.APICache.Revalidate = true
+	}
+	if data.Verbose() {
+		data.APICache.SetOutput(data.Output)
+	}
+}
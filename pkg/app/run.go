@@ -1,16 +1,21 @@
 package app
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/fastly/go-fastly/v9/fastly"
 	"github.com/fastly/kingpin"
@@ -20,22 +25,35 @@ import (
 
 	"github.com/fastly/cli/pkg/api"
 	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/audit"
 	"github.com/fastly/cli/pkg/auth"
 	"github.com/fastly/cli/pkg/commands"
+	"github.com/fastly/cli/pkg/commands/alias"
 	"github.com/fastly/cli/pkg/commands/compute"
+	configcmd "github.com/fastly/cli/pkg/commands/config"
+	servicealias "github.com/fastly/cli/pkg/commands/service/alias"
 	"github.com/fastly/cli/pkg/commands/update"
 	"github.com/fastly/cli/pkg/commands/version"
 	"github.com/fastly/cli/pkg/config"
+	"github.com/fastly/cli/pkg/debug"
+	"github.com/fastly/cli/pkg/deprecation"
 	"github.com/fastly/cli/pkg/env"
 	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/events"
+	fstexec "github.com/fastly/cli/pkg/exec"
 	"github.com/fastly/cli/pkg/github"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/httpclient"
+	"github.com/fastly/cli/pkg/i18n"
+	"github.com/fastly/cli/pkg/interrupt"
 	"github.com/fastly/cli/pkg/lookup"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/profile"
 	"github.com/fastly/cli/pkg/revision"
 	"github.com/fastly/cli/pkg/sync"
 	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/cli/pkg/timing"
+	"github.com/fastly/cli/pkg/tracing"
 )
 
 // Run kick starts the CLI application.
@@ -54,24 +72,28 @@ var Init = func(args []string, stdin io.Reader) (*global.Data, error) {
 	// Parse the arguments provided by the user via the command-line interface.
 	args = args[1:]
 
-	// Define a HTTP client that will be used for making arbitrary HTTP requests.
-	httpClient := &http.Client{Timeout: time.Minute * 2}
-
 	// Define the standard input/output streams.
 	var (
 		in  io.Reader = stdin
-		out io.Writer = sync.NewWriter(color.Output)
+		out io.Writer = text.NewLineWriter(sync.NewWriter(color.Output))
 	)
 
+	// The deprecated flags, manifest keys and env vars used are recorded from
+	// the start, but only reported once the arguments are parsed (see Exec).
+	deprecations := deprecation.NewTracker()
+
 	// Read relevant configuration options from the user's environment.
 	var e config.Environment
-	e.Read(env.Parse(os.Environ()))
+	state := env.Parse(os.Environ())
+	deprecations.Environ(state)
+	e.Read(state)
 
 	// Identify verbose flag early (before Kingpin parser has executed) so we can
 	// print additional output related to the CLI configuration.
 	var verboseOutput bool
 	for _, seg := range args {
-		if seg == "-v" || seg == "--verbose" {
+		switch seg {
+		case "-v", "-vv", "-vvv", "--verbose", "--verbosity":
 			verboseOutput = true
 		}
 	}
@@ -89,28 +111,102 @@ var Init = func(args []string, stdin io.Reader) (*global.Data, error) {
 		}
 	}
 
+	// Identify the config file early (before Kingpin parser has executed) as
+	// it's read before the arguments are parsed.
+	configOverride := configFileFlag(args)
+	if configOverride == "" {
+		configOverride = e.ConfigFile
+	}
+	configPath, err := config.Path(configOverride)
+	if err != nil {
+		return nil, err
+	}
+
+	// It's the first time the CLI is run if there's no config file yet (it's
+	// created by cfg.Read), in which case a guided setup is offered when a
+	// command needs a token (see firstRunSetup).
+	_, statErr := os.Stat(configPath)
+	firstRun := errors.Is(statErr, fs.ErrNotExist)
+
 	// Extract a subset of configuration options from the local app directory.
 	var cfg config.File
 	cfg.SetAutoYes(autoYes)
 	cfg.SetNonInteractive(nonInteractive)
-	if err := cfg.Read(config.FilePath, in, out, fsterr.Log, verboseOutput); err != nil {
+	if err := cfg.Read(configPath, in, out, fsterr.Log, verboseOutput); err != nil {
+		return nil, err
+	}
+
+	// Cancel long-running operations when the user interrupts the CLI.
+	//
+	// NOTE: The signal handling lasts for the lifetime of the process.
+	ctx, _ := interrupt.NotifyContext(context.Background(), os.Stderr)
+
+	// Define a HTTP client that will be used for making arbitrary HTTP requests.
+	// The timeouts and proxy are configurable via the config file/environment.
+	httpOpts, err := httpclient.ParseOpts(e, cfg.HTTP)
+	if err != nil {
 		return nil, err
 	}
+	httpOpts.Context = ctx
+	httpClient := httpclient.New(httpOpts)
+
+	// The Fastly API client records unsuccessful responses so the API's request
+	// ID can be displayed alongside any resulting error. Repeated GET requests
+	// (e.g. resolving the same service from different code paths) are only sent
+	// once per invocation, and service and version listings are cached on disk
+	// for a short time so later invocations can reuse them (see
+	// httpclient.Cache). Requests that are sent are logged at the higher
+	// verbosity levels (see Exec). The Date header of the first response is
+	// used to detect a wrong system clock (see httpclient.ClockSkew). Responses
+	// are requested gzip compressed, which makes large listings much quicker
+	// to download on slow connections. When tracing is enabled (see Exec) each
+	// request that is sent is recorded as a span of the command's trace. Bulk
+	// commands count the connections their requests open and reuse (see
+	// httpclient.ConnTracker).
+	apiConns := &httpclient.ConnTracker{
+		Base:                httpClient.Transport,
+		MaxIdleConnsPerHost: httpOpts.MaxIdleConnsPerHost,
+		Proxy:               httpOpts.Proxy(),
+	}
+	apiCompression := &httpclient.Compression{Base: apiConns}
+	apiClock := &httpclient.ClockSkew{Base: apiCompression, Threshold: httpclient.DefaultClockSkewThreshold}
+	apiTrace := &debug.Transport{Base: apiClock}
+	apiDeadline := &httpclient.Deadline{Base: apiTrace}
+	apiTracing := &tracing.Transport{Base: apiDeadline}
+	apiCache := &httpclient.Cache{Base: apiTracing, Dir: filepath.Join(filepath.Dir(configPath), "cache", "api")}
+	apiMemo := &httpclient.Memo{Base: apiCache}
+	apiResponses := &httpclient.Recorder{Base: apiMemo}
+	apiHTTPClient := &http.Client{Transport: apiResponses}
+
+	// The output width is decided once, from the config file, the COLUMNS env
+	// var or the terminal, in that order (see text.DetectWidth).
+	width := text.DetectWidth(cfg.CLI.TerminalWidth, os.Getenv("COLUMNS"))
 
 	// Extract user's project configuration from the fastly.toml manifest.
 	var md manifest.Data
 	md.File.Args = args
 	md.File.SetErrLog(fsterr.Log)
 	md.File.SetOutput(out)
+	md.File.SetDeprecations(deprecations)
 
 	// NOTE: We skip handling the error because not all commands relate to Compute.
 	_ = md.File.Read(manifest.Filename)
 
+	// User can set env.DebugHTTP to log every API request/response to stderr.
+	// Request/response bodies are only logged when --verbose is also set.
+	debugHTTP, _ := strconv.ParseBool(e.DebugHTTP)
+
 	factory := func(token, endpoint string, debugMode bool) (api.Interface, error) {
 		client, err := fastly.NewClientForEndpoint(token, endpoint)
 		if debugMode {
 			client.DebugMode = true
 		}
+		if err == nil {
+			client.HTTPClient = apiHTTPClient
+		}
+		if err == nil && debugHTTP {
+			client.HTTPClient = debug.NewHTTPClient(client.HTTPClient, os.Stderr, verboseOutput)
+		}
 		return client, err
 	}
 
@@ -139,17 +235,37 @@ var Init = func(args []string, stdin io.Reader) (*global.Data, error) {
 	}
 
 	return &global.Data{
+		APICache:         apiCache,
 		APIClientFactory: factory,
+		APIClock:         apiClock,
+		APICompression:   apiCompression,
+		APIConns:         apiConns,
+		APIDeadline:      apiDeadline,
+		APIMemo:          apiMemo,
+		APIResponses:     apiResponses,
+		APITrace:         apiTrace,
+		APITracing:       apiTracing,
 		Args:             args,
+		AuditLogPath:     audit.LogPath,
 		Config:           cfg,
-		ConfigPath:       config.FilePath,
+		ConfigPath:       configPath,
+		Context:          ctx,
+		Deprecations:     deprecations,
 		Env:              e,
 		ErrLog:           fsterr.Log,
+		ErrOutput:        os.Stderr,
+		ExecuteEditor:    fstexec.Interactive,
+		ExecuteGit:       compute.ExecuteGit,
 		ExecuteWasmTools: compute.ExecuteWasmTools,
+		FirstRun:         firstRun,
 		HTTPClient:       httpClient,
+		IsTTY:            text.IsTTY,
 		Manifest:         &md,
 		Opener:           open.Run,
 		Output:           out,
+		Resolver:         net.DefaultResolver,
+		TerminalWidth:    &width,
+		Timings:          timing.New(nil),
 		Versioners:       versioners,
 		Input:            in,
 	}, nil
@@ -166,8 +282,49 @@ var Init = func(args []string, stdin io.Reader) (*global.Data, error) {
 // io.Writer. All error-related information should be encoded into an error type
 // and returned to the caller. This includes usage text.
 func Exec(data *global.Data) error {
+	if data.Context == nil {
+		data.Context = context.Background()
+	}
+
+	if data.Timings == nil {
+		data.Timings = timing.New(nil)
+	}
+
+	if data.Deprecations == nil {
+		data.Deprecations = deprecation.NewTracker()
+	}
+
+	// Don't leave the user's shell prompt mid-line or colored.
+	defer text.Finish(data.Output)
+
+	// NOTE: The theme and width aren't reset when Exec returns, as they also
+	// apply to the error the caller displays.
+	applyTheme(data)
+	applyTimeFormat(data)
+	applyLocale(data)
+	text.SetWidth(data.TerminalWidth)
+
 	app := configureKingpin(data)
 	cmds := commands.Define(app, data)
+
+	var commandNames []string
+	for _, c := range app.Model().Commands {
+		commandNames = append(commandNames, c.Name)
+	}
+	args, err := alias.Expand(data.Args, data.Config.Aliases, commandNames)
+	if err != nil {
+		return err
+	}
+	args, defaults, err := configcmd.ApplyDefaults(args, data.Config.CommandDefaults, app.Model())
+	if err != nil {
+		return err
+	}
+	args, serviceAlias, err := servicealias.Expand(args, data.Config.ServiceAliases, app.Model())
+	if err != nil {
+		return err
+	}
+	data.Args = args
+
 	command, commandName, err := processCommandInput(data, app, cmds)
 	if err != nil {
 		return err
@@ -186,6 +343,53 @@ func Exec(data *global.Data) error {
 		return nil
 	}
 
+	if data.Flags.MaxTime > 0 {
+		cancel := applyMaxTime(data)
+		defer cancel()
+	}
+	applyInterrupt(data, command)
+	defer text.SetContext(context.Background())
+
+	// User can set env.Quiet env var or the --quiet boolean flag.
+	if quietEnv, _ := strconv.ParseBool(data.Env.Quiet); quietEnv {
+		if data.Flags.Verbose || data.Flags.Verbosity > 0 {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("--verbose flag provided while %s is set", env.Quiet),
+				Remediation: fmt.Sprintf("Either remove the --verbose flag or unset %s.", env.Quiet),
+			}
+		}
+		if data.Verbosity() > 0 {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("%s and %s are both set", env.Verbosity, env.Quiet),
+				Remediation: fmt.Sprintf("Unset either %s or %s.", env.Verbosity, env.Quiet),
+			}
+		}
+		data.Flags.Quiet = true
+	}
+	if data.Flags.Quiet && data.Verbosity() > 0 {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("--quiet flag provided while %s is set", env.Verbosity),
+			Remediation: fmt.Sprintf("Either remove the --quiet flag or unset %s.", env.Verbosity),
+		}
+	}
+	// NOTE: Commands check Flags.Verbose so it must reflect every way of
+	// requesting verbose output (e.g. -vv or the environment). The level is
+	// resolved first, otherwise setting Flags.Verbose would mask the level set
+	// in the environment.
+	data.Flags.Verbosity = data.Verbosity()
+	data.Flags.Verbose = data.Verbose()
+	text.SetQuiet(data.Flags.Quiet)
+	defer text.SetQuiet(false)
+
+	if err := reportDeprecations(data, commandName); err != nil {
+		return err
+	}
+
+	if err := applyInteractive(data); err != nil {
+		return err
+	}
+	defer text.SetInteractive(text.InteractiveAuto)
+
 	metadataDisable, _ := strconv.ParseBool(data.Env.WasmMetadataDisable)
 	if !slices.Contains(data.Args, "--metadata-disable") && !metadataDisable && !data.Config.CLI.MetadataNoticeDisplayed && commandCollectsData(commandName) {
 		text.Important(data.Output, "The Fastly CLI is configured to collect data related to Wasm builds (e.g. compilation times, resource usage, and other non-identifying data). To learn more about what data is being collected, why, and how to disable it: https://developer.fastly.com/reference/cli/")
@@ -202,9 +406,19 @@ func Exec(data *global.Data) error {
 		data.Manifest.File.SetQuiet(true)
 	}
 
+	if len(defaults) > 0 && data.Verbose() {
+		text.Info(data.Output, "Applied default flags from the [command_defaults] config for '%s': %s", commandName, strings.Join(defaults, " "))
+	}
+	if serviceAlias != nil && data.Verbose() {
+		displayServiceAlias(serviceAlias, data.Output)
+	}
+
 	apiEndpoint, endpointSource := data.APIEndpoint()
 	if data.Verbose() {
 		displayAPIEndpoint(apiEndpoint, endpointSource, data.Output)
+		if data.TerminalWidth != nil {
+			fmt.Fprintf(data.Output, "Terminal width: %s\n", data.TerminalWidth)
+		}
 	}
 
 	// User can set env.DebugMode env var or the --debug-mode boolean flag.
@@ -241,31 +455,443 @@ func Exec(data *global.Data) error {
 		}
 
 		token, tokenSource, err := processToken(cmds, data)
+		if err == nil && tokenSource == lookup.SourceUndefined && data.FirstRun {
+			token, tokenSource, err = firstRunSetup(data, commandName)
+		}
 		if err != nil {
 			if errors.Is(err, fsterr.ErrDontContinue) {
 				return nil // we shouldn't exit 1 if user chooses to stop
 			}
 			return fmt.Errorf("failed to process token: %w", err)
 		}
+		if tokenSource == lookup.SourceFile {
+			if _, p, err := data.Profile(); err == nil {
+				data.Manifest.ProfileServiceID = p.ServiceID
+			}
+		}
 
 		if data.Verbose() {
 			displayToken(tokenSource, data)
 		}
 		if !data.Flags.Quiet {
-			checkConfigPermissions(commandName, tokenSource, data.Output)
+			checkConfigPermissions(commandName, tokenSource, data.ConfigPath, data.Output)
 		}
 
-		data.APIClient, data.RTSClient, err = configureClients(token, apiEndpoint, data.APIClientFactory, data.Flags.Debug)
+		var rtsClient api.RealtimeStatsInterface
+		data.APIClient, rtsClient, err = configureClients(token, apiEndpoint, data.APIClientFactory, data.Flags.Debug)
 		if err != nil {
 			data.ErrLog.Add(err)
 			return fmt.Errorf("error constructing client: %w", err)
 		}
+		// NOTE: Tests provide their own realtime stats client.
+		if data.RTSClient == nil {
+			data.RTSClient = rtsClient
+		}
 	}
 
-	f := checkForUpdates(data.Versioners.CLI, commandName, data.Flags.Quiet)
+	f := checkForUpdates(data, commandName)
 	defer f(data.Output)
 
-	return command.Exec(data.Input, data.Output)
+	if err := openEvents(data); err != nil {
+		return err
+	}
+	defer func() {
+		_ = data.Events.Close()
+	}()
+
+	configureCache(data, command, commandName)
+	if data.Verbosity() >= global.VerbosityRequests {
+		data.APIMemo.SetOutput(data.Output)
+		if data.APITrace != nil {
+			data.APITrace.Output = data.Output
+			data.APITrace.Summary = data.Verbosity() < global.VerbosityTrace
+			data.APITrace.Bodies = true
+		}
+	}
+
+	if data.CommandFlags == nil {
+		data.CommandFlags = commandFlags(data)
+	}
+	if data.PrepareCommand == nil {
+		data.PrepareCommand = prepareCommand(data)
+	}
+	if data.TokenSelf == nil {
+		data.TokenSelf = tokenSelf(data)
+	}
+	if err := checkScope(data, command, commandName); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	tracer := startTrace(data, commandName)
+	err = data.APIResponses.Annotate(maxTimeError(data, command.Exec(data.Input, data.Output)))
+	err = data.APIClock.Annotate(err)
+	err = annotateVersionLocked(data, commandName, err)
+	err = annotateValidation(data, command, commandName, err)
+	exportTrace(data, tracer, err)
+	printClockSkew(data)
+	printTimings(data)
+	if err == nil {
+		recordRecentService(data)
+	}
+	recordAudit(data, command, commandName, start, err)
+	return err
+}
+
+// applyInterrupt lets a command that watches data.Context stop by itself when
+// the user presses Ctrl-C, whereas any other command exits at once. Prompts are
+// abandoned when the context is cancelled.
+func applyInterrupt(data *global.Data, command argparser.Command) {
+	i, ok := command.(argparser.Interruptible)
+	interrupt.SetGraceful(ok && i.Interruptible())
+	text.SetContext(data.Context)
+}
+
+// applyTheme selects the color theme set by the environment or config file,
+// warning about an unknown theme (the default theme is used instead).
+func applyTheme(data *global.Data) {
+	name, source := data.Env.Theme, "by "+env.Theme
+	if name == "" {
+		name, source = data.Config.CLI.Theme, "in the config file"
+	}
+	if err := text.SetTheme(name); err != nil {
+		out := data.ErrOutput
+		if out == nil {
+			out = data.Output
+		}
+		text.Warning(out, "%s set %s, using the default theme (themes: %s).", err, source, strings.Join(text.ThemeNames(), ", "))
+	}
+}
+
+// applyLocale selects the locale messages are displayed in, as set by the
+// environment or config file, warning about a locale without a catalog (English
+// is used instead).
+func applyLocale(data *global.Data) {
+	name, source := data.Env.Locale, "by "+env.Locale
+	if name == "" {
+		name, source = data.Config.CLI.Locale, "in the config file"
+	}
+	if err := i18n.SetLocale(name); err != nil {
+		out := data.ErrOutput
+		if out == nil {
+			out = data.Output
+		}
+		text.Warning(out, "%s set %s, using English (locales: %s).", err, source, strings.Join(i18n.Locales(), ", "))
+	}
+}
+
+// applyTimeFormat selects how timestamps are displayed, as set by the
+// environment or config file, warning about an invalid format or timezone (the
+// default is used instead).
+func applyTimeFormat(data *global.Data) {
+	out := data.ErrOutput
+	if out == nil {
+		out = data.Output
+	}
+
+	format, source := data.Env.TimeFormat, "by "+env.TimeFormat
+	if format == "" {
+		format, source = data.Config.CLI.TimeFormat, "in the config file"
+	}
+	if err := text.SetTimeFormat(format); err != nil {
+		text.Warning(out, "%s set %s, using the default format (formats: %s, or a layout such as '%s').", err, source, strings.Join(text.TimeFormats, ", "), time.DateTime)
+	}
+
+	zone, source := data.Env.TimeZone, "by "+env.TimeZone
+	if zone == "" {
+		zone, source = data.Config.CLI.TimeZone, "in the config file"
+	}
+	if err := text.SetTimeZone(zone); err != nil {
+		text.Warning(out, "%s set %s, using UTC (timezones: %s, %s, or a name such as 'Europe/London').", err, source, text.TimeZoneUTC, text.TimeZoneLocal)
+	}
+}
+
+// applyInteractive resolves whether prompts are displayed. Prompts are disabled
+// in a CI environment unless --interactive is provided.
+//
+// NOTE: A CI environment only disables the prompts, so a prompt fails with an
+// error naming the flag to pass instead. Unlike --non-interactive it doesn't
+// answer them (e.g. as --auto-yes would).
+func applyInteractive(data *global.Data) error {
+	if data.Flags.Interactive && data.Flags.NonInteractive {
+		return fsterr.ErrInvalidInteractiveCombo
+	}
+	switch {
+	case data.Flags.Interactive:
+		text.SetInteractive(text.InteractiveAlways)
+	case data.Flags.NonInteractive:
+		text.SetInteractive(text.InteractiveNever)
+	case data.Env.CI != "":
+		text.SetInteractive(text.InteractiveNever)
+		if data.Verbose() {
+			text.Info(data.Output, "A CI environment was detected (%s is set) so prompts are disabled. Use --interactive to display them.", data.Env.CI)
+		}
+	default:
+		text.SetInteractive(text.InteractiveAuto)
+	}
+	return nil
+}
+
+// applyMaxTime bounds the invocation by the --max-time deadline: commands
+// observe it via data.Context, and API requests via data.APIDeadline.
+func applyMaxTime(data *global.Data) context.CancelFunc {
+	ctx, cancel := context.WithTimeoutCause(data.Context, data.Flags.MaxTime, fsterr.MaxTimeError{MaxTime: data.Flags.MaxTime})
+	data.Context = ctx
+	if data.APIDeadline != nil {
+		data.APIDeadline.Context = ctx
+	}
+	return cancel
+}
+
+// maxTimeError attributes a command's error to the --max-time deadline if it
+// was reached, so the error names the flag and the CLI exits with
+// fsterr.MaxTimeExitCode.
+func maxTimeError(data *global.Data, err error) error {
+	var mte fsterr.MaxTimeError
+	if err == nil || errors.As(err, &mte) || !errors.As(context.Cause(data.Context), &mte) {
+		return err
+	}
+	mte.Err = err
+	return mte
+}
+
+// printClockSkew displays the difference between the local clock and the
+// Fastly API's clock (if it was measured).
+func printClockSkew(data *global.Data) {
+	skew, ok := data.APIClock.Skew()
+	if !ok || data.Verbosity() < global.VerbosityRequests {
+		return
+	}
+	text.Break(data.Output)
+	text.Output(data.Output, "Clock skew (local time minus Fastly API time): %s", skew)
+	if data.APIClock.Exceeded() {
+		text.Warning(data.Output, "Your system clock is out of sync with the Fastly API by more than %s.", data.APIClock.Threshold)
+	}
+}
+
+// openEvents connects the progress event stream requested via --events (or
+// the environment). An emitter already set on data (e.g. by tests) is kept.
+func openEvents(data *global.Data) error {
+	target := data.Flags.Events
+	if target == "" {
+		target = data.Env.Events
+	}
+	if target == "" || data.Events != nil {
+		return nil
+	}
+	e, err := events.Open(target)
+	if err != nil {
+		data.ErrLog.Add(err)
+		return fsterr.RemediationError{
+			Inner:       err,
+			Remediation: fmt.Sprintf("Set --events (or %s) to a file descriptor opened by the parent process (3 or above) or the path of a listening unix socket.", env.Events),
+		}
+	}
+	data.Events = e
+	return nil
+}
+
+// annotateVersionLocked adds the command to re-run with --autoclone to the
+// remediation of an error caused by changing a locked or active service
+// version, if the command accepts --autoclone. Other errors are returned
+// unmodified.
+func annotateVersionLocked(data *global.Data, commandName string, err error) error {
+	if err == nil || !fsterr.IsVersionLocked(err) {
+		return err
+	}
+	re := fsterr.Deduce(err)
+	if data.CommandFlags == nil || slices.Contains(data.Args, "--autoclone") {
+		return re
+	}
+	flags, ferr := data.CommandFlags(commandName)
+	if ferr != nil || !slices.Contains(flags, "autoclone") {
+		return re
+	}
+	args := append(audit.RedactArgs(data.Args), "--autoclone")
+	re.Remediation += fmt.Sprintf("\n\nTo clone the version and make the changes to the clone, run:\n\n  fastly %s", shellJoin(args))
+	return re
+}
+
+// annotateValidation names the flags that supplied the fields the API
+// rejected with a 422 Unprocessable Entity, and in verbose mode displays the
+// API's response body before the error. Other errors are returned unmodified.
+func annotateValidation(data *global.Data, command argparser.Command, commandName string, err error) error {
+	if err == nil {
+		return err
+	}
+	re := fsterr.Deduce(err)
+	var ve fsterr.ValidationError
+	if !errors.As(re.Inner, &ve) {
+		return err
+	}
+	var flags []string
+	if data.CommandFlags != nil {
+		flags, _ = data.CommandFlags(commandName)
+	}
+	re.Inner = ve.WithFlags(func(field string) string {
+		return argparser.FlagForField(command, flags, field)
+	})
+	if data.Verbose() {
+		re.Prefix = "The Fastly API responded with:\n\n" + strings.TrimSpace(ve.Body)
+	}
+	return re
+}
+
+// shellJoin returns the arguments as a command line, quoting any argument the
+// shell would otherwise split or expand.
+func shellJoin(args []string) string {
+	quoted := make([]string, 0, len(args))
+	for _, a := range args {
+		if a != "" && !strings.ContainsFunc(a, func(r rune) bool {
+			return !(unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-_./:=@,+%", r))
+		}) {
+			quoted = append(quoted, a)
+			continue
+		}
+		quoted = append(quoted, "'"+strings.ReplaceAll(a, "'", `'\''`)+"'")
+	}
+	return strings.Join(quoted, " ")
+}
+
+// startTrace starts tracing the command if a trace file or collector endpoint
+// is set in the environment, returning nil otherwise.
+func startTrace(data *global.Data, commandName string) *tracing.Tracer {
+	if data.Env.TraceFile == "" && data.Env.TraceEndpoint == "" {
+		return nil
+	}
+	tracer := tracing.New(commandName, revision.AppVersion, data.Timings, nil)
+	if data.APITracing != nil {
+		data.APITracing.Tracer = tracer
+	}
+	return tracer
+}
+
+// exportTrace writes the command's trace to the file, and sends it to the
+// collector, set in the environment.
+//
+// NOTE: Tracing is a diagnostic aid, so failing to export a trace is logged
+// (and displayed in verbose mode) but doesn't change the command's outcome.
+func exportTrace(data *global.Data, tracer *tracing.Tracer, err error) {
+	if tracer == nil {
+		return
+	}
+	tracer.End(err)
+	if data.APITracing != nil {
+		data.APITracing.Tracer = nil
+	}
+
+	var errs []error
+	if path := data.Env.TraceFile; path != "" {
+		if err := tracer.WriteFile(path); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if endpoint := data.Env.TraceEndpoint; endpoint != "" && data.HTTPClient != nil {
+		if err := tracer.Send(data.Context, data.HTTPClient, endpoint); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	out := data.ErrOutput
+	if out == nil {
+		out = data.Output
+	}
+	for _, err := range errs {
+		data.ErrLog.Add(err)
+		if data.Verbose() {
+			text.Warning(out, "Failed to export the trace of the command: %s", err)
+		}
+	}
+	if len(errs) == 0 && data.Verbose() {
+		text.Info(out, "Exported trace %s.", tracer.TraceID())
+	}
+}
+
+// printTimings displays the duration of any phases recorded by the command.
+// In verbose mode a table is written to the command output, followed by the
+// size of the API responses, while in JSON mode a "timings" object is written
+// to the diagnostic output (so the command's JSON output remains valid).
+func printTimings(data *global.Data) {
+	if data.Verbose() {
+		transfer := data.APICompression.Transfer()
+		if data.Timings.Empty() && transfer.Responses == 0 {
+			return
+		}
+		text.Break(data.Output)
+		if !data.Timings.Empty() {
+			data.Timings.Print(data.Output)
+		}
+		if !data.Timings.Empty() && transfer.Responses > 0 {
+			text.Break(data.Output)
+		}
+		if transfer.Responses > 0 {
+			text.Output(data.Output, "API responses: %d (%s on the wire, %s decoded)", transfer.Responses, text.Bytes(float64(transfer.WireBytes)), text.Bytes(float64(transfer.DecodedBytes)))
+		}
+		return
+	}
+	if data.Timings.Empty() || data.ErrOutput == nil {
+		return
+	}
+	for _, arg := range data.Args {
+		if arg == "--json" || arg == "-j" {
+			_ = data.Timings.WriteJSON(data.ErrOutput)
+			return
+		}
+	}
+}
+
+// recordAudit appends the invocation of a mutating command to the audit log
+// (if enabled in the CLI config). A command that implements argparser.Mutator
+// reports whether it's mutating itself.
+//
+// NOTE: The audit log is best-effort so failing to write it is ignored.
+func recordAudit(data *global.Data, command argparser.Command, commandName string, start time.Time, err error) {
+	if !data.Config.Audit.Enabled || data.AuditLogPath == "" {
+		return
+	}
+	if m, ok := command.(argparser.Mutator); ok {
+		if !m.Mutating() {
+			return
+		}
+	} else if !audit.Mutating(commandName) {
+		return
+	}
+	e := audit.Entry{
+		Time:       start.UTC(),
+		Command:    commandName,
+		Args:       audit.RedactArgs(data.Args),
+		DurationMS: time.Since(start).Milliseconds(),
+		Outcome:    audit.OutcomeSuccess,
+	}
+	e.ServiceID, _ = data.Manifest.ServiceID()
+	for i, a := range data.Args {
+		if v, ok := strings.CutPrefix(a, "--version="); ok {
+			e.ServiceVersion = v
+		} else if a == "--version" && i+1 < len(data.Args) {
+			e.ServiceVersion = data.Args[i+1]
+		}
+	}
+	if err != nil {
+		e.Outcome = audit.OutcomeError
+		e.Error = fsterr.FilterToken(err.Error())
+	}
+	_ = audit.Record(data.AuditLogPath, e)
+}
+
+// recordRecentService remembers the service ID and name passed to a successful
+// command so they can be offered by shell completion without an API call.
+func recordRecentService(data *global.Data) {
+	var name string
+	for i, a := range data.Args {
+		if v, ok := strings.CutPrefix(a, "--"+argparser.FlagServiceName+"="); ok {
+			name = v
+		} else if a == "--"+argparser.FlagServiceName && i+1 < len(data.Args) {
+			name = data.Args[i+1]
+		}
+	}
+	if data.Config.Completion.RecordService(data.Manifest.Flag.ServiceID, name) {
+		// Shell completion is non-essential so failing to persist is ignored.
+		_ = data.Config.Write(data.ConfigPath)
+	}
 }
 
 func configureKingpin(data *global.Data) *kingpin.Application {
@@ -279,6 +905,7 @@ func configureKingpin(data *global.Data) *kingpin.Application {
 	app.UsageContext(&kingpin.UsageContext{
 		Template: VerboseUsageTemplate,
 		Funcs:    UsageTemplateFuncs,
+		Width:    usageWidth(),
 	})
 
 	// Prevent kingpin from calling os.Exit, this gives us greater control over
@@ -299,19 +926,69 @@ func configureKingpin(data *global.Data) *kingpin.Application {
 	app.Flag("account", "Fastly Accounts endpoint").Hidden().StringVar(&data.Flags.AccountEndpoint)
 	app.Flag("api", "Fastly API endpoint").Hidden().StringVar(&data.Flags.APIEndpoint)
 	app.Flag("auto-yes", "Answer yes automatically to all Yes/No confirmations. This may suppress security warnings").Short('y').BoolVar(&data.Flags.AutoYes)
+	configFileHelp := fmt.Sprintf("Use an alternative CLI config file, created if it doesn't exist (or via %s)", env.ConfigFile)
+	app.Flag("config-file", configFileHelp).StringVar(&data.Flags.ConfigFile)
 	// IMPORTANT: `--debug` is a built-in Kingpin flag so we must use `debug-mode`.
 	app.Flag("debug-mode", "Print API request and response details (NOTE: can disrupt the normal CLI flow output formatting)").BoolVar(&data.Flags.Debug)
 	// IMPORTANT: `--sso` causes a Kingpin runtime panic 🤦 so we use `enable-sso`.
 	app.Flag("enable-sso", "Enable Single-Sign On (SSO) for current profile execution (see also: 'fastly sso')").Hidden().BoolVar(&data.Flags.SSO)
+	eventsHelp := fmt.Sprintf("Write newline-delimited JSON progress events to a file descriptor (e.g. 3) or unix socket path (or via %s)", env.Events)
+	app.Flag("events", eventsHelp).StringVar(&data.Flags.Events)
+	app.Flag("interactive", "Display prompts even when a CI environment is detected or standard input isn't a terminal (e.g. to answer them from a pipe)").BoolVar(&data.Flags.Interactive)
+	app.Flag("max-time", "Stop the command after this duration (e.g. 30s, 5m), exiting with status 124. Listings display the results gathered so far").DurationVar(&data.Flags.MaxTime)
+	// NOTE: Kingpin parses a boolean flag prefixed with no- as its negation, so
+	// the flag's value is always false and it's recorded by its action instead.
+	app.Flag("no-cache", "Don't reuse the service and version listings cached by earlier commands (they're cached for up to a minute)").Action(func(_ *kingpin.ParseElement, _ *kingpin.ParseContext) error {
+		data.Flags.NoCache = true
+		return nil
+	}).Bool()
 	app.Flag("non-interactive", "Do not prompt for user input - suitable for CI processes. Equivalent to --accept-defaults and --auto-yes").Short('i').BoolVar(&data.Flags.NonInteractive)
 	app.Flag("profile", "Switch account profile for single command execution (see also: 'fastly profile switch')").Short('o').StringVar(&data.Flags.Profile)
-	app.Flag("quiet", "Silence all output except direct command output. This won't prevent interactive prompts (see: --accept-defaults, --auto-yes, --non-interactive)").Short('q').BoolVar(&data.Flags.Quiet)
+	quietHelp := fmt.Sprintf("Silence all output except direct command output (or via %s). This won't prevent interactive prompts (see: --accept-defaults, --auto-yes, --non-interactive)", env.Quiet)
+	app.Flag("quiet", quietHelp).Short('q').BoolVar(&data.Flags.Quiet)
+	app.Flag("skip-name-validation", "Send resource names to the API without checking them against its naming rules first (e.g. if the rules have been relaxed)").BoolVar(&data.Flags.SkipNameValidation)
+	app.Flag("skip-scope-check", "Don't check the API token has the scope a command needs before running it (e.g. if the token's scope can't be looked up)").BoolVar(&data.Flags.SkipScopeCheck)
+	strictHelp := fmt.Sprintf("Fail instead of warning when a deprecated flag, manifest key or environment variable is used, or when a command's checks find a likely mistake (e.g. misconfigured backend settings) (or via %s)", env.Strict)
+	app.Flag("strict", strictHelp).BoolVar(&data.Flags.Strict)
 	app.Flag("token", tokenHelp).HintAction(env.Vars).Short('t').StringVar(&data.Flags.Token)
-	app.Flag("verbose", "Verbose logging").Short('v').BoolVar(&data.Flags.Verbose)
+	app.Flag("verbose", "Verbose logging. Repeat for more detail: -vv logs API requests, -vvv also logs their (redacted) headers and bodies").Short('v').CounterVar(&data.Flags.Verbosity)
+	verbosityHelp := fmt.Sprintf("Verbose logging level from 0 to 3 (equivalent to repeating --verbose, or via %s)", env.Verbosity)
+	app.Flag("verbosity", verbosityHelp).IntVar(&data.Flags.Verbosity)
 
 	return app
 }
 
+// reportDeprecations warns about the deprecated items used so far (and any
+// used later), or fails in strict mode.
+//
+// NOTE: `fastly deprecations` lists the items in use, so it only warns.
+func reportDeprecations(data *global.Data, commandName string) error {
+	out := data.ErrOutput
+	if out == nil {
+		out = data.Output
+	}
+	return data.Deprecations.Start(out, data.Strict() && commandName != "deprecations")
+}
+
+// configFileFlag returns the value of the --config-file flag.
+//
+// NOTE: The flag is also defined in configureKingpin (for help output and to
+// be accepted by the parser), but the config is read before parsing.
+func configFileFlag(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if v, ok := strings.CutPrefix(arg, "--config-file="); ok {
+			return v
+		}
+		if arg == "--config-file" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
 // processToken handles all aspects related to the required API token.
 //
 // First we check if a profile token is defined in config and if so, we will
@@ -485,7 +1162,7 @@ func ssoAuthentication(outputMessage string, cmds []argparser.Command, data *glo
 				}
 				text.Important(data.Output, "%s. We need to open your browser to authenticate you.", outputMessage)
 				text.Break(data.Output)
-				cont, err := text.AskYesNo(data.Output, text.BoldYellow("Do you want to continue? [y/N]: "), data.Input)
+				cont, err := text.AskYesNo(data.Output, text.WarningStyle("Do you want to continue? [y/N]: "), data.Input, "--auto-yes")
 				text.Break(data.Output)
 				if err != nil {
 					return token, tokenSource, err
@@ -533,19 +1210,32 @@ func displayToken(tokenSource lookup.Source, data *global.Data) {
 // If we are using the token from config file, check the file's permissions
 // to assert if they are not too open or have been altered outside of the
 // application and warn if so.
-func checkConfigPermissions(commandName string, tokenSource lookup.Source, out io.Writer) {
+func checkConfigPermissions(commandName string, tokenSource lookup.Source, configPath string, out io.Writer) {
 	segs := strings.Split(commandName, " ")
 	if tokenSource == lookup.SourceFile && (len(segs) > 0 && segs[0] != "profile") {
-		if fi, err := os.Stat(config.FilePath); err == nil {
+		// NOTE: Only regular files are checked (e.g. not /dev/null).
+		if fi, err := os.Stat(configPath); err == nil && fi.Mode().IsRegular() {
 			if mode := fi.Mode().Perm(); mode > config.FilePermissions {
 				text.Warning(out, "Unprotected configuration file.\n\n")
-				text.Output(out, "Permissions for '%s' are too open\n\n", config.FilePath)
+				text.Output(out, "Permissions for '%s' are too open\n\n", configPath)
 				text.Output(out, "It is recommended that your configuration file is NOT accessible by others.\n\n")
 			}
 		}
 	}
 }
 
+// displayServiceAlias displays how the --service flag was resolved.
+func displayServiceAlias(r *servicealias.Resolution, out io.Writer) {
+	switch {
+	case r.Ignored:
+		text.Info(out, "Ignoring --%s %s as --%s was provided", servicealias.FlagName, r.Value, argparser.FlagServiceIDName)
+	case r.ServiceID != "":
+		text.Info(out, "Using service alias '%s' (%s)", r.Value, r.ServiceID)
+	default:
+		text.Info(out, "No service alias '%s' found, looking up the service by name", r.Value)
+	}
+}
+
 func displayAPIEndpoint(endpoint string, endpointSource lookup.Source, out io.Writer) {
 	switch endpointSource {
 	case lookup.SourceFlag:
@@ -575,13 +1265,36 @@ func configureClients(token, apiEndpoint string, acf global.APIClientFactory, de
 	return apiClient, rtsClient, nil
 }
 
-func checkForUpdates(av github.AssetVersioner, commandName string, quietMode bool) func(io.Writer) {
-	if av != nil && commandName != "update" && !version.IsPreRelease(revision.AppVersion) {
-		return update.CheckAsync(revision.AppVersion, av, quietMode)
+// checkForUpdates starts a background check for a new CLI version.
+//
+// The check is skipped entirely when it is disabled (config, env or --quiet),
+// when the output isn't a terminal or when JSON output was requested, so the
+// notice never corrupts machine readable output.
+func checkForUpdates(data *global.Data, commandName string) func(io.Writer) {
+	av := data.Versioners.CLI
+	if av == nil || commandName == "update" || strings.HasPrefix(commandName, "completion") || version.IsPreRelease(revision.AppVersion) || !updateNoticeEnabled(data) {
+		return func(_ io.Writer) {
+			// no-op
+		}
 	}
-	return func(_ io.Writer) {
-		// no-op
+	return update.CheckAsync(update.CheckOpts{
+		Config:         &data.Config,
+		ConfigPath:     data.ConfigPath,
+		CurrentVersion: revision.AppVersion,
+		Versioner:      av,
+	})
+}
+
+func updateNoticeEnabled(data *global.Data) bool {
+	for _, v := range []string{data.Env.NoUpdateCheck, data.Env.Offline} {
+		if disabled, _ := strconv.ParseBool(v); disabled {
+			return false
+		}
+	}
+	if data.Flags.Quiet || data.Config.UpdateCheck.Disabled || argparser.ArgsIsJSON(data.Args) {
+		return false
 	}
+	return data.IsTTY != nil && data.IsTTY(data.Output)
 }
 
 // determineProfile determines if the provided token was acquired via the
@@ -622,12 +1335,12 @@ func commandRequiresAuthServer(command string) bool {
 // requires an API token.
 func commandRequiresToken(command string) bool {
 	switch command {
-	case "compute init", "compute metadata", "compute serve":
+	case "compute init", "compute metadata", "compute serve", "compute status", "service alias remove":
 		return false
 	}
 	command = strings.Split(command, " ")[0]
 	switch command {
-	case "config", "profile", "update", "version":
+	case "alias", "completion", "config", "explain", "history", "profile", "support-bundle", "update", "version":
 		return false
 	}
 	return true
@@ -3,15 +3,34 @@ package app_test
 import (
 	"bufio"
 	"bytes"
+	stderrors "errors"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"testing/iotest"
+	"time"
 
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/api"
 	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/update"
+	"github.com/fastly/cli/pkg/config"
+	"github.com/fastly/cli/pkg/debug"
+	"github.com/fastly/cli/pkg/env"
 	"github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/httpclient"
+	"github.com/fastly/cli/pkg/i18n"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/revision"
 	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/text"
 )
 
 func TestShellCompletion(t *testing.T) {
@@ -61,8 +80,10 @@ complete -F _fastly_bash_autocomplete fastly
 			WantOutput: `help
 acl
 acl-entry
+alias
 auth-token
 backend
+completion
 compute
 config
 config-store
@@ -70,13 +91,17 @@ config-store-entry
 dictionary
 dictionary-entry
 domain
+events
+explain
 healthcheck
+history
 install
 ip-list
 kv-store
 kv-store-entry
 log-tail
 logging
+plan
 pops
 products
 profile
@@ -89,11 +114,14 @@ service
 service-auth
 service-version
 stats
+support-bundle
 tls-config
 tls-custom
+tls-expiry
 tls-platform
 tls-subscription
 update
+usage
 user
 vcl
 version
@@ -141,6 +169,192 @@ whoami
 	}
 }
 
+func TestUpdateNotice(t *testing.T) {
+	defer func(v string) { revision.AppVersion = v }(revision.AppVersion)
+	revision.AppVersion = "1.0.0"
+
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	defer func(fn func() time.Time) { update.Now = fn }(update.Now)
+	update.Now = func() time.Time { return now }
+
+	notice := "A new version of the Fastly CLI is available (1.0.0 -> 2.0.0)"
+
+	scenarios := []struct {
+		name       string
+		args       string
+		env        config.Environment
+		tty        bool
+		lastCheck  time.Time
+		wantNotice bool
+	}{
+		{
+			name:       "notice after command output",
+			args:       "profile list",
+			tty:        true,
+			wantNotice: true,
+		},
+		{
+			name: "suppressed in JSON mode",
+			args: "profile list --json",
+			tty:  true,
+		},
+		{
+			name: "suppressed when not a terminal",
+			args: "profile list",
+		},
+		{
+			name: "suppressed by env",
+			args: "profile list",
+			env:  config.Environment{NoUpdateCheck: "true"},
+			tty:  true,
+		},
+		{
+			name: "suppressed offline",
+			args: "profile list",
+			env:  config.Environment{Offline: "1"},
+			tty:  true,
+		},
+		{
+			name:      "checked within the interval",
+			args:      "profile list",
+			tty:       true,
+			lastCheck: now.Add(-time.Hour),
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			args := testutil.Args(testcase.args)
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.Env = testcase.env
+				opts.IsTTY = func(_ any) bool { return testcase.tty }
+				opts.Versioners = global.Versioners{CLI: mock.AssetVersioner{AssetVersion: "2.0.0"}}
+				if !testcase.lastCheck.IsZero() {
+					opts.Config.UpdateCheck.LastChecked = testcase.lastCheck.Format(time.RFC3339)
+				}
+				return opts, nil
+			}
+			err := app.Run(args, nil)
+			testutil.AssertNoError(t, err)
+
+			output := stdout.String()
+			if has := strings.Contains(output, notice); has != testcase.wantNotice {
+				t.Fatalf("want notice %v, have output:\n%s", testcase.wantNotice, output)
+			}
+			if testcase.wantNotice && !strings.HasSuffix(strings.TrimSpace(output), "upgrade.") {
+				t.Errorf("want notice after command output, have:\n%s", output)
+			}
+		})
+	}
+}
+
+func TestVerbosity(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"123","name":"Foo","type":"vcl"}`))
+	}))
+	defer ts.Close()
+
+	const (
+		provenance = "Service ID (via --service-id): 123"
+		request    = "[fastly-debug-http] GET /service/123/details 200 ("
+		header     = "[fastly-debug-http]     Fastly-Key: REDACTED"
+		body       = `[fastly-debug-http]     body: {"id":"123"`
+	)
+
+	scenarios := []struct {
+		name      string
+		args      string
+		env       config.Environment
+		want      []string
+		dontWant  []string
+		wantError string
+	}{
+		{
+			name:     "default",
+			args:     "service describe --service-id 123",
+			dontWant: []string{provenance, request, header, body},
+		},
+		{
+			name:     "level 1",
+			args:     "service describe --service-id 123 -v",
+			want:     []string{provenance},
+			dontWant: []string{request, header, body},
+		},
+		{
+			name:     "level 2",
+			args:     "service describe --service-id 123 -vv",
+			want:     []string{provenance, request},
+			dontWant: []string{header, body},
+		},
+		{
+			name: "level 3",
+			args: "service describe --service-id 123 --verbosity 3",
+			want: []string{provenance, header, body},
+		},
+		{
+			name:     "level from environment",
+			args:     "service describe --service-id 123",
+			env:      config.Environment{Verbosity: "2"},
+			want:     []string{provenance, request},
+			dontWant: []string{header, body},
+		},
+		{
+			name:     "flag takes precedence over environment",
+			args:     "service describe --service-id 123 --verbose",
+			env:      config.Environment{Verbosity: "3"},
+			want:     []string{provenance},
+			dontWant: []string{request, header, body},
+		},
+		{
+			name:      "quiet conflicts with any level",
+			args:      "service describe --service-id 123 -vv --quiet",
+			wantError: "--verbose and --quiet flag provided",
+		},
+		{
+			name:      "quiet conflicts with level from environment",
+			args:      "service describe --service-id 123 --quiet",
+			env:       config.Environment{Verbosity: "1"},
+			wantError: "--quiet flag provided while FASTLY_VERBOSITY is set",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			args := testutil.Args(testcase.args)
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.Env = testcase.env
+				opts.APITrace = &debug.Transport{Base: ts.Client().Transport}
+				opts.APIClientFactory = func(token, _ string, _ bool) (api.Interface, error) {
+					client, err := fastly.NewClientForEndpoint(token, ts.URL)
+					if err == nil {
+						client.HTTPClient = &http.Client{Transport: opts.APITrace}
+					}
+					return client, err
+				}
+				return opts, nil
+			}
+			err := app.Run(args, nil)
+			if testcase.wantError != "" {
+				testutil.AssertErrorContains(t, err, testcase.wantError)
+				return
+			}
+			testutil.AssertNoError(t, err)
+
+			output := stdout.String()
+			testutil.AssertStringContains(t, output, "Name: Foo")
+			for _, s := range testcase.want {
+				testutil.AssertStringContains(t, output, s)
+			}
+			for _, s := range testcase.dontWant {
+				testutil.AssertStringDoesntContain(t, output, s)
+			}
+		})
+	}
+}
+
 // stripTrailingSpace removes any trailing spaces from the multiline str.
 func stripTrailingSpace(str string) string {
 	buf := bytes.NewBuffer(nil)
@@ -152,3 +366,1092 @@ func stripTrailingSpace(str string) string {
 	}
 	return buf.String()
 }
+
+// defaultInit is the real app.Init, as tests replace it with a mock.
+var defaultInit = app.Init
+
+// TestConfigFileOverride runs the real app.Init, so that the config file is
+// read from (and written to) the --config-file/FASTLY_CONFIG_FILE override.
+func TestConfigFileOverride(t *testing.T) {
+	// Isolate the CLI from the environment of whoever runs the tests.
+	t.Setenv(env.APIToken, "")
+	t.Setenv(env.ConfigFile, "")
+	t.Setenv(env.Events, "")
+	t.Setenv(env.NoUpdateCheck, "true")
+
+	defaultData, defaultErr := os.ReadFile(config.FilePath)
+
+	run := func(t *testing.T, args, stdin string) (string, error) {
+		t.Helper()
+		var stdout bytes.Buffer
+		app.Init = func(args []string, in io.Reader) (*global.Data, error) {
+			data, err := defaultInit(args, in)
+			if err != nil {
+				return nil, err
+			}
+			data.APIClientFactory = mock.APIClient(mock.API{
+				GetTokenSelfFn: func() (*fastly.Token, error) {
+					return &fastly.Token{UserID: fastly.ToPointer("456")}, nil
+				},
+				GetUserFn: func(i *fastly.GetUserInput) (*fastly.User, error) {
+					return &fastly.User{UserID: fastly.ToPointer(i.UserID), Login: fastly.ToPointer("foo@example.com")}, nil
+				},
+			})
+			data.AuthServer = &testutil.MockAuthServer{}
+			data.Output = &stdout
+			return data, nil
+		}
+		// NOTE: The real Init expects the binary name (as in os.Args).
+		err := app.Run(testutil.Args("fastly "+args), strings.NewReader(stdin))
+		t.Log(stdout.String())
+		return stdout.String(), err
+	}
+
+	t.Run("flag", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "nested", "fastly", "config.toml")
+
+		out, err := run(t, "profile create flag-profile --config-file "+path, "some_token\n")
+		testutil.AssertNoError(t, err)
+		testutil.AssertStringContains(t, out, path)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testutil.AssertStringContains(t, string(data), "[profile.flag-profile]")
+
+		out, err = run(t, "profile list --config-file="+path, "")
+		testutil.AssertNoError(t, err)
+		testutil.AssertStringContains(t, out, "flag-profile")
+
+		out, err = run(t, "config --config-file "+path, "")
+		testutil.AssertNoError(t, err)
+		testutil.AssertStringContains(t, out, "# "+path+" (via --config-file)")
+
+		// The API listings are cached alongside the config file in use.
+		g, err := defaultInit(testutil.Args("fastly version --config-file "+path), strings.NewReader(""))
+		testutil.AssertNoError(t, err)
+		testutil.AssertString(t, filepath.Join(filepath.Dir(path), "cache", "api"), g.APICache.Dir)
+	})
+
+	t.Run("env", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		t.Setenv(env.ConfigFile, path)
+
+		_, err := run(t, "profile create env-profile", "some_token\n")
+		testutil.AssertNoError(t, err)
+
+		out, err := run(t, "profile list", "")
+		testutil.AssertNoError(t, err)
+		testutil.AssertStringContains(t, out, "env-profile")
+		if strings.Contains(out, "flag-profile") {
+			t.Errorf("want only the profiles of %s, have:\n%s", path, out)
+		}
+
+		out, err = run(t, "config --location", "")
+		testutil.AssertNoError(t, err)
+		testutil.AssertString(t, path+"\n", out)
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		_, err := run(t, "profile list --config-file "+t.TempDir(), "")
+		testutil.AssertErrorContains(t, err, "is a directory")
+	})
+
+	data, err := os.ReadFile(config.FilePath)
+	if (err == nil) != (defaultErr == nil) || !bytes.Equal(data, defaultData) {
+		t.Errorf("want the default config file (%s) to be untouched", config.FilePath)
+	}
+}
+
+// TestMaxTime validates a command is stopped at the --max-time deadline
+// without making changes it hadn't started, while a change that's already in
+// flight is left to complete.
+func TestMaxTime(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []string
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received = append(received, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		switch r.URL.Path {
+		case "/service/123/version":
+			_, _ = w.Write([]byte(`[{"number": 1, "service_id": "123"}]`))
+		default:
+			_, _ = w.Write([]byte(`{"id": "123", "name": "foo"}`))
+		}
+	}))
+	defer ts.Close()
+
+	scenarios := []struct {
+		name         string
+		args         string
+		wantError    bool
+		wantReceived []string
+	}{
+		{
+			name:         "read cancelled before a change",
+			args:         "service-version clone --service-id 123 --version latest --max-time 50ms",
+			wantError:    true,
+			wantReceived: []string{"GET /service/123/version"},
+		},
+		{
+			name:         "change in flight completes",
+			args:         "service update --service-id 123 --name foo --max-time 50ms",
+			wantReceived: []string{"PUT /service/123"},
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			mu.Lock()
+			received = nil
+			mu.Unlock()
+
+			args := testutil.Args(testcase.args)
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIDeadline = &httpclient.Deadline{Base: ts.Client().Transport}
+				opts.APIClientFactory = func(token, _ string, _ bool) (api.Interface, error) {
+					client, err := fastly.NewClientForEndpoint(token, ts.URL)
+					if err == nil {
+						client.HTTPClient = &http.Client{Transport: opts.APIDeadline}
+					}
+					return client, err
+				}
+				return opts, nil
+			}
+			err := app.Run(args, nil)
+
+			if testcase.wantError {
+				var mte errors.MaxTimeError
+				if !stderrors.As(err, &mte) {
+					t.Fatalf("want an errors.MaxTimeError, have %#v", err)
+				}
+				testutil.AssertEqual(t, errors.MaxTimeExitCode, errors.ExitCode(err, args, io.Discard))
+				testutil.AssertStringContains(t, errors.Deduce(err).Remediation, "--max-time")
+			} else {
+				testutil.AssertNoError(t, err)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			testutil.AssertEqual(t, testcase.wantReceived, received)
+		})
+	}
+}
+
+// TestTheme validates the theme is selected by the environment (taking
+// precedence over the config file), and an unknown theme is warned about.
+func TestTheme(t *testing.T) {
+	defer func() {
+		_ = text.SetTheme(text.DefaultThemeName)
+	}()
+
+	scenarios := []struct {
+		name      string
+		env       string
+		config    string
+		wantTheme string
+		wantWarn  string
+	}{
+		{
+			name:      "default",
+			wantTheme: text.DefaultThemeName,
+		},
+		{
+			name:      "config",
+			config:    "monochrome-bold",
+			wantTheme: "monochrome-bold",
+		},
+		{
+			name:      "environment over config",
+			env:       "colorblind-safe",
+			config:    "monochrome-bold",
+			wantTheme: "colorblind-safe",
+		},
+		{
+			name:      "unknown",
+			env:       "neon",
+			wantTheme: text.DefaultThemeName,
+			wantWarn:  "unknown theme 'neon' set by FASTLY_THEME, using the default theme (themes: default, colorblind-safe, monochrome-bold)",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			args := testutil.Args("config --location")
+			var stdout, stderr bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.Env.Theme = testcase.env
+				opts.Config.CLI.Theme = testcase.config
+				opts.ErrOutput = &stderr
+				return opts, nil
+			}
+			testutil.AssertNoError(t, app.Run(args, nil))
+			testutil.AssertString(t, testcase.wantTheme, text.CurrentTheme().Name)
+			if testcase.wantWarn != "" {
+				testutil.AssertStringContains(t, stderr.String(), testcase.wantWarn)
+			} else {
+				testutil.AssertString(t, "", stderr.String())
+			}
+		})
+	}
+}
+
+// TestTerminalWidth validates the output width decided for the invocation is
+// applied, and displayed in verbose mode.
+func TestTerminalWidth(t *testing.T) {
+	defer text.SetWidth(nil)
+
+	scenarios := []struct {
+		name       string
+		width      *text.Width
+		wantOutput string
+		wantProse  uint
+	}{
+		{
+			name: "not decided",
+		},
+		{
+			name:       "COLUMNS",
+			width:      &text.Width{Columns: 100, Source: text.WidthSourceColumns},
+			wantOutput: "Terminal width: 100 columns (via COLUMNS)",
+			wantProse:  100,
+		},
+		{
+			name:       "detection failed",
+			width:      &text.Width{Source: text.WidthSourceUnknown, Err: stderrors.New("inappropriate ioctl for device")},
+			wantOutput: "Terminal width: unknown (wrapping at 80 columns, tables unbounded): inappropriate ioctl for device",
+			wantProse:  text.FallbackTextWidth,
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			args := testutil.Args("config --location --verbose")
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.TerminalWidth = testcase.width
+				return opts, nil
+			}
+			testutil.AssertNoError(t, app.Run(args, nil))
+			if testcase.wantOutput != "" {
+				testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			} else if strings.Contains(stdout.String(), "Terminal width") {
+				t.Errorf("want no terminal width, have:\n%s", stdout.String())
+			}
+			w, ok := text.CurrentWidth()
+			testutil.AssertEqual(t, testcase.width != nil, ok)
+			if ok {
+				testutil.AssertEqual(t, testcase.wantProse, w.Prose())
+			}
+		})
+	}
+}
+
+// TestTimeFormat validates the time format and timezone are selected by the
+// environment (taking precedence over the config file), and an invalid format
+// or unknown timezone is warned about.
+func TestTimeFormat(t *testing.T) {
+	defer func() {
+		_ = text.SetTimeFormat("")
+		_ = text.SetTimeZone("")
+	}()
+
+	ts := time.Date(2021, 6, 15, 23, 0, 0, 0, time.UTC)
+	scenarios := []struct {
+		name       string
+		envFormat  string
+		envZone    string
+		format     string
+		zone       string
+		wantFormat string
+		wantWarn   string
+	}{
+		{
+			name:       "default",
+			wantFormat: "2021-06-15 23:00:00 +0000 UTC",
+		},
+		{
+			name:       "config",
+			format:     "rfc3339",
+			zone:       "Asia/Tokyo",
+			wantFormat: "2021-06-16T08:00:00+09:00",
+		},
+		{
+			name:       "environment over config",
+			envFormat:  time.DateTime,
+			envZone:    "utc",
+			format:     "rfc3339",
+			zone:       "Asia/Tokyo",
+			wantFormat: "2021-06-15 23:00:00",
+		},
+		{
+			name:       "invalid format",
+			envFormat:  "YYYY-MM-DD",
+			wantFormat: "2021-06-15 23:00:00 +0000 UTC",
+			wantWarn:   "invalid time format 'YYYY-MM-DD' set by FASTLY_TIME_FORMAT, using the default format (formats: default, rfc3339, relative, or a layout such as '2006-01-02 15:04:05')",
+		},
+		{
+			name:       "unknown timezone",
+			zone:       "Mars/Olympus",
+			wantFormat: "2021-06-15 23:00:00 +0000 UTC",
+			wantWarn:   "unknown time zone 'Mars/Olympus' set in the config file, using UTC (timezones: utc, local, or a name such as 'Europe/London')",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			args := testutil.Args("config --location")
+			var stdout, stderr bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.Env.TimeFormat = testcase.envFormat
+				opts.Env.TimeZone = testcase.envZone
+				opts.Config.CLI.TimeFormat = testcase.format
+				opts.Config.CLI.TimeZone = testcase.zone
+				opts.ErrOutput = &stderr
+				return opts, nil
+			}
+			testutil.AssertNoError(t, app.Run(args, nil))
+			testutil.AssertString(t, testcase.wantFormat, text.FormatTime(&ts))
+			if testcase.wantWarn != "" {
+				testutil.AssertStringContains(t, strings.Join(strings.Fields(stderr.String()), " "), testcase.wantWarn)
+			} else {
+				testutil.AssertString(t, "", stderr.String())
+			}
+		})
+	}
+}
+
+// TestLocale validates the locale is selected by the environment (taking
+// precedence over the config file), and a locale without a catalog is warned
+// about.
+func TestLocale(t *testing.T) {
+	defer func() {
+		_ = i18n.SetLocale("")
+	}()
+
+	scenarios := []struct {
+		name       string
+		env        string
+		config     string
+		wantLocale string
+		wantWarn   string
+	}{
+		{
+			name:       "default",
+			wantLocale: i18n.DefaultLocale,
+		},
+		{
+			name:       "config",
+			config:     "fr",
+			wantLocale: "fr",
+		},
+		{
+			name:       "environment over config",
+			env:        "en_US.UTF-8",
+			config:     "fr",
+			wantLocale: i18n.DefaultLocale,
+		},
+		{
+			name:       "unknown",
+			env:        "xx",
+			wantLocale: i18n.DefaultLocale,
+			wantWarn:   "unknown locale 'xx' set by FASTLY_LOCALE, using English (locales: en, fr)",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			args := testutil.Args("config --location")
+			var stdout, stderr bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.Env.Locale = testcase.env
+				opts.Config.CLI.Locale = testcase.config
+				opts.ErrOutput = &stderr
+				return opts, nil
+			}
+			testutil.AssertNoError(t, app.Run(args, nil))
+			testutil.AssertString(t, testcase.wantLocale, i18n.Locale())
+			if testcase.wantWarn != "" {
+				testutil.AssertStringContains(t, strings.Join(strings.Fields(stderr.String()), " "), testcase.wantWarn)
+			} else {
+				testutil.AssertString(t, "", stderr.String())
+			}
+		})
+	}
+}
+
+// TestVersionLocked validates a change to a locked or active service version
+// fails before any changes are made, with the command to re-run with
+// --autoclone.
+func TestVersionLocked(t *testing.T) {
+	args := append(testutil.Args("backend create --service-id 123 --version 1 --address example.com --token 123 --name"), "www test")
+	api := mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		CreateBackendFn: func(*fastly.CreateBackendInput) (*fastly.Backend, error) {
+			t.Fatal("unexpected change to a locked version")
+			return nil, nil
+		},
+	}
+	var stdout bytes.Buffer
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		opts := testutil.MockGlobalData(args, &stdout)
+		opts.APIClientFactory = mock.APIClient(api)
+		return opts, nil
+	}
+	err := app.Run(args, nil)
+	testutil.AssertErrorContains(t, err, "service version 1 is not editable")
+	testutil.AssertBool(t, true, errors.IsVersionLocked(err))
+
+	re := errors.Deduce(err)
+	testutil.AssertEqual(t, errors.CodeVersionLocked, re.Code)
+	testutil.AssertString(t, errors.AutoCloneRemediation+`
+
+To clone the version and make the changes to the clone, run:
+
+  fastly backend create --service-id 123 --version 1 --address example.com --token REDACTED --name 'www test' --autoclone`, re.Remediation)
+}
+
+// TestValidation validates the fields the API rejects with a 422 are displayed
+// with the flags that supplied them, and an unrecognised response body is
+// displayed as before.
+func TestValidation(t *testing.T) {
+	rejected := func(body string) error {
+		return &fastly.HTTPError{
+			StatusCode: http.StatusUnprocessableEntity,
+			Errors:     []*fastly.ErrorObject{{Title: "Undefined error", Detail: body}},
+		}
+	}
+	scenarios := []struct {
+		name        string
+		args        string
+		body        string
+		wantError   string
+		wantCode    errors.Code
+		wantPrefix  string
+		wantMissing string
+	}{
+		{
+			name: "derived flags",
+			args: "backend update --service-id 123 --version 3 --name www --new-name api --max-conn 0",
+			body: `{"msg":"Record invalid","errors":{"name":["has already been taken"],"max_conn":["must be greater than 0"],"ipv4":["is not a valid address"]}}`,
+			wantError: `the Fastly API returned 422 Unprocessable Entity:
+
+  invalid value for field 'ipv4': is not a valid address
+  invalid value for --max-conn: must be greater than 0
+  invalid value for --new-name: has already been taken`,
+			wantCode: errors.CodeValidation,
+		},
+		{
+			name:      "registered flags",
+			args:      "dictionary-entry update --service-id 123 --dictionary-id 456 --key foo --value bar --ignore-limits",
+			body:      `{"errors":{"item_key":["is too long"],"item_value":"is too long"}}`,
+			wantError: "invalid value for --key: is too long\n  invalid value for --value: is too long",
+			wantCode:  errors.CodeValidation,
+		},
+		{
+			name:       "verbose",
+			args:       "dictionary-entry update --service-id 123 --dictionary-id 456 --key foo --value bar --ignore-limits --verbose",
+			body:       `{"errors":{"item_key":["is too long"]}}`,
+			wantError:  "invalid value for --key: is too long",
+			wantCode:   errors.CodeValidation,
+			wantPrefix: "The Fastly API responded with:\n\n" + `{"errors":{"item_key":["is too long"]}}`,
+		},
+		{
+			name:        "unrecognised body",
+			args:        "dictionary-entry update --service-id 123 --dictionary-id 456 --key foo --value bar --ignore-limits",
+			body:        `{"msg":"Bad request","detail":"Value is invalid"}`,
+			wantError:   `the Fastly API returned 422 Unprocessable Entity: Undefined error ({"msg":"Bad request","detail":"Value is invalid"})`,
+			wantCode:    errors.CodeBug,
+			wantMissing: "invalid value for",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			args := testutil.Args(testcase.args)
+			api := mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				UpdateBackendFn: func(*fastly.UpdateBackendInput) (*fastly.Backend, error) {
+					return nil, rejected(testcase.body)
+				},
+				UpdateDictionaryItemFn: func(*fastly.UpdateDictionaryItemInput) (*fastly.DictionaryItem, error) {
+					return nil, rejected(testcase.body)
+				},
+			}
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIClientFactory = mock.APIClient(api)
+				return opts, nil
+			}
+			re := errors.Deduce(app.Run(args, nil))
+			testutil.AssertStringContains(t, re.Error(), testcase.wantError)
+			testutil.AssertEqual(t, testcase.wantCode, re.Code)
+			testutil.AssertString(t, testcase.wantPrefix, re.Prefix)
+			if testcase.wantMissing != "" && strings.Contains(re.Error(), testcase.wantMissing) {
+				t.Errorf("unexpected %q in error: %s", testcase.wantMissing, re.Error())
+			}
+		})
+	}
+}
+
+// TestDeprecations validates a deprecated flag is warned about once, or
+// rejected before any changes are made in strict mode.
+func TestDeprecations(t *testing.T) {
+	const warning = "The --ssl-check-cert flag of `fastly backend update` is deprecated"
+	scenarios := []struct {
+		name        string
+		args        string
+		env         string
+		wantError   string
+		wantUpdated bool
+	}{
+		{
+			name:        "warning",
+			args:        "backend update --service-id 123 --version 3 --name www --ssl-check-cert",
+			wantUpdated: true,
+		},
+		{
+			name:      "strict flag",
+			args:      "backend update --service-id 123 --version 3 --name www --ssl-check-cert --strict",
+			wantError: "deprecated items used in strict mode:\n\n  " + warning,
+		},
+		{
+			name:      "strict env",
+			args:      "backend update --service-id 123 --version 3 --name www --ssl-check-cert",
+			env:       "true",
+			wantError: "deprecated items used in strict mode",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			args := testutil.Args(testcase.args)
+			var updated bool
+			api := mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				GetBackendFn: func(i *fastly.GetBackendInput) (*fastly.Backend, error) {
+					return &fastly.Backend{Name: fastly.ToPointer(i.Name), Address: fastly.ToPointer("www.example.com"), Port: fastly.ToPointer(443), UseSSL: fastly.ToPointer(true)}, nil
+				},
+				UpdateBackendFn: func(i *fastly.UpdateBackendInput) (*fastly.Backend, error) {
+					updated = true
+					return &fastly.Backend{ServiceID: fastly.ToPointer(i.ServiceID), Name: fastly.ToPointer(i.Name)}, nil
+				},
+			}
+			var stdout, stderr bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIClientFactory = mock.APIClient(api)
+				opts.Env.Strict = testcase.env
+				opts.ErrOutput = &stderr
+				return opts, nil
+			}
+			err := app.Run(args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertBool(t, testcase.wantUpdated, updated)
+			if testcase.wantError == "" {
+				testutil.AssertEqual(t, 1, strings.Count(stderr.String(), warning))
+			}
+		})
+	}
+}
+
+// TestTraceExportFailure validates failing to export a trace doesn't change
+// the outcome of the command.
+func TestTraceExportFailure(t *testing.T) {
+	args := testutil.Args("config --location --verbose")
+	var stdout, stderr bytes.Buffer
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		opts := testutil.MockGlobalData(args, &stdout)
+		opts.Env.TraceFile = filepath.Join(t.TempDir(), "missing", "trace.json")
+		opts.ErrOutput = &stderr
+		return opts, nil
+	}
+	testutil.AssertNoError(t, app.Run(args, nil))
+	testutil.AssertStringContains(t, stderr.String(), "Failed to export the trace of the command")
+}
+
+// TestNonInteractive validates a prompt fails fast when standard input is a
+// pipe, and prompts are disabled in a CI environment unless --interactive is
+// provided (e.g. to answer them from a pipe).
+func TestNonInteractive(t *testing.T) {
+	api := mock.API{
+		GetTokenSelfFn: func() (*fastly.Token, error) {
+			return &fastly.Token{TokenID: fastly.ToPointer("123")}, nil
+		},
+	}
+
+	scenarios := []struct {
+		name               string
+		args               string
+		ci                 string
+		closeInput         bool
+		noProfiles         bool
+		wantError          string
+		wantRemediation    string
+		wantNonInteractive bool
+		wantOutput         string
+	}{
+		{
+			// NOTE: The first prompt asks whether the new profile should be the
+			// default, as there's already a default profile.
+			name:            "piped stdin",
+			args:            "profile create foo --automation-token",
+			wantError:       "standard input isn't a terminal",
+			wantRemediation: "Pass --auto-yes to supply the value",
+		},
+		{
+			name:            "CI environment",
+			args:            "profile create foo --automation-token",
+			ci:              "GITHUB_ACTIONS",
+			wantError:       "non-interactive mode is enabled",
+			wantRemediation: "Pass --auto-yes to supply the value",
+		},
+		{
+			name:       "--interactive in a CI environment",
+			args:       "profile create foo --automation-token --interactive",
+			ci:         "GITHUB_ACTIONS",
+			closeInput: true,
+			noProfiles: true,
+			wantOutput: "Profile 'foo' created",
+		},
+		{
+			name:               "--interactive with --non-interactive",
+			args:               "profile create foo --automation-token --interactive --non-interactive",
+			wantError:          errors.ErrInvalidInteractiveCombo.Inner.Error(),
+			wantNonInteractive: true,
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			// The token is written to the pipe, but unless the write end is
+			// closed, a prompt reading from it would block.
+			stdin, w, err := os.Pipe()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer stdin.Close()
+			defer w.Close()
+			if _, err := w.WriteString("some_token\n"); err != nil {
+				t.Fatal(err)
+			}
+			if testcase.closeInput {
+				_ = w.Close()
+			}
+
+			args := testutil.Args(testcase.args)
+			var stdout bytes.Buffer
+			opts := testutil.MockGlobalData(args, &stdout)
+			opts.APIClientFactory = mock.APIClient(api)
+			opts.ConfigPath = filepath.Join(t.TempDir(), "config.toml")
+			opts.Env.CI = testcase.ci
+			opts.Input = stdin
+			if testcase.noProfiles {
+				opts.Config.Profiles = nil
+			}
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				return opts, nil
+			}
+
+			done := make(chan error, 1)
+			go func() {
+				done <- app.Run(args, nil)
+			}()
+			select {
+			case err = <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("the prompt waited for input")
+			}
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if testcase.wantRemediation != "" {
+				if !stderrors.Is(err, errors.ErrNonInteractive) {
+					t.Fatalf("want errors.ErrNonInteractive, have %#v", err)
+				}
+				testutil.AssertStringContains(t, errors.Deduce(err).Remediation, testcase.wantRemediation)
+			}
+			testutil.AssertBool(t, testcase.wantNonInteractive, opts.Flags.NonInteractive)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+		})
+	}
+}
+
+// TestCIConfirmation validates a CI environment doesn't confirm an operation
+// affecting production traffic as --auto-yes would.
+func TestCIConfirmation(t *testing.T) {
+	scenarios := []struct {
+		args       string
+		wantError  string
+		wantPurged bool
+	}{
+		{
+			args:      "purge --all --service-id 123",
+			wantError: "confirmation required to purge all cached content from service 123",
+		},
+		{
+			args:       "purge --all --service-id 123 --auto-yes",
+			wantPurged: true,
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.args, func(t *testing.T) {
+			var purged bool
+			api := mock.API{
+				PurgeAllFn: func(_ *fastly.PurgeAllInput) (*fastly.Purge, error) {
+					purged = true
+					return &fastly.Purge{Status: fastly.ToPointer("ok")}, nil
+				},
+			}
+
+			args := testutil.Args(testcase.args)
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIClientFactory = mock.APIClient(api)
+				opts.Env.CI = "1"
+				return opts, nil
+			}
+			err := app.Run(args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if testcase.wantError != "" {
+				testutil.AssertStringContains(t, errors.Deduce(err).Remediation, "pass --auto-yes (-y)")
+			}
+			testutil.AssertBool(t, testcase.wantPurged, purged)
+		})
+	}
+}
+
+// TestFirstRunSetup validates the guided setup offered the first time the CLI
+// is run (there's no config file) by a command that needs a token.
+func TestFirstRunSetup(t *testing.T) {
+	scenarios := []struct {
+		name          string
+		stdin         string
+		notTTY        bool
+		tokenErr      error
+		wantError     string
+		wantOutput    []string
+		wantOpened    bool
+		wantConfig    bool
+		wantRemoved   bool
+		wantServiceID string
+	}{
+		{
+			name:          "full setup",
+			stdin:         "y\ny\nabc123\n123\n",
+			wantOutput:    []string{"Welcome to the Fastly CLI!", "Authenticated as test@example.com", "The default service is 'Foo'", "Created the 'user' profile", "Service ID (via the profile's default service): 123"},
+			wantOpened:    true,
+			wantConfig:    true,
+			wantServiceID: "123",
+		},
+		{
+			name:       "no default service",
+			stdin:      "y\nn\nabc123\n\n",
+			wantOutput: []string{"Create a token at " + app.TokenURL, "Created the 'user' profile"},
+			wantConfig: true,
+			wantError:  "no service ID found",
+		},
+		{
+			name:        "declined",
+			stdin:       "n\n",
+			wantOutput:  []string{"Nothing was changed", "fastly profile create", "FASTLY_API_TOKEN"},
+			wantRemoved: true,
+		},
+		{
+			name:      "invalid token",
+			stdin:     "y\nn\nabc123\n",
+			tokenErr:  stderrors.New("401 Unauthorized"),
+			wantError: "error validating token: 401 Unauthorized",
+		},
+		{
+			name:      "not a terminal",
+			notTTY:    true,
+			wantError: errors.ErrNoProfile.Inner.Error(),
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			// NOTE: The config file is written before the setup is offered, as it
+			// is by app.Init.
+			configPath := filepath.Join(t.TempDir(), "config.toml")
+			if err := os.WriteFile(configPath, nil, config.FilePermissions); err != nil {
+				t.Fatal(err)
+			}
+			var opened string
+			api := mock.API{
+				GetTokenSelfFn: func() (*fastly.Token, error) {
+					if testcase.tokenErr != nil {
+						return nil, testcase.tokenErr
+					}
+					return &fastly.Token{TokenID: fastly.ToPointer("456"), UserID: fastly.ToPointer("789")}, nil
+				},
+				GetUserFn: func(_ *fastly.GetUserInput) (*fastly.User, error) {
+					return &fastly.User{Login: fastly.ToPointer("test@example.com")}, nil
+				},
+				GetServiceFn: func(i *fastly.GetServiceInput) (*fastly.Service, error) {
+					return &fastly.Service{ServiceID: fastly.ToPointer(i.ServiceID), Name: fastly.ToPointer("Foo")}, nil
+				},
+				GetServiceDetailsFn: func(i *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+					return &fastly.ServiceDetail{ServiceID: fastly.ToPointer(i.ServiceID), Name: fastly.ToPointer("Foo")}, nil
+				},
+			}
+
+			args := testutil.Args("service describe --verbose")
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, in io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIClientFactory = mock.APIClient(api)
+				opts.Config.Profiles = nil
+				opts.ConfigPath = configPath
+				opts.FirstRun = true
+				opts.Input = in
+				opts.IsTTY = func(_ any) bool {
+					return !testcase.notTTY
+				}
+				opts.Opener = func(url string) error {
+					opened = url
+					return nil
+				}
+				return opts, nil
+			}
+			// NOTE: The answers are read one byte at a time so that each prompt
+			// only reads its own line.
+			err := app.Run(args, iotest.OneByteReader(strings.NewReader(testcase.stdin)))
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			if testcase.wantOpened {
+				testutil.AssertString(t, app.TokenURL, opened)
+			}
+
+			if testcase.wantRemoved {
+				if _, err := os.Stat(configPath); !os.IsNotExist(err) {
+					t.Fatalf("want the config file removed, have: %v", err)
+				}
+			}
+			if !testcase.wantConfig {
+				return
+			}
+			var f config.File
+			if err := f.Read(configPath, nil, io.Discard, errors.MockLog{}, false); err != nil {
+				t.Fatal(err)
+			}
+			p := f.Profiles["user"]
+			if p == nil {
+				t.Fatalf("want the 'user' profile, have: %v", f.Profiles)
+			}
+			testutil.AssertBool(t, true, p.Default)
+			testutil.AssertString(t, "abc123", p.Token)
+			testutil.AssertString(t, "test@example.com", p.Email)
+			testutil.AssertString(t, testcase.wantServiceID, p.ServiceID)
+			t.Log(stdout.String())
+		})
+	}
+}
+
+// TestScopePreflight validates a mutating command is rejected before any
+// changes are made when the API token lacks the scope it needs.
+func TestScopePreflight(t *testing.T) {
+	scenarios := []struct {
+		name        string
+		args        string
+		scope       fastly.TokenScope
+		lookupErr   error
+		wantError   string
+		wantOutput  string
+		wantLookups int
+	}{
+		{
+			name:        "global scope",
+			args:        "service create --name foo",
+			scope:       fastly.GlobalScope,
+			wantOutput:  "Created service 123",
+			wantLookups: 1,
+		},
+		{
+			name:        "read-only scope",
+			args:        "service create --name foo",
+			scope:       fastly.GlobalReadScope,
+			wantError:   "`fastly service create` requires an API token with the 'global' scope (the token's scope is 'global:read')",
+			wantLookups: 1,
+		},
+		{
+			name:       "read-only command",
+			args:       "service-version list --service-id 123",
+			scope:      fastly.GlobalReadScope,
+			wantOutput: "NUMBER",
+		},
+		{
+			name:        "purge a URL",
+			args:        "purge --service-id 123 --url https://example.com/foo",
+			scope:       fastly.PurgeSelectScope,
+			wantOutput:  "Purged URL: https://example.com/foo",
+			wantLookups: 1,
+		},
+		{
+			name:        "purge all with purge_select",
+			args:        "purge --service-id 123 --all",
+			scope:       fastly.PurgeSelectScope,
+			wantError:   "`fastly purge` requires an API token with the 'purge_all' scope (the token's scope is 'purge_select')",
+			wantLookups: 1,
+		},
+		{
+			name:        "purge all",
+			args:        "purge --service-id 123 --all --auto-yes",
+			scope:       fastly.PurgeAllScope,
+			wantOutput:  "Purge all status: ok",
+			wantLookups: 1,
+		},
+		{
+			name:       "skipped",
+			args:       "service create --name foo --skip-scope-check",
+			scope:      fastly.GlobalReadScope,
+			wantOutput: "Created service 123",
+		},
+		{
+			name:        "lookup failed",
+			args:        "service create --name foo",
+			lookupErr:   stderrors.New("connection refused"),
+			wantError:   "failed to look up the scope of the API token: connection refused",
+			wantLookups: 1,
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			var lookups int
+			api := mock.API{
+				GetTokenSelfFn: func() (*fastly.Token, error) {
+					lookups++
+					if testcase.lookupErr != nil {
+						return nil, testcase.lookupErr
+					}
+					return &fastly.Token{Scope: fastly.ToPointer(testcase.scope)}, nil
+				},
+				CreateServiceFn: func(i *fastly.CreateServiceInput) (*fastly.Service, error) {
+					if testcase.wantError != "" {
+						t.Fatal("unexpected service creation")
+					}
+					return &fastly.Service{ServiceID: fastly.ToPointer("123")}, nil
+				},
+				ListVersionsFn: testutil.ListVersions,
+				PurgeFn: func(*fastly.PurgeInput) (*fastly.Purge, error) {
+					return &fastly.Purge{Status: fastly.ToPointer("ok"), PurgeID: fastly.ToPointer("abc")}, nil
+				},
+				PurgeAllFn: func(*fastly.PurgeAllInput) (*fastly.Purge, error) {
+					if testcase.wantError != "" {
+						t.Fatal("unexpected purge")
+					}
+					return &fastly.Purge{Status: fastly.ToPointer("ok")}, nil
+				},
+			}
+
+			args := testutil.Args(testcase.args)
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIClientFactory = mock.APIClient(api)
+				opts.TokenSelf = nil
+				return opts, nil
+			}
+			err := app.Run(args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if testcase.wantError != "" {
+				testutil.AssertEqual(t, errors.CodeTokenScope, errors.Deduce(err).Code)
+			}
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			testutil.AssertEqual(t, testcase.wantLookups, lookups)
+		})
+	}
+}
+
+// TestCache validates listings are reused by a later invocation (and reported
+// in verbose mode), but not with --no-cache, by a mutating command, or once a
+// change has been made.
+func TestCache(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []string
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received = append(received, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/service/123/version":
+			_, _ = w.Write([]byte(`[{"number": 1, "service_id": "123"}]`))
+		default:
+			_, _ = w.Write([]byte(`{"id": "123", "name": "foo", "number": 2}`))
+		}
+	}))
+	defer ts.Close()
+	dir := t.TempDir()
+
+	scenarios := []struct {
+		name         string
+		args         string
+		wantOutput   string
+		wantReceived []string
+	}{
+		{
+			name:         "first listing",
+			args:         "service-version list --service-id 123",
+			wantReceived: []string{"GET /service/123/version"},
+		},
+		{
+			name:       "cached listing",
+			args:       "service-version list --service-id 123 --verbose",
+			wantOutput: "Using cached API response (fetched 0s ago): GET /service/123/version",
+		},
+		{
+			name:         "--no-cache",
+			args:         "service-version list --service-id 123 --no-cache",
+			wantReceived: []string{"GET /service/123/version"},
+		},
+		{
+			name:         "mutating command",
+			args:         "service-version clone --service-id 123 --version latest",
+			wantReceived: []string{"GET /service/123/version", "PUT /service/123/version/1/clone"},
+		},
+		{
+			name:         "after a change",
+			args:         "service-version list --service-id 123",
+			wantReceived: []string{"GET /service/123/version"},
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			mu.Lock()
+			received = nil
+			mu.Unlock()
+
+			args := testutil.Args(testcase.args)
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APICache = &httpclient.Cache{Base: ts.Client().Transport, Dir: dir}
+				opts.APIClientFactory = func(token, _ string, _ bool) (api.Interface, error) {
+					client, err := fastly.NewClientForEndpoint(token, ts.URL)
+					if err == nil {
+						client.HTTPClient = &http.Client{Transport: opts.APICache}
+					}
+					return client, err
+				}
+				opts.Config.CLI.MetadataNoticeDisplayed = true
+				return opts, nil
+			}
+			testutil.AssertNoError(t, app.Run(args, nil))
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			mu.Lock()
+			defer mu.Unlock()
+			testutil.AssertEqual(t, testcase.wantReceived, received)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package app
+
+import (
+	"sync"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/scope"
+)
+
+// tokenSelf returns a global.Data.TokenSelf implementation, which requests the
+// description of the API token the first time it's called.
+func tokenSelf(data *global.Data) func() (*fastly.Token, error) {
+	var (
+		once  sync.Once
+		token *fastly.Token
+		err   error
+	)
+	return func() (*fastly.Token, error) {
+		once.Do(func() {
+			token, err = data.APIClient.GetTokenSelf()
+		})
+		return token, err
+	}
+}
+
+// checkScope fails before the command runs if the API token lacks the scope
+// the command needs (see scope.Required), so that a read-only token doesn't
+// fail part way through a command's changes.
+func checkScope(data *global.Data, command argparser.Command, commandName string) error {
+	if data.Flags.SkipScopeCheck || data.Offline() || !commandRequiresToken(commandName) {
+		return nil
+	}
+	return scope.Check(commandName, scope.Required(commandName, command), data.TokenSelf)
+}
@@ -0,0 +1,151 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/config"
+	"github.com/fastly/cli/pkg/env"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/lookup"
+	"github.com/fastly/cli/pkg/profile"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// TokenURL is the page of the Fastly web interface that creates API tokens.
+const TokenURL = "https://manage.fastly.com/account/personal/tokens"
+
+// firstRunSetup guides a new user through creating a profile, when the CLI is
+// run for the first time (see global.Data.FirstRun) and the command needs an
+// API token that isn't available.
+//
+// The token is validated, and the config file is only written once the setup
+// is complete. Declining the setup removes the config file created when the
+// CLI started, so it's as if the CLI was never run, and displays the manual
+// steps instead (fsterr.ErrDontContinue is returned).
+//
+// The setup needs a terminal, so otherwise fsterr.ErrNoProfile is returned.
+func firstRunSetup(data *global.Data, commandName string) (token string, source lookup.Source, err error) {
+	in, out := data.Input, data.Output
+	if data.Flags.AutoYes || data.Flags.NonInteractive || !data.IsTTY(in) || !data.IsTTY(out) {
+		return "", lookup.SourceUndefined, fsterr.ErrNoProfile
+	}
+
+	text.Info(out, "Welcome to the Fastly CLI! `fastly %s` uses the Fastly API, which needs an API token. The token is stored in a profile in the CLI config file, so later commands use it too.", commandName)
+	text.Break(out)
+	ok, err := text.AskYesNo(out, "Set up a profile now? [y/N] ", in, "")
+	if err != nil {
+		return "", lookup.SourceUndefined, err
+	}
+	text.Break(out)
+	if !ok {
+		discardConfig(data)
+		displayManualSetup(data)
+		return "", lookup.SourceUndefined, fsterr.ErrDontContinue
+	}
+
+	ok, err = text.AskYesNo(out, "Open the page that creates API tokens in your browser? [y/N] ", in, "")
+	if err != nil {
+		return "", lookup.SourceUndefined, err
+	}
+	if ok {
+		if err := data.Opener(TokenURL); err != nil {
+			text.Warning(out, "The browser couldn't be opened (%s), visit %s to create a token.", err, TokenURL)
+		}
+	} else {
+		text.Info(out, "Create a token at %s", TokenURL)
+	}
+	text.Break(out)
+
+	token, err = text.InputSecure(out, text.Prompt("Paste the API token: "), in, "", func(s string) error {
+		if s == "" {
+			return errors.New("token cannot be empty")
+		}
+		return nil
+	})
+	if err != nil {
+		return "", lookup.SourceUndefined, err
+	}
+	text.Break(out)
+
+	endpoint, _ := data.APIEndpoint()
+	client, err := data.APIClientFactory(token, endpoint, data.Flags.Debug)
+	if err != nil {
+		data.ErrLog.Add(err)
+		return "", lookup.SourceUndefined, fmt.Errorf("error constructing Fastly API client: %w", err)
+	}
+	t, err := client.GetTokenSelf()
+	if err != nil {
+		data.ErrLog.Add(err)
+		return "", lookup.SourceUndefined, fsterr.RemediationError{
+			Inner:       fmt.Errorf("error validating token: %w", err),
+			Remediation: fmt.Sprintf("Check the token was copied in full, or create a new one at %s.", TokenURL),
+		}
+	}
+	// NOTE: The user of an automation token can't be looked up.
+	email := fmt.Sprintf("Automation Token (%s)", fastly.ToValue(t.TokenID))
+	if user, err := client.GetUser(&fastly.GetUserInput{UserID: fastly.ToValue(t.UserID)}); err == nil {
+		email = fastly.ToValue(user.Login)
+	}
+	text.Success(out, "Authenticated as %s", email)
+	text.Break(out)
+
+	p := &config.Profile{
+		Default: true,
+		Email:   email,
+		Token:   token,
+	}
+	text.Info(out, "A default service is used by commands that aren't given one, except in a Compute project (whose fastly.toml names its service).")
+	serviceID, err := text.Input(out, text.Prompt("Default service ID (leave empty for none): "), in, "")
+	if err != nil {
+		return "", lookup.SourceUndefined, err
+	}
+	if serviceID = strings.TrimSpace(serviceID); serviceID != "" {
+		s, err := client.GetService(&fastly.GetServiceInput{ServiceID: serviceID})
+		if err != nil {
+			data.ErrLog.Add(err)
+			text.Warning(out, "The service couldn't be found (%s), so no default service was set.", err)
+		} else {
+			p.ServiceID = serviceID
+			text.Info(out, "The default service is '%s'", fastly.ToValue(s.Name))
+		}
+	}
+	text.Break(out)
+
+	if data.Config.Profiles == nil {
+		data.Config.Profiles = make(config.Profiles)
+	}
+	data.Config.Profiles[profile.DefaultName] = p
+	data.Config.Fastly.APIEndpoint = endpoint
+	if err := data.Config.WriteAtomic(data.ConfigPath); err != nil {
+		data.ErrLog.Add(err)
+		return "", lookup.SourceUndefined, fmt.Errorf("error saving config file: %w", err)
+	}
+	text.Success(out, "Created the '%s' profile in %s", profile.DefaultName, data.ConfigPath)
+	text.Break(out)
+	return token, lookup.SourceFile, nil
+}
+
+// discardConfig removes the config file created when the CLI started, as the
+// user declined the setup.
+func discardConfig(data *global.Data) {
+	if err := os.Remove(data.ConfigPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		data.ErrLog.Add(err)
+	}
+}
+
+// displayManualSetup displays how to set the CLI up without the guided setup.
+func displayManualSetup(data *global.Data) {
+	text.Info(data.Output, "Nothing was changed. To set up the CLI later:")
+	text.Break(data.Output)
+	fmt.Fprintf(data.Output, "1. Create an API token at %s\n", TokenURL)
+	fmt.Fprintf(data.Output, "2. Run `fastly profile create` and paste the token when prompted\n")
+	text.Break(data.Output)
+	text.Info(data.Output, "A token can also be given to a single command with --token, or the %s environment variable.", env.APIToken)
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/fastly/cli/pkg/commands"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+)
+
+// commandFlags returns a global.Data.CommandFlags implementation.
+func commandFlags(parent *global.Data) func(command string) ([]string, error) {
+	return func(command string) ([]string, error) {
+		d := stepData(parent, nil)
+		app := configureKingpin(d)
+		_ = commands.Define(app, d)
+
+		var flags []string
+		for _, f := range app.Model().Flags {
+			flags = append(flags, f.Name)
+		}
+		for _, c := range app.Model().FlattenedCommands() {
+			if c.FullCommand() == command {
+				for _, f := range c.Flags {
+					flags = append(flags, f.Name)
+				}
+				return flags, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown command '%s'", command)
+	}
+}
+
+// prepareCommand returns a global.Data.PrepareCommand implementation.
+//
+// NOTE: Each command is defined afresh, against a copy of the invocation's
+// data, so that the flags of one command don't leak into the next. The copy
+// keeps the global flags and API client of the invocation. Like any other
+// invocation, a mutating command is recorded in the audit log.
+func prepareCommand(parent *global.Data) func(args []string) (func(out io.Writer) error, error) {
+	return func(args []string) (func(out io.Writer) error, error) {
+		d := stepData(parent, args)
+		app := configureKingpin(d)
+		cmds := commands.Define(app, d)
+		command, name, err := processCommandInput(d, app, cmds)
+		if err != nil {
+			return nil, err
+		}
+		if command == nil {
+			return nil, fmt.Errorf("no command in '%s'", strings.Join(args, " "))
+		}
+		if commandRequiresToken(name) && d.APIClient == nil {
+			return nil, fmt.Errorf("'%s' requires an API token", name)
+		}
+		if err := checkScope(d, command, name); err != nil {
+			return nil, err
+		}
+		configureCache(d, command, name)
+		return func(out io.Writer) error {
+			d.Output = out
+			start := time.Now()
+			err := command.Exec(d.Input, out)
+			recordAudit(d, command, name, start, err)
+			return err
+		}, nil
+	}
+}
+
+// stepData copies the invocation's data for a command run within it.
+func stepData(parent *global.Data, args []string) *global.Data {
+	d := *parent
+	d.Args = args
+	md := manifest.Data{File: parent.Manifest.File}
+	d.Manifest = &md
+	return &d
+}
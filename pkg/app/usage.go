@@ -30,12 +30,22 @@ func Usage(args []string, app *kingpin.Application, out, err io.Writer, vars map
 		Template: CompactUsageTemplate,
 		Funcs:    UsageTemplateFuncs,
 		Vars:     vars,
+		Width:    usageWidth(),
 	})
 	app.Usage(args)
 	app.Writers(out, err)
 	return buf.String()
 }
 
+// usageWidth returns the width the usage output is formatted to fit. Zero
+// (i.e. no width was decided, see text.SetWidth) leaves it to kingpin.
+func usageWidth() int {
+	if w, ok := text.CurrentWidth(); ok {
+		return w.Usage()
+	}
+	return 0
+}
+
 // CompactUsageTemplate is the default usage template, rendered when users type
 // e.g. just `fastly`, or use the `-h, --help` flag.
 var CompactUsageTemplate = `{{define "FormatCommand" -}}
@@ -145,18 +155,27 @@ var UsageTemplateFuncs = template.FuncMap{
 //
 // NOTE: This map is used to help populate the CLI 'usage' template renderer.
 var globalFlags = map[string]bool{
-	"accept-defaults": true,
-	"account":         true,
-	"auto-yes":        true,
-	"debug-mode":      true,
-	"enable-sso":      true,
-	"endpoint":        true,
-	"help":            true,
-	"non-interactive": true,
-	"profile":         true,
-	"quiet":           true,
-	"token":           true,
-	"verbose":         true,
+	"accept-defaults":      true,
+	"account":              true,
+	"auto-yes":             true,
+	"config-file":          true,
+	"debug-mode":           true,
+	"enable-sso":           true,
+	"endpoint":             true,
+	"events":               true,
+	"help":                 true,
+	"interactive":          true,
+	"max-time":             true,
+	"no-cache":             true,
+	"non-interactive":      true,
+	"profile":              true,
+	"quiet":                true,
+	"skip-name-validation": true,
+	"skip-scope-check":     true,
+	"strict":               true,
+	"token":                true,
+	"verbose":              true,
+	"verbosity":            true,
 }
 
 // VerboseUsageTemplate is the full-fat usage template, rendered when users type
@@ -254,7 +273,7 @@ func processCommandInput(
 	if argparser.IsVerboseAndQuiet(data.Args) {
 		return command, cmdName, fsterr.RemediationError{
 			Inner:       errors.New("--verbose and --quiet flag provided"),
-			Remediation: "Either remove both --verbose and --quiet flags, or one of them.",
+			Remediation: "Either remove both --verbose (or --verbosity) and --quiet flags, or one of them.",
 		}
 	}
 
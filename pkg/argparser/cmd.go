@@ -1,8 +1,12 @@
 package argparser
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/fastly/go-fastly/v9/fastly"
 	"github.com/fastly/kingpin"
@@ -11,6 +15,7 @@ import (
 	"github.com/fastly/cli/pkg/env"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/httpclient"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/text"
 )
@@ -40,6 +45,60 @@ type Registerer interface {
 	Command(name, help string) *kingpin.CmdClause
 }
 
+// FieldFlagger is implemented by commands that supply an API field via a flag
+// whose name can't be derived from the field's name (see FlagForField).
+type FieldFlagger interface {
+	// FieldFlags maps the names of API fields to the names of the flags (without
+	// the leading dashes) that supply them, e.g. "item_key" to "key".
+	FieldFlags() map[string]string
+}
+
+// Scoper is implemented by commands whose API token scope isn't the one
+// implied by the command's name (see scope.Required).
+type Scoper interface {
+	// RequiredScope returns the scope the token needs (e.g. purge_all), or an
+	// empty scope if a token of any scope will do.
+	RequiredScope() fastly.TokenScope
+}
+
+// Mutator is implemented by commands that change remote state depending on
+// how they're invoked, which can't be told from the command's name (see
+// audit.Mutating).
+type Mutator interface {
+	// Mutating reports whether this invocation of the command changed, or
+	// tried to change, remote state. It's called once the command has run.
+	Mutating() bool
+}
+
+// Interruptible is implemented by commands that watch Globals.Context, so they
+// can stop and clean up when the user presses Ctrl-C (see interrupt.SetGraceful).
+// Any other command exits as soon as it's interrupted.
+type Interruptible interface {
+	// Interruptible reports whether this invocation of the command stops when
+	// Globals.Context is cancelled.
+	Interruptible() bool
+}
+
+// FlagForField returns the name of the command's flag that supplied the API
+// field, given the names of the command's flags, or an empty string if no
+// flag did. The flag is the one mapped by FieldFlagger, otherwise the field's
+// name in kebab case, preferring a "new-" flag (e.g. --new-name supplies the
+// name field when --name identifies the resource being updated).
+func FlagForField(command Command, flags []string, field string) string {
+	if ff, ok := command.(FieldFlagger); ok {
+		if flag, ok := ff.FieldFlags()[field]; ok {
+			return flag
+		}
+	}
+	name := strings.ReplaceAll(field, "_", "-")
+	for _, flag := range []string{"new-" + name, name} {
+		if slices.Contains(flags, flag) {
+			return flag
+		}
+	}
+	return ""
+}
+
 // Globals are flags and other stuff that's useful to every command. Globals are
 // passed to each concrete command's constructor as a pointer, and are populated
 // after a call to Parse. A concrete command's Exec method can use any of the
@@ -105,6 +164,7 @@ type ServiceDetailsOpts struct {
 	AllowActiveLocked  bool
 	AutoCloneFlag      OptionalAutoClone
 	APIClient          api.Interface
+	Context            context.Context
 	Manifest           manifest.Data
 	Out                io.Writer
 	ServiceNameFlag    OptionalServiceNameID
@@ -123,28 +183,42 @@ func ServiceDetails(opts ServiceDetailsOpts) (serviceID string, serviceVersion *
 		DisplayServiceID(serviceID, flag, source, opts.Out)
 	}
 
-	v, err := opts.ServiceVersionFlag.Parse(serviceID, opts.APIClient)
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	current, err := opts.ServiceVersionFlag.Parse(serviceID, opts.APIClient)
 	if err != nil {
 		return serviceID, serviceVersion, err
 	}
-
-	if opts.AutoCloneFlag.WasSet {
-		currentVersion := v
-		v, err = opts.AutoCloneFlag.Parse(currentVersion, serviceID, opts.VerboseMode, opts.Out, opts.APIClient)
-		if err != nil {
-			return serviceID, currentVersion, err
-		}
-	} else if !opts.AllowActiveLocked && (fastly.ToValue(v.Active) || fastly.ToValue(v.Locked)) {
-		err = fsterr.RemediationError{
-			Inner:       fmt.Errorf("service version %d is not editable", fastly.ToValue(v.Number)),
-			Remediation: fsterr.AutoCloneRemediation,
-		}
+	v, cloned, err := EditableVersion(ctx, opts.APIClient, serviceID, current, VersionFlags{
+		AllowActiveLocked: opts.AllowActiveLocked,
+		AutoClone:         opts.AutoCloneFlag,
+	})
+	if err != nil {
 		return serviceID, v, err
 	}
+	if cloned && opts.VerboseMode {
+		displayAutoClone(opts.Out, current, v)
+	}
 
 	return serviceID, v, nil
 }
 
+// NoServiceIDError returns fsterr.ErrNoServiceID with a remediation naming the
+// manifest that was checked for a service_id.
+func NoServiceIDError() fsterr.RemediationError {
+	err := fsterr.ErrNoServiceID
+	path, absErr := filepath.Abs(manifest.Filename)
+	if absErr != nil {
+		return err
+	}
+	err.Remediation = fsterr.RenderRemediation(fsterr.RemediationServiceID, map[string]any{
+		"ManifestPath": path,
+	})
+	return err
+}
+
 // ServiceID returns the Service ID and the source of that information.
 //
 // NOTE: If Service ID not provided then check if Service Name provided and use
@@ -155,7 +229,7 @@ func ServiceID(serviceName OptionalServiceNameID, data manifest.Data, client api
 
 	if source == manifest.SourceUndefined {
 		if !serviceName.WasSet {
-			err = fsterr.ErrNoServiceID
+			err = NoServiceIDError()
 			if li != nil {
 				li.Add(err)
 			}
@@ -187,6 +261,8 @@ func DisplayServiceID(sid, flag string, s manifest.Source, out io.Writer) {
 		via = fmt.Sprintf(" (via %s)", manifest.Filename)
 	case manifest.SourceEnv:
 		via = fmt.Sprintf(" (via %s)", env.ServiceID)
+	case manifest.SourceProfile:
+		via = " (via the profile's default service)"
 	case manifest.SourceUndefined:
 		via = " (not provided)"
 	}
@@ -194,6 +270,25 @@ func DisplayServiceID(sid, flag string, s manifest.Source, out io.Writer) {
 	text.Break(out)
 }
 
+// DisplayConnections displays the connections used by a bulk operation (see
+// httpclient.ConnTracker) in verbose mode. Responses that closed their
+// connection are always warned about, as every request then opens a new one.
+func DisplayConnections(out io.Writer, s httpclient.ConnSummary, verbose bool) {
+	if verbose && s.Requests() > 0 {
+		text.Info(out, "API connections: %d opened, %d reused (%d requests from %d workers)", s.Opened, s.Reused, s.Requests(), s.PoolSize)
+		if s.MaxIdleConnsPerHost > 0 && s.PoolSize > s.MaxIdleConnsPerHost {
+			text.Info(out, "The %d workers exceed the %d idle connections kept per host, so some connections were closed rather than reused. Set `%s` in the [http] section of the CLI config to match.", s.PoolSize, s.MaxIdleConnsPerHost, httpclient.SettingMaxIdleConnsPerHost)
+		}
+	}
+	if s.Closed > 0 && s.Requests() > 1 {
+		by := "the API or a proxy on the network"
+		if s.Proxy != nil {
+			by = fmt.Sprintf("the proxy %s", s.Proxy.Redacted())
+		}
+		text.Warning(out, "%d of %d API responses closed their connection (Connection: close), so the requests that followed had to open new ones. This is likely forced by %s, and allowing keep-alive connections would make bulk operations quicker.", s.Closed, s.Requests(), by)
+	}
+}
+
 // ArgsIsHelpJSON determines whether the supplied command arguments are exactly
 // `help --format=json` or `help --format json`.
 func ArgsIsHelpJSON(args []string) bool {
@@ -220,15 +315,36 @@ func IsHelpFlagOnly(args []string) bool {
 	return len(args) > 0 && args[0] == "--help"
 }
 
-// IsVerboseAndQuiet indicates if the user called `fastly --verbose --quiet`.
+// ArgsIsJSON indicates if the user requested JSON output, i.e. with --json
+// (-j) or --format json, or part of it with --field or a --format template.
+func ArgsIsJSON(args []string) bool {
+	for _, a := range args {
+		switch {
+		case a == "--json", a == "-j":
+			return true
+		case a == "--field", strings.HasPrefix(a, "--field="):
+			return true
+		case a == "--format", strings.HasPrefix(a, "--format="):
+			return true
+		}
+	}
+	return false
+}
+
+// IsVerboseAndQuiet indicates if the user called `fastly --verbose --quiet`
+// (or any other verbosity level, e.g. `-vv` or `--verbosity 2`, with --quiet).
 // These flags are mutually exclusive.
 func IsVerboseAndQuiet(args []string) bool {
 	matches := map[string]bool{}
-	for _, a := range args {
-		if a == "--verbose" || a == "-v" {
+	for i, a := range args {
+		switch {
+		case a == "--verbose", a == "-v", a == "-vv", a == "-vvv":
 			matches["--verbose"] = true
-		}
-		if a == "--quiet" || a == "-q" {
+		case a == "--verbosity" && i+1 < len(args) && args[i+1] != "0":
+			matches["--verbose"] = true
+		case strings.HasPrefix(a, "--verbosity=") && a != "--verbosity=0":
+			matches["--verbose"] = true
+		case a == "--quiet", a == "-q":
 			matches["--quiet"] = true
 		}
 	}
@@ -261,25 +377,36 @@ func IsGlobalFlagsOnly(args []string) bool {
 	// False positive https://github.com/semgrep/semgrep/issues/8593
 	// nosemgrep: trailofbits.go.iterate-over-empty-map.iterate-over-empty-map
 	globals := map[string]int{
-		"--accept-defaults": 0,
-		"-d":                0,
-		"--account":         1,
-		"--api":             1,
-		"--auto-yes":        0,
-		"-y":                0,
-		"--debug-mode":      0,
-		"--enable-sso":      0,
-		"--help":            0,
-		"--non-interactive": 0,
-		"-i":                0,
-		"--profile":         1,
-		"-o":                1,
-		"--quiet":           0,
-		"-q":                0,
-		"--token":           1,
-		"-t":                1,
-		"--verbose":         0,
-		"-v":                0,
+		"--accept-defaults":      0,
+		"-d":                     0,
+		"--account":              1,
+		"--api":                  1,
+		"--auto-yes":             0,
+		"-y":                     0,
+		"--config-file":          1,
+		"--debug-mode":           0,
+		"--enable-sso":           0,
+		"--events":               1,
+		"--help":                 0,
+		"--interactive":          0,
+		"--max-time":             1,
+		"--no-cache":             0,
+		"--non-interactive":      0,
+		"-i":                     0,
+		"--profile":              1,
+		"-o":                     1,
+		"--quiet":                0,
+		"-q":                     0,
+		"--skip-name-validation": 0,
+		"--skip-scope-check":     0,
+		"--strict":               0,
+		"--token":                1,
+		"-t":                     1,
+		"--verbose":              0,
+		"-v":                     0,
+		"-vv":                    0,
+		"-vvv":                   0,
+		"--verbosity":            1,
 	}
 	var total int
 	for _, a := range args {
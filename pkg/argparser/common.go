@@ -5,6 +5,14 @@ var (
 	FlagCustomerIDName = "customer-id"
 	// FlagCustomerIDDesc is the flag description.
 	FlagCustomerIDDesc = "Alphanumeric string identifying the customer (falls back to FASTLY_CUSTOMER_ID)"
+	// FlagFieldName is the flag name.
+	FlagFieldName = "field"
+	// FlagFieldDesc is the flag description.
+	FlagFieldDesc = "Print a single field of the JSON output (e.g. active_version.number)"
+	// FlagFormatName is the flag name.
+	FlagFormatName = "format"
+	// FlagFormatDesc is the flag description.
+	FlagFormatDesc = "Format the JSON output with a Go template (e.g. '{{.Name}}'), or 'json' for --json"
 	// FlagJSONName is the flag name.
 	FlagJSONName = "json"
 	// FlagJSONDesc is the flag description.
@@ -0,0 +1,241 @@
+package argparser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// ErrNotConfirmed indicates the user declined a confirmation prompt.
+var ErrNotConfirmed = errors.New("operation not confirmed: no changes were made")
+
+// ConfirmOpts describes an operation requiring confirmation.
+type ConfirmOpts struct {
+	// Action describes the operation, e.g. "purge all cached content".
+	Action string
+	// Globals provides the API client, flags and terminal detection.
+	Globals *global.Data
+	// In is the user input.
+	In io.Reader
+	// Out is the user output.
+	Out io.Writer
+	// RequireName only accepts the service name typed back (or the service ID
+	// when the name can't be looked up), not "yes".
+	RequireName bool
+	// ServiceID is the affected service.
+	ServiceID string
+	// ServiceVersion is the affected version. The active version is used to
+	// look up the affected domains when zero.
+	ServiceVersion int
+}
+
+// Confirm gates production-impacting operations.
+//
+// The operation proceeds without prompting when --auto-yes is set. Otherwise,
+// if the input is a terminal and --non-interactive isn't set, a summary of the
+// affected service, version and domains is displayed and the user must type
+// the service name or "yes" (only the name when RequireName is set). In any
+// other context an error is returned naming the flag required to proceed.
+func Confirm(opts ConfirmOpts) error {
+	g := opts.Globals
+	if g.Flags.AutoYes {
+		return nil
+	}
+
+	isTTY := text.IsTTY
+	if g.IsTTY != nil {
+		isTTY = g.IsTTY
+	}
+	if g.Flags.NonInteractive || opts.In == nil || !isTTY(opts.In) {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("confirmation required to %s", opts.Action),
+			Remediation: "This operation affects production traffic. Run the command in an interactive terminal to confirm it, or pass --auto-yes (-y) to confirm it non-interactively.",
+		}
+	}
+
+	serviceName, version, domains := confirmSummary(g, opts.ServiceID, opts.ServiceVersion)
+
+	text.Warning(opts.Out, "This will %s.\n\n", opts.Action)
+	if serviceName != "" {
+		text.Output(opts.Out, "Service: %s (%s)", serviceName, opts.ServiceID)
+	} else {
+		text.Output(opts.Out, "Service: %s", opts.ServiceID)
+	}
+	if version > 0 {
+		text.Output(opts.Out, "Version: %d", version)
+	}
+	if len(domains) > 0 {
+		text.Output(opts.Out, "Domains: %s", strings.Join(domains, ", "))
+	}
+	text.Break(opts.Out)
+
+	prompt := "Type 'yes' to continue: "
+	if serviceName != "" {
+		prompt = fmt.Sprintf("Type the service name (%s) or 'yes' to continue: ", serviceName)
+	}
+	if opts.RequireName {
+		if serviceName == "" {
+			serviceName = opts.ServiceID
+		}
+		prompt = fmt.Sprintf("Type the service name (%s) to continue: ", serviceName)
+	}
+	answer, err := text.Input(opts.Out, prompt, opts.In, "--auto-yes")
+	if err != nil {
+		return err
+	}
+	if (!opts.RequireName && strings.EqualFold(answer, "yes")) || (serviceName != "" && answer == serviceName) {
+		text.Break(opts.Out)
+		return nil
+	}
+	return ErrNotConfirmed
+}
+
+// confirmSummary looks up the service name and the domains on the version.
+// Lookup failures are ignored as the summary is informational.
+func confirmSummary(g *global.Data, serviceID string, version int) (name string, v int, domains []string) {
+	v = version
+	s, err := g.APIClient.GetServiceDetails(&fastly.GetServiceInput{
+		ServiceID: serviceID,
+	})
+	if err == nil {
+		name = fastly.ToValue(s.Name)
+		if v == 0 && s.ActiveVersion != nil {
+			v = fastly.ToValue(s.ActiveVersion.Number)
+		}
+	}
+	if v == 0 {
+		return name, v, nil
+	}
+
+	ds, err := g.APIClient.ListDomains(&fastly.ListDomainsInput{
+		ServiceID:      serviceID,
+		ServiceVersion: v,
+	})
+	if err == nil {
+		for _, d := range ds {
+			domains = append(domains, fastly.ToValue(d.Name))
+		}
+	}
+	return name, v, domains
+}
+
+// ErrNoChanges indicates the local content is identical to the remote content.
+var ErrNoChanges = errors.New("no changes")
+
+// ConfirmChangeOpts describes a change to remote content requiring
+// confirmation.
+type ConfirmChangeOpts struct {
+	// Globals provides the --auto-yes and --non-interactive flags.
+	Globals *global.Data
+	// In is the user input, nil if it can't be prompted (e.g. the content was
+	// read from stdin).
+	In io.Reader
+	// Local is the new content.
+	Local string
+	// Name identifies the content in the diff header.
+	Name string
+	// Out is the user output.
+	Out io.Writer
+	// Remote is the current content.
+	Remote string
+}
+
+// ConfirmChange displays a diff of the remote and local content and asks the
+// user to confirm the change.
+//
+// ErrNoChanges is returned if the content is identical. As with Confirm, the
+// change proceeds without prompting only when --auto-yes is set, and an error
+// is returned if --non-interactive is set or the user can't be prompted.
+// Otherwise ErrNotConfirmed is returned unless the user answers yes.
+func ConfirmChange(opts ConfirmChangeOpts) error {
+	if opts.Remote == opts.Local {
+		return ErrNoChanges
+	}
+
+	text.Diff(opts.Out, opts.Name+" (remote)", opts.Name+" (local)", opts.Remote, opts.Local)
+	text.Break(opts.Out)
+
+	g := opts.Globals
+	if g.Flags.AutoYes {
+		return nil
+	}
+	if g.Flags.NonInteractive {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("confirmation required to update %s", opts.Name),
+			Remediation: "Run the command in an interactive terminal to confirm it, or pass --auto-yes (-y) to confirm it non-interactively.",
+		}
+	}
+	if opts.In == nil {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("confirmation required to update %s", opts.Name),
+			Remediation: "The content was read from stdin, so the change can't be confirmed interactively. Pass --auto-yes (-y) to apply it.",
+		}
+	}
+	cont, err := text.AskYesNo(opts.Out, "Apply these changes? [y/N]: ", opts.In, "--auto-yes")
+	if err != nil {
+		return err
+	}
+	if !cont {
+		return ErrNotConfirmed
+	}
+	return nil
+}
+
+// ConfirmBulkOpts describes an operation applied to many services.
+type ConfirmBulkOpts struct {
+	// Action describes the operation, e.g. "set the tags team=edge".
+	Action string
+	// Globals provides the flags and terminal detection.
+	Globals *global.Data
+	// In is the user input.
+	In io.Reader
+	// Out is the user output.
+	Out io.Writer
+	// Services describe the affected services, e.g. "Bar (456)".
+	Services []string
+}
+
+// ConfirmBulk gates an operation applied to every service matched by a
+// filter, listing the affected services. As with Confirm, the operation
+// proceeds without prompting only when --auto-yes is set, and an error is
+// returned if --non-interactive is set or the input isn't a terminal.
+func ConfirmBulk(opts ConfirmBulkOpts) error {
+	g := opts.Globals
+	if g.Flags.AutoYes {
+		return nil
+	}
+
+	isTTY := text.IsTTY
+	if g.IsTTY != nil {
+		isTTY = g.IsTTY
+	}
+	if g.Flags.NonInteractive || opts.In == nil || !isTTY(opts.In) {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("confirmation required to %s on %d services", opts.Action, len(opts.Services)),
+			Remediation: "Run the command in an interactive terminal to review the services and confirm it, or pass --auto-yes (-y) to confirm it non-interactively.",
+		}
+	}
+
+	text.Warning(opts.Out, "This will %s on %d services:\n\n", opts.Action, len(opts.Services))
+	for _, s := range opts.Services {
+		fmt.Fprintf(opts.Out, "  %s\n", s)
+	}
+	text.Break(opts.Out)
+
+	answer, err := text.Input(opts.Out, "Type 'yes' to continue: ", opts.In, "--auto-yes")
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(answer, "yes") {
+		text.Break(opts.Out)
+		return nil
+	}
+	return ErrNotConfirmed
+}
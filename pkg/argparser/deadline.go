@@ -0,0 +1,30 @@
+package argparser
+
+import (
+	"context"
+	"errors"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+)
+
+// MaxTimeReached reports whether the --max-time deadline has been reached, in
+// which case a command gathering results (e.g. paginating or streaming) should
+// stop and display what it has.
+//
+// NOTE: An interrupt (e.g. Ctrl-C) isn't reported, as the user asked for the
+// command to stop rather than for its results.
+func MaxTimeReached(g *global.Data) bool {
+	return errors.As(context.Cause(g.Context), &fsterr.MaxTimeError{})
+}
+
+// PartialResults returns the error for a command that displayed the results
+// gathered before the --max-time deadline, or nil if it wasn't reached.
+func PartialResults(g *global.Data) error {
+	var mte fsterr.MaxTimeError
+	if !errors.As(context.Cause(g.Context), &mte) {
+		return nil
+	}
+	mte.Partial = true
+	return mte
+}
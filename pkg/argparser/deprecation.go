@@ -0,0 +1,19 @@
+package argparser
+
+import (
+	"github.com/fastly/kingpin"
+
+	"github.com/fastly/cli/pkg/deprecation"
+)
+
+// Deprecated returns a flag action recording the use of a deprecated flag, so
+// it's warned about (or rejected in strict mode) once the arguments are
+// parsed. The item is expected to be registered (see deprecation.Register),
+// e.g.
+//
+//	c.CmdClause.Flag("old-name", "...").Hidden().Action(c.Deprecated(oldNameFlag)).StringVar(&c.name)
+func (b Base) Deprecated(item deprecation.Item) kingpin.Action {
+	return func(_ *kingpin.ParseElement, _ *kingpin.ParseContext) error {
+		return b.Globals.Deprecations.Use(item)
+	}
+}
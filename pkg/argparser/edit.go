@@ -0,0 +1,105 @@
+package argparser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/runtime"
+)
+
+// ErrEditCancelled indicates the user saved an empty or unchanged file.
+var ErrEditCancelled = errors.New("edit cancelled: the file was empty or unchanged, no changes were made")
+
+// EditOpts describes a value to edit in the user's text editor.
+type EditOpts struct {
+	// Extension is the temporary file's extension (e.g. ".vcl"), so the editor
+	// can apply syntax highlighting.
+	Extension string
+	// Globals provides the editor runner and the --non-interactive flag.
+	Globals *global.Data
+	// Initial is the current value, or a template for a new one.
+	Initial string
+	// Name identifies the value in the temporary file name (e.g. "snippet").
+	Name string
+	// Validate checks the edited value (optional).
+	Validate func(string) error
+}
+
+// Edit writes the initial value to a temporary file, opens it in the user's
+// editor ($VISUAL, $EDITOR, otherwise notepad on Windows and vi elsewhere)
+// and returns the saved content without trailing newlines.
+//
+// ErrEditCancelled is returned if the file is saved empty or unchanged. The
+// temporary file is only readable by the user and is always removed.
+func Edit(opts EditOpts) (string, error) {
+	g := opts.Globals
+	if g.Flags.NonInteractive {
+		return "", fsterr.RemediationError{
+			Inner:       fmt.Errorf("--edit cannot be used with --non-interactive"),
+			Remediation: "Pass the value via its flag instead.",
+		}
+	}
+
+	f, err := os.CreateTemp("", "fastly-"+opts.Name+"-*"+opts.Extension)
+	if err != nil {
+		return "", fmt.Errorf("failed to create a temporary file to edit: %w", err)
+	}
+	path := f.Name()
+	defer func() {
+		_ = os.Remove(path)
+	}()
+
+	initial := strings.TrimRight(opts.Initial, "\r\n")
+	err = f.Chmod(0o600)
+	if err == nil {
+		_, err = f.WriteString(initial + "\n")
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write temporary file '%s': %w", path, err)
+	}
+
+	editor := Editor()
+	if err := g.ExecuteEditor(append(editor, path)); err != nil {
+		return "", fsterr.RemediationError{
+			Inner:       fmt.Errorf("failed to run editor '%s': %w", strings.Join(editor, " "), err),
+			Remediation: "Set the VISUAL or EDITOR environment variable to an editor that waits until the file is closed (e.g. `code --wait`).",
+		}
+	}
+
+	// G304 (CWE-22): Potential file inclusion via variable
+	// #nosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read temporary file '%s': %w", path, err)
+	}
+	value := strings.TrimRight(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	if strings.TrimSpace(value) == "" || value == strings.ReplaceAll(initial, "\r\n", "\n") {
+		return "", ErrEditCancelled
+	}
+	if opts.Validate != nil {
+		if err := opts.Validate(value); err != nil {
+			return "", err
+		}
+	}
+	return value, nil
+}
+
+// Editor returns the user's editor command and its arguments.
+func Editor() []string {
+	for _, v := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(v)); len(fields) > 0 {
+			return fields
+		}
+	}
+	if runtime.Windows {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
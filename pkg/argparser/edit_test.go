@@ -0,0 +1,142 @@
+package argparser_test
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestEdit(t *testing.T) {
+	errInvalid := errors.New("invalid")
+
+	scenarios := []struct {
+		name           string
+		initial        string
+		edited         string
+		editorErr      error
+		nonInteractive bool
+		validate       func(string) error
+		want           string
+		wantError      string
+	}{
+		{
+			name:    "edited value",
+			initial: "old",
+			edited:  "new\n\n",
+			want:    "new",
+		},
+		{
+			name:    "windows line endings",
+			initial: "old",
+			edited:  "first\r\nsecond\r\n",
+			want:    "first\nsecond",
+		},
+		{
+			name:      "unchanged",
+			initial:   "old\n",
+			edited:    "old\n",
+			wantError: argparser.ErrEditCancelled.Error(),
+		},
+		{
+			name:      "emptied",
+			initial:   "old",
+			edited:    " \n",
+			wantError: argparser.ErrEditCancelled.Error(),
+		},
+		{
+			name:      "template left empty",
+			edited:    "\n",
+			wantError: argparser.ErrEditCancelled.Error(),
+		},
+		{
+			name:      "validation failure",
+			edited:    "new",
+			validate:  func(string) error { return errInvalid },
+			wantError: errInvalid.Error(),
+		},
+		{
+			name:      "editor failure",
+			editorErr: errors.New("exit status 1"),
+			wantError: "failed to run editor 'fake-editor --wait': exit status 1",
+		},
+		{
+			name:           "non-interactive",
+			nonInteractive: true,
+			wantError:      "--edit cannot be used with --non-interactive",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			t.Setenv("VISUAL", "fake-editor --wait")
+
+			var path string
+			g := &global.Data{
+				ExecuteEditor: func(args []string) error {
+					testutil.AssertEqual(t, []string{"fake-editor", "--wait"}, args[:len(args)-1])
+					path = args[len(args)-1]
+
+					fi, err := os.Stat(path)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if runtime.GOOS != "windows" && fi.Mode().Perm() != 0o600 {
+						t.Errorf("want the file permissions to be 0600, have %o", fi.Mode().Perm())
+					}
+					if !strings.HasSuffix(path, ".txt") {
+						t.Errorf("want the file extension .txt, have %s", path)
+					}
+					data, err := os.ReadFile(path)
+					if err != nil {
+						t.Fatal(err)
+					}
+					testutil.AssertString(t, strings.TrimRight(testcase.initial, "\n")+"\n", string(data))
+
+					if testcase.editorErr != nil {
+						return testcase.editorErr
+					}
+					return os.WriteFile(path, []byte(testcase.edited), 0o600)
+				},
+			}
+			g.Flags.NonInteractive = testcase.nonInteractive
+
+			value, err := argparser.Edit(argparser.EditOpts{
+				Extension: ".txt",
+				Globals:   g,
+				Initial:   testcase.initial,
+				Name:      "test",
+				Validate:  testcase.validate,
+			})
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertString(t, testcase.want, value)
+
+			if path != "" {
+				if _, err := os.Stat(path); !os.IsNotExist(err) {
+					t.Errorf("want the temporary file to be removed, have %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestEditor(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "nano -w")
+	testutil.AssertEqual(t, []string{"nano", "-w"}, argparser.Editor())
+
+	t.Setenv("VISUAL", "code --wait")
+	testutil.AssertEqual(t, []string{"code", "--wait"}, argparser.Editor())
+
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+	want := []string{"vi"}
+	if runtime.GOOS == "windows" {
+		want = []string{"notepad"}
+	}
+	testutil.AssertEqual(t, want, argparser.Editor())
+}
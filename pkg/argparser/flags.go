@@ -1,12 +1,14 @@
 package argparser
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"sort"
 	"strconv"
@@ -18,6 +20,7 @@ import (
 	"github.com/fastly/cli/pkg/api"
 	"github.com/fastly/cli/pkg/env"
 	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/text"
 )
 
@@ -158,22 +161,137 @@ type OptionalServiceNameID struct {
 }
 
 // Parse returns a service ID based off the given service name.
+//
+// NOTE: If the name doesn't exactly match a service, the returned error
+// suggests the closest matching service names.
 func (sv *OptionalServiceNameID) Parse(client api.Interface) (serviceID string, err error) {
+	services, err := ListServices(client)
+	if err != nil {
+		return serviceID, err
+	}
+	return MatchServiceName(sv.Value, services)
+}
+
+// ListServices returns all services, handling pagination.
+func ListServices(client api.Interface) ([]*fastly.Service, error) {
 	paginator := client.GetServices(&fastly.GetServicesInput{})
 	var services []*fastly.Service
 	for paginator.HasNext() {
 		data, err := paginator.GetNext()
 		if err != nil {
-			return serviceID, fmt.Errorf("error listing services: %w", err)
+			return nil, fmt.Errorf("error listing services: %w", err)
 		}
 		services = append(services, data...)
 	}
+	return services, nil
+}
+
+// MaxServiceNameSuggestions is the number of service names suggested when no
+// service exactly matches the given name.
+const MaxServiceNameSuggestions = 3
+
+// MatchServiceName returns the ID of the service whose name exactly matches
+// the given name. If there is no match, the closest service names are
+// suggested, and if multiple services share the name the candidate IDs are
+// listed.
+func MatchServiceName(name string, services []*fastly.Service) (serviceID string, err error) {
+	var ids []string
+	for _, s := range services {
+		if fastly.ToValue(s.Name) == name {
+			ids = append(ids, fastly.ToValue(s.ServiceID))
+		}
+	}
+
+	switch len(ids) {
+	case 1:
+		return ids[0], nil
+	case 0:
+		remediation := fsterr.ServiceIDRemediation
+		if suggestions := SuggestServiceNames(name, services, MaxServiceNameSuggestions); len(suggestions) > 0 {
+			var candidates []string
+			for _, s := range suggestions {
+				candidates = append(candidates, fmt.Sprintf("'%s' (%s)", fastly.ToValue(s.Name), fastly.ToValue(s.ServiceID)))
+			}
+			remediation = fmt.Sprintf("Did you mean: %s? Run `fastly service search --name <pattern> --regex` to list matching services.", strings.Join(candidates, ", "))
+		}
+		return serviceID, fsterr.RemediationError{
+			Inner:       fmt.Errorf("error matching service name with available services: no service named '%s' found", name),
+			Remediation: remediation,
+		}
+	default:
+		return serviceID, fsterr.RemediationError{
+			Inner:       fmt.Errorf("error matching service name with available services: %d services named '%s' found (IDs: %s)", len(ids), name, strings.Join(ids, ", ")),
+			Remediation: "Use --service-id with one of the listed IDs instead of --service-name.",
+		}
+	}
+}
+
+// SuggestServiceNames returns up to n services whose names most closely
+// resemble the given name, ordered from closest to furthest.
+//
+// Names are compared case-insensitively using the Levenshtein edit distance.
+// A name containing the input (or vice versa) is always ranked ahead of one
+// that doesn't, and services that bear no resemblance are never suggested.
+func SuggestServiceNames(name string, services []*fastly.Service, n int) []*fastly.Service {
+	type candidate struct {
+		service  *fastly.Service
+		contains bool
+		distance int
+	}
+
+	target := strings.ToLower(name)
+	var candidates []candidate
 	for _, s := range services {
-		if fastly.ToValue(s.Name) == sv.Value {
-			return fastly.ToValue(s.ServiceID), nil
+		sname := strings.ToLower(fastly.ToValue(s.Name))
+		c := candidate{
+			service:  s,
+			contains: target != "" && (strings.Contains(sname, target) || strings.Contains(target, sname)),
+			distance: levenshtein(target, sname),
+		}
+		// Ignore names that would require replacing most of the characters.
+		if !c.contains && c.distance > max(len(target), len(sname))/2 {
+			continue
 		}
+		candidates = append(candidates, c)
 	}
-	return serviceID, errors.New("error matching service name with available services")
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].contains != candidates[j].contains {
+			return candidates[i].contains
+		}
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return fastly.ToValue(candidates[i].service.Name) < fastly.ToValue(candidates[j].service.Name)
+	})
+
+	var suggestions []*fastly.Service
+	for i := 0; i < len(candidates) && i < n; i++ {
+		suggestions = append(suggestions, candidates[i].service)
+	}
+	return suggestions
+}
+
+// levenshtein returns the edit distance between two strings.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
 }
 
 // OptionalCustomerID represents a Fastly customer ID.
@@ -220,31 +338,20 @@ type OptionalAutoClone struct {
 // The returned version is either the same as the input argument `v` or it's a
 // cloned version if the input argument was either active or locked.
 func (ac *OptionalAutoClone) Parse(v *fastly.Version, sid string, verbose bool, out io.Writer, client api.Interface) (*fastly.Version, error) {
-	// if user didn't provide --autoclone flag
-	if !ac.Value && (fastly.ToValue(v.Active) || fastly.ToValue(v.Locked)) {
-		return nil, fsterr.RemediationError{
-			Inner:       fmt.Errorf("service version %d is not editable", fastly.ToValue(v.Number)),
-			Remediation: fsterr.AutoCloneRemediation,
-		}
+	version, cloned, err := EditableVersion(context.Background(), client, sid, v, VersionFlags{AutoClone: *ac})
+	if err != nil {
+		return nil, err
 	}
-	if ac.Value && (v.Active != nil && *v.Active || v.Locked != nil && *v.Locked) {
-		version, err := client.CloneVersion(&fastly.CloneVersionInput{
-			ServiceID:      sid,
-			ServiceVersion: fastly.ToValue(v.Number),
-		})
-		if err != nil {
-			return nil, fmt.Errorf("error cloning service version: %w", err)
-		}
-		if verbose {
-			msg := "Service version %d is not editable, so it was automatically cloned because --autoclone is enabled. Now operating on version %d.\n\n"
-			format := fmt.Sprintf(msg, fastly.ToValue(v.Number), fastly.ToValue(version.Number))
-			text.Info(out, format)
-		}
-		return version, nil
+	if cloned && verbose {
+		displayAutoClone(out, v, version)
 	}
+	return version, nil
+}
 
-	// Treat the function as a no-op if the version is editable.
-	return v, nil
+// displayAutoClone informs the user that version v was cloned.
+func displayAutoClone(out io.Writer, v, clone *fastly.Version) {
+	msg := "Service version %d is not editable, so it was automatically cloned because --autoclone is enabled. Now operating on version %d.\n\n"
+	text.Info(out, fmt.Sprintf(msg, fastly.ToValue(v.Number), fastly.ToValue(clone.Number)))
 }
 
 // GetActiveVersion returns the active service version.
@@ -287,6 +394,59 @@ func Content(flagval string) string {
 	return content
 }
 
+// StdinPath is the action of a flag whose value is a file path or '-' to read
+// from STDIN. The kingpin parser reads a lone '-' as an empty value, so dst is
+// set back to '-' when the flag is given without one.
+func StdinPath(dst *string) kingpin.Action {
+	return func(_ *kingpin.ParseElement, _ *kingpin.ParseContext) error {
+		if *dst == "" {
+			*dst = "-"
+		}
+		return nil
+	}
+}
+
+// ContentFile reads content from the file at path, or from in when path is
+// "-".
+func ContentFile(path string, in io.Reader) (string, error) {
+	if path == "-" {
+		if in == nil {
+			return "", fmt.Errorf("failed to read content from stdin: no input available")
+		}
+		data, err := io.ReadAll(in)
+		if err != nil {
+			return "", fmt.Errorf("failed to read content from stdin: %w", err)
+		}
+		return string(data), nil
+	}
+	path = filepath.Clean(path)
+	data, err := os.ReadFile(path) // #nosec G304 (CWE-22)
+	if err != nil {
+		return "", fsterr.RemediationError{
+			Inner:       fmt.Errorf("failed to read content file '%s': %w", path, err),
+			Remediation: fsterr.HostRemediation,
+		}
+	}
+	return string(data), nil
+}
+
+// ResolveContent returns the content given by either a --content flag (a file
+// path or the content itself) or a --content-file flag (a file path, or "-" for
+// stdin). ok is false if neither flag was set.
+func ResolveContent(content OptionalString, contentFile string, in io.Reader) (value string, ok bool, err error) {
+	if content.WasSet && contentFile != "" {
+		return "", false, fmt.Errorf("error parsing arguments: --content and --content-file are mutually exclusive")
+	}
+	if contentFile != "" {
+		value, err = ContentFile(contentFile, in)
+		return value, err == nil, err
+	}
+	if content.WasSet {
+		return Content(content.Value), true, nil
+	}
+	return "", false, nil
+}
+
 // IntToBool converts a binary 0|1 to a boolean.
 func IntToBool(i int) bool {
 	return i > 0
@@ -324,10 +484,93 @@ func IsCompletion(args []string) bool {
 	return found
 }
 
+// EmptyState describes the resource listed by a command, so that a
+// consistent message is displayed when there's nothing to list. It can be
+// embedded into command structs, and is declared in the constructor.
+type EmptyState struct {
+	// Resource is the plural name of the listed resource (e.g. "backends").
+	Resource string
+	// Create is the command that creates the resource, if there is one
+	// (e.g. "fastly backend create --version <VERSION> --name <NAME>").
+	Create string
+}
+
+// PrintEmpty displays the empty state message if there are no items,
+// reporting whether it did so the command can return early.
+//
+// NOTE: The message is informational, so it isn't displayed with --quiet.
+// It isn't displayed with --json either, as the caller should have already
+// written the empty list (see JSONOutput.WriteJSON).
+func (e *EmptyState) PrintEmpty(out io.Writer, items int) bool {
+	if items > 0 {
+		return false
+	}
+	if e.Create == "" {
+		text.Info(out, "No %s found.", e.Resource)
+		return true
+	}
+	text.Info(out, "No %s found. Run `%s`.", e.Resource, e.Create)
+	return true
+}
+
+// NextSteps collects the follow-up actions a command suggests once it
+// succeeds, so that they're displayed consistently. It can be embedded into
+// command structs.
+type NextSteps struct {
+	steps []text.NextStep
+}
+
+// AddNextStep registers a suggested follow-up action.
+func (n *NextSteps) AddNextStep(label, command, url string) {
+	n.steps = append(n.steps, text.NextStep{Label: label, Command: command, URL: url})
+}
+
+// NextStepsJSON returns the registered steps for the `next_steps` field of
+// the command's JSON output (an empty array rather than null if there are
+// none).
+func (n *NextSteps) NextStepsJSON() []text.NextStep {
+	if n.steps == nil {
+		return []text.NextStep{}
+	}
+	return n.steps
+}
+
+// PrintNextSteps displays the registered steps as a numbered list.
+//
+// NOTE: The steps are informational, so they aren't displayed with --quiet.
+func (n *NextSteps) PrintNextSteps(out io.Writer) {
+	text.NextSteps(out, n.steps)
+}
+
 // JSONOutput is a helper for adding a `--json` flag and encoding
 // values to JSON. It can be embedded into command structs.
 type JSONOutput struct {
-	Enabled bool // Set via flag.
+	Enabled bool   // Set via flag.
+	Field   string // Set via flag (see RegisterJSONFlags).
+	Format  string // Set via flag (see RegisterJSONFlags).
+}
+
+// RegisterJSONFlags defines the --json flag, along with the --field and
+// --format flags that select part of the JSON output for scripts. Either of
+// them enables JSON output (see JSONOutput.WriteJSON).
+func (b Base) RegisterJSONFlags(j *JSONOutput) {
+	b.RegisterFlagBool(j.JSONFlag())
+	enable := func(_ *kingpin.ParseElement, _ *kingpin.ParseContext) error {
+		j.Enabled = true
+		return nil
+	}
+	b.RegisterFlag(StringFlagOpts{
+		Action:      enable,
+		Description: FlagFieldDesc,
+		Dst:         &j.Field,
+		Name:        FlagFieldName,
+	})
+	b.RegisterFlag(StringFlagOpts{
+		Action:      enable,
+		Description: FlagFormatDesc,
+		Dst:         &j.Format,
+		Name:        FlagFormatName,
+	})
 }
 
 // JSONFlag creates a flag for enabling JSON output.
@@ -340,13 +583,50 @@ func (j *JSONOutput) JSONFlag() BoolFlagOpts {
 	}
 }
 
+// ProgressOutput returns the writer a long-running command displays its
+// progress to: out, or io.Discard with --json so that only the JSON is written
+// to out. As prompts would be hidden too, they're disabled with --json (as if
+// --non-interactive was provided).
+func (j *JSONOutput) ProgressOutput(g *global.Data, out io.Writer) io.Writer {
+	if !j.Enabled {
+		return out
+	}
+	g.Flags.NonInteractive = true
+	text.SetInteractive(text.InteractiveNever)
+	return io.Discard
+}
+
 // WriteJSON checks whether the enabled flag is set or not. If set,
 // then the given value is written as JSON to out. Otherwise, false is returned.
+//
+// With --field only the selected field is written, and with --format the
+// value is formatted with the template (see writeField and writeTemplate).
+//
+// NOTE: A nil slice or map is written as an empty array or object (rather than
+// null) so that an empty list can be consumed like any other.
 func (j *JSONOutput) WriteJSON(out io.Writer, value any) (bool, error) {
 	if !j.Enabled {
 		return false, nil
 	}
 
+	if v := reflect.ValueOf(value); v.IsValid() {
+		switch {
+		case v.Kind() == reflect.Slice && v.IsNil():
+			value = reflect.MakeSlice(v.Type(), 0, 0).Interface()
+		case v.Kind() == reflect.Map && v.IsNil():
+			value = reflect.MakeMap(v.Type()).Interface()
+		}
+	}
+
+	switch {
+	case j.Field != "" && j.Format != "":
+		return true, ErrFieldFormatCombo
+	case j.Field != "":
+		return true, writeField(out, value, j.Field)
+	case j.Format != "" && j.Format != "json":
+		return true, writeTemplate(out, value, j.Format)
+	}
+
 	enc := json.NewEncoder(out)
 	enc.SetIndent("", "  ")
 	return true, enc.Encode(value)
@@ -334,3 +334,86 @@ func cloneVersionResult(version int) func(i *fastly.CloneVersionInput) (*fastly.
 func errMatches(version int, err error) bool {
 	return err.Error() == fmt.Sprintf("service version %d is not editable", version)
 }
+
+func TestSuggestServiceNames(t *testing.T) {
+	services := []*fastly.Service{
+		{Name: fastly.ToPointer("production-api"), ServiceID: fastly.ToPointer("1")},
+		{Name: fastly.ToPointer("staging-api"), ServiceID: fastly.ToPointer("2")},
+		{Name: fastly.ToPointer("production-web"), ServiceID: fastly.ToPointer("3")},
+		{Name: fastly.ToPointer("prodution-api"), ServiceID: fastly.ToPointer("4")},
+		{Name: fastly.ToPointer("unrelated"), ServiceID: fastly.ToPointer("5")},
+	}
+
+	for _, testcase := range []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "typo ranks closest first",
+			input: "Production-API",
+			want:  []string{"production-api", "prodution-api", "production-web"},
+		},
+		{
+			name:  "substring ranks ahead of edit distance",
+			input: "staging",
+			want:  []string{"staging-api"},
+		},
+		{
+			name:  "no resemblance",
+			input: "zzzzzzzz",
+			want:  nil,
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var have []string
+			for _, s := range argparser.SuggestServiceNames(testcase.input, services, argparser.MaxServiceNameSuggestions) {
+				have = append(have, fastly.ToValue(s.Name))
+			}
+			testutil.AssertEqual(t, testcase.want, have)
+		})
+	}
+}
+
+func TestMatchServiceName(t *testing.T) {
+	services := []*fastly.Service{
+		{Name: fastly.ToPointer("foo"), ServiceID: fastly.ToPointer("123")},
+		{Name: fastly.ToPointer("bar"), ServiceID: fastly.ToPointer("456")},
+		{Name: fastly.ToPointer("bar"), ServiceID: fastly.ToPointer("789")},
+	}
+
+	for _, testcase := range []struct {
+		name            string
+		input           string
+		wantID          string
+		wantError       string
+		wantRemediation string
+	}{
+		{
+			name:   "exact match",
+			input:  "foo",
+			wantID: "123",
+		},
+		{
+			name:            "no match suggests closest names",
+			input:           "fooo",
+			wantError:       "no service named 'fooo' found",
+			wantRemediation: "Did you mean: 'foo' (123)?",
+		},
+		{
+			name:            "ambiguous match lists candidate IDs",
+			input:           "bar",
+			wantError:       "2 services named 'bar' found (IDs: 456, 789)",
+			wantRemediation: "--service-id",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			id, err := argparser.MatchServiceName(testcase.input, services)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertString(t, testcase.wantID, id)
+			if testcase.wantRemediation != "" {
+				testutil.AssertRemediationErrorContains(t, err, testcase.wantRemediation)
+			}
+		})
+	}
+}
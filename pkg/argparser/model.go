@@ -0,0 +1,94 @@
+package argparser
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/fastly/kingpin"
+)
+
+// InvokedCommand returns the command identified by args, along with the index
+// of the first argument after the command path. It's used to inspect the
+// arguments before they're parsed.
+func InvokedCommand(args []string, app *kingpin.ApplicationModel) (*kingpin.CmdModel, int) {
+	var cmd *kingpin.CmdModel
+	end := 0
+	cmds := app.Commands
+	flags := app.Flags
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			break
+		}
+		if strings.HasPrefix(a, "-") {
+			if f := LookupFlag(a, flags); f != nil && TakesNextArg(a, f) {
+				i++ // skip the flag value
+			}
+			continue
+		}
+		next := subcommand(a, cmds)
+		if next == nil {
+			break
+		}
+		cmd, end = next, i+1
+		cmds = next.Commands
+		flags = append(slices.Clone(flags), next.Flags...)
+	}
+	return cmd, end
+}
+
+// FindCommand returns the command with the given full path (e.g. "compute
+// deploy"), or nil if there isn't one.
+func FindCommand(path string, app *kingpin.ApplicationModel) *kingpin.CmdModel {
+	var cmd *kingpin.CmdModel
+	cmds := app.Commands
+	for _, name := range strings.Fields(path) {
+		cmd = subcommand(name, cmds)
+		if cmd == nil {
+			return nil
+		}
+		cmds = cmd.Commands
+	}
+	return cmd
+}
+
+// subcommand returns the command in cmds with the given name (or alias).
+func subcommand(name string, cmds []*kingpin.CmdModel) *kingpin.CmdModel {
+	for _, c := range cmds {
+		if c.Name == name || slices.Contains(c.Aliases, name) {
+			return c
+		}
+	}
+	return nil
+}
+
+// CommandFlags returns the flags accepted by cmd, including those of its
+// parent commands and the global flags.
+func CommandFlags(cmd *kingpin.CmdModel, app *kingpin.ApplicationModel) []*kingpin.ClauseModel {
+	flags := slices.Clone(app.Flags)
+	for c := cmd; c != nil; c = c.Parent {
+		flags = append(flags, c.Flags...)
+	}
+	return flags
+}
+
+// LookupFlag returns the flag that arg (e.g. "--token", "--token=123", "-t")
+// refers to, or nil if there isn't one.
+func LookupFlag(arg string, flags []*kingpin.ClauseModel) *kingpin.ClauseModel {
+	name, _, _ := strings.Cut(arg, "=")
+	for _, f := range flags {
+		if name == "--"+f.Name || name == "--no-"+f.Name || (f.Short != 0 && name == "-"+string(f.Short)) {
+			return f
+		}
+	}
+	return nil
+}
+
+// TakesNextArg reports whether the flag arg (for flag f) is followed by its
+// value as a separate argument (i.e. `--token 123` rather than `--token=123`).
+func TakesNextArg(arg string, f *kingpin.ClauseModel) bool {
+	if f.IsBoolFlag() || strings.Contains(arg, "=") {
+		return false
+	}
+	return strings.HasPrefix(arg, "--") || len(arg) == 2
+}
@@ -0,0 +1,21 @@
+package argparser
+
+import (
+	"github.com/fastly/cli/pkg/naming"
+)
+
+// ValidateName checks a name given for a resource against the API's naming
+// rules (see naming.Validate), unless --skip-name-validation is set.
+//
+// NOTE: Commands validate a name before any API call, including those made to
+// resolve the service version, so that an invalid name fails fast.
+func (b Base) ValidateName(r naming.Resource, name string) error {
+	if b.Globals.Flags.SkipNameValidation {
+		return nil
+	}
+	if err := naming.Validate(r, name); err != nil {
+		b.Globals.ErrLog.Add(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package argparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// ErrFieldFormatCombo means both --field and --format were provided.
+var ErrFieldFormatCombo = fsterr.RemediationError{
+	Inner:       errors.New("--field and --format are mutually exclusive"),
+	Remediation: "Use --field to print a single value, or --format to combine several.",
+}
+
+// indexRegEx matches an array index in a field path (e.g. the [0] of
+// versions[0]).
+var indexRegEx = regexp.MustCompile(`\[(\d+)\]`)
+
+// decodeJSON returns the value as it's encoded in JSON, i.e. made of maps,
+// slices and scalars, so fields are selected by the names in the JSON output.
+//
+// NOTE: Numbers are decoded as json.Number so they're printed as they're
+// encoded (e.g. large integers aren't printed in exponent notation).
+func decodeJSON(value any) (any, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// splitFieldPath splits a field path into its keys and array indexes, e.g.
+// versions[0].number is "versions", "0" and "number".
+func splitFieldPath(path string) []string {
+	path = indexRegEx.ReplaceAllString(path, ".$1")
+	var segments []string
+	for _, s := range strings.Split(path, ".") {
+		if s != "" {
+			segments = append(segments, s)
+		}
+	}
+	return segments
+}
+
+// normaliseKey folds the differences between the names of the fields in the
+// JSON output, so a path like active_version.number selects ActiveVersion's
+// Number.
+func normaliseKey(key string) string {
+	key = strings.ToLower(key)
+	return strings.NewReplacer("_", "", "-", "").Replace(key)
+}
+
+// selectField returns the field of v at the path. Keys are matched exactly
+// unless fold is set, in which case they're matched by normaliseKey.
+//
+// An error names the part of the path that couldn't be found, along with the
+// available keys (or the length of an array).
+func selectField(v any, segments []string, fold bool) (any, error) {
+	for i, segment := range segments {
+		at := "the output"
+		if i > 0 {
+			at = "'" + strings.Join(segments[:i], ".") + "'"
+		}
+		switch current := v.(type) {
+		case map[string]any:
+			key, ok := lookupKey(current, segment, fold)
+			if !ok {
+				return nil, fsterr.RemediationError{
+					Inner:       fmt.Errorf("no field '%s' in %s (available keys: %s)", segment, at, strings.Join(sortedKeys(current), ", ")),
+					Remediation: fsterr.FieldRemediation,
+				}
+			}
+			v = current[key]
+		case []any:
+			n, err := strconv.Atoi(segment)
+			if err != nil || n < 0 || n >= len(current) {
+				return nil, fsterr.RemediationError{
+					Inner:       fmt.Errorf("no element '%s' in %s (an array of length %d)", segment, at, len(current)),
+					Remediation: fsterr.FieldRemediation,
+				}
+			}
+			v = current[n]
+		default:
+			return nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("no field '%s' in %s (it isn't an object or array)", segment, at),
+				Remediation: fsterr.FieldRemediation,
+			}
+		}
+	}
+	return v, nil
+}
+
+// lookupKey returns the key of m matching key.
+func lookupKey(m map[string]any, key string, fold bool) (string, bool) {
+	if _, ok := m[key]; ok {
+		return key, true
+	}
+	if !fold {
+		return "", false
+	}
+	want := normaliseKey(key)
+	for _, k := range sortedKeys(m) {
+		if normaliseKey(k) == want {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// writeField writes the field of the value at the path (see --field). A
+// string or number is written raw, i.e. without quotes, and an object or array
+// as compact JSON.
+func writeField(out io.Writer, value any, path string) error {
+	v, err := decodeJSON(value)
+	if err != nil {
+		return err
+	}
+	v, err = selectField(v, splitFieldPath(path), true)
+	if err != nil {
+		return err
+	}
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case json.Number:
+		s = v.String()
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		s = string(b)
+	}
+	return writeRaw(out, s)
+}
+
+// writeTemplate formats the value with the Go template (see --format). The
+// template is given the value as it's encoded in JSON, so {{.Name}} refers to
+// the Name key of the JSON output.
+//
+// The fields the template refers to are checked before it's executed, so a
+// wrong field is reported with the available keys.
+func writeTemplate(out io.Writer, value any, format string) error {
+	tmpl, err := template.New("format").Option("missingkey=error").Parse(format)
+	if err != nil {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid --format template: %w", err),
+			Remediation: "Check the template's syntax, see https://pkg.go.dev/text/template.",
+		}
+	}
+	v, err := decodeJSON(value)
+	if err != nil {
+		return err
+	}
+	if err := checkTemplateFields(tmpl.Tree.Root, v); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, v); err != nil {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("error executing --format template: %w", err),
+			Remediation: fsterr.FieldRemediation,
+		}
+	}
+	return writeRaw(out, buf.String())
+}
+
+// checkTemplateFields checks the fields of the root value the template refers
+// to exist. Fields of a value selected by {{range}} or {{with}} are checked
+// when the template is executed.
+func checkTemplateFields(node parse.Node, v any) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := checkTemplateFields(child, v); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		return checkPipeFields(n.Pipe, v)
+	case *parse.IfNode:
+		return checkBranchFields(&n.BranchNode, v, true)
+	case *parse.RangeNode:
+		return checkBranchFields(&n.BranchNode, v, false)
+	case *parse.WithNode:
+		return checkBranchFields(&n.BranchNode, v, false)
+	case *parse.TemplateNode:
+		return checkPipeFields(n.Pipe, v)
+	}
+	return nil
+}
+
+// checkBranchFields checks the fields of an {{if}}, {{range}} or {{with}}.
+// The body of a {{range}} or {{with}} has a different value, so isn't checked
+// (sameDot is false).
+func checkBranchFields(n *parse.BranchNode, v any, sameDot bool) error {
+	if err := checkPipeFields(n.Pipe, v); err != nil {
+		return err
+	}
+	if sameDot {
+		if err := checkTemplateFields(n.List, v); err != nil {
+			return err
+		}
+	}
+	return checkTemplateFields(n.ElseList, v)
+}
+
+func checkPipeFields(pipe *parse.PipeNode, v any) error {
+	if pipe == nil {
+		return nil
+	}
+	for _, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			switch arg := arg.(type) {
+			case *parse.FieldNode:
+				if _, err := selectField(v, arg.Ident, false); err != nil {
+					return err
+				}
+			case *parse.PipeNode:
+				if err := checkPipeFields(arg, v); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// writeRaw writes s terminated by a single newline, i.e. a newline is only
+// added if s doesn't already end with one.
+func writeRaw(out io.Writer, s string) error {
+	if !strings.HasSuffix(s, "\n") {
+		s += "\n"
+	}
+	_, err := io.WriteString(out, s)
+	return err
+}
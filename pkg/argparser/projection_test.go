@@ -0,0 +1,157 @@
+package argparser_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+// TestWriteJSONProjection validates --field selects a single value of the
+// JSON output and --format applies a template to it.
+func TestWriteJSONProjection(t *testing.T) {
+	service := &fastly.ServiceDetail{
+		ServiceID:     fastly.ToPointer("123"),
+		Name:          fastly.ToPointer("Foo"),
+		Comment:       fastly.ToPointer("line one\n"),
+		ActiveVersion: &fastly.Version{Number: fastly.ToPointer(2), Active: fastly.ToPointer(true)},
+		Versions: []*fastly.Version{
+			{Number: fastly.ToPointer(1)},
+			{Number: fastly.ToPointer(2)},
+		},
+	}
+	type setting struct {
+		Key   string `json:"key"`
+		Value int64  `json:"value"`
+	}
+
+	scenarios := []struct {
+		name            string
+		value           any
+		field           string
+		format          string
+		wantOutput      string
+		wantError       string
+		wantRemediation string
+	}{
+		{
+			name:       "nested field",
+			value:      service,
+			field:      "active_version.number",
+			wantOutput: "2\n",
+		},
+		{
+			name:       "exact field name",
+			value:      service,
+			field:      "ActiveVersion.Active",
+			wantOutput: "true\n",
+		},
+		{
+			name:       "string",
+			value:      service,
+			field:      "name",
+			wantOutput: "Foo\n",
+		},
+		{
+			name:       "string ending with a newline",
+			value:      service,
+			field:      "comment",
+			wantOutput: "line one\n",
+		},
+		{
+			name:       "array index",
+			value:      service,
+			field:      "versions[1].number",
+			wantOutput: "2\n",
+		},
+		{
+			name:       "index of a list",
+			value:      []setting{{Key: "a", Value: 1}, {Key: "b", Value: 12345678901}},
+			field:      "[1].value",
+			wantOutput: "12345678901\n",
+		},
+		{
+			name:       "object",
+			value:      []setting{{Key: "a", Value: 1}},
+			field:      "0",
+			wantOutput: `{"key":"a","value":1}` + "\n",
+		},
+		{
+			name:            "unknown field",
+			value:           service,
+			field:           "active_version.nmber",
+			wantError:       "no field 'nmber' in 'active_version' (available keys: Active, Comment, CreatedAt, DeletedAt, Deployed, Locked, Number, ServiceID, Staging, Testing, UpdatedAt)",
+			wantRemediation: "run it with --json",
+		},
+		{
+			name:      "index out of range",
+			value:     service,
+			field:     "versions[2]",
+			wantError: "no element '2' in 'versions' (an array of length 2)",
+		},
+		{
+			name:      "field of a scalar",
+			value:     service,
+			field:     "name.first",
+			wantError: "no field 'first' in 'name' (it isn't an object or array)",
+		},
+		{
+			name:       "template",
+			value:      service,
+			format:     "Service {{.Name}} is at v{{.ActiveVersion.Number}}",
+			wantOutput: "Service Foo is at v2\n",
+		},
+		{
+			name:       "template with range",
+			value:      service,
+			format:     "{{range .Versions}}{{.Number}}\n{{end}}",
+			wantOutput: "1\n2\n",
+		},
+		{
+			name:      "template with an unknown field",
+			value:     service,
+			format:    "{{.ActiveVersion.Nmber}}",
+			wantError: "no field 'Nmber' in 'ActiveVersion' (available keys: Active,",
+		},
+		{
+			name:      "template with an unknown field in a range",
+			value:     service,
+			format:    "{{range .Versions}}{{.Nmber}}{{end}}",
+			wantError: "error executing --format template",
+		},
+		{
+			name:            "template syntax error",
+			value:           service,
+			format:          "{{.Name",
+			wantError:       "invalid --format template",
+			wantRemediation: "text/template",
+		},
+		{
+			name:       "--format json",
+			value:      setting{Key: "a", Value: 1},
+			format:     "json",
+			wantOutput: "{\n  \"key\": \"a\",\n  \"value\": 1\n}\n",
+		},
+		{
+			name:      "--field and --format",
+			value:     service,
+			field:     "name",
+			format:    "{{.Name}}",
+			wantError: "mutually exclusive",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			j := argparser.JSONOutput{Enabled: true, Field: testcase.field, Format: testcase.format}
+			var buf bytes.Buffer
+			ok, err := j.WriteJSON(&buf, testcase.value)
+			testutil.AssertBool(t, true, ok)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertRemediationErrorContains(t, err, testcase.wantRemediation)
+			testutil.AssertString(t, testcase.wantOutput, buf.String())
+		})
+	}
+}
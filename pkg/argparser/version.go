@@ -0,0 +1,95 @@
+package argparser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/api"
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// VersionFlags are the user inputs that determine which service version a
+// command operates on.
+type VersionFlags struct {
+	// AllowActiveLocked permits resolving an active or locked version without
+	// cloning it (e.g. for commands that only read from the version).
+	AllowActiveLocked bool
+	// AutoClone is the --autoclone flag.
+	AutoClone OptionalAutoClone
+	// ServiceVersion is the --version flag (a number, "latest" or "active").
+	ServiceVersion OptionalServiceVersion
+}
+
+// ResolveEditableVersion returns the service version identified by the
+// --version flag, cloning it if it's active or locked and --autoclone is set.
+// The returned bool reports whether the version is a clone.
+//
+// If the version isn't editable, and neither --autoclone is set nor
+// AllowActiveLocked, then a RemediationError suggesting --autoclone is returned
+// along with the (uneditable) version. This is checked before the command makes
+// any changes, so it fails without changing the version at all.
+//
+// NOTE: The versions are listed with a single API request, which is only sent
+// once per invocation (see httpclient.Memo).
+func ResolveEditableVersion(ctx context.Context, client api.Interface, serviceID string, flags VersionFlags) (version *fastly.Version, cloned bool, err error) {
+	v, err := flags.ServiceVersion.Parse(serviceID, client)
+	if err != nil {
+		return nil, false, err
+	}
+	return EditableVersion(ctx, client, serviceID, v, flags)
+}
+
+// EditableVersion is the same as ResolveEditableVersion but for a service
+// version v that the caller has already resolved (flags.ServiceVersion is
+// ignored). It's for commands that must inspect the version before deciding
+// whether to clone it.
+func EditableVersion(ctx context.Context, client api.Interface, serviceID string, v *fastly.Version, flags VersionFlags) (version *fastly.Version, cloned bool, err error) {
+	if !fastly.ToValue(v.Active) && !fastly.ToValue(v.Locked) {
+		return v, false, nil
+	}
+	if !flags.AutoClone.Value {
+		if flags.AllowActiveLocked {
+			return v, false, nil
+		}
+		return v, false, fsterr.RemediationError{
+			Inner:       fmt.Errorf("service version %d is not editable", fastly.ToValue(v.Number)),
+			Remediation: fsterr.AutoCloneRemediation,
+			Code:        fsterr.CodeVersionLocked,
+		}
+	}
+
+	// Don't create a clone the user will never use (e.g. they pressed Ctrl-C).
+	if err := ctx.Err(); err != nil {
+		return v, false, err
+	}
+	clone, err := client.CloneVersion(&fastly.CloneVersionInput{
+		ServiceID:      serviceID,
+		ServiceVersion: fastly.ToValue(v.Number),
+	})
+	if err != nil {
+		return v, false, fmt.Errorf("error cloning service version: %w", err)
+	}
+	return clone, true, nil
+}
+
+// LockedVersionError returns the error for a change the API rejected because
+// the service version was locked (or activated) after the command checked it
+// was editable (see fsterr.IsVersionLocked). change describes the rejected
+// change (e.g. "updating snippet 'recv'") and done the changes that were made
+// to the version before it, so the user knows which of them succeeded.
+func LockedVersionError(version int, change string, done []string, err error) error {
+	remediation := fsterr.AutoCloneRemediation
+	if len(done) > 0 {
+		remediation += fmt.Sprintf("\n\nThese changes were made to version %d before it was locked:\n\n  - %s", version, strings.Join(done, "\n  - "))
+	} else {
+		remediation += fmt.Sprintf("\n\nNo changes were made to version %d.", version)
+	}
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("service version %d is not editable, it was locked before %s: %w", version, change, err),
+		Remediation: remediation,
+		Code:        fsterr.CodeVersionLocked,
+	}
+}
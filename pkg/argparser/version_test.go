@@ -0,0 +1,177 @@
+package argparser_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+// resolveVersions returns the versions of a service where version 1 is
+// locked, version 2 is active and version 3 (the latest) is editable.
+func resolveVersions(*fastly.ListVersionsInput) ([]*fastly.Version, error) {
+	return []*fastly.Version{
+		{Number: fastly.ToPointer(1), Locked: fastly.ToPointer(true)},
+		{Number: fastly.ToPointer(2), Active: fastly.ToPointer(true), Locked: fastly.ToPointer(true)},
+		{Number: fastly.ToPointer(3)},
+	}, nil
+}
+
+func TestResolveEditableVersion(t *testing.T) {
+	const (
+		omitted = iota
+		enabled
+		disabled
+	)
+
+	for _, testcase := range []struct {
+		name              string
+		version           string
+		autoClone         int
+		allowActiveLocked bool
+		listVersionsFn    func(*fastly.ListVersionsInput) ([]*fastly.Version, error)
+		cloneVersionFn    func(*fastly.CloneVersionInput) (*fastly.Version, error)
+		cancelled         bool
+		wantVersion       int
+		wantCloned        bool
+		wantError         string
+		wantRemediation   string
+	}{
+		// Version selection.
+		{name: "no --version resolves the active version", autoClone: enabled, wantVersion: 4, wantCloned: true},
+		{name: "--version latest", version: "latest", wantVersion: 3},
+		{name: "--version LATEST", version: "LATEST", wantVersion: 3},
+		{name: "--version active", version: "active", autoClone: enabled, wantVersion: 4, wantCloned: true},
+		{name: "--version number", version: "3", wantVersion: 3},
+		{name: "--version unknown number", version: "9", wantError: "specified service version not found: 9"},
+		{name: "--version invalid", version: "abc", wantError: `strconv.Atoi: parsing "abc": invalid syntax`},
+		{
+			name:    "no --version and no active version resolves the latest version",
+			version: "",
+			listVersionsFn: func(*fastly.ListVersionsInput) ([]*fastly.Version, error) {
+				return []*fastly.Version{
+					{Number: fastly.ToPointer(2)},
+					{Number: fastly.ToPointer(1), Locked: fastly.ToPointer(true)},
+				}, nil
+			},
+			wantVersion: 2,
+		},
+		{
+			name:    "--version active with no active version",
+			version: "active",
+			listVersionsFn: func(*fastly.ListVersionsInput) ([]*fastly.Version, error) {
+				return []*fastly.Version{{Number: fastly.ToPointer(1)}}, nil
+			},
+			wantError: "no active service version found",
+		},
+		{
+			name: "service with no versions",
+			listVersionsFn: func(*fastly.ListVersionsInput) ([]*fastly.Version, error) {
+				return []*fastly.Version{}, nil
+			},
+			wantError: "error listing service versions: no versions available",
+		},
+		{
+			name: "error listing versions",
+			listVersionsFn: func(*fastly.ListVersionsInput) ([]*fastly.Version, error) {
+				return nil, testutil.Err
+			},
+			wantError: "error listing service versions: test error",
+		},
+
+		// Editable version.
+		{name: "editable", version: "3", wantVersion: 3},
+		{name: "editable with --autoclone", version: "3", autoClone: enabled, wantVersion: 3},
+		{name: "editable with --autoclone=false", version: "3", autoClone: disabled, wantVersion: 3},
+		{name: "editable allowing active/locked", version: "3", allowActiveLocked: true, wantVersion: 3},
+		{name: "editable with --autoclone allowing active/locked", version: "3", autoClone: enabled, allowActiveLocked: true, wantVersion: 3},
+		{name: "editable with --autoclone=false allowing active/locked", version: "3", autoClone: disabled, allowActiveLocked: true, wantVersion: 3},
+
+		// Active version.
+		{name: "active", version: "2", wantVersion: 2, wantError: "service version 2 is not editable", wantRemediation: fsterr.AutoCloneRemediation},
+		{name: "active with --autoclone", version: "2", autoClone: enabled, wantVersion: 4, wantCloned: true},
+		{name: "active with --autoclone=false", version: "2", autoClone: disabled, wantVersion: 2, wantError: "service version 2 is not editable", wantRemediation: fsterr.AutoCloneRemediation},
+		{name: "active allowing active/locked", version: "2", allowActiveLocked: true, wantVersion: 2},
+		{name: "active with --autoclone allowing active/locked", version: "2", autoClone: enabled, allowActiveLocked: true, wantVersion: 4, wantCloned: true},
+		{name: "active with --autoclone=false allowing active/locked", version: "2", autoClone: disabled, allowActiveLocked: true, wantVersion: 2},
+
+		// Locked version.
+		{name: "locked", version: "1", wantVersion: 1, wantError: "service version 1 is not editable", wantRemediation: fsterr.AutoCloneRemediation},
+		{name: "locked with --autoclone", version: "1", autoClone: enabled, wantVersion: 4, wantCloned: true},
+		{name: "locked with --autoclone=false", version: "1", autoClone: disabled, wantVersion: 1, wantError: "service version 1 is not editable", wantRemediation: fsterr.AutoCloneRemediation},
+		{name: "locked allowing active/locked", version: "1", allowActiveLocked: true, wantVersion: 1},
+		{name: "locked with --autoclone allowing active/locked", version: "1", autoClone: enabled, allowActiveLocked: true, wantVersion: 4, wantCloned: true},
+		{name: "locked with --autoclone=false allowing active/locked", version: "1", autoClone: disabled, allowActiveLocked: true, wantVersion: 1},
+
+		// Cloning failures.
+		{
+			name:      "error cloning",
+			version:   "1",
+			autoClone: enabled,
+			cloneVersionFn: func(*fastly.CloneVersionInput) (*fastly.Version, error) {
+				return nil, testutil.Err
+			},
+			wantVersion: 1,
+			wantError:   "error cloning service version: test error",
+		},
+		{
+			name:      "cancelled before cloning",
+			version:   "1",
+			autoClone: enabled,
+			cloneVersionFn: func(*fastly.CloneVersionInput) (*fastly.Version, error) {
+				t.Fatal("unexpected clone")
+				return nil, nil
+			},
+			cancelled:   true,
+			wantVersion: 1,
+			wantError:   context.Canceled.Error(),
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			api := mock.API{
+				CloneVersionFn: cloneVersionResult(4),
+				ListVersionsFn: resolveVersions,
+			}
+			if testcase.listVersionsFn != nil {
+				api.ListVersionsFn = testcase.listVersionsFn
+			}
+			if testcase.cloneVersionFn != nil {
+				api.CloneVersionFn = testcase.cloneVersionFn
+			}
+
+			flags := argparser.VersionFlags{AllowActiveLocked: testcase.allowActiveLocked}
+			if testcase.version != "" {
+				flags.ServiceVersion.WasSet = true
+				flags.ServiceVersion.Value = testcase.version
+			}
+			if testcase.autoClone != omitted {
+				flags.AutoClone.WasSet = true
+				flags.AutoClone.Value = testcase.autoClone == enabled
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if testcase.cancelled {
+				cancel()
+			}
+
+			v, cloned, err := argparser.ResolveEditableVersion(ctx, api, "123", flags)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertRemediationErrorContains(t, err, testcase.wantRemediation)
+			testutil.AssertBool(t, testcase.wantRemediation == fsterr.AutoCloneRemediation, fsterr.IsVersionLocked(err))
+			testutil.AssertBool(t, testcase.wantCloned, cloned)
+			if testcase.wantVersion == 0 {
+				if v != nil {
+					t.Errorf("wanted no version, have %d", fastly.ToValue(v.Number))
+				}
+				return
+			}
+			testutil.AssertEqual(t, testcase.wantVersion, fastly.ToValue(v.Number))
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package artifacts
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fastly/cli/pkg/manifest"
+)
+
+// Categories of artifacts.
+const (
+	CategoryBuild       = "build"
+	CategoryStarterKits = "starter-kits"
+	CategoryViceroy     = "viceroy"
+	CategoryUpdates     = "updates"
+)
+
+// Scopes of artifacts.
+const (
+	// ScopeProject artifacts belong to the Compute project being inspected.
+	ScopeProject = "project"
+	// ScopeGlobal artifacts are shared by all projects.
+	ScopeGlobal = "global"
+)
+
+// Locations are the directories searched for artifacts.
+type Locations struct {
+	// ProjectDir is the Compute project directory (empty to skip the project).
+	// It's ignored if it doesn't contain a fastly.toml manifest.
+	ProjectDir string
+	// InstallDir is where the CLI installs binaries (e.g. Viceroy).
+	InstallDir string
+	// TempDir is where the CLI creates temporary files and directories.
+	TempDir string
+}
+
+// rule describes where artifacts of a category live.
+//
+// NOTE: Patterns are deliberately specific (never a whole directory the user
+// might also keep source code in) so that pruning can't delete source files.
+type rule struct {
+	category string
+	scope    string
+	root     func(Locations) string
+	pattern  string
+}
+
+var rules = []rule{
+	{CategoryBuild, ScopeProject, project, "bin/main.wasm"},
+	{CategoryBuild, ScopeProject, project, "pkg/*.tar.gz"},
+	{CategoryBuild, ScopeProject, project, "pkg/package"},
+	{CategoryBuild, ScopeGlobal, temp, "fastly-build-*"},
+	{CategoryStarterKits, ScopeGlobal, temp, "package-init-*"},
+	{CategoryViceroy, ScopeGlobal, install, "viceroy*"},
+	{CategoryUpdates, ScopeGlobal, temp, "fastly-download*"},
+	{CategoryUpdates, ScopeGlobal, temp, "fastly-update*"},
+}
+
+func project(l Locations) string { return l.ProjectDir }
+func install(l Locations) string { return l.InstallDir }
+func temp(l Locations) string    { return l.TempDir }
+
+// Entry is a file or directory generated or downloaded by the CLI.
+type Entry struct {
+	Category string    `json:"category"`
+	Scope    string    `json:"scope"`
+	Path     string    `json:"path"`
+	Size     int64     `json:"size_bytes"`
+	ModTime  time.Time `json:"modified"`
+	// root is the directory the entry was found in.
+	root string
+}
+
+// Find returns the artifacts in the given locations, ordered by scope,
+// category and path.
+func Find(l Locations) ([]Entry, error) {
+	if l.ProjectDir != "" {
+		if _, err := os.Stat(filepath.Join(l.ProjectDir, manifest.Filename)); err != nil {
+			l.ProjectDir = ""
+		}
+	}
+
+	var entries []Entry
+	for _, r := range rules {
+		root := r.root(l)
+		if root == "" {
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(root, filepath.FromSlash(r.pattern)))
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			size, mtime, err := usage(m)
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					continue // removed since the glob (e.g. by a concurrent build)
+				}
+				return nil, fmt.Errorf("failed to measure '%s': %w", m, err)
+			}
+			entries = append(entries, Entry{
+				Category: r.category,
+				Scope:    r.scope,
+				Path:     m,
+				Size:     size,
+				ModTime:  mtime,
+				root:     root,
+			})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Scope != b.Scope {
+			return a.Scope == ScopeProject
+		}
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.Path < b.Path
+	})
+	return entries, nil
+}
+
+// usage returns the total size of the regular files at path, and the most
+// recent modification time of anything within it.
+func usage(path string) (size int64, mtime time.Time, err error) {
+	err = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if fi.Mode().IsRegular() {
+			size += fi.Size()
+		}
+		if fi.ModTime().After(mtime) {
+			mtime = fi.ModTime()
+		}
+		return nil
+	})
+	return size, mtime, err
+}
+
+// Usage is the disk usage of a category of artifacts.
+type Usage struct {
+	Category string `json:"category"`
+	Scope    string `json:"scope"`
+	Entries  int    `json:"entries"`
+	Size     int64  `json:"size_bytes"`
+}
+
+// Summarise totals the entries by scope and category, in the order of entries.
+func Summarise(entries []Entry) []Usage {
+	var usages []Usage
+	index := make(map[string]int)
+	for _, e := range entries {
+		key := e.Scope + "/" + e.Category
+		i, ok := index[key]
+		if !ok {
+			i = len(usages)
+			index[key] = i
+			usages = append(usages, Usage{Category: e.Category, Scope: e.Scope})
+		}
+		usages[i].Entries++
+		usages[i].Size += e.Size
+	}
+	return usages
+}
+
+// Total returns the combined size of the entries.
+func Total(entries []Entry) (size int64) {
+	for _, e := range entries {
+		size += e.Size
+	}
+	return size
+}
+
+// PruneOptions selects the entries to prune.
+type PruneOptions struct {
+	// All prunes every entry regardless of age.
+	All bool
+	// OlderThan prunes entries not modified within the duration.
+	OlderThan time.Duration
+	// Now is the time OlderThan is relative to.
+	Now time.Time
+	// DryRun selects the entries without deleting anything.
+	DryRun bool
+}
+
+// Prune deletes the entries selected by opts and returns them. When an entry
+// can't be deleted, the entries pruned so far are returned with the error.
+func Prune(entries []Entry, opts PruneOptions) ([]Entry, error) {
+	var pruned []Entry
+	for _, e := range entries {
+		if !opts.All && opts.Now.Sub(e.ModTime) < opts.OlderThan {
+			continue
+		}
+		if !opts.DryRun {
+			if err := remove(e); err != nil {
+				return pruned, err
+			}
+		}
+		pruned = append(pruned, e)
+	}
+	return pruned, nil
+}
+
+// remove deletes an entry after checking it's within the directory it was
+// found in.
+func remove(e Entry) error {
+	rel, err := filepath.Rel(e.root, e.Path)
+	if e.root == "" || err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("refusing to delete '%s': not within '%s'", e.Path, e.root)
+	}
+	if err := os.RemoveAll(e.Path); err != nil {
+		return fmt.Errorf("failed to delete '%s': %w", e.Path, err)
+	}
+	return nil
+}
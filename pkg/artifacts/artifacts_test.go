@@ -0,0 +1,185 @@
+package artifacts_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fastly/cli/pkg/artifacts"
+)
+
+var now = time.Date(2021, time.June, 15, 23, 0, 0, 0, time.UTC)
+
+// newTree creates a synthetic project, install and temp directory, with files
+// of the given size (in bytes) and age.
+func newTree(t *testing.T) artifacts.Locations {
+	t.Helper()
+	root := t.TempDir()
+	l := artifacts.Locations{
+		ProjectDir: filepath.Join(root, "project"),
+		InstallDir: filepath.Join(root, "install"),
+		TempDir:    filepath.Join(root, "tmp"),
+	}
+	for _, f := range []struct {
+		path string
+		size int
+		age  time.Duration
+	}{
+		// Project
+		{"project/fastly.toml", 10, 0},
+		{"project/src/main.rs", 20, 0},
+		{"project/pkg/lib.go", 30, 0},
+		{"project/bin/main.wasm", 100, 48 * time.Hour},
+		{"project/pkg/project.tar.gz", 50, time.Hour},
+		// Install
+		{"install/config.toml", 10, 72 * time.Hour},
+		{"install/viceroy", 1000, 72 * time.Hour},
+		// Temp
+		{"tmp/package-init-123/fastly.toml", 5, 72 * time.Hour},
+		{"tmp/package-init-123/src/index.js", 5, time.Hour},
+		{"tmp/fastly-update456/fastly", 500, 72 * time.Hour},
+		{"tmp/other/file", 10, 72 * time.Hour},
+	} {
+		p := filepath.Join(root, filepath.FromSlash(f.path))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, make([]byte, f.size), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		mtime := now.Add(-f.age)
+		if err := os.Chtimes(p, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// NOTE: Directory modification times change when files are written to
+	// them, so they're set once all the files are created.
+	for _, d := range []string{"tmp/package-init-123", "tmp/package-init-123/src", "tmp/fastly-update456"} {
+		p := filepath.Join(root, filepath.FromSlash(d))
+		mtime := now.Add(-72 * time.Hour)
+		if err := os.Chtimes(p, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return l
+}
+
+func TestFind(t *testing.T) {
+	l := newTree(t)
+	entries, err := artifacts.Find(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type summary struct {
+		scope, category, path string
+		size                  int64
+	}
+	var have []summary
+	for _, e := range entries {
+		rel, err := filepath.Rel(filepath.Dir(l.ProjectDir), e.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		have = append(have, summary{e.Scope, e.Category, filepath.ToSlash(rel), e.Size})
+	}
+	want := []summary{
+		{artifacts.ScopeProject, artifacts.CategoryBuild, "project/bin/main.wasm", 100},
+		{artifacts.ScopeProject, artifacts.CategoryBuild, "project/pkg/project.tar.gz", 50},
+		{artifacts.ScopeGlobal, artifacts.CategoryStarterKits, "tmp/package-init-123", 10},
+		{artifacts.ScopeGlobal, artifacts.CategoryUpdates, "tmp/fastly-update456", 500},
+		{artifacts.ScopeGlobal, artifacts.CategoryViceroy, "install/viceroy", 1000},
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("want %+v, have %+v", want, have)
+	}
+
+	wantUsage := []artifacts.Usage{
+		{Category: artifacts.CategoryBuild, Scope: artifacts.ScopeProject, Entries: 2, Size: 150},
+		{Category: artifacts.CategoryStarterKits, Scope: artifacts.ScopeGlobal, Entries: 1, Size: 10},
+		{Category: artifacts.CategoryUpdates, Scope: artifacts.ScopeGlobal, Entries: 1, Size: 500},
+		{Category: artifacts.CategoryViceroy, Scope: artifacts.ScopeGlobal, Entries: 1, Size: 1000},
+	}
+	if have := artifacts.Summarise(entries); !reflect.DeepEqual(wantUsage, have) {
+		t.Errorf("want %+v, have %+v", wantUsage, have)
+	}
+	if have := artifacts.Total(entries); have != 1660 {
+		t.Errorf("want total 1660, have %d", have)
+	}
+}
+
+func TestFindOutsideProject(t *testing.T) {
+	l := newTree(t)
+	if err := os.Remove(filepath.Join(l.ProjectDir, "fastly.toml")); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := artifacts.Find(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.Scope == artifacts.ScopeProject {
+			t.Errorf("unexpected project artifact without a manifest: %s", e.Path)
+		}
+	}
+}
+
+func TestPrune(t *testing.T) {
+	for _, testcase := range []struct {
+		name       string
+		opts       artifacts.PruneOptions
+		wantPruned []string
+	}{
+		{
+			name:       "older than",
+			opts:       artifacts.PruneOptions{OlderThan: 24 * time.Hour, Now: now},
+			wantPruned: []string{"project/bin/main.wasm", "tmp/fastly-update456", "install/viceroy"},
+		},
+		{
+			name:       "all",
+			opts:       artifacts.PruneOptions{All: true, Now: now},
+			wantPruned: []string{"project/bin/main.wasm", "project/pkg/project.tar.gz", "tmp/package-init-123", "tmp/fastly-update456", "install/viceroy"},
+		},
+		{
+			name:       "dry run",
+			opts:       artifacts.PruneOptions{All: true, Now: now, DryRun: true},
+			wantPruned: []string{"project/bin/main.wasm", "project/pkg/project.tar.gz", "tmp/package-init-123", "tmp/fastly-update456", "install/viceroy"},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			l := newTree(t)
+			root := filepath.Dir(l.ProjectDir)
+			entries, err := artifacts.Find(l)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pruned, err := artifacts.Prune(entries, testcase.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var have []string
+			for _, e := range pruned {
+				rel, _ := filepath.Rel(root, e.Path)
+				have = append(have, filepath.ToSlash(rel))
+				_, err := os.Stat(e.Path)
+				if exists := err == nil; exists != testcase.opts.DryRun {
+					t.Errorf("%s: want exists=%t, have %t", rel, testcase.opts.DryRun, exists)
+				}
+			}
+			if !reflect.DeepEqual(testcase.wantPruned, have) {
+				t.Errorf("want %v, have %v", testcase.wantPruned, have)
+			}
+
+			// Source files and other files in the searched directories are never
+			// deleted.
+			for _, p := range []string{"project/fastly.toml", "project/src/main.rs", "project/pkg/lib.go", "install/config.toml", "tmp/other/file"} {
+				if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(p))); err != nil {
+					t.Errorf("%s was deleted", p)
+				}
+			}
+		})
+	}
+}
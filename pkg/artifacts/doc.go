@@ -0,0 +1,4 @@
+// Package artifacts locates the files the CLI generates or downloads outside of
+// a project's source code (build output, Viceroy binaries, starter kit clones,
+// update downloads) so their disk usage can be reported and pruned.
+package artifacts
@@ -0,0 +1,234 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// LogPath is the location of the fastly CLI audit log.
+var LogPath = func() string {
+	if dir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(dir, "fastly", "audit.log")
+	}
+	if dir, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(dir, ".fastly", "audit.log")
+	}
+	panic("unable to deduce user config dir or user home dir")
+}()
+
+// FileRotationSize represents the size the log file needs to be before it's
+// rotated. The rotated file is kept alongside with a ".1" suffix, replacing any
+// previously rotated file.
+//
+// NOTE: This is a variable so the test suite can use a much smaller value.
+var FileRotationSize int64 = 5242880 // 5mb
+
+// Outcomes of a command.
+const (
+	OutcomeError   = "error"
+	OutcomeSuccess = "success"
+)
+
+// Redacted replaces the value of a sensitive flag.
+const Redacted = "REDACTED"
+
+// Entry represents a single invocation of a mutating command.
+type Entry struct {
+	Time           time.Time `json:"time"`
+	Command        string    `json:"command"`
+	Args           []string  `json:"args"`
+	ServiceID      string    `json:"service_id,omitempty"`
+	ServiceVersion string    `json:"service_version,omitempty"`
+	Outcome        string    `json:"outcome"`
+	Error          string    `json:"error,omitempty"`
+	DurationMS     int64     `json:"duration_ms"`
+}
+
+// mutatingVerbs are the subcommands that change remote state.
+var mutatingVerbs = map[string]bool{
+	"activate":   true,
+	"clone":      true,
+	"create":     true,
+	"deactivate": true,
+	"delete":     true,
+	"deploy":     true,
+	"disable":    true,
+	"enable":     true,
+	"import":     true,
+	"lock":       true,
+	"publish":    true,
+	"remove":     true,
+	"rotate":     true,
+	"set":        true,
+	"sync":       true,
+	"update":     true,
+	"upload":     true,
+}
+
+// localCommands only change local state and so aren't audited. Either the
+// top-level command or its first subcommand (e.g. "service alias") is matched.
+var localCommands = map[string]bool{
+	"alias":         true,
+	"completion":    true,
+	"config":        true,
+	"history":       true,
+	"profile":       true,
+	"service alias": true,
+}
+
+// Mutating reports whether the command (e.g. "service create") performs a
+// mutating API call.
+func Mutating(command string) bool {
+	segs := strings.Fields(command)
+	if len(segs) == 0 || localCommands[segs[0]] || (len(segs) > 1 && localCommands[segs[0]+" "+segs[1]]) {
+		return false
+	}
+	if segs[0] == "purge" {
+		return true
+	}
+	// NOTE: A top-level `update` updates the CLI itself.
+	return len(segs) > 1 && mutatingVerbs[segs[len(segs)-1]]
+}
+
+// SensitiveFlags are the flags whose values are redacted.
+var SensitiveFlags = map[string]bool{
+	"--access-key":       true,
+	"--auth-token":       true,
+	"--automation-token": true,
+	"--client-key":       true,
+	"--password":         true,
+	"--sas-token":        true,
+	"--secret-key":       true,
+	"--ssl-client-key":   true,
+	"--tls-client-key":   true,
+	"--token":            true,
+	"-t":                 true,
+}
+
+// RedactArgs returns a copy of args with the values of SensitiveFlags replaced
+// by Redacted.
+func RedactArgs(args []string) []string {
+	redacted := make([]string, 0, len(args))
+	var redactNext bool
+	for _, a := range args {
+		if redactNext {
+			redacted = append(redacted, Redacted)
+			redactNext = false
+			continue
+		}
+		name, _, hasValue := strings.Cut(a, "=")
+		switch {
+		case SensitiveFlags[name] && hasValue:
+			a = name + "=" + Redacted
+		case SensitiveFlags[name]:
+			redactNext = true
+		case !strings.HasPrefix(a, "-"):
+			a = fsterr.FilterToken(a)
+		}
+		redacted = append(redacted, a)
+	}
+	return redacted
+}
+
+// Record appends the entry to the log as a JSON line, rotating the log first
+// if it has reached FileRotationSize.
+func Record(path string, e Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if fi, err := os.Stat(path); err == nil && fi.Size() >= FileRotationSize {
+		if err := os.Rename(path, path+".1"); err != nil {
+			return fmt.Errorf("failed to rotate audit log: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	//
+	// Disabling as the input is determined from our own package.
+	/* #nosec */
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("error accessing audit log file: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read returns the entries from the rotated log followed by the current log,
+// oldest first. Lines that can't be decoded are skipped.
+func Read(path string) ([]Entry, error) {
+	var entries []Entry
+	for _, p := range []string{path + ".1", path} {
+		es, err := readFile(p)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, es...)
+	}
+	return entries, nil
+}
+
+func readFile(path string) ([]Entry, error) {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	//
+	// Disabling as the input is determined from our own package.
+	/* #nosec */
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading audit log file: %w", err)
+	}
+	defer f.Close() // #nosec G307
+
+	var entries []Entry
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for s.Scan() {
+		var e Entry
+		if err := json.Unmarshal(s.Bytes(), &e); err == nil {
+			entries = append(entries, e)
+		}
+	}
+	return entries, s.Err()
+}
+
+// Filter returns the entries matching the service ID (if set) and recorded
+// within the time range (if set).
+func Filter(entries []Entry, serviceID string, from, to time.Time) []Entry {
+	var filtered []Entry
+	for _, e := range entries {
+		if serviceID != "" && e.ServiceID != serviceID {
+			continue
+		}
+		if !from.IsZero() && e.Time.Before(from) {
+			continue
+		}
+		if !to.IsZero() && e.Time.After(to) {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+	return filtered
+}
@@ -0,0 +1,103 @@
+package audit_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fastly/cli/pkg/audit"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestMutating(t *testing.T) {
+	for command, want := range map[string]bool{
+		"service create":           true,
+		"service-version activate": true,
+		"compute deploy":           true,
+		"purge":                    true,
+		"service tag set":          true,
+		"service tag remove":       true,
+		"auth-token rotate":        true,
+		"service list":             false,
+		"compute build":            false,
+		"profile create":           false,
+		"service alias remove":     false,
+		"update":                   false,
+		"":                         false,
+	} {
+		if got := audit.Mutating(command); got != want {
+			t.Errorf("Mutating(%q): want %t, have %t", command, want, got)
+		}
+	}
+}
+
+func TestRedactArgs(t *testing.T) {
+	args := testutil.Args("service create --name foo --token abc -t=def --secret-key ghi --password=jkl --key surrogate")
+	want := "service create --name foo --token REDACTED -t=REDACTED --secret-key REDACTED --password=REDACTED --key surrogate"
+	testutil.AssertString(t, want, strings.Join(audit.RedactArgs(args), " "))
+}
+
+func TestRecordRotation(t *testing.T) {
+	defer func(size int64) {
+		audit.FileRotationSize = size
+	}(audit.FileRotationSize)
+	audit.FileRotationSize = 150
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	entry := func(command string) audit.Entry {
+		return audit.Entry{
+			Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Command: command,
+			Args:    []string{command},
+			Outcome: audit.OutcomeSuccess,
+		}
+	}
+
+	// Each entry is ~100 bytes so the second record exceeds the threshold and
+	// the third record triggers the rotation.
+	for _, c := range []string{"one", "two", "three"} {
+		testutil.AssertNoError(t, audit.Record(path, entry(c)))
+	}
+
+	rotated, err := os.ReadFile(path + ".1")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 2, strings.Count(string(rotated), "\n"))
+
+	current, err := os.ReadFile(path)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, 1, strings.Count(string(current), "\n"))
+
+	entries, err := audit.Read(path)
+	testutil.AssertNoError(t, err)
+	var commands []string
+	for _, e := range entries {
+		commands = append(commands, e.Command)
+	}
+	testutil.AssertEqual(t, []string{"one", "two", "three"}, commands)
+}
+
+func TestFilter(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+	entries := []audit.Entry{
+		{Command: "a", ServiceID: "123", Time: day(1)},
+		{Command: "b", ServiceID: "456", Time: day(2)},
+		{Command: "c", ServiceID: "123", Time: day(3)},
+	}
+	commands := func(es []audit.Entry) string {
+		var cs []string
+		for _, e := range es {
+			cs = append(cs, e.Command)
+		}
+		return strings.Join(cs, ",")
+	}
+
+	testutil.AssertString(t, "a,b,c", commands(audit.Filter(entries, "", time.Time{}, time.Time{})))
+	testutil.AssertString(t, "a,c", commands(audit.Filter(entries, "123", time.Time{}, time.Time{})))
+	testutil.AssertString(t, "b,c", commands(audit.Filter(entries, "", day(2), time.Time{})))
+	testutil.AssertString(t, "a,b", commands(audit.Filter(entries, "", time.Time{}, day(2))))
+	testutil.AssertString(t, "c", commands(audit.Filter(entries, "123", day(2), day(3))))
+}
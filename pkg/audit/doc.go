@@ -0,0 +1,3 @@
+// Package audit records the invocations of mutating commands to a local log
+// file so they can be reviewed later (see `fastly history`).
+package audit
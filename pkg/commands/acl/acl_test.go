@@ -83,6 +83,27 @@ func TestACLCreate(t *testing.T) {
 			Args:       args("acl create --autoclone --name foo --service-id 123 --version 1"),
 			WantOutput: "Created ACL 'foo' (id: 456, service: 123, version: 4)",
 		},
+		{
+			Name:      "validate invalid --name fails before any API call",
+			Args:      args("acl create --name foo!bar --service-id 123 --version 3"),
+			WantError: `invalid ACL name "foo!bar": '!' isn't allowed, the name can contain only letters, digits, underscores and spaces`,
+		},
+		{
+			Name: "validate --skip-name-validation sends the name as it is",
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				CreateACLFn: func(i *fastly.CreateACLInput) (*fastly.ACL, error) {
+					return &fastly.ACL{
+						ACLID:          fastly.ToPointer("456"),
+						Name:           i.Name,
+						ServiceID:      fastly.ToPointer(i.ServiceID),
+						ServiceVersion: fastly.ToPointer(i.ServiceVersion),
+					}, nil
+				},
+			},
+			Args:       args("acl create --name foo!bar --service-id 123 --version 3 --skip-name-validation"),
+			WantOutput: "Created ACL 'foo!bar' (id: 456, service: 123, version: 3)",
+		},
 	}
 
 	for testcaseIdx := range scenarios {
@@ -9,6 +9,7 @@ import (
 	"github.com/fastly/cli/pkg/argparser"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
 )
 
 // NewDescribeCommand returns a usable command registered under the parent.
@@ -30,7 +31,7 @@ func NewDescribeCommand(parent argparser.Registerer, g *global.Data) *DescribeCo
 	})
 
 	// Optional.
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	c.RegisterFlag(argparser.StringFlagOpts{
 		Name:        argparser.FlagServiceIDName,
 		Description: argparser.FlagServiceIDDesc,
@@ -118,13 +119,13 @@ func (c *DescribeCommand) print(out io.Writer, a *fastly.ACL) error {
 	fmt.Fprintf(out, "Name: %s\n", fastly.ToValue(a.Name))
 	fmt.Fprintf(out, "ID: %s\n\n", fastly.ToValue(a.ACLID))
 	if a.CreatedAt != nil {
-		fmt.Fprintf(out, "Created at: %s\n", a.CreatedAt)
+		fmt.Fprintf(out, "Created at: %s\n", text.FormatTime(a.CreatedAt))
 	}
 	if a.UpdatedAt != nil {
-		fmt.Fprintf(out, "Updated at: %s\n", a.UpdatedAt)
+		fmt.Fprintf(out, "Updated at: %s\n", text.FormatTime(a.UpdatedAt))
 	}
 	if a.DeletedAt != nil {
-		fmt.Fprintf(out, "Deleted at: %s\n", a.DeletedAt)
+		fmt.Fprintf(out, "Deleted at: %s\n", text.FormatTime(a.DeletedAt))
 	}
 	return nil
 }
@@ -20,6 +20,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 		},
 	}
 	c.CmdClause = parent.Command("list", "List ACLs")
+	c.EmptyState = argparser.EmptyState{Resource: "ACLs", Create: "fastly acl create --version <VERSION> --name <NAME>"}
 
 	// Required.
 	c.RegisterFlag(argparser.StringFlagOpts{
@@ -30,7 +31,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 	})
 
 	// Optional.
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	c.RegisterFlag(argparser.StringFlagOpts{
 		Name:        argparser.FlagServiceIDName,
 		Description: argparser.FlagServiceIDDesc,
@@ -51,6 +52,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 
 	serviceName    argparser.OptionalServiceNameID
 	serviceVersion argparser.OptionalServiceVersion
@@ -94,6 +96,10 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
+	if c.PrintEmpty(out, len(o)) {
+		return nil
+	}
+
 	if c.Globals.Verbose() {
 		c.printVerbose(out, fastly.ToValue(serviceVersion.Number), o)
 	} else {
@@ -125,13 +131,13 @@ func (c *ListCommand) printVerbose(out io.Writer, serviceVersion int, as []*fast
 		fmt.Fprintf(out, "ID: %s\n\n", fastly.ToValue(a.ACLID))
 
 		if a.CreatedAt != nil {
-			fmt.Fprintf(out, "Created at: %s\n", a.CreatedAt)
+			fmt.Fprintf(out, "Created at: %s\n", text.FormatTime(a.CreatedAt))
 		}
 		if a.UpdatedAt != nil {
-			fmt.Fprintf(out, "Updated at: %s\n", a.UpdatedAt)
+			fmt.Fprintf(out, "Updated at: %s\n", text.FormatTime(a.UpdatedAt))
 		}
 		if a.DeletedAt != nil {
-			fmt.Fprintf(out, "Deleted at: %s\n", a.DeletedAt)
+			fmt.Fprintf(out, "Deleted at: %s\n", text.FormatTime(a.DeletedAt))
 		}
 
 		fmt.Fprintf(out, "\n")
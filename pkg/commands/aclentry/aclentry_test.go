@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -103,7 +105,7 @@ func TestACLEntryDelete(t *testing.T) {
 		{
 			Name:      "validate missing --id flag",
 			Args:      args("acl-entry delete --acl-id 123"),
-			WantError: "error parsing arguments: required flag --id not provided",
+			WantError: "invalid command, one of --id, --ip-glob or --ip-regex is required",
 		},
 		{
 			Name:      "validate missing --service-id flag",
@@ -148,6 +150,95 @@ func TestACLEntryDelete(t *testing.T) {
 	}
 }
 
+func TestACLEntryDeletePattern(t *testing.T) {
+	args := testutil.Args
+	remoteEntries := func(i *fastly.GetACLEntriesInput) *fastly.ListPaginator[fastly.ACLEntry] {
+		return fastly.NewPaginator[fastly.ACLEntry](mock.HTTPClient{
+			Errors: []error{nil},
+			Responses: []*http.Response{
+				{
+					Body: io.NopCloser(strings.NewReader(`[
+            {"id": "1", "ip": "127.0.0.1", "subnet": 0},
+            {"id": "2", "ip": "192.0.2.0", "subnet": 24},
+            {"id": "3", "ip": "192.0.2.7"},
+            {"id": "4", "ip": "10.0.0.0", "subnet": 8}
+          ]`)),
+				},
+			},
+		}, fastly.ListOpts{}, "/example")
+	}
+	var deleted []string
+	batchModify := func(i *fastly.BatchModifyACLEntriesInput) error {
+		for _, e := range i.Entries {
+			testutil.AssertEqual(t, fastly.DeleteBatchOperation, fastly.ToValue(e.Operation))
+			deleted = append(deleted, fastly.ToValue(e.EntryID))
+		}
+		return nil
+	}
+
+	scenarios := []struct {
+		testutil.TestScenario
+		wantDeleted []string
+	}{
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "validate --id and a pattern can't be combined",
+				Args:      args("acl-entry delete --acl-id 123 --service-id 123 --id 456 --ip-glob 192.0.2.*"),
+				WantError: "invalid flag combination, --id and --ip-glob or --ip-regex",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "validate a pattern matching every entry requires --match-all",
+				API:       mock.API{GetACLEntriesFn: remoteEntries, BatchModifyACLEntriesFn: batchModify},
+				Args:      args("acl-entry delete --acl-id 123 --service-id 123 --ip-glob * --auto-yes"),
+				WantError: "the pattern '*' matches every entry",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:            "validate dry run",
+				API:             mock.API{GetACLEntriesFn: remoteEntries, BatchModifyACLEntriesFn: batchModify},
+				Args:            args("acl-entry delete --acl-id 123 --service-id 123 --ip-glob 192.0.2.* --dry-run"),
+				WantOutputs:     []string{"2 entries match '192.0.2.*'.", "  192.0.2.0/24\n", "  192.0.2.7\n", "Dry run: no entries were deleted from ACL '123'"},
+				DontWantOutputs: []string{"127.0.0.1", "10.0.0.0/8"},
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:       "validate matching entries are deleted",
+				API:        mock.API{GetACLEntriesFn: remoteEntries, BatchModifyACLEntriesFn: batchModify},
+				Args:       args("acl-entry delete --acl-id 123 --service-id 123 --ip-regex /(8|24)$ --auto-yes"),
+				WantOutput: "Deleted 2 ACL entries matching '/(8|24)$' (service: 123)",
+			},
+			wantDeleted: []string{"4", "2"},
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			deleted = nil
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(testcase.Args, &stdout)
+				opts.APIClientFactory = mock.APIClient(testcase.API)
+				return opts, nil
+			}
+			err := app.Run(testcase.Args, nil)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertEqual(t, testcase.wantDeleted, deleted)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+			for _, s := range testcase.WantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			for _, s := range testcase.DontWantOutputs {
+				testutil.AssertStringDoesntContain(t, stdout.String(), s)
+			}
+		})
+	}
+}
+
 func TestACLEntryDescribe(t *testing.T) {
 	args := testutil.Args
 	scenarios := []testutil.TestScenario{
@@ -456,6 +547,273 @@ func TestACLEntryUpdate(t *testing.T) {
 	}
 }
 
+func TestACLEntryBulk(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	invalid := writeFile("invalid.txt", "# allow list\n192.0.2.0/24\nnot-an-ip\n10.0.0.1/8 # host bits\n\n2001:db8::/129\n")
+	overlapping := writeFile("overlapping.txt", "10.0.0.0/8\n10.1.0.0/16 # nested\n")
+	valid := writeFile("valid.txt", "127.0.0.1 # local\n!192.0.2.0/24\n198.51.100.0/24\n203.0.113.0/24\n2001:db8::/32\n")
+	unrelated := writeFile("unrelated.txt", "198.51.100.0/24\n")
+
+	paginate := func(body string) func(*fastly.GetACLEntriesInput) *fastly.ListPaginator[fastly.ACLEntry] {
+		return func(_ *fastly.GetACLEntriesInput) *fastly.ListPaginator[fastly.ACLEntry] {
+			return fastly.NewPaginator[fastly.ACLEntry](mock.HTTPClient{
+				Errors: []error{nil},
+				Responses: []*http.Response{
+					{Body: io.NopCloser(strings.NewReader(body))},
+				},
+			}, fastly.ListOpts{}, "/example")
+		}
+	}
+	remoteEntries := paginate(`[
+            {"id": "1", "ip": "127.0.0.1", "negated": 0, "subnet": 0, "comment": "local"},
+            {"id": "2", "ip": "192.0.2.0", "negated": 0, "subnet": 24, "comment": ""},
+            {"id": "3", "ip": "10.0.0.0", "negated": 0, "subnet": 8, "comment": "stale"}
+          ]`)
+
+	t.Run("invalid CIDRs are reported with line numbers", func(t *testing.T) {
+		var stdout bytes.Buffer
+		args := testutil.Args("acl-entry import --acl-id 123 --service-id 123 --file " + invalid)
+		app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+			opts := testutil.MockGlobalData(args, &stdout)
+			opts.APIClientFactory = mock.APIClient(mock.API{})
+			return opts, nil
+		}
+		err := app.Run(args, nil)
+		testutil.AssertErrorContains(t, err, `line 3: invalid IP address "not-an-ip"`)
+		testutil.AssertErrorContains(t, err, `line 4: invalid CIDR "10.0.0.1/8": host bits are set (did you mean 10.0.0.0/8?)`)
+		testutil.AssertErrorContains(t, err, `line 6: invalid CIDR "2001:db8::/129"`)
+	})
+
+	t.Run("overlaps are rejected when requested", func(t *testing.T) {
+		var stdout bytes.Buffer
+		args := testutil.Args("acl-entry import --acl-id 123 --service-id 123 --reject-overlaps --file " + overlapping)
+		app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+			opts := testutil.MockGlobalData(args, &stdout)
+			opts.APIClientFactory = mock.APIClient(mock.API{})
+			return opts, nil
+		}
+		err := app.Run(args, nil)
+		testutil.AssertErrorContains(t, err, "line 2: 10.1.0.0/16 overlaps 10.0.0.0/8 (line 1)")
+	})
+
+	t.Run("import creates missing entries in chunks", func(t *testing.T) {
+		var (
+			stdout  bytes.Buffer
+			batches [][]*fastly.BatchACLEntry
+		)
+		args := testutil.Args("acl-entry import --acl-id 123 --service-id 123 --batch-size 2 --file " + valid)
+		app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+			opts := testutil.MockGlobalData(args, &stdout)
+			opts.APIClientFactory = mock.APIClient(mock.API{
+				GetACLEntriesFn: remoteEntries,
+				BatchModifyACLEntriesFn: func(i *fastly.BatchModifyACLEntriesInput) error {
+					batches = append(batches, i.Entries)
+					return nil
+				},
+			})
+			return opts, nil
+		}
+		err := app.Run(args, nil)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 2, len(batches))
+		testutil.AssertEqual(t, 2, len(batches[0]))
+		testutil.AssertEqual(t, 1, len(batches[1]))
+		for _, b := range batches {
+			for _, e := range b {
+				testutil.AssertEqual(t, fastly.CreateBatchOperation, fastly.ToValue(e.Operation))
+			}
+		}
+		testutil.AssertString(t, "2001:db8::", fastly.ToValue(batches[1][0].IP))
+		testutil.AssertEqual(t, 32, fastly.ToValue(batches[1][0].Subnet))
+		testutil.AssertStringContains(t, stdout.String(), "Applied 3 changes to ACL '123'")
+	})
+
+	t.Run("sync updates and deletes entries", func(t *testing.T) {
+		var (
+			stdout  bytes.Buffer
+			entries []*fastly.BatchACLEntry
+		)
+		args := testutil.Args("acl-entry sync --acl-id 123 --service-id 123 --auto-yes --json --file " + valid)
+		app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+			opts := testutil.MockGlobalData(args, &stdout)
+			opts.APIClientFactory = mock.APIClient(mock.API{
+				GetACLEntriesFn: remoteEntries,
+				BatchModifyACLEntriesFn: func(i *fastly.BatchModifyACLEntriesInput) error {
+					entries = append(entries, i.Entries...)
+					return nil
+				},
+			})
+			return opts, nil
+		}
+		err := app.Run(args, nil)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 5, len(entries))
+		testutil.AssertEqual(t, fastly.UpdateBatchOperation, fastly.ToValue(entries[0].Operation))
+		testutil.AssertString(t, "2", fastly.ToValue(entries[0].EntryID))
+		testutil.AssertEqual(t, fastly.DeleteBatchOperation, fastly.ToValue(entries[4].Operation))
+		testutil.AssertString(t, "3", fastly.ToValue(entries[4].EntryID))
+		testutil.AssertStringContains(t, stdout.String(), `"deleted": 1`)
+		testutil.AssertStringContains(t, stdout.String(), `"unchanged": 1`)
+	})
+
+	t.Run("sync deletions require confirmation", func(t *testing.T) {
+		var (
+			stdout bytes.Buffer
+			called bool
+		)
+		args := testutil.Args("acl-entry sync --acl-id 123 --service-id 123 --file " + valid)
+		app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+			opts := testutil.MockGlobalData(args, &stdout)
+			opts.APIClientFactory = mock.APIClient(mock.API{
+				GetACLEntriesFn: remoteEntries,
+				BatchModifyACLEntriesFn: func(i *fastly.BatchModifyACLEntriesInput) error {
+					called = true
+					return nil
+				},
+			})
+			opts.Input = strings.NewReader("n\n")
+			return opts, nil
+		}
+		err := app.Run(args, nil)
+		testutil.AssertErrorContains(t, err, "deletion not confirmed")
+		testutil.AssertBool(t, false, called)
+		testutil.AssertStringContains(t, stdout.String(), "1 ACL entries will be permanently deleted")
+	})
+
+	t.Run("sync reads the file from stdin", func(t *testing.T) {
+		var (
+			stdout  bytes.Buffer
+			entries []*fastly.BatchACLEntry
+		)
+		args := testutil.Args("acl-entry sync --acl-id 123 --service-id 123 --auto-yes --file -")
+		app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+			opts := testutil.MockGlobalData(args, &stdout)
+			opts.APIClientFactory = mock.APIClient(mock.API{
+				GetACLEntriesFn: remoteEntries,
+				BatchModifyACLEntriesFn: func(i *fastly.BatchModifyACLEntriesInput) error {
+					entries = append(entries, i.Entries...)
+					return nil
+				},
+			})
+			opts.Input = strings.NewReader("127.0.0.1 # local\n192.0.2.0/24\n10.0.0.0/8 # stale\n198.51.100.0/24\n")
+			return opts, nil
+		}
+		err := app.Run(args, nil)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, len(entries))
+		testutil.AssertEqual(t, fastly.CreateBatchOperation, fastly.ToValue(entries[0].Operation))
+	})
+
+	t.Run("sync deletes remote entries that can't be parsed", func(t *testing.T) {
+		unparsed := paginate(`[
+            {"id": "1", "ip": "198.51.100.0", "negated": 0, "subnet": 24, "comment": ""},
+            {"id": "2", "ip": "not-an-ip", "negated": 0, "subnet": 24, "comment": ""}
+          ]`)
+		run := func(flags string) (string, []*fastly.BatchACLEntry, error) {
+			var (
+				stdout  bytes.Buffer
+				entries []*fastly.BatchACLEntry
+			)
+			args := testutil.Args("acl-entry sync --acl-id 123 --service-id 123 --auto-yes --json --file " + unrelated + flags)
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIClientFactory = mock.APIClient(mock.API{
+					GetACLEntriesFn: unparsed,
+					BatchModifyACLEntriesFn: func(i *fastly.BatchModifyACLEntriesInput) error {
+						entries = append(entries, i.Entries...)
+						return nil
+					},
+				})
+				return opts, nil
+			}
+			err := app.Run(args, nil)
+			return stdout.String(), entries, err
+		}
+
+		_, entries, err := run(" --mass-deletion-threshold 40")
+		testutil.AssertErrorContains(t, err, "the sync would delete 1 of the 2 existing entries (50%), more than the 40% threshold:\n\t\"not-an-ip/24\" (invalid, entry 2)")
+		testutil.AssertEqual(t, 0, len(entries))
+
+		out, entries, err := run("")
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, 1, len(entries))
+		testutil.AssertEqual(t, fastly.DeleteBatchOperation, fastly.ToValue(entries[0].Operation))
+		testutil.AssertString(t, "2", fastly.ToValue(entries[0].EntryID))
+		testutil.AssertStringContains(t, out, `"deleted": 1`)
+	})
+
+	t.Run("sync deletions in JSON mode require --auto-yes", func(t *testing.T) {
+		var (
+			stdout bytes.Buffer
+			called bool
+		)
+		args := testutil.Args("acl-entry sync --acl-id 123 --service-id 123 --json --file " + valid)
+		app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+			opts := testutil.MockGlobalData(args, &stdout)
+			opts.APIClientFactory = mock.APIClient(mock.API{
+				GetACLEntriesFn: remoteEntries,
+				BatchModifyACLEntriesFn: func(i *fastly.BatchModifyACLEntriesInput) error {
+					called = true
+					return nil
+				},
+			})
+			opts.Input = strings.NewReader("y\n")
+			return opts, nil
+		}
+		err := app.Run(args, nil)
+		testutil.AssertErrorContains(t, err, "deletion not confirmed")
+		testutil.AssertBool(t, false, called)
+		testutil.AssertString(t, "", stdout.String())
+	})
+
+	for _, testcase := range []struct {
+		name      string
+		args      string
+		wantError string
+		wantOps   int
+	}{
+		{
+			name:      "sync refuses a mass deletion",
+			wantError: "the sync would delete 3 of the 3 existing entries (100%), more than the 50% threshold:\n\t10.0.0.0/8\n\t127.0.0.1/32\n\t192.0.2.0/24",
+		},
+		{
+			name:    "sync allows a mass deletion with --allow-mass-deletion",
+			args:    " --allow-mass-deletion",
+			wantOps: 4,
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var (
+				stdout  bytes.Buffer
+				entries []*fastly.BatchACLEntry
+			)
+			args := testutil.Args("acl-entry sync --acl-id 123 --service-id 123 --auto-yes --file " + unrelated + testcase.args)
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIClientFactory = mock.APIClient(mock.API{
+					GetACLEntriesFn: remoteEntries,
+					BatchModifyACLEntriesFn: func(i *fastly.BatchModifyACLEntriesInput) error {
+						entries = append(entries, i.Entries...)
+						return nil
+					},
+				})
+				return opts, nil
+			}
+			err := app.Run(args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertEqual(t, testcase.wantOps, len(entries))
+		})
+	}
+}
+
 func getACLEntry(i *fastly.GetACLEntryInput) (*fastly.ACLEntry, error) {
 	t := testutil.Date
 
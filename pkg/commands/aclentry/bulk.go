@@ -0,0 +1,441 @@
+package aclentry
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+	"github.com/fastly/kingpin"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/itemsync"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// NewImportCommand returns a usable command registered under the parent.
+func NewImportCommand(parent argparser.Registerer, g *global.Data) *BulkCommand {
+	return newBulkCommand(parent.Command("import", "Add ACL entries from a file of CIDRs (one per line)"), g, false)
+}
+
+// NewSyncCommand returns a usable command registered under the parent.
+func NewSyncCommand(parent argparser.Registerer, g *global.Data) *BulkCommand {
+	return newBulkCommand(parent.Command("sync", "Make the entries of an ACL exactly match a file of CIDRs (one per line)"), g, true)
+}
+
+func newBulkCommand(cmd *kingpin.CmdClause, g *global.Data, sync bool) *BulkCommand {
+	c := BulkCommand{
+		Base: argparser.Base{
+			Globals: g,
+		},
+		sync: sync,
+	}
+	c.CmdClause = cmd
+
+	// Required.
+	c.CmdClause.Flag("acl-id", "Alphanumeric string identifying a ACL").Required().StringVar(&c.aclID)
+	c.CmdClause.Flag("file", "Path to a file of CIDRs, one per line, with an optional '#' comment ('-' for stdin). Prefix a CIDR with '!' to negate it").Required().Action(argparser.StdinPath(&c.file)).StringVar(&c.file)
+
+	// Optional.
+	c.CmdClause.Flag("batch-size", "Number of entries sent per batch request").Default(fmt.Sprint(fastly.BatchModifyMaximumOperations)).IntVar(&c.batchSize)
+	if sync {
+		c.CmdClause.Flag("dry-run", "Display the changes without applying them").BoolVar(&c.dryRun)
+		c.massDeletion = itemsync.NewMassDeletionFlags(c.CmdClause, "entry")
+	}
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.CmdClause.Flag("reject-overlaps", "Fail validation if any CIDRs in the file overlap").BoolVar(&c.rejectOverlaps)
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Name:        argparser.FlagServiceIDName,
+		Description: argparser.FlagServiceIDDesc,
+		Dst:         &g.Manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        argparser.FlagServiceName,
+		Description: argparser.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+
+	return &c
+}
+
+// BulkCommand calls the Fastly API to add (or sync) many ACL entries.
+type BulkCommand struct {
+	argparser.Base
+	argparser.JSONOutput
+
+	aclID          string
+	batchSize      int
+	dryRun         bool
+	file           string
+	massDeletion   *itemsync.MassDeletionFlags
+	rejectOverlaps bool
+	serviceName    argparser.OptionalServiceNameID
+	sync           bool
+}
+
+// errDeleteNotConfirmed is returned when a sync would delete ACL entries but
+// the user didn't confirm it.
+var errDeleteNotConfirmed = fsterr.RemediationError{
+	Inner:       errors.New("deletion not confirmed: no changes were made"),
+	Remediation: "Re-run the command and confirm the prompt, or pass the --auto-yes flag to delete ACL entries missing from the file.",
+}
+
+// Entry is a validated ACL entry read from a file.
+type Entry struct {
+	// Line is the line number the entry was read from.
+	Line int
+	// Prefix is the CIDR (a single IP is represented as a /32 or /128).
+	Prefix netip.Prefix
+	// Negated indicates the entry was prefixed with '!'.
+	Negated bool
+	// Comment is the optional comment following the CIDR.
+	Comment string
+}
+
+// bulkSummary is the outcome of an import or sync.
+type bulkSummary struct {
+	Created   int  `json:"created"`
+	Updated   int  `json:"updated"`
+	Deleted   int  `json:"deleted"`
+	Unchanged int  `json:"unchanged"`
+	Batches   int  `json:"batches"`
+	DryRun    bool `json:"dry_run,omitempty"`
+
+	// deletes are the CIDRs deleted by a sync.
+	deletes []string
+}
+
+// Exec invokes the application logic for the command.
+func (c *BulkCommand) Exec(in io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+	if c.batchSize < 1 || c.batchSize > fastly.BatchModifyMaximumOperations {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid --batch-size: %d", c.batchSize),
+			Remediation: fmt.Sprintf("Set --batch-size to a value between 1 and %d.", fastly.BatchModifyMaximumOperations),
+		}
+	}
+
+	serviceID, source, flag, err := argparser.ServiceID(c.serviceName, *c.Globals.Manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		argparser.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	r := in
+	if c.file != "-" {
+		// G304 (CWE-22): Potential file inclusion via variable
+		// #nosec
+		f, err := os.Open(c.file)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		r = f
+	} else if in == nil || text.IsTTY(in) {
+		return fsterr.ErrNoSTDINData
+	}
+
+	entries, err := ParseEntries(r, c.rejectOverlaps)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	remote, unparsed, err := c.remoteEntries(serviceID)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"ACL ID":     c.aclID,
+			"Service ID": serviceID,
+		})
+		return err
+	}
+
+	ops, summary := c.plan(entries, remote, unparsed)
+
+	if c.dryRun {
+		summary.DryRun = true
+		return c.printSummary(out, summary)
+	}
+	if c.sync {
+		if err := c.massDeletion.Check(summary.deletes, len(remote)+len(unparsed), c.Globals.Config.CLI.MassDeletionThreshold); err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+	}
+
+	if summary.Deleted > 0 && !c.Globals.Flags.AutoYes && !c.Globals.Flags.NonInteractive {
+		// NOTE: We can't prompt when the file was read from stdin, nor without
+		// corrupting the JSON output.
+		if in == nil || c.file == "-" || c.JSONOutput.Enabled {
+			return errDeleteNotConfirmed
+		}
+		text.Warning(out, "\n%d ACL entries will be permanently deleted.\n\n", summary.Deleted)
+		cont, err := text.AskYesNo(out, "Are you sure you want to continue? [y/N]: ", in, "--auto-yes")
+		if err != nil {
+			return err
+		}
+		if !cont {
+			return errDeleteNotConfirmed
+		}
+		text.Break(out)
+	}
+
+	// NOTE: The batches are sent one at a time, so by a single worker.
+	conns := c.Globals.APIConns.Track(1)
+	defer conns.Stop()
+	for i := 0; i < len(ops); i += c.batchSize {
+		end := i + c.batchSize
+		if end > len(ops) {
+			end = len(ops)
+		}
+		err := c.Globals.APIClient.BatchModifyACLEntries(&fastly.BatchModifyACLEntriesInput{
+			ACLID:     c.aclID,
+			Entries:   ops[i:end],
+			ServiceID: serviceID,
+		})
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"ACL ID":     c.aclID,
+				"Service ID": serviceID,
+				"Batch":      summary.Batches + 1,
+			})
+			return fmt.Errorf("failed to apply batch %d (%d of %d entries already applied): %w", summary.Batches+1, i, len(ops), err)
+		}
+		summary.Batches++
+	}
+	if !c.JSONOutput.Enabled {
+		argparser.DisplayConnections(out, conns.Summary(), c.Globals.Verbose())
+	}
+
+	return c.printSummary(out, summary)
+}
+
+// remoteEntries returns all the existing ACL entries keyed by CIDR, along with
+// those whose IP address or subnet can't be parsed.
+func (c *BulkCommand) remoteEntries(serviceID string) (remote map[netip.Prefix]*fastly.ACLEntry, unparsed []*fastly.ACLEntry, err error) {
+	remote = make(map[netip.Prefix]*fastly.ACLEntry)
+	paginator := c.Globals.APIClient.GetACLEntries(&fastly.GetACLEntriesInput{
+		ACLID:     c.aclID,
+		ServiceID: serviceID,
+	})
+	for paginator.HasNext() {
+		data, err := paginator.GetNext()
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, e := range data {
+			addr, err := netip.ParseAddr(fastly.ToValue(e.IP))
+			if err != nil {
+				unparsed = append(unparsed, e)
+				continue
+			}
+			bits := fastly.ToValue(e.Subnet)
+			if bits == 0 {
+				bits = addr.BitLen()
+			}
+			p, err := addr.Prefix(bits)
+			if err != nil {
+				unparsed = append(unparsed, e)
+				continue
+			}
+			remote[p] = e
+		}
+	}
+	return remote, unparsed, nil
+}
+
+// plan calculates the batch operations required.
+//
+// A sync deletes the unparsed remote entries, as they can't match any CIDR of
+// the file.
+func (c *BulkCommand) plan(entries []Entry, remote map[netip.Prefix]*fastly.ACLEntry, unparsed []*fastly.ACLEntry) ([]*fastly.BatchACLEntry, bulkSummary) {
+	var (
+		ops     []*fastly.BatchACLEntry
+		summary bulkSummary
+		local   = make(map[netip.Prefix]bool, len(entries))
+	)
+
+	for _, e := range entries {
+		local[e.Prefix] = true
+		existing, ok := remote[e.Prefix]
+		switch {
+		case !ok:
+			op := newBatchEntry(fastly.CreateBatchOperation, e)
+			ops = append(ops, op)
+			summary.Created++
+		case c.sync && (fastly.ToValue(existing.Negated) != e.Negated || fastly.ToValue(existing.Comment) != e.Comment):
+			op := newBatchEntry(fastly.UpdateBatchOperation, e)
+			op.EntryID = existing.EntryID
+			ops = append(ops, op)
+			summary.Updated++
+		default:
+			summary.Unchanged++
+		}
+	}
+
+	if c.sync {
+		var deletes []netip.Prefix
+		for p := range remote {
+			if !local[p] {
+				deletes = append(deletes, p)
+			}
+		}
+		sort.Slice(deletes, func(i, j int) bool {
+			return deletes[i].String() < deletes[j].String()
+		})
+		for _, p := range deletes {
+			ops = append(ops, &fastly.BatchACLEntry{
+				EntryID:   remote[p].EntryID,
+				Operation: fastly.ToPointer(fastly.DeleteBatchOperation),
+			})
+			summary.Deleted++
+			summary.deletes = append(summary.deletes, p.String())
+		}
+		for _, e := range unparsed {
+			ops = append(ops, &fastly.BatchACLEntry{
+				EntryID:   e.EntryID,
+				Operation: fastly.ToPointer(fastly.DeleteBatchOperation),
+			})
+			summary.Deleted++
+			summary.deletes = append(summary.deletes, unparsedLabel(e))
+		}
+	}
+
+	return ops, summary
+}
+
+func (c *BulkCommand) printSummary(out io.Writer, s bulkSummary) error {
+	if ok, err := c.WriteJSON(out, s); ok {
+		return err
+	}
+
+	t := text.NewTable(out)
+	t.AddHeader("CREATED", "UPDATED", "DELETED", "UNCHANGED", "BATCHES")
+	t.AddLine(s.Created, s.Updated, s.Deleted, s.Unchanged, s.Batches)
+	t.Print()
+
+	text.Break(out)
+	if s.DryRun {
+		text.Info(out, "Dry run: no changes were made to ACL '%s'", c.aclID)
+		return nil
+	}
+	text.Success(out, "Applied %d changes to ACL '%s'", s.Created+s.Updated+s.Deleted, c.aclID)
+	return nil
+}
+
+// unparsedLabel describes a remote entry whose CIDR can't be parsed.
+func unparsedLabel(e *fastly.ACLEntry) string {
+	cidr := fastly.ToValue(e.IP)
+	if bits := fastly.ToValue(e.Subnet); bits != 0 {
+		cidr = fmt.Sprintf("%s/%d", cidr, bits)
+	}
+	return fmt.Sprintf("%q (invalid, entry %s)", cidr, fastly.ToValue(e.EntryID))
+}
+
+func newBatchEntry(op fastly.BatchOperation, e Entry) *fastly.BatchACLEntry {
+	return &fastly.BatchACLEntry{
+		Comment:   fastly.ToPointer(e.Comment),
+		IP:        fastly.ToPointer(e.Prefix.Addr().String()),
+		Negated:   fastly.ToPointer(fastly.Compatibool(e.Negated)),
+		Operation: fastly.ToPointer(op),
+		Subnet:    fastly.ToPointer(e.Prefix.Bits()),
+	}
+}
+
+// ParseEntries reads and validates one CIDR per line.
+//
+// Blank lines and lines starting with '#' are ignored. Every invalid line is
+// reported (with its line number) rather than just the first.
+func ParseEntries(r io.Reader, rejectOverlaps bool) ([]Entry, error) {
+	var (
+		entries []Entry
+		invalid []string
+		seen    = make(map[netip.Prefix]int)
+	)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		l := strings.TrimSpace(scanner.Text())
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+
+		e := Entry{Line: line}
+		if value, comment, ok := strings.Cut(l, "#"); ok {
+			l, e.Comment = strings.TrimSpace(value), strings.TrimSpace(comment)
+		}
+		if strings.HasPrefix(l, "!") {
+			e.Negated = true
+			l = strings.TrimSpace(l[1:])
+		}
+
+		p, err := parsePrefix(l)
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("line %d: %s", line, err))
+			continue
+		}
+		if prev, ok := seen[p]; ok {
+			invalid = append(invalid, fmt.Sprintf("line %d: duplicate of line %d (%s)", line, prev, p))
+			continue
+		}
+		seen[p] = line
+		e.Prefix = p
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ACL entries: %w", err)
+	}
+
+	if rejectOverlaps {
+		for i := range entries {
+			for j := i + 1; j < len(entries); j++ {
+				if entries[i].Prefix.Overlaps(entries[j].Prefix) {
+					invalid = append(invalid, fmt.Sprintf("line %d: %s overlaps %s (line %d)", entries[j].Line, entries[j].Prefix, entries[i].Prefix, entries[i].Line))
+				}
+			}
+		}
+	}
+
+	if len(invalid) > 0 {
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid ACL entries:\n\t%s", strings.Join(invalid, "\n\t")),
+			Remediation: "Fix the reported lines. Each line must contain an IP address or CIDR (e.g. 192.0.2.0/24), optionally prefixed with '!' and followed by a '#' comment.",
+		}
+	}
+	return entries, nil
+}
+
+// parsePrefix parses an IP address or CIDR.
+func parsePrefix(s string) (netip.Prefix, error) {
+	if !strings.Contains(s, "/") {
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid IP address %q", s)
+		}
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+	p, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid CIDR %q", s)
+	}
+	if p.Masked() != p {
+		return netip.Prefix{}, fmt.Errorf("invalid CIDR %q: host bits are set (did you mean %s?)", s, p.Masked())
+	}
+	return p, nil
+}
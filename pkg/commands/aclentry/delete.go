@@ -1,12 +1,14 @@
 package aclentry
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/fastly/go-fastly/v9/fastly"
 
 	"github.com/fastly/cli/pkg/argparser"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/itemsync"
 	"github.com/fastly/cli/pkg/text"
 )
 
@@ -21,9 +23,10 @@ func NewDeleteCommand(parent argparser.Registerer, g *global.Data) *DeleteComman
 
 	// Required.
 	c.CmdClause.Flag("acl-id", "Alphanumeric string identifying a ACL").Required().StringVar(&c.aclID)
-	c.CmdClause.Flag("id", "Alphanumeric string identifying an ACL Entry").Required().StringVar(&c.id)
 
 	// Optional.
+	c.CmdClause.Flag("id", "Alphanumeric string identifying an ACL Entry").Action(c.id.Set).StringVar(&c.id.Value)
+	c.pattern = itemsync.NewPatternFlags(c.CmdClause, "ip", "entry", "192.0.2.*")
 	c.RegisterFlag(argparser.StringFlagOpts{
 		Name:        argparser.FlagServiceIDName,
 		Description: argparser.FlagServiceIDDesc,
@@ -45,12 +48,17 @@ type DeleteCommand struct {
 	argparser.Base
 
 	aclID       string
-	id          string
+	id          argparser.OptionalString
+	pattern     *itemsync.PatternFlags
 	serviceName argparser.OptionalServiceNameID
 }
 
 // Exec invokes the application logic for the command.
-func (c *DeleteCommand) Exec(_ io.Reader, out io.Writer) error {
+func (c *DeleteCommand) Exec(in io.Reader, out io.Writer) error {
+	if err := c.pattern.Validate("--id", c.id.WasSet); err != nil {
+		return err
+	}
+
 	serviceID, source, flag, err := argparser.ServiceID(c.serviceName, *c.Globals.Manifest, c.Globals.APIClient, c.Globals.ErrLog)
 	if err != nil {
 		return err
@@ -59,6 +67,10 @@ func (c *DeleteCommand) Exec(_ io.Reader, out io.Writer) error {
 		argparser.DisplayServiceID(serviceID, flag, source, out)
 	}
 
+	if c.pattern.WasSet() {
+		return c.deleteMatching(in, out, serviceID)
+	}
+
 	input := c.constructInput(serviceID)
 	err = c.Globals.APIClient.DeleteACLEntry(input)
 	if err != nil {
@@ -72,12 +84,94 @@ func (c *DeleteCommand) Exec(_ io.Reader, out io.Writer) error {
 	return nil
 }
 
+// deleteMatching deletes every ACL entry whose IP matches the pattern, using
+// batch requests. An entry with a subnet is matched as a CIDR (e.g.
+// 192.0.2.0/24).
+func (c *DeleteCommand) deleteMatching(in io.Reader, out io.Writer, serviceID string) error {
+	p, err := c.pattern.Pattern()
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[string]*string)
+	paginator := c.Globals.APIClient.GetACLEntries(&fastly.GetACLEntriesInput{
+		ACLID:     c.aclID,
+		ServiceID: serviceID,
+	})
+	for paginator.HasNext() {
+		data, err := paginator.GetNext()
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"ACL ID":     c.aclID,
+				"Service ID": serviceID,
+			})
+			return err
+		}
+		for _, e := range data {
+			ip := fastly.ToValue(e.IP)
+			if bits := fastly.ToValue(e.Subnet); bits != 0 {
+				ip = fmt.Sprintf("%s/%d", ip, bits)
+			}
+			ids[ip] = e.EntryID
+		}
+	}
+	ips := make([]string, 0, len(ids))
+	for ip := range ids {
+		ips = append(ips, ip)
+	}
+
+	matches := p.Filter(ips)
+	c.pattern.PrintMatches(out, p, matches)
+	if len(matches) == 0 {
+		return nil
+	}
+	if c.pattern.DryRun {
+		text.Break(out)
+		text.Info(out, "Dry run: no entries were deleted from ACL '%s'", c.aclID)
+		return nil
+	}
+	if err := c.pattern.ConfirmMatches(out, in, len(matches), c.Globals.Flags.AutoYes || c.Globals.Flags.NonInteractive); err != nil {
+		return err
+	}
+
+	result, err := itemsync.DeleteBatches(out, matches, fastly.BatchModifyMaximumOperations, func(batch []string) []string {
+		input := fastly.BatchModifyACLEntriesInput{
+			ACLID:     c.aclID,
+			ServiceID: serviceID,
+		}
+		for _, ip := range batch {
+			input.Entries = append(input.Entries, &fastly.BatchACLEntry{
+				EntryID:   ids[ip],
+				Operation: fastly.ToPointer(fastly.DeleteBatchOperation),
+			})
+		}
+		if err := c.Globals.APIClient.BatchModifyACLEntries(&input); err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"ACL ID":     c.aclID,
+				"Service ID": serviceID,
+			})
+			return batch
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := result.Err(); err != nil {
+		return err
+	}
+
+	text.Break(out)
+	text.Success(out, "Deleted %d ACL entries matching '%s' (service: %s)", len(result.Deleted), p, serviceID)
+	return nil
+}
+
 // constructInput transforms values parsed from CLI flags into an object to be used by the API client library.
 func (c *DeleteCommand) constructInput(serviceID string) *fastly.DeleteACLEntryInput {
 	var input fastly.DeleteACLEntryInput
 
 	input.ACLID = c.aclID
-	input.EntryID = c.id
+	input.EntryID = c.id.Value
 	input.ServiceID = serviceID
 
 	return &input
@@ -9,6 +9,7 @@ import (
 	"github.com/fastly/cli/pkg/argparser"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
 )
 
 // NewDescribeCommand returns a usable command registered under the parent.
@@ -25,7 +26,7 @@ func NewDescribeCommand(parent argparser.Registerer, g *global.Data) *DescribeCo
 	c.CmdClause.Flag("id", "Alphanumeric string identifying an ACL Entry").Required().StringVar(&c.id)
 
 	// Optional.
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	c.RegisterFlag(argparser.StringFlagOpts{
 		Name:        argparser.FlagServiceIDName,
 		Description: argparser.FlagServiceIDDesc,
@@ -107,13 +108,13 @@ func (c *DescribeCommand) print(out io.Writer, a *fastly.ACLEntry) error {
 	fmt.Fprintf(out, "Comment: %s\n\n", fastly.ToValue(a.Comment))
 
 	if a.CreatedAt != nil {
-		fmt.Fprintf(out, "Created at: %s\n", a.CreatedAt)
+		fmt.Fprintf(out, "Created at: %s\n", text.FormatTime(a.CreatedAt))
 	}
 	if a.UpdatedAt != nil {
-		fmt.Fprintf(out, "Updated at: %s\n", a.UpdatedAt)
+		fmt.Fprintf(out, "Updated at: %s\n", text.FormatTime(a.UpdatedAt))
 	}
 	if a.DeletedAt != nil {
-		fmt.Fprintf(out, "Deleted at: %s\n", a.DeletedAt)
+		fmt.Fprintf(out, "Deleted at: %s\n", text.FormatTime(a.DeletedAt))
 	}
 	return nil
 }
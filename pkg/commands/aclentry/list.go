@@ -20,12 +20,13 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 		},
 	}
 	c.CmdClause = parent.Command("list", "List ACLs")
+	c.EmptyState = argparser.EmptyState{Resource: "ACL entries", Create: "fastly acl-entry create --acl-id <ACL_ID> --ip <IP>"}
 
 	// Required.
 	c.CmdClause.Flag("acl-id", "Alphanumeric string identifying a ACL").Required().StringVar(&c.aclID)
 
 	// Optional.
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	c.RegisterFlag(argparser.StringFlagOpts{
 		Name:        argparser.FlagServiceIDName,
 		Description: argparser.FlagServiceIDDesc,
@@ -51,6 +52,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 
 	aclID       string
 	direction   string
@@ -78,9 +80,12 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 	paginator := c.Globals.APIClient.GetACLEntries(input)
 
 	var o []*fastly.ACLEntry
-	for paginator.HasNext() {
+	for paginator.HasNext() && !argparser.MaxTimeReached(c.Globals) {
 		data, err := paginator.GetNext()
 		if err != nil {
+			if argparser.MaxTimeReached(c.Globals) {
+				break
+			}
 			c.Globals.ErrLog.AddWithContext(err, map[string]any{
 				"ACL ID":          c.aclID,
 				"Service ID":      serviceID,
@@ -92,7 +97,14 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 	}
 
 	if ok, err := c.WriteJSON(out, o); ok {
-		return err
+		if err != nil {
+			return err
+		}
+		return argparser.PartialResults(c.Globals)
+	}
+
+	if c.PrintEmpty(out, len(o)) {
+		return argparser.PartialResults(c.Globals)
 	}
 
 	if c.Globals.Verbose() {
@@ -103,7 +115,7 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 			return err
 		}
 	}
-	return nil
+	return argparser.PartialResults(c.Globals)
 }
 
 // constructInput transforms values parsed from CLI flags into an object to be used by the API client library.
@@ -140,13 +152,13 @@ func (c *ListCommand) printVerbose(out io.Writer, as []*fastly.ACLEntry) {
 		fmt.Fprintf(out, "Comment: %s\n\n", fastly.ToValue(a.Comment))
 
 		if a.CreatedAt != nil {
-			fmt.Fprintf(out, "Created at: %s\n", a.CreatedAt)
+			fmt.Fprintf(out, "Created at: %s\n", text.FormatTime(a.CreatedAt))
 		}
 		if a.UpdatedAt != nil {
-			fmt.Fprintf(out, "Updated at: %s\n", a.UpdatedAt)
+			fmt.Fprintf(out, "Updated at: %s\n", text.FormatTime(a.UpdatedAt))
 		}
 		if a.DeletedAt != nil {
-			fmt.Fprintf(out, "Deleted at: %s\n", a.DeletedAt)
+			fmt.Fprintf(out, "Deleted at: %s\n", text.FormatTime(a.DeletedAt))
 		}
 
 		fmt.Fprintf(out, "\n")
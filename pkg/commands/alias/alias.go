@@ -0,0 +1,110 @@
+package alias
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/fastly/cli/pkg/config"
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// Builtins are the aliases available to all users.
+//
+// NOTE: User-defined aliases of the same name take precedence.
+var Builtins = config.Aliases{
+	"cb":  "compute build",
+	"cd":  "compute deploy",
+	"cp":  "compute publish",
+	"cs":  "compute serve",
+	"sl":  "service list",
+	"svl": "service-version list",
+}
+
+// globalValueFlags are the global flags that consume the following argument.
+// They're skipped when looking for the alias to expand.
+var globalValueFlags = map[string]bool{
+	"--account": true,
+	"--api":     true,
+	"--profile": true,
+	"-o":        true,
+	"--token":   true,
+	"-t":        true,
+}
+
+// Expand replaces the first positional argument in args with its expansion if
+// it's a user-defined or built-in alias. Any arguments following the alias are
+// kept after the expansion, e.g. `cbd --env stage` with the alias
+// `cbd = "compute build --verbose"` expands to
+// `compute build --verbose --env stage`.
+//
+// An expansion may itself start with another alias, which is expanded in turn.
+// An error is returned if the aliases reference each other in a loop, or if a
+// user-defined alias is invoked that has the same name as one of the commands.
+//
+// NOTE: The expansion is split on whitespace and doesn't support quoting.
+func Expand(args []string, user config.Aliases, commands []string) ([]string, error) {
+	i := positional(args)
+	if i < 0 {
+		return args, nil
+	}
+
+	var seen []string
+	for {
+		name := args[i]
+		expansion, ok := lookup(name, user)
+		if !ok {
+			return args, nil
+		}
+		if _, isUser := user[name]; isUser && slices.Contains(commands, name) {
+			return nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("alias '%s' shadows the '%s' command", name, name),
+				Remediation: fmt.Sprintf("Rename or remove the '%s' alias in the [alias] section of the CLI config (see `fastly config --location`).", name),
+			}
+		}
+		if slices.Contains(seen, name) {
+			return nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("alias '%s' is recursive: %s -> %s", name, strings.Join(seen, " -> "), name),
+				Remediation: "Update the [alias] section of the CLI config so the aliases don't reference each other in a loop.",
+			}
+		}
+		seen = append(seen, name)
+
+		fields := strings.Fields(expansion)
+		if len(fields) == 0 {
+			return nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("alias '%s' is empty", name),
+				Remediation: "Set the command the alias expands to in the [alias] section of the CLI config.",
+			}
+		}
+		expanded := make([]string, 0, len(args)+len(fields)-1)
+		expanded = append(expanded, args[:i]...)
+		expanded = append(expanded, fields...)
+		expanded = append(expanded, args[i+1:]...)
+		args = expanded
+	}
+}
+
+// lookup returns the expansion for the alias name.
+func lookup(name string, user config.Aliases) (string, bool) {
+	if v, ok := user[name]; ok {
+		return v, true
+	}
+	v, ok := Builtins[name]
+	return v, ok
+}
+
+// positional returns the index of the first argument that isn't a flag (or a
+// global flag value), or -1 if there isn't one.
+func positional(args []string) int {
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if !strings.HasPrefix(a, "-") {
+			return i
+		}
+		if globalValueFlags[a] {
+			i++
+		}
+	}
+	return -1
+}
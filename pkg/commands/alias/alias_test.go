@@ -0,0 +1,139 @@
+package alias_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/alias"
+	"github.com/fastly/cli/pkg/config"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+var commands = []string{"alias", "compute", "service", "service-version"}
+
+func TestExpand(t *testing.T) {
+	user := config.Aliases{
+		"cbd":   "compute build --verbose",
+		"cbd2":  "cbd --env stage",
+		"cs":    "compute serve --watch",
+		"loop":  "loop2 --verbose",
+		"loop2": "loop",
+		"self":  "self",
+		"empty": "  ",
+	}
+
+	scenarios := []struct {
+		name      string
+		args      string
+		user      config.Aliases
+		wantArgs  string
+		wantError string
+	}{
+		{
+			name:     "no alias",
+			args:     "service list --json",
+			wantArgs: "service list --json",
+		},
+		{
+			name:     "no args",
+			args:     "",
+			wantArgs: "",
+		},
+		{
+			name:     "user alias with trailing args",
+			args:     "cbd --env stage --timeout 60",
+			wantArgs: "compute build --verbose --env stage --timeout 60",
+		},
+		{
+			name:     "user alias after global flags",
+			args:     "-o admin --quiet cbd",
+			wantArgs: "-o admin --quiet compute build --verbose",
+		},
+		{
+			name:     "alias expanding to another alias",
+			args:     "cbd2 --timeout 60",
+			wantArgs: "compute build --verbose --env stage --timeout 60",
+		},
+		{
+			name:     "builtin alias",
+			args:     "sl --json",
+			wantArgs: "service list --json",
+		},
+		{
+			name:     "user alias overrides builtin",
+			args:     "cs",
+			wantArgs: "compute serve --watch",
+		},
+		{
+			name:     "alias name only expanded in command position",
+			args:     "service describe --service-name cbd",
+			wantArgs: "service describe --service-name cbd",
+		},
+		{
+			name:      "recursion",
+			args:      "loop",
+			wantError: "alias 'loop' is recursive: loop -> loop2 -> loop",
+		},
+		{
+			name:      "self recursion",
+			args:      "self",
+			wantError: "alias 'self' is recursive: self -> self",
+		},
+		{
+			name:      "empty",
+			args:      "empty",
+			wantError: "alias 'empty' is empty",
+		},
+		{
+			name:      "shadowing",
+			args:      "service",
+			user:      config.Aliases{"service": "service list"},
+			wantError: "alias 'service' shadows the 'service' command",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			aliases := user
+			if s.user != nil {
+				aliases = s.user
+			}
+			got, err := alias.Expand(strings.Fields(s.args), aliases, commands)
+			testutil.AssertErrorContains(t, err, s.wantError)
+			if s.wantError == "" {
+				testutil.AssertString(t, s.wantArgs, strings.Join(got, " "))
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	var stdout bytes.Buffer
+	args := testutil.Args("al")
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		opts := testutil.MockGlobalData(args, &stdout)
+		opts.Config.Aliases = config.Aliases{
+			"al": "alias list",
+			"cs": "compute serve --watch",
+		}
+		return opts, nil
+	}
+	err := app.Run(args, nil)
+	testutil.AssertNoError(t, err)
+
+	want := `
+ALIAS  EXPANSION              SOURCE
+cb     compute build          built-in
+cd     compute deploy         built-in
+cp     compute publish        built-in
+sl     service list           built-in
+svl    service-version list   built-in
+al     alias list             user
+cs     compute serve --watch  user
+`
+	testutil.AssertString(t, want, stdout.String())
+}
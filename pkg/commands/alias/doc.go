@@ -0,0 +1,2 @@
+// Package alias contains commands to inspect command aliases.
+package alias
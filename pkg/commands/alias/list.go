@@ -0,0 +1,82 @@
+package alias
+
+import (
+	"io"
+	"sort"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// Sources of an alias.
+const (
+	SourceBuiltin = "built-in"
+	SourceUser    = "user"
+)
+
+// Alias is a command alias and the command it expands to.
+type Alias struct {
+	Name      string `json:"name"`
+	Expansion string `json:"expansion"`
+	Source    string `json:"source"`
+}
+
+// ListCommand represents a Kingpin command.
+type ListCommand struct {
+	argparser.Base
+	argparser.JSONOutput
+}
+
+// NewListCommand returns a usable command registered under the parent.
+func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
+	var c ListCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("list", "List built-in and user-defined command aliases")
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	aliases := c.aliases()
+	if ok, err := c.WriteJSON(out, aliases); ok {
+		return err
+	}
+
+	text.Break(out)
+	t := text.NewTable(out)
+	t.AddHeader("ALIAS", "EXPANSION", "SOURCE")
+	for _, a := range aliases {
+		t.AddLine(a.Name, a.Expansion, a.Source)
+	}
+	t.Print()
+	return nil
+}
+
+// aliases returns the built-in aliases not overridden by the user followed by
+// the user-defined aliases, each sorted by name.
+func (c *ListCommand) aliases() []Alias {
+	user := c.Globals.Config.Aliases
+
+	var builtins, users []Alias
+	for name, expansion := range Builtins {
+		if _, ok := user[name]; !ok {
+			builtins = append(builtins, Alias{Name: name, Expansion: expansion, Source: SourceBuiltin})
+		}
+	}
+	for name, expansion := range user {
+		users = append(users, Alias{Name: name, Expansion: expansion, Source: SourceUser})
+	}
+	for _, as := range [][]Alias{builtins, users} {
+		sort.Slice(as, func(i, j int) bool {
+			return as[i].Name < as[j].Name
+		})
+	}
+	return append(builtins, users...)
+}
@@ -2,68 +2,144 @@ package authtoken_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/fastly/go-fastly/v9/fastly"
 
+	"github.com/fastly/cli/pkg/api"
 	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/audit"
+	"github.com/fastly/cli/pkg/commands/whoami"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/mock"
 	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/text"
 )
 
 func TestAuthTokenCreate(t *testing.T) {
 	args := testutil.Args
-	scenarios := []testutil.TestScenario{
+	type ts struct {
+		testutil.TestScenario
+		Client api.HTTPClient
+		Stdin  string
+	}
+	scenarios := []ts{
 		{
-			Name:      "validate missing --password flag",
-			Args:      args("auth-token create"),
-			WantError: "error parsing arguments: required flag --password not provided",
+			TestScenario: testutil.TestScenario{
+				Name:      "validate missing --password flag when non-interactive",
+				Args:      args("auth-token create --non-interactive"),
+				WantError: "the --password flag is required when running non-interactively",
+			},
 		},
 		{
-			Name: "validate CreateToken API error",
-			API: mock.API{
-				CreateTokenFn: func(i *fastly.CreateTokenInput) (*fastly.Token, error) {
-					return nil, testutil.Err
+			TestScenario: testutil.TestScenario{
+				Name: "validate CreateToken API error",
+				API: mock.API{
+					CreateTokenFn: func(i *fastly.CreateTokenInput) (*fastly.Token, error) {
+						return nil, testutil.Err
+					},
 				},
+				Args:      args("auth-token create --password secure --token 123"),
+				WantError: testutil.Err.Error(),
 			},
-			Args:      args("auth-token create --password secure --token 123"),
-			WantError: testutil.Err.Error(),
 		},
 		{
-			Name: "validate CreateToken API success with no flags",
-			API: mock.API{
-				CreateTokenFn: func(i *fastly.CreateTokenInput) (*fastly.Token, error) {
-					return &fastly.Token{
-						ExpiresAt:   &testutil.Date,
-						TokenID:     fastly.ToPointer("123"),
-						Name:        fastly.ToPointer("Example"),
-						Scope:       fastly.ToPointer(fastly.TokenScope("foobar")),
-						AccessToken: fastly.ToPointer("123abc"),
-					}, nil
+			TestScenario: testutil.TestScenario{
+				Name: "validate CreateToken API success with no flags",
+				API: mock.API{
+					CreateTokenFn: func(i *fastly.CreateTokenInput) (*fastly.Token, error) {
+						return &fastly.Token{
+							ExpiresAt:   &testutil.Date,
+							TokenID:     fastly.ToPointer("123"),
+							Name:        fastly.ToPointer("Example"),
+							Scope:       fastly.ToPointer(fastly.TokenScope("foobar")),
+							AccessToken: fastly.ToPointer("123abc"),
+						}, nil
+					},
 				},
+				Args:       args("auth-token create --password secure --token 123"),
+				WantOutput: "Created token '123abc' (name: Example, id: 123, scope: foobar, expires: 2021-06-15 23:00:00 +0000 UTC)",
 			},
-			Args:       args("auth-token create --password secure --token 123"),
-			WantOutput: "Created token '123abc' (name: Example, id: 123, scope: foobar, expires: 2021-06-15 23:00:00 +0000 UTC)",
 		},
 		{
-			Name: "validate CreateToken API success with all flags",
-			API: mock.API{
-				CreateTokenFn: func(i *fastly.CreateTokenInput) (*fastly.Token, error) {
-					return &fastly.Token{
-						ExpiresAt:   i.ExpiresAt,
-						TokenID:     fastly.ToPointer("123"),
-						Name:        i.Name,
-						Scope:       i.Scope,
-						AccessToken: fastly.ToPointer("123abc"),
-					}, nil
+			TestScenario: testutil.TestScenario{
+				Name: "validate CreateToken API success with all flags",
+				API: mock.API{
+					CreateTokenFn: func(i *fastly.CreateTokenInput) (*fastly.Token, error) {
+						return &fastly.Token{
+							ExpiresAt:   i.ExpiresAt,
+							TokenID:     fastly.ToPointer("123"),
+							Name:        i.Name,
+							Scope:       i.Scope,
+							AccessToken: fastly.ToPointer("123abc"),
+						}, nil
+					},
+				},
+				Args:       args("auth-token create --expires 2021-09-15T23:00:00Z --name Testing --password secure --scope purge_all --scope global:read --services a,b,c --token 123"),
+				WantOutput: "Created token '123abc' (name: Testing, id: 123, scope: purge_all global:read, expires: 2021-09-15 23:00:00 +0000 UTC)",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "validate password is prompted for",
+				API: mock.API{
+					CreateTokenFn: func(i *fastly.CreateTokenInput) (*fastly.Token, error) {
+						if fastly.ToValue(i.Password) != "secure" {
+							return nil, fmt.Errorf("unexpected password: %s", fastly.ToValue(i.Password))
+						}
+						return &fastly.Token{
+							TokenID:     fastly.ToPointer("123"),
+							Name:        fastly.ToPointer("Example"),
+							Scope:       fastly.ToPointer(fastly.GlobalScope),
+							AccessToken: fastly.ToPointer("123abc"),
+						}, nil
+					},
 				},
+				Args:        args("auth-token create --token 123"),
+				WantOutputs: []string{"Account password: ", "Two-factor authentication code (leave blank if not enabled): ", "Created token '123abc'"},
+			},
+			Stdin: "secure\n\n",
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:       "validate one-time password is sent with the request",
+				Args:       args("auth-token create --name Testing --scope purge_all --services a,b --token 123"),
+				WantOutput: "Created token '123abc' (name: Testing, id: 123, scope: purge_all, expires: never)",
 			},
-			Args:       args("auth-token create --expires 2021-09-15T23:00:00Z --name Testing --password secure --scope purge_all --scope global:read --services a,b,c --token 123"),
-			WantOutput: "Created token '123abc' (name: Testing, id: 123, scope: purge_all global:read, expires: 2021-09-15 23:00:00 +0000 UTC)",
+			Client: tokensClient(func(r *http.Request) (*http.Response, error) {
+				if err := r.ParseForm(); err != nil {
+					return nil, err
+				}
+				if have := r.Header.Get("Fastly-OTP"); have != "654321" {
+					return nil, fmt.Errorf("unexpected Fastly-OTP header: %s", have)
+				}
+				if have := r.PostForm.Encode(); have != "name=Testing&password=secure&scope=purge_all&services%5B%5D=a&services%5B%5D=b" {
+					return nil, fmt.Errorf("unexpected form: %s", have)
+				}
+				return jsonResponse(http.StatusOK, `{"access_token":"123abc","id":"123","name":"Testing","scope":"purge_all"}`), nil
+			}),
+			Stdin: "secure\n654321\n",
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "validate one-time password rejected",
+				Args:      args("auth-token create --otp 000000 --password secure --token 123"),
+				WantError: "error response",
+			},
+			Client: tokensClient(func(*http.Request) (*http.Response, error) {
+				return jsonResponse(http.StatusBadRequest, `{"msg":"Invalid one-time password"}`), nil
+			}),
 		},
 	}
 
@@ -74,11 +150,18 @@ func TestAuthTokenCreate(t *testing.T) {
 			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
 				opts := testutil.MockGlobalData(testcase.Args, &stdout)
 				opts.APIClientFactory = mock.APIClient(testcase.API)
+				opts.Input = iotest.OneByteReader(strings.NewReader(testcase.Stdin))
+				if testcase.Client != nil {
+					opts.HTTPClient = testcase.Client
+				}
 				return opts, nil
 			}
 			err := app.Run(testcase.Args, nil)
 			testutil.AssertErrorContains(t, err, testcase.WantError)
 			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+			for _, s := range testcase.WantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
 		})
 	}
 }
@@ -218,6 +301,43 @@ func TestAuthTokenDescribe(t *testing.T) {
 	}
 }
 
+// TestAuthTokenDescribeTimeFormat validates timestamps are displayed using the
+// time format and timezone, while JSON output is always RFC3339 in UTC.
+func TestAuthTokenDescribeTimeFormat(t *testing.T) {
+	defer func() {
+		_ = text.SetTimeFormat("")
+		_ = text.SetTimeZone("")
+	}()
+
+	for _, testcase := range []struct {
+		args       string
+		wantOutput string
+	}{
+		{
+			args:       "auth-token describe --token 123",
+			wantOutput: "Created at: 2021-06-16T08:00:00+09:00",
+		},
+		{
+			args:       "auth-token describe --token 123 --json",
+			wantOutput: `"CreatedAt": "2021-06-15T23:00:00Z"`,
+		},
+	} {
+		t.Run(testcase.args, func(t *testing.T) {
+			args := testutil.Args(testcase.args)
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIClientFactory = mock.APIClient(mock.API{GetTokenSelfFn: getToken})
+				opts.Env.TimeFormat = "rfc3339"
+				opts.Env.TimeZone = "Asia/Tokyo"
+				return opts, nil
+			}
+			testutil.AssertNoError(t, app.Run(args, nil))
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+		})
+	}
+}
+
 func TestAuthTokenList(t *testing.T) {
 	args := testutil.Args
 	type ts struct {
@@ -318,6 +438,346 @@ func TestAuthTokenList(t *testing.T) {
 	}
 }
 
+func TestAuthTokenRotate(t *testing.T) {
+	args := testutil.Args
+
+	currentToken := func() (*fastly.Token, error) {
+		return &fastly.Token{
+			TokenID:  fastly.ToPointer("old-id"),
+			Name:     fastly.ToPointer("Deploys"),
+			Scope:    fastly.ToPointer(fastly.TokenScope("purge_all global:read")),
+			Services: []string{"a", "b"},
+		}, nil
+	}
+	replacementToken := func(i *fastly.CreateTokenInput) (*fastly.Token, error) {
+		return &fastly.Token{
+			AccessToken: fastly.ToPointer("new-token"),
+			TokenID:     fastly.ToPointer("new-id"),
+			Name:        i.Name,
+			Scope:       i.Scope,
+			Services:    i.Services,
+		}, nil
+	}
+	verified := verifyClient("new-token", "new-id")
+
+	scenarios := []struct {
+		name string
+		args []string
+		api  mock.API
+		// client mocks the /verify API call.
+		client api.HTTPClient
+		// readOnlyConfig causes writing the config file to fail.
+		readOnlyConfig bool
+		sso            bool
+		stdin          string
+		wantCalls      []string
+		wantError      string
+		wantOutput     string
+		wantProfile    string
+		wantRemedation string
+	}{
+		{
+			name:      "validate token from --token flag",
+			args:      args("auth-token rotate --password secure --token 123"),
+			wantError: "only a token stored in a profile can be rotated",
+		},
+		{
+			name:      "validate SSO profile",
+			args:      args("auth-token rotate --password secure"),
+			sso:       true,
+			wantError: "the 'user' profile uses an SSO-based token",
+		},
+		{
+			name:        "validate missing --password flag when non-interactive",
+			args:        args("auth-token rotate --non-interactive"),
+			wantError:   "the --password flag is required when running non-interactively",
+			wantProfile: "mock-token",
+		},
+		{
+			name: "validate GetTokenSelf API error",
+			args: args("auth-token rotate --password secure"),
+			api: mock.API{
+				GetTokenSelfFn: func() (*fastly.Token, error) {
+					return nil, testutil.Err
+				},
+			},
+			wantCalls:      []string{"GetTokenSelf"},
+			wantError:      "token rotation failed at step 1 of 5 (look up the current token): test error",
+			wantProfile:    "mock-token",
+			wantRemedation: "The old token hasn't been changed and is still in use.",
+		},
+		{
+			name: "validate CreateToken API error",
+			args: args("auth-token rotate --password secure"),
+			api: mock.API{
+				GetTokenSelfFn: currentToken,
+				CreateTokenFn: func(*fastly.CreateTokenInput) (*fastly.Token, error) {
+					return nil, testutil.Err
+				},
+			},
+			wantCalls:      []string{"GetTokenSelf", "CreateToken"},
+			wantError:      "token rotation failed at step 2 of 5 (create the replacement token): test error",
+			wantProfile:    "mock-token",
+			wantRemedation: "The old token hasn't been changed and is still in use.",
+		},
+		{
+			name: "validate profile update error",
+			args: args("auth-token rotate --password secure"),
+			api: mock.API{
+				GetTokenSelfFn: currentToken,
+				CreateTokenFn:  replacementToken,
+			},
+			readOnlyConfig: true,
+			wantCalls:      []string{"GetTokenSelf", "CreateToken", "DeleteToken new-id"},
+			wantError:      "token rotation failed at step 3 of 5 (update the profile)",
+			wantProfile:    "mock-token",
+			wantRemedation: "the profile couldn't be restored to use it",
+		},
+		{
+			name: "validate verification error",
+			args: args("auth-token rotate --password secure"),
+			api: mock.API{
+				GetTokenSelfFn: currentToken,
+				CreateTokenFn:  replacementToken,
+			},
+			client:         verifyClient("new-token", "other-id"),
+			wantCalls:      []string{"GetTokenSelf", "CreateToken", "DeleteToken new-id"},
+			wantError:      "token rotation failed at step 4 of 5 (verify the replacement token): the API identified the token as 'other-id', expected 'new-id'",
+			wantProfile:    "mock-token",
+			wantRemedation: "The old token hasn't been changed and the profile still uses it.",
+		},
+		{
+			name: "validate verification API error",
+			args: args("auth-token rotate --password secure"),
+			api: mock.API{
+				GetTokenSelfFn: currentToken,
+				CreateTokenFn:  replacementToken,
+			},
+			client: tokensClient(func(*http.Request) (*http.Response, error) {
+				return jsonResponse(http.StatusUnauthorized, `{"msg":"Provided credentials are missing or invalid"}`), nil
+			}),
+			wantCalls:      []string{"GetTokenSelf", "CreateToken", "DeleteToken new-id"},
+			wantError:      "token rotation failed at step 4 of 5 (verify the replacement token): error executing API request",
+			wantProfile:    "mock-token",
+			wantRemedation: "The old token hasn't been changed and the profile still uses it.",
+		},
+		{
+			name: "validate verification error when the replacement token can't be deleted",
+			args: args("auth-token rotate --password secure"),
+			api: mock.API{
+				GetTokenSelfFn: currentToken,
+				CreateTokenFn:  replacementToken,
+				DeleteTokenFn: func(*fastly.DeleteTokenInput) error {
+					return testutil.Err
+				},
+			},
+			client:         verifyClient("new-token", "other-id"),
+			wantCalls:      []string{"GetTokenSelf", "CreateToken", "DeleteToken new-id"},
+			wantError:      "token rotation failed at step 4 of 5 (verify the replacement token)",
+			wantProfile:    "mock-token",
+			wantRemedation: "The replacement token (id: new-id) couldn't be deleted: delete it with `fastly auth-token delete --id new-id`.",
+		},
+		{
+			name: "validate DeleteTokenSelf API error",
+			args: args("auth-token rotate --password secure"),
+			api: mock.API{
+				GetTokenSelfFn: currentToken,
+				CreateTokenFn:  replacementToken,
+				DeleteTokenSelfFn: func() error {
+					return testutil.Err
+				},
+			},
+			client:         verified,
+			wantCalls:      []string{"GetTokenSelf", "CreateToken", "DeleteTokenSelf"},
+			wantError:      "token rotation failed at step 5 of 5 (delete the old token): test error",
+			wantProfile:    "new-token",
+			wantRemedation: "Delete it with `fastly auth-token delete --id old-id`.",
+		},
+		{
+			name: "validate rotation success",
+			args: args("auth-token rotate --password secure"),
+			api: mock.API{
+				GetTokenSelfFn: currentToken,
+				CreateTokenFn: func(i *fastly.CreateTokenInput) (*fastly.Token, error) {
+					if fastly.ToValue(i.Name) != "Deploys" || fastly.ToValue(i.Scope) != "purge_all global:read" || strings.Join(i.Services, ",") != "a,b" || fastly.ToValue(i.Password) != "secure" {
+						return nil, fmt.Errorf("unexpected input: %#v", i)
+					}
+					return replacementToken(i)
+				},
+			},
+			client:      verified,
+			wantCalls:   []string{"GetTokenSelf", "CreateToken", "DeleteTokenSelf"},
+			wantOutput:  "Rotated the token for the 'user' profile (old id: old-id, new id: new-id, scope: purge_all global:read)",
+			wantProfile: "new-token",
+		},
+		{
+			name: "validate rotation success with prompted password and --name",
+			args: args("auth-token rotate --name Renamed"),
+			api: mock.API{
+				GetTokenSelfFn: currentToken,
+				CreateTokenFn: func(i *fastly.CreateTokenInput) (*fastly.Token, error) {
+					if fastly.ToValue(i.Name) != "Renamed" || fastly.ToValue(i.Password) != "secure" {
+						return nil, fmt.Errorf("unexpected input: %#v", i)
+					}
+					return replacementToken(i)
+				},
+			},
+			client:      verified,
+			stdin:       "secure\n\n",
+			wantCalls:   []string{"GetTokenSelf", "CreateToken", "DeleteTokenSelf"},
+			wantOutput:  "Rotated the token for the 'user' profile",
+			wantProfile: "new-token",
+		},
+	}
+
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			var calls []string
+			api := testcase.api
+			if fn := api.GetTokenSelfFn; fn != nil {
+				api.GetTokenSelfFn = func() (*fastly.Token, error) {
+					calls = append(calls, "GetTokenSelf")
+					return fn()
+				}
+			}
+			if fn := api.CreateTokenFn; fn != nil {
+				api.CreateTokenFn = func(i *fastly.CreateTokenInput) (*fastly.Token, error) {
+					calls = append(calls, "CreateToken")
+					return fn(i)
+				}
+			}
+			deleteToken := api.DeleteTokenFn
+			api.DeleteTokenFn = func(i *fastly.DeleteTokenInput) error {
+				calls = append(calls, "DeleteToken "+i.TokenID)
+				if deleteToken != nil {
+					return deleteToken(i)
+				}
+				return nil
+			}
+			deleteTokenSelf := api.DeleteTokenSelfFn
+			api.DeleteTokenSelfFn = func() error {
+				calls = append(calls, "DeleteTokenSelf")
+				if deleteTokenSelf != nil {
+					return deleteTokenSelf()
+				}
+				return nil
+			}
+
+			configPath := filepath.Join(t.TempDir(), "config.toml")
+			if testcase.readOnlyConfig {
+				configPath = filepath.Join(t.TempDir(), "missing", "config.toml")
+			}
+
+			var (
+				stdout bytes.Buffer
+				opts   *global.Data
+			)
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts = testutil.MockGlobalData(testcase.args, &stdout)
+				opts.APIClientFactory = mock.APIClient(api)
+				opts.ConfigPath = configPath
+				opts.Input = iotest.OneByteReader(strings.NewReader(testcase.stdin))
+				if testcase.client != nil {
+					opts.HTTPClient = testcase.client
+				}
+				if testcase.sso {
+					opts.Config.Profiles["user"].RefreshToken = "refresh"
+				}
+				return opts, nil
+			}
+			err := app.Run(testcase.args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertRemediationErrorContains(t, err, testcase.wantRemedation)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			testutil.AssertEqual(t, testcase.wantCalls, calls)
+
+			if testcase.wantProfile == "" {
+				return
+			}
+			testutil.AssertString(t, testcase.wantProfile, opts.Config.Profiles["user"].Token)
+			written, err := os.ReadFile(configPath)
+			if errors.Is(err, fs.ErrNotExist) {
+				return // the config wasn't written
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutil.AssertStringContains(t, string(written), fmt.Sprintf("token = %q", testcase.wantProfile))
+		})
+	}
+}
+
+// TestAuthTokenRotateAudit validates a rotation is recorded in the audit log
+// without the password.
+func TestAuthTokenRotateAudit(t *testing.T) {
+	args := testutil.Args("auth-token rotate --password secure")
+	path := filepath.Join(t.TempDir(), "audit.log")
+	api := mock.API{
+		GetTokenSelfFn: func() (*fastly.Token, error) {
+			return &fastly.Token{TokenID: fastly.ToPointer("old-id"), Scope: fastly.ToPointer(fastly.GlobalScope)}, nil
+		},
+		CreateTokenFn: func(i *fastly.CreateTokenInput) (*fastly.Token, error) {
+			return &fastly.Token{AccessToken: fastly.ToPointer("new-token"), TokenID: fastly.ToPointer("new-id"), Scope: i.Scope}, nil
+		},
+		DeleteTokenSelfFn: func() error {
+			return nil
+		},
+	}
+
+	var stdout bytes.Buffer
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		opts := testutil.MockGlobalData(args, &stdout)
+		opts.APIClientFactory = mock.APIClient(api)
+		opts.AuditLogPath = path
+		opts.Config.Audit.Enabled = true
+		opts.ConfigPath = filepath.Join(t.TempDir(), "config.toml")
+		opts.HTTPClient = verifyClient("new-token", "new-id")
+		return opts, nil
+	}
+	testutil.AssertNoError(t, app.Run(args, nil))
+
+	entries, err := audit.Read(path)
+	testutil.AssertNoError(t, err)
+	if len(entries) != 1 {
+		t.Fatalf("want 1 entry, have %d: %#v", len(entries), entries)
+	}
+	testutil.AssertString(t, "auth-token rotate", entries[0].Command)
+	testutil.AssertString(t, "auth-token rotate --password REDACTED", strings.Join(entries[0].Args, " "))
+	testutil.AssertString(t, audit.OutcomeSuccess, entries[0].Outcome)
+}
+
+// tokensClient mocks the HTTP client used for undocumented API calls.
+type tokensClient func(*http.Request) (*http.Response, error)
+
+// Do executes the HTTP request.
+func (c tokensClient) Do(r *http.Request) (*http.Response, error) {
+	return c(r)
+}
+
+// verifyClient returns a client that mocks a successful `/verify` API call
+// for token, identifying it as tokenID.
+func verifyClient(token, tokenID string) tokensClient {
+	return func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path != "/verify" {
+			return nil, fmt.Errorf("unexpected request: %s", r.URL.Path)
+		}
+		if have := r.Header.Get("Fastly-Key"); have != token {
+			return jsonResponse(http.StatusUnauthorized, `{"msg":"Provided credentials are missing or invalid"}`), nil
+		}
+		rec := httptest.NewRecorder()
+		_ = json.NewEncoder(rec).Encode(whoami.VerifyResponse{Token: whoami.Token{ID: tokenID}})
+		return rec.Result(), nil
+	}
+}
+
+// jsonResponse returns a HTTP response with the given status and body.
+func jsonResponse(status int, body string) *http.Response {
+	rec := httptest.NewRecorder()
+	rec.WriteHeader(status)
+	_, _ = rec.WriteString(body)
+	return rec.Result()
+}
+
 func getToken() (*fastly.Token, error) {
 	t := testutil.Date
 
@@ -340,15 +800,14 @@ func listTokens(_ *fastly.ListTokensInput) ([]*fastly.Token, error) {
 	vs := []*fastly.Token{
 		token,
 		{
-			TokenID:    fastly.ToPointer("456"),
-			Name:       fastly.ToPointer("Bar"),
-			UserID:     fastly.ToPointer("789"),
-			Services:   []string{"a", "b"},
-			Scope:      fastly.ToPointer(fastly.GlobalScope),
-			IP:         fastly.ToPointer("127.0.0.2"),
-			CreatedAt:  &t,
-			ExpiresAt:  &t,
-			LastUsedAt: &t,
+			TokenID:   fastly.ToPointer("456"),
+			Name:      fastly.ToPointer("Bar"),
+			UserID:    fastly.ToPointer("789"),
+			Services:  []string{"a", "b"},
+			Scope:     fastly.ToPointer(fastly.GlobalScope),
+			IP:        fastly.ToPointer("127.0.0.2"),
+			CreatedAt: &t,
+			ExpiresAt: &t,
 		},
 	}
 	return vs, nil
@@ -405,7 +864,6 @@ Scope: global
 IP: 127.0.0.2
 
 Created at: 2021-06-15 23:00:00 +0000 UTC
-Last used at: 2021-06-15 23:00:00 +0000 UTC
 Expires at: 2021-06-15 23:00:00 +0000 UTC
 
 `
@@ -416,7 +874,7 @@ func listTokenOutputSummary(env bool) string {
 	if env {
 		msg = "INFO: Listing customer tokens for the FASTLY_CUSTOMER_ID environment variable\n\n"
 	}
-	return fmt.Sprintf(`%sNAME  TOKEN ID  USER ID  SCOPE                  SERVICES
-Foo   123       456      purge_all global:read  a, b
-Bar   456       789      global                 a, b`, msg)
+	return fmt.Sprintf(`%sNAME  TOKEN ID  USER ID  SCOPE                  SERVICES  LAST USED
+Foo   123       456      purge_all global:read  a, b      2021-06-15T23:00:00Z
+Bar   456       789      global                 a, b      never`, msg)
 }
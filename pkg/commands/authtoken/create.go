@@ -26,14 +26,6 @@ func NewCreateCommand(parent argparser.Registerer, g *global.Data) *CreateComman
 	}
 	c.CmdClause = parent.Command("create", "Create an API token").Alias("add")
 
-	// Required.
-	//
-	// NOTE: The go-fastly client internally calls `/sudo` before `/tokens` and
-	// the sudo endpoint requires a password to be provided alongside an API
-	// token. The password must be for the user account that created the token
-	// being passed as authentication to the API endpoint.
-	c.CmdClause.Flag("password", "User password corresponding with --token or $FASTLY_API_TOKEN").Required().StringVar(&c.password)
-
 	// Optional.
 	//
 	// NOTE: The API describes 'scope' as being space-delimited but we've opted
@@ -43,6 +35,7 @@ func NewCreateCommand(parent argparser.Registerer, g *global.Data) *CreateComman
 	// value to a space-delimited value.
 	c.CmdClause.Flag("expires", "Time-stamp (UTC) of when the token will expire").HintOptions("2016-07-28T19:24:50+00:00").TimeVar(time.RFC3339, &c.expires)
 	c.CmdClause.Flag("name", "Name of the token").StringVar(&c.name)
+	c.credentials.register(c.CmdClause)
 	c.CmdClause.Flag("scope", "Authorization scope (repeat flag per scope)").HintOptions(Scopes...).EnumsVar(&c.scope, Scopes...)
 	c.CmdClause.Flag("services", "A comma-separated list of alphanumeric strings identifying services (default: access to all services)").StringsVar(&c.services, kingpin.Separator(","))
 	return &c
@@ -52,18 +45,23 @@ func NewCreateCommand(parent argparser.Registerer, g *global.Data) *CreateComman
 type CreateCommand struct {
 	argparser.Base
 
-	expires  time.Time
-	name     string
-	password string
-	scope    []string
-	services []string
+	credentials credentials
+	expires     time.Time
+	name        string
+	scope       []string
+	services    []string
 }
 
 // Exec invokes the application logic for the command.
-func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
+func (c *CreateCommand) Exec(in io.Reader, out io.Writer) error {
+	if err := c.credentials.prompt(c.Globals, in, out); err != nil {
+		return err
+	}
+
 	input := c.constructInput()
 
-	r, err := c.Globals.APIClient.CreateToken(input)
+	token, _ := c.Globals.Token()
+	r, err := c.credentials.createToken(c.Globals, c.Globals.APIClient, token, input)
 	if err != nil {
 		c.Globals.ErrLog.Add(err)
 		return err
@@ -71,10 +69,10 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 
 	expires := "never"
 	if r.ExpiresAt != nil {
-		expires = r.ExpiresAt.String()
+		expires = text.FormatTime(r.ExpiresAt)
 	}
 
-	text.Success(out, "Created token '%s' (name: %s, id: %s, scope: %s, expires: %s)", fastly.ToValue(r.AccessToken), fastly.ToValue(r.Name), fastly.ToValue(r.TokenID), fastly.ToValue(r.Scope), expires)
+	text.Result(out, fastly.ToValue(r.AccessToken), "Created token '%s' (name: %s, id: %s, scope: %s, expires: %s)", fastly.ToValue(r.AccessToken), fastly.ToValue(r.Name), fastly.ToValue(r.TokenID), fastly.ToValue(r.Scope), expires)
 	return nil
 }
 
@@ -82,8 +80,6 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 func (c *CreateCommand) constructInput() *fastly.CreateTokenInput {
 	var input fastly.CreateTokenInput
 
-	input.Password = fastly.ToPointer(c.password)
-
 	if !c.expires.IsZero() {
 		input.ExpiresAt = &c.expires
 	}
@@ -10,6 +10,7 @@ import (
 	"github.com/fastly/cli/pkg/argparser"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
 )
 
 // NewDescribeCommand returns a usable command registered under the parent.
@@ -21,7 +22,7 @@ func NewDescribeCommand(parent argparser.Registerer, g *global.Data) *DescribeCo
 	}
 	c.CmdClause = parent.Command("describe", "Get the current API token").Alias("get")
 
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	return &c
 }
 
@@ -60,13 +61,13 @@ func (c *DescribeCommand) print(out io.Writer, t *fastly.Token) error {
 	fmt.Fprintf(out, "IP: %s\n\n", fastly.ToValue(t.IP))
 
 	if t.CreatedAt != nil {
-		fmt.Fprintf(out, "Created at: %s\n", t.CreatedAt)
+		fmt.Fprintf(out, "Created at: %s\n", text.FormatTime(t.CreatedAt))
 	}
 	if t.LastUsedAt != nil {
-		fmt.Fprintf(out, "Last used at: %s\n", t.LastUsedAt)
+		fmt.Fprintf(out, "Last used at: %s\n", text.FormatTime(t.LastUsedAt))
 	}
 	if t.ExpiresAt != nil {
-		fmt.Fprintf(out, "Expires at: %s\n", t.ExpiresAt)
+		fmt.Fprintf(out, "Expires at: %s\n", text.FormatTime(t.ExpiresAt))
 	}
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/fastly/go-fastly/v9/fastly"
 
@@ -21,6 +22,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 		},
 	}
 	c.CmdClause = parent.Command("list", "List API tokens")
+	c.EmptyState = argparser.EmptyState{Resource: "tokens", Create: "fastly auth-token create --name <NAME>"}
 
 	c.RegisterFlag(argparser.StringFlagOpts{
 		Name:        argparser.FlagCustomerIDName,
@@ -28,7 +30,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 		Dst:         &c.customerID.Value,
 		Action:      c.customerID.Set,
 	})
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	return &c
 }
 
@@ -36,6 +38,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 
 	customerID argparser.OptionalCustomerID
 }
@@ -73,6 +76,10 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
+	if c.PrintEmpty(out, len(o)) {
+		return nil
+	}
+
 	if c.Globals.Verbose() {
 		c.printVerbose(out, o)
 	} else {
@@ -105,13 +112,13 @@ func (c *ListCommand) printVerbose(out io.Writer, rs []*fastly.Token) {
 		fmt.Fprintf(out, "IP: %s\n\n", fastly.ToValue(r.IP))
 
 		if r.CreatedAt != nil {
-			fmt.Fprintf(out, "Created at: %s\n", r.CreatedAt)
+			fmt.Fprintf(out, "Created at: %s\n", text.FormatTime(r.CreatedAt))
 		}
 		if r.LastUsedAt != nil {
-			fmt.Fprintf(out, "Last used at: %s\n", r.LastUsedAt)
+			fmt.Fprintf(out, "Last used at: %s\n", text.FormatTime(r.LastUsedAt))
 		}
 		if r.ExpiresAt != nil {
-			fmt.Fprintf(out, "Expires at: %s\n", r.ExpiresAt)
+			fmt.Fprintf(out, "Expires at: %s\n", text.FormatTime(r.ExpiresAt))
 		}
 	}
 	fmt.Fprintf(out, "\n")
@@ -121,14 +128,19 @@ func (c *ListCommand) printVerbose(out io.Writer, rs []*fastly.Token) {
 // format.
 func (c *ListCommand) printSummary(out io.Writer, ts []*fastly.Token) error {
 	tbl := text.NewTable(out)
-	tbl.AddHeader("NAME", "TOKEN ID", "USER ID", "SCOPE", "SERVICES")
+	tbl.AddHeader("NAME", "TOKEN ID", "USER ID", "SCOPE", "SERVICES", "LAST USED")
 	for _, t := range ts {
+		lastUsed := "never"
+		if t.LastUsedAt != nil {
+			lastUsed = text.FormatTimeLayout(t.LastUsedAt, time.RFC3339)
+		}
 		tbl.AddLine(
 			fastly.ToValue(t.Name),
 			fastly.ToValue(t.TokenID),
 			fastly.ToValue(t.UserID),
 			fastly.ToValue(t.Scope),
 			strings.Join(t.Services, ", "),
+			lastUsed,
 		)
 	}
 	tbl.Print()
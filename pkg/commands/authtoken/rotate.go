@@ -0,0 +1,224 @@
+package authtoken
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/api/undocumented"
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/commands/whoami"
+	"github.com/fastly/cli/pkg/config"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/lookup"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/cli/pkg/useragent"
+)
+
+// The steps of a token rotation, in the order they're executed.
+const (
+	stepLookup = iota + 1
+	stepCreate
+	stepProfile
+	stepVerify
+	stepDelete
+)
+
+// rotationSteps describes each rotation step for use in error messages.
+var rotationSteps = map[int]string{
+	stepLookup:  "look up the current token",
+	stepCreate:  "create the replacement token",
+	stepProfile: "update the profile",
+	stepVerify:  "verify the replacement token",
+	stepDelete:  "delete the old token",
+}
+
+// NewRotateCommand returns a usable command registered under the parent.
+func NewRotateCommand(parent argparser.Registerer, g *global.Data) *RotateCommand {
+	c := RotateCommand{
+		Base: argparser.Base{
+			Globals: g,
+		},
+	}
+	c.CmdClause = parent.Command("rotate", "Replace the API token of the active profile with a new token that has the same scope")
+
+	// Optional.
+	c.CmdClause.Flag("expires", "Time-stamp (UTC) of when the replacement token will expire").HintOptions("2016-07-28T19:24:50+00:00").TimeVar(time.RFC3339, &c.expires)
+	c.CmdClause.Flag("name", "Name of the replacement token (default: the name of the current token)").StringVar(&c.name)
+	c.credentials.register(c.CmdClause)
+	return &c
+}
+
+// RotateCommand calls the Fastly API to replace the profile's API token.
+type RotateCommand struct {
+	argparser.Base
+
+	credentials credentials
+	expires     time.Time
+	name        string
+}
+
+// Exec invokes the application logic for the command.
+//
+// The old token is only deleted once the profile has been updated to use the
+// replacement token and the replacement token has been verified. If any step
+// before that fails, the profile is restored and the replacement token (if
+// created) is deleted, so the old token remains in use.
+func (c *RotateCommand) Exec(in io.Reader, out io.Writer) error {
+	oldToken, source := c.Globals.Token()
+	if source != lookup.SourceFile {
+		return fsterr.RemediationError{
+			Inner:       errors.New("only a token stored in a profile can be rotated"),
+			Remediation: "Remove the --token flag and unset the FASTLY_API_TOKEN environment variable so the token is read from the active profile (see `fastly profile list`).",
+		}
+	}
+	profileName, p, err := c.Globals.Profile()
+	if err != nil {
+		return err
+	}
+	if p.RefreshToken != "" {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("the '%s' profile uses an SSO-based token", profileName),
+			Remediation: "SSO-based tokens are replaced automatically when they expire. To get a new token now, run `fastly sso`.",
+		}
+	}
+
+	if err := c.credentials.prompt(c.Globals, in, out); err != nil {
+		return err
+	}
+
+	spinner, err := text.NewSpinner(out)
+	if err != nil {
+		return err
+	}
+
+	var current *fastly.Token
+	err = spinner.Process("Looking up the current token", func(_ *text.SpinnerWrapper) error {
+		current, err = c.Globals.APIClient.GetTokenSelf()
+		return err
+	})
+	if err != nil {
+		return c.rotationError(stepLookup, err, "")
+	}
+
+	input := &fastly.CreateTokenInput{
+		Name:     current.Name,
+		Scope:    current.Scope,
+		Services: current.Services,
+	}
+	if c.name != "" {
+		input.Name = fastly.ToPointer(c.name)
+	}
+	if !c.expires.IsZero() {
+		input.ExpiresAt = &c.expires
+	}
+
+	var replacement *fastly.Token
+	err = spinner.Process("Creating the replacement token", func(_ *text.SpinnerWrapper) error {
+		replacement, err = c.credentials.createToken(c.Globals, c.Globals.APIClient, oldToken, input)
+		return err
+	})
+	if err != nil {
+		return c.rotationError(stepCreate, err, "")
+	}
+	newToken := fastly.ToValue(replacement.AccessToken)
+
+	err = spinner.Process(fmt.Sprintf("Updating the '%s' profile", profileName), func(_ *text.SpinnerWrapper) error {
+		p.Token = newToken
+		return c.Globals.Config.Write(c.Globals.ConfigPath)
+	})
+	if err != nil {
+		return c.rotationError(stepProfile, err, c.rollback(p, oldToken, replacement))
+	}
+
+	err = spinner.Process("Verifying the replacement token", func(_ *text.SpinnerWrapper) error {
+		return c.verify(newToken, fastly.ToValue(replacement.TokenID))
+	})
+	if err != nil {
+		return c.rotationError(stepVerify, err, c.rollback(p, oldToken, replacement))
+	}
+
+	err = spinner.Process("Deleting the old token", func(_ *text.SpinnerWrapper) error {
+		return c.Globals.APIClient.DeleteTokenSelf()
+	})
+	if err != nil {
+		return c.rotationError(stepDelete, err, fmt.Sprintf("The '%s' profile now uses the replacement token (id: %s) but the old token (id: %s) is still valid. Delete it with `fastly auth-token delete --id %s`.", profileName, fastly.ToValue(replacement.TokenID), fastly.ToValue(current.TokenID), fastly.ToValue(current.TokenID)))
+	}
+
+	text.Success(out, "Rotated the token for the '%s' profile (old id: %s, new id: %s, scope: %s)", profileName, fastly.ToValue(current.TokenID), fastly.ToValue(replacement.TokenID), fastly.ToValue(replacement.Scope))
+	return nil
+}
+
+// verify checks the API accepts token, and identifies it as tokenID.
+func (c *RotateCommand) verify(token, tokenID string) error {
+	debugMode, _ := strconv.ParseBool(c.Globals.Env.DebugMode)
+	apiEndpoint, _ := c.Globals.APIEndpoint()
+	data, err := undocumented.Call(undocumented.CallOptions{
+		APIEndpoint: apiEndpoint,
+		HTTPClient:  c.Globals.HTTPClient,
+		HTTPHeaders: []undocumented.HTTPHeader{
+			{
+				Key:   "Accept",
+				Value: "application/json",
+			},
+			{
+				Key:   "User-Agent",
+				Value: useragent.Name,
+			},
+		},
+		Method: http.MethodGet,
+		Path:   "/verify",
+		Token:  token,
+		Debug:  debugMode,
+	})
+	if err != nil {
+		return fmt.Errorf("error executing API request: %w", err)
+	}
+
+	var response whoami.VerifyResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return fmt.Errorf("error decoding API response: %w", err)
+	}
+	if response.Token.ID != tokenID {
+		return fmt.Errorf("the API identified the token as '%s', expected '%s'", response.Token.ID, tokenID)
+	}
+	return nil
+}
+
+// rollback restores the profile to use oldToken and deletes the replacement
+// token. It returns a remediation describing the state it left things in.
+func (c *RotateCommand) rollback(p *config.Profile, oldToken string, replacement *fastly.Token) string {
+	remediation := "The old token hasn't been changed and the profile still uses it. Resolve the error and try again."
+
+	p.Token = oldToken
+	if err := c.Globals.Config.Write(c.Globals.ConfigPath); err != nil {
+		c.Globals.ErrLog.Add(err)
+		remediation = fmt.Sprintf("The old token hasn't been changed but the profile couldn't be restored to use it (%s). Run `fastly profile update` to set the token again.", err)
+	}
+
+	id := fastly.ToValue(replacement.TokenID)
+	if err := c.Globals.APIClient.DeleteToken(&fastly.DeleteTokenInput{TokenID: id}); err != nil {
+		c.Globals.ErrLog.Add(err)
+		remediation += fmt.Sprintf(" The replacement token (id: %s) couldn't be deleted: delete it with `fastly auth-token delete --id %s`.", id, id)
+	}
+	return remediation
+}
+
+// rotationError returns a RemediationError identifying the failed step.
+func (c *RotateCommand) rotationError(step int, err error, remediation string) error {
+	c.Globals.ErrLog.Add(err)
+	if remediation == "" {
+		remediation = "The old token hasn't been changed and is still in use. Resolve the error and try again."
+	}
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("token rotation failed at step %d of %d (%s): %w", step, len(rotationSteps), rotationSteps[step], err),
+		Remediation: remediation,
+	}
+}
@@ -0,0 +1,173 @@
+package authtoken
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+	"github.com/fastly/kingpin"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/api/undocumented"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// ErrEmptyPassword is returned when a user supplies an empty string as a
+// password in the terminal prompt.
+var ErrEmptyPassword = errors.New("password cannot be empty")
+
+// PasswordRemediation explains how to provide the account password when the
+// CLI can't prompt for it.
+var PasswordRemediation = "Provide the password for the account that owns the API token using the --password flag (and --otp if the account has two-factor authentication enabled)."
+
+// credentials are the account details the API requires before it will create
+// a new token.
+//
+// NOTE: The go-fastly client internally calls `/sudo` before `/tokens` and
+// the sudo endpoint requires a password to be provided alongside an API
+// token. The password must be for the user account that created the token
+// being passed as authentication to the API endpoint.
+type credentials struct {
+	otp      string
+	password string
+}
+
+// register adds the --password and --otp flags to cmd.
+func (cr *credentials) register(cmd *kingpin.CmdClause) {
+	cmd.Flag("otp", "One-time password for accounts with two-factor authentication enabled (prompted for if --password is omitted)").StringVar(&cr.otp)
+	cmd.Flag("password", "User password corresponding with --token or $FASTLY_API_TOKEN (prompted for if omitted)").StringVar(&cr.password)
+}
+
+// prompt asks for the password (and a one-time password) using masked input
+// if --password wasn't provided.
+func (cr *credentials) prompt(g *global.Data, in io.Reader, out io.Writer) error {
+	if cr.password != "" {
+		return nil
+	}
+	if g.Flags.NonInteractive {
+		return fsterr.RemediationError{
+			Inner:       errors.New("the --password flag is required when running non-interactively"),
+			Remediation: PasswordRemediation,
+		}
+	}
+
+	password, err := text.InputSecure(out, text.Prompt("Account password: "), in, "--password", validatePasswordNotEmpty)
+	if err != nil {
+		g.ErrLog.Add(err)
+		return err
+	}
+	cr.password = password
+
+	if cr.otp == "" {
+		otp, err := text.InputSecure(out, text.Prompt("Two-factor authentication code (leave blank if not enabled): "), in, "--otp")
+		if err != nil {
+			g.ErrLog.Add(err)
+			return err
+		}
+		cr.otp = strings.TrimSpace(otp)
+	}
+	text.Break(out)
+	return nil
+}
+
+func validatePasswordNotEmpty(s string) error {
+	if s == "" {
+		return ErrEmptyPassword
+	}
+	return nil
+}
+
+// createToken creates a token using the credentials, authenticated by token.
+//
+// The go-fastly client has no way to send the `Fastly-OTP` header, so when a
+// one-time password is provided the `/tokens` endpoint is called directly.
+func (cr *credentials) createToken(g *global.Data, client api.Interface, token string, input *fastly.CreateTokenInput) (*fastly.Token, error) {
+	input.Password = fastly.ToPointer(cr.password)
+	if cr.otp == "" {
+		return client.CreateToken(input)
+	}
+
+	form := url.Values{}
+	form.Set("password", cr.password)
+	if input.ExpiresAt != nil {
+		form.Set("expires_at", input.ExpiresAt.Format(time.RFC3339))
+	}
+	if input.Name != nil {
+		form.Set("name", *input.Name)
+	}
+	if input.Scope != nil {
+		form.Set("scope", string(*input.Scope))
+	}
+	for _, s := range input.Services {
+		form.Add("services[]", s)
+	}
+
+	debugMode, _ := strconv.ParseBool(g.Env.DebugMode)
+	apiEndpoint, _ := g.APIEndpoint()
+	data, err := undocumented.Call(undocumented.CallOptions{
+		APIEndpoint: apiEndpoint,
+		Body:        strings.NewReader(form.Encode()),
+		HTTPClient:  g.HTTPClient,
+		HTTPHeaders: []undocumented.HTTPHeader{
+			{
+				Key:   "Accept",
+				Value: "application/json",
+			},
+			{
+				Key:   "Content-Type",
+				Value: "application/x-www-form-urlencoded",
+			},
+			{
+				Key:   "Fastly-OTP",
+				Value: cr.otp,
+			},
+		},
+		Method: http.MethodPost,
+		Path:   "/tokens",
+		Token:  token,
+		Debug:  debugMode,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var r tokenResponse
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("error decoding API response: %w", err)
+	}
+	return r.token(), nil
+}
+
+// tokenResponse models the `/tokens` API response.
+type tokenResponse struct {
+	AccessToken string     `json:"access_token"`
+	CreatedAt   *time.Time `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Scope       string     `json:"scope"`
+	Services    []string   `json:"services"`
+	UserID      string     `json:"user_id"`
+}
+
+func (r tokenResponse) token() *fastly.Token {
+	return &fastly.Token{
+		AccessToken: fastly.ToPointer(r.AccessToken),
+		CreatedAt:   r.CreatedAt,
+		ExpiresAt:   r.ExpiresAt,
+		Name:        fastly.ToPointer(r.Name),
+		Scope:       fastly.ToPointer(fastly.TokenScope(r.Scope)),
+		Services:    r.Services,
+		TokenID:     fastly.ToPointer(r.ID),
+		UserID:      fastly.ToPointer(r.UserID),
+	}
+}
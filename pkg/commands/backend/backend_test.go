@@ -2,15 +2,30 @@ package backend_test
 
 import (
 	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"io"
+	"math/big"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/fastly/go-fastly/v9/fastly"
 
 	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/backend"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/mock"
@@ -219,6 +234,14 @@ func TestBackendList(t *testing.T) {
 			},
 			WantError: errTest.Error(),
 		},
+		{
+			Args: args("backend list --service-id 123 --version 1"),
+			API: mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListBackendsFn: listBackendsEmpty,
+			},
+			WantOutput: "INFO: No backends found. Run `fastly backend create --version <VERSION> --name <NAME> --address <ADDRESS>`.\n",
+		},
 	}
 	for testcaseIdx := range scenarios {
 		testcase := &scenarios[testcaseIdx]
@@ -418,6 +441,10 @@ func listBackendsError(_ *fastly.ListBackendsInput) ([]*fastly.Backend, error) {
 	return nil, errTest
 }
 
+func listBackendsEmpty(_ *fastly.ListBackendsInput) ([]*fastly.Backend, error) {
+	return nil, nil
+}
+
 var listBackendsJSONOutput = strings.TrimSpace(`
 [
   {
@@ -619,3 +646,501 @@ func deleteBackendOK(_ *fastly.DeleteBackendInput) error {
 func deleteBackendError(_ *fastly.DeleteBackendInput) error {
 	return errTest
 }
+
+func TestBackendProbe(t *testing.T) {
+	good := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer good.Close()
+	goodCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: good.Certificate().Raw}))
+	goodPort := listenerPort(t, good.Listener)
+
+	expiredCert, expiredCA := generateCert(t, time.Now().Add(-48*time.Hour), time.Now().Add(-24*time.Hour))
+	expiredPort := serveTLS(t, expiredCert)
+
+	backends := []*fastly.Backend{
+		{
+			Name:            fastly.ToPointer("good"),
+			Address:         fastly.ToPointer("127.0.0.1"),
+			Port:            fastly.ToPointer(goodPort),
+			UseSSL:          fastly.ToPointer(true),
+			SSLCACert:       fastly.ToPointer(goodCA),
+			SSLCertHostname: fastly.ToPointer("example.com"),
+		},
+		{
+			Name:            fastly.ToPointer("mismatch"),
+			Address:         fastly.ToPointer("127.0.0.1"),
+			Port:            fastly.ToPointer(goodPort),
+			UseSSL:          fastly.ToPointer(true),
+			SSLCACert:       fastly.ToPointer(goodCA),
+			SSLCertHostname: fastly.ToPointer("origin.example.org"),
+		},
+		{
+			Name:            fastly.ToPointer("expired"),
+			Address:         fastly.ToPointer("127.0.0.1"),
+			Port:            fastly.ToPointer(expiredPort),
+			UseSSL:          fastly.ToPointer(true),
+			SSLCACert:       fastly.ToPointer(expiredCA),
+			SSLCertHostname: fastly.ToPointer("probe.test"),
+		},
+		{
+			Name:            fastly.ToPointer("untrusted"),
+			Address:         fastly.ToPointer("127.0.0.1"),
+			Port:            fastly.ToPointer(goodPort),
+			UseSSL:          fastly.ToPointer(true),
+			SSLCACert:       fastly.ToPointer(expiredCA),
+			SSLCertHostname: fastly.ToPointer("example.com"),
+		},
+	}
+	listBackends := func(_ *fastly.ListBackendsInput) ([]*fastly.Backend, error) {
+		return backends, nil
+	}
+
+	t.Run("single healthy backend", func(t *testing.T) {
+		var stdout bytes.Buffer
+		args := testutil.Args("backend probe --service-id 123 --version 1 --name good")
+		app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+			opts := testutil.MockGlobalData(args, &stdout)
+			opts.APIClientFactory = mock.APIClient(mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListBackendsFn: listBackends,
+			})
+			return opts, nil
+		}
+		err := app.Run(args, nil)
+		testutil.AssertNoError(t, err)
+		testutil.AssertStringContains(t, stdout.String(), "NAME")
+		testutil.AssertStringContains(t, stdout.String(), "good")
+		testutil.AssertStringDoesntContain(t, stdout.String(), "mismatch")
+	})
+
+	t.Run("unknown backend", func(t *testing.T) {
+		var stdout bytes.Buffer
+		args := testutil.Args("backend probe --service-id 123 --version 1 --name missing")
+		app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+			opts := testutil.MockGlobalData(args, &stdout)
+			opts.APIClientFactory = mock.APIClient(mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListBackendsFn: listBackends,
+			})
+			return opts, nil
+		}
+		err := app.Run(args, nil)
+		testutil.AssertErrorContains(t, err, "backend 'missing' not found on service version 1")
+	})
+
+	t.Run("all backends as JSON", func(t *testing.T) {
+		var stdout bytes.Buffer
+		args := testutil.Args("backend probe --service-id 123 --version 1 --json")
+		app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+			opts := testutil.MockGlobalData(args, &stdout)
+			opts.APIClientFactory = mock.APIClient(mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListBackendsFn: listBackends,
+			})
+			return opts, nil
+		}
+		err := app.Run(args, nil)
+		testutil.AssertErrorContains(t, err, "3 of 4 backends failed the probe")
+
+		var results []backend.ProbeResult
+		if err := json.Unmarshal(stdout.Bytes(), &results); err != nil {
+			t.Fatal(err)
+		}
+		testutil.AssertEqual(t, 4, len(results))
+		testutil.AssertString(t, backend.ProbeStatusOK, results[0].Status)
+		testutil.AssertString(t, backend.ProbeStatusError, results[1].Status)
+		testutil.AssertStringContains(t, results[1].Issues[0].Problem, "certificate hostname mismatch")
+		testutil.AssertString(t, backend.ProbeStatusError, results[2].Status)
+		testutil.AssertStringContains(t, results[2].Issues[0].Problem, "certificate expired on")
+		testutil.AssertString(t, backend.ProbeStatusError, results[3].Status)
+		testutil.AssertString(t, "certificate is signed by an unknown authority", results[3].Issues[0].Problem)
+	})
+
+	t.Run("port 443 without TLS", func(t *testing.T) {
+		plain := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+		defer plain.Close()
+
+		r := backend.Probe(&fastly.Backend{
+			Name:    fastly.ToPointer("plain"),
+			Address: fastly.ToPointer("127.0.0.1"),
+			Port:    fastly.ToPointer(443),
+		}, 100*time.Millisecond)
+		testutil.AssertString(t, "port 443 is configured without use_ssl", r.Issues[0].Problem)
+
+		r = backend.Probe(&fastly.Backend{
+			Name:    fastly.ToPointer("plain"),
+			Address: fastly.ToPointer("127.0.0.1"),
+			Port:    fastly.ToPointer(listenerPort(t, plain.Listener)),
+		}, time.Second)
+		testutil.AssertString(t, backend.ProbeStatusOK, r.Status)
+		testutil.AssertEqual(t, 0, len(r.Issues))
+	})
+}
+
+func listenerPort(t *testing.T, l net.Listener) int {
+	t.Helper()
+	_, port, err := net.SplitHostPort(l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// generateCert returns a self-signed certificate for probe.test along with
+// its PEM encoding.
+func generateCert(t *testing.T, notBefore, notAfter time.Time) (tls.Certificate, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "probe.test"},
+		DNSNames:              []string{"probe.test"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+// serveTLS accepts TLS connections with the given certificate until the test
+// ends, returning the listening port.
+func serveTLS(t *testing.T, cert tls.Certificate) int {
+	t.Helper()
+	l, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_ = conn.(*tls.Conn).Handshake()
+				_ = conn.Close()
+			}()
+		}
+	}()
+	return listenerPort(t, l)
+}
+
+func TestCheck(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		settings backend.Settings
+		want     []string
+	}{
+		{
+			name: "plaintext",
+			settings: backend.Settings{
+				Address: "origin.example.com",
+				Port:    fastly.ToPointer(80),
+			},
+		},
+		{
+			name: "tls",
+			settings: backend.Settings{
+				Address:         "origin.example.com",
+				Port:            fastly.ToPointer(443),
+				SSLCertHostname: "origin.example.com",
+				SSLCheckCert:    true,
+				UseSSL:          true,
+			},
+		},
+		{
+			name: "non-standard port",
+			settings: backend.Settings{
+				Address: "origin.example.com",
+				Port:    fastly.ToPointer(8443),
+			},
+		},
+		{
+			name: "port 443 without ssl",
+			settings: backend.Settings{
+				Address: "origin.example.com",
+				Port:    fastly.ToPointer(443),
+			},
+			want: []string{"port 443 is used for TLS"},
+		},
+		{
+			name: "port 80 with ssl",
+			settings: backend.Settings{
+				Address: "origin.example.com",
+				Port:    fastly.ToPointer(80),
+				UseSSL:  true,
+			},
+			want: []string{"port 80 is used for plaintext HTTP"},
+		},
+		{
+			name: "ip with ssl and no cert hostname",
+			settings: backend.Settings{
+				Address:      "192.0.2.1",
+				Port:         fastly.ToPointer(443),
+				SSLCheckCert: true,
+				UseSSL:       true,
+			},
+			want: []string{"the address 192.0.2.1 is an IP"},
+		},
+		{
+			name: "ipv6 with ssl and no cert hostname",
+			settings: backend.Settings{
+				Address:      "2001:db8::1",
+				SSLCheckCert: true,
+				UseSSL:       true,
+			},
+			want: []string{"the address 2001:db8::1 is an IP"},
+		},
+		{
+			name: "ip with ssl and cert check disabled",
+			settings: backend.Settings{
+				Address: "192.0.2.1",
+				Port:    fastly.ToPointer(443),
+				UseSSL:  true,
+			},
+		},
+		{
+			name: "ip without ssl",
+			settings: backend.Settings{
+				Address:      "192.0.2.1",
+				SSLCheckCert: true,
+			},
+		},
+		{
+			name: "shared hosting without override host",
+			settings: backend.Settings{
+				Address: "my-app.HerokuApp.com",
+			},
+			want: []string{"is on shared hosting (herokuapp.com)"},
+		},
+		{
+			name: "shared hosting via cname",
+			settings: backend.Settings{
+				Address: "www.example.com",
+				CNAME:   "example.netlify.app",
+			},
+			want: []string{"the address www.example.com is on shared hosting (netlify.app)"},
+		},
+		{
+			name: "shared hosting with override host",
+			settings: backend.Settings{
+				Address:      "bucket.s3.amazonaws.com",
+				OverrideHost: "bucket.s3.amazonaws.com",
+			},
+		},
+		{
+			name: "suffix without a dot boundary",
+			settings: backend.Settings{
+				Address: "notgithub.io.example.com",
+			},
+		},
+		{
+			name: "first byte timeout longer than between bytes",
+			settings: backend.Settings{
+				Address:             "origin.example.com",
+				BetweenBytesTimeout: fastly.ToPointer(1000),
+				FirstByteTimeout:    fastly.ToPointer(5000),
+			},
+			want: []string{"the first-byte timeout (5000ms) is longer than the between-bytes timeout (1000ms)"},
+		},
+		{
+			name: "equal timeouts",
+			settings: backend.Settings{
+				Address:             "origin.example.com",
+				BetweenBytesTimeout: fastly.ToPointer(5000),
+				FirstByteTimeout:    fastly.ToPointer(5000),
+			},
+		},
+		{
+			name: "one timeout",
+			settings: backend.Settings{
+				Address:          "origin.example.com",
+				FirstByteTimeout: fastly.ToPointer(5000),
+			},
+		},
+		{
+			name: "several findings",
+			settings: backend.Settings{
+				Address:             "203.0.113.7",
+				BetweenBytesTimeout: fastly.ToPointer(1),
+				FirstByteTimeout:    fastly.ToPointer(2),
+				Port:                fastly.ToPointer(80),
+				SSLCheckCert:        true,
+				UseSSL:              true,
+			},
+			want: []string{"port 80", "is an IP", "first-byte timeout"},
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			findings := backend.Check(testcase.settings)
+			if len(findings) != len(testcase.want) {
+				t.Fatalf("want %d findings, have %d: %v", len(testcase.want), len(findings), findings)
+			}
+			for i, f := range findings {
+				testutil.AssertStringContains(t, f.Problem, testcase.want[i])
+				if f.Remediation == "" {
+					t.Errorf("want a remediation for %q", f.Problem)
+				}
+			}
+		})
+	}
+}
+
+func TestBackendCheckFlags(t *testing.T) {
+	args := testutil.Args
+	getBackendShared := func(i *fastly.GetBackendInput) (*fastly.Backend, error) {
+		return &fastly.Backend{
+			Address:        fastly.ToPointer("www.example.org"),
+			Name:           fastly.ToPointer(i.Name),
+			Port:           fastly.ToPointer(443),
+			ServiceID:      fastly.ToPointer(i.ServiceID),
+			ServiceVersion: fastly.ToPointer(i.ServiceVersion),
+			UseSSL:         fastly.ToPointer(true),
+		}, nil
+	}
+	updateBackendName := func(i *fastly.UpdateBackendInput) (*fastly.Backend, error) {
+		return &fastly.Backend{
+			Name:           fastly.ToPointer(i.Name),
+			ServiceID:      fastly.ToPointer(i.ServiceID),
+			ServiceVersion: fastly.ToPointer(i.ServiceVersion),
+		}, nil
+	}
+
+	scenarios := []struct {
+		testutil.TestScenario
+		wantNoOutput string
+	}{
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "create sends use_ssl",
+				Args: args("backend create --service-id 123 --version 3 --address www.test.com --name www.test.com --use-ssl"),
+				API: mock.API{
+					ListVersionsFn: testutil.ListVersions,
+					CreateBackendFn: func(i *fastly.CreateBackendInput) (*fastly.Backend, error) {
+						if i.UseSSL == nil || !*i.UseSSL {
+							return nil, errors.New("want use_ssl to be sent")
+						}
+						return createBackendOK(i)
+					},
+				},
+				WantOutput: "Created backend www.test.com (service 123 version 3)",
+			},
+			wantNoOutput: "WARNING",
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "create warns",
+				Args: args("backend create --service-id 123 --version 3 --address www.test.com --name www.test.com --port 443"),
+				API: mock.API{
+					ListVersionsFn:  testutil.ListVersions,
+					CreateBackendFn: createBackendOK,
+				},
+				WantOutput: "WARNING: port 443 is used for TLS, but SSL is disabled so Fastly will send plaintext HTTP to it. Pass --use-ssl",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "create strict",
+				Args: args("backend create --service-id 123 --version 3 --address www.test.com --name www.test.com --port 443 --first-byte-timeout 2000 --between-bytes-timeout 1000 --strict"),
+				API: mock.API{
+					ListVersionsFn: testutil.ListVersions,
+				},
+				WantError: "backend settings look misconfigured (--strict): 2 problems found:\n\t- port 443 is used for TLS",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "update warns using the current backend",
+				Args: args("backend update --service-id 123 --version 3 --name www.test.com --port 80"),
+				API: mock.API{
+					ListVersionsFn:  testutil.ListVersions,
+					GetBackendFn:    getBackendShared,
+					UpdateBackendFn: updateBackendName,
+				},
+				WantOutput: "WARNING: port 80 is used for plaintext HTTP",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "update resolves the address",
+				Args: args("backend update --service-id 123 --version 3 --name www.test.com --comment test --use-ssl"),
+				API: mock.API{
+					ListVersionsFn:  testutil.ListVersions,
+					GetBackendFn:    getBackendShared,
+					UpdateBackendFn: updateBackendName,
+				},
+				WantOutput: "the address www.example.org is on shared hosting (github.io)",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "update strict",
+				Args: args("backend update --service-id 123 --version 3 --name www.test.com --port 80 --strict"),
+				API: mock.API{
+					ListVersionsFn: testutil.ListVersions,
+					GetBackendFn:   getBackendShared,
+				},
+				WantError: "backend settings look misconfigured (--strict): 2 problems found",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "update without checked flags",
+				Args: args("backend update --service-id 123 --version 3 --name www.test.com --comment test"),
+				API: mock.API{
+					ListVersionsFn:  testutil.ListVersions,
+					UpdateBackendFn: updateBackendName,
+				},
+				WantOutput: "Updated backend www.test.com (service 123 version 3)",
+			},
+			wantNoOutput: "WARNING",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(testcase.Args, &stdout)
+				opts.APIClientFactory = mock.APIClient(testcase.API)
+				opts.Resolver = fakeResolver{"www.example.org": "example.github.io."}
+				return opts, nil
+			}
+			err := app.Run(testcase.Args, nil)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+			if testcase.wantNoOutput != "" && strings.Contains(stdout.String(), testcase.wantNoOutput) {
+				t.Errorf("want output without %q, have:\n%s", testcase.wantNoOutput, stdout.String())
+			}
+		})
+	}
+}
+
+// fakeResolver maps hostnames to their CNAME.
+type fakeResolver map[string]string
+
+func (r fakeResolver) LookupCNAME(_ context.Context, host string) (string, error) {
+	if cname, ok := r[host]; ok {
+		return cname, nil
+	}
+	return "", errors.New("no such host")
+}
+
+func (r fakeResolver) LookupHost(_ context.Context, _ string) ([]string, error) {
+	return nil, errors.New("no such host")
+}
@@ -0,0 +1,214 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/fastly/cli/pkg/deprecation"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// The --ssl-check-cert flag is deprecated as the API checks SSL certs unless
+// --no-ssl-check-cert is set.
+var (
+	sslCheckCertCreateFlag = deprecation.Register(sslCheckCertFlag("backend create"))
+	sslCheckCertUpdateFlag = deprecation.Register(sslCheckCertFlag("backend update"))
+)
+
+// sslCheckCertFlag returns the deprecated --ssl-check-cert flag of a command.
+func sslCheckCertFlag(command string) deprecation.Item {
+	return deprecation.Item{
+		Kind:      deprecation.KindFlag,
+		Name:      "ssl-check-cert",
+		Command:   command,
+		Hint:      "The Fastly API checks SSL certs by default. Use --no-ssl-check-cert to disable the check.",
+		RemovedIn: "v11.0.0",
+	}
+}
+
+// sharedHostingSuffixes are domains of hosting platforms that serve many sites
+// from the same addresses and route each request by its Host header.
+var sharedHostingSuffixes = []string{
+	"appspot.com",
+	"azurewebsites.net",
+	"blob.core.windows.net",
+	"cloudfront.net",
+	"elasticbeanstalk.com",
+	"github.io",
+	"herokuapp.com",
+	"herokudns.com",
+	"netlify.app",
+	"onrender.com",
+	"pages.dev",
+	"s3.amazonaws.com",
+	"storage.googleapis.com",
+	"vercel.app",
+}
+
+// Settings are the effective settings of a backend, i.e. the flags merged
+// with the current backend when updating.
+type Settings struct {
+	Address string
+	// CNAME is the canonical name of the address (empty if unknown).
+	CNAME string
+	// BetweenBytesTimeout and FirstByteTimeout are only compared when both
+	// are set.
+	BetweenBytesTimeout *int
+	FirstByteTimeout    *int
+	OverrideHost        string
+	Port                *int
+	SSLCertHostname     string
+	SSLCheckCert        bool
+	UseSSL              bool
+}
+
+// Finding is a likely misconfiguration of a backend.
+type Finding struct {
+	// Problem explains what is likely wrong.
+	Problem string
+	// Remediation explains how to fix it.
+	Remediation string
+}
+
+func (f Finding) Error() string {
+	return f.Problem
+}
+
+// rules are the checks Check runs, each returning nil when the settings pass.
+var rules = []func(s Settings) *Finding{
+	checkPortSSL,
+	checkCertHostname,
+	checkOverrideHost,
+	checkTimeouts,
+}
+
+// Check returns the likely misconfigurations of a backend.
+func Check(s Settings) []Finding {
+	var findings []Finding
+	for _, rule := range rules {
+		if f := rule(s); f != nil {
+			findings = append(findings, *f)
+		}
+	}
+	return findings
+}
+
+func checkPortSSL(s Settings) *Finding {
+	if s.Port == nil {
+		return nil
+	}
+	switch {
+	case *s.Port == 443 && !s.UseSSL:
+		return &Finding{
+			Problem:     "port 443 is used for TLS, but SSL is disabled so Fastly will send plaintext HTTP to it",
+			Remediation: "Pass --use-ssl, or use --port 80 if the origin really serves plaintext on 443.",
+		}
+	case *s.Port == 80 && s.UseSSL:
+		return &Finding{
+			Problem:     "port 80 is used for plaintext HTTP, but SSL is enabled so Fastly will attempt a TLS handshake with it",
+			Remediation: "Use --port 443, or disable SSL (--use-ssl=false) if the origin only serves plaintext.",
+		}
+	}
+	return nil
+}
+
+func checkCertHostname(s Settings) *Finding {
+	if !s.UseSSL || !s.SSLCheckCert || s.SSLCertHostname != "" || net.ParseIP(s.Address) == nil {
+		return nil
+	}
+	return &Finding{
+		Problem:     fmt.Sprintf("the address %s is an IP, so with SSL enabled the origin certificate can't be verified without a certificate hostname", s.Address),
+		Remediation: "Pass --ssl-cert-hostname with the hostname on the origin's certificate.",
+	}
+}
+
+func checkOverrideHost(s Settings) *Finding {
+	if s.OverrideHost != "" {
+		return nil
+	}
+	for _, host := range []string{s.Address, s.CNAME} {
+		if suffix := sharedHostingSuffix(host); suffix != "" {
+			return &Finding{
+				Problem:     fmt.Sprintf("the address %s is on shared hosting (%s), which routes requests by Host header, but no override host is set", s.Address, suffix),
+				Remediation: "Pass --override-host with the hostname the hosting provider expects (usually the address itself).",
+			}
+		}
+	}
+	return nil
+}
+
+func checkTimeouts(s Settings) *Finding {
+	if s.FirstByteTimeout == nil || s.BetweenBytesTimeout == nil || *s.FirstByteTimeout <= *s.BetweenBytesTimeout {
+		return nil
+	}
+	return &Finding{
+		Problem:     fmt.Sprintf("the first-byte timeout (%dms) is longer than the between-bytes timeout (%dms)", *s.FirstByteTimeout, *s.BetweenBytesTimeout),
+		Remediation: "Check the timeouts aren't the wrong way round: --first-byte-timeout applies until the response starts, --between-bytes-timeout to each gap after that.",
+	}
+}
+
+// sharedHostingSuffix returns the shared hosting domain host belongs to, if
+// any.
+func sharedHostingSuffix(host string) string {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, suffix := range sharedHostingSuffixes {
+		if host == suffix || strings.HasSuffix(host, "."+suffix) {
+			return suffix
+		}
+	}
+	return ""
+}
+
+// lookupCNAME returns the canonical name of a hostname address, or an empty
+// string if it can't be resolved.
+func lookupCNAME(g *global.Data, address string) string {
+	if g.Resolver == nil || address == "" || net.ParseIP(address) != nil {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	cname, err := g.Resolver.LookupCNAME(ctx, address)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(cname, ".")
+}
+
+// checkSettings displays a warning for each likely misconfiguration, or
+// returns them all as an error in strict mode (see --strict).
+func checkSettings(g *global.Data, out io.Writer, s Settings) error {
+	if s.OverrideHost == "" {
+		s.CNAME = lookupCNAME(g, s.Address)
+	}
+	findings := Check(s)
+	if len(findings) == 0 {
+		return nil
+	}
+
+	if g.Strict() {
+		errs := make(fsterr.MultiError, 0, len(findings))
+		remediations := make([]string, 0, len(findings))
+		for _, f := range findings {
+			errs = append(errs, f)
+			remediations = append(remediations, f.Remediation)
+		}
+		err := fsterr.RemediationError{
+			Inner:       fmt.Errorf("backend settings look misconfigured (--strict): %w", errs),
+			Remediation: strings.Join(remediations, "\n"),
+		}
+		g.ErrLog.Add(err)
+		return err
+	}
+
+	for _, f := range findings {
+		text.Warning(out, "%s. %s", f.Problem, f.Remediation)
+	}
+	text.Break(out)
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/fastly/cli/pkg/argparser"
 	"github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/naming"
 	"github.com/fastly/cli/pkg/text"
 )
 
@@ -102,7 +103,7 @@ func NewCreateCommand(parent argparser.Registerer, g *global.Data) *CreateComman
 	c.CmdClause.Flag("shield", "The shield POP designated to reduce inbound load on this origin by serving the cached data to the rest of the network").Action(c.shield.Set).StringVar(&c.shield.Value)
 	c.CmdClause.Flag("ssl-ca-cert", "CA certificate attached to origin").Action(c.sslCACert.Set).StringVar(&c.sslCACert.Value)
 	c.CmdClause.Flag("ssl-cert-hostname", "Overrides ssl_hostname, but only for cert verification. Does not affect SNI at all.").Action(c.sslCertHostname.Set).StringVar(&c.sslCertHostname.Value)
-	c.CmdClause.Flag("ssl-check-cert", "Be strict on checking SSL certs").Action(c.sslCheckCert.Set).BoolVar(&c.sslCheckCert.Value)
+	c.CmdClause.Flag("ssl-check-cert", "Be strict on checking SSL certs").Hidden().Action(c.sslCheckCert.Set).Action(c.Deprecated(sslCheckCertCreateFlag)).BoolVar(&c.sslCheckCert.Value)
 	c.CmdClause.Flag("ssl-ciphers", "List of OpenSSL ciphers (https://www.openssl.org/docs/man1.0.2/man1/ciphers)").Action(c.sslCiphers.Set).StringVar(&c.sslCiphers.Value)
 	c.CmdClause.Flag("ssl-client-cert", "Client certificate attached to origin").Action(c.sslClientCert.Set).StringVar(&c.sslClientCert.Value)
 	c.CmdClause.Flag("ssl-client-key", "Client key attached to origin").Action(c.sslClientKey.Set).StringVar(&c.sslClientKey.Value)
@@ -115,6 +116,12 @@ func NewCreateCommand(parent argparser.Registerer, g *global.Data) *CreateComman
 
 // Exec invokes the application logic for the command.
 func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.name.WasSet {
+		if err := c.ValidateName(naming.Backend, c.name.Value); err != nil {
+			return err
+		}
+	}
+
 	serviceID, serviceVersion, err := argparser.ServiceDetails(argparser.ServiceDetailsOpts{
 		AutoCloneFlag:      c.autoClone,
 		APIClient:          c.Globals.APIClient,
@@ -188,7 +195,6 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 		input.SSLCertHostname = &c.sslCertHostname.Value
 	}
 	if c.sslCheckCert.WasSet {
-		text.Deprecated(out, "The Fastly API defaults `ssl_check_cert` to true. Use `--no-ssl-check-cert` to disable this setting.\n\n")
 		input.SSLCheckCert = fastly.ToPointer(fastly.Compatibool(c.sslCheckCert.Value))
 	}
 	if c.sslCiphers.WasSet {
@@ -203,6 +209,9 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 	if c.sslSNIHostname.WasSet {
 		input.SSLSNIHostname = &c.sslSNIHostname.Value
 	}
+	if c.useSSL.WasSet {
+		input.UseSSL = fastly.ToPointer(fastly.Compatibool(c.useSSL.Value))
+	}
 	if c.weight.WasSet {
 		input.Weight = &c.weight.Value
 	}
@@ -236,6 +245,10 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 		}
 	}
 
+	if err := checkSettings(c.Globals, out, createSettings(&input)); err != nil {
+		return err
+	}
+
 	b, err := c.Globals.APIClient.CreateBackend(&input)
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
@@ -249,6 +262,20 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 	return nil
 }
 
+// createSettings returns the settings the backend will be created with.
+func createSettings(input *fastly.CreateBackendInput) Settings {
+	return Settings{
+		Address:             fastly.ToValue(input.Address),
+		BetweenBytesTimeout: input.BetweenBytesTimeout,
+		FirstByteTimeout:    input.FirstByteTimeout,
+		OverrideHost:        fastly.ToValue(input.OverrideHost),
+		Port:                input.Port,
+		SSLCertHostname:     fastly.ToValue(input.SSLCertHostname),
+		SSLCheckCert:        input.SSLCheckCert == nil || bool(*input.SSLCheckCert),
+		UseSSL:              input.UseSSL != nil && bool(*input.UseSSL),
+	}
+}
+
 // SetBackendHostDefaults configures the OverrideHost and SSLSNIHostname fields.
 //
 // By default we set the override_host and ssl_sni_hostname properties of the
@@ -0,0 +1,341 @@
+package backend
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// Probe result statuses.
+const (
+	ProbeStatusOK      = "ok"
+	ProbeStatusWarning = "warning"
+	ProbeStatusError   = "error"
+)
+
+// certExpiryWarning is how close to expiry a certificate has to be before the
+// probe warns about it.
+const certExpiryWarning = 30 * 24 * time.Hour
+
+// ProbeCommand checks whether the backends on a service version are reachable
+// and correctly configured.
+type ProbeCommand struct {
+	argparser.Base
+	argparser.JSONOutput
+
+	name           argparser.OptionalString
+	serviceName    argparser.OptionalServiceNameID
+	serviceVersion argparser.OptionalServiceVersion
+	timeout        time.Duration
+}
+
+// NewProbeCommand returns a usable command registered under the parent.
+func NewProbeCommand(parent argparser.Registerer, g *global.Data) *ProbeCommand {
+	c := ProbeCommand{
+		Base: argparser.Base{
+			Globals: g,
+		},
+	}
+	c.CmdClause = parent.Command("probe", "Check the connectivity and TLS configuration of backends from this machine")
+
+	// Required.
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Name:        argparser.FlagVersionName,
+		Description: argparser.FlagVersionDesc,
+		Dst:         &c.serviceVersion.Value,
+		Required:    true,
+	})
+
+	// Optional.
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.CmdClause.Flag("name", "Name of the backend to probe (all backends are probed if omitted)").Short('n').Action(c.name.Set).StringVar(&c.name.Value)
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Name:        argparser.FlagServiceIDName,
+		Description: argparser.FlagServiceIDDesc,
+		Dst:         &g.Manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        argparser.FlagServiceName,
+		Description: argparser.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.CmdClause.Flag("timeout", "Maximum duration of each connection attempt").Default("5s").DurationVar(&c.timeout)
+	return &c
+}
+
+// ProbeIssue is a misconfiguration or failure found by a probe.
+type ProbeIssue struct {
+	Severity    string `json:"severity"`
+	Problem     string `json:"problem"`
+	Remediation string `json:"remediation"`
+}
+
+// ProbeResult is the outcome of probing a single backend.
+type ProbeResult struct {
+	Name        string       `json:"name"`
+	Address     string       `json:"address"`
+	Port        int          `json:"port"`
+	UseSSL      bool         `json:"use_ssl"`
+	Status      string       `json:"status"`
+	ConnectMS   int64        `json:"connect_ms"`
+	HandshakeMS int64        `json:"handshake_ms,omitempty"`
+	CertExpiry  *time.Time   `json:"cert_expiry,omitempty"`
+	Issues      []ProbeIssue `json:"issues,omitempty"`
+}
+
+func (r *ProbeResult) warn(problem, remediation string) {
+	r.Issues = append(r.Issues, ProbeIssue{ProbeStatusWarning, problem, remediation})
+	if r.Status == ProbeStatusOK {
+		r.Status = ProbeStatusWarning
+	}
+}
+
+func (r *ProbeResult) fail(problem, remediation string) {
+	r.Issues = append(r.Issues, ProbeIssue{ProbeStatusError, problem, remediation})
+	r.Status = ProbeStatusError
+}
+
+// Exec invokes the application logic for the command.
+func (c *ProbeCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	serviceID, serviceVersion, err := argparser.ServiceDetails(argparser.ServiceDetailsOpts{
+		AllowActiveLocked:  true,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           *c.Globals.Manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": fsterr.ServiceVersion(serviceVersion),
+		})
+		return err
+	}
+
+	backends, err := c.Globals.APIClient.ListBackends(&fastly.ListBackendsInput{
+		ServiceID:      serviceID,
+		ServiceVersion: fastly.ToValue(serviceVersion.Number),
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": fastly.ToValue(serviceVersion.Number),
+		})
+		return err
+	}
+
+	if c.name.WasSet {
+		var filtered []*fastly.Backend
+		for _, b := range backends {
+			if fastly.ToValue(b.Name) == c.name.Value {
+				filtered = append(filtered, b)
+			}
+		}
+		if len(filtered) == 0 {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("backend '%s' not found on service version %d", c.name.Value, fastly.ToValue(serviceVersion.Number)),
+				Remediation: "Run `fastly backend list` to see the available backends.",
+			}
+		}
+		backends = filtered
+	}
+
+	results := make([]ProbeResult, 0, len(backends))
+	var failed int
+	for _, b := range backends {
+		r := Probe(b, c.timeout)
+		if r.Status == ProbeStatusError {
+			failed++
+		}
+		results = append(results, r)
+	}
+
+	if ok, err := c.WriteJSON(out, results); ok {
+		if err != nil {
+			return err
+		}
+	} else {
+		printProbeResults(out, results)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d backends failed the probe", failed, len(results))
+	}
+	return nil
+}
+
+func printProbeResults(out io.Writer, results []ProbeResult) {
+	tw := text.NewTable(out)
+	tw.AddHeader("NAME", "ADDRESS", "PORT", "SSL", "STATUS", "CONNECT", "HANDSHAKE", "CERT EXPIRY")
+	for _, r := range results {
+		handshake, expiry := "-", "-"
+		if r.UseSSL && r.HandshakeMS > 0 {
+			handshake = fmt.Sprintf("%dms", r.HandshakeMS)
+		}
+		if r.CertExpiry != nil {
+			expiry = text.FormatTimeLayout(r.CertExpiry, time.DateOnly)
+		}
+		connect := "-"
+		if r.ConnectMS > 0 || r.Status != ProbeStatusError {
+			connect = fmt.Sprintf("%dms", r.ConnectMS)
+		}
+		tw.AddLine(r.Name, r.Address, r.Port, r.UseSSL, r.Status, connect, handshake, expiry)
+	}
+	tw.Print()
+
+	for _, r := range results {
+		for _, i := range r.Issues {
+			text.Break(out)
+			if i.Severity == ProbeStatusError {
+				text.Error(out, "%s: %s", r.Name, i.Problem)
+			} else {
+				text.Warning(out, "%s: %s", r.Name, i.Problem)
+			}
+			text.Indent(out, 4, "%s", i.Remediation)
+		}
+	}
+}
+
+// Probe connects to the backend from the local machine and validates its TLS
+// configuration. Fastly's own network may see a different result (e.g. due to
+// firewalls), but the most common misconfigurations are detectable locally.
+func Probe(b *fastly.Backend, timeout time.Duration) ProbeResult {
+	r := ProbeResult{
+		Name:    fastly.ToValue(b.Name),
+		Address: fastly.ToValue(b.Address),
+		Port:    fastly.ToValue(b.Port),
+		UseSSL:  fastly.ToValue(b.UseSSL),
+		Status:  ProbeStatusOK,
+	}
+	if r.Port == 0 {
+		r.Port = 80
+		if r.UseSSL {
+			r.Port = 443
+		}
+	}
+
+	switch {
+	case r.Port == 443 && !r.UseSSL:
+		r.warn("port 443 is configured without use_ssl", "Enable TLS with `fastly backend update --use-ssl` or use a plaintext port.")
+	case r.Port == 80 && r.UseSSL:
+		r.warn("port 80 is configured with use_ssl", "Set `--port 443` (or the port your origin serves TLS on).")
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	start := time.Now()
+	conn, err := dialer.Dial("tcp", net.JoinHostPort(r.Address, strconv.Itoa(r.Port)))
+	if err != nil {
+		r.fail(fmt.Sprintf("unable to connect: %v", err), "Check the backend address and port, and that the origin accepts connections from Fastly.")
+		return r
+	}
+	r.ConnectMS = time.Since(start).Milliseconds()
+	defer conn.Close()
+
+	if !r.UseSSL {
+		return r
+	}
+
+	serverName := fastly.ToValue(b.SSLSNIHostname)
+	if serverName == "" {
+		serverName = fastly.ToValue(b.SSLCertHostname)
+	}
+	if serverName == "" && net.ParseIP(r.Address) == nil {
+		serverName = r.Address
+	}
+
+	// NOTE: Verification is done manually below so that the specific failure
+	// (expiry, hostname mismatch, untrusted issuer) can be reported.
+	// #nosec G402
+	tlsConn := tls.Client(conn, &tls.Config{
+		InsecureSkipVerify: true,
+		ServerName:         serverName,
+	})
+	_ = tlsConn.SetDeadline(time.Now().Add(timeout))
+	start = time.Now()
+	if err := tlsConn.Handshake(); err != nil {
+		r.fail(fmt.Sprintf("TLS handshake failed: %v", err), "Check the origin serves TLS on this port and supports the configured SNI hostname.")
+		return r
+	}
+	r.HandshakeMS = time.Since(start).Milliseconds()
+
+	certs := tlsConn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		r.fail("origin presented no certificate", "Configure a certificate on the origin.")
+		return r
+	}
+	leaf := certs[0]
+	r.CertExpiry = &leaf.NotAfter
+
+	// When certificate checks are disabled Fastly still connects, and so
+	// verification failures are only warnings.
+	certIssue := r.fail
+	if b.SSLCheckCert != nil && !fastly.ToValue(b.SSLCheckCert) {
+		certIssue = r.warn
+		r.warn("certificate verification is disabled (ssl_check_cert is false)", "Enable certificate verification with `fastly backend update --ssl-check-cert`.")
+	}
+
+	now := time.Now()
+	switch {
+	case now.After(leaf.NotAfter):
+		r.fail(fmt.Sprintf("certificate expired on %s", text.FormatTimeLayout(&leaf.NotAfter, time.RFC3339)), "Renew the certificate on the origin.")
+	case leaf.NotAfter.Sub(now) < certExpiryWarning:
+		r.warn(fmt.Sprintf("certificate expires on %s", text.FormatTimeLayout(&leaf.NotAfter, time.RFC3339)), "Renew the certificate on the origin soon.")
+	}
+
+	certHostname := fastly.ToValue(b.SSLCertHostname)
+	if certHostname == "" {
+		certHostname = serverName
+	}
+	if certHostname == "" {
+		r.warn("no certificate hostname is configured", "Set `--ssl-cert-hostname` so Fastly can verify the origin certificate.")
+	} else if err := leaf.VerifyHostname(certHostname); err != nil {
+		certIssue(fmt.Sprintf("certificate hostname mismatch: %v", err), "Set `--ssl-cert-hostname` to a name the certificate is valid for, or fix the certificate on the origin.")
+	}
+
+	if err := verifyChain(certs, fastly.ToValue(b.SSLCACert)); err != nil {
+		var unknown x509.UnknownAuthorityError
+		if errors.As(err, &unknown) {
+			certIssue("certificate is signed by an unknown authority", "Use a certificate from a public CA or set `--ssl-ca-cert` to the issuing CA.")
+		}
+	}
+
+	return r
+}
+
+// verifyChain verifies the chain of trust, ignoring expiry and hostname which
+// are reported separately.
+func verifyChain(certs []*x509.Certificate, caPEM string) error {
+	opts := x509.VerifyOptions{
+		CurrentTime:   certs[0].NotBefore.Add(time.Second),
+		Intermediates: x509.NewCertPool(),
+	}
+	if caPEM != "" {
+		opts.Roots = x509.NewCertPool()
+		opts.Roots.AppendCertsFromPEM([]byte(caPEM))
+	}
+	for _, c := range certs[1:] {
+		opts.Intermediates.AddCert(c)
+	}
+	_, err := certs[0].Verify(opts)
+	return err
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/fastly/cli/pkg/argparser"
 	"github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/naming"
 	"github.com/fastly/cli/pkg/text"
 )
 
@@ -100,7 +101,7 @@ func NewUpdateCommand(parent argparser.Registerer, g *global.Data) *UpdateComman
 	c.CmdClause.Flag("shield", "The shield POP designated to reduce inbound load on this origin by serving the cached data to the rest of the network").Action(c.Shield.Set).StringVar(&c.Shield.Value)
 	c.CmdClause.Flag("ssl-ca-cert", "CA certificate attached to origin").Action(c.SSLCACert.Set).StringVar(&c.SSLCACert.Value)
 	c.CmdClause.Flag("ssl-cert-hostname", "Overrides ssl_hostname, but only for cert verification. Does not affect SNI at all.").Action(c.SSLCertHostname.Set).StringVar(&c.SSLCertHostname.Value)
-	c.CmdClause.Flag("ssl-check-cert", "Be strict on checking SSL certs").Action(c.SSLCheckCert.Set).BoolVar(&c.SSLCheckCert.Value)
+	c.CmdClause.Flag("ssl-check-cert", "Be strict on checking SSL certs").Hidden().Action(c.SSLCheckCert.Set).Action(c.Deprecated(sslCheckCertUpdateFlag)).BoolVar(&c.SSLCheckCert.Value)
 	c.CmdClause.Flag("ssl-ciphers", "List of OpenSSL ciphers (https://www.openssl.org/docs/man1.0.2/man1/ciphers)").Action(c.SSLCiphers.Set).StringVar(&c.SSLCiphers.Value)
 	c.CmdClause.Flag("ssl-client-cert", "Client certificate attached to origin").Action(c.SSLClientCert.Set).StringVar(&c.SSLClientCert.Value)
 	c.CmdClause.Flag("ssl-client-key", "Client key attached to origin").Action(c.SSLClientKey.Set).StringVar(&c.SSLClientKey.Value)
@@ -112,6 +113,12 @@ func NewUpdateCommand(parent argparser.Registerer, g *global.Data) *UpdateComman
 
 // Exec invokes the application logic for the command.
 func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.NewName.WasSet {
+		if err := c.ValidateName(naming.Backend, c.NewName.Value); err != nil {
+			return err
+		}
+	}
+
 	serviceID, serviceVersion, err := argparser.ServiceDetails(argparser.ServiceDetailsOpts{
 		AutoCloneFlag:      c.autoClone,
 		APIClient:          c.Globals.APIClient,
@@ -200,7 +207,6 @@ func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
 	}
 
 	if c.SSLCheckCert.WasSet {
-		text.Deprecated(out, "The Fastly API defaults `ssl_check_cert` to true. Use `--no-ssl-check-cert` to disable this setting.\n\n")
 		input.SSLCheckCert = fastly.ToPointer(fastly.Compatibool(c.SSLCheckCert.Value))
 	}
 
@@ -236,6 +242,24 @@ func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
 		input.SSLCiphers = &c.SSLCiphers.Value
 	}
 
+	if c.checkedFlagsSet() {
+		current, err := c.Globals.APIClient.GetBackend(&fastly.GetBackendInput{
+			ServiceID:      serviceID,
+			ServiceVersion: fastly.ToValue(serviceVersion.Number),
+			Name:           c.name,
+		})
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID":      serviceID,
+				"Service Version": serviceVersion.Number,
+			})
+			return err
+		}
+		if err := checkSettings(c.Globals, out, updateSettings(current, input)); err != nil {
+			return err
+		}
+	}
+
 	b, err := c.Globals.APIClient.UpdateBackend(input)
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
@@ -248,3 +272,62 @@ func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
 	text.Success(out, "Updated backend %s (service %s version %d)", fastly.ToValue(b.Name), fastly.ToValue(b.ServiceID), fastly.ToValue(b.ServiceVersion))
 	return nil
 }
+
+// checkedFlagsSet reports whether any flag affecting the settings validated
+// by Check was set. Otherwise the current backend isn't fetched or checked.
+func (c *UpdateCommand) checkedFlagsSet() bool {
+	for _, set := range []bool{
+		c.Address.WasSet,
+		c.BetweenBytesTimeout.WasSet,
+		c.FirstByteTimeout.WasSet,
+		c.NoSSLCheckCert.WasSet,
+		c.OverrideHost.WasSet,
+		c.Port.WasSet,
+		c.SSLCertHostname.WasSet,
+		c.SSLCheckCert.WasSet,
+		c.UseSSL.WasSet,
+	} {
+		if set {
+			return true
+		}
+	}
+	return false
+}
+
+// updateSettings returns the settings of the backend once the input is
+// applied to it.
+//
+// NOTE: The timeouts are only taken from the input, as the API defaults
+// (15s first byte, 10s between bytes) would otherwise be reported on every
+// update.
+func updateSettings(b *fastly.Backend, input *fastly.UpdateBackendInput) Settings {
+	s := Settings{
+		Address:             fastly.ToValue(b.Address),
+		BetweenBytesTimeout: input.BetweenBytesTimeout,
+		FirstByteTimeout:    input.FirstByteTimeout,
+		OverrideHost:        fastly.ToValue(b.OverrideHost),
+		Port:                b.Port,
+		SSLCertHostname:     fastly.ToValue(b.SSLCertHostname),
+		SSLCheckCert:        b.SSLCheckCert == nil || *b.SSLCheckCert,
+		UseSSL:              fastly.ToValue(b.UseSSL),
+	}
+	if input.Address != nil {
+		s.Address = *input.Address
+	}
+	if input.OverrideHost != nil {
+		s.OverrideHost = *input.OverrideHost
+	}
+	if input.Port != nil {
+		s.Port = input.Port
+	}
+	if input.SSLCertHostname != nil {
+		s.SSLCertHostname = *input.SSLCertHostname
+	}
+	if input.SSLCheckCert != nil {
+		s.SSLCheckCert = bool(*input.SSLCheckCert)
+	}
+	if input.UseSSL != nil {
+		s.UseSSL = bool(*input.UseSSL)
+	}
+	return s
+}
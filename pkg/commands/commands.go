@@ -6,16 +6,24 @@ import (
 	"github.com/fastly/cli/pkg/argparser"
 	"github.com/fastly/cli/pkg/commands/acl"
 	"github.com/fastly/cli/pkg/commands/aclentry"
+	"github.com/fastly/cli/pkg/commands/alias"
 	"github.com/fastly/cli/pkg/commands/authtoken"
 	"github.com/fastly/cli/pkg/commands/backend"
+	"github.com/fastly/cli/pkg/commands/completion"
 	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/commands/compute/cache"
+	"github.com/fastly/cli/pkg/commands/compute/viceroy"
 	"github.com/fastly/cli/pkg/commands/config"
 	"github.com/fastly/cli/pkg/commands/configstore"
 	"github.com/fastly/cli/pkg/commands/configstoreentry"
+	"github.com/fastly/cli/pkg/commands/deprecations"
 	"github.com/fastly/cli/pkg/commands/dictionary"
 	"github.com/fastly/cli/pkg/commands/dictionaryentry"
 	"github.com/fastly/cli/pkg/commands/domain"
+	"github.com/fastly/cli/pkg/commands/events"
+	"github.com/fastly/cli/pkg/commands/explain"
 	"github.com/fastly/cli/pkg/commands/healthcheck"
+	"github.com/fastly/cli/pkg/commands/history"
 	"github.com/fastly/cli/pkg/commands/install"
 	"github.com/fastly/cli/pkg/commands/ip"
 	"github.com/fastly/cli/pkg/commands/kvstore"
@@ -48,6 +56,7 @@ import (
 	"github.com/fastly/cli/pkg/commands/logging/sumologic"
 	"github.com/fastly/cli/pkg/commands/logging/syslog"
 	"github.com/fastly/cli/pkg/commands/logtail"
+	"github.com/fastly/cli/pkg/commands/plan"
 	"github.com/fastly/cli/pkg/commands/pop"
 	"github.com/fastly/cli/pkg/commands/products"
 	"github.com/fastly/cli/pkg/commands/profile"
@@ -57,20 +66,25 @@ import (
 	"github.com/fastly/cli/pkg/commands/secretstore"
 	"github.com/fastly/cli/pkg/commands/secretstoreentry"
 	"github.com/fastly/cli/pkg/commands/service"
+	servicealias "github.com/fastly/cli/pkg/commands/service/alias"
+	servicetag "github.com/fastly/cli/pkg/commands/service/tag"
 	"github.com/fastly/cli/pkg/commands/serviceauth"
 	"github.com/fastly/cli/pkg/commands/serviceversion"
 	"github.com/fastly/cli/pkg/commands/shellcomplete"
 	"github.com/fastly/cli/pkg/commands/sso"
 	"github.com/fastly/cli/pkg/commands/stats"
+	"github.com/fastly/cli/pkg/commands/supportbundle"
 	tlsconfig "github.com/fastly/cli/pkg/commands/tls/config"
 	tlscustom "github.com/fastly/cli/pkg/commands/tls/custom"
 	tlscustomactivation "github.com/fastly/cli/pkg/commands/tls/custom/activation"
 	tlscustomcertificate "github.com/fastly/cli/pkg/commands/tls/custom/certificate"
 	tlscustomdomain "github.com/fastly/cli/pkg/commands/tls/custom/domain"
 	tlscustomprivatekey "github.com/fastly/cli/pkg/commands/tls/custom/privatekey"
+	tlsexpiry "github.com/fastly/cli/pkg/commands/tls/expiry"
 	tlsplatform "github.com/fastly/cli/pkg/commands/tls/platform"
 	tlssubscription "github.com/fastly/cli/pkg/commands/tls/subscription"
 	"github.com/fastly/cli/pkg/commands/update"
+	"github.com/fastly/cli/pkg/commands/usage"
 	"github.com/fastly/cli/pkg/commands/user"
 	"github.com/fastly/cli/pkg/commands/vcl"
 	"github.com/fastly/cli/pkg/commands/vcl/condition"
@@ -106,32 +120,55 @@ func Define(
 	aclEntryCreate := aclentry.NewCreateCommand(aclEntryCmdRoot.CmdClause, data)
 	aclEntryDelete := aclentry.NewDeleteCommand(aclEntryCmdRoot.CmdClause, data)
 	aclEntryDescribe := aclentry.NewDescribeCommand(aclEntryCmdRoot.CmdClause, data)
+	aclEntryImport := aclentry.NewImportCommand(aclEntryCmdRoot.CmdClause, data)
 	aclEntryList := aclentry.NewListCommand(aclEntryCmdRoot.CmdClause, data)
+	aclEntrySync := aclentry.NewSyncCommand(aclEntryCmdRoot.CmdClause, data)
 	aclEntryUpdate := aclentry.NewUpdateCommand(aclEntryCmdRoot.CmdClause, data)
+	aliasCmdRoot := alias.NewRootCommand(app, data)
+	aliasList := alias.NewListCommand(aliasCmdRoot.CmdClause, data)
 	authtokenCmdRoot := authtoken.NewRootCommand(app, data)
 	authtokenCreate := authtoken.NewCreateCommand(authtokenCmdRoot.CmdClause, data)
 	authtokenDelete := authtoken.NewDeleteCommand(authtokenCmdRoot.CmdClause, data)
 	authtokenDescribe := authtoken.NewDescribeCommand(authtokenCmdRoot.CmdClause, data)
 	authtokenList := authtoken.NewListCommand(authtokenCmdRoot.CmdClause, data)
+	authtokenRotate := authtoken.NewRotateCommand(authtokenCmdRoot.CmdClause, data)
 	backendCmdRoot := backend.NewRootCommand(app, data)
 	backendCreate := backend.NewCreateCommand(backendCmdRoot.CmdClause, data)
 	backendDelete := backend.NewDeleteCommand(backendCmdRoot.CmdClause, data)
 	backendDescribe := backend.NewDescribeCommand(backendCmdRoot.CmdClause, data)
 	backendList := backend.NewListCommand(backendCmdRoot.CmdClause, data)
+	backendProbe := backend.NewProbeCommand(backendCmdRoot.CmdClause, data)
 	backendUpdate := backend.NewUpdateCommand(backendCmdRoot.CmdClause, data)
+	completionCmdRoot := completion.NewRootCommand(app, data)
+	completionBash := completion.NewScriptCommand(completionCmdRoot.CmdClause, data, app, completion.Bash)
+	completionFish := completion.NewScriptCommand(completionCmdRoot.CmdClause, data, app, completion.Fish)
+	completionValues := completion.NewValuesCommand(completionCmdRoot.CmdClause, data)
+	completionZsh := completion.NewScriptCommand(completionCmdRoot.CmdClause, data, app, completion.Zsh)
 	computeCmdRoot := compute.NewRootCommand(app, data)
 	computeBuild := compute.NewBuildCommand(computeCmdRoot.CmdClause, data)
+	computeCacheCmdRoot := cache.NewRootCommand(computeCmdRoot.CmdClause, data)
+	computeCachePrune := cache.NewPruneCommand(computeCacheCmdRoot.CmdClause, data)
+	computeCacheSize := cache.NewSizeCommand(computeCacheCmdRoot.CmdClause, data)
 	computeDeploy := compute.NewDeployCommand(computeCmdRoot.CmdClause, data)
+	computeDrift := compute.NewDriftCommand(computeCmdRoot.CmdClause, data)
 	computeHashFiles := compute.NewHashFilesCommand(computeCmdRoot.CmdClause, data, computeBuild)
 	computeHashsum := compute.NewHashsumCommand(computeCmdRoot.CmdClause, data, computeBuild)
+	computeImportSetup := compute.NewImportSetupCommand(computeCmdRoot.CmdClause, data)
 	computeInit := compute.NewInitCommand(computeCmdRoot.CmdClause, data)
+	computeInspect := compute.NewInspectCommand(computeCmdRoot.CmdClause, data)
 	computeMetadata := compute.NewMetadataCommand(computeCmdRoot.CmdClause, data)
 	computePack := compute.NewPackCommand(computeCmdRoot.CmdClause, data)
 	computePublish := compute.NewPublishCommand(computeCmdRoot.CmdClause, data, computeBuild, computeDeploy)
 	computeServe := compute.NewServeCommand(computeCmdRoot.CmdClause, data, computeBuild)
+	computeStatus := compute.NewStatusCommand(computeCmdRoot.CmdClause, data)
 	computeUpdate := compute.NewUpdateCommand(computeCmdRoot.CmdClause, data)
 	computeValidate := compute.NewValidateCommand(computeCmdRoot.CmdClause, data)
+	computeViceroyCmdRoot := viceroy.NewRootCommand(computeCmdRoot.CmdClause, data)
+	computeViceroyInstall := viceroy.NewInstallCommand(computeViceroyCmdRoot.CmdClause, data)
+	computeViceroyList := viceroy.NewListCommand(computeViceroyCmdRoot.CmdClause, data)
+	computeViceroyPin := viceroy.NewPinCommand(computeViceroyCmdRoot.CmdClause, data)
 	configCmdRoot := config.NewRootCommand(app, data)
+	configCheck := config.NewCheckCommand(configCmdRoot.CmdClause, data, app)
 	configstoreCmdRoot := configstore.NewRootCommand(app, data)
 	configstoreCreate := configstore.NewCreateCommand(configstoreCmdRoot.CmdClause, data)
 	configstoreDelete := configstore.NewDeleteCommand(configstoreCmdRoot.CmdClause, data)
@@ -144,7 +181,9 @@ func Define(
 	configstoreentryDelete := configstoreentry.NewDeleteCommand(configstoreentryCmdRoot.CmdClause, data)
 	configstoreentryDescribe := configstoreentry.NewDescribeCommand(configstoreentryCmdRoot.CmdClause, data)
 	configstoreentryList := configstoreentry.NewListCommand(configstoreentryCmdRoot.CmdClause, data)
+	configstoreentrySync := configstoreentry.NewSyncCommand(configstoreentryCmdRoot.CmdClause, data)
 	configstoreentryUpdate := configstoreentry.NewUpdateCommand(configstoreentryCmdRoot.CmdClause, data)
+	deprecationsCmdRoot := deprecations.NewRootCommand(app, data)
 	dictionaryCmdRoot := dictionary.NewRootCommand(app, data)
 	dictionaryCreate := dictionary.NewCreateCommand(dictionaryCmdRoot.CmdClause, data)
 	dictionaryDelete := dictionary.NewDeleteCommand(dictionaryCmdRoot.CmdClause, data)
@@ -154,28 +193,36 @@ func Define(
 	dictionaryEntryDelete := dictionaryentry.NewDeleteCommand(dictionaryEntryCmdRoot.CmdClause, data)
 	dictionaryEntryDescribe := dictionaryentry.NewDescribeCommand(dictionaryEntryCmdRoot.CmdClause, data)
 	dictionaryEntryList := dictionaryentry.NewListCommand(dictionaryEntryCmdRoot.CmdClause, data)
+	dictionaryEntrySync := dictionaryentry.NewSyncCommand(dictionaryEntryCmdRoot.CmdClause, data)
 	dictionaryEntryUpdate := dictionaryentry.NewUpdateCommand(dictionaryEntryCmdRoot.CmdClause, data)
 	dictionaryList := dictionary.NewListCommand(dictionaryCmdRoot.CmdClause, data)
 	dictionaryUpdate := dictionary.NewUpdateCommand(dictionaryCmdRoot.CmdClause, data)
 	domainCmdRoot := domain.NewRootCommand(app, data)
+	domainCheckDNS := domain.NewCheckDNSCommand(domainCmdRoot.CmdClause, data)
 	domainCreate := domain.NewCreateCommand(domainCmdRoot.CmdClause, data)
 	domainDelete := domain.NewDeleteCommand(domainCmdRoot.CmdClause, data)
 	domainDescribe := domain.NewDescribeCommand(domainCmdRoot.CmdClause, data)
 	domainList := domain.NewListCommand(domainCmdRoot.CmdClause, data)
 	domainUpdate := domain.NewUpdateCommand(domainCmdRoot.CmdClause, data)
 	domainValidate := domain.NewValidateCommand(domainCmdRoot.CmdClause, data)
+	eventsCmdRoot := events.NewRootCommand(app, data)
+	eventsList := events.NewListCommand(eventsCmdRoot.CmdClause, data)
+	explainCmdRoot := explain.NewRootCommand(app, data)
 	healthcheckCmdRoot := healthcheck.NewRootCommand(app, data)
 	healthcheckCreate := healthcheck.NewCreateCommand(healthcheckCmdRoot.CmdClause, data)
 	healthcheckDelete := healthcheck.NewDeleteCommand(healthcheckCmdRoot.CmdClause, data)
 	healthcheckDescribe := healthcheck.NewDescribeCommand(healthcheckCmdRoot.CmdClause, data)
 	healthcheckList := healthcheck.NewListCommand(healthcheckCmdRoot.CmdClause, data)
 	healthcheckUpdate := healthcheck.NewUpdateCommand(healthcheckCmdRoot.CmdClause, data)
+	historyCmdRoot := history.NewRootCommand(app, data)
 	installRoot := install.NewRootCommand(app, data)
 	ipCmdRoot := ip.NewRootCommand(app, data)
 	kvstoreCmdRoot := kvstore.NewRootCommand(app, data)
 	kvstoreCreate := kvstore.NewCreateCommand(kvstoreCmdRoot.CmdClause, data)
 	kvstoreDelete := kvstore.NewDeleteCommand(kvstoreCmdRoot.CmdClause, data)
 	kvstoreDescribe := kvstore.NewDescribeCommand(kvstoreCmdRoot.CmdClause, data)
+	kvstoreExport := kvstore.NewExportCommand(kvstoreCmdRoot.CmdClause, data)
+	kvstoreImport := kvstore.NewImportCommand(kvstoreCmdRoot.CmdClause, data)
 	kvstoreList := kvstore.NewListCommand(kvstoreCmdRoot.CmdClause, data)
 	kvstoreentryCmdRoot := kvstoreentry.NewRootCommand(app, data)
 	kvstoreentryCreate := kvstoreentry.NewCreateCommand(kvstoreentryCmdRoot.CmdClause, data)
@@ -340,6 +387,8 @@ func Define(
 	loggingSyslogDescribe := syslog.NewDescribeCommand(loggingSyslogCmdRoot.CmdClause, data)
 	loggingSyslogList := syslog.NewListCommand(loggingSyslogCmdRoot.CmdClause, data)
 	loggingSyslogUpdate := syslog.NewUpdateCommand(loggingSyslogCmdRoot.CmdClause, data)
+	planCmdRoot := plan.NewRootCommand(app, data)
+	planRun := plan.NewRunCommand(planCmdRoot.CmdClause, data)
 	popCmdRoot := pop.NewRootCommand(app, data)
 	productsCmdRoot := products.NewRootCommand(app, data)
 	profileCmdRoot := profile.NewRootCommand(app, data)
@@ -371,13 +420,24 @@ func Define(
 	secretstoreentryCreate := secretstoreentry.NewCreateCommand(secretstoreentryCmdRoot.CmdClause, data)
 	secretstoreentryDescribe := secretstoreentry.NewDescribeCommand(secretstoreentryCmdRoot.CmdClause, data)
 	secretstoreentryDelete := secretstoreentry.NewDeleteCommand(secretstoreentryCmdRoot.CmdClause, data)
+	secretstoreentryImport := secretstoreentry.NewImportCommand(secretstoreentryCmdRoot.CmdClause, data)
 	secretstoreentryList := secretstoreentry.NewListCommand(secretstoreentryCmdRoot.CmdClause, data)
 	serviceCmdRoot := service.NewRootCommand(app, data)
+	serviceAliasCmdRoot := servicealias.NewRootCommand(serviceCmdRoot.CmdClause, data)
+	serviceAliasAdd := servicealias.NewAddCommand(serviceAliasCmdRoot.CmdClause, data)
+	serviceAliasList := servicealias.NewListCommand(serviceAliasCmdRoot.CmdClause, data)
+	serviceAliasRemove := servicealias.NewRemoveCommand(serviceAliasCmdRoot.CmdClause, data)
+	serviceClone := service.NewCloneCommand(serviceCmdRoot.CmdClause, data)
 	serviceCreate := service.NewCreateCommand(serviceCmdRoot.CmdClause, data)
 	serviceDelete := service.NewDeleteCommand(serviceCmdRoot.CmdClause, data)
 	serviceDescribe := service.NewDescribeCommand(serviceCmdRoot.CmdClause, data)
 	serviceList := service.NewListCommand(serviceCmdRoot.CmdClause, data)
+	serviceResources := service.NewResourcesCommand(serviceCmdRoot.CmdClause, data)
 	serviceSearch := service.NewSearchCommand(serviceCmdRoot.CmdClause, data)
+	serviceStatus := service.NewStatusCommand(serviceCmdRoot.CmdClause, data)
+	serviceTagCmdRoot := servicetag.NewRootCommand(serviceCmdRoot.CmdClause, data)
+	serviceTagRemove := servicetag.NewRemoveCommand(serviceTagCmdRoot.CmdClause, data)
+	serviceTagSet := servicetag.NewSetCommand(serviceTagCmdRoot.CmdClause, data)
 	serviceUpdate := service.NewUpdateCommand(serviceCmdRoot.CmdClause, data)
 	serviceauthCmdRoot := serviceauth.NewRootCommand(app, data)
 	serviceauthCreate := serviceauth.NewCreateCommand(serviceauthCmdRoot.CmdClause, data)
@@ -389,6 +449,7 @@ func Define(
 	serviceVersionActivate := serviceversion.NewActivateCommand(serviceVersionCmdRoot.CmdClause, data)
 	serviceVersionClone := serviceversion.NewCloneCommand(serviceVersionCmdRoot.CmdClause, data)
 	serviceVersionDeactivate := serviceversion.NewDeactivateCommand(serviceVersionCmdRoot.CmdClause, data)
+	serviceVersionFind := serviceversion.NewFindCommand(serviceVersionCmdRoot.CmdClause, data)
 	serviceVersionList := serviceversion.NewListCommand(serviceVersionCmdRoot.CmdClause, data)
 	serviceVersionLock := serviceversion.NewLockCommand(serviceVersionCmdRoot.CmdClause, data)
 	serviceVersionUpdate := serviceversion.NewUpdateCommand(serviceVersionCmdRoot.CmdClause, data)
@@ -396,6 +457,8 @@ func Define(
 	statsHistorical := stats.NewHistoricalCommand(statsCmdRoot.CmdClause, data)
 	statsRealtime := stats.NewRealtimeCommand(statsCmdRoot.CmdClause, data)
 	statsRegions := stats.NewRegionsCommand(statsCmdRoot.CmdClause, data)
+	statsWatch := stats.NewWatchCommand(statsCmdRoot.CmdClause, data)
+	supportBundleCmdRoot := supportbundle.NewRootCommand(app, data)
 	tlsConfigCmdRoot := tlsconfig.NewRootCommand(app, data)
 	tlsConfigDescribe := tlsconfig.NewDescribeCommand(tlsConfigCmdRoot.CmdClause, data)
 	tlsConfigList := tlsconfig.NewListCommand(tlsConfigCmdRoot.CmdClause, data)
@@ -420,6 +483,7 @@ func Define(
 	tlsCustomPrivateKeyDelete := tlscustomprivatekey.NewDeleteCommand(tlsCustomPrivateKeyCmdRoot.CmdClause, data)
 	tlsCustomPrivateKeyDescribe := tlscustomprivatekey.NewDescribeCommand(tlsCustomPrivateKeyCmdRoot.CmdClause, data)
 	tlsCustomPrivateKeyList := tlscustomprivatekey.NewListCommand(tlsCustomPrivateKeyCmdRoot.CmdClause, data)
+	tlsExpiryCmdRoot := tlsexpiry.NewRootCommand(app, data)
 	tlsPlatformCmdRoot := tlsplatform.NewRootCommand(app, data)
 	tlsPlatformCreate := tlsplatform.NewCreateCommand(tlsPlatformCmdRoot.CmdClause, data)
 	tlsPlatformDelete := tlsplatform.NewDeleteCommand(tlsPlatformCmdRoot.CmdClause, data)
@@ -433,6 +497,7 @@ func Define(
 	tlsSubscriptionList := tlssubscription.NewListCommand(tlsSubscriptionCmdRoot.CmdClause, data)
 	tlsSubscriptionUpdate := tlssubscription.NewUpdateCommand(tlsSubscriptionCmdRoot.CmdClause, data)
 	updateRoot := update.NewRootCommand(app, data)
+	usageCmdRoot := usage.NewRootCommand(app, data)
 	userCmdRoot := user.NewRootCommand(app, data)
 	userCreate := user.NewCreateCommand(userCmdRoot.CmdClause, data)
 	userDelete := user.NewDeleteCommand(userCmdRoot.CmdClause, data)
@@ -457,6 +522,7 @@ func Define(
 	vclSnippetDelete := snippet.NewDeleteCommand(vclSnippetCmdRoot.CmdClause, data)
 	vclSnippetDescribe := snippet.NewDescribeCommand(vclSnippetCmdRoot.CmdClause, data)
 	vclSnippetList := snippet.NewListCommand(vclSnippetCmdRoot.CmdClause, data)
+	vclSnippetSync := snippet.NewSyncCommand(vclSnippetCmdRoot.CmdClause, data)
 	vclSnippetUpdate := snippet.NewUpdateCommand(vclSnippetCmdRoot.CmdClause, data)
 	versionCmdRoot := version.NewRootCommand(app, data)
 	whoamiCmdRoot := whoami.NewRootCommand(app, data)
@@ -473,32 +539,54 @@ func Define(
 		aclEntryCreate,
 		aclEntryDelete,
 		aclEntryDescribe,
+		aclEntryImport,
 		aclEntryList,
+		aclEntrySync,
 		aclEntryUpdate,
+		aliasCmdRoot,
+		aliasList,
 		authtokenCmdRoot,
 		authtokenCreate,
 		authtokenDelete,
 		authtokenDescribe,
 		authtokenList,
+		authtokenRotate,
 		backendCmdRoot,
 		backendCreate,
 		backendDelete,
 		backendDescribe,
 		backendList,
+		backendProbe,
 		backendUpdate,
-		computeBuild,
+		completionCmdRoot,
+		completionBash,
+		completionFish,
+		completionValues,
+		completionZsh,
 		computeCmdRoot,
+		computeBuild,
+		computeCacheCmdRoot,
+		computeCachePrune,
+		computeCacheSize,
 		computeDeploy,
+		computeDrift,
 		computeHashFiles,
 		computeHashsum,
+		computeImportSetup,
 		computeInit,
+		computeInspect,
 		computeMetadata,
 		computePack,
 		computePublish,
 		computeServe,
+		computeStatus,
 		computeUpdate,
 		computeValidate,
+		computeViceroyInstall,
+		computeViceroyList,
+		computeViceroyPin,
 		configCmdRoot,
+		configCheck,
 		configstoreCmdRoot,
 		configstoreCreate,
 		configstoreDelete,
@@ -511,7 +599,9 @@ func Define(
 		configstoreentryDelete,
 		configstoreentryDescribe,
 		configstoreentryList,
+		configstoreentrySync,
 		configstoreentryUpdate,
+		deprecationsCmdRoot,
 		dictionaryCmdRoot,
 		dictionaryCreate,
 		dictionaryDelete,
@@ -521,27 +611,35 @@ func Define(
 		dictionaryEntryDelete,
 		dictionaryEntryDescribe,
 		dictionaryEntryList,
+		dictionaryEntrySync,
 		dictionaryEntryUpdate,
 		dictionaryList,
 		dictionaryUpdate,
 		domainCmdRoot,
+		domainCheckDNS,
 		domainCreate,
 		domainDelete,
 		domainDescribe,
 		domainList,
 		domainUpdate,
 		domainValidate,
+		eventsCmdRoot,
+		eventsList,
+		explainCmdRoot,
 		healthcheckCmdRoot,
 		healthcheckCreate,
 		healthcheckDelete,
 		healthcheckDescribe,
 		healthcheckList,
 		healthcheckUpdate,
+		historyCmdRoot,
 		installRoot,
 		ipCmdRoot,
 		kvstoreCreate,
 		kvstoreDelete,
 		kvstoreDescribe,
+		kvstoreExport,
+		kvstoreImport,
 		kvstoreList,
 		kvstoreentryCreate,
 		kvstoreentryDelete,
@@ -705,6 +803,8 @@ func Define(
 		loggingSyslogDescribe,
 		loggingSyslogList,
 		loggingSyslogUpdate,
+		planCmdRoot,
+		planRun,
 		popCmdRoot,
 		productsCmdRoot,
 		profileCmdRoot,
@@ -734,13 +834,24 @@ func Define(
 		secretstoreentryCreate,
 		secretstoreentryDescribe,
 		secretstoreentryDelete,
+		secretstoreentryImport,
 		secretstoreentryList,
 		serviceCmdRoot,
+		serviceAliasCmdRoot,
+		serviceAliasAdd,
+		serviceAliasList,
+		serviceAliasRemove,
+		serviceClone,
 		serviceCreate,
 		serviceDelete,
 		serviceDescribe,
 		serviceList,
+		serviceResources,
 		serviceSearch,
+		serviceStatus,
+		serviceTagCmdRoot,
+		serviceTagRemove,
+		serviceTagSet,
 		serviceUpdate,
 		serviceauthCmdRoot,
 		serviceauthCreate,
@@ -752,6 +863,7 @@ func Define(
 		serviceVersionClone,
 		serviceVersionCmdRoot,
 		serviceVersionDeactivate,
+		serviceVersionFind,
 		serviceVersionList,
 		serviceVersionLock,
 		serviceVersionUpdate,
@@ -760,6 +872,8 @@ func Define(
 		statsHistorical,
 		statsRealtime,
 		statsRegions,
+		statsWatch,
+		supportBundleCmdRoot,
 		tlsConfigCmdRoot,
 		tlsConfigDescribe,
 		tlsConfigList,
@@ -784,6 +898,7 @@ func Define(
 		tlsCustomPrivateKeyDelete,
 		tlsCustomPrivateKeyDescribe,
 		tlsCustomPrivateKeyList,
+		tlsExpiryCmdRoot,
 		tlsPlatformCmdRoot,
 		tlsPlatformCreate,
 		tlsPlatformDelete,
@@ -797,6 +912,7 @@ func Define(
 		tlsSubscriptionList,
 		tlsSubscriptionUpdate,
 		updateRoot,
+		usageCmdRoot,
 		userCmdRoot,
 		userCreate,
 		userDelete,
@@ -821,6 +937,7 @@ func Define(
 		vclSnippetDelete,
 		vclSnippetDescribe,
 		vclSnippetList,
+		vclSnippetSync,
 		vclSnippetUpdate,
 		versionCmdRoot,
 		whoamiCmdRoot,
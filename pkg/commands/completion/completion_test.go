@@ -0,0 +1,143 @@
+package completion_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+	"github.com/fastly/kingpin"
+	toml "github.com/pelletier/go-toml"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/completion"
+	"github.com/fastly/cli/pkg/config"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+// fixtureApp is a small command tree covering each kind of flag.
+func fixtureApp() *kingpin.Application {
+	a := kingpin.New("fastly", "A tool to interact with the Fastly API")
+	a.Flag("profile", "Switch account profile").Short('o').String()
+	a.Flag("verbose", "Verbose logging").Short('v').Bool()
+	a.Flag("api", "Fastly API endpoint").Hidden().String()
+
+	service := a.Command("service", "Manipulate Fastly services")
+	describe := service.Command("describe", "Show detailed information about a Fastly service")
+	describe.Flag("service-id", "Service ID").Short('s').String()
+	describe.Flag("service-name", "The name of the service").String()
+	describe.Flag("json", "Render output as JSON").Short('j').Bool()
+	service.Command("list", "List Fastly services")
+
+	version := a.Command("service-version", "Manipulate Fastly service versions")
+	activate := version.Command("activate", "Activate a Fastly service version")
+	activate.Flag("comment", "Human-readable comment, e.g. 'it's live'").String()
+	activate.Flag("service-id", "Service ID").Short('s').String()
+
+	a.Command("secret", "Hidden command").Hidden()
+	return a
+}
+
+func TestGenerateGolden(t *testing.T) {
+	for _, shell := range []string{completion.Bash, completion.Fish, completion.Zsh} {
+		t.Run(shell, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := completion.Generate(&buf, shell, fixtureApp().Model()); err != nil {
+				t.Fatal(err)
+			}
+
+			testutil.AssertGolden(t, filepath.Join("testdata", "fixture."+shell), buf.String())
+		})
+	}
+}
+
+func TestGenerateUnsupportedShell(t *testing.T) {
+	err := completion.Generate(io.Discard, "powershell", fixtureApp().Model())
+	testutil.AssertErrorContains(t, err, "unsupported shell: powershell")
+}
+
+func TestCompletionCommandIsDeterministic(t *testing.T) {
+	run := func() string {
+		args := testutil.Args("completion fish")
+		var stdout bytes.Buffer
+		app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+			return testutil.MockGlobalData(args, &stdout), nil
+		}
+		if err := app.Run(args, nil); err != nil {
+			t.Fatal(err)
+		}
+		return stdout.String()
+	}
+
+	first := run()
+	testutil.AssertStringContains(t, first, "complete -c fastly -n '__fastly_using_path \"service\"' -a describe")
+	testutil.AssertStringContains(t, first, "-l service-id -s s -x -a '(fastly completion values service-id 2>/dev/null)'")
+	testutil.AssertString(t, first, run())
+}
+
+func readFixtureConfig(t *testing.T) config.File {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "config.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg config.File
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestValues(t *testing.T) {
+	cfg := readFixtureConfig(t)
+
+	testutil.AssertEqual(t, []string{"admin", "user"}, completion.Values(completion.KindProfile, cfg))
+	testutil.AssertEqual(t, []string{"456def", "123abc"}, completion.Values(completion.KindServiceID, cfg))
+	testutil.AssertEqual(t, []string{"production", "staging"}, completion.Values(completion.KindServiceName, cfg))
+	// The 'production' alias shadows the service name, so it's only listed once.
+	testutil.AssertEqual(t, []string{"prod-api", "production", "staging"}, completion.Values(completion.KindService, cfg))
+	testutil.AssertEqual(t, []string(nil), completion.Values("unknown", cfg))
+}
+
+func TestValuesCommand(t *testing.T) {
+	cfg := readFixtureConfig(t)
+	args := testutil.Args("completion values service-name")
+
+	var stdout bytes.Buffer
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		opts := testutil.MockGlobalData(args, &stdout)
+		opts.Config = cfg
+		// Completion must never call the API.
+		opts.APIClientFactory = mock.APIClient(mock.API{})
+		return opts, nil
+	}
+	err := app.Run(args, nil)
+	testutil.AssertNoError(t, err)
+	testutil.AssertString(t, "production\nstaging\n", stdout.String())
+}
+
+func TestRecentServicesRecorded(t *testing.T) {
+	args := testutil.Args("service describe --service-id 789ghi")
+
+	var (
+		opts   *global.Data
+		stdout bytes.Buffer
+	)
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		opts = testutil.MockGlobalData(args, &stdout)
+		opts.Config = readFixtureConfig(t)
+		opts.APIClientFactory = mock.APIClient(mock.API{
+			GetServiceDetailsFn: func(_ *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+				return &fastly.ServiceDetail{ServiceID: fastly.ToPointer("789ghi")}, nil
+			},
+		})
+		return opts, nil
+	}
+	err := app.Run(args, nil)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, []string{"789ghi", "456def", "123abc"}, completion.Values(completion.KindServiceID, opts.Config))
+}
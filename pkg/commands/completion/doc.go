@@ -0,0 +1,2 @@
+// Package completion contains commands to generate shell completion scripts.
+package completion
@@ -0,0 +1,334 @@
+package completion
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/fastly/kingpin"
+)
+
+// dynamicFlags maps the flags whose values are completed at runtime (see
+// ValuesCommand) to the kind of value.
+var dynamicFlags = map[string]string{
+	"profile":      KindProfile,
+	"service":      KindService,
+	"service-id":   KindServiceID,
+	"service-name": KindServiceName,
+}
+
+// command is a visible command with its visible flags.
+type command struct {
+	flags       []flag
+	help        string
+	name        string
+	path        string
+	subcommands []*command
+}
+
+type flag struct {
+	boolean bool
+	dynamic string
+	help    string
+	name    string
+	short   rune
+}
+
+// Generate writes the completion script for the shell describing the commands
+// and flags in the application model.
+//
+// Commands and flags are sorted so the script only changes when the commands
+// and flags do, which allows it to be checked into version control.
+func Generate(w io.Writer, shell string, app *kingpin.ApplicationModel) error {
+	root := &command{
+		flags:       flags(app.FlagGroupModel),
+		name:        app.Name,
+		subcommands: commands(app.CmdGroupModel, ""),
+	}
+
+	bw := bufio.NewWriter(w)
+	switch shell {
+	case Bash:
+		writeBash(bw, root)
+	case Zsh:
+		writeZsh(bw, root)
+	case Fish:
+		writeFish(bw, root)
+	default:
+		return fmt.Errorf("unsupported shell: %s", shell)
+	}
+	return bw.Flush()
+}
+
+func commands(m *kingpin.CmdGroupModel, parent string) []*command {
+	var cmds []*command
+	for _, cm := range m.Commands {
+		if cm.Hidden {
+			continue
+		}
+		path := cm.Name
+		if parent != "" {
+			path = parent + " " + cm.Name
+		}
+		cmds = append(cmds, &command{
+			flags:       flags(cm.FlagGroupModel),
+			help:        cm.Help,
+			name:        cm.Name,
+			path:        path,
+			subcommands: commands(cm.CmdGroupModel, path),
+		})
+	}
+	sort.Slice(cmds, func(i, j int) bool {
+		return cmds[i].name < cmds[j].name
+	})
+	return cmds
+}
+
+func flags(m *kingpin.FlagGroupModel) []flag {
+	var fs []flag
+	for _, fm := range m.Flags {
+		if fm.Hidden {
+			continue
+		}
+		fs = append(fs, flag{
+			boolean: fm.IsBoolFlag(),
+			dynamic: dynamicFlags[fm.Name],
+			help:    fm.Help,
+			name:    fm.Name,
+			short:   fm.Short,
+		})
+		// NOTE: The --service flag is accepted by every command with a
+		// --service-name flag, but it's expanded before the arguments are
+		// parsed so it isn't part of the model (see the service alias package).
+		if fm.Name == "service-name" {
+			fs = append(fs, flag{
+				dynamic: KindService,
+				help:    "Service alias or name",
+				name:    "service",
+			})
+		}
+	}
+	sort.Slice(fs, func(i, j int) bool {
+		return fs[i].name < fs[j].name
+	})
+	return fs
+}
+
+// walk calls fn for the command and all its descendants, depth first.
+func walk(c *command, fn func(*command)) {
+	fn(c)
+	for _, sc := range c.subcommands {
+		walk(sc, fn)
+	}
+}
+
+// flagWords returns the long (and short) flag names, e.g. --verbose -v.
+func flagWords(fs []flag) string {
+	words := make([]string, 0, len(fs))
+	for _, f := range fs {
+		words = append(words, "--"+f.name)
+		if f.short != 0 {
+			words = append(words, "-"+string(f.short))
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// dynamicFlagPatterns returns the case patterns for each dynamic value kind,
+// e.g. "--profile|-o". Short names are only included for global flags as they
+// may mean something else for individual commands.
+func dynamicFlagPatterns(root *command) map[string]string {
+	patterns := map[string][]string{}
+	add := func(kind, word string) {
+		for _, p := range patterns[kind] {
+			if p == word {
+				return
+			}
+		}
+		patterns[kind] = append(patterns[kind], word)
+	}
+	for _, f := range root.flags {
+		if f.dynamic != "" && f.short != 0 {
+			add(f.dynamic, "-"+string(f.short))
+		}
+	}
+	walk(root, func(c *command) {
+		for _, f := range c.flags {
+			if f.dynamic != "" {
+				add(f.dynamic, "--"+f.name)
+			}
+		}
+	})
+	joined := map[string]string{}
+	for kind, words := range patterns {
+		sort.Strings(words)
+		joined[kind] = strings.Join(words, "|")
+	}
+	return joined
+}
+
+func writeBashFunctions(w io.Writer, root *command) {
+	fmt.Fprintf(w, "_%s_subcommands() {\n", root.name)
+	fmt.Fprint(w, "    case \"$1\" in\n")
+	walk(root, func(c *command) {
+		if len(c.subcommands) == 0 {
+			return
+		}
+		names := make([]string, 0, len(c.subcommands))
+		for _, sc := range c.subcommands {
+			names = append(names, sc.name)
+		}
+		fmt.Fprintf(w, "        '%s') echo '%s' ;;\n", c.path, strings.Join(names, " "))
+	})
+	fmt.Fprint(w, "    esac\n}\n\n")
+
+	fmt.Fprintf(w, "_%s_flags() {\n", root.name)
+	fmt.Fprint(w, "    case \"$1\" in\n")
+	walk(root, func(c *command) {
+		if len(c.flags) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "        '%s') echo '%s' ;;\n", c.path, flagWords(c.flags))
+	})
+	fmt.Fprint(w, "    esac\n}\n\n")
+
+	patterns := dynamicFlagPatterns(root)
+	fmt.Fprintf(w, "_%s_values() {\n", root.name)
+	fmt.Fprint(w, "    case \"$1\" in\n")
+	for _, kind := range kinds {
+		if p, ok := patterns[kind]; ok {
+			fmt.Fprintf(w, "        %s) %s completion values %s 2>/dev/null ;;\n", p, root.name, kind)
+		}
+	}
+	fmt.Fprint(w, "        *) return 1 ;;\n")
+	fmt.Fprint(w, "    esac\n}\n\n")
+
+	fmt.Fprintf(w, `_%[1]s() {
+    local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"
+    local path="" word values words i
+    for ((i = 1; i < COMP_CWORD; i++)); do
+        word="${COMP_WORDS[i]}"
+        [[ "$word" == -* ]] && continue
+        if [[ " $(_%[1]s_subcommands "$path") " == *" $word "* ]]; then
+            path="${path:+$path }$word"
+        fi
+    done
+    if values="$(_%[1]s_values "$prev")"; then
+        COMPREPLY=($(compgen -W "$values" -- "$cur"))
+        return 0
+    fi
+    if [[ "$cur" == -* ]]; then
+        words="$(_%[1]s_flags "$path")"
+        [[ -n "$path" ]] && words="$words $(_%[1]s_flags '')"
+    else
+        words="$(_%[1]s_subcommands "$path")"
+    fi
+    COMPREPLY=($(compgen -W "$words" -- "$cur"))
+}
+`, root.name)
+}
+
+func writeBash(w io.Writer, root *command) {
+	fmt.Fprintf(w, `# bash completion for %[1]s
+#
+# Generated by '%[1]s completion bash'. To enable, add the following to ~/.bashrc:
+#
+#   source <(%[1]s completion bash)
+
+`, root.name)
+	writeBashFunctions(w, root)
+	fmt.Fprintf(w, "\ncomplete -F _%[1]s %[1]s\n", root.name)
+}
+
+func writeZsh(w io.Writer, root *command) {
+	fmt.Fprintf(w, `#compdef %[1]s
+#
+# zsh completion for %[1]s
+#
+# Generated by '%[1]s completion zsh'. To enable, add the following to ~/.zshrc:
+#
+#   source <(%[1]s completion zsh)
+
+autoload -U +X compinit && compinit
+autoload -U +X bashcompinit && bashcompinit
+
+`, root.name)
+	writeBashFunctions(w, root)
+	fmt.Fprintf(w, "\ncomplete -F _%[1]s %[1]s\n", root.name)
+}
+
+func writeFish(w io.Writer, root *command) {
+	fmt.Fprintf(w, `# fish completion for %[1]s
+#
+# Generated by '%[1]s completion fish'. To enable, run:
+#
+#   %[1]s completion fish > ~/.config/fish/completions/%[1]s.fish
+
+function __%[1]s_subcommands
+    switch "$argv[1]"
+`, root.name)
+	walk(root, func(c *command) {
+		if len(c.subcommands) == 0 {
+			return
+		}
+		names := make([]string, 0, len(c.subcommands))
+		for _, sc := range c.subcommands {
+			names = append(names, sc.name)
+		}
+		fmt.Fprintf(w, "        case '%s'\n            printf '%%s\\n' %s\n", c.path, strings.Join(names, " "))
+	})
+	fmt.Fprintf(w, `    end
+end
+
+function __%[1]s_path
+    set -l path ''
+    for word in (commandline -opc)[2..-1]
+        string match -q -- '-*' $word; and continue
+        if contains -- $word (__%[1]s_subcommands $path)
+            set path (string trim -- "$path $word")
+        end
+    end
+    echo $path
+end
+
+function __%[1]s_using_path
+    test (__%[1]s_path) = "$argv[1]"
+end
+
+complete -c %[1]s -f
+`, root.name)
+
+	walk(root, func(c *command) {
+		condition := ""
+		if c != root {
+			condition = fmt.Sprintf(" -n '__%s_using_path \"%s\"'", root.name, c.path)
+		}
+		fmt.Fprintln(w)
+		for _, sc := range c.subcommands {
+			fmt.Fprintf(w, "complete -c %s -n '__%s_using_path \"%s\"' -a %s -d '%s'\n", root.name, root.name, c.path, sc.name, fishEscape(sc.help))
+		}
+		for _, f := range c.flags {
+			fmt.Fprintf(w, "complete -c %s%s -l %s", root.name, condition, f.name)
+			if f.short != 0 {
+				fmt.Fprintf(w, " -s %s", string(f.short))
+			}
+			switch {
+			case f.dynamic != "":
+				fmt.Fprintf(w, " -x -a '(%s completion values %s 2>/dev/null)'", root.name, f.dynamic)
+			case !f.boolean:
+				fmt.Fprint(w, " -r")
+			}
+			fmt.Fprintf(w, " -d '%s'\n", fishEscape(f.help))
+		}
+	})
+}
+
+// fishEscape returns the first line of s escaped for a single quoted fish
+// string.
+func fishEscape(s string) string {
+	s, _, _ = strings.Cut(s, "\n")
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, "'", `\'`)
+}
@@ -0,0 +1,50 @@
+package completion
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fastly/kingpin"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/global"
+)
+
+// Supported shells.
+const (
+	Bash = "bash"
+	Fish = "fish"
+	Zsh  = "zsh"
+)
+
+var setup = map[string]string{
+	Bash: "Add `source <(fastly completion bash)` to ~/.bashrc",
+	Fish: "Run `fastly completion fish > ~/.config/fish/completions/fastly.fish`",
+	Zsh:  "Add `source <(fastly completion zsh)` to ~/.zshrc",
+}
+
+// ScriptCommand writes the completion script for a shell.
+type ScriptCommand struct {
+	argparser.Base
+
+	app   *kingpin.Application
+	shell string
+}
+
+// NewScriptCommand returns a usable command registered under the parent.
+//
+// The application is used to describe every command and flag registered by
+// the time the command is executed.
+func NewScriptCommand(parent argparser.Registerer, g *global.Data, app *kingpin.Application, shell string) *ScriptCommand {
+	var c ScriptCommand
+	c.Globals = g
+	c.app = app
+	c.shell = shell
+	c.CmdClause = parent.Command(shell, fmt.Sprintf("Generate the %s completion script. %s", shell, setup[shell]))
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *ScriptCommand) Exec(_ io.Reader, out io.Writer) error {
+	return Generate(out, c.shell, c.app.Model())
+}
@@ -0,0 +1,56 @@
+# bash completion for fastly
+#
+# Generated by 'fastly completion bash'. To enable, add the following to ~/.bashrc:
+#
+#   source <(fastly completion bash)
+
+_fastly_subcommands() {
+    case "$1" in
+        '') echo 'service service-version' ;;
+        'service') echo 'describe list' ;;
+        'service-version') echo 'activate' ;;
+    esac
+}
+
+_fastly_flags() {
+    case "$1" in
+        '') echo '--help --profile -o --verbose -v' ;;
+        'service describe') echo '--json -j --service --service-id -s --service-name' ;;
+        'service-version activate') echo '--comment --service-id -s' ;;
+    esac
+}
+
+_fastly_values() {
+    case "$1" in
+        --profile|-o) fastly completion values profile 2>/dev/null ;;
+        --service) fastly completion values service 2>/dev/null ;;
+        --service-id) fastly completion values service-id 2>/dev/null ;;
+        --service-name) fastly completion values service-name 2>/dev/null ;;
+        *) return 1 ;;
+    esac
+}
+
+_fastly() {
+    local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"
+    local path="" word values words i
+    for ((i = 1; i < COMP_CWORD; i++)); do
+        word="${COMP_WORDS[i]}"
+        [[ "$word" == -* ]] && continue
+        if [[ " $(_fastly_subcommands "$path") " == *" $word "* ]]; then
+            path="${path:+$path }$word"
+        fi
+    done
+    if values="$(_fastly_values "$prev")"; then
+        COMPREPLY=($(compgen -W "$values" -- "$cur"))
+        return 0
+    fi
+    if [[ "$cur" == -* ]]; then
+        words="$(_fastly_flags "$path")"
+        [[ -n "$path" ]] && words="$words $(_fastly_flags '')"
+    else
+        words="$(_fastly_subcommands "$path")"
+    fi
+    COMPREPLY=($(compgen -W "$words" -- "$cur"))
+}
+
+complete -F _fastly fastly
@@ -0,0 +1,53 @@
+# fish completion for fastly
+#
+# Generated by 'fastly completion fish'. To enable, run:
+#
+#   fastly completion fish > ~/.config/fish/completions/fastly.fish
+
+function __fastly_subcommands
+    switch "$argv[1]"
+        case ''
+            printf '%s\n' service service-version
+        case 'service'
+            printf '%s\n' describe list
+        case 'service-version'
+            printf '%s\n' activate
+    end
+end
+
+function __fastly_path
+    set -l path ''
+    for word in (commandline -opc)[2..-1]
+        string match -q -- '-*' $word; and continue
+        if contains -- $word (__fastly_subcommands $path)
+            set path (string trim -- "$path $word")
+        end
+    end
+    echo $path
+end
+
+function __fastly_using_path
+    test (__fastly_path) = "$argv[1]"
+end
+
+complete -c fastly -f
+
+complete -c fastly -n '__fastly_using_path ""' -a service -d 'Manipulate Fastly services'
+complete -c fastly -n '__fastly_using_path ""' -a service-version -d 'Manipulate Fastly service versions'
+complete -c fastly -l help -d 'Show context-sensitive help.'
+complete -c fastly -l profile -s o -x -a '(fastly completion values profile 2>/dev/null)' -d 'Switch account profile'
+complete -c fastly -l verbose -s v -d 'Verbose logging'
+
+complete -c fastly -n '__fastly_using_path "service"' -a describe -d 'Show detailed information about a Fastly service'
+complete -c fastly -n '__fastly_using_path "service"' -a list -d 'List Fastly services'
+
+complete -c fastly -n '__fastly_using_path "service describe"' -l json -s j -d 'Render output as JSON'
+complete -c fastly -n '__fastly_using_path "service describe"' -l service -x -a '(fastly completion values service 2>/dev/null)' -d 'Service alias or name'
+complete -c fastly -n '__fastly_using_path "service describe"' -l service-id -s s -x -a '(fastly completion values service-id 2>/dev/null)' -d 'Service ID'
+complete -c fastly -n '__fastly_using_path "service describe"' -l service-name -x -a '(fastly completion values service-name 2>/dev/null)' -d 'The name of the service'
+
+
+complete -c fastly -n '__fastly_using_path "service-version"' -a activate -d 'Activate a Fastly service version'
+
+complete -c fastly -n '__fastly_using_path "service-version activate"' -l comment -r -d 'Human-readable comment, e.g. \'it\'s live\''
+complete -c fastly -n '__fastly_using_path "service-version activate"' -l service-id -s s -x -a '(fastly completion values service-id 2>/dev/null)' -d 'Service ID'
@@ -0,0 +1,82 @@
+package completion
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/config"
+	"github.com/fastly/cli/pkg/global"
+)
+
+// Kinds of dynamically completed values.
+const (
+	KindProfile     = "profile"
+	KindService     = "service"
+	KindServiceID   = "service-id"
+	KindServiceName = "service-name"
+)
+
+var kinds = []string{KindProfile, KindService, KindServiceID, KindServiceName}
+
+// ValuesCommand prints the completion candidates for a flag value. It is
+// called by the completion scripts and only reads the config file: it must
+// never make network requests as it runs on every TAB press.
+type ValuesCommand struct {
+	argparser.Base
+
+	kind string
+}
+
+// NewValuesCommand returns a usable command registered under the parent.
+func NewValuesCommand(parent argparser.Registerer, g *global.Data) *ValuesCommand {
+	var c ValuesCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("values", "Print the completion candidates for a flag value").Hidden()
+	c.CmdClause.Arg("kind", "Kind of value").Required().EnumVar(&c.kind, kinds...)
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *ValuesCommand) Exec(_ io.Reader, out io.Writer) error {
+	for _, v := range Values(c.kind, c.Globals.Config) {
+		fmt.Fprintln(out, v)
+	}
+	return nil
+}
+
+// Values returns the completion candidates for the kind of value.
+//
+// Profiles are read from the config file. Service IDs and names are the
+// recently used values remembered in the config file (see
+// config.Completion.RecordService), most recent first. The --service flag
+// accepts the service aliases (sorted) followed by the recent service names.
+func Values(kind string, cfg config.File) []string {
+	switch kind {
+	case KindProfile:
+		names := make([]string, 0, len(cfg.Profiles))
+		for name := range cfg.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	case KindService:
+		names := make([]string, 0, len(cfg.ServiceAliases)+len(cfg.Completion.RecentServiceNames))
+		for name := range cfg.ServiceAliases {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range cfg.Completion.RecentServiceNames {
+			if _, ok := cfg.ServiceAliases[name]; !ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	case KindServiceID:
+		return cfg.Completion.RecentServiceIDs
+	case KindServiceName:
+		return cfg.Completion.RecentServiceNames
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package compute
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/mholt/archiver/v3"
+
+	"github.com/fastly/cli/pkg/config"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/wasm"
+)
+
+// DefaultEntrypoint is the function a Compute program's Wasm binary must
+// export, if the config doesn't set one (see config.ComputeABI).
+const DefaultEntrypoint = "_start"
+
+// packageBinary is the path of the Wasm binary within the package's top-level
+// directory.
+const packageBinary = "bin/main.wasm"
+
+// allowABIMismatchDesc describes the flag, shared by deploy and publish, that
+// downgrades the problems found by checkPackageABI to warnings.
+const allowABIMismatchDesc = "Warn about, rather than refuse to upload, a package that doesn't conform to the Compute ABI (e.g. it imports an unknown hostcall namespace)"
+
+// Remediations of the problems found by checkPackageABI.
+const (
+	abiLayoutRemediation     = "Create the package with `fastly compute build` (or `fastly compute pack` for a prebuilt Wasm binary), which lays out the archive as the Compute platform expects."
+	abiTargetRemediation     = "Check the program was built for the wasm32-wasi target (rather than e.g. wasm32-unknown-unknown, a browser or a component target) with an up-to-date Compute SDK. Run `fastly compute inspect` to list the imports of the Wasm binary."
+	abiEntrypointRemediation = "Check the program was built as an executable (a WASI command) rather than a library, e.g. a Rust `bin` target rather than a `cdylib`."
+)
+
+// abiProblem is a way a package doesn't conform to the Compute ABI.
+type abiProblem struct {
+	// Problem names the offending file or import.
+	Problem string
+	// Remediation is how to fix the problem.
+	Remediation string
+}
+
+// computeABI returns the ABI from the config, with the defaults for any value
+// it doesn't set.
+func computeABI(g *global.Data) config.ComputeABI {
+	abi := g.Config.ComputeABI
+	if abi.Entrypoint == "" {
+		abi.Entrypoint = DefaultEntrypoint
+	}
+	if len(abi.ImportModules) == 0 {
+		abi.ImportModules = ComputeABIModules
+	}
+	return abi
+}
+
+// checkPackageABI checks the package archive is laid out as the Compute
+// platform expects (a single top-level directory containing fastly.toml and
+// the one Wasm binary at bin/main.wasm), and that the Wasm binary exports the
+// entrypoint and only imports functions from the ABI's import modules.
+//
+// An error is only returned if the package can't be read.
+func checkPackageABI(pkgPath string, abi config.ComputeABI) ([]abiProblem, error) {
+	var (
+		binaries    []string
+		data        []byte
+		hasManifest bool
+		roots       = make(map[string]bool)
+	)
+	err := packageFiles(pkgPath, func(f archiver.File) error {
+		header, _ := f.Header.(*tar.Header) // packageFiles only passes regular tar files
+		name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+		root, rel, _ := strings.Cut(name, "/")
+		roots[root] = true
+		switch {
+		case rel == manifest.Filename:
+			hasManifest = true
+		case path.Base(name) == path.Base(packageBinary):
+			binaries = append(binaries, name)
+			if rel == packageBinary {
+				b, err := io.ReadAll(f)
+				if err != nil {
+					return fmt.Errorf("error reading %s: %w", name, err)
+				}
+				data = b
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []abiProblem
+	if len(roots) != 1 {
+		problems = append(problems, abiProblem{
+			Problem:     fmt.Sprintf("the package must contain a single top-level directory, but it has %d top-level entries (%s)", len(roots), strings.Join(sortedKeys(roots), ", ")),
+			Remediation: abiLayoutRemediation,
+		})
+	}
+	if !hasManifest {
+		problems = append(problems, abiProblem{
+			Problem:     fmt.Sprintf("the package has no %s in its top-level directory", manifest.Filename),
+			Remediation: abiLayoutRemediation,
+		})
+	}
+	switch {
+	case data == nil:
+		problems = append(problems, abiProblem{
+			Problem:     fmt.Sprintf("the package has no Wasm binary at %s in its top-level directory", packageBinary),
+			Remediation: abiLayoutRemediation,
+		})
+	case len(binaries) > 1:
+		sort.Strings(binaries)
+		problems = append(problems, abiProblem{
+			Problem:     fmt.Sprintf("the package has %d Wasm binaries named %s (%s) rather than a single %s", len(binaries), path.Base(packageBinary), strings.Join(binaries, ", "), packageBinary),
+			Remediation: abiLayoutRemediation,
+		})
+	}
+	if data != nil {
+		problems = append(problems, checkWasmABI(data, abi)...)
+	}
+	return problems, nil
+}
+
+// checkWasmABI checks the Wasm binary exports the entrypoint and only imports
+// functions from the ABI's import modules.
+func checkWasmABI(data []byte, abi config.ComputeABI) []abiProblem {
+	m, err := wasm.Parse(data)
+	if errors.Is(err, wasm.ErrComponent) {
+		return []abiProblem{{
+			Problem:     fmt.Sprintf("%s is a WebAssembly component, but the Compute platform runs core modules", packageBinary),
+			Remediation: abiTargetRemediation,
+		}}
+	}
+	if err != nil {
+		return []abiProblem{{
+			Problem:     fmt.Sprintf("%s isn't a valid WebAssembly module: %v", packageBinary, err),
+			Remediation: abiTargetRemediation,
+		}}
+	}
+
+	var problems []abiProblem
+	// NOTE: The functions imported from each unknown module are grouped, so a
+	// binary built for the wrong target reports one problem per module.
+	var unknown []string
+	imports := make(map[string][]string)
+	for _, i := range m.Imports {
+		if isComputeABIModule(abi.ImportModules, i.Module) {
+			continue
+		}
+		if _, ok := imports[i.Module]; !ok {
+			unknown = append(unknown, i.Module)
+		}
+		imports[i.Module] = append(imports[i.Module], i.Name)
+	}
+	for _, module := range unknown {
+		fns := imports[module]
+		imported := fmt.Sprintf("'%s'", fns[0])
+		if len(fns) > 1 {
+			imported += fmt.Sprintf(" (and %d other functions)", len(fns)-1)
+		}
+		problems = append(problems, abiProblem{
+			Problem:     fmt.Sprintf("%s imports %s from the '%s' module, which isn't a Compute hostcall namespace", packageBinary, imported, module),
+			Remediation: abiTargetRemediation,
+		})
+	}
+
+	exported := false
+	for _, e := range m.Exports {
+		if e == abi.Entrypoint {
+			exported = true
+			break
+		}
+	}
+	if !exported {
+		problems = append(problems, abiProblem{
+			Problem:     fmt.Sprintf("%s doesn't export the '%s' function the Compute platform calls to run the program", packageBinary, abi.Entrypoint),
+			Remediation: abiEntrypointRemediation,
+		})
+	}
+	return problems
+}
+
+// abiError returns an error describing the problems found by checkPackageABI,
+// with the remediation of each kind of problem.
+func abiError(problems []abiProblem) fsterr.RemediationError {
+	descriptions := make([]string, 0, len(problems))
+	var remediations []string
+	seen := make(map[string]bool)
+	for _, p := range problems {
+		descriptions = append(descriptions, p.Problem)
+		if !seen[p.Remediation] {
+			seen[p.Remediation] = true
+			remediations = append(remediations, p.Remediation)
+		}
+	}
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("the package doesn't conform to the Compute ABI: %s", strings.Join(descriptions, "; ")),
+		Remediation: strings.Join(remediations, "\n\n"),
+	}
+}
+
+// sortedKeys returns the keys of the set in order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/fastly/cli/pkg/argparser"
 	"github.com/fastly/cli/pkg/check"
 	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/events"
 	"github.com/fastly/cli/pkg/filesystem"
 	"github.com/fastly/cli/pkg/github"
 	"github.com/fastly/cli/pkg/global"
@@ -36,7 +37,7 @@ import (
 const IgnoreFilePath = ".fastlyignore"
 
 // CustomPostScriptMessage is the message displayed to a user when there is
-// either a post_init or post_build script defined.
+// a post_init script defined.
 const CustomPostScriptMessage = "This project has a custom post_%s script defined in the %s manifest"
 
 // ErrWasmtoolsNotFound represents an error finding the binary installed.
@@ -47,12 +48,14 @@ var ErrWasmtoolsNotFound = fsterr.RemediationError{
 
 // Flags represents the flags defined for the command.
 type Flags struct {
-	Dir         string
-	Env         string
-	IncludeSrc  bool
-	Lang        string
-	PackageName string
-	Timeout     int
+	Dir                string
+	Env                string
+	IncludeSrc         bool
+	Lang               string
+	PackageName        string
+	Reproducible       bool
+	SkipToolchainCheck bool
+	Timeout            int
 }
 
 // BuildCommand produces a deployable artifact from files on the local disk.
@@ -66,7 +69,8 @@ type BuildCommand struct {
 	MetadataDisable       bool
 	MetadataFilterEnvVars string
 	MetadataShow          bool
-	SkipChangeDir         bool // set by parent composite commands (e.g. serve, publish)
+	SkipChangeDir         bool       // set by parent composite commands (e.g. serve, publish)
+	VCSStatus             *VCSStatus // set by publish when a VCS guard is enabled
 }
 
 // NewBuildCommand returns a usable command registered under the parent.
@@ -85,6 +89,8 @@ func NewBuildCommand(parent argparser.Registerer, g *global.Data) *BuildCommand
 	c.CmdClause.Flag("metadata-filter-envvars", "Redact specified environment variables from [scripts.env_vars] using comma-separated list").StringVar(&c.MetadataFilterEnvVars)
 	c.CmdClause.Flag("metadata-show", "Inspect the Wasm binary metadata").BoolVar(&c.MetadataShow)
 	c.CmdClause.Flag("package-name", "Package name").StringVar(&c.Flags.PackageName)
+	c.CmdClause.Flag("reproducible", "Build a byte-identical package from identical sources (timestamps come from SOURCE_DATE_EPOCH, and environment-dependent metadata is omitted)").BoolVar(&c.Flags.Reproducible)
+	c.CmdClause.Flag("skip-toolchain-check", "Skip checking the installed toolchain version meets the requirements (Go only)").BoolVar(&c.Flags.SkipToolchainCheck)
 	c.CmdClause.Flag("timeout", "Timeout, in seconds, for the build compilation step").IntVar(&c.Flags.Timeout)
 
 	return &c
@@ -92,6 +98,19 @@ func NewBuildCommand(parent argparser.Registerer, g *global.Data) *BuildCommand
 
 // Exec implements the command interface.
 func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
+	phase := c.Globals.Timings.Start("build")
+	defer phase.End()
+
+	c.Globals.Events.Emit(events.BuildStarted, nil)
+	var pkgPath string
+	defer func() {
+		if err != nil {
+			c.Globals.Events.Emit(events.BuildFailed, events.Fields{"error": err.Error()})
+			return
+		}
+		c.Globals.Events.Emit(events.BuildFinished, events.Fields{"package": pkgPath})
+	}()
+
 	// We'll restore this at the end to print a final successful build output.
 	originalOut := out
 	if c.Globals.Flags.Quiet {
@@ -167,6 +186,18 @@ func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		return err
 	}
 
+	var mtime time.Time
+	if c.Flags.Reproducible {
+		mtime, err = SourceDateEpoch(c.Globals.Env.SourceDateEpoch)
+		if err != nil {
+			return err
+		}
+		if !c.Globals.Flags.Quiet {
+			text.Warning(out, ReproducibleWarning)
+			text.Break(out)
+		}
+	}
+
 	wasmtools, wasmtoolsErr := GetWasmTools(spinner, out, c.Globals.Versioners.WasmTools, c.Globals)
 
 	var pkgName string
@@ -177,6 +208,11 @@ func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
 	if err != nil {
 		return err
 	}
+	defer func() {
+		if err != nil {
+			err = fsterr.WithContext(err, fsterr.PrefixContext("while building package %s", pkgName))
+		}
+	}()
 
 	var toolchain string
 	err = spinner.Process("Identifying toolchain", func(_ *text.SpinnerWrapper) error {
@@ -187,7 +223,7 @@ func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		return err
 	}
 
-	language, err := language(toolchain, manifestFilename, c, in, out, spinner)
+	language, err := language(toolchain, manifestFilename, c, out, spinner)
 	if err != nil {
 		return err
 	}
@@ -197,10 +233,23 @@ func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		return err
 	}
 
-	if err := language.Build(); err != nil {
+	err = c.runHook(HookPreBuild, manifestFilename, false, phase, in, out, spinner)
+	if err != nil {
+		return err
+	}
+
+	compile := phase.Start("compile")
+	err = language.Build()
+	compile.End()
+	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
 			"Language": language.Name,
 		})
+		return fsterr.WithContext(err, fsterr.PrefixContext("while running the %s build script", language.Name))
+	}
+
+	err = c.runHook(HookPostBuild, manifestFilename, true, phase, in, out, spinner)
+	if err != nil {
 		return err
 	}
 
@@ -217,7 +266,9 @@ func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		}
 
 		metadataDisable, _ := strconv.ParseBool(c.Globals.Env.WasmMetadataDisable)
-		if !c.MetadataDisable && !metadataDisable {
+		// NOTE: The full metadata includes details of the machine running the
+		// build (e.g. memory usage) and so isn't reproducible.
+		if !c.MetadataDisable && !metadataDisable && !c.Flags.Reproducible {
 			if err := c.AnnotateWasmBinaryLong(wasmtools, metadataArgs, language); err != nil {
 				return err
 			}
@@ -239,6 +290,7 @@ func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
 	}
 
 	dest := filepath.Join("pkg", fmt.Sprintf("%s.tar.gz", pkgName))
+	archive := phase.Start("package archive")
 	err = spinner.Process("Creating package archive", func(_ *text.SpinnerWrapper) error {
 		// IMPORTANT: The minimum package requirement is `fastly.toml` and `main.wasm`.
 		//
@@ -258,12 +310,12 @@ func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
 			// than the standard fastly.toml manifest.
 			if _, err := os.Stat(manifest.Filename); err == nil {
 				backup := fmt.Sprintf("%s.backup.%d", manifest.Filename, time.Now().Unix())
-				if err := os.Rename(manifest.Filename, backup); err != nil {
-					return fmt.Errorf("failed to backup primary manifest file: %w", err)
+				if err := filesystem.Rename(manifest.Filename, backup); err != nil {
+					return err
 				}
 				defer func() {
 					// 4. Rename the fastly.toml.backup back to fastly.toml
-					if err = os.Rename(backup, manifest.Filename); err != nil {
+					if err = filesystem.Rename(backup, manifest.Filename); err != nil {
 						text.Error(out, err.Error())
 					}
 				}()
@@ -275,7 +327,7 @@ func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
 				// fastly.production.toml) then we should remove the fastly.toml that we
 				// created just for the packaging process (see step 2. below).
 				defer func() {
-					if err = os.Remove(manifest.Filename); err != nil {
+					if err = filesystem.Remove(manifest.Filename); err != nil {
 						text.Error(out, err.Error())
 					}
 				}()
@@ -299,7 +351,11 @@ func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		if err != nil {
 			return err
 		}
-		err = CreatePackageArchive(files, dest)
+		if c.Flags.Reproducible {
+			err = CreateReproduciblePackageArchive(files, dest, mtime)
+		} else {
+			err = CreatePackageArchive(files, dest)
+		}
 		if err != nil {
 			c.Globals.ErrLog.AddWithContext(err, map[string]any{
 				"Files":       files,
@@ -309,10 +365,12 @@ func (c *BuildCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		}
 		return nil
 	})
+	archive.End()
 	if err != nil {
 		return err
 	}
 
+	pkgPath = dest
 	out = originalOut
 	text.Success(out, "\nBuilt package (%s)", dest)
 	return nil
@@ -367,9 +425,12 @@ func (c *BuildCommand) AnnotateWasmBinaryLong(wasmtools string, args []string, l
 			EnvVars:          FilterSecretsFromSlice(c.Globals.Manifest.File.Scripts.EnvVars),
 			PostInitScript:   FilterSecretsFromString(c.Globals.Manifest.File.Scripts.PostInit),
 			PostBuildScript:  FilterSecretsFromString(c.Globals.Manifest.File.Scripts.PostBuild),
+			PreBuildScript:   FilterSecretsFromString(c.Globals.Manifest.File.Scripts.PreBuild),
 		}
 	}
 
+	dc.VCSInfo = c.VCSStatus
+
 	data, err := json.Marshal(dc)
 	if err != nil {
 		return err
@@ -657,37 +718,37 @@ func identifyToolchain(c *BuildCommand) (string, error) {
 // language returns a pointer to a supported language.
 //
 // TODO: Fix the mess that is New<language>()'s argument list.
-func language(toolchain, manifestFilename string, c *BuildCommand, in io.Reader, out io.Writer, spinner text.Spinner) (*Language, error) {
+func language(toolchain, manifestFilename string, c *BuildCommand, out io.Writer, spinner text.Spinner) (*Language, error) {
 	var language *Language
 	switch toolchain {
 	case "assemblyscript":
 		language = NewLanguage(&LanguageOptions{
 			Name:            "assemblyscript",
 			SourceDirectory: AsSourceDirectory,
-			Toolchain:       NewAssemblyScript(c, in, manifestFilename, out, spinner),
+			Toolchain:       NewAssemblyScript(c, manifestFilename, out, spinner),
 		})
 	case "go":
 		language = NewLanguage(&LanguageOptions{
 			Name:            "go",
 			SourceDirectory: GoSourceDirectory,
-			Toolchain:       NewGo(c, in, manifestFilename, out, spinner),
+			Toolchain:       NewGo(c, manifestFilename, out, spinner),
 		})
 	case "javascript":
 		language = NewLanguage(&LanguageOptions{
 			Name:            "javascript",
 			SourceDirectory: JsSourceDirectory,
-			Toolchain:       NewJavaScript(c, in, manifestFilename, out, spinner),
+			Toolchain:       NewJavaScript(c, manifestFilename, out, spinner),
 		})
 	case "rust":
 		language = NewLanguage(&LanguageOptions{
 			Name:            "rust",
 			SourceDirectory: RustSourceDirectory,
-			Toolchain:       NewRust(c, in, manifestFilename, out, spinner),
+			Toolchain:       NewRust(c, manifestFilename, out, spinner),
 		})
 	case "other":
 		language = NewLanguage(&LanguageOptions{
 			Name:      "other",
-			Toolchain: NewOther(c, in, manifestFilename, out, spinner),
+			Toolchain: NewOther(c, out, spinner),
 		})
 	default:
 		return nil, fmt.Errorf("unsupported language %s", toolchain)
@@ -723,6 +784,16 @@ func binDir(c *BuildCommand) error {
 // temporary directory to ensure only the specified files are included and not
 // any in the directory which may be ignored.
 func CreatePackageArchive(files []string, destination string) error {
+	return createPackageArchive(files, destination, nil)
+}
+
+// CreateReproduciblePackageArchive is like CreatePackageArchive, but produces
+// a byte-identical archive for identical files (see CreateReproducibleArchive).
+func CreateReproduciblePackageArchive(files []string, destination string, mtime time.Time) error {
+	return createPackageArchive(files, destination, &mtime)
+}
+
+func createPackageArchive(files []string, destination string, mtime *time.Time) error {
 	// Create temporary directory to copy files into.
 	p := make([]byte, 8)
 	n, err := rand.Read(p)
@@ -735,8 +806,8 @@ func CreatePackageArchive(files []string, destination string) error {
 		fmt.Sprintf("fastly-build-%x", p[:n]),
 	)
 
-	if err := os.MkdirAll(tmpDir, 0o700); err != nil {
-		return fmt.Errorf("error creating temporary directory: %w", err)
+	if err := filesystem.MkdirAll(tmpDir, 0o700); err != nil {
+		return err
 	}
 	defer os.RemoveAll(tmpDir)
 
@@ -744,8 +815,8 @@ func CreatePackageArchive(files []string, destination string) error {
 	// root of the archive. This replaces the `tar.ImplicitTopLevelFolder`
 	// behavior.
 	dir := filepath.Join(tmpDir, FileNameWithoutExtension(destination))
-	if err := os.Mkdir(dir, 0o700); err != nil {
-		return fmt.Errorf("error creating temporary directory: %w", err)
+	if err := filesystem.MkdirAll(dir, 0o700); err != nil {
+		return err
 	}
 
 	for _, src := range files {
@@ -755,6 +826,10 @@ func CreatePackageArchive(files []string, destination string) error {
 		}
 	}
 
+	if mtime != nil {
+		return CreateReproducibleArchive(dir, destination, *mtime)
+	}
+
 	tar := archiver.NewTarGz()
 	tar.OverwriteExisting = true //
 	tar.MkdirAll = true          // make destination directory if it doesn't exist
@@ -873,6 +948,7 @@ type DataCollection struct {
 	MachineInfo DataCollectionMachineInfo `json:"machine_info,omitempty"`
 	PackageInfo DataCollectionPackageInfo `json:"package_info,omitempty"`
 	ScriptInfo  DataCollectionScriptInfo  `json:"script_info,omitempty"`
+	VCSInfo     *VCSStatus                `json:"vcs_info,omitempty"`
 }
 
 // DataCollectionBuildInfo represents build data annotated onto the Wasm binary.
@@ -906,4 +982,5 @@ type DataCollectionScriptInfo struct {
 	EnvVars          []string `json:"env_vars,omitempty"`
 	PostInitScript   string   `json:"post_init_script,omitempty"`
 	PostBuildScript  string   `json:"post_build_script,omitempty"`
+	PreBuildScript   string   `json:"pre_build_script,omitempty"`
 }
@@ -1,22 +1,27 @@
 package compute_test
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/fastly/cli/pkg/app"
 	"github.com/fastly/cli/pkg/commands/compute"
 	"github.com/fastly/cli/pkg/config"
+	fstexec "github.com/fastly/cli/pkg/exec"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/mock"
 	"github.com/fastly/cli/pkg/testutil"
 	"github.com/fastly/cli/pkg/threadsafe"
+	"github.com/fastly/cli/pkg/timing"
 )
 
 func TestBuildRust(t *testing.T) {
@@ -445,6 +450,280 @@ func TestBuildGo(t *testing.T) {
 	}
 }
 
+func TestParseToolchainVersion(t *testing.T) {
+	for _, testcase := range []struct {
+		toolchain   string
+		output      string
+		wantVersion string
+		wantError   string
+	}{
+		{toolchain: "go", output: "go version go1.21.5 linux/amd64", wantVersion: "1.21.5"},
+		{toolchain: "go", output: "go version go1.22 darwin/arm64", wantVersion: "1.22.0"},
+		{toolchain: "go", output: "go version go1 linux/amd64", wantVersion: "1.0.0"},
+		{toolchain: "go", output: "go version go1.22rc1 linux/amd64", wantVersion: "1.22.0"},
+		{toolchain: "go", output: "go version go1.21beta2 windows/amd64", wantVersion: "1.21.0"},
+		{toolchain: "go", output: "go version devel go1.23-e8ee1dc4f9 Wed Feb 7 23:15:10 2024 +0000 linux/amd64", wantVersion: "1.23.0"},
+		{toolchain: "go", output: "go version go1.20.12 (Red Hat 1.20.12-1.el9_3) linux/amd64", wantVersion: "1.20.12"},
+		{toolchain: "go", output: "go version go1.21.6 X:nocoverageredesign linux/amd64", wantVersion: "1.21.6"},
+		{toolchain: "go", output: "go: downloading go1.22.1\ngo version go1.22.1 linux/amd64\n", wantVersion: "1.22.1"},
+		{toolchain: "go", output: "go version devel +b7a85e0003 Tue Jan 1 00:00:00 2019 +0000 linux/amd64", wantError: compute.ErrDevelToolchain.Error()},
+		{toolchain: "go", output: "", wantError: "unable to parse the go version from the output of `go version`"},
+		{toolchain: "go", output: "go version unknown", wantError: "unable to parse the go version"},
+		{toolchain: "go", output: "bash: go: command not found", wantError: "unable to parse the go version"},
+		{toolchain: "go", output: "go version go99999999999999999999.1 linux/amd64", wantError: "unable to parse the go version"},
+		{toolchain: "tinygo", output: "tinygo version 0.30.0 linux/amd64 (using go version go1.21.5 and LLVM version 16.0.1)", wantVersion: "0.30.0"},
+		{toolchain: "tinygo", output: "tinygo version 0.31.0-dev-3f4d3a2 darwin/arm64 (using go version go1.22.0 and LLVM version 17.0.1)", wantVersion: "0.31.0-dev-3f4d3a2"},
+		{toolchain: "tinygo", output: "tinygo version v0.28.1 windows/amd64", wantVersion: "0.28.1"},
+		{toolchain: "tinygo", output: "tinygo version 0.29 linux/arm64", wantVersion: "0.29.0"},
+		{toolchain: "tinygo", output: "tinygo version linux/amd64", wantError: "unable to parse the tinygo version"},
+		{toolchain: "tinygo", output: "go version go1.21.5 linux/amd64", wantError: "unable to parse the tinygo version"},
+		{toolchain: "rustc", output: "rustc 1.75.0", wantError: "unsupported toolchain 'rustc'"},
+	} {
+		t.Run(testcase.toolchain+" "+testcase.output, func(t *testing.T) {
+			v, err := compute.ParseToolchainVersion(testcase.toolchain, testcase.output)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if testcase.wantError == "" {
+				testutil.AssertString(t, testcase.wantVersion, v.String())
+			}
+		})
+	}
+}
+
+func TestBuildGoToolchainCheck(t *testing.T) {
+	args := testutil.Args
+
+	binary := func(name string) string {
+		if runtime.GOOS == "windows" {
+			return name + ".exe"
+		}
+		return name
+	}
+
+	for _, testcase := range []struct {
+		name string
+		args []string
+		// build is the [scripts.build] (TinyGo is checked if it contains `tinygo build`).
+		build string
+		// binaries are the toolchain binaries on $PATH, by directory.
+		binaries [][]string
+		// versions is the faked `<toolchain> version` output, by toolchain.
+		versions map[string]string
+		runError error
+		// wantBuild indicates the check is expected to pass (or be skipped), so
+		// the build script is executed (which fails in the test environment).
+		wantBuild       bool
+		wantError       string
+		wantRemediation string
+		wantOutput      []string
+		dontWantOutput  []string
+	}{
+		{
+			name:      "go meets the constraint",
+			args:      args("compute build --verbose"),
+			build:     "go build -o bin/main.wasm ./",
+			binaries:  [][]string{{"go"}},
+			versions:  map[string]string{"go": "go version go1.21.5 linux/amd64"},
+			wantBuild: true,
+			wantOutput: []string{
+				"The Fastly CLI build step requires a go version '>= 1.21'",
+				"Using the go binary",
+			},
+			dontWantOutput: []string{"tinygo version"},
+		},
+		{
+			name:            "go doesn't meet the constraint",
+			args:            args("compute build"),
+			build:           "go build -o bin/main.wasm ./",
+			binaries:        [][]string{{"go"}},
+			versions:        map[string]string{"go": "go version go1.20.12 linux/amd64"},
+			wantError:       "the go version '1.20.12'",
+			wantRemediation: "go install golang.org/dl/go1.21.0@latest && go1.21.0 download",
+		},
+		{
+			name:      "go release candidate meets the constraint",
+			args:      args("compute build"),
+			build:     "go build -o bin/main.wasm ./",
+			binaries:  [][]string{{"go"}},
+			versions:  map[string]string{"go": "go version go1.22rc1 linux/amd64"},
+			wantBuild: true,
+		},
+		{
+			name:       "go development build without a version",
+			args:       args("compute build"),
+			build:      "go build -o bin/main.wasm ./",
+			binaries:   [][]string{{"go"}},
+			versions:   map[string]string{"go": "go version devel +b7a85e0003 linux/amd64"},
+			wantBuild:  true,
+			wantOutput: []string{"Unable to identify the version of the go development build"},
+		},
+		{
+			name:            "go version output malformed",
+			args:            args("compute build"),
+			build:           "go build -o bin/main.wasm ./",
+			binaries:        [][]string{{"go"}},
+			versions:        map[string]string{"go": "something unexpected"},
+			wantError:       `unable to parse the go version from the output of ` + "`go version`" + `: "something unexpected"`,
+			wantRemediation: compute.SkipToolchainCheckRemediation,
+		},
+		{
+			name:            "go version fails to run",
+			args:            args("compute build"),
+			build:           "go build -o bin/main.wasm ./",
+			binaries:        [][]string{{"go"}},
+			runError:        testutil.Err,
+			wantError:       "failed to execute",
+			wantRemediation: compute.SkipToolchainCheckRemediation,
+		},
+		{
+			name:            "go not installed",
+			args:            args("compute build"),
+			build:           "go build -o bin/main.wasm ./",
+			wantError:       "go not found on $PATH",
+			wantRemediation: "Install Go 1.21.0 or later",
+		},
+		{
+			name:      "multiple go binaries on $PATH",
+			args:      args("compute build"),
+			build:     "go build -o bin/main.wasm ./",
+			binaries:  [][]string{{"go"}, {"go"}},
+			versions:  map[string]string{"go": "go version go1.21.5 linux/amd64"},
+			wantBuild: true,
+			wantOutput: []string{
+				fmt.Sprintf("Found multiple go binaries on $PATH, using '%s'", filepath.Join("0", binary("go"))),
+				fmt.Sprintf("(also found: %s", filepath.Join("1", binary("go"))),
+			},
+		},
+		{
+			name:      "tinygo meets the constraint",
+			args:      args("compute build --verbose"),
+			build:     "tinygo build -target=wasi -o bin/main.wasm ./",
+			binaries:  [][]string{{"go", "tinygo"}},
+			versions:  map[string]string{"go": "go version go1.19 linux/amd64", "tinygo": "tinygo version 0.31.0-dev-3f4d3a2 linux/amd64 (using go version go1.19 and LLVM version 17.0.1)"},
+			wantBuild: true,
+			wantOutput: []string{
+				"The Fastly CLI build step requires a go version '>= 1.18'",
+				"The Fastly CLI build step requires a tinygo version '>= 0.28.1-0'",
+			},
+		},
+		{
+			name:            "tinygo doesn't meet the constraint",
+			args:            args("compute build"),
+			build:           "tinygo build -target=wasi -o bin/main.wasm ./",
+			binaries:        [][]string{{"go", "tinygo"}},
+			versions:        map[string]string{"go": "go version go1.21.5 linux/amd64", "tinygo": "tinygo version 0.27.0 linux/amd64 (using go version go1.21.5 and LLVM version 15.0.0)"},
+			wantError:       "the tinygo version '0.27.0'",
+			wantRemediation: "Install TinyGo 0.28.1 or later",
+		},
+		{
+			name:            "tinygo not installed",
+			args:            args("compute build"),
+			build:           "tinygo build -target=wasi -o bin/main.wasm ./",
+			binaries:        [][]string{{"go"}},
+			versions:        map[string]string{"go": "go version go1.21.5 linux/amd64"},
+			wantError:       "tinygo not found on $PATH",
+			wantRemediation: "Install TinyGo 0.28.1 or later",
+		},
+		{
+			name:      "check skipped",
+			args:      args("compute build --skip-toolchain-check --verbose"),
+			build:     "go build -o bin/main.wasm ./",
+			binaries:  [][]string{{"go"}},
+			versions:  map[string]string{"go": "go version go1.16 linux/amd64"},
+			wantBuild: true,
+			wantOutput: []string{
+				"Skipping the Go toolchain version check (--skip-toolchain-check)",
+			},
+			dontWantOutput: []string{"requires a go version"},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			pwd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			wasmtoolsBinName := "wasm-tools"
+			latestDownloaded := wasmtoolsBinName + "-latest-downloaded"
+			write := []testutil.FileIO{
+				{Src: "#!/usr/bin/env bash\necho wasm-tools 1.0.4", Dst: wasmtoolsBinName, Executable: true},
+				{Src: "#!/usr/bin/env bash\necho wasm-tools 2.0.0", Dst: latestDownloaded, Executable: true},
+				{Src: fmt.Sprintf("manifest_version = 2\nname = \"test\"\nlanguage = \"go\"\n[scripts]\nbuild = %q\n", testcase.build), Dst: manifest.Filename},
+			}
+			var pathDirs []string
+			for i, bins := range testcase.binaries {
+				dir := strconv.Itoa(i)
+				pathDirs = append(pathDirs, dir)
+				for _, bin := range bins {
+					write = append(write, testutil.FileIO{Src: "fake", Dst: filepath.Join(dir, binary(bin)), Executable: true})
+				}
+			}
+			rootdir := testutil.NewEnv(testutil.EnvOpts{T: t, Write: write})
+			defer os.RemoveAll(rootdir)
+
+			if err := os.Chdir(rootdir); err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = os.Chdir(pwd)
+			}()
+
+			// Only the fake binaries are on $PATH (relative to the project
+			// directory, which keeps the reported paths short).
+			t.Setenv("PATH", strings.Join(append(pathDirs, filepath.Join(rootdir, "empty")), string(os.PathListSeparator)))
+
+			originalRunner := compute.ToolchainVersionRunner
+			defer func() { compute.ToolchainVersionRunner = originalRunner }()
+			compute.ToolchainVersionRunner = func(path string, args ...string) ([]byte, error) {
+				if testcase.runError != nil {
+					return nil, testcase.runError
+				}
+				toolchain := strings.TrimSuffix(filepath.Base(path), ".exe")
+				testutil.AssertEqual(t, []string{"version"}, args)
+				return []byte(testcase.versions[toolchain]), nil
+			}
+
+			var stdout threadsafe.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(testcase.args, &stdout)
+				opts.Config.CLI.MetadataNoticeDisplayed = true // avoid the notice's delay
+				opts.Config.Language.Go = config.Go{
+					TinyGoConstraint:          ">= 0.28.1-0",
+					TinyGoConstraintFallback:  ">= 0.26.0-0",
+					ToolchainConstraintTinyGo: ">= 1.18",
+					ToolchainConstraint:       ">= 1.21",
+				}
+				opts.Versioners = global.Versioners{
+					WasmTools: mock.AssetVersioner{
+						AssetVersion:    "1.2.3",
+						BinaryFilename:  wasmtoolsBinName,
+						DownloadOK:      true,
+						DownloadedFile:  latestDownloaded,
+						InstallFilePath: filepath.Join(rootdir, wasmtoolsBinName),
+					},
+				}
+				return opts, nil
+			}
+			err = app.Run(testcase.args, nil)
+
+			t.Log(stdout.String())
+
+			if testcase.wantBuild {
+				// The fake toolchain can't build anything.
+				testutil.AssertRemediationErrorContains(t, err, compute.DefaultBuildErrorRemediation)
+			} else {
+				testutil.AssertErrorContains(t, err, testcase.wantError)
+				testutil.AssertRemediationErrorContains(t, err, testcase.wantRemediation)
+			}
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			for _, s := range testcase.dontWantOutput {
+				testutil.AssertStringDoesntContain(t, stdout.String(), s)
+			}
+		})
+	}
+}
+
 func TestBuildJavaScript(t *testing.T) {
 	if os.Getenv("TEST_COMPUTE_BUILD_JAVASCRIPT") == "" && os.Getenv("TEST_COMPUTE_BUILD") == "" {
 		t.Log("skipping test")
@@ -1027,3 +1306,155 @@ func TestBuildOther(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildHooks(t *testing.T) {
+	args := testutil.Args
+
+	for _, testcase := range []struct {
+		name            string
+		args            []string
+		fail            string
+		stdin           string
+		wantError       string
+		wantRemediation string
+		wantOutput      []string
+		wantPhases      []string
+		wantRan         []string
+	}{
+		{
+			name:    "hooks run around the build",
+			args:    args("compute build --non-interactive"),
+			wantRan: []string{"pre-build-script", "build-script", "post-build-script"},
+			wantOutput: []string{
+				"[pre_build] running pre-build-script",
+				"[post_build] running post-build-script",
+				"Built package",
+			},
+			wantPhases: []string{"build", "pre_build", "compile", "post_build", "package archive"},
+		},
+		{
+			name:       "failing pre_build skips the build",
+			args:       args("compute build --non-interactive"),
+			fail:       "pre-build-script",
+			wantRan:    []string{"pre-build-script"},
+			wantError:  "[scripts.pre_build] failed, so the build was skipped: exit status 1",
+			wantPhases: []string{"build", "pre_build"},
+		},
+		{
+			name:            "failing post_build reports the build succeeded",
+			args:            args("compute build --non-interactive"),
+			fail:            "post-build-script",
+			wantRan:         []string{"pre-build-script", "build-script", "post-build-script"},
+			wantError:       "the build succeeded but [scripts.post_build] failed: exit status 1",
+			wantRemediation: "The Wasm binary was built (./bin/main.wasm) but it wasn't packaged.",
+			wantPhases:      []string{"build", "pre_build", "compile", "post_build"},
+		},
+		{
+			name:            "declining the pre_build prompt stops the build",
+			args:            args("compute build"),
+			stdin:           "N",
+			wantError:       "build process stopped by user",
+			wantRemediation: "[scripts.pre_build]",
+			wantOutput:      []string{"This project has a custom pre_build script defined in the fastly.toml manifest"},
+			wantPhases:      []string{"build"},
+		},
+		{
+			name:      "skipping the build skips the hooks",
+			args:      args("compute hash-files --skip-build --non-interactive"),
+			wantError: "error reading package",
+		},
+		{
+			name:       "skipping the build runs the hooks with --run-hooks",
+			args:       args("compute hash-files --skip-build --run-hooks --non-interactive --verbose"),
+			wantRan:    []string{"pre-build-script", "post-build-script"},
+			wantError:  "error reading package",
+			wantOutput: []string{"[pre_build] running pre-build-script"},
+			wantPhases: []string{"hooks", "pre_build", "post_build"},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			pwd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			wasmtoolsBinName := "wasm-tools"
+			latestDownloaded := wasmtoolsBinName + "-latest-downloaded"
+			rootdir := testutil.NewEnv(testutil.EnvOpts{
+				T: t,
+				Write: []testutil.FileIO{
+					{Src: "#!/usr/bin/env bash\necho wasm-tools 1.0.4", Dst: wasmtoolsBinName, Executable: true},
+					{Src: "#!/usr/bin/env bash\necho wasm-tools 2.0.0", Dst: latestDownloaded, Executable: true},
+					{Src: `manifest_version = 3
+name = "test"
+language = "other"
+[scripts]
+build = "build-script"
+post_build = "post-build-script"
+pre_build = "pre-build-script"
+`, Dst: manifest.Filename},
+				},
+			})
+			defer os.RemoveAll(rootdir)
+
+			if err := os.Chdir(rootdir); err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = os.Chdir(pwd)
+			}()
+
+			// The fake runner records the scripts in the order they're run, and the
+			// build script produces a Wasm binary that looks valid.
+			var ran []string
+			originalRunner := compute.ScriptRunner
+			defer func() { compute.ScriptRunner = originalRunner }()
+			compute.ScriptRunner = func(opts fstexec.CommandOpts) error {
+				script := opts.Args[len(opts.Args)-1]
+				ran = append(ran, script)
+				fmt.Fprintf(opts.Output, "running %s\n", script)
+				if script == testcase.fail {
+					return errors.New("exit status 1")
+				}
+				if script == "build-script" {
+					return os.WriteFile(filepath.Join("bin", "main.wasm"), []byte("\x00asm\x01\x00\x00\x00"), 0o600)
+				}
+				return nil
+			}
+
+			var stdout threadsafe.Buffer
+			timings := timing.New(nil)
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(testcase.args, &stdout)
+				opts.Config.CLI.MetadataNoticeDisplayed = true // avoid the notice's delay
+				opts.Input = strings.NewReader(testcase.stdin)
+				opts.Timings = timings
+				opts.Versioners = global.Versioners{
+					WasmTools: mock.AssetVersioner{
+						AssetVersion:    "1.2.3",
+						BinaryFilename:  wasmtoolsBinName,
+						DownloadOK:      true,
+						DownloadedFile:  latestDownloaded,
+						InstallFilePath: filepath.Join(rootdir, wasmtoolsBinName),
+					},
+				}
+				return opts, nil
+			}
+			err = app.Run(testcase.args, nil)
+
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertRemediationErrorContains(t, err, testcase.wantRemediation)
+			testutil.AssertEqual(t, testcase.wantRan, ran)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			var phases []string
+			for _, r := range timings.Records() {
+				phases = append(phases, r.Name)
+			}
+			testutil.AssertEqual(t, testcase.wantPhases, phases)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package cache_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/github"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+// setupTree creates a project with build artifacts, a Viceroy binary and a
+// leftover update download, returning the project directory.
+func setupTree(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	old := time.Now().Add(-72 * time.Hour)
+	for _, f := range []struct {
+		path  string
+		size  int
+		mtime time.Time
+	}{
+		{"project/fastly.toml", 10, time.Now()},
+		{"project/src/main.rs", 20, old},
+		{"project/bin/main.wasm", 2000, old},
+		{"project/pkg/project.tar.gz", 500, time.Now()},
+		{"install/viceroy", 30000, old},
+		{"tmp/fastly-update123/fastly", 1000, old},
+	} {
+		p := filepath.Join(root, filepath.FromSlash(f.path))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, make([]byte, f.size), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(p, f.mtime, f.mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chtimes(filepath.Join(root, "tmp", "fastly-update123"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	installDir := github.InstallDir
+	github.InstallDir = filepath.Join(root, "install")
+	t.Cleanup(func() {
+		github.InstallDir = installDir
+	})
+	t.Setenv("TMPDIR", filepath.Join(root, "tmp"))
+
+	return filepath.Join(root, "project")
+}
+
+func run(t *testing.T, args []string) (string, error) {
+	t.Helper()
+	var stdout bytes.Buffer
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		return testutil.MockGlobalData(args, &stdout), nil
+	}
+	err := app.Run(args, nil)
+	t.Log(stdout.String())
+	return stdout.String(), err
+}
+
+func TestCacheSize(t *testing.T) {
+	project := setupTree(t)
+
+	out, err := run(t, testutil.Args("compute cache size --dir "+project))
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, out, "project  build")
+	testutil.AssertStringContains(t, out, "2.5 KB")
+	testutil.AssertStringContains(t, out, "global   updates")
+	testutil.AssertStringContains(t, out, "global   viceroy")
+	testutil.AssertStringContains(t, out, "30.0 KB")
+	testutil.AssertStringContains(t, out, "Total: 33.5 KB")
+	testutil.AssertStringDoesntContain(t, out, "starter-kits")
+}
+
+func TestCachePrune(t *testing.T) {
+	project := setupTree(t)
+	args := testutil.Args
+
+	_, err := run(t, args("compute cache prune --dir "+project))
+	testutil.AssertErrorContains(t, err, "nothing selected to prune")
+
+	out, err := run(t, args("compute cache prune --older-than 24h --dry-run --dir "+project))
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, out, "Dry run: 3 artifact(s) would be deleted, reclaiming 33.0 KB")
+	testutil.AssertStringContains(t, out, filepath.Join(project, "bin", "main.wasm"))
+	if _, err := os.Stat(filepath.Join(project, "bin", "main.wasm")); err != nil {
+		t.Fatalf("dry run deleted a file: %s", err)
+	}
+
+	out, err = run(t, args("compute cache prune --older-than 24h --dir "+project))
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, out, "Deleted 3 artifact(s), reclaiming 33.0 KB")
+	for _, p := range []string{"bin/main.wasm", "../install/viceroy", "../tmp/fastly-update123"} {
+		if _, err := os.Stat(filepath.Join(project, filepath.FromSlash(p))); !os.IsNotExist(err) {
+			t.Errorf("%s wasn't deleted", p)
+		}
+	}
+	for _, p := range []string{"fastly.toml", "src/main.rs", "pkg/project.tar.gz"} {
+		if _, err := os.Stat(filepath.Join(project, filepath.FromSlash(p))); err != nil {
+			t.Errorf("%s was deleted", p)
+		}
+	}
+
+	out, err = run(t, args("compute cache prune --all --dir "+project))
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, out, "Deleted 1 artifact(s), reclaiming 500.0 B")
+}
@@ -0,0 +1,4 @@
+// Package cache contains commands to inspect and prune the files the CLI
+// generates or downloads for Compute projects (build output, Viceroy binaries,
+// starter kit clones, update downloads).
+package cache
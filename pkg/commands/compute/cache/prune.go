@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/artifacts"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// PruneCommand deletes local artifacts.
+type PruneCommand struct {
+	argparser.Base
+
+	all       bool
+	dir       string
+	dryRun    bool
+	olderThan time.Duration
+}
+
+// NewPruneCommand returns a usable command registered under the parent.
+func NewPruneCommand(parent argparser.Registerer, g *global.Data) *PruneCommand {
+	c := PruneCommand{
+		Base: argparser.Base{
+			Globals: g,
+		},
+	}
+	c.CmdClause = parent.Command("prune", "Delete build artifacts, starter kit clones, Viceroy binaries and update downloads (source files are never deleted)")
+
+	// Optional.
+	c.CmdClause.Flag("all", "Delete all artifacts regardless of age").BoolVar(&c.all)
+	c.CmdClause.Flag("dir", "Project directory (default: current directory)").Short('C').StringVar(&c.dir)
+	c.CmdClause.Flag("dry-run", "Display what would be deleted without deleting anything").BoolVar(&c.dryRun)
+	c.CmdClause.Flag("older-than", "Delete artifacts not modified within this duration (e.g. 168h)").DurationVar(&c.olderThan)
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *PruneCommand) Exec(_ io.Reader, out io.Writer) error {
+	if !c.all && c.olderThan <= 0 {
+		return fsterr.RemediationError{
+			Inner:       errors.New("nothing selected to prune"),
+			Remediation: "Set --older-than (e.g. --older-than 168h) to delete artifacts that haven't been modified recently, or --all to delete everything. Use --dry-run to preview.",
+		}
+	}
+
+	l, err := locations(c.dir)
+	if err != nil {
+		return err
+	}
+	entries, err := artifacts.Find(l)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	pruned, err := artifacts.Prune(entries, artifacts.PruneOptions{
+		All:       c.all,
+		DryRun:    c.dryRun,
+		Now:       time.Now(),
+		OlderThan: c.olderThan,
+	})
+	for _, e := range pruned {
+		if c.dryRun || c.Globals.Verbose() {
+			fmt.Fprintf(out, "%s\t%s\t%s\n", e.Category, text.Bytes(float64(e.Size)), e.Path)
+		}
+	}
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fsterr.RemediationError{
+			Inner:       err,
+			Remediation: fsterr.HostRemediation,
+		}
+	}
+
+	reclaimed := text.Bytes(float64(artifacts.Total(pruned)))
+	if c.dryRun {
+		text.Break(out)
+		text.Info(out, "Dry run: %d artifact(s) would be deleted, reclaiming %s", len(pruned), reclaimed)
+		return nil
+	}
+	text.Success(out, "Deleted %d artifact(s), reclaiming %s", len(pruned), reclaimed)
+	return nil
+}
@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/artifacts"
+	"github.com/fastly/cli/pkg/github"
+	"github.com/fastly/cli/pkg/global"
+)
+
+// RootCommand is the parent command for all subcommands in this package.
+// It should be installed under the primary root command.
+type RootCommand struct {
+	argparser.Base
+	// no flags
+}
+
+// NewRootCommand returns a new command registered in the parent.
+func NewRootCommand(parent argparser.Registerer, g *global.Data) *RootCommand {
+	var c RootCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("cache", "Inspect and prune local build artifacts and downloaded tools")
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *RootCommand) Exec(_ io.Reader, _ io.Writer) error {
+	panic("unreachable")
+}
+
+// locations returns the directories to search for artifacts, with dir as the
+// project directory (the current directory when empty).
+func locations(dir string) (artifacts.Locations, error) {
+	if dir == "" {
+		dir = "."
+	}
+	projectDir, err := filepath.Abs(dir)
+	if err != nil {
+		return artifacts.Locations{}, err
+	}
+	return artifacts.Locations{
+		ProjectDir: projectDir,
+		InstallDir: github.InstallDir,
+		TempDir:    os.TempDir(),
+	}, nil
+}
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/artifacts"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// SizeCommand reports the disk usage of local artifacts.
+type SizeCommand struct {
+	argparser.Base
+	argparser.JSONOutput
+
+	dir string
+}
+
+// NewSizeCommand returns a usable command registered under the parent.
+func NewSizeCommand(parent argparser.Registerer, g *global.Data) *SizeCommand {
+	c := SizeCommand{
+		Base: argparser.Base{
+			Globals: g,
+		},
+	}
+	c.CmdClause = parent.Command("size", "Report the disk usage of build artifacts, starter kit clones, Viceroy binaries and update downloads")
+
+	// Optional.
+	c.CmdClause.Flag("dir", "Project directory (default: current directory)").Short('C').StringVar(&c.dir)
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *SizeCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	l, err := locations(c.dir)
+	if err != nil {
+		return err
+	}
+	entries, err := artifacts.Find(l)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	usages := artifacts.Summarise(entries)
+
+	if ok, err := c.WriteJSON(out, usages); ok {
+		return err
+	}
+
+	if len(usages) == 0 {
+		text.Info(out, "No local artifacts found")
+		return nil
+	}
+	tw := text.NewTable(out)
+	tw.AddHeader("SCOPE", "CATEGORY", "ENTRIES", "SIZE")
+	for _, u := range usages {
+		tw.AddLine(u.Scope, u.Category, strconv.Itoa(u.Entries), text.Bytes(float64(u.Size)))
+	}
+	tw.Print()
+
+	if c.Globals.Verbose() {
+		text.Break(out)
+		for _, e := range entries {
+			fmt.Fprintf(out, "%s\t%s\n", text.Bytes(float64(e.Size)), e.Path)
+		}
+	}
+	text.Break(out)
+	text.Output(out, "Total: %s", text.Bytes(float64(artifacts.Total(entries))))
+	return nil
+}
@@ -39,6 +39,10 @@ func createConfigStoreOK(i *fastly.CreateConfigStoreInput) (*fastly.ConfigStore,
 	}, nil
 }
 
+func createConfigStoreError(_ *fastly.CreateConfigStoreInput) (*fastly.ConfigStore, error) {
+	return nil, testutil.Err
+}
+
 func updateConfigStoreItemOK(i *fastly.UpdateConfigStoreItemInput) (*fastly.ConfigStoreItem, error) {
 	return &fastly.ConfigStoreItem{
 		Key:   i.Key,
@@ -123,6 +127,10 @@ func listKVStoresEmpty(_ *fastly.ListKVStoresInput) (*fastly.ListKVStoresRespons
 	return &fastly.ListKVStoresResponse{}, nil
 }
 
+func listKVStoresError(_ *fastly.ListKVStoresInput) (*fastly.ListKVStoresResponse, error) {
+	return nil, testutil.Err
+}
+
 func getKVStoreOk(_ *fastly.GetKVStoreInput) (*fastly.KVStore, error) {
 	return &fastly.KVStore{
 		StoreID: "123",
@@ -1,10 +1,16 @@
 package compute_test
 
 import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/fastly/kingpin"
 	"github.com/mholt/archiver/v3"
@@ -98,14 +104,20 @@ func TestFlagDivergenceServe(t *testing.T) {
 	// Some flags on `compute serve` are unique to it.
 	// We only want to be sure serve contains all build flags.
 	ignoreServeFlags := []string{
+		"access-log",
+		"access-log-file",
 		"addr",
 		"debug",
+		"env-var",
 		"file",
 		"profile-guest",
 		"profile-guest-dir",
+		"replay",
+		"run-hooks",
 		"skip-build",
 		"viceroy-check",
 		"viceroy-path",
+		"wait-timeout",
 		"watch",
 		"watch-dir",
 	}
@@ -158,6 +170,7 @@ func TestFlagDivergenceHashSum(t *testing.T) {
 	// We only want to be sure hashsum contains all build flags.
 	ignoreHashsumFlags := []string{
 		"package",
+		"run-hooks",
 		"skip-build",
 	}
 
@@ -209,6 +222,7 @@ func TestFlagDivergenceHashFiles(t *testing.T) {
 	// We only want to be sure hashsum contains all build flags.
 	ignoreHashfilesFlags := []string{
 		"package",
+		"run-hooks",
 		"skip-build",
 	}
 
@@ -292,6 +306,120 @@ func TestCreatePackageArchive(t *testing.T) {
 	testutil.AssertEqual(t, wantFiles, files)
 }
 
+func TestCreateReproduciblePackageArchive(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootdir := testutil.NewEnv(testutil.EnvOpts{
+		T: t,
+		Copy: []testutil.FileIO{
+			{Src: filepath.Join("testdata", "build", "rust", "Cargo.lock"), Dst: "Cargo.lock"},
+			{Src: filepath.Join("testdata", "build", "rust", "Cargo.toml"), Dst: "Cargo.toml"},
+			{Src: filepath.Join("testdata", "build", "rust", "src", "main.rs"), Dst: filepath.Join("src", "main.rs")},
+		},
+	})
+	defer os.RemoveAll(rootdir)
+
+	if err := os.Chdir(rootdir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.Chdir(pwd)
+	}()
+
+	files := []string{"Cargo.toml", "Cargo.lock", "src/main.rs"}
+	mtime := time.Unix(1700000000, 0).UTC()
+
+	// NOTE: The second build happens after the source files are touched, so
+	// the packages only match if the file timestamps aren't recorded.
+	err = compute.CreateReproduciblePackageArchive(files, filepath.Join("first", "cli.tar.gz"), mtime)
+	testutil.AssertNoError(t, err)
+	later := time.Now().Add(time.Hour)
+	for _, f := range files {
+		if err := os.Chtimes(f, later, later); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = compute.CreateReproduciblePackageArchive(files, filepath.Join("second", "cli.tar.gz"), mtime)
+	testutil.AssertNoError(t, err)
+
+	testutil.AssertEqual(t, sha256File(t, filepath.Join("first", "cli.tar.gz")), sha256File(t, filepath.Join("second", "cli.tar.gz")))
+
+	f, err := os.Open(filepath.Join("first", "cli.tar.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, hdr.Name)
+		if !hdr.ModTime.Equal(mtime) {
+			t.Errorf("%s: want mtime %s, have %s", hdr.Name, mtime, hdr.ModTime)
+		}
+		if hdr.Uid != 0 || hdr.Gid != 0 || hdr.Uname != "" || hdr.Gname != "" {
+			t.Errorf("%s: want no ownership, have %d/%d (%s/%s)", hdr.Name, hdr.Uid, hdr.Gid, hdr.Uname, hdr.Gname)
+		}
+	}
+	wantNames := []string{"cli/", "cli/Cargo.lock", "cli/Cargo.toml", "cli/src/", "cli/src/main.rs"}
+	testutil.AssertEqual(t, wantNames, names)
+}
+
+func TestSourceDateEpoch(t *testing.T) {
+	for _, testcase := range []struct {
+		value     string
+		wantTime  time.Time
+		wantError string
+	}{
+		{
+			value:    "",
+			wantTime: time.Unix(0, 0).UTC(),
+		},
+		{
+			value:    "1700000000",
+			wantTime: time.Unix(1700000000, 0).UTC(),
+		},
+		{
+			value:     "yesterday",
+			wantError: "invalid SOURCE_DATE_EPOCH value 'yesterday'",
+		},
+		{
+			value:     "-1",
+			wantError: "invalid SOURCE_DATE_EPOCH value '-1'",
+		},
+	} {
+		t.Run(testcase.value, func(t *testing.T) {
+			have, err := compute.SourceDateEpoch(testcase.value)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if testcase.wantError == "" {
+				testutil.AssertEqual(t, testcase.wantTime, have)
+			}
+		})
+	}
+}
+
+func sha256File(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
 func TestFileNameWithoutExtension(t *testing.T) {
 	for _, testcase := range []struct {
 		input      string
@@ -1,17 +1,16 @@
 package compute
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"net/http"
 	"os"
-	"os/signal"
 	"path/filepath"
 	"strconv"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/fastly/go-fastly/v9/fastly"
@@ -22,11 +21,14 @@ import (
 	"github.com/fastly/cli/pkg/api/undocumented"
 	"github.com/fastly/cli/pkg/argparser"
 	"github.com/fastly/cli/pkg/commands/compute/setup"
+	"github.com/fastly/cli/pkg/env"
 	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/events"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/lookup"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/cli/pkg/timing"
 	"github.com/fastly/cli/pkg/undo"
 )
 
@@ -41,21 +43,32 @@ var ErrPackageUnchanged = errors.New("package is unchanged")
 // DeployCommand deploys an artifact previously produced by build.
 type DeployCommand struct {
 	argparser.Base
+	argparser.JSONOutput
+	argparser.NextSteps
 	manifestPath string
+	// phase records the duration of the deploy steps.
+	phase *timing.Span
 
 	// NOTE: these are public so that the "publish" composite command can set the
 	// values appropriately before calling the Exec() function.
+	AcceptRemote       bool
+	AllowABIMismatch   bool
 	Comment            argparser.OptionalString
 	Dir                string
 	Domain             string
 	Env                string
 	PackagePath        string
+	PushLocal          bool
 	ServiceName        argparser.OptionalServiceNameID
 	ServiceVersion     argparser.OptionalServiceVersion
 	StatusCheckCode    int
 	StatusCheckOff     bool
 	StatusCheckPath    string
 	StatusCheckTimeout int
+	VCSGuards          VCSGuards
+	// VCSStatus is the result of the VCS guards (set by publish, which checks
+	// them before building the package).
+	VCSStatus *VCSStatus
 }
 
 // NewDeployCommand returns a usable command registered under the parent.
@@ -84,11 +97,17 @@ func NewDeployCommand(parent argparser.Registerer, g *global.Data) *DeployComman
 		Dst:         &c.ServiceVersion.Value,
 		Name:        argparser.FlagVersionName,
 	})
+	c.CmdClause.Flag("accept-remote", acceptRemoteDesc).BoolVar(&c.AcceptRemote)
+	c.CmdClause.Flag("allow-abi-mismatch", allowABIMismatchDesc).BoolVar(&c.AllowABIMismatch)
 	c.CmdClause.Flag("comment", "Human-readable comment").Action(c.Comment.Set).StringVar(&c.Comment.Value)
 	c.CmdClause.Flag("dir", "Project directory (default: current directory)").Short('C').StringVar(&c.Dir)
 	c.CmdClause.Flag("domain", "The name of the domain associated to the package").StringVar(&c.Domain)
 	c.CmdClause.Flag("env", "The manifest environment config to use (e.g. 'stage' will attempt to read 'fastly.stage.toml')").StringVar(&c.Env)
+	c.RegisterFlagBool(c.JSONFlag()) // --json
 	c.CmdClause.Flag("package", "Path to a package tar.gz").Short('p').StringVar(&c.PackagePath)
+	c.CmdClause.Flag("push-local", pushLocalDesc).BoolVar(&c.PushLocal)
+	c.CmdClause.Flag("require-branch", requireBranchDesc).StringVar(&c.VCSGuards.RequireBranch)
+	c.CmdClause.Flag("require-clean", requireCleanDesc).BoolVar(&c.VCSGuards.RequireClean)
 	c.CmdClause.Flag("status-check-code", "Set the expected status response for the service availability check").IntVar(&c.StatusCheckCode)
 	c.CmdClause.Flag("status-check-off", "Disable the service availability check").BoolVar(&c.StatusCheckOff)
 	c.CmdClause.Flag("status-check-path", "Specify the URL path for the service availability check").Default("/").StringVar(&c.StatusCheckPath)
@@ -96,8 +115,23 @@ func NewDeployCommand(parent argparser.Registerer, g *global.Data) *DeployComman
 	return &c
 }
 
+// Interruptible implements the argparser.Interruptible interface. An interrupted
+// deploy stops between steps and reports what was left behind.
+func (c *DeployCommand) Interruptible() bool {
+	return true
+}
+
 // Exec implements the command interface.
 func (c *DeployCommand) Exec(in io.Reader, out io.Writer) (err error) {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+	result := out
+	out = c.ProgressOutput(c.Globals, out)
+
+	c.phase = c.Globals.Timings.Start("deploy")
+	defer c.phase.End()
+
 	manifestFilename := EnvironmentManifest(c.Env)
 	if c.Env != "" {
 		if c.Globals.Verbose() {
@@ -124,6 +158,14 @@ func (c *DeployCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		c.manifestPath = filepath.Join(projectDir, manifestFilename)
 	}
 
+	if c.VCSStatus == nil {
+		c.VCSStatus, err = c.VCSGuards.Check(c.Globals.ExecuteGit)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+	}
+
 	spinner, err := text.NewSpinner(out)
 	if err != nil {
 		return err
@@ -169,6 +211,44 @@ func (c *DeployCommand) Exec(in io.Reader, out io.Writer) (err error) {
 	}
 	noExistingService := serviceID == ""
 
+	var sr ServiceResources
+
+	c.Globals.Events.Emit(events.DeployStarted, events.Fields{"service_id": serviceID})
+	var deployed events.Fields
+	defer func() {
+		if err != nil {
+			failed := events.Fields{"error": err.Error()}
+			if sr.report != nil {
+				failed["resources"] = sr.report.Resources
+			}
+			c.Globals.Events.Emit(events.DeployFailed, failed)
+			return
+		}
+		if deployed == nil {
+			deployed = events.Fields{"service_id": serviceID, "skipped": true}
+		}
+		c.Globals.Events.Emit(events.DeployFinished, deployed)
+	}()
+
+	// NOTE: These are deferred before the undo stack so that they run after any
+	// clean-up, and can then summarise what was (and wasn't) completed and add
+	// the deploy's context to the error.
+	var progress deployProgress
+	defer func() {
+		switch {
+		case err == nil || serviceID == "":
+		case progress.version == 0:
+			err = fsterr.WithContext(err, fsterr.PrefixContext("while deploying service %s", serviceID))
+		default:
+			err = fsterr.WithContext(err, fsterr.PrefixContext("while deploying service %s version %d", serviceID, progress.version))
+		}
+	}()
+	defer func() {
+		if err != nil && c.Globals.Context.Err() != nil {
+			err = progress.interrupted(serviceID, noExistingService)
+		}
+	}()
+
 	undoStack := undo.NewStack()
 	undoStack.Push(func() error {
 		if noExistingService && serviceID != "" {
@@ -184,10 +264,6 @@ func (c *DeployCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		undoStack.RunIfError(out, err)
 	}(c.Globals.ErrLog)
 
-	signalCh := make(chan os.Signal, 1)
-	signal.Notify(signalCh, syscall.SIGINT, syscall.SIGTERM)
-	go monitorSignals(signalCh, noExistingService, out, undoStack, spinner)
-
 	var serviceVersion *fastly.Version
 	if noExistingService {
 		serviceID, serviceVersion, err = c.NewService(manifestFilename, fnActivateTrial, spinner, in, out)
@@ -200,9 +276,18 @@ func (c *DeployCommand) Exec(in io.Reader, out io.Writer) (err error) {
 	} else {
 		// ErrPackageUnchanged is returned AFTER identifying the service version.
 		// nosemgrep: trailofbits.go.invalid-usage-of-modified-variable.invalid-usage-of-modified-variable
-		serviceVersion, err = c.ExistingServiceVersion(serviceID, out)
+		serviceVersion, err = c.ExistingServiceVersion(serviceID, in, out)
 		if err != nil {
 			if errors.Is(err, ErrPackageUnchanged) {
+				if ok, err := c.WriteJSON(result, deployResult{
+					ServiceID: serviceID,
+					Version:   fastly.ToValue(serviceVersion.Number),
+					Skipped:   true,
+					VCS:       c.VCSStatus,
+					NextSteps: c.NextStepsJSON(),
+				}); ok {
+					return err
+				}
 				text.Info(out, "Skipping package deployment, local and service version are identical. (service %s, version %d) ", serviceID, serviceVersion.Number)
 				return nil
 			}
@@ -213,7 +298,10 @@ func (c *DeployCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		}
 	}
 
-	var sr ServiceResources
+	progress.version = fastly.ToValue(serviceVersion.Number)
+	if err = c.Globals.Context.Err(); err != nil {
+		return err
+	}
 
 	// NOTE: A 'domain' resource isn't strictly part of the [setup] config.
 	// It's part of the implementation so that we can utilise the same interface.
@@ -249,7 +337,7 @@ func (c *DeployCommand) Exec(in io.Reader, out io.Writer) (err error) {
 	}
 	if noExistingService {
 		if err = c.ConfigureServiceResources(sr, serviceID, serviceVersionNumber); err != nil {
-			return err
+			return fsterr.WithContext(err, fsterr.PrefixContext("while configuring the [setup] resources"))
 		}
 	}
 
@@ -268,23 +356,45 @@ func (c *DeployCommand) Exec(in io.Reader, out io.Writer) (err error) {
 	}
 	if noExistingService {
 		if err = c.CreateServiceResources(sr, spinner, serviceID, serviceVersionNumber); err != nil {
-			return err
+			return fsterr.WithContext(err, fsterr.PrefixContext("while creating the [setup] resources"))
 		}
 	}
+	if err = c.Globals.Context.Err(); err != nil {
+		return err
+	}
 
+	upload := c.phase.Start("upload")
+	size, _ := packageSize(c.PackagePath)
+	c.Globals.Events.Emit(events.UploadStarted, events.Fields{
+		"service_id": serviceID,
+		"version":    serviceVersionNumber,
+		"package":    c.PackagePath,
+		"size_bytes": size,
+	})
 	err = c.UploadPackage(spinner, serviceID, serviceVersionNumber)
+	upload.End()
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
 			"Package path":    c.PackagePath,
 			"Service ID":      serviceID,
 			"Service Version": serviceVersion,
 		})
+		return fsterr.WithContext(err, fsterr.PrefixContext("while uploading package %s", c.PackagePath))
+	}
+	progress.uploaded = true
+	c.Globals.Events.Emit(events.UploadFinished, events.Fields{"service_id": serviceID, "version": serviceVersionNumber})
+	if err = c.Globals.Context.Err(); err != nil {
 		return err
 	}
 
-	if err = c.ProcessService(serviceID, serviceVersionNumber, spinner); err != nil {
-		return err
+	activation := c.phase.Start("activation")
+	c.Globals.Events.Emit(events.ActivationStarted, events.Fields{"service_id": serviceID, "version": serviceVersionNumber})
+	err = c.ProcessService(serviceID, serviceVersionNumber, spinner)
+	activation.End()
+	if err != nil {
+		return fsterr.WithContext(err, fsterr.PrefixContext("while activating the service version"))
 	}
+	c.Globals.Events.Emit(events.ActivationDone, events.Fields{"service_id": serviceID, "version": serviceVersionNumber})
 
 	serviceURL, err := c.GetServiceURL(serviceID, serviceVersionNumber)
 	if err != nil {
@@ -292,14 +402,42 @@ func (c *DeployCommand) Exec(in io.Reader, out io.Writer) (err error) {
 	}
 
 	if !c.StatusCheckOff && noExistingService {
+		check := c.phase.Start("availability check")
 		c.StatusCheck(serviceURL, spinner, out)
+		check.End()
 	}
 
 	if !noExistingService {
 		text.Break(out)
 	}
-	displayDeployOutput(out, manageServiceBaseURL, serviceID, serviceURL, serviceVersionNumber)
-	return nil
+	if skipped := sr.report.Skipped(); len(skipped) > 0 {
+		text.Warning(out, "Deployed without the optional [setup] resource(s): %s\n\n", strings.Join(skipped, ", "))
+	}
+	c.RecordDeploy(out, serviceID, serviceVersionNumber)
+	deployed = events.Fields{"service_id": serviceID, "version": serviceVersionNumber, "url": serviceURL}
+	if sr.report != nil {
+		deployed["resources"] = sr.report.Resources
+	}
+	return c.displayDeployOutput(result, manageServiceBaseURL, serviceID, serviceURL, serviceVersionNumber)
+}
+
+// RecordDeploy records the deploy in the project directory, for use by
+// `compute status`. The deploy has already succeeded, so a failure to record
+// it is only reported as a warning.
+func (c *DeployCommand) RecordDeploy(out io.Writer, serviceID string, serviceVersion int) {
+	filesHash, err := getFilesHash(c.PackagePath)
+	if err == nil {
+		err = WriteDeployState(".", DeployState{
+			DeployedAt:     time.Now().UTC(),
+			PackageHash:    filesHash,
+			ServiceID:      serviceID,
+			ServiceVersion: serviceVersion,
+		})
+	}
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		text.Warning(out, "Failed to record the deploy for `fastly compute status`: %s", err)
+	}
 }
 
 // StatusCheck checks the service URL and identifies when it's ready.
@@ -330,10 +468,41 @@ func (c *DeployCommand) StatusCheck(serviceURL string, spinner text.Spinner, out
 	}
 }
 
-func displayDeployOutput(out io.Writer, manageServiceBaseURL, serviceID, serviceURL string, serviceVersion int) {
+func (c *DeployCommand) displayDeployOutput(out io.Writer, manageServiceBaseURL, serviceID, serviceURL string, serviceVersion int) error {
+	c.AddNextStep("View the service", "", serviceURL)
+	c.AddNextStep("Tail the service's logs", fmt.Sprintf("fastly log-tail --service-id %s", serviceID), "")
+
+	if ok, err := c.WriteJSON(out, deployResult{
+		ServiceID: serviceID,
+		Version:   serviceVersion,
+		URL:       serviceURL,
+		ManageURL: manageServiceBaseURL + serviceID,
+		VCS:       c.VCSStatus,
+		NextSteps: c.NextStepsJSON(),
+	}); ok {
+		return err
+	}
+
+	if c.VCSStatus != nil {
+		text.Description(out, "Deployed from git", c.VCSStatus.String())
+	}
+
 	text.Description(out, "Manage this service at", fmt.Sprintf("%s%s", manageServiceBaseURL, serviceID))
-	text.Description(out, "View this service at", serviceURL)
 	text.Success(out, "Deployed package (service %s, version %v)", serviceID, serviceVersion)
+	text.Break(out)
+	c.PrintNextSteps(out)
+	return nil
+}
+
+// deployResult is the JSON output of a deploy.
+type deployResult struct {
+	ServiceID string          `json:"service_id"`
+	Version   int             `json:"version"`
+	URL       string          `json:"url,omitempty"`
+	ManageURL string          `json:"manage_url,omitempty"`
+	Skipped   bool            `json:"skipped,omitempty"`
+	VCS       *VCSStatus      `json:"vcs,omitempty"`
+	NextSteps []text.NextStep `json:"next_steps"`
 }
 
 // validStatusCodeRange checks the status is a valid status code.
@@ -381,6 +550,12 @@ func (c *DeployCommand) Setup(out io.Writer) (fnActivateTrial Activator, service
 		})
 		return defaultActivator, serviceID, err
 	}
+	if err = c.checkABI(out); err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Package path": c.PackagePath,
+		})
+		return defaultActivator, serviceID, err
+	}
 
 	endpoint, _ := c.Globals.APIEndpoint()
 	fnActivateTrial = preconfigureActivateTrial(endpoint, token, c.Globals.HTTPClient, c.Globals.Env.DebugMode)
@@ -388,6 +563,26 @@ func (c *DeployCommand) Setup(out io.Writer) (fnActivateTrial Activator, service
 	return fnActivateTrial, serviceID, err
 }
 
+// checkABI checks the package conforms to the Compute ABI, so a package built
+// for the wrong target is refused before it's uploaded (rather than failing to
+// activate). With --allow-abi-mismatch the problems are only warned about.
+func (c *DeployCommand) checkABI(out io.Writer) error {
+	problems, err := checkPackageABI(c.PackagePath, computeABI(c.Globals))
+	if err != nil || len(problems) == 0 {
+		return err
+	}
+	if c.AllowABIMismatch {
+		for _, p := range problems {
+			text.Warning(out, "%s.", p.Problem)
+		}
+		text.Break(out)
+		return nil
+	}
+	re := abiError(problems)
+	re.Remediation += "\n\nTo upload the package anyway, set --allow-abi-mismatch."
+	return re
+}
+
 // validatePackage checks the package and returns its path, which can change
 // depending on the user flow scenario.
 func validatePackage(pkgPath string) error {
@@ -400,8 +595,12 @@ func validatePackage(pkgPath string) error {
 	}
 	if pkgSize > MaxPackageSize {
 		return fsterr.RemediationError{
-			Inner:       fmt.Errorf("package size is too large (%d bytes)", pkgSize),
-			Remediation: fsterr.PackageSizeRemediation,
+			Inner: fmt.Errorf("package size is too large (%d bytes)", pkgSize),
+			Remediation: fsterr.RenderRemediation(fsterr.RemediationPackageSize, map[string]any{
+				"Size":  text.Bytes(float64(pkgSize)),
+				"Limit": text.Bytes(float64(MaxPackageSize)),
+			}),
+			Code: fsterr.CodePackageSize,
 		}
 	}
 	return validatePackageContent(pkgPath)
@@ -541,7 +740,7 @@ func (c *DeployCommand) NewService(manifestFilename string, fnActivateTrial Acti
 		}
 
 		text.Break(out)
-		answer, err := text.AskYesNo(out, "Create new service: [y/N] ", in)
+		answer, err := text.AskYesNo(out, "Create new service: [y/N] ", in, "--auto-yes")
 		if err != nil {
 			return serviceID, serviceVersion, err
 		}
@@ -563,7 +762,7 @@ func (c *DeployCommand) NewService(manifestFilename string, fnActivateTrial Acti
 	case c.Globals.Flags.AcceptDefaults || c.Globals.Flags.NonInteractive:
 		serviceName = defaultServiceName
 	default:
-		serviceName, err = text.Input(out, text.Prompt(fmt.Sprintf("Service name: [%s] ", defaultServiceName)), in)
+		serviceName, err = text.Input(out, text.Prompt(fmt.Sprintf("Service name: [%s] ", defaultServiceName)), in, "--service-name")
 		if err != nil || serviceName == "" {
 			serviceName = defaultServiceName
 		}
@@ -572,7 +771,7 @@ func (c *DeployCommand) NewService(manifestFilename string, fnActivateTrial Acti
 	// There is no service and so we'll do a one time creation of the service
 	//
 	// NOTE: we're shadowing the `serviceID` and `serviceVersion` variables.
-	serviceID, serviceVersion, err = createService(c.Globals, serviceName, fnActivateTrial, spinner, out)
+	serviceID, serviceVersion, err = createService(c.Globals, serviceName, fnActivateTrial, spinner, in, out)
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
 			"Service name": serviceName,
@@ -604,12 +803,14 @@ func (c *DeployCommand) NewService(manifestFilename string, fnActivateTrial Acti
 // createService creates a service to associate with the compute package.
 //
 // NOTE: If the creation of the service fails because the user has not
-// activated a free trial, then we'll trigger the trial for their account.
+// activated a free trial, then we'll offer to trigger the trial for their
+// account and create the service once it's enabled (see activateTrial).
 func createService(
 	g *global.Data,
 	serviceName string,
 	fnActivateTrial Activator,
 	spinner text.Spinner,
+	in io.Reader,
 	out io.Writer,
 ) (serviceID string, serviceVersion *fastly.Version, err error) {
 	f := g.Flags
@@ -627,61 +828,26 @@ func createService(
 	msg := "Creating service"
 	spinner.Message(msg + "...")
 
-	service, err := apiClient.CreateService(&fastly.CreateServiceInput{
+	input := &fastly.CreateServiceInput{
 		Name: &serviceName,
 		Type: fastly.ToPointer("wasm"),
-	})
+	}
+	service, err := apiClient.CreateService(input)
 	if err != nil {
-		if strings.Contains(err.Error(), trialNotActivated) {
-			user, err := apiClient.GetCurrentUser()
-			if err != nil {
-				err = fmt.Errorf("unable to identify user associated with the given token: %w", err)
-				spinner.StopFailMessage(msg)
-				spinErr := spinner.StopFail()
-				if spinErr != nil {
-					return "", nil, fmt.Errorf(text.SpinnerErrWrapper, spinErr, err)
-				}
-				return serviceID, serviceVersion, fsterr.RemediationError{
-					Inner:       err,
-					Remediation: "To ensure you have access to the Compute platform we need your Customer ID. " + fsterr.AuthRemediation,
-				}
-			}
-
-			customerID := fastly.ToValue(user.CustomerID)
-			err = fnActivateTrial(customerID)
-			if err != nil {
-				err = fmt.Errorf("error creating service: you do not have the Compute free trial enabled on your Fastly account")
-				spinner.StopFailMessage(msg)
-				spinErr := spinner.StopFail()
-				if spinErr != nil {
-					return "", nil, fmt.Errorf(text.SpinnerErrWrapper, spinErr, err)
-				}
-				return serviceID, serviceVersion, fsterr.RemediationError{
-					Inner:       err,
-					Remediation: fsterr.ComputeTrialRemediation,
-				}
-			}
-
-			errLog.AddWithContext(err, map[string]any{
-				"Service Name": serviceName,
-				"Customer ID":  customerID,
-			})
-
-			spinner.StopFailMessage(msg)
-			err = spinner.StopFail()
-			if err != nil {
-				return "", nil, err
-			}
-
-			return createService(g, serviceName, fnActivateTrial, spinner, out)
-		}
-
 		spinner.StopFailMessage(msg)
 		spinErr := spinner.StopFail()
 		if spinErr != nil {
 			return "", nil, spinErr
 		}
 
+		if isTrialNotActivated(err) {
+			service, err = activateTrial(g, input, fnActivateTrial, spinner, in, out)
+			if err != nil {
+				return serviceID, serviceVersion, err
+			}
+			return fastly.ToValue(service.ServiceID), &fastly.Version{Number: fastly.ToPointer(1)}, nil
+		}
+
 		errLog.AddWithContext(err, map[string]any{
 			"Service Name": serviceName,
 		})
@@ -696,6 +862,134 @@ func createService(
 	return fastly.ToValue(service.ServiceID), &fastly.Version{Number: fastly.ToPointer(1)}, nil
 }
 
+// TrialPollInterval is how often we retry creating the service once the
+// Compute free trial has been requested.
+var TrialPollInterval = 5 * time.Second
+
+// TrialPollTimeout is how long we wait for a requested Compute free trial to be
+// enabled before giving up.
+var TrialPollTimeout = 2 * time.Minute
+
+// isTrialNotActivated reports whether err is the API error returned when
+// creating a Compute service on an account without the free trial enabled.
+func isTrialNotActivated(err error) bool {
+	return err != nil && strings.Contains(err.Error(), trialNotActivated)
+}
+
+// activateTrial requests the Compute free trial for the user's account (after
+// confirming with the user) and then creates the service described by input,
+// retrying until the trial is enabled or TrialPollTimeout is reached.
+//
+// In non-interactive mode the trial isn't requested, instead the returned
+// error describes the API call needed to request it.
+func activateTrial(
+	g *global.Data,
+	input *fastly.CreateServiceInput,
+	fnActivateTrial Activator,
+	spinner text.Spinner,
+	in io.Reader,
+	out io.Writer,
+) (*fastly.Service, error) {
+	user, err := g.APIClient.GetCurrentUser()
+	if err != nil {
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("unable to identify user associated with the given token: %w", err),
+			Remediation: "To ensure you have access to the Compute platform we need your Customer ID. " + fsterr.AuthRemediation,
+		}
+	}
+	customerID := fastly.ToValue(user.CustomerID)
+	errLog := g.ErrLog
+	errContext := map[string]any{
+		"Service Name": fastly.ToValue(input.Name),
+		"Customer ID":  customerID,
+	}
+
+	errNoTrial := errors.New("error creating service: you do not have the Compute free trial enabled on your Fastly account")
+	endpoint, _ := g.APIEndpoint()
+	requestErr := fsterr.RemediationError{
+		Inner:       errNoTrial,
+		Remediation: trialRemediation(endpoint, customerID),
+	}
+
+	if g.Flags.NonInteractive {
+		return nil, requestErr
+	}
+	if !g.Flags.AutoYes {
+		text.Break(out)
+		text.Info(out, "The Compute free trial isn't enabled on your Fastly account. It can be requested now, and the service will be created once it's enabled.")
+		text.Break(out)
+		answer, err := text.AskYesNo(out, "Request the Compute free trial: [y/N] ", in, "--auto-yes")
+		if err != nil {
+			return nil, err
+		}
+		if !answer {
+			return nil, requestErr
+		}
+		text.Break(out)
+	}
+
+	err = spinner.Process("Requesting Compute free trial", func(_ *text.SpinnerWrapper) error {
+		return fnActivateTrial(customerID)
+	})
+	if err != nil {
+		errLog.AddWithContext(err, errContext)
+		return nil, fsterr.RemediationError{
+			Inner:       errNoTrial,
+			Remediation: fsterr.ComputeTrialRemediation,
+		}
+	}
+
+	ctx := g.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timeout := time.After(TrialPollTimeout)
+
+	var service *fastly.Service
+	err = spinner.Process("Creating service", func(sp *text.SpinnerWrapper) error {
+		for {
+			var createErr error
+			service, createErr = g.APIClient.CreateService(input)
+			if createErr == nil {
+				return nil
+			}
+			if !isTrialNotActivated(createErr) {
+				errLog.AddWithContext(createErr, errContext)
+				return fmt.Errorf("error creating service: %w", createErr)
+			}
+			sp.Message("Waiting for the Compute free trial to be enabled...")
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-timeout:
+				errLog.AddWithContext(createErr, errContext)
+				return fsterr.RemediationError{
+					Inner:       fmt.Errorf("%w (timed out after %s waiting for the requested trial)", errNoTrial, TrialPollTimeout),
+					Remediation: "The trial can take a little longer to be enabled, please try again shortly. " + fsterr.ComputeTrialRemediation,
+				}
+			case <-time.After(TrialPollInterval):
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return service, nil
+}
+
+// trialRemediation describes the API call that requests the Compute free trial
+// for the customer, for users who can't (or chose not to) request it via the CLI.
+func trialRemediation(endpoint, customerID string) string {
+	return fmt.Sprintf(strings.Join([]string{
+		"To request the Compute free trial, run the command again without --non-interactive, or call the API directly:",
+		"",
+		"\t$ curl -X POST -H \"Fastly-Key: $%s\" %s%s",
+		"",
+		"%s",
+	}, "\n"), env.APIToken, strings.TrimRight(endpoint, "/"), fmt.Sprintf(undocumented.EdgeComputeTrial, customerID), fsterr.ComputeTrialRemediation)
+}
+
 // CleanupNewService is executed if a new service flow has errors.
 // It deletes the service, which will cause any contained resources to be deleted.
 // It will also strip the Service ID from the fastly.toml manifest file.
@@ -756,7 +1050,9 @@ func errLogService(l fsterr.LogInterface, err error, sid string, sv int) {
 // CLI to add the Service ID. Any subsequent deploys will be aborted because
 // there will be no changes made by the CLI nor the user.
 func (c *DeployCommand) CompareLocalRemotePackage(serviceID string, version int) error {
+	hash := c.phase.Start("package hash")
 	filesHash, err := getFilesHash(c.PackagePath)
+	hash.End()
 	if err != nil {
 		return err
 	}
@@ -793,6 +1089,10 @@ func (c *DeployCommand) UploadPackage(spinner text.Spinner, serviceID string, ve
 // ServiceResources is a collection of backend objects created during setup.
 // Objects may be nil.
 type ServiceResources struct {
+	// report records the outcome of each [setup] store (nil for an existing
+	// service, as [setup] is only processed for a new service).
+	report *setup.Report
+
 	domains      *setup.Domains
 	backends     *setup.Backends
 	configStores *setup.ConfigStores
@@ -811,6 +1111,8 @@ func (c *DeployCommand) ConstructNewServiceResources(
 	in io.Reader,
 	out io.Writer,
 ) {
+	sr.report = &setup.Report{}
+
 	sr.backends = &setup.Backends{
 		APIClient:      c.Globals.APIClient,
 		AcceptDefaults: c.Globals.Flags.AcceptDefaults,
@@ -826,6 +1128,7 @@ func (c *DeployCommand) ConstructNewServiceResources(
 		APIClient:      c.Globals.APIClient,
 		AcceptDefaults: c.Globals.Flags.AcceptDefaults,
 		NonInteractive: c.Globals.Flags.NonInteractive,
+		Report:         sr.report,
 		ServiceID:      serviceID,
 		ServiceVersion: serviceVersion,
 		Setup:          c.Globals.Manifest.File.Setup.ConfigStores,
@@ -842,6 +1145,7 @@ func (c *DeployCommand) ConstructNewServiceResources(
 		APIClient:      c.Globals.APIClient,
 		AcceptDefaults: c.Globals.Flags.AcceptDefaults,
 		NonInteractive: c.Globals.Flags.NonInteractive,
+		Report:         sr.report,
 		ServiceID:      serviceID,
 		ServiceVersion: serviceVersion,
 		Setup:          c.Globals.Manifest.File.Setup.ObjectStores,
@@ -853,6 +1157,7 @@ func (c *DeployCommand) ConstructNewServiceResources(
 		APIClient:      c.Globals.APIClient,
 		AcceptDefaults: c.Globals.Flags.AcceptDefaults,
 		NonInteractive: c.Globals.Flags.NonInteractive,
+		Report:         sr.report,
 		ServiceID:      serviceID,
 		ServiceVersion: serviceVersion,
 		Setup:          c.Globals.Manifest.File.Setup.KVStores,
@@ -864,6 +1169,7 @@ func (c *DeployCommand) ConstructNewServiceResources(
 		APIClient:      c.Globals.APIClient,
 		AcceptDefaults: c.Globals.Flags.AcceptDefaults,
 		NonInteractive: c.Globals.Flags.NonInteractive,
+		Report:         sr.report,
 		ServiceID:      serviceID,
 		ServiceVersion: serviceVersion,
 		Setup:          c.Globals.Manifest.File.Setup.SecretStores,
@@ -1073,9 +1379,13 @@ func checkingServiceAvailability(
 		expected = fmt.Sprintf("%d status code", c.StatusCheckCode)
 	}
 
-	// Keep trying until we're timed out, got a result or got an error
+	// Keep trying until we're timed out, got a result, got an error or the user
+	// interrupted the check (the service is already activated at this point).
 	for {
 		select {
+		case <-c.Globals.Context.Done():
+			spinner.StopFailMessage(msg + " (interrupted)")
+			return status, spinner.StopFail()
 		case <-timeout:
 			err := errors.New("timeout: service not yet available")
 			returnedStatus := fmt.Sprintf(" (status: %d)", status)
@@ -1161,7 +1471,7 @@ func pingServiceURL(serviceURL string, httpClient api.HTTPClient, expectedStatus
 
 // ExistingServiceVersion returns a Service Version for an existing service.
 // If the current service version is active or locked, we clone the version.
-func (c *DeployCommand) ExistingServiceVersion(serviceID string, out io.Writer) (*fastly.Version, error) {
+func (c *DeployCommand) ExistingServiceVersion(serviceID string, in io.Reader, out io.Writer) (*fastly.Version, error) {
 	var (
 		err            error
 		serviceVersion *fastly.Version
@@ -1216,6 +1526,14 @@ func (c *DeployCommand) ExistingServiceVersion(serviceID string, out io.Writer)
 		}
 	}
 
+	// The drift check only applies to the manifest's own service, and not when
+	// deploying elsewhere (e.g. a staging service selected by --service-name).
+	if !c.ServiceName.WasSet && serviceID == c.Globals.Manifest.File.ServiceID {
+		if err = c.CheckDrift(serviceID, serviceDetails, in, out); err != nil {
+			return serviceVersion, err
+		}
+	}
+
 	err = c.CompareLocalRemotePackage(serviceID, serviceVersionNumber)
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
@@ -1230,15 +1548,14 @@ func (c *DeployCommand) ExistingServiceVersion(serviceID string, out io.Writer)
 	// the compute deploy command is a composite of behaviours, and so as we
 	// already automatically activate a version we should autoclone without
 	// requiring the user to explicitly provide an --autoclone flag.
-	if fastly.ToValue(serviceVersion.Active) || fastly.ToValue(serviceVersion.Locked) {
-		clonedVersion, err := c.Globals.APIClient.CloneVersion(&fastly.CloneVersionInput{
-			ServiceID:      serviceID,
-			ServiceVersion: serviceVersionNumber,
-		})
-		if err != nil {
-			errLogService(c.Globals.ErrLog, err, serviceID, serviceVersionNumber)
-			return serviceVersion, fmt.Errorf("error cloning service version: %w", err)
-		}
+	clonedVersion, cloned, err := argparser.EditableVersion(c.Globals.Context, c.Globals.APIClient, serviceID, serviceVersion, argparser.VersionFlags{
+		AutoClone: argparser.OptionalAutoClone{OptionalBool: argparser.OptionalBool{Value: true}},
+	})
+	if err != nil {
+		errLogService(c.Globals.ErrLog, err, serviceID, serviceVersionNumber)
+		return serviceVersion, err
+	}
+	if cloned {
 		if c.Globals.Verbose() {
 			msg := "Service version %d is not editable, so it was automatically cloned. Now operating on version %d.\n\n"
 			format := fmt.Sprintf(msg, serviceVersionNumber, fastly.ToValue(clonedVersion.Number))
@@ -1250,14 +1567,65 @@ func (c *DeployCommand) ExistingServiceVersion(serviceID string, out io.Writer)
 	return serviceVersion, nil
 }
 
-func monitorSignals(signalCh chan os.Signal, noExistingService bool, out io.Writer, undoStack *undo.Stack, spinner text.Spinner) {
-	<-signalCh
-	signal.Stop(signalCh)
-	spinner.StopFailMessage("Signal received to interrupt/terminate the Fastly CLI process")
-	_ = spinner.StopFail()
-	text.Important(out, "\n\nThe Fastly CLI process will be terminated after any clean-up tasks have been processed")
-	if noExistingService {
-		undoStack.Unwind(out)
+// CheckDrift warns when the manifest's name or description differ from the
+// service, and reconciles them by flag or prompt.
+//
+// A deploy isn't stopped by drift it can't prompt about (e.g. in
+// non-interactive mode), so the warning is all that's displayed.
+func (c *DeployCommand) CheckDrift(serviceID string, s *fastly.ServiceDetail, in io.Reader, out io.Writer) error {
+	r := driftReconciler{
+		globals:      c.Globals,
+		acceptRemote: c.AcceptRemote,
+		pushLocal:    c.PushLocal,
+		manifestPath: c.manifestPath,
+		serviceID:    serviceID,
+	}
+	if err := r.validate(); err != nil {
+		return err
+	}
+	drift := DetectDrift(&c.Globals.Manifest.File, serviceID, s)
+	if len(drift) == 0 {
+		return nil
+	}
+	err := r.run(drift, in, out)
+	if errors.Is(err, ErrDriftUnreconciled) {
+		text.Info(out, "Deploying without reconciling: pass --accept-remote or --push-local, or run `fastly compute drift`.")
+		text.Break(out)
+		return nil
+	}
+	return err
+}
+
+// deployProgress records the deploy steps completed before an interruption.
+type deployProgress struct {
+	// version is the service version being deployed (zero if not yet known).
+	version int
+	// uploaded indicates the package was uploaded to the service version.
+	uploaded bool
+}
+
+// interrupted returns an error describing what was completed and what wasn't
+// when the deploy was interrupted (e.g. the user pressed Ctrl-C).
+//
+// NOTE: A new service is deleted by the undo stack, so there's nothing to
+// resume and the user must deploy again.
+func (p deployProgress) interrupted(serviceID string, newService bool) error {
+	inner := errors.New("deploy interrupted before a service version was created")
+	remediation := "Run `fastly compute deploy` again to deploy the package."
+
+	switch {
+	case newService && serviceID != "":
+		inner = fmt.Errorf("deploy interrupted: service %s was created but not activated, and has been deleted", serviceID)
+	case p.version == 0:
+	case p.uploaded:
+		inner = fmt.Errorf("deploy interrupted: version %d created and package uploaded, but not activated", p.version)
+		remediation = fmt.Sprintf("Run `fastly service-version activate --service-id %s --version %d` to activate the version.", serviceID, p.version)
+	default:
+		inner = fmt.Errorf("deploy interrupted: version %d created but not activated (the package was not uploaded)", p.version)
+		remediation = fmt.Sprintf("Run `fastly compute deploy --service-id %s --version %d` to resume the deploy.", serviceID, p.version)
+	}
+	return fsterr.RemediationError{
+		Inner:       inner,
+		Remediation: remediation,
 	}
-	os.Exit(1)
 }
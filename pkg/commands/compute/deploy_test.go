@@ -1,7 +1,10 @@
 package compute_test
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,17 +13,21 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/fastly/go-fastly/v9/fastly"
 
 	"github.com/fastly/cli/pkg/app"
 	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/commands/compute/setup"
 	"github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/events"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/mock"
 	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/text"
 	"github.com/fastly/cli/pkg/threadsafe"
 )
 
@@ -220,7 +227,7 @@ func TestDeploy(t *testing.T) {
 			args:                 args("compute deploy --package pkg/package.tar.gz --token 123"),
 			reduceSizeLimit:      true,
 			wantError:            "package size is too large",
-			wantRemediationError: errors.PackageSizeRemediation,
+			wantRemediationError: "but the limit is 1.0 MB. " + errors.PackageSizeRemediation,
 		},
 		// The following test doesn't just validate the package API error behaviour
 		// but as a side effect it validates that when deleting the created
@@ -308,6 +315,8 @@ func TestDeploy(t *testing.T) {
 			},
 			stdin: []string{
 				"Y", // when prompted to create a new service
+				"",  // when prompted for a service name
+				"Y", // when prompted to request the free trial
 			},
 			wantError:            "error creating service: you do not have the Compute free trial enabled on your Fastly account",
 			wantRemediationError: errors.ComputeTrialRemediation,
@@ -333,6 +342,8 @@ func TestDeploy(t *testing.T) {
 			},
 			stdin: []string{
 				"Y", // when prompted to create a new service
+				"",  // when prompted for a service name
+				"Y", // when prompted to request the free trial
 			},
 			wantError:            "error creating service: you do not have the Compute free trial enabled on your Fastly account",
 			wantRemediationError: errors.ComputeTrialRemediation,
@@ -515,7 +526,8 @@ func TestDeploy(t *testing.T) {
 				"Activating service",
 				"Manage this service at:",
 				"https://manage.fastly.com/configure/services/123",
-				"View this service at:",
+				"Next steps:",
+				"1. View the service:",
 				"https://directly-careful-coyote.edgecompute.app",
 				"Deployed package (service 123, version 4)",
 			},
@@ -547,7 +559,8 @@ func TestDeploy(t *testing.T) {
 				"Activating service",
 				"Manage this service at:",
 				"https://manage.fastly.com/configure/services/123",
-				"View this service at:",
+				"Next steps:",
+				"1. View the service:",
 				"https://directly-careful-coyote.edgecompute.app",
 				"Deployed package (service 123, version 3)",
 			},
@@ -585,7 +598,8 @@ func TestDeploy(t *testing.T) {
 				"Activating service",
 				"Manage this service at:",
 				"https://manage.fastly.com/configure/services/123",
-				"View this service at:",
+				"Next steps:",
+				"1. View the service:",
 				"https://directly-careful-coyote.edgecompute.app",
 				"Deployed package (service 123, version 3)",
 			},
@@ -1875,6 +1889,99 @@ func TestDeploy(t *testing.T) {
 				"SUCCESS: Deployed package (service 12345, version 1)",
 			},
 		},
+		{
+			name: "success with optional setup.kv_stores unavailable and required setup.config_stores",
+			args: args("compute deploy --non-interactive --token 123"),
+			api: mock.API{
+				ActivateVersionFn:       activateVersionOk,
+				CreateBackendFn:         createBackendOK,
+				CreateConfigStoreFn:     createConfigStoreOK,
+				CreateDomainFn:          createDomainOK,
+				CreateResourceFn:        createResourceOK,
+				CreateServiceFn:         createServiceOK,
+				GetPackageFn:            getPackageOk,
+				GetServiceDetailsFn:     getServiceDetailsWasm,
+				GetServiceFn:            getServiceOK,
+				ListConfigStoresFn:      listConfigStoresEmpty,
+				ListDomainsFn:           listDomainsOk,
+				ListKVStoresFn:          listKVStoresError,
+				ListVersionsFn:          testutil.ListVersions,
+				UpdateConfigStoreItemFn: updateConfigStoreItemOK,
+				UpdatePackageFn:         updatePackageOk,
+			},
+			httpClientRes: []*http.Response{
+				{
+					Body:       io.NopCloser(strings.NewReader("success")),
+					Status:     http.StatusText(http.StatusOK),
+					StatusCode: http.StatusOK,
+				},
+			},
+			httpClientErr: []error{
+				nil,
+			},
+			manifest: `
+			name = "package"
+			manifest_version = 2
+			language = "rust"
+
+			[setup.config_stores.example]
+			[setup.config_stores.example.items.foo]
+			value = "my default value for foo"
+
+			[setup.kv_stores.store_one]
+			optional = true
+			[setup.kv_stores.store_one.items.foo]
+			value = "my default value for foo"
+			`,
+			wantOutput: []string{
+				"Skipping optional KV Store 'store_one': test error",
+				"fastly resource-link create",
+				"Creating config store 'example'",
+				"Deployed without the optional [setup] resource(s): store_one",
+				"SUCCESS: Deployed package (service 12345, version 1)",
+			},
+			dontWantOutput: []string{
+				"Creating KV Store 'store_one'",
+			},
+		},
+		{
+			name: "error with required setup.config_stores failing alongside optional setup.kv_stores",
+			args: args("compute deploy --non-interactive --token 123"),
+			api: mock.API{
+				CreateBackendFn:     createBackendOK,
+				CreateConfigStoreFn: createConfigStoreError,
+				CreateDomainFn:      createDomainOK,
+				CreateServiceFn:     createServiceOK,
+				DeleteServiceFn:     deleteServiceOK,
+				GetPackageFn:        getPackageOk,
+				GetServiceDetailsFn: getServiceDetailsWasm,
+				GetServiceFn:        getServiceOK,
+				ListConfigStoresFn:  listConfigStoresEmpty,
+				ListDomainsFn:       listDomainsOk,
+				ListKVStoresFn:      listKVStoresError,
+				ListVersionsFn:      testutil.ListVersions,
+			},
+			manifest: `
+			name = "package"
+			manifest_version = 2
+			language = "rust"
+
+			[setup.config_stores.example]
+			[setup.config_stores.example.items.foo]
+			value = "my default value for foo"
+
+			[setup.kv_stores.store_one]
+			optional = true
+			`,
+			wantOutput: []string{
+				"Skipping optional KV Store 'store_one': test error",
+			},
+			dontWantOutput: []string{
+				"Deployed without the optional [setup] resource(s)",
+				"SUCCESS: Deployed package",
+			},
+			wantError: "error creating config store: test error",
+		},
 		{
 			name: "success with setup.kv_stores configuration and no existing service and no predefined values",
 			args: args("compute deploy --token 123"),
@@ -2239,6 +2346,801 @@ func TestDeploy(t *testing.T) {
 	}
 }
 
+// TestDeployInterrupted validates that cancelling the context (e.g. the user
+// pressing Ctrl-C) mid-deploy cleans up and reports what was completed.
+func TestDeployInterrupted(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootdir := testutil.NewEnv(testutil.EnvOpts{
+		T: t,
+		Copy: []testutil.FileIO{
+			{
+				Src: filepath.Join("testdata", "deploy", "pkg", "package.tar.gz"),
+				Dst: filepath.Join("pkg", "package.tar.gz"),
+			},
+		},
+	})
+	defer os.RemoveAll(rootdir)
+
+	if err := os.Chdir(rootdir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.Chdir(pwd)
+	}()
+
+	args := testutil.Args
+	scenarios := []struct {
+		name string
+		args []string
+		// cancelAfterUpload cancels the context once the package is uploaded,
+		// otherwise the context is cancelled while uploading.
+		cancelAfterUpload    bool
+		existingService      bool
+		wantDeleted          bool
+		wantError            string
+		wantRemediationError string
+	}{
+		{
+			name:                 "new service is deleted",
+			args:                 args("compute deploy --token 123 --package pkg/package.tar.gz"),
+			wantDeleted:          true,
+			wantError:            "deploy interrupted: service 12345 was created but not activated, and has been deleted",
+			wantRemediationError: "Run `fastly compute deploy` again",
+		},
+		{
+			name:                 "existing service interrupted during upload",
+			args:                 args("compute deploy --service-id 123 --token 123 --package pkg/package.tar.gz"),
+			existingService:      true,
+			wantError:            "deploy interrupted: version 4 created but not activated (the package was not uploaded)",
+			wantRemediationError: "fastly compute deploy --service-id 123 --version 4",
+		},
+		{
+			name:                 "existing service interrupted after upload",
+			args:                 args("compute deploy --service-id 123 --token 123 --package pkg/package.tar.gz"),
+			cancelAfterUpload:    true,
+			existingService:      true,
+			wantError:            "deploy interrupted: version 4 created and package uploaded, but not activated",
+			wantRemediationError: "fastly service-version activate --service-id 123 --version 4",
+		},
+	}
+
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			if err := os.WriteFile(filepath.Join(rootdir, manifest.Filename), []byte("manifest_version = 2\nname = \"package\"\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var activated, deleted bool
+			api := mock.API{
+				ActivateVersionFn: func(i *fastly.ActivateVersionInput) (*fastly.Version, error) {
+					activated = true
+					return activateVersionOk(i)
+				},
+				CloneVersionFn:  testutil.CloneVersionResult(4),
+				CreateBackendFn: createBackendOK,
+				CreateDomainFn:  createDomainOK,
+				CreateServiceFn: createServiceOK,
+				DeleteServiceFn: func(_ *fastly.DeleteServiceInput) error {
+					deleted = true
+					return nil
+				},
+				GetPackageFn:        getPackageOk,
+				GetServiceDetailsFn: getServiceDetailsWasm,
+				GetServiceFn:        getServiceOK,
+				ListDomainsFn:       listDomainsOk,
+				ListVersionsFn:      testutil.ListVersions,
+				UpdatePackageFn: func(i *fastly.UpdatePackageInput) (*fastly.Package, error) {
+					cancel()
+					if testcase.cancelAfterUpload {
+						return updatePackageOk(i)
+					}
+					return nil, context.Canceled
+				},
+			}
+
+			var stdout threadsafe.Buffer
+			opts := testutil.MockGlobalData(testcase.args, &stdout)
+			opts.APIClientFactory = mock.APIClient(api)
+			opts.Context = ctx
+			opts.Input = strings.NewReader("Y")
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				return opts, nil
+			}
+			err := app.Run(testcase.args, nil)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertRemediationErrorContains(t, err, testcase.wantRemediationError)
+			testutil.AssertBool(t, testcase.wantDeleted, deleted)
+			testutil.AssertBool(t, false, activated)
+		})
+	}
+}
+
+// TestDeployEvents validates the progress events emitted during a deploy are
+// written in order and follow the event schema.
+func TestDeployEvents(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootdir := testutil.NewEnv(testutil.EnvOpts{
+		T: t,
+		Copy: []testutil.FileIO{
+			{
+				Src: filepath.Join("testdata", "deploy", "pkg", "package.tar.gz"),
+				Dst: filepath.Join("pkg", "package.tar.gz"),
+			},
+		},
+		Write: []testutil.FileIO{
+			{Src: "manifest_version = 2\nname = \"package\"\n", Dst: manifest.Filename},
+		},
+	})
+	defer os.RemoveAll(rootdir)
+
+	if err := os.Chdir(rootdir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.Chdir(pwd)
+	}()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	lines := make(chan []string)
+	go func() {
+		var l []string
+		s := bufio.NewScanner(r)
+		for s.Scan() {
+			l = append(l, s.Text())
+		}
+		lines <- l
+	}()
+
+	const token = "secret-token-value"
+	args := testutil.Args("compute deploy --service-id 123 --token " + token + " --package pkg/package.tar.gz")
+	api := mock.API{
+		ActivateVersionFn:   activateVersionOk,
+		CloneVersionFn:      testutil.CloneVersionResult(4),
+		GetPackageFn:        getPackageOk,
+		GetServiceDetailsFn: getServiceDetailsWasm,
+		GetServiceFn:        getServiceOK,
+		ListDomainsFn:       listDomainsOk,
+		ListVersionsFn:      testutil.ListVersions,
+		UpdatePackageFn:     updatePackageOk,
+	}
+
+	var stdout threadsafe.Buffer
+	opts := testutil.MockGlobalData(args, &stdout)
+	opts.APIClientFactory = mock.APIClient(api)
+	opts.Events = events.New(w, nil)
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		return opts, nil
+	}
+	err = app.Run(args, nil)
+	t.Log(stdout.String())
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, stdout.String(), "Deployed package (service 123, version 4)")
+
+	var types []string
+	for _, line := range <-lines {
+		if strings.Contains(line, token) {
+			t.Errorf("event contains the API token: %s", line)
+		}
+		var ev map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("invalid event %q: %v", line, err)
+		}
+		if len(ev) != 3 || ev["type"] == nil || ev["timestamp"] == nil || ev["fields"] == nil {
+			t.Fatalf("event doesn't match the schema (type, timestamp, fields): %s", line)
+		}
+		var e events.Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("invalid event %q: %v", line, err)
+		}
+		if e.Timestamp.IsZero() {
+			t.Errorf("event has no timestamp: %s", line)
+		}
+		types = append(types, e.Type)
+	}
+	testutil.AssertEqual(t, []string{
+		events.DeployStarted,
+		events.UploadStarted,
+		events.UploadFinished,
+		events.ActivationStarted,
+		events.ActivationDone,
+		events.DeployFinished,
+	}, types)
+}
+
+// TestDeployEventsResources validates the deploy summary event lists the
+// outcome of each [setup] resource.
+func TestDeployEventsResources(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootdir := testutil.NewEnv(testutil.EnvOpts{
+		T: t,
+		Copy: []testutil.FileIO{
+			{
+				Src: filepath.Join("testdata", "deploy", "pkg", "package.tar.gz"),
+				Dst: filepath.Join("pkg", "package.tar.gz"),
+			},
+		},
+		Write: []testutil.FileIO{
+			{
+				Src: `manifest_version = 2
+name = "package"
+
+[setup.config_stores.example]
+
+[setup.kv_stores.store_one]
+optional = true
+`,
+				Dst: manifest.Filename,
+			},
+		},
+	})
+	defer os.RemoveAll(rootdir)
+
+	if err := os.Chdir(rootdir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.Chdir(pwd)
+	}()
+
+	var buf bytes.Buffer
+	args := testutil.Args("compute deploy --non-interactive --status-check-off --token 123 --package pkg/package.tar.gz")
+	api := mock.API{
+		ActivateVersionFn:   activateVersionOk,
+		CreateBackendFn:     createBackendOK,
+		CreateConfigStoreFn: createConfigStoreOK,
+		CreateDomainFn:      createDomainOK,
+		CreateResourceFn:    createResourceOK,
+		CreateServiceFn:     createServiceOK,
+		GetPackageFn:        getPackageOk,
+		GetServiceDetailsFn: getServiceDetailsWasm,
+		GetServiceFn:        getServiceOK,
+		ListConfigStoresFn:  listConfigStoresEmpty,
+		ListDomainsFn:       listDomainsOk,
+		ListKVStoresFn:      listKVStoresError,
+		ListVersionsFn:      testutil.ListVersions,
+		UpdatePackageFn:     updatePackageOk,
+	}
+
+	var stdout threadsafe.Buffer
+	opts := testutil.MockGlobalData(args, &stdout)
+	opts.APIClientFactory = mock.APIClient(api)
+	opts.Events = events.New(&buf, nil)
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		return opts, nil
+	}
+	err = app.Run(args, nil)
+	t.Log(stdout.String())
+	testutil.AssertNoError(t, err)
+
+	var finished *events.Event
+	s := bufio.NewScanner(&buf)
+	for s.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			t.Fatalf("invalid event %q: %v", s.Text(), err)
+		}
+		if e.Type == events.DeployFinished {
+			finished = &e
+		}
+	}
+	if finished == nil {
+		t.Fatalf("no %s event", events.DeployFinished)
+	}
+
+	data, err := json.Marshal(finished.Fields["resources"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resources []setup.ResourceResult
+	if err := json.Unmarshal(data, &resources); err != nil {
+		t.Fatal(err)
+	}
+	// NOTE: The KV Store is skipped while configuring the resources (listing
+	// the existing stores fails), before any resource is created.
+	testutil.AssertEqual(t, []setup.ResourceResult{
+		{Type: setup.TypeKVStore, Name: "store_one", Status: setup.StatusSkipped, Error: "test error"},
+		{Type: setup.TypeConfigStore, Name: "example", Status: setup.StatusCreated},
+	}, resources)
+}
+
+// TestDeployJSON validates --json only writes the deploy result (including the
+// next steps) to stdout.
+func TestDeployJSON(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootdir := testutil.NewEnv(testutil.EnvOpts{
+		T: t,
+		Copy: []testutil.FileIO{
+			{
+				Src: filepath.Join("testdata", "deploy", "pkg", "package.tar.gz"),
+				Dst: filepath.Join("pkg", "package.tar.gz"),
+			},
+		},
+		Write: []testutil.FileIO{
+			{Src: "manifest_version = 2\nname = \"package\"\n", Dst: manifest.Filename},
+		},
+	})
+	defer os.RemoveAll(rootdir)
+
+	if err := os.Chdir(rootdir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.Chdir(pwd)
+	}()
+
+	args := testutil.Args("compute deploy --service-id 123 --token 123 --package pkg/package.tar.gz --status-check-off --json")
+	api := mock.API{
+		ActivateVersionFn:   activateVersionOk,
+		CloneVersionFn:      testutil.CloneVersionResult(4),
+		GetPackageFn:        getPackageOk,
+		GetServiceDetailsFn: getServiceDetailsWasm,
+		GetServiceFn:        getServiceOK,
+		ListDomainsFn: func(_ *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+			return []*fastly.Domain{{Name: fastly.ToPointer("directly-careful-coyote.edgecompute.app")}}, nil
+		},
+		ListVersionsFn:  testutil.ListVersions,
+		UpdatePackageFn: updatePackageOk,
+	}
+
+	var stdout threadsafe.Buffer
+	opts := testutil.MockGlobalData(args, &stdout)
+	opts.APIClientFactory = mock.APIClient(api)
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		return opts, nil
+	}
+	err = app.Run(args, nil)
+	t.Log(stdout.String())
+	testutil.AssertNoError(t, err)
+
+	var result struct {
+		ServiceID string          `json:"service_id"`
+		Version   int             `json:"version"`
+		URL       string          `json:"url"`
+		NextSteps []text.NextStep `json:"next_steps"`
+	}
+	if err := json.Unmarshal([]byte(stdout.String()), &result); err != nil {
+		t.Fatalf("stdout isn't only the JSON result: %v", err)
+	}
+	testutil.AssertString(t, "123", result.ServiceID)
+	testutil.AssertEqual(t, 4, result.Version)
+	testutil.AssertEqual(t, []text.NextStep{
+		{Label: "View the service", URL: "https://directly-careful-coyote.edgecompute.app"},
+		{Label: "Tail the service's logs", Command: "fastly log-tail --service-id 123"},
+	}, result.NextSteps)
+}
+
+// TestDeployABI validates a package that doesn't conform to the Compute ABI
+// isn't uploaded, unless --allow-abi-mismatch is set.
+func TestDeployABI(t *testing.T) {
+	scenarios := []struct {
+		name                 string
+		fixture              string
+		flags                string
+		importModules        []string
+		wantError            string
+		wantRemediationError string
+		wantOutput           []string
+	}{
+		{
+			name:       "valid",
+			fixture:    "valid.tar.gz",
+			wantOutput: []string{"Deployed package (service 123, version 4)"},
+		},
+		{
+			name:                 "missing entrypoint",
+			fixture:              "no-entrypoint.tar.gz",
+			wantError:            "the package doesn't conform to the Compute ABI: bin/main.wasm doesn't export the '_start' function",
+			wantRemediationError: "rather than a library",
+		},
+		{
+			name:                 "unknown import namespace",
+			fixture:              "unknown-import.tar.gz",
+			wantError:            "bin/main.wasm imports '__wbindgen_describe' (and 1 other functions) from the '__wbindgen_placeholder__' module, which isn't a Compute hostcall namespace",
+			wantRemediationError: "To upload the package anyway, set --allow-abi-mismatch.",
+		},
+		{
+			name:          "namespace added to the config",
+			fixture:       "unknown-import.tar.gz",
+			importModules: append([]string{"__wbindgen_placeholder__"}, compute.ComputeABIModules...),
+			wantOutput:    []string{"Deployed package (service 123, version 4)"},
+		},
+		{
+			name:    "downgraded to a warning",
+			fixture: "unknown-import.tar.gz",
+			flags:   "--allow-abi-mismatch",
+			wantOutput: []string{
+				"WARNING: bin/main.wasm imports '__wbindgen_describe' (and 1 other functions) from the '__wbindgen_placeholder__' module",
+				"Deployed package (service 123, version 4)",
+			},
+		},
+		{
+			name:                 "layout",
+			fixture:              "layout.tar.gz",
+			wantError:            "the package must contain a single top-level directory, but it has 3 top-level entries (fastly.toml, main.wasm, package); the package has no fastly.toml in its top-level directory; the package has 2 Wasm binaries named main.wasm (main.wasm, package/bin/main.wasm) rather than a single bin/main.wasm",
+			wantRemediationError: "fastly compute build",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			pwd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+			rootdir := testutil.NewEnv(testutil.EnvOpts{
+				T: t,
+				Copy: []testutil.FileIO{
+					{
+						Src: filepath.Join("testdata", "deploy", "abi", testcase.fixture),
+						Dst: filepath.Join("pkg", "package.tar.gz"),
+					},
+				},
+				Write: []testutil.FileIO{
+					{Src: "manifest_version = 2\nname = \"package\"\n", Dst: manifest.Filename},
+				},
+			})
+			defer os.RemoveAll(rootdir)
+			if err := os.Chdir(rootdir); err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = os.Chdir(pwd)
+			}()
+
+			args := testutil.Args(strings.TrimSpace("compute deploy --service-id 123 --token 123 --package pkg/package.tar.gz --status-check-off " + testcase.flags))
+			api := mock.API{
+				ActivateVersionFn:   activateVersionOk,
+				CloneVersionFn:      testutil.CloneVersionResult(4),
+				GetPackageFn:        getPackageOk,
+				GetServiceDetailsFn: getServiceDetailsWasm,
+				GetServiceFn:        getServiceOK,
+				ListDomainsFn:       listDomainsOk,
+				ListVersionsFn:      testutil.ListVersions,
+				UpdatePackageFn:     updatePackageOk,
+			}
+
+			var stdout threadsafe.Buffer
+			opts := testutil.MockGlobalData(args, &stdout)
+			opts.APIClientFactory = mock.APIClient(api)
+			opts.Config.ComputeABI.ImportModules = testcase.importModules
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				return opts, nil
+			}
+			err = app.Run(args, nil)
+			t.Log(stdout.String())
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertRemediationErrorContains(t, err, testcase.wantRemediationError)
+			if testcase.wantError != "" {
+				testutil.AssertStringDoesntContain(t, stdout.String(), "Uploading package")
+			}
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+		})
+	}
+}
+
+// TestDeployTrace validates the trace of a deploy has a span for the command,
+// with the deploy phases nested within it.
+func TestDeployTrace(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootdir := testutil.NewEnv(testutil.EnvOpts{
+		T: t,
+		Copy: []testutil.FileIO{
+			{
+				Src: filepath.Join("testdata", "deploy", "pkg", "package.tar.gz"),
+				Dst: filepath.Join("pkg", "package.tar.gz"),
+			},
+		},
+		Write: []testutil.FileIO{
+			{Src: "manifest_version = 2\nname = \"package\"\n", Dst: manifest.Filename},
+		},
+	})
+	defer os.RemoveAll(rootdir)
+
+	if err := os.Chdir(rootdir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.Chdir(pwd)
+	}()
+
+	args := testutil.Args("compute deploy --service-id 123 --token 123 --package pkg/package.tar.gz --status-check-off")
+	api := mock.API{
+		ActivateVersionFn:   activateVersionOk,
+		CloneVersionFn:      testutil.CloneVersionResult(4),
+		GetPackageFn:        getPackageOk,
+		GetServiceDetailsFn: getServiceDetailsWasm,
+		GetServiceFn:        getServiceOK,
+		ListDomainsFn:       listDomainsOk,
+		ListVersionsFn:      testutil.ListVersions,
+		UpdatePackageFn:     updatePackageOk,
+	}
+
+	var stdout threadsafe.Buffer
+	opts := testutil.MockGlobalData(args, &stdout)
+	opts.APIClientFactory = mock.APIClient(api)
+	opts.Env.TraceFile = filepath.Join(rootdir, "trace.json")
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		return opts, nil
+	}
+	err = app.Run(args, nil)
+	t.Log(stdout.String())
+	testutil.AssertNoError(t, err)
+
+	data, err := os.ReadFile(opts.Env.TraceFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var trace struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []struct {
+					TraceID      string `json:"traceId"`
+					SpanID       string `json:"spanId"`
+					ParentSpanID string `json:"parentSpanId"`
+					Name         string `json:"name"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	if err := json.Unmarshal(data, &trace); err != nil {
+		t.Fatal(err)
+	}
+	spans := trace.ResourceSpans[0].ScopeSpans[0].Spans
+
+	// The tree is rendered with each span indented below its parent.
+	depth := make(map[string]int)
+	var tree []string
+	for _, s := range spans {
+		testutil.AssertString(t, spans[0].TraceID, s.TraceID)
+		d := 0
+		if s.ParentSpanID != "" {
+			parent, ok := depth[s.ParentSpanID]
+			if !ok {
+				t.Fatalf("%s: the parent span %s isn't in the trace", s.Name, s.ParentSpanID)
+			}
+			d = parent + 1
+		}
+		depth[s.SpanID] = d
+		tree = append(tree, strings.Repeat("  ", d)+s.Name)
+	}
+	testutil.AssertEqual(t, []string{
+		"compute deploy",
+		"  deploy",
+		"    package hash",
+		"    upload",
+		"    activation",
+	}, tree)
+}
+
+// TestDeployTrialActivation validates that when the Compute free trial isn't
+// enabled the user is offered to request it, and the deploy resumes once the
+// trial is enabled.
+func TestDeployTrialActivation(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootdir := testutil.NewEnv(testutil.EnvOpts{
+		T: t,
+		Copy: []testutil.FileIO{
+			{
+				Src: filepath.Join("testdata", "deploy", "pkg", "package.tar.gz"),
+				Dst: filepath.Join("pkg", "package.tar.gz"),
+			},
+		},
+	})
+	defer os.RemoveAll(rootdir)
+
+	if err := os.Chdir(rootdir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.Chdir(pwd)
+	}()
+
+	originalInterval, originalTimeout := compute.TrialPollInterval, compute.TrialPollTimeout
+	defer func() {
+		compute.TrialPollInterval, compute.TrialPollTimeout = originalInterval, originalTimeout
+	}()
+	compute.TrialPollInterval = time.Millisecond
+	compute.TrialPollTimeout = time.Second
+
+	args := testutil.Args
+	scenarios := []struct {
+		name string
+		args []string
+		// trialStatus is the status code returned by the entitlement endpoint.
+		trialStatus int
+		// enabledAfter is the number of service creation attempts rejected (as
+		// the trial isn't enabled) before the service is created.
+		enabledAfter         int
+		stdin                string
+		wantCreateAttempts   int
+		wantError            string
+		wantOutput           []string
+		wantRemediationError string
+		wantTrialRequested   bool
+	}{
+		{
+			name:               "immediate grant",
+			args:               args("compute deploy --status-check-off --token 123"),
+			trialStatus:        http.StatusOK,
+			enabledAfter:       1,
+			stdin:              "Y\n\nY\n",
+			wantCreateAttempts: 2,
+			wantOutput: []string{
+				"Request the Compute free trial: [y/N]",
+				"Requesting Compute free trial",
+				"Deployed package (service 12345, version 1)",
+			},
+			wantTrialRequested: true,
+		},
+		{
+			name:               "delayed grant",
+			args:               args("compute deploy --status-check-off --token 123"),
+			trialStatus:        http.StatusOK,
+			enabledAfter:       4,
+			stdin:              "Y\n\nY\n",
+			wantCreateAttempts: 5,
+			wantOutput: []string{
+				"Requesting Compute free trial",
+				"Deployed package (service 12345, version 1)",
+			},
+			wantTrialRequested: true,
+		},
+		{
+			name:               "trial already requested",
+			args:               args("compute deploy --status-check-off --token 123 --auto-yes"),
+			trialStatus:        http.StatusConflict,
+			enabledAfter:       1,
+			wantCreateAttempts: 2,
+			wantOutput: []string{
+				"Deployed package (service 12345, version 1)",
+			},
+			wantTrialRequested: true,
+		},
+		{
+			name:                 "denial",
+			args:                 args("compute deploy --status-check-off --token 123"),
+			trialStatus:          http.StatusForbidden,
+			enabledAfter:         1,
+			stdin:                "Y\n\nY\n",
+			wantCreateAttempts:   1,
+			wantError:            "error creating service: you do not have the Compute free trial enabled on your Fastly account",
+			wantRemediationError: errors.ComputeTrialRemediation,
+			wantTrialRequested:   true,
+		},
+		{
+			name:                 "timeout waiting for trial",
+			args:                 args("compute deploy --status-check-off --token 123 --auto-yes"),
+			trialStatus:          http.StatusOK,
+			enabledAfter:         -1,
+			wantError:            "timed out after 1s waiting for the requested trial",
+			wantRemediationError: "please try again shortly",
+			wantTrialRequested:   true,
+		},
+		{
+			name:                 "declined",
+			args:                 args("compute deploy --status-check-off --token 123"),
+			enabledAfter:         -1,
+			stdin:                "Y\n\nN\n",
+			wantCreateAttempts:   1,
+			wantError:            "error creating service: you do not have the Compute free trial enabled on your Fastly account",
+			wantRemediationError: `curl -X POST -H "Fastly-Key: $FASTLY_API_TOKEN" https://api.fastly.com/customer/abc/edge-compute-trial`,
+		},
+		{
+			name:                 "non-interactive",
+			args:                 args("compute deploy --status-check-off --token 123 --non-interactive"),
+			enabledAfter:         -1,
+			wantCreateAttempts:   1,
+			wantError:            "error creating service: you do not have the Compute free trial enabled on your Fastly account",
+			wantRemediationError: `curl -X POST -H "Fastly-Key: $FASTLY_API_TOKEN" https://api.fastly.com/customer/abc/edge-compute-trial`,
+		},
+	}
+
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			if err := os.WriteFile(filepath.Join(rootdir, manifest.Filename), []byte("manifest_version = 2\nname = \"package\"\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			var createAttempts int
+			api := mock.API{
+				ActivateVersionFn: activateVersionOk,
+				CreateBackendFn:   createBackendOK,
+				CreateDomainFn:    createDomainOK,
+				CreateServiceFn: func(i *fastly.CreateServiceInput) (*fastly.Service, error) {
+					createAttempts++
+					if testcase.enabledAfter < 0 || createAttempts <= testcase.enabledAfter {
+						return createServiceErrorNoTrial(i)
+					}
+					return createServiceOK(i)
+				},
+				DeleteServiceFn:  deleteServiceOK,
+				GetCurrentUserFn: getCurrentUser,
+				GetPackageFn:     getPackageOk,
+				ListDomainsFn:    listDomainsOk,
+				UpdatePackageFn:  updatePackageOk,
+			}
+
+			var trialRequests []string
+			httpClient := trialHTTPClient(func(r *http.Request) (*http.Response, error) {
+				trialRequests = append(trialRequests, r.Method+" "+r.URL.Path)
+				return &http.Response{
+					Body:       io.NopCloser(strings.NewReader("")),
+					Status:     http.StatusText(testcase.trialStatus),
+					StatusCode: testcase.trialStatus,
+				}, nil
+			})
+
+			var stdout threadsafe.Buffer
+			opts := testutil.MockGlobalData(testcase.args, &stdout)
+			opts.APIClientFactory = mock.APIClient(api)
+			opts.HTTPClient = httpClient
+			// NOTE: Each prompt reads a line, so the input must be read byte by byte.
+			opts.Input = iotest.OneByteReader(strings.NewReader(testcase.stdin))
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				return opts, nil
+			}
+			err := app.Run(testcase.args, nil)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertRemediationErrorContains(t, err, testcase.wantRemediationError)
+			if testcase.wantCreateAttempts > 0 {
+				testutil.AssertEqual(t, testcase.wantCreateAttempts, createAttempts)
+			}
+			var wantTrialRequests []string
+			if testcase.wantTrialRequested {
+				wantTrialRequests = []string{"POST /customer/abc/edge-compute-trial"}
+			}
+			testutil.AssertEqual(t, wantTrialRequests, trialRequests)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+		})
+	}
+}
+
+// trialHTTPClient mocks the HTTP client used to call the entitlement endpoint.
+type trialHTTPClient func(*http.Request) (*http.Response, error)
+
+func (c trialHTTPClient) Do(r *http.Request) (*http.Response, error) {
+	return c(r)
+}
+
 func createServiceOK(i *fastly.CreateServiceInput) (*fastly.Service, error) {
 	return &fastly.Service{
 		ServiceID: fastly.ToPointer("12345"),
@@ -2292,8 +3194,8 @@ func getPackageIdentical(i *fastly.GetPackageInput) (*fastly.Package, error) {
 		ServiceID:      fastly.ToPointer(i.ServiceID),
 		ServiceVersion: fastly.ToPointer(i.ServiceVersion),
 		Metadata: &fastly.PackageMetadata{
-			FilesHash: fastly.ToPointer("d8786807216a37608ecd0bc2357c86f883faad89043141f0a147f2c186ce0212333d31229399c131539205908f5cf0884ea64552782544ff9b27416cd5b996b2"),
-			HashSum:   fastly.ToPointer("bf634ccf8be5c8417cf562466ece47ea61056ddeb07273a3d861e8ad757ed3577bc182006d04093c301467cadfd2b1805eedebd1e7cfa0404c723680f2dbc01e"),
+			FilesHash: fastly.ToPointer("ce31b2fed4a4d4be00e55390240090788351220b6d7a8347ea936b650d946616a2b2b6e438b45b94ceae137526423d27c9302ed5331d14b1bd4463cb067b7c56"),
+			HashSum:   fastly.ToPointer("720a12628544430de034bb69ad36def83a0edc555a505f71fd80a4ee9c0032e45921b290352c55d55a8e54f9064289b5c96d46be0ae402f648fdc66991371302"),
 		},
 	}, nil
 }
@@ -2309,3 +3211,171 @@ func listDomainsError(_ *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
 func listDomainsNone(_ *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
 	return []*fastly.Domain{}, nil
 }
+
+// gitState is the state of the git working tree reported by fakeGit.
+type gitState struct {
+	branch  string // empty if HEAD is detached
+	notRepo bool
+	status  string // the `git status --porcelain` output
+}
+
+const fakeCommit = "3e1f9a2c5b7d8e6f0a1b2c3d4e5f60718293a4b5"
+
+// fakeGit returns a git runner reporting the state.
+func fakeGit(s gitState) func(args ...string) ([]byte, error) {
+	return func(args ...string) ([]byte, error) {
+		if s.notRepo {
+			return []byte("fatal: not a git repository (or any of the parent directories): .git\n"), fmt.Errorf("exit status 128")
+		}
+		switch strings.Join(args[:2], " ") {
+		case "rev-parse --is-inside-work-tree":
+			return []byte("true\n"), nil
+		case "rev-parse HEAD":
+			return []byte(fakeCommit + "\n"), nil
+		case "symbolic-ref --quiet":
+			if s.branch == "" {
+				return nil, fmt.Errorf("exit status 1")
+			}
+			return []byte(s.branch + "\n"), nil
+		case "status --porcelain":
+			return []byte(s.status), nil
+		}
+		return nil, fmt.Errorf("unexpected git invocation: %v", args)
+	}
+}
+
+// TestDeployVCSGuards validates the --require-clean and --require-branch
+// guards are checked before anything is deployed, and their result is
+// included in the deploy's output.
+func TestDeployVCSGuards(t *testing.T) {
+	scenarios := []struct {
+		name        string
+		args        string
+		git         gitState
+		wantError   string
+		wantOutput  []string
+		wantVCS     *compute.VCSStatus
+		wantDeploys bool
+	}{
+		{
+			name:        "clean",
+			args:        "--require-clean --require-branch main",
+			git:         gitState{branch: "main"},
+			wantDeploys: true,
+			wantOutput:  []string{"Deployed from git:", "main @ 3e1f9a2 (clean)"},
+		},
+		{
+			name:        "clean with --json",
+			args:        "--require-clean --require-branch main --json",
+			git:         gitState{branch: "main"},
+			wantDeploys: true,
+			wantVCS: &compute.VCSStatus{
+				Branch:        "main",
+				Clean:         true,
+				Commit:        fakeCommit,
+				RequireBranch: "main",
+				RequireClean:  true,
+			},
+		},
+		{
+			name:      "dirty",
+			args:      "--require-clean",
+			git:       gitState{branch: "main", status: " M src/main.rs\n?? notes.txt\n"},
+			wantError: "--require-clean: the git working tree has 2 uncommitted or untracked changes:\n\n   M src/main.rs\n  ?? notes.txt",
+		},
+		{
+			name:        "dirty without --require-clean",
+			args:        "--require-branch main",
+			git:         gitState{branch: "main", status: " M src/main.rs\n"},
+			wantDeploys: true,
+			wantOutput:  []string{"main @ 3e1f9a2 (1 uncommitted changes)"},
+		},
+		{
+			name:      "other branch",
+			args:      "--require-branch main",
+			git:       gitState{branch: "feature"},
+			wantError: "--require-branch: HEAD is on branch 'feature', not on branch 'main'",
+		},
+		{
+			name:      "detached HEAD",
+			args:      "--require-branch main",
+			git:       gitState{},
+			wantError: "--require-branch: HEAD is detached at " + fakeCommit + ", not on branch 'main'",
+		},
+		{
+			name:      "no repository",
+			args:      "--require-clean",
+			git:       gitState{notRepo: true},
+			wantError: "the project directory is not a git repository",
+		},
+	}
+
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			pwd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+			rootdir := testutil.NewEnv(testutil.EnvOpts{
+				T: t,
+				Copy: []testutil.FileIO{
+					{
+						Src: filepath.Join("testdata", "deploy", "pkg", "package.tar.gz"),
+						Dst: filepath.Join("pkg", "package.tar.gz"),
+					},
+				},
+				Write: []testutil.FileIO{
+					{Src: "manifest_version = 2\nname = \"package\"\n", Dst: manifest.Filename},
+				},
+			})
+			defer os.RemoveAll(rootdir)
+			if err := os.Chdir(rootdir); err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = os.Chdir(pwd)
+			}()
+
+			args := testutil.Args("compute deploy --service-id 123 --token 123 --package pkg/package.tar.gz --status-check-off " + testcase.args)
+			var deployed bool
+			api := mock.API{
+				ActivateVersionFn:   activateVersionOk,
+				CloneVersionFn:      testutil.CloneVersionResult(4),
+				GetPackageFn:        getPackageOk,
+				GetServiceDetailsFn: getServiceDetailsWasm,
+				GetServiceFn:        getServiceOK,
+				ListDomainsFn:       listDomainsOk,
+				ListVersionsFn:      testutil.ListVersions,
+				UpdatePackageFn: func(i *fastly.UpdatePackageInput) (*fastly.Package, error) {
+					deployed = true
+					return updatePackageOk(i)
+				},
+			}
+
+			var stdout threadsafe.Buffer
+			opts := testutil.MockGlobalData(args, &stdout)
+			opts.APIClientFactory = mock.APIClient(api)
+			opts.ExecuteGit = fakeGit(testcase.git)
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				return opts, nil
+			}
+			err = app.Run(args, nil)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertBool(t, testcase.wantDeploys, deployed)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			if testcase.wantVCS != nil {
+				var result struct {
+					VCS *compute.VCSStatus `json:"vcs"`
+				}
+				if err := json.Unmarshal([]byte(stdout.String()), &result); err != nil {
+					t.Fatal(err)
+				}
+				testutil.AssertEqual(t, testcase.wantVCS, result.VCS)
+			}
+		})
+	}
+}
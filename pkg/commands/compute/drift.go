@@ -0,0 +1,309 @@
+package compute
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// The drift reconciliation flags, shared by deploy and drift.
+const (
+	acceptRemoteDesc = "Reconcile drift between fastly.toml and the service by updating fastly.toml with the service's name and comment"
+	pushLocalDesc    = "Reconcile drift between fastly.toml and the service by updating the service with fastly.toml's name and description"
+)
+
+// ErrDriftUnreconciled indicates drift was detected but not reconciled.
+var ErrDriftUnreconciled = errors.New("fastly.toml has drifted from the service")
+
+// DriftField is a manifest field whose value differs from the service.
+type DriftField struct {
+	// Field is the manifest field (e.g. "description").
+	Field string `json:"field"`
+	// Attribute is the corresponding service attribute (e.g. "comment").
+	Attribute string `json:"attribute"`
+	// Local is the manifest value.
+	Local string `json:"local"`
+	// Remote is the service value.
+	Remote string `json:"remote"`
+}
+
+// pushable indicates the service attribute can be updated from the manifest.
+func (f DriftField) pushable() bool {
+	return f.Attribute != "id"
+}
+
+// DetectDrift compares the manifest against the service it's deployed to.
+//
+// The service_id only drifts when the service was selected by a flag or
+// environment variable rather than the manifest. Attributes the API didn't
+// return are skipped.
+func DetectDrift(m *manifest.File, serviceID string, s *fastly.ServiceDetail) []DriftField {
+	var drift []DriftField
+	if m.ServiceID != serviceID {
+		drift = append(drift, DriftField{Field: "service_id", Attribute: "id", Local: m.ServiceID, Remote: serviceID})
+	}
+	if s.Name != nil && m.Name != *s.Name {
+		drift = append(drift, DriftField{Field: "name", Attribute: "name", Local: m.Name, Remote: *s.Name})
+	}
+	if s.Comment != nil && m.Description != *s.Comment {
+		drift = append(drift, DriftField{Field: "description", Attribute: "comment", Local: m.Description, Remote: *s.Comment})
+	}
+	return drift
+}
+
+// driftReconciler reconciles drift between a manifest and a service, either
+// by the direction set by flag or by prompting for it.
+type driftReconciler struct {
+	globals      *global.Data
+	acceptRemote bool
+	pushLocal    bool
+	manifestPath string
+	serviceID    string
+
+	// pushed indicates the service was updated from the manifest.
+	pushed bool
+}
+
+// validate checks the reconciliation flags aren't contradictory.
+func (r *driftReconciler) validate() error {
+	if r.acceptRemote && r.pushLocal {
+		return fsterr.ErrInvalidAcceptRemotePushLocalCombo
+	}
+	return nil
+}
+
+// run displays the drift and reconciles it. ErrDriftUnreconciled is returned
+// if the direction wasn't set by flag and can't be prompted for.
+func (r *driftReconciler) run(drift []DriftField, in io.Reader, out io.Writer) error {
+	filename := filepath.Base(r.manifestPath)
+	text.Warning(out, "%s has drifted from service %s (e.g. the service was edited in the web interface):", filename, r.serviceID)
+	text.Break(out)
+	for _, f := range drift {
+		text.Output(out, "%s (service %s)", f.Field, f.Attribute)
+		text.Output(out, "  - %s: %q", filename, f.Local)
+		text.Output(out, "  + service: %q", f.Remote)
+	}
+	text.Break(out)
+
+	acceptRemote, pushLocal := r.acceptRemote, r.pushLocal
+	if !acceptRemote && !pushLocal {
+		if r.globals.Flags.NonInteractive || r.globals.Flags.AutoYes {
+			return fsterr.RemediationError{
+				Inner:       ErrDriftUnreconciled,
+				Remediation: "Pass --accept-remote to update fastly.toml from the service, or --push-local to update the service from fastly.toml.",
+			}
+		}
+		answer, err := text.Input(out, text.Prompt("Update [m]anifest from the service, update [s]ervice from the manifest, or [k]eep both as they are: [k] "), in, "--accept-remote")
+		if err != nil {
+			return fmt.Errorf("error reading input: %w", err)
+		}
+		switch strings.ToLower(answer) {
+		case "m", "manifest":
+			acceptRemote = true
+		case "s", "service":
+			pushLocal = true
+		default:
+			text.Info(out, "Kept %s and the service as they are.", filename)
+			text.Break(out)
+			return nil
+		}
+	}
+
+	if acceptRemote {
+		return r.acceptRemoteValues(drift, out)
+	}
+	return r.pushLocalValues(drift, out)
+}
+
+// acceptRemoteValues updates the manifest with the service's values.
+//
+// NOTE: The manifest is edited in place (see manifest.SetFields) rather than
+// re-encoded with manifest.File.Write, so the user's comments are kept.
+func (r *driftReconciler) acceptRemoteValues(drift []DriftField, out io.Writer) error {
+	fields := make(map[string]string, len(drift))
+	for _, f := range drift {
+		fields[f.Field] = f.Remote
+	}
+	if err := manifest.SetFields(r.manifestPath, fields); err != nil {
+		r.globals.ErrLog.Add(err)
+		return fmt.Errorf("error updating %s: %w", filepath.Base(r.manifestPath), err)
+	}
+
+	m := &r.globals.Manifest.File
+	for _, f := range drift {
+		switch f.Field {
+		case "service_id":
+			m.ServiceID = f.Remote
+		case "name":
+			m.Name = f.Remote
+		case "description":
+			m.Description = f.Remote
+		}
+	}
+	text.Success(out, "Updated %s from service %s", filepath.Base(r.manifestPath), r.serviceID)
+	text.Break(out)
+	return nil
+}
+
+// pushLocalValues updates the service with the manifest's values.
+func (r *driftReconciler) pushLocalValues(drift []DriftField, out io.Writer) error {
+	input := &fastly.UpdateServiceInput{ServiceID: r.serviceID}
+	var unpushable []string
+	for _, f := range drift {
+		local := f.Local
+		switch f.Attribute {
+		case "name":
+			input.Name = &local
+		case "comment":
+			input.Comment = &local
+		}
+		if !f.pushable() {
+			unpushable = append(unpushable, f.Field)
+		}
+	}
+
+	if input.Name != nil || input.Comment != nil {
+		r.pushed = true
+		if _, err := r.globals.APIClient.UpdateService(input); err != nil {
+			r.globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID": r.serviceID,
+			})
+			return fmt.Errorf("error updating service %s: %w", r.serviceID, err)
+		}
+		text.Success(out, "Updated service %s from %s", r.serviceID, filepath.Base(r.manifestPath))
+	}
+	if len(unpushable) > 0 {
+		text.Info(out, "A service can't be changed to match %s, only reconciled with --accept-remote.", strings.Join(unpushable, ", "))
+	}
+	text.Break(out)
+	return nil
+}
+
+// DriftCommand compares fastly.toml with the service it's deployed to.
+type DriftCommand struct {
+	argparser.Base
+
+	acceptRemote bool
+	dir          string
+	env          string
+	pushLocal    bool
+	pushed       bool
+	serviceName  argparser.OptionalServiceNameID
+}
+
+// NewDriftCommand returns a usable command registered under the parent.
+func NewDriftCommand(parent argparser.Registerer, g *global.Data) *DriftCommand {
+	var c DriftCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("drift", "Compare the name, description and service ID in fastly.toml with the service, and reconcile any differences")
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Name:        argparser.FlagServiceIDName,
+		Description: argparser.FlagServiceIDDesc,
+		Dst:         &c.Globals.Manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        argparser.FlagServiceName,
+		Description: argparser.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.CmdClause.Flag("accept-remote", acceptRemoteDesc).BoolVar(&c.acceptRemote)
+	c.CmdClause.Flag("dir", "Project directory (default: current directory)").Short('C').StringVar(&c.dir)
+	c.CmdClause.Flag("env", "The manifest environment config to use (e.g. 'stage' will attempt to read 'fastly.stage.toml')").StringVar(&c.env)
+	c.CmdClause.Flag("push-local", pushLocalDesc).BoolVar(&c.pushLocal)
+	return &c
+}
+
+// RequiredScope implements the argparser.Scoper interface. Only --push-local
+// updates the service, which needs the global scope.
+func (c *DriftCommand) RequiredScope() fastly.TokenScope {
+	if c.pushLocal {
+		return fastly.GlobalScope
+	}
+	return ""
+}
+
+// Mutating implements the argparser.Mutator interface. The service is updated
+// by --push-local, or when the user chooses to at the prompt, unless only the
+// service ID has drifted.
+func (c *DriftCommand) Mutating() bool {
+	return c.pushed
+}
+
+// Exec invokes the application logic for the command.
+func (c *DriftCommand) Exec(in io.Reader, out io.Writer) error {
+	projectDir := c.dir
+	if projectDir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current working directory: %w", err)
+		}
+		projectDir = wd
+	}
+	manifestPath := filepath.Join(projectDir, EnvironmentManifest(c.env))
+	if err := c.Globals.Manifest.File.Read(manifestPath); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = fsterr.ErrReadingManifest
+		}
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	var (
+		serviceID string
+		source    manifest.Source
+		flag      string
+		err       error
+	)
+	if c.serviceName.WasSet {
+		serviceID, err = c.serviceName.Parse(c.Globals.APIClient)
+	} else {
+		serviceID, source, flag, err = argparser.ServiceID(c.serviceName, *c.Globals.Manifest, c.Globals.APIClient, c.Globals.ErrLog)
+		if err == nil && c.Globals.Verbose() {
+			argparser.DisplayServiceID(serviceID, flag, source, out)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	r := driftReconciler{
+		globals:      c.Globals,
+		acceptRemote: c.acceptRemote,
+		pushLocal:    c.pushLocal,
+		manifestPath: manifestPath,
+		serviceID:    serviceID,
+	}
+	if err := r.validate(); err != nil {
+		return err
+	}
+
+	s, err := c.Globals.APIClient.GetServiceDetails(&fastly.GetServiceInput{ServiceID: serviceID})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+		})
+		return err
+	}
+
+	drift := DetectDrift(&c.Globals.Manifest.File, serviceID, s)
+	if len(drift) == 0 {
+		text.Success(out, "%s matches service %s", filepath.Base(manifestPath), serviceID)
+		return nil
+	}
+	err = r.run(drift, in, out)
+	c.pushed = r.pushed
+	return err
+}
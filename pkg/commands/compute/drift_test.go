@@ -0,0 +1,314 @@
+package compute_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/audit"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+const driftManifest = `# Managed by the platform team.
+manifest_version = 3
+name = "local-name" # the package name
+description = "local description"
+service_id = "123"
+`
+
+func TestDrift(t *testing.T) {
+	remoteService := func(name, comment string) func(*fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+		return func(i *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+			return &fastly.ServiceDetail{
+				ServiceID: fastly.ToPointer(i.ServiceID),
+				Name:      fastly.ToPointer(name),
+				Comment:   fastly.ToPointer(comment),
+				Type:      fastly.ToPointer("wasm"),
+			}, nil
+		}
+	}
+
+	scenarios := []struct {
+		name           string
+		args           string
+		stdin          string
+		nonInteractive bool
+		tokenScope     fastly.TokenScope
+		api            mock.API
+		wantAudit      []string
+		wantUpdate     *fastly.UpdateServiceInput
+		wantManifest   string
+		wantOutput     []string
+		wantError      string
+	}{
+		{
+			name:         "no drift",
+			args:         "compute drift",
+			api:          mock.API{GetServiceDetailsFn: remoteService("local-name", "local description")},
+			wantManifest: driftManifest,
+			wantOutput:   []string{"SUCCESS: fastly.toml matches service 123"},
+		},
+		{
+			name:         "accept remote",
+			args:         "compute drift --accept-remote",
+			api:          mock.API{GetServiceDetailsFn: remoteService("remote-name", "remote description")},
+			wantManifest: strings.NewReplacer("local-name", "remote-name", "local description", "remote description").Replace(driftManifest),
+			wantOutput: []string{
+				"WARNING: fastly.toml has drifted from service 123",
+				"name (service name)",
+				`- fastly.toml: "local-name"`,
+				`+ service: "remote-name"`,
+				"description (service comment)",
+				"SUCCESS: Updated fastly.toml from service 123",
+			},
+		},
+		{
+			name: "push local",
+			args: "compute drift --push-local",
+			api: mock.API{
+				GetServiceDetailsFn: remoteService("local-name", "remote description"),
+				UpdateServiceFn:     updateServiceOK,
+			},
+			wantAudit:    []string{"compute drift --push-local"},
+			wantUpdate:   &fastly.UpdateServiceInput{ServiceID: "123", Comment: fastly.ToPointer("local description")},
+			wantManifest: driftManifest,
+			wantOutput:   []string{"SUCCESS: Updated service 123 from fastly.toml"},
+		},
+		{
+			name:       "push local without the global scope",
+			args:       "compute drift --push-local",
+			tokenScope: fastly.GlobalReadScope,
+			api: mock.API{
+				GetServiceDetailsFn: remoteService("local-name", "remote description"),
+				UpdateServiceFn:     updateServiceOK,
+			},
+			wantManifest: driftManifest,
+			wantError:    "`fastly compute drift` requires an API token with the 'global' scope",
+		},
+		{
+			name:         "prompt to accept remote",
+			args:         "compute drift",
+			stdin:        "m",
+			api:          mock.API{GetServiceDetailsFn: remoteService("remote-name", "local description")},
+			wantManifest: strings.Replace(driftManifest, "local-name", "remote-name", 1),
+		},
+		{
+			name:  "prompt to push local",
+			args:  "compute drift",
+			stdin: "s",
+			api: mock.API{
+				GetServiceDetailsFn: remoteService("remote-name", "local description"),
+				UpdateServiceFn:     updateServiceOK,
+			},
+			wantAudit:    []string{"compute drift"},
+			wantUpdate:   &fastly.UpdateServiceInput{ServiceID: "123", Name: fastly.ToPointer("local-name")},
+			wantManifest: driftManifest,
+		},
+		{
+			name:         "prompt to keep",
+			args:         "compute drift",
+			stdin:        "k",
+			api:          mock.API{GetServiceDetailsFn: remoteService("remote-name", "local description")},
+			wantManifest: driftManifest,
+			wantOutput:   []string{"Kept fastly.toml and the service as they are"},
+		},
+		{
+			name:           "non-interactive refusal",
+			args:           "compute drift",
+			nonInteractive: true,
+			api:            mock.API{GetServiceDetailsFn: remoteService("remote-name", "local description")},
+			wantManifest:   driftManifest,
+			wantOutput:     []string{"WARNING: fastly.toml has drifted from service 123"},
+			wantError:      "fastly.toml has drifted from the service",
+		},
+		{
+			name:         "accept remote service ID",
+			args:         "compute drift --service-id 456 --accept-remote",
+			api:          mock.API{GetServiceDetailsFn: remoteService("local-name", "local description")},
+			wantManifest: strings.Replace(driftManifest, `"123"`, `"456"`, 1),
+		},
+		{
+			name:         "push local service ID",
+			args:         "compute drift --service-id 456 --push-local",
+			api:          mock.API{GetServiceDetailsFn: remoteService("local-name", "local description")},
+			wantManifest: driftManifest,
+			wantOutput:   []string{"A service can't be changed to match service_id, only reconciled with --accept-remote"},
+		},
+		{
+			name:         "both directions",
+			args:         "compute drift --accept-remote --push-local",
+			wantManifest: driftManifest,
+			wantError:    "invalid flag combination: --accept-remote and --push-local",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			rootdir := testutil.NewEnv(testutil.EnvOpts{
+				T:     t,
+				Write: []testutil.FileIO{{Src: driftManifest, Dst: manifest.Filename}},
+			})
+			defer os.RemoveAll(rootdir)
+
+			var update *fastly.UpdateServiceInput
+			api := testcase.api
+			if api.UpdateServiceFn != nil {
+				fn := api.UpdateServiceFn
+				api.UpdateServiceFn = func(i *fastly.UpdateServiceInput) (*fastly.Service, error) {
+					update = i
+					return fn(i)
+				}
+			}
+
+			args := testutil.Args(testcase.args + " --dir " + rootdir)
+			auditLog := filepath.Join(t.TempDir(), "audit.log")
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIClientFactory = mock.APIClient(api)
+				opts.AuditLogPath = auditLog
+				opts.Config.Audit.Enabled = true
+				opts.Flags.NonInteractive = testcase.nonInteractive
+				opts.Input = strings.NewReader(testcase.stdin)
+				if testcase.tokenScope != "" {
+					opts.TokenSelf = func() (*fastly.Token, error) {
+						return &fastly.Token{Scope: fastly.ToPointer(testcase.tokenScope)}, nil
+					}
+				}
+				return opts, nil
+			}
+			err := app.Run(args, nil)
+			t.Log(stdout.String())
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			testutil.AssertEqual(t, testcase.wantUpdate, update)
+
+			entries, err := audit.Read(auditLog)
+			testutil.AssertNoError(t, err)
+			var audited []string
+			for _, e := range entries {
+				// NOTE: The trailing --dir flag is dropped, as it's a temporary
+				// directory.
+				audited = append(audited, strings.Join(e.Args[:len(e.Args)-2], " "))
+			}
+			testutil.AssertEqual(t, testcase.wantAudit, audited)
+
+			data, err := os.ReadFile(filepath.Join(rootdir, manifest.Filename))
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutil.AssertString(t, testcase.wantManifest, string(data))
+		})
+	}
+}
+
+// TestDeployDrift validates deploy warns about drift without stopping, and
+// reconciles it when asked to.
+func TestDeployDrift(t *testing.T) {
+	scenarios := []struct {
+		name         string
+		args         string
+		wantManifest string
+		wantOutput   []string
+	}{
+		{
+			name:         "warn",
+			args:         "compute deploy --non-interactive",
+			wantManifest: driftManifest,
+			wantOutput: []string{
+				"WARNING: fastly.toml has drifted from service 123",
+				"Deploying without reconciling",
+				"Deployed package (service 123, version 4)",
+			},
+		},
+		{
+			name:         "accept remote",
+			args:         "compute deploy --non-interactive --accept-remote",
+			wantManifest: strings.Replace(driftManifest, "local description", "edited in the UI", 1),
+			wantOutput:   []string{"Updated fastly.toml from service 123"},
+		},
+		{
+			name:         "no check for another service",
+			args:         "compute deploy --non-interactive --service-id 456",
+			wantManifest: driftManifest,
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			pwd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+			rootdir := testutil.NewEnv(testutil.EnvOpts{
+				T: t,
+				Copy: []testutil.FileIO{
+					{
+						Src: filepath.Join("testdata", "deploy", "pkg", "package.tar.gz"),
+						Dst: filepath.Join("pkg", "package.tar.gz"),
+					},
+				},
+				Write: []testutil.FileIO{{Src: driftManifest, Dst: manifest.Filename}},
+			})
+			defer os.RemoveAll(rootdir)
+			if err := os.Chdir(rootdir); err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = os.Chdir(pwd)
+			}()
+
+			args := testutil.Args(testcase.args + " --package pkg/package.tar.gz")
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIClientFactory = mock.APIClient(mock.API{
+					ActivateVersionFn: activateVersionOk,
+					CloneVersionFn:    testutil.CloneVersionResult(4),
+					GetPackageFn:      getPackageOk,
+					GetServiceDetailsFn: func(i *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+						return &fastly.ServiceDetail{
+							ServiceID: fastly.ToPointer(i.ServiceID),
+							Name:      fastly.ToPointer("local-name"),
+							Comment:   fastly.ToPointer("edited in the UI"),
+							Type:      fastly.ToPointer("wasm"),
+						}, nil
+					},
+					GetServiceFn:    getServiceOK,
+					ListDomainsFn:   listDomainsOk,
+					ListVersionsFn:  testutil.ListVersions,
+					UpdatePackageFn: updatePackageOk,
+				})
+				return opts, nil
+			}
+			err = app.Run(args, nil)
+			t.Log(stdout.String())
+			testutil.AssertNoError(t, err)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			if testcase.wantOutput == nil && strings.Contains(stdout.String(), "drifted") {
+				t.Errorf("want no drift warning, have: %s", stdout.String())
+			}
+
+			data, err := os.ReadFile(manifest.Filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutil.AssertString(t, testcase.wantManifest, string(data))
+		})
+	}
+}
+
+func updateServiceOK(i *fastly.UpdateServiceInput) (*fastly.Service, error) {
+	return &fastly.Service{ServiceID: fastly.ToPointer(i.ServiceID)}, nil
+}
@@ -0,0 +1,187 @@
+package compute
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	toml "github.com/pelletier/go-toml"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// GuestEnvConfigStore is the config store `compute serve` exposes the guest
+// environment through (see --env-var and [local_server.env]).
+//
+// NOTE: Viceroy doesn't pass environment variables to the guest, so the values
+// are read the same way a deployed service reads its configuration.
+const GuestEnvConfigStore = "env"
+
+// LocalServerConfigFilename is the Viceroy config generated within StateDir
+// when a guest environment is set.
+const LocalServerConfigFilename = "local_server.toml"
+
+// The sources of a GuestEnvValue.
+const (
+	GuestEnvSourceFlag        = "--env-var"
+	GuestEnvSourceEnvironment = "--env-var, from the environment"
+	GuestEnvSourceManifest    = "[local_server.env]"
+)
+
+// GuestEnvValue is a value of the guest environment.
+type GuestEnvValue struct {
+	// Value is the value the guest reads.
+	Value string
+	// Secret indicates the value is redacted from output.
+	Secret bool
+	// Source is where the value came from (e.g. GuestEnvSourceFlag).
+	Source string
+}
+
+// String returns the value for display, redacted if it's secret.
+func (v GuestEnvValue) String() string {
+	if v.Secret {
+		return "REDACTED"
+	}
+	return v.Value
+}
+
+// ResolveGuestEnv merges the [local_server.env] values of the manifest with
+// the --env-var flags, which take precedence.
+//
+// A flag is either KEY=VALUE, or KEY to pass through the variable of the CLI's
+// environment. A value passed through is secret, as are the values named by
+// [local_server.secret_env] whatever their source.
+func ResolveGuestEnv(ls manifest.LocalServer, flags []string, lookupEnv func(string) (string, bool)) (map[string]GuestEnvValue, error) {
+	secret := make(map[string]bool, len(ls.SecretEnv))
+	for _, k := range ls.SecretEnv {
+		secret[k] = true
+	}
+
+	env := make(map[string]GuestEnvValue, len(ls.Env)+len(flags))
+	for k, v := range ls.Env {
+		env[k] = GuestEnvValue{Value: v, Secret: secret[k], Source: GuestEnvSourceManifest}
+	}
+	for _, f := range flags {
+		k, v, hasValue := strings.Cut(f, "=")
+		if k = strings.TrimSpace(k); k == "" {
+			return nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("invalid --env-var '%s': missing key", f),
+				Remediation: "Use --env-var KEY=VALUE, or --env-var KEY to pass through the value of an environment variable.",
+			}
+		}
+		if !hasValue {
+			value, ok := lookupEnv(k)
+			if !ok {
+				return nil, fsterr.RemediationError{
+					Inner:       fmt.Errorf("invalid --env-var '%s': the environment variable isn't set", k),
+					Remediation: fmt.Sprintf("Set the %s environment variable, or give its value with --env-var %s=VALUE.", k, k),
+				}
+			}
+			env[k] = GuestEnvValue{Value: value, Secret: true, Source: GuestEnvSourceEnvironment}
+			continue
+		}
+		env[k] = GuestEnvValue{Value: v, Secret: secret[k], Source: GuestEnvSourceFlag}
+	}
+	return env, nil
+}
+
+// GenerateLocalServerConfig returns the Viceroy config for the manifest with
+// the guest environment (see ResolveGuestEnv) added as the
+// GuestEnvConfigStore config store. The config is nil when there's no guest
+// environment, in which case Viceroy is run with the manifest itself.
+//
+// NOTE: The manifest is read from disk (rather than the in-memory manifest)
+// so that changes made while watching are applied when the server reloads.
+func GenerateLocalServerConfig(manifestPath string, flags []string, lookupEnv func(string) (string, bool)) ([]byte, map[string]GuestEnvValue, error) {
+	tree, err := toml.LoadFile(manifestPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read manifest '%s': %w", manifestPath, err)
+	}
+	var m struct {
+		LocalServer manifest.LocalServer `toml:"local_server"`
+	}
+	if err := tree.Unmarshal(&m); err != nil {
+		return nil, nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("failed to parse the [local_server] section of '%s': %w", manifestPath, err),
+			Remediation: "Ensure the values of [local_server.env] are strings, and `secret_env` is a list of their names.",
+		}
+	}
+
+	env, err := ResolveGuestEnv(m.LocalServer, flags, lookupEnv)
+	if err != nil || len(env) == 0 {
+		return nil, env, err
+	}
+
+	store := []string{"local_server", "config_stores", GuestEnvConfigStore}
+	if tree.HasPath(store) {
+		return nil, nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("the config store '%s' is already defined in '%s'", GuestEnvConfigStore, manifestPath),
+			Remediation: fmt.Sprintf("The guest environment is exposed as the '%s' config store. Rename the [local_server.config_stores.%s] section, or move its contents to [local_server.env].", GuestEnvConfigStore, GuestEnvConfigStore),
+		}
+	}
+	for _, key := range []string{"env", "secret_env"} {
+		if path := []string{"local_server", key}; tree.HasPath(path) {
+			_ = tree.DeletePath(path)
+		}
+	}
+
+	contents := make(map[string]any, len(env))
+	for k, v := range env {
+		contents[k] = v.Value
+	}
+	contentsTree, err := toml.TreeFromMap(contents)
+	if err != nil {
+		return nil, nil, err
+	}
+	tree.SetPath(append(store, "format"), "inline-toml")
+	tree.SetPath(append(store, "contents"), contentsTree)
+
+	s, err := tree.ToTomlString()
+	if err != nil {
+		return nil, nil, err
+	}
+	return []byte(s), env, nil
+}
+
+// localServerConfig returns the config Viceroy is run with. That's the
+// manifest, unless a guest environment is set, in which case a config is
+// generated from it within the project's StateDir.
+func (c *ServeCommand) localServerConfig(manifestPath string, out io.Writer) (string, error) {
+	config, env, err := GenerateLocalServerConfig(manifestPath, c.envVars, os.LookupEnv)
+	if err != nil {
+		return "", err
+	}
+	if config == nil {
+		return manifestPath, nil
+	}
+
+	dir := filepath.Join(filepath.Dir(manifestPath), StateDir)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create directory '%s': %w", dir, err)
+	}
+	// NOTE: The config contains the secret values, so only the user can read it.
+	path := filepath.Join(dir, LocalServerConfigFilename)
+	if err := os.WriteFile(path, config, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write local server config '%s': %w", path, err)
+	}
+
+	if c.Globals.Verbose() {
+		keys := make([]string, 0, len(env))
+		for k := range env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		text.Info(out, "The guest environment is readable from the '%s' config store:", GuestEnvConfigStore)
+		for _, k := range keys {
+			text.Output(out, "  %s=%s (via %s)", k, env[k], env[k].Source)
+		}
+		text.Break(out)
+	}
+	return path, nil
+}
@@ -41,10 +41,13 @@ type HashFilesCommand struct {
 	metadataFilterEnvVars argparser.OptionalString
 	metadataShow          argparser.OptionalBool
 	packageName           argparser.OptionalString
+	reproducible          argparser.OptionalBool
+	skipToolchainCheck    argparser.OptionalBool
 	timeout               argparser.OptionalInt
 
 	buildCmd  *BuildCommand
 	Package   string
+	RunHooks  bool
 	SkipBuild bool
 }
 
@@ -63,7 +66,10 @@ func NewHashFilesCommand(parent argparser.Registerer, g *global.Data, build *Bui
 	c.CmdClause.Flag("metadata-show", "Inspect the Wasm binary metadata").Action(c.metadataShow.Set).BoolVar(&c.metadataShow.Value)
 	c.CmdClause.Flag("package", "Path to a package tar.gz").Short('p').StringVar(&c.Package)
 	c.CmdClause.Flag("package-name", "Package name").Action(c.packageName.Set).StringVar(&c.packageName.Value)
-	c.CmdClause.Flag("skip-build", "Skip the build step").BoolVar(&c.SkipBuild)
+	c.CmdClause.Flag("reproducible", "Build a byte-identical package from identical sources (timestamps come from SOURCE_DATE_EPOCH, and environment-dependent metadata is omitted)").Action(c.reproducible.Set).BoolVar(&c.reproducible.Value)
+	c.CmdClause.Flag("run-hooks", "Run the [scripts] pre_build and post_build hooks when the build step is skipped").BoolVar(&c.RunHooks)
+	c.CmdClause.Flag("skip-build", "Skip the build step (and the pre_build and post_build hooks, unless --run-hooks is set)").BoolVar(&c.SkipBuild)
+	c.CmdClause.Flag("skip-toolchain-check", "Skip checking the installed toolchain version meets the requirements (Go only)").Action(c.skipToolchainCheck.Set).BoolVar(&c.skipToolchainCheck.Value)
 	c.CmdClause.Flag("timeout", "Timeout, in seconds, for the build compilation step").Action(c.timeout.Set).IntVar(&c.timeout.Value)
 
 	return &c
@@ -118,6 +124,12 @@ func (c *HashFilesCommand) Exec(in io.Reader, out io.Writer) (err error) {
 			return err
 		}
 
+		if c.SkipBuild && c.RunHooks {
+			if err := c.runHooks(in, out); err != nil {
+				return err
+			}
+		}
+
 		projectName, source := c.Globals.Manifest.Name()
 		if source == manifest.SourceUndefined {
 			return fsterr.ErrReadingManifest
@@ -145,6 +157,23 @@ func (c *HashFilesCommand) Build(in io.Reader, out io.Writer) error {
 	if !c.Globals.Verbose() {
 		output = io.Discard
 	}
+	c.configureBuild()
+	return c.buildCmd.Exec(in, output)
+}
+
+// runHooks runs the pre_build and post_build hooks without building the
+// project.
+func (c *HashFilesCommand) runHooks(in io.Reader, out io.Writer) error {
+	output := out
+	if !c.Globals.Verbose() {
+		output = io.Discard
+	}
+	c.configureBuild()
+	return c.buildCmd.RunHooks(in, output)
+}
+
+// configureBuild sets the flags of the BuildCommand from the HashFilesCommand.
+func (c *HashFilesCommand) configureBuild() {
 	if c.dir.WasSet {
 		c.buildCmd.Flags.Dir = c.dir.Value
 	}
@@ -160,6 +189,12 @@ func (c *HashFilesCommand) Build(in io.Reader, out io.Writer) error {
 	if c.packageName.WasSet {
 		c.buildCmd.Flags.PackageName = c.packageName.Value
 	}
+	if c.reproducible.WasSet {
+		c.buildCmd.Flags.Reproducible = c.reproducible.Value
+	}
+	if c.skipToolchainCheck.WasSet {
+		c.buildCmd.Flags.SkipToolchainCheck = c.skipToolchainCheck.Value
+	}
 	if c.timeout.WasSet {
 		c.buildCmd.Flags.Timeout = c.timeout.Value
 	}
@@ -172,7 +207,6 @@ func (c *HashFilesCommand) Build(in io.Reader, out io.Writer) error {
 	if c.metadataShow.WasSet {
 		c.buildCmd.MetadataShow = c.metadataShow.Value
 	}
-	return c.buildCmd.Exec(in, output)
 }
 
 // getFilesHash returns a hash of all the files in the package in sorted filename order.
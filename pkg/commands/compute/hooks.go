@@ -0,0 +1,174 @@
+package compute
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/segmentio/textio"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	fstexec "github.com/fastly/cli/pkg/exec"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/cli/pkg/timing"
+)
+
+// The hooks are custom scripts defined in the [scripts] section of the
+// manifest that are run around the build script.
+const (
+	// HookPreBuild is run before the build script. If it fails, the build is
+	// skipped.
+	HookPreBuild = "pre_build"
+	// HookPostBuild is run after the build script, but before the Wasm binary
+	// is annotated and added to the .tar.gz archive.
+	HookPostBuild = "post_build"
+)
+
+// CustomScriptMessage is the message displayed to a user when there is a
+// pre_build or post_build script defined.
+const CustomScriptMessage = "This project has a custom %s script defined in the %s manifest"
+
+// ScriptRunner executes the build script and hooks. It's a variable so tests
+// can fake it.
+var ScriptRunner = fstexec.Command
+
+// RunHooks runs the pre_build and post_build hooks without building the
+// project. It's used by the composite commands (e.g. `compute serve`) when
+// --skip-build is set along with --run-hooks.
+//
+// NOTE: The manifest must already have been read, from the project directory.
+func (c *BuildCommand) RunHooks(in io.Reader, out io.Writer) error {
+	phase := c.Globals.Timings.Start("hooks")
+	defer phase.End()
+
+	spinner, err := text.NewSpinner(out)
+	if err != nil {
+		return err
+	}
+	manifestFilename := EnvironmentManifest(c.Flags.Env)
+	if err := c.runHook(HookPreBuild, manifestFilename, false, phase, in, out, spinner); err != nil {
+		return err
+	}
+	return c.runHook(HookPostBuild, manifestFilename, false, phase, in, out, spinner)
+}
+
+// runHook runs the named hook (if it's defined in the manifest) as a phase of
+// the build, in the same environment as the build script.
+//
+// The hook's output is prefixed with its name so it can be told apart from
+// the output of the build script. built indicates the build script has run,
+// so a failing post_build can report that the build itself succeeded.
+func (c *BuildCommand) runHook(name, manifestFilename string, built bool, phase *timing.Span, in io.Reader, out io.Writer, spinner text.Spinner) error {
+	scripts := c.Globals.Manifest.File.Scripts
+	script := scripts.PreBuild
+	if name == HookPostBuild {
+		script = scripts.PostBuild
+	}
+	if script == "" {
+		return nil
+	}
+
+	if !c.Globals.Flags.AutoYes && !c.Globals.Flags.NonInteractive {
+		msg := fmt.Sprintf(CustomScriptMessage, name, manifestFilename)
+		if err := promptForHookContinue(name, msg, script, out, in); err != nil {
+			return err
+		}
+	}
+
+	span := phase.Start(name)
+	defer span.End()
+
+	verbose := c.Globals.Verbose()
+	cmd, args := Shell{}.Build(script)
+	msg := fmt.Sprintf("Running [scripts.%s]", name)
+
+	// If we're in verbose mode, the hook output is shown.
+	// So in that case we don't want to have a spinner as it'll interweave output.
+	if !verbose {
+		if err := spinner.Start(); err != nil {
+			return err
+		}
+		spinner.Message(msg + "...")
+	}
+
+	pw := textio.NewPrefixWriter(out, fmt.Sprintf("[%s] ", name))
+	err := ScriptRunner(fstexec.CommandOpts{
+		Args:           args,
+		Command:        cmd,
+		Env:            scripts.EnvVars,
+		ErrLog:         c.Globals.ErrLog,
+		Output:         pw,
+		Spinner:        spinner,
+		SpinnerMessage: msg,
+		Timeout:        c.Flags.Timeout,
+		Verbose:        verbose,
+	})
+	if flushErr := pw.Flush(); flushErr != nil && err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		// In verbose mode we'll have the failure status AFTER the error output.
+		// In non-verbose mode stopping the spinner is handled internally by
+		// fstexec.Streaming.Exec().
+		if verbose {
+			text.Break(out)
+			if spinErr := spinner.Start(); spinErr != nil {
+				return fmt.Errorf(text.SpinnerErrWrapper, spinErr, err)
+			}
+			spinner.Message(msg + "...")
+			spinner.StopFailMessage(msg)
+			if spinErr := spinner.StopFail(); spinErr != nil {
+				return fmt.Errorf(text.SpinnerErrWrapper, spinErr, err)
+			}
+		}
+		return hookError(name, built, err)
+	}
+
+	if verbose {
+		if err := spinner.Start(); err != nil {
+			return err
+		}
+		spinner.Message(msg + "...")
+		text.Break(out)
+	}
+	spinner.StopMessage(msg)
+	return spinner.Stop()
+}
+
+// promptForHookContinue ensures the user is happy to continue with the build
+// when there is a pre_build or post_build in the fastly.toml manifest file.
+func promptForHookContinue(name, msg, script string, out io.Writer, in io.Reader) error {
+	text.Info(out, "%s:\n", msg)
+	text.Indent(out, 4, "%s", script)
+
+	label := "\nDo you want to run this now? [y/N] "
+	answer, err := text.AskYesNo(out, label, in, "--auto-yes")
+	if err != nil {
+		return err
+	}
+	if !answer {
+		if name == HookPostBuild {
+			return fsterr.ErrPostBuildStopped
+		}
+		return fsterr.ErrPreBuildStopped
+	}
+	text.Break(out)
+	return nil
+}
+
+// hookError is returned when a hook fails. A failing pre_build skips the
+// build, whereas a failing post_build means the Wasm binary isn't packaged.
+func hookError(name string, built bool, err error) error {
+	inner := fmt.Errorf("[scripts.%s] failed: %w", name, err)
+	remediation := fmt.Sprintf("Check the [scripts.%s] in the fastly.toml manifest, or re-run the fastly command with the --verbose flag to see its output.", name)
+	switch {
+	case name == HookPreBuild:
+		inner = fmt.Errorf("[scripts.pre_build] failed, so the build was skipped: %w", err)
+	case built:
+		inner = fmt.Errorf("the build succeeded but [scripts.post_build] failed: %w", err)
+		remediation = "The Wasm binary was built (./bin/main.wasm) but it wasn't packaged. " + remediation
+	}
+	return fsterr.RemediationError{
+		Inner:       inner,
+		Remediation: remediation,
+	}
+}
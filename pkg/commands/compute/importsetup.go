@@ -0,0 +1,348 @@
+package compute
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// ImportSetupCommand writes the resources of a service version to the [setup]
+// section of fastly.toml, so that deploying the project to a new service
+// recreates them.
+type ImportSetupCommand struct {
+	argparser.Base
+
+	dir            string
+	env            string
+	serviceName    argparser.OptionalServiceNameID
+	serviceVersion argparser.OptionalServiceVersion
+}
+
+// NewImportSetupCommand returns a usable command registered under the parent.
+func NewImportSetupCommand(parent argparser.Registerer, g *global.Data) *ImportSetupCommand {
+	var c ImportSetupCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("import-setup", "Import the backends, dictionaries and stores of a service version into the [setup] section of fastly.toml")
+
+	// Required.
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Name:        argparser.FlagVersionName,
+		Description: argparser.FlagVersionDesc,
+		Dst:         &c.serviceVersion.Value,
+		Required:    true,
+	})
+
+	// Optional.
+	c.CmdClause.Flag("dir", "Project directory (default: current directory)").Short('C').StringVar(&c.dir)
+	c.CmdClause.Flag("env", "The manifest environment config to use (e.g. 'stage' will attempt to read 'fastly.stage.toml')").StringVar(&c.env)
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Name:        argparser.FlagServiceIDName,
+		Description: argparser.FlagServiceIDDesc,
+		Dst:         &c.Globals.Manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        argparser.FlagServiceName,
+		Description: argparser.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	return &c
+}
+
+// setupSection is a [setup] section that can be imported from a service.
+type setupSection struct {
+	// key is the section's key in the [setup] table (e.g. "backends").
+	key string
+	// noun describes the section's resources (e.g. "backends").
+	noun string
+	// value is the section's resources, as encoded in the manifest.
+	value func(s manifest.Setup) any
+	// count is the number of resources in the section.
+	count func(s manifest.Setup) int
+}
+
+// setupSections are the sections written by import-setup, in order.
+var setupSections = []setupSection{
+	{
+		key:   "backends",
+		noun:  "backends",
+		value: func(s manifest.Setup) any { return s.Backends },
+		count: func(s manifest.Setup) int { return len(s.Backends) },
+	},
+	{
+		key:   "config_stores",
+		noun:  "config stores",
+		value: func(s manifest.Setup) any { return s.ConfigStores },
+		count: func(s manifest.Setup) int { return len(s.ConfigStores) },
+	},
+	{
+		key:   "kv_stores",
+		noun:  "KV stores",
+		value: func(s manifest.Setup) any { return s.KVStores },
+		count: func(s manifest.Setup) int { return len(s.KVStores) },
+	},
+	{
+		key:   "secret_stores",
+		noun:  "secret stores",
+		value: func(s manifest.Setup) any { return s.SecretStores },
+		count: func(s manifest.Setup) int { return len(s.SecretStores) },
+	},
+}
+
+// Exec invokes the application logic for the command.
+func (c *ImportSetupCommand) Exec(in io.Reader, out io.Writer) error {
+	projectDir := c.dir
+	if projectDir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current working directory: %w", err)
+		}
+		projectDir = wd
+	}
+	manifestPath := filepath.Join(projectDir, EnvironmentManifest(c.env))
+	if err := c.Globals.Manifest.File.Read(manifestPath); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = fsterr.ErrReadingManifest
+		}
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	serviceID, serviceVersion, err := argparser.ServiceDetails(argparser.ServiceDetailsOpts{
+		AllowActiveLocked:  true,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           *c.Globals.Manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": fsterr.ServiceVersion(serviceVersion),
+		})
+		return err
+	}
+	version := fastly.ToValue(serviceVersion.Number)
+
+	imported, err := ImportSetup(c.Globals.APIClient, serviceID, version, out)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": version,
+		})
+		return err
+	}
+
+	filename := filepath.Base(manifestPath)
+	existing := c.Globals.Manifest.File.Setup
+	var written int
+	for _, section := range setupSections {
+		n := section.count(imported)
+		if n == 0 {
+			continue
+		}
+		table := "setup." + section.key
+		if section.count(existing) > 0 {
+			overwrite, err := c.confirmOverwrite(table, filename, in, out)
+			if err != nil {
+				return err
+			}
+			if !overwrite {
+				text.Info(out, "Kept [%s] in %s as it is.", table, filename)
+				continue
+			}
+		}
+
+		content, err := manifest.EncodeTables(table, section.value(imported))
+		if err == nil {
+			err = manifest.SetTables(manifestPath, table, content)
+		}
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error updating %s: %w", filename, err)
+		}
+		written++
+		text.Success(out, "Imported %d %s into [%s]", n, section.noun, table)
+	}
+
+	if written == 0 {
+		text.Info(out, "Nothing was imported from service %s version %d.", serviceID, version)
+		return nil
+	}
+	for _, s := range imported.SecretStores {
+		if len(s.Entries) > 0 {
+			text.Info(out, "Secret values aren't imported: `fastly compute deploy` prompts for them when it creates the secret stores.")
+			break
+		}
+	}
+	text.Break(out)
+	text.Info(out, "The [setup] section of %s is used when the project is deployed to a new service (i.e. without a service ID).", filename)
+	return nil
+}
+
+// confirmOverwrite asks whether a section that's already in the manifest
+// should be replaced. It's only replaced without asking with --auto-yes.
+func (c *ImportSetupCommand) confirmOverwrite(table, filename string, in io.Reader, out io.Writer) (bool, error) {
+	if c.Globals.Flags.AutoYes {
+		return true, nil
+	}
+	if c.Globals.Flags.NonInteractive {
+		text.Warning(out, "[%s] is already in %s (use --auto-yes to replace it).", table, filename)
+		return false, nil
+	}
+	return text.AskYesNo(out, text.WarningStyle(fmt.Sprintf("[%s] is already in %s. Replace it? [y/N]: ", table, filename)), in, "--auto-yes")
+}
+
+// ImportSetup reads the resources of a service version as [setup] sections.
+//
+// Dictionaries are imported as config stores, as [setup] has no dictionaries
+// and both are read the same way by a Compute package. Secret values can't be
+// read (only the names of the secrets are imported) and KV store items aren't
+// imported, as they're data rather than configuration. Anything that isn't
+// imported is reported to out.
+func ImportSetup(client api.Interface, serviceID string, serviceVersion int, out io.Writer) (manifest.Setup, error) {
+	var s manifest.Setup
+
+	backends, err := client.ListBackends(&fastly.ListBackendsInput{ServiceID: serviceID, ServiceVersion: serviceVersion})
+	if err != nil {
+		return s, fmt.Errorf("error listing backends: %w", err)
+	}
+	for _, b := range backends {
+		if s.Backends == nil {
+			s.Backends = make(map[string]*manifest.SetupBackend)
+		}
+		s.Backends[fastly.ToValue(b.Name)] = &manifest.SetupBackend{
+			Address:     fastly.ToValue(b.Address),
+			Port:        fastly.ToValue(b.Port),
+			Description: fastly.ToValue(b.Comment),
+		}
+	}
+
+	resources, err := client.ListResources(&fastly.ListResourcesInput{ServiceID: serviceID, ServiceVersion: serviceVersion})
+	if err != nil {
+		return s, fmt.Errorf("error listing resource links: %w", err)
+	}
+	// NOTE: A resource is imported by the name of its link (rather than of the
+	// store), as that's the name the package uses and the name deploy gives a
+	// store it creates.
+	for _, r := range resources {
+		name, storeID := fastly.ToValue(r.Name), fastly.ToValue(r.ResourceID)
+		switch resourceKind(fastly.ToValue(r.ResourceType)) {
+		case "config":
+			items, err := client.ListConfigStoreItems(&fastly.ListConfigStoreItemsInput{StoreID: storeID})
+			if err != nil {
+				return s, fmt.Errorf("error listing the items of config store '%s': %w", name, err)
+			}
+			store := &manifest.SetupConfigStore{}
+			for _, item := range items {
+				if store.Items == nil {
+					store.Items = make(map[string]manifest.SetupConfigStoreItems)
+				}
+				store.Items[item.Key] = manifest.SetupConfigStoreItems{Value: item.Value}
+			}
+			if s.ConfigStores == nil {
+				s.ConfigStores = make(map[string]*manifest.SetupConfigStore)
+			}
+			s.ConfigStores[name] = store
+		case "kv":
+			if s.KVStores == nil {
+				s.KVStores = make(map[string]*manifest.SetupKVStore)
+			}
+			s.KVStores[name] = &manifest.SetupKVStore{}
+			text.Info(out, "The items of KV store '%s' aren't imported.", name)
+		case "secret":
+			store := &manifest.SetupSecretStore{}
+			input := &fastly.ListSecretsInput{StoreID: storeID}
+			for {
+				secrets, err := client.ListSecrets(input)
+				if err != nil {
+					return s, fmt.Errorf("error listing the secrets of secret store '%s': %w", name, err)
+				}
+				for _, secret := range secrets.Data {
+					if store.Entries == nil {
+						store.Entries = make(map[string]manifest.SetupSecretStoreEntry)
+					}
+					store.Entries[secret.Name] = manifest.SetupSecretStoreEntry{}
+				}
+				if secrets.Meta.NextCursor == "" || secrets.Meta.NextCursor == input.Cursor {
+					break
+				}
+				input.Cursor = secrets.Meta.NextCursor
+			}
+			if s.SecretStores == nil {
+				s.SecretStores = make(map[string]*manifest.SetupSecretStore)
+			}
+			s.SecretStores[name] = store
+		default:
+			text.Info(out, "Resource '%s' (type %s) isn't supported by [setup] and wasn't imported.", name, fastly.ToValue(r.ResourceType))
+		}
+	}
+
+	dictionaries, err := client.ListDictionaries(&fastly.ListDictionariesInput{ServiceID: serviceID, ServiceVersion: serviceVersion})
+	if err != nil {
+		return s, fmt.Errorf("error listing dictionaries: %w", err)
+	}
+	var skipped []string
+	for _, d := range dictionaries {
+		name := fastly.ToValue(d.Name)
+		if _, ok := s.ConfigStores[name]; ok {
+			skipped = append(skipped, name)
+			continue
+		}
+		if fastly.ToValue(d.WriteOnly) {
+			text.Info(out, "Dictionary '%s' is write-only, so its items can't be imported.", name)
+		}
+		items, err := client.ListDictionaryItems(&fastly.ListDictionaryItemsInput{ServiceID: serviceID, DictionaryID: fastly.ToValue(d.DictionaryID)})
+		if err != nil {
+			return s, fmt.Errorf("error listing the items of dictionary '%s': %w", name, err)
+		}
+		store := &manifest.SetupConfigStore{}
+		for _, item := range items {
+			if store.Items == nil {
+				store.Items = make(map[string]manifest.SetupConfigStoreItems)
+			}
+			store.Items[fastly.ToValue(item.ItemKey)] = manifest.SetupConfigStoreItems{Value: fastly.ToValue(item.ItemValue)}
+		}
+		if s.ConfigStores == nil {
+			s.ConfigStores = make(map[string]*manifest.SetupConfigStore)
+		}
+		s.ConfigStores[name] = store
+	}
+	if len(skipped) > 0 {
+		sort.Strings(skipped)
+		text.Warning(out, "Dictionaries with the same name as a config store weren't imported: %s", strings.Join(skipped, ", "))
+	}
+
+	return s, nil
+}
+
+// resourceKind returns the kind of store a resource link's type refers to
+// (i.e. "config", "kv" or "secret").
+func resourceKind(resourceType string) string {
+	t := strings.ToLower(resourceType)
+	switch {
+	case strings.Contains(t, "secret"):
+		return "secret"
+	case strings.Contains(t, "kv"), strings.Contains(t, "object"):
+		return "kv"
+	case strings.Contains(t, "config"):
+		return "config"
+	}
+	return ""
+}
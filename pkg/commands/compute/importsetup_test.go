@@ -0,0 +1,184 @@
+package compute_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+const importSetupManifest = `# This file describes a Fastly Compute package.
+manifest_version = 3
+name = "package" # the package name
+service_id = "123"
+
+# Settings for the local server.
+[local_server]
+[local_server.backends.origin]
+url = "http://127.0.0.1:8080"
+`
+
+// importSetupAPI is a service version with a resource of each kind.
+var importSetupAPI = mock.API{
+	ListVersionsFn: testutil.ListVersions,
+	ListBackendsFn: func(_ *fastly.ListBackendsInput) ([]*fastly.Backend, error) {
+		return []*fastly.Backend{
+			{Name: fastly.ToPointer("origin"), Address: fastly.ToPointer("example.com"), Port: fastly.ToPointer(443), Comment: fastly.ToPointer("the \"main\" origin")},
+			{Name: fastly.ToPointer("my api"), Address: fastly.ToPointer("127.0.0.1"), Port: fastly.ToPointer(8080)},
+		}, nil
+	},
+	ListResourcesFn: func(_ *fastly.ListResourcesInput) ([]*fastly.Resource, error) {
+		return []*fastly.Resource{
+			{Name: fastly.ToPointer("flags"), ResourceID: fastly.ToPointer("cs1"), ResourceType: fastly.ToPointer("config")},
+			{Name: fastly.ToPointer("assets"), ResourceID: fastly.ToPointer("kv1"), ResourceType: fastly.ToPointer("kv-store")},
+			{Name: fastly.ToPointer("credentials"), ResourceID: fastly.ToPointer("ss1"), ResourceType: fastly.ToPointer("secret-store")},
+		}, nil
+	},
+	ListConfigStoreItemsFn: func(i *fastly.ListConfigStoreItemsInput) ([]*fastly.ConfigStoreItem, error) {
+		return []*fastly.ConfigStoreItem{
+			{StoreID: i.StoreID, Key: "beta", Value: "true"},
+			{StoreID: i.StoreID, Key: "greeting", Value: "hello\nworld"},
+		}, nil
+	},
+	ListSecretsFn: func(i *fastly.ListSecretsInput) (*fastly.Secrets, error) {
+		if i.Cursor == "" {
+			return &fastly.Secrets{
+				Data: []fastly.Secret{{Name: "api_key"}},
+				Meta: fastly.SecretStoreMeta{NextCursor: "page2"},
+			}, nil
+		}
+		return &fastly.Secrets{Data: []fastly.Secret{{Name: "password"}}}, nil
+	},
+	ListDictionariesFn: func(_ *fastly.ListDictionariesInput) ([]*fastly.Dictionary, error) {
+		return []*fastly.Dictionary{{Name: fastly.ToPointer("geo"), DictionaryID: fastly.ToPointer("d1")}}, nil
+	},
+	ListDictionaryItemsFn: func(_ *fastly.ListDictionaryItemsInput) ([]*fastly.DictionaryItem, error) {
+		return []*fastly.DictionaryItem{
+			{ItemKey: fastly.ToPointer("GB"), ItemValue: fastly.ToPointer("eu")},
+			{ItemKey: fastly.ToPointer("US"), ItemValue: fastly.ToPointer("us")},
+		}, nil
+	},
+}
+
+func TestImportSetup(t *testing.T) {
+	rootdir := testutil.NewEnv(testutil.EnvOpts{
+		T:     t,
+		Write: []testutil.FileIO{{Src: importSetupManifest, Dst: manifest.Filename}},
+	})
+	defer os.RemoveAll(rootdir)
+
+	args := testutil.Args("compute import-setup --version 1 --dir " + rootdir)
+	var stdout bytes.Buffer
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		opts := testutil.MockGlobalData(args, &stdout)
+		opts.APIClientFactory = mock.APIClient(importSetupAPI)
+		return opts, nil
+	}
+	err := app.Run(args, nil)
+	t.Log(stdout.String())
+	testutil.AssertNoError(t, err)
+	for _, s := range []string{
+		"Imported 2 backends into [setup.backends]",
+		"Imported 2 config stores into [setup.config_stores]",
+		"Imported 1 KV stores into [setup.kv_stores]",
+		"Imported 1 secret stores into [setup.secret_stores]",
+		"The items of KV store 'assets' aren't imported",
+		"Secret values aren't imported",
+	} {
+		testutil.AssertStringContains(t, stdout.String(), s)
+	}
+
+	path := filepath.Join(rootdir, manifest.Filename)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertGolden(t, filepath.Join("testdata", "import-setup", "fastly.toml"), string(data))
+
+	// The generated sections must be read back as the setup that was imported,
+	// so that a deploy to a new service recreates the same resources.
+	imported, err := compute.ImportSetup(importSetupAPI, "123", 1, io.Discard)
+	testutil.AssertNoError(t, err)
+	var m manifest.File
+	testutil.AssertNoError(t, m.Read(path))
+	testutil.AssertEqual(t, imported, m.Setup)
+	testutil.AssertEqual(t, "http://127.0.0.1:8080", m.LocalServer.Backends["origin"].URL)
+}
+
+func TestImportSetupExistingSection(t *testing.T) {
+	const existing = importSetupManifest + `
+[setup.backends.legacy]
+address = "legacy.example.com"
+`
+	scenarios := []struct {
+		name       string
+		args       string
+		stdin      string
+		wantLegacy bool
+		wantOutput string
+	}{
+		{
+			name:       "non-interactive",
+			args:       "--non-interactive",
+			wantLegacy: true,
+			wantOutput: "WARNING: [setup.backends] is already in fastly.toml (use --auto-yes to replace it)",
+		},
+		{
+			name:       "auto-yes",
+			args:       "--auto-yes",
+			wantOutput: "Imported 2 backends into [setup.backends]",
+		},
+		{
+			name:       "prompt to replace",
+			stdin:      "y",
+			wantOutput: "Imported 2 backends into [setup.backends]",
+		},
+		{
+			name:       "prompt to keep",
+			stdin:      "n",
+			wantLegacy: true,
+			wantOutput: "Kept [setup.backends] in fastly.toml as it is",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			rootdir := testutil.NewEnv(testutil.EnvOpts{
+				T:     t,
+				Write: []testutil.FileIO{{Src: existing, Dst: manifest.Filename}},
+			})
+			defer os.RemoveAll(rootdir)
+
+			args := testutil.Args(strings.TrimSpace("compute import-setup --version 1 --dir " + rootdir + " " + testcase.args))
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIClientFactory = mock.APIClient(importSetupAPI)
+				opts.Input = strings.NewReader(testcase.stdin)
+				return opts, nil
+			}
+			err := app.Run(args, nil)
+			t.Log(stdout.String())
+			testutil.AssertNoError(t, err)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+
+			var m manifest.File
+			testutil.AssertNoError(t, m.Read(filepath.Join(rootdir, manifest.Filename)))
+			_, legacy := m.Setup.Backends["legacy"]
+			testutil.AssertEqual(t, testcase.wantLegacy, legacy)
+			_, origin := m.Setup.Backends["origin"]
+			testutil.AssertEqual(t, !testcase.wantLegacy, origin)
+			testutil.AssertEqual(t, 2, len(m.Setup.ConfigStores))
+		})
+	}
+}
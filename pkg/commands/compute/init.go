@@ -15,8 +15,6 @@ import (
 	"strings"
 	"time"
 
-	cp "github.com/otiai10/copy"
-
 	"github.com/fastly/cli/pkg/argparser"
 	"github.com/fastly/cli/pkg/config"
 	fsterr "github.com/fastly/cli/pkg/errors"
@@ -30,7 +28,7 @@ import (
 )
 
 var (
-	gitRepositoryRegEx        = regexp.MustCompile(`((git|ssh|http(s)?)|(git@[\w\.]+))(:(//)?)([\w\.@\:/\-~]+)(\.git)?(/)?`)
+	gitRepositoryRegEx        = regexp.MustCompile(`((git|ssh|file|http(s)?)|(git@[\w\.]+))(:(//)?)([\w\.@\:/\-~]+)(\.git)?(/)?`)
 	fastlyOrgRegEx            = regexp.MustCompile(`^https:\/\/github\.com\/fastly`)
 	fastlyFileIgnoreListRegEx = regexp.MustCompile(`\.github|LICENSE|SECURITY\.md|CHANGELOG\.md|screenshot\.png`)
 )
@@ -38,12 +36,23 @@ var (
 // InitCommand initializes a Compute project package on the local machine.
 type InitCommand struct {
 	argparser.Base
-
-	branch    string
-	dir       string
-	cloneFrom string
-	language  string
-	tag       string
+	argparser.JSONOutput
+	argparser.NextSteps
+
+	branch     string
+	dir        string
+	cloneFrom  string
+	language   string
+	onConflict string
+	refresh    bool
+	tag        string
+
+	// templateResult is the files of the package template written to the
+	// project directory (nil if there's no template).
+	templateResult *TemplateResult
+	// templateMerged indicates the template was written to a directory that
+	// wasn't empty.
+	templateMerged bool
 }
 
 // Languages is a list of supported language options.
@@ -59,14 +68,29 @@ func NewInitCommand(parent argparser.Registerer, g *global.Data) *InitCommand {
 	c.CmdClause.Flag("branch", "Git branch name to clone from package template repository").Hidden().StringVar(&c.branch)
 	c.CmdClause.Flag("directory", "Destination to write the new package, defaulting to the current directory").Short('p').StringVar(&c.dir)
 	c.CmdClause.Flag("from", "Local project directory, or Git repository URL, or URL referencing a .zip/.tar.gz file, containing a package template").Short('f').StringVar(&c.cloneFrom)
+	c.RegisterFlagBool(c.JSONFlag()) // --json
 	c.CmdClause.Flag("language", "Language of the package").Short('l').HintOptions(Languages...).EnumVar(&c.language, Languages...)
+	c.CmdClause.Flag("on-conflict", "How to handle the files of the package template that already exist in the directory").HintOptions(OnConflictStrategies...).EnumVar(&c.onConflict, OnConflictStrategies...)
+	c.CmdClause.Flag("refresh", "Re-fetch the package template rather than using a cached copy").BoolVar(&c.refresh)
 	c.CmdClause.Flag("tag", "Git tag name to clone from package template repository").Hidden().StringVar(&c.tag)
 
 	return &c
 }
 
+// Interruptible implements the argparser.Interruptible interface. The post_init
+// script is stopped when interrupted.
+func (c *InitCommand) Interruptible() bool {
+	return true
+}
+
 // Exec implements the command interface.
 func (c *InitCommand) Exec(in io.Reader, out io.Writer) (err error) {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+	result := out
+	out = c.ProgressOutput(c.Globals, out)
+
 	var introContext string
 	if c.cloneFrom != "" {
 		introContext = " (using --from to locate package template)"
@@ -175,7 +199,8 @@ func (c *InitCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		}
 	}
 
-	var from, branch, tag string
+	var from string
+	branch, tag := c.branch, c.tag
 
 	// If the user doesn't tell us where to clone from, or there is already a
 	// fastly.toml manifest, or the language they selected was "other" (meaning
@@ -206,7 +231,7 @@ func (c *InitCommand) Exec(in io.Reader, out io.Writer) (err error) {
 	// "other" because this means they intend on handling the compilation of code
 	// that isn't natively supported by the platform.
 	if c.cloneFrom != "" {
-		err = c.FetchPackageTemplate(branch, tag, file.Archives, spinner, out)
+		err = c.FetchPackageTemplate(branch, tag, file.Archives, spinner, in, out)
 		if err != nil {
 			c.Globals.ErrLog.AddWithContext(err, map[string]any{
 				"From":      from,
@@ -241,6 +266,12 @@ func (c *InitCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		return fmt.Errorf("error initializing package: %w", err)
 	}
 
+	// The deploy state recorded in StateDir is specific to this checkout.
+	if err := IgnoreStateDir(dst); err != nil {
+		c.Globals.ErrLog.Add(err)
+		text.Warning(out, "Failed to add %s to .gitignore: %s\n\n", StateDir, err)
+	}
+
 	var md manifest.Data
 	err = md.File.Read(manifest.Filename)
 	if err != nil {
@@ -254,8 +285,7 @@ func (c *InitCommand) Exec(in io.Reader, out io.Writer) (err error) {
 			err := promptForPostInitContinue(msg, postInit, out, in)
 			if err != nil {
 				if errors.Is(err, fsterr.ErrPostInitStopped) {
-					displayInitOutput(mf.Name, dst, language.Name, out)
-					return nil
+					return c.displayInitOutput(result, mf.Name, dst, language.Name)
 				}
 				return err
 			}
@@ -287,6 +317,7 @@ func (c *InitCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		err := fstexec.Command(fstexec.CommandOpts{
 			Args:           args,
 			Command:        command,
+			Context:        c.Globals.Context,
 			Env:            md.File.Scripts.EnvVars,
 			ErrLog:         c.Globals.ErrLog,
 			Output:         out,
@@ -332,8 +363,7 @@ func (c *InitCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		}
 	}
 
-	displayInitOutput(mf.Name, dst, language.Name, out)
-	return nil
+	return c.displayInitOutput(result, mf.Name, dst, language.Name)
 }
 
 // VerifyDirectory indicates if the user wants to continue with the execution
@@ -360,9 +390,14 @@ func (c *InitCommand) VerifyDirectory(in io.Reader, out io.Writer) (cont, notEmp
 		text.Warning(out, "Your project path contains spaces. In some cases this can result in issues with your installed language toolchain, e.g. `npm`. Consider removing any spaces.\n\n")
 	}
 
-	if len(files) > 0 && !flags.AutoYes && !flags.NonInteractive {
+	// The files of a package template are compared to those of the directory
+	// once they're known (see copyTemplate), so the directory only needs
+	// confirming when an existing project is initialized.
+	templated := c.cloneFrom != "" || (!c.Globals.Manifest.File.Exists() && c.language != "other")
+
+	if len(files) > 0 && !templated && !flags.AutoYes && !flags.NonInteractive {
 		label := fmt.Sprintf("The current directory isn't empty. Are you sure you want to initialize a Compute project in %s? [y/N] ", dir)
-		result, err := text.AskYesNo(out, label, in)
+		result, err := text.AskYesNo(out, label, in, "--auto-yes")
 		if err != nil {
 			return false, true, err
 		}
@@ -391,10 +426,7 @@ func (c *InitCommand) VerifyDestination(spinner text.Spinner) (dst string, err e
 	}
 	if err != nil && errors.Is(err, fs.ErrNotExist) { // normal-ish case
 		err := spinner.Process(fmt.Sprintf("Creating %s", dst), func(_ *text.SpinnerWrapper) error {
-			if err := os.MkdirAll(dst, 0o700); err != nil {
-				return fmt.Errorf("error creating package destination: %w", err)
-			}
-			return nil
+			return filesystem.MkdirAll(dst, 0o700)
 		})
 		if err != nil {
 			return "", err
@@ -414,24 +446,16 @@ func validateDirectoryPermissions(dst string) text.SpinnerProcess {
 			return fmt.Errorf("failed to generate enough entropy (%d/%d)", n, 16)
 		}
 
-		// gosec flagged this:
-		// G304 (CWE-22): Potential file inclusion via variable
-		//
-		// Disabling as the input is determined by our own package.
-		// #nosec
-		f, err := os.Create(filepath.Join(dst, fmt.Sprintf("tmp_%x", tmpname)))
+		f, err := filesystem.CreateFile(filepath.Join(dst, fmt.Sprintf("tmp_%x", tmpname)))
 		if err != nil {
-			return fmt.Errorf("error creating file in package destination: %w", err)
+			return err
 		}
 
 		if err := f.Close(); err != nil {
 			return fmt.Errorf("error closing file in package destination: %w", err)
 		}
 
-		if err := os.Remove(f.Name()); err != nil {
-			return fmt.Errorf("error removing file in package destination: %w", err)
-		}
-		return nil
+		return filesystem.Remove(f.Name())
 	}
 }
 
@@ -485,7 +509,7 @@ func (c *InitCommand) PromptPackageName(flags global.Flags, name string, in io.R
 
 	if name == "" {
 		var err error
-		name, err = text.Input(out, fmt.Sprintf("Name: [%s] ", defaultName), in)
+		name, err = text.Input(out, fmt.Sprintf("Name: [%s] ", defaultName), in, "--name")
 		if err != nil {
 			return "", fmt.Errorf("error reading input: %w", err)
 		}
@@ -507,7 +531,7 @@ func promptPackageDescription(flags global.Flags, desc string, in io.Reader, out
 	if desc == "" {
 		var err error
 
-		desc, err = text.Input(out, "Description: ", in)
+		desc, err = text.Input(out, "Description: ", in, "--description")
 		if err != nil {
 			return "", fmt.Errorf("error reading input: %w", err)
 		}
@@ -535,7 +559,7 @@ func promptPackageAuthors(flags global.Flags, authors []string, manifestEmail st
 			label = fmt.Sprintf("%s[%s] ", label, manifestEmail)
 		}
 
-		author, err := text.Input(out, label, in)
+		author, err := text.Input(out, label, in, "--author")
 		if err != nil {
 			return []string{}, fmt.Errorf("error reading input %w", err)
 		}
@@ -568,7 +592,7 @@ func (c *InitCommand) PromptForLanguage(languages []*Language, in io.Reader, out
 		}
 
 		text.Break(out)
-		option, err = text.Input(out, "Choose option: [1] ", in, validateLanguageOption(languages))
+		option, err = text.Input(out, "Choose option: [1] ", in, "--language", validateLanguageOption(languages))
 		if err != nil {
 			return nil, fmt.Errorf("reading input %w", err)
 		}
@@ -622,7 +646,7 @@ func (c *InitCommand) PromptForStarterKit(kits []config.StarterKit, in io.Reader
 		text.Indent(out, 4, "https://developer.fastly.com/solutions/starters/")
 		text.Break(out)
 
-		option, err = text.Input(out, "Choose option or paste git URL: [1] ", in, validateTemplateOptionOrURL(kits))
+		option, err = text.Input(out, "Choose option or paste git URL: [1] ", in, "--from", validateTemplateOptionOrURL(kits))
 		if err != nil {
 			return "", "", "", fmt.Errorf("error reading input: %w", err)
 		}
@@ -664,29 +688,85 @@ func validateTemplateOptionOrURL(templates []config.StarterKit) func(string) err
 // FetchPackageTemplate will determine if the package code should be fetched
 // from GitHub using the git binary to clone the source or a HTTP request that
 // uses content-negotiation to determine the type of archive format used.
-func (c *InitCommand) FetchPackageTemplate(branch, tag string, archives []file.Archive, spinner text.Spinner, out io.Writer) error {
-	err := spinner.Start()
-	if err != nil {
-		return err
-	}
-	msg := "Fetching package template"
-	spinner.Message(msg + "...")
-
+//
+// A fetched package template is cached (see starterKitCache), and the cached
+// copy is used when the git tag or commit it was fetched at can't have changed,
+// or when offline, unless --refresh is set.
+//
+// The template is copied to c.dir with copyTemplate, which handles the files
+// that already exist there.
+func (c *InitCommand) FetchPackageTemplate(branch, tag string, archives []file.Archive, spinner text.Spinner, in io.Reader, out io.Writer) error {
 	// If the user has provided a local file path, we'll recursively copy the
 	// directory to c.dir.
 	if fi, err := os.Stat(c.cloneFrom); err == nil && fi.IsDir() {
-		if err := cp.Copy(c.cloneFrom, c.dir); err != nil {
-			spinner.StopFailMessage(msg)
-			spinErr := spinner.StopFail()
-			if spinErr != nil {
-				return fmt.Errorf(text.SpinnerErrWrapper, spinErr, err)
+		return c.copyTemplate(c.cloneFrom, "Fetching package template", spinner, in, out)
+	}
+
+	sc := newStarterKitCache(c.cloneFrom, branch, tag)
+	if !c.refresh && (sc.immutable || c.Globals.Offline()) {
+		entry, ok, corrupted := sc.lookup()
+		if corrupted && c.Globals.Verbose() {
+			text.Warning(out, "The cached copy of the package template didn't match its recorded hash and was discarded.\n\n")
+		}
+		if ok {
+			err := c.copyTemplate(sc.dir, "Copying cached package template", spinner, in, out)
+			if err != nil {
+				return err
 			}
-			return err
+			if c.Globals.Verbose() {
+				text.Info(out, "Using the package template cached from %s (ref: %s) on %s.\n\nSHA-256: %s\nCache: %s\n\n", entry.URL, displayRef(entry.Ref), entry.Fetched.Format(time.RFC3339), entry.Hash, sc.dir)
+			}
+			return nil
 		}
-		spinner.StopMessage(msg)
-		return spinner.Stop()
 	}
-	c.Globals.ErrLog.Add(err)
+
+	staging, err := tempDir("package-template")
+	if err != nil {
+		return fmt.Errorf("error creating temporary path for package template: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	err = spinner.Process("Fetching package template", func(_ *text.SpinnerWrapper) error {
+		return c.fetchTemplate(staging, branch, tag, archives, out)
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.copyTemplate(staging, "Copying package template", spinner, in, out); err != nil {
+		return err
+	}
+
+	// NOTE: Failing to cache the package template doesn't prevent the project
+	// from being initialized.
+	entry, err := sc.store(staging, time.Now())
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		if c.Globals.Verbose() {
+			text.Warning(out, "Unable to cache the package template: %s\n\n", err)
+		}
+		return nil
+	}
+	if c.Globals.Verbose() {
+		text.Info(out, "Cached the package template fetched from %s (ref: %s).\n\nSHA-256: %s\nCache: %s\n\n", entry.URL, displayRef(entry.Ref), entry.Hash, sc.dir)
+	}
+	return nil
+}
+
+// displayRef returns the git ref for display, where an empty ref is the
+// repository's default branch.
+func displayRef(ref string) string {
+	if ref == "" {
+		return "default branch"
+	}
+	return ref
+}
+
+// fetchTemplate fetches the package template into dst, either by cloning the
+// git repository or by downloading and extracting an archive.
+func (c *InitCommand) fetchTemplate(dst, branch, tag string, archives []file.Archive, out io.Writer) error {
+	if strings.HasPrefix(c.cloneFrom, "file://") {
+		return c.ClonePackageFromEndpoint(dst, branch, tag)
+	}
 
 	req, err := http.NewRequest("GET", c.cloneFrom, nil)
 	if err != nil {
@@ -694,22 +774,7 @@ func (c *InitCommand) FetchPackageTemplate(branch, tag string, archives []file.A
 		c.Globals.ErrLog.Add(err)
 
 		if gitRepositoryRegEx.MatchString(c.cloneFrom) {
-			if err := c.ClonePackageFromEndpoint(branch, tag); err != nil {
-				spinner.StopFailMessage(msg)
-				spinErr := spinner.StopFail()
-				if spinErr != nil {
-					return fmt.Errorf(text.SpinnerErrWrapper, spinErr, err)
-				}
-				return err
-			}
-			spinner.StopMessage(msg)
-			return spinner.Stop()
-		}
-
-		spinner.StopFailMessage(msg)
-		spinErr := spinner.StopFail()
-		if spinErr != nil {
-			return fmt.Errorf(text.SpinnerErrWrapper, spinErr, err)
+			return c.ClonePackageFromEndpoint(dst, branch, tag)
 		}
 		return err
 	}
@@ -724,11 +789,6 @@ func (c *InitCommand) FetchPackageTemplate(branch, tag string, archives []file.A
 	if err != nil {
 		err = fmt.Errorf("failed to get package: %w", err)
 		c.Globals.ErrLog.Add(err)
-		spinner.StopFailMessage(msg)
-		spinErr := spinner.StopFail()
-		if spinErr != nil {
-			return fmt.Errorf(text.SpinnerErrWrapper, spinErr, err)
-		}
 		return err
 	}
 	defer res.Body.Close() // #nosec G307
@@ -736,31 +796,15 @@ func (c *InitCommand) FetchPackageTemplate(branch, tag string, archives []file.A
 	if res.StatusCode != http.StatusOK {
 		err := fmt.Errorf("failed to get package: %s", res.Status)
 		c.Globals.ErrLog.Add(err)
-		spinner.StopFailMessage(msg)
-		spinErr := spinner.StopFail()
-		if spinErr != nil {
-			return fmt.Errorf(text.SpinnerErrWrapper, spinErr, err)
-		}
 		return err
 	}
 
 	filename := filepath.Base(c.cloneFrom)
 	ext := filepath.Ext(filename)
 
-	// gosec flagged this:
-	// G304 (CWE-22): Potential file inclusion via variable
-	//
-	// Disabling as we require a user to configure their own environment.
-	/* #nosec */
-	f, err := os.Create(filename)
+	f, err := filesystem.CreateFile(filename)
 	if err != nil {
-		err = fmt.Errorf("failed to create local %s archive: %w", filename, err)
 		c.Globals.ErrLog.Add(err)
-		spinner.StopFailMessage(msg)
-		spinErr := spinner.StopFail()
-		if spinErr != nil {
-			return fmt.Errorf(text.SpinnerErrWrapper, spinErr, err)
-		}
 		return err
 	}
 	defer func() {
@@ -778,11 +822,6 @@ func (c *InitCommand) FetchPackageTemplate(branch, tag string, archives []file.A
 	if err != nil {
 		err = fmt.Errorf("failed to write %s archive to disk: %w", filename, err)
 		c.Globals.ErrLog.Add(err)
-		spinner.StopFailMessage(msg)
-		spinErr := spinner.StopFail()
-		if spinErr != nil {
-			return fmt.Errorf(text.SpinnerErrWrapper, spinErr, err)
-		}
 		return err
 	}
 
@@ -824,54 +863,33 @@ mimes:
 		// the archive.Extract() method.
 		if ext == "" {
 			filenameWithExt := filename + archive.Extensions()[0]
-			err := os.Rename(filename, filenameWithExt)
+			err := filesystem.Rename(filename, filenameWithExt)
 			if err != nil {
 				c.Globals.ErrLog.Add(err)
-				spinner.StopFailMessage(msg)
-				spinErr := spinner.StopFail()
-				if spinErr != nil {
-					return fmt.Errorf(text.SpinnerErrWrapper, spinErr, err)
-				}
 				return err
 			}
 			filename = filenameWithExt
 		}
 
-		archive.SetDestination(c.dir)
+		archive.SetDestination(dst)
 		archive.SetFilename(filename)
 
 		err = archive.Extract()
 		if err != nil {
 			err = fmt.Errorf("failed to extract %s archive content: %w", filename, err)
 			c.Globals.ErrLog.Add(err)
-			spinner.StopFailMessage(msg)
-			spinErr := spinner.StopFail()
-			if spinErr != nil {
-				return fmt.Errorf(text.SpinnerErrWrapper, spinErr, err)
-			}
 			return err
 		}
 
-		spinner.StopMessage(msg)
-		return spinner.Stop()
-	}
-
-	if err := c.ClonePackageFromEndpoint(branch, tag); err != nil {
-		spinner.StopFailMessage(msg)
-		spinErr := spinner.StopFail()
-		if spinErr != nil {
-			return fmt.Errorf(text.SpinnerErrWrapper, spinErr, err)
-		}
-		return err
+		return nil
 	}
 
-	spinner.StopMessage(msg)
-	return spinner.Stop()
+	return c.ClonePackageFromEndpoint(dst, branch, tag)
 }
 
 // ClonePackageFromEndpoint clones the given repo (from) into a temp directory,
-// then copies specific files to the destination directory (path).
-func (c *InitCommand) ClonePackageFromEndpoint(branch, tag string) error {
+// then copies specific files to the destination directory (dst).
+func (c *InitCommand) ClonePackageFromEndpoint(dst, branch, tag string) error {
 	from := c.cloneFrom
 
 	_, err := exec.LookPath("git")
@@ -944,12 +962,12 @@ func (c *InitCommand) ClonePackageFromEndpoint(branch, tag string) error {
 			return nil
 		}
 
-		dst := filepath.Join(c.dir, rel)
-		if err := os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+		target := filepath.Join(dst, rel)
+		if err := filesystem.MkdirAll(filepath.Dir(target), 0o750); err != nil {
 			return err
 		}
 
-		return filesystem.CopyFile(path, dst)
+		return filesystem.CopyFile(path, target)
 	})
 
 	if err != nil {
@@ -968,7 +986,7 @@ func tempDir(prefix string) (abspath string, err error) {
 		return "", err
 	}
 
-	if err = os.MkdirAll(abspath, 0o750); err != nil {
+	if err = filesystem.MkdirAll(abspath, 0o750); err != nil {
 		return "", err
 	}
 
@@ -1108,7 +1126,7 @@ func promptForPostInitContinue(msg, script string, out io.Writer, in io.Reader)
 	text.Indent(out, 4, "%s", script)
 
 	label := "\nDo you want to run this now? [y/N] "
-	answer, err := text.AskYesNo(out, label, in)
+	answer, err := text.AskYesNo(out, label, in, "--auto-yes")
 	if err != nil {
 		return err
 	}
@@ -1119,18 +1137,47 @@ func promptForPostInitContinue(msg, script string, out io.Writer, in io.Reader)
 	return nil
 }
 
-// displayInitOutput of package information and useful links.
-func displayInitOutput(name, dst, language string, out io.Writer) {
-	text.Break(out)
-	text.Description(out, fmt.Sprintf("Initialized package %s to", text.Bold(name)), dst)
-
+// displayInitOutput displays the initialized package and the suggested next
+// steps (or writes them as JSON with --json).
+func (c *InitCommand) displayInitOutput(out io.Writer, name, dst, language string) error {
 	if language == "other" {
-		text.Description(out, "To package a pre-compiled Wasm binary for deployment, run", "fastly compute pack")
-		text.Description(out, "To deploy the package, run", "fastly compute deploy")
+		c.AddNextStep("Package a pre-compiled Wasm binary", "fastly compute pack", "")
 	} else {
-		text.Description(out, "To publish the package (build and deploy), run", "fastly compute publish")
+		c.AddNextStep("Build the package", "fastly compute build", "")
+		c.AddNextStep("Run the package locally", "fastly compute serve", "")
+	}
+	c.AddNextStep("Deploy the package", "fastly compute deploy", "")
+	c.AddNextStep("Deploy with third-party orchestration tools", "", "https://developer.fastly.com/learning/integrations/orchestration/")
+
+	if ok, err := c.WriteJSON(out, initResult{
+		Name:      name,
+		Directory: dst,
+		Language:  language,
+		Files:     c.templateResult,
+		NextSteps: c.NextStepsJSON(),
+	}); ok {
+		return err
+	}
+
+	// The files are only listed when the directory already had some, as
+	// otherwise they were all created.
+	if c.templateMerged && c.templateResult != nil {
+		displayTemplateResult(out, *c.templateResult)
 	}
 
-	text.Description(out, "To learn about deploying Compute projects using third-party orchestration tools, visit", "https://developer.fastly.com/learning/integrations/orchestration/")
+	text.Break(out)
+	text.Description(out, fmt.Sprintf("Initialized package %s to", text.Bold(name)), dst)
 	text.Success(out, "Initialized package %s", text.Bold(name))
+	text.Break(out)
+	c.PrintNextSteps(out)
+	return nil
+}
+
+// initResult is the JSON output of an initialized package.
+type initResult struct {
+	Name      string          `json:"name"`
+	Directory string          `json:"directory"`
+	Language  string          `json:"language"`
+	Files     *TemplateResult `json:"files,omitempty"`
+	NextSteps []text.NextStep `json:"next_steps"`
 }
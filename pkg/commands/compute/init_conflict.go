@@ -0,0 +1,330 @@
+package compute
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/filesystem"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// Strategies for the files of a package template that already exist in the
+// project directory (see --on-conflict).
+const (
+	// OnConflictAbort leaves the directory unchanged.
+	OnConflictAbort = "abort"
+	// OnConflictSkip keeps the existing files, only creating the others.
+	OnConflictSkip = "skip"
+	// OnConflictOverwrite replaces the existing files with the template's.
+	OnConflictOverwrite = "overwrite"
+)
+
+// OnConflictStrategies is the list of --on-conflict options.
+var OnConflictStrategies = []string{OnConflictAbort, OnConflictSkip, OnConflictOverwrite}
+
+// TemplatePlan is the files a package template would write to the project
+// directory, as slash-separated paths relative to it.
+type TemplatePlan struct {
+	// Create is the files that don't exist in the project directory.
+	Create []string
+	// Conflict is the files that already exist in the project directory.
+	Conflict []string
+	// NotEmpty indicates the project directory already has content (other
+	// than a .git directory).
+	NotEmpty bool
+}
+
+// TemplateResult is the files of a package template written to the project
+// directory (see CopyTemplate).
+type TemplateResult struct {
+	Created     []string `json:"created,omitempty"`
+	Skipped     []string `json:"skipped,omitempty"`
+	Overwritten []string `json:"overwritten,omitempty"`
+}
+
+// PlanTemplate compares the files of the package template in src to the
+// project directory dst.
+//
+// A .git directory is ignored, whether it's in the template or the project,
+// so the project's repository is never changed.
+func PlanTemplate(src, dst string) (TemplatePlan, error) {
+	var plan TemplatePlan
+
+	entries, err := os.ReadDir(dst)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return plan, err
+	}
+	for _, e := range entries {
+		if e.Name() != ".git" {
+			plan.NotEmpty = true
+			break
+		}
+	}
+
+	err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != src && d.Name() == ".git" {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil // e.g. the .git file of a submodule
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		// NOTE: A path that can't be checked (e.g. because a parent is a file) is
+		// a conflict, so it's only written if the user chooses to overwrite, as
+		// is a path within a symlink of the project.
+		_, err = os.Lstat(filepath.Join(dst, rel))
+		if errors.Is(err, fs.ErrNotExist) && symlinkedDir(dst, rel) == "" {
+			plan.Create = append(plan.Create, filepath.ToSlash(rel))
+		} else {
+			plan.Conflict = append(plan.Conflict, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	return plan, err
+}
+
+// CopyTemplate copies the files of the plan from the package template in src
+// to the project directory dst. The conflicting files are replaced if the
+// strategy is OnConflictOverwrite, and skipped otherwise (the caller handles
+// OnConflictAbort).
+//
+// Nothing outside the plan is changed: an existing symlink is replaced rather
+// than written through, and a file isn't written within a directory of the
+// project that's a symlink.
+func CopyTemplate(src, dst string, plan TemplatePlan, strategy string) (TemplateResult, error) {
+	var result TemplateResult
+	for _, rel := range plan.Create {
+		if err := copyTemplateFile(src, dst, rel); err != nil {
+			return result, err
+		}
+		result.Created = append(result.Created, rel)
+	}
+	for _, rel := range plan.Conflict {
+		if strategy != OnConflictOverwrite {
+			result.Skipped = append(result.Skipped, rel)
+			continue
+		}
+		target := filepath.Join(dst, filepath.FromSlash(rel))
+		if fi, err := os.Lstat(target); err == nil {
+			if fi.IsDir() {
+				return result, fmt.Errorf("error overwriting %s: the package template has a file where the project has a directory", target)
+			}
+			if err := filesystem.Remove(target); err != nil {
+				return result, err
+			}
+		}
+		if err := copyTemplateFile(src, dst, rel); err != nil {
+			return result, err
+		}
+		result.Overwritten = append(result.Overwritten, rel)
+	}
+	return result, nil
+}
+
+// copyTemplateFile copies the file at the relative path from src to dst,
+// preserving its permissions (or recreating it if it's a symlink).
+func copyTemplateFile(src, dst, rel string) error {
+	from := filepath.Join(src, filepath.FromSlash(rel))
+	to := filepath.Join(dst, filepath.FromSlash(rel))
+
+	if dir := symlinkedDir(dst, rel); dir != "" {
+		return fmt.Errorf("error writing %s: %s is a symlink, which could lead outside of the project directory", to, dir)
+	}
+	if err := filesystem.MkdirAll(filepath.Dir(to), 0o750); err != nil {
+		return err
+	}
+
+	fi, err := os.Lstat(from)
+	if err != nil {
+		return err
+	}
+	if fi.Mode()&fs.ModeSymlink != 0 {
+		link, err := os.Readlink(from)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(link, to)
+	}
+	if err := filesystem.CopyFile(from, to); err != nil {
+		return fmt.Errorf("error copying %s from package template: %w", rel, err)
+	}
+	return os.Chmod(to, fi.Mode().Perm())
+}
+
+// symlinkedDir returns the directory of the relative path within dst that's a
+// symlink, if any.
+func symlinkedDir(dst, rel string) string {
+	dir := dst
+	for _, name := range strings.Split(filepath.Dir(filepath.FromSlash(rel)), string(filepath.Separator)) {
+		if name == "." {
+			break
+		}
+		dir = filepath.Join(dir, name)
+		fi, err := os.Lstat(dir)
+		if err != nil {
+			break // the rest of the directories are created
+		}
+		if fi.Mode()&fs.ModeSymlink != 0 {
+			return dir
+		}
+	}
+	return ""
+}
+
+// copyTemplate copies the package template in src to the project directory.
+//
+// When the directory isn't empty, the files the template would create are
+// listed first, and those that already exist are handled as --on-conflict says
+// or, failing that, as the user chooses. Nothing is written if the user aborts.
+func (c *InitCommand) copyTemplate(src, msg string, spinner text.Spinner, in io.Reader, out io.Writer) error {
+	plan, err := PlanTemplate(src, c.dir)
+	if err != nil {
+		return fmt.Errorf("error comparing the package template to %s: %w", c.dir, err)
+	}
+
+	strategy := c.onConflict
+	if plan.NotEmpty {
+		displayTemplatePlan(out, plan, c.dir)
+		strategy, err = c.resolveConflicts(plan, in, out)
+		if err != nil {
+			return err
+		}
+		c.templateMerged = true
+	}
+
+	return spinner.Process(msg, func(_ *text.SpinnerWrapper) error {
+		result, err := CopyTemplate(src, c.dir, plan, strategy)
+		c.templateResult = &result
+		return err
+	})
+}
+
+// resolveConflicts returns the strategy for the files of the package template
+// that already exist in the project directory, prompting for it unless it's
+// set with --on-conflict.
+//
+// Without a prompt (e.g. --non-interactive) conflicts abort, as the files are
+// only replaced or kept with the user's consent.
+func (c *InitCommand) resolveConflicts(plan TemplatePlan, in io.Reader, out io.Writer) (string, error) {
+	flags := c.Globals.Flags
+	strategy := c.onConflict
+
+	switch {
+	case strategy != "":
+	case len(plan.Conflict) == 0:
+		// Nothing would be overwritten, but the user is asked before files are
+		// added to a directory that isn't empty.
+		strategy = OnConflictSkip
+		if !flags.AutoYes && !flags.NonInteractive {
+			label := fmt.Sprintf("Add the package template's files to %s? [y/N] ", c.dir)
+			cont, err := text.AskYesNo(out, label, in, "--auto-yes")
+			if err != nil {
+				return "", err
+			}
+			if !cont {
+				text.Break(out)
+				return "", fsterr.RemediationError{
+					Inner:       fmt.Errorf("project directory not empty"),
+					Remediation: fsterr.ExistingDirRemediation,
+				}
+			}
+		}
+	case flags.AcceptDefaults || flags.AutoYes || flags.NonInteractive:
+		strategy = OnConflictAbort
+	default:
+		text.Output(out, "%s", text.Bold("How should the existing files be handled?"))
+		fmt.Fprintf(out, "[1] %s: leave the directory unchanged\n", OnConflictAbort)
+		fmt.Fprintf(out, "[2] %s: keep the existing files, only create the new ones\n", OnConflictSkip)
+		fmt.Fprintf(out, "[3] %s: replace the existing files with the package template's\n", OnConflictOverwrite)
+		text.Break(out)
+		option, err := text.Input(out, "Choose option: [1] ", in, "--on-conflict", validateConflictOption)
+		if err != nil {
+			return "", fmt.Errorf("error reading input: %w", err)
+		}
+		text.Break(out)
+		strategy = conflictOption(option)
+	}
+
+	if strategy == OnConflictAbort && len(plan.Conflict) > 0 {
+		return "", fsterr.RemediationError{
+			Inner:       fmt.Errorf("%d file(s) of the package template already exist in %s", len(plan.Conflict), c.dir),
+			Remediation: fsterr.InitConflictRemediation,
+		}
+	}
+	return strategy, nil
+}
+
+// conflictOption returns the strategy for a prompt option, which is either its
+// number or name (defaulting to OnConflictAbort).
+func conflictOption(option string) string {
+	if i, err := strconv.Atoi(option); err == nil && i >= 1 && i <= len(OnConflictStrategies) {
+		return OnConflictStrategies[i-1]
+	}
+	for _, s := range OnConflictStrategies {
+		if strings.EqualFold(option, s) {
+			return s
+		}
+	}
+	return OnConflictAbort
+}
+
+// validateConflictOption ensures the user selects one of the strategies
+// displayed.
+func validateConflictOption(input string) error {
+	if input == "" {
+		return nil
+	}
+	if i, err := strconv.Atoi(input); err == nil && i >= 1 && i <= len(OnConflictStrategies) {
+		return nil
+	}
+	for _, s := range OnConflictStrategies {
+		if strings.EqualFold(input, s) {
+			return nil
+		}
+	}
+	return fmt.Errorf("must be a valid option")
+}
+
+// displayTemplatePlan lists the files the package template would create in the
+// project directory, and those that already exist.
+func displayTemplatePlan(out io.Writer, plan TemplatePlan, dir string) {
+	text.Break(out)
+	text.Info(out, "%s isn't empty. The package template has %d file(s), %d of which already exist.", dir, len(plan.Create)+len(plan.Conflict), len(plan.Conflict))
+	displayFileList(out, "New files:", plan.Create)
+	displayFileList(out, "Existing files:", plan.Conflict)
+	text.Break(out)
+}
+
+// displayTemplateResult lists the files of the package template that were
+// created, skipped and overwritten.
+func displayTemplateResult(out io.Writer, result TemplateResult) {
+	displayFileList(out, "Created:", result.Created)
+	displayFileList(out, "Skipped (already exist):", result.Skipped)
+	displayFileList(out, "Overwritten:", result.Overwritten)
+}
+
+func displayFileList(out io.Writer, heading string, files []string) {
+	if len(files) == 0 {
+		return
+	}
+	text.Output(out, "\n%s", text.Bold(heading))
+	for _, f := range files {
+		fmt.Fprintf(out, "  %s\n", f)
+	}
+}
@@ -4,15 +4,19 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"io/fs"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
 	"github.com/fastly/cli/pkg/config"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/manifest"
+	fstruntime "github.com/fastly/cli/pkg/runtime"
 	"github.com/fastly/cli/pkg/testutil"
 )
 
@@ -219,6 +223,7 @@ func TestInit(t *testing.T) {
 				},
 			},
 			wantFiles: []string{
+				".gitignore",
 				"Cargo.toml",
 				"fastly.toml",
 				"src/main.rs",
@@ -293,13 +298,12 @@ func TestInit(t *testing.T) {
 		},
 		{
 			name: "with directory name inferred from --directory",
-			args: args("compute init --directory ./foo"),
+			args: args("compute init --directory ./foo --on-conflict overwrite"),
 			configFile: config.File{
 				StarterKits: config.StarterKitLanguages{
 					Rust: skRust,
 				},
 			},
-			stdin:            "Y",
 			manifest:         `manifest_version = 2`,
 			manifestPath:     "foo",
 			manifestIncludes: `name = "foo`,
@@ -323,7 +327,7 @@ func TestInit(t *testing.T) {
 			manifestIncludes: `language = "other"`,
 			wantOutput: []string{
 				"Initialized package",
-				"To package a pre-compiled Wasm binary for deployment",
+				"fastly compute pack",
 				"SUCCESS: Initialized package",
 			},
 		},
@@ -398,3 +402,373 @@ func TestInit(t *testing.T) {
 		})
 	}
 }
+
+func TestInitStarterKitCache(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is required to run this test")
+	}
+
+	cacheDir := compute.StarterKitCacheDir
+	compute.StarterKitCacheDir = t.TempDir()
+	defer func() {
+		compute.StarterKitCacheDir = cacheDir
+	}()
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %s\n\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+	// commit writes the template's source file with the content, and commits it.
+	commit := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, "main.rs"), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		git("add", "-A")
+		git("commit", "-q", "-m", content)
+	}
+	if err := os.WriteFile(filepath.Join(repo, manifest.Filename), []byte("manifest_version = 3\nlanguage = \"rust\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	git("init", "-q", "-b", "main")
+	commit("v1")
+	git("tag", "v1")
+	from := "file://" + repo
+
+	// run initializes a project with the flags, returning the output and the
+	// content of the project's source file.
+	run := func(offline bool, flags string) (string, string, error) {
+		t.Helper()
+		pwd, err := os.Getwd()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chdir(t.TempDir()); err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			_ = os.Chdir(pwd)
+		}()
+
+		args := testutil.Args("compute init --from " + from + " --language rust --non-interactive --verbose " + flags)
+		var stdout bytes.Buffer
+		app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+			opts := testutil.MockGlobalData(args, &stdout)
+			if offline {
+				opts.Env.Offline = "true"
+			}
+			return opts, nil
+		}
+		err = app.Run(args, nil)
+		t.Log(stdout.String())
+		content, _ := os.ReadFile("main.rs")
+		return stdout.String(), string(content), err
+	}
+
+	const cached = "Using the package template cached from " // a cache hit
+	const fetched = "Cached the package template fetched from "
+
+	t.Run("fetch tag", func(t *testing.T) {
+		output, content, err := run(false, "--tag v1")
+		testutil.AssertNoError(t, err)
+		testutil.AssertString(t, "v1", content)
+		testutil.AssertStringContains(t, output, fetched)
+		testutil.AssertStringContains(t, output, "(ref: v1)")
+	})
+
+	// NOTE: Moving the tag shows the cached copy is used.
+	commit("v2")
+	git("tag", "-f", "v1")
+
+	t.Run("cache hit", func(t *testing.T) {
+		output, content, err := run(false, "--tag v1")
+		testutil.AssertNoError(t, err)
+		testutil.AssertString(t, "v1", content)
+		testutil.AssertStringContains(t, output, cached+from+" (ref: v1)")
+		testutil.AssertStringContains(t, output, "SHA-256: ")
+	})
+
+	t.Run("forced refresh", func(t *testing.T) {
+		output, content, err := run(false, "--tag v1 --refresh")
+		testutil.AssertNoError(t, err)
+		testutil.AssertString(t, "v2", content)
+		testutil.AssertStringContains(t, output, fetched)
+
+		_, content, err = run(false, "--tag v1")
+		testutil.AssertNoError(t, err)
+		testutil.AssertString(t, "v2", content)
+	})
+
+	t.Run("branch", func(t *testing.T) {
+		_, content, err := run(false, "--branch main")
+		testutil.AssertNoError(t, err)
+		testutil.AssertString(t, "v2", content)
+
+		// A branch can move, so it's re-fetched unless offline.
+		commit("v3")
+		output, content, err := run(false, "--branch main")
+		testutil.AssertNoError(t, err)
+		testutil.AssertString(t, "v3", content)
+		testutil.AssertStringContains(t, output, fetched)
+	})
+
+	t.Run("offline reuse", func(t *testing.T) {
+		commit("v4")
+		output, content, err := run(true, "--branch main")
+		testutil.AssertNoError(t, err)
+		testutil.AssertString(t, "v3", content)
+		testutil.AssertStringContains(t, output, cached+from+" (ref: main)")
+	})
+
+	t.Run("corruption recovery", func(t *testing.T) {
+		err := filepath.WalkDir(compute.StarterKitCacheDir, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && d.Name() == "main.rs" {
+				err = os.WriteFile(path, []byte("corrupted"), 0o600)
+			}
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		output, content, err := run(false, "--tag v1")
+		testutil.AssertNoError(t, err)
+		testutil.AssertString(t, "v2", content)
+		testutil.AssertStringContains(t, output, "didn't match its recorded hash and was discarded")
+		testutil.AssertStringContains(t, output, fetched)
+
+		output, content, err = run(true, "--branch main")
+		testutil.AssertNoError(t, err)
+		testutil.AssertString(t, "v4", content)
+		testutil.AssertStringContains(t, output, fetched)
+	})
+}
+
+// TestInitOnConflict validates how the files of a package template that
+// already exist in the project directory are handled.
+func TestInitOnConflict(t *testing.T) {
+	template := t.TempDir()
+	for name, content := range map[string]string{
+		manifest.Filename: "manifest_version = 3\nname = \"template\"\nlanguage = \"rust\"\n",
+		"README.md":       "template readme",
+		"src/main.rs":     "fn main() {}",
+		".git/HEAD":       "template head",
+	} {
+		path := filepath.Join(template, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The project has two of the template's files, a file of its own and a git
+	// repository.
+	project := map[string]string{
+		manifest.Filename: "manifest_version = 3\nname = \"mine\"\ndescription = \"Mine\"\nauthors = [\"me@example.com\"]\nlanguage = \"rust\"\n",
+		"README.md":       "my readme",
+		"notes.txt":       "my notes",
+		".git/HEAD":       "my head",
+	}
+
+	scenarios := []struct {
+		name       string
+		args       string
+		empty      bool // the project only has a git repository
+		stdin      string
+		wantError  string
+		wantOutput []string
+		wantFiles  map[string]string // content, or "" if the file mustn't exist
+	}{
+		{
+			name:      "abort",
+			args:      "--on-conflict abort",
+			wantError: "2 file(s) of the package template already exist",
+			wantOutput: []string{
+				"The package template has 4 file(s), 2 of which already exist.",
+				"New files:\n  .gitignore\n  src/main.rs",
+				"Existing files:\n  README.md\n  fastly.toml",
+			},
+			wantFiles: map[string]string{"README.md": "my readme", "src/main.rs": ""},
+		},
+		{
+			name:      "non-interactive aborts",
+			args:      "--non-interactive",
+			wantError: "2 file(s) of the package template already exist",
+			wantFiles: map[string]string{"README.md": "my readme", "src/main.rs": ""},
+		},
+		{
+			name: "skip",
+			args: "--on-conflict skip",
+			wantOutput: []string{
+				"Created:\n  .gitignore\n  src/main.rs",
+				"Skipped (already exist):\n  README.md\n  fastly.toml",
+			},
+			wantFiles: map[string]string{"README.md": "my readme", "src/main.rs": "fn main() {}"},
+		},
+		{
+			name: "overwrite",
+			args: "--on-conflict overwrite",
+			wantOutput: []string{
+				"Created:\n  .gitignore\n  src/main.rs",
+				"Overwritten:\n  README.md\n  fastly.toml",
+			},
+			wantFiles: map[string]string{"README.md": "template readme", "src/main.rs": "fn main() {}"},
+		},
+		{
+			name:       "prompt overwrite",
+			stdin:      "3",
+			wantOutput: []string{"How should the existing files be handled?", "Overwritten:"},
+			wantFiles:  map[string]string{"README.md": "template readme"},
+		},
+		{
+			name:       "prompt skip",
+			stdin:      "skip",
+			wantOutput: []string{"Skipped (already exist):"},
+			wantFiles:  map[string]string{"README.md": "my readme", "src/main.rs": "fn main() {}"},
+		},
+		{
+			name:      "prompt abort by default",
+			stdin:     "",
+			wantError: "2 file(s) of the package template already exist",
+			wantFiles: map[string]string{"src/main.rs": ""},
+		},
+		{
+			name:  "directory with only a git repository",
+			args:  "--non-interactive",
+			empty: true,
+			wantFiles: map[string]string{
+				"README.md":   "template readme",
+				"src/main.rs": "fn main() {}",
+			},
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			dir := t.TempDir()
+			files := project
+			if testcase.empty {
+				files = map[string]string{".git/HEAD": "my head"}
+			}
+			for name, content := range files {
+				path := filepath.Join(dir, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// NOTE: The template's .gitignore is listed as a new file.
+			if err := os.WriteFile(filepath.Join(template, ".gitignore"), []byte("/target\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			pwd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chdir(dir); err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = os.Chdir(pwd)
+			}()
+
+			args := testutil.Args(strings.TrimSpace("compute init --from " + template + " --language rust " + testcase.args))
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.Input = strings.NewReader(testcase.stdin)
+				return opts, nil
+			}
+			err = app.Run(args, nil)
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if testcase.wantError != "" {
+				testutil.AssertRemediationErrorContains(t, err, "--on-conflict skip")
+			}
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			for name, want := range testcase.wantFiles {
+				content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+				if want == "" {
+					if !errors.Is(err, os.ErrNotExist) {
+						t.Errorf("unwanted file %s found", name)
+					}
+					continue
+				}
+				testutil.AssertNoError(t, err)
+				testutil.AssertString(t, want, string(content))
+			}
+			// The project's own files and repository are never changed.
+			for _, name := range []string{"notes.txt", ".git/HEAD"} {
+				want, ok := files[name]
+				if !ok {
+					continue
+				}
+				content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+				testutil.AssertNoError(t, err)
+				testutil.AssertString(t, want, string(content))
+			}
+		})
+	}
+}
+
+// TestCopyTemplateSymlinks validates overwriting a file doesn't write through
+// a symlink of the project directory.
+func TestCopyTemplateSymlinks(t *testing.T) {
+	if fstruntime.Windows {
+		t.Skip("symlinks require elevated permissions on Windows")
+	}
+	template, dir, outside := t.TempDir(), t.TempDir(), t.TempDir()
+	for _, name := range []string{"link.txt", "linked/main.rs"} {
+		path := filepath.Join(template, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("template"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	outsideFile := filepath.Join(outside, "file.txt")
+	if err := os.WriteFile(outsideFile, []byte("outside"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outsideFile, filepath.Join(dir, "link.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "linked")); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := compute.PlanTemplate(template, dir)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, []string{"link.txt", "linked/main.rs"}, plan.Conflict)
+
+	result, err := compute.CopyTemplate(template, dir, plan, compute.OnConflictSkip)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, plan.Conflict, result.Skipped)
+
+	result, err = compute.CopyTemplate(template, dir, plan, compute.OnConflictOverwrite)
+	testutil.AssertErrorContains(t, err, "is a symlink, which could lead outside of the project directory")
+	testutil.AssertEqual(t, []string{"link.txt"}, result.Overwritten)
+
+	content, err := os.ReadFile(filepath.Join(dir, "link.txt"))
+	testutil.AssertNoError(t, err)
+	testutil.AssertString(t, "template", string(content))
+	content, err = os.ReadFile(outsideFile)
+	testutil.AssertNoError(t, err)
+	testutil.AssertString(t, "outside", string(content))
+	if _, err := os.Stat(filepath.Join(outside, "main.rs")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("unwanted file written outside of the project directory")
+	}
+}
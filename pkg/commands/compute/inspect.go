@@ -0,0 +1,254 @@
+package compute
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/cli/pkg/wasm"
+)
+
+// ComputeABIModules are the import modules provided to a Compute program,
+// unless the config lists them (see config.ComputeABI). A function imported
+// from any other module can't be resolved when the package is instantiated.
+var ComputeABIModules = []string{
+	"fastly_abi",
+	"fastly_acl",
+	"fastly_async_io",
+	"fastly_backend",
+	"fastly_cache",
+	"fastly_compute_runtime",
+	"fastly_config_store",
+	"fastly_device_detection",
+	"fastly_dictionary",
+	"fastly_erl",
+	"fastly_geo",
+	"fastly_http_body",
+	"fastly_http_cache",
+	"fastly_http_downstream",
+	"fastly_http_req",
+	"fastly_http_resp",
+	"fastly_image_optimizer",
+	"fastly_kv_store",
+	"fastly_log",
+	"fastly_object_store",
+	"fastly_purge",
+	"fastly_secret_store",
+	"fastly_shielding",
+	"fastly_uap",
+	"wasi_snapshot_preview1",
+}
+
+// InspectCommand reports what a built Wasm binary contains.
+type InspectCommand struct {
+	argparser.Base
+	argparser.JSONOutput
+
+	dir        string
+	stripDebug bool
+	wasmBinary string
+}
+
+// NewInspectCommand returns a usable command registered under the parent.
+func NewInspectCommand(parent argparser.Registerer, g *global.Data) *InspectCommand {
+	var c InspectCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("inspect", "Report the sections, imports and exports of a built Wasm binary")
+	c.CmdClause.Flag("dir", "Project directory (default: current directory)").Short('C').StringVar(&c.dir)
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.CmdClause.Flag("strip-debug", "Remove the debug sections (DWARF, function names and source maps) from the Wasm binary").BoolVar(&c.stripDebug)
+	c.CmdClause.Flag("wasm-binary", "Path to the Wasm binary (default: bin/main.wasm in the project directory)").Short('w').StringVar(&c.wasmBinary)
+	return &c
+}
+
+// Inspection is the report of a Wasm binary.
+type Inspection struct {
+	Path     string              `json:"path"`
+	Size     int                 `json:"size"`
+	Sections []InspectionSection `json:"sections"`
+	Imports  []InspectionImport  `json:"imports"`
+	Exports  []string            `json:"exports"`
+	// DebugSize is the size of the debug sections, which is saved by
+	// stripping them.
+	DebugSize int `json:"debug_size"`
+	// StrippedSize is the size after the debug sections were stripped (nil
+	// unless --strip-debug was set).
+	StrippedSize *int `json:"stripped_size,omitempty"`
+}
+
+// InspectionSection is a section of the Wasm binary.
+type InspectionSection struct {
+	Name   string `json:"name"`
+	Custom bool   `json:"custom"`
+	Debug  bool   `json:"debug"`
+	Size   int    `json:"size"`
+}
+
+// InspectionImport is a host function imported by the Wasm binary.
+type InspectionImport struct {
+	Module string `json:"module"`
+	Name   string `json:"name"`
+	// ComputeABI indicates the function's module is provided to Compute
+	// programs (see computeABI).
+	ComputeABI bool `json:"compute_abi"`
+}
+
+// Exec invokes the application logic for the command.
+func (c *InspectCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	path := c.wasmBinary
+	if path == "" {
+		path = filepath.Join(c.dir, binWasmPath)
+	}
+	// G304 (CWE-22): Potential file inclusion via variable
+	// #nosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		if errors.Is(err, os.ErrNotExist) {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("failed to read the Wasm binary: %w", err),
+				Remediation: "Run `fastly compute build` first, or pass the path of a Wasm binary with --wasm-binary.",
+			}
+		}
+		return fmt.Errorf("failed to read the Wasm binary: %w", err)
+	}
+
+	m, err := wasm.Parse(data)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	report := inspect(path, m, computeABI(c.Globals).ImportModules)
+
+	if c.stripDebug && report.DebugSize > 0 {
+		stripped, _, err := wasm.StripDebug(data)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("failed to strip %s: %w", path, err)
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, stripped, fi.Mode().Perm()); err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		size := len(stripped)
+		report.StrippedSize = &size
+	}
+
+	if ok, err := c.WriteJSON(out, report); ok {
+		return err
+	}
+	printInspection(out, report, c.stripDebug)
+	return nil
+}
+
+// inspect builds the report of a parsed Wasm binary.
+func inspect(path string, m *wasm.Module, modules []string) Inspection {
+	report := Inspection{
+		Path:      path,
+		Size:      m.Size,
+		Sections:  make([]InspectionSection, 0, len(m.Sections)),
+		Imports:   make([]InspectionImport, 0, len(m.Imports)),
+		Exports:   m.Exports,
+		DebugSize: m.DebugSize(),
+	}
+	if report.Exports == nil {
+		report.Exports = []string{}
+	}
+	for _, s := range m.Sections {
+		report.Sections = append(report.Sections, InspectionSection{
+			Name:   s.Name,
+			Custom: s.Custom(),
+			Debug:  s.Debug(),
+			Size:   s.Size,
+		})
+	}
+	for _, i := range m.Imports {
+		report.Imports = append(report.Imports, InspectionImport{
+			Module:     i.Module,
+			Name:       i.Name,
+			ComputeABI: isComputeABIModule(modules, i.Module),
+		})
+	}
+	return report
+}
+
+func isComputeABIModule(modules []string, module string) bool {
+	for _, m := range modules {
+		if m == module {
+			return true
+		}
+	}
+	return false
+}
+
+func printInspection(out io.Writer, r Inspection, stripDebug bool) {
+	text.Output(out, "%s: %s", r.Path, text.Bytes(float64(r.Size)))
+	text.Break(out)
+
+	t := text.NewTable(out)
+	t.AddHeader("SECTION", "SIZE", "%")
+	for _, s := range r.Sections {
+		name := s.Name
+		if s.Custom {
+			name = fmt.Sprintf("custom %q", s.Name)
+		}
+		t.AddLine(name, s.Size, fmt.Sprintf("%.1f", percent(s.Size, r.Size)))
+	}
+	t.Print()
+	text.Break(out)
+
+	var foreign []string
+	text.Output(out, "Imported host functions: %d", len(r.Imports))
+	for _, i := range r.Imports {
+		fn := i.Module + "::" + i.Name
+		if !i.ComputeABI {
+			foreign = append(foreign, fn)
+			fn += " (not in the Compute ABI)"
+		}
+		text.Indent(out, 2, "%s", fn)
+	}
+	text.Break(out)
+	text.Output(out, "Exported functions: %d", len(r.Exports))
+	for _, e := range r.Exports {
+		text.Indent(out, 2, "%s", e)
+	}
+	text.Break(out)
+
+	if len(foreign) > 0 {
+		text.Warning(out, "%d imported functions aren't provided by the Compute platform, so the package will fail to start: %s", len(foreign), strings.Join(foreign, ", "))
+		text.Break(out)
+	}
+
+	switch {
+	case r.StrippedSize != nil:
+		text.Success(out, "Stripped the debug sections from %s: %s -> %s (saved %s)", r.Path, text.Bytes(float64(r.Size)), text.Bytes(float64(*r.StrippedSize)), text.Bytes(float64(r.Size-*r.StrippedSize)))
+	case r.DebugSize > 0:
+		text.Info(out, "The debug sections are %s (%.1f%% of the binary). Strip them with --strip-debug.", text.Bytes(float64(r.DebugSize)), percent(r.DebugSize, r.Size))
+	case stripDebug:
+		text.Info(out, "%s has no debug sections to strip.", r.Path)
+	default:
+		text.Info(out, "%s has no debug sections.", r.Path)
+	}
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(total)
+}
@@ -0,0 +1,164 @@
+package compute_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestInspect(t *testing.T) {
+	scenarios := []struct {
+		name       string
+		args       string
+		fixture    string
+		wantOutput []string
+		wantError  string
+		// wantBinary is the fixture the binary must match after the command.
+		wantBinary string
+	}{
+		{
+			name:    "report",
+			args:    "compute inspect",
+			fixture: "main.wasm",
+			wantOutput: []string{
+				"bin/main.wasm: 182.0 B",
+				`custom "producers"`,
+				"fastly_http_req::body_downstream_get\n",
+				"wasi_snapshot_preview1::proc_exit\n",
+				"env::abort (not in the Compute ABI)",
+				"Exported functions: 1\n  _start",
+				"WARNING: 1 imported functions aren't provided by the Compute platform, so the package will fail to start: env::abort",
+				"has no debug sections.",
+			},
+			wantBinary: "main.wasm",
+		},
+		{
+			name:       "debug sections",
+			args:       "compute inspect",
+			fixture:    "debug.wasm",
+			wantOutput: []string{`custom ".debug_info"`, `custom "name"`, "The debug sections are 231.0 B (55.9% of the binary). Strip them with --strip-debug."},
+			wantBinary: "debug.wasm",
+		},
+		{
+			name:       "strip debug",
+			args:       "compute inspect --strip-debug",
+			fixture:    "debug.wasm",
+			wantOutput: []string{"SUCCESS: Stripped the debug sections from bin/main.wasm: 413.0 B -> 182.0 B (saved 231.0 B)"},
+			wantBinary: "main.wasm",
+		},
+		{
+			name:       "nothing to strip",
+			args:       "compute inspect --strip-debug",
+			fixture:    "main.wasm",
+			wantOutput: []string{"has no debug sections to strip."},
+			wantBinary: "main.wasm",
+		},
+		{
+			name:      "not built",
+			args:      "compute inspect",
+			wantError: "failed to read the Wasm binary",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			opts := testutil.EnvOpts{T: t}
+			if testcase.fixture != "" {
+				opts.Copy = []testutil.FileIO{{
+					Src: filepath.Join("testdata", "inspect", testcase.fixture),
+					Dst: filepath.Join("bin", "main.wasm"),
+				}}
+			}
+			rootdir := testutil.NewEnv(opts)
+			defer os.RemoveAll(rootdir)
+			pwd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chdir(rootdir); err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = os.Chdir(pwd)
+			}()
+
+			args := testutil.Args(testcase.args)
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				return testutil.MockGlobalData(args, &stdout), nil
+			}
+			err = app.Run(args, nil)
+			t.Log(stdout.String())
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+
+			if testcase.wantBinary != "" {
+				have, err := os.ReadFile(filepath.Join("bin", "main.wasm"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				want, err := os.ReadFile(filepath.Join(pwd, "testdata", "inspect", testcase.wantBinary))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(want, have) {
+					t.Errorf("want the binary to match %s", testcase.wantBinary)
+				}
+			}
+		})
+	}
+}
+
+func TestInspectJSON(t *testing.T) {
+	args := testutil.Args("compute inspect --json --wasm-binary " + filepath.Join("testdata", "inspect", "debug.wasm"))
+	var stdout bytes.Buffer
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		return testutil.MockGlobalData(args, &stdout), nil
+	}
+	testutil.AssertNoError(t, app.Run(args, nil))
+
+	var report compute.Inspection
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %s\n%s", err, stdout.String())
+	}
+	testutil.AssertEqual(t, 413, report.Size)
+	testutil.AssertEqual(t, 231, report.DebugSize)
+	testutil.AssertEqual(t, []string{"_start"}, report.Exports)
+	testutil.AssertEqual(t, []compute.InspectionImport{
+		{Module: "fastly_http_req", Name: "body_downstream_get", ComputeABI: true},
+		{Module: "wasi_snapshot_preview1", Name: "proc_exit", ComputeABI: true},
+		{Module: "env", Name: "abort"},
+	}, report.Imports)
+
+	var names []string
+	var total int
+	for _, s := range report.Sections {
+		names = append(names, s.Name)
+		total += s.Size
+	}
+	testutil.AssertEqual(t, []string{"type", "import", "function", "export", "code", ".debug_info", "name", "producers"}, names)
+	// The sections and the 8 byte header account for the whole binary.
+	testutil.AssertEqual(t, report.Size, total+8)
+}
+
+func TestInspectComponent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "component.wasm")
+	if err := os.WriteFile(path, []byte{0x00, 0x61, 0x73, 0x6d, 0x0d, 0x00, 0x01, 0x00}, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	args := testutil.Args("compute inspect --wasm-binary " + path)
+	var stdout bytes.Buffer
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		return testutil.MockGlobalData(args, &stdout), nil
+	}
+	testutil.AssertErrorContains(t, app.Run(args, nil), "the binary is a WebAssembly component")
+}
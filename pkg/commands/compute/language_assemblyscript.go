@@ -37,7 +37,6 @@ const AsSourceDirectory = "assembly"
 // NewAssemblyScript constructs a new AssemblyScript toolchain.
 func NewAssemblyScript(
 	c *BuildCommand,
-	in io.Reader,
 	manifestFilename string,
 	out io.Writer,
 	spinner text.Spinner,
@@ -47,11 +46,9 @@ func NewAssemblyScript(
 
 		build:                 c.Globals.Manifest.File.Scripts.Build,
 		errlog:                c.Globals.ErrLog,
-		input:                 in,
 		manifestFilename:      manifestFilename,
 		metadataFilterEnvVars: c.MetadataFilterEnvVars,
 		output:                out,
-		postBuild:             c.Globals.Manifest.File.Scripts.PostBuild,
 		spinner:               spinner,
 		timeout:               c.Flags.Timeout,
 		verbose:               c.Globals.Verbose(),
@@ -62,27 +59,18 @@ func NewAssemblyScript(
 type AssemblyScript struct {
 	Shell
 
-	// autoYes is the --auto-yes flag.
-	autoYes bool
 	// build is a shell command defined in fastly.toml using [scripts.build].
 	build string
 	// defaultBuild indicates if the default build script was used.
 	defaultBuild bool
 	// errlog is an abstraction for recording errors to disk.
 	errlog fsterr.LogInterface
-	// input is the user's terminal stdin stream
-	input io.Reader
 	// manifestFilename is the name of the manifest file.
 	manifestFilename string
 	// metadataFilterEnvVars is a comma-separated list of user defined env vars.
 	metadataFilterEnvVars string
-	// nonInteractive is the --non-interactive flag.
-	nonInteractive bool
 	// output is the users terminal stdout stream
 	output io.Writer
-	// postBuild is a custom script executed after the build but before the Wasm
-	// binary is added to the .tar.gz archive.
-	postBuild string
 	// spinner is a terminal progress status indicator.
 	spinner text.Spinner
 	// timeout is the build execution threshold.
@@ -145,16 +133,11 @@ func (a *AssemblyScript) Build() error {
 	}
 
 	bt := BuildToolchain{
-		autoYes:               a.autoYes,
 		buildFn:               a.Shell.Build,
 		buildScript:           a.build,
 		errlog:                a.errlog,
-		in:                    a.input,
-		manifestFilename:      a.manifestFilename,
 		metadataFilterEnvVars: a.metadataFilterEnvVars,
-		nonInteractive:        a.nonInteractive,
 		out:                   a.output,
-		postBuild:             a.postBuild,
 		spinner:               a.spinner,
 		timeout:               a.timeout,
 		verbose:               a.verbose,
@@ -2,11 +2,14 @@ package compute
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
+	"runtime"
 	"strings"
 
 	"github.com/Masterminds/semver/v3"
@@ -34,7 +37,6 @@ const GoSourceDirectory = "."
 // NewGo constructs a new Go toolchain.
 func NewGo(
 	c *BuildCommand,
-	in io.Reader,
 	manifestFilename string,
 	out io.Writer,
 	spinner text.Spinner,
@@ -42,17 +44,14 @@ func NewGo(
 	return &Go{
 		Shell: Shell{},
 
-		autoYes:               c.Globals.Flags.AutoYes,
 		build:                 c.Globals.Manifest.File.Scripts.Build,
 		config:                c.Globals.Config.Language.Go,
 		env:                   c.Globals.Manifest.File.Scripts.EnvVars,
 		errlog:                c.Globals.ErrLog,
-		input:                 in,
 		manifestFilename:      manifestFilename,
 		metadataFilterEnvVars: c.MetadataFilterEnvVars,
-		nonInteractive:        c.Globals.Flags.NonInteractive,
 		output:                out,
-		postBuild:             c.Globals.Manifest.File.Scripts.PostBuild,
+		skipToolchainCheck:    c.Flags.SkipToolchainCheck,
 		spinner:               spinner,
 		timeout:               c.Flags.Timeout,
 		verbose:               c.Globals.Verbose(),
@@ -68,8 +67,6 @@ func NewGo(
 type Go struct {
 	Shell
 
-	// autoYes is the --auto-yes flag.
-	autoYes bool
 	// build is a shell command defined in fastly.toml using [scripts.build].
 	build string
 	// config is the Go specific application configuration.
@@ -80,19 +77,14 @@ type Go struct {
 	env []string
 	// errlog is an abstraction for recording errors to disk.
 	errlog fsterr.LogInterface
-	// input is the user's terminal stdin stream
-	input io.Reader
 	// manifestFilename is the name of the manifest file.
 	manifestFilename string
 	// metadataFilterEnvVars is a comma-separated list of user defined env vars.
 	metadataFilterEnvVars string
-	// nonInteractive is the --non-interactive flag.
-	nonInteractive bool
 	// output is the users terminal stdout stream
 	output io.Writer
-	// postBuild is a custom script executed after the build but before the Wasm
-	// binary is added to the .tar.gz archive.
-	postBuild string
+	// skipToolchainCheck is the --skip-toolchain-check flag.
+	skipToolchainCheck bool
 	// spinner is a terminal progress status indicator.
 	spinner text.Spinner
 	// timeout is the build execution threshold.
@@ -165,28 +157,28 @@ func (g *Go) Build() error {
 	// 0.2.0 version. If it less than, change the TinyGo constraint to 0.26.0
 	tinygoConstraint := identifyTinyGoConstraint(g.config.TinyGoConstraint, g.config.TinyGoConstraintFallback)
 
-	g.toolchainConstraint(
-		"go", `go version go(?P<version>\d[^\s]+)`, toolchainConstraint,
-	)
-
-	if tinygoToolchain {
-		g.toolchainConstraint(
-			"tinygo", `tinygo version (?P<version>\d[^\s]+)`, tinygoConstraint,
-		)
+	if g.skipToolchainCheck {
+		if g.verbose {
+			text.Info(g.output, "Skipping the Go toolchain version check (--skip-toolchain-check).\n\n")
+		}
+	} else {
+		if err := g.checkToolchain("go", toolchainConstraint); err != nil {
+			return err
+		}
+		if tinygoToolchain {
+			if err := g.checkToolchain("tinygo", tinygoConstraint); err != nil {
+				return err
+			}
+		}
 	}
 
 	bt := BuildToolchain{
-		autoYes:               g.autoYes,
 		buildFn:               g.Shell.Build,
 		buildScript:           g.build,
 		env:                   g.env,
 		errlog:                g.errlog,
-		in:                    g.input,
-		manifestFilename:      g.manifestFilename,
 		metadataFilterEnvVars: g.metadataFilterEnvVars,
-		nonInteractive:        g.nonInteractive,
 		out:                   g.output,
-		postBuild:             g.postBuild,
 		spinner:               g.spinner,
 		timeout:               g.timeout,
 		verbose:               g.verbose,
@@ -273,49 +265,208 @@ func identifyTinyGoConstraint(configConstraint, fallback string) string {
 	return configConstraint
 }
 
-// toolchainConstraint warns the user if the required constraint is not met.
-//
-// NOTE: We don't stop the build as their toolchain may compile successfully.
-// The warning is to help a user know something isn't quite right and gives them
-// the opportunity to do something about it if they choose.
-func (g *Go) toolchainConstraint(toolchain, pattern, constraint string) {
-	if g.verbose {
-		text.Info(g.output, "The Fastly CLI build step requires a %s version '%s'.\n\n", toolchain, constraint)
-	}
-
-	versionCommand := fmt.Sprintf("%s version", toolchain)
-	args := strings.Split(versionCommand, " ")
-
+// ToolchainVersionRunner executes the toolchain binary at path with args and
+// returns its combined stdout/stderr. It's a variable so tests can fake it.
+var ToolchainVersionRunner = func(path string, args ...string) ([]byte, error) {
 	// gosec flagged this:
 	// G204 (CWE-78): Subprocess launched with function call as argument or cmd arguments
 	// Disabling as we trust the source of the variable.
 	// #nosec
 	// nosemgrep
-	cmd := exec.Command(args[0], args[1:]...)
-	stdoutStderr, err := cmd.CombinedOutput()
-	output := string(stdoutStderr)
+	return exec.Command(path, args...).CombinedOutput()
+}
+
+// SkipToolchainCheckRemediation explains how to build regardless of the
+// installed toolchain version.
+const SkipToolchainCheckRemediation = "To build with the installed version anyway, use the --skip-toolchain-check flag."
+
+// checkToolchain returns an error if the toolchain binary isn't installed or
+// its version doesn't satisfy constraint.
+func (g *Go) checkToolchain(toolchain, constraint string) error {
+	if g.verbose {
+		text.Info(g.output, "The Fastly CLI build step requires a %s version '%s'.\n\n", toolchain, constraint)
+	}
+
+	c, err := semver.NewConstraint(constraint)
 	if err != nil {
-		return
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid %s version constraint '%s' in the CLI config: %w", toolchain, constraint, err),
+			Remediation: fsterr.ConfigRemediation,
+		}
 	}
 
-	versionPattern := regexp.MustCompile(pattern)
-	match := versionPattern.FindStringSubmatch(output)
-	if len(match) < 2 { // We expect a pattern with one capture group.
-		return
+	paths := findBinaries(toolchain, os.Getenv("PATH"))
+	if len(paths) == 0 {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("%s not found on $PATH", toolchain),
+			Remediation: toolchainRemediation(toolchain, constraint, runtime.GOOS, runtime.GOARCH),
+		}
+	}
+	path := paths[0]
+	switch {
+	case len(paths) > 1:
+		text.Info(g.output, "Found multiple %s binaries on $PATH, using '%s' (also found: %s).\n\n", toolchain, path, strings.Join(paths[1:], ", "))
+	case g.verbose:
+		text.Info(g.output, "Using the %s binary '%s'.\n\n", toolchain, path)
 	}
-	version := match[1]
 
-	v, err := semver.NewVersion(version)
+	output, err := ToolchainVersionRunner(path, "version")
 	if err != nil {
-		return
+		g.errlog.Add(err)
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("failed to execute `%s version`: %w", path, err),
+			Remediation: strings.Join([]string{toolchainRemediation(toolchain, constraint, runtime.GOOS, runtime.GOARCH), SkipToolchainCheckRemediation}, " "),
+		}
 	}
 
-	c, err := semver.NewConstraint(constraint)
+	v, err := ParseToolchainVersion(toolchain, string(output))
+	if errors.Is(err, ErrDevelToolchain) {
+		text.Warning(g.output, "Unable to identify the version of the %s development build '%s', skipping the '%s' constraint check.\n\n", toolchain, path, constraint)
+		return nil
+	}
 	if err != nil {
-		return
+		g.errlog.Add(err)
+		return fsterr.RemediationError{
+			Inner:       err,
+			Remediation: SkipToolchainCheckRemediation,
+		}
 	}
 
 	if !c.Check(v) {
-		text.Warning(g.output, "The %s version '%s' didn't meet the constraint '%s'\n\n", toolchain, version, constraint)
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("the %s version '%s' (%s) doesn't meet the constraint '%s'", toolchain, v, path, constraint),
+			Remediation: strings.Join([]string{toolchainRemediation(toolchain, constraint, runtime.GOOS, runtime.GOARCH), SkipToolchainCheckRemediation}, " "),
+		}
+	}
+	return nil
+}
+
+// ErrDevelToolchain is returned by ParseToolchainVersion when the toolchain
+// is a development build that doesn't report a version number.
+var ErrDevelToolchain = errors.New("development build without a version")
+
+var (
+	// goVersionPattern matches `go version` output, e.g.
+	//
+	//	go version go1.21.5 linux/amd64
+	//	go version go1.22rc1 darwin/arm64
+	//	go version go1.20.12 (Red Hat 1.20.12-1.el9_3) linux/amd64
+	//	go version devel go1.23-e8ee1dc4f9 Wed Feb 7 23:15:10 2024 +0000 linux/amd64
+	goVersionPattern = regexp.MustCompile(`\bgo version (?:devel )?go(\d+)(?:\.(\d+))?(?:\.(\d+))?((?:rc|beta)\d+)?`)
+	// goDevelPattern matches `go version` output for a development build that
+	// only reports a commit, e.g. `go version devel +b7a85e0003 linux/amd64`.
+	goDevelPattern = regexp.MustCompile(`\bgo version devel\b`)
+	// tinygoVersionPattern matches `tinygo version` output, e.g.
+	//
+	//	tinygo version 0.30.0 linux/amd64 (using go version go1.21.5 and LLVM version 16.0.1)
+	//	tinygo version 0.31.0-dev-3f4d3a2 darwin/arm64 (using go version go1.22.0 and LLVM version 17.0.1)
+	tinygoVersionPattern = regexp.MustCompile(`\btinygo version v?(\d+)(?:\.(\d+))?(?:\.(\d+))?(-[0-9A-Za-z.-]+)?`)
+)
+
+// ParseToolchainVersion extracts the version from the output of the
+// `go version` or `tinygo version` command.
+//
+// Go release candidates and betas (e.g. go1.22rc1) and development builds
+// (e.g. devel go1.23-e8ee1dc4f9) are treated as the release they precede, so
+// that they satisfy constraints such as '>= 1.21'. Vendor annotations (e.g.
+// X:nocoverageredesign or a distribution suffix) are ignored. TinyGo
+// pre-release suffixes (e.g. 0.31.0-dev) are kept, as the TinyGo constraints
+// explicitly accept pre-releases.
+func ParseToolchainVersion(toolchain, output string) (*semver.Version, error) {
+	var (
+		match      []string
+		prerelease string
+	)
+	switch toolchain {
+	case "go":
+		match = goVersionPattern.FindStringSubmatch(output)
+		if match == nil && goDevelPattern.MatchString(output) {
+			return nil, ErrDevelToolchain
+		}
+	case "tinygo":
+		match = tinygoVersionPattern.FindStringSubmatch(output)
+		if match != nil {
+			prerelease = match[4]
+		}
+	default:
+		return nil, fmt.Errorf("unsupported toolchain '%s'", toolchain)
+	}
+	if match == nil {
+		return nil, fmt.Errorf("unable to parse the %s version from the output of `%s version`: %q", toolchain, toolchain, strings.TrimSpace(output))
+	}
+
+	parts := []string{match[1], match[2], match[3]}
+	for i, p := range parts {
+		if p == "" {
+			parts[i] = "0"
+		}
+	}
+	v, err := semver.StrictNewVersion(strings.Join(parts, ".") + prerelease)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the %s version from the output of `%s version`: %w", toolchain, toolchain, err)
+	}
+	return v, nil
+}
+
+// findBinaries returns the paths of the executables named name in the
+// directories of pathEnv (a $PATH value), in order of precedence.
+func findBinaries(name, pathEnv string) []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, dir := range filepath.SplitList(pathEnv) {
+		if dir == "" {
+			continue
+		}
+		p, err := exec.LookPath(filepath.Join(dir, name))
+		if err != nil || seen[p] {
+			continue
+		}
+		seen[p] = true
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// minimumVersionPattern matches the lower bound of a version constraint.
+var minimumVersionPattern = regexp.MustCompile(`>=?\s*v?(\d+(?:\.\d+){0,2})`)
+
+// toolchainRemediation returns the commands to install or upgrade to a version
+// of the toolchain that satisfies constraint on the given platform.
+func toolchainRemediation(toolchain, constraint, goos, goarch string) string {
+	var version string
+	if match := minimumVersionPattern.FindStringSubmatch(constraint); match != nil {
+		version = match[1]
+	}
+
+	switch toolchain {
+	case "go":
+		if version == "" {
+			return fmt.Sprintf("Install a Go version matching '%s' from https://go.dev/dl/.", constraint)
+		}
+		// Go 1.21+ names its first release `go1.21.0`, earlier ones `go1.20`.
+		if strings.Count(version, ".") == 1 {
+			if v, err := semver.NewVersion(version); err == nil && (v.Major() > 1 || v.Minor() >= 21) {
+				version += ".0"
+			}
+		}
+		return fmt.Sprintf("Install Go %s or later from https://go.dev/dl/, or run `go install golang.org/dl/go%s@latest && go%s download` and use `go%s` in place of `go` in your [scripts.build].", version, version, version, version)
+	case "tinygo":
+		if version == "" {
+			return fmt.Sprintf("Install a TinyGo version matching '%s': https://tinygo.org/getting-started/install/", constraint)
+		}
+		if strings.Count(version, ".") == 1 {
+			version += ".0"
+		}
+		switch goos {
+		case "darwin":
+			return fmt.Sprintf("Install TinyGo %s or later by running `brew tap tinygo-org/tools && brew install tinygo` (or `brew upgrade tinygo` if it's already installed).", version)
+		case "linux":
+			deb := fmt.Sprintf("tinygo_%s_%s.deb", version, goarch)
+			return fmt.Sprintf("Install TinyGo %s or later, e.g. on Debian/Ubuntu run `wget https://github.com/tinygo-org/tinygo/releases/download/v%s/%s && sudo dpkg -i %s` (see https://tinygo.org/getting-started/install/linux/ for other distributions).", version, version, deb, deb)
+		case "windows":
+			return fmt.Sprintf("Install TinyGo %s or later by running `scoop install tinygo` (or `scoop update tinygo` if it's already installed).", version)
+		}
+		return fmt.Sprintf("Install TinyGo %s or later: https://tinygo.org/getting-started/install/", version)
 	}
+	return ""
 }
@@ -38,7 +38,6 @@ const JsSourceDirectory = "src"
 // NewJavaScript constructs a new JavaScript toolchain.
 func NewJavaScript(
 	c *BuildCommand,
-	in io.Reader,
 	manifestFilename string,
 	out io.Writer,
 	spinner text.Spinner,
@@ -46,16 +45,12 @@ func NewJavaScript(
 	return &JavaScript{
 		Shell: Shell{},
 
-		autoYes:               c.Globals.Flags.AutoYes,
 		build:                 c.Globals.Manifest.File.Scripts.Build,
 		env:                   c.Globals.Manifest.File.Scripts.EnvVars,
 		errlog:                c.Globals.ErrLog,
-		input:                 in,
 		manifestFilename:      manifestFilename,
 		metadataFilterEnvVars: c.MetadataFilterEnvVars,
-		nonInteractive:        c.Globals.Flags.NonInteractive,
 		output:                out,
-		postBuild:             c.Globals.Manifest.File.Scripts.PostBuild,
 		spinner:               spinner,
 		timeout:               c.Flags.Timeout,
 		verbose:               c.Globals.Verbose(),
@@ -66,8 +61,6 @@ func NewJavaScript(
 type JavaScript struct {
 	Shell
 
-	// autoYes is the --auto-yes flag.
-	autoYes bool
 	// build is a shell command defined in fastly.toml using [scripts.build].
 	build string
 	// defaultBuild indicates if the default build script was used.
@@ -76,19 +69,12 @@ type JavaScript struct {
 	env []string
 	// errlog is an abstraction for recording errors to disk.
 	errlog fsterr.LogInterface
-	// input is the user's terminal stdin stream
-	input io.Reader
 	// manifestFilename is the name of the manifest file.
 	manifestFilename string
 	// metadataFilterEnvVars is a comma-separated list of user defined env vars.
 	metadataFilterEnvVars string
-	// nonInteractive is the --non-interactive flag.
-	nonInteractive bool
 	// output is the users terminal stdout stream
 	output io.Writer
-	// postBuild is a custom script executed after the build but before the Wasm
-	// binary is added to the .tar.gz archive.
-	postBuild string
 	// spinner is a terminal progress status indicator.
 	spinner text.Spinner
 	// timeout is the build execution threshold.
@@ -156,17 +142,12 @@ func (j *JavaScript) Build() error {
 	}
 
 	bt := BuildToolchain{
-		autoYes:               j.autoYes,
 		buildFn:               j.Shell.Build,
 		buildScript:           j.build,
 		env:                   j.env,
 		errlog:                j.errlog,
-		in:                    j.input,
-		manifestFilename:      j.manifestFilename,
 		metadataFilterEnvVars: j.metadataFilterEnvVars,
-		nonInteractive:        j.nonInteractive,
 		out:                   j.output,
-		postBuild:             j.postBuild,
 		spinner:               j.spinner,
 		timeout:               j.timeout,
 		verbose:               j.verbose,
@@ -10,25 +10,18 @@ import (
 // NewOther constructs a new unsupported language instance.
 func NewOther(
 	c *BuildCommand,
-	in io.Reader,
-	manifestFilename string,
 	out io.Writer,
 	spinner text.Spinner,
 ) *Other {
 	return &Other{
 		Shell: Shell{},
 
-		autoYes:               c.Globals.Flags.AutoYes,
 		build:                 c.Globals.Manifest.File.Scripts.Build,
 		defaultBuild:          false, // there is no default build for 'other'
 		env:                   c.Globals.Manifest.File.Scripts.EnvVars,
 		errlog:                c.Globals.ErrLog,
-		input:                 in,
-		manifestFilename:      manifestFilename,
 		metadataFilterEnvVars: c.MetadataFilterEnvVars,
-		nonInteractive:        c.Globals.Flags.NonInteractive,
 		output:                out,
-		postBuild:             c.Globals.Manifest.File.Scripts.PostBuild,
 		spinner:               spinner,
 		timeout:               c.Flags.Timeout,
 		verbose:               c.Globals.Verbose(),
@@ -39,8 +32,6 @@ func NewOther(
 type Other struct {
 	Shell
 
-	// autoYes is the --auto-yes flag.
-	autoYes bool
 	// build is a shell command defined in fastly.toml using [scripts.build].
 	build string
 	// defaultBuild indicates if the default build script was used.
@@ -49,19 +40,10 @@ type Other struct {
 	env []string
 	// errlog is an abstraction for recording errors to disk.
 	errlog fsterr.LogInterface
-	// input is the user's terminal stdin stream
-	input io.Reader
-	// manifestFilename is the name of the manifest file.
-	manifestFilename string
 	// metadataFilterEnvVars is a comma-separated list of user defined env vars.
 	metadataFilterEnvVars string
-	// nonInteractive is the --non-interactive flag.
-	nonInteractive bool
 	// output is the users terminal stdout stream
 	output io.Writer
-	// postBuild is a custom script executed after the build but before the Wasm
-	// binary is added to the .tar.gz archive.
-	postBuild string
 	// spinner is a terminal progress status indicator.
 	spinner text.Spinner
 	// timeout is the build execution threshold.
@@ -85,17 +67,12 @@ func (o Other) Dependencies() map[string]string {
 // source to a Wasm binary.
 func (o Other) Build() error {
 	bt := BuildToolchain{
-		autoYes:               o.autoYes,
 		buildFn:               o.Shell.Build,
 		buildScript:           o.build,
 		env:                   o.env,
 		errlog:                o.errlog,
-		in:                    o.input,
-		manifestFilename:      o.manifestFilename,
 		metadataFilterEnvVars: o.metadataFilterEnvVars,
-		nonInteractive:        o.nonInteractive,
 		out:                   o.output,
-		postBuild:             o.postBuild,
 		spinner:               o.spinner,
 		timeout:               o.timeout,
 		verbose:               o.verbose,
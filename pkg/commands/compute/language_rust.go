@@ -43,7 +43,6 @@ const RustSourceDirectory = "src"
 // NewRust constructs a new Rust toolchain.
 func NewRust(
 	c *BuildCommand,
-	in io.Reader,
 	manifestFilename string,
 	out io.Writer,
 	spinner text.Spinner,
@@ -51,17 +50,13 @@ func NewRust(
 	return &Rust{
 		Shell: Shell{},
 
-		autoYes:               c.Globals.Flags.AutoYes,
 		build:                 c.Globals.Manifest.File.Scripts.Build,
 		config:                c.Globals.Config.Language.Rust,
 		env:                   c.Globals.Manifest.File.Scripts.EnvVars,
 		errlog:                c.Globals.ErrLog,
-		input:                 in,
 		manifestFilename:      manifestFilename,
 		metadataFilterEnvVars: c.MetadataFilterEnvVars,
-		nonInteractive:        c.Globals.Flags.NonInteractive,
 		output:                out,
-		postBuild:             c.Globals.Manifest.File.Scripts.PostBuild,
 		spinner:               spinner,
 		timeout:               c.Flags.Timeout,
 		verbose:               c.Globals.Verbose(),
@@ -72,8 +67,6 @@ func NewRust(
 type Rust struct {
 	Shell
 
-	// autoYes is the --auto-yes flag.
-	autoYes bool
 	// build is a shell command defined in fastly.toml using [scripts.build].
 	build string
 	// config is the Rust specific application configuration.
@@ -84,21 +77,14 @@ type Rust struct {
 	env []string
 	// errlog is an abstraction for recording errors to disk.
 	errlog fsterr.LogInterface
-	// input is the user's terminal stdin stream
-	input io.Reader
 	// manifestFilename is the name of the manifest file.
 	manifestFilename string
 	// metadataFilterEnvVars is a comma-separated list of user defined env vars.
 	metadataFilterEnvVars string
-	// nonInteractive is the --non-interactive flag.
-	nonInteractive bool
 	// output is the users terminal stdout stream
 	output io.Writer
 	// packageName is the resolved package name from the project Cargo.toml
 	packageName string
-	// postBuild is a custom script executed after the build but before the Wasm
-	// binary is added to the .tar.gz archive.
-	postBuild string
 	// projectRoot is the root directory where the Cargo.toml is located.
 	projectRoot string
 	// spinner is a terminal progress status indicator.
@@ -160,18 +146,13 @@ func (r *Rust) Build() error {
 	r.toolchainConstraint()
 
 	bt := BuildToolchain{
-		autoYes:                   r.autoYes,
 		buildFn:                   r.Shell.Build,
 		buildScript:               r.build,
 		env:                       r.env,
 		errlog:                    r.errlog,
-		in:                        r.input,
 		internalPostBuildCallback: r.ProcessLocation,
-		manifestFilename:          r.manifestFilename,
 		metadataFilterEnvVars:     r.metadataFilterEnvVars,
-		nonInteractive:            r.nonInteractive,
 		out:                       r.output,
-		postBuild:                 r.postBuild,
 		spinner:                   r.spinner,
 		timeout:                   r.timeout,
 		verbose:                   r.verbose,
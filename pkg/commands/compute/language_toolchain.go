@@ -11,7 +11,6 @@ import (
 
 	fsterr "github.com/fastly/cli/pkg/errors"
 	fstexec "github.com/fastly/cli/pkg/exec"
-	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/text"
 )
 
@@ -33,10 +32,9 @@ var DefaultBuildErrorRemediation = func() string {
 - Is the required version (if any) of the language toolchain installed/activated?
 - Were the required dependencies (package.json, Cargo.toml etc) installed?
 - Did the build script (see fastly.toml [scripts.build]) produce a ./bin/main.wasm binary file?
-- Was there a configured [scripts.post_build] step that needs to be double-checked?
 
 For more information on fastly.toml configuration settings, refer to https://developer.fastly.com/reference/compute/fastly-toml/`,
-		text.BoldYellow("Here are some steps you can follow to debug the issue"))
+		text.WarningStyle("Here are some steps you can follow to debug the issue"))
 }()
 
 // Toolchain abstracts a Compute source language toolchain.
@@ -51,8 +49,6 @@ type Toolchain interface {
 
 // BuildToolchain enables a language toolchain to compile their build script.
 type BuildToolchain struct {
-	// autoYes is the --auto-yes flag.
-	autoYes bool
 	// buildFn constructs a `sh -c` command from the buildScript.
 	buildFn func(string) (string, []string)
 	// buildScript is the [scripts.build] within the fastly.toml manifest.
@@ -61,21 +57,12 @@ type BuildToolchain struct {
 	env []string
 	// errlog is an abstraction for recording errors to disk.
 	errlog fsterr.LogInterface
-	// in is the user's terminal stdin stream
-	in io.Reader
-	// internalPostBuildCallback is run after the build but before post build.
+	// internalPostBuildCallback is run after the build but before post_build.
 	internalPostBuildCallback func() error
-	// manifestFilename is the name of the manifest file.
-	manifestFilename string
 	// metadataFilterEnvVars is a comma-separated list of user defined env vars.
 	metadataFilterEnvVars string
-	// nonInteractive is the --non-interactive flag.
-	nonInteractive bool
 	// out is the users terminal stdout stream
 	out io.Writer
-	// postBuild is a custom script executed after the build but before the Wasm
-	// binary is added to the .tar.gz archive.
-	postBuild string
 	// spinner is a terminal progress status indicator.
 	spinner text.Spinner
 	// timeout is the build execution threshold.
@@ -167,7 +154,7 @@ func (bt BuildToolchain) Build() error {
 	// NOTE: internalPostBuildCallback is only used by Rust currently.
 	// It's not a step that would be configured by a user in their fastly.toml
 	// It enables Rust to move the compiled binary to a different location.
-	// This has to happen BEFORE the postBuild step.
+	// This has to happen BEFORE the post_build hook.
 	if bt.internalPostBuildCallback != nil {
 		err := bt.internalPostBuildCallback()
 		if err != nil {
@@ -187,72 +174,6 @@ func (bt BuildToolchain) Build() error {
 		return err
 	}
 
-	if bt.postBuild != "" {
-		if !bt.autoYes && !bt.nonInteractive {
-			manifestFilename := bt.manifestFilename
-			if manifestFilename == "" {
-				manifestFilename = manifest.Filename
-			}
-			msg := fmt.Sprintf(CustomPostScriptMessage, "build", manifestFilename)
-			err := bt.promptForPostBuildContinue(msg, bt.postBuild, bt.out, bt.in)
-			if err != nil {
-				return err
-			}
-		}
-
-		// If we're in verbose mode, the build output is shown.
-		// So in that case we don't want to have a spinner as it'll interweave output.
-		// In non-verbose mode we have a spinner running while the build is happening.
-		if !bt.verbose {
-			err = bt.spinner.Start()
-			if err != nil {
-				return err
-			}
-			msg = "Running [scripts.post_build]..."
-			bt.spinner.Message(msg)
-		}
-
-		cmd, args := bt.buildFn(bt.postBuild)
-		err := bt.execCommand(cmd, args, msg)
-		if err != nil {
-			// In verbose mode we'll have the failure status AFTER the error output.
-			// But we can't just call StopFailMessage() without first starting the spinner.
-			if bt.verbose {
-				text.Break(bt.out)
-				spinErr := bt.spinner.Start()
-				if spinErr != nil {
-					return fmt.Errorf(text.SpinnerErrWrapper, spinErr, err)
-				}
-				bt.spinner.Message(msg + "...")
-				bt.spinner.StopFailMessage(msg)
-				spinErr = bt.spinner.StopFail()
-				if spinErr != nil {
-					return fmt.Errorf(text.SpinnerErrWrapper, spinErr, err)
-				}
-			}
-			// WARNING: Don't try to add 'StopFailMessage/StopFail' calls here.
-			// It is handled internally by fstexec.Streaming.Exec().
-			return bt.handleError(err)
-		}
-
-		// In verbose mode we'll have the failure status AFTER the error output.
-		// But we can't just call StopMessage() without first starting the spinner.
-		if bt.verbose {
-			err = bt.spinner.Start()
-			if err != nil {
-				return err
-			}
-			bt.spinner.Message(msg + "...")
-			text.Break(bt.out)
-		}
-
-		bt.spinner.StopMessage(msg)
-		err = bt.spinner.Stop()
-		if err != nil {
-			return err
-		}
-	}
-
 	return nil
 }
 
@@ -309,7 +230,7 @@ func (bt BuildToolchain) handleError(err error) error {
 // This causes the spinner message to be displayed twice with different status.
 // By passing in the spinner and message we can short-circuit the spinner.
 func (bt BuildToolchain) execCommand(cmd string, args []string, spinMessage string) error {
-	return fstexec.Command(fstexec.CommandOpts{
+	return ScriptRunner(fstexec.CommandOpts{
 		Args:           args,
 		Command:        cmd,
 		Env:            bt.env,
@@ -321,21 +242,3 @@ func (bt BuildToolchain) execCommand(cmd string, args []string, spinMessage stri
 		Verbose:        bt.verbose,
 	})
 }
-
-// promptForPostBuildContinue ensures the user is happy to continue with the build
-// when there is a post_build in the fastly.toml manifest file.
-func (bt BuildToolchain) promptForPostBuildContinue(msg, script string, out io.Writer, in io.Reader) error {
-	text.Info(out, "%s:\n", msg)
-	text.Indent(out, 4, "%s", script)
-
-	label := "\nDo you want to run this now? [y/N] "
-	answer, err := text.AskYesNo(out, label, in)
-	if err != nil {
-		return err
-	}
-	if !answer {
-		return fsterr.ErrPostBuildStopped
-	}
-	text.Break(out)
-	return nil
-}
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/mholt/archiver/v3"
 
@@ -100,6 +103,11 @@ func (c *PackCommand) Exec(_ io.Reader, out io.Writer) (err error) {
 		return err
 	}
 
+	includes, err := resolveIncludes(c.Globals.Manifest.File.Package.Include)
+	if err != nil {
+		return err
+	}
+
 	err = spinner.Process("Copying manifest", func(_ *text.SpinnerWrapper) error {
 		src = manifest.Filename
 		dst = fmt.Sprintf("pkg/package/%s", manifest.Filename)
@@ -116,7 +124,29 @@ func (c *PackCommand) Exec(_ io.Reader, out io.Writer) (err error) {
 		return err
 	}
 
-	return spinner.Process("Creating package.tar.gz file", func(_ *text.SpinnerWrapper) error {
+	if len(includes) > 0 {
+		err = spinner.Process("Copying included files", func(_ *text.SpinnerWrapper) error {
+			for _, f := range includes {
+				dst := filepath.Join("pkg", "package", filepath.FromSlash(f.Destination))
+				if err := filesystem.CopyFile(f.Source, dst); err != nil {
+					c.Globals.ErrLog.AddWithContext(err, map[string]any{
+						"Include (destination)": dst,
+						"Include (source)":      f.Source,
+					})
+					return fmt.Errorf("error copying '%s' to '%s': %w", f.Source, dst, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// NOTE: The archive entries are added in lexical order of their paths (the
+	// package directory is walked), so the archive layout is deterministic.
+	pkgPath := "pkg/package.tar.gz"
+	err = spinner.Process("Creating package.tar.gz file", func(_ *text.SpinnerWrapper) error {
 		tar := archiver.NewTarGz()
 		tar.OverwriteExisting = true
 		{
@@ -133,4 +163,190 @@ func (c *PackCommand) Exec(_ io.Reader, out io.Writer) (err error) {
 		}
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	return c.printSizeSummary(out, bin, includes, pkgPath)
+}
+
+// packageInclude is a file to be copied into the package.
+type packageInclude struct {
+	// Source is the path of the file on disk.
+	Source string
+	// Destination is the slash-separated path of the file inside the package.
+	Destination string
+	// Rule is the manifest include rule (source glob) that matched the file.
+	Rule string
+	// Size is the size of the file in bytes.
+	Size int64
+}
+
+// reservedPackagePaths are the package paths written by `compute pack` itself.
+var reservedPackagePaths = map[string]string{
+	"bin/main.wasm":   "the Wasm binary",
+	manifest.Filename: "the manifest",
+}
+
+// resolveIncludes expands the [[package.include]] rules from the manifest into
+// the files to be copied into the package, sorted by their destination.
+//
+// An error is returned if a rule is invalid, matches no files, or if two files
+// (or a file and the Wasm binary/manifest) would end up at the same path.
+func resolveIncludes(rules []manifest.PackageInclude) ([]packageInclude, error) {
+	var includes []packageInclude
+	for _, rule := range rules {
+		files, err := resolveInclude(rule)
+		if err != nil {
+			return nil, fsterr.RemediationError{
+				Inner:       err,
+				Remediation: PackageIncludeRemediation,
+			}
+		}
+		includes = append(includes, files...)
+	}
+	if err := validateIncludeDestinations(includes); err != nil {
+		return nil, fsterr.RemediationError{
+			Inner:       err,
+			Remediation: PackageIncludeRemediation,
+		}
+	}
+	sort.Slice(includes, func(i, j int) bool {
+		return includes[i].Destination < includes[j].Destination
+	})
+	return includes, nil
+}
+
+// PackageIncludeRemediation suggests fixing the [[package.include]] rules.
+var PackageIncludeRemediation = fmt.Sprintf("Update the [[package.include]] rules in the %s manifest. Each rule needs a `source` glob pattern and a `destination` path relative to the root of the package (use a trailing slash to copy the matched files into a directory).", manifest.Filename)
+
+// resolveInclude expands a single include rule.
+func resolveInclude(rule manifest.PackageInclude) ([]packageInclude, error) {
+	if rule.Source == "" {
+		return nil, fmt.Errorf("package include with destination '%s' has no source", rule.Destination)
+	}
+	dir := strings.HasSuffix(rule.Destination, "/")
+	dst := path.Clean(filepath.ToSlash(rule.Destination))
+	if rule.Destination == "" || dst == "." || path.IsAbs(dst) || dst == ".." || strings.HasPrefix(dst, "../") {
+		return nil, fmt.Errorf("package include '%s' has an invalid destination '%s': it must be a path inside the package", rule.Source, rule.Destination)
+	}
+
+	matches, err := filepath.Glob(rule.Source)
+	if err != nil {
+		return nil, fmt.Errorf("package include '%s' has an invalid source pattern: %w", rule.Source, err)
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("package include '%s' matched no files", rule.Source)
+	}
+	if len(matches) > 1 && !dir {
+		return nil, fmt.Errorf("package include '%s' matched %d files but its destination '%s' is a file (add a trailing slash to copy them into a directory)", rule.Source, len(matches), rule.Destination)
+	}
+
+	files := make([]packageInclude, 0, len(matches))
+	for _, m := range matches {
+		fi, err := os.Stat(m)
+		if err != nil {
+			return nil, filesystem.Wrap("read package include", m, err)
+		}
+		if fi.IsDir() {
+			return nil, fmt.Errorf("package include '%s' matched the directory '%s' (use a pattern matching files, e.g. '%s')", rule.Source, m, path.Join(filepath.ToSlash(m), "*"))
+		}
+		d := dst
+		if dir {
+			d = path.Join(dst, filepath.Base(m))
+		}
+		files = append(files, packageInclude{
+			Source:      m,
+			Destination: d,
+			Rule:        rule.Source,
+			Size:        fi.Size(),
+		})
+	}
+	return files, nil
+}
+
+// validateIncludeDestinations returns an error if any of the includes would
+// overwrite another file in the package, or a file would need to be a
+// directory (e.g. 'data' and 'data/file.bin').
+func validateIncludeDestinations(includes []packageInclude) error {
+	owners := make(map[string]string, len(includes)+len(reservedPackagePaths))
+	for p, owner := range reservedPackagePaths {
+		owners[p] = owner
+	}
+	for _, f := range includes {
+		owner := fmt.Sprintf("'%s' (from include '%s')", f.Source, f.Rule)
+		if existing, ok := owners[f.Destination]; ok {
+			return fmt.Errorf("package include destination '%s' collides: it's used by both %s and %s", f.Destination, existing, owner)
+		}
+		owners[f.Destination] = owner
+	}
+	paths := make([]string, 0, len(owners))
+	for p := range owners {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		for dir := path.Dir(p); dir != "."; dir = path.Dir(dir) {
+			if existing, ok := owners[dir]; ok {
+				return fmt.Errorf("package include destination '%s' collides: it's a file used by %s but is also a directory containing %s", dir, existing, owners[p])
+			}
+		}
+	}
+	return nil
+}
+
+// printSizeSummary displays the size of each file in the package and of the
+// package itself. Included files larger than the package size limit are
+// reported individually, and an error is returned if the package exceeds it.
+func (c *PackCommand) printSizeSummary(out io.Writer, bin string, includes []packageInclude, pkgPath string) error {
+	type entry struct {
+		path string
+		size int64
+	}
+	entries := make([]entry, 0, len(includes)+2)
+	for p, src := range map[string]string{"bin/main.wasm": bin, manifest.Filename: manifest.Filename} {
+		fi, err := os.Stat(src)
+		if err != nil {
+			return filesystem.Wrap("read", src, err)
+		}
+		entries = append(entries, entry{p, fi.Size()})
+	}
+	for _, f := range includes {
+		entries = append(entries, entry{f.Destination, f.Size})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].path < entries[j].path
+	})
+
+	fi, err := os.Stat(pkgPath)
+	if err != nil {
+		return filesystem.Wrap("read", pkgPath, err)
+	}
+
+	text.Break(out)
+	tbl := text.NewTable(out)
+	tbl.AddHeader("PATH", "SIZE (BYTES)")
+	for _, e := range entries {
+		tbl.AddLine(e.path, e.size)
+	}
+	tbl.Print()
+	text.Break(out)
+	text.Output(out, "Package size: %d bytes (compressed)", fi.Size())
+
+	for _, f := range includes {
+		if f.Size > MaxPackageSize {
+			text.Warning(out, "Included file '%s' (from '%s') is %d bytes, which is larger than the package size limit (%d bytes)", f.Destination, f.Source, f.Size, MaxPackageSize)
+		}
+	}
+	if fi.Size() > MaxPackageSize {
+		return fsterr.RemediationError{
+			Inner: fmt.Errorf("package size is too large (%d bytes)", fi.Size()),
+			Remediation: fsterr.RenderRemediation(fsterr.RemediationPackageSize, map[string]any{
+				"Size":  text.Bytes(float64(fi.Size())),
+				"Limit": text.Bytes(float64(MaxPackageSize)),
+			}),
+			Code: fsterr.CodePackageSize,
+		}
+	}
+	return nil
 }
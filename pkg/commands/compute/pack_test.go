@@ -1,13 +1,17 @@
 package compute_test
 
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
+	"errors"
 	"io"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/testutil"
@@ -105,3 +109,249 @@ func TestPack(t *testing.T) {
 		})
 	}
 }
+
+func TestPackIncludes(t *testing.T) {
+	args := testutil.Args
+	fixtures := []testutil.FileIO{
+		{Src: "aaaa", Dst: filepath.Join("assets", "data", "a.bin")},
+		{Src: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", Dst: filepath.Join("assets", "data", "b.bin")},
+		{Src: `{"debug":false}`, Dst: filepath.Join("assets", "config.json")},
+		{Src: "(module)", Dst: filepath.Join("assets", "helper.wasm")},
+	}
+
+	for _, testcase := range []struct {
+		name           string
+		include        string
+		maxPackageSize int64
+		wantError      string
+		wantOutput     []string
+		wantEntries    []string
+		wantContent    map[string]string
+	}{
+		{
+			name: "includes mapped to destinations",
+			include: `
+			[[package.include]]
+			source = "assets/data/*.bin"
+			destination = "data/"
+
+			[[package.include]]
+			source = "assets/config.json"
+			destination = "config/app.json"
+
+			[[package.include]]
+			source = "assets/helper.wasm"
+			destination = "bin/helper.wasm"`,
+			wantOutput: []string{
+				"Copying included files",
+				"data/a.bin       4",
+				"data/b.bin       100",
+				"config/app.json  15",
+				"Package size: ",
+			},
+			wantEntries: []string{
+				"package/bin/helper.wasm",
+				"package/bin/main.wasm",
+				"package/config/app.json",
+				"package/data/a.bin",
+				"package/data/b.bin",
+				"package/fastly.toml",
+			},
+			wantContent: map[string]string{
+				"package/config/app.json": `{"debug":false}`,
+				"package/data/a.bin":      "aaaa",
+			},
+		},
+		{
+			name: "no includes",
+			wantEntries: []string{
+				"package/bin/main.wasm",
+				"package/fastly.toml",
+			},
+		},
+		{
+			name: "collision with the wasm binary",
+			include: `
+			[[package.include]]
+			source = "assets/helper.wasm"
+			destination = "bin/main.wasm"`,
+			wantError: "package include destination 'bin/main.wasm' collides: it's used by both the Wasm binary and 'assets/helper.wasm' (from include 'assets/helper.wasm')",
+		},
+		{
+			name: "collision with the manifest",
+			include: `
+			[[package.include]]
+			source = "assets/config.json"
+			destination = "./fastly.toml"`,
+			wantError: "package include destination 'fastly.toml' collides: it's used by both the manifest",
+		},
+		{
+			name: "collision between includes",
+			include: `
+			[[package.include]]
+			source = "assets/data/a.bin"
+			destination = "data/"
+
+			[[package.include]]
+			source = "assets/config.json"
+			destination = "data/a.bin"`,
+			wantError: "package include destination 'data/a.bin' collides: it's used by both 'assets/data/a.bin' (from include 'assets/data/a.bin') and 'assets/config.json' (from include 'assets/config.json')",
+		},
+		{
+			name: "collision between a file and a directory",
+			include: `
+			[[package.include]]
+			source = "assets/data/*.bin"
+			destination = "data/"
+
+			[[package.include]]
+			source = "assets/config.json"
+			destination = "data"`,
+			wantError: "package include destination 'data' collides: it's a file used by 'assets/config.json' (from include 'assets/config.json') but is also a directory containing",
+		},
+		{
+			name: "no matching files",
+			include: `
+			[[package.include]]
+			source = "assets/*.txt"
+			destination = "txt/"`,
+			wantError: "package include 'assets/*.txt' matched no files",
+		},
+		{
+			name: "multiple files for a file destination",
+			include: `
+			[[package.include]]
+			source = "assets/data/*.bin"
+			destination = "data.bin"`,
+			wantError: "package include 'assets/data/*.bin' matched 2 files but its destination 'data.bin' is a file",
+		},
+		{
+			name: "directory matched",
+			include: `
+			[[package.include]]
+			source = "assets/data"
+			destination = "data/"`,
+			wantError: "package include 'assets/data' matched the directory 'assets/data' (use a pattern matching files, e.g. 'assets/data/*')",
+		},
+		{
+			name: "destination outside the package",
+			include: `
+			[[package.include]]
+			source = "assets/config.json"
+			destination = "../config.json"`,
+			wantError: "package include 'assets/config.json' has an invalid destination '../config.json': it must be a path inside the package",
+		},
+		{
+			name: "missing destination",
+			include: `
+			[[package.include]]
+			source = "assets/config.json"`,
+			wantError: "package include 'assets/config.json' has an invalid destination ''",
+		},
+		{
+			name: "oversized include reported",
+			include: `
+			[[package.include]]
+			source = "assets/data/*.bin"
+			destination = "data/"`,
+			maxPackageSize: 50,
+			wantOutput: []string{
+				"Included file 'data/b.bin' (from 'assets/data/b.bin') is 100 bytes, which is larger than the package size limit (50 bytes)",
+			},
+			wantError: "package size is too large",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			if testcase.maxPackageSize > 0 {
+				original := compute.MaxPackageSize
+				compute.MaxPackageSize = testcase.maxPackageSize
+				defer func() { compute.MaxPackageSize = original }()
+			}
+
+			pwd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rootdir := testutil.NewEnv(testutil.EnvOpts{
+				T: t,
+				Copy: []testutil.FileIO{
+					{Src: filepath.Join("testdata", "pack", "main.wasm"), Dst: "main.wasm"},
+				},
+				Write: append([]testutil.FileIO{
+					{Src: "manifest_version = 2\nname = \"mypackagename\"\n" + testcase.include, Dst: manifest.Filename},
+				}, fixtures...),
+			})
+			defer os.RemoveAll(rootdir)
+
+			if err := os.Chdir(rootdir); err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				_ = os.Chdir(pwd)
+			}()
+
+			args := args("compute pack --wasm-binary ./main.wasm")
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				return testutil.MockGlobalData(args, &stdout), nil
+			}
+			err = app.Run(args, nil)
+
+			t.Log(stdout.String())
+
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if testcase.wantError != "" && testcase.maxPackageSize == 0 {
+				testutil.AssertRemediationErrorContains(t, err, compute.PackageIncludeRemediation)
+			}
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			if testcase.wantEntries == nil {
+				return
+			}
+
+			entries, contents := readPackageArchive(t, filepath.Join(rootdir, "pkg", "package.tar.gz"))
+			testutil.AssertEqual(t, testcase.wantEntries, entries)
+			for name, want := range testcase.wantContent {
+				testutil.AssertString(t, want, contents[name])
+			}
+		})
+	}
+}
+
+// readPackageArchive returns the names of the regular files in the package
+// archive (in archive order) along with their contents.
+func readPackageArchive(t *testing.T, pkgPath string) (names []string, contents map[string]string) {
+	f, err := os.Open(pkgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents = make(map[string]string)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, hdr.Name)
+		contents[hdr.Name] = string(data)
+	}
+	return names, contents
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/text"
 )
@@ -13,6 +14,7 @@ import (
 // PublishCommand produces and deploys an artifact from files on the local disk.
 type PublishCommand struct {
 	argparser.Base
+	argparser.JSONOutput
 	build  *BuildCommand
 	deploy *DeployCommand
 
@@ -24,13 +26,20 @@ type PublishCommand struct {
 	metadataFilterEnvVars argparser.OptionalString
 	metadataShow          argparser.OptionalBool
 	packageName           argparser.OptionalString
+	reproducible          argparser.OptionalBool
+	skipToolchainCheck    argparser.OptionalBool
 	timeout               argparser.OptionalInt
 
 	// Deploy fields
+	acceptRemote       bool
+	allowABIMismatch   bool
 	comment            argparser.OptionalString
 	domain             argparser.OptionalString
 	env                argparser.OptionalString
 	pkg                argparser.OptionalString
+	pushLocal          bool
+	requireBranch      string
+	requireClean       bool
 	serviceName        argparser.OptionalServiceNameID
 	serviceVersion     argparser.OptionalServiceVersion
 	statusCheckCode    int
@@ -50,17 +59,24 @@ func NewPublishCommand(parent argparser.Registerer, g *global.Data, build *Build
 	c.deploy = deploy
 	c.CmdClause = parent.Command("publish", "Build and deploy a Compute package to a Fastly service")
 
+	c.CmdClause.Flag("accept-remote", acceptRemoteDesc).BoolVar(&c.acceptRemote)
+	c.CmdClause.Flag("allow-abi-mismatch", allowABIMismatchDesc).BoolVar(&c.allowABIMismatch)
 	c.CmdClause.Flag("comment", "Human-readable comment").Action(c.comment.Set).StringVar(&c.comment.Value)
 	c.CmdClause.Flag("dir", "Project directory to build (default: current directory)").Short('C').Action(c.dir.Set).StringVar(&c.dir.Value)
 	c.CmdClause.Flag("domain", "The name of the domain associated to the package").Action(c.domain.Set).StringVar(&c.domain.Value)
 	c.CmdClause.Flag("env", "The manifest environment config to use (e.g. 'stage' will attempt to read 'fastly.stage.toml')").Action(c.env.Set).StringVar(&c.env.Value)
 	c.CmdClause.Flag("include-source", "Include source code in built package").Action(c.includeSrc.Set).BoolVar(&c.includeSrc.Value)
+	c.RegisterFlagBool(c.JSONFlag()) // --json
 	c.CmdClause.Flag("language", "Language type").Action(c.lang.Set).StringVar(&c.lang.Value)
 	c.CmdClause.Flag("metadata-disable", "Disable Wasm binary metadata annotations").Action(c.metadataDisable.Set).BoolVar(&c.metadataDisable.Value)
 	c.CmdClause.Flag("metadata-filter-envvars", "Redact specified environment variables from [scripts.env_vars] using comma-separated list").Action(c.metadataFilterEnvVars.Set).StringVar(&c.metadataFilterEnvVars.Value)
 	c.CmdClause.Flag("metadata-show", "Inspect the Wasm binary metadata").Action(c.metadataShow.Set).BoolVar(&c.metadataShow.Value)
 	c.CmdClause.Flag("package", "Path to a package tar.gz").Short('p').Action(c.pkg.Set).StringVar(&c.pkg.Value)
 	c.CmdClause.Flag("package-name", "Package name").Action(c.packageName.Set).StringVar(&c.packageName.Value)
+	c.CmdClause.Flag("push-local", pushLocalDesc).BoolVar(&c.pushLocal)
+	c.CmdClause.Flag("reproducible", "Build a byte-identical package from identical sources (timestamps come from SOURCE_DATE_EPOCH, and environment-dependent metadata is omitted)").Action(c.reproducible.Set).BoolVar(&c.reproducible.Value)
+	c.CmdClause.Flag("require-branch", requireBranchDesc).StringVar(&c.requireBranch)
+	c.CmdClause.Flag("require-clean", requireCleanDesc).BoolVar(&c.requireClean)
 	c.RegisterFlag(argparser.StringFlagOpts{
 		Name:        argparser.FlagServiceIDName,
 		Description: argparser.FlagServiceIDDesc,
@@ -83,11 +99,18 @@ func NewPublishCommand(parent argparser.Registerer, g *global.Data, build *Build
 		Dst:         &c.serviceVersion.Value,
 		Action:      c.serviceVersion.Set,
 	})
+	c.CmdClause.Flag("skip-toolchain-check", "Skip checking the installed toolchain version meets the requirements (Go only)").Action(c.skipToolchainCheck.Set).BoolVar(&c.skipToolchainCheck.Value)
 	c.CmdClause.Flag("timeout", "Timeout, in seconds, for the build compilation step").Action(c.timeout.Set).IntVar(&c.timeout.Value)
 
 	return &c
 }
 
+// Interruptible implements the argparser.Interruptible interface, as the deploy
+// step stops when interrupted.
+func (c *PublishCommand) Interruptible() bool {
+	return true
+}
+
 // Exec implements the command interface.
 //
 // NOTE: unlike other non-aggregate commands that initialize a new
@@ -96,6 +119,13 @@ func NewPublishCommand(parent argparser.Registerer, g *global.Data, build *Build
 // non-deterministic ways. It's best to leave those nested commands to handle
 // the progress indicator.
 func (c *PublishCommand) Exec(in io.Reader, out io.Writer) (err error) {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+	// With --json only the deploy result is displayed.
+	result := out
+	out = c.ProgressOutput(c.Globals, out)
+
 	wd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get current working directory: %w", err)
@@ -114,6 +144,18 @@ func (c *PublishCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		}
 	}
 
+	// The guards are checked before building, so the package isn't built from a
+	// working tree that won't be deployed, and the result is recorded in the
+	// Wasm binary's metadata.
+	guards := VCSGuards{RequireBranch: c.requireBranch, RequireClean: c.requireClean}
+	vcs, err := guards.Check(c.Globals.ExecuteGit)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	c.build.VCSStatus = vcs
+	c.deploy.VCSStatus = vcs
+
 	err = c.Build(in, out)
 	if err != nil {
 		c.Globals.ErrLog.Add(err)
@@ -122,7 +164,7 @@ func (c *PublishCommand) Exec(in io.Reader, out io.Writer) (err error) {
 
 	text.Break(out)
 
-	err = c.Deploy(in, out)
+	err = c.Deploy(in, result)
 	if err != nil {
 		c.Globals.ErrLog.Add(err)
 		return err
@@ -148,6 +190,12 @@ func (c *PublishCommand) Build(in io.Reader, out io.Writer) error {
 	if c.packageName.WasSet {
 		c.build.Flags.PackageName = c.packageName.Value
 	}
+	if c.reproducible.WasSet {
+		c.build.Flags.Reproducible = c.reproducible.Value
+	}
+	if c.skipToolchainCheck.WasSet {
+		c.build.Flags.SkipToolchainCheck = c.skipToolchainCheck.Value
+	}
 	if c.timeout.WasSet {
 		c.build.Flags.Timeout = c.timeout.Value
 	}
@@ -190,6 +238,15 @@ func (c *PublishCommand) Deploy(in io.Reader, out io.Writer) error {
 	if c.comment.WasSet {
 		c.deploy.Comment = c.comment
 	}
+	if c.acceptRemote {
+		c.deploy.AcceptRemote = c.acceptRemote
+	}
+	if c.allowABIMismatch {
+		c.deploy.AllowABIMismatch = c.allowABIMismatch
+	}
+	if c.pushLocal {
+		c.deploy.PushLocal = c.pushLocal
+	}
 	if c.statusCheckCode > 0 {
 		c.deploy.StatusCheckCode = c.statusCheckCode
 	}
@@ -200,5 +257,6 @@ func (c *PublishCommand) Deploy(in io.Reader, out io.Writer) error {
 		c.deploy.StatusCheckTimeout = c.statusCheckTimeout
 	}
 	c.deploy.StatusCheckPath = c.statusCheckPath
+	c.deploy.JSONOutput.Enabled = c.JSONOutput.Enabled
 	return c.deploy.Exec(in, out)
 }
@@ -0,0 +1,153 @@
+package compute
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// readyPollInterval is how often the local server is polled until it's ready.
+const readyPollInterval = 100 * time.Millisecond
+
+// serverOutputLines is the number of lines of the local server's output
+// reported when it fails to start.
+const serverOutputLines = 10
+
+var (
+	// ErrServerExited means the local server exited before it was ready.
+	ErrServerExited = errors.New("the local server exited before it was ready to accept connections")
+	// ErrServerTimeout means the local server wasn't ready within the
+	// --wait-timeout.
+	ErrServerTimeout = errors.New("timed out waiting for the local server to accept connections")
+)
+
+// ServerOutput keeps the last lines written by the local server, so they can
+// be reported if it fails to start.
+type ServerOutput struct {
+	mu      sync.Mutex
+	max     int
+	lines   []string
+	partial []byte
+}
+
+// NewServerOutput returns a ServerOutput that keeps the last n lines.
+func NewServerOutput(n int) *ServerOutput {
+	return &ServerOutput{max: n}
+}
+
+// Write implements io.Writer.
+func (o *ServerOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.partial = append(o.partial, p...)
+	for {
+		i := bytes.IndexByte(o.partial, '\n')
+		if i < 0 {
+			break
+		}
+		o.add(string(o.partial[:i]))
+		o.partial = o.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+func (o *ServerOutput) add(line string) {
+	line = strings.TrimRight(line, "\r")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	o.lines = append(o.lines, line)
+	if len(o.lines) > o.max {
+		o.lines = o.lines[len(o.lines)-o.max:]
+	}
+}
+
+// Lines returns the last lines written, including a final line without a
+// newline.
+func (o *ServerOutput) Lines() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	lines := append([]string(nil), o.lines...)
+	if strings.TrimSpace(string(o.partial)) != "" {
+		lines = append(lines, string(o.partial))
+	}
+	if len(lines) > o.max {
+		lines = lines[len(lines)-o.max:]
+	}
+	return lines
+}
+
+// WaitForServer polls the local server at addr until it responds to an HTTP
+// request (with any status), and returns nil once it does.
+//
+// The result of the server process is received from exited. If the process
+// exits first, the error wraps ErrServerExited and the process's error. If the
+// server isn't ready within timeout, the error wraps ErrServerTimeout. Either
+// error reports the last lines of output.
+func WaitForServer(ctx context.Context, addr string, timeout time.Duration, exited <-chan error, output *ServerOutput) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	client := &http.Client{
+		Timeout: time.Second,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(readyPollInterval)
+	defer ticker.Stop()
+
+	for {
+		// NOTE: The context isn't used to stop waiting, as the process is killed
+		// when it's done, so the process's error is received from exited.
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, "http://"+addr+"/", nil)
+		if err != nil {
+			return err
+		}
+		if resp, err := client.Do(req); err == nil {
+			_ = resp.Body.Close()
+			return nil
+		}
+
+		select {
+		case err := <-exited:
+			inner := ErrServerExited
+			if err != nil {
+				inner = fmt.Errorf("%w: %w", ErrServerExited, err)
+			}
+			return serverError(inner, output, "Fix the error reported by the local server, then run the command again.")
+		case <-deadline.C:
+			return serverError(
+				fmt.Errorf("%w on %s after %s", ErrServerTimeout, addr, timeout),
+				output,
+				"Check the output of the local server, or wait longer with --wait-timeout.",
+			)
+		case <-ticker.C:
+		}
+	}
+}
+
+// serverError reports the last lines of the local server's output along
+// with the remediation.
+func serverError(inner error, output *ServerOutput, remediation string) error {
+	var lines []string
+	if output != nil {
+		lines = output.Lines()
+	}
+	if len(lines) > 0 {
+		remediation = fmt.Sprintf("The last lines of output from the local server were:\n\n    %s\n\n%s", strings.Join(lines, "\n    "), remediation)
+	}
+	return fsterr.RemediationError{
+		Inner:       inner,
+		Remediation: remediation,
+	}
+}
@@ -0,0 +1,133 @@
+package compute
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fastly/cli/pkg/env"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/filesystem"
+)
+
+// ReproducibleWarning describes the nondeterminism that --reproducible can't
+// remove, because it comes from the language toolchain rather than packaging.
+const ReproducibleWarning = "--reproducible makes the package archive deterministic, but the Wasm binary is only byte-identical if the toolchain produces identical output from identical sources. Pin the compiler and dependency versions, and avoid build scripts that embed absolute paths, timestamps or random values."
+
+// SourceDateEpoch returns the modification time to record for every file in a
+// reproducible package. It's read from the SOURCE_DATE_EPOCH environment
+// variable (seconds since the Unix epoch) and defaults to the Unix epoch.
+func SourceDateEpoch(value string) (time.Time, error) {
+	if value == "" {
+		return time.Unix(0, 0).UTC(), nil
+	}
+	secs, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || secs < 0 {
+		return time.Time{}, fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid %s value '%s'", env.SourceDateEpoch, value),
+			Remediation: fmt.Sprintf("Set %s to a non-negative number of seconds since the Unix epoch (e.g. the commit time: `git log -1 --format=%%ct`), or unset it.", env.SourceDateEpoch),
+		}
+	}
+	return time.Unix(secs, 0).UTC(), nil
+}
+
+// CreateReproducibleArchive writes the contents of dir to a .tar.gz file at
+// destination, with dir's base name as the top-level directory.
+//
+// The archive is byte-identical for identical file contents: entries are
+// written in lexical order, every entry has the same modification time (mtime),
+// ownership is root with no user/group names, permissions are normalised to
+// 0755 (directories and executables) or 0644, and the gzip header carries no
+// name or timestamp.
+func CreateReproducibleArchive(dir, destination string, mtime time.Time) (err error) {
+	if err := filesystem.MakeDirectoryIfNotExists(filepath.Dir(destination)); err != nil {
+		return err
+	}
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	// Disabling as the destination is determined by the CLI.
+	// #nosec
+	f, err := os.Create(destination)
+	if err != nil {
+		return fmt.Errorf("error creating package archive: %w", err)
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	root := filepath.Base(dir)
+
+	// NOTE: WalkDir visits entries in lexical order, which makes the order of
+	// the archive entries independent of the filesystem.
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		name := path.Join(root, filepath.ToSlash(rel))
+
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr := &tar.Header{
+			ModTime: mtime,
+			Mode:    0o644,
+			Name:    name,
+		}
+		switch {
+		case d.IsDir():
+			hdr.Typeflag = tar.TypeDir
+			hdr.Name += "/"
+			hdr.Mode = 0o755
+		case fi.Mode().IsRegular():
+			hdr.Typeflag = tar.TypeReg
+			hdr.Size = fi.Size()
+			if fi.Mode()&0o111 != 0 {
+				hdr.Mode = 0o755
+			}
+		default:
+			return fmt.Errorf("unsupported file type for '%s': reproducible packages can only contain regular files", p)
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil
+		}
+
+		// #nosec G304 (CWE-22) the path is within the package directory.
+		src, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error creating package archive: %w", err)
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("error creating package archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("error creating package archive: %w", err)
+	}
+	return nil
+}
@@ -2,11 +2,13 @@ package compute
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"net"
+	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
@@ -24,7 +26,11 @@ import (
 
 	"github.com/fastly/cli/pkg/argparser"
 	"github.com/fastly/cli/pkg/check"
+	"github.com/fastly/cli/pkg/commands/compute/traffic"
+	"github.com/fastly/cli/pkg/commands/compute/viceroy"
+	"github.com/fastly/cli/pkg/env"
 	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/events"
 	fstexec "github.com/fastly/cli/pkg/exec"
 	"github.com/fastly/cli/pkg/filesystem"
 	"github.com/fastly/cli/pkg/github"
@@ -51,6 +57,8 @@ type ServeCommand struct {
 	metadataFilterEnvVars argparser.OptionalString
 	metadataShow          argparser.OptionalBool
 	packageName           argparser.OptionalString
+	reproducible          argparser.OptionalBool
+	skipToolchainCheck    argparser.OptionalBool
 	timeout               argparser.OptionalInt
 
 	// Serve public fields (public for testing purposes)
@@ -59,14 +67,20 @@ type ServeCommand struct {
 	ViceroyVersioner        github.AssetVersioner
 
 	// Serve private fields
+	accessLog       bool
+	accessLogFile   string
 	addr            string
 	debug           bool
 	env             argparser.OptionalString
+	envVars         []string
 	file            string
 	profileGuest    bool
 	profileGuestDir argparser.OptionalString
 	projectDir      string
+	replay          string
+	runHooks        bool
 	skipBuild       bool
+	waitTimeout     time.Duration
 	watch           bool
 	watchDir        argparser.OptionalString
 }
@@ -79,10 +93,13 @@ func NewServeCommand(parent argparser.Registerer, g *global.Data, build *BuildCo
 	c.ViceroyVersioner = g.Versioners.Viceroy
 	c.CmdClause = parent.Command("serve", "Build and run a Compute package locally")
 
+	c.CmdClause.Flag("access-log", "Log the method, path, status, duration and cache state of each request (requests are proxied to the local server)").BoolVar(&c.accessLog)
+	c.CmdClause.Flag("access-log-file", "Append each request and response to a JSON lines file, for use with --replay (implies --access-log)").StringVar(&c.accessLogFile)
 	c.CmdClause.Flag("addr", "The IPv4 address and port to listen on").Default("127.0.0.1:7676").StringVar(&c.addr)
 	c.CmdClause.Flag("debug", "Run the server in Debug Adapter mode").Hidden().BoolVar(&c.debug)
 	c.CmdClause.Flag("dir", "Project directory to build (default: current directory)").Short('C').Action(c.dir.Set).StringVar(&c.dir.Value)
 	c.CmdClause.Flag("env", "The manifest environment config to use (e.g. 'stage' will attempt to read 'fastly.stage.toml')").Action(c.env.Set).StringVar(&c.env.Value)
+	c.CmdClause.Flag("env-var", "Set a value the guest reads from the 'env' config store, as KEY=VALUE or KEY to pass through an environment variable (repeatable, overrides [local_server.env])").StringsVar(&c.envVars)
 	c.CmdClause.Flag("file", "The Wasm file to run").Default("bin/main.wasm").StringVar(&c.file)
 	c.CmdClause.Flag("include-source", "Include source code in built package").Action(c.includeSrc.Set).BoolVar(&c.includeSrc.Value)
 	c.CmdClause.Flag("language", "Language type").Action(c.lang.Set).StringVar(&c.lang.Value)
@@ -90,18 +107,29 @@ func NewServeCommand(parent argparser.Registerer, g *global.Data, build *BuildCo
 	c.CmdClause.Flag("metadata-filter-envvars", "Redact specified environment variables from [scripts.env_vars] using comma-separated list").Action(c.metadataFilterEnvVars.Set).StringVar(&c.metadataFilterEnvVars.Value)
 	c.CmdClause.Flag("metadata-show", "Inspect the Wasm binary metadata").Action(c.metadataShow.Set).BoolVar(&c.metadataShow.Value)
 	c.CmdClause.Flag("package-name", "Package name").Action(c.packageName.Set).StringVar(&c.packageName.Value)
+	c.CmdClause.Flag("replay", "Re-send the requests captured in a --access-log-file once the local server starts, and compare the responses").StringVar(&c.replay)
+	c.CmdClause.Flag("reproducible", "Build a byte-identical package from identical sources (timestamps come from SOURCE_DATE_EPOCH, and environment-dependent metadata is omitted)").Action(c.reproducible.Set).BoolVar(&c.reproducible.Value)
 	c.CmdClause.Flag("profile-guest", "Profile the Wasm guest under Viceroy (requires Viceroy 0.9.1 or higher). View profiles at https://profiler.firefox.com/.").BoolVar(&c.profileGuest)
 	c.CmdClause.Flag("profile-guest-dir", "The directory where the per-request profiles are saved to. Defaults to guest-profiles.").Action(c.profileGuestDir.Set).StringVar(&c.profileGuestDir.Value)
-	c.CmdClause.Flag("skip-build", "Skip the build step").BoolVar(&c.skipBuild)
+	c.CmdClause.Flag("run-hooks", "Run the [scripts] pre_build and post_build hooks when the build step is skipped").BoolVar(&c.runHooks)
+	c.CmdClause.Flag("skip-build", "Skip the build step (and the pre_build and post_build hooks, unless --run-hooks is set)").BoolVar(&c.skipBuild)
+	c.CmdClause.Flag("skip-toolchain-check", "Skip checking the installed toolchain version meets the requirements (Go only)").Action(c.skipToolchainCheck.Set).BoolVar(&c.skipToolchainCheck.Value)
 	c.CmdClause.Flag("timeout", "Timeout, in seconds, for the build compilation step").Action(c.timeout.Set).IntVar(&c.timeout.Value)
 	c.CmdClause.Flag("viceroy-check", "Force the CLI to check for a newer version of the Viceroy binary").BoolVar(&c.ForceCheckViceroyLatest)
 	c.CmdClause.Flag("viceroy-path", "The path to a user installed version of the Viceroy binary").StringVar(&c.ViceroyBinPath)
+	c.CmdClause.Flag("wait-timeout", "How long to wait for the local server to accept connections").Default("30s").DurationVar(&c.waitTimeout)
 	c.CmdClause.Flag("watch", "Watch for file changes, then rebuild project and restart local server").BoolVar(&c.watch)
 	c.CmdClause.Flag("watch-dir", "The directory to watch files from (can be relative or absolute). Defaults to current directory.").Action(c.watchDir.Set).StringVar(&c.watchDir.Value)
 
 	return &c
 }
 
+// Interruptible implements the argparser.Interruptible interface. The local
+// server runs until interrupted.
+func (c *ServeCommand) Interruptible() bool {
+	return true
+}
+
 // Exec implements the command interface.
 func (c *ServeCommand) Exec(in io.Reader, out io.Writer) (err error) {
 	if c.skipBuild && c.watch {
@@ -115,6 +143,30 @@ func (c *ServeCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		}
 	}
 
+	// NOTE: The capture is read before building so a bad --replay file is
+	// reported immediately rather than after the local server has started.
+	var replay []traffic.Entry
+	if c.replay != "" {
+		replay, err = readCapture(c.replay)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+	}
+	// NOTE: The --env-var flags are validated before building, so a mistake
+	// isn't reported only once the build has finished.
+	if _, err := ResolveGuestEnv(manifest.LocalServer{}, c.envVars, os.LookupEnv); err != nil {
+		return err
+	}
+	// The working directory changes to --dir before the access log is opened.
+	if c.accessLogFile != "" {
+		c.accessLogFile, err = filepath.Abs(c.accessLogFile)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("failed to resolve access log file path: %w", err)
+		}
+	}
+
 	manifestFilename := EnvironmentManifest(c.env.Value)
 	if c.env.Value != "" {
 		if c.Globals.Verbose() {
@@ -175,6 +227,12 @@ func (c *ServeCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		if c.Globals.Verbose() {
 			text.Info(out, "Fastly manifest set to: %s\n\n", manifestPath)
 		}
+		if c.runHooks {
+			c.configureBuild()
+			if err := c.build.RunHooks(in, out); err != nil {
+				return err
+			}
+		}
 	}
 
 	bin, err := c.GetViceroy(spinner, out, manifestPath)
@@ -199,11 +257,48 @@ func (c *ServeCommand) Exec(in io.Reader, out io.Writer) (err error) {
 		text.Break(out)
 	}
 
-	var restart bool
+	upstream := c.addr
+	if c.accessLog || c.accessLogFile != "" {
+		var stop func()
+		upstream, stop, err = c.startAccessLog(out)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+		defer stop()
+	}
+	if len(replay) > 0 {
+		go c.replayRequests(out, replay)
+	}
+
+	c.Globals.Events.Emit(events.ServeStarted, events.Fields{"addr": c.addr, "watch": c.watch})
+
+	var (
+		restart      bool
+		restartedAt  time.Time
+		serverConfig string
+	)
 	for {
+		// The local server config is generated on each (re)start, so changes to
+		// [local_server.env] made while watching are applied. A mistake made while
+		// watching is reported, and the previous config used.
+		config, configErr := c.localServerConfig(manifestPath, out)
+		switch {
+		case configErr == nil:
+			serverConfig = config
+		case !restart:
+			c.Globals.ErrLog.Add(configErr)
+			return configErr
+		default:
+			c.Globals.ErrLog.Add(configErr)
+			fsterr.Deduce(configErr).Print(color.Error)
+		}
+
 		err = local(localOpts{
 			addr:            c.addr,
 			bin:             bin,
+			config:          serverConfig,
+			ctx:             c.Globals.Context,
 			debug:           c.debug,
 			errLog:          c.Globals.ErrLog,
 			file:            c.file,
@@ -212,20 +307,26 @@ func (c *ServeCommand) Exec(in io.Reader, out io.Writer) (err error) {
 			profileGuest:    c.profileGuest,
 			profileGuestDir: c.profileGuestDir,
 			restarted:       restart,
+			restartedAt:     restartedAt,
+			upstream:        upstream,
 			verbose:         c.Globals.Verbose(),
+			waitTimeout:     c.waitTimeout,
 			watch:           c.watch,
 			watchDir:        c.watchDir,
 		})
 		if err != nil {
 			if err != fsterr.ErrViceroyRestart {
 				if err == fsterr.ErrSignalInterrupt || err == fsterr.ErrSignalKilled {
+					c.Globals.Events.Emit(events.ServeStopped, nil)
 					text.Info(out, "\nLocal server stopped")
 					return nil
 				}
+				c.Globals.Events.Emit(events.ServeStopped, events.Fields{"error": err.Error()})
 				return err
 			}
 
 			// Before restarting Viceroy we should rebuild.
+			restartedAt = time.Now()
 			text.Break(out)
 			err = c.Build(in, out)
 			if err != nil {
@@ -235,12 +336,132 @@ func (c *ServeCommand) Exec(in io.Reader, out io.Writer) (err error) {
 				fsterr.Deduce(err).Print(color.Error)
 			}
 			restart = true
+			c.Globals.Events.Emit(events.ServeRestarted, events.Fields{"addr": c.addr})
+		}
+	}
+}
+
+// startAccessLog listens on c.addr with a proxy that logs (and optionally
+// captures) each request before forwarding it to Viceroy, which is moved to
+// the returned internal address. The stop function closes the proxy.
+func (c *ServeCommand) startAccessLog(out io.Writer) (upstream string, stop func(), err error) {
+	// NOTE: The port is released so Viceroy can bind to it.
+	internal, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to reserve a port for the local server: %w", err)
+	}
+	upstream = internal.Addr().String()
+	_ = internal.Close()
+
+	proxy := traffic.NewProxy(&url.URL{Scheme: "http", Host: upstream}, out)
+	var capture *os.File
+	if c.accessLogFile != "" {
+		capture, err = os.OpenFile(c.accessLogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600) // #nosec G304 (CWE-22)
+		if err != nil {
+			return "", nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("failed to open access log file '%s': %w", c.accessLogFile, err),
+				Remediation: fsterr.HostRemediation,
+			}
+		}
+		proxy.Capture = capture
+	}
+
+	l, err := net.Listen("tcp", c.addr)
+	if err != nil {
+		if capture != nil {
+			_ = capture.Close()
+		}
+		return "", nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("failed to listen on %s: %w", c.addr, err),
+			Remediation: "Check no other process is listening on the address, or set a different address with --addr.",
+		}
+	}
+	srv := &http.Server{
+		Handler:           proxy,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		_ = srv.Serve(l)
+	}()
+
+	return upstream, func() {
+		_ = srv.Close()
+		if capture != nil {
+			_ = capture.Close()
 		}
+	}, nil
+}
+
+// replayRequests waits for the local server to accept connections, then
+// re-sends the captured requests and reports how the responses differ.
+func (c *ServeCommand) replayRequests(out io.Writer, entries []traffic.Entry) {
+	ctx := c.Globals.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !waitForListener(ctx, c.addr, time.Minute) {
+		text.Warning(out, "Skipping --replay: the local server didn't start listening on %s", c.addr)
+		return
+	}
+	client := &http.Client{
+		Timeout: time.Minute,
+		// The captured redirect responses are compared, not followed.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
 	}
+	results := traffic.Replay(ctx, client, &url.URL{Scheme: "http", Host: c.addr}, entries)
+	text.Break(out)
+	traffic.PrintResults(out, results)
+}
+
+// readCapture reads the requests captured by --access-log-file.
+func readCapture(path string) ([]traffic.Entry, error) {
+	f, err := os.Open(path) // #nosec G304 (CWE-22)
+	if err != nil {
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("failed to open replay file '%s': %w", path, err),
+			Remediation: fsterr.HostRemediation,
+		}
+	}
+	defer f.Close() // #nosec G307
+	entries, err := traffic.ReadCapture(f)
+	if err != nil {
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("failed to read replay file '%s': %w", path, err),
+			Remediation: "The --replay file should be a capture written by `fastly compute serve --access-log-file`.",
+		}
+	}
+	return entries, nil
+}
+
+// waitForListener reports whether addr accepts a TCP connection before the
+// timeout elapses.
+func waitForListener(ctx context.Context, addr string, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err == nil {
+			_ = conn.Close()
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+	return false
 }
 
 // Build constructs and executes the build logic.
 func (c *ServeCommand) Build(in io.Reader, out io.Writer) error {
+	c.configureBuild()
+	return c.build.Exec(in, out)
+}
+
+// configureBuild sets the flags of the BuildCommand from the ServeCommand.
+func (c *ServeCommand) configureBuild() {
 	// Reset the fields on the BuildCommand based on ServeCommand values.
 	if c.dir.WasSet {
 		c.build.Flags.Dir = c.dir.Value
@@ -257,6 +478,12 @@ func (c *ServeCommand) Build(in io.Reader, out io.Writer) error {
 	if c.packageName.WasSet {
 		c.build.Flags.PackageName = c.packageName.Value
 	}
+	if c.reproducible.WasSet {
+		c.build.Flags.Reproducible = c.reproducible.Value
+	}
+	if c.skipToolchainCheck.WasSet {
+		c.build.Flags.SkipToolchainCheck = c.skipToolchainCheck.Value
+	}
 	if c.timeout.WasSet {
 		c.build.Flags.Timeout = c.timeout.Value
 	}
@@ -272,7 +499,6 @@ func (c *ServeCommand) Build(in io.Reader, out io.Writer) error {
 	if c.projectDir != "" {
 		c.build.SkipChangeDir = true // we've already changed directory
 	}
-	return c.build.Exec(in, out)
 }
 
 // setBackendsWithDefaultOverrideHostIfMissing sets an override_host for any
@@ -298,9 +524,14 @@ func (c *ServeCommand) setBackendsWithDefaultOverrideHostIfMissing(out io.Writer
 
 // GetViceroy returns the path to the installed binary.
 //
-// If Viceroy is installed we either update it or pin it to the version defined
-// in the fastly.toml [viceroy.viceroy_version]. Otherwise, if not installed, we
-// install it in the same directory as the application configuration data.
+// Viceroy is pinned to a version by the fastly.toml [local_server.viceroy_version]
+// or, if the project doesn't pin a version, by the CLI config [viceroy.pin].
+// If the pinned version was installed with `compute viceroy install` then that
+// binary is used. Otherwise if Viceroy is installed we either update it or pin
+// it to the pinned version, and if not installed, we install it in the same
+// directory as the application configuration data.
+//
+// In offline mode nothing is downloaded, so an installed version is used.
 //
 // In the case of a network failure we fallback to the latest installed version of the
 // Viceroy binary as long as one is installed and has the correct permissions.
@@ -324,6 +555,32 @@ func (c *ServeCommand) GetViceroy(spinner text.Spinner, out io.Writer, manifestP
 		return filepath.Abs(path)
 	}
 
+	// A pinned version installed with `compute viceroy install` is used as is.
+	pin, source := viceroy.ResolvePin(c.ViceroyVersioner.RequestedVersion(), c.Globals.Config.Viceroy.Pin)
+	pinnedIn := fmt.Sprintf("the %s file", manifestPath)
+	if source == viceroy.PinSourceConfig {
+		pinnedIn = "the CLI config (`fastly config`)"
+	}
+	if pin != "" {
+		if _, err := semver.Parse(pin); err != nil {
+			remediation := fmt.Sprintf("Ensure the %s `viceroy_version` value '%s' (under the [local_server] section) is a valid semver (https://semver.org/), e.g. `0.1.0`)", manifestPath, pin)
+			if source == viceroy.PinSourceConfig {
+				remediation = fmt.Sprintf("Ensure the CLI config `pin` value '%s' (under the [viceroy] section) is a valid semver (https://semver.org/), e.g. `0.1.0`), or run `fastly compute viceroy pin --global --unset`", pin)
+			}
+			return "", fsterr.RemediationError{
+				Inner:       fmt.Errorf("failed to parse configured version as a semver: %w", err),
+				Remediation: remediation,
+			}
+		}
+		bin = github.VersionPath(c.ViceroyVersioner.BinaryName(), pin)
+		if _, err := os.Stat(bin); err == nil {
+			if c.Globals.Verbose() {
+				text.Info(out, "Using Viceroy %s (pinned in %s): %s\n\n", pin, pinnedIn, bin)
+			}
+			return bin, nil
+		}
+	}
+
 	bin = filepath.Join(github.InstallDir, c.ViceroyVersioner.BinaryName())
 
 	// NOTE: When checking if Viceroy is installed we don't use
@@ -355,21 +612,18 @@ func (c *ServeCommand) GetViceroy(spinner text.Spinner, out io.Writer, manifestP
 		installedVersion = segs[1]
 	}
 
+	if c.Globals.Offline() {
+		return c.offlineViceroy(out, bin, installedVersion, pin, pinnedIn)
+	}
+
 	// If the user hasn't explicitly set a Viceroy version, then we'll use
 	// whatever the latest version is.
 	versionToInstall := "latest"
-	if v := c.ViceroyVersioner.RequestedVersion(); v != "" {
-		versionToInstall = v
-
-		if _, err := semver.Parse(versionToInstall); err != nil {
-			return bin, fsterr.RemediationError{
-				Inner:       fmt.Errorf("failed to parse configured version as a semver: %w", err),
-				Remediation: fmt.Sprintf("Ensure the %s `viceroy_version` value '%s' (under the [local_server] section) is a valid semver (https://semver.org/), e.g. `0.1.0`)", manifestPath, versionToInstall),
-			}
-		}
+	if pin != "" {
+		versionToInstall = pin
 	}
 
-	err = c.InstallViceroy(installedVersion, versionToInstall, manifestPath, bin, spinner)
+	err = c.InstallViceroy(installedVersion, versionToInstall, pinnedIn, bin, spinner)
 	if err != nil {
 		c.Globals.ErrLog.Add(err)
 		return bin, err
@@ -383,6 +637,41 @@ func (c *ServeCommand) GetViceroy(spinner text.Spinner, out io.Writer, manifestP
 	return bin, nil
 }
 
+// offlineViceroy returns an installed Viceroy binary without checking for (or
+// downloading) a newer version, as network requests are avoided in offline
+// mode. Unless Viceroy is pinned, the newest version installed with `compute
+// viceroy install` is used if the auto-updated binary isn't installed.
+func (c *ServeCommand) offlineViceroy(out io.Writer, bin, installedVersion, pin, pinnedIn string) (string, error) {
+	if installedVersion != "" && (pin == "" || installedVersion == pin) {
+		if c.Globals.Verbose() {
+			text.Info(out, "Using the installed Viceroy %s, as offline mode is on: %s\n\n", installedVersion, bin)
+		}
+		return bin, nil
+	}
+
+	if pin != "" {
+		return bin, fsterr.RemediationError{
+			Inner:       fmt.Errorf("the Viceroy version %s pinned in %s isn't installed, and can't be downloaded in offline mode", pin, pinnedIn),
+			Remediation: fmt.Sprintf("Install it while online with `fastly compute viceroy install %s`, or unset the %s environment variable so that `compute serve` downloads it.", pin, env.Offline),
+		}
+	}
+
+	versions, err := github.InstalledVersions(c.ViceroyVersioner.BinaryName())
+	if err != nil {
+		return bin, err
+	}
+	if len(versions) == 0 {
+		return bin, fsterr.RemediationError{
+			Inner:       errors.New("no Viceroy version is installed, and one can't be downloaded in offline mode"),
+			Remediation: fmt.Sprintf("Install it while online with `fastly compute viceroy install latest`, or unset the %s environment variable so that `compute serve` downloads it.", env.Offline),
+		}
+	}
+	if c.Globals.Verbose() {
+		text.Info(out, "Using the installed Viceroy %s, as offline mode is on: %s\n\n", versions[0].Version, versions[0].Path)
+	}
+	return versions[0].Path, nil
+}
+
 // checkViceroyEnvVar indicates if the CLI should use a Viceroy binary exposed
 // on the user's $PATH.
 func checkViceroyEnvVar(value string) bool {
@@ -401,7 +690,7 @@ func checkViceroyEnvVar(value string) bool {
 // 2. If so, check the latest release matches the installed version.
 // 3. If not latest, check the installed version matches the expected version.
 func (c *ServeCommand) InstallViceroy(
-	installedVersion, versionToInstall, manifestPath, bin string,
+	installedVersion, versionToInstall, pinnedIn, bin string,
 	spinner text.Spinner,
 ) error {
 	var (
@@ -429,12 +718,12 @@ func (c *ServeCommand) InstallViceroy(
 	case versionToInstall != "latest":
 		if installedVersion == versionToInstall {
 			if c.Globals.Verbose() {
-				text.Info(c.Globals.Output, "Viceroy is already installed, and the installed version matches the required version (%s) in the %s file.\n\n", versionToInstall, manifestPath)
+				text.Info(c.Globals.Output, "Viceroy is already installed, and the installed version matches the required version (%s) in %s.\n\n", versionToInstall, pinnedIn)
 			}
 			return nil
 		}
 		if c.Globals.Verbose() {
-			text.Info(c.Globals.Output, "Viceroy is already installed, but the installed version (%s) doesn't match the required version (%s) specified in the %s file.\n\n", installedVersion, versionToInstall, manifestPath)
+			text.Info(c.Globals.Output, "Viceroy is already installed, but the installed version (%s) doesn't match the required version (%s) specified in %s.\n\n", installedVersion, versionToInstall, pinnedIn)
 		}
 
 		err = spinner.Start()
@@ -538,6 +827,8 @@ func (c *ServeCommand) InstallViceroy(
 type localOpts struct {
 	addr            string
 	bin             string
+	config          string
+	ctx             context.Context
 	debug           bool
 	errLog          fsterr.LogInterface
 	file            string
@@ -546,7 +837,10 @@ type localOpts struct {
 	profileGuest    bool
 	profileGuestDir argparser.OptionalString
 	restarted       bool
+	restartedAt     time.Time
+	upstream        string
 	verbose         bool
+	waitTimeout     time.Duration
 	watch           bool
 	watchDir        argparser.OptionalString
 }
@@ -556,7 +850,13 @@ func local(opts localOpts) error {
 	// NOTE: Viceroy no longer displays errors unless in verbose mode.
 	// This can cause confusion for customers: https://github.com/fastly/cli/issues/913
 	// So regardless of CLI --verbose flag we'll always set verbose for Viceroy.
-	args := []string{"-v", "-C", opts.manifestPath, "--addr", opts.addr, opts.file}
+	// NOTE: Viceroy listens on an internal address when requests are proxied
+	// for --access-log.
+	upstream := opts.upstream
+	if upstream == "" {
+		upstream = opts.addr
+	}
+	args := []string{"-v", "-C", opts.config, "--addr", upstream, opts.file}
 
 	if opts.debug {
 		args = append(args, "--debug")
@@ -578,6 +878,9 @@ func local(opts localOpts) error {
 			text.Break(opts.out)
 		}
 		text.Output(opts.out, "%s: %s", text.BoldYellow("Manifest"), opts.manifestPath)
+		if opts.config != opts.manifestPath {
+			text.Output(opts.out, "%s: %s", text.BoldYellow("Local server config"), opts.config)
+		}
 		text.Output(opts.out, "%s: %s", text.BoldYellow("Wasm binary"), opts.file)
 		text.Output(opts.out, "%s: %s", text.BoldYellow("Viceroy binary"), opts.bin)
 
@@ -590,15 +893,17 @@ func local(opts localOpts) error {
 		if output, err := c.Output(); err == nil {
 			text.Output(opts.out, "%s: %s", text.BoldYellow("Viceroy version"), string(output))
 		}
-		text.Info(opts.out, "Listening on http://%s", opts.addr)
 		if opts.watch {
 			text.Break(opts.out)
 		}
 	}
 
+	output := NewServerOutput(serverOutputLines)
 	s := &fstexec.Streaming{
 		Args:        args,
+		Capture:     output,
 		Command:     opts.bin,
+		Context:     opts.ctx,
 		Env:         os.Environ(),
 		ForceOutput: true,
 		Output:      opts.out,
@@ -628,9 +933,10 @@ func local(opts localOpts) error {
 	// 2. Explicit signal (SIGINT, SIGTERM etc).
 	// 3. Irrecoverable error (i.e. error watching files).
 	//
-	// In the case of a signal (e.g. user presses Ctrl-c) the listener logic
-	// inside of (*fstexec.Streaming).MonitorSignals() will call
-	// (*fstexec.Streaming).Signal(signal os.Signal) to kill the process.
+	// In the case of a signal (e.g. user presses Ctrl-c) the context is
+	// cancelled and the listener logic inside of
+	// (*fstexec.Streaming).MonitorSignals() will call
+	// (*fstexec.Streaming).Signal(signal os.Signal) to kill the process group.
 	//
 	// In the case of a file modification the viceroy executable needs to first
 	// be killed (handled by the watchFiles() function) and then we can stop the
@@ -661,7 +967,34 @@ func local(opts localOpts) error {
 		// no-op: allow logic to flow to starting up Viceroy executable.
 	}
 
-	if err := s.Exec(); err != nil {
+	// NOTE: The URL is only printed once the server accepts connections, so
+	// it can be requested straight away. Once the server is ready the result
+	// of the process is waited for, and if it exits first the error reports
+	// its output.
+	exited := make(chan error, 1)
+	go func() {
+		exited <- s.Exec()
+	}()
+	err := WaitForServer(opts.ctx, upstream, opts.waitTimeout, exited, output)
+	switch {
+	case err == nil:
+		if opts.restarted {
+			text.Info(opts.out, "Reloaded in %dms", time.Since(opts.restartedAt).Milliseconds())
+		} else {
+			text.Info(opts.out, "Listening on http://%s", opts.addr)
+		}
+		err = <-exited
+	case errors.Is(err, ErrServerTimeout):
+		s.SignalCh <- syscall.SIGTERM
+		<-exited
+		opts.errLog.Add(err)
+		return err
+	}
+
+	if err != nil {
+		if opts.ctx != nil && opts.ctx.Err() != nil {
+			return fsterr.ErrSignalInterrupt
+		}
 		if !strings.Contains(err.Error(), "signal: ") {
 			opts.errLog.Add(err)
 		}
@@ -715,7 +1048,7 @@ func watchFiles(root string, gi *ignore.GitIgnore, verbose bool, s *fstexec.Stre
 		// existing file has been 'created' or 'renamed' when from a user's
 		// perspective the file already exists and was only modified.
 		text.Break(out)
-		text.Output(out, "%s Restarting local server (%s)", text.BoldGreen("✓"), modifiedFile)
+		text.Output(out, "%s Restarting local server (%s)", text.SuccessStyle("✓"), modifiedFile)
 
 		// NOTE: We force closing the watcher by pushing true into a done channel.
 		// We do this because if we didn't, then we'd get an error after one
@@ -818,7 +1151,8 @@ func watchFiles(root string, gi *ignore.GitIgnore, verbose bool, s *fstexec.Stre
 
 // ignoreFiles returns the specific ignore rules being respected.
 //
-// NOTE: We also ignore the .git directory.
+// NOTE: We also ignore the .git directory, and the StateDir (where the local
+// server config is generated on each restart).
 func ignoreFiles(watchDir argparser.OptionalString) *ignore.GitIgnore {
 	var patterns []string
 
@@ -837,7 +1171,7 @@ func ignoreFiles(watchDir argparser.OptionalString) *ignore.GitIgnore {
 		patterns = append(patterns, readIgnoreFile(file)...)
 	}
 
-	patterns = append(patterns, ".git/")
+	patterns = append(patterns, ".git/", StateDir+"/")
 
 	return ignore.CompileIgnoreLines(patterns...)
 }
@@ -870,7 +1204,7 @@ func watchFile(path string, watcher *fsnotify.Watcher, verbose bool, out io.Writ
 
 	err = watcher.Add(absolute)
 	if err != nil {
-		text.Output(out, "%s %s", text.BoldRed("✗"), absolute)
+		text.Output(out, "%s %s", text.ErrorStyle("✗"), absolute)
 	} else if verbose {
 		text.Output(out, "%s", absolute)
 	}
@@ -2,11 +2,24 @@ package compute_test
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
 	"testing"
+	"time"
 
+	toml "github.com/pelletier/go-toml"
+
+	"github.com/fastly/cli/pkg/app"
 	"github.com/fastly/cli/pkg/argparser"
 	"github.com/fastly/cli/pkg/commands/compute"
 	"github.com/fastly/cli/pkg/config"
@@ -16,6 +29,7 @@ import (
 	"github.com/fastly/cli/pkg/mock"
 	"github.com/fastly/cli/pkg/testutil"
 	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/cli/pkg/threadsafe"
 )
 
 // TestGetViceroy validates that Viceroy is installed to the appropriate
@@ -121,3 +135,431 @@ func TestGetViceroy(t *testing.T) {
 		t.Fatalf("binary was not moved to the install directory: %s", err)
 	}
 }
+
+// TestGetViceroyPin validates the pinned Viceroy version is resolved from the
+// manifest, then the CLI config, and that in offline mode an installed version
+// is used (or an error is returned) without any network requests.
+func TestGetViceroyPin(t *testing.T) {
+	for _, testcase := range []struct {
+		name            string
+		manifestPin     string
+		configPin       string
+		installed       []string
+		offline         bool
+		wantVersion     string
+		wantError       string
+		wantRemediation string
+	}{
+		{
+			name:        "manifest pin takes precedence",
+			manifestPin: "0.9.2",
+			configPin:   "0.9.3",
+			installed:   []string{"0.9.2", "0.9.3"},
+			wantVersion: "0.9.2",
+		},
+		{
+			name:        "config pin",
+			configPin:   "0.9.3",
+			installed:   []string{"0.9.2", "0.9.3"},
+			wantVersion: "0.9.3",
+		},
+		{
+			name:        "offline with the pinned version installed",
+			configPin:   "0.9.2",
+			installed:   []string{"0.9.2", "0.9.3"},
+			offline:     true,
+			wantVersion: "0.9.2",
+		},
+		{
+			name:            "offline without the pinned version installed",
+			manifestPin:     "0.9.5",
+			installed:       []string{"0.9.3"},
+			offline:         true,
+			wantError:       "the Viceroy version 0.9.5 pinned in the fastly.toml file isn't installed, and can't be downloaded in offline mode",
+			wantRemediation: "fastly compute viceroy install 0.9.5",
+		},
+		{
+			name:        "offline and unpinned",
+			installed:   []string{"0.9.2", "0.9.3"},
+			offline:     true,
+			wantVersion: "0.9.3",
+		},
+		{
+			name:            "offline and not installed",
+			offline:         true,
+			wantError:       "no Viceroy version is installed",
+			wantRemediation: "fastly compute viceroy install latest",
+		},
+		{
+			name:            "invalid config pin",
+			configPin:       "latest",
+			wantError:       "failed to parse configured version as a semver",
+			wantRemediation: "[viceroy] section",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			installDir := github.InstallDir
+			github.InstallDir = t.TempDir()
+			defer func() {
+				github.InstallDir = installDir
+			}()
+			for _, v := range testcase.installed {
+				path := github.VersionPath("viceroy", v)
+				if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, nil, 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			server := testutil.NewReleaseServer(t, "viceroy", "0.9.4")
+			var out bytes.Buffer
+			spinner, err := text.NewSpinner(&out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			serveCommand := &compute.ServeCommand{
+				Base: argparser.Base{
+					Globals: &global.Data{
+						Config: config.File{Viceroy: config.Versioner{Pin: testcase.configPin}},
+						ErrLog: fsterr.MockLog{},
+					},
+				},
+				ViceroyVersioner: github.New(github.Opts{
+					HTTPClient: server.Client(),
+					Org:        "fastly",
+					Repo:       "viceroy",
+					Binary:     "viceroy",
+					Version:    testcase.manifestPin,
+				}),
+			}
+			if testcase.offline {
+				serveCommand.Globals.Env.Offline = "true"
+			}
+
+			bin, err := serveCommand.GetViceroy(spinner, &out, "fastly.toml")
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertRemediationErrorContains(t, err, testcase.wantRemediation)
+			if testcase.wantVersion != "" {
+				testutil.AssertString(t, github.VersionPath("viceroy", testcase.wantVersion), bin)
+			}
+			testutil.AssertEqual(t, 0, len(server.Paths()))
+		})
+	}
+}
+
+// fakeViceroy writes a shell script, used as the Viceroy binary, that runs
+// script (with Viceroy's arguments).
+func fakeViceroy(t *testing.T, script string) (rootdir, bin string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	rootdir = t.TempDir()
+	bin = filepath.Join(rootdir, "viceroy")
+	if err := os.WriteFile(bin, []byte("#!/bin/sh\n"+script+"\n"), 0o700); err != nil /* #nosec G306 */ {
+		t.Fatal(err)
+	}
+	manifest := "manifest_version = 2\nname = \"test\"\nlanguage = \"rust\"\n"
+	if err := os.WriteFile(filepath.Join(rootdir, "fastly.toml"), []byte(manifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return rootdir, bin
+}
+
+// freeAddr returns a local address that nothing is listening on.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	_ = l.Close()
+	return addr
+}
+
+// runServe runs `compute serve` with the fake Viceroy, until ctx is done.
+func runServe(ctx context.Context, rootdir, bin, addr string, stdout io.Writer) error {
+	args := testutil.Args("compute serve --metadata-disable --skip-build --dir " + rootdir + " --viceroy-path " + bin + " --addr " + addr)
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		opts := testutil.MockGlobalData(args, stdout)
+		opts.Context = ctx
+		opts.Versioners.Viceroy = mock.AssetVersioner{}
+		return opts, nil
+	}
+	return app.Run(args, nil)
+}
+
+// TestServeWaitsUntilReady validates the URL is only printed once the local
+// server accepts connections. The fake Viceroy doesn't listen, so a test
+// server starts listening on its address after a delay.
+func TestServeWaitsUntilReady(t *testing.T) {
+	rootdir, bin := fakeViceroy(t, "sleep 30 & wait")
+	addr := freeAddr(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var stdout threadsafe.Buffer
+	result := make(chan error, 1)
+	go func() {
+		result <- runServe(ctx, rootdir, bin, addr, &stdout)
+	}()
+
+	// The fake Viceroy is started a second after the command runs.
+	time.Sleep(1500 * time.Millisecond)
+	testutil.AssertStringDoesntContain(t, stdout.String(), "Listening on")
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Listener = l
+	srv.Start()
+	defer srv.Close()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(stdout.String(), "Listening on http://"+addr) {
+		if time.Now().After(deadline) {
+			t.Fatalf("the URL wasn't printed once the server was ready: %s", stdout.String())
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	cancel()
+	testutil.AssertNoError(t, <-result)
+	testutil.AssertStringContains(t, stdout.String(), "Local server stopped")
+}
+
+// TestServeExitsBeforeReady validates the output of a local server that exits
+// before it's ready is reported.
+func TestServeExitsBeforeReady(t *testing.T) {
+	rootdir, bin := fakeViceroy(t, "echo 'starting' >&2\necho 'Error: failed to instantiate main.wasm' >&2\nexit 1")
+
+	var stdout threadsafe.Buffer
+	err := runServe(context.Background(), rootdir, bin, freeAddr(t), &stdout)
+	if !errors.Is(err, compute.ErrServerExited) {
+		t.Fatalf("want ErrServerExited, have %v", err)
+	}
+	var re fsterr.RemediationError
+	if !errors.As(err, &re) {
+		t.Fatalf("want a RemediationError, have %T", err)
+	}
+	testutil.AssertStringContains(t, re.Remediation, "The last lines of output from the local server were:\n\n    starting\n    Error: failed to instantiate main.wasm\n\n")
+	testutil.AssertStringDoesntContain(t, stdout.String(), "Listening on")
+}
+
+func TestWaitForServer(t *testing.T) {
+	t.Run("delayed", func(t *testing.T) {
+		addr := freeAddr(t)
+		go func() {
+			time.Sleep(300 * time.Millisecond)
+			l, err := net.Listen("tcp", addr)
+			if err != nil {
+				return
+			}
+			_ = http.Serve(l, http.NotFoundHandler()) // #nosec G114
+		}()
+		start := time.Now()
+		err := compute.WaitForServer(context.Background(), addr, 5*time.Second, make(chan error), nil)
+		testutil.AssertNoError(t, err)
+		if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+			t.Fatalf("the server was ready after %s, before it was listening", elapsed)
+		}
+	})
+
+	t.Run("exited", func(t *testing.T) {
+		output := compute.NewServerOutput(2)
+		fmt.Fprint(output, "one\ntwo\n\nthree\nfour")
+		exited := make(chan error, 1)
+		exited <- errors.New("exit status 1")
+
+		err := compute.WaitForServer(context.Background(), freeAddr(t), 5*time.Second, exited, output)
+		if !errors.Is(err, compute.ErrServerExited) {
+			t.Fatalf("want ErrServerExited, have %v", err)
+		}
+		testutil.AssertErrorContains(t, err, "exit status 1")
+		var re fsterr.RemediationError
+		if !errors.As(err, &re) {
+			t.Fatalf("want a RemediationError, have %T", err)
+		}
+		testutil.AssertStringContains(t, re.Remediation, "\n\n    three\n    four\n\n")
+		testutil.AssertStringDoesntContain(t, re.Remediation, "two")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		err := compute.WaitForServer(context.Background(), freeAddr(t), 300*time.Millisecond, make(chan error), nil)
+		if !errors.Is(err, compute.ErrServerTimeout) {
+			t.Fatalf("want ErrServerTimeout, have %v", err)
+		}
+	})
+}
+
+// TestGenerateLocalServerConfig validates the guest environment is added to
+// the manifest as a config store, with --env-var taking precedence over
+// [local_server.env].
+func TestGenerateLocalServerConfig(t *testing.T) {
+	lookupEnv := func(k string) (string, bool) {
+		if k == "HOST_TOKEN" {
+			return "from-host", true
+		}
+		return "", false
+	}
+
+	scenarios := []struct {
+		name            string
+		manifest        string
+		flags           []string
+		wantContents    map[string]any
+		wantSecret      []string
+		wantSources     map[string]string
+		wantNoConfig    bool
+		wantError       string
+		wantRemediation string
+	}{
+		{
+			name: "no guest environment",
+			manifest: `manifest_version = 3
+[local_server.backends.origin]
+url = "http://127.0.0.1:8080"
+`,
+			wantNoConfig: true,
+		},
+		{
+			name: "manifest",
+			manifest: `manifest_version = 3
+[local_server.env]
+API_URL = "http://localhost:8080"
+API_KEY = "abc123"
+[local_server]
+secret_env = ["API_KEY"]
+`,
+			wantContents: map[string]any{"API_URL": "http://localhost:8080", "API_KEY": "abc123"},
+			wantSecret:   []string{"API_KEY"},
+			wantSources: map[string]string{
+				"API_URL": compute.GuestEnvSourceManifest,
+				"API_KEY": compute.GuestEnvSourceManifest,
+			},
+		},
+		{
+			name: "flag overrides manifest",
+			manifest: `manifest_version = 3
+[local_server]
+secret_env = ["API_KEY"]
+[local_server.env]
+API_URL = "http://localhost:8080"
+API_KEY = "abc123"
+`,
+			flags:        []string{"API_KEY=override", "DEBUG=1", "TOKEN=a=b"},
+			wantContents: map[string]any{"API_URL": "http://localhost:8080", "API_KEY": "override", "DEBUG": "1", "TOKEN": "a=b"},
+			// The override of a secret value is still secret.
+			wantSecret: []string{"API_KEY"},
+			wantSources: map[string]string{
+				"API_URL": compute.GuestEnvSourceManifest,
+				"API_KEY": compute.GuestEnvSourceFlag,
+				"DEBUG":   compute.GuestEnvSourceFlag,
+				"TOKEN":   compute.GuestEnvSourceFlag,
+			},
+		},
+		{
+			name:         "later flag overrides earlier flag",
+			manifest:     "manifest_version = 3\n",
+			flags:        []string{"DEBUG=1", "DEBUG=2"},
+			wantContents: map[string]any{"DEBUG": "2"},
+			wantSources:  map[string]string{"DEBUG": compute.GuestEnvSourceFlag},
+		},
+		{
+			name:         "passthrough",
+			manifest:     "manifest_version = 3\n[local_server.env]\nHOST_TOKEN = \"from-manifest\"\n",
+			flags:        []string{"HOST_TOKEN"},
+			wantContents: map[string]any{"HOST_TOKEN": "from-host"},
+			wantSecret:   []string{"HOST_TOKEN"},
+			wantSources:  map[string]string{"HOST_TOKEN": compute.GuestEnvSourceEnvironment},
+		},
+		{
+			name:            "passthrough of unset variable",
+			manifest:        "manifest_version = 3\n",
+			flags:           []string{"MISSING"},
+			wantError:       "invalid --env-var 'MISSING': the environment variable isn't set",
+			wantRemediation: "--env-var MISSING=VALUE",
+		},
+		{
+			name:      "missing key",
+			manifest:  "manifest_version = 3\n",
+			flags:     []string{"=value"},
+			wantError: "invalid --env-var '=value': missing key",
+		},
+		{
+			name:            "value isn't a string",
+			manifest:        "manifest_version = 3\n[local_server.env]\nPORT = 8080\n",
+			wantError:       "failed to parse the [local_server] section",
+			wantRemediation: "are strings",
+		},
+		{
+			name: "config store already defined",
+			manifest: `manifest_version = 3
+[local_server.config_stores.env]
+format = "inline-toml"
+[local_server.config_stores.env.contents]
+A = "b"
+`,
+			flags:           []string{"DEBUG=1"},
+			wantError:       "the config store 'env' is already defined",
+			wantRemediation: "[local_server.config_stores.env]",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			manifestPath := filepath.Join(t.TempDir(), "fastly.toml")
+			if err := os.WriteFile(manifestPath, []byte(testcase.manifest), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			config, env, err := compute.GenerateLocalServerConfig(manifestPath, testcase.flags, lookupEnv)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertRemediationErrorContains(t, err, testcase.wantRemediation)
+			if err != nil {
+				return
+			}
+			if testcase.wantNoConfig {
+				if config != nil {
+					t.Fatalf("want no config, have:\n%s", config)
+				}
+				return
+			}
+
+			tree, err := toml.LoadBytes(config)
+			if err != nil {
+				t.Fatalf("generated config isn't valid TOML: %v\n%s", err, config)
+			}
+			testutil.AssertEqual(t, "inline-toml", tree.Get("local_server.config_stores.env.format"))
+			contents, ok := tree.Get("local_server.config_stores.env.contents").(*toml.Tree)
+			if !ok {
+				t.Fatalf("want [local_server.config_stores.env.contents], have:\n%s", config)
+			}
+			testutil.AssertEqual(t, testcase.wantContents, contents.ToMap())
+			// Viceroy doesn't know the guest environment's settings.
+			testutil.AssertBool(t, false, tree.Has("local_server.env"))
+			testutil.AssertBool(t, false, tree.Has("local_server.secret_env"))
+			// The rest of the manifest is kept.
+			testutil.AssertEqual(t, int64(3), tree.Get("manifest_version"))
+
+			var secret []string
+			sources := make(map[string]string)
+			for k, v := range env {
+				sources[k] = v.Source
+				if v.Secret {
+					secret = append(secret, k)
+					testutil.AssertString(t, "REDACTED", v.String())
+				}
+			}
+			sort.Strings(secret)
+			testutil.AssertEqual(t, testcase.wantSecret, secret)
+			testutil.AssertEqual(t, testcase.wantSources, sources)
+		})
+	}
+}
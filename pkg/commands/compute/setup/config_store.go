@@ -21,6 +21,7 @@ type ConfigStores struct {
 	APIClient      api.Interface
 	AcceptDefaults bool
 	NonInteractive bool
+	Report         *Report
 	Spinner        text.Spinner
 	ServiceID      string
 	ServiceVersion int
@@ -39,6 +40,7 @@ type ConfigStore struct {
 	Items             []ConfigStoreItem
 	LinkExistingStore bool
 	ExistingStoreID   string
+	Optional          bool
 }
 
 // ConfigStoreItem represents the configuration parameters for creating config
@@ -52,6 +54,10 @@ type ConfigStoreItem struct {
 func (o *ConfigStores) Configure() error {
 	existingStores, err := o.APIClient.ListConfigStores(&fastly.ListConfigStoresInput{})
 	if err != nil {
+		if allOptional(o.Setup, func(s *manifest.SetupConfigStore) bool { return s.Optional }) {
+			skipAll(o.Report, o.Stdout, TypeConfigStore, o.Setup, err)
+			return nil
+		}
 		return err
 	}
 
@@ -69,7 +75,7 @@ func (o *ConfigStores) Configure() error {
 				} else {
 					text.Warning(o.Stdout, "\nA Config Store called '%s' already exists. If you use this store, then this implies that any keys defined in your setup configuration will either be newly created or will update an existing one. To avoid updating an existing key, then stop the command now and edit the setup configuration before re-running the deployment process\n\n", name)
 					prompt := text.Prompt("Use a different store name (or leave empty to use the existing store): ")
-					value, err := text.Input(o.Stdout, prompt, o.Stdin)
+					value, err := text.Input(o.Stdout, prompt, o.Stdin, "--accept-defaults")
 					if err != nil {
 						return fmt.Errorf("error reading prompt input: %w", err)
 					}
@@ -111,7 +117,7 @@ func (o *ConfigStores) Configure() error {
 				}
 				text.Break(o.Stdout)
 
-				value, err = text.Input(o.Stdout, prompt, o.Stdin)
+				value, err = text.Input(o.Stdout, prompt, o.Stdin, "--accept-defaults")
 				if err != nil {
 					return fmt.Errorf("error reading prompt input: %w", err)
 				}
@@ -132,6 +138,7 @@ func (o *ConfigStores) Configure() error {
 			Items:             items,
 			LinkExistingStore: linkExistingStore,
 			ExistingStoreID:   existingStoreID,
+			Optional:          settings.Optional,
 		})
 	}
 
@@ -148,75 +155,86 @@ func (o *ConfigStores) Create() error {
 	}
 
 	for _, configStore := range o.required {
-		var (
-			err error
-			cs  *fastly.ConfigStore
-		)
+		err := o.create(configStore)
+		if err := o.Report.Record(o.Stdout, TypeConfigStore, configStore.Name, configStore.Optional, err); err != nil {
+			return err
+		}
+	}
 
-		if configStore.LinkExistingStore {
-			err = o.Spinner.Process(fmt.Sprintf("Retrieving existing Config Store '%s'", configStore.Name), func(_ *text.SpinnerWrapper) error {
-				cs, err = o.APIClient.GetConfigStore(&fastly.GetConfigStoreInput{
-					StoreID: configStore.ExistingStoreID,
-				})
-				if err != nil {
-					return fmt.Errorf("failed to get existing store '%s': %w", configStore.Name, err)
-				}
-				return nil
+	return nil
+}
+
+// create creates the config store (or retrieves the existing store) along
+// with its items, and links it to the service.
+func (o *ConfigStores) create(configStore ConfigStore) error {
+	var (
+		err error
+		cs  *fastly.ConfigStore
+	)
+
+	if configStore.LinkExistingStore {
+		err = o.Spinner.Process(fmt.Sprintf("Retrieving existing Config Store '%s'", configStore.Name), func(_ *text.SpinnerWrapper) error {
+			cs, err = o.APIClient.GetConfigStore(&fastly.GetConfigStoreInput{
+				StoreID: configStore.ExistingStoreID,
 			})
 			if err != nil {
-				return err
+				return fmt.Errorf("failed to get existing store '%s': %w", configStore.Name, err)
 			}
-		} else {
-			err = o.Spinner.Process(fmt.Sprintf("Creating config store '%s'", configStore.Name), func(_ *text.SpinnerWrapper) error {
-				cs, err = o.APIClient.CreateConfigStore(&fastly.CreateConfigStoreInput{
-					Name: configStore.Name,
-				})
-				if err != nil {
-					return fmt.Errorf("error creating config store: %w", err)
-				}
-				return nil
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		err = o.Spinner.Process(fmt.Sprintf("Creating config store '%s'", configStore.Name), func(_ *text.SpinnerWrapper) error {
+			cs, err = o.APIClient.CreateConfigStore(&fastly.CreateConfigStoreInput{
+				Name: configStore.Name,
 			})
 			if err != nil {
-				return err
+				return fmt.Errorf("error creating config store: %w", err)
 			}
+			return nil
+		})
+		if err != nil {
+			return err
 		}
+	}
 
-		if len(configStore.Items) > 0 {
-			for _, item := range configStore.Items {
-				err = o.Spinner.Process(fmt.Sprintf("Creating config store item '%s'", item.Key), func(_ *text.SpinnerWrapper) error {
-					_, err = o.APIClient.UpdateConfigStoreItem(&fastly.UpdateConfigStoreItemInput{
-						Upsert:  true, // Use upsert to avoid conflicts when reusing a starter kit.
-						StoreID: cs.StoreID,
-						Key:     item.Key,
-						Value:   item.Value,
-					})
-					if err != nil {
-						return fmt.Errorf("error creating config store item: %w", err)
-					}
-					return nil
+	if len(configStore.Items) > 0 {
+		for _, item := range configStore.Items {
+			err = o.Spinner.Process(fmt.Sprintf("Creating config store item '%s'", item.Key), func(_ *text.SpinnerWrapper) error {
+				_, err = o.APIClient.UpdateConfigStoreItem(&fastly.UpdateConfigStoreItemInput{
+					Upsert:  true, // Use upsert to avoid conflicts when reusing a starter kit.
+					StoreID: cs.StoreID,
+					Key:     item.Key,
+					Value:   item.Value,
 				})
 				if err != nil {
-					return err
+					return fmt.Errorf("error creating config store item: %w", err)
 				}
-			}
-		}
-
-		// IMPORTANT: We need to link the config store to the Compute Service.
-		err = o.Spinner.Process(fmt.Sprintf("Creating resource link between service and config store '%s'...", cs.Name), func(_ *text.SpinnerWrapper) error {
-			_, err = o.APIClient.CreateResource(&fastly.CreateResourceInput{
-				ServiceID:      o.ServiceID,
-				ServiceVersion: o.ServiceVersion,
-				Name:           fastly.ToPointer(cs.Name),
-				ResourceID:     fastly.ToPointer(cs.StoreID),
+				return nil
 			})
 			if err != nil {
-				return fmt.Errorf("error creating resource link between the service '%s' and the config store '%s': %w", o.ServiceID, configStore.Name, err)
+				return err
 			}
-			return nil
+		}
+	}
+
+	// IMPORTANT: We need to link the config store to the Compute Service.
+	err = o.Spinner.Process(fmt.Sprintf("Creating resource link between service and config store '%s'...", cs.Name), func(_ *text.SpinnerWrapper) error {
+		_, err = o.APIClient.CreateResource(&fastly.CreateResourceInput{
+			ServiceID:      o.ServiceID,
+			ServiceVersion: o.ServiceVersion,
+			Name:           fastly.ToPointer(cs.Name),
+			ResourceID:     fastly.ToPointer(cs.StoreID),
 		})
 		if err != nil {
-			return err
+			return fmt.Errorf("error creating resource link between the service '%s' and the config store '%s': %w", o.ServiceID, configStore.Name, err)
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	return nil
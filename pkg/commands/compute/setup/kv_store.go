@@ -23,6 +23,7 @@ type KVStores struct {
 	APIClient      api.Interface
 	AcceptDefaults bool
 	NonInteractive bool
+	Report         *Report
 	Spinner        text.Spinner
 	ServiceID      string
 	ServiceVersion int
@@ -41,6 +42,7 @@ type KVStore struct {
 	Items             []KVStoreItem
 	LinkExistingStore bool
 	ExistingStoreID   string
+	Optional          bool
 }
 
 // KVStoreItem represents the configuration parameters for creating KV Store
@@ -63,6 +65,11 @@ func (o *KVStores) Configure() error {
 			Cursor: cursor,
 		})
 		if err != nil {
+			// NOTE: The API fails here if the account isn't entitled to KV Stores.
+			if allOptional(o.Setup, func(s *manifest.SetupKVStore) bool { return s.Optional }) {
+				skipAll(o.Report, o.Stdout, TypeKVStore, o.Setup, err)
+				return nil
+			}
 			return err
 		}
 
@@ -94,7 +101,7 @@ func (o *KVStores) Configure() error {
 				} else {
 					text.Warning(o.Stdout, "\nA KV Store called '%s' already exists\n\n", name)
 					prompt := text.Prompt("Use a different store name (or leave empty to use the existing store): ")
-					value, err := text.Input(o.Stdout, prompt, o.Stdin)
+					value, err := text.Input(o.Stdout, prompt, o.Stdin, "--accept-defaults")
 					if err != nil {
 						return fmt.Errorf("error reading prompt input: %w", err)
 					}
@@ -147,7 +154,7 @@ func (o *KVStores) Configure() error {
 				}
 				text.Break(o.Stdout)
 
-				value, err = text.Input(o.Stdout, prompt, o.Stdin)
+				value, err = text.Input(o.Stdout, prompt, o.Stdin, "--accept-defaults")
 				if err != nil {
 					return fmt.Errorf("error reading prompt input: %w", err)
 				}
@@ -191,6 +198,7 @@ func (o *KVStores) Configure() error {
 			Items:             items,
 			LinkExistingStore: linkExistingStore,
 			ExistingStoreID:   existingStoreID,
+			Optional:          settings.Optional,
 		})
 	}
 
@@ -207,79 +215,90 @@ func (o *KVStores) Create() error {
 	}
 
 	for _, kvStore := range o.required {
-		var (
-			err   error
-			store *fastly.KVStore
-		)
+		err := o.create(kvStore)
+		if err := o.Report.Record(o.Stdout, TypeKVStore, kvStore.Name, kvStore.Optional, err); err != nil {
+			return err
+		}
+	}
 
-		if kvStore.LinkExistingStore {
-			err = o.Spinner.Process(fmt.Sprintf("Retrieving existing KV Store '%s'", kvStore.Name), func(_ *text.SpinnerWrapper) error {
-				store, err = o.APIClient.GetKVStore(&fastly.GetKVStoreInput{
-					StoreID: kvStore.ExistingStoreID,
-				})
-				if err != nil {
-					return fmt.Errorf("failed to get existing store '%s': %w", kvStore.Name, err)
-				}
-				return nil
+	return nil
+}
+
+// create creates the KV Store (or retrieves the existing store) along with its
+// keys, and links it to the service.
+func (o *KVStores) create(kvStore KVStore) error {
+	var (
+		err   error
+		store *fastly.KVStore
+	)
+
+	if kvStore.LinkExistingStore {
+		err = o.Spinner.Process(fmt.Sprintf("Retrieving existing KV Store '%s'", kvStore.Name), func(_ *text.SpinnerWrapper) error {
+			store, err = o.APIClient.GetKVStore(&fastly.GetKVStoreInput{
+				StoreID: kvStore.ExistingStoreID,
 			})
 			if err != nil {
-				return err
+				return fmt.Errorf("failed to get existing store '%s': %w", kvStore.Name, err)
 			}
-		} else {
-			err = o.Spinner.Process(fmt.Sprintf("Creating KV Store '%s'", kvStore.Name), func(_ *text.SpinnerWrapper) error {
-				store, err = o.APIClient.CreateKVStore(&fastly.CreateKVStoreInput{
-					Name: kvStore.Name,
-				})
-				if err != nil {
-					return fmt.Errorf("error creating KV Store: %w", err)
-				}
-				return nil
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		err = o.Spinner.Process(fmt.Sprintf("Creating KV Store '%s'", kvStore.Name), func(_ *text.SpinnerWrapper) error {
+			store, err = o.APIClient.CreateKVStore(&fastly.CreateKVStoreInput{
+				Name: kvStore.Name,
 			})
 			if err != nil {
-				return err
+				return fmt.Errorf("error creating KV Store: %w", err)
 			}
+			return nil
+		})
+		if err != nil {
+			return err
 		}
+	}
 
-		if len(kvStore.Items) > 0 {
-			for _, item := range kvStore.Items {
-				err = o.Spinner.Process(fmt.Sprintf("Creating KV Store key '%s'...", item.Key), func(_ *text.SpinnerWrapper) error {
-					input := &fastly.InsertKVStoreKeyInput{
-						StoreID: store.StoreID,
-						Key:     item.Key,
-					}
-					if item.Body != nil {
-						input.Body = item.Body
-					} else {
-						input.Value = item.Value
-					}
-					err = o.APIClient.InsertKVStoreKey(input)
-					if err != nil {
-						return fmt.Errorf("error creating KV Store key: %w", err)
-					}
-					return nil
-				})
+	if len(kvStore.Items) > 0 {
+		for _, item := range kvStore.Items {
+			err = o.Spinner.Process(fmt.Sprintf("Creating KV Store key '%s'...", item.Key), func(_ *text.SpinnerWrapper) error {
+				input := &fastly.InsertKVStoreKeyInput{
+					StoreID: store.StoreID,
+					Key:     item.Key,
+				}
+				if item.Body != nil {
+					input.Body = item.Body
+				} else {
+					input.Value = item.Value
+				}
+				err = o.APIClient.InsertKVStoreKey(input)
 				if err != nil {
-					return err
+					return fmt.Errorf("error creating KV Store key: %w", err)
 				}
-			}
-		}
-
-		// IMPORTANT: We need to link the KV Store to the Compute Service.
-		err = o.Spinner.Process(fmt.Sprintf("Creating resource link between service and KV Store '%s'...", kvStore.Name), func(_ *text.SpinnerWrapper) error {
-			_, err = o.APIClient.CreateResource(&fastly.CreateResourceInput{
-				ServiceID:      o.ServiceID,
-				ServiceVersion: o.ServiceVersion,
-				Name:           fastly.ToPointer(store.Name),
-				ResourceID:     fastly.ToPointer(store.StoreID),
+				return nil
 			})
 			if err != nil {
-				return fmt.Errorf("error creating resource link between the service '%s' and the KV Store '%s': %w", o.ServiceID, store.Name, err)
+				return err
 			}
-			return nil
+		}
+	}
+
+	// IMPORTANT: We need to link the KV Store to the Compute Service.
+	err = o.Spinner.Process(fmt.Sprintf("Creating resource link between service and KV Store '%s'...", kvStore.Name), func(_ *text.SpinnerWrapper) error {
+		_, err = o.APIClient.CreateResource(&fastly.CreateResourceInput{
+			ServiceID:      o.ServiceID,
+			ServiceVersion: o.ServiceVersion,
+			Name:           fastly.ToPointer(store.Name),
+			ResourceID:     fastly.ToPointer(store.StoreID),
 		})
 		if err != nil {
-			return err
+			return fmt.Errorf("error creating resource link between the service '%s' and the KV Store '%s': %w", o.ServiceID, store.Name, err)
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	return nil
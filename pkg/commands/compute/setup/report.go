@@ -0,0 +1,113 @@
+package setup
+
+import (
+	"io"
+	"sort"
+
+	"github.com/fastly/cli/pkg/text"
+)
+
+// Resource types recorded in a Report.
+const (
+	TypeConfigStore = "config_store"
+	TypeKVStore     = "kv_store"
+	TypeSecretStore = "secret_store"
+)
+
+// Resource statuses recorded in a Report.
+const (
+	StatusCreated = "created"
+	StatusSkipped = "skipped"
+	StatusFailed  = "failed"
+)
+
+// typeLabels are the resource types as displayed to the user.
+var typeLabels = map[string]string{
+	TypeConfigStore: "config store",
+	TypeKVStore:     "KV Store",
+	TypeSecretStore: "Secret Store",
+}
+
+// OptionalRemediation explains how to add an optional resource that was
+// skipped during deploy.
+const OptionalRemediation = "The service was deployed without it, which is fine if the Compute program doesn't depend on it. Check the account has access to the feature (e.g. `fastly products`), then create the resource and link it to the service with `fastly resource-link create`."
+
+// ResourceResult is the outcome of creating a [setup] resource.
+type ResourceResult struct {
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report records the outcome of each [setup] resource processed during
+// deploy. A nil *Report records nothing.
+type Report struct {
+	Resources []ResourceResult
+}
+
+// Record adds the outcome of creating a resource (err is nil on success).
+//
+// A failed optional resource is reported to the user as a warning and nil is
+// returned so the deploy can continue. Otherwise err is returned unchanged.
+func (r *Report) Record(out io.Writer, resourceType, name string, optional bool, err error) error {
+	result := ResourceResult{
+		Type:   resourceType,
+		Name:   name,
+		Status: StatusCreated,
+	}
+	switch {
+	case err == nil:
+	case optional:
+		result.Status = StatusSkipped
+		result.Error = err.Error()
+		text.Warning(out, "\nSkipping optional %s '%s': %s\n\n%s\n\n", typeLabels[resourceType], name, err, OptionalRemediation)
+	default:
+		result.Status = StatusFailed
+		result.Error = err.Error()
+	}
+	if r != nil {
+		r.Resources = append(r.Resources, result)
+	}
+	if result.Status == StatusSkipped {
+		return nil
+	}
+	return err
+}
+
+// Skipped returns the names of the skipped resources.
+func (r *Report) Skipped() []string {
+	if r == nil {
+		return nil
+	}
+	var names []string
+	for _, res := range r.Resources {
+		if res.Status == StatusSkipped {
+			names = append(names, res.Name)
+		}
+	}
+	return names
+}
+
+// allOptional indicates if every resource in a [setup] block is optional.
+func allOptional[T any](setup map[string]T, optional func(T) bool) bool {
+	for _, s := range setup {
+		if !optional(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// skipAll records every resource in a [setup] block as skipped because of err.
+// The resources are recorded in name order.
+func skipAll[T any](r *Report, out io.Writer, resourceType string, setup map[string]T, err error) {
+	names := make([]string, 0, len(setup))
+	for name := range setup {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		_ = r.Record(out, resourceType, name, true, err)
+	}
+}
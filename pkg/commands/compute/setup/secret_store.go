@@ -22,6 +22,7 @@ type SecretStores struct {
 	APIClient      api.Interface
 	AcceptDefaults bool
 	NonInteractive bool
+	Report         *Report
 	Spinner        text.Spinner
 	ServiceID      string
 	ServiceVersion int
@@ -40,6 +41,7 @@ type SecretStore struct {
 	Entries           []SecretStoreEntry
 	LinkExistingStore bool
 	ExistingStoreID   string
+	Optional          bool
 }
 
 // SecretStoreEntry represents the configuration parameters for creating
@@ -67,6 +69,10 @@ func (s *SecretStores) Configure() error {
 			Cursor: cursor,
 		})
 		if err != nil {
+			if allOptional(s.Setup, func(store *manifest.SetupSecretStore) bool { return store.Optional }) {
+				skipAll(s.Report, s.Stdout, TypeSecretStore, s.Setup, err)
+				return nil
+			}
 			return err
 		}
 		if o != nil {
@@ -93,7 +99,7 @@ func (s *SecretStores) Configure() error {
 				} else {
 					text.Warning(s.Stdout, "\nA Secret Store called '%s' already exists\n\n", name)
 					prompt := text.Prompt("Use a different store name (or leave empty to use the existing store): ")
-					value, err := text.Input(s.Stdout, prompt, s.Stdin)
+					value, err := text.Input(s.Stdout, prompt, s.Stdin, "--accept-defaults")
 					if err != nil {
 						return fmt.Errorf("error reading prompt input: %w", err)
 					}
@@ -119,6 +125,7 @@ func (s *SecretStores) Configure() error {
 			Entries:           make([]SecretStoreEntry, 0, len(settings.Entries)),
 			LinkExistingStore: linkExistingStore,
 			ExistingStoreID:   existingStoreID,
+			Optional:          settings.Optional,
 		}
 
 		for key, entry := range settings.Entries {
@@ -135,7 +142,7 @@ func (s *SecretStores) Configure() error {
 				text.Break(s.Stdout)
 
 				prompt := text.Prompt("Value: ")
-				value, err = text.InputSecure(s.Stdout, prompt, s.Stdin)
+				value, err = text.InputSecure(s.Stdout, prompt, s.Stdin, "--accept-defaults")
 				if err != nil {
 					return fmt.Errorf("error reading prompt input: %w", err)
 				}
@@ -167,67 +174,60 @@ func (s *SecretStores) Create() error {
 	}
 
 	for _, secretStore := range s.required {
-		var (
-			err   error
-			store *fastly.SecretStore
-		)
+		err := s.create(secretStore)
+		if err := s.Report.Record(s.Stdout, TypeSecretStore, secretStore.Name, secretStore.Optional, err); err != nil {
+			return err
+		}
+	}
 
-		if secretStore.LinkExistingStore {
-			err = s.Spinner.Process(fmt.Sprintf("Retrieving existing Secret Store '%s'", secretStore.Name), func(_ *text.SpinnerWrapper) error {
-				store, err = s.APIClient.GetSecretStore(&fastly.GetSecretStoreInput{
-					StoreID: secretStore.ExistingStoreID,
-				})
-				if err != nil {
-					return fmt.Errorf("failed to get existing store '%s': %w", secretStore.Name, err)
-				}
-				return nil
-			})
-			if err != nil {
-				return err
-			}
-		} else {
-			err = s.Spinner.Process(fmt.Sprintf("Creating Secret Store '%s'", secretStore.Name), func(_ *text.SpinnerWrapper) error {
-				store, err = s.APIClient.CreateSecretStore(&fastly.CreateSecretStoreInput{
-					Name: secretStore.Name,
-				})
-				if err != nil {
-					return fmt.Errorf("error creating Secret Store %q: %w", secretStore.Name, err)
-				}
-				return nil
+	return nil
+}
+
+// create creates the Secret Store (or retrieves the existing store) along
+// with its entries, and links it to the service.
+func (s *SecretStores) create(secretStore SecretStore) error {
+	var (
+		err   error
+		store *fastly.SecretStore
+	)
+
+	if secretStore.LinkExistingStore {
+		err = s.Spinner.Process(fmt.Sprintf("Retrieving existing Secret Store '%s'", secretStore.Name), func(_ *text.SpinnerWrapper) error {
+			store, err = s.APIClient.GetSecretStore(&fastly.GetSecretStoreInput{
+				StoreID: secretStore.ExistingStoreID,
 			})
 			if err != nil {
-				return err
+				return fmt.Errorf("failed to get existing store '%s': %w", secretStore.Name, err)
 			}
+			return nil
+		})
+		if err != nil {
+			return err
 		}
-
-		for _, entry := range secretStore.Entries {
-			err = s.Spinner.Process(fmt.Sprintf("Creating Secret Store entry '%s'...", entry.Name), func(_ *text.SpinnerWrapper) error {
-				_, err = s.APIClient.CreateSecret(&fastly.CreateSecretInput{
-					StoreID: store.StoreID,
-					Name:    entry.Name,
-					Secret:  []byte(entry.Secret),
-				})
-				if err != nil {
-					return fmt.Errorf("error creating Secret Store entry %q: %w", entry.Name, err)
-				}
-				return nil
+	} else {
+		err = s.Spinner.Process(fmt.Sprintf("Creating Secret Store '%s'", secretStore.Name), func(_ *text.SpinnerWrapper) error {
+			store, err = s.APIClient.CreateSecretStore(&fastly.CreateSecretStoreInput{
+				Name: secretStore.Name,
 			})
 			if err != nil {
-				return err
+				return fmt.Errorf("error creating Secret Store %q: %w", secretStore.Name, err)
 			}
+			return nil
+		})
+		if err != nil {
+			return err
 		}
+	}
 
-		err = s.Spinner.Process(fmt.Sprintf("Creating resource link between service and Secret Store '%s'...", store.Name), func(_ *text.SpinnerWrapper) error {
-			// We need to link the secret store to the C@E Service, otherwise the service
-			// will not have access to the store.
-			_, err = s.APIClient.CreateResource(&fastly.CreateResourceInput{
-				ServiceID:      s.ServiceID,
-				ServiceVersion: s.ServiceVersion,
-				Name:           fastly.ToPointer(store.Name),
-				ResourceID:     fastly.ToPointer(store.StoreID),
+	for _, entry := range secretStore.Entries {
+		err = s.Spinner.Process(fmt.Sprintf("Creating Secret Store entry '%s'...", entry.Name), func(_ *text.SpinnerWrapper) error {
+			_, err = s.APIClient.CreateSecret(&fastly.CreateSecretInput{
+				StoreID: store.StoreID,
+				Name:    entry.Name,
+				Secret:  []byte(entry.Secret),
 			})
 			if err != nil {
-				return fmt.Errorf("error creating resource link between the service %q and the Secret Store %q: %w", s.ServiceID, store.Name, err)
+				return fmt.Errorf("error creating Secret Store entry %q: %w", entry.Name, err)
 			}
 			return nil
 		})
@@ -236,5 +236,23 @@ func (s *SecretStores) Create() error {
 		}
 	}
 
+	err = s.Spinner.Process(fmt.Sprintf("Creating resource link between service and Secret Store '%s'...", store.Name), func(_ *text.SpinnerWrapper) error {
+		// We need to link the secret store to the C@E Service, otherwise the service
+		// will not have access to the store.
+		_, err = s.APIClient.CreateResource(&fastly.CreateResourceInput{
+			ServiceID:      s.ServiceID,
+			ServiceVersion: s.ServiceVersion,
+			Name:           fastly.ToPointer(store.Name),
+			ResourceID:     fastly.ToPointer(store.StoreID),
+		})
+		if err != nil {
+			return fmt.Errorf("error creating resource link between the service %q and the Secret Store %q: %w", s.ServiceID, store.Name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
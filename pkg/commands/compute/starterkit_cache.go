@@ -0,0 +1,154 @@
+package compute
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	cp "github.com/otiai10/copy"
+
+	"github.com/fastly/cli/pkg/github"
+)
+
+// StarterKitCacheDir is the directory fetched starter kits are cached in.
+//
+// NOTE: This is a package level variable so the test suite can use a
+// temporary directory.
+var StarterKitCacheDir = filepath.Join(github.InstallDir, "starter-kits")
+
+// commitSHARegEx matches an abbreviated or full git commit SHA.
+var commitSHARegEx = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// starterKitCache is the cache entry of a starter kit, identified by the URL
+// it was fetched from and the git branch or tag (if any).
+//
+// An entry is a directory holding the starter kit's files, alongside a JSON
+// file recording where they were fetched from and the hash of their content,
+// so an entry that was modified (or only partially written) is detected.
+type starterKitCache struct {
+	dir string
+	// immutable indicates the ref identifies content that can't change (a tag
+	// or commit SHA), so the cached starter kit can be reused when online.
+	//
+	// NOTE: A tag can technically be moved, but starter kit tags aren't.
+	immutable bool
+	ref       string
+	url       string
+}
+
+// starterKitCacheEntry is the metadata of a cached starter kit.
+type starterKitCacheEntry struct {
+	URL     string    `json:"url"`
+	Ref     string    `json:"ref,omitempty"`
+	Hash    string    `json:"sha256"`
+	Fetched time.Time `json:"fetched"`
+}
+
+// newStarterKitCache returns the cache entry for the starter kit fetched from
+// url at the branch or tag.
+func newStarterKitCache(url, branch, tag string) starterKitCache {
+	ref := branch
+	if tag != "" {
+		ref = tag
+	}
+	sum := sha256.Sum256([]byte(url + "\n" + ref))
+	return starterKitCache{
+		dir:       filepath.Join(StarterKitCacheDir, hex.EncodeToString(sum[:16])),
+		immutable: tag != "" || commitSHARegEx.MatchString(branch),
+		ref:       ref,
+		url:       url,
+	}
+}
+
+func (sc starterKitCache) metadataPath() string {
+	return sc.dir + ".json"
+}
+
+// lookup returns the metadata of the cached starter kit. An entry whose files
+// don't match the recorded hash is discarded, with corrupted reporting that
+// it was.
+func (sc starterKitCache) lookup() (entry starterKitCacheEntry, ok, corrupted bool) {
+	data, err := os.ReadFile(sc.metadataPath())
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			// A directory without metadata is an interrupted write.
+			_ = os.RemoveAll(sc.dir)
+		}
+		return entry, false, false
+	}
+	if err := json.Unmarshal(data, &entry); err == nil && entry.URL == sc.url && entry.Ref == sc.ref {
+		if hash, err := hashTree(sc.dir); err == nil && hash == entry.Hash {
+			return entry, true, false
+		}
+	}
+	sc.discard()
+	return starterKitCacheEntry{}, false, true
+}
+
+// store replaces the cached starter kit with the files in src.
+func (sc starterKitCache) store(src string, fetched time.Time) (starterKitCacheEntry, error) {
+	hash, err := hashTree(src)
+	if err != nil {
+		return starterKitCacheEntry{}, err
+	}
+	sc.discard()
+	if err := cp.Copy(src, sc.dir); err != nil {
+		sc.discard()
+		return starterKitCacheEntry{}, fmt.Errorf("error caching the starter kit: %w", err)
+	}
+	entry := starterKitCacheEntry{URL: sc.url, Ref: sc.ref, Hash: hash, Fetched: fetched.UTC()}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err == nil {
+		// NOTE: The metadata is written last, as an entry without it is ignored.
+		err = os.WriteFile(sc.metadataPath(), data, 0o600)
+	}
+	if err != nil {
+		sc.discard()
+		return starterKitCacheEntry{}, fmt.Errorf("error caching the starter kit: %w", err)
+	}
+	return entry, nil
+}
+
+// discard removes the cached starter kit.
+func (sc starterKitCache) discard() {
+	_ = os.Remove(sc.metadataPath())
+	_ = os.RemoveAll(sc.dir)
+}
+
+// hashTree returns the SHA-256 hash of the paths and content of the regular
+// files within dir.
+func hashTree(dir string) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(path) // #nosec G304 (CWE-22)
+		if err != nil {
+			return err
+		}
+		defer f.Close() // #nosec G307
+		fh := sha256.New()
+		if _, err := io.Copy(fh, f); err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%x\n", filepath.ToSlash(rel), fh.Sum(nil))
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("error hashing the starter kit: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
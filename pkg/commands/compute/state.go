@@ -0,0 +1,100 @@
+package compute
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// StateDir is the project directory where the CLI records state about the
+// project (e.g. the last deploy). It's local to each checkout, so init adds it
+// to the project's .gitignore.
+const StateDir = ".fastly"
+
+// DeployStateFilename is the file within StateDir recording the last deploy.
+const DeployStateFilename = "state.json"
+
+// DeployState records the last successful deploy from a project directory.
+type DeployState struct {
+	// DeployedAt is when the deploy completed.
+	DeployedAt time.Time `json:"deployed_at"`
+	// PackageHash is the hash of the files within the deployed package.
+	PackageHash string `json:"package_hash"`
+	// ServiceID is the service deployed to.
+	ServiceID string `json:"service_id"`
+	// ServiceVersion is the version activated by the deploy.
+	ServiceVersion int `json:"service_version"`
+}
+
+// DeployStatePath returns the path to the deploy state within a project
+// directory.
+func DeployStatePath(projectDir string) string {
+	return filepath.Join(projectDir, StateDir, DeployStateFilename)
+}
+
+// ReadDeployState reads the deploy state from a project directory. The error
+// satisfies errors.Is(err, os.ErrNotExist) if no deploy has been recorded.
+func ReadDeployState(projectDir string) (*DeployState, error) {
+	path := DeployStatePath(projectDir)
+	// G304 (CWE-22): Potential file inclusion via variable
+	// #nosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s DeployState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// WriteDeployState records the deploy state in a project directory.
+func WriteDeployState(projectDir string, s DeployState) error {
+	path := DeployStatePath(projectDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// IgnoreStateDir adds StateDir to the .gitignore file in a project directory,
+// creating the file if necessary. Nothing is changed if it's already ignored.
+func IgnoreStateDir(projectDir string) error {
+	path := filepath.Join(projectDir, ".gitignore")
+
+	// G304 (CWE-22): Potential file inclusion via variable
+	// #nosec
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		switch strings.TrimSpace(line) {
+		case StateDir, StateDir + "/", "/" + StateDir, "/" + StateDir + "/":
+			return nil
+		}
+	}
+
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += "/" + StateDir + "/\n"
+	// gosec flagged this:
+	// G306 (CWE-276): Expect WriteFile permissions to be 0600 or less
+	// Disabling as .gitignore is committed and needs to be readable.
+	// #nosec
+	return os.WriteFile(path, []byte(content), 0o644)
+}
@@ -0,0 +1,223 @@
+package compute
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/lookup"
+	"github.com/fastly/cli/pkg/text"
+	fsttime "github.com/fastly/cli/pkg/time"
+)
+
+// The sync states reported by StatusCommand.
+const (
+	// SyncInSync means the active version serves the last deployed package.
+	SyncInSync = "in-sync"
+	// SyncAhead means the last deploy is newer than the active version, e.g.
+	// the service was rolled back.
+	SyncAhead = "ahead"
+	// SyncDiverged means the active version serves a different package, e.g.
+	// it was deployed from another checkout.
+	SyncDiverged = "diverged"
+	// SyncUnknown means the state couldn't be determined (see Status.Note).
+	SyncUnknown = "unknown"
+)
+
+// shortHashLength is the number of characters of a package hash displayed.
+const shortHashLength = 12
+
+// StatusCommand compares the last deploy from the project directory against
+// the service's active version.
+type StatusCommand struct {
+	argparser.Base
+	argparser.JSONOutput
+
+	dir string
+}
+
+// NewStatusCommand returns a usable command registered under the parent.
+func NewStatusCommand(parent argparser.Registerer, g *global.Data) *StatusCommand {
+	var c StatusCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("status", "Compare the package last deployed from this project with the service's active version")
+	c.CmdClause.Flag("dir", "Project directory (default: current directory)").Short('C').StringVar(&c.dir)
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	return &c
+}
+
+// Status is the result of comparing the last deploy with the active version.
+type Status struct {
+	// Sync is one of SyncInSync, SyncAhead, SyncDiverged or SyncUnknown.
+	Sync string `json:"status"`
+	// Note explains why the status is unknown.
+	Note string `json:"note,omitempty"`
+	// LastDeploy is nil if no deploy was recorded.
+	LastDeploy *DeployState `json:"last_deploy"`
+	// ActiveVersion is nil if the service couldn't be fetched or has no
+	// active version.
+	ActiveVersion     *int   `json:"active_version"`
+	ActivePackageHash string `json:"active_package_hash,omitempty"`
+}
+
+// Exec invokes the application logic for the command.
+func (c *StatusCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	projectDir := c.dir
+	if projectDir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current working directory: %w", err)
+		}
+		projectDir = wd
+	}
+
+	status := c.status(projectDir)
+	if ok, err := c.WriteJSON(out, status); ok {
+		return err
+	}
+	printComputeStatus(out, status)
+	return nil
+}
+
+// status determines the sync state of the project.
+//
+// NOTE: A missing state file or API token isn't an error, as the command is
+// meant to answer "what did I last deploy?" in any checkout.
+func (c *StatusCommand) status(projectDir string) Status {
+	state, err := ReadDeployState(projectDir)
+	if err != nil {
+		note := fmt.Sprintf("no deploy has been recorded for this project (%s not found)", DeployStatePath(projectDir))
+		if !errors.Is(err, os.ErrNotExist) {
+			c.Globals.ErrLog.Add(err)
+			note = err.Error()
+		}
+		return Status{Sync: SyncUnknown, Note: note}
+	}
+
+	status := Status{Sync: SyncUnknown, LastDeploy: state}
+	client, err := c.apiClient()
+	if err != nil {
+		status.Note = err.Error()
+		return status
+	}
+
+	s, err := client.GetServiceDetails(&fastly.GetServiceInput{ServiceID: state.ServiceID})
+	if err != nil {
+		status.Note = c.remoteNote(err, state.ServiceID, "service")
+		return status
+	}
+	if s.ActiveVersion == nil || s.ActiveVersion.Number == nil {
+		status.Sync = SyncAhead
+		return status
+	}
+	active := *s.ActiveVersion.Number
+	status.ActiveVersion = &active
+
+	p, err := client.GetPackage(&fastly.GetPackageInput{
+		ServiceID:      state.ServiceID,
+		ServiceVersion: active,
+	})
+	if err != nil {
+		status.Note = c.remoteNote(err, state.ServiceID, fmt.Sprintf("the package of version %d", active))
+		return status
+	}
+	if p.Metadata != nil {
+		status.ActivePackageHash = fastly.ToValue(p.Metadata.FilesHash)
+	}
+
+	switch {
+	case status.ActivePackageHash == state.PackageHash:
+		status.Sync = SyncInSync
+	case active < state.ServiceVersion:
+		status.Sync = SyncAhead
+	default:
+		status.Sync = SyncDiverged
+	}
+	return status
+}
+
+// apiClient returns a client using the configured API token.
+//
+// NOTE: The command doesn't require a token (see commandRequiresToken), so
+// that the last deploy can still be displayed without one.
+func (c *StatusCommand) apiClient() (api.Interface, error) {
+	token, source := c.Globals.Token()
+	if source == lookup.SourceUndefined {
+		return nil, errors.New("the active version can't be compared without an API token (see `fastly profile create` or the --token flag)")
+	}
+	endpoint, _ := c.Globals.APIEndpoint()
+	client, err := c.Globals.APIClientFactory(token, endpoint, c.Globals.Flags.Debug)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return nil, fmt.Errorf("failed to construct the API client: %w", err)
+	}
+	return client, nil
+}
+
+// remoteNote records a failure to fetch remote data and describes it.
+func (c *StatusCommand) remoteNote(err error, serviceID, what string) string {
+	c.Globals.ErrLog.AddWithContext(err, map[string]any{
+		"Service ID": serviceID,
+	})
+	var he *fastly.HTTPError
+	if errors.As(err, &he) {
+		switch he.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return fmt.Sprintf("failed to fetch %s: the token lacks access to service %s (HTTP %d)", what, serviceID, he.StatusCode)
+		case http.StatusNotFound:
+			return fmt.Sprintf("failed to fetch %s: not found on service %s (HTTP %d)", what, serviceID, he.StatusCode)
+		}
+	}
+	return fmt.Sprintf("failed to fetch %s: %s", what, err)
+}
+
+func printComputeStatus(out io.Writer, s Status) {
+	if s.LastDeploy == nil {
+		text.Info(out, "Status unknown: %s. A deploy is recorded by `fastly compute deploy`.", s.Note)
+		return
+	}
+
+	d := s.LastDeploy
+	text.Output(out, "Last deploy: service %s, version %d, package %s, at %s", d.ServiceID, d.ServiceVersion, shortHash(d.PackageHash), text.FormatTimeLayout(&d.DeployedAt, fsttime.Format))
+	switch {
+	case s.ActiveVersion != nil:
+		text.Output(out, "Active version: %d, package %s", *s.ActiveVersion, shortHash(s.ActivePackageHash))
+	case s.Sync != SyncUnknown:
+		text.Output(out, "Active version: none")
+	}
+	text.Break(out)
+
+	switch s.Sync {
+	case SyncInSync:
+		text.Success(out, "In sync: the active version serves the package last deployed from this project")
+	case SyncAhead:
+		text.Warning(out, "Ahead: version %d was deployed from this project but isn't active (e.g. the service was rolled back)", d.ServiceVersion)
+	case SyncDiverged:
+		text.Warning(out, "Diverged: the active version serves a different package than the one last deployed from this project (e.g. it was deployed from another checkout)")
+	default:
+		text.Info(out, "Status unknown: %s", s.Note)
+	}
+}
+
+// shortHash abbreviates a package hash for display.
+func shortHash(h string) string {
+	if h == "" {
+		return "none"
+	}
+	if len(h) > shortHashLength {
+		return h[:shortHashLength]
+	}
+	return h
+}
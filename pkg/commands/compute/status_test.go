@@ -0,0 +1,327 @@
+package compute_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+const (
+	deployedHash = "1111111111111111aaaa"
+	otherHash    = "2222222222222222bbbb"
+)
+
+func TestComputeStatus(t *testing.T) {
+	recorded := &compute.DeployState{
+		DeployedAt:     time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		PackageHash:    deployedHash,
+		ServiceID:      "123",
+		ServiceVersion: 5,
+	}
+	activeVersion := func(n int) func(*fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+		return func(i *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+			return &fastly.ServiceDetail{
+				ServiceID:     fastly.ToPointer(i.ServiceID),
+				ActiveVersion: &fastly.Version{Number: fastly.ToPointer(n)},
+			}, nil
+		}
+	}
+	packageHash := func(hash string) func(*fastly.GetPackageInput) (*fastly.Package, error) {
+		return func(i *fastly.GetPackageInput) (*fastly.Package, error) {
+			return &fastly.Package{
+				ServiceID:      fastly.ToPointer(i.ServiceID),
+				ServiceVersion: fastly.ToPointer(i.ServiceVersion),
+				Metadata:       &fastly.PackageMetadata{FilesHash: fastly.ToPointer(hash)},
+			}, nil
+		}
+	}
+
+	scenarios := []struct {
+		name       string
+		state      *compute.DeployState
+		stateFile  string
+		api        mock.API
+		noToken    bool
+		wantStatus string
+		wantNote   string
+		wantOutput []string
+	}{
+		{
+			name:       "in sync",
+			state:      recorded,
+			api:        mock.API{GetServiceDetailsFn: activeVersion(5), GetPackageFn: packageHash(deployedHash)},
+			wantStatus: compute.SyncInSync,
+			wantOutput: []string{
+				"Last deploy: service 123, version 5, package 111111111111, at 2024-05-01 12:00",
+				"Active version: 5, package 111111111111",
+				"SUCCESS: In sync",
+			},
+		},
+		{
+			name:       "in sync with the same package on a newer version",
+			state:      recorded,
+			api:        mock.API{GetServiceDetailsFn: activeVersion(6), GetPackageFn: packageHash(deployedHash)},
+			wantStatus: compute.SyncInSync,
+		},
+		{
+			name:       "ahead after a rollback",
+			state:      recorded,
+			api:        mock.API{GetServiceDetailsFn: activeVersion(3), GetPackageFn: packageHash(otherHash)},
+			wantStatus: compute.SyncAhead,
+			wantOutput: []string{
+				"Active version: 3, package 222222222222",
+				"WARNING: Ahead: version 5 was deployed from this project but isn't active",
+			},
+		},
+		{
+			name:  "ahead without an active version",
+			state: recorded,
+			api: mock.API{GetServiceDetailsFn: func(_ *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+				return &fastly.ServiceDetail{}, nil
+			}},
+			wantStatus: compute.SyncAhead,
+			wantOutput: []string{"Active version: none"},
+		},
+		{
+			name:       "diverged",
+			state:      recorded,
+			api:        mock.API{GetServiceDetailsFn: activeVersion(7), GetPackageFn: packageHash(otherHash)},
+			wantStatus: compute.SyncDiverged,
+			wantOutput: []string{"WARNING: Diverged"},
+		},
+		{
+			name:       "no state",
+			wantStatus: compute.SyncUnknown,
+			wantNote:   "no deploy has been recorded for this project",
+			wantOutput: []string{"A deploy is recorded by `fastly compute deploy`"},
+		},
+		{
+			name:       "invalid state",
+			stateFile:  "{",
+			wantStatus: compute.SyncUnknown,
+			wantNote:   "failed to parse",
+		},
+		{
+			name:       "no token",
+			state:      recorded,
+			noToken:    true,
+			wantStatus: compute.SyncUnknown,
+			wantNote:   "the active version can't be compared without an API token",
+			wantOutput: []string{"Last deploy: service 123, version 5"},
+		},
+		{
+			name:  "no access",
+			state: recorded,
+			api: mock.API{GetServiceDetailsFn: func(_ *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+				return nil, &fastly.HTTPError{StatusCode: http.StatusForbidden}
+			}},
+			wantStatus: compute.SyncUnknown,
+			wantNote:   "failed to fetch service: the token lacks access to service 123 (HTTP 403)",
+		},
+		{
+			name:  "no package",
+			state: recorded,
+			api: mock.API{
+				GetServiceDetailsFn: activeVersion(5),
+				GetPackageFn: func(_ *fastly.GetPackageInput) (*fastly.Package, error) {
+					return nil, &fastly.HTTPError{StatusCode: http.StatusNotFound}
+				},
+			},
+			wantStatus: compute.SyncUnknown,
+			wantNote:   "failed to fetch the package of version 5: not found on service 123 (HTTP 404)",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		for _, jsonOutput := range []bool{false, true} {
+			name := testcase.name
+			if jsonOutput {
+				name += " json"
+			}
+			t.Run(name, func(t *testing.T) {
+				rootdir := testutil.NewEnv(testutil.EnvOpts{
+					T: t,
+					Write: []testutil.FileIO{
+						{Src: "manifest_version = 2\nname = \"package\"\n", Dst: manifest.Filename},
+					},
+				})
+				defer os.RemoveAll(rootdir)
+
+				if testcase.state != nil {
+					if err := compute.WriteDeployState(rootdir, *testcase.state); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if testcase.stateFile != "" {
+					path := compute.DeployStatePath(rootdir)
+					if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+						t.Fatal(err)
+					}
+					if err := os.WriteFile(path, []byte(testcase.stateFile), 0o600); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				args := testutil.Args("compute status --dir " + rootdir)
+				if jsonOutput {
+					args = append(args, "--json")
+				}
+				var stdout bytes.Buffer
+				app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+					opts := testutil.MockGlobalData(args, &stdout)
+					opts.APIClientFactory = mock.APIClient(testcase.api)
+					if testcase.noToken {
+						opts.Config.Profiles = nil
+					}
+					return opts, nil
+				}
+				err := app.Run(args, nil)
+				t.Log(stdout.String())
+				testutil.AssertNoError(t, err)
+
+				if !jsonOutput {
+					for _, s := range testcase.wantOutput {
+						testutil.AssertStringContains(t, stdout.String(), s)
+					}
+					if testcase.wantNote != "" {
+						testutil.AssertStringContains(t, stdout.String(), testcase.wantNote)
+					}
+					return
+				}
+
+				var status compute.Status
+				if err := json.Unmarshal(stdout.Bytes(), &status); err != nil {
+					t.Fatal(err)
+				}
+				testutil.AssertString(t, testcase.wantStatus, status.Sync)
+				testutil.AssertStringContains(t, status.Note, testcase.wantNote)
+				if testcase.state != nil {
+					testutil.AssertEqual(t, testcase.state, status.LastDeploy)
+				}
+			})
+		}
+	}
+}
+
+// TestDeployRecordsState validates a successful deploy is recorded for
+// `compute status`.
+func TestDeployRecordsState(t *testing.T) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootdir := testutil.NewEnv(testutil.EnvOpts{
+		T: t,
+		Copy: []testutil.FileIO{
+			{
+				Src: filepath.Join("testdata", "deploy", "pkg", "package.tar.gz"),
+				Dst: filepath.Join("pkg", "package.tar.gz"),
+			},
+		},
+		Write: []testutil.FileIO{
+			{Src: "manifest_version = 2\nname = \"package\"\n", Dst: manifest.Filename},
+		},
+	})
+	defer os.RemoveAll(rootdir)
+
+	if err := os.Chdir(rootdir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.Chdir(pwd)
+	}()
+
+	args := testutil.Args("compute deploy --service-id 123 --package pkg/package.tar.gz")
+	var stdout bytes.Buffer
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		opts := testutil.MockGlobalData(args, &stdout)
+		opts.APIClientFactory = mock.APIClient(mock.API{
+			ActivateVersionFn:   activateVersionOk,
+			CloneVersionFn:      testutil.CloneVersionResult(4),
+			GetPackageFn:        getPackageOk,
+			GetServiceDetailsFn: getServiceDetailsWasm,
+			GetServiceFn:        getServiceOK,
+			ListDomainsFn:       listDomainsOk,
+			ListVersionsFn:      testutil.ListVersions,
+			UpdatePackageFn:     updatePackageOk,
+		})
+		return opts, nil
+	}
+	start := time.Now()
+	err = app.Run(args, nil)
+	t.Log(stdout.String())
+	testutil.AssertNoError(t, err)
+
+	state, err := compute.ReadDeployState(rootdir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertString(t, "123", state.ServiceID)
+	testutil.AssertEqual(t, 4, state.ServiceVersion)
+	if len(state.PackageHash) != 128 {
+		t.Errorf("want a sha512 package hash, have %q", state.PackageHash)
+	}
+	if state.DeployedAt.Before(start.Add(-time.Second)) {
+		t.Errorf("want the deploy time to be recorded, have %s", state.DeployedAt)
+	}
+}
+
+func TestIgnoreStateDir(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		existing *string
+		want     string
+	}{
+		{
+			name: "no .gitignore",
+			want: "/.fastly/\n",
+		},
+		{
+			name:     "append",
+			existing: fastly.ToPointer("bin/\npkg/\n"),
+			want:     "bin/\npkg/\n/.fastly/\n",
+		},
+		{
+			name:     "append without trailing newline",
+			existing: fastly.ToPointer("bin/"),
+			want:     "bin/\n/.fastly/\n",
+		},
+		{
+			name:     "already ignored",
+			existing: fastly.ToPointer("bin/\n.fastly\n"),
+			want:     "bin/\n.fastly\n",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, ".gitignore")
+			if testcase.existing != nil {
+				if err := os.WriteFile(path, []byte(*testcase.existing), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			testutil.AssertNoError(t, compute.IgnoreStateDir(dir))
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutil.AssertString(t, testcase.want, string(data))
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package traffic inspects the requests handled by the local server started
+// by `compute serve`: it logs and captures each request via a reverse proxy,
+// and replays captured requests to compare the responses.
+package traffic
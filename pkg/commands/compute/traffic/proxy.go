@@ -0,0 +1,198 @@
+package traffic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// DefaultMaxBodySize is the number of body bytes captured for each request
+// and response. The remainder of a larger body is streamed but not captured.
+const DefaultMaxBodySize = 64 * 1024
+
+// CacheHeader is the response header reported as the cache state.
+const CacheHeader = "X-Cache"
+
+// Entry is a request handled by the local server.
+type Entry struct {
+	Time       time.Time `json:"time"`
+	Method     string    `json:"method"`
+	URL        string    `json:"url"`
+	Status     int       `json:"status"`
+	DurationMS float64   `json:"duration_ms"`
+	Cache      string    `json:"cache,omitempty"`
+	Request    Message   `json:"request"`
+	Response   Message   `json:"response"`
+}
+
+// Message is the captured header and body of a request or response.
+type Message struct {
+	Header http.Header `json:"header,omitempty"`
+	// Body is the captured body (at most the proxy's MaxBodySize bytes).
+	Body []byte `json:"body,omitempty"`
+	// Size is the full size of the body.
+	Size int64 `json:"size"`
+	// Truncated indicates the body exceeded MaxBodySize.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Proxy is a reverse proxy to the local server that logs each request.
+type Proxy struct {
+	// Capture is where each Entry is written as a JSON line (optional).
+	Capture io.Writer
+	// MaxBodySize is the number of body bytes captured (DefaultMaxBodySize if
+	// zero).
+	MaxBodySize int
+	// Now returns the current time (time.Now if nil).
+	Now func() time.Time
+	// Output is where a summary line of each request is written.
+	Output io.Writer
+
+	mu      sync.Mutex
+	reverse *httputil.ReverseProxy
+}
+
+// NewProxy returns a Proxy forwarding requests to target.
+func NewProxy(target *url.URL, out io.Writer) *Proxy {
+	p := &Proxy{Output: out}
+	p.reverse = httputil.NewSingleHostReverseProxy(target)
+	return p
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	now := time.Now
+	if p.Now != nil {
+		now = p.Now
+	}
+	start := now()
+
+	e := Entry{
+		Time:   start.UTC(),
+		Method: r.Method,
+		URL:    r.URL.RequestURI(),
+		Request: Message{
+			Header: r.Header.Clone(),
+		},
+	}
+
+	reqBody := &capture{limit: p.maxBodySize()}
+	if r.Body != nil && r.Body != http.NoBody {
+		r.Body = readCloser{Reader: io.TeeReader(r.Body, reqBody), Closer: r.Body}
+	}
+	rw := &responseWriter{ResponseWriter: w, body: capture{limit: p.maxBodySize()}}
+
+	p.reverse.ServeHTTP(rw, r)
+
+	e.DurationMS = float64(now().Sub(start).Microseconds()) / 1000
+	e.Status = rw.status()
+	e.Cache = rw.Header().Get(CacheHeader)
+	e.Request.Body, e.Request.Size, e.Request.Truncated = reqBody.result()
+	e.Response.Header = rw.Header().Clone()
+	e.Response.Body, e.Response.Size, e.Response.Truncated = rw.body.result()
+
+	p.record(e)
+}
+
+// record writes the summary line and capture of an entry.
+func (p *Proxy) record(e Entry) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.Output != nil {
+		fmt.Fprintln(p.Output, FormatEntry(e))
+	}
+	if p.Capture != nil {
+		if b, err := json.Marshal(e); err == nil {
+			_, _ = p.Capture.Write(append(b, '\n'))
+		}
+	}
+}
+
+func (p *Proxy) maxBodySize() int {
+	if p.MaxBodySize > 0 {
+		return p.MaxBodySize
+	}
+	return DefaultMaxBodySize
+}
+
+// FormatEntry returns the summary line of an entry.
+// e.g. "12:00:00 GET /hello 200 1.25ms HIT"
+func FormatEntry(e Entry) string {
+	cache := e.Cache
+	if cache == "" {
+		cache = "-"
+	}
+	duration := time.Duration(e.DurationMS * float64(time.Millisecond))
+	return fmt.Sprintf("%s %s %s %d %s %s", e.Time.Local().Format(time.TimeOnly), e.Method, e.URL, e.Status, duration, cache)
+}
+
+// capture records the first limit bytes written to it, and counts the rest.
+type capture struct {
+	buf   bytes.Buffer
+	limit int
+	size  int64
+}
+
+func (c *capture) Write(b []byte) (int, error) {
+	c.size += int64(len(b))
+	if room := c.limit - c.buf.Len(); room > 0 {
+		c.buf.Write(b[:min(room, len(b))])
+	}
+	return len(b), nil
+}
+
+func (c *capture) result() (body []byte, size int64, truncated bool) {
+	if c.buf.Len() > 0 {
+		body = bytes.Clone(c.buf.Bytes())
+	}
+	return body, c.size, c.size > int64(c.buf.Len())
+}
+
+// responseWriter records the status and body written to a response.
+type responseWriter struct {
+	http.ResponseWriter
+	body capture
+	code int
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	_, _ = w.body.Write(b[:n])
+	return n, err
+}
+
+// Flush supports streamed responses (e.g. server-sent events).
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *responseWriter) status() int {
+	if w.code == 0 {
+		return http.StatusOK
+	}
+	return w.code
+}
+
+// readCloser pairs a replacement body reader with the original body closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,171 @@
+package traffic
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fastly/cli/pkg/text"
+)
+
+// ComparedHeaders are the response headers compared when replaying.
+var ComparedHeaders = []string{"Content-Type", CacheHeader}
+
+// ReadCapture reads the entries of a capture (JSON lines).
+func ReadCapture(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	// NOTE: A line holds two bodies of up to DefaultMaxBodySize (base64 encoded).
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return nil, fmt.Errorf("invalid capture on line %d: %w", n, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// Result is the outcome of replaying a captured request.
+type Result struct {
+	// Entry is the captured request and response.
+	Entry Entry
+	// Response is the response to the replayed request.
+	Response Message
+	// Status is the status of the replayed request.
+	Status int
+	// Differences describes how the response differs from the capture.
+	Differences []string
+	// Skipped explains why the request wasn't replayed (if it wasn't).
+	Skipped string
+	// Err is the error sending the request (if any).
+	Err error
+}
+
+// Matched indicates if the replayed response matches the capture.
+func (r Result) Matched() bool {
+	return r.Skipped == "" && r.Err == nil && len(r.Differences) == 0
+}
+
+// Replay sends each captured request to base (e.g. http://127.0.0.1:7676) and
+// compares the responses with those captured.
+func Replay(ctx context.Context, client *http.Client, base *url.URL, entries []Entry) []Result {
+	results := make([]Result, 0, len(entries))
+	for _, e := range entries {
+		results = append(results, replay(ctx, client, base, e))
+	}
+	return results
+}
+
+func replay(ctx context.Context, client *http.Client, base *url.URL, e Entry) Result {
+	result := Result{Entry: e}
+	if e.Request.Truncated {
+		result.Skipped = "the request body exceeded the capture size limit"
+		return result
+	}
+
+	ref, err := url.Parse(e.URL)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	req, err := http.NewRequestWithContext(ctx, e.Method, base.ResolveReference(ref).String(), bytes.NewReader(e.Request.Body))
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	for k, v := range e.Request.Header {
+		req.Header[k] = v
+	}
+	req.Header.Del("Content-Length")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	defer resp.Body.Close()
+
+	limit := max(len(e.Response.Body), DefaultMaxBodySize)
+	body := &capture{limit: limit}
+	if _, err := io.Copy(body, resp.Body); err != nil {
+		result.Err = err
+		return result
+	}
+	result.Status = resp.StatusCode
+	result.Response.Header = resp.Header
+	result.Response.Body, result.Response.Size, result.Response.Truncated = body.result()
+	result.Differences = compare(e, result)
+	return result
+}
+
+// compare describes the differences between the captured and replayed
+// responses.
+func compare(e Entry, r Result) []string {
+	var diffs []string
+	if e.Status != r.Status {
+		diffs = append(diffs, fmt.Sprintf("status: %d -> %d", e.Status, r.Status))
+	}
+	for _, h := range ComparedHeaders {
+		if was, now := e.Response.Header.Get(h), r.Response.Header.Get(h); was != now {
+			diffs = append(diffs, fmt.Sprintf("%s: %q -> %q", h, was, now))
+		}
+	}
+	if e.Response.Size != r.Response.Size {
+		diffs = append(diffs, fmt.Sprintf("body size: %d -> %d bytes", e.Response.Size, r.Response.Size))
+	}
+	// NOTE: Only the captured part of a truncated body can be compared.
+	replayed := r.Response.Body
+	if len(replayed) > len(e.Response.Body) && e.Response.Truncated {
+		replayed = replayed[:len(e.Response.Body)]
+	}
+	if !bytes.Equal(e.Response.Body, replayed) {
+		diffs = append(diffs, "body")
+	}
+	return diffs
+}
+
+// PrintResults writes a line for each result, followed by the body diff of
+// mismatched textual responses, and a summary.
+func PrintResults(out io.Writer, results []Result) {
+	var matched, differed, failed, skipped int
+	for _, r := range results {
+		line := fmt.Sprintf("%s %s", r.Entry.Method, r.Entry.URL)
+		switch {
+		case r.Skipped != "":
+			skipped++
+			text.Output(out, "%s %s (skipped: %s)", text.WarningStyle("SKIP"), line, r.Skipped)
+		case r.Err != nil:
+			failed++
+			text.Output(out, "%s %s: %s", text.ErrorStyle("FAIL"), line, r.Err)
+		case r.Matched():
+			matched++
+			text.Output(out, "%s %s %d", text.SuccessStyle("OK"), line, r.Status)
+		default:
+			differed++
+			text.Output(out, "%s %s (%s)", text.ErrorStyle("DIFF"), line, strings.Join(r.Differences, ", "))
+			if printable(r.Entry.Response.Body) && printable(r.Response.Body) {
+				text.Diff(out, "captured", "replayed", string(r.Entry.Response.Body), string(r.Response.Body))
+			}
+		}
+	}
+	text.Break(out)
+	text.Output(out, "Replayed %d request(s): %d matched, %d differed, %d failed, %d skipped", len(results), matched, differed, failed, skipped)
+}
+
+// printable indicates if a body can be displayed as text.
+func printable(b []byte) bool {
+	return utf8.Valid(b) && !bytes.ContainsRune(b, 0)
+}
@@ -0,0 +1,178 @@
+package traffic_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fastly/cli/pkg/commands/compute/traffic"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+// viceroy stands in for the local server started by `compute serve`.
+func viceroy() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hello", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("X-Cache", "MISS")
+		_, _ = io.WriteString(w, "hello")
+	})
+	mux.HandleFunc("/greeting", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		if r.URL.Query().Get("lang") == "en" {
+			_, _ = io.WriteString(w, "new")
+		}
+	})
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = io.Copy(w, r.Body)
+	})
+	mux.HandleFunc("/big", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = io.WriteString(w, strings.Repeat("x", 1000))
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, "not found")
+	})
+	return mux
+}
+
+func TestProxy(t *testing.T) {
+	upstream := httptest.NewServer(viceroy())
+	defer upstream.Close()
+	target, err := url.Parse(upstream.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out, capture bytes.Buffer
+	p := traffic.NewProxy(target, &out)
+	p.Capture = &capture
+	p.MaxBodySize = 16
+	proxy := httptest.NewServer(p)
+	defer proxy.Close()
+
+	get := func(path string) string {
+		resp, err := http.Get(proxy.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	testutil.AssertString(t, "hello", get("/hello"))
+	// The client receives the full body even though the capture is truncated.
+	testutil.AssertString(t, strings.Repeat("x", 1000), get("/big?page=1"))
+	resp, err := http.Post(proxy.URL+"/echo", "text/plain", strings.NewReader(strings.Repeat("y", 20)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("want 3 lines, have %d: %q", len(lines), out.String())
+	}
+	testutil.AssertStringContains(t, lines[0], " GET /hello 200 ")
+	testutil.AssertStringContains(t, lines[0], " MISS")
+	testutil.AssertStringContains(t, lines[1], " GET /big?page=1 200 ")
+	testutil.AssertStringContains(t, lines[1], " -")
+	testutil.AssertStringContains(t, lines[2], " POST /echo 200 ")
+
+	entries, err := traffic.ReadCapture(&capture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("want 3 entries, have %d", len(entries))
+	}
+
+	hello := entries[0]
+	testutil.AssertString(t, "MISS", hello.Cache)
+	testutil.AssertString(t, "hello", string(hello.Response.Body))
+	testutil.AssertEqual(t, int64(5), hello.Response.Size)
+	testutil.AssertBool(t, false, hello.Response.Truncated)
+
+	big := entries[1]
+	testutil.AssertString(t, "/big?page=1", big.URL)
+	testutil.AssertString(t, strings.Repeat("x", 16), string(big.Response.Body))
+	testutil.AssertEqual(t, int64(1000), big.Response.Size)
+	testutil.AssertBool(t, true, big.Response.Truncated)
+
+	echo := entries[2]
+	testutil.AssertString(t, strings.Repeat("y", 16), string(echo.Request.Body))
+	testutil.AssertEqual(t, int64(20), echo.Request.Size)
+	testutil.AssertBool(t, true, echo.Request.Truncated)
+	testutil.AssertString(t, "text/plain", echo.Request.Header.Get("Content-Type"))
+}
+
+func TestFormatEntry(t *testing.T) {
+	e := traffic.Entry{
+		Time:       time.Date(2024, 1, 2, 12, 0, 0, 0, time.Local),
+		Method:     http.MethodGet,
+		URL:        "/hello",
+		Status:     http.StatusOK,
+		DurationMS: 1.25,
+		Cache:      "HIT",
+	}
+	testutil.AssertString(t, "12:00:00 GET /hello 200 1.25ms HIT", traffic.FormatEntry(e))
+}
+
+func TestReplay(t *testing.T) {
+	upstream := httptest.NewServer(viceroy())
+	defer upstream.Close()
+	base, err := url.Parse(upstream.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open("testdata/capture.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	entries, err := traffic.ReadCapture(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := traffic.Replay(context.Background(), upstream.Client(), base, entries)
+	if len(results) != 5 {
+		t.Fatalf("want 5 results, have %d", len(results))
+	}
+
+	testutil.AssertBool(t, true, results[0].Matched())
+	testutil.AssertEqual(t, []string{"body"}, results[1].Differences)
+	testutil.AssertBool(t, true, results[2].Matched())
+	testutil.AssertString(t, "the request body exceeded the capture size limit", results[3].Skipped)
+	testutil.AssertEqual(t, []string{"status: 200 -> 404", "body size: 5 -> 9 bytes", "body"}, results[4].Differences)
+
+	var out bytes.Buffer
+	traffic.PrintResults(&out, results)
+	output := out.String()
+	testutil.AssertStringContains(t, output, "OK GET /hello 200")
+	testutil.AssertStringContains(t, output, "DIFF GET /greeting?lang=en (body)")
+	testutil.AssertStringContains(t, output, "-old")
+	testutil.AssertStringContains(t, output, "+new")
+	testutil.AssertStringContains(t, output, "SKIP POST /upload")
+	testutil.AssertStringContains(t, output, "DIFF GET /missing (status: 200 -> 404, body size: 5 -> 9 bytes, body)")
+	testutil.AssertStringContains(t, output, "Replayed 5 request(s): 2 matched, 2 differed, 0 failed, 1 skipped")
+}
+
+func TestReadCaptureInvalid(t *testing.T) {
+	_, err := traffic.ReadCapture(strings.NewReader("{\"method\":\"GET\"}\nnot json\n"))
+	testutil.AssertErrorContains(t, err, "invalid capture on line 2")
+}
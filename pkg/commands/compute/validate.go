@@ -66,6 +66,17 @@ func (c *ValidateCommand) Exec(_ io.Reader, out io.Writer) error {
 		}
 	}
 
+	problems, err := checkPackageABI(p, computeABI(c.Globals))
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	if len(problems) > 0 {
+		err := abiError(problems)
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
 	text.Success(out, "Validated package %s", p)
 	return nil
 }
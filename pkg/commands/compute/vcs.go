@@ -0,0 +1,168 @@
+package compute
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// Descriptions of the flags, shared by deploy and publish, that guard against
+// deploying from an unexpected git working tree.
+const (
+	requireBranchDesc = "Refuse to deploy unless the project's git repository is on the named branch"
+	requireCleanDesc  = "Refuse to deploy if the project's git working tree has uncommitted or untracked changes"
+)
+
+// maxListedChanges is the number of uncommitted changes listed by the error
+// of the --require-clean guard.
+const maxListedChanges = 20
+
+// ErrNotGitRepository indicates a VCS guard was requested for a project that
+// isn't within a git repository.
+var ErrNotGitRepository = errors.New("the project directory is not a git repository")
+
+// VCSGuards are the optional checks of the project's git working tree made
+// before a deploy. They're a no-op unless one is enabled.
+type VCSGuards struct {
+	// RequireBranch is the branch HEAD must be on (if set).
+	RequireBranch string
+	// RequireClean refuses uncommitted or untracked changes.
+	RequireClean bool
+}
+
+// Enabled indicates if any guard was requested.
+func (g VCSGuards) Enabled() bool {
+	return g.RequireClean || g.RequireBranch != ""
+}
+
+// VCSStatus is the state of the project's git working tree, as checked by the
+// VCS guards. It's included in the deploy's output and in the metadata of a
+// Wasm binary built by `compute publish`.
+type VCSStatus struct {
+	// Branch is the branch HEAD is on (empty if HEAD is detached).
+	Branch string `json:"branch,omitempty"`
+	// Changes are the uncommitted and untracked changes, in the `git status
+	// --porcelain` format.
+	Changes []string `json:"changes,omitempty"`
+	// Clean indicates there are no uncommitted or untracked changes.
+	Clean bool `json:"clean"`
+	// Commit is the commit SHA of HEAD (empty if there are no commits).
+	Commit string `json:"commit,omitempty"`
+	// RequireBranch is the branch required by --require-branch.
+	RequireBranch string `json:"require_branch,omitempty"`
+	// RequireClean indicates --require-clean was set.
+	RequireClean bool `json:"require_clean,omitempty"`
+}
+
+// String summarises the status, e.g. "main @ 3e1f9a2 (clean)".
+func (s *VCSStatus) String() string {
+	ref := s.Branch
+	if ref == "" {
+		ref = "detached HEAD"
+	}
+	if s.Commit != "" {
+		commit := s.Commit
+		if len(commit) > 7 {
+			commit = commit[:7]
+		}
+		ref += " @ " + commit
+	}
+	if s.Clean {
+		return ref + " (clean)"
+	}
+	return fmt.Sprintf("%s (%d uncommitted changes)", ref, len(s.Changes))
+}
+
+// Check inspects the git working tree of the current directory with git (see
+// global.Data.ExecuteGit) and returns an error if a guard fails. A nil status
+// is returned if no guard is enabled.
+func (g VCSGuards) Check(git func(args ...string) ([]byte, error)) (*VCSStatus, error) {
+	if !g.Enabled() {
+		return nil, nil
+	}
+
+	if output, err := git("rev-parse", "--is-inside-work-tree"); err != nil {
+		if strings.Contains(string(output), "not a git repository") {
+			return nil, fsterr.RemediationError{
+				Inner:       ErrNotGitRepository,
+				Remediation: "The --require-clean and --require-branch flags check the project's git working tree. Deploy from a git repository, or remove the flags.",
+			}
+		}
+		return nil, gitError(err, output)
+	}
+
+	status := &VCSStatus{
+		RequireBranch: g.RequireBranch,
+		RequireClean:  g.RequireClean,
+	}
+	// NOTE: HEAD doesn't resolve in a repository without commits.
+	if output, err := git("rev-parse", "HEAD"); err == nil {
+		status.Commit = strings.TrimSpace(string(output))
+	}
+	// NOTE: symbolic-ref fails (quietly) when HEAD is detached.
+	if output, err := git("symbolic-ref", "--quiet", "--short", "HEAD"); err == nil {
+		status.Branch = strings.TrimSpace(string(output))
+	}
+	output, err := git("status", "--porcelain", "--untracked-files=all")
+	if err != nil {
+		return nil, gitError(err, output)
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.TrimSpace(line) != "" {
+			status.Changes = append(status.Changes, line)
+		}
+	}
+	status.Clean = len(status.Changes) == 0
+
+	if g.RequireClean && !status.Clean {
+		changes := status.Changes
+		var more string
+		if len(changes) > maxListedChanges {
+			more = fmt.Sprintf("\n  ... and %d more", len(changes)-maxListedChanges)
+			changes = changes[:maxListedChanges]
+		}
+		return status, fsterr.RemediationError{
+			Inner:       fmt.Errorf("--require-clean: the git working tree has %d uncommitted or untracked changes:\n\n  %s%s", len(status.Changes), strings.Join(changes, "\n  "), more),
+			Remediation: "Commit or stash the changes (or add untracked files to .gitignore), then deploy again.",
+		}
+	}
+	if g.RequireBranch != "" && status.Branch != g.RequireBranch {
+		current := fmt.Sprintf("on branch '%s'", status.Branch)
+		if status.Branch == "" {
+			current = "detached"
+			if status.Commit != "" {
+				current += " at " + status.Commit
+			}
+		}
+		return status, fsterr.RemediationError{
+			Inner:       fmt.Errorf("--require-branch: HEAD is %s, not on branch '%s'", current, g.RequireBranch),
+			Remediation: fmt.Sprintf("Check out the branch (`git switch %s`), then deploy again.", g.RequireBranch),
+		}
+	}
+	return status, nil
+}
+
+// gitError describes a failed git command with its output.
+func gitError(err error, output []byte) error {
+	if errors.Is(err, exec.ErrNotFound) {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("`git` not found in $PATH"),
+			Remediation: "The --require-clean and --require-branch flags require a local installation of git.",
+		}
+	}
+	return fmt.Errorf("error running git: %w\n\n%s", err, strings.TrimSpace(string(output)))
+}
+
+// ExecuteGit runs git with args in the current directory, returning its
+// combined output.
+func ExecuteGit(args ...string) ([]byte, error) {
+	// gosec flagged this:
+	// G204 (CWE-78): Subprocess launched with variable
+	// Disabling as the arguments are constant.
+	// #nosec
+	// nosemgrep: go.lang.security.audit.dangerous-exec-command.dangerous-exec-command
+	return exec.Command("git", args...).CombinedOutput()
+}
@@ -0,0 +1,3 @@
+// Package viceroy contains commands to manage the versions of Viceroy (the
+// local testing server) used by `compute serve`.
+package viceroy
@@ -0,0 +1,96 @@
+package viceroy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/blang/semver"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/env"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/github"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// InstallCommand downloads and installs a specific Viceroy version.
+type InstallCommand struct {
+	argparser.Base
+
+	checksum string
+	version  string
+}
+
+// NewInstallCommand returns a usable command registered under the parent.
+func NewInstallCommand(parent argparser.Registerer, g *global.Data) *InstallCommand {
+	c := InstallCommand{
+		Base: argparser.Base{
+			Globals: g,
+		},
+	}
+	c.CmdClause = parent.Command("install", "Download a Viceroy version, verify its checksum and install it alongside other versions")
+
+	// Required.
+	c.CmdClause.Arg("version", "The Viceroy version (e.g. 0.9.4), or 'latest'").Required().StringVar(&c.version)
+
+	// Optional.
+	c.CmdClause.Flag("checksum", "The expected SHA-256 checksum of the release asset (default: the checksum published with the release)").StringVar(&c.checksum)
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *InstallCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Offline() {
+		return fsterr.RemediationError{
+			Inner:       errors.New("can't download Viceroy in offline mode"),
+			Remediation: fmt.Sprintf("Unset the %s environment variable to install Viceroy.", env.Offline),
+		}
+	}
+
+	av := c.Globals.Versioners.Viceroy
+	version := c.version
+	if version == "latest" {
+		v, err := av.LatestVersion()
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("error fetching latest version: %w", err),
+				Remediation: fsterr.NetworkRemediation,
+			}
+		}
+		version = v
+	}
+	if _, err := semver.Parse(version); err != nil {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid version '%s': %w", version, err),
+			Remediation: "Provide a valid semver (https://semver.org/), e.g. `0.9.4`, or 'latest'.",
+		}
+	}
+
+	bin := github.VersionPath(av.BinaryName(), version)
+	if _, err := os.Stat(bin); err == nil {
+		text.Info(out, "Viceroy %s is already installed: %s", version, bin)
+		return nil
+	}
+
+	spinner, err := text.NewSpinner(out)
+	if err != nil {
+		return err
+	}
+	err = spinner.Process(fmt.Sprintf("Installing Viceroy %s", version), func(_ *text.SpinnerWrapper) error {
+		bin, err = av.InstallVersion(version, c.checksum)
+		return err
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Version": version,
+		})
+		return err
+	}
+
+	text.Success(out, "Installed Viceroy %s to %s", version, bin)
+	return nil
+}
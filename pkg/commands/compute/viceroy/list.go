@@ -0,0 +1,125 @@
+package viceroy
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/github"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// ListCommand lists the installed Viceroy versions.
+type ListCommand struct {
+	argparser.Base
+	argparser.EmptyState
+	argparser.JSONOutput
+}
+
+// NewListCommand returns a usable command registered under the parent.
+func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
+	c := ListCommand{
+		Base: argparser.Base{
+			Globals: g,
+		},
+	}
+	c.CmdClause = parent.Command("list", "List the installed Viceroy versions and their paths")
+	c.EmptyState = argparser.EmptyState{Resource: "Viceroy versions", Create: "fastly compute viceroy install <VERSION>"}
+
+	// Optional.
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
+	return &c
+}
+
+// installation is an installed Viceroy version.
+type installation struct {
+	Version string `json:"version"`
+	Path    string `json:"path"`
+	// AutoUpdated indicates the version `compute serve` keeps up-to-date when
+	// Viceroy isn't pinned (rather than one installed with `install`).
+	AutoUpdated bool `json:"auto_updated"`
+	// Pinned is where the version is pinned (see ResolvePin), if it is.
+	Pinned string `json:"pinned,omitempty"`
+}
+
+// Exec invokes the application logic for the command.
+func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	av := c.Globals.Versioners.Viceroy
+	versions, err := github.InstalledVersions(av.BinaryName())
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	pin, source := ResolvePin(c.Globals.Manifest.File.LocalServer.ViceroyVersion, c.Globals.Config.Viceroy.Pin)
+
+	// NOTE: `compute serve` prefers a pinned version installed with `install`,
+	// otherwise it uses the auto-updated version if it matches the pin.
+	installations := []installation{}
+	pinned := false
+	for _, v := range versions {
+		i := installation{Version: v.Version, Path: v.Path}
+		if v.Version == pin {
+			i.Pinned = source
+			pinned = true
+		}
+		installations = append(installations, i)
+	}
+	if bin := av.InstallPath(); bin != "" {
+		if v := binaryVersion(bin); v != "" {
+			i := installation{Version: v, Path: bin, AutoUpdated: true}
+			if v == pin && !pinned {
+				i.Pinned = source
+			}
+			installations = append([]installation{i}, installations...)
+		}
+	}
+
+	if ok, err := c.WriteJSON(out, installations); ok {
+		return err
+	}
+	if c.PrintEmpty(out, len(installations)) {
+		return nil
+	}
+
+	tw := text.NewTable(out)
+	tw.AddHeader("VERSION", "PINNED", "PATH")
+	for _, i := range installations {
+		version := i.Version
+		if i.AutoUpdated {
+			version += " (auto-updated)"
+		}
+		tw.AddLine(version, i.Pinned, i.Path)
+	}
+	tw.Print()
+	return nil
+}
+
+// binaryVersion returns the version reported by the Viceroy binary (e.g.
+// `viceroy 0.9.4`), or an empty string if it isn't installed.
+func binaryVersion(bin string) string {
+	if _, err := os.Stat(bin); err != nil {
+		return ""
+	}
+	// gosec flagged this:
+	// G204 (CWE-78): Subprocess launched with variable
+	// Disabling as the path is where the CLI installs Viceroy.
+	/* #nosec */
+	// nosemgrep
+	output, err := exec.Command(bin, "--version").Output()
+	if err != nil {
+		return "unknown"
+	}
+	segs := strings.Fields(string(output))
+	if len(segs) < 2 {
+		return "unknown"
+	}
+	return segs[1]
+}
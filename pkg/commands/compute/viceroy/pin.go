@@ -0,0 +1,127 @@
+package viceroy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/blang/semver"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/github"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// Where a Viceroy version is pinned (see ResolvePin).
+const (
+	// PinSourceManifest is the viceroy_version in the [local_server] section of
+	// the project's manifest.
+	PinSourceManifest = "manifest"
+	// PinSourceConfig is the pin in the [viceroy] section of the CLI config.
+	PinSourceConfig = "config"
+)
+
+// ResolvePin returns the pinned Viceroy version and where it's pinned. A
+// version pinned by the project's manifest takes precedence over one pinned in
+// the CLI config. An empty version means Viceroy isn't pinned, in which case
+// `compute serve` uses the latest version.
+func ResolvePin(manifestVersion, configVersion string) (version, source string) {
+	switch {
+	case manifestVersion != "":
+		return manifestVersion, PinSourceManifest
+	case configVersion != "":
+		return configVersion, PinSourceConfig
+	}
+	return "", ""
+}
+
+// PinCommand pins the Viceroy version used by `compute serve`.
+type PinCommand struct {
+	argparser.Base
+
+	global  bool
+	unset   bool
+	version string
+}
+
+// NewPinCommand returns a usable command registered under the parent.
+func NewPinCommand(parent argparser.Registerer, g *global.Data) *PinCommand {
+	c := PinCommand{
+		Base: argparser.Base{
+			Globals: g,
+		},
+	}
+	c.CmdClause = parent.Command("pin", "Pin the Viceroy version used by `compute serve`, for the project (fastly.toml) or globally (CLI config)")
+
+	// Optional.
+	c.CmdClause.Arg("version", "The Viceroy version (e.g. 0.9.4)").StringVar(&c.version)
+	c.CmdClause.Flag("global", "Pin the version in the CLI config, for projects that don't pin a version themselves").BoolVar(&c.global)
+	c.CmdClause.Flag("unset", "Remove the pin, so that the latest version is used").BoolVar(&c.unset)
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *PinCommand) Exec(_ io.Reader, out io.Writer) error {
+	switch {
+	case c.unset && c.version != "":
+		return fsterr.RemediationError{
+			Inner:       errors.New("a version can't be provided with --unset"),
+			Remediation: "Either provide a version to pin, or --unset to remove the pin.",
+		}
+	case !c.unset && c.version == "":
+		return fsterr.RemediationError{
+			Inner:       errors.New("no version provided"),
+			Remediation: "Provide the version to pin (e.g. `fastly compute viceroy pin 0.9.4`), or --unset to remove the pin.",
+		}
+	case c.version != "":
+		if _, err := semver.Parse(c.version); err != nil {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("invalid version '%s': %w", c.version, err),
+				Remediation: "Provide a valid semver (https://semver.org/), e.g. `0.9.4`.",
+			}
+		}
+	}
+
+	if c.global {
+		c.Globals.Config.Viceroy.Pin = c.version
+		if err := c.Globals.Config.Write(c.Globals.ConfigPath); err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error saving config file: %w", err)
+		}
+	} else {
+		if _, err := os.Stat(manifest.Filename); err != nil {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("error reading %s: %w", manifest.Filename, err),
+				Remediation: "Run the command from the project directory, or pin the version for all projects with --global.",
+			}
+		}
+		if err := c.Globals.Manifest.File.Read(manifest.Filename); err != nil {
+			return fmt.Errorf("error reading %s: %w", manifest.Filename, err)
+		}
+		c.Globals.Manifest.File.LocalServer.ViceroyVersion = c.version
+		if err := c.Globals.Manifest.File.Write(manifest.Filename); err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("error saving %s: %w", manifest.Filename, err)
+		}
+	}
+
+	where := manifest.Filename
+	if c.global {
+		where = "the CLI config"
+	}
+	if c.unset {
+		text.Success(out, "Removed the Viceroy pin from %s", where)
+		return nil
+	}
+	text.Success(out, "Pinned Viceroy %s in %s", c.version, where)
+
+	if _, err := os.Stat(github.VersionPath(c.Globals.Versioners.Viceroy.BinaryName(), c.version)); err != nil {
+		text.Break(out)
+		text.Info(out, "Viceroy %s isn't installed yet. `compute serve` will download it, or install it now with `fastly compute viceroy install %s`.", c.version, c.version)
+	}
+	return nil
+}
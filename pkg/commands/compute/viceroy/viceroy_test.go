@@ -0,0 +1,240 @@
+package viceroy_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/compute/viceroy"
+	"github.com/fastly/cli/pkg/github"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	fstruntime "github.com/fastly/cli/pkg/runtime"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+// setupProject creates a project directory (the current directory for the
+// duration of the test) and an empty install directory.
+func setupProject(t *testing.T, fastlyToml string) {
+	t.Helper()
+	root := t.TempDir()
+	project := filepath.Join(root, "project")
+	if err := os.MkdirAll(project, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if fastlyToml != "" {
+		if err := os.WriteFile(filepath.Join(project, manifest.Filename), []byte(fastlyToml), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(project); err != nil {
+		t.Fatal(err)
+	}
+	installDir := github.InstallDir
+	github.InstallDir = filepath.Join(root, "install")
+	t.Cleanup(func() {
+		github.InstallDir = installDir
+		_ = os.Chdir(wd)
+	})
+}
+
+// run runs the command with Viceroy downloaded from server, returning the
+// output and the global data (to inspect the config).
+func run(t *testing.T, args []string, server *testutil.ReleaseServer, configure func(*global.Data)) (string, *global.Data, error) {
+	t.Helper()
+	var stdout bytes.Buffer
+	opts := testutil.MockGlobalData(args, &stdout)
+	if server == nil {
+		server = testutil.NewReleaseServer(t, "viceroy", "0.9.4")
+	}
+	opts.Versioners.Viceroy = github.New(github.Opts{
+		HTTPClient: server.Client(),
+		Org:        "fastly",
+		Repo:       "viceroy",
+		Binary:     "viceroy",
+	})
+	if configure != nil {
+		configure(opts)
+	}
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		return opts, nil
+	}
+	err := app.Run(args, nil)
+	t.Log(stdout.String())
+	return stdout.String(), opts, err
+}
+
+func TestResolvePin(t *testing.T) {
+	for _, testcase := range []struct {
+		manifest, config        string
+		wantVersion, wantSource string
+	}{
+		{},
+		{config: "0.9.3", wantVersion: "0.9.3", wantSource: viceroy.PinSourceConfig},
+		{manifest: "0.9.2", wantVersion: "0.9.2", wantSource: viceroy.PinSourceManifest},
+		{manifest: "0.9.2", config: "0.9.3", wantVersion: "0.9.2", wantSource: viceroy.PinSourceManifest},
+	} {
+		version, source := viceroy.ResolvePin(testcase.manifest, testcase.config)
+		testutil.AssertString(t, testcase.wantVersion, version)
+		testutil.AssertString(t, testcase.wantSource, source)
+	}
+}
+
+func TestInstall(t *testing.T) {
+	if fstruntime.Windows {
+		t.Skip("the fake release asset contains a shell script")
+	}
+	args := testutil.Args
+
+	t.Run("specific version", func(t *testing.T) {
+		setupProject(t, "")
+		server := testutil.NewReleaseServer(t, "viceroy", "0.9.4")
+
+		out, _, err := run(t, args("compute viceroy install 0.9.3"), server, nil)
+		testutil.AssertNoError(t, err)
+		bin := github.VersionPath("viceroy", "0.9.3")
+		testutil.AssertStringContains(t, out, "Installed Viceroy 0.9.3 to "+bin)
+		asset := "/viceroy/v0.9.3/viceroy_v0.9.3_" + runtime.GOOS + "-" + runtime.GOARCH + ".tar.gz"
+		testutil.AssertEqual(t, []string{
+			"/api/internal/releases/meta/viceroy/" + runtime.GOOS + "/" + runtime.GOARCH,
+			asset + ".sha256",
+			asset,
+		}, server.Paths())
+
+		out, _, err = run(t, args("compute viceroy install 0.9.3"), server, nil)
+		testutil.AssertNoError(t, err)
+		testutil.AssertStringContains(t, out, "Viceroy 0.9.3 is already installed")
+	})
+
+	t.Run("latest version", func(t *testing.T) {
+		setupProject(t, "")
+
+		out, _, err := run(t, args("compute viceroy install latest"), nil, nil)
+		testutil.AssertNoError(t, err)
+		testutil.AssertStringContains(t, out, "Installed Viceroy 0.9.4")
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		setupProject(t, "")
+		server := testutil.NewReleaseServer(t, "viceroy", "0.9.4")
+		server.BadChecksum["0.9.3"] = true
+
+		_, _, err := run(t, args("compute viceroy install 0.9.3"), server, nil)
+		testutil.AssertErrorContains(t, err, "checksum mismatch for viceroy_v0.9.3_")
+		testutil.AssertRemediationErrorContains(t, err, "The download may be corrupt or incomplete")
+		if _, err := os.Stat(github.VersionPath("viceroy", "0.9.3")); !os.IsNotExist(err) {
+			t.Fatal("the unverified binary was installed")
+		}
+	})
+
+	t.Run("offline", func(t *testing.T) {
+		setupProject(t, "")
+		server := testutil.NewReleaseServer(t, "viceroy", "0.9.4")
+
+		_, _, err := run(t, args("compute viceroy install 0.9.3"), server, func(g *global.Data) {
+			g.Env.Offline = "true"
+		})
+		testutil.AssertErrorContains(t, err, "can't download Viceroy in offline mode")
+		testutil.AssertEqual(t, 0, len(server.Paths()))
+	})
+}
+
+func TestPin(t *testing.T) {
+	args := testutil.Args
+
+	t.Run("project", func(t *testing.T) {
+		setupProject(t, "manifest_version = 3\nname = \"example\"\n")
+
+		out, _, err := run(t, args("compute viceroy pin 0.9.3"), nil, nil)
+		testutil.AssertNoError(t, err)
+		testutil.AssertStringContains(t, out, "Pinned Viceroy 0.9.3 in fastly.toml")
+		testutil.AssertStringContains(t, out, "Viceroy 0.9.3 isn't installed yet")
+
+		var m manifest.File
+		testutil.AssertNoError(t, m.Read(manifest.Filename))
+		testutil.AssertString(t, "0.9.3", m.LocalServer.ViceroyVersion)
+		testutil.AssertString(t, "example", m.Name)
+
+		out, _, err = run(t, args("compute viceroy pin --unset"), nil, nil)
+		testutil.AssertNoError(t, err)
+		testutil.AssertStringContains(t, out, "Removed the Viceroy pin from fastly.toml")
+		var unpinned manifest.File
+		testutil.AssertNoError(t, unpinned.Read(manifest.Filename))
+		testutil.AssertString(t, "", unpinned.LocalServer.ViceroyVersion)
+	})
+
+	t.Run("global", func(t *testing.T) {
+		setupProject(t, "")
+
+		out, g, err := run(t, args("compute viceroy pin 0.9.3 --global"), nil, nil)
+		testutil.AssertNoError(t, err)
+		testutil.AssertStringContains(t, out, "Pinned Viceroy 0.9.3 in the CLI config")
+		testutil.AssertString(t, "0.9.3", g.Config.Viceroy.Pin)
+	})
+
+	t.Run("no manifest", func(t *testing.T) {
+		setupProject(t, "")
+
+		_, _, err := run(t, args("compute viceroy pin 0.9.3"), nil, nil)
+		testutil.AssertErrorContains(t, err, "error reading fastly.toml")
+		testutil.AssertRemediationErrorContains(t, err, "--global")
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		setupProject(t, "manifest_version = 3\n")
+
+		_, _, err := run(t, args("compute viceroy pin latest"), nil, nil)
+		testutil.AssertErrorContains(t, err, "invalid version 'latest'")
+	})
+
+	t.Run("version and unset", func(t *testing.T) {
+		setupProject(t, "manifest_version = 3\n")
+
+		_, _, err := run(t, args("compute viceroy pin 0.9.3 --unset"), nil, nil)
+		testutil.AssertErrorContains(t, err, "a version can't be provided with --unset")
+	})
+}
+
+func TestList(t *testing.T) {
+	if fstruntime.Windows {
+		t.Skip("the fake release asset contains a shell script")
+	}
+	args := testutil.Args
+	setupProject(t, "manifest_version = 3\n[local_server]\nviceroy_version = \"0.9.2\"\n")
+
+	out, _, err := run(t, args("compute viceroy list"), nil, nil)
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, out, "No Viceroy versions found. Run `fastly compute viceroy install <VERSION>`.")
+
+	for _, v := range []string{"0.9.2", "0.9.3"} {
+		_, _, err := run(t, args("compute viceroy install "+v), nil, nil)
+		testutil.AssertNoError(t, err)
+	}
+
+	// The project's pin takes precedence over the CLI config.
+	out, _, err = run(t, args("compute viceroy list --json"), nil, func(g *global.Data) {
+		g.Config.Viceroy.Pin = "0.9.3"
+	})
+	testutil.AssertNoError(t, err)
+	var installations []map[string]any
+	testutil.AssertNoError(t, json.Unmarshal([]byte(out), &installations))
+	testutil.AssertEqual(t, []map[string]any{
+		{"version": "0.9.3", "path": github.VersionPath("viceroy", "0.9.3"), "auto_updated": false},
+		{"version": "0.9.2", "path": github.VersionPath("viceroy", "0.9.2"), "auto_updated": false, "pinned": viceroy.PinSourceManifest},
+	}, installations)
+
+	out, _, err = run(t, args("compute viceroy list"), nil, nil)
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, out, "VERSION  PINNED    PATH")
+	testutil.AssertStringContains(t, out, "0.9.2    manifest  "+github.VersionPath("viceroy", "0.9.2"))
+}
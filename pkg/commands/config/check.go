@@ -0,0 +1,37 @@
+package config
+
+import (
+	"io"
+
+	"github.com/fastly/kingpin"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// CheckCommand validates the CLI config.
+type CheckCommand struct {
+	argparser.Base
+
+	app *kingpin.Application
+}
+
+// NewCheckCommand returns a usable command registered under the parent.
+func NewCheckCommand(parent argparser.Registerer, g *global.Data, app *kingpin.Application) *CheckCommand {
+	var c CheckCommand
+	c.Globals = g
+	c.app = app
+	c.CmdClause = parent.Command("check", "Validate the Fastly CLI configuration (e.g. the [command_defaults] section)")
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *CheckCommand) Exec(_ io.Reader, out io.Writer) error {
+	if err := ValidateDefaults(c.Globals.Config.CommandDefaults, c.app.Model()); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	text.Success(out, "The CLI configuration is valid")
+	return nil
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/fastly/kingpin"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/config"
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// DefaultsRemediation suggests fixing the [command_defaults] config section.
+var DefaultsRemediation = "Update the [command_defaults] section of the CLI config (see `fastly config --location`), then run `fastly config check` to validate it."
+
+// ApplyDefaults returns args with the flags configured for the invoked command
+// in the [command_defaults] config section added, along with the flags that
+// were added. Flags explicitly provided in args aren't added, so that they
+// take precedence over the configured defaults.
+//
+// The defaults are inserted directly after the command path, e.g. with
+// "compute deploy" = { status-check-off = true } the args
+// `compute deploy --verbose` become `compute deploy --status-check-off --verbose`.
+//
+// An error is returned if the defaults for the invoked command are invalid.
+func ApplyDefaults(args []string, defaults config.CommandDefaults, app *kingpin.ApplicationModel) (result, applied []string, err error) {
+	if len(defaults) == 0 {
+		return args, nil, nil
+	}
+	cmd, end := argparser.InvokedCommand(args, app)
+	if cmd == nil {
+		return args, nil, nil
+	}
+	path := cmd.FullCommand()
+	values, ok := defaults[path]
+	if !ok {
+		return args, nil, nil
+	}
+
+	flags := argparser.CommandFlags(cmd, app)
+	explicit := args
+	if i := slices.Index(args, "--"); i >= 0 {
+		explicit = args[:i]
+	}
+	for _, name := range sortedKeys(values) {
+		flag, err := defaultFlag(path, name, values[name], flags)
+		if err != nil {
+			return nil, nil, fsterr.RemediationError{
+				Inner:       err,
+				Remediation: DefaultsRemediation,
+			}
+		}
+		if flagProvided(explicit, flag) {
+			continue
+		}
+		applied = append(applied, formatDefault(flag, values[name])...)
+	}
+	if len(applied) == 0 {
+		return args, nil, nil
+	}
+
+	result = make([]string, 0, len(args)+len(applied))
+	result = append(result, args[:end]...)
+	result = append(result, applied...)
+	result = append(result, args[end:]...)
+	return result, applied, nil
+}
+
+// ValidateDefaults returns an error describing every [command_defaults] entry
+// that refers to an unknown command or flag, or has a value of the wrong type.
+func ValidateDefaults(defaults config.CommandDefaults, app *kingpin.ApplicationModel) error {
+	var errs []error
+	for _, path := range sortedKeys(defaults) {
+		cmd := argparser.FindCommand(path, app)
+		if cmd == nil {
+			errs = append(errs, fmt.Errorf("unknown command '%s'", path))
+			continue
+		}
+		flags := argparser.CommandFlags(cmd, app)
+		for _, name := range sortedKeys(defaults[path]) {
+			if _, err := defaultFlag(path, name, defaults[path][name], flags); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("invalid [command_defaults] config:\n%w", errors.Join(errs...)),
+		Remediation: DefaultsRemediation,
+	}
+}
+
+// defaultFlag returns the flag named by a [command_defaults] key, validating
+// that the command accepts it and that value is of the right type.
+func defaultFlag(path, name string, value any, flags []*kingpin.ClauseModel) (*kingpin.ClauseModel, error) {
+	i := slices.IndexFunc(flags, func(f *kingpin.ClauseModel) bool { return f.Name == name })
+	if i < 0 {
+		return nil, fmt.Errorf("unknown flag '--%s' for command '%s'", name, path)
+	}
+	flag := flags[i]
+
+	values, repeated := value.([]any)
+	if !repeated {
+		values = []any{value}
+	} else if !flag.Cumulative {
+		return nil, fmt.Errorf("flag '--%s' for command '%s' can't be repeated", name, path)
+	}
+	for _, v := range values {
+		_, isBool := v.(bool)
+		switch {
+		case flag.IsBoolFlag() && !isBool:
+			return nil, fmt.Errorf("flag '--%s' for command '%s' must be true or false", name, path)
+		case !flag.IsBoolFlag() && isBool:
+			return nil, fmt.Errorf("flag '--%s' for command '%s' requires a value, not %t", name, path, v)
+		}
+	}
+	return flag, nil
+}
+
+// flagProvided reports whether flag (or its negation) is present in args.
+func flagProvided(args []string, flag *kingpin.ClauseModel) bool {
+	return slices.ContainsFunc(args, func(a string) bool {
+		return argparser.LookupFlag(a, []*kingpin.ClauseModel{flag}) != nil ||
+			(flag.Short != 0 && !flag.IsBoolFlag() && strings.HasPrefix(a, "-"+string(flag.Short)) && !strings.HasPrefix(a, "--"))
+	})
+}
+
+// formatDefault returns the arguments that set flag to value.
+//
+// NOTE: A false value for a boolean flag that can't be negated adds nothing,
+// as the flag already defaults to false.
+func formatDefault(flag *kingpin.ClauseModel, value any) []string {
+	values, ok := value.([]any)
+	if !ok {
+		values = []any{value}
+	}
+	var args []string
+	for _, v := range values {
+		switch b, isBool := v.(bool); {
+		case isBool && b:
+			args = append(args, "--"+flag.Name)
+		case isBool && flag.IsNegatable():
+			args = append(args, "--no-"+flag.Name)
+		case isBool:
+		default:
+			args = append(args, fmt.Sprintf("--%s=%v", flag.Name, v))
+		}
+	}
+	return args
+}
+
+// sortedKeys returns the keys of m in order, so output is deterministic.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,289 @@
+package config_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/fastly/kingpin"
+
+	"github.com/fastly/cli/pkg/app"
+	configcmd "github.com/fastly/cli/pkg/commands/config"
+	"github.com/fastly/cli/pkg/config"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+// defaultsApp returns the model of a small command tree resembling the CLI.
+func defaultsApp() *kingpin.ApplicationModel {
+	a := kingpin.New("fastly", "")
+	a.Flag("non-interactive", "").Short('i').Bool()
+	a.Flag("token", "").Short('t').String()
+	a.Flag("verbose", "").Short('v').Bool()
+
+	compute := a.Command("compute", "")
+	build := compute.Command("build", "")
+	build.Flag("env", "").String()
+	deploy := compute.Command("deploy", "")
+	deploy.Flag("autoclone", "").Bool()
+	deploy.Flag("comment", "").String()
+	deploy.Flag("status-check-off", "").Bool()
+	deploy.Flag("status-check-timeout", "").Int()
+	deploy.Flag("upload", "").NegatableBool()
+
+	serviceVersion := a.Command("service-version", "")
+	activate := serviceVersion.Command("activate", "")
+	activate.Flag("version", "").String()
+	activate.Flag("header", "").Strings()
+
+	return a.Model()
+}
+
+func TestApplyDefaults(t *testing.T) {
+	for _, testcase := range []struct {
+		name        string
+		args        string
+		defaults    config.CommandDefaults
+		wantArgs    string
+		wantApplied string
+		wantError   string
+	}{
+		{
+			name:     "no defaults",
+			args:     "compute deploy --verbose",
+			wantArgs: "compute deploy --verbose",
+		},
+		{
+			name: "defaults inserted after the command path",
+			args: "compute deploy --verbose",
+			defaults: config.CommandDefaults{
+				"compute deploy": {"autoclone": true, "status-check-timeout": int64(300)},
+			},
+			wantArgs:    "compute deploy --autoclone --status-check-timeout=300 --verbose",
+			wantApplied: "--autoclone --status-check-timeout=300",
+		},
+		{
+			name: "global flags",
+			args: "compute deploy",
+			defaults: config.CommandDefaults{
+				"compute deploy": {"non-interactive": true, "verbose": false},
+			},
+			wantArgs:    "compute deploy --non-interactive",
+			wantApplied: "--non-interactive",
+		},
+		{
+			name: "explicit flags take precedence",
+			args: "compute deploy --status-check-timeout 10 --comment=hello -i",
+			defaults: config.CommandDefaults{
+				"compute deploy": {"comment": "default", "non-interactive": true, "status-check-timeout": int64(300)},
+			},
+			wantArgs: "compute deploy --status-check-timeout 10 --comment=hello -i",
+		},
+		{
+			name: "explicit short flag with attached value takes precedence",
+			args: "compute deploy -tabc",
+			defaults: config.CommandDefaults{
+				"compute deploy": {"token": "123"},
+			},
+			wantArgs: "compute deploy -tabc",
+		},
+		{
+			name: "explicit negated flag takes precedence",
+			args: "compute deploy --no-upload",
+			defaults: config.CommandDefaults{
+				"compute deploy": {"upload": true},
+			},
+			wantArgs: "compute deploy --no-upload",
+		},
+		{
+			name: "negatable flag",
+			args: "compute deploy",
+			defaults: config.CommandDefaults{
+				"compute deploy": {"upload": false},
+			},
+			wantArgs:    "compute deploy --no-upload",
+			wantApplied: "--no-upload",
+		},
+		{
+			name: "global flags and values before the command path",
+			args: "--token 123 -v compute deploy",
+			defaults: config.CommandDefaults{
+				"compute deploy": {"autoclone": true},
+			},
+			wantArgs:    "--token 123 -v compute deploy --autoclone",
+			wantApplied: "--autoclone",
+		},
+		{
+			name: "flags after -- are ignored",
+			args: "compute deploy -- --autoclone",
+			defaults: config.CommandDefaults{
+				"compute deploy": {"autoclone": true},
+			},
+			wantArgs:    "compute deploy --autoclone -- --autoclone",
+			wantApplied: "--autoclone",
+		},
+		{
+			name: "nested command path",
+			args: "service-version activate --version 3",
+			defaults: config.CommandDefaults{
+				"service-version activate": {"header": []any{"a", "b"}},
+			},
+			wantArgs:    "service-version activate --header=a --header=b --version 3",
+			wantApplied: "--header=a --header=b",
+		},
+		{
+			name: "defaults only apply to the exact command path",
+			args: "compute build",
+			defaults: config.CommandDefaults{
+				"compute":        {"verbose": true},
+				"compute deploy": {"autoclone": true},
+			},
+			wantArgs: "compute build",
+		},
+		{
+			name: "parent command",
+			args: "compute",
+			defaults: config.CommandDefaults{
+				"compute": {"verbose": true},
+			},
+			wantArgs:    "compute --verbose",
+			wantApplied: "--verbose",
+		},
+		{
+			name: "unknown flag",
+			args: "compute deploy",
+			defaults: config.CommandDefaults{
+				"compute deploy": {"env": "stage"},
+			},
+			wantError: "unknown flag '--env' for command 'compute deploy'",
+		},
+		{
+			name: "unknown flag for another command is ignored",
+			args: "compute build",
+			defaults: config.CommandDefaults{
+				"compute deploy": {"env": "stage"},
+			},
+			wantArgs: "compute build",
+		},
+		{
+			name: "boolean flag with a value",
+			args: "compute deploy",
+			defaults: config.CommandDefaults{
+				"compute deploy": {"autoclone": "yes"},
+			},
+			wantError: "flag '--autoclone' for command 'compute deploy' must be true or false",
+		},
+		{
+			name: "value flag with a boolean",
+			args: "compute deploy",
+			defaults: config.CommandDefaults{
+				"compute deploy": {"comment": true},
+			},
+			wantError: "flag '--comment' for command 'compute deploy' requires a value, not true",
+		},
+		{
+			name: "repeated flag that can't be repeated",
+			args: "compute deploy",
+			defaults: config.CommandDefaults{
+				"compute deploy": {"comment": []any{"a", "b"}},
+			},
+			wantError: "flag '--comment' for command 'compute deploy' can't be repeated",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			args, applied, err := configcmd.ApplyDefaults(strings.Fields(testcase.args), testcase.defaults, defaultsApp())
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if testcase.wantError != "" {
+				testutil.AssertRemediationErrorContains(t, err, configcmd.DefaultsRemediation)
+				return
+			}
+			testutil.AssertString(t, testcase.wantArgs, strings.Join(args, " "))
+			testutil.AssertString(t, testcase.wantApplied, strings.Join(applied, " "))
+		})
+	}
+}
+
+func TestValidateDefaults(t *testing.T) {
+	testutil.AssertNoError(t, configcmd.ValidateDefaults(config.CommandDefaults{
+		"compute deploy":           {"autoclone": true, "verbose": true},
+		"service-version activate": {"header": []any{"a"}},
+	}, defaultsApp()))
+
+	err := configcmd.ValidateDefaults(config.CommandDefaults{
+		"compute deploy":  {"env": "stage", "autoclone": int64(1)},
+		"compute destroy": {"verbose": true},
+	}, defaultsApp())
+	testutil.AssertErrorContains(t, err, "invalid [command_defaults] config:\nflag '--autoclone' for command 'compute deploy' must be true or false\nunknown flag '--env' for command 'compute deploy'\nunknown command 'compute destroy'")
+	testutil.AssertRemediationErrorContains(t, err, configcmd.DefaultsRemediation)
+}
+
+func TestCommandDefaults(t *testing.T) {
+	args := testutil.Args
+	scenarios := []struct {
+		name           string
+		args           []string
+		defaults       config.CommandDefaults
+		wantError      string
+		wantOutput     []string
+		dontWantOutput []string
+	}{
+		{
+			name:       "config check with a valid section",
+			args:       args("config check"),
+			defaults:   config.CommandDefaults{"compute deploy": {"status-check-timeout": int64(300)}, "backend create": {"autoclone": true}},
+			wantOutput: []string{"SUCCESS: The CLI configuration is valid"},
+		},
+		{
+			name:      "config check with an invalid section",
+			args:      args("config check"),
+			defaults:  config.CommandDefaults{"compute deploy": {"autoclone": true}},
+			wantError: "unknown flag '--autoclone' for command 'compute deploy'",
+		},
+		{
+			name:       "default applied",
+			args:       args("alias list"),
+			defaults:   config.CommandDefaults{"alias list": {"json": true}},
+			wantOutput: []string{`"name": "cb"`},
+		},
+		{
+			name:     "verbose note",
+			args:     args("alias list"),
+			defaults: config.CommandDefaults{"alias list": {"verbose": true}},
+			wantOutput: []string{
+				"INFO: Applied default flags from the [command_defaults] config for 'alias list': --verbose",
+			},
+		},
+		{
+			name:           "no verbose note",
+			args:           args("alias list"),
+			defaults:       config.CommandDefaults{"alias list": {"json": true}},
+			dontWantOutput: []string{"Applied default flags"},
+		},
+		{
+			name:      "invalid defaults rejected",
+			args:      args("alias list"),
+			defaults:  config.CommandDefaults{"alias list": {"jsn": true}},
+			wantError: "unknown flag '--jsn' for command 'alias list'",
+		},
+	}
+
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(testcase.args, &stdout)
+				opts.Config.CommandDefaults = testcase.defaults
+				return opts, nil
+			}
+			err := app.Run(testcase.args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			for _, s := range testcase.dontWantOutput {
+				testutil.AssertStringDoesntContain(t, stdout.String(), s)
+			}
+		})
+	}
+}
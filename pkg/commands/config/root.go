@@ -6,7 +6,7 @@ import (
 	"os"
 
 	"github.com/fastly/cli/pkg/argparser"
-	"github.com/fastly/cli/pkg/config"
+	"github.com/fastly/cli/pkg/env"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/text"
 )
@@ -24,7 +24,7 @@ type RootCommand struct {
 func NewRootCommand(parent argparser.Registerer, g *global.Data) *RootCommand {
 	var c RootCommand
 	c.Globals = g
-	c.CmdClause = parent.Command("config", "Display the Fastly CLI configuration")
+	c.CmdClause = parent.Command("config", "Display the Fastly CLI configuration").OptionalSubcommands()
 	c.CmdClause.Flag("location", "Print the location of the CLI configuration file").Short('l').BoolVar(&c.location)
 	c.CmdClause.Flag("reset", "Reset the config to a version compatible with the current CLI version").Short('r').BoolVar(&c.reset)
 	return &c
@@ -33,7 +33,7 @@ func NewRootCommand(parent argparser.Registerer, g *global.Data) *RootCommand {
 // Exec implements the command interface.
 func (c *RootCommand) Exec(_ io.Reader, out io.Writer) (err error) {
 	if c.reset {
-		if err := c.Globals.Config.UseStatic(config.FilePath); err != nil {
+		if err := c.Globals.Config.UseStatic(c.Globals.ConfigPath); err != nil {
 			return err
 		}
 	}
@@ -51,6 +51,19 @@ func (c *RootCommand) Exec(_ io.Reader, out io.Writer) (err error) {
 		c.Globals.ErrLog.Add(err)
 		return err
 	}
+	// NOTE: The location is a TOML comment so the output remains a valid config.
+	fmt.Fprintf(out, "# %s (%s)\n", c.Globals.ConfigPath, c.configSource())
 	fmt.Fprintln(out, string(data))
 	return nil
 }
+
+// configSource describes where the config file location came from.
+func (c *RootCommand) configSource() string {
+	switch {
+	case c.Globals.Flags.ConfigFile != "":
+		return "via --config-file"
+	case c.Globals.Env.ConfigFile != "":
+		return "via " + env.ConfigFile
+	}
+	return "default location"
+}
@@ -276,7 +276,24 @@ func TestListStoresCommand(t *testing.T) {
 					return nil, nil
 				},
 			},
-			WantOutput: fmtStores(nil),
+			WantOutput: "INFO: No config stores found. Run `fastly config-store create --name <NAME>`.\n",
+		},
+		{
+			Args: testutil.Args(configstore.RootName + " list --json"),
+			API: mock.API{
+				ListConfigStoresFn: func(i *fastly.ListConfigStoresInput) ([]*fastly.ConfigStore, error) {
+					return nil, nil
+				},
+			},
+			WantOutput: "[]\n",
+		},
+		{
+			Args: testutil.Args(configstore.RootName + " list --quiet"),
+			API: mock.API{
+				ListConfigStoresFn: func(i *fastly.ListConfigStoresInput) ([]*fastly.ConfigStore, error) {
+					return nil, nil
+				},
+			},
 		},
 		{
 			Args: testutil.Args(configstore.RootName + " list"),
@@ -8,6 +8,7 @@ import (
 	"github.com/fastly/cli/pkg/argparser"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/naming"
 	"github.com/fastly/cli/pkg/text"
 )
 
@@ -49,6 +50,10 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 		return fsterr.ErrInvalidVerboseJSONCombo
 	}
 
+	if err := c.ValidateName(naming.ConfigStore, c.input.Name); err != nil {
+		return err
+	}
+
 	o, err := c.Globals.APIClient.CreateConfigStore(&c.input)
 	if err != nil {
 		c.Globals.ErrLog.Add(err)
@@ -59,6 +64,6 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
-	text.Success(out, "Created Config Store '%s' (%s)", o.Name, o.StoreID)
+	text.Result(out, o.StoreID, "Created Config Store '%s' (%s)", o.Name, o.StoreID)
 	return nil
 }
@@ -20,9 +20,10 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 	}
 
 	c.CmdClause = parent.Command("list", "List config stores")
+	c.EmptyState = argparser.EmptyState{Resource: "config stores", Create: "fastly config-store create --name <NAME>"}
 
 	// Optional.
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 
 	return &c
 }
@@ -31,6 +32,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 }
 
 // Exec invokes the application logic for the command.
@@ -49,6 +51,10 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
+	if c.PrintEmpty(out, len(o)) {
+		return nil
+	}
+
 	text.PrintConfigStoresTbl(out, o)
 
 	return nil
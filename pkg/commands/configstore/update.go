@@ -8,6 +8,7 @@ import (
 	"github.com/fastly/cli/pkg/argparser"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/naming"
 	"github.com/fastly/cli/pkg/text"
 )
 
@@ -50,6 +51,10 @@ func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
 		return fsterr.ErrInvalidVerboseJSONCombo
 	}
 
+	if err := c.ValidateName(naming.ConfigStore, c.input.Name); err != nil {
+		return err
+	}
+
 	o, err := c.Globals.APIClient.UpdateConfigStore(&c.input)
 	if err != nil {
 		c.Globals.ErrLog.Add(err)
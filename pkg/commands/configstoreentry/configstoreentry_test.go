@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -430,6 +433,111 @@ func TestUpdateEntryCommand(t *testing.T) {
 	}
 }
 
+func TestSyncEntriesCommand(t *testing.T) {
+	const storeID = "store-id-123"
+
+	file := filepath.Join(t.TempDir(), "items.json")
+	if err := os.WriteFile(file, []byte(`{"a": "1", "b": "changed", "c": "3"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	remote := []*fastly.ConfigStoreItem{
+		{StoreID: storeID, Key: "b", Value: "2"},
+		{StoreID: storeID, Key: "c", Value: "3"},
+		{StoreID: storeID, Key: "d", Value: "4"},
+	}
+
+	var batches [][]*fastly.BatchConfigStoreItem
+	api := mock.API{
+		ListConfigStoreItemsFn: func(i *fastly.ListConfigStoreItemsInput) ([]*fastly.ConfigStoreItem, error) {
+			return remote, nil
+		},
+		BatchModifyConfigStoreItemsFn: func(i *fastly.BatchModifyConfigStoreItemsInput) error {
+			batches = append(batches, i.Items)
+			return nil
+		},
+	}
+
+	for _, testcase := range []struct {
+		name        string
+		args        string
+		file        string
+		stdin       string
+		wantError   string
+		wantOutputs []string
+		wantBatch   []*fastly.BatchConfigStoreItem
+	}{
+		{
+			name:        "dry run",
+			args:        "--dry-run",
+			wantOutputs: []string{"create     a", "update     b", "delete     d", "Plan: 1 to create, 1 to update, 1 to delete."},
+		},
+		{
+			name:        "refuse to delete without confirmation",
+			stdin:       "n\n",
+			wantError:   "deleting keys was not confirmed",
+			wantOutputs: []string{"1 key(s) will be permanently deleted"},
+		},
+		{
+			name:        "confirmed",
+			stdin:       "y\n",
+			wantOutputs: []string{"SUCCESS: Synchronised Config Store 'store-id-123': 1 created, 1 updated, 1 deleted"},
+			wantBatch: []*fastly.BatchConfigStoreItem{
+				{ItemKey: "a", ItemValue: "1", Operation: fastly.CreateBatchOperation},
+				{ItemKey: "b", ItemValue: "changed", Operation: fastly.UpdateBatchOperation},
+				{ItemKey: "d", Operation: fastly.DeleteBatchOperation},
+			},
+		},
+		{
+			name:        "auto-yes",
+			args:        "--auto-yes",
+			wantOutputs: []string{"SUCCESS: Synchronised Config Store"},
+			wantBatch: []*fastly.BatchConfigStoreItem{
+				{ItemKey: "a", ItemValue: "1", Operation: fastly.CreateBatchOperation},
+				{ItemKey: "b", ItemValue: "changed", Operation: fastly.UpdateBatchOperation},
+				{ItemKey: "d", Operation: fastly.DeleteBatchOperation},
+			},
+		},
+		{
+			name:        "file from stdin",
+			args:        "--auto-yes",
+			file:        "-",
+			stdin:       `{"b": "2", "c": "3", "d": "4", "e": "5"}`,
+			wantOutputs: []string{"SUCCESS: Synchronised Config Store 'store-id-123': 1 created, 0 updated, 0 deleted"},
+			wantBatch: []*fastly.BatchConfigStoreItem{
+				{ItemKey: "e", ItemValue: "5", Operation: fastly.CreateBatchOperation},
+			},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			batches = nil
+			file := file
+			if testcase.file != "" {
+				file = testcase.file
+			}
+			args := testutil.Args(strings.TrimSpace(fmt.Sprintf("%s sync --store-id %s --file %s %s", configstoreentry.RootName, storeID, file, testcase.args)))
+
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIClientFactory = mock.APIClient(api)
+				opts.Input = strings.NewReader(testcase.stdin)
+				return opts, nil
+			}
+			err := app.Run(args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			if testcase.wantBatch == nil {
+				testutil.AssertEqual(t, 0, len(batches))
+				return
+			}
+			testutil.AssertEqual(t, [][]*fastly.BatchConfigStoreItem{testcase.wantBatch}, batches)
+		})
+	}
+}
+
 func printConfigStoreItem(i *fastly.ConfigStoreItem) string {
 	var b bytes.Buffer
 	text.PrintConfigStoreItem(&b, "", i)
@@ -20,12 +20,13 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 	}
 
 	c.CmdClause = parent.Command("list", "List config store items")
+	c.EmptyState = argparser.EmptyState{Resource: "config store entries", Create: "fastly config-store-entry create --store-id <STORE_ID> --key <KEY> --value <VALUE>"}
 
 	// Required.
 	c.RegisterFlag(argparser.StoreIDFlag(&c.input.StoreID)) // --store-id
 
 	// Optional.
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 
 	return &c
 }
@@ -34,6 +35,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 	input fastly.ListConfigStoreItemsInput
 }
 
@@ -53,6 +55,10 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
+	if c.PrintEmpty(out, len(o)) {
+		return nil
+	}
+
 	text.PrintConfigStoreItemsTbl(out, o)
 
 	return nil
@@ -0,0 +1,115 @@
+package configstoreentry
+
+import (
+	"io"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/itemsync"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// NewSyncCommand returns a usable command registered under the parent.
+func NewSyncCommand(parent argparser.Registerer, g *global.Data) *SyncCommand {
+	c := SyncCommand{
+		Base: argparser.Base{
+			Globals: g,
+		},
+	}
+
+	c.CmdClause = parent.Command("sync", "Synchronise the items of a config store with a local JSON or CSV file")
+
+	// Required.
+	c.RegisterFlag(argparser.StoreIDFlag(&c.storeID)) // --store-id
+	c.CmdClause.Flag("file", "Path to a JSON object or two-column CSV file of key/value pairs ('-' for stdin)").Required().Action(argparser.StdinPath(&c.file)).StringVar(&c.file)
+
+	// Optional.
+	c.CmdClause.Flag("dry-run", "Display the changes without applying them").BoolVar(&c.dryRun)
+	c.massDeletion = itemsync.NewMassDeletionFlags(c.CmdClause, "item")
+
+	return &c
+}
+
+// SyncCommand calls the Fastly API to synchronise config store items with a
+// local file.
+type SyncCommand struct {
+	argparser.Base
+
+	dryRun       bool
+	file         string
+	massDeletion *itemsync.MassDeletionFlags
+	storeID      string
+}
+
+// Exec invokes the application logic for the command.
+func (c *SyncCommand) Exec(in io.Reader, out io.Writer) error {
+	local, err := itemsync.ReadFile(c.file, in)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	// NOTE: The Config Store returns ALL items (there is no pagination).
+	items, err := c.Globals.APIClient.ListConfigStoreItems(&fastly.ListConfigStoreItemsInput{
+		StoreID: c.storeID,
+	})
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	remote := make(map[string]string, len(items))
+	for _, item := range items {
+		remote[item.Key] = item.Value
+	}
+
+	plan := itemsync.Diff(local, remote)
+	if plan.Empty() {
+		text.Info(out, "Config Store '%s' is already in sync with %s", c.storeID, c.file)
+		return nil
+	}
+
+	plan.Print(out)
+	if c.dryRun {
+		return nil
+	}
+	if err := c.massDeletion.Check(plan.Delete, len(remote), c.Globals.Config.CLI.MassDeletionThreshold); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	// NOTE: The file may have been read from stdin, so we can't prompt.
+	prompt := in
+	if c.file == "-" {
+		prompt = nil
+	}
+	if err := plan.ConfirmDeletes(out, prompt, c.Globals.Flags.AutoYes || c.Globals.Flags.NonInteractive); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	for _, batch := range plan.Ops(fastly.BatchModifyMaximumOperations) {
+		input := fastly.BatchModifyConfigStoreItemsInput{
+			StoreID: c.storeID,
+		}
+		for _, op := range batch {
+			input.Items = append(input.Items, &fastly.BatchConfigStoreItem{
+				ItemKey:   op.Key,
+				ItemValue: op.Value,
+				Operation: op.Operation,
+			})
+		}
+		if err := c.Globals.APIClient.BatchModifyConfigStoreItems(&input); err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Store ID": c.storeID,
+			})
+			return err
+		}
+	}
+
+	text.Break(out)
+	text.Success(out, "Synchronised Config Store '%s': %d created, %d updated, %d deleted", c.storeID, len(plan.Create), len(plan.Update), len(plan.Delete))
+	return nil
+}
@@ -0,0 +1,70 @@
+package deprecations_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/deprecations"
+	"github.com/fastly/cli/pkg/config"
+	"github.com/fastly/cli/pkg/deprecation"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+const dictionariesManifest = `manifest_version = 3
+name = "example"
+
+[setup.dictionaries.example]
+`
+
+func TestDeprecations(t *testing.T) {
+	rootdir := testutil.NewEnv(testutil.EnvOpts{
+		T:     t,
+		Write: []testutil.FileIO{{Src: dictionariesManifest, Dst: manifest.Filename}},
+	})
+	defer os.RemoveAll(rootdir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(rootdir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.Chdir(wd)
+	}()
+
+	// NOTE: The report is displayed in strict mode, as it lists the items used.
+	args := testutil.Args("deprecations --json --strict")
+	var stdout, stderr bytes.Buffer
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		opts := testutil.MockGlobalData(args, &stdout)
+		opts.Config.CommandDefaults = config.CommandDefaults{
+			"backend update": {"ssl-check-cert": true},
+		}
+		opts.ErrOutput = &stderr
+		return opts, nil
+	}
+	testutil.AssertNoError(t, app.Run(args, nil))
+	testutil.AssertStringContains(t, stderr.String(), "The `setup.dictionaries` key of the fastly.toml manifest is deprecated")
+
+	var items []deprecations.Item
+	if err := json.Unmarshal(stdout.Bytes(), &items); err != nil {
+		t.Fatal(err)
+	}
+	usedBy := make(map[string]string)
+	for _, i := range items {
+		usedBy[i.Command+" "+i.Name] = i.UsedBy
+	}
+	testutil.AssertEqual(t, map[string]string{
+		"backend create ssl-check-cert": "",
+		"backend update ssl-check-cert": deprecations.UsedByConfig,
+		" setup.dictionaries":           deprecations.UsedByManifest,
+	}, usedBy)
+	testutil.AssertEqual(t, len(deprecation.Items()), len(items))
+}
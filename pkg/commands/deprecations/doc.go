@@ -0,0 +1,3 @@
+// Package deprecations contains a hidden command that reports the deprecated
+// flags, manifest keys and env vars, and which of them are in use.
+package deprecations
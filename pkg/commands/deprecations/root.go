@@ -0,0 +1,132 @@
+package deprecations
+
+import (
+	"io"
+	"strings"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/deprecation"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// Where a deprecated item is used.
+const (
+	UsedByArgs     = "command line"
+	UsedByConfig   = "config file"
+	UsedByEnv      = "environment"
+	UsedByManifest = "fastly.toml"
+)
+
+// Item is a deprecated item and where it's used (if anywhere).
+type Item struct {
+	Kind        deprecation.Kind `json:"kind"`
+	Name        string           `json:"name"`
+	Command     string           `json:"command,omitempty"`
+	Replacement string           `json:"replacement,omitempty"`
+	RemovedIn   string           `json:"removed_in,omitempty"`
+	UsedBy      string           `json:"used_by,omitempty"`
+}
+
+// RootCommand reports every deprecated item.
+type RootCommand struct {
+	argparser.Base
+	argparser.JSONOutput
+}
+
+// NewRootCommand returns a new command registered in the parent.
+func NewRootCommand(parent argparser.Registerer, g *global.Data) *RootCommand {
+	var c RootCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("deprecations", "List the deprecated flags, manifest keys and environment variables, and whether the current project or config uses them").Hidden()
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	items := c.items()
+	if ok, err := c.WriteJSON(out, items); ok {
+		return err
+	}
+
+	t := text.NewTable(out)
+	t.AddHeader("KIND", "NAME", "COMMAND", "REPLACEMENT", "REMOVED IN", "USED BY")
+	var used int
+	for _, i := range items {
+		t.AddLine(i.Kind, i.Name, i.Command, i.Replacement, i.RemovedIn, i.UsedBy)
+		if i.UsedBy != "" {
+			used++
+		}
+	}
+	t.Print()
+	if used > 0 {
+		text.Break(out)
+		text.Warning(out, "%d deprecated items are used by the current project or config. Run the CLI with --strict to fail when they're used.", used)
+	}
+	return nil
+}
+
+// items returns every deprecated item and where it's used.
+//
+// The manifest keys and env vars used are those recorded when the manifest and
+// environment were read. Flags are used if they're set by the config file's
+// [command_defaults] or an alias.
+func (c *RootCommand) items() []Item {
+	all := deprecation.Items()
+	items := make([]Item, 0, len(all))
+	for _, i := range all {
+		items = append(items, Item{
+			Kind:        i.Kind,
+			Name:        i.Name,
+			Command:     i.Command,
+			Replacement: i.Replacement,
+			RemovedIn:   i.RemovedIn,
+			UsedBy:      c.usedBy(i),
+		})
+	}
+	return items
+}
+
+// usedBy returns where the item is used, or an empty string.
+func (c *RootCommand) usedBy(i deprecation.Item) string {
+	if c.Globals.Deprecations.IsUsed(i) {
+		switch i.Kind {
+		case deprecation.KindEnvVar:
+			return UsedByEnv
+		case deprecation.KindManifestKey:
+			return UsedByManifest
+		default:
+			return UsedByArgs
+		}
+	}
+	if i.Kind != deprecation.KindFlag {
+		return ""
+	}
+	cfg := c.Globals.Config
+	if _, ok := cfg.CommandDefaults[i.Command][i.Name]; ok {
+		return UsedByConfig
+	}
+	for _, expansion := range cfg.Aliases {
+		args := strings.Fields(expansion)
+		if (i.Command == "" || strings.HasPrefix(expansion, i.Command+" ")) && flagSet(args, i.Name) {
+			return UsedByConfig
+		}
+	}
+	return ""
+}
+
+// flagSet indicates whether the flag is in args.
+func flagSet(args []string, name string) bool {
+	for _, arg := range args {
+		if arg == "--"+name || strings.HasPrefix(arg, "--"+name+"=") {
+			return true
+		}
+	}
+	return false
+}
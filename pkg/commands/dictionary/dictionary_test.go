@@ -209,6 +209,29 @@ func TestListDictionary(t *testing.T) {
 			},
 			wantOutput: listDictionariesOutput,
 		},
+		{
+			args: args("dictionary list --version 1 --service-id 123"),
+			api: mock.API{
+				ListVersionsFn:     testutil.ListVersions,
+				ListDictionariesFn: listDictionariesEmpty,
+			},
+			wantOutput: "INFO: No dictionaries found. Run `fastly dictionary create --version <VERSION> --name <NAME>`.\n",
+		},
+		{
+			args: args("dictionary list --version 1 --service-id 123 --json"),
+			api: mock.API{
+				ListVersionsFn:     testutil.ListVersions,
+				ListDictionariesFn: listDictionariesEmpty,
+			},
+			wantOutput: "[]\n",
+		},
+		{
+			args: args("dictionary list --version 1 --service-id 123 --quiet"),
+			api: mock.API{
+				ListVersionsFn:     testutil.ListVersions,
+				ListDictionariesFn: listDictionariesEmpty,
+			},
+		},
 	}
 	for testcaseIdx := range scenarios {
 		testcase := &scenarios[testcaseIdx]
@@ -433,6 +456,10 @@ func listDictionariesOk(i *fastly.ListDictionariesInput) ([]*fastly.Dictionary,
 	}, nil
 }
 
+func listDictionariesEmpty(_ *fastly.ListDictionariesInput) ([]*fastly.Dictionary, error) {
+	return nil, nil
+}
+
 func updateDictionaryNameOK(i *fastly.UpdateDictionaryInput) (*fastly.Dictionary, error) {
 	return &fastly.Dictionary{
 		ServiceID:      fastly.ToPointer(i.ServiceID),
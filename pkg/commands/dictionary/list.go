@@ -16,6 +16,7 @@ import (
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 
 	Input          fastly.ListDictionariesInput
 	serviceName    argparser.OptionalServiceNameID
@@ -30,6 +31,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 		},
 	}
 	c.CmdClause = parent.Command("list", "List all dictionaries on a Fastly service version")
+	c.EmptyState = argparser.EmptyState{Resource: "dictionaries", Create: "fastly dictionary create --version <VERSION> --name <NAME>"}
 
 	// Required.
 	c.RegisterFlag(argparser.StringFlagOpts{
@@ -40,7 +42,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 	})
 
 	// Optional.
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	c.RegisterFlag(argparser.StringFlagOpts{
 		Name:        argparser.FlagServiceIDName,
 		Description: argparser.FlagServiceIDDesc,
@@ -95,6 +97,10 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
+	if c.PrintEmpty(out, len(o)) {
+		return nil
+	}
+
 	if !c.Globals.Verbose() {
 		fmt.Fprintf(out, "\nService ID: %s\n", serviceID)
 	}
@@ -10,10 +10,18 @@ import (
 	"github.com/fastly/cli/pkg/text"
 )
 
+// itemFieldFlags maps the API fields of a dictionary item to the flags that
+// supply them.
+var itemFieldFlags = map[string]string{
+	"item_key":   "key",
+	"item_value": "value",
+}
+
 // CreateCommand calls the Fastly API to create a dictionary item.
 type CreateCommand struct {
 	argparser.Base
 	Input              fastly.CreateDictionaryItemInput
+	ignoreLimits       bool
 	itemKey, itemValue string
 	serviceName        argparser.OptionalServiceNameID
 }
@@ -33,6 +41,7 @@ func NewCreateCommand(parent argparser.Registerer, g *global.Data) *CreateComman
 	c.CmdClause.Flag("value", "Dictionary item value").Required().StringVar(&c.itemValue)
 
 	// Optional.
+	c.CmdClause.Flag("ignore-limits", ignoreLimitsDesc).BoolVar(&c.ignoreLimits)
 	c.RegisterFlag(argparser.StringFlagOpts{
 		Name:        argparser.FlagServiceIDName,
 		Description: argparser.FlagServiceIDDesc,
@@ -48,6 +57,11 @@ func NewCreateCommand(parent argparser.Registerer, g *global.Data) *CreateComman
 	return &c
 }
 
+// FieldFlags implements the argparser.FieldFlagger interface.
+func (c *CreateCommand) FieldFlags() map[string]string {
+	return itemFieldFlags
+}
+
 // Exec invokes the application logic for the command.
 func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 	serviceID, source, flag, err := argparser.ServiceID(c.serviceName, *c.Globals.Manifest, c.Globals.APIClient, c.Globals.ErrLog)
@@ -58,6 +72,16 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 		argparser.DisplayServiceID(serviceID, flag, source, out)
 	}
 
+	existing, err := existingKeys(c.Globals, serviceID, c.Input.DictionaryID, c.ignoreLimits)
+	if err != nil {
+		return err
+	}
+	err = Preflight(existing, []Write{{Key: c.itemKey, Value: c.itemValue}}, c.ignoreLimits)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
 	c.Input.ItemKey = &c.itemKey
 	c.Input.ItemValue = &c.itemValue
 	c.Input.ServiceID = serviceID
@@ -7,6 +7,7 @@ import (
 
 	"github.com/fastly/cli/pkg/argparser"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/itemsync"
 	"github.com/fastly/cli/pkg/text"
 )
 
@@ -14,6 +15,8 @@ import (
 type DeleteCommand struct {
 	argparser.Base
 	Input       fastly.DeleteDictionaryItemInput
+	key         argparser.OptionalString
+	pattern     *itemsync.PatternFlags
 	serviceName argparser.OptionalServiceNameID
 }
 
@@ -28,9 +31,10 @@ func NewDeleteCommand(parent argparser.Registerer, g *global.Data) *DeleteComman
 
 	// Required.
 	c.CmdClause.Flag("dictionary-id", "Dictionary ID").Required().StringVar(&c.Input.DictionaryID)
-	c.CmdClause.Flag("key", "Dictionary item key").Required().StringVar(&c.Input.ItemKey)
 
 	// Optional.
+	c.CmdClause.Flag("key", "Dictionary item key").Action(c.key.Set).StringVar(&c.key.Value)
+	c.pattern = itemsync.NewPatternFlags(c.CmdClause, "key", "item", "temp:*")
 	c.RegisterFlag(argparser.StringFlagOpts{
 		Name:        argparser.FlagServiceIDName,
 		Description: argparser.FlagServiceIDDesc,
@@ -47,7 +51,11 @@ func NewDeleteCommand(parent argparser.Registerer, g *global.Data) *DeleteComman
 }
 
 // Exec invokes the application logic for the command.
-func (c *DeleteCommand) Exec(_ io.Reader, out io.Writer) error {
+func (c *DeleteCommand) Exec(in io.Reader, out io.Writer) error {
+	if err := c.pattern.Validate("--key", c.key.WasSet); err != nil {
+		return err
+	}
+
 	serviceID, source, flag, err := argparser.ServiceID(c.serviceName, *c.Globals.Manifest, c.Globals.APIClient, c.Globals.ErrLog)
 	if err != nil {
 		return err
@@ -56,6 +64,11 @@ func (c *DeleteCommand) Exec(_ io.Reader, out io.Writer) error {
 		argparser.DisplayServiceID(serviceID, flag, source, out)
 	}
 
+	if c.pattern.WasSet() {
+		return c.deleteMatching(in, out, serviceID)
+	}
+
+	c.Input.ItemKey = c.key.Value
 	c.Input.ServiceID = serviceID
 	err = c.Globals.APIClient.DeleteDictionaryItem(&c.Input)
 	if err != nil {
@@ -68,3 +81,73 @@ func (c *DeleteCommand) Exec(_ io.Reader, out io.Writer) error {
 	text.Success(out, "Deleted dictionary item %s (service %s, dictionary %s)", c.Input.ItemKey, c.Input.ServiceID, c.Input.DictionaryID)
 	return nil
 }
+
+// deleteMatching deletes every dictionary item whose key matches the pattern,
+// using batch requests.
+func (c *DeleteCommand) deleteMatching(in io.Reader, out io.Writer, serviceID string) error {
+	p, err := c.pattern.Pattern()
+	if err != nil {
+		return err
+	}
+
+	items, err := c.Globals.APIClient.ListDictionaryItems(&fastly.ListDictionaryItemsInput{
+		DictionaryID: c.Input.DictionaryID,
+		ServiceID:    serviceID,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Dictionary ID": c.Input.DictionaryID,
+			"Service ID":    serviceID,
+		})
+		return err
+	}
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		keys = append(keys, fastly.ToValue(item.ItemKey))
+	}
+
+	matches := p.Filter(keys)
+	c.pattern.PrintMatches(out, p, matches)
+	if len(matches) == 0 {
+		return nil
+	}
+	if c.pattern.DryRun {
+		text.Break(out)
+		text.Info(out, "Dry run: no items were deleted from dictionary %s", c.Input.DictionaryID)
+		return nil
+	}
+	if err := c.pattern.ConfirmMatches(out, in, len(matches), c.Globals.Flags.AutoYes || c.Globals.Flags.NonInteractive); err != nil {
+		return err
+	}
+
+	result, err := itemsync.DeleteBatches(out, matches, fastly.BatchModifyMaximumOperations, func(batch []string) []string {
+		input := fastly.BatchModifyDictionaryItemsInput{
+			DictionaryID: c.Input.DictionaryID,
+			ServiceID:    serviceID,
+		}
+		for _, k := range batch {
+			input.Items = append(input.Items, &fastly.BatchDictionaryItem{
+				ItemKey:   fastly.ToPointer(k),
+				Operation: fastly.ToPointer(fastly.DeleteBatchOperation),
+			})
+		}
+		if err := c.Globals.APIClient.BatchModifyDictionaryItems(&input); err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Dictionary ID": c.Input.DictionaryID,
+				"Service ID":    serviceID,
+			})
+			return batch
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := result.Err(); err != nil {
+		return err
+	}
+
+	text.Break(out)
+	text.Success(out, "Deleted %d dictionary items matching '%s' (service %s, dictionary %s)", len(result.Deleted), p, serviceID, c.Input.DictionaryID)
+	return nil
+}
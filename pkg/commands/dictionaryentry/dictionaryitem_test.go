@@ -3,6 +3,7 @@ package dictionaryentry_test
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"os"
@@ -160,7 +161,22 @@ func TestDictionaryItemCreate(t *testing.T) {
 			wantError: "error parsing arguments: required flag ",
 		},
 		{
-			args:       args("dictionary-entry create --service-id 123 --dictionary-id 456 --key foo --value bar"),
+			args: args("dictionary-entry create --service-id 123 --dictionary-id 456 --key foo --value bar"),
+			api: mock.API{
+				CreateDictionaryItemFn: createDictionaryItemOK,
+				ListDictionaryItemsFn:  listDictionaryItemsOK(0),
+			},
+			wantOutput: "SUCCESS: Created dictionary item foo (service 123, dictionary 456)\n",
+		},
+		// NOTE: CreateDictionaryItemFn is nil, so the test panics if the item is
+		// sent to the API.
+		{
+			args:      args("dictionary-entry create --service-id 123 --dictionary-id 456 --key foo --value bar"),
+			api:       mock.API{ListDictionaryItemsFn: listDictionaryItemsOK(1000)},
+			wantError: "the dictionary would hold 1001 items (currently 1000), the limit is 1000",
+		},
+		{
+			args:       args("dictionary-entry create --service-id 123 --dictionary-id 456 --key foo --value bar --ignore-limits"),
 			api:        mock.API{CreateDictionaryItemFn: createDictionaryItemOK},
 			wantOutput: "SUCCESS: Created dictionary item foo (service 123, dictionary 456)\n",
 		},
@@ -201,8 +217,11 @@ func TestDictionaryItemUpdate(t *testing.T) {
 			wantError: "an empty value is not allowed for either the '--key' or '--value' flags",
 		},
 		{
-			args:       args("dictionary-entry update --service-id 123 --dictionary-id 456 --key foo --value bar"),
-			api:        mock.API{UpdateDictionaryItemFn: updateDictionaryItemOK},
+			args: args("dictionary-entry update --service-id 123 --dictionary-id 456 --key foo --value bar"),
+			api: mock.API{
+				ListDictionaryItemsFn:  listDictionaryItemsOK(0),
+				UpdateDictionaryItemFn: updateDictionaryItemOK,
+			},
 			wantOutput: updateDictionaryItemOutput,
 		},
 		{
@@ -219,17 +238,29 @@ func TestDictionaryItemUpdate(t *testing.T) {
 			wantError: "open missingPath:",
 		},
 		{
-			args:      args("dictionary-entry update --service-id 123 --dictionary-id 456 --file filePath"),
-			fileData:  dictionaryItemBatchModifyInputOK,
-			api:       mock.API{BatchModifyDictionaryItemsFn: batchModifyDictionaryItemsError},
+			args:     args("dictionary-entry update --service-id 123 --dictionary-id 456 --file filePath"),
+			fileData: dictionaryItemBatchModifyInputOK,
+			api: mock.API{
+				BatchModifyDictionaryItemsFn: batchModifyDictionaryItemsError,
+				ListDictionaryItemsFn:        listDictionaryItemsOK(0),
+			},
 			wantError: errTest.Error(),
 		},
 		{
-			args:       args("dictionary-entry update --service-id 123 --dictionary-id 456 --file filePath"),
-			fileData:   dictionaryItemBatchModifyInputOK,
-			api:        mock.API{BatchModifyDictionaryItemsFn: batchModifyDictionaryItemsOK},
+			args:     args("dictionary-entry update --service-id 123 --dictionary-id 456 --file filePath"),
+			fileData: dictionaryItemBatchModifyInputOK,
+			api: mock.API{
+				BatchModifyDictionaryItemsFn: batchModifyDictionaryItemsOK,
+				ListDictionaryItemsFn:        listDictionaryItemsOK(0),
+			},
 			wantOutput: "SUCCESS: Made 4 modifications of Dictionary 456 on service 123\n",
 		},
+		{
+			args:      args("dictionary-entry update --service-id 123 --dictionary-id 456 --file filePath"),
+			fileData:  dictionaryItemBatchModifyInputInvalid,
+			api:       mock.API{ListDictionaryItemsFn: listDictionaryItemsOK(0)},
+			wantError: "2 problems found:",
+		},
 	}
 	for testcaseIdx := range scenarios {
 		testcase := &scenarios[testcaseIdx]
@@ -276,7 +307,7 @@ func TestDictionaryItemDelete(t *testing.T) {
 		{
 			args:      args("dictionary-entry delete --service-id 123 --dictionary-id 456"),
 			api:       mock.API{DeleteDictionaryItemFn: deleteDictionaryItemOK},
-			wantError: "error parsing arguments: required flag ",
+			wantError: "invalid command, one of --key, --key-glob or --key-regex is required",
 		},
 		{
 			args:       args("dictionary-entry delete --service-id 123 --dictionary-id 456 --key foo"),
@@ -300,6 +331,138 @@ func TestDictionaryItemDelete(t *testing.T) {
 	}
 }
 
+func TestDictionaryItemDeletePattern(t *testing.T) {
+	args := testutil.Args
+	var batches []int
+	countBatches := func(i *fastly.BatchModifyDictionaryItemsInput) error {
+		for _, item := range i.Items {
+			if fastly.ToValue(item.Operation) != fastly.DeleteBatchOperation {
+				t.Errorf("want a delete operation, have %s", fastly.ToValue(item.Operation))
+			}
+		}
+		batches = append(batches, len(i.Items))
+		return nil
+	}
+	noBatches := func(_ *fastly.BatchModifyDictionaryItemsInput) error {
+		t.Error("unexpected batch request")
+		return nil
+	}
+
+	scenarios := []struct {
+		name            string
+		args            []string
+		stdin           string
+		api             mock.API
+		wantBatches     []int
+		wantError       string
+		wantOutputs     []string
+		dontWantOutputs []string
+	}{
+		{
+			name:      "validate --key and a pattern can't be combined",
+			args:      args("dictionary-entry delete --service-id 123 --dictionary-id 456 --key foo --key-glob 'temp:*'"),
+			wantError: "invalid flag combination, --key and --key-glob or --key-regex",
+		},
+		{
+			name:      "validate --dry-run requires a pattern",
+			args:      args("dictionary-entry delete --service-id 123 --dictionary-id 456 --key foo --dry-run"),
+			wantError: "--dry-run and --match-all require --key-glob or --key-regex",
+		},
+		{
+			name:      "validate an invalid glob",
+			args:      args("dictionary-entry delete --service-id 123 --dictionary-id 456 --key-glob key-[1"),
+			wantError: "invalid glob 'key-[1': unterminated '['",
+		},
+		{
+			name:      "validate a glob matching every key requires --match-all",
+			args:      args("dictionary-entry delete --service-id 123 --dictionary-id 456 --key-glob *"),
+			api:       mock.API{ListDictionaryItemsFn: listDictionaryItemsOK(3), BatchModifyDictionaryItemsFn: noBatches},
+			wantError: "the pattern '*' matches every item",
+		},
+		{
+			name:      "validate a regex matching every key requires --match-all",
+			args:      args("dictionary-entry delete --service-id 123 --dictionary-id 456 --key-regex .*"),
+			api:       mock.API{ListDictionaryItemsFn: listDictionaryItemsOK(3), BatchModifyDictionaryItemsFn: noBatches},
+			wantError: "the pattern '.*' matches every item",
+		},
+		{
+			name:        "validate --match-all allows a pattern matching every key",
+			args:        args("dictionary-entry delete --service-id 123 --dictionary-id 456 --key-glob * --match-all --dry-run"),
+			api:         mock.API{ListDictionaryItemsFn: listDictionaryItemsOK(3), BatchModifyDictionaryItemsFn: noBatches},
+			wantOutputs: []string{"3 items match '*'.", "key-0\n", "key-2\n"},
+		},
+		{
+			name: "validate dry run lists every match without deleting",
+			args: args("dictionary-entry delete --service-id 123 --dictionary-id 456 --key-glob key-1? --dry-run"),
+			api:  mock.API{ListDictionaryItemsFn: listDictionaryItemsOK(20), BatchModifyDictionaryItemsFn: noBatches},
+			wantOutputs: []string{
+				"10 items match 'key-1?'.",
+				"  key-10\n",
+				"  key-19\n",
+				"Dry run: no items were deleted from dictionary 456",
+			},
+			dontWantOutputs: []string{"  key-1\n", "more"},
+		},
+		{
+			name:        "validate no matches",
+			args:        args("dictionary-entry delete --service-id 123 --dictionary-id 456 --key-regex ^nope"),
+			api:         mock.API{ListDictionaryItemsFn: listDictionaryItemsOK(20), BatchModifyDictionaryItemsFn: noBatches},
+			wantOutputs: []string{"0 items match '^nope'."},
+		},
+		{
+			name:      "validate the deletion must be confirmed",
+			args:      args("dictionary-entry delete --service-id 123 --dictionary-id 456 --key-glob key-1*"),
+			stdin:     "n\n",
+			api:       mock.API{ListDictionaryItemsFn: listDictionaryItemsOK(20), BatchModifyDictionaryItemsFn: noBatches},
+			wantError: "deleting the matches was not confirmed",
+			wantOutputs: []string{
+				"11 items match 'key-1*'.",
+				"  ... and 1 more",
+				"11 items will be permanently deleted.",
+			},
+		},
+		{
+			name:        "validate matches are deleted in batches",
+			args:        args("dictionary-entry delete --service-id 123 --dictionary-id 456 --key-regex ^key-[0-9]+$"),
+			stdin:       "y\n",
+			api:         mock.API{ListDictionaryItemsFn: listDictionaryItemsOK(1200), BatchModifyDictionaryItemsFn: countBatches},
+			wantBatches: []int{1000, 200},
+			wantOutputs: []string{
+				"Deleting matches: 1200 deleted, 0 failed",
+				"SUCCESS: Deleted 1200 dictionary items matching '^key-[0-9]+$' (service 123, dictionary 456)",
+			},
+		},
+		{
+			name:      "validate failed batches are reported",
+			args:      args("dictionary-entry delete --service-id 123 --dictionary-id 456 --key-glob key-1? --auto-yes"),
+			api:       mock.API{ListDictionaryItemsFn: listDictionaryItemsOK(20), BatchModifyDictionaryItemsFn: batchModifyDictionaryItemsError},
+			wantError: "failed to delete 10 of 10 matches: key-10, key-11",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.name, func(t *testing.T) {
+			batches = nil
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(testcase.args, &stdout)
+				opts.APIClientFactory = mock.APIClient(testcase.api)
+				opts.Input = strings.NewReader(testcase.stdin)
+				return opts, nil
+			}
+			err := app.Run(testcase.args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertEqual(t, testcase.wantBatches, batches)
+			for _, s := range testcase.wantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			for _, s := range testcase.dontWantOutputs {
+				testutil.AssertStringDoesntContain(t, stdout.String(), s)
+			}
+		})
+	}
+}
+
 func describeDictionaryItemOK(i *fastly.GetDictionaryItemInput) (*fastly.DictionaryItem, error) {
 	return &fastly.DictionaryItem{
 		ServiceID:    fastly.ToPointer(i.ServiceID),
@@ -419,6 +582,41 @@ var dictionaryItemBatchModifyInputOK = `
 	]
 }`
 
+var dictionaryItemBatchModifyInputInvalid = `
+{
+	"items": [
+		{
+		  "op": "upsert",
+		  "item_key": "multi\nline",
+		  "item_value": "value"
+		},
+		{
+		  "op": "upsert",
+		  "item_key": "ok",
+		  "item_value": "value"
+		},
+		{
+		  "op": "create",
+		  "item_key": "",
+		  "item_value": "value"
+		}
+	]
+}`
+
+// listDictionaryItemsOK returns a mock listing n items.
+func listDictionaryItemsOK(n int) func(*fastly.ListDictionaryItemsInput) ([]*fastly.DictionaryItem, error) {
+	return func(_ *fastly.ListDictionaryItemsInput) ([]*fastly.DictionaryItem, error) {
+		items := make([]*fastly.DictionaryItem, 0, n)
+		for i := 0; i < n; i++ {
+			items = append(items, &fastly.DictionaryItem{
+				ItemKey:   fastly.ToPointer(fmt.Sprintf("key-%d", i)),
+				ItemValue: fastly.ToPointer("value"),
+			})
+		}
+		return items, nil
+	}
+}
+
 func batchModifyDictionaryItemsOK(_ *fastly.BatchModifyDictionaryItemsInput) error {
 	return nil
 }
@@ -0,0 +1,131 @@
+package dictionaryentry
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+)
+
+// Edge dictionary limits.
+const (
+	// MaxItems is the number of items a dictionary can hold.
+	MaxItems = 1000
+	// MaxKeyLength is the number of characters in an item key.
+	MaxKeyLength = 256
+	// MaxValueLength is the number of characters in an item value.
+	MaxValueLength = 8000
+)
+
+// ignoreLimitsDesc describes the --ignore-limits flag.
+const ignoreLimitsDesc = "Skip checking items against the default dictionary limits (for accounts with raised limits)"
+
+// Write is a change to a dictionary item.
+type Write struct {
+	Key    string
+	Value  string
+	Delete bool
+}
+
+// Preflight checks writes to a dictionary before any of them is sent to the
+// API, so a batch can't be rejected part way through. Every problem is
+// returned in a MultiError.
+//
+// existing holds the keys currently in the dictionary. When ignoreLimits is
+// set only the key characters are checked, and existing may be nil.
+func Preflight(existing map[string]bool, writes []Write, ignoreLimits bool) error {
+	var errs fsterr.MultiError
+	count := len(existing)
+	keys := make(map[string]bool, len(existing))
+	for k := range existing {
+		keys[k] = true
+	}
+
+	for _, w := range writes {
+		label := fmt.Sprintf("item %q", displayKey(w.Key))
+		switch {
+		case w.Key == "":
+			errs = append(errs, fmt.Errorf("an item key is empty"))
+		case !validKey(w.Key):
+			errs = append(errs, fmt.Errorf("%s: the key contains control characters", label))
+		}
+		if w.Delete {
+			if keys[w.Key] {
+				delete(keys, w.Key)
+				count--
+			}
+			continue
+		}
+		if !keys[w.Key] {
+			keys[w.Key] = true
+			count++
+		}
+		if ignoreLimits {
+			continue
+		}
+		if n := utf8.RuneCountInString(w.Key); n > MaxKeyLength {
+			errs = append(errs, fmt.Errorf("%s: the key is %d characters, the limit is %d", label, n, MaxKeyLength))
+		}
+		if n := utf8.RuneCountInString(w.Value); n > MaxValueLength {
+			errs = append(errs, fmt.Errorf("%s: the value is %d characters, the limit is %d", label, n, MaxValueLength))
+		}
+	}
+
+	if !ignoreLimits && count > MaxItems {
+		errs = append(errs, fmt.Errorf("the dictionary would hold %d items (currently %d), the limit is %d", count, len(existing), MaxItems))
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fsterr.RemediationError{
+		Inner:       errs,
+		Remediation: fsterr.DictionaryLimitsRemediation,
+	}
+}
+
+// existingKeys returns the keys currently in the dictionary, or nil if the
+// limits are ignored (the count isn't needed).
+func existingKeys(g *global.Data, serviceID, dictionaryID string, ignoreLimits bool) (map[string]bool, error) {
+	if ignoreLimits {
+		return nil, nil
+	}
+	items, err := g.APIClient.ListDictionaryItems(&fastly.ListDictionaryItemsInput{
+		DictionaryID: dictionaryID,
+		ServiceID:    serviceID,
+	})
+	if err != nil {
+		g.ErrLog.AddWithContext(err, map[string]any{
+			"Dictionary ID": dictionaryID,
+			"Service ID":    serviceID,
+		})
+		return nil, err
+	}
+	keys := make(map[string]bool, len(items))
+	for _, item := range items {
+		keys[fastly.ToValue(item.ItemKey)] = true
+	}
+	return keys, nil
+}
+
+// validKey indicates if a key is free of control characters (e.g. newlines).
+func validKey(key string) bool {
+	for _, r := range key {
+		if unicode.IsControl(r) {
+			return false
+		}
+	}
+	return utf8.ValidString(key)
+}
+
+// displayKey shortens a long key for display in an error.
+func displayKey(key string) string {
+	const length = 40
+	if r := []rune(key); len(r) > length {
+		return string(r[:length]) + "..."
+	}
+	return key
+}
@@ -0,0 +1,133 @@
+package dictionaryentry_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/commands/dictionaryentry"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestPreflight(t *testing.T) {
+	existing := func(n int) map[string]bool {
+		keys := make(map[string]bool, n)
+		for i := 0; i < n; i++ {
+			keys[strings.Repeat("k", i+1)] = true
+		}
+		return keys
+	}
+
+	scenarios := []struct {
+		name         string
+		existing     map[string]bool
+		writes       []dictionaryentry.Write
+		ignoreLimits bool
+		wantErrors   []string
+	}{
+		{
+			name: "at the limits",
+			writes: []dictionaryentry.Write{{
+				Key:   strings.Repeat("k", dictionaryentry.MaxKeyLength),
+				Value: strings.Repeat("v", dictionaryentry.MaxValueLength),
+			}},
+		},
+		{
+			name:   "characters are counted rather than bytes",
+			writes: []dictionaryentry.Write{{Key: "ü", Value: strings.Repeat("ü", dictionaryentry.MaxValueLength)}},
+		},
+		{
+			name: "over the limits",
+			writes: []dictionaryentry.Write{{
+				Key:   strings.Repeat("k", dictionaryentry.MaxKeyLength+1),
+				Value: strings.Repeat("v", dictionaryentry.MaxValueLength+1),
+			}},
+			wantErrors: []string{
+				"the key is 257 characters, the limit is 256",
+				"the value is 8001 characters, the limit is 8000",
+			},
+		},
+		{
+			name:     "filling the dictionary",
+			existing: existing(dictionaryentry.MaxItems - 1),
+			writes:   []dictionaryentry.Write{{Key: "new"}},
+		},
+		{
+			name:       "overfilling the dictionary",
+			existing:   existing(dictionaryentry.MaxItems),
+			writes:     []dictionaryentry.Write{{Key: "new"}},
+			wantErrors: []string{"the dictionary would hold 1001 items (currently 1000), the limit is 1000"},
+		},
+		{
+			name:     "updating a full dictionary",
+			existing: existing(dictionaryentry.MaxItems),
+			writes:   []dictionaryentry.Write{{Key: "k", Value: "updated"}},
+		},
+		{
+			name:     "deleting to make room",
+			existing: existing(dictionaryentry.MaxItems),
+			writes:   []dictionaryentry.Write{{Key: "new"}, {Key: "k", Delete: true}},
+		},
+		{
+			name:     "ignoring the limits",
+			existing: existing(dictionaryentry.MaxItems),
+			writes: []dictionaryentry.Write{
+				{Key: "new", Value: strings.Repeat("v", dictionaryentry.MaxValueLength+1)},
+			},
+			ignoreLimits: true,
+		},
+		{
+			name: "invalid keys are reported even when ignoring the limits",
+			writes: []dictionaryentry.Write{
+				{Key: "", Value: "v"},
+				{Key: "tab\there", Value: "v"},
+			},
+			ignoreLimits: true,
+			wantErrors: []string{
+				"an item key is empty",
+				`item "tab\there": the key contains control characters`,
+			},
+		},
+		{
+			name:     "every violation is reported",
+			existing: existing(dictionaryentry.MaxItems),
+			writes: []dictionaryentry.Write{
+				{Key: "a", Value: strings.Repeat("v", dictionaryentry.MaxValueLength+1)},
+				{Key: "ok", Value: "v"},
+				{Key: "new\nline", Value: "v"},
+				{Key: strings.Repeat("x", dictionaryentry.MaxKeyLength+1), Value: "v"},
+			},
+			wantErrors: []string{
+				`item "a": the value is 8001 characters`,
+				`item "new\nline": the key contains control characters`,
+				`item "` + strings.Repeat("x", 40) + `...": the key is 257 characters`,
+				"the dictionary would hold 1004 items (currently 1000)",
+			},
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			err := dictionaryentry.Preflight(testcase.existing, testcase.writes, testcase.ignoreLimits)
+			if len(testcase.wantErrors) == 0 {
+				testutil.AssertNoError(t, err)
+				return
+			}
+			var re fsterr.RemediationError
+			if !errors.As(err, &re) {
+				t.Fatalf("want a RemediationError, have %#v", err)
+			}
+			testutil.AssertString(t, fsterr.DictionaryLimitsRemediation, re.Remediation)
+			var me fsterr.MultiError
+			if !errors.As(err, &me) {
+				t.Fatalf("want a MultiError, have %#v", re.Inner)
+			}
+			if len(me) != len(testcase.wantErrors) {
+				t.Fatalf("want %d errors, have %d: %s", len(testcase.wantErrors), len(me), me)
+			}
+			for i, want := range testcase.wantErrors {
+				testutil.AssertStringContains(t, me[i].Error(), want)
+			}
+		})
+	}
+}
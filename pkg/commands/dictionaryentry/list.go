@@ -16,6 +16,7 @@ import (
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 
 	direction     string
 	input         fastly.GetDictionaryItemsInput
@@ -32,13 +33,14 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 		},
 	}
 	c.CmdClause = parent.Command("list", "List items in a Fastly edge dictionary")
+	c.EmptyState = argparser.EmptyState{Resource: "dictionary items", Create: "fastly dictionary-entry create --dictionary-id <DICTIONARY_ID> --key <KEY> --value <VALUE>"}
 
 	// Required.
 	c.CmdClause.Flag("dictionary-id", "Dictionary ID").Required().StringVar(&c.input.DictionaryID)
 
 	// Optional.
 	c.CmdClause.Flag("direction", "Direction in which to sort results").Default(argparser.PaginationDirection[0]).HintOptions(argparser.PaginationDirection...).EnumVar(&c.direction, argparser.PaginationDirection...)
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	c.CmdClause.Flag("page", "Page number of data set to fetch").IntVar(&c.page)
 	c.CmdClause.Flag("per-page", "Number of records per page").IntVar(&c.perPage)
 	c.RegisterFlag(argparser.StringFlagOpts{
@@ -79,9 +81,12 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 	paginator := c.Globals.APIClient.GetDictionaryItems(&c.input)
 
 	var o []*fastly.DictionaryItem
-	for paginator.HasNext() {
+	for paginator.HasNext() && !argparser.MaxTimeReached(c.Globals) {
 		data, err := paginator.GetNext()
 		if err != nil {
+			if argparser.MaxTimeReached(c.Globals) {
+				break
+			}
 			c.Globals.ErrLog.AddWithContext(err, map[string]any{
 				"Dictionary ID":   c.input.DictionaryID,
 				"Service ID":      serviceID,
@@ -93,7 +98,14 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 	}
 
 	if ok, err := c.WriteJSON(out, o); ok {
-		return err
+		if err != nil {
+			return err
+		}
+		return argparser.PartialResults(c.Globals)
+	}
+
+	if c.PrintEmpty(out, len(o)) {
+		return argparser.PartialResults(c.Globals)
 	}
 
 	if !c.Globals.Verbose() {
@@ -105,5 +117,5 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		text.Break(out)
 	}
 
-	return nil
+	return argparser.PartialResults(c.Globals)
 }
@@ -0,0 +1,167 @@
+package dictionaryentry
+
+import (
+	"io"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/itemsync"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// SyncCommand calls the Fastly API to synchronise dictionary items with a
+// local file.
+type SyncCommand struct {
+	argparser.Base
+
+	dictionaryID string
+	dryRun       bool
+	file         string
+	ignoreLimits bool
+	massDeletion *itemsync.MassDeletionFlags
+	serviceName  argparser.OptionalServiceNameID
+}
+
+// NewSyncCommand returns a usable command registered under the parent.
+func NewSyncCommand(parent argparser.Registerer, g *global.Data) *SyncCommand {
+	c := SyncCommand{
+		Base: argparser.Base{
+			Globals: g,
+		},
+	}
+	c.CmdClause = parent.Command("sync", "Synchronise the items of a Fastly edge dictionary with a local JSON or CSV file")
+
+	// Required.
+	c.CmdClause.Flag("dictionary-id", "Dictionary ID").Required().StringVar(&c.dictionaryID)
+	c.CmdClause.Flag("file", "Path to a JSON object or two-column CSV file of key/value pairs ('-' for stdin)").Required().Action(argparser.StdinPath(&c.file)).StringVar(&c.file)
+
+	// Optional.
+	c.CmdClause.Flag("dry-run", "Display the changes without applying them").BoolVar(&c.dryRun)
+	c.CmdClause.Flag("ignore-limits", ignoreLimitsDesc).BoolVar(&c.ignoreLimits)
+	c.massDeletion = itemsync.NewMassDeletionFlags(c.CmdClause, "item")
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Name:        argparser.FlagServiceIDName,
+		Description: argparser.FlagServiceIDDesc,
+		Dst:         &g.Manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        argparser.FlagServiceName,
+		Description: argparser.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *SyncCommand) Exec(in io.Reader, out io.Writer) error {
+	serviceID, source, flag, err := argparser.ServiceID(c.serviceName, *c.Globals.Manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		argparser.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	local, err := itemsync.ReadFile(c.file, in)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	items, err := c.Globals.APIClient.ListDictionaryItems(&fastly.ListDictionaryItemsInput{
+		DictionaryID: c.dictionaryID,
+		ServiceID:    serviceID,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Dictionary ID": c.dictionaryID,
+			"Service ID":    serviceID,
+		})
+		return err
+	}
+
+	remote := make(map[string]string, len(items))
+	for _, item := range items {
+		remote[fastly.ToValue(item.ItemKey)] = fastly.ToValue(item.ItemValue)
+	}
+
+	plan := itemsync.Diff(local, remote)
+	if plan.Empty() {
+		text.Info(out, "Dictionary %s is already in sync with %s", c.dictionaryID, c.file)
+		return nil
+	}
+
+	existing := make(map[string]bool, len(remote))
+	for k := range remote {
+		existing[k] = true
+	}
+	writes := make([]Write, 0, plan.Len())
+	for _, k := range plan.Create {
+		writes = append(writes, Write{Key: k, Value: plan.Values[k]})
+	}
+	for _, k := range plan.Update {
+		writes = append(writes, Write{Key: k, Value: plan.Values[k]})
+	}
+	for _, k := range plan.Delete {
+		writes = append(writes, Write{Key: k, Delete: true})
+	}
+	if err := Preflight(existing, writes, c.ignoreLimits); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	plan.Print(out)
+	if c.dryRun {
+		return nil
+	}
+	if err := c.massDeletion.Check(plan.Delete, len(remote), c.Globals.Config.CLI.MassDeletionThreshold); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	// NOTE: The file may have been read from stdin, so we can't prompt.
+	prompt := in
+	if c.file == "-" {
+		prompt = nil
+	}
+	if err := plan.ConfirmDeletes(out, prompt, c.Globals.Flags.AutoYes || c.Globals.Flags.NonInteractive); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	// NOTE: A pool size of 1 as the batches are sent sequentially.
+	conns := c.Globals.APIConns.Track(1)
+	defer conns.Stop()
+	for _, batch := range plan.Ops(fastly.BatchModifyMaximumOperations) {
+		input := fastly.BatchModifyDictionaryItemsInput{
+			DictionaryID: c.dictionaryID,
+			ServiceID:    serviceID,
+		}
+		for _, op := range batch {
+			item := &fastly.BatchDictionaryItem{
+				ItemKey:   fastly.ToPointer(op.Key),
+				Operation: fastly.ToPointer(op.Operation),
+			}
+			if op.Operation != fastly.DeleteBatchOperation {
+				item.ItemValue = fastly.ToPointer(op.Value)
+			}
+			input.Items = append(input.Items, item)
+		}
+		if err := c.Globals.APIClient.BatchModifyDictionaryItems(&input); err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Dictionary ID": c.dictionaryID,
+				"Service ID":    serviceID,
+			})
+			return err
+		}
+	}
+	argparser.DisplayConnections(out, conns.Summary(), c.Globals.Verbose())
+
+	text.Break(out)
+	text.Success(out, "Synchronised dictionary %s (service %s): %d created, %d updated, %d deleted", c.dictionaryID, serviceID, len(plan.Create), len(plan.Update), len(plan.Delete))
+	return nil
+}
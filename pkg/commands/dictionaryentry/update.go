@@ -17,10 +17,11 @@ import (
 type UpdateCommand struct {
 	argparser.Base
 
-	Input       fastly.UpdateDictionaryItemInput
-	InputBatch  fastly.BatchModifyDictionaryItemsInput
-	file        argparser.OptionalString
-	serviceName argparser.OptionalServiceNameID
+	Input        fastly.UpdateDictionaryItemInput
+	InputBatch   fastly.BatchModifyDictionaryItemsInput
+	file         argparser.OptionalString
+	ignoreLimits bool
+	serviceName  argparser.OptionalServiceNameID
 }
 
 // NewUpdateCommand returns a usable command registered under the parent.
@@ -37,6 +38,7 @@ func NewUpdateCommand(parent argparser.Registerer, g *global.Data) *UpdateComman
 
 	// Optional.
 	c.CmdClause.Flag("file", "Batch update json file").Action(c.file.Set).StringVar(&c.file.Value)
+	c.CmdClause.Flag("ignore-limits", ignoreLimitsDesc).BoolVar(&c.ignoreLimits)
 	c.CmdClause.Flag("key", "Dictionary item key").StringVar(&c.Input.ItemKey)
 	c.RegisterFlag(argparser.StringFlagOpts{
 		Name:        argparser.FlagServiceIDName,
@@ -54,6 +56,11 @@ func NewUpdateCommand(parent argparser.Registerer, g *global.Data) *UpdateComman
 	return &c
 }
 
+// FieldFlags implements the argparser.FieldFlagger interface.
+func (c *UpdateCommand) FieldFlags() map[string]string {
+	return itemFieldFlags
+}
+
 // Exec invokes the application logic for the command.
 func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
 	serviceID, source, flag, err := argparser.ServiceID(c.serviceName, *c.Globals.Manifest, c.Globals.APIClient, c.Globals.ErrLog)
@@ -81,6 +88,16 @@ func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
 		return fmt.Errorf("an empty value is not allowed for either the '--key' or '--value' flags")
 	}
 
+	existing, err := existingKeys(c.Globals, serviceID, c.Input.DictionaryID, c.ignoreLimits)
+	if err != nil {
+		return err
+	}
+	err = Preflight(existing, []Write{{Key: c.Input.ItemKey, Value: c.Input.ItemValue}}, c.ignoreLimits)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
 	d, err := c.Globals.APIClient.UpdateDictionaryItem(&c.Input)
 	if err != nil {
 		c.Globals.ErrLog.Add(err)
@@ -115,6 +132,23 @@ func (c *UpdateCommand) batchModify(out io.Writer) error {
 		return fmt.Errorf("item key not found in file %s", c.file.Value)
 	}
 
+	existing, err := existingKeys(c.Globals, c.InputBatch.ServiceID, c.InputBatch.DictionaryID, c.ignoreLimits)
+	if err != nil {
+		return err
+	}
+	writes := make([]Write, 0, len(c.InputBatch.Items))
+	for _, item := range c.InputBatch.Items {
+		writes = append(writes, Write{
+			Key:    fastly.ToValue(item.ItemKey),
+			Value:  fastly.ToValue(item.ItemValue),
+			Delete: fastly.ToValue(item.Operation) == fastly.DeleteBatchOperation,
+		})
+	}
+	err = Preflight(existing, writes, c.ignoreLimits)
+	if err != nil {
+		return err
+	}
+
 	err = c.Globals.APIClient.BatchModifyDictionaryItems(&c.InputBatch)
 	if err != nil {
 		c.Globals.ErrLog.Add(err)
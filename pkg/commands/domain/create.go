@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"io"
 
 	"github.com/fastly/go-fastly/v9/fastly"
@@ -20,6 +21,7 @@ type CreateCommand struct {
 
 	// Optional.
 	autoClone   argparser.OptionalAutoClone
+	checkDNS    bool
 	comment     argparser.OptionalString
 	name        argparser.OptionalString
 	serviceName argparser.OptionalServiceNameID
@@ -47,6 +49,7 @@ func NewCreateCommand(parent argparser.Registerer, g *global.Data) *CreateComman
 		Action: c.autoClone.Set,
 		Dst:    &c.autoClone.Value,
 	})
+	c.CmdClause.Flag("check-dns", "Check the domain's DNS records point at Fastly once created").BoolVar(&c.checkDNS)
 	c.CmdClause.Flag("comment", "A descriptive note").Action(c.comment.Set).StringVar(&c.comment.Value)
 	c.CmdClause.Flag("name", "Domain name").Short('n').Action(c.name.Set).StringVar(&c.name.Value)
 	c.RegisterFlag(argparser.StringFlagOpts{
@@ -102,5 +105,13 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 	}
 
 	text.Success(out, "Created domain %s (service %s version %d)", fastly.ToValue(d.Name), fastly.ToValue(d.ServiceID), fastly.ToValue(d.ServiceVersion))
+
+	// NOTE: A failing check isn't an error as DNS is commonly configured after
+	// the domain has been created. The output guides the user instead.
+	if c.checkDNS {
+		text.Break(out)
+		r := NewDNSChecker(c.Globals).Check(context.Background(), fastly.ToValue(d.Name))
+		PrintDNSResults(out, []DNSResult{r})
+	}
 	return nil
 }
@@ -0,0 +1,361 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+	"golang.org/x/net/publicsuffix"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// Default DNS targets, used when the application config doesn't define them.
+var (
+	defaultCNAMETarget   = "dualstack.global.fastly.net"
+	defaultApexAddresses = []string{"151.101.1.57", "151.101.65.57", "151.101.129.57", "151.101.193.57"}
+)
+
+// DNS check statuses.
+const (
+	DNSStatusPass = "pass"
+	DNSStatusFail = "fail"
+)
+
+// NewCheckDNSCommand returns a usable command registered under the parent.
+func NewCheckDNSCommand(parent argparser.Registerer, g *global.Data) *CheckDNSCommand {
+	c := CheckDNSCommand{
+		Base: argparser.Base{
+			Globals: g,
+		},
+	}
+	c.CmdClause = parent.Command("check-dns", "Check the DNS records of domains on a Fastly service version point at Fastly")
+
+	// Required.
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Name:        argparser.FlagVersionName,
+		Description: argparser.FlagVersionDesc,
+		Dst:         &c.serviceVersion.Value,
+		Required:    true,
+	})
+
+	// Optional.
+	c.CmdClause.Flag("interval", "How often to re-check DNS when using --wait").Default("10s").DurationVar(&c.interval)
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.CmdClause.Flag("name", "Domain name to check (all domains are checked if omitted)").Short('n').Action(c.name.Set).StringVar(&c.name.Value)
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Name:        argparser.FlagServiceIDName,
+		Description: argparser.FlagServiceIDDesc,
+		Dst:         &g.Manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        argparser.FlagServiceName,
+		Description: argparser.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.CmdClause.Flag("timeout", "Maximum time to wait for DNS to propagate when using --wait").Default("10m").DurationVar(&c.timeout)
+	c.CmdClause.Flag("wait", "Poll until every domain points at Fastly (or --timeout expires)").BoolVar(&c.wait)
+
+	return &c
+}
+
+// CheckDNSCommand resolves domains and compares them against Fastly's DNS
+// targets.
+type CheckDNSCommand struct {
+	argparser.Base
+	argparser.JSONOutput
+
+	interval       time.Duration
+	name           argparser.OptionalString
+	serviceName    argparser.OptionalServiceNameID
+	serviceVersion argparser.OptionalServiceVersion
+	timeout        time.Duration
+	wait           bool
+}
+
+// Exec invokes the application logic for the command.
+func (c *CheckDNSCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	serviceID, serviceVersion, err := argparser.ServiceDetails(argparser.ServiceDetailsOpts{
+		AllowActiveLocked:  true,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           *c.Globals.Manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": fsterr.ServiceVersion(serviceVersion),
+		})
+		return err
+	}
+
+	var names []string
+	if c.name.WasSet {
+		names = []string{c.name.Value}
+	} else {
+		domains, err := c.Globals.APIClient.ListDomains(&fastly.ListDomainsInput{
+			ServiceID:      serviceID,
+			ServiceVersion: fastly.ToValue(serviceVersion.Number),
+		})
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID":      serviceID,
+				"Service Version": fastly.ToValue(serviceVersion.Number),
+			})
+			return err
+		}
+		for _, d := range domains {
+			names = append(names, fastly.ToValue(d.Name))
+		}
+	}
+	if len(names) == 0 {
+		text.Info(out, "No domains found on service version %d", fastly.ToValue(serviceVersion.Number))
+		return nil
+	}
+
+	checker := NewDNSChecker(c.Globals)
+	ctx := context.Background()
+	deadline := time.Now().Add(c.timeout)
+
+	var results []DNSResult
+	for {
+		results = checker.CheckAll(ctx, names)
+		failed := countFailed(results)
+		if failed == 0 || !c.wait {
+			break
+		}
+		if time.Now().Add(c.interval).After(deadline) {
+			c.print(out, results)
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("timed out after %s waiting for DNS to propagate (%d of %d domains not pointed at Fastly)", c.timeout, failed, len(results)),
+				Remediation: "DNS changes can take time to propagate depending on the record TTL. Re-run the command later or increase --timeout.",
+			}
+		}
+		if !c.JSONOutput.Enabled {
+			text.Info(out, "Waiting for DNS to propagate (%d of %d domains pending)...", failed, len(results))
+		}
+		time.Sleep(c.interval)
+	}
+
+	if err := c.print(out, results); err != nil {
+		return err
+	}
+	if failed := countFailed(results); failed > 0 {
+		return fmt.Errorf("%d of %d domains are not pointed at Fastly", failed, len(results))
+	}
+	return nil
+}
+
+func (c *CheckDNSCommand) print(out io.Writer, results []DNSResult) error {
+	if ok, err := c.WriteJSON(out, results); ok {
+		return err
+	}
+	PrintDNSResults(out, results)
+	return nil
+}
+
+// DNSResult is the outcome of checking a single domain.
+type DNSResult struct {
+	Domain string `json:"domain"`
+	Status string `json:"status"`
+	// Records are the records currently found for the domain.
+	Records []string `json:"records"`
+	// Problem describes why the check failed.
+	Problem string `json:"problem,omitempty"`
+	// Suggestions are the DNS records that should be created.
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// DNSChecker compares the DNS records of domains against Fastly's targets.
+type DNSChecker struct {
+	CNAMETarget   string
+	ApexAddresses []string
+	Resolver      api.Resolver
+}
+
+// NewDNSChecker returns a DNSChecker configured from the application config.
+func NewDNSChecker(g *global.Data) *DNSChecker {
+	c := &DNSChecker{
+		CNAMETarget:   g.Config.DNS.CNAMETarget,
+		ApexAddresses: g.Config.DNS.ApexAddresses,
+		Resolver:      g.Resolver,
+	}
+	if c.CNAMETarget == "" {
+		c.CNAMETarget = defaultCNAMETarget
+	}
+	if len(c.ApexAddresses) == 0 {
+		c.ApexAddresses = defaultApexAddresses
+	}
+	if c.Resolver == nil {
+		c.Resolver = net.DefaultResolver
+	}
+	return c
+}
+
+// CheckAll checks each of the given domains.
+func (c *DNSChecker) CheckAll(ctx context.Context, names []string) []DNSResult {
+	results := make([]DNSResult, 0, len(names))
+	for _, name := range names {
+		results = append(results, c.Check(ctx, name))
+	}
+	return results
+}
+
+// Check resolves the domain and compares it against the expected targets.
+func (c *DNSChecker) Check(ctx context.Context, name string) DNSResult {
+	r := DNSResult{Domain: name, Status: DNSStatusPass, Records: []string{}}
+
+	// NOTE: A wildcard domain can't be resolved directly, and so we check an
+	// arbitrary label underneath it.
+	host := name
+	if strings.HasPrefix(host, "*.") {
+		host = "fastly-dns-check" + host[1:]
+	}
+
+	cname, err := c.Resolver.LookupCNAME(ctx, host)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			r.fail("domain does not resolve (NXDOMAIN)", c.suggest(name))
+			return r
+		}
+		r.fail(fmt.Sprintf("DNS lookup failed: %v", err), nil)
+		return r
+	}
+
+	cname = normalizeHost(cname)
+	if cname != normalizeHost(host) {
+		r.Records = append(r.Records, "CNAME "+cname)
+		if cname == normalizeHost(c.CNAMETarget) || strings.HasSuffix(cname, ".fastly.net") || strings.HasSuffix(cname, ".fastlylb.net") {
+			return r
+		}
+		r.fail(fmt.Sprintf("CNAME points to %s instead of Fastly", cname), c.suggest(name))
+		return r
+	}
+
+	addrs, err := c.Resolver.LookupHost(ctx, host)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			r.fail("domain has no A/AAAA records", c.suggest(name))
+			return r
+		}
+		r.fail(fmt.Sprintf("DNS lookup failed: %v", err), nil)
+		return r
+	}
+
+	var conflicting []string
+	for _, a := range addrs {
+		ip := net.ParseIP(a)
+		if ip != nil && ip.To4() == nil {
+			r.Records = append(r.Records, "AAAA "+a)
+		} else {
+			r.Records = append(r.Records, "A "+a)
+		}
+		if !slices.Contains(c.ApexAddresses, a) {
+			conflicting = append(conflicting, a)
+		}
+	}
+	if len(conflicting) > 0 {
+		r.fail(fmt.Sprintf("address records point to %s instead of Fastly (remove these records)", strings.Join(conflicting, ", ")), c.suggest(name))
+	}
+	return r
+}
+
+func (r *DNSResult) fail(problem string, suggestions []string) {
+	r.Status = DNSStatusFail
+	r.Problem = problem
+	r.Suggestions = suggestions
+}
+
+// suggest returns the records a domain should have. Apex domains can't have a
+// CNAME and so require A records.
+func (c *DNSChecker) suggest(name string) []string {
+	fqdn := strings.TrimSuffix(name, ".") + "."
+	if isApex(name) {
+		var s []string
+		for _, a := range c.ApexAddresses {
+			s = append(s, fmt.Sprintf("%s A %s", fqdn, a))
+		}
+		return s
+	}
+	return []string{fmt.Sprintf("%s CNAME %s.", fqdn, strings.TrimSuffix(c.CNAMETarget, "."))}
+}
+
+// isApex reports whether the name is a registrable domain (e.g. example.com or
+// example.co.uk), according to the public suffix list.
+func isApex(name string) bool {
+	name = normalizeHost(name)
+	apex, err := publicsuffix.EffectiveTLDPlusOne(name)
+	return err == nil && apex == name
+}
+
+func normalizeHost(h string) string {
+	return strings.ToLower(strings.TrimSuffix(h, "."))
+}
+
+func countFailed(results []DNSResult) int {
+	var n int
+	for _, r := range results {
+		if r.Status == DNSStatusFail {
+			n++
+		}
+	}
+	return n
+}
+
+// PrintDNSResults displays a summary table followed by the records to create
+// for any domain that failed the check.
+func PrintDNSResults(out io.Writer, results []DNSResult) {
+	tw := text.NewTable(out)
+	tw.AddHeader("DOMAIN", "STATUS", "RECORDS")
+	for _, r := range results {
+		records := strings.Join(r.Records, ", ")
+		if records == "" {
+			records = "-"
+		}
+		tw.AddLine(r.Domain, r.Status, records)
+	}
+	tw.Print()
+
+	for _, r := range results {
+		if r.Status == DNSStatusPass {
+			continue
+		}
+		text.Break(out)
+		text.Warning(out, "%s: %s", r.Domain, r.Problem)
+		if len(r.Suggestions) > 0 {
+			text.Output(out, "Create the following DNS record(s):")
+			for _, s := range r.Suggestions {
+				text.Indent(out, 4, "%s", s)
+			}
+		}
+	}
+
+	text.Break(out)
+	passed := len(results) - countFailed(results)
+	if passed == len(results) {
+		text.Success(out, "%d of %d domains are pointed at Fastly", passed, len(results))
+	} else {
+		text.Info(out, "%d of %d domains are pointed at Fastly", passed, len(results))
+	}
+}
@@ -2,15 +2,20 @@ package domain_test
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/fastly/go-fastly/v9/fastly"
 
 	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/domain"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/mock"
 	"github.com/fastly/cli/pkg/testutil"
@@ -501,3 +506,136 @@ Name: bar.example.com
 Valid: true
 CNAME: bar`
 }
+
+func TestDomainCheckDNS(t *testing.T) {
+	resolver := &fakeResolver{
+		cnames: map[string]string{
+			"www.good.com": "dualstack.global.fastly.net.",
+			"wrong.com":    "wrong.com.",
+		},
+		hosts: map[string][]string{
+			"wrong.com": {"192.0.2.1", "151.101.1.57"},
+		},
+	}
+	listDomains := func(i *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+		return []*fastly.Domain{
+			{Name: fastly.ToPointer("www.good.com")},
+			{Name: fastly.ToPointer("wrong.com")},
+			{Name: fastly.ToPointer("missing.example.com")},
+		}, nil
+	}
+
+	run := func(t *testing.T, args []string, r *fakeResolver) (string, error) {
+		t.Helper()
+		var stdout bytes.Buffer
+		app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+			opts := testutil.MockGlobalData(args, &stdout)
+			opts.APIClientFactory = mock.APIClient(mock.API{
+				CloneVersionFn: testutil.CloneVersionResult(4),
+				ListVersionsFn: testutil.ListVersions,
+				ListDomainsFn:  listDomains,
+				CreateDomainFn: createDomainOK,
+			})
+			opts.Resolver = r
+			return opts, nil
+		}
+		err := app.Run(args, nil)
+		return stdout.String(), err
+	}
+
+	t.Run("single domain with correct CNAME", func(t *testing.T) {
+		out, err := run(t, testutil.Args("domain check-dns --service-id 123 --version 1 --name www.good.com"), resolver)
+		testutil.AssertNoError(t, err)
+		testutil.AssertStringContains(t, out, "CNAME dualstack.global.fastly.net")
+		testutil.AssertStringContains(t, out, "1 of 1 domains are pointed at Fastly")
+	})
+
+	t.Run("all domains", func(t *testing.T) {
+		out, err := run(t, testutil.Args("domain check-dns --service-id 123 --version 1"), resolver)
+		testutil.AssertErrorContains(t, err, "2 of 3 domains are not pointed at Fastly")
+		testutil.AssertStringContains(t, out, "wrong.com: address records point to 192.0.2.1 instead of Fastly")
+		testutil.AssertStringContains(t, out, "wrong.com. A 151.101.65.57")
+		testutil.AssertStringContains(t, out, "missing.example.com: domain does not resolve (NXDOMAIN)")
+		testutil.AssertStringContains(t, out, "missing.example.com. CNAME dualstack.global.fastly.net.")
+		testutil.AssertStringContains(t, out, "1 of 3 domains are pointed at Fastly")
+	})
+
+	t.Run("apex under a multi-label public suffix", func(t *testing.T) {
+		out, err := run(t, testutil.Args("domain check-dns --service-id 123 --version 1 --name example.co.uk"), resolver)
+		testutil.AssertErrorContains(t, err, "1 of 1 domains are not pointed at Fastly")
+		testutil.AssertStringContains(t, out, "example.co.uk. A 151.101.1.57")
+
+		out, err = run(t, testutil.Args("domain check-dns --service-id 123 --version 1 --name www.example.co.uk"), resolver)
+		testutil.AssertErrorContains(t, err, "1 of 1 domains are not pointed at Fastly")
+		testutil.AssertStringContains(t, out, "www.example.co.uk. CNAME dualstack.global.fastly.net.")
+	})
+
+	t.Run("all domains as JSON", func(t *testing.T) {
+		out, err := run(t, testutil.Args("domain check-dns --service-id 123 --version 1 --json"), resolver)
+		testutil.AssertErrorContains(t, err, "2 of 3 domains are not pointed at Fastly")
+		var results []domain.DNSResult
+		if err := json.Unmarshal([]byte(out), &results); err != nil {
+			t.Fatal(err)
+		}
+		testutil.AssertEqual(t, []string{domain.DNSStatusPass, domain.DNSStatusFail, domain.DNSStatusFail}, []string{results[0].Status, results[1].Status, results[2].Status})
+		testutil.AssertEqual(t, []string{"A 192.0.2.1", "A 151.101.1.57"}, results[1].Records)
+	})
+
+	t.Run("wait until propagated", func(t *testing.T) {
+		r := &fakeResolver{
+			cnames:    map[string]string{"www.good.com": "origin.example.net."},
+			propagate: map[string]string{"www.good.com": "dualstack.global.fastly.net."},
+			after:     2,
+		}
+		out, err := run(t, testutil.Args("domain check-dns --service-id 123 --version 1 --name www.good.com --wait --interval 1ms"), r)
+		testutil.AssertNoError(t, err)
+		testutil.AssertStringContains(t, out, "Waiting for DNS to propagate (1 of 1 domains pending)")
+		testutil.AssertStringContains(t, out, "1 of 1 domains are pointed at Fastly")
+	})
+
+	t.Run("wait timeout", func(t *testing.T) {
+		out, err := run(t, testutil.Args("domain check-dns --service-id 123 --version 1 --name missing.example.com --wait --interval 1ms --timeout 5ms"), resolver)
+		testutil.AssertErrorContains(t, err, "waiting for DNS to propagate (1 of 1 domains not pointed at Fastly)")
+		testutil.AssertStringContains(t, out, "domain does not resolve")
+	})
+
+	t.Run("create with --check-dns", func(t *testing.T) {
+		out, err := run(t, testutil.Args("domain create --service-id 123 --version 1 --name www.test.com --autoclone --check-dns"), resolver)
+		testutil.AssertNoError(t, err)
+		testutil.AssertStringContains(t, out, "Created domain www.test.com")
+		testutil.AssertStringContains(t, out, "www.test.com. CNAME dualstack.global.fastly.net.")
+	})
+}
+
+// fakeResolver resolves from static maps. Names in neither map are NXDOMAIN.
+// Once the resolver has answered a CNAME lookup `after` times, records in
+// propagate take precedence.
+type fakeResolver struct {
+	cnames    map[string]string
+	hosts     map[string][]string
+	propagate map[string]string
+	after     int
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (r *fakeResolver) LookupCNAME(_ context.Context, host string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	if c, ok := r.propagate[host]; ok && r.calls > r.after {
+		return c, nil
+	}
+	if c, ok := r.cnames[host]; ok {
+		return c, nil
+	}
+	return "", &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func (r *fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if h, ok := r.hosts[host]; ok {
+		return h, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
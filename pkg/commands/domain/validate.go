@@ -9,6 +9,7 @@ import (
 	"github.com/fastly/cli/pkg/argparser"
 	"github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
 )
 
 // NewValidateCommand returns a usable command registered under the parent.
@@ -139,13 +140,13 @@ func (c *ValidateCommand) print(out io.Writer, r *fastly.DomainValidationResult)
 		fmt.Fprintf(out, "CNAME: %s\n", *r.CName)
 	}
 	if r.Metadata.CreatedAt != nil {
-		fmt.Fprintf(out, "Created at: %s\n", r.Metadata.CreatedAt)
+		fmt.Fprintf(out, "Created at: %s\n", text.FormatTime(r.Metadata.CreatedAt))
 	}
 	if r.Metadata.UpdatedAt != nil {
-		fmt.Fprintf(out, "Updated at: %s\n", r.Metadata.UpdatedAt)
+		fmt.Fprintf(out, "Updated at: %s\n", text.FormatTime(r.Metadata.UpdatedAt))
 	}
 	if r.Metadata.DeletedAt != nil {
-		fmt.Fprintf(out, "Deleted at: %s\n", r.Metadata.DeletedAt)
+		fmt.Fprintf(out, "Deleted at: %s\n", text.FormatTime(r.Metadata.DeletedAt))
 	}
 	fmt.Fprintf(out, "\n")
 }
@@ -175,13 +176,13 @@ func (c *ValidateCommand) printAll(out io.Writer, rs []*fastly.DomainValidationR
 			fmt.Fprintf(out, "CNAME: %s\n", *r.CName)
 		}
 		if r.Metadata.CreatedAt != nil {
-			fmt.Fprintf(out, "Created at: %s\n", r.Metadata.CreatedAt)
+			fmt.Fprintf(out, "Created at: %s\n", text.FormatTime(r.Metadata.CreatedAt))
 		}
 		if r.Metadata.UpdatedAt != nil {
-			fmt.Fprintf(out, "Updated at: %s\n", r.Metadata.UpdatedAt)
+			fmt.Fprintf(out, "Updated at: %s\n", text.FormatTime(r.Metadata.UpdatedAt))
 		}
 		if r.Metadata.DeletedAt != nil {
-			fmt.Fprintf(out, "Deleted at: %s\n", r.Metadata.DeletedAt)
+			fmt.Fprintf(out, "Deleted at: %s\n", text.FormatTime(r.Metadata.DeletedAt))
 		}
 		fmt.Fprintf(out, "\n")
 	}
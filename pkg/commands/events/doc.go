@@ -0,0 +1,4 @@
+// Package events contains commands to query the event log of a Fastly
+// account, which records who changed what (e.g. activating a service
+// version).
+package events
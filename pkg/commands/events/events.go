@@ -0,0 +1,137 @@
+package events
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/commands/stats"
+)
+
+// PageSize is the number of events requested per page.
+const PageSize = 100
+
+// Client is the subset of the API used to query events.
+type Client interface {
+	GetAPIEvents(i *fastly.GetAPIEventsFilterInput) (fastly.GetAPIEventsResponse, error)
+}
+
+// Filter selects the events to return.
+type Filter struct {
+	EventType string
+	ServiceID string
+	UserID    string
+	// From and To bound the time an event was created (either may be zero).
+	// The API doesn't filter by time, so the events are filtered once they've
+	// been fetched.
+	From time.Time
+	To   time.Time
+}
+
+// Fetch reads every page of events matching the filter, and returns them in
+// the order they were created.
+func Fetch(client Client, f Filter) ([]*fastly.Event, error) {
+	input := fastly.GetAPIEventsFilterInput{
+		EventType:  f.EventType,
+		MaxResults: PageSize,
+		ServiceID:  f.ServiceID,
+		UserID:     f.UserID,
+	}
+
+	var events []*fastly.Event
+	for page := 1; ; page++ {
+		// NOTE: The page number is set so go-fastly returns a single page,
+		// rather than following the links to the other pages itself.
+		input.PageNumber = page
+		resp, err := client.GetAPIEvents(&input)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range resp.Events {
+			if f.includes(e) {
+				events = append(events, e)
+			}
+		}
+		if resp.Links.Next == "" || len(resp.Events) == 0 {
+			break
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return createdAt(events[i]).Before(createdAt(events[j]))
+	})
+	return events, nil
+}
+
+// includes indicates the event was created within the time range.
+func (f Filter) includes(e *fastly.Event) bool {
+	t := createdAt(e)
+	if !f.From.IsZero() && t.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && t.After(f.To) {
+		return false
+	}
+	return true
+}
+
+func createdAt(e *fastly.Event) time.Time {
+	if e.CreatedAt == nil {
+		return time.Time{}
+	}
+	return *e.CreatedAt
+}
+
+// Follower polls for events that weren't seen before.
+type Follower struct {
+	Client   Client
+	Clock    stats.Clock
+	Filter   Filter
+	Interval time.Duration
+
+	seen map[string]bool
+}
+
+// Seen records events as seen, so they aren't returned by Run.
+func (f *Follower) Seen(events []*fastly.Event) {
+	if f.seen == nil {
+		f.seen = make(map[string]bool)
+	}
+	for _, e := range events {
+		f.seen[e.ID] = true
+	}
+}
+
+// Run polls every interval until ctx is cancelled, passing the new events to
+// render. Fetch errors are passed to renderErr and polling continues.
+func (f *Follower) Run(ctx context.Context, render func([]*fastly.Event), renderErr func(error)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-f.Clock.After(f.Interval):
+		}
+
+		events, err := Fetch(f.Client, f.Filter)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			renderErr(err)
+			continue
+		}
+
+		var fresh []*fastly.Event
+		for _, e := range events {
+			if !f.seen[e.ID] {
+				fresh = append(fresh, e)
+			}
+		}
+		f.Seen(fresh)
+		if len(fresh) > 0 {
+			render(fresh)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package events_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/events"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+// event returns an event created the given time ago.
+func event(id, eventType, serviceID string, ago time.Duration) *fastly.Event {
+	created := time.Now().Add(-ago).Truncate(time.Second)
+	return &fastly.Event{
+		CreatedAt: &created,
+		EventType: eventType,
+		ID:        id,
+		ServiceID: serviceID,
+		UserID:    "user-" + id,
+	}
+}
+
+// pages returns a GetAPIEvents mock that serves each page in turn, checking
+// the page number and filters of each request.
+func pages(t *testing.T, pages ...[]*fastly.Event) func(i *fastly.GetAPIEventsFilterInput) (fastly.GetAPIEventsResponse, error) {
+	return func(i *fastly.GetAPIEventsFilterInput) (fastly.GetAPIEventsResponse, error) {
+		if i.PageNumber < 1 || i.PageNumber > len(pages) {
+			t.Fatalf("unexpected page number %d", i.PageNumber)
+		}
+		testutil.AssertEqual(t, events.PageSize, i.MaxResults)
+		resp := fastly.GetAPIEventsResponse{Events: pages[i.PageNumber-1]}
+		if i.PageNumber < len(pages) {
+			resp.Links.Next = fmt.Sprintf("https://api.fastly.com/events?page[number]=%d", i.PageNumber+1)
+		}
+		return resp, nil
+	}
+}
+
+func TestList(t *testing.T) {
+	args := testutil.Args
+	threePages := [][]*fastly.Event{
+		{event("e5", "version.activate", "svc1", time.Minute), event("e4", "version.update", "svc1", time.Hour+time.Minute)},
+		{event("e3", "user.login", "", 2*time.Hour), event("e2", "version.clone", "svc2", 25*time.Hour)},
+		{event("e1", "service.create", "svc2", 48*time.Hour)},
+	}
+
+	scenarios := []testutil.TestScenario{
+		{
+			Name: "validate every page is listed, oldest first",
+			API: mock.API{
+				GetAPIEventsFn: pages(t, threePages...),
+			},
+			Args: args("events list"),
+			WantOutputs: []string{
+				"TIME",
+				"ACTOR",
+				"EVENT",
+				"RESOURCE",
+				"user-e1  service.create    service svc2",
+				"user-e3  user.login        account",
+				"user-e5  version.activate  service svc1",
+			},
+		},
+		{
+			Name: "validate filters are sent to the API",
+			API: mock.API{
+				GetAPIEventsFn: func(i *fastly.GetAPIEventsFilterInput) (fastly.GetAPIEventsResponse, error) {
+					testutil.AssertString(t, "version.activate", i.EventType)
+					testutil.AssertString(t, "svc1", i.ServiceID)
+					testutil.AssertString(t, "user-e5", i.UserID)
+					return fastly.GetAPIEventsResponse{Events: threePages[0][:1]}, nil
+				},
+			},
+			Args:       args("events list --event-type version.activate --service-id svc1 --user-id user-e5"),
+			WantOutput: "version.activate",
+		},
+		{
+			Name: "validate relative time range",
+			API: mock.API{
+				GetAPIEventsFn: pages(t, threePages...),
+			},
+			Args:            args("events list --from=-1d --to=-1h"),
+			WantOutputs:     []string{"user.login", "version.update"},
+			DontWantOutputs: []string{"service.create", "version.clone", "version.activate"},
+		},
+		{
+			Name: "validate JSON output",
+			API: mock.API{
+				GetAPIEventsFn: pages(t, threePages...),
+			},
+			Args:            args("events list --json --from=-90m"),
+			WantOutputs:     []string{`"ID": "e4"`, `"ID": "e5"`},
+			DontWantOutputs: []string{`"ID": "e3"`},
+		},
+		{
+			Name: "validate no events",
+			API: mock.API{
+				GetAPIEventsFn: pages(t, []*fastly.Event{}),
+			},
+			Args:       args("events list"),
+			WantOutput: "No events found.",
+		},
+		{
+			Name: "validate API error",
+			API: mock.API{
+				GetAPIEventsFn: func(_ *fastly.GetAPIEventsFilterInput) (fastly.GetAPIEventsResponse, error) {
+					return fastly.GetAPIEventsResponse{}, testutil.Err
+				},
+			},
+			Args:      args("events list"),
+			WantError: testutil.Err.Error(),
+		},
+		{
+			Name:      "validate invalid --from",
+			Args:      args("events list --from yesterday"),
+			WantError: `invalid --from: invalid time "yesterday"`,
+		},
+		{
+			Name:      "validate --from after --to",
+			Args:      args("events list --from=-1h --to=-1d"),
+			WantError: "invalid time range",
+		},
+		{
+			Name:      "validate --follow with --to",
+			Args:      args("events list --follow --to=-1h"),
+			WantError: "--follow can't be used with --to",
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(testcase.Args, &stdout)
+				opts.APIClientFactory = mock.APIClient(testcase.API)
+				return opts, nil
+			}
+			err := app.Run(testcase.Args, nil)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+			for _, s := range testcase.WantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			for _, s := range testcase.DontWantOutputs {
+				testutil.AssertStringDoesntContain(t, stdout.String(), s)
+			}
+		})
+	}
+}
+
+// TestListFollow validates new events are appended until the command is
+// interrupted.
+func TestListFollow(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first := event("e1", "version.clone", "svc1", time.Hour)
+	second := event("e2", "version.activate", "svc1", time.Minute)
+	var calls int
+	api := mock.API{
+		GetAPIEventsFn: func(_ *fastly.GetAPIEventsFilterInput) (fastly.GetAPIEventsResponse, error) {
+			calls++
+			switch calls {
+			case 1:
+				return fastly.GetAPIEventsResponse{Events: []*fastly.Event{first}}, nil
+			case 2:
+				return fastly.GetAPIEventsResponse{}, testutil.Err
+			case 3:
+				return fastly.GetAPIEventsResponse{Events: []*fastly.Event{second, first}}, nil
+			default:
+				cancel()
+				return fastly.GetAPIEventsResponse{Events: []*fastly.Event{second, first}}, nil
+			}
+		},
+	}
+
+	args := testutil.Args("events list --follow --interval 1ms --json")
+	var stdout, stderr bytes.Buffer
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		opts := testutil.MockGlobalData(args, &stdout)
+		opts.APIClientFactory = mock.APIClient(api)
+		opts.Context = ctx
+		opts.ErrOutput = &stderr
+		return opts, nil
+	}
+	testutil.AssertNoError(t, app.Run(args, nil))
+	testutil.AssertStringContains(t, stderr.String(), "Failed to poll for events (retrying in 1ms): test error")
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("want 2 JSON lines, have %d: %s", len(lines), stdout.String())
+	}
+	testutil.AssertStringContains(t, lines[0], `"ID":"e1"`)
+	testutil.AssertStringContains(t, lines[1], `"ID":"e2"`)
+}
+
+// fakeClock advances by the requested duration whenever After is called.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// scriptedEvents returns one scripted page per poll, and cancels the context
+// once the script is exhausted.
+type scriptedEvents struct {
+	cancel    context.CancelFunc
+	responses [][]*fastly.Event
+	errors    map[int]error
+	polls     []time.Time
+	clock     *fakeClock
+}
+
+func (s *scriptedEvents) GetAPIEvents(_ *fastly.GetAPIEventsFilterInput) (fastly.GetAPIEventsResponse, error) {
+	n := len(s.polls)
+	s.polls = append(s.polls, s.clock.Now())
+	if n == len(s.responses)-1 {
+		s.cancel()
+	}
+	if err := s.errors[n]; err != nil {
+		return fastly.GetAPIEventsResponse{}, err
+	}
+	return fastly.GetAPIEventsResponse{Events: s.responses[n]}, nil
+}
+
+func TestFollower(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clock := &fakeClock{now: time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)}
+	e1 := event("e1", "version.clone", "svc1", time.Hour)
+	e2 := event("e2", "version.activate", "svc1", 30*time.Minute)
+	e3 := event("e3", "version.update", "svc1", time.Minute)
+	client := &scriptedEvents{
+		cancel: cancel,
+		clock:  clock,
+		responses: [][]*fastly.Event{
+			{e1},
+			{e2, e1},
+			nil,
+			{e3, e2, e1},
+			{e3, e2, e1},
+		},
+		errors: map[int]error{2: testutil.Err},
+	}
+
+	f := &events.Follower{
+		Client:   client,
+		Clock:    clock,
+		Interval: 30 * time.Second,
+	}
+	f.Seen([]*fastly.Event{e1})
+
+	var (
+		rendered [][]string
+		errs     []error
+	)
+	f.Run(ctx, func(events []*fastly.Event) {
+		var ids []string
+		for _, e := range events {
+			ids = append(ids, e.ID)
+		}
+		rendered = append(rendered, ids)
+	}, func(err error) {
+		errs = append(errs, err)
+	})
+
+	// Only the events that weren't seen before are rendered, once each.
+	testutil.AssertEqual(t, [][]string{{"e2"}, {"e3"}}, rendered)
+	if len(errs) != 1 || errs[0] != testutil.Err {
+		t.Fatalf("want the poll error to be rendered once, have %v", errs)
+	}
+
+	// The events are polled every interval.
+	start := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	want := make([]time.Time, 0, len(client.responses))
+	for i := range client.responses {
+		want = append(want, start.Add(time.Duration(i+1)*30*time.Second))
+	}
+	testutil.AssertEqual(t, want, client.polls)
+}
@@ -0,0 +1,231 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/commands/stats"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// ListCommand calls the Fastly API to list the events of the account.
+type ListCommand struct {
+	argparser.Base
+	argparser.JSONOutput
+	argparser.EmptyState
+
+	eventType string
+	follow    bool
+	from      string
+	interval  time.Duration
+	serviceID string
+	to        string
+	userID    string
+}
+
+// NewListCommand returns a usable command registered under the parent.
+func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
+	var c ListCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("list", "List the events of the account, oldest first")
+	c.EmptyState = argparser.EmptyState{Resource: "events"}
+	c.CmdClause.Flag("event-type", "Only list events of this type (e.g. version.activate)").StringVar(&c.eventType)
+	c.CmdClause.Flag("follow", "Keep polling for new events until interrupted").Short('f').BoolVar(&c.follow)
+	c.CmdClause.Flag("from", "Only list events since this time: relative (e.g. -24h, -7d), a Unix timestamp, RFC3339 or YYYY-MM-DD").StringVar(&c.from)
+	c.CmdClause.Flag("interval", "How often to poll for new events when using --follow").Default("10s").DurationVar(&c.interval)
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
+	c.CmdClause.Flag("service-id", "Only list events of this service").StringVar(&c.serviceID)
+	c.CmdClause.Flag("to", "Only list events until this time: relative (e.g. -1h), a Unix timestamp, RFC3339 or YYYY-MM-DD").StringVar(&c.to)
+	c.CmdClause.Flag("user-id", "Only list events caused by this user").StringVar(&c.userID)
+	return &c
+}
+
+// Interruptible implements the argparser.Interruptible interface. --follow polls
+// for new events until interrupted.
+func (c *ListCommand) Interruptible() bool {
+	return c.follow
+}
+
+// Exec invokes the application logic for the command.
+func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+	if c.follow && c.to != "" {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("--follow can't be used with --to"),
+			Remediation: "Remove --to to follow new events, or remove --follow to list the events until --to.",
+		}
+	}
+	if c.follow && c.interval <= 0 {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid --interval: %s", c.interval),
+			Remediation: "Set --interval to a positive duration (e.g. 30s).",
+		}
+	}
+
+	now := time.Now()
+	from, err := c.parseTime("from", c.from, now)
+	if err != nil {
+		return err
+	}
+	to, err := c.parseTime("to", c.to, now)
+	if err != nil {
+		return err
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid time range: --from (%s) must be before --to (%s)", from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339)),
+			Remediation: "Swap the --from and --to values.",
+		}
+	}
+
+	filter := Filter{
+		EventType: c.eventType,
+		From:      from,
+		ServiceID: c.serviceID,
+		To:        to,
+		UserID:    c.userID,
+	}
+	events, err := Fetch(c.Globals.APIClient, filter)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Event Type": c.eventType,
+			"Service ID": c.serviceID,
+			"User ID":    c.userID,
+		})
+		return err
+	}
+
+	if !c.follow {
+		if ok, err := c.WriteJSON(out, events); ok {
+			return err
+		}
+		if c.PrintEmpty(out, len(events)) {
+			return nil
+		}
+		c.print(out, events, true)
+		return nil
+	}
+
+	if len(events) > 0 {
+		c.print(out, events, true)
+	} else if !c.JSONOutput.Enabled {
+		text.Info(out, "Waiting for new events (polling every %s, press Ctrl-C to stop).", c.interval)
+	}
+	f := &Follower{
+		Client:   c.Globals.APIClient,
+		Clock:    stats.RealClock{},
+		Filter:   filter,
+		Interval: c.interval,
+	}
+	f.Seen(events)
+	ctx := c.Globals.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// Warnings are kept out of the JSON lines.
+	warnings := out
+	if c.JSONOutput.Enabled && c.Globals.ErrOutput != nil {
+		warnings = c.Globals.ErrOutput
+	}
+	f.Run(ctx, func(events []*fastly.Event) {
+		c.print(out, events, false)
+	}, func(err error) {
+		c.Globals.ErrLog.Add(err)
+		text.Warning(warnings, "Failed to poll for events (retrying in %s): %s", c.interval, err)
+	})
+	return nil
+}
+
+// print displays events as a table. When following, each event is written as
+// a JSON object on its own line with --json, so the output can be processed
+// as the events arrive.
+func (c *ListCommand) print(out io.Writer, events []*fastly.Event, header bool) {
+	if c.JSONOutput.Enabled {
+		for _, e := range events {
+			// NOTE: An Event is plain data so it always encodes.
+			data, _ := json.Marshal(e)
+			fmt.Fprintf(out, "%s\n", data)
+		}
+		return
+	}
+
+	if c.Globals.Verbose() {
+		for _, e := range events {
+			printVerbose(out, e)
+		}
+		return
+	}
+
+	t := text.NewTable(out)
+	if header {
+		t.AddHeader("TIME", "ACTOR", "EVENT", "RESOURCE")
+	}
+	for _, e := range events {
+		t.AddLine(formatTime(e), actor(e), e.EventType, resource(e))
+	}
+	t.Print()
+}
+
+func printVerbose(out io.Writer, e *fastly.Event) {
+	fmt.Fprintf(out, "\nID: %s\n", e.ID)
+	fmt.Fprintf(out, "Time: %s\n", formatTime(e))
+	fmt.Fprintf(out, "Event type: %s\n", e.EventType)
+	fmt.Fprintf(out, "Description: %s\n", e.Description)
+	fmt.Fprintf(out, "User ID: %s\n", e.UserID)
+	fmt.Fprintf(out, "Fastly admin: %t\n", e.Admin)
+	fmt.Fprintf(out, "IP: %s\n", e.IP)
+	fmt.Fprintf(out, "Customer ID: %s\n", e.CustomerID)
+	fmt.Fprintf(out, "Service ID: %s\n", e.ServiceID)
+}
+
+func formatTime(e *fastly.Event) string {
+	if e.CreatedAt == nil {
+		return "-"
+	}
+	return text.FormatTimeLayout(e.CreatedAt, time.RFC3339)
+}
+
+// actor returns who caused the event.
+func actor(e *fastly.Event) string {
+	switch {
+	case e.Admin:
+		return "Fastly admin"
+	case e.UserID == "":
+		return "-"
+	}
+	return e.UserID
+}
+
+// resource returns what the event changed.
+func resource(e *fastly.Event) string {
+	if e.ServiceID != "" {
+		return "service " + e.ServiceID
+	}
+	return "account"
+}
+
+// parseTime parses the value of a time flag, returning the zero time if the
+// flag wasn't set.
+func (c *ListCommand) parseTime(flag, value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := stats.ParseTime(value, now)
+	if err != nil {
+		return t, fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid --%s: %w", flag, err),
+			Remediation: "Use a relative time in the past such as -24h or -7d, a Unix timestamp, an RFC3339 timestamp (e.g. 2024-01-02T15:04:05Z) or a date (e.g. 2024-01-02).",
+		}
+	}
+	return t, nil
+}
@@ -0,0 +1,3 @@
+// Package explain contains a command to display the documentation of the
+// CLI's error codes.
+package explain
@@ -0,0 +1,156 @@
+package explain_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/fastly/cli/pkg/app"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestExplain(t *testing.T) {
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name: "validate an error code is explained",
+			Args: args("explain FASTLY_ERR_AUTH"),
+			WantOutputs: []string{
+				"FASTLY_ERR_AUTH\n\nThe Fastly API rejected the API token\n",
+				"The Fastly API responded with 401 Unauthorized.",
+				"How to fix it",
+				"Environment variables\n\n  - FASTLY_API_TOKEN\n",
+				"Documentation\n\n  - https://docs.fastly.com/en/guides/using-api-tokens\n",
+			},
+		},
+		{
+			Name:       "validate the prefix and case are optional",
+			Args:       args("explain max-time"),
+			WantOutput: "FASTLY_ERR_MAX_TIME",
+		},
+		{
+			Name:            "validate a partial code matching one error code",
+			Args:            args("explain interactive"),
+			WantOutput:      "FASTLY_ERR_NON_INTERACTIVE",
+			DontWantOutputs: []string{"FASTLY_ERR_AUTH"},
+		},
+		{
+			Name:      "validate a partial code matching more than one error code",
+			Args:      args("explain _i"),
+			WantError: "'_i' matches more than one error code",
+		},
+		{
+			Name:      "validate an unknown error code",
+			Args:      args("explain FASTLY_ERR_NOPE"),
+			WantError: "unknown error code 'FASTLY_ERR_NOPE'",
+		},
+		{
+			Name: "validate the error codes are listed",
+			Args: args("explain --list"),
+			WantOutputs: []string{
+				"CODE",
+				"SUMMARY",
+				"FASTLY_ERR_AUTH",
+				"FASTLY_ERR_SERVICE_ID",
+			},
+		},
+		{
+			Name:       "validate the error codes are listed without a code",
+			Args:       args("explain"),
+			WantOutput: "FASTLY_ERR_NETWORK",
+		},
+		{
+			Name: "validate JSON output",
+			Args: args("explain auth --json"),
+			WantOutputs: []string{
+				`"code": "FASTLY_ERR_AUTH"`,
+				`"env_vars": [`,
+			},
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				return testutil.MockGlobalData(testcase.Args, &stdout), nil
+			}
+			err := app.Run(testcase.Args, nil)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+			for _, s := range testcase.WantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			for _, s := range testcase.DontWantOutputs {
+				testutil.AssertStringDoesntContain(t, stdout.String(), s)
+			}
+		})
+	}
+}
+
+// TestExplainSuggestions validates the remediation of an unknown or ambiguous
+// error code suggests the closest error codes.
+func TestExplainSuggestions(t *testing.T) {
+	for _, testcase := range []struct {
+		args            string
+		wantRemediation string
+	}{
+		{
+			args:            "explain AUHT",
+			wantRemediation: "Did you mean FASTLY_ERR_AUTH? Run `fastly explain --list` to list every error code.",
+		},
+		{
+			args:            "explain fastly_err_netwrk",
+			wantRemediation: "Did you mean FASTLY_ERR_NETWORK? Run `fastly explain --list` to list every error code.",
+		},
+		{
+			args:            "explain _i",
+			wantRemediation: "Did you mean FASTLY_ERR_NON_INTERACTIVE or FASTLY_ERR_SERVICE_ID?",
+		},
+		{
+			args:            "explain completely-unrelated",
+			wantRemediation: "Run `fastly explain --list` to list every error code.",
+		},
+	} {
+		t.Run(testcase.args, func(t *testing.T) {
+			args := testutil.Args(testcase.args)
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				return testutil.MockGlobalData(args, &stdout), nil
+			}
+			err := app.Run(args, nil)
+			if err == nil {
+				t.Fatal("want an error")
+			}
+			testutil.AssertString(t, testcase.wantRemediation, fsterr.Deduce(err).Remediation)
+		})
+	}
+}
+
+// TestExplainCatalog validates every registered error code can be explained.
+func TestExplainCatalog(t *testing.T) {
+	args := testutil.Args("explain --list --json")
+	var stdout bytes.Buffer
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		return testutil.MockGlobalData(args, &stdout), nil
+	}
+	testutil.AssertNoError(t, app.Run(args, nil))
+
+	var entries []fsterr.CatalogEntry
+	testutil.AssertNoError(t, json.Unmarshal(stdout.Bytes(), &entries))
+	testutil.AssertEqual(t, fsterr.Catalog(), entries)
+
+	for _, e := range entries {
+		args := testutil.Args("explain " + string(e.Code))
+		var stdout bytes.Buffer
+		app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+			return testutil.MockGlobalData(args, &stdout), nil
+		}
+		testutil.AssertNoError(t, app.Run(args, nil))
+		testutil.AssertStringContains(t, stdout.String(), e.Summary)
+	}
+}
@@ -0,0 +1,128 @@
+package explain
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// MaxSuggestions is the number of error codes suggested for an unknown code.
+const MaxSuggestions = 3
+
+// RootCommand is the parent command for all subcommands in this package.
+// It should be installed under the primary root command.
+type RootCommand struct {
+	argparser.Base
+	argparser.JSONOutput
+
+	code string
+	list bool
+}
+
+// NewRootCommand returns a new command registered in the parent.
+func NewRootCommand(parent argparser.Registerer, g *global.Data) *RootCommand {
+	var c RootCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("explain", "Explain an error code (e.g. FASTLY_ERR_AUTH), including how to fix the error")
+	c.CmdClause.Arg("code", "The error code, or part of it (e.g. AUTH or max-time)").StringVar(&c.code)
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.CmdClause.Flag("list", "List every error code").BoolVar(&c.list)
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+	if c.list || c.code == "" {
+		return c.printList(out, fsterr.Catalog())
+	}
+
+	entry, err := find(c.code)
+	if err != nil {
+		return err
+	}
+	if ok, err := c.WriteJSON(out, entry); ok {
+		return err
+	}
+	text.Markdown(out, document(entry))
+	return nil
+}
+
+// find returns the entry for an exact code, or the only entry matching a
+// partial code.
+func find(code string) (fsterr.CatalogEntry, error) {
+	if entry, ok := fsterr.LookupCode(code); ok {
+		return entry, nil
+	}
+
+	matches := fsterr.SearchCodes(code)
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	if len(matches) > 1 {
+		return fsterr.CatalogEntry{}, fsterr.RemediationError{
+			Inner:       fmt.Errorf("'%s' matches more than one error code", code),
+			Remediation: fmt.Sprintf("Did you mean %s?", codes(matches)),
+		}
+	}
+
+	remediation := "Run `fastly explain --list` to list every error code."
+	if suggestions := fsterr.SuggestCodes(code, MaxSuggestions); len(suggestions) > 0 {
+		remediation = fmt.Sprintf("Did you mean %s? %s", codes(suggestions), remediation)
+	}
+	return fsterr.CatalogEntry{}, fsterr.RemediationError{
+		Inner:       fmt.Errorf("unknown error code '%s'", code),
+		Remediation: remediation,
+	}
+}
+
+// codes returns the codes of the entries as a list of alternatives.
+func codes(entries []fsterr.CatalogEntry) string {
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, string(e.Code))
+	}
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
+
+// document returns the entry as Markdown (see text.Markdown).
+func document(e fsterr.CatalogEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n%s\n\n%s\n\n## How to fix it\n\n%s\n", e.Code, e.Summary, e.Description, e.Remediation)
+	if len(e.EnvVars) > 0 {
+		b.WriteString("\n## Environment variables\n\n")
+		for _, v := range e.EnvVars {
+			fmt.Fprintf(&b, "- `%s`\n", v)
+		}
+	}
+	if len(e.Links) > 0 {
+		b.WriteString("\n## Documentation\n\n")
+		for _, l := range e.Links {
+			fmt.Fprintf(&b, "- %s\n", l)
+		}
+	}
+	return b.String()
+}
+
+func (c *RootCommand) printList(out io.Writer, entries []fsterr.CatalogEntry) error {
+	if ok, err := c.WriteJSON(out, entries); ok {
+		return err
+	}
+	t := text.NewTable(out)
+	t.AddHeader("CODE", "SUMMARY")
+	for _, e := range entries {
+		t.AddLine(e.Code, e.Summary)
+	}
+	t.Print()
+	return nil
+}
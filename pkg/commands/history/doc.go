@@ -0,0 +1,3 @@
+// Package history contains a command to display the audit log of mutating
+// commands.
+package history
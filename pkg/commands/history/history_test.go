@@ -0,0 +1,142 @@
+package history_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/audit"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestHistoryRecordsMutatingCommands(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	run := func(args []string, api mock.API, enabled bool) error {
+		var stdout bytes.Buffer
+		app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+			opts := testutil.MockGlobalData(args, &stdout)
+			opts.APIClientFactory = mock.APIClient(api)
+			opts.AuditLogPath = path
+			opts.Config.Audit.Enabled = enabled
+			return opts, nil
+		}
+		return app.Run(args, nil)
+	}
+
+	api := mock.API{
+		CreateServiceFn: func(_ *fastly.CreateServiceInput) (*fastly.Service, error) {
+			return &fastly.Service{ServiceID: fastly.ToPointer("12345")}, nil
+		},
+		GetServiceDetailsFn: func(_ *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+			return &fastly.ServiceDetail{ServiceID: fastly.ToPointer("123")}, nil
+		},
+		ListVersionsFn: func(_ *fastly.ListVersionsInput) ([]*fastly.Version, error) {
+			return []*fastly.Version{{Number: fastly.ToPointer(2)}}, nil
+		},
+		LockVersionFn: func(_ *fastly.LockVersionInput) (*fastly.Version, error) {
+			return nil, errors.New("test error")
+		},
+	}
+	testutil.AssertNoError(t, run(testutil.Args("service create --name foo --token abc123"), api, true))
+	testutil.AssertNoError(t, run(testutil.Args("service describe --service-id 123"), api, true))
+	testutil.AssertNoError(t, run(testutil.Args("service create --name bar"), api, false))
+	_ = run(testutil.Args("service-version lock --service-id 123 --version 2"), api, true)
+
+	entries, err := audit.Read(path)
+	testutil.AssertNoError(t, err)
+	if len(entries) != 2 {
+		t.Fatalf("want 2 entries, have %d: %#v", len(entries), entries)
+	}
+	testutil.AssertString(t, "service create", entries[0].Command)
+	testutil.AssertString(t, "service create --name foo --token REDACTED", strings.Join(entries[0].Args, " "))
+	testutil.AssertString(t, audit.OutcomeSuccess, entries[0].Outcome)
+	testutil.AssertString(t, "service-version lock", entries[1].Command)
+	testutil.AssertString(t, "123", entries[1].ServiceID)
+	testutil.AssertString(t, "2", entries[1].ServiceVersion)
+	testutil.AssertString(t, audit.OutcomeError, entries[1].Outcome)
+	testutil.AssertStringContains(t, entries[1].Error, "test error")
+}
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	for _, e := range []audit.Entry{
+		{Time: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), Command: "service create", Outcome: audit.OutcomeSuccess, DurationMS: 1500},
+		{Time: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC), Command: "service-version activate", ServiceID: "123", ServiceVersion: "2", Outcome: audit.OutcomeSuccess, DurationMS: 250},
+		{Time: time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC), Command: "backend create", ServiceID: "456", ServiceVersion: "1", Outcome: audit.OutcomeError, DurationMS: 10},
+	} {
+		testutil.AssertNoError(t, audit.Record(path, e))
+	}
+
+	args := testutil.Args
+	scenarios := []testutil.TestScenario{
+		{
+			Name: "all",
+			Args: args("history"),
+			WantOutput: `
+TIME                  COMMAND                   SERVICE  VERSION  OUTCOME  DURATION
+2024-01-01T10:00:00Z  service create                              success  1.5s
+2024-01-02T10:00:00Z  service-version activate  123      2        success  250ms
+2024-01-03T10:00:00Z  backend create            456      1        error    10ms
+`,
+		},
+		{
+			Name: "filter by service",
+			Args: args("history --service-id 123"),
+			WantOutput: `
+TIME                  COMMAND                   SERVICE  VERSION  OUTCOME  DURATION
+2024-01-02T10:00:00Z  service-version activate  123      2        success  250ms
+`,
+		},
+		{
+			Name: "filter by date",
+			Args: args("history --from 2024-01-02 --to 2024-01-02T23:59:59Z"),
+			WantOutput: `
+TIME                  COMMAND                   SERVICE  VERSION  OUTCOME  DURATION
+2024-01-02T10:00:00Z  service-version activate  123      2        success  250ms
+`,
+		},
+		{
+			Name:       "json",
+			Args:       args("history --service-id 456 --json"),
+			WantOutput: `"command": "backend create"`,
+		},
+		{
+			Name:       "no matches",
+			Args:       args("history --service-id 789"),
+			WantOutput: "No commands found.",
+		},
+		{
+			Name:      "invalid time",
+			Args:      args("history --from yesterday"),
+			WantError: `invalid --from: invalid time "yesterday"`,
+		},
+	}
+
+	for _, testcase := range scenarios {
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(testcase.Args, &stdout)
+				opts.AuditLogPath = path
+				opts.Config.Audit.Enabled = true
+				return opts, nil
+			}
+			err := app.Run(testcase.Args, nil)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			if testcase.Name == "json" || testcase.Name == "no matches" {
+				testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+				return
+			}
+			testutil.AssertString(t, testcase.WantOutput, stdout.String())
+		})
+	}
+}
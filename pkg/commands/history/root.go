@@ -0,0 +1,100 @@
+package history
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/audit"
+	"github.com/fastly/cli/pkg/commands/stats"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// RootCommand is the parent command for all subcommands in this package.
+// It should be installed under the primary root command.
+type RootCommand struct {
+	argparser.Base
+	argparser.JSONOutput
+
+	from      string
+	serviceID string
+	to        string
+}
+
+// NewRootCommand returns a new command registered in the parent.
+func NewRootCommand(parent argparser.Registerer, g *global.Data) *RootCommand {
+	var c RootCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("history", "Display the audit log of mutating commands (enable with `enabled = true` in the [audit] section of the CLI config)")
+	c.CmdClause.Flag("from", "Only display commands run since this time (e.g. -7d, 2024-01-02, 2024-01-02T15:04:05Z)").StringVar(&c.from)
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.CmdClause.Flag("service-id", "Only display commands affecting this service").StringVar(&c.serviceID)
+	c.CmdClause.Flag("to", "Only display commands run until this time (e.g. -1d, 2024-01-02, 2024-01-02T15:04:05Z)").StringVar(&c.to)
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	now := time.Now()
+	from, err := c.parseTime("from", c.from, now)
+	if err != nil {
+		return err
+	}
+	to, err := c.parseTime("to", c.to, now)
+	if err != nil {
+		return err
+	}
+
+	entries, err := audit.Read(c.Globals.AuditLogPath)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	entries = audit.Filter(entries, c.serviceID, from, to)
+
+	if ok, err := c.WriteJSON(out, entries); ok {
+		return err
+	}
+
+	if len(entries) == 0 {
+		if !c.Globals.Config.Audit.Enabled {
+			text.Info(out, "The audit log is disabled. To enable it, set `enabled = true` in the [audit] section of the CLI config (see `fastly config --location`).")
+			return nil
+		}
+		text.Info(out, "No commands found.")
+		return nil
+	}
+
+	text.Break(out)
+	t := text.NewTable(out)
+	t.AddHeader("TIME", "COMMAND", "SERVICE", "VERSION", "OUTCOME", "DURATION")
+	for _, e := range entries {
+		duration := (time.Duration(e.DurationMS) * time.Millisecond).String()
+		t.AddLine(text.FormatTimeLayout(&e.Time, time.RFC3339), e.Command, e.ServiceID, e.ServiceVersion, e.Outcome, duration)
+	}
+	t.Print()
+	return nil
+}
+
+// parseTime parses the value of a time flag, returning the zero time if the
+// flag wasn't set.
+func (c *RootCommand) parseTime(flag, value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := stats.ParseTime(value, now)
+	if err != nil {
+		return t, fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid --%s: %w", flag, err),
+			Remediation: "Use a relative time in the past such as -24h or -7d, a Unix timestamp, an RFC3339 timestamp (e.g. 2024-01-02T15:04:05Z) or a date (e.g. 2024-01-02).",
+		}
+	}
+	return t, nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/fastly/cli/pkg/argparser"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/naming"
 	"github.com/fastly/cli/pkg/text"
 )
 
@@ -38,6 +39,10 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 		return fsterr.ErrInvalidVerboseJSONCombo
 	}
 
+	if err := c.ValidateName(naming.KVStore, c.Input.Name); err != nil {
+		return err
+	}
+
 	o, err := c.Globals.APIClient.CreateKVStore(&c.Input)
 	if err != nil {
 		c.Globals.ErrLog.Add(err)
@@ -48,6 +53,6 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
-	text.Success(out, "Created KV Store '%s' (%s)", o.Name, o.StoreID)
+	text.Result(out, o.StoreID, "Created KV Store '%s' (%s)", o.Name, o.StoreID)
 	return nil
 }
@@ -0,0 +1,131 @@
+package kvstore
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// Record is a single KV Store entry as represented in an export file.
+//
+// The format (newline-delimited JSON with a base64 encoded value) matches what
+// the batch endpoint accepts and so can also be passed to
+// `kv-store-entry create --file`. Entry metadata isn't included, as the API
+// client doesn't expose it.
+type Record struct {
+	// Key is the entry key.
+	Key string `json:"key"`
+	// Value is the base64 encoded entry value.
+	Value string `json:"value"`
+}
+
+// NewExportCommand returns a usable command registered under the parent.
+func NewExportCommand(parent argparser.Registerer, g *global.Data) *ExportCommand {
+	c := ExportCommand{
+		Base: argparser.Base{
+			Globals: g,
+		},
+	}
+	c.CmdClause = parent.Command("export", "Export the keys and values of a KV Store as newline-delimited JSON (entry metadata isn't exported)")
+
+	// Required.
+	c.CmdClause.Flag("store-id", "Store ID").Short('s').Required().StringVar(&c.storeID)
+
+	// Optional.
+	c.CmdClause.Flag("consistency", "Determines accuracy of the key listing").Default("strong").HintOptions("eventual", "strong").EnumVar(&c.consistency, "eventual", "strong")
+	c.CmdClause.Flag("file", "Path to write the export to (default: stdout)").StringVar(&c.file)
+
+	return &c
+}
+
+// ExportCommand calls the Fastly API to export the entries of a KV Store.
+type ExportCommand struct {
+	argparser.Base
+
+	consistency string
+	file        string
+	storeID     string
+}
+
+// Exec invokes the application logic for the command.
+func (c *ExportCommand) Exec(_ io.Reader, out io.Writer) error {
+	w := out
+	if c.file != "" && c.file != "-" {
+		f, err := os.Create(c.file)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("failed to create export file: %w", err)
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		w = f
+	}
+
+	bw := bufio.NewWriter(w)
+	n, err := c.export(bw)
+	if ferr := bw.Flush(); err == nil {
+		err = ferr
+	}
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Store ID": c.storeID,
+			"Exported": n,
+		})
+		return err
+	}
+
+	// NOTE: We don't want to corrupt the export when it's written to stdout.
+	if w != out {
+		text.Success(out, "Exported %d keys from KV Store '%s' to %s", n, c.storeID, c.file)
+	}
+	return nil
+}
+
+// export streams each entry to w as it's fetched so that the full store is
+// never held in memory. Each value is read whole though, so a single value
+// must fit in memory.
+func (c *ExportCommand) export(w io.Writer) (int, error) {
+	input := &fastly.ListKVStoreKeysInput{StoreID: c.storeID}
+	switch c.consistency {
+	case "eventual":
+		input.Consistency = fastly.ConsistencyEventual
+	case "strong":
+		input.Consistency = fastly.ConsistencyStrong
+	}
+
+	var n int
+	enc := json.NewEncoder(w)
+	p := c.Globals.APIClient.NewListKVStoreKeysPaginator(input)
+	for p.Next() {
+		for _, key := range p.Keys() {
+			value, err := c.Globals.APIClient.GetKVStoreKey(&fastly.GetKVStoreKeyInput{
+				StoreID: c.storeID,
+				Key:     key,
+			})
+			if err != nil {
+				return n, fmt.Errorf("failed to get key '%s': %w", key, err)
+			}
+			if err := enc.Encode(Record{
+				Key:   key,
+				Value: base64.StdEncoding.EncodeToString([]byte(value)),
+			}); err != nil {
+				return n, fmt.Errorf("failed to write key '%s': %w", key, err)
+			}
+			n++
+		}
+	}
+	if err := p.Err(); err != nil {
+		return n, fmt.Errorf("failed to list keys: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,321 @@
+package kvstore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// importRetryDelay is the initial delay before retrying a failed insert.
+// It doubles with each subsequent attempt.
+var importRetryDelay = 500 * time.Millisecond
+
+// NewImportCommand returns a usable command registered under the parent.
+func NewImportCommand(parent argparser.Registerer, g *global.Data) *ImportCommand {
+	c := ImportCommand{
+		Base: argparser.Base{
+			Globals: g,
+		},
+	}
+	c.CmdClause = parent.Command("import", "Import entries into a KV Store from newline-delimited JSON (as produced by `kv-store export`). Entry metadata isn't imported")
+
+	// Required.
+	c.CmdClause.Flag("store-id", "Store ID").Short('s').Required().StringVar(&c.storeID)
+	c.CmdClause.Flag("file", "Path to the newline-delimited JSON file to import ('-' for stdin)").Required().Action(argparser.StdinPath(&c.file)).StringVar(&c.file)
+
+	// Optional.
+	c.CmdClause.Flag("checkpoint", "Path to a file recording imported keys, so an interrupted import can be resumed").StringVar(&c.checkpoint)
+	c.CmdClause.Flag("concurrency", "Limit the number of concurrent network resources allocated").Default("50").IntVar(&c.concurrency)
+	c.CmdClause.Flag("retries", "Number of times to retry a failed key").Default("3").IntVar(&c.retries)
+
+	return &c
+}
+
+// ImportCommand calls the Fastly API to import entries into a KV Store.
+type ImportCommand struct {
+	argparser.Base
+
+	checkpoint  string
+	concurrency int
+	file        string
+	retries     int
+	storeID     string
+}
+
+// importResult summarises an import.
+type importResult struct {
+	written int
+	skipped int
+	failed  []importErr
+	// notImported counts the keys read before the import was interrupted but
+	// never attempted.
+	notImported int
+}
+
+// importErr represents an error related to importing an individual key.
+type importErr struct {
+	key string
+	err error
+}
+
+// Interruptible implements the argparser.Interruptible interface. An interrupted
+// import stops inserting keys and reports those left to import.
+func (c *ImportCommand) Interruptible() bool {
+	return true
+}
+
+// Exec invokes the application logic for the command.
+func (c *ImportCommand) Exec(in io.Reader, out io.Writer) error {
+	if c.concurrency < 1 {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid --concurrency value: %d", c.concurrency),
+			Remediation: "Set --concurrency to a value of 1 or greater.",
+		}
+	}
+
+	r := in
+	if c.file != "-" {
+		// G304 (CWE-22): Potential file inclusion via variable
+		// #nosec
+		f, err := os.Open(c.file)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fmt.Errorf("failed to open import file: %w", err)
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		r = f
+	} else if in == nil || text.IsTTY(in) {
+		return fsterr.ErrNoSTDINData
+	}
+
+	done, cp, err := c.openCheckpoint()
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	if cp != nil {
+		defer func() {
+			_ = cp.Close()
+		}()
+	}
+
+	conns := c.Globals.APIConns.Track(c.concurrency)
+	defer conns.Stop()
+
+	result, err := c.importRecords(r, done, cp)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	argparser.DisplayConnections(out, conns.Summary(), c.Globals.Verbose())
+
+	if len(result.failed) > 0 {
+		sort.Slice(result.failed, func(i, j int) bool {
+			return result.failed[i].key < result.failed[j].key
+		})
+		t := text.NewTable(out)
+		t.AddHeader("FAILED KEY", "ERROR")
+		for _, e := range result.failed {
+			t.AddLine(e.key, e.err)
+		}
+		t.Print()
+		text.Break(out)
+	}
+
+	summary := fmt.Sprintf("%d written, %d skipped, %d failed", result.written, result.skipped, len(result.failed))
+	if result.notImported > 0 {
+		summary += fmt.Sprintf(", %d not imported", result.notImported)
+	}
+	if c.Globals.Context.Err() != nil {
+		err := fmt.Errorf("import into KV Store '%s' was interrupted (%s)", c.storeID, summary)
+		c.Globals.ErrLog.Add(err)
+		if c.checkpoint != "" {
+			return fsterr.RemediationError{
+				Inner:       err,
+				Remediation: fmt.Sprintf("Re-run the command with the same --checkpoint (%s) to import the remaining keys.", c.checkpoint),
+			}
+		}
+		return err
+	}
+	if len(result.failed) > 0 {
+		err := fmt.Errorf("failed to import %d keys into KV Store '%s' (%s)", len(result.failed), c.storeID, summary)
+		c.Globals.ErrLog.Add(err)
+		if c.checkpoint != "" {
+			return fsterr.RemediationError{
+				Inner:       err,
+				Remediation: fmt.Sprintf("Re-run the command with the same --checkpoint (%s) to retry only the failed keys.", c.checkpoint),
+			}
+		}
+		return err
+	}
+
+	text.Success(out, "Imported keys into KV Store '%s': %s", c.storeID, summary)
+	return nil
+}
+
+// openCheckpoint returns the keys already recorded in the checkpoint file,
+// along with the file opened for appending newly imported keys.
+func (c *ImportCommand) openCheckpoint() (map[string]bool, *os.File, error) {
+	done := make(map[string]bool)
+	if c.checkpoint == "" {
+		return done, nil, nil
+	}
+
+	// G304 (CWE-22): Potential file inclusion via variable
+	// #nosec
+	f, err := os.OpenFile(c.checkpoint, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open checkpoint file: %w", err)
+	}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// NOTE: Keys are JSON encoded as they may contain newlines.
+		var key string
+		if err := json.Unmarshal(scanner.Bytes(), &key); err == nil {
+			done[key] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		_ = f.Close()
+		return nil, nil, fmt.Errorf("failed to read checkpoint file: %w", err)
+	}
+	return done, f, nil
+}
+
+// importRecords streams records from r to a pool of workers. Reading stops when
+// the CLI is interrupted, and the records not yet attempted are counted as not
+// imported.
+func (c *ImportCommand) importRecords(r io.Reader, done map[string]bool, cp io.Writer) (importResult, error) {
+	var (
+		result importResult
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+	)
+
+	records := make(chan Record)
+	for i := 0; i < c.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for rec := range records {
+				// NOTE: Records already handed over when the CLI is interrupted
+				// are left out of the checkpoint, so a resumed import picks them
+				// up.
+				if c.Globals.Context.Err() != nil {
+					mu.Lock()
+					result.notImported++
+					mu.Unlock()
+					continue
+				}
+				err := c.insert(rec)
+
+				mu.Lock()
+				if err != nil {
+					result.failed = append(result.failed, importErr{key: rec.Key, err: err})
+				} else {
+					result.written++
+					if cp != nil {
+						if b, err := json.Marshal(rec.Key); err == nil {
+							_, _ = cp.Write(append(b, '\n'))
+						}
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// NOTE: The decoder reads one record at a time so the file isn't loaded
+	// into memory. Keys are tracked to avoid importing duplicates.
+	var decodeErr error
+	seen := make(map[string]bool)
+	dec := json.NewDecoder(r)
+	for line := 1; ; line++ {
+		var rec Record
+		if err := dec.Decode(&rec); err != nil {
+			if !errors.Is(err, io.EOF) {
+				decodeErr = fmt.Errorf("failed to parse record %d: %w", line, err)
+			}
+			break
+		}
+		if rec.Key == "" {
+			decodeErr = fmt.Errorf("failed to parse record %d: missing key", line)
+			break
+		}
+		if done[rec.Key] || seen[rec.Key] {
+			mu.Lock()
+			result.skipped++
+			mu.Unlock()
+			continue
+		}
+		seen[rec.Key] = true
+		if !c.send(records, rec) {
+			mu.Lock()
+			result.notImported++
+			mu.Unlock()
+			break
+		}
+	}
+
+	close(records)
+	wg.Wait()
+	return result, decodeErr
+}
+
+// send hands rec to a worker, returning false if the CLI is interrupted first.
+func (c *ImportCommand) send(records chan<- Record, rec Record) bool {
+	if c.Globals.Context.Err() != nil {
+		return false
+	}
+	select {
+	case records <- rec:
+		return true
+	case <-c.Globals.Context.Done():
+		return false
+	}
+}
+
+// insert writes a single record, retrying on failure until the retries are
+// exhausted or the CLI is interrupted.
+func (c *ImportCommand) insert(rec Record) error {
+	value, err := base64.StdEncoding.DecodeString(rec.Value)
+	if err != nil {
+		return fmt.Errorf("invalid base64 value: %w", err)
+	}
+
+	delay := importRetryDelay
+	for attempt := 0; ; attempt++ {
+		err = c.Globals.APIClient.InsertKVStoreKey(&fastly.InsertKVStoreKeyInput{
+			Body:    bytes.NewReader(value),
+			StoreID: c.storeID,
+			Key:     rec.Key,
+		})
+		if err == nil || attempt >= c.retries {
+			return err
+		}
+		select {
+		case <-c.Globals.Context.Done():
+			return c.Globals.Context.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
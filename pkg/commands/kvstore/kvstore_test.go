@@ -1,10 +1,18 @@
 package kvstore_test
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -300,6 +308,219 @@ func TestListStoresCommand(t *testing.T) {
 	}
 }
 
+func TestExportImportCommands(t *testing.T) {
+	const (
+		storeID = "store-id-123"
+		total   = 300
+	)
+
+	keys := make([]string, total)
+	values := make(map[string]string, total)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%03d", i)
+		values[keys[i]] = fmt.Sprintf("value %d", i)
+	}
+
+	run := func(t *testing.T, api mock.API, args string) (string, error) {
+		t.Helper()
+		var stdout bytes.Buffer
+		argv := testutil.Args(args)
+		app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+			opts := testutil.MockGlobalData(argv, &stdout)
+			opts.APIClientFactory = mock.APIClient(api)
+			return opts, nil
+		}
+		err := app.Run(argv, nil)
+		return stdout.String(), err
+	}
+
+	// Export the source store, which returns keys across multiple pages.
+	exportAPI := mock.API{
+		NewListKVStoreKeysPaginatorFn: func(i *fastly.ListKVStoreKeysInput) fastly.PaginatorKVStoreEntries {
+			return &mockKeysPaginator{pages: [][]string{keys[:100], keys[100:200], keys[200:]}}
+		},
+		GetKVStoreKeyFn: func(i *fastly.GetKVStoreKeyInput) (string, error) {
+			return values[i.Key], nil
+		},
+	}
+	export, err := run(t, exportAPI, fmt.Sprintf("%s export --store-id %s", kvstore.RootName, storeID))
+	testutil.AssertNoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(export), "\n")
+	testutil.AssertEqual(t, total, len(lines))
+	var rec kvstore.Record
+	if err := json.Unmarshal([]byte(lines[42]), &rec); err != nil {
+		t.Fatalf("invalid export line: %v", err)
+	}
+	testutil.AssertEqual(t, kvstore.Record{Key: "key-042", Value: base64.StdEncoding.EncodeToString([]byte("value 42"))}, rec)
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "export.jsonl")
+	checkpoint := filepath.Join(dir, "checkpoint")
+	if err := os.WriteFile(file, []byte(export), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu       sync.Mutex
+		imported = make(map[string]string)
+		attempts = make(map[string]int)
+	)
+	importAPI := func(fail func(key string, attempt int) bool) mock.API {
+		return mock.API{
+			InsertKVStoreKeyFn: func(i *fastly.InsertKVStoreKeyInput) error {
+				b, err := io.ReadAll(i.Body)
+				if err != nil {
+					return err
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				attempts[i.Key]++
+				if fail(i.Key, attempts[i.Key]) {
+					return errors.New("whoops")
+				}
+				imported[i.Key] = string(b)
+				return nil
+			},
+		}
+	}
+	importArgs := fmt.Sprintf("%s import --store-id %s --file %s --checkpoint %s --retries 1 --concurrency 20", kvstore.RootName, storeID, file, checkpoint)
+
+	// The first import fails part way through: every key from key-200 onwards
+	// fails permanently while key-007 only fails on its first attempt.
+	out, err := run(t, importAPI(func(key string, attempt int) bool {
+		return key >= "key-200" || (key == "key-007" && attempt == 1)
+	}), importArgs)
+	testutil.AssertErrorContains(t, err, "failed to import 100 keys into KV Store 'store-id-123' (200 written, 0 skipped, 100 failed)")
+	testutil.AssertRemediationErrorContains(t, err, "Re-run the command with the same --checkpoint")
+	testutil.AssertStringContains(t, out, "FAILED KEY")
+	testutil.AssertStringContains(t, out, "key-299")
+	testutil.AssertStringContains(t, out, "whoops")
+	testutil.AssertEqual(t, 200, len(imported))
+	testutil.AssertEqual(t, 2, attempts["key-007"])
+
+	// Resuming skips everything recorded in the checkpoint.
+	out, err = run(t, importAPI(func(string, int) bool { return false }), importArgs)
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, out, "Imported keys into KV Store 'store-id-123': 100 written, 200 skipped, 0 failed")
+	testutil.AssertEqual(t, values, imported)
+	testutil.AssertEqual(t, 1, attempts["key-000"])
+	testutil.AssertEqual(t, 3, attempts["key-299"])
+
+	f, err := os.Open(checkpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var recorded int
+	for scanner := bufio.NewScanner(f); scanner.Scan(); {
+		recorded++
+	}
+	testutil.AssertEqual(t, total, recorded)
+}
+
+func TestImportCommand(t *testing.T) {
+	const storeID = "store-id-123"
+	record := func(key string) string {
+		return `{"key": "` + key + `", "value": "` + base64.StdEncoding.EncodeToString([]byte("1")) + `"}` + "\n"
+	}
+
+	for _, testcase := range []struct {
+		name           string
+		records        string
+		cancelOn       int // cancel the context during this insert
+		insertErr      error
+		wantError      string
+		wantOutput     string
+		wantInserts    int
+		wantCheckpoint string
+	}{
+		{
+			name:           "file from stdin",
+			records:        record("a"),
+			wantOutput:     "Imported keys into KV Store 'store-id-123': 1 written, 0 skipped, 0 failed",
+			wantInserts:    1,
+			wantCheckpoint: `"a"` + "\n",
+		},
+		{
+			name:        "interrupt stops the retries",
+			records:     record("a"),
+			cancelOn:    1,
+			insertErr:   errors.New("whoops"),
+			wantError:   "import into KV Store 'store-id-123' was interrupted (0 written, 0 skipped, 1 failed)",
+			wantOutput:  "context canceled",
+			wantInserts: 1,
+		},
+		{
+			name:           "interrupt stops the import",
+			records:        record("a") + record("b") + record("c") + record("d"),
+			cancelOn:       2,
+			wantError:      "import into KV Store 'store-id-123' was interrupted (2 written, 0 skipped, 0 failed, 1 not imported)",
+			wantInserts:    2,
+			wantCheckpoint: `"a"` + "\n" + `"b"` + "\n",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var (
+				inserts int
+				stdout  bytes.Buffer
+			)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			api := mock.API{
+				InsertKVStoreKeyFn: func(_ *fastly.InsertKVStoreKeyInput) error {
+					inserts++
+					if inserts == testcase.cancelOn {
+						cancel()
+					}
+					return testcase.insertErr
+				},
+			}
+			checkpoint := filepath.Join(t.TempDir(), "checkpoint")
+			args := testutil.Args(fmt.Sprintf("%s import --store-id %s --file - --retries 100 --concurrency 1 --checkpoint %s", kvstore.RootName, storeID, checkpoint))
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIClientFactory = mock.APIClient(api)
+				opts.Input = strings.NewReader(testcase.records)
+				opts.Context = ctx
+				return opts, nil
+			}
+			err := app.Run(args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			testutil.AssertEqual(t, testcase.wantInserts, inserts)
+
+			b, err := os.ReadFile(checkpoint)
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutil.AssertString(t, testcase.wantCheckpoint, string(b))
+		})
+	}
+}
+
+type mockKeysPaginator struct {
+	pages [][]string
+	keys  []string
+}
+
+func (m *mockKeysPaginator) Next() bool {
+	if len(m.pages) == 0 {
+		m.keys = nil
+		return false
+	}
+	m.keys, m.pages = m.pages[0], m.pages[1:]
+	return true
+}
+
+func (m *mockKeysPaginator) Keys() []string {
+	return m.keys
+}
+
+func (m *mockKeysPaginator) Err() error {
+	return nil
+}
+
 func fmtStore(ks *fastly.KVStore) string {
 	var b bytes.Buffer
 	text.PrintKVStore(&b, "", ks)
@@ -15,6 +15,7 @@ import (
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 }
 
 // NewListCommand returns a usable command registered under the parent.
@@ -25,9 +26,10 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 		},
 	}
 	c.CmdClause = parent.Command("list", "List kv stores")
+	c.EmptyState = argparser.EmptyState{Resource: "KV stores", Create: "fastly kv-store create --name <NAME>"}
 
 	// Optional.
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 
 	return &c
 }
@@ -55,6 +57,10 @@ func (c *ListCommand) Exec(in io.Reader, out io.Writer) error {
 			return err
 		}
 
+		if cursor == "" && o != nil && c.PrintEmpty(out, len(o.Data)) {
+			return nil
+		}
+
 		if o != nil {
 			for _, o := range o.Data {
 				// avoid gosec loop aliasing check :/
@@ -68,7 +74,7 @@ func (c *ListCommand) Exec(in io.Reader, out io.Writer) error {
 					continue
 				}
 				text.Break(out)
-				printNext, err := text.AskYesNo(out, "Print next page [y/N]: ", in)
+				printNext, err := text.AskYesNo(out, "Print next page [y/N]: ", in, "--auto-yes")
 				if err != nil {
 					return err
 				}
@@ -206,6 +206,9 @@ func (c *CreateCommand) ProcessDir(in io.Reader, out io.Writer) error {
 	msg := "%s %d of %d files"
 	spinner.Message(fmt.Sprintf(msg, "Processing", 0, filesTotal) + "...")
 
+	conns := c.Globals.APIConns.Track(c.dirConcurrency)
+	defer conns.Stop()
+
 	base := filepath.Base(path)
 	processed := make(chan struct{}, c.dirConcurrency)
 	sem := make(chan struct{}, c.dirConcurrency)
@@ -323,6 +326,7 @@ func (c *CreateCommand) ProcessDir(in io.Reader, out io.Writer) error {
 			fmt.Println(filename)
 		}
 	}
+	argparser.DisplayConnections(out, conns.Summary(), c.Globals.Verbose())
 
 	if len(processingErrors) == 0 {
 		text.Success(out, "\nInserted %d keys into KV Store", len(filteredFiles))
@@ -343,7 +347,7 @@ func (c *CreateCommand) ProcessDir(in io.Reader, out io.Writer) error {
 func (c *CreateCommand) PromptWindowsUser(in io.Reader, out io.Writer) (bool, error) {
 	if !c.Globals.Flags.AutoYes && !c.Globals.Flags.NonInteractive {
 		label := `The Fastly CLI will skip dotfiles (filenames prefixed with a period character, example: '.ignore') but this does not include files set with a "hidden" attribute). Are you sure you want to continue? [y/N] `
-		result, err := text.AskYesNo(out, label, in)
+		result, err := text.AskYesNo(out, label, in, "--auto-yes")
 		if err != nil {
 			return false, err
 		}
@@ -12,6 +12,7 @@ import (
 	"github.com/fastly/cli/pkg/argparser"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/itemsync"
 	"github.com/fastly/cli/pkg/text"
 )
 
@@ -27,6 +28,7 @@ type DeleteCommand struct {
 	concurrency argparser.OptionalInt
 	deleteAll   bool
 	key         argparser.OptionalString
+	pattern     *itemsync.PatternFlags
 	storeID     string
 }
 
@@ -44,9 +46,10 @@ func NewDeleteCommand(parent argparser.Registerer, g *global.Data) *DeleteComman
 
 	// Optional.
 	c.CmdClause.Flag("all", "Delete all entries within the store").Short('a').BoolVar(&c.deleteAll)
-	c.CmdClause.Flag("concurrency", "Control thread pool size (ignored when set without the --all flag)").Short('c').Action(c.concurrency.Set).IntVar(&c.concurrency.Value)
+	c.CmdClause.Flag("concurrency", "Control thread pool size (ignored when set without the --all flag or a pattern)").Short('c').Action(c.concurrency.Set).IntVar(&c.concurrency.Value)
 	c.RegisterFlagBool(c.JSONFlag()) // --json
 	c.CmdClause.Flag("key", "Key name").Short('k').Action(c.key.Set).StringVar(&c.key.Value)
+	c.pattern = itemsync.NewPatternFlags(c.CmdClause, "key", "key", "temp:*")
 
 	return &c
 }
@@ -63,14 +66,36 @@ func (c *DeleteCommand) Exec(in io.Reader, out io.Writer) error {
 	if c.deleteAll && c.key.WasSet {
 		return fsterr.ErrInvalidDeleteAllKeyCombo
 	}
+	if c.pattern.WasSet() {
+		if c.deleteAll {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("invalid flag combination, --all and --key-glob or --key-regex"),
+				Remediation: "Use either --all to delete every key, or --key-glob or --key-regex to delete the matching keys.",
+			}
+		}
+		if c.JSONOutput.Enabled {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("invalid flag combination, --json and --key-glob or --key-regex"),
+				Remediation: "Remove the --json flag.",
+			}
+		}
+		if err := c.pattern.Validate("--key", c.key.WasSet); err != nil {
+			return err
+		}
+		return c.deleteMatching(in, out)
+	}
 	if !c.deleteAll && !c.key.WasSet {
 		return fsterr.ErrMissingDeleteAllKeyCombo
 	}
+	// NOTE: --dry-run and --match-all are only valid with a pattern.
+	if err := c.pattern.Validate("--key", true); err != nil {
+		return err
+	}
 
 	if c.deleteAll {
 		if !c.Globals.Flags.AutoYes && !c.Globals.Flags.NonInteractive {
 			text.Warning(out, "This will delete ALL entries from your store!\n\n")
-			cont, err := text.AskYesNo(out, "Are you sure you want to continue? [y/N]: ", in)
+			cont, err := text.AskYesNo(out, "Are you sure you want to continue? [y/N]: ", in, "--auto-yes")
 			if err != nil {
 				return err
 			}
@@ -111,6 +136,83 @@ func (c *DeleteCommand) Exec(in io.Reader, out io.Writer) error {
 	return nil
 }
 
+// deleteMatching deletes every key matching the pattern.
+//
+// NOTE: The KV Store API has no batch delete endpoint, and so the keys are
+// deleted concurrently (see --concurrency) one batch at a time.
+func (c *DeleteCommand) deleteMatching(in io.Reader, out io.Writer) error {
+	p, err := c.pattern.Pattern()
+	if err != nil {
+		return err
+	}
+
+	var keys []string
+	paginator := c.Globals.APIClient.NewListKVStoreKeysPaginator(&fastly.ListKVStoreKeysInput{
+		StoreID: c.storeID,
+	})
+	for paginator.Next() {
+		keys = append(keys, paginator.Keys()...)
+	}
+	if err := paginator.Err(); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return fmt.Errorf("failed to list keys: %w", err)
+	}
+
+	matches := p.Filter(keys)
+	c.pattern.PrintMatches(out, p, matches)
+	if len(matches) == 0 {
+		return nil
+	}
+	if c.pattern.DryRun {
+		text.Break(out)
+		text.Info(out, "Dry run: no keys were deleted from KV Store '%s'", c.storeID)
+		return nil
+	}
+	if err := c.pattern.ConfirmMatches(out, in, len(matches), c.Globals.Flags.AutoYes || c.Globals.Flags.NonInteractive); err != nil {
+		return err
+	}
+
+	poolSize := deleteKeysConcurrencyLimit
+	if c.concurrency.WasSet {
+		poolSize = c.concurrency.Value
+	}
+	conns := c.Globals.APIConns.Track(poolSize)
+	defer conns.Stop()
+	result, err := itemsync.DeleteBatches(out, matches, poolSize, func(batch []string) []string {
+		var (
+			failed []string
+			mu     sync.Mutex
+			wg     sync.WaitGroup
+		)
+		for _, key := range batch {
+			wg.Add(1)
+			go func(key string) {
+				defer wg.Done()
+				err := c.Globals.APIClient.DeleteKVStoreKey(&fastly.DeleteKVStoreKeyInput{StoreID: c.storeID, Key: key})
+				if err != nil {
+					c.Globals.ErrLog.Add(fmt.Errorf("failed to delete key '%s': %s", key, err))
+					mu.Lock()
+					failed = append(failed, key)
+					mu.Unlock()
+				}
+			}(key)
+		}
+		wg.Wait()
+		return failed
+	})
+	if err != nil {
+		return err
+	}
+	argparser.DisplayConnections(out, conns.Summary(), c.Globals.Verbose())
+	if err := result.Err(); err != nil {
+		return err
+	}
+
+	text.Break(out)
+	text.Success(out, "Deleted %d keys matching '%s' from KV Store '%s'", len(result.Deleted), p, c.storeID)
+	return nil
+}
+
 func (c *DeleteCommand) deleteAllKeys(out io.Writer) error {
 	p := c.Globals.APIClient.NewListKVStoreKeysPaginator(&fastly.ListKVStoreKeysInput{
 		StoreID: c.storeID,
@@ -126,6 +228,9 @@ func (c *DeleteCommand) deleteAllKeys(out io.Writer) error {
 	}
 	semaphore := make(chan struct{}, poolSize)
 
+	conns := c.Globals.APIConns.Track(poolSize)
+	defer conns.Stop()
+
 	failedKeys := []string{}
 
 	for p.Next() {
@@ -156,6 +261,7 @@ func (c *DeleteCommand) deleteAllKeys(out io.Writer) error {
 
 	wg.Wait()
 	close(semaphore)
+	argparser.DisplayConnections(out, conns.Summary(), c.Globals.Verbose())
 
 	if err := p.Err(); err != nil {
 		return fmt.Errorf("failed to delete keys: %s", err)
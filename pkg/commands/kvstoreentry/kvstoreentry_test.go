@@ -246,6 +246,83 @@ SUCCESS: Deleted all keys from KV Store '%s'
 			},
 			WantError: "failed to delete keys: whoops",
 		},
+		{
+			Args:      testutil.Args(fmt.Sprintf("%s delete --store-id %s --all --key-glob temp:*", kvstoreentry.RootName, storeID)),
+			WantError: "invalid flag combination, --all and --key-glob or --key-regex",
+		},
+		{
+			Args:      testutil.Args(fmt.Sprintf("%s delete --store-id %s --all --dry-run", kvstoreentry.RootName, storeID)),
+			WantError: "--dry-run and --match-all require --key-glob or --key-regex",
+		},
+		{
+			Args: testutil.Args(fmt.Sprintf("%s delete --store-id %s --key-glob temp:* --dry-run", kvstoreentry.RootName, storeID)),
+			API: mock.API{
+				NewListKVStoreKeysPaginatorFn: func(i *fastly.ListKVStoreKeysInput) fastly.PaginatorKVStoreEntries {
+					return &mockKVStoresEntriesPaginator{
+						next: true,
+						keys: []string{"temp:b", "foo", "temp:a"},
+					}
+				},
+				DeleteKVStoreKeyFn: func(i *fastly.DeleteKVStoreKeyInput) error {
+					return errors.New("unexpected delete")
+				},
+			},
+			WantOutput: fmt.Sprintf(`2 keys match 'temp:*'.
+
+  temp:a
+  temp:b
+
+INFO: Dry run: no keys were deleted from KV Store '%s'
+`, storeID),
+		},
+		{
+			Args: testutil.Args(fmt.Sprintf("%s delete --store-id %s --key-glob * --auto-yes", kvstoreentry.RootName, storeID)),
+			API: mock.API{
+				NewListKVStoreKeysPaginatorFn: func(i *fastly.ListKVStoreKeysInput) fastly.PaginatorKVStoreEntries {
+					return &mockKVStoresEntriesPaginator{
+						next: true,
+						keys: []string{"foo"},
+					}
+				},
+			},
+			WantError: "the pattern '*' matches every key",
+		},
+		{
+			Args: testutil.Args(fmt.Sprintf("%s delete --store-id %s --key-regex ^temp: --auto-yes --concurrency 1", kvstoreentry.RootName, storeID)),
+			API: mock.API{
+				NewListKVStoreKeysPaginatorFn: func(i *fastly.ListKVStoreKeysInput) fastly.PaginatorKVStoreEntries {
+					return &mockKVStoresEntriesPaginator{
+						next: true,
+						keys: []string{"temp:b", "foo", "temp:a"},
+					}
+				},
+				DeleteKVStoreKeyFn: func(i *fastly.DeleteKVStoreKeyInput) error {
+					if !strings.HasPrefix(i.Key, "temp:") {
+						return errors.New("unexpected delete")
+					}
+					return nil
+				},
+			},
+			WantOutput: fstfmt.Success("Deleted 2 keys matching '^temp:' from KV Store '%s'", storeID),
+		},
+		{
+			Args: testutil.Args(fmt.Sprintf("%s delete --store-id %s --key-glob temp:* --auto-yes", kvstoreentry.RootName, storeID)),
+			API: mock.API{
+				NewListKVStoreKeysPaginatorFn: func(i *fastly.ListKVStoreKeysInput) fastly.PaginatorKVStoreEntries {
+					return &mockKVStoresEntriesPaginator{
+						next: true,
+						keys: []string{"temp:b", "foo", "temp:a"},
+					}
+				},
+				DeleteKVStoreKeyFn: func(i *fastly.DeleteKVStoreKeyInput) error {
+					if i.Key == "temp:b" {
+						return errors.New("whoops")
+					}
+					return nil
+				},
+			},
+			WantError: "failed to delete 1 of 2 matches: temp:b",
+		},
 	}
 
 	for _, testcase := range scenarios {
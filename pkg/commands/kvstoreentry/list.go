@@ -16,6 +16,7 @@ import (
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 
 	consistency string
 	Input       fastly.ListKVStoreKeysInput
@@ -36,13 +37,14 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 	}
 
 	c.CmdClause = parent.Command("list", "List keys")
+	c.EmptyState = argparser.EmptyState{Resource: "keys", Create: "fastly kv-store-entry create --store-id <STORE_ID> --key <KEY> --value <VALUE>"}
 
 	// Required.
 	c.CmdClause.Flag("store-id", "Store ID").Short('s').Required().StringVar(&c.Input.StoreID)
 
 	// Optional.
 	c.CmdClause.Flag("consistency", "Determines accuracy of results. i.e. 'eventual' uses caching to improve performance").Default("strong").HintOptions(ConsistencyOptions...).EnumVar(&c.consistency, ConsistencyOptions...)
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	return &c
 }
 
@@ -82,9 +84,12 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		c.Input.Consistency = fastly.ConsistencyStrong
 	}
 
-	for {
+	for !argparser.MaxTimeReached(c.Globals) {
 		o, err := c.Globals.APIClient.ListKVStoreKeys(&c.Input)
 		if err != nil {
+			if argparser.MaxTimeReached(c.Globals) {
+				break
+			}
 			c.Globals.ErrLog.Add(err)
 			if !c.JSONOutput.Enabled {
 				spinner.StopFailMessage(msg)
@@ -112,26 +117,24 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		}
 	}
 
-	if keys == nil {
-		if ok, err := c.WriteJSON(out, []string{}); ok {
+	if ok, err := c.WriteJSON(out, keys); ok {
+		if err != nil {
 			return err
 		}
-		text.Break(out)
-		text.Output(out, "no keys")
-		return nil
+		return argparser.PartialResults(c.Globals)
 	}
 
-	if ok, err := c.WriteJSON(out, keys); ok {
-		return err
+	if c.PrintEmpty(out, len(keys)) {
+		return argparser.PartialResults(c.Globals)
 	}
 
 	if c.Globals.Flags.Verbose {
 		text.PrintKVStoreKeys(out, "", keys)
-		return nil
+		return argparser.PartialResults(c.Globals)
 	}
 
 	for _, k := range keys {
 		text.Output(out, k)
 	}
-	return nil
+	return argparser.PartialResults(c.Globals)
 }
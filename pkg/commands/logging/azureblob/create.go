@@ -69,7 +69,7 @@ func NewCreateCommand(parent argparser.Registerer, g *global.Data) *CreateComman
 	common.CompressionCodec(c.CmdClause, &c.CompressionCodec)
 	c.CmdClause.Flag("container", "The name of the Azure Blob Storage container in which to store logs").Action(c.Container.Set).StringVar(&c.Container.Value)
 	c.CmdClause.Flag("file-max-bytes", "The maximum size of a log file in bytes").Action(c.FileMaxBytes.Set).IntVar(&c.FileMaxBytes.Value)
-	common.Format(c.CmdClause, &c.Format)
+	common.Format(c.CmdClause, &c.Format, g)
 	common.FormatVersion(c.CmdClause, &c.FormatVersion)
 	common.GzipLevel(c.CmdClause, &c.GzipLevel)
 	common.MessageType(c.CmdClause, &c.MessageType)
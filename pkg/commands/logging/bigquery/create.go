@@ -30,6 +30,7 @@ type CreateCommand struct {
 	Format            argparser.OptionalString
 	FormatVersion     argparser.OptionalInt
 	Placement         argparser.OptionalString
+	Preset            argparser.OptionalString
 	ProjectID         argparser.OptionalString
 	ResponseCondition argparser.OptionalString
 	SecretKey         argparser.OptionalString
@@ -62,10 +63,11 @@ func NewCreateCommand(parent argparser.Registerer, g *global.Data) *CreateComman
 		Dst:    &c.AutoClone.Value,
 	})
 	c.CmdClause.Flag("dataset", "Your BigQuery dataset").Action(c.Dataset.Set).StringVar(&c.Dataset.Value)
-	common.Format(c.CmdClause, &c.Format)
+	common.Format(c.CmdClause, &c.Format, g)
 	common.FormatVersion(c.CmdClause, &c.FormatVersion)
 	c.CmdClause.Flag("name", "The name of the BigQuery logging object. Used as a primary key for API access").Short('n').Action(c.EndpointName.Set).StringVar(&c.EndpointName.Value)
 	common.Placement(c.CmdClause, &c.Placement)
+	common.PresetFlag(c.CmdClause, "bigquery", &c.Preset)
 	c.CmdClause.Flag("project-id", "Your Google Cloud Platform project ID").Action(c.ProjectID.Set).StringVar(&c.ProjectID.Value)
 	common.ResponseCondition(c.CmdClause, &c.ResponseCondition)
 	c.CmdClause.Flag("secret-key", "Your Google Cloud Platform account secret key. The private_key field in your service account authentication JSON.").Action(c.SecretKey.Set).StringVar(&c.SecretKey.Value)
@@ -135,7 +137,25 @@ func (c *CreateCommand) ConstructInput(serviceID string, serviceVersion int) (*f
 }
 
 // Exec invokes the application logic for the command.
-func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
+func (c *CreateCommand) Exec(in io.Reader, out io.Writer) error {
+	if c.Preset.WasSet {
+		err := common.ApplyPreset(c.Preset.Value, common.PresetOpts{
+			Provider:      "bigquery",
+			Format:        &c.Format,
+			FormatVersion: &c.FormatVersion,
+			Fields: map[string]*argparser.OptionalString{
+				"project-id": &c.ProjectID,
+				"dataset":    &c.Dataset,
+				"table":      &c.Table,
+				"user":       &c.User,
+				"secret-key": &c.SecretKey,
+			},
+		}, c.Globals, in, out)
+		if err != nil {
+			return err
+		}
+	}
+
 	serviceID, serviceVersion, err := argparser.ServiceDetails(argparser.ServiceDetailsOpts{
 		AutoCloneFlag:      c.AutoClone,
 		APIClient:          c.Globals.APIClient,
@@ -1,9 +1,15 @@
 package common
 
 import (
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/fastly/kingpin"
 
 	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
 )
 
 // AccountName defines the account-name flag.
@@ -11,9 +17,89 @@ func AccountName(command *kingpin.CmdClause, c *argparser.OptionalString) {
 	command.Flag("account-name", "The google account name used to obtain temporary credentials (default none)").Action(c.Set).StringVar(&c.Value)
 }
 
-// Format defines the format flag.
-func Format(command *kingpin.CmdClause, c *argparser.OptionalString) {
+// Format defines the format, format-file and edit flags.
+//
+// The format-file flag reads the log format from a file, which avoids having
+// to shell escape the many quotes and percent signs a format typically has.
+// The edit flag opens a template format in the user's editor instead.
+func Format(command *kingpin.CmdClause, c *argparser.OptionalString, g *global.Data) {
 	command.Flag("format", "Apache style log formatting. Your log must produce valid JSON").Action(c.Set).StringVar(&c.Value)
+
+	var path string
+	command.Flag("format-file", "Path to a file containing the Apache style log format (mutually exclusive with --format)").Action(func(_ *kingpin.ParseElement, ctx *kingpin.ParseContext) error {
+		if _, ok := ctx.Elements.FlagMap()["format"]; ok {
+			return fmt.Errorf("error parsing arguments: the --format flag is mutually exclusive with the --format-file flag")
+		}
+		// G304 (CWE-22): Potential file inclusion via variable
+		// #nosec
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("failed to read --format-file: %w", err),
+				Remediation: fsterr.HostRemediation,
+			}
+		}
+		c.Value = strings.TrimRight(string(data), "\r\n")
+		c.WasSet = true
+		return nil
+	}).StringVar(&path)
+
+	var edit bool
+	command.Flag("edit", "Write the log format in your editor ($VISUAL or $EDITOR), starting from a JSON template").Action(func(_ *kingpin.ParseElement, ctx *kingpin.ParseContext) error {
+		if argparser.ContextHasHelpFlag(ctx) {
+			return nil
+		}
+		flags := ctx.Elements.FlagMap()
+		for _, name := range []string{"format", "format-file"} {
+			if _, ok := flags[name]; ok {
+				return fmt.Errorf("error parsing arguments: the --edit flag is mutually exclusive with the --%s flag", name)
+			}
+		}
+		format, err := argparser.Edit(argparser.EditOpts{
+			Globals:  g,
+			Initial:  jsonFormat,
+			Name:     "log-format",
+			Validate: ValidateFormat,
+		})
+		if err != nil {
+			return err
+		}
+		c.Value, c.WasSet = format, true
+		return nil
+	}).BoolVar(&edit)
+}
+
+// ValidateFormat checks the braces in a log format are balanced. A brace
+// escaped with a backslash (e.g. in a strftime pattern) is ignored.
+func ValidateFormat(format string) error {
+	var open []int
+	escaped := false
+	for i, r := range []rune(format) {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '{':
+			open = append(open, i)
+		case r == '}':
+			if len(open) == 0 {
+				return invalidFormatError(fmt.Errorf("invalid log format: unexpected '}' at character %d", i+1))
+			}
+			open = open[:len(open)-1]
+		}
+	}
+	if len(open) > 0 {
+		return invalidFormatError(fmt.Errorf("invalid log format: unclosed '{' at character %d", open[len(open)-1]+1))
+	}
+	return nil
+}
+
+func invalidFormatError(err error) error {
+	return fsterr.RemediationError{
+		Inner:       err,
+		Remediation: "Every '{' in a log format must be closed by a '}', e.g. %{req.url}V. See https://docs.fastly.com/en/guides/custom-log-formats",
+	}
 }
 
 // GzipLevel defines the gzip flag.
@@ -0,0 +1,27 @@
+package common_test
+
+import (
+	"testing"
+
+	"github.com/fastly/cli/pkg/commands/logging/common"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestValidateFormat(t *testing.T) {
+	for _, testcase := range []struct {
+		format    string
+		wantError string
+	}{
+		{format: `%h %l %u %t "%r" %>s %b`},
+		{format: `{"url":"%{json.escape(req.url)}V"}`},
+		{format: `%{strftime(\{"%Y-%m-%d"\}, time.start)}V`},
+		{format: `{"url":"%{req.url}V"`, wantError: "unclosed '{' at character 1"},
+		{format: `%{req.url}V}`, wantError: "unexpected '}' at character 12"},
+		{format: `%{strftime(\{"%Y"}, time.start)}V`, wantError: "unexpected '}' at character 32"},
+	} {
+		t.Run(testcase.format, func(t *testing.T) {
+			err := common.ValidateFormat(testcase.format)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+		})
+	}
+}
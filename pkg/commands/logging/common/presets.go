@@ -0,0 +1,285 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fastly/kingpin"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// jsonFormat is the recommended log format line for providers which ingest
+// JSON.
+const jsonFormat = `{"timestamp":"%{strftime(\{"%Y-%m-%dT%H:%M:%S%z"\}, time.start)}V","client_ip":"%{req.http.Fastly-Client-IP}V","geo_country":"%{client.geo.country_name}V","url":"%{json.escape(req.url)}V","request_method":"%{json.escape(req.method)}V","response_status":%{resp.status}V,"response_body_size":%{resp.body_bytes_written}V,"cache_status":"%{fastly_info.state}V","service_version":"%{req.vcl.version}V"}`
+
+// PresetField is a value a preset requires but can't default, and so must be
+// provided via a flag or prompted for.
+type PresetField struct {
+	// Flag is the name of the flag (without the leading dashes).
+	Flag string
+	// Prompt is displayed when asking the user for the value.
+	Prompt string
+	// Secret indicates the value shouldn't be echoed or displayed.
+	Secret bool
+	// Unless lists flags which, when set, make this field unnecessary (e.g. an
+	// IAM role instead of an access key).
+	Unless []string
+}
+
+// Preset is a template of sensible defaults for a logging provider.
+type Preset struct {
+	// Name is the value passed to the --preset flag.
+	Name string
+	// Provider is the logging command the preset applies to (e.g. s3).
+	Provider string
+	// Description summarises the preset.
+	Description string
+	// Format is the recommended log format line.
+	Format string
+	// FormatVersion is the log format version.
+	FormatVersion int
+	// MessageType is the message type (only for providers supporting it).
+	MessageType string
+	// Required are the fields which can't be defaulted.
+	Required []PresetField
+}
+
+// Presets is the built-in catalog of logging endpoint presets.
+var Presets = []Preset{
+	{
+		Name:          "bigquery",
+		Provider:      "bigquery",
+		Description:   "JSON rows for a BigQuery table whose columns match the format's keys",
+		Format:        jsonFormat,
+		FormatVersion: 2,
+		Required: []PresetField{
+			{Flag: "project-id", Prompt: "Google Cloud project ID: "},
+			{Flag: "dataset", Prompt: "BigQuery dataset: "},
+			{Flag: "table", Prompt: "BigQuery table: "},
+			{Flag: "user", Prompt: "Service account email address: "},
+			{Flag: "secret-key", Prompt: "Service account private key: ", Secret: true},
+		},
+	},
+	{
+		Name:          "datadog",
+		Provider:      "datadog",
+		Description:   "JSON events for Datadog log management",
+		Format:        jsonFormat,
+		FormatVersion: 2,
+		Required: []PresetField{
+			{Flag: "auth-token", Prompt: "Datadog API key: ", Secret: true},
+		},
+	},
+	{
+		Name:          "s3-combined",
+		Provider:      "s3",
+		Description:   "Apache combined log lines, readable by most log analysis tools",
+		Format:        `%h %l %u %t "%r" %>s %b "%{Referer}i" "%{User-Agent}i"`,
+		FormatVersion: 2,
+		MessageType:   "blank",
+		Required:      s3Required,
+	},
+	{
+		Name:          "s3-json",
+		Provider:      "s3",
+		Description:   "Newline delimited JSON, suitable for Athena or other query engines",
+		Format:        jsonFormat,
+		FormatVersion: 2,
+		MessageType:   "blank",
+		Required:      s3Required,
+	},
+	{
+		Name:          "splunk",
+		Provider:      "splunk",
+		Description:   "JSON events for the Splunk HTTP Event Collector",
+		Format:        jsonFormat,
+		FormatVersion: 2,
+		Required: []PresetField{
+			{Flag: "url", Prompt: "Splunk HTTP Event Collector URL: "},
+			{Flag: "auth-token", Prompt: "Splunk HEC token: ", Secret: true},
+		},
+	},
+}
+
+var s3Required = []PresetField{
+	{Flag: "bucket", Prompt: "S3 bucket name: "},
+	{Flag: "access-key", Prompt: "S3 access key: ", Unless: []string{"iam-role"}},
+	{Flag: "secret-key", Prompt: "S3 secret key: ", Secret: true, Unless: []string{"iam-role"}},
+}
+
+// PresetNames returns the names of the presets for the given provider.
+func PresetNames(provider string) []string {
+	var names []string
+	for _, p := range Presets {
+		if p.Provider == provider {
+			names = append(names, p.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PresetFlag defines the preset flag.
+func PresetFlag(command *kingpin.CmdClause, provider string, c *argparser.OptionalString) {
+	names := PresetNames(provider)
+	command.Flag("preset", fmt.Sprintf("Create the endpoint from a built-in template of recommended defaults. One of: %s", strings.Join(names, ", "))).HintOptions(names...).Action(c.Set).EnumVar(&c.Value, names...)
+}
+
+// PresetOpts are the destinations a preset is applied to.
+type PresetOpts struct {
+	// Provider is the logging command being run.
+	Provider string
+	// Format is the --format flag value.
+	Format *argparser.OptionalString
+	// FormatVersion is the --format-version flag value.
+	FormatVersion *argparser.OptionalInt
+	// MessageType is the --message-type flag value (nil if unsupported).
+	MessageType *argparser.OptionalString
+	// Fields maps a flag name to its value, for the fields a preset requires.
+	Fields map[string]*argparser.OptionalString
+}
+
+// ErrPresetNotConfirmed indicates the user declined the preset configuration.
+var ErrPresetNotConfirmed = errors.New("logging endpoint creation cancelled")
+
+// ApplyPreset merges the named preset into opts, without overriding any flag
+// the user explicitly set. Values the preset requires but can't default are
+// prompted for, then the final configuration is displayed for confirmation.
+//
+// In non-interactive mode missing values are an error, and with --auto-yes the
+// confirmation is skipped.
+func ApplyPreset(name string, opts PresetOpts, g *global.Data, in io.Reader, out io.Writer) error {
+	var preset *Preset
+	for i := range Presets {
+		if Presets[i].Name == name && Presets[i].Provider == opts.Provider {
+			preset = &Presets[i]
+		}
+	}
+	if preset == nil {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("unknown %s logging preset: %s", opts.Provider, name),
+			Remediation: fmt.Sprintf("Use one of: %s", strings.Join(PresetNames(opts.Provider), ", ")),
+		}
+	}
+
+	if !opts.Format.WasSet {
+		opts.Format.Value, opts.Format.WasSet = preset.Format, true
+	}
+	if !opts.FormatVersion.WasSet {
+		opts.FormatVersion.Value, opts.FormatVersion.WasSet = preset.FormatVersion, true
+	}
+	if opts.MessageType != nil && preset.MessageType != "" && !opts.MessageType.WasSet {
+		opts.MessageType.Value, opts.MessageType.WasSet = preset.MessageType, true
+	}
+
+	interactive := !g.Flags.NonInteractive && in != nil
+	var missing []string
+	for _, f := range preset.Required {
+		dst := opts.Fields[f.Flag]
+		if dst == nil || dst.WasSet || anySet(opts.Fields, f.Unless) {
+			continue
+		}
+		if !interactive {
+			missing = append(missing, "--"+f.Flag)
+			continue
+		}
+		input := text.Input
+		if f.Secret {
+			input = text.InputSecure
+		}
+		v, err := input(out, f.Prompt, in, "--"+f.Flag, notEmpty(f.Flag))
+		if err != nil {
+			return fmt.Errorf("error reading input: %w", err)
+		}
+		dst.Value, dst.WasSet = v, true
+		if f.Secret {
+			fsterr.RegisterSecret(v)
+		}
+	}
+	if len(missing) > 0 {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("error parsing arguments: the %s preset requires %s", preset.Name, strings.Join(missing, ", ")),
+			Remediation: "Provide the missing flags, or run the command interactively to be prompted for them.",
+		}
+	}
+
+	printPreset(out, preset, opts)
+
+	if g.Flags.AutoYes || g.Flags.NonInteractive {
+		return nil
+	}
+	if in == nil {
+		return fsterr.RemediationError{
+			Inner:       ErrPresetNotConfirmed,
+			Remediation: "Pass the --auto-yes flag to create the endpoint without confirmation.",
+		}
+	}
+	cont, err := text.AskYesNo(out, "Create the logging endpoint with this configuration? [y/N] ", in, "--auto-yes")
+	if err != nil {
+		return err
+	}
+	if !cont {
+		return ErrPresetNotConfirmed
+	}
+	return nil
+}
+
+func printPreset(out io.Writer, preset *Preset, opts PresetOpts) {
+	text.Break(out)
+	text.Info(out, "Using the %s preset: %s", preset.Name, preset.Description)
+	text.Break(out)
+
+	tw := text.NewTable(out)
+	tw.AddHeader("FLAG", "VALUE")
+	tw.AddLine("--format-version", strconv.Itoa(opts.FormatVersion.Value))
+	if opts.MessageType != nil && opts.MessageType.WasSet {
+		tw.AddLine("--message-type", opts.MessageType.Value)
+	}
+	secrets := make(map[string]bool)
+	for _, f := range preset.Required {
+		secrets[f.Flag] = f.Secret
+	}
+	var flags []string
+	for f, v := range opts.Fields {
+		if v.WasSet {
+			flags = append(flags, f)
+		}
+	}
+	sort.Strings(flags)
+	for _, f := range flags {
+		v := opts.Fields[f].Value
+		if secrets[f] {
+			v = "********"
+		}
+		tw.AddLine("--"+f, v)
+	}
+	tw.AddLine("--format", opts.Format.Value)
+	tw.Print()
+	text.Break(out)
+}
+
+func anySet(fields map[string]*argparser.OptionalString, flags []string) bool {
+	for _, f := range flags {
+		if v := fields[f]; v != nil && v.WasSet {
+			return true
+		}
+	}
+	return false
+}
+
+func notEmpty(flag string) func(string) error {
+	return func(s string) error {
+		if s == "" {
+			return fmt.Errorf("--%s cannot be empty", flag)
+		}
+		return nil
+	}
+}
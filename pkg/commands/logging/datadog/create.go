@@ -28,6 +28,7 @@ type CreateCommand struct {
 	Format            argparser.OptionalString
 	FormatVersion     argparser.OptionalInt
 	Placement         argparser.OptionalString
+	Preset            argparser.OptionalString
 	Region            argparser.OptionalString
 	ResponseCondition argparser.OptionalString
 	Token             argparser.OptionalString
@@ -57,9 +58,10 @@ func NewCreateCommand(parent argparser.Registerer, g *global.Data) *CreateComman
 		Dst:    &c.AutoClone.Value,
 	})
 	c.CmdClause.Flag("auth-token", "The API key from your Datadog account").Action(c.Token.Set).StringVar(&c.Token.Value)
-	common.Format(c.CmdClause, &c.Format)
+	common.Format(c.CmdClause, &c.Format, g)
 	common.FormatVersion(c.CmdClause, &c.FormatVersion)
 	common.Placement(c.CmdClause, &c.Placement)
+	common.PresetFlag(c.CmdClause, "datadog", &c.Preset)
 	c.CmdClause.Flag("region", "The region that log data will be sent to. One of US, US3, US5, or EU. Defaults to US if undefined").Action(c.Region.Set).StringVar(&c.Region.Value)
 	common.ResponseCondition(c.CmdClause, &c.ResponseCondition)
 	c.RegisterFlag(argparser.StringFlagOpts{
@@ -114,7 +116,21 @@ func (c *CreateCommand) ConstructInput(serviceID string, serviceVersion int) (*f
 }
 
 // Exec invokes the application logic for the command.
-func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
+func (c *CreateCommand) Exec(in io.Reader, out io.Writer) error {
+	if c.Preset.WasSet {
+		err := common.ApplyPreset(c.Preset.Value, common.PresetOpts{
+			Provider:      "datadog",
+			Format:        &c.Format,
+			FormatVersion: &c.FormatVersion,
+			Fields: map[string]*argparser.OptionalString{
+				"auth-token": &c.Token,
+			},
+		}, c.Globals, in, out)
+		if err != nil {
+			return err
+		}
+	}
+
 	serviceID, serviceVersion, err := argparser.ServiceDetails(argparser.ServiceDetailsOpts{
 		AutoCloneFlag:      c.AutoClone,
 		APIClient:          c.Globals.APIClient,
@@ -30,7 +30,7 @@ func NewDescribeCommand(parent argparser.Registerer, g *global.Data) *DescribeCo
 	})
 
 	// Optional.
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	c.RegisterFlag(argparser.StringFlagOpts{
 		Name:        argparser.FlagServiceIDName,
 		Description: argparser.FlagServiceIDDesc,
@@ -122,13 +122,13 @@ func (c *DescribeCommand) print(out io.Writer, nr *fastly.NewRelic) error {
 		"Token":              fastly.ToValue(nr.Token),
 	}
 	if nr.CreatedAt != nil {
-		lines["Created at"] = nr.CreatedAt
+		lines["Created at"] = text.FormatTime(nr.CreatedAt)
 	}
 	if nr.UpdatedAt != nil {
-		lines["Updated at"] = nr.UpdatedAt
+		lines["Updated at"] = text.FormatTime(nr.UpdatedAt)
 	}
 	if nr.DeletedAt != nil {
-		lines["Deleted at"] = nr.DeletedAt
+		lines["Deleted at"] = text.FormatTime(nr.DeletedAt)
 	}
 
 	if !c.Globals.Verbose() {
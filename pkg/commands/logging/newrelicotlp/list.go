@@ -20,6 +20,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 		},
 	}
 	c.CmdClause = parent.Command("list", "List all of the New Relic OTLP Logs logging objects for a particular service and version")
+	c.EmptyState = argparser.EmptyState{Resource: "New Relic OTLP logging endpoints", Create: "fastly logging newrelicotlp create --version <VERSION> --name <NAME>"}
 
 	// Required.
 	c.RegisterFlag(argparser.StringFlagOpts{
@@ -30,7 +31,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 	})
 
 	// Optional.
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	c.RegisterFlag(argparser.StringFlagOpts{
 		Name:        argparser.FlagServiceIDName,
 		Description: argparser.FlagServiceIDDesc,
@@ -51,6 +52,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 
 	serviceName    argparser.OptionalServiceNameID
 	serviceVersion argparser.OptionalServiceVersion
@@ -94,6 +96,10 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
+	if c.PrintEmpty(out, len(o)) {
+		return nil
+	}
+
 	if c.Globals.Verbose() {
 		c.printVerbose(out, fastly.ToValue(serviceVersion.Number), o)
 	} else {
@@ -130,13 +136,13 @@ func (c *ListCommand) printVerbose(out io.Writer, serviceVersion int, ls []*fast
 		fmt.Fprintf(out, "\nResponse Condition: %s\n\n", fastly.ToValue(l.ResponseCondition))
 
 		if l.CreatedAt != nil {
-			fmt.Fprintf(out, "Created at: %s\n", l.CreatedAt)
+			fmt.Fprintf(out, "Created at: %s\n", text.FormatTime(l.CreatedAt))
 		}
 		if l.UpdatedAt != nil {
-			fmt.Fprintf(out, "Updated at: %s\n", l.UpdatedAt)
+			fmt.Fprintf(out, "Updated at: %s\n", text.FormatTime(l.UpdatedAt))
 		}
 		if l.DeletedAt != nil {
-			fmt.Fprintf(out, "Deleted at: %s\n", l.DeletedAt)
+			fmt.Fprintf(out, "Deleted at: %s\n", text.FormatTime(l.DeletedAt))
 		}
 	}
 }
@@ -43,6 +43,7 @@ type CreateCommand struct {
 	Path                         argparser.OptionalString
 	Period                       argparser.OptionalInt
 	Placement                    argparser.OptionalString
+	Preset                       argparser.OptionalString
 	PublicKey                    argparser.OptionalString
 	Redundancy                   argparser.OptionalString
 	ResponseCondition            argparser.OptionalString
@@ -79,7 +80,7 @@ func NewCreateCommand(parent argparser.Registerer, g *global.Data) *CreateComman
 	common.CompressionCodec(c.CmdClause, &c.CompressionCodec)
 	c.CmdClause.Flag("domain", "The domain of the S3 endpoint").Action(c.Domain.Set).StringVar(&c.Domain.Value)
 	c.CmdClause.Flag("file-max-bytes", "The maximum size of a log file in bytes").Action(c.FileMaxBytes.Set).IntVar(&c.FileMaxBytes.Value)
-	common.Format(c.CmdClause, &c.Format)
+	common.Format(c.CmdClause, &c.Format, g)
 	common.FormatVersion(c.CmdClause, &c.FormatVersion)
 	common.GzipLevel(c.CmdClause, &c.GzipLevel)
 	c.CmdClause.Flag("iam-role", "The IAM role ARN for logging").Action(c.IAMRole.Set).StringVar(&c.IAMRole.Value)
@@ -88,6 +89,7 @@ func NewCreateCommand(parent argparser.Registerer, g *global.Data) *CreateComman
 	common.Period(c.CmdClause, &c.Period)
 	common.Placement(c.CmdClause, &c.Placement)
 	common.PublicKey(c.CmdClause, &c.PublicKey)
+	common.PresetFlag(c.CmdClause, "s3", &c.Preset)
 	c.CmdClause.Flag("redundancy", "The S3 storage class. One of: standard, intelligent_tiering, standard_ia, onezone_ia, glacier, glacier_ir, deep_archive, or reduced_redundancy").Action(c.Redundancy.Set).EnumVar(&c.Redundancy.Value, string(fastly.S3RedundancyStandard), string(fastly.S3RedundancyIntelligentTiering), string(fastly.S3RedundancyStandardIA), string(fastly.S3RedundancyOneZoneIA), string(fastly.S3RedundancyGlacierFlexibleRetrieval), string(fastly.S3RedundancyGlacierInstantRetrieval), string(fastly.S3RedundancyGlacierDeepArchive), string(fastly.S3RedundancyReduced))
 	common.ResponseCondition(c.CmdClause, &c.ResponseCondition)
 	c.CmdClause.Flag("secret-key", "Your S3 account secret key").Action(c.SecretKey.Set).StringVar(&c.SecretKey.Value)
@@ -261,7 +263,25 @@ func ValidateRedundancy(val string) (redundancy fastly.S3Redundancy, err error)
 }
 
 // Exec invokes the application logic for the command.
-func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
+func (c *CreateCommand) Exec(in io.Reader, out io.Writer) error {
+	if c.Preset.WasSet {
+		err := common.ApplyPreset(c.Preset.Value, common.PresetOpts{
+			Provider:      "s3",
+			Format:        &c.Format,
+			FormatVersion: &c.FormatVersion,
+			MessageType:   &c.MessageType,
+			Fields: map[string]*argparser.OptionalString{
+				"bucket":     &c.BucketName,
+				"access-key": &c.AccessKey,
+				"secret-key": &c.SecretKey,
+				"iam-role":   &c.IAMRole,
+			},
+		}, c.Globals, in, out)
+		if err != nil {
+			return err
+		}
+	}
+
 	serviceID, serviceVersion, err := argparser.ServiceDetails(argparser.ServiceDetailsOpts{
 		AutoCloneFlag:      c.AutoClone,
 		APIClient:          c.Globals.APIClient,
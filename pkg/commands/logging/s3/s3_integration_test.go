@@ -4,12 +4,16 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/fastly/go-fastly/v9/fastly"
 
 	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/logging/common"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/mock"
 	"github.com/fastly/cli/pkg/testutil"
@@ -116,6 +120,200 @@ func TestS3Create(t *testing.T) {
 	}
 }
 
+func TestS3CreatePreset(t *testing.T) {
+	formatFile := filepath.Join(t.TempDir(), "format.txt")
+	if err := os.WriteFile(formatFile, []byte("%h \"%r\" %>s\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var s3JSON common.Preset
+	for _, p := range common.Presets {
+		if p.Name == "s3-json" {
+			s3JSON = p
+		}
+	}
+
+	scenarios := []struct {
+		name       string
+		args       string
+		stdin      string
+		wantError  string
+		wantOutput []string
+		wantInput  *fastly.CreateS3Input
+	}{
+		{
+			name: "preset merged with explicit flag overrides",
+			args: "logging s3 create --service-id 123 --version 3 --name log --bucket log --access-key foo --secret-key bar --preset s3-json --message-type classic --auto-yes",
+			wantOutput: []string{
+				"Using the s3-json preset",
+				"--message-type    classic",
+				"Created S3 logging endpoint log (service 123 version 3)",
+			},
+			wantInput: &fastly.CreateS3Input{
+				Format:        fastly.ToPointer(s3JSON.Format),
+				FormatVersion: fastly.ToPointer(2),
+				MessageType:   fastly.ToPointer("classic"),
+			},
+		},
+		{
+			name: "format file overrides preset format",
+			args: "logging s3 create --service-id 123 --version 3 --name log --bucket log --iam-role arn --preset s3-combined --format-file " + formatFile + " --auto-yes",
+			wantInput: &fastly.CreateS3Input{
+				Format:        fastly.ToPointer(`%h "%r" %>s`),
+				FormatVersion: fastly.ToPointer(2),
+				MessageType:   fastly.ToPointer("blank"),
+			},
+		},
+		{
+			name:      "format and format file are mutually exclusive",
+			args:      "logging s3 create --service-id 123 --version 3 --name log --bucket log --iam-role arn --format foo --format-file " + formatFile,
+			wantError: "the --format flag is mutually exclusive with the --format-file flag",
+		},
+		{
+			name:      "missing format file",
+			args:      "logging s3 create --service-id 123 --version 3 --name log --bucket log --iam-role arn --format-file " + formatFile + ".missing",
+			wantError: "failed to read --format-file",
+		},
+		{
+			name:  "prompts for required fields",
+			args:  "logging s3 create --service-id 123 --version 3 --name log --preset s3-json",
+			stdin: "my-bucket\nmy-access-key\nmy-secret-key\ny\n",
+			wantOutput: []string{
+				"S3 bucket name: ",
+				"--bucket          my-bucket",
+				"--secret-key      ********",
+				"Created S3 logging endpoint log",
+			},
+			wantInput: &fastly.CreateS3Input{
+				BucketName: fastly.ToPointer("my-bucket"),
+				AccessKey:  fastly.ToPointer("my-access-key"),
+				SecretKey:  fastly.ToPointer("my-secret-key"),
+			},
+		},
+		{
+			name:      "non-interactive requires all fields",
+			args:      "logging s3 create --service-id 123 --version 3 --name log --bucket log --preset s3-json --non-interactive",
+			wantError: "the s3-json preset requires --access-key, --secret-key",
+		},
+		{
+			name:      "confirmation declined",
+			args:      "logging s3 create --service-id 123 --version 3 --name log --bucket log --iam-role arn --preset s3-json",
+			stdin:     "n\n",
+			wantError: common.ErrPresetNotConfirmed.Error(),
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			var (
+				stdout bytes.Buffer
+				input  *fastly.CreateS3Input
+			)
+			args := testutil.Args(testcase.args)
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIClientFactory = mock.APIClient(mock.API{
+					ListVersionsFn: testutil.ListVersions,
+					CreateS3Fn: func(i *fastly.CreateS3Input) (*fastly.S3, error) {
+						input = i
+						return createS3OK(i)
+					},
+				})
+				// NOTE: Each prompt creates its own scanner and so the input must be
+				// read a byte at a time to avoid one prompt consuming the next line.
+				opts.Input = iotest.OneByteReader(strings.NewReader(testcase.stdin))
+				return opts, nil
+			}
+			err := app.Run(args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, want := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), want)
+			}
+			testutil.AssertStringDoesntContain(t, stdout.String(), "my-secret-key")
+			if testcase.wantInput == nil {
+				return
+			}
+			if input == nil {
+				t.Fatal("expected CreateS3 to be called")
+			}
+			want := testcase.wantInput
+			if want.Format != nil {
+				testutil.AssertString(t, *want.Format, fastly.ToValue(input.Format))
+			}
+			if want.FormatVersion != nil {
+				testutil.AssertEqual(t, *want.FormatVersion, fastly.ToValue(input.FormatVersion))
+			}
+			if want.MessageType != nil {
+				testutil.AssertString(t, *want.MessageType, fastly.ToValue(input.MessageType))
+			}
+			if want.BucketName != nil {
+				testutil.AssertString(t, *want.BucketName, fastly.ToValue(input.BucketName))
+				testutil.AssertString(t, *want.AccessKey, fastly.ToValue(input.AccessKey))
+				testutil.AssertString(t, *want.SecretKey, fastly.ToValue(input.SecretKey))
+			}
+		})
+	}
+}
+
+func TestS3CreateEdit(t *testing.T) {
+	scenarios := []struct {
+		name       string
+		args       string
+		edited     string
+		wantError  string
+		wantFormat string
+	}{
+		{
+			name:       "edited format",
+			args:       "logging s3 create --service-id 123 --version 3 --name log --bucket log --iam-role arn --edit",
+			edited:     "{\"url\":\"%{json.escape(req.url)}V\"}\n",
+			wantFormat: `{"url":"%{json.escape(req.url)}V"}`,
+		},
+		{
+			name:      "unbalanced braces",
+			args:      "logging s3 create --service-id 123 --version 3 --name log --bucket log --iam-role arn --edit",
+			edited:    "{\"url\":\"%{json.escape(req.url)V\"}\n",
+			wantError: "invalid log format: unclosed '{' at character 1",
+		},
+		{
+			name:      "edit and format are mutually exclusive",
+			args:      "logging s3 create --service-id 123 --version 3 --name log --bucket log --iam-role arn --format foo --edit",
+			wantError: "the --edit flag is mutually exclusive with the --format flag",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			var (
+				stdout bytes.Buffer
+				input  *fastly.CreateS3Input
+			)
+			args := testutil.Args(testcase.args)
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIClientFactory = mock.APIClient(mock.API{
+					ListVersionsFn: testutil.ListVersions,
+					CreateS3Fn: func(i *fastly.CreateS3Input) (*fastly.S3, error) {
+						input = i
+						return createS3OK(i)
+					},
+				})
+				opts.ExecuteEditor = func(editor []string) error {
+					return os.WriteFile(editor[len(editor)-1], []byte(testcase.edited), 0o600)
+				}
+				return opts, nil
+			}
+			err := app.Run(args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if testcase.wantFormat == "" {
+				return
+			}
+			if input == nil {
+				t.Fatal("expected CreateS3 to be called")
+			}
+			testutil.AssertString(t, testcase.wantFormat, fastly.ToValue(input.Format))
+		})
+	}
+}
+
 func TestS3List(t *testing.T) {
 	args := testutil.Args
 	scenarios := []struct {
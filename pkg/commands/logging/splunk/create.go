@@ -28,6 +28,7 @@ type CreateCommand struct {
 	Format            argparser.OptionalString
 	FormatVersion     argparser.OptionalInt
 	Placement         argparser.OptionalString
+	Preset            argparser.OptionalString
 	ResponseCondition argparser.OptionalString
 	TimestampFormat   argparser.OptionalString
 	TLSCACert         argparser.OptionalString
@@ -62,9 +63,10 @@ func NewCreateCommand(parent argparser.Registerer, g *global.Data) *CreateComman
 		Action: c.AutoClone.Set,
 		Dst:    &c.AutoClone.Value,
 	})
-	common.Format(c.CmdClause, &c.Format)
+	common.Format(c.CmdClause, &c.Format, g)
 	common.FormatVersion(c.CmdClause, &c.FormatVersion)
 	common.Placement(c.CmdClause, &c.Placement)
+	common.PresetFlag(c.CmdClause, "splunk", &c.Preset)
 	common.ResponseCondition(c.CmdClause, &c.ResponseCondition)
 	c.RegisterFlag(argparser.StringFlagOpts{
 		Name:        argparser.FlagServiceIDName,
@@ -139,7 +141,22 @@ func (c *CreateCommand) ConstructInput(serviceID string, serviceVersion int) (*f
 }
 
 // Exec invokes the application logic for the command.
-func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
+func (c *CreateCommand) Exec(in io.Reader, out io.Writer) error {
+	if c.Preset.WasSet {
+		err := common.ApplyPreset(c.Preset.Value, common.PresetOpts{
+			Provider:      "splunk",
+			Format:        &c.Format,
+			FormatVersion: &c.FormatVersion,
+			Fields: map[string]*argparser.OptionalString{
+				"url":        &c.URL,
+				"auth-token": &c.Token,
+			},
+		}, c.Globals, in, out)
+		if err != nil {
+			return err
+		}
+	}
+
 	serviceID, serviceVersion, err := argparser.ServiceDetails(argparser.ServiceDetailsOpts{
 		AutoCloneFlag:      c.AutoClone,
 		APIClient:          c.Globals.APIClient,
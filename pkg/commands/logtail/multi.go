@@ -0,0 +1,274 @@
+package logtail
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+	"github.com/fatih/color"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/commands/service/tag"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// labelColors are the colors of the labels of each service's output, assigned
+// in the order the services are tailed.
+var labelColors = []color.Attribute{
+	color.FgCyan,
+	color.FgMagenta,
+	color.FgYellow,
+	color.FgGreen,
+	color.FgBlue,
+	color.FgRed,
+}
+
+// target is a service whose logs are tailed.
+type target struct {
+	// id is the service ID.
+	id string
+	// label identifies the service's output when tailing multiple services,
+	// and is empty when tailing a single service.
+	label string
+}
+
+// targets returns the services selected by --service or --tag, or the single
+// service selected by --service-id, --service-name or the manifest.
+func (c *RootCommand) targets(out io.Writer) ([]target, error) {
+	g := c.Globals
+	if len(c.services) == 0 && len(c.tagFilter) == 0 {
+		serviceID, source, flag, err := argparser.ServiceID(c.serviceName, *g.Manifest, g.APIClient, g.ErrLog)
+		if err != nil {
+			return nil, err
+		}
+		if g.Verbose() {
+			argparser.DisplayServiceID(serviceID, flag, source, out)
+		}
+		return []target{{id: serviceID}}, nil
+	}
+
+	switch {
+	case g.Manifest.Flag.ServiceID != "" || c.serviceName.WasSet:
+		return nil, fmt.Errorf("error parsing arguments: --service and --tag cannot be used with --service-id or --service-name")
+	case len(c.services) > 0 && len(c.tagFilter) > 0:
+		return nil, fmt.Errorf("error parsing arguments: --service cannot be used with --tag")
+	}
+	filter, err := tag.ParseFilter(c.tagFilter)
+	if err != nil {
+		return nil, err
+	}
+	all, err := argparser.ListServices(g.APIClient)
+	if err != nil {
+		g.ErrLog.Add(err)
+		return nil, err
+	}
+
+	var selected []*fastly.Service
+	if len(c.tagFilter) > 0 {
+		for _, s := range all {
+			if filter.Match(fastly.ToValue(s.Comment)) {
+				selected = append(selected, s)
+			}
+		}
+		if len(selected) == 0 {
+			return nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("no services matched the --tag filter"),
+				Remediation: "Run `fastly service list --tag <expression>` to check which services a filter matches.",
+			}
+		}
+	}
+	for _, v := range c.services {
+		s, err := findService(v, all)
+		if err != nil {
+			g.ErrLog.Add(err)
+			return nil, err
+		}
+		selected = append(selected, s)
+	}
+
+	var targets []target
+	seen := make(map[string]bool)
+	for _, s := range selected {
+		id := fastly.ToValue(s.ServiceID)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		label := fastly.ToValue(s.Name)
+		if label == "" {
+			label = id
+		}
+		targets = append(targets, target{id: id, label: label})
+	}
+	return targets, nil
+}
+
+// findService returns the service with the ID or name.
+func findService(idOrName string, services []*fastly.Service) (*fastly.Service, error) {
+	for _, s := range services {
+		if fastly.ToValue(s.ServiceID) == idOrName {
+			return s, nil
+		}
+	}
+	id, err := argparser.MatchServiceName(idOrName, services)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range services {
+		if fastly.ToValue(s.ServiceID) == id {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("service '%s' not found", idOrName)
+}
+
+// session returns a copy of the command that tails the logs of the target
+// service. The sessions share dieCh, so stopping the command stops them all,
+// but each has its own output loop and reconnects independently.
+func (c *RootCommand) session(t target, endpoint string) *RootCommand {
+	s := *c
+	s.Input.ServiceID = t.id
+	s.batchCh = make(chan Batch)
+	s.cfg.path = fmt.Sprintf("%s/service/%s/log_stream/managed/instance_output", endpoint, t.id)
+	s.doneCh = make(chan struct{})
+	s.service = t.label
+	return &s
+}
+
+// tailAll tails the logs of each session concurrently, until every session
+// has finished (e.g. reached --to) or the command is stopped.
+//
+// When tailing multiple services, each line of output is prefixed with the
+// label of the service it came from (JSON lines include a service field
+// instead), and a session that fails is reported without stopping the others.
+// Messages about the sessions are written to stderr (see diagnostics).
+func (c *RootCommand) tailAll(sessions []*RootCommand, out io.Writer) error {
+	var width int
+	for _, s := range sessions {
+		width = max(width, len(s.service))
+	}
+
+	type result struct {
+		session *RootCommand
+		err     error
+	}
+	results := make(chan result, len(sessions))
+	for i, s := range sessions {
+		logOut, msgOut := out, c.diagnostics(out)
+		var logPW, msgPW *prefixWriter
+		if s.service != "" {
+			label := fmt.Sprintf("[%-*s]", width, s.service)
+			prefix := color.New(labelColors[i%len(labelColors)]).Sprint(label) + " "
+			msgPW = newPrefixWriter(msgOut, prefix)
+			msgOut = msgPW
+			if !c.cfg.jsonl {
+				logPW = newPrefixWriter(out, prefix)
+				logOut = logPW
+			}
+		}
+		go s.outputLoop(logOut)
+		go func(s *RootCommand) {
+			err := s.tail(msgOut)
+			if msgPW != nil {
+				if err != nil && !s.stopping() {
+					text.Error(msgPW, "stopped tailing the logs of service %s: %v", s.Input.ServiceID, err)
+				}
+				msgPW.Flush()
+			}
+			if logPW != nil {
+				logPW.Flush()
+			}
+			results <- result{s, err}
+		}(s)
+	}
+
+	// NOTE: Once the command is stopped, the sessions are waited for (so none
+	// is left mid-request) but the errors caused by stopping them are ignored.
+	done := c.Globals.Context.Done()
+	var errs fsterr.MultiError
+	for remaining := len(sessions); remaining > 0; {
+		select {
+		case r := <-results:
+			remaining--
+			if r.err == nil || done == nil {
+				continue
+			}
+			if len(sessions) == 1 {
+				close(c.dieCh)
+				return r.err
+			}
+			errs = append(errs, fmt.Errorf("error tailing the logs of service %s (%s): %w", r.session.service, r.session.Input.ServiceID, r.err))
+		case <-done:
+			close(c.dieCh)
+			done = nil
+		}
+	}
+	if done != nil {
+		close(c.dieCh)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return argparser.PartialResults(c.Globals)
+}
+
+// prefixWriter is an io.Writer that prefixes each line written with a label,
+// so the output of concurrent sessions can be told apart. Each line is
+// written to the underlying writer with a single call, so lines from
+// different sessions don't interleave.
+type prefixWriter struct {
+	mu      sync.Mutex
+	partial []byte
+	prefix  string
+	w       io.Writer
+}
+
+// newPrefixWriter returns a prefixWriter that writes to w.
+func newPrefixWriter(w io.Writer, prefix string) *prefixWriter {
+	return &prefixWriter{prefix: prefix, w: w}
+}
+
+// Write implements the io.Writer interface. A line without a trailing
+// newline is buffered until it's completed (or Flush is called).
+func (pw *prefixWriter) Write(p []byte) (int, error) {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	pw.partial = append(pw.partial, p...)
+	for {
+		i := bytes.IndexByte(pw.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := pw.partial[:i+1]
+		pw.partial = pw.partial[i+1:]
+		if err := pw.writeLine(line); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush writes any buffered partial line.
+func (pw *prefixWriter) Flush() {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	if len(pw.partial) > 0 {
+		_ = pw.writeLine(append(pw.partial, '\n'))
+		pw.partial = nil
+	}
+}
+
+// writeLine writes the line with the prefix. Blank lines (e.g. the spacing
+// around a warning) are written without the prefix.
+func (pw *prefixWriter) writeLine(line []byte) error {
+	if strings.TrimSpace(string(line)) == "" {
+		_, err := pw.w.Write(line)
+		return err
+	}
+	_, err := pw.w.Write(append([]byte(pw.prefix), line...))
+	return err
+}
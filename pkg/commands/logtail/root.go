@@ -9,22 +9,31 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"os"
-	"os/signal"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/fastly/go-fastly/v9/fastly"
 	"github.com/tomnomnom/linkheader"
 
 	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/text"
 )
 
+// Streams are the Compute output streams that can be selected with --stream.
+var Streams = []string{"stdout", "stderr", "both"}
+
+// The delays used when reconnecting after a transient error. The delay doubles
+// on each consecutive failure and resets once a request succeeds.
+var (
+	retryBaseDelay = 1 * time.Second
+	retryMaxDelay  = 30 * time.Second
+)
+
 // RootCommand is the parent command for all subcommands in this package.
 // It should be installed under the primary root command.
 type RootCommand struct {
@@ -36,8 +45,11 @@ type RootCommand struct {
 	dieCh       chan struct{} // channel to end output/printing
 	doneCh      chan struct{} // channel to signal we've reached the end of the run
 	hClient     *http.Client  // TODO: this will go away when GET is in go-fastly
+	service     string        // labels the logs when tailing multiple services
 	serviceName argparser.OptionalServiceNameID
-	token       string // TODO: this will go away when GET is in go-fastly
+	services    []string // --service values
+	tagFilter   []string // --tag values
+	token       string   // TODO: this will go away when GET is in go-fastly
 }
 
 // NewRootCommand returns a new command registered in the parent.
@@ -61,30 +73,42 @@ func NewRootCommand(parent argparser.Registerer, g *global.Data) *RootCommand {
 	c.CmdClause.Flag("to", "To time, in Unix seconds").Int64Var(&c.cfg.to)
 	c.CmdClause.Flag("sort-buffer", "Duration of sort buffer for received logs").Default("1s").DurationVar(&c.cfg.sortBuffer)
 	c.CmdClause.Flag("search-padding", "Time beyond from/to to consider in searches").Default("2s").DurationVar(&c.cfg.searchPadding)
-	c.CmdClause.Flag("stream", "Output: stdout, stderr, both (default)").StringVar(&c.cfg.stream)
+	c.CmdClause.Flag("stream", "Output: stdout, stderr, both (default)").HintOptions(Streams...).EnumVar(&c.cfg.stream, Streams...)
+	c.CmdClause.Flag("filter", "Only display logs whose message matches this regular expression").StringVar(&c.cfg.filter)
+	c.CmdClause.Flag("jsonl", "Render each log as a JSON object on its own line (timestamp, stream, instance, message, and service when tailing multiple services)").BoolVar(&c.cfg.jsonl)
+	c.CmdClause.Flag("service", "Tail the logs of the service with this name or ID (repeat to tail several services at once, merging their output)").StringsVar(&c.services)
+	c.CmdClause.Flag("tag", "Tail the logs of every service whose tags match the expression: key=value, key!=value, key or !key (repeat to require every expression to match)").StringsVar(&c.tagFilter)
 	return &c
 }
 
+// Interruptible implements the argparser.Interruptible interface. Logs are
+// streamed until interrupted.
+func (c *RootCommand) Interruptible() bool {
+	return true
+}
+
 // Exec implements the command interface.
 func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
-	serviceID, source, flag, err := argparser.ServiceID(c.serviceName, *c.Globals.Manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	targets, err := c.targets(out)
 	if err != nil {
 		return err
 	}
-	if c.Globals.Verbose() {
-		argparser.DisplayServiceID(serviceID, flag, source, out)
-	}
 
-	c.Input.ServiceID = serviceID
+	if c.cfg.filter != "" {
+		c.cfg.filterRegex, err = regexp.Compile(c.cfg.filter)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("invalid --filter regular expression: %w", err),
+				Remediation: "Ensure the --filter value is a valid regular expression (https://pkg.go.dev/regexp/syntax).",
+			}
+		}
+	}
 
 	c.Input.Kind = fastly.ManagedLoggingInstanceOutput
 	endpoint, _ := c.Globals.APIEndpoint()
-	c.cfg.path = fmt.Sprintf("%s/service/%s/log_stream/managed/instance_output", endpoint, c.Input.ServiceID)
 
 	c.dieCh = make(chan struct{})
-	c.batchCh = make(chan Batch)
-	c.doneCh = make(chan struct{})
-
 	c.hClient = http.DefaultClient
 	c.token, _ = c.Globals.Token()
 
@@ -92,35 +116,17 @@ func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
 	// defined. We adjust the times based on searchPadding.
 	c.adjustTimes()
 
-	// Enable managed logging if not already enabled.
-	if err := c.enableManagedLogging(out); err != nil {
-		c.Globals.ErrLog.Add(err)
-		return err
-	}
-
-	failure := make(chan error)
-	sigs := make(chan os.Signal, 2)
-	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
-
-	// Start the output loop.
-	go c.outputLoop(out)
-
-	// Start tailing the logs.
-	go func() {
-		failure <- c.tail(out)
-	}()
-
-	select {
-	case asyncErr := <-failure:
-		close(c.dieCh)
-		return asyncErr
-	case <-c.doneCh:
-		return nil
-	case <-sigs:
-		close(c.dieCh)
+	sessions := make([]*RootCommand, 0, len(targets))
+	for _, t := range targets {
+		s := c.session(t, endpoint)
+		// Enable managed logging if not already enabled.
+		if err := s.enableManagedLogging(c.diagnostics(out)); err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+		sessions = append(sessions, s)
 	}
-
-	return nil
+	return c.tailAll(sessions, out)
 }
 
 // Tail starts the virtual tail process. Tail fetches data from the eventbuffer
@@ -141,6 +147,10 @@ func (c *RootCommand) tail(out io.Writer) error {
 	// re-request on failure.
 	var lastBatchID string
 
+	// failures counts consecutive failed requests and is used to calculate the
+	// backoff before reconnecting.
+	var failures int
+
 	for {
 		// Check to see if we already passed the "to" requirement.
 		if toWindow != 0 && curWindow > toWindow {
@@ -162,7 +172,23 @@ func (c *RootCommand) tail(out io.Writer) error {
 		resp, err := c.doReq(req)
 		if err != nil {
 			c.Globals.ErrLog.Add(err)
-			return fmt.Errorf("unable to execute request: %w", err)
+			if c.stopping() {
+				return fmt.Errorf("unable to execute request: %w", err)
+			}
+
+			// The stream was interrupted (e.g. a dropped connection), so
+			// reconnect from the last received cursor.
+			delay := backoff(failures)
+			failures++
+			text.Warning(out, "log stream interrupted (%v), reconnecting in %s", err, delay)
+			if !c.wait(delay) {
+				return nil
+			}
+			path, err = makeNewPath(path, curWindow, lastBatchID)
+			if err != nil {
+				return err
+			}
+			continue
 		}
 
 		// Check that our request was successful. If the server is
@@ -189,10 +215,13 @@ func (c *RootCommand) tail(out io.Writer) error {
 				c.Globals.ErrLog.Add(err)
 			}
 
-			// Try the response again after a 1 second wait.
+			// Try the response again after backing off.
 			if resp.StatusCode/100 == 5 && resp.StatusCode != 501 ||
 				resp.StatusCode == 429 {
-				time.Sleep(1 * time.Second)
+				if !c.wait(backoff(failures)) {
+					return nil
+				}
+				failures++
 				continue
 			}
 
@@ -232,8 +261,14 @@ func (c *RootCommand) tail(out io.Writer) error {
 				// anything fails along the way, we
 				// can re-request.
 				lastBatchID = batch.ID
-				// Send batch down batchCh to the output loop.
-				c.batchCh <- batch
+				// Send batch down batchCh to the output loop, unless
+				// the output loop has been stopped.
+				select {
+				case c.batchCh <- batch:
+				case <-c.dieCh:
+					_ = resp.Body.Close()
+					return nil
+				}
 			}
 		}
 		err = resp.Body.Close()
@@ -251,12 +286,17 @@ func (c *RootCommand) tail(out io.Writer) error {
 
 			// Something happened in the scanner, re-request the
 			// current batchID.
+			if !c.wait(backoff(failures)) {
+				return nil
+			}
+			failures++
 			path, err = makeNewPath(path, curWindow, lastBatchID)
 			if err != nil {
 				return err
 			}
 			continue
 		}
+		failures = 0
 
 		// Get our next time window to request.
 		_, next := getLinks(resp.Header)
@@ -279,6 +319,42 @@ func (c *RootCommand) tail(out io.Writer) error {
 	return nil
 }
 
+// wait blocks for the given duration, returning false if the command was
+// stopped in the meantime.
+func (c *RootCommand) wait(d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-c.dieCh:
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// stopping reports whether the command has been asked to stop.
+func (c *RootCommand) stopping() bool {
+	select {
+	case <-c.dieCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff returns the delay to wait before the next attempt, given the number
+// of consecutive failures so far.
+func backoff(failures int) time.Duration {
+	d := retryBaseDelay
+	for i := 0; i < failures && d < retryMaxDelay; i++ {
+		d *= 2
+	}
+	if d > retryMaxDelay {
+		d = retryMaxDelay
+	}
+	return d
+}
+
 // adjustTimes adjusts the passed in from and to flags based on the
 // specified padding.
 func (c *RootCommand) adjustTimes() {
@@ -294,6 +370,16 @@ func (c *RootCommand) adjustTimes() {
 	}
 }
 
+// diagnostics returns the output for messages about the log stream (e.g. a
+// reconnection), which are written to stderr so they're never mixed with the
+// logs (e.g. with --jsonl).
+func (c *RootCommand) diagnostics(out io.Writer) io.Writer {
+	if c.Globals.ErrOutput != nil {
+		return c.Globals.ErrOutput
+	}
+	return out
+}
+
 // enableManagedLogging enables managed logging in our API.
 func (c *RootCommand) enableManagedLogging(out io.Writer) error {
 	_, err := c.Globals.APIClient.CreateManagedLogging(&c.Input)
@@ -439,12 +525,21 @@ func (c *RootCommand) outputLoop(out io.Writer) {
 }
 
 // printLogs is a simple printer for Log slices, only printing requested
-// streams.
+// streams and messages matching the filter.
 func (c *RootCommand) printLogs(out io.Writer, logs []Log) {
 	if len(logs) > 0 {
-		filtered := filterStream(c.cfg.stream, logs)
+		filtered := filterMessage(c.cfg.filterRegex, filterStream(c.cfg.stream, logs))
 
 		for _, l := range filtered {
+			if c.cfg.jsonl {
+				b, err := l.JSONLine(c.service)
+				if err != nil {
+					c.Globals.ErrLog.Add(err)
+					continue
+				}
+				fmt.Fprintln(out, string(b))
+				continue
+			}
 			fmt.Fprintln(out, l.String())
 		}
 	}
@@ -491,6 +586,13 @@ type (
 		// customer wants to consume.
 		// Undefined == both stderr and stdout.
 		stream string
+		// filter is a regular expression that log messages must match
+		// in order to be printed.
+		filter string
+		// filterRegex is the compiled filter.
+		filterRegex *regexp.Regexp
+		// jsonl specifies whether logs should be printed as JSON lines.
+		jsonl bool
 	}
 
 	// Log defines the message envelope that the Compute platform wraps the
@@ -511,6 +613,15 @@ type (
 		Message string `json:"message"`
 	}
 
+	// jsonLog is the JSON lines representation of a Log.
+	jsonLog struct {
+		Timestamp string `json:"timestamp"`
+		Stream    string `json:"stream"`
+		Instance  string `json:"instance"`
+		Message   string `json:"message"`
+		Service   string `json:"service,omitempty"`
+	}
+
 	// Batch encompasses a batch ID and the logs for this batch.
 	Batch struct {
 		ID   string `json:"batch_id"`
@@ -536,6 +647,18 @@ func (l *Log) String() string {
 		l.Message)
 }
 
+// JSONLine returns the log encoded as a single line of JSON. The service is
+// only included if it's set (i.e. when tailing multiple services).
+func (l *Log) JSONLine(service string) ([]byte, error) {
+	return json.Marshal(jsonLog{
+		Timestamp: l.RequestStartFromRaw().UTC().Format(time.RFC3339Nano),
+		Stream:    l.Stream,
+		Instance:  l.RequestID,
+		Message:   l.Message,
+		Service:   service,
+	})
+}
+
 // makeNewPath generates a new request path based on current
 // path, window, and batchID.
 func makeNewPath(path string, window int64, batchID string) (string, error) {
@@ -585,7 +708,7 @@ func parseResponseData(data []byte) (Batch, error) {
 // filterStream returns only logs that are requested by the stream flag.
 func filterStream(stream string, logs []Log) []Log {
 	// If unset, do not filter out any logs.
-	if stream == "" {
+	if stream == "" || stream == "both" {
 		return logs
 	}
 
@@ -599,6 +722,21 @@ func filterStream(stream string, logs []Log) []Log {
 	return out
 }
 
+// filterMessage returns only logs whose message matches the filter.
+func filterMessage(filter *regexp.Regexp, logs []Log) []Log {
+	if filter == nil {
+		return logs
+	}
+
+	var out []Log
+	for _, l := range logs {
+		if filter.MatchString(l.Message) {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
 // getTimeFromLink splits a link header format, returning
 // the time.
 func getTimeFromLink(link string) (int64, error) {
@@ -1,13 +1,25 @@
 package logtail
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"reflect"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/fatih/color"
 	"github.com/google/go-cmp/cmp"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
 )
 
 const responseFile = "testdata/response.json"
@@ -339,3 +351,401 @@ func TestHighSequence(t *testing.T) {
 		}
 	}
 }
+
+// TestPrintLogsFilter tests that logs are filtered by stream and message.
+func TestPrintLogsFilter(t *testing.T) {
+	logs := []Log{
+		{Stream: "stdout", RequestID: "aaaaaaaa", Message: "GET /health 200"},
+		{Stream: "stdout", RequestID: "bbbbbbbb", Message: "GET /api 500"},
+		{Stream: "stderr", RequestID: "cccccccc", Message: "panic: 500"},
+	}
+
+	for i, test := range []struct {
+		stream string
+		filter string
+		want   []string
+	}{
+		{want: []string{"/health", "/api", "panic"}},
+		{stream: "both", want: []string{"/health", "/api", "panic"}},
+		{stream: "stdout", want: []string{"/health", "/api"}},
+		{filter: `\b500$`, want: []string{"/api", "panic"}},
+		{stream: "stderr", filter: "500", want: []string{"panic"}},
+		{filter: "^POST", want: nil},
+	} {
+		var buf bytes.Buffer
+		c := RootCommand{cfg: cfg{stream: test.stream}}
+		if test.filter != "" {
+			c.cfg.filterRegex = regexp.MustCompile(test.filter)
+		}
+		c.printLogs(&buf, logs)
+
+		var lines []string
+		if s := strings.TrimSpace(buf.String()); s != "" {
+			lines = strings.Split(s, "\n")
+		}
+		if len(lines) != len(test.want) {
+			t.Fatalf("#%d: want %d lines, have %d: %q", i, len(test.want), len(lines), lines)
+		}
+		for j, w := range test.want {
+			if !strings.Contains(lines[j], w) {
+				t.Errorf("#%d: want line %d to contain %q, have %q", i, j, w, lines[j])
+			}
+		}
+	}
+}
+
+// TestPrintLogsJSONL tests that each log is rendered as a JSON object on its
+// own line.
+func TestPrintLogsJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	c := RootCommand{cfg: cfg{jsonl: true}}
+	c.printLogs(&buf, []Log{
+		{RequestStart: 1601645172164667, Stream: "stdout", RequestID: "44a1eedd-5831-49fe-b094-7435908ba1fb", Message: "hello"},
+		{RequestStart: 1601645172164667, Stream: "stderr", RequestID: "44a1eedd-5831-49fe-b094-7435908ba1fb", Message: "world"},
+	})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("want 2 lines, have %d: %q", len(lines), lines)
+	}
+
+	var got map[string]string
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatalf("line is not valid JSON: %v", err)
+	}
+	want := map[string]string{
+		"timestamp": "2020-10-02T13:26:12.164667Z",
+		"stream":    "stdout",
+		"instance":  "44a1eedd-5831-49fe-b094-7435908ba1fb",
+		"message":   "hello",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("JSON line mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestTailReconnect feeds a scripted sequence of responses through the tail
+// loop, including a truncated stream and a dropped connection, and validates
+// that we resume from the last received cursor without dropping or
+// duplicating batches.
+func TestTailReconnect(t *testing.T) {
+	defer func(d time.Duration) { retryBaseDelay = d }(retryBaseDelay)
+	retryBaseDelay = time.Millisecond
+
+	const path = "/service/sid/log_stream/managed/instance_output"
+
+	batch := func(id string) string {
+		return fmt.Sprintf(`{"batch_id":%q,"logs":[{"sequence_number":1,"stream":"stdout","id":"req","message":%q}]}`+"\n", id, id)
+	}
+
+	var (
+		mu      sync.Mutex
+		queries []string
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		n := len(queries)
+		mu.Unlock()
+
+		switch n {
+		case 1:
+			w.Header().Set("Link", fmt.Sprintf(`<%s?from=100>; rel="next"`, path))
+			_, _ = w.Write([]byte(batch("b1")))
+		case 2:
+			// Send a batch, then drop the connection mid-stream.
+			w.(http.Flusher).Flush()
+			_, _ = w.Write([]byte(batch("b2")))
+			w.(http.Flusher).Flush()
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			_ = conn.Close()
+		case 3:
+			// Drop the connection before sending a response.
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			_ = conn.Close()
+		case 4:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Header().Set("Link", fmt.Sprintf(`<%s?from=300>; rel="next"`, path))
+			_, _ = w.Write([]byte(batch("b3")))
+		}
+	}))
+	defer ts.Close()
+
+	c := RootCommand{
+		batchCh: make(chan Batch, 10),
+		cfg:     cfg{path: ts.URL + path, to: 200},
+		dieCh:   make(chan struct{}),
+		doneCh:  make(chan struct{}),
+		hClient: ts.Client(),
+	}
+	c.Globals = &global.Data{ErrLog: fsterr.Log}
+
+	var out bytes.Buffer
+	if err := c.tail(&out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	close(c.batchCh)
+
+	var ids []string
+	for b := range c.batchCh {
+		ids = append(ids, b.ID)
+	}
+	if diff := cmp.Diff([]string{"b1", "b2", "b3"}, ids); diff != "" {
+		t.Errorf("batches mismatch (-want +got):\n%s", diff)
+	}
+
+	want := []string{
+		"",
+		"from=100",
+		"batch_id=b2&from=100",
+		"batch_id=b2&from=100",
+		"batch_id=b2&from=100",
+	}
+	if diff := cmp.Diff(want, queries); diff != "" {
+		t.Errorf("queries mismatch (-want +got):\n%s", diff)
+	}
+
+	// NOTE: This package can't import testutil (it depends on the app package,
+	// which imports this package).
+	for _, want := range []string{"log stream interrupted", "reconnecting in"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("want output to contain %q, have %q", want, out.String())
+		}
+	}
+}
+
+// fakeSource is a log stream API for a single service, which sends scripted
+// responses and then holds the request open until it's cancelled.
+type fakeSource struct {
+	mu        sync.Mutex
+	queries   []string
+	responses []func(w http.ResponseWriter)
+}
+
+func (f *fakeSource) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.queries = append(f.queries, r.URL.RawQuery)
+	n := len(f.queries)
+	f.mu.Unlock()
+	if n <= len(f.responses) {
+		f.responses[n-1](w)
+		return
+	}
+	<-r.Context().Done()
+}
+
+func (f *fakeSource) Queries() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.queries...)
+}
+
+// syncBuffer is a bytes.Buffer that's safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// TestTailAll tails two services whose batches arrive interleaved, one of
+// which fails and is retried, and validates each line is labeled with its
+// service, that reconnecting one session doesn't disturb the other, and that
+// the messages about the sessions are written to stderr rather than mixed with
+// the logs.
+func TestTailAll(t *testing.T) {
+	defer func(d time.Duration) { retryBaseDelay = d }(retryBaseDelay)
+	retryBaseDelay = time.Millisecond
+	defer func(noColor bool) { color.NoColor = noColor }(color.NoColor)
+	color.NoColor = true
+
+	const path = "/service/%s/log_stream/managed/instance_output"
+
+	// respond sends a batch (after waiting for the previous batch of the other
+	// service, so their batches interleave) with a link to the next window.
+	respond := func(serviceID, id string, next int, after <-chan struct{}, sent chan<- struct{}) func(w http.ResponseWriter) {
+		return func(w http.ResponseWriter) {
+			if after != nil {
+				<-after
+			}
+			w.Header().Set("Link", fmt.Sprintf(`<%s?from=%d>; rel="next"`, fmt.Sprintf(path, serviceID), next))
+			_, _ = fmt.Fprintf(w, `{"batch_id":%q,"logs":[{"sequence_number":1,"stream":"stdout","id":"req-%s","message":%q}]}`+"\n", id, id, id)
+			if sent != nil {
+				close(sent)
+			}
+		}
+	}
+	failed := func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	rejected := func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+
+	for _, testcase := range []struct {
+		name        string
+		jsonl       bool
+		failB       bool
+		wantLines   []string
+		wantErrors  []string
+		wantQueries map[string][]string
+		wantError   string
+	}{
+		{
+			name: "text",
+			wantLines: []string{
+				"[www      ] stdout |   req-a1 | a1",
+				"[www-stage] stdout |   req-b1 | b1",
+				"[www      ] stdout |   req-a2 | a2",
+				"[www-stage] stdout |   req-b2 | b2",
+			},
+			wantErrors: []string{
+				"[www      ] WARNING: non-200 resp 500",
+			},
+			wantQueries: map[string][]string{
+				"a": {"", "from=100", "from=100", "from=200"},
+				"b": {"", "from=100", "from=200"},
+			},
+		},
+		{
+			name:  "jsonl",
+			jsonl: true,
+			wantLines: []string{
+				`{"timestamp":"1970-01-01T00:00:00Z","stream":"stdout","instance":"req-a1","message":"a1","service":"www"}`,
+				`{"timestamp":"1970-01-01T00:00:00Z","stream":"stdout","instance":"req-b2","message":"b2","service":"www-stage"}`,
+			},
+			wantErrors: []string{
+				"[www      ] WARNING: non-200 resp 500",
+			},
+		},
+		{
+			name:  "failed session",
+			failB: true,
+			wantLines: []string{
+				"[www      ] stdout |   req-a2 | a2",
+			},
+			wantErrors: []string{
+				"[www-stage] ERROR: stopped tailing the logs of service b: unrecoverable error, response code: 400",
+			},
+			wantError: "error tailing the logs of service www-stage (b): unrecoverable error, response code: 400",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			a1, b1, a2 := make(chan struct{}), make(chan struct{}), make(chan struct{})
+			a := &fakeSource{responses: []func(http.ResponseWriter){
+				respond("a", "a1", 100, nil, a1),
+				failed,
+				respond("a", "a2", 200, b1, a2),
+			}}
+			b := &fakeSource{responses: []func(http.ResponseWriter){
+				respond("b", "b1", 100, a1, b1),
+				respond("b", "b2", 200, a2, nil),
+			}}
+			if testcase.failB {
+				b.responses[1] = rejected
+			}
+			mux := http.NewServeMux()
+			mux.Handle(fmt.Sprintf(path, "a"), a)
+			mux.Handle(fmt.Sprintf(path, "b"), b)
+			ts := httptest.NewServer(mux)
+			defer ts.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			c := RootCommand{
+				cfg:     cfg{jsonl: testcase.jsonl, sortBuffer: time.Millisecond},
+				dieCh:   make(chan struct{}),
+				hClient: ts.Client(),
+			}
+			var out, errOut syncBuffer
+			c.Globals = &global.Data{Context: ctx, ErrLog: fsterr.Log, ErrOutput: &errOut}
+			sessions := []*RootCommand{
+				c.session(target{id: "a", label: "www"}, ts.URL),
+				c.session(target{id: "b", label: "www-stage"}, ts.URL),
+			}
+
+			result := make(chan error)
+			go func() {
+				result <- c.tailAll(sessions, &out)
+			}()
+
+			// Stop the sessions (as Ctrl-C does) once the logs are displayed.
+			deadline := time.Now().Add(5 * time.Second)
+			for !(containsAll(out.String(), testcase.wantLines) && containsAll(errOut.String(), testcase.wantErrors)) && time.Now().Before(deadline) {
+				time.Sleep(5 * time.Millisecond)
+			}
+			cancel()
+			var err error
+			select {
+			case err = <-result:
+			case <-time.After(5 * time.Second):
+				t.Fatal("the sessions didn't stop")
+			}
+			if testcase.wantError == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if testcase.wantError != "" && (err == nil || !strings.Contains(err.Error(), testcase.wantError)) {
+				t.Fatalf("want error %q, have %v", testcase.wantError, err)
+			}
+
+			for _, want := range testcase.wantLines {
+				if !strings.Contains(out.String(), want+"\n") {
+					t.Errorf("want output to contain %q, have:\n%s", want, out.String())
+				}
+			}
+			for _, want := range testcase.wantErrors {
+				if !strings.Contains(errOut.String(), want+"\n") {
+					t.Errorf("want stderr to contain %q, have:\n%s", want, errOut.String())
+				}
+			}
+			for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+				if testcase.jsonl {
+					var obj map[string]any
+					if err := json.Unmarshal([]byte(line), &obj); err != nil {
+						t.Errorf("line %q is not a JSON object: %v", line, err)
+					}
+					continue
+				}
+				if line != "" && !strings.HasPrefix(line, "[www      ] ") && !strings.HasPrefix(line, "[www-stage] ") {
+					t.Errorf("unlabeled line %q", line)
+				}
+			}
+			for _, line := range strings.Split(strings.TrimSpace(errOut.String()), "\n") {
+				if line != "" && !strings.HasPrefix(line, "[www      ] ") && !strings.HasPrefix(line, "[www-stage] ") {
+					t.Errorf("unlabeled message %q", line)
+				}
+			}
+			if strings.Contains(errOut.String(), "[www-stage] WARNING: non-200 resp 500") {
+				t.Errorf("the reconnection of www was reported for www-stage:\n%s", errOut.String())
+			}
+			if testcase.wantQueries != nil {
+				if diff := cmp.Diff(testcase.wantQueries["a"], a.Queries()); diff != "" {
+					t.Errorf("www queries mismatch (-want +got):\n%s", diff)
+				}
+				if diff := cmp.Diff(testcase.wantQueries["b"], b.Queries()); diff != "" {
+					t.Errorf("www-stage queries mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func containsAll(s string, substrs []string) bool {
+	for _, sub := range substrs {
+		if !strings.Contains(s, sub) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,3 @@
+// Package plan contains commands to run a sequence of CLI commands described
+// by a plan file.
+package plan
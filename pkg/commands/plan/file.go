@@ -0,0 +1,229 @@
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	toml "github.com/pelletier/go-toml"
+	"gopkg.in/yaml.v3"
+)
+
+// The values of File.OnError.
+const (
+	// OnErrorStop stops the plan at the first step that fails.
+	OnErrorStop = "stop"
+	// OnErrorContinue runs every step, even after one has failed.
+	OnErrorContinue = "continue"
+)
+
+// File is a plan: a sequence of steps, each a CLI command and its flags.
+//
+// Steps that accept --version share a single version, which is cloned from
+// Version before the first step and activated once every step has succeeded.
+// Steps that accept --service-id are given ServiceID. A step can set either
+// flag itself to opt out.
+type File struct {
+	// ServiceID is the service the steps apply to (the --service-id flag and
+	// fastly.toml are used if it's unset).
+	ServiceID string `toml:"service_id" yaml:"service_id"`
+	// Version is the version to clone (default "active").
+	Version string `toml:"version" yaml:"version"`
+	// Activate indicates whether the cloned version is activated at the end
+	// (default true).
+	Activate *bool `toml:"activate" yaml:"activate"`
+	// OnError is OnErrorStop (the default) or OnErrorContinue.
+	OnError string `toml:"on_error" yaml:"on_error"`
+	// Steps are run in order.
+	Steps []Step `toml:"steps" yaml:"steps"`
+}
+
+// Step is a CLI command run by a plan.
+type Step struct {
+	// Name optionally describes the step in the report.
+	Name string `toml:"name" yaml:"name"`
+	// Command is the command path (e.g. "backend create").
+	Command string `toml:"command" yaml:"command"`
+	// Args are positional arguments.
+	Args []string `toml:"args" yaml:"args"`
+	// Flags are the command's flags by long name (without the leading
+	// dashes). A value is a string, number, boolean (true sets the flag,
+	// false omits it), list (the flag is repeated) or an environment variable
+	// reference such as { env = "API_KEY" }.
+	Flags map[string]any `toml:"flags" yaml:"flags"`
+}
+
+// Label returns the step's name, or its command if it has no name.
+func (s Step) Label() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Command
+}
+
+// secretFlagWords identify the flags whose values are secrets. Their values
+// must be referenced from the environment, so the plan can be committed.
+var secretFlagWords = []string{"secret", "password", "token", "api-key", "access-key", "private-key", "client-key"}
+
+// IsSecretFlag indicates the flag's value is a secret.
+func IsSecretFlag(name string) bool {
+	for _, w := range secretFlagWords {
+		if strings.Contains(name, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// Load reads and validates the plan at path. A path ending in .yaml or .yml
+// is read as YAML, and any other as TOML.
+func Load(path string) (*File, error) {
+	// G304 (CWE-22): Potential file inclusion via variable
+	// #nosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f File
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &f)
+	default:
+		err = toml.Unmarshal(data, &f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := f.validate(); err != nil {
+		return nil, fmt.Errorf("invalid plan %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// validate checks the plan is well-formed, without resolving its steps.
+func (f *File) validate() error {
+	switch f.OnError {
+	case "", OnErrorStop, OnErrorContinue:
+	default:
+		return fmt.Errorf("on_error must be '%s' or '%s', not '%s'", OnErrorStop, OnErrorContinue, f.OnError)
+	}
+	if len(f.Steps) == 0 {
+		return errors.New("the plan has no steps")
+	}
+	for i, s := range f.Steps {
+		n := i + 1
+		fields := strings.Fields(s.Command)
+		switch {
+		case len(fields) == 0:
+			return fmt.Errorf("step %d has no command", n)
+		case fields[0] == RootName:
+			return fmt.Errorf("step %d: a plan can't run another plan", n)
+		case strings.HasPrefix(fields[0], "-"):
+			return fmt.Errorf("step %d: the command must not include flags (set them in flags)", n)
+		}
+		for name, v := range s.Flags {
+			if name == "" || strings.HasPrefix(name, "-") {
+				return fmt.Errorf("step %d: invalid flag name '%s' (use the long name without dashes)", n, name)
+			}
+			if _, ok := envRef(v); !ok && IsSecretFlag(name) {
+				return fmt.Errorf("step %d: the value of --%s must be referenced from the environment (e.g. %s = { env = \"MY_SECRET\" }), not set in the plan", n, name, name)
+			}
+		}
+	}
+	return nil
+}
+
+// envRef returns the environment variable a flag value references.
+func envRef(v any) (string, bool) {
+	m, ok := v.(map[string]any)
+	if !ok || len(m) != 1 {
+		return "", false
+	}
+	name, ok := m["env"].(string)
+	return name, ok && name != ""
+}
+
+// Resolve returns the step's command line, along with the same command line
+// for display, where values referenced from the environment are shown as
+// the variable name (e.g. $API_KEY). lookup reads the environment.
+func (s Step) Resolve(lookup func(string) (string, bool)) (args, display []string, err error) {
+	args = strings.Fields(s.Command)
+	display = append(display, args...)
+	args = append(args, s.Args...)
+	display = append(display, s.Args...)
+
+	names := make([]string, 0, len(s.Flags))
+	for name := range s.Flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		values, shown, err := flagValues(name, s.Flags[name], lookup)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i, v := range values {
+			if v == nil {
+				args = append(args, "--"+name)
+				display = append(display, "--"+name)
+				continue
+			}
+			args = append(args, "--"+name+"="+*v)
+			display = append(display, "--"+name+"="+shown[i])
+		}
+	}
+	return args, display, nil
+}
+
+// flagValues converts a flag value to the values of each occurrence of the
+// flag. A nil value is a boolean flag without a value.
+func flagValues(name string, v any, lookup func(string) (string, bool)) (values []*string, shown []string, err error) {
+	if ref, ok := envRef(v); ok {
+		value, ok := lookup(ref)
+		if !ok {
+			return nil, nil, fmt.Errorf("the environment variable %s referenced by --%s isn't set", ref, name)
+		}
+		return []*string{&value}, []string{"$" + ref}, nil
+	}
+
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case bool:
+		if !v {
+			return nil, nil, nil
+		}
+		return []*string{nil}, []string{""}, nil
+	case int:
+		s = strconv.Itoa(v)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case uint64:
+		s = strconv.FormatUint(v, 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	case []any:
+		for _, item := range v {
+			if _, ok := item.([]any); ok {
+				return nil, nil, fmt.Errorf("invalid value for --%s: lists can't be nested", name)
+			}
+			vs, ss, err := flagValues(name, item, lookup)
+			if err != nil {
+				return nil, nil, err
+			}
+			values = append(values, vs...)
+			shown = append(shown, ss...)
+		}
+		return values, shown, nil
+	default:
+		return nil, nil, fmt.Errorf("invalid value for --%s: %v", name, v)
+	}
+	return []*string{&s}, []string{s}, nil
+}
@@ -0,0 +1,287 @@
+package plan_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/audit"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+const threeStepPlan = `service_id = "123"
+
+[[steps]]
+name = "origin"
+command = "backend create"
+flags = { name = "origin", address = "example.com", port = 443 }
+
+[[steps]]
+command = "logging datadog create"
+flags = { name = "dd", auth-token = { env = "PLAN_TEST_DD_TOKEN" } }
+
+[[steps]]
+command = "dictionary create"
+flags = { name = "geo" }
+`
+
+const threeStepPlanYAML = `service_id: "123"
+steps:
+  - name: origin
+    command: backend create
+    flags: {name: origin, address: example.com, port: 443}
+  - command: logging datadog create
+    flags: {name: dd, auth-token: {env: PLAN_TEST_DD_TOKEN}}
+  - command: dictionary create
+    flags: {name: geo}
+`
+
+// planCalls records the API calls made by a plan.
+type planCalls struct {
+	clones       int
+	activated    []int
+	backends     []*fastly.CreateBackendInput
+	datadogs     []*fastly.CreateDatadogInput
+	dictionaries []*fastly.CreateDictionaryInput
+}
+
+func (c *planCalls) api(datadogErr error) mock.API {
+	return mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		CloneVersionFn: func(i *fastly.CloneVersionInput) (*fastly.Version, error) {
+			c.clones++
+			return testutil.CloneVersionResult(3)(i)
+		},
+		ActivateVersionFn: func(i *fastly.ActivateVersionInput) (*fastly.Version, error) {
+			c.activated = append(c.activated, i.ServiceVersion)
+			return &fastly.Version{ServiceID: fastly.ToPointer(i.ServiceID), Number: fastly.ToPointer(i.ServiceVersion)}, nil
+		},
+		CreateBackendFn: func(i *fastly.CreateBackendInput) (*fastly.Backend, error) {
+			c.backends = append(c.backends, i)
+			return &fastly.Backend{ServiceID: fastly.ToPointer(i.ServiceID), ServiceVersion: fastly.ToPointer(i.ServiceVersion), Name: i.Name}, nil
+		},
+		CreateDatadogFn: func(i *fastly.CreateDatadogInput) (*fastly.Datadog, error) {
+			c.datadogs = append(c.datadogs, i)
+			if datadogErr != nil {
+				return nil, datadogErr
+			}
+			return &fastly.Datadog{ServiceID: fastly.ToPointer(i.ServiceID), ServiceVersion: fastly.ToPointer(i.ServiceVersion), Name: i.Name}, nil
+		},
+		CreateDictionaryFn: func(i *fastly.CreateDictionaryInput) (*fastly.Dictionary, error) {
+			c.dictionaries = append(c.dictionaries, i)
+			return &fastly.Dictionary{ServiceID: fastly.ToPointer(i.ServiceID), ServiceVersion: fastly.ToPointer(i.ServiceVersion), Name: i.Name, DictionaryID: fastly.ToPointer("d1")}, nil
+		},
+	}
+}
+
+func runPlan(t *testing.T, plan, filename, flags string, api mock.API) (string, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), filename)
+	if err := os.WriteFile(path, []byte(plan), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	args := testutil.Args(strings.TrimSpace("plan run --file " + path + " " + flags))
+	var stdout bytes.Buffer
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		opts := testutil.MockGlobalData(args, &stdout)
+		opts.APIClientFactory = mock.APIClient(api)
+		return opts, nil
+	}
+	err := app.Run(args, nil)
+	t.Log(stdout.String())
+	return stdout.String(), err
+}
+
+// TestPlanRunSharedVersion validates the steps share a version that's cloned
+// once and activated at the end, for both plan formats.
+func TestPlanRunSharedVersion(t *testing.T) {
+	t.Setenv("PLAN_TEST_DD_TOKEN", "dd-secret")
+	for _, format := range []struct{ filename, plan string }{
+		{"plan.toml", threeStepPlan},
+		{"plan.yaml", threeStepPlanYAML},
+	} {
+		t.Run(format.filename, func(t *testing.T) {
+			var calls planCalls
+			out, err := runPlan(t, format.plan, format.filename, "", calls.api(nil))
+			testutil.AssertNoError(t, err)
+
+			testutil.AssertEqual(t, 1, calls.clones)
+			testutil.AssertEqual(t, []int{3}, calls.activated)
+			testutil.AssertEqual(t, 1, len(calls.backends))
+			testutil.AssertEqual(t, 1, len(calls.datadogs))
+			testutil.AssertEqual(t, 1, len(calls.dictionaries))
+			testutil.AssertEqual(t, "123", calls.backends[0].ServiceID)
+			testutil.AssertEqual(t, 3, calls.backends[0].ServiceVersion)
+			testutil.AssertEqual(t, 443, fastly.ToValue(calls.backends[0].Port))
+			testutil.AssertEqual(t, 3, calls.datadogs[0].ServiceVersion)
+			testutil.AssertEqual(t, "dd-secret", fastly.ToValue(calls.datadogs[0].Token))
+			testutil.AssertEqual(t, 3, calls.dictionaries[0].ServiceVersion)
+
+			for _, s := range []string{
+				"Cloned version 1 of service 123 to version 3 for the plan.",
+				"1. origin [ok]",
+				"SUCCESS: Created backend origin (service 123 version 3)",
+				"2. logging datadog create [ok]",
+				"3. dictionary create [ok]",
+				"SUCCESS: Ran 3 steps and activated version 3 of service 123",
+			} {
+				testutil.AssertStringContains(t, out, s)
+			}
+			if strings.Contains(out, "dd-secret") {
+				t.Error("want the secret not to be displayed")
+			}
+		})
+	}
+}
+
+// TestPlanRunAudit validates each mutating step is recorded in the audit log,
+// as if it had been run by itself.
+func TestPlanRunAudit(t *testing.T) {
+	t.Setenv("PLAN_TEST_DD_TOKEN", "dd-secret")
+	dir := t.TempDir()
+	plan := filepath.Join(dir, "plan.toml")
+	if err := os.WriteFile(plan, []byte(threeStepPlan), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "audit.log")
+
+	var calls planCalls
+	args := testutil.Args("plan run --file " + plan)
+	var stdout bytes.Buffer
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		opts := testutil.MockGlobalData(args, &stdout)
+		opts.APIClientFactory = mock.APIClient(calls.api(nil))
+		opts.AuditLogPath = path
+		opts.Config.Audit.Enabled = true
+		return opts, nil
+	}
+	testutil.AssertNoError(t, app.Run(args, nil))
+
+	entries, err := audit.Read(path)
+	testutil.AssertNoError(t, err)
+	var commands []string
+	for _, e := range entries {
+		if e.Command == "plan run" {
+			continue
+		}
+		commands = append(commands, e.Command)
+		testutil.AssertString(t, "123", e.ServiceID)
+		testutil.AssertString(t, "3", e.ServiceVersion)
+		testutil.AssertString(t, audit.OutcomeSuccess, e.Outcome)
+		if strings.Contains(strings.Join(e.Args, " "), "dd-secret") {
+			t.Errorf("want the secret redacted, have %q", e.Args)
+		}
+	}
+	testutil.AssertEqual(t, []string{"backend create", "logging datadog create", "dictionary create"}, commands)
+}
+
+func TestPlanRunStopOnError(t *testing.T) {
+	t.Setenv("PLAN_TEST_DD_TOKEN", "dd-secret")
+	var calls planCalls
+	out, err := runPlan(t, threeStepPlan, "plan.toml", "", calls.api(testutil.Err))
+	testutil.AssertErrorContains(t, err, "1 of 3 steps failed")
+
+	testutil.AssertEqual(t, 1, len(calls.datadogs))
+	testutil.AssertEqual(t, 0, len(calls.dictionaries))
+	testutil.AssertEqual(t, 0, len(calls.activated))
+	testutil.AssertStringContains(t, out, "1. origin [ok]")
+	testutil.AssertStringContains(t, out, "2. logging datadog create [failed]\n    ERROR: "+testutil.Err.Error())
+	testutil.AssertStringContains(t, out, "3. dictionary create [skipped]")
+
+	var re fsterr.RemediationError
+	if !errors.As(err, &re) {
+		t.Fatalf("want a RemediationError, have %T", err)
+	}
+	testutil.AssertStringContains(t, re.Remediation, "Version 3 of service 123 was left inactive")
+}
+
+func TestPlanRunContinueOnError(t *testing.T) {
+	t.Setenv("PLAN_TEST_DD_TOKEN", "dd-secret")
+	var calls planCalls
+	plan := strings.Replace(threeStepPlan, `service_id = "123"`, "service_id = \"123\"\non_error = \"continue\"", 1)
+	out, err := runPlan(t, plan, "plan.toml", "", calls.api(testutil.Err))
+	testutil.AssertErrorContains(t, err, "1 of 3 steps failed")
+
+	testutil.AssertEqual(t, 1, calls.clones)
+	testutil.AssertEqual(t, 1, len(calls.dictionaries))
+	testutil.AssertEqual(t, 3, calls.dictionaries[0].ServiceVersion)
+	testutil.AssertEqual(t, 0, len(calls.activated))
+	testutil.AssertStringContains(t, out, "3. dictionary create [ok]")
+
+	// The flag overrides the plan.
+	calls = planCalls{}
+	_, err = runPlan(t, plan, "plan.toml", "--on-error stop", calls.api(testutil.Err))
+	testutil.AssertErrorContains(t, err, "1 of 3 steps failed")
+	testutil.AssertEqual(t, 0, len(calls.dictionaries))
+}
+
+func TestPlanRunDryRun(t *testing.T) {
+	t.Setenv("PLAN_TEST_DD_TOKEN", "dd-secret")
+
+	// No API calls are expected (the mock panics on any).
+	out, err := runPlan(t, threeStepPlan, "plan.toml", "--dry-run", mock.API{})
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, out, "fastly logging datadog create --auth-token=$PLAN_TEST_DD_TOKEN --name=dd --service-id=123 --version=active")
+	testutil.AssertStringContains(t, out, "The 3 steps in")
+
+	invalid := strings.Replace(threeStepPlan, `flags = { name = "geo" }`, `flags = { name = "geo", colour = "blue" }`, 1)
+	out, err = runPlan(t, invalid, "plan.toml", "", mock.API{})
+	testutil.AssertErrorContains(t, err, "1 of 3 steps in")
+	testutil.AssertStringContains(t, out, "3. dictionary create [invalid]")
+	testutil.AssertStringContains(t, out, "unknown long flag '--colour'")
+}
+
+func TestPlanLoadErrors(t *testing.T) {
+	scenarios := []struct {
+		name      string
+		plan      string
+		wantError string
+	}{
+		{
+			name:      "inline secret",
+			plan:      strings.Replace(threeStepPlan, `{ env = "PLAN_TEST_DD_TOKEN" }`, `"dd-secret"`, 1),
+			wantError: "step 2: the value of --auth-token must be referenced from the environment",
+		},
+		{
+			name:      "unset environment variable",
+			plan:      threeStepPlan,
+			wantError: "step 2 (logging datadog create): the environment variable PLAN_TEST_DD_TOKEN referenced by --auth-token isn't set",
+		},
+		{
+			name:      "unknown command",
+			plan:      "[[steps]]\ncommand = \"backend frobnicate\"\n",
+			wantError: "step 1: unknown command 'backend frobnicate'",
+		},
+		{
+			name:      "nested plan",
+			plan:      "[[steps]]\ncommand = \"plan run\"\n",
+			wantError: "step 1: a plan can't run another plan",
+		},
+		{
+			name:      "invalid on_error",
+			plan:      "on_error = \"retry\"\n[[steps]]\ncommand = \"whoami\"\n",
+			wantError: "on_error must be 'stop' or 'continue'",
+		},
+		{
+			name:      "no steps",
+			plan:      "service_id = \"123\"\n",
+			wantError: "the plan has no steps",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			_, err := runPlan(t, testcase.plan, "plan.toml", "", mock.API{})
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+		})
+	}
+}
@@ -0,0 +1,356 @@
+package plan
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// The status of a step in the report.
+const (
+	StatusOK      = "ok"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// RunCommand runs the steps of a plan file.
+type RunCommand struct {
+	argparser.Base
+
+	dryRun      bool
+	file        string
+	onError     string
+	serviceName argparser.OptionalServiceNameID
+}
+
+// NewRunCommand returns a usable command registered under the parent.
+func NewRunCommand(parent argparser.Registerer, g *global.Data) *RunCommand {
+	var c RunCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("run", "Run the steps of a plan file (TOML or YAML) in order, sharing a cloned service version that's activated at the end")
+	c.CmdClause.Flag("dry-run", "Validate the flags of every step without calling the API").BoolVar(&c.dryRun)
+	c.CmdClause.Flag("file", "Path to the plan file (.toml, .yaml or .yml)").Short('f').Required().StringVar(&c.file)
+	c.CmdClause.Flag("on-error", "Whether to stop at the first failed step or continue with the next (overrides on_error in the plan)").HintOptions(OnErrorStop, OnErrorContinue).EnumVar(&c.onError, OnErrorStop, OnErrorContinue)
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Name:        argparser.FlagServiceIDName,
+		Description: argparser.FlagServiceIDDesc,
+		Dst:         &g.Manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        argparser.FlagServiceName,
+		Description: argparser.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	return &c
+}
+
+// step is a step of the plan being run.
+type step struct {
+	Step
+	// args and display are the command line (see Step.Resolve).
+	args, display []string
+	// sharedVersion and sharedService indicate the step is given the plan's
+	// version and service ID.
+	sharedVersion, sharedService bool
+
+	status string
+	output string
+	err    error
+}
+
+// commandLine returns the step's command line with the shared flags.
+func (s *step) commandLine(args []string, serviceID, version string) []string {
+	line := slices.Clone(args)
+	if s.sharedService && serviceID != "" {
+		line = append(line, "--service-id="+serviceID)
+	}
+	if s.sharedVersion {
+		line = append(line, "--version="+version)
+	}
+	return line
+}
+
+// RequiredScope implements the argparser.Scoper interface. The steps of a
+// plan create and update service configuration.
+func (c *RunCommand) RequiredScope() fastly.TokenScope {
+	return fastly.GlobalScope
+}
+
+// Interruptible implements the argparser.Interruptible interface. An interrupted
+// plan stops before its next step.
+func (c *RunCommand) Interruptible() bool {
+	return true
+}
+
+// Exec invokes the application logic for the command.
+func (c *RunCommand) Exec(_ io.Reader, out io.Writer) error {
+	p, err := Load(c.file)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	onError := p.OnError
+	if c.onError != "" {
+		onError = c.onError
+	}
+	if p.ServiceID != "" && c.Globals.Manifest.Flag.ServiceID == "" {
+		c.Globals.Manifest.Flag.ServiceID = p.ServiceID
+	}
+	baseVersion := p.Version
+	if baseVersion == "" {
+		baseVersion = "active"
+	}
+
+	steps, err := c.resolve(p)
+	if err != nil {
+		return err
+	}
+	var needsVersion bool
+	for _, s := range steps {
+		needsVersion = needsVersion || s.sharedVersion
+	}
+
+	// Every step is parsed before anything is run, so a mistake in the plan
+	// doesn't leave it half applied. The service ID isn't looked up by name
+	// (an API call) until the steps are run.
+	serviceID, _ := c.Globals.Manifest.ServiceID()
+	var invalid int
+	for _, s := range steps {
+		s.display = s.commandLine(s.display, serviceID, baseVersion)
+		if _, err := c.Globals.PrepareCommand(s.commandLine(s.args, serviceID, baseVersion)); err != nil {
+			s.status, s.err = StatusFailed, err
+			invalid++
+		}
+	}
+	if c.dryRun || invalid > 0 {
+		printDryRun(out, steps)
+		if invalid > 0 {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("%d of %d steps in %s are invalid", invalid, len(steps), c.file),
+				Remediation: "Fix the flags of the steps listed above. Nothing was run.",
+			}
+		}
+		text.Success(out, "The %d steps in %s are valid", len(steps), c.file)
+		return nil
+	}
+
+	var (
+		sid     string
+		version string
+	)
+	if needsVersion || c.serviceName.WasSet {
+		sid, _, _, err = argparser.ServiceID(c.serviceName, *c.Globals.Manifest, c.Globals.APIClient, c.Globals.ErrLog)
+		if err != nil {
+			return err
+		}
+	}
+	if needsVersion {
+		from, to, err := c.cloneVersion(sid, baseVersion)
+		if err != nil {
+			return err
+		}
+		version = strconv.Itoa(to)
+		text.Info(out, "Cloned version %d of service %s to version %s for the plan.", from, sid, version)
+		text.Break(out)
+	}
+
+	failed := c.run(steps, sid, version, onError)
+
+	activated := false
+	if needsVersion && failed == 0 && (p.Activate == nil || *p.Activate) {
+		if err := c.activate(sid, version); err != nil {
+			printReport(out, steps)
+			return err
+		}
+		activated = true
+	}
+
+	printReport(out, steps)
+	switch {
+	case failed > 0:
+		remediation := "Fix the failed steps and run the plan again."
+		if needsVersion {
+			remediation = fmt.Sprintf("Version %s of service %s was left inactive. %s", version, sid, remediation)
+		}
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("%d of %d steps failed", failed, len(steps)),
+			Remediation: remediation,
+		}
+	case activated:
+		text.Success(out, "Ran %d steps and activated version %s of service %s", len(steps), version, sid)
+	case needsVersion:
+		text.Success(out, "Ran %d steps on version %s of service %s (not activated)", len(steps), version, sid)
+	default:
+		text.Success(out, "Ran %d steps", len(steps))
+	}
+	return nil
+}
+
+// resolve resolves the command line of each step, and whether it's given the
+// shared service ID and version.
+func (c *RunCommand) resolve(p *File) ([]*step, error) {
+	steps := make([]*step, 0, len(p.Steps))
+	for i, ps := range p.Steps {
+		args, display, err := ps.Resolve(os.LookupEnv)
+		if err != nil {
+			return nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("step %d (%s): %w", i+1, ps.Label(), err),
+				Remediation: "Secrets are referenced from the environment, so set the variable before running the plan.",
+			}
+		}
+		flags, err := c.Globals.CommandFlags(strings.Join(strings.Fields(ps.Command), " "))
+		if err != nil {
+			return nil, fmt.Errorf("step %d: %w", i+1, err)
+		}
+		_, setsService := ps.Flags[argparser.FlagServiceIDName]
+		_, setsServiceName := ps.Flags[argparser.FlagServiceName]
+		_, setsVersion := ps.Flags[argparser.FlagVersionName]
+		steps = append(steps, &step{
+			Step:          ps,
+			args:          args,
+			display:       display,
+			sharedService: slices.Contains(flags, argparser.FlagServiceIDName) && !setsService && !setsServiceName,
+			sharedVersion: slices.Contains(flags, argparser.FlagVersionName) && !setsVersion,
+		})
+	}
+	return steps, nil
+}
+
+// cloneVersion clones the plan's base version, returning the version that
+// was cloned and the new version.
+func (c *RunCommand) cloneVersion(serviceID, base string) (from, to int, err error) {
+	sv := argparser.OptionalServiceVersion{OptionalString: argparser.OptionalString{Value: base}}
+	current, err := sv.Parse(serviceID, c.Globals.APIClient)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+		})
+		return 0, 0, err
+	}
+	from = fastly.ToValue(current.Number)
+	v, err := c.Globals.APIClient.CloneVersion(&fastly.CloneVersionInput{
+		ServiceID:      serviceID,
+		ServiceVersion: from,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": from,
+		})
+		return 0, 0, fmt.Errorf("error cloning service version: %w", err)
+	}
+	return from, fastly.ToValue(v.Number), nil
+}
+
+// run runs the steps, capturing their output, and returns the number that
+// failed. The remaining steps are skipped after a failure unless onError is
+// OnErrorContinue, or if the command is interrupted.
+func (c *RunCommand) run(steps []*step, serviceID, version, onError string) (failed int) {
+	for _, s := range steps {
+		if (failed > 0 && onError != OnErrorContinue) || c.Globals.Context.Err() != nil {
+			s.status = StatusSkipped
+			continue
+		}
+		var buf bytes.Buffer
+		exec, err := c.Globals.PrepareCommand(s.commandLine(s.args, serviceID, version))
+		if err == nil {
+			err = exec(&buf)
+		}
+		s.output = buf.String()
+		if err != nil {
+			s.status, s.err = StatusFailed, err
+			failed++
+			continue
+		}
+		s.status = StatusOK
+	}
+	return failed
+}
+
+// activate activates the plan's version.
+func (c *RunCommand) activate(serviceID, version string) error {
+	n, _ := strconv.Atoi(version)
+	_, err := c.Globals.APIClient.ActivateVersion(&fastly.ActivateVersionInput{
+		ServiceID:      serviceID,
+		ServiceVersion: n,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": n,
+		})
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("error activating version %s: %w", version, err),
+			Remediation: fmt.Sprintf("Every step succeeded. Activate the version with `fastly service-version activate --service-id %s --version %s`.", serviceID, version),
+		}
+	}
+	return nil
+}
+
+func printDryRun(out io.Writer, steps []*step) {
+	for i, s := range steps {
+		status := text.SuccessStyle("valid")
+		if s.err != nil {
+			status = text.ErrorStyle("invalid")
+		}
+		text.Output(out, "%d. %s [%s]", i+1, s.Label(), status)
+		text.Indent(out, 4, "fastly %s", strings.Join(s.display, " "))
+		if s.err != nil {
+			writeIndented(out, errorText(s.err))
+		}
+	}
+	text.Break(out)
+}
+
+func printReport(out io.Writer, steps []*step) {
+	for i, s := range steps {
+		var status string
+		switch s.status {
+		case StatusOK:
+			status = text.SuccessStyle(s.status)
+		case StatusFailed:
+			status = text.ErrorStyle(s.status)
+		default:
+			status = text.WarningStyle(s.status)
+		}
+		text.Output(out, "%d. %s [%s]", i+1, s.Label(), status)
+		if output := strings.TrimSpace(s.output); output != "" {
+			writeIndented(out, output)
+		}
+		if s.err != nil {
+			writeIndented(out, errorText(s.err))
+		}
+	}
+	text.Break(out)
+}
+
+// errorText formats a step's error with its remediation (if any).
+func errorText(err error) string {
+	var re fsterr.RemediationError
+	if errors.As(err, &re) && re.Remediation != "" && re.Inner != nil {
+		return fmt.Sprintf("ERROR: %s.\n%s", re.Inner, re.Remediation)
+	}
+	return fmt.Sprintf("ERROR: %s.", err)
+}
+
+// writeIndented writes each line of s indented under its step, without
+// wrapping (the output of a step is already formatted).
+func writeIndented(out io.Writer, s string) {
+	for _, line := range strings.Split(s, "\n") {
+		fmt.Fprintf(out, "    %s\n", line)
+	}
+}
@@ -135,7 +135,7 @@ func (c *CreateCommand) staticTokenFlow(makeDefault bool, in io.Reader, out io.W
 
 func promptForToken(in io.Reader, out io.Writer, errLog fsterr.LogInterface) (string, error) {
 	text.Output(out, "\nA user token is used to authenticate requests to the Fastly API. To create a token, visit https://manage.fastly.com/account/personal/tokens\n\n")
-	token, err := text.InputSecure(out, text.Prompt("Fastly API token: "), in, validateTokenNotEmpty)
+	token, err := text.InputSecure(out, text.Prompt("Fastly API token: "), in, "", validateTokenNotEmpty)
 	if err != nil {
 		errLog.Add(err)
 		return "", err
@@ -268,7 +268,7 @@ func displayCfgPath(path string, out io.Writer) {
 }
 
 func (c *CreateCommand) promptForDefault(in io.Reader, out io.Writer) (bool, error) {
-	cont, err := text.AskYesNo(out, "Set this profile to be your default? [y/N] ", in)
+	cont, err := text.AskYesNo(out, "Set this profile to be your default? [y/N] ", in, "--auto-yes")
 	if err != nil {
 		c.Globals.ErrLog.Add(err)
 		return false, err
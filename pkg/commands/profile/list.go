@@ -1,7 +1,6 @@
 package profile
 
 import (
-	"fmt"
 	"io"
 
 	"github.com/fastly/cli/pkg/argparser"
@@ -17,6 +16,7 @@ import (
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 }
 
 // NewListCommand returns a usable command registered under the parent.
@@ -24,7 +24,8 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 	var c ListCommand
 	c.Globals = g
 	c.CmdClause = parent.Command("list", "List user profiles")
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.EmptyState = argparser.EmptyState{Resource: "profiles", Create: "fastly profile create <NAME>"}
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	return &c
 }
 
@@ -38,17 +39,7 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
-	if c.Globals.Config.Profiles == nil {
-		msg := "no profiles available"
-		return fsterr.RemediationError{
-			Inner:       fmt.Errorf(msg),
-			Remediation: fsterr.ProfileRemediation,
-		}
-	}
-
-	if len(c.Globals.Config.Profiles) == 0 {
-		text.Break(out)
-		text.Description(out, "No profiles defined. To create a profile, run", "fastly profile create <name>")
+	if c.PrintEmpty(out, len(c.Globals.Config.Profiles)) {
 		return nil
 	}
 
@@ -360,28 +360,28 @@ func TestProfileList(t *testing.T) {
 		},
 		{
 			TestScenario: testutil.TestScenario{
-				Name:      "validate no profiles defined",
-				Args:      args("profile list"),
-				WantError: "no profiles available",
+				Name:       "validate no profiles defined",
+				Args:       args("profile list"),
+				WantOutput: "INFO: No profiles found. Run `fastly profile create <NAME>`.",
 			},
 		},
-		// NOTE: The following test is subtly different to the previous one in that
-		// our logic checks whether the config.Profiles map type is nil. If it is
-		// then we error (see above test), otherwise if the map is set but there
-		// are no profiles, then we notify the user no profiles exist.
 		{
 			TestScenario: testutil.TestScenario{
-				Name: "validate no profiles available",
-				Args: args("profile list"),
-				WantOutputs: []string{
-					"No profiles defined. To create a profile, run",
-					"fastly profile create <name>",
-				},
+				Name:       "validate no profiles available",
+				Args:       args("profile list"),
+				WantOutput: "INFO: No profiles found. Run `fastly profile create <NAME>`.",
 			},
 			ConfigFile: config.File{
 				Profiles: config.Profiles{},
 			},
 		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:       "validate no profiles defined with --json",
+				Args:       args("profile list --json"),
+				WantOutput: "{}\n",
+			},
+		},
 		{
 			TestScenario: testutil.TestScenario{
 				Name: "validate listing profiles displays warning if no default set",
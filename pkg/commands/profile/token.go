@@ -58,5 +58,6 @@ func (c *TokenCommand) Exec(_ io.Reader, out io.Writer) (err error) {
 	return fsterr.RemediationError{
 		Inner:       fmt.Errorf("no profiles available"),
 		Remediation: fsterr.ProfileRemediation,
+		Code:        fsterr.CodeProfile,
 	}
 }
@@ -57,7 +57,7 @@ func (c *UpdateCommand) Exec(in io.Reader, out io.Writer) error {
 
 	if !c.Globals.Flags.AutoYes && !c.Globals.Flags.NonInteractive {
 		text.Break(out)
-		makeDefault, err = text.AskYesNo(out, text.BoldYellow("Make profile the default? [y/N] "), in)
+		makeDefault, err = text.AskYesNo(out, text.WarningStyle("Make profile the default? [y/N] "), in, "--auto-yes")
 		text.Break(out)
 		if err != nil {
 			return err
@@ -216,7 +216,7 @@ func (c *UpdateCommand) validateToken(token, endpoint string, spinner text.Spinn
 func (c *UpdateCommand) staticTokenFlow(profileName string, p *config.Profile, in io.Reader, out io.Writer) error {
 	opts := []profile.EditOption{}
 
-	token, err := text.InputSecure(out, text.BoldYellow("Profile token: (leave blank to skip): "), in)
+	token, err := text.InputSecure(out, text.WarningStyle("Profile token: (leave blank to skip): "), in, "")
 	if err != nil {
 		c.Globals.ErrLog.Add(err)
 		return err
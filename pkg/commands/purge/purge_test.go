@@ -2,13 +2,22 @@ package purge_test
 
 import (
 	"bytes"
+	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/fastly/go-fastly/v9/fastly"
 
 	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/commands/purge"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/mock"
 	"github.com/fastly/cli/pkg/testutil"
@@ -34,7 +43,7 @@ func TestPurgeAll(t *testing.T) {
 					return nil, testutil.Err
 				},
 			},
-			Args:      args("purge --all --service-id 123"),
+			Args:      args("purge --all --service-id 123 --auto-yes"),
 			WantError: testutil.Err.Error(),
 		},
 		{
@@ -46,7 +55,7 @@ func TestPurgeAll(t *testing.T) {
 					}, nil
 				},
 			},
-			Args:       args("purge --all --service-id 123"),
+			Args:       args("purge --all --service-id 123 --auto-yes"),
 			WantOutput: "Purge all status: ok",
 		},
 	}
@@ -258,3 +267,367 @@ func TestPurgeURL(t *testing.T) {
 		})
 	}
 }
+
+func TestPurgeKeysBulk(t *testing.T) {
+	generateKeys := func(n int) string {
+		keys := make([]string, n)
+		for i := range keys {
+			keys[i] = fmt.Sprintf("key-%03d", i)
+		}
+		return strings.Join(keys, "\n")
+	}
+	purgeKeysSuccess := func(i *fastly.PurgeKeysInput) (map[string]string, error) {
+		m := make(map[string]string, len(i.Keys))
+		for _, k := range i.Keys {
+			m[k] = "id-" + k
+		}
+		return m, nil
+	}
+
+	scenarios := []struct {
+		name        string
+		args        string
+		stdin       string
+		purgeKeysFn func(i *fastly.PurgeKeysInput) (map[string]string, error)
+		wantBatches []int
+		wantSoft    bool
+		wantError   string
+		wantOutputs []string
+	}{
+		{
+			name:        "repeated --key flags",
+			args:        "purge --key foo --key bar --service-id 123",
+			purgeKeysFn: purgeKeysSuccess,
+			wantBatches: []int{2},
+			wantOutputs: []string{"KEY  ID\nbar  id-bar\nfoo  id-foo\n"},
+		},
+		{
+			name:        "keys from stdin",
+			args:        "purge --file - --service-id 123 --soft",
+			stdin:       "foo\n\nbar\nfoo\n",
+			purgeKeysFn: purgeKeysSuccess,
+			wantBatches: []int{2},
+			wantSoft:    true,
+			wantOutputs: []string{"bar  id-bar", "foo  id-foo"},
+		},
+		{
+			name:        "exactly one full batch",
+			args:        "purge --file - --service-id 123",
+			stdin:       generateKeys(256),
+			purgeKeysFn: purgeKeysSuccess,
+			wantBatches: []int{256},
+			wantOutputs: []string{"key-000  id-key-000", "key-255  id-key-255"},
+		},
+		{
+			name:        "batch boundary exceeded",
+			args:        "purge --file - --service-id 123 --soft --concurrency 2",
+			stdin:       generateKeys(257),
+			purgeKeysFn: purgeKeysSuccess,
+			wantBatches: []int{1, 256},
+			wantSoft:    true,
+			wantOutputs: []string{"key-256  id-key-256", "Purged 257 surrogate keys in 2 batches (soft: true)"},
+		},
+		{
+			name:  "partial failure",
+			args:  "purge --file - --service-id 123",
+			stdin: generateKeys(300),
+			purgeKeysFn: func(i *fastly.PurgeKeysInput) (map[string]string, error) {
+				if i.Keys[0] == "key-256" {
+					return nil, testutil.Err
+				}
+				return purgeKeysSuccess(i)
+			},
+			wantBatches: []int{44, 256},
+			wantError:   "failed to purge 44 of 300 surrogate keys (soft: false)",
+			wantOutputs: []string{"key-255  id-key-255", "FAILED KEY", "key-299     " + testutil.Err.Error()},
+		},
+		{
+			name:        "no stdin data",
+			args:        "purge --file - --service-id 123",
+			purgeKeysFn: purgeKeysSuccess,
+			wantError:   "no surrogate keys provided",
+		},
+	}
+
+	for _, testcase := range scenarios {
+		testcase := testcase
+		t.Run(testcase.name, func(t *testing.T) {
+			var (
+				mu      sync.Mutex
+				batches []int
+				soft    = true
+				stdout  bytes.Buffer
+			)
+			api := mock.API{
+				PurgeKeysFn: func(i *fastly.PurgeKeysInput) (map[string]string, error) {
+					mu.Lock()
+					batches = append(batches, len(i.Keys))
+					soft = soft && i.Soft
+					mu.Unlock()
+					return testcase.purgeKeysFn(i)
+				},
+			}
+			args := testutil.Args(testcase.args)
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIClientFactory = mock.APIClient(api)
+				opts.Input = strings.NewReader(testcase.stdin)
+				return opts, nil
+			}
+			err := app.Run(args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			sort.Ints(batches)
+			testutil.AssertEqual(t, testcase.wantBatches, batches)
+			if len(batches) > 0 {
+				testutil.AssertBool(t, testcase.wantSoft, soft)
+			}
+		})
+	}
+}
+
+func TestPurgeAllConfirm(t *testing.T) {
+	getServiceDetailsOK := func(i *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+		return &fastly.ServiceDetail{
+			ServiceID: fastly.ToPointer(i.ServiceID),
+			Name:      fastly.ToPointer("production"),
+		}, nil
+	}
+	getServiceDetailsError := func(_ *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+		return nil, testutil.Err
+	}
+
+	scenarios := []struct {
+		name                string
+		getServiceDetailsFn func(i *fastly.GetServiceInput) (*fastly.ServiceDetail, error)
+		stdin               string
+		wantError           string
+		wantOutput          string
+		wantPurged          bool
+	}{
+		{
+			name:                "service name typed back",
+			getServiceDetailsFn: getServiceDetailsOK,
+			stdin:               "production\n",
+			wantOutput:          "Type the service name (production) to continue: ",
+			wantPurged:          true,
+		},
+		{
+			name:                "yes is not accepted",
+			getServiceDetailsFn: getServiceDetailsOK,
+			stdin:               "yes\n",
+			wantError:           argparser.ErrNotConfirmed.Error(),
+		},
+		{
+			name:                "wrong service name",
+			getServiceDetailsFn: getServiceDetailsOK,
+			stdin:               "staging\n",
+			wantError:           argparser.ErrNotConfirmed.Error(),
+		},
+		{
+			name:                "service ID typed back when the name lookup fails",
+			getServiceDetailsFn: getServiceDetailsError,
+			stdin:               "123\n",
+			wantOutput:          "Type the service name (123) to continue: ",
+			wantPurged:          true,
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.name, func(t *testing.T) {
+			var (
+				purged bool
+				stdout bytes.Buffer
+			)
+			api := mock.API{
+				GetServiceDetailsFn: testcase.getServiceDetailsFn,
+				ListDomainsFn: func(_ *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+					return nil, nil
+				},
+				PurgeAllFn: func(_ *fastly.PurgeAllInput) (*fastly.Purge, error) {
+					purged = true
+					return &fastly.Purge{Status: fastly.ToPointer("ok")}, nil
+				},
+			}
+			args := testutil.Args("purge --all --service-id 123")
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIClientFactory = mock.APIClient(api)
+				opts.Input = strings.NewReader(testcase.stdin)
+				opts.IsTTY = func(_ any) bool { return true }
+				return opts, nil
+			}
+			err := app.Run(args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			testutil.AssertBool(t, testcase.wantPurged, purged)
+		})
+	}
+}
+
+// cacheOrigin simulates a Fastly service whose cache is invalidated a number
+// of requests after a purge is issued.
+type cacheOrigin struct {
+	mu sync.Mutex
+	// hits is the number of requests still served from cache.
+	hits int
+	// credentials records any credentials sent to the origin.
+	credentials []string
+	requests    int
+}
+
+func (o *cacheOrigin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, h := range []string{"Fastly-Key", "Authorization"} {
+		if v := r.Header.Get(h); v != "" {
+			o.credentials = append(o.credentials, h+": "+v)
+		}
+	}
+	if n, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/redirect/")); err == nil && n > 0 {
+		http.Redirect(w, r, fmt.Sprintf("/redirect/%d", n-1), http.StatusFound)
+		return
+	}
+	o.requests++
+	if r.URL.Path == "/uncached" {
+		return
+	}
+	state := "MISS"
+	if o.hits > 0 {
+		o.hits--
+		state = "HIT"
+	}
+	w.Header().Set("X-Cache", "MISS, "+state)
+}
+
+func TestPurgeVerify(t *testing.T) {
+	scenarios := []struct {
+		name         string
+		args         string
+		path         string
+		hits         int
+		wantError    string
+		wantOutput   string
+		wantRequests int
+	}{
+		{
+			name:         "delayed invalidation after purging a URL",
+			args:         "purge --service-id 123 --url https://example.com",
+			path:         "/",
+			hits:         3,
+			wantOutput:   "MISS observed after",
+			wantRequests: 4,
+		},
+		{
+			name:         "delayed invalidation after purging a key",
+			args:         "purge --service-id 123 --key foo",
+			path:         "/",
+			hits:         1,
+			wantOutput:   "(2 requests)",
+			wantRequests: 2,
+		},
+		{
+			name:         "redirects are followed",
+			args:         "purge --service-id 123 --key foo",
+			path:         "/redirect/2",
+			wantOutput:   "(1 requests)",
+			wantRequests: 1,
+		},
+		{
+			name:      "too many redirects",
+			args:      "purge --service-id 123 --key foo",
+			path:      "/redirect/3",
+			wantError: "stopped after 2 redirects",
+		},
+		{
+			name:         "missing cache state",
+			args:         "purge --service-id 123 --key foo",
+			path:         "/uncached",
+			wantError:    "has no X-Cache response header",
+			wantRequests: 1,
+		},
+		{
+			name:      "timeout",
+			args:      "purge --service-id 123 --key foo --verify-timeout 100ms",
+			path:      "/",
+			hits:      1000,
+			wantError: "was still served from cache (X-Cache: HIT) after 100ms",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.name, func(t *testing.T) {
+			origin := &cacheOrigin{}
+			ts := httptest.NewServer(origin)
+			defer ts.Close()
+
+			var stdout bytes.Buffer
+			api := mock.API{
+				PurgeFn: func(_ *fastly.PurgeInput) (*fastly.Purge, error) {
+					origin.mu.Lock()
+					origin.hits = testcase.hits
+					origin.mu.Unlock()
+					return &fastly.Purge{Status: fastly.ToPointer("ok"), PurgeID: fastly.ToPointer("123")}, nil
+				},
+				PurgeKeyFn: func(_ *fastly.PurgeKeyInput) (*fastly.Purge, error) {
+					origin.mu.Lock()
+					origin.hits = testcase.hits
+					origin.mu.Unlock()
+					return &fastly.Purge{Status: fastly.ToPointer("ok"), PurgeID: fastly.ToPointer("123")}, nil
+				},
+			}
+			args := testutil.Args(testcase.args + " --verify-interval 1ms --verify " + ts.URL + testcase.path)
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIClientFactory = mock.APIClient(api)
+				return opts, nil
+			}
+			err := app.Run(args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+
+			origin.mu.Lock()
+			defer origin.mu.Unlock()
+			if len(origin.credentials) > 0 {
+				t.Errorf("want no credentials sent to the verified URL, have %v", origin.credentials)
+			}
+			if testcase.wantRequests > 0 {
+				testutil.AssertEqual(t, testcase.wantRequests, origin.requests)
+			}
+		})
+	}
+}
+
+func TestPurgeVerifyInvalidURL(t *testing.T) {
+	var stdout bytes.Buffer
+	args := testutil.Args("purge --service-id 123 --key foo --verify example.com/index.html")
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		opts := testutil.MockGlobalData(args, &stdout)
+		// PurgeKeyFn is nil so the test panics if the purge is attempted.
+		opts.APIClientFactory = mock.APIClient(mock.API{})
+		return opts, nil
+	}
+	err := app.Run(args, nil)
+	testutil.AssertErrorContains(t, err, "invalid --verify URL: example.com/index.html")
+}
+
+func TestCacheState(t *testing.T) {
+	for _, testcase := range []struct {
+		values []string
+		want   string
+	}{
+		{nil, ""},
+		{[]string{"MISS"}, "MISS"},
+		{[]string{"MISS, HIT"}, "HIT"},
+		{[]string{"HIT, miss"}, "MISS"},
+		{[]string{"MISS", "HIT"}, "HIT"},
+	} {
+		h := http.Header{}
+		for _, v := range testcase.values {
+			h.Add("X-Cache", v)
+		}
+		testutil.AssertString(t, testcase.want, purge.CacheState(h))
+	}
+}
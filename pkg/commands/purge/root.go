@@ -2,11 +2,15 @@ package purge
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/fastly/go-fastly/v9/fastly"
 
@@ -17,6 +21,14 @@ import (
 	"github.com/fastly/cli/pkg/text"
 )
 
+// maxKeysPerRequest is the maximum number of surrogate keys the API accepts
+// in a single multi-key purge request.
+const maxKeysPerRequest = 256
+
+// purgeKeysConcurrencyLimit is used to limit the concurrency when purging
+// batches of keys. This is effectively the 'thread pool' size.
+const purgeKeysConcurrencyLimit int = 5
+
 // NewRootCommand returns a new command registered in the parent.
 func NewRootCommand(parent argparser.Registerer, g *global.Data) *RootCommand {
 	var c RootCommand
@@ -25,8 +37,9 @@ func NewRootCommand(parent argparser.Registerer, g *global.Data) *RootCommand {
 
 	// Optional.
 	c.CmdClause.Flag("all", "Purge everything from a service").BoolVar(&c.all)
-	c.CmdClause.Flag("file", "Purge a service of a newline delimited list of Surrogate Keys").StringVar(&c.file)
-	c.CmdClause.Flag("key", "Purge a service of objects tagged with a Surrogate Key").StringVar(&c.key)
+	c.CmdClause.Flag("concurrency", "Control thread pool size when purging multiple batches of Surrogate Keys").Action(c.concurrency.Set).IntVar(&c.concurrency.Value)
+	c.CmdClause.Flag("file", "Purge a service of a newline delimited list of Surrogate Keys (use '-' to read from STDIN)").Action(argparser.StdinPath(&c.file)).StringVar(&c.file)
+	c.CmdClause.Flag("key", "Purge a service of objects tagged with a Surrogate Key (set flag once per key)").StringsVar(&c.keys)
 	c.RegisterFlag(argparser.StringFlagOpts{
 		Name:        argparser.FlagServiceIDName,
 		Description: argparser.FlagServiceIDDesc,
@@ -41,6 +54,9 @@ func NewRootCommand(parent argparser.Registerer, g *global.Data) *RootCommand {
 	})
 	c.CmdClause.Flag("soft", "A 'soft' purge marks affected objects as stale rather than making them inaccessible").BoolVar(&c.soft)
 	c.CmdClause.Flag("url", "Purge an individual URL").StringVar(&c.url)
+	c.CmdClause.Flag("verify", "After purging, request this URL until the X-Cache response header reports a MISS and display the time taken").StringVar(&c.verify)
+	c.CmdClause.Flag("verify-interval", "Delay between --verify requests").Default("1s").DurationVar(&c.verifyInterval)
+	c.CmdClause.Flag("verify-timeout", "How long to wait for --verify to observe a MISS").Default("30s").DurationVar(&c.verifyTimeout)
 
 	return &c
 }
@@ -50,16 +66,35 @@ func NewRootCommand(parent argparser.Registerer, g *global.Data) *RootCommand {
 type RootCommand struct {
 	argparser.Base
 
-	all         bool
-	file        string
-	key         string
-	serviceName argparser.OptionalServiceNameID
-	soft        bool
-	url         string
+	all            bool
+	concurrency    argparser.OptionalInt
+	file           string
+	keys           []string
+	serviceName    argparser.OptionalServiceNameID
+	soft           bool
+	url            string
+	verify         string
+	verifyInterval time.Duration
+	verifyTimeout  time.Duration
+}
+
+// RequiredScope implements the argparser.Scoper interface. Purging all of a
+// service's content needs a broader scope than purging a URL or keys.
+func (c *RootCommand) RequiredScope() fastly.TokenScope {
+	if c.all {
+		return fastly.PurgeAllScope
+	}
+	return fastly.PurgeSelectScope
+}
+
+// Interruptible implements the argparser.Interruptible interface. Only --verify
+// polls until the purge takes effect, which Ctrl-C stops.
+func (c *RootCommand) Interruptible() bool {
+	return c.verify != ""
 }
 
 // Exec implements the command interface.
-func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
+func (c *RootCommand) Exec(in io.Reader, out io.Writer) error {
 	serviceID, source, flag, err := argparser.ServiceID(c.serviceName, *c.Globals.Manifest, c.Globals.APIClient, c.Globals.ErrLog)
 	if err != nil {
 		return err
@@ -68,10 +103,16 @@ func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
 		argparser.DisplayServiceID(serviceID, flag, source, out)
 	}
 
+	if c.verify != "" {
+		if err := verifyURL(c.verify); err != nil {
+			return err
+		}
+	}
+
 	// The URL purge API call doesn't require a Service ID.
 	if c.url == "" {
 		if source == manifest.SourceUndefined {
-			return fsterr.ErrNoServiceID
+			return argparser.NoServiceIDError()
 		}
 	}
 
@@ -82,7 +123,18 @@ func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
 				Remediation: "The --soft flag should not be used with --all so retry command without it.",
 			}
 		}
-		err := c.purgeAll(serviceID, out)
+		err := argparser.Confirm(argparser.ConfirmOpts{
+			Action:      fmt.Sprintf("purge all cached content from service %s", serviceID),
+			Globals:     c.Globals,
+			In:          in,
+			Out:         out,
+			RequireName: true,
+			ServiceID:   serviceID,
+		})
+		if err != nil {
+			return err
+		}
+		err = c.purgeAll(serviceID, out)
 		if err != nil {
 			c.Globals.ErrLog.AddWithContext(err, map[string]any{
 				"Service ID": serviceID,
@@ -90,31 +142,32 @@ func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
 			})
 			return err
 		}
-		return nil
+		return c.verifyPurge(out)
 	}
 
-	if c.file != "" {
-		err := c.purgeKeys(serviceID, out)
+	if c.file != "" || len(c.keys) > 1 {
+		err := c.purgeKeys(serviceID, in, out)
 		if err != nil {
 			c.Globals.ErrLog.AddWithContext(err, map[string]any{
 				"Service ID": serviceID,
 				"File":       c.file,
+				"Keys":       c.keys,
 			})
 			return err
 		}
-		return nil
+		return c.verifyPurge(out)
 	}
 
-	if c.key != "" {
-		err := c.purgeKey(serviceID, out)
+	if len(c.keys) == 1 {
+		err := c.purgeKey(serviceID, c.keys[0], out)
 		if err != nil {
 			c.Globals.ErrLog.AddWithContext(err, map[string]any{
 				"Service ID": serviceID,
-				"Key":        c.key,
+				"Key":        c.keys[0],
 			})
 			return err
 		}
-		return nil
+		return c.verifyPurge(out)
 	}
 
 	if c.url != "" {
@@ -125,12 +178,38 @@ func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
 			})
 			return err
 		}
-		return nil
+		return c.verifyPurge(out)
 	}
 
 	return nil
 }
 
+// verifyPurge polls the --verify URL, if set, until the purge takes effect.
+func (c *RootCommand) verifyPurge(out io.Writer) error {
+	if c.verify == "" {
+		return nil
+	}
+	ctx := context.Background()
+	if c.Globals.Context != nil {
+		ctx = c.Globals.Context
+	}
+	text.Info(out, "Verifying purge of %s (timeout: %s)...", c.verify, c.verifyTimeout)
+	v := verifier{
+		Interval: c.verifyInterval,
+		Timeout:  c.verifyTimeout,
+	}
+	r, err := v.verify(ctx, c.verify)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Verify URL": c.verify,
+			"Attempts":   r.Attempts,
+		})
+		return err
+	}
+	printVerifyResult(out, c.verify, r)
+	return nil
+}
+
 func (c *RootCommand) purgeAll(serviceID string, out io.Writer) error {
 	p, err := c.Globals.APIClient.PurgeAll(&fastly.PurgeAllInput{
 		ServiceID: serviceID,
@@ -145,60 +224,134 @@ func (c *RootCommand) purgeAll(serviceID string, out io.Writer) error {
 	return nil
 }
 
-func (c *RootCommand) purgeKeys(serviceID string, out io.Writer) error {
-	keys, err := populateKeys(c.file, c.Globals.ErrLog)
-	if err != nil {
-		c.Globals.ErrLog.AddWithContext(err, map[string]any{
-			"Service ID": serviceID,
-		})
-		return err
+func (c *RootCommand) purgeKeys(serviceID string, in io.Reader, out io.Writer) error {
+	keys := c.keys
+	if c.file != "" {
+		fileKeys, err := c.readKeys(in)
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID": serviceID,
+			})
+			return err
+		}
+		keys = append(keys, fileKeys...)
+	}
+	keys = uniqueKeys(keys)
+	if len(keys) == 0 {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("no surrogate keys provided"),
+			Remediation: "Provide at least one Surrogate Key via --key or --file.",
+		}
 	}
 
-	m, err := c.Globals.APIClient.PurgeKeys(&fastly.PurgeKeysInput{
-		ServiceID: serviceID,
-		Keys:      keys,
-		Soft:      c.soft,
-	})
-	if err != nil {
-		c.Globals.ErrLog.AddWithContext(err, map[string]any{
-			"Service ID": serviceID,
-			"Keys":       keys,
-			"Soft":       c.soft,
-		})
-		return err
+	poolSize := purgeKeysConcurrencyLimit
+	if c.concurrency.WasSet && c.concurrency.Value > 0 {
+		poolSize = c.concurrency.Value
 	}
 
-	sortedKeys := make([]string, 0, len(m))
-	for k := range m {
-		sortedKeys = append(sortedKeys, k)
+	batches := batchKeys(keys, maxKeysPerRequest)
+	results := make([]batchResult, len(batches))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, poolSize)
+
+	for i, batch := range batches {
+		wg.Add(1)
+		go func(i int, batch []string) {
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			defer wg.Done()
+
+			m, err := c.Globals.APIClient.PurgeKeys(&fastly.PurgeKeysInput{
+				ServiceID: serviceID,
+				Keys:      batch,
+				Soft:      c.soft,
+			})
+			if err != nil {
+				c.Globals.ErrLog.AddWithContext(err, map[string]any{
+					"Service ID": serviceID,
+					"Keys":       batch,
+					"Soft":       c.soft,
+				})
+			}
+			results[i] = batchResult{keys: batch, ids: m, err: err}
+		}(i, batch)
+	}
+
+	wg.Wait()
+	close(semaphore)
+
+	purged := make(map[string]string)
+	failed := make(map[string]error)
+	var firstErr error
+	for _, r := range results {
+		for _, k := range r.keys {
+			switch {
+			case r.err != nil:
+				failed[k] = r.err
+				if firstErr == nil {
+					firstErr = r.err
+				}
+			default:
+				if id, ok := r.ids[k]; ok {
+					purged[k] = id
+				} else {
+					failed[k] = fmt.Errorf("no purge ID returned")
+				}
+			}
+		}
+	}
+
+	if len(purged) > 0 {
+		t := text.NewTable(out)
+		t.AddHeader("KEY", "ID")
+		for _, k := range sortedKeys(purged) {
+			t.AddLine(k, purged[k])
+		}
+		t.Print()
+	}
+
+	if len(failed) > 0 {
+		if len(purged) > 0 {
+			text.Break(out)
+		}
+		t := text.NewTable(out)
+		t.AddHeader("FAILED KEY", "ERROR")
+		for _, k := range sortedKeys(failed) {
+			t.AddLine(k, failed[k].Error())
+		}
+		t.Print()
+
+		err := fmt.Errorf("failed to purge %d of %d surrogate keys (soft: %t)", len(failed), len(keys), c.soft)
+		if firstErr != nil {
+			err = fmt.Errorf("%w: %w", err, firstErr)
+		}
+		return err
 	}
-	sort.Strings(sortedKeys)
 
-	t := text.NewTable(out)
-	t.AddHeader("KEY", "ID")
-	for _, k := range sortedKeys {
-		t.AddLine(k, m[k])
+	if len(batches) > 1 {
+		text.Break(out)
+		text.Success(out, "Purged %d surrogate keys in %d batches (soft: %t)", len(purged), len(batches), c.soft)
 	}
-	t.Print()
 
 	return nil
 }
 
-func (c *RootCommand) purgeKey(serviceID string, out io.Writer) error {
+func (c *RootCommand) purgeKey(serviceID, key string, out io.Writer) error {
 	p, err := c.Globals.APIClient.PurgeKey(&fastly.PurgeKeyInput{
 		ServiceID: serviceID,
-		Key:       c.key,
+		Key:       key,
 		Soft:      c.soft,
 	})
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
 			"Service ID": serviceID,
-			"Key":        c.key,
+			"Key":        key,
 			"Soft":       c.soft,
 		})
 		return err
 	}
-	text.Success(out, "Purged key: %s (soft: %t). Status: %s, ID: %s", c.key, c.soft, fastly.ToValue(p.Status), fastly.ToValue(p.PurgeID))
+	text.Success(out, "Purged key: %s (soft: %t). Status: %s, ID: %s", key, c.soft, fastly.ToValue(p.Status), fastly.ToValue(p.PurgeID))
 	return nil
 }
 
@@ -218,22 +371,36 @@ func (c *RootCommand) purgeURL(out io.Writer) error {
 	return nil
 }
 
+// readKeys reads the newline delimited list of surrogate keys from the --file
+// flag value, which is either a file path or '-' to indicate STDIN.
+func (c *RootCommand) readKeys(in io.Reader) ([]string, error) {
+	if c.file == "-" {
+		if in == nil {
+			return nil, fsterr.ErrNoSTDINData
+		}
+		keys, err := scanKeys(in)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return nil, err
+		}
+		return keys, nil
+	}
+	return populateKeys(c.file, c.Globals.ErrLog)
+}
+
 // populateKeys opens the given file path, initializes a scanner, and appends
 // each line of the file (expected to be a surrogate key) to a slice.
 func populateKeys(fpath string, errLog fsterr.LogInterface) (keys []string, err error) {
 	var (
-		file io.Reader
+		file *os.File
 		path string
 	)
 
 	if path, err = filepath.Abs(fpath); err == nil {
 		if _, err = os.Stat(path); err == nil {
 			if file, err = os.Open(path); err == nil /* #nosec */ {
-				scanner := bufio.NewScanner(file)
-				for scanner.Scan() {
-					keys = append(keys, scanner.Text())
-				}
-				err = scanner.Err()
+				keys, err = scanKeys(file)
+				_ = file.Close()
 			}
 		}
 	}
@@ -244,3 +411,55 @@ func populateKeys(fpath string, errLog fsterr.LogInterface) (keys []string, err
 	}
 	return keys, nil
 }
+
+// scanKeys reads each non-empty line from r (expected to be a surrogate key).
+func scanKeys(r io.Reader) (keys []string, err error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if k := strings.TrimSpace(scanner.Text()); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	return keys, scanner.Err()
+}
+
+// uniqueKeys removes duplicate keys while preserving the original order.
+func uniqueKeys(keys []string) []string {
+	seen := make(map[string]bool, len(keys))
+	unique := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if !seen[k] {
+			seen[k] = true
+			unique = append(unique, k)
+		}
+	}
+	return unique
+}
+
+// batchKeys splits keys into batches of at most size keys.
+func batchKeys(keys []string, size int) (batches [][]string) {
+	for size < len(keys) {
+		keys, batches = keys[size:], append(batches, keys[0:size:size])
+	}
+	if len(keys) > 0 {
+		batches = append(batches, keys)
+	}
+	return batches
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// batchResult is the outcome of a single multi-key purge request.
+type batchResult struct {
+	keys []string
+	ids  map[string]string
+	err  error
+}
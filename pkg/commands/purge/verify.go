@@ -0,0 +1,141 @@
+package purge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// maxVerifyRedirects is the number of redirects followed when requesting the
+// --verify URL.
+const maxVerifyRedirects = 2
+
+// verifier polls a URL after a purge until a cache MISS is observed.
+type verifier struct {
+	// Interval is the delay between requests.
+	Interval time.Duration
+	// Timeout is how long to wait for a MISS.
+	Timeout time.Duration
+}
+
+// verifyResult describes a successful purge verification.
+type verifyResult struct {
+	// Attempts is the number of requests made.
+	Attempts int
+	// Elapsed is the time until the MISS was observed.
+	Elapsed time.Duration
+}
+
+// verify requests rawURL until its cache state is a MISS or the timeout
+// expires. An error is returned straight away if the response has no cache
+// state, as polling it would never succeed.
+//
+// NOTE: The requests are made with a dedicated HTTP client so that the API
+// token (or any other credential) is never sent to the verified URL.
+func (v verifier) verify(ctx context.Context, rawURL string) (verifyResult, error) {
+	client := &http.Client{CheckRedirect: checkVerifyRedirect}
+
+	ctx, cancel := context.WithTimeout(ctx, v.Timeout)
+	defer cancel()
+
+	var (
+		result verifyResult
+		state  string
+	)
+	start := time.Now()
+	for {
+		result.Attempts++
+		s, err := requestCacheState(ctx, client, rawURL)
+		if err != nil && ctx.Err() == nil {
+			return result, err
+		}
+		if err == nil {
+			state = s
+			if state == "" {
+				return result, fsterr.RemediationError{
+					Inner:       fmt.Errorf("cannot verify purge: %s has no X-Cache response header", rawURL),
+					Remediation: "Verify with a URL served through Fastly, requested directly rather than via another CDN or proxy that strips the header.",
+				}
+			}
+			if state == "MISS" {
+				result.Elapsed = time.Since(start)
+				return result, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, fsterr.RemediationError{
+				Inner:       fmt.Errorf("purge not verified: %s was still served from cache (X-Cache: %s) after %s", rawURL, state, v.Timeout),
+				Remediation: "Purges usually propagate within a few seconds. Retry with a longer --verify-timeout, and check the URL isn't cached by a browser or proxy in front of Fastly.",
+			}
+		case <-time.After(v.Interval):
+		}
+	}
+}
+
+// checkVerifyRedirect limits the redirects followed to maxVerifyRedirects.
+func checkVerifyRedirect(_ *http.Request, via []*http.Request) error {
+	if len(via) > maxVerifyRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxVerifyRedirects)
+	}
+	return nil
+}
+
+// requestCacheState requests rawURL and returns its cache state.
+func requestCacheState(ctx context.Context, client *http.Client, rawURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		var ue *url.Error
+		if errors.As(err, &ue) {
+			err = ue.Err
+		}
+		return "", fmt.Errorf("failed to request %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close() // #nosec G307
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	return CacheState(resp.Header), nil
+}
+
+// CacheState returns the cache state of a response (e.g. "HIT" or "MISS"),
+// or an empty string if the response has no X-Cache header.
+//
+// With shielding the header lists the state at each POP (e.g. "MISS, HIT").
+// The last entry is the state at the POP that served the request.
+func CacheState(h http.Header) string {
+	values := h.Values("X-Cache")
+	if len(values) == 0 {
+		return ""
+	}
+	states := strings.Split(strings.Join(values, ","), ",")
+	return strings.ToUpper(strings.TrimSpace(states[len(states)-1]))
+}
+
+// verifyURL checks the --verify flag value is an HTTP(S) URL.
+func verifyURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid --verify URL: %s", rawURL),
+			Remediation: "Provide an absolute http:// or https:// URL, e.g. --verify https://www.example.com/index.html",
+		}
+	}
+	return nil
+}
+
+// printVerifyResult reports the time taken for the purge to take effect.
+func printVerifyResult(out io.Writer, rawURL string, r verifyResult) {
+	text.Success(out, "Verified purge of %s: MISS observed after %s (%d requests)", rawURL, r.Elapsed.Round(time.Millisecond), r.Attempts)
+}
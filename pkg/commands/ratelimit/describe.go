@@ -9,6 +9,7 @@ import (
 	"github.com/fastly/cli/pkg/argparser"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
 )
 
 // NewDescribeCommand returns a usable command registered under the parent.
@@ -21,7 +22,7 @@ func NewDescribeCommand(parent argparser.Registerer, g *global.Data) *DescribeCo
 	c.CmdClause.Flag("id", "Alphanumeric string identifying the rate limiter").Required().StringVar(&c.id)
 
 	// Optional.
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 
 	return &c
 }
@@ -80,13 +81,13 @@ func (c *DescribeCommand) print(out io.Writer, o *fastly.ERL) {
 	fmt.Fprintf(out, "WindowSize: %+v\n", fastly.ToValue(o.WindowSize))
 
 	if o.CreatedAt != nil {
-		fmt.Fprintf(out, "Created at: %s\n", o.CreatedAt)
+		fmt.Fprintf(out, "Created at: %s\n", text.FormatTime(o.CreatedAt))
 	}
 	if o.UpdatedAt != nil {
-		fmt.Fprintf(out, "Updated at: %s\n", o.UpdatedAt)
+		fmt.Fprintf(out, "Updated at: %s\n", text.FormatTime(o.UpdatedAt))
 	}
 	if o.DeletedAt != nil {
-		fmt.Fprintf(out, "Deleted at: %s\n", o.DeletedAt)
+		fmt.Fprintf(out, "Deleted at: %s\n", text.FormatTime(o.DeletedAt))
 	}
 }
 
@@ -16,6 +16,7 @@ import (
 func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 	var c ListCommand
 	c.CmdClause = parent.Command("list", "List all rate limiters for a particular service and version")
+	c.EmptyState = argparser.EmptyState{Resource: "rate limiters", Create: "fastly rate-limit create --version <VERSION> --name <NAME>"}
 	c.Globals = g
 
 	// Required.
@@ -27,7 +28,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 	})
 
 	// Optional.
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	c.RegisterFlag(argparser.StringFlagOpts{
 		Name:        argparser.FlagServiceIDName,
 		Description: argparser.FlagServiceIDDesc,
@@ -47,6 +48,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 
 	serviceName    argparser.OptionalServiceNameID
 	serviceVersion argparser.OptionalServiceVersion
@@ -93,6 +95,10 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
+	if c.PrintEmpty(out, len(o)) {
+		return nil
+	}
+
 	if c.Globals.Verbose() {
 		c.printVerbose(out, o)
 	} else {
@@ -123,13 +129,13 @@ func (c *ListCommand) printVerbose(out io.Writer, o []*fastly.ERL) {
 		fmt.Fprintf(out, "Version: %+v\n", fastly.ToValue(u.Version))
 		fmt.Fprintf(out, "WindowSize: %+v\n", fastly.ToValue(u.WindowSize))
 		if u.CreatedAt != nil {
-			fmt.Fprintf(out, "Created at: %s\n", u.CreatedAt)
+			fmt.Fprintf(out, "Created at: %s\n", text.FormatTime(u.CreatedAt))
 		}
 		if u.UpdatedAt != nil {
-			fmt.Fprintf(out, "Updated at: %s\n", u.UpdatedAt)
+			fmt.Fprintf(out, "Updated at: %s\n", text.FormatTime(u.UpdatedAt))
 		}
 		if u.DeletedAt != nil {
-			fmt.Fprintf(out, "Deleted at: %s\n", u.DeletedAt)
+			fmt.Fprintf(out, "Deleted at: %s\n", text.FormatTime(u.DeletedAt))
 		}
 	}
 }
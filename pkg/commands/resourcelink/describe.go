@@ -59,7 +59,7 @@ func NewDescribeCommand(parent argparser.Registerer, g *global.Data) *DescribeCo
 	})
 
 	// Optional.
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 
 	return &c
 }
@@ -78,7 +78,10 @@ func (c *DescribeCommand) Exec(_ io.Reader, out io.Writer) error {
 		argparser.DisplayServiceID(serviceID, flag, source, out)
 	}
 
-	serviceVersion, err := c.serviceVersion.Parse(serviceID, c.Globals.APIClient)
+	serviceVersion, _, err := argparser.ResolveEditableVersion(c.Globals.Context, c.Globals.APIClient, serviceID, argparser.VersionFlags{
+		AllowActiveLocked: true,
+		ServiceVersion:    c.serviceVersion,
+	})
 	if err != nil {
 		return err
 	}
@@ -16,6 +16,7 @@ import (
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 
 	input          fastly.ListResourcesInput
 	serviceName    argparser.OptionalServiceNameID
@@ -30,6 +31,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 		},
 	}
 	c.CmdClause = parent.Command("list", "List all resource links for a Fastly service version")
+	c.EmptyState = argparser.EmptyState{Resource: "resource links", Create: "fastly resource-link create --version <VERSION> --resource-id <RESOURCE_ID>"}
 
 	// Required.
 	c.RegisterFlag(argparser.StringFlagOpts{
@@ -54,7 +56,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 	})
 
 	// Optional.
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 
 	return &c
 }
@@ -73,7 +75,10 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		argparser.DisplayServiceID(serviceID, flag, source, out)
 	}
 
-	serviceVersion, err := c.serviceVersion.Parse(serviceID, c.Globals.APIClient)
+	serviceVersion, _, err := argparser.ResolveEditableVersion(c.Globals.Context, c.Globals.APIClient, serviceID, argparser.VersionFlags{
+		AllowActiveLocked: true,
+		ServiceVersion:    c.serviceVersion,
+	})
 	if err != nil {
 		return err
 	}
@@ -94,6 +99,10 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
+	if c.PrintEmpty(out, len(o)) {
+		return nil
+	}
+
 	if !c.Globals.Verbose() {
 		fmt.Fprintf(out, "Service ID: %s\n", c.input.ServiceID)
 	}
@@ -8,6 +8,7 @@ import (
 	"github.com/fastly/cli/pkg/argparser"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/naming"
 	"github.com/fastly/cli/pkg/text"
 )
 
@@ -44,6 +45,10 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 		return fsterr.ErrInvalidVerboseJSONCombo
 	}
 
+	if err := c.ValidateName(naming.SecretStore, c.Input.Name); err != nil {
+		return err
+	}
+
 	o, err := c.Globals.APIClient.CreateSecretStore(&c.Input)
 	if err != nil {
 		c.Globals.ErrLog.Add(err)
@@ -54,7 +59,7 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
-	text.Success(out, "Created Secret Store '%s' (%s)", o.Name, o.StoreID)
+	text.Result(out, o.StoreID, "Created Secret Store '%s' (%s)", o.Name, o.StoreID)
 
 	return nil
 }
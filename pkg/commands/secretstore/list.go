@@ -20,10 +20,11 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 	}
 
 	c.CmdClause = parent.Command("list", "List secret stores")
+	c.EmptyState = argparser.EmptyState{Resource: "secret stores", Create: "fastly secret-store create --name <NAME>"}
 
 	// Optional.
 	c.RegisterFlag(argparser.CursorFlag(&c.Input.Cursor))  // --cursor
-	c.RegisterFlagBool(c.JSONFlag())                       // --json
+	c.RegisterJSONFlags(&c.JSONOutput)                     // --json, --field, --format
 	c.RegisterFlagInt(argparser.LimitFlag(&c.Input.Limit)) // --limit
 
 	return &c
@@ -33,6 +34,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 
 	// NOTE: API returns 10 items even when --limit is set to smaller.
 	Input fastly.ListSecretStoresInput
@@ -64,11 +66,15 @@ func (c *ListCommand) Exec(in io.Reader, out io.Writer) error {
 				break
 			}
 
+			if len(data) == 0 {
+				break
+			}
+
 			text.PrintSecretStoresTbl(out, o.Data)
 
 			if o.Meta.NextCursor != "" {
 				text.Break(out)
-				printNext, err := text.AskYesNo(out, "Print next page [y/N]: ", in)
+				printNext, err := text.AskYesNo(out, "Print next page [y/N]: ", in, "--auto-yes")
 				if err != nil {
 					return err
 				}
@@ -87,6 +93,9 @@ func (c *ListCommand) Exec(in io.Reader, out io.Writer) error {
 	if err != nil {
 		return err
 	}
+	if !ok && c.PrintEmpty(out, len(data)) {
+		return nil
+	}
 
 	// Only print output here if we've not already printed JSON.
 	// And only if we're non interactive.
@@ -33,6 +33,56 @@ const (
 // the FASTLY_USE_API_SIGNING_KEY environment variable.
 var signingKey = mustDecode("CrO/A92vkxEZjtTW7D/Sr+1EMf/q9BahC0sfLkWa+0k=")
 
+// newClientKey fetches a client key and verifies it was signed by the API
+// signing key.
+func newClientKey(g *global.Data) (*fastly.ClientKey, error) {
+	ck, err := g.APIClient.CreateClientKey()
+	if err != nil {
+		g.ErrLog.Add(err)
+		return nil, err
+	}
+
+	sk, err := g.APIClient.GetSigningKey()
+	if err != nil {
+		g.ErrLog.Add(err)
+		return nil, err
+	}
+
+	if !bytes.Equal(sk, signingKey) && os.Getenv("FASTLY_USE_API_SIGNING_KEY") == "" {
+		err := fmt.Errorf("API signing key does not match expected value")
+		g.ErrLog.Add(err)
+		return nil, err
+	}
+
+	if !ck.VerifySignature(sk) {
+		err := fmt.Errorf("unable to validate signature of client key")
+		g.ErrLog.Add(err)
+		return nil, err
+	}
+
+	return ck, nil
+}
+
+// createSecret encrypts the plaintext input.Secret with the client key and
+// creates the secret.
+func createSecret(g *global.Data, ck *fastly.ClientKey, input fastly.CreateSecretInput) (*fastly.Secret, error) {
+	wrapped, err := ck.Encrypt(input.Secret)
+	if err != nil {
+		g.ErrLog.Add(err)
+		return nil, err
+	}
+
+	input.Secret = wrapped
+	input.ClientKey = ck.PublicKey
+
+	o, err := g.APIClient.CreateSecret(&input)
+	if err != nil {
+		g.ErrLog.Add(err)
+		return nil, err
+	}
+	return o, nil
+}
+
 func mustDecode(s string) []byte {
 	b, err := base64.StdEncoding.DecodeString(s)
 	if err != nil {
@@ -56,8 +106,9 @@ func NewCreateCommand(parent argparser.Registerer, g *global.Data) *CreateComman
 	c.RegisterFlag(argparser.StoreIDFlag(&c.Input.StoreID)) // --store-id
 
 	// Optional.
-	c.RegisterFlag(secretFileFlag(&c.secretFile)) // --file
-	c.RegisterFlagBool(c.JSONFlag())              // --json
+	c.RegisterFlag(secretFileFlag(&c.secretFile))       // --file
+	c.RegisterFlag(secretFromEnvFlag(&c.secretFromEnv)) // --from-env
+	c.RegisterFlagBool(c.JSONFlag())                    // --json
 	c.RegisterFlagBool(argparser.BoolFlagOpts{
 		Name:        "recreate",
 		Description: "Recreate secret by name (errors if secret doesn't already exist)",
@@ -84,12 +135,13 @@ type CreateCommand struct {
 	recreate      bool
 	recreateAllow bool
 	secretFile    string
+	secretFromEnv string
 	secretSTDIN   bool
 }
 
 var errMultipleSecretValue = fsterr.RemediationError{
-	Inner:       fmt.Errorf("invalid flag combination, --file and --stdin"),
-	Remediation: "Use one of --file or --stdin flag",
+	Inner:       fmt.Errorf("invalid flag combination, --file, --from-env and --stdin"),
+	Remediation: "Use only one of --file, --from-env or --stdin flag",
 }
 
 var errMaxSecretLength = fsterr.RemediationError{
@@ -102,7 +154,13 @@ func (c *CreateCommand) Exec(in io.Reader, out io.Writer) error {
 	if c.Globals.Verbose() && c.JSONOutput.Enabled {
 		return fsterr.ErrInvalidVerboseJSONCombo
 	}
-	if c.secretFile != "" && c.secretSTDIN {
+	var sources int
+	for _, set := range []bool{c.secretFile != "", c.secretFromEnv != "", c.secretSTDIN} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
 		return errMultipleSecretValue
 	}
 
@@ -118,7 +176,7 @@ func (c *CreateCommand) Exec(in io.Reader, out io.Writer) error {
 		c.Input.Method = http.MethodPut
 	}
 
-	// Read secret's value: either from STDIN, a file, or prompt.
+	// Read secret's value: either from STDIN, a file, the environment, or prompt.
 	switch {
 	case c.secretSTDIN:
 		// Determine if 'in' has data available.
@@ -138,54 +196,38 @@ func (c *CreateCommand) Exec(in io.Reader, out io.Writer) error {
 			return err
 		}
 
+	case c.secretFromEnv != "":
+		v, ok := os.LookupEnv(c.secretFromEnv)
+		if !ok {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("environment variable %s is not set", c.secretFromEnv),
+				Remediation: "Export the environment variable before running the command, or use --file or --stdin.",
+			}
+		}
+		c.Input.Secret = []byte(v)
+
 	default:
-		secret, err := text.InputSecure(out, "Secret: ", in)
+		secret, err := text.InputSecure(out, "Secret: ", in, "--file")
 		if err != nil {
 			return err
 		}
 		c.Input.Secret = []byte(secret)
 	}
 
+	// Ensure the plaintext value can't leak via verbose/debug output.
+	fsterr.RegisterSecret(string(c.Input.Secret))
+
 	if len(c.Input.Secret) > maxSecretLen {
 		return errMaxSecretLength
 	}
 
-	ck, err := c.Globals.APIClient.CreateClientKey()
-	if err != nil {
-		c.Globals.ErrLog.Add(err)
-		return err
-	}
-
-	sk, err := c.Globals.APIClient.GetSigningKey()
-	if err != nil {
-		c.Globals.ErrLog.Add(err)
-		return err
-	}
-
-	if !bytes.Equal(sk, signingKey) && os.Getenv("FASTLY_USE_API_SIGNING_KEY") == "" {
-		err := fmt.Errorf("API signing key does not match expected value")
-		c.Globals.ErrLog.Add(err)
-		return err
-	}
-
-	if !ck.VerifySignature(sk) {
-		err := fmt.Errorf("unable to validate signature of client key")
-		c.Globals.ErrLog.Add(err)
-		return err
-	}
-
-	wrapped, err := ck.Encrypt(c.Input.Secret)
+	ck, err := newClientKey(c.Globals)
 	if err != nil {
-		c.Globals.ErrLog.Add(err)
 		return err
 	}
 
-	c.Input.Secret = wrapped
-	c.Input.ClientKey = ck.PublicKey
-
-	o, err := c.Globals.APIClient.CreateSecret(&c.Input)
+	o, err := createSecret(c.Globals, ck, c.Input)
 	if err != nil {
-		c.Globals.ErrLog.Add(err)
 		return err
 	}
 
@@ -32,3 +32,12 @@ func secretStdinFlag(dst *bool) argparser.BoolFlagOpts {
 		Required:    false,
 	}
 }
+
+func secretFromEnvFlag(dst *string) argparser.StringFlagOpts {
+	return argparser.StringFlagOpts{
+		Name:        "from-env",
+		Description: "Read secret value from the named environment variable instead of prompt",
+		Dst:         dst,
+		Required:    false,
+	}
+}
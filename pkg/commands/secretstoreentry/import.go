@@ -0,0 +1,234 @@
+package secretstoreentry
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// NewImportCommand returns a usable command registered under the parent.
+func NewImportCommand(parent argparser.Registerer, g *global.Data) *ImportCommand {
+	c := ImportCommand{
+		Base: argparser.Base{
+			Globals: g,
+		},
+	}
+
+	c.CmdClause = parent.Command("import", "Create secrets within specified store from a dotenv-style file of NAME=VALUE lines")
+
+	// Required.
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Name:        "file",
+		Short:       'f',
+		Action:      argparser.StdinPath(&c.file),
+		Description: "Path to a dotenv-style file ('-' for stdin)",
+		Dst:         &c.file,
+		Required:    true,
+	})
+	c.RegisterFlag(argparser.StoreIDFlag(&c.storeID)) // --store-id
+
+	// Optional.
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterFlagBool(argparser.BoolFlagOpts{
+		Name:        "recreate-allow",
+		Description: "Create or recreate secrets by name",
+		Dst:         &c.recreateAllow,
+		Required:    false,
+	})
+
+	return &c
+}
+
+// ImportCommand calls the Fastly API to create multiple secrets.
+type ImportCommand struct {
+	argparser.Base
+	argparser.JSONOutput
+
+	file          string
+	recreateAllow bool
+	storeID       string
+}
+
+// importResult is the outcome of creating a single secret.
+type importResult struct {
+	Name      string `json:"name"`
+	Digest    string `json:"digest,omitempty"`
+	Recreated bool   `json:"recreated,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Exec invokes the application logic for the command.
+func (c *ImportCommand) Exec(in io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	r := in
+	if c.file == "-" {
+		if in == nil || text.IsTTY(in) {
+			return fsterr.ErrNoSTDINData
+		}
+	} else {
+		// G304 (CWE-22): Potential file inclusion via variable
+		// #nosec
+		f, err := os.Open(c.file)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		r = f
+	}
+
+	secrets, err := parseDotenv(r)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	if len(secrets) == 0 {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("no secrets found in %s", c.file),
+			Remediation: "Ensure the file contains lines in the format NAME=VALUE.",
+		}
+	}
+
+	ck, err := newClientKey(c.Globals)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	results := make([]importResult, 0, len(secrets))
+	for _, s := range secrets {
+		result := importResult{Name: s.name}
+
+		input := fastly.CreateSecretInput{
+			Name:    s.name,
+			Secret:  []byte(s.value),
+			StoreID: c.storeID,
+		}
+		if c.recreateAllow {
+			input.Method = http.MethodPut
+		}
+
+		switch {
+		case len(input.Secret) > maxSecretLen:
+			result.Error = errMaxSecretLength.Error()
+		default:
+			o, err := createSecret(c.Globals, ck, input)
+			if err != nil {
+				result.Error = fsterr.FilterToken(err.Error())
+				break
+			}
+			result.Digest = hex.EncodeToString(o.Digest)
+			result.Recreated = o.Recreated
+		}
+
+		if result.Error != "" {
+			failed++
+		}
+		results = append(results, result)
+	}
+
+	if ok, err := c.WriteJSON(out, results); ok {
+		if err == nil && failed > 0 {
+			err = fmt.Errorf("failed to create %d of %d secrets", failed, len(results))
+		}
+		return err
+	}
+
+	t := text.NewTable(out)
+	t.AddHeader("NAME", "STATUS", "DETAIL")
+	for _, r := range results {
+		switch {
+		case r.Error != "":
+			t.AddLine(r.Name, "failed", r.Error)
+		case r.Recreated:
+			t.AddLine(r.Name, "recreated", r.Digest)
+		default:
+			t.AddLine(r.Name, "created", r.Digest)
+		}
+	}
+	t.Print()
+
+	if failed > 0 {
+		return fmt.Errorf("failed to create %d of %d secrets", failed, len(results))
+	}
+
+	text.Break(out)
+	text.Success(out, "Imported %d secrets into Secret Store '%s'", len(results), c.storeID)
+	return nil
+}
+
+// dotenvSecret is a single NAME=VALUE entry.
+type dotenvSecret struct {
+	name  string
+	value string
+}
+
+// parseDotenv parses a dotenv-style file.
+//
+// Blank lines and lines starting with # are ignored, an optional `export `
+// prefix is allowed, and values may be wrapped in single or double quotes.
+// Every value is registered for redaction as it's parsed.
+func parseDotenv(r io.Reader) ([]dotenvSecret, error) {
+	var (
+		secrets []dotenvSecret
+		seen    = make(map[string]bool)
+	)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSecretLen*2)
+	for line := 1; scanner.Scan(); line++ {
+		l := strings.TrimSpace(scanner.Text())
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		l = strings.TrimPrefix(l, "export ")
+
+		name, value, ok := strings.Cut(l, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("invalid line %d: expected NAME=VALUE", line),
+				Remediation: "Ensure every non-comment line is in the format NAME=VALUE.",
+			}
+		}
+		if seen[name] {
+			return nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("duplicate secret name '%s' on line %d", name, line),
+				Remediation: "Ensure each secret name appears only once in the file.",
+			}
+		}
+		seen[name] = true
+
+		value = strings.TrimSpace(value)
+		if n := len(value); n >= 2 && (value[0] == '"' || value[0] == '\'') && value[n-1] == value[0] {
+			quote := value[0]
+			value = value[1 : n-1]
+			if quote == '"' {
+				value = strings.NewReplacer(`\n`, "\n", `\"`, `"`, `\\`, `\`).Replace(value)
+			}
+		}
+
+		fsterr.RegisterSecret(value)
+		secrets = append(secrets, dotenvSecret{name: name, value: value})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read secrets: %w", err)
+	}
+	return secrets, nil
+}
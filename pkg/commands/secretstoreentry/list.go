@@ -20,13 +20,14 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 	}
 
 	c.CmdClause = parent.Command("list", "List secrets within a specified store")
+	c.EmptyState = argparser.EmptyState{Resource: "secrets", Create: "fastly secret-store-entry create --store-id <STORE_ID> --name <NAME>"}
 
 	// Required.
 	c.RegisterFlag(argparser.StoreIDFlag(&c.Input.StoreID)) // --store-id
 
 	// Optional.
 	c.RegisterFlag(argparser.CursorFlag(&c.Input.Cursor))  // --cursor
-	c.RegisterFlagBool(c.JSONFlag())                       // --json
+	c.RegisterJSONFlags(&c.JSONOutput)                     // --json, --field, --format
 	c.RegisterFlagInt(argparser.LimitFlag(&c.Input.Limit)) // --limit
 
 	return &c
@@ -36,6 +37,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 
 	Input fastly.ListSecretsInput
 }
@@ -58,12 +60,16 @@ func (c *ListCommand) Exec(in io.Reader, out io.Writer) error {
 			return err
 		}
 
+		if c.Input.Cursor == "" && o != nil && c.PrintEmpty(out, len(o.Data)) {
+			return nil
+		}
+
 		text.PrintSecretsTbl(out, o)
 
 		if o != nil && o.Meta.NextCursor != "" {
 			// Check if 'out' is interactive before prompting.
 			if !c.Globals.Flags.NonInteractive && !c.Globals.Flags.AutoYes && text.IsTTY(out) {
-				printNext, err := text.AskYesNo(out, "Print next page [y/N]: ", in)
+				printNext, err := text.AskYesNo(out, "Print next page [y/N]: ", in, "--auto-yes")
 				if err != nil {
 					return err
 				}
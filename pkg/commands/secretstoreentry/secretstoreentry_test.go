@@ -12,6 +12,7 @@ import (
 	"os"
 	"path"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -20,6 +21,7 @@ import (
 
 	"github.com/fastly/cli/pkg/app"
 	"github.com/fastly/cli/pkg/commands/secretstoreentry"
+	fsterr "github.com/fastly/cli/pkg/errors"
 	fstfmt "github.com/fastly/cli/pkg/fmt"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/mock"
@@ -40,6 +42,7 @@ func TestCreateSecretCommand(t *testing.T) {
 		t.Fatal(err)
 	}
 	doesNotExistFile := path.Join(tmpDir, "DOES-NOT-EXIST")
+	t.Setenv("FASTLY_TEST_SECRET_VALUE", secretValue)
 
 	ckPub, ckPriv, err := box.GenerateKey(rand.Reader)
 	if err != nil {
@@ -101,6 +104,35 @@ func TestCreateSecretCommand(t *testing.T) {
 			args:      fmt.Sprintf("create --store-id %s --name %s --stdin --recreate --recreate-allow", storeID, secretName),
 			wantError: "invalid flag combination, --recreate and --recreate-allow",
 		},
+		{
+			args:      fmt.Sprintf("create --store-id %s --name %s --file %s --from-env FASTLY_TEST_SECRET_VALUE", storeID, secretName, secretFile),
+			wantError: "invalid flag combination, --file, --from-env and --stdin",
+		},
+		{
+			args:      fmt.Sprintf("create --store-id %s --name %s --from-env FASTLY_TEST_DOES_NOT_EXIST", storeID, secretName),
+			wantError: "environment variable FASTLY_TEST_DOES_NOT_EXIST is not set",
+		},
+		// Read from environment variable.
+		{
+			args: fmt.Sprintf("create --store-id %s --name %s --from-env FASTLY_TEST_SECRET_VALUE", storeID, secretName),
+			api: mock.API{
+				CreateClientKeyFn: mockCreateClientKey,
+				GetSigningKeyFn:   mockGetSigningKey,
+				CreateSecretFn: func(i *fastly.CreateSecretInput) (*fastly.Secret, error) {
+					if got, err := decrypt(i.Secret); err != nil {
+						return nil, err
+					} else if got != secretValue {
+						return nil, fmt.Errorf("invalid secret: %s", got)
+					}
+					return &fastly.Secret{
+						Name:   i.Name,
+						Digest: []byte(secretDigest),
+					}, nil
+				},
+			},
+			wantAPIInvoked: true,
+			wantOutput:     fstfmt.Success("Created secret '%s' in Secret Store '%s' (digest: %s)", secretName, storeID, hex.EncodeToString([]byte(secretDigest))),
+		},
 		// Read from STDIN.
 		{
 			args:  fmt.Sprintf("create --store-id %s --name %s --stdin", storeID, secretName),
@@ -262,6 +294,93 @@ func TestCreateSecretCommand(t *testing.T) {
 			}
 		})
 	}
+
+	// Every secret value read must be redacted from logged output.
+	testutil.AssertString(t, "value: REDACTED", fsterr.FilterToken("value: "+secretValue))
+}
+
+func TestImportSecretCommand(t *testing.T) {
+	const storeID = "store123"
+
+	dotenv := path.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(dotenv, []byte(`# comment
+
+FIRST=one-value
+export SECOND="two\nlines"
+THIRD='three # not a comment'
+FAIL=fails-to-create
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ckPub, _, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skPub, skPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ck := &fastly.ClientKey{
+		PublicKey: ckPub[:],
+		Signature: ed25519.Sign(skPriv, ckPub[:]),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	var created []string
+	api := mock.API{
+		CreateClientKeyFn: func() (*fastly.ClientKey, error) { return ck, nil },
+		GetSigningKeyFn:   func() (ed25519.PublicKey, error) { return skPub, nil },
+		CreateSecretFn: func(i *fastly.CreateSecretInput) (*fastly.Secret, error) {
+			if i.Method != http.MethodPut {
+				return nil, fmt.Errorf("got method %q, want %q", i.Method, http.MethodPut)
+			}
+			if i.Name == "FAIL" {
+				return nil, errors.New("whoops")
+			}
+			created = append(created, i.Name)
+			return &fastly.Secret{Name: i.Name, Digest: []byte(i.Name), Recreated: i.Name == "SECOND"}, nil
+		},
+	}
+
+	t.Setenv("FASTLY_USE_API_SIGNING_KEY", "1")
+
+	var stdout bytes.Buffer
+	args := testutil.Args(fmt.Sprintf("%s import --store-id %s --file %s --recreate-allow", secretstoreentry.RootNameSecret, storeID, dotenv))
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		opts := testutil.MockGlobalData(args, &stdout)
+		opts.APIClientFactory = mock.APIClient(api)
+		return opts, nil
+	}
+	err = app.Run(args, nil)
+
+	testutil.AssertErrorContains(t, err, "failed to create 1 of 4 secrets")
+	testutil.AssertEqual(t, []string{"FIRST", "SECOND", "THIRD"}, created)
+	for _, want := range []string{
+		"FIRST   created    " + hex.EncodeToString([]byte("FIRST")),
+		"SECOND  recreated  " + hex.EncodeToString([]byte("SECOND")),
+		"FAIL    failed     whoops",
+	} {
+		testutil.AssertStringContains(t, stdout.String(), want)
+	}
+
+	// Values parsed from the file must be redacted from logged output.
+	for _, v := range []string{"one-value", "two\nlines", "three # not a comment"} {
+		testutil.AssertString(t, "REDACTED", fsterr.FilterToken(v))
+	}
+
+	// The file can also be read from stdin.
+	created = nil
+	args = testutil.Args(fmt.Sprintf("%s import --store-id %s --file - --recreate-allow", secretstoreentry.RootNameSecret, storeID))
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		opts := testutil.MockGlobalData(args, &stdout)
+		opts.APIClientFactory = mock.APIClient(api)
+		opts.Input = strings.NewReader("FOURTH=four\n")
+		return opts, nil
+	}
+	err = app.Run(args, nil)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, []string{"FOURTH"}, created)
 }
 
 func TestDeleteSecretCommand(t *testing.T) {
@@ -0,0 +1,70 @@
+package alias
+
+import (
+	"io"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/config"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// AddCommand calls the Fastly API to validate the service, then adds the
+// alias to the config file.
+type AddCommand struct {
+	argparser.Base
+
+	name      string
+	serviceID string
+}
+
+// NewAddCommand returns a usable command registered under the parent.
+func NewAddCommand(parent argparser.Registerer, g *global.Data) *AddCommand {
+	var c AddCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("add", "Add (or update) an alias for a service, which can be passed to any command with --service")
+	c.CmdClause.Arg("name", "Alias name (e.g. prod-api)").Required().StringVar(&c.name)
+	c.CmdClause.Arg("service-id", "ID of the aliased service").Required().StringVar(&c.serviceID)
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *AddCommand) Exec(_ io.Reader, out io.Writer) error {
+	if err := ValidateName(c.name); err != nil {
+		return err
+	}
+
+	services, err := argparser.ListServices(c.Globals.APIClient)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	previous, updated := c.Globals.Config.ServiceAliases[c.name]
+	if c.Globals.Config.ServiceAliases == nil {
+		c.Globals.Config.ServiceAliases = make(config.ServiceAliases)
+	}
+	c.Globals.Config.ServiceAliases[c.name] = c.serviceID
+	if err := c.Globals.Config.Write(c.Globals.ConfigPath); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	alias := Resolve(config.ServiceAliases{c.name: c.serviceID}, services)[0]
+	if !alias.Exists {
+		text.Warning(out, "No service with ID '%s' was found, so commands using the alias will fail until it exists.", c.serviceID)
+	}
+	if id, ok := Shadowed(c.name, c.serviceID, services); ok {
+		text.Warning(out, "The service %s is also named '%s'. --service %s selects the aliased service, use --service-name %s to select %s.", id, c.name, c.name, c.name, id)
+	}
+
+	switch {
+	case updated && previous != c.serviceID:
+		text.Success(out, "Updated service alias '%s' from %s to %s", c.name, previous, c.serviceID)
+	case alias.ServiceName != "":
+		text.Success(out, "Added service alias '%s' for %s (%s)", c.name, c.serviceID, alias.ServiceName)
+	default:
+		text.Success(out, "Added service alias '%s' for %s", c.name, c.serviceID)
+	}
+	return nil
+}
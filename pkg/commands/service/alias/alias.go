@@ -0,0 +1,77 @@
+package alias
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/config"
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// Alias is a service alias and the service it refers to.
+type Alias struct {
+	Name      string `json:"name"`
+	ServiceID string `json:"service_id"`
+	// ServiceName is the name of the service (empty if it doesn't exist).
+	ServiceName string `json:"service_name"`
+	// Exists indicates the service still exists.
+	Exists bool `json:"exists"`
+}
+
+// validName matches a valid alias name.
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidateName returns an error if name can't be used as an alias.
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid service alias '%s'", name),
+			Remediation: "A service alias must start with a letter or digit, and only contain letters, digits, '.', '_' and '-'.",
+		}
+	}
+	return nil
+}
+
+// Resolve returns the aliases sorted by name, along with the name of each
+// aliased service and whether it still exists.
+func Resolve(aliases config.ServiceAliases, services []*fastly.Service) []Alias {
+	names := make(map[string]string, len(services))
+	for _, s := range services {
+		names[fastly.ToValue(s.ServiceID)] = fastly.ToValue(s.Name)
+	}
+
+	result := make([]Alias, 0, len(aliases))
+	for name, id := range aliases {
+		serviceName, ok := names[id]
+		result = append(result, Alias{
+			Name:        name,
+			ServiceID:   id,
+			ServiceName: serviceName,
+			Exists:      ok,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// Shadowed returns the ID of a service (other than the aliased service) whose
+// name is the same as the alias, as --service selects the alias rather than
+// the service.
+func Shadowed(name, serviceID string, services []*fastly.Service) (string, bool) {
+	for _, s := range services {
+		if fastly.ToValue(s.Name) == name && fastly.ToValue(s.ServiceID) != serviceID {
+			return fastly.ToValue(s.ServiceID), true
+		}
+	}
+	return "", false
+}
+
+// StaleRemediation suggests fixing an alias to a service that doesn't exist.
+func StaleRemediation(name string) string {
+	return fmt.Sprintf("Run `fastly service alias add %s <SERVICE_ID>` to update the alias, or `fastly service alias remove %s` to remove it.", name, name)
+}
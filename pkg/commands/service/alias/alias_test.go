@@ -0,0 +1,319 @@
+package alias_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+	"github.com/fastly/kingpin"
+	toml "github.com/pelletier/go-toml"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/service/alias"
+	"github.com/fastly/cli/pkg/config"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+// fixtureApp is a small command tree with and without the service flags.
+func fixtureApp() *kingpin.Application {
+	a := kingpin.New("fastly", "A tool to interact with the Fastly API")
+	a.Flag("profile", "Switch account profile").Short('o').String()
+	a.Flag("verbose", "Verbose logging").Short('v').Bool()
+
+	service := a.Command("service", "Manipulate Fastly services")
+	describe := service.Command("describe", "Show detailed information about a Fastly service")
+	describe.Flag("service-id", "Service ID").Short('s').String()
+	describe.Flag("service-name", "The name of the service").String()
+	service.Command("list", "List Fastly services")
+	return a
+}
+
+func TestExpand(t *testing.T) {
+	aliases := config.ServiceAliases{
+		"prod-api": "123abc",
+		// An alias with the same name as a service.
+		"staging": "456def",
+	}
+
+	scenarios := []struct {
+		name        string
+		args        string
+		wantArgs    string
+		wantAlias   string
+		wantIgnored bool
+		wantError   string
+	}{
+		{
+			name:     "no flag",
+			args:     "service describe --service-id 123abc",
+			wantArgs: "service describe --service-id 123abc",
+		},
+		{
+			name:      "alias",
+			args:      "service describe --service prod-api --verbose",
+			wantArgs:  "service describe --service-id=123abc --verbose",
+			wantAlias: "123abc",
+		},
+		{
+			name:      "alias with equals",
+			args:      "service describe --service=prod-api",
+			wantArgs:  "service describe --service-id=123abc",
+			wantAlias: "123abc",
+		},
+		{
+			name:      "flag before the command",
+			args:      "--service prod-api service describe",
+			wantArgs:  "--service-id=123abc service describe",
+			wantAlias: "123abc",
+		},
+		{
+			name:     "name lookup when there is no alias",
+			args:     "service describe --service www",
+			wantArgs: "service describe --service-name=www",
+		},
+		{
+			name:      "alias takes precedence over a service name",
+			args:      "service describe --service staging",
+			wantArgs:  "service describe --service-id=456def",
+			wantAlias: "456def",
+		},
+		{
+			name:        "explicit ID takes precedence over an alias",
+			args:        "service describe --service prod-api --service-id 789ghi",
+			wantArgs:    "service describe --service-id 789ghi",
+			wantIgnored: true,
+		},
+		{
+			name:        "explicit short ID takes precedence over an alias",
+			args:        "service describe -s 789ghi --service prod-api",
+			wantArgs:    "service describe -s 789ghi",
+			wantIgnored: true,
+		},
+		{
+			name:      "arguments after -- are kept",
+			args:      "service describe --service prod-api -- --service x",
+			wantArgs:  "service describe --service-id=123abc -- --service x",
+			wantAlias: "123abc",
+		},
+		{
+			name:      "service name flag",
+			args:      "service describe --service prod-api --service-name www",
+			wantError: "invalid flag combination, --service and --service-name",
+		},
+		{
+			name:      "command without service flags",
+			args:      "service list --service prod-api",
+			wantError: "the 'service list' command doesn't accept --service",
+		},
+		{
+			name:      "missing value",
+			args:      "service describe --service",
+			wantError: "expected argument for flag '--service'",
+		},
+		{
+			name:      "repeated",
+			args:      "service describe --service a --service b",
+			wantError: "--service can only be provided once",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			got, r, err := alias.Expand(strings.Fields(s.args), aliases, fixtureApp().Model())
+			testutil.AssertErrorContains(t, err, s.wantError)
+			if s.wantError != "" {
+				return
+			}
+			testutil.AssertString(t, s.wantArgs, strings.Join(got, " "))
+			if !strings.Contains(s.args, "--service ") && !strings.Contains(s.args, "--service=") {
+				if r != nil {
+					t.Fatalf("want no resolution, have %+v", r)
+				}
+				return
+			}
+			testutil.AssertString(t, s.wantAlias, r.ServiceID)
+			testutil.AssertBool(t, s.wantIgnored, r.Ignored)
+		})
+	}
+}
+
+// getServices returns the services 'www' (123abc) and 'staging' (789ghi).
+func getServices(_ *fastly.GetServicesInput) *fastly.ListPaginator[fastly.Service] {
+	return fastly.NewPaginator[fastly.Service](mock.HTTPClient{
+		Errors: []error{nil},
+		Responses: []*http.Response{
+			{
+				Body: io.NopCloser(strings.NewReader(`[
+          {"name": "www", "id": "123abc", "type": "vcl", "version": 1},
+          {"name": "staging", "id": "789ghi", "type": "wasm", "version": 1}
+        ]`)),
+			},
+		},
+	}, fastly.ListOpts{}, "/example")
+}
+
+func TestAliasCommands(t *testing.T) {
+	scenarios := []struct {
+		args       string
+		aliases    config.ServiceAliases
+		wantError  string
+		wantOutput []string
+		wantConfig config.ServiceAliases
+	}{
+		{
+			args:       "service alias add prod-api 123abc",
+			wantOutput: []string{"SUCCESS: Added service alias 'prod-api' for 123abc (www)"},
+			wantConfig: config.ServiceAliases{"prod-api": "123abc"},
+		},
+		{
+			args:       "service alias add prod-api 123abc",
+			aliases:    config.ServiceAliases{"prod-api": "000000", "other": "789ghi"},
+			wantOutput: []string{"SUCCESS: Updated service alias 'prod-api' from 000000 to 123abc"},
+			wantConfig: config.ServiceAliases{"prod-api": "123abc", "other": "789ghi"},
+		},
+		{
+			args: "service alias add old 000000",
+			wantOutput: []string{
+				"WARNING: No service with ID '000000' was found, so commands using the alias will fail until it exists.",
+				"SUCCESS: Added service alias 'old' for 000000",
+			},
+			wantConfig: config.ServiceAliases{"old": "000000"},
+		},
+		{
+			args: "service alias add staging 123abc",
+			wantOutput: []string{
+				"WARNING: The service 789ghi is also named 'staging'. --service staging selects the aliased service, use --service-name staging to select 789ghi.",
+				"SUCCESS: Added service alias 'staging' for 123abc (www)",
+			},
+			wantConfig: config.ServiceAliases{"staging": "123abc"},
+		},
+		{
+			args:      "service alias add prod/api 123abc",
+			wantError: "invalid service alias 'prod/api'",
+		},
+		{
+			args:    "service alias list",
+			aliases: config.ServiceAliases{"prod-api": "123abc", "old": "000000"},
+			wantOutput: []string{
+				"ALIAS     SERVICE ID  SERVICE NAME",
+				"old       000000      (not found)",
+				"prod-api  123abc      www",
+				"WARNING: The service aliased by 'old' no longer exists. Run `fastly service alias add old <SERVICE_ID>` to update the alias, or `fastly service alias remove old` to remove it.",
+			},
+		},
+		{
+			args:       "service alias list --json",
+			aliases:    config.ServiceAliases{"old": "000000"},
+			wantOutput: []string{`"service_id": "000000"`, `"exists": false`},
+		},
+		{
+			args:       "service alias list",
+			wantOutput: []string{"INFO: No service aliases found."},
+		},
+		{
+			args:       "service alias remove prod-api",
+			aliases:    config.ServiceAliases{"prod-api": "123abc", "other": "789ghi"},
+			wantOutput: []string{"SUCCESS: Removed service alias 'prod-api' (123abc)"},
+			wantConfig: config.ServiceAliases{"other": "789ghi"},
+		},
+		{
+			args:      "service alias remove prod-api",
+			wantError: "unknown service alias 'prod-api'",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.args, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.toml")
+			args := testutil.Args(s.args)
+
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIClientFactory = mock.APIClient(mock.API{GetServicesFn: getServices})
+				opts.Config.ServiceAliases = s.aliases
+				opts.ConfigPath = configPath
+				return opts, nil
+			}
+			err := app.Run(args, nil)
+			testutil.AssertErrorContains(t, err, s.wantError)
+
+			output := strings.Join(strings.Fields(stdout.String()), " ")
+			for _, want := range s.wantOutput {
+				testutil.AssertStringContains(t, output, strings.Join(strings.Fields(want), " "))
+			}
+
+			if s.wantConfig != nil {
+				data, err := os.ReadFile(configPath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var f config.File
+				if err := toml.Unmarshal(data, &f); err != nil {
+					t.Fatal(err)
+				}
+				testutil.AssertEqual(t, s.wantConfig, f.ServiceAliases)
+			}
+		})
+	}
+}
+
+// TestServiceFlag validates a command resolves --service using the aliases in
+// the config.
+func TestServiceFlag(t *testing.T) {
+	scenarios := []struct {
+		args          string
+		wantServiceID string
+		wantOutput    string
+	}{
+		{
+			args:          "service describe --service prod-api --verbose",
+			wantServiceID: "123abc",
+			wantOutput:    "INFO: Using service alias 'prod-api' (123abc)",
+		},
+		{
+			args:          "service describe --service staging --service-id 000000 --verbose",
+			wantServiceID: "000000",
+			wantOutput:    "INFO: Ignoring --service staging as --service-id was provided",
+		},
+		{
+			args:          "service describe --service www --verbose",
+			wantServiceID: "123abc",
+			wantOutput:    "INFO: No service alias 'www' found, looking up the service by name",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.args, func(t *testing.T) {
+			args := testutil.Args(s.args)
+
+			var (
+				stdout    bytes.Buffer
+				serviceID string
+			)
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIClientFactory = mock.APIClient(mock.API{
+					GetServicesFn: getServices,
+					GetServiceDetailsFn: func(i *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+						serviceID = i.ServiceID
+						return &fastly.ServiceDetail{ServiceID: fastly.ToPointer(i.ServiceID)}, nil
+					},
+				})
+				opts.Config.ServiceAliases = config.ServiceAliases{"prod-api": "123abc", "staging": "456def"}
+				return opts, nil
+			}
+			err := app.Run(args, nil)
+			testutil.AssertNoError(t, err)
+			testutil.AssertString(t, s.wantServiceID, serviceID)
+			testutil.AssertStringContains(t, strings.Join(strings.Fields(stdout.String()), " "), s.wantOutput)
+		})
+	}
+}
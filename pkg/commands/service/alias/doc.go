@@ -0,0 +1,3 @@
+// Package alias contains commands to manage service aliases, which name a
+// service ID so it can be passed to any command with --service.
+package alias
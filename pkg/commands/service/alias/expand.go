@@ -0,0 +1,118 @@
+package alias
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/fastly/kingpin"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/config"
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// FlagName is the name of the flag that accepts a service alias or name.
+const FlagName = "service"
+
+// Resolution describes how the --service flag was resolved.
+type Resolution struct {
+	// Value is the value of the --service flag.
+	Value string
+	// ServiceID is the ID of the aliased service (empty if Value isn't an
+	// alias).
+	ServiceID string
+	// Ignored indicates --service-id was also provided, which takes
+	// precedence.
+	Ignored bool
+}
+
+// Expand replaces the --service flag in args with the flag the command
+// accepts: --service-id if the value is a service alias, otherwise
+// --service-name so that the service is looked up by name. It returns a nil
+// Resolution if the flag isn't provided.
+//
+// The service is resolved in the order: --service-id, then an alias, then a
+// service name. So an alias takes precedence over a service with the same
+// name, which can still be selected with --service-name.
+//
+// NOTE: The flag isn't defined for each command, it's only expanded here.
+func Expand(args []string, aliases config.ServiceAliases, app *kingpin.ApplicationModel) ([]string, *Resolution, error) {
+	explicit := len(args)
+	if i := slices.Index(args, "--"); i >= 0 {
+		explicit = i
+	}
+
+	var (
+		at   = -1
+		rest []string
+		r    Resolution
+	)
+	for i := 0; i < explicit; i++ {
+		a := args[i]
+		value, hasValue := strings.CutPrefix(a, "--"+FlagName+"=")
+		if a != "--"+FlagName && !hasValue {
+			rest = append(rest, a)
+			continue
+		}
+		if at >= 0 {
+			return nil, nil, fmt.Errorf("error parsing arguments: --%s can only be provided once", FlagName)
+		}
+		if !hasValue {
+			if i+1 >= explicit {
+				return nil, nil, fmt.Errorf("error parsing arguments: expected argument for flag '--%s'", FlagName)
+			}
+			i++
+			value = args[i]
+		}
+		at = len(rest)
+		r.Value = value
+	}
+	if at < 0 {
+		return args, nil, nil
+	}
+	rest = append(rest, args[explicit:]...)
+
+	cmd, _ := argparser.InvokedCommand(rest, app)
+	var idFlag, nameFlag *kingpin.ClauseModel
+	if cmd != nil {
+		flags := argparser.CommandFlags(cmd, app)
+		idFlag = argparser.LookupFlag("--"+argparser.FlagServiceIDName, flags)
+		nameFlag = argparser.LookupFlag("--"+argparser.FlagServiceName, flags)
+	}
+	if idFlag == nil || nameFlag == nil {
+		name := "fastly"
+		if cmd != nil {
+			name = cmd.FullCommand()
+		}
+		return nil, nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("the '%s' command doesn't accept --%s", name, FlagName),
+			Remediation: fmt.Sprintf("Only commands accepting --%s accept --%s. Run the command with --help to see its flags.", argparser.FlagServiceName, FlagName),
+		}
+	}
+
+	for _, a := range rest {
+		if a == "--" {
+			break
+		}
+		switch argparser.LookupFlag(a, []*kingpin.ClauseModel{idFlag, nameFlag}) {
+		case idFlag:
+			r.Ignored = true
+		case nameFlag:
+			return nil, nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("invalid flag combination, --%s and --%s", FlagName, argparser.FlagServiceName),
+				Remediation: fmt.Sprintf("Provide either --%s or --%s.", FlagName, argparser.FlagServiceName),
+			}
+		}
+	}
+	if r.Ignored {
+		return rest, &r, nil
+	}
+
+	flag := fmt.Sprintf("--%s=%s", argparser.FlagServiceName, r.Value)
+	if id, ok := aliases[r.Value]; ok {
+		r.ServiceID = id
+		flag = fmt.Sprintf("--%s=%s", argparser.FlagServiceIDName, id)
+	}
+	return slices.Insert(rest, at, flag), &r, nil
+}
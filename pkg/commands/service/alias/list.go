@@ -0,0 +1,84 @@
+package alias
+
+import (
+	"io"
+	"strings"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// ListCommand calls the Fastly API to list the service aliases along with the
+// aliased services.
+type ListCommand struct {
+	argparser.Base
+	argparser.JSONOutput
+	argparser.EmptyState
+}
+
+// NewListCommand returns a usable command registered under the parent.
+func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
+	var c ListCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("list", "List service aliases, and whether the aliased services still exist")
+	c.EmptyState = argparser.EmptyState{Resource: "service aliases", Create: "fastly service alias add <NAME> <SERVICE_ID>"}
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	var resolved []Alias
+	if aliases := c.Globals.Config.ServiceAliases; len(aliases) > 0 {
+		services, err := argparser.ListServices(c.Globals.APIClient)
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+		resolved = Resolve(aliases, services)
+	}
+	if ok, err := c.WriteJSON(out, resolved); ok {
+		return err
+	}
+	if c.PrintEmpty(out, len(resolved)) {
+		return nil
+	}
+
+	var stale []string
+	t := text.NewTable(out)
+	t.AddHeader("ALIAS", "SERVICE ID", "SERVICE NAME")
+	for _, a := range resolved {
+		name := a.ServiceName
+		if !a.Exists {
+			name = "(not found)"
+			stale = append(stale, a.Name)
+		}
+		t.AddLine(a.Name, a.ServiceID, name)
+	}
+	t.Print()
+
+	if len(stale) > 0 {
+		text.Break(out)
+		if len(stale) == 1 {
+			text.Warning(out, "The service aliased by '%s' no longer exists. %s", stale[0], StaleRemediation(stale[0]))
+			return nil
+		}
+		text.Warning(out, "The services aliased by %s no longer exist. Run `fastly service alias add <NAME> <SERVICE_ID>` to update the aliases, or `fastly service alias remove <NAME>` to remove them.", quote(stale))
+	}
+	return nil
+}
+
+// quote returns the names quoted and separated by commas.
+func quote(names []string) string {
+	quoted := make([]string, 0, len(names))
+	for _, n := range names {
+		quoted = append(quoted, "'"+n+"'")
+	}
+	return strings.Join(quoted, ", ")
+}
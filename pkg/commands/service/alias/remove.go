@@ -0,0 +1,46 @@
+package alias
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// RemoveCommand removes a service alias from the config file.
+type RemoveCommand struct {
+	argparser.Base
+
+	name string
+}
+
+// NewRemoveCommand returns a usable command registered under the parent.
+func NewRemoveCommand(parent argparser.Registerer, g *global.Data) *RemoveCommand {
+	var c RemoveCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("remove", "Remove a service alias")
+	c.CmdClause.Arg("name", "Alias name").Required().StringVar(&c.name)
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *RemoveCommand) Exec(_ io.Reader, out io.Writer) error {
+	id, ok := c.Globals.Config.ServiceAliases[c.name]
+	if !ok {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("unknown service alias '%s'", c.name),
+			Remediation: "Run `fastly service alias list` to list the service aliases.",
+		}
+	}
+
+	delete(c.Globals.Config.ServiceAliases, c.name)
+	if err := c.Globals.Config.Write(c.Globals.ConfigPath); err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	text.Success(out, "Removed service alias '%s' (%s)", c.name, id)
+	return nil
+}
@@ -0,0 +1,603 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/naming"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/cli/pkg/undo"
+)
+
+// CloneCategories are the categories of resource that can be cloned, in the
+// order they're created (conditions and health checks are referenced by
+// backends, so must exist first).
+var CloneCategories = []string{"condition", "healthcheck", "backend", "domain", "dictionary", "acl", "snippet", "vcl", "logging"}
+
+// CloneCommand creates a new service with the configuration of an existing
+// service version.
+type CloneCommand struct {
+	argparser.Base
+
+	copyContent    bool
+	domainMap      string
+	domainSuffix   string
+	keepPartial    bool
+	name           string
+	serviceName    argparser.OptionalServiceNameID
+	serviceVersion argparser.OptionalServiceVersion
+	skip           []string
+}
+
+// NewCloneCommand returns a usable command registered under the parent.
+func NewCloneCommand(parent argparser.Registerer, g *global.Data) *CloneCommand {
+	c := CloneCommand{
+		Base: argparser.Base{
+			Globals: g,
+		},
+	}
+	c.CmdClause = parent.Command("clone", "Create a new Fastly service with the configuration of an existing service version")
+
+	// Required.
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Name:        argparser.FlagVersionName,
+		Description: argparser.FlagVersionDesc,
+		Dst:         &c.serviceVersion.Value,
+		Required:    true,
+	})
+
+	// Optional.
+	c.CmdClause.Flag("copy-content", "Copy the items of dictionaries and the entries of ACLs").BoolVar(&c.copyContent)
+	c.CmdClause.Flag("domain-map", "Path to a file mapping each source domain to a new domain, one 'source target' pair per line").StringVar(&c.domainMap)
+	c.CmdClause.Flag("domain-suffix", "Rename each domain by replacing everything after its first label, e.g. --domain-suffix=-staging.example.com renames www.example.com to www-staging.example.com").StringVar(&c.domainSuffix)
+	c.CmdClause.Flag("keep-partial", "Don't delete the new service if cloning fails part way through").BoolVar(&c.keepPartial)
+	c.CmdClause.Flag("name", "Name of the new service (defaults to the source service name with a '-clone' suffix)").Short('n').StringVar(&c.name)
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Name:        argparser.FlagServiceIDName,
+		Description: argparser.FlagServiceIDDesc,
+		Dst:         &g.Manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        argparser.FlagServiceName,
+		Description: argparser.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.CmdClause.Flag("skip", fmt.Sprintf("Comma-separated categories of resource not to clone (%s). Can be repeated", strings.Join(CloneCategories, ", "))).StringsVar(&c.skip)
+	return &c
+}
+
+// clonedResource is a resource created on the new service.
+type clonedResource struct {
+	Category string
+	Name     string
+	Note     string
+}
+
+// Exec invokes the application logic for the command.
+func (c *CloneCommand) Exec(_ io.Reader, out io.Writer) (err error) {
+	if c.name != "" {
+		if err := c.ValidateName(naming.Service, c.name); err != nil {
+			return err
+		}
+	}
+
+	skip, err := parseSkip(c.skip)
+	if err != nil {
+		return err
+	}
+
+	domains := map[string]string{}
+	if c.domainMap != "" {
+		domains, err = ReadDomainMap(c.domainMap)
+		if err != nil {
+			return err
+		}
+	}
+
+	serviceID, serviceVersion, err := argparser.ServiceDetails(argparser.ServiceDetailsOpts{
+		AllowActiveLocked:  true,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           *c.Globals.Manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": fsterr.ServiceVersion(serviceVersion),
+		})
+		return err
+	}
+	version := fastly.ToValue(serviceVersion.Number)
+
+	source, err := c.Globals.APIClient.GetServiceDetails(&fastly.GetServiceInput{ServiceID: serviceID})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+		})
+		return err
+	}
+	serviceType := fastly.ToValue(source.Type)
+
+	// NOTE: Domains are unique across Fastly, so every domain must be renamed
+	// before anything is created.
+	var renames [][2]string
+	if !skip["domain"] {
+		sourceDomains, err := c.Globals.APIClient.ListDomains(&fastly.ListDomainsInput{ServiceID: serviceID, ServiceVersion: version})
+		if err != nil {
+			return fmt.Errorf("error listing domains: %w", err)
+		}
+		renames, err = RenameDomains(sourceDomains, domains, c.domainSuffix)
+		if err != nil {
+			return err
+		}
+	}
+
+	name := c.name
+	if name == "" {
+		name = fastly.ToValue(source.Name) + "-clone"
+	}
+	input := fastly.CreateServiceInput{
+		Name:    &name,
+		Type:    source.Type,
+		Comment: fastly.ToPointer(fmt.Sprintf("Cloned from service %s version %d", serviceID, version)),
+	}
+	newService, err := c.Globals.APIClient.CreateService(&input)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service Name": name,
+		})
+		return fmt.Errorf("error creating service: %w", err)
+	}
+	newServiceID := fastly.ToValue(newService.ServiceID)
+
+	undoStack := undo.NewStack()
+	undoStack.Push(func() error {
+		text.Info(out, "Deleting partially cloned service %s", newServiceID)
+		return c.Globals.APIClient.DeleteService(&fastly.DeleteServiceInput{ServiceID: newServiceID})
+	})
+	defer func() {
+		if err == nil {
+			return
+		}
+		if c.keepPartial {
+			text.Warning(out, "The partially cloned service %s has been kept (--keep-partial).", newServiceID)
+			return
+		}
+		undoStack.RunIfError(out, err)
+	}()
+
+	cloner := &cloner{
+		c:           c,
+		from:        serviceID,
+		fromVersion: version,
+		to:          newServiceID,
+		toVersion:   1,
+	}
+	for _, category := range CloneCategories {
+		if skip[category] {
+			continue
+		}
+		if serviceType == "wasm" && (category == "snippet" || category == "vcl") {
+			continue
+		}
+		if category == "domain" {
+			err = cloner.domains(renames)
+		} else {
+			err = cloner.clone(category)
+		}
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID":      serviceID,
+				"Service Version": version,
+				"New Service ID":  newServiceID,
+				"Category":        category,
+			})
+			return fmt.Errorf("error cloning %s resources: %w", category, err)
+		}
+	}
+
+	if len(cloner.created) > 0 {
+		t := text.NewTable(out)
+		t.AddHeader("CATEGORY", "NAME", "NOTE")
+		for _, r := range cloner.created {
+			t.AddLine(r.Category, r.Name, r.Note)
+		}
+		t.Print()
+		text.Break(out)
+	}
+	if serviceType == "wasm" {
+		text.Info(out, "The Compute package isn't cloned. Deploy a package to version 1 of the new service before activating it.")
+	}
+	text.Success(out, "Cloned service %s (version %d) to new service %s (version 1)", serviceID, version, newServiceID)
+	return nil
+}
+
+// cloner copies resources from a source service version to a new service.
+type cloner struct {
+	c           *CloneCommand
+	created     []clonedResource
+	from        string
+	fromVersion int
+	to          string
+	toVersion   int
+}
+
+func (cl *cloner) add(category, name, note string) {
+	cl.created = append(cl.created, clonedResource{Category: category, Name: name, Note: note})
+}
+
+// clone copies every resource of the given category.
+func (cl *cloner) clone(category string) error {
+	client := cl.c.Globals.APIClient
+
+	switch category {
+	case "condition":
+		rs, err := client.ListConditions(&fastly.ListConditionsInput{ServiceID: cl.from, ServiceVersion: cl.fromVersion})
+		if err != nil {
+			return err
+		}
+		for _, r := range rs {
+			_, err := client.CreateCondition(&fastly.CreateConditionInput{
+				ServiceID:      cl.to,
+				ServiceVersion: cl.toVersion,
+				Name:           r.Name,
+				Priority:       r.Priority,
+				Statement:      r.Statement,
+				Type:           r.Type,
+			})
+			if err != nil {
+				return fmt.Errorf("%s: %w", fastly.ToValue(r.Name), err)
+			}
+			cl.add(category, fastly.ToValue(r.Name), "")
+		}
+	case "healthcheck":
+		rs, err := client.ListHealthChecks(&fastly.ListHealthChecksInput{ServiceID: cl.from, ServiceVersion: cl.fromVersion})
+		if err != nil {
+			return err
+		}
+		for _, r := range rs {
+			_, err := client.CreateHealthCheck(&fastly.CreateHealthCheckInput{
+				ServiceID:        cl.to,
+				ServiceVersion:   cl.toVersion,
+				CheckInterval:    r.CheckInterval,
+				Comment:          r.Comment,
+				ExpectedResponse: r.ExpectedResponse,
+				HTTPVersion:      r.HTTPVersion,
+				Host:             r.Host,
+				Initial:          r.Initial,
+				Method:           r.Method,
+				Name:             r.Name,
+				Path:             r.Path,
+				Threshold:        r.Threshold,
+				Timeout:          r.Timeout,
+				Window:           r.Window,
+			})
+			if err != nil {
+				return fmt.Errorf("%s: %w", fastly.ToValue(r.Name), err)
+			}
+			cl.add(category, fastly.ToValue(r.Name), "")
+		}
+	case "backend":
+		rs, err := client.ListBackends(&fastly.ListBackendsInput{ServiceID: cl.from, ServiceVersion: cl.fromVersion})
+		if err != nil {
+			return err
+		}
+		for _, r := range rs {
+			_, err := client.CreateBackend(&fastly.CreateBackendInput{
+				ServiceID:           cl.to,
+				ServiceVersion:      cl.toVersion,
+				Address:             r.Address,
+				AutoLoadbalance:     compatibool(r.AutoLoadbalance),
+				BetweenBytesTimeout: r.BetweenBytesTimeout,
+				Comment:             r.Comment,
+				ConnectTimeout:      r.ConnectTimeout,
+				FirstByteTimeout:    r.FirstByteTimeout,
+				HealthCheck:         r.HealthCheck,
+				MaxConn:             r.MaxConn,
+				MaxTLSVersion:       r.MaxTLSVersion,
+				MinTLSVersion:       r.MinTLSVersion,
+				Name:                r.Name,
+				OverrideHost:        r.OverrideHost,
+				Port:                r.Port,
+				RequestCondition:    r.RequestCondition,
+				SSLCACert:           r.SSLCACert,
+				SSLCertHostname:     r.SSLCertHostname,
+				SSLCheckCert:        compatibool(r.SSLCheckCert),
+				SSLCiphers:          r.SSLCiphers,
+				SSLClientCert:       r.SSLClientCert,
+				SSLClientKey:        r.SSLClientKey,
+				SSLSNIHostname:      r.SSLSNIHostname,
+				Shield:              r.Shield,
+				UseSSL:              compatibool(r.UseSSL),
+				Weight:              r.Weight,
+			})
+			if err != nil {
+				return fmt.Errorf("%s: %w", fastly.ToValue(r.Name), err)
+			}
+			cl.add(category, fastly.ToValue(r.Name), fmt.Sprintf("%s:%d", fastly.ToValue(r.Address), fastly.ToValue(r.Port)))
+		}
+	case "dictionary":
+		rs, err := client.ListDictionaries(&fastly.ListDictionariesInput{ServiceID: cl.from, ServiceVersion: cl.fromVersion})
+		if err != nil {
+			return err
+		}
+		for _, r := range rs {
+			d, err := client.CreateDictionary(&fastly.CreateDictionaryInput{
+				ServiceID:      cl.to,
+				ServiceVersion: cl.toVersion,
+				Name:           r.Name,
+				WriteOnly:      compatibool(r.WriteOnly),
+			})
+			if err != nil {
+				return fmt.Errorf("%s: %w", fastly.ToValue(r.Name), err)
+			}
+			note, err := cl.dictionaryItems(r, d)
+			if err != nil {
+				return fmt.Errorf("%s: %w", fastly.ToValue(r.Name), err)
+			}
+			cl.add(category, fastly.ToValue(r.Name), note)
+		}
+	case "acl":
+		rs, err := client.ListACLs(&fastly.ListACLsInput{ServiceID: cl.from, ServiceVersion: cl.fromVersion})
+		if err != nil {
+			return err
+		}
+		for _, r := range rs {
+			a, err := client.CreateACL(&fastly.CreateACLInput{
+				ServiceID:      cl.to,
+				ServiceVersion: cl.toVersion,
+				Name:           r.Name,
+			})
+			if err != nil {
+				return fmt.Errorf("%s: %w", fastly.ToValue(r.Name), err)
+			}
+			note, err := cl.aclEntries(r, a)
+			if err != nil {
+				return fmt.Errorf("%s: %w", fastly.ToValue(r.Name), err)
+			}
+			cl.add(category, fastly.ToValue(r.Name), note)
+		}
+	case "snippet":
+		rs, err := client.ListSnippets(&fastly.ListSnippetsInput{ServiceID: cl.from, ServiceVersion: cl.fromVersion})
+		if err != nil {
+			return err
+		}
+		for _, r := range rs {
+			_, err := client.CreateSnippet(&fastly.CreateSnippetInput{
+				ServiceID:      cl.to,
+				ServiceVersion: cl.toVersion,
+				Content:        r.Content,
+				Dynamic:        r.Dynamic,
+				Name:           r.Name,
+				Priority:       r.Priority,
+				Type:           r.Type,
+			})
+			if err != nil {
+				return fmt.Errorf("%s: %w", fastly.ToValue(r.Name), err)
+			}
+			cl.add(category, fastly.ToValue(r.Name), "")
+		}
+	case "vcl":
+		rs, err := client.ListVCLs(&fastly.ListVCLsInput{ServiceID: cl.from, ServiceVersion: cl.fromVersion})
+		if err != nil {
+			return err
+		}
+		for _, r := range rs {
+			_, err := client.CreateVCL(&fastly.CreateVCLInput{
+				ServiceID:      cl.to,
+				ServiceVersion: cl.toVersion,
+				Content:        r.Content,
+				Main:           r.Main,
+				Name:           r.Name,
+			})
+			if err != nil {
+				return fmt.Errorf("%s: %w", fastly.ToValue(r.Name), err)
+			}
+			cl.add(category, fastly.ToValue(r.Name), "")
+		}
+	case "logging":
+		// NOTE: Logging endpoints hold credentials for third-party services,
+		// so they're listed for the user to recreate rather than copied.
+		rs, err := listLoggingEndpoints(client, cl.from, cl.fromVersion)
+		if err != nil {
+			return err
+		}
+		for _, r := range rs {
+			cl.add(category, r.Name, fmt.Sprintf("not cloned, recreate with `fastly logging %s create`", r.Type))
+		}
+	}
+	return nil
+}
+
+// domains creates the renamed domains.
+func (cl *cloner) domains(renames [][2]string) error {
+	for _, r := range renames {
+		_, err := cl.c.Globals.APIClient.CreateDomain(&fastly.CreateDomainInput{
+			ServiceID:      cl.to,
+			ServiceVersion: cl.toVersion,
+			Name:           fastly.ToPointer(r[1]),
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", r[1], err)
+		}
+		cl.add("domain", r[1], "renamed from "+r[0])
+	}
+	return nil
+}
+
+// dictionaryItems copies the items of a dictionary when --copy-content is set.
+func (cl *cloner) dictionaryItems(from, to *fastly.Dictionary) (string, error) {
+	if !cl.c.copyContent {
+		return "", nil
+	}
+	if fastly.ToValue(from.WriteOnly) {
+		return "write-only, items not copied", nil
+	}
+	items, err := cl.c.Globals.APIClient.ListDictionaryItems(&fastly.ListDictionaryItemsInput{
+		ServiceID:    cl.from,
+		DictionaryID: fastly.ToValue(from.DictionaryID),
+	})
+	if err != nil {
+		return "", err
+	}
+	for i := 0; i < len(items); i += fastly.BatchModifyMaximumOperations {
+		end := min(i+fastly.BatchModifyMaximumOperations, len(items))
+		input := fastly.BatchModifyDictionaryItemsInput{
+			DictionaryID: fastly.ToValue(to.DictionaryID),
+			ServiceID:    cl.to,
+		}
+		for _, item := range items[i:end] {
+			input.Items = append(input.Items, &fastly.BatchDictionaryItem{
+				ItemKey:   item.ItemKey,
+				ItemValue: item.ItemValue,
+				Operation: fastly.ToPointer(fastly.CreateBatchOperation),
+			})
+		}
+		if err := cl.c.Globals.APIClient.BatchModifyDictionaryItems(&input); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%d item(s) copied", len(items)), nil
+}
+
+// aclEntries copies the entries of an ACL when --copy-content is set.
+func (cl *cloner) aclEntries(from, to *fastly.ACL) (string, error) {
+	if !cl.c.copyContent {
+		return "", nil
+	}
+	entries, err := cl.c.Globals.APIClient.ListACLEntries(&fastly.ListACLEntriesInput{
+		ServiceID: cl.from,
+		ACLID:     fastly.ToValue(from.ACLID),
+	})
+	if err != nil {
+		return "", err
+	}
+	for i := 0; i < len(entries); i += fastly.BatchModifyMaximumOperations {
+		end := min(i+fastly.BatchModifyMaximumOperations, len(entries))
+		ops := make([]*fastly.BatchACLEntry, 0, end-i)
+		for _, e := range entries[i:end] {
+			ops = append(ops, &fastly.BatchACLEntry{
+				Comment:   e.Comment,
+				IP:        e.IP,
+				Negated:   compatibool(e.Negated),
+				Operation: fastly.ToPointer(fastly.CreateBatchOperation),
+				Subnet:    e.Subnet,
+			})
+		}
+		err := cl.c.Globals.APIClient.BatchModifyACLEntries(&fastly.BatchModifyACLEntriesInput{
+			ACLID:     fastly.ToValue(to.ACLID),
+			Entries:   ops,
+			ServiceID: cl.to,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%d entr(ies) copied", len(entries)), nil
+}
+
+// RenameDomains returns each domain paired with its new name. The mapping
+// takes precedence over the suffix, and an error is returned if a domain
+// can't be renamed by either.
+func RenameDomains(domains []*fastly.Domain, mapping map[string]string, suffix string) ([][2]string, error) {
+	var (
+		renames   [][2]string
+		unmatched []string
+	)
+	for _, d := range domains {
+		name := fastly.ToValue(d.Name)
+		if target, ok := mapping[name]; ok {
+			renames = append(renames, [2]string{name, target})
+			continue
+		}
+		if suffix != "" {
+			label, _, _ := strings.Cut(name, ".")
+			renames = append(renames, [2]string{name, label + suffix})
+			continue
+		}
+		unmatched = append(unmatched, name)
+	}
+	if len(unmatched) > 0 {
+		sort.Strings(unmatched)
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("no new name for domain(s): %s", strings.Join(unmatched, ", ")),
+			Remediation: "Domains can only belong to one service. Rename them with --domain-suffix or --domain-map, or don't clone them with --skip domain.",
+		}
+	}
+	return renames, nil
+}
+
+// ReadDomainMap reads a file of 'source target' domain pairs, one per line.
+// Blank lines and lines starting with # are ignored.
+func ReadDomainMap(path string) (map[string]string, error) {
+	path = filepath.Clean(path)
+	f, err := os.Open(path) // #nosec G304 (CWE-22)
+	if err != nil {
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("failed to open domain map '%s': %w", path, err),
+			Remediation: fsterr.HostRemediation,
+		}
+	}
+	defer f.Close() // #nosec G307
+
+	mapping := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid domain map line %d: want 'source target', got %q", n, line)
+		}
+		mapping[fields[0]] = fields[1]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read domain map '%s': %w", path, err)
+	}
+	return mapping, nil
+}
+
+func parseSkip(values []string) (map[string]bool, error) {
+	skip := make(map[string]bool)
+	for _, v := range values {
+		for _, category := range strings.Split(v, ",") {
+			category = strings.TrimSpace(category)
+			if category == "" {
+				continue
+			}
+			if !slices.Contains(CloneCategories, category) {
+				return nil, fsterr.RemediationError{
+					Inner:       fmt.Errorf("invalid --skip category %q", category),
+					Remediation: fmt.Sprintf("Valid categories are: %s.", strings.Join(CloneCategories, ", ")),
+				}
+			}
+			skip[category] = true
+		}
+	}
+	return skip, nil
+}
+
+func compatibool(b *bool) *fastly.Compatibool {
+	if b == nil {
+		return nil
+	}
+	return fastly.ToPointer(fastly.Compatibool(*b))
+}
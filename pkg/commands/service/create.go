@@ -7,6 +7,7 @@ import (
 
 	"github.com/fastly/cli/pkg/argparser"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/naming"
 	"github.com/fastly/cli/pkg/text"
 )
 
@@ -38,6 +39,12 @@ func NewCreateCommand(parent argparser.Registerer, g *global.Data) *CreateComman
 
 // Exec invokes the application logic for the command.
 func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.name.WasSet {
+		if err := c.ValidateName(naming.Service, c.name.Value); err != nil {
+			return err
+		}
+	}
+
 	input := fastly.CreateServiceInput{}
 
 	if c.name.WasSet {
@@ -59,6 +66,6 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
-	text.Success(out, "Created service %s", fastly.ToValue(s.ServiceID))
+	text.Result(out, fastly.ToValue(s.ServiceID), "Created service %s", fastly.ToValue(s.ServiceID))
 	return nil
 }
@@ -48,7 +48,7 @@ func NewDeleteCommand(parent argparser.Registerer, g *global.Data) *DeleteComman
 }
 
 // Exec invokes the application logic for the command.
-func (c *DeleteCommand) Exec(_ io.Reader, out io.Writer) error {
+func (c *DeleteCommand) Exec(in io.Reader, out io.Writer) error {
 	serviceID, source, flag, err := argparser.ServiceID(c.serviceName, *c.Globals.Manifest, c.Globals.APIClient, c.Globals.ErrLog)
 	if err != nil {
 		return err
@@ -59,6 +59,21 @@ func (c *DeleteCommand) Exec(_ io.Reader, out io.Writer) error {
 
 	c.Input.ServiceID = serviceID
 
+	action := fmt.Sprintf("delete service %s", serviceID)
+	if c.force {
+		action = fmt.Sprintf("deactivate and delete service %s", serviceID)
+	}
+	err = argparser.Confirm(argparser.ConfirmOpts{
+		Action:    action,
+		Globals:   c.Globals,
+		In:        in,
+		Out:       out,
+		ServiceID: serviceID,
+	})
+	if err != nil {
+		return err
+	}
+
 	if c.force {
 		s, err := c.Globals.APIClient.GetServiceDetails(&fastly.GetServiceInput{
 			ServiceID: serviceID,
@@ -7,6 +7,7 @@ import (
 	"github.com/fastly/go-fastly/v9/fastly"
 
 	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/commands/service/tag"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/manifest"
@@ -33,7 +34,7 @@ func NewDescribeCommand(parent argparser.Registerer, g *global.Data) *DescribeCo
 	c.CmdClause = parent.Command("describe", "Show detailed information about a Fastly service").Alias("get")
 
 	// Optional.
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	c.RegisterFlag(argparser.StringFlagOpts{
 		Name:        argparser.FlagServiceIDName,
 		Description: argparser.FlagServiceIDDesc,
@@ -64,7 +65,7 @@ func (c *DescribeCommand) Exec(_ io.Reader, out io.Writer) error {
 	}
 
 	if source == manifest.SourceUndefined && !c.serviceName.WasSet {
-		err := fsterr.ErrNoServiceID
+		err := argparser.NoServiceIDError()
 		c.Globals.ErrLog.Add(err)
 		return err
 	}
@@ -90,16 +91,20 @@ func (c *DescribeCommand) print(s *fastly.ServiceDetail, out io.Writer) error {
 	fmt.Fprintf(out, "ID: %s\n", fastly.ToValue(s.ServiceID))
 	fmt.Fprintf(out, "Name: %s\n", fastly.ToValue(s.Name))
 	fmt.Fprintf(out, "Type: %s\n", fastly.ToValue(s.Type))
-	fmt.Fprintf(out, "Comment: %s\n", fastly.ToValue(s.Comment))
+	comment, tags := tag.Split(fastly.ToValue(s.Comment))
+	fmt.Fprintf(out, "Comment: %s\n", comment)
+	if len(tags) > 0 {
+		fmt.Fprintf(out, "Tags: %s\n", tags)
+	}
 	fmt.Fprintf(out, "Customer ID: %s\n", fastly.ToValue(s.CustomerID))
 	if s.CreatedAt != nil {
-		fmt.Fprintf(out, "Created (UTC): %s\n", s.CreatedAt.UTC().Format(time.Format))
+		fmt.Fprintf(out, "%s: %s\n", text.TimeLabel("Created"), text.FormatTimeLayout(s.CreatedAt, time.Format))
 	}
 	if s.UpdatedAt != nil {
-		fmt.Fprintf(out, "Last edited (UTC): %s\n", s.UpdatedAt.UTC().Format(time.Format))
+		fmt.Fprintf(out, "%s: %s\n", text.TimeLabel("Last edited"), text.FormatTimeLayout(s.UpdatedAt, time.Format))
 	}
 	if s.DeletedAt != nil {
-		fmt.Fprintf(out, "Deleted (UTC): %s\n", s.DeletedAt.UTC().Format(time.Format))
+		fmt.Fprintf(out, "%s: %s\n", text.TimeLabel("Deleted"), text.FormatTimeLayout(s.DeletedAt, time.Format))
 	}
 	if s.ActiveVersion != nil {
 		fmt.Fprintf(out, "Active version:\n")
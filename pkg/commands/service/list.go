@@ -3,26 +3,37 @@ package service
 import (
 	"fmt"
 	"io"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/fastly/go-fastly/v9/fastly"
 
 	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/commands/service/tag"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/text"
-	"github.com/fastly/cli/pkg/time"
+	fsttime "github.com/fastly/cli/pkg/time"
 )
 
 // ListCommand calls the Fastly API to list services.
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 
+	columns       string
+	customerID    string
 	direction     string
+	nameFilter    string
 	page, perPage int
 	input         fastly.GetServicesInput
+	serviceType   string
 	sort          string
+	tags          []string
 }
 
 // NewListCommand returns a usable command registered under the parent.
@@ -33,13 +44,19 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 		},
 	}
 	c.CmdClause = parent.Command("list", "List Fastly services")
+	c.EmptyState = argparser.EmptyState{Resource: "services", Create: "fastly service create --name <NAME>"}
 
 	// Optional.
+	c.CmdClause.Flag("columns", fmt.Sprintf("Comma-separated columns to display, in order. Any of: %s", strings.Join(serviceColumnNames, ", "))).Default(defaultServiceColumns).StringVar(&c.columns)
+	c.CmdClause.Flag("customer-id", "Only list services owned by this customer (e.g. for resellers)").StringVar(&c.customerID)
 	c.CmdClause.Flag("direction", "Direction in which to sort results").Default(argparser.PaginationDirection[0]).HintOptions(argparser.PaginationDirection...).EnumVar(&c.direction, argparser.PaginationDirection...)
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
+	c.CmdClause.Flag("name-filter", "Only list services whose name matches this regular expression (or substring), ignoring case").StringVar(&c.nameFilter)
 	c.CmdClause.Flag("page", "Page number of data set to fetch").IntVar(&c.page)
 	c.CmdClause.Flag("per-page", "Number of records per page").IntVar(&c.perPage)
-	c.CmdClause.Flag("sort", "Field on which to sort").Default("created").StringVar(&c.sort)
+	c.CmdClause.Flag("sort", fmt.Sprintf("Field on which to sort. One of: %s", strings.Join(serviceColumnNames, ", "))).Default("created").HintOptions(serviceColumnNames...).EnumVar(&c.sort, serviceColumnNames...)
+	c.CmdClause.Flag("tag", tag.FilterFlagDesc).StringsVar(&c.tags)
+	c.CmdClause.Flag("type", "Only list services of this type").HintOptions(serviceTypes...).EnumVar(&c.serviceType, serviceTypes...)
 	return &c
 }
 
@@ -49,54 +66,103 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		return fsterr.ErrInvalidVerboseJSONCombo
 	}
 
-	c.input.Direction = &c.direction
+	columns, err := parseServiceColumns(c.columns)
+	if err != nil {
+		return err
+	}
+	var nameRegex *regexp.Regexp
+	if c.nameFilter != "" {
+		nameRegex, err = regexp.Compile("(?i)" + c.nameFilter)
+		if err != nil {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("invalid --name-filter pattern: %w", err),
+				Remediation: "Provide a valid regular expression (see https://pkg.go.dev/regexp/syntax) or a plain substring.",
+			}
+		}
+	}
+
+	filter, err := tag.ParseFilter(c.tags)
+	if err != nil {
+		return err
+	}
+
+	// NOTE: Services are filtered and sorted once every page has been fetched
+	// (or the --max-time deadline is reached, in which case the services
+	// fetched so far are listed).
 	c.input.Page = &c.page
 	c.input.PerPage = &c.perPage
-	c.input.Sort = &c.sort
 	paginator := c.Globals.APIClient.GetServices(&c.input)
 
-	var o []*fastly.Service
-	for paginator.HasNext() {
+	var all []*fastly.Service
+	for paginator.HasNext() && !argparser.MaxTimeReached(c.Globals) {
 		data, err := paginator.GetNext()
 		if err != nil {
+			if argparser.MaxTimeReached(c.Globals) {
+				break
+			}
 			c.Globals.ErrLog.AddWithContext(err, map[string]any{
 				"Remaining Pages": paginator.Remaining(),
 			})
 			return err
 		}
-		o = append(o, data...)
+		all = append(all, data...)
+	}
+
+	o := make([]*fastly.Service, 0, len(all))
+	for _, s := range all {
+		if c.serviceType != "" && fastly.ToValue(s.Type) != c.serviceType {
+			continue
+		}
+		if c.customerID != "" && fastly.ToValue(s.CustomerID) != c.customerID {
+			continue
+		}
+		if nameRegex != nil && !nameRegex.MatchString(fastly.ToValue(s.Name)) {
+			continue
+		}
+		if !filter.Match(fastly.ToValue(s.Comment)) {
+			continue
+		}
+		o = append(o, s)
+	}
+	// The tags are listed by default when any service has them.
+	if c.columns == defaultServiceColumns && anyTagged(o) {
+		columns = append(columns, "tags")
 	}
+	sortServices(o, c.sort, c.direction == "descend")
 
 	if ok, err := c.WriteJSON(out, o); ok {
-		return err
+		if err != nil {
+			return err
+		}
+		return argparser.PartialResults(c.Globals)
 	}
 
+	if c.PrintEmpty(out, len(all)) {
+		return argparser.PartialResults(c.Globals)
+	}
+
+	filtered := c.serviceType != "" || c.customerID != "" || c.nameFilter != "" || len(c.tags) > 0
+
 	if !c.Globals.Verbose() {
 		tw := text.NewTable(out)
-		tw.AddHeader("NAME", "ID", "TYPE", "ACTIVE VERSION", "LAST EDITED (UTC)")
+		headers := make([]any, 0, len(columns))
+		for _, col := range columns {
+			headers = append(headers, serviceColumns[col].header)
+		}
+		tw.AddHeader(headers...)
 		for _, service := range o {
-			updatedAt := "n/a"
-			if service.UpdatedAt != nil {
-				updatedAt = service.UpdatedAt.UTC().Format(time.Format)
+			values := make([]any, 0, len(columns))
+			for _, col := range columns {
+				values = append(values, serviceColumns[col].value(service))
 			}
-
-			activeVersion := strconv.Itoa(fastly.ToValue(service.ActiveVersion))
-			for _, v := range service.Versions {
-				if fastly.ToValue(v.Number) == fastly.ToValue(service.ActiveVersion) && !fastly.ToValue(v.Active) {
-					activeVersion = "n/a"
-				}
-			}
-
-			tw.AddLine(
-				fastly.ToValue(service.Name),
-				fastly.ToValue(service.ServiceID),
-				fastly.ToValue(service.Type),
-				activeVersion,
-				updatedAt,
-			)
+			tw.AddLine(values...)
 		}
 		tw.Print()
-		return nil
+		if filtered {
+			text.Break(out)
+			text.Info(out, "%d of %d services matched", len(o), len(all))
+		}
+		return argparser.PartialResults(c.Globals)
 	}
 
 	for i, service := range o {
@@ -104,6 +170,144 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		text.PrintService(out, "\t", service)
 		fmt.Fprintln(out)
 	}
+	if filtered {
+		text.Info(out, "%d of %d services matched", len(o), len(all))
+	}
+
+	return argparser.PartialResults(c.Globals)
+}
+
+// serviceTypes are the values of the --type flag.
+var serviceTypes = []string{"vcl", "wasm"}
+
+// defaultServiceColumns is the default value of the --columns flag.
+const defaultServiceColumns = "name,id,type,active_version,updated"
 
-	return nil
+// serviceColumnNames are the columns (and sort fields) of the service list.
+var serviceColumnNames = []string{"name", "id", "type", "active_version", "created", "updated", "customer_id", "comment", "tags"}
+
+// serviceColumn describes a column of the service list.
+type serviceColumn struct {
+	header string
+	value  func(s *fastly.Service) string
+	// key is the value to sort by, when it differs from the displayed value.
+	key func(s *fastly.Service) any
+}
+
+var serviceColumns = map[string]serviceColumn{
+	"name": {header: "NAME", value: func(s *fastly.Service) string { return fastly.ToValue(s.Name) }},
+	"id":   {header: "ID", value: func(s *fastly.Service) string { return fastly.ToValue(s.ServiceID) }},
+	"type": {header: "TYPE", value: func(s *fastly.Service) string { return fastly.ToValue(s.Type) }},
+	"active_version": {
+		header: "ACTIVE VERSION",
+		value:  activeVersion,
+		key:    func(s *fastly.Service) any { return fastly.ToValue(s.ActiveVersion) },
+	},
+	"created": {
+		header: text.TimeLabel("CREATED"),
+		value:  func(s *fastly.Service) string { return formatTime(s.CreatedAt) },
+		key:    func(s *fastly.Service) any { return timeKey(s.CreatedAt) },
+	},
+	"updated": {
+		header: text.TimeLabel("LAST EDITED"),
+		value:  func(s *fastly.Service) string { return formatTime(s.UpdatedAt) },
+		key:    func(s *fastly.Service) any { return timeKey(s.UpdatedAt) },
+	},
+	"customer_id": {header: "CUSTOMER ID", value: func(s *fastly.Service) string { return fastly.ToValue(s.CustomerID) }},
+	"comment":     {header: "COMMENT", value: func(s *fastly.Service) string { return serviceComment(s) }},
+	"tags":        {header: "TAGS", value: func(s *fastly.Service) string { return serviceTags(s).String() }},
+}
+
+// serviceComment returns the service's comment, without its tags.
+func serviceComment(s *fastly.Service) string {
+	comment, _ := tag.Split(fastly.ToValue(s.Comment))
+	return comment
+}
+
+// serviceTags returns the tags recorded in the service's comment.
+func serviceTags(s *fastly.Service) tag.Tags {
+	_, tags := tag.Split(fastly.ToValue(s.Comment))
+	return tags
+}
+
+// anyTagged indicates whether any of the services has tags.
+func anyTagged(services []*fastly.Service) bool {
+	for _, s := range services {
+		if len(serviceTags(s)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// parseServiceColumns validates the --columns flag value.
+func parseServiceColumns(value string) ([]string, error) {
+	var columns []string
+	for _, col := range strings.Split(value, ",") {
+		col = strings.ToLower(strings.TrimSpace(col))
+		if col == "" {
+			continue
+		}
+		if _, ok := serviceColumns[col]; !ok {
+			return nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("error parsing arguments: unknown --columns value '%s'", col),
+				Remediation: fmt.Sprintf("Use a comma-separated list of: %s", strings.Join(serviceColumnNames, ", ")),
+			}
+		}
+		columns = append(columns, col)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("error parsing arguments: --columns cannot be empty")
+	}
+	return columns, nil
+}
+
+// sortServices sorts the services by the given column, keeping the API order
+// for equal values.
+func sortServices(services []*fastly.Service, column string, descend bool) {
+	col := serviceColumns[column]
+	less := func(a, b *fastly.Service) bool {
+		if col.key == nil {
+			return strings.ToLower(col.value(a)) < strings.ToLower(col.value(b))
+		}
+		switch x := col.key(a).(type) {
+		case int:
+			return x < col.key(b).(int)
+		case time.Time:
+			return x.Before(col.key(b).(time.Time))
+		}
+		return false
+	}
+	sort.SliceStable(services, func(i, j int) bool {
+		if descend {
+			return less(services[j], services[i])
+		}
+		return less(services[i], services[j])
+	})
+}
+
+// activeVersion returns the active version number, or n/a if the service's
+// version list shows it isn't active.
+func activeVersion(s *fastly.Service) string {
+	for _, v := range s.Versions {
+		if fastly.ToValue(v.Number) == fastly.ToValue(s.ActiveVersion) && !fastly.ToValue(v.Active) {
+			return "n/a"
+		}
+	}
+	return strconv.Itoa(fastly.ToValue(s.ActiveVersion))
+}
+
+// timeKey sorts a missing timestamp before any other.
+func timeKey(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "n/a"
+	}
+	return text.FormatTimeLayout(t, fsttime.Format)
 }
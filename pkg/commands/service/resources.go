@@ -0,0 +1,484 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// ResourcesCommand calls the Fastly API to describe every resource on a
+// service version.
+type ResourcesCommand struct {
+	argparser.Base
+	argparser.JSONOutput
+
+	serviceName    argparser.OptionalServiceNameID
+	serviceVersion argparser.OptionalServiceVersion
+}
+
+// NewResourcesCommand returns a usable command registered under the parent.
+func NewResourcesCommand(parent argparser.Registerer, g *global.Data) *ResourcesCommand {
+	c := ResourcesCommand{
+		Base: argparser.Base{
+			Globals: g,
+		},
+	}
+	c.CmdClause = parent.Command("resources", "Show every resource configured on a Fastly service version")
+
+	// Required.
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Name:        argparser.FlagVersionName,
+		Description: argparser.FlagVersionDesc,
+		Dst:         &c.serviceVersion.Value,
+		Required:    true,
+	})
+
+	// Optional.
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Name:        argparser.FlagServiceIDName,
+		Description: argparser.FlagServiceIDDesc,
+		Dst:         &g.Manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        argparser.FlagServiceName,
+		Description: argparser.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	return &c
+}
+
+// ResourceReport is every resource on a service version.
+type ResourceReport struct {
+	ServiceID      string             `json:"service_id"`
+	ServiceName    string             `json:"service_name,omitempty"`
+	ServiceType    string             `json:"service_type,omitempty"`
+	ServiceVersion int                `json:"service_version"`
+	Sections       []*ResourceSection `json:"sections"`
+}
+
+// ResourceSection is a single type of resource. If the resources couldn't be
+// (fully) fetched then Error is set, and the rest of the report is still valid.
+type ResourceSection struct {
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"`
+	Items any    `json:"items"`
+
+	header []string
+	rows   [][]any
+}
+
+// resourceFetcher fetches a section's items and renders them as table rows.
+type resourceFetcher struct {
+	name  string
+	fetch func() (items any, header []string, rows [][]any, err error)
+}
+
+// Exec invokes the application logic for the command.
+func (c *ResourcesCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	serviceID, serviceVersion, err := argparser.ServiceDetails(argparser.ServiceDetailsOpts{
+		AllowActiveLocked:  true,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           *c.Globals.Manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": fsterr.ServiceVersion(serviceVersion),
+		})
+		return err
+	}
+
+	report := &ResourceReport{
+		ServiceID:      serviceID,
+		ServiceVersion: fastly.ToValue(serviceVersion.Number),
+	}
+
+	// NOTE: The service type decides whether VCL or a package is relevant. If
+	// the lookup fails then both are fetched.
+	var serviceType string
+	if s, err := c.Globals.APIClient.GetServiceDetails(&fastly.GetServiceInput{ServiceID: serviceID}); err == nil {
+		report.ServiceName = fastly.ToValue(s.Name)
+		serviceType = fastly.ToValue(s.Type)
+		report.ServiceType = serviceType
+	} else {
+		c.Globals.ErrLog.Add(err)
+	}
+
+	fetchers := resourceFetchers(c.Globals.APIClient, serviceID, report.ServiceVersion, serviceType)
+	report.Sections = make([]*ResourceSection, len(fetchers))
+
+	var wg sync.WaitGroup
+	for i, f := range fetchers {
+		wg.Add(1)
+		go func(i int, f resourceFetcher) {
+			defer wg.Done()
+			s := &ResourceSection{Name: f.name}
+			items, header, rows, err := f.fetch()
+			if err != nil {
+				c.Globals.ErrLog.AddWithContext(err, map[string]any{
+					"Service ID":      serviceID,
+					"Service Version": report.ServiceVersion,
+					"Section":         f.name,
+				})
+				s.Error = err.Error()
+			}
+			s.Items, s.header, s.rows = items, header, rows
+			report.Sections[i] = s
+		}(i, f)
+	}
+	wg.Wait()
+
+	if ok, err := c.WriteJSON(out, report); ok {
+		return err
+	}
+
+	c.print(out, report)
+	return nil
+}
+
+func (c *ResourcesCommand) print(out io.Writer, r *ResourceReport) {
+	fmt.Fprintf(out, "Service ID: %s\n", r.ServiceID)
+	if r.ServiceName != "" {
+		fmt.Fprintf(out, "Service name: %s\n", r.ServiceName)
+	}
+	if r.ServiceType != "" {
+		fmt.Fprintf(out, "Service type: %s\n", r.ServiceType)
+	}
+	fmt.Fprintf(out, "Service version: %d\n", r.ServiceVersion)
+
+	var failed int
+	for _, s := range r.Sections {
+		text.Break(out)
+		fmt.Fprintln(out, text.Bold(fmt.Sprintf("%s (%d)", s.Name, len(s.rows))))
+		if s.Error != "" {
+			failed++
+			text.Error(out, "failed to fetch %s: %s", strings.ToLower(s.Name), s.Error)
+		}
+		if len(s.rows) == 0 {
+			if s.Error == "" {
+				fmt.Fprintln(out, "None")
+			}
+			continue
+		}
+		tw := text.NewTable(out)
+		tw.AddHeader(toAny(s.header)...)
+		for _, row := range s.rows {
+			tw.AddLine(row...)
+		}
+		tw.Print()
+	}
+
+	if failed > 0 {
+		text.Break(out)
+		text.Warning(out, "%d of %d sections could not be fetched", failed, len(r.Sections))
+	}
+}
+
+func toAny(xs []string) []any {
+	out := make([]any, len(xs))
+	for i, x := range xs {
+		out[i] = x
+	}
+	return out
+}
+
+// resourceFetchers returns the fetchers for every section of the report, in
+// display order.
+func resourceFetchers(client api.Interface, serviceID string, version int, serviceType string) []resourceFetcher {
+	fetchers := []resourceFetcher{
+		{"Domains", func() (any, []string, [][]any, error) {
+			o, err := client.ListDomains(&fastly.ListDomainsInput{ServiceID: serviceID, ServiceVersion: version})
+			var rows [][]any
+			for _, d := range o {
+				rows = append(rows, []any{fastly.ToValue(d.Name), fastly.ToValue(d.Comment)})
+			}
+			return o, []string{"NAME", "COMMENT"}, rows, err
+		}},
+		{"Backends", func() (any, []string, [][]any, error) {
+			o, err := client.ListBackends(&fastly.ListBackendsInput{ServiceID: serviceID, ServiceVersion: version})
+			var rows [][]any
+			for _, b := range o {
+				rows = append(rows, []any{fastly.ToValue(b.Name), fastly.ToValue(b.Address), fastly.ToValue(b.Port), fastly.ToValue(b.UseSSL)})
+			}
+			return o, []string{"NAME", "ADDRESS", "PORT", "SSL"}, rows, err
+		}},
+		{"Health checks", func() (any, []string, [][]any, error) {
+			o, err := client.ListHealthChecks(&fastly.ListHealthChecksInput{ServiceID: serviceID, ServiceVersion: version})
+			var rows [][]any
+			for _, h := range o {
+				rows = append(rows, []any{fastly.ToValue(h.Name), fastly.ToValue(h.Method), fastly.ToValue(h.Host), fastly.ToValue(h.Path)})
+			}
+			return o, []string{"NAME", "METHOD", "HOST", "PATH"}, rows, err
+		}},
+		{"Logging endpoints", func() (any, []string, [][]any, error) {
+			o, err := listLoggingEndpoints(client, serviceID, version)
+			var rows [][]any
+			for _, l := range o {
+				rows = append(rows, []any{l.Type, l.Name})
+			}
+			return o, []string{"TYPE", "NAME"}, rows, err
+		}},
+		{"Dictionaries", func() (any, []string, [][]any, error) {
+			o, err := client.ListDictionaries(&fastly.ListDictionariesInput{ServiceID: serviceID, ServiceVersion: version})
+			var rows [][]any
+			for _, d := range o {
+				rows = append(rows, []any{fastly.ToValue(d.Name), fastly.ToValue(d.DictionaryID), fastly.ToValue(d.WriteOnly)})
+			}
+			return o, []string{"NAME", "ID", "WRITE ONLY"}, rows, err
+		}},
+		{"ACLs", func() (any, []string, [][]any, error) {
+			o, err := client.ListACLs(&fastly.ListACLsInput{ServiceID: serviceID, ServiceVersion: version})
+			var rows [][]any
+			for _, a := range o {
+				rows = append(rows, []any{fastly.ToValue(a.Name), fastly.ToValue(a.ACLID)})
+			}
+			return o, []string{"NAME", "ID"}, rows, err
+		}},
+	}
+
+	if serviceType != "wasm" {
+		fetchers = append(fetchers,
+			resourceFetcher{"VCL snippets", func() (any, []string, [][]any, error) {
+				o, err := client.ListSnippets(&fastly.ListSnippetsInput{ServiceID: serviceID, ServiceVersion: version})
+				var rows [][]any
+				for _, s := range o {
+					rows = append(rows, []any{fastly.ToValue(s.Name), fastly.ToValue(s.Type), fastly.ToValue(s.Priority), fastly.ToValue(s.Dynamic) == 1})
+				}
+				return o, []string{"NAME", "TYPE", "PRIORITY", "DYNAMIC"}, rows, err
+			}},
+			resourceFetcher{"Custom VCL", func() (any, []string, [][]any, error) {
+				o, err := client.ListVCLs(&fastly.ListVCLsInput{ServiceID: serviceID, ServiceVersion: version})
+				var rows [][]any
+				for _, v := range o {
+					rows = append(rows, []any{fastly.ToValue(v.Name), fastly.ToValue(v.Main)})
+				}
+				return o, []string{"NAME", "MAIN"}, rows, err
+			}},
+		)
+	}
+	if serviceType != "vcl" {
+		fetchers = append(fetchers, resourceFetcher{"Package", func() (any, []string, [][]any, error) {
+			o, err := client.GetPackage(&fastly.GetPackageInput{ServiceID: serviceID, ServiceVersion: version})
+			if err != nil || o == nil || o.Metadata == nil {
+				return o, nil, nil, err
+			}
+			rows := [][]any{{fastly.ToValue(o.PackageID), fastly.ToValue(o.Metadata.Name), fastly.ToValue(o.Metadata.Language), fastly.ToValue(o.Metadata.Size), fastly.ToValue(o.Metadata.HashSum)}}
+			return o, []string{"ID", "NAME", "LANGUAGE", "SIZE", "HASHSUM"}, rows, nil
+		}})
+	}
+
+	return append(fetchers,
+		resourceFetcher{"Gzip", func() (any, []string, [][]any, error) {
+			o, err := client.ListGzips(&fastly.ListGzipsInput{ServiceID: serviceID, ServiceVersion: version})
+			var rows [][]any
+			for _, g := range o {
+				rows = append(rows, []any{fastly.ToValue(g.Name), fastly.ToValue(g.ContentTypes), fastly.ToValue(g.Extensions)})
+			}
+			return o, []string{"NAME", "CONTENT TYPES", "EXTENSIONS"}, rows, err
+		}},
+		resourceFetcher{"Headers", func() (any, []string, [][]any, error) {
+			o, err := client.ListHeaders(&fastly.ListHeadersInput{ServiceID: serviceID, ServiceVersion: version})
+			var rows [][]any
+			for _, h := range o {
+				rows = append(rows, []any{fastly.ToValue(h.Name), fastly.ToValue(h.Action), fastly.ToValue(h.Type), fastly.ToValue(h.Destination)})
+			}
+			return o, []string{"NAME", "ACTION", "TYPE", "DESTINATION"}, rows, err
+		}},
+		resourceFetcher{"Cache settings", func() (any, []string, [][]any, error) {
+			o, err := client.ListCacheSettings(&fastly.ListCacheSettingsInput{ServiceID: serviceID, ServiceVersion: version})
+			var rows [][]any
+			for _, s := range o {
+				rows = append(rows, []any{fastly.ToValue(s.Name), fastly.ToValue(s.Action), fastly.ToValue(s.TTL), fastly.ToValue(s.StaleTTL)})
+			}
+			return o, []string{"NAME", "ACTION", "TTL", "STALE TTL"}, rows, err
+		}},
+	)
+}
+
+// loggingEndpoint is a logging endpoint of any provider.
+type loggingEndpoint struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// listLoggingEndpoints lists the endpoints of every logging provider. A failure
+// for one provider doesn't prevent the others from being listed, but the
+// errors are returned so the section is reported as incomplete.
+func listLoggingEndpoints(client api.Interface, serviceID string, version int) ([]loggingEndpoint, error) {
+	var (
+		endpoints []loggingEndpoint
+		errs      []error
+	)
+	add := func(kind string, names []string) {
+		for _, n := range names {
+			endpoints = append(endpoints, loggingEndpoint{Type: kind, Name: n})
+		}
+	}
+
+	if o, err := client.ListBlobStorages(&fastly.ListBlobStoragesInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("azureblob: %w", err))
+	} else {
+		add("azureblob", endpointNames(o, func(e *fastly.BlobStorage) *string { return e.Name }))
+	}
+	if o, err := client.ListBigQueries(&fastly.ListBigQueriesInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("bigquery: %w", err))
+	} else {
+		add("bigquery", endpointNames(o, func(e *fastly.BigQuery) *string { return e.Name }))
+	}
+	if o, err := client.ListCloudfiles(&fastly.ListCloudfilesInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("cloudfiles: %w", err))
+	} else {
+		add("cloudfiles", endpointNames(o, func(e *fastly.Cloudfiles) *string { return e.Name }))
+	}
+	if o, err := client.ListDatadog(&fastly.ListDatadogInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("datadog: %w", err))
+	} else {
+		add("datadog", endpointNames(o, func(e *fastly.Datadog) *string { return e.Name }))
+	}
+	if o, err := client.ListDigitalOceans(&fastly.ListDigitalOceansInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("digitalocean: %w", err))
+	} else {
+		add("digitalocean", endpointNames(o, func(e *fastly.DigitalOcean) *string { return e.Name }))
+	}
+	if o, err := client.ListElasticsearch(&fastly.ListElasticsearchInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("elasticsearch: %w", err))
+	} else {
+		add("elasticsearch", endpointNames(o, func(e *fastly.Elasticsearch) *string { return e.Name }))
+	}
+	if o, err := client.ListFTPs(&fastly.ListFTPsInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("ftp: %w", err))
+	} else {
+		add("ftp", endpointNames(o, func(e *fastly.FTP) *string { return e.Name }))
+	}
+	if o, err := client.ListGCSs(&fastly.ListGCSsInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("gcs: %w", err))
+	} else {
+		add("gcs", endpointNames(o, func(e *fastly.GCS) *string { return e.Name }))
+	}
+	if o, err := client.ListPubsubs(&fastly.ListPubsubsInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("googlepubsub: %w", err))
+	} else {
+		add("googlepubsub", endpointNames(o, func(e *fastly.Pubsub) *string { return e.Name }))
+	}
+	if o, err := client.ListHerokus(&fastly.ListHerokusInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("heroku: %w", err))
+	} else {
+		add("heroku", endpointNames(o, func(e *fastly.Heroku) *string { return e.Name }))
+	}
+	if o, err := client.ListHoneycombs(&fastly.ListHoneycombsInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("honeycomb: %w", err))
+	} else {
+		add("honeycomb", endpointNames(o, func(e *fastly.Honeycomb) *string { return e.Name }))
+	}
+	if o, err := client.ListHTTPS(&fastly.ListHTTPSInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("https: %w", err))
+	} else {
+		add("https", endpointNames(o, func(e *fastly.HTTPS) *string { return e.Name }))
+	}
+	if o, err := client.ListKafkas(&fastly.ListKafkasInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("kafka: %w", err))
+	} else {
+		add("kafka", endpointNames(o, func(e *fastly.Kafka) *string { return e.Name }))
+	}
+	if o, err := client.ListKinesis(&fastly.ListKinesisInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("kinesis: %w", err))
+	} else {
+		add("kinesis", endpointNames(o, func(e *fastly.Kinesis) *string { return e.Name }))
+	}
+	if o, err := client.ListLogentries(&fastly.ListLogentriesInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("logentries: %w", err))
+	} else {
+		add("logentries", endpointNames(o, func(e *fastly.Logentries) *string { return e.Name }))
+	}
+	if o, err := client.ListLoggly(&fastly.ListLogglyInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("loggly: %w", err))
+	} else {
+		add("loggly", endpointNames(o, func(e *fastly.Loggly) *string { return e.Name }))
+	}
+	if o, err := client.ListLogshuttles(&fastly.ListLogshuttlesInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("logshuttle: %w", err))
+	} else {
+		add("logshuttle", endpointNames(o, func(e *fastly.Logshuttle) *string { return e.Name }))
+	}
+	if o, err := client.ListNewRelic(&fastly.ListNewRelicInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("newrelic: %w", err))
+	} else {
+		add("newrelic", endpointNames(o, func(e *fastly.NewRelic) *string { return e.Name }))
+	}
+	if o, err := client.ListNewRelicOTLP(&fastly.ListNewRelicOTLPInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("newrelicotlp: %w", err))
+	} else {
+		add("newrelicotlp", endpointNames(o, func(e *fastly.NewRelicOTLP) *string { return e.Name }))
+	}
+	if o, err := client.ListOpenstack(&fastly.ListOpenstackInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("openstack: %w", err))
+	} else {
+		add("openstack", endpointNames(o, func(e *fastly.Openstack) *string { return e.Name }))
+	}
+	if o, err := client.ListPapertrails(&fastly.ListPapertrailsInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("papertrail: %w", err))
+	} else {
+		add("papertrail", endpointNames(o, func(e *fastly.Papertrail) *string { return e.Name }))
+	}
+	if o, err := client.ListS3s(&fastly.ListS3sInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("s3: %w", err))
+	} else {
+		add("s3", endpointNames(o, func(e *fastly.S3) *string { return e.Name }))
+	}
+	if o, err := client.ListScalyrs(&fastly.ListScalyrsInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("scalyr: %w", err))
+	} else {
+		add("scalyr", endpointNames(o, func(e *fastly.Scalyr) *string { return e.Name }))
+	}
+	if o, err := client.ListSFTPs(&fastly.ListSFTPsInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("sftp: %w", err))
+	} else {
+		add("sftp", endpointNames(o, func(e *fastly.SFTP) *string { return e.Name }))
+	}
+	if o, err := client.ListSplunks(&fastly.ListSplunksInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("splunk: %w", err))
+	} else {
+		add("splunk", endpointNames(o, func(e *fastly.Splunk) *string { return e.Name }))
+	}
+	if o, err := client.ListSumologics(&fastly.ListSumologicsInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("sumologic: %w", err))
+	} else {
+		add("sumologic", endpointNames(o, func(e *fastly.Sumologic) *string { return e.Name }))
+	}
+	if o, err := client.ListSyslogs(&fastly.ListSyslogsInput{ServiceID: serviceID, ServiceVersion: version}); err != nil {
+		errs = append(errs, fmt.Errorf("syslog: %w", err))
+	} else {
+		add("syslog", endpointNames(o, func(e *fastly.Syslog) *string { return e.Name }))
+	}
+
+	return endpoints, errors.Join(errs...)
+}
+
+func endpointNames[T any](endpoints []*T, name func(*T) *string) []string {
+	names := make([]string, 0, len(endpoints))
+	for _, e := range endpoints {
+		names = append(names, fastly.ToValue(name(e)))
+	}
+	return names
+}
@@ -1,11 +1,15 @@
 package service
 
 import (
+	"fmt"
 	"io"
+	"regexp"
+	"strconv"
 
 	"github.com/fastly/go-fastly/v9/fastly"
 
 	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/commands/service/tag"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/text"
@@ -17,6 +21,9 @@ type SearchCommand struct {
 	argparser.JSONOutput
 
 	Input fastly.SearchServiceInput
+
+	regex bool
+	tags  []string
 }
 
 // NewSearchCommand returns a usable command registered under the parent.
@@ -33,6 +40,8 @@ func NewSearchCommand(parent argparser.Registerer, g *global.Data) *SearchComman
 
 	// Optional.
 	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.CmdClause.Flag("regex", "Treat --name as a regular expression (or substring) and list all matching services").BoolVar(&c.regex)
+	c.CmdClause.Flag("tag", tag.FilterFlagDesc).StringsVar(&c.tags)
 
 	return &c
 }
@@ -43,6 +52,10 @@ func (c *SearchCommand) Exec(_ io.Reader, out io.Writer) error {
 		return fsterr.ErrInvalidVerboseJSONCombo
 	}
 
+	if c.regex || len(c.tags) > 0 {
+		return c.search(out)
+	}
+
 	service, err := c.Globals.APIClient.SearchService(&c.Input)
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
@@ -58,3 +71,70 @@ func (c *SearchCommand) Exec(_ io.Reader, out io.Writer) error {
 	text.PrintService(out, "", service)
 	return nil
 }
+
+// search lists all services whose name matches the --name pattern (or is the
+// --name without --regex) and whose tags match the --tag filter.
+func (c *SearchCommand) search(out io.Writer) error {
+	pattern := "(?i)" + c.Input.Name
+	if !c.regex {
+		pattern = "^" + regexp.QuoteMeta(c.Input.Name) + "$"
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid --name pattern: %w", err),
+			Remediation: "Provide a valid regular expression (see https://pkg.go.dev/regexp/syntax) or a plain substring.",
+		}
+	}
+
+	filter, err := tag.ParseFilter(c.tags)
+	if err != nil {
+		return err
+	}
+
+	services, err := argparser.ListServices(c.Globals.APIClient)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service Name": c.Input.Name,
+		})
+		return err
+	}
+
+	matches := []*fastly.Service{}
+	for _, s := range services {
+		if re.MatchString(fastly.ToValue(s.Name)) && filter.Match(fastly.ToValue(s.Comment)) {
+			matches = append(matches, s)
+		}
+	}
+
+	if ok, err := c.WriteJSON(out, matches); ok {
+		return err
+	}
+
+	if len(matches) == 0 {
+		text.Info(out, "No services matched '%s'", c.Input.Name)
+		return nil
+	}
+
+	if !c.Globals.Verbose() {
+		tw := text.NewTable(out)
+		tw.AddHeader("NAME", "ID", "TYPE", "ACTIVE VERSION")
+		for _, s := range matches {
+			tw.AddLine(
+				fastly.ToValue(s.Name),
+				fastly.ToValue(s.ServiceID),
+				fastly.ToValue(s.Type),
+				strconv.Itoa(fastly.ToValue(s.ActiveVersion)),
+			)
+		}
+		tw.Print()
+		return nil
+	}
+
+	for i, s := range matches {
+		fmt.Fprintf(out, "Service %d/%d\n", i+1, len(matches))
+		text.PrintService(out, "\t", s)
+		fmt.Fprintln(out)
+	}
+	return nil
+}
@@ -2,11 +2,15 @@ package service_test
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
@@ -14,6 +18,9 @@ import (
 	"github.com/fastly/go-fastly/v9/fastly"
 
 	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/service"
+	"github.com/fastly/cli/pkg/config"
+	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/manifest"
 	"github.com/fastly/cli/pkg/mock"
@@ -75,6 +82,52 @@ func TestServiceCreate(t *testing.T) {
 	}
 }
 
+func TestServiceCreateQuiet(t *testing.T) {
+	args := testutil.Args
+	scenarios := []struct {
+		args       []string
+		env        config.Environment
+		wantError  string
+		wantOutput string
+	}{
+		{
+			args:       args("service create --name Foo --quiet"),
+			wantOutput: "12345\n",
+		},
+		{
+			args:       args("service create --name Foo"),
+			env:        config.Environment{Quiet: "true"},
+			wantOutput: "12345\n",
+		},
+		{
+			args:      args("service create --name Foo --verbose"),
+			env:       config.Environment{Quiet: "true"},
+			wantError: "--verbose flag provided while FASTLY_QUIET is set",
+		},
+		{
+			args:      args("service create --name Foo --verbose --quiet"),
+			wantError: "--verbose and --quiet flag provided",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(testcase.args, &stdout)
+				opts.APIClientFactory = mock.APIClient(mock.API{CreateServiceFn: createServiceOK})
+				opts.Env = testcase.env
+				return opts, nil
+			}
+			err := app.Run(testcase.args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if testcase.wantError == "" {
+				testutil.AssertString(t, testcase.wantOutput, stdout.String())
+			}
+		})
+	}
+}
+
 func TestServiceList(t *testing.T) {
 	args := testutil.Args
 	scenarios := []struct {
@@ -218,6 +271,286 @@ func TestServiceList(t *testing.T) {
 	}
 }
 
+func TestServiceListFilters(t *testing.T) {
+	getServices := func(_ *fastly.GetServicesInput) *fastly.ListPaginator[fastly.Service] {
+		return fastly.NewPaginator[fastly.Service](mock.HTTPClient{
+			Errors: []error{nil},
+			Responses: []*http.Response{
+				{
+					Body: io.NopCloser(strings.NewReader(`[
+            {"name": "www-prod", "id": "1", "type": "vcl", "version": 7, "customer_id": "acme", "created_at": "2020-01-01T00:00:00Z", "updated_at": "2021-03-01T00:00:00Z"},
+            {"name": "api-prod", "id": "2", "type": "wasm", "version": 12, "customer_id": "acme", "created_at": "2020-02-01T00:00:00Z", "updated_at": "2021-01-01T00:00:00Z"},
+            {"name": "API-staging", "id": "3", "type": "wasm", "version": 3, "customer_id": "acme", "created_at": "2020-03-01T00:00:00Z", "updated_at": "2021-02-01T00:00:00Z"},
+            {"name": "api-prod", "id": "4", "type": "wasm", "version": 1, "customer_id": "globex", "created_at": "2020-04-01T00:00:00Z"}
+          ]`)),
+				},
+			},
+		}, fastly.ListOpts{}, "/example")
+	}
+
+	args := testutil.Args
+	scenarios := []struct {
+		args            []string
+		wantError       string
+		wantRemediation string
+		wantOutput      string
+	}{
+		{
+			args: args("service list --type wasm --name-filter ^api --customer-id acme"),
+			wantOutput: strings.TrimSpace(`
+NAME         ID  TYPE  ACTIVE VERSION  LAST EDITED (UTC)
+api-prod     2   wasm  12              2021-01-01 00:00
+API-staging  3   wasm  3               2021-02-01 00:00
+
+INFO: 2 of 4 services matched`) + "\n",
+		},
+		{
+			args: args("service list --columns active_version,id,customer_id --sort active_version --direction descend"),
+			wantOutput: strings.TrimSpace(`
+ACTIVE VERSION  ID  CUSTOMER ID
+12              2   acme
+7               1   acme
+3               3   acme
+1               4   globex`) + "\n",
+		},
+		{
+			args: args("service list --columns name,created --sort updated --name-filter PROD"),
+			wantOutput: strings.TrimSpace(`
+NAME      CREATED (UTC)
+api-prod  2020-04-01 00:00
+api-prod  2020-02-01 00:00
+www-prod  2020-01-01 00:00
+
+INFO: 3 of 4 services matched`) + "\n",
+		},
+		{
+			args: args("service list --type vcl --name-filter api"),
+			wantOutput: strings.TrimSpace(`
+NAME  ID  TYPE  ACTIVE VERSION  LAST EDITED (UTC)
+
+INFO: 0 of 4 services matched`) + "\n",
+		},
+		{
+			args:            args("service list --name-filter api-(prod"),
+			wantError:       "invalid --name-filter pattern: error parsing regexp: missing closing ): `(?i)api-(prod`",
+			wantRemediation: "Provide a valid regular expression",
+		},
+		{
+			args:            args("service list --columns name,version"),
+			wantError:       "error parsing arguments: unknown --columns value 'version'",
+			wantRemediation: "name, id, type, active_version",
+		},
+		{
+			args:      args("service list --type compute"),
+			wantError: "enum value must be one of vcl,wasm, got 'compute'",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(testcase.args, &stdout)
+				opts.APIClientFactory = mock.APIClient(mock.API{GetServicesFn: getServices})
+				return opts, nil
+			}
+			err := app.Run(testcase.args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if testcase.wantRemediation != "" {
+				var re fsterr.RemediationError
+				if !errors.As(err, &re) {
+					t.Fatalf("want a RemediationError, have %#v", err)
+				}
+				testutil.AssertStringContains(t, re.Remediation, testcase.wantRemediation)
+			}
+			if testcase.wantError != "" {
+				return
+			}
+			testutil.AssertString(t, testcase.wantOutput, stdout.String())
+		})
+	}
+}
+
+func TestServiceListTags(t *testing.T) {
+	getServices := func(_ *fastly.GetServicesInput) *fastly.ListPaginator[fastly.Service] {
+		return fastly.NewPaginator[fastly.Service](mock.HTTPClient{
+			Errors: []error{nil},
+			Responses: []*http.Response{
+				{
+					Body: io.NopCloser(strings.NewReader(`[
+            {"name": "www", "id": "1", "type": "vcl", "version": 7, "comment": "Website\nfastly-tags: env=prod team=edge"},
+            {"name": "www-stage", "id": "2", "type": "vcl", "version": 3, "comment": "fastly-tags: env=stage team=edge"},
+            {"name": "api", "id": "3", "type": "wasm", "version": 12, "comment": "API"}
+          ]`)),
+				},
+			},
+		}, fastly.ListOpts{}, "/example")
+	}
+
+	args := testutil.Args
+	scenarios := []struct {
+		args       []string
+		wantError  string
+		wantOutput string
+	}{
+		{
+			args: args("service list --columns name,comment,tags"),
+			wantOutput: "NAME       COMMENT  TAGS\n" +
+				"www        Website  env=prod team=edge\n" +
+				"www-stage           env=stage team=edge\n" +
+				"api        API      \n",
+		},
+		{
+			args: args("service list --tag team=edge --tag env!=prod --columns name,id"),
+			wantOutput: strings.TrimSpace(`
+NAME       ID
+www-stage  2
+
+INFO: 1 of 3 services matched`) + "\n",
+		},
+		{
+			args: args("service list --tag !team"),
+			wantOutput: strings.TrimSpace(`
+NAME  ID  TYPE  ACTIVE VERSION  LAST EDITED (UTC)
+api   3   wasm  12              n/a
+
+INFO: 1 of 3 services matched`) + "\n",
+		},
+		{
+			args:       args("service search --name www --tag env=prod"),
+			wantOutput: "NAME  ID  TYPE  ACTIVE VERSION\nwww   1   vcl   7\n",
+		},
+		{
+			args:       args("service search --name www --tag env=stage"),
+			wantOutput: "INFO: No services matched 'www'\n",
+		},
+		{
+			args:      args("service list --tag team==edge"),
+			wantError: "invalid --tag filter 'team==edge'",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(testcase.args, &stdout)
+				opts.APIClientFactory = mock.APIClient(mock.API{GetServicesFn: getServices})
+				return opts, nil
+			}
+			err := app.Run(testcase.args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if testcase.wantError != "" {
+				return
+			}
+			testutil.AssertString(t, testcase.wantOutput, stdout.String())
+		})
+	}
+}
+
+func TestServiceListFiltersJSON(t *testing.T) {
+	args := testutil.Args("service list --type wasm --customer-id acme --sort name --json")
+	var stdout bytes.Buffer
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		opts := testutil.MockGlobalData(args, &stdout)
+		opts.APIClientFactory = mock.APIClient(mock.API{
+			GetServicesFn: func(_ *fastly.GetServicesInput) *fastly.ListPaginator[fastly.Service] {
+				return fastly.NewPaginator[fastly.Service](mock.HTTPClient{
+					Errors: []error{nil},
+					Responses: []*http.Response{
+						{
+							Body: io.NopCloser(strings.NewReader(`[
+                {"name": "b", "id": "1", "type": "wasm", "customer_id": "acme"},
+                {"name": "c", "id": "2", "type": "vcl", "customer_id": "acme"},
+                {"name": "a", "id": "3", "type": "wasm", "customer_id": "acme"},
+                {"name": "d", "id": "4", "type": "wasm", "customer_id": "globex"}
+              ]`)),
+						},
+					},
+				}, fastly.ListOpts{}, "/example")
+			},
+		})
+		return opts, nil
+	}
+	err := app.Run(args, nil)
+	testutil.AssertNoError(t, err)
+
+	var services []*fastly.Service
+	if err := json.Unmarshal(stdout.Bytes(), &services); err != nil {
+		t.Fatalf("invalid JSON output: %s\n%s", err, stdout.String())
+	}
+	var ids []string
+	for _, s := range services {
+		ids = append(ids, fastly.ToValue(s.ServiceID))
+	}
+	testutil.AssertEqual(t, []string{"3", "1"}, ids)
+	testutil.AssertStringDoesntContain(t, stdout.String(), "matched")
+}
+
+// TestServiceListMaxTime validates the services fetched before the --max-time
+// deadline are listed, and the CLI exits with the deadline's status.
+func TestServiceListMaxTime(t *testing.T) {
+	for _, jsonOutput := range []bool{false, true} {
+		name := "table"
+		if jsonOutput {
+			name = "json"
+		}
+		t.Run(name, func(t *testing.T) {
+			args := testutil.Args("service list --max-time 50ms")
+			if jsonOutput {
+				args = append(args, "--json")
+			}
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIClientFactory = mock.APIClient(mock.API{
+					GetServicesFn: func(_ *fastly.GetServicesInput) *fastly.ListPaginator[fastly.Service] {
+						return fastly.NewPaginator[fastly.Service](&slowPagesClient{globals: opts}, fastly.ListOpts{}, "/service")
+					},
+				})
+				return opts, nil
+			}
+			err := app.Run(args, nil)
+			t.Log(stdout.String())
+
+			var mte fsterr.MaxTimeError
+			if !errors.As(err, &mte) || !mte.Partial {
+				t.Fatalf("want a partial fsterr.MaxTimeError, have %#v", err)
+			}
+			testutil.AssertErrorContains(t, err, "timed out after 50ms, results may be partial")
+			testutil.AssertEqual(t, fsterr.MaxTimeExitCode, fsterr.ExitCode(err, args, io.Discard))
+
+			if jsonOutput {
+				var services []*fastly.Service
+				if err := json.Unmarshal(stdout.Bytes(), &services); err != nil {
+					t.Fatalf("invalid JSON output: %s\n%s", err, stdout.String())
+				}
+				testutil.AssertEqual(t, 1, len(services))
+				return
+			}
+			testutil.AssertStringContains(t, stdout.String(), "first")
+		})
+	}
+}
+
+// slowPagesClient serves the first of two pages of services, and holds the
+// request for the second until the command's context is done.
+type slowPagesClient struct {
+	globals *global.Data
+}
+
+// Get implements fastly.PaginationClient.
+func (c *slowPagesClient) Get(_ string, ro *fastly.RequestOptions) (*http.Response, error) {
+	if ro.Params["page"] != "1" {
+		<-c.globals.Context.Done()
+		return nil, context.Cause(c.globals.Context)
+	}
+	return &http.Response{
+		Header: http.Header{"Link": []string{`</service?page=2>; rel="last"`}},
+		Body:   io.NopCloser(strings.NewReader(`[{"name": "first", "id": "123", "type": "wasm"}]`)),
+	}, nil
+}
+
 func TestServiceDescribe(t *testing.T) {
 	args := testutil.Args
 	scenarios := []struct {
@@ -261,6 +594,27 @@ func TestServiceDescribe(t *testing.T) {
 			api:       mock.API{GetServiceDetailsFn: describeServiceError},
 			wantError: errTest.Error(),
 		},
+		{
+			args:       args("service describe --service-id 123 --field active_version.number"),
+			api:        mock.API{GetServiceDetailsFn: describeServiceOK},
+			wantOutput: "2\n",
+		},
+		{
+			args:       args("service describe --service-id 123 --field versions[0].comment"),
+			api:        mock.API{GetServiceDetailsFn: describeServiceOK},
+			wantOutput: "a\n",
+		},
+
+		{
+			args:       args("service describe --service-id 123 --format {{.Name}}/{{.Type}}"),
+			api:        mock.API{GetServiceDetailsFn: describeServiceOK},
+			wantOutput: "Foo/wasm\n",
+		},
+		{
+			args:      args("service describe --service-id 123 --field versions[5]"),
+			api:       mock.API{GetServiceDetailsFn: describeServiceOK},
+			wantError: "no element '5' in 'versions' (an array of length 2)",
+		},
 	}
 	for testcaseIdx := range scenarios {
 		testcase := &scenarios[testcaseIdx]
@@ -278,6 +632,474 @@ func TestServiceDescribe(t *testing.T) {
 	}
 }
 
+func TestServiceResources(t *testing.T) {
+	api := stubAPI(mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		GetServiceDetailsFn: func(i *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+			return &fastly.ServiceDetail{
+				ServiceID: fastly.ToPointer(i.ServiceID),
+				Name:      fastly.ToPointer("Foo"),
+				Type:      fastly.ToPointer("vcl"),
+			}, nil
+		},
+		ListDomainsFn: func(i *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+			return []*fastly.Domain{{Name: fastly.ToPointer("www.example.com"), Comment: fastly.ToPointer("main")}}, nil
+		},
+		ListBackendsFn: func(i *fastly.ListBackendsInput) ([]*fastly.Backend, error) {
+			return nil, testutil.Err
+		},
+		ListS3sFn: func(i *fastly.ListS3sInput) ([]*fastly.S3, error) {
+			return []*fastly.S3{{Name: fastly.ToPointer("archive")}}, nil
+		},
+		ListSplunksFn: func(i *fastly.ListSplunksInput) ([]*fastly.Splunk, error) {
+			return nil, testutil.Err
+		},
+		ListGzipsFn: func(i *fastly.ListGzipsInput) ([]*fastly.Gzip, error) {
+			return []*fastly.Gzip{{Name: fastly.ToPointer("default"), ContentTypes: fastly.ToPointer("text/html"), Extensions: fastly.ToPointer("html")}}, nil
+		},
+		GetPackageFn: func(i *fastly.GetPackageInput) (*fastly.Package, error) {
+			t.Fatal("unexpected package lookup for a VCL service")
+			return nil, nil
+		},
+	})
+
+	t.Run("text", func(t *testing.T) {
+		var stdout bytes.Buffer
+		args := testutil.Args("service resources --service-id 123 --version 1")
+		app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+			opts := testutil.MockGlobalData(args, &stdout)
+			opts.APIClientFactory = mock.APIClient(api)
+			return opts, nil
+		}
+		err := app.Run(args, nil)
+		testutil.AssertNoError(t, err)
+		out := stdout.String()
+		testutil.AssertStringContains(t, out, "Service name: Foo")
+		testutil.AssertStringContains(t, out, "Domains (1)")
+		testutil.AssertStringContains(t, out, "www.example.com  main")
+		testutil.AssertStringContains(t, out, "Backends (0)")
+		testutil.AssertStringContains(t, out, "failed to fetch backends: test error")
+		testutil.AssertStringContains(t, out, "Logging endpoints (1)")
+		testutil.AssertStringContains(t, out, "s3    archive")
+		testutil.AssertStringContains(t, out, "failed to fetch logging endpoints: splunk: test error")
+		testutil.AssertStringContains(t, out, "Gzip (1)")
+		testutil.AssertStringContains(t, out, "default  text/html      html")
+		testutil.AssertStringContains(t, out, "VCL snippets (0)")
+		testutil.AssertStringDoesntContain(t, out, "Package")
+		testutil.AssertStringContains(t, out, "2 of 11 sections could not be fetched")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var stdout bytes.Buffer
+		args := testutil.Args("service resources --service-id 123 --version 1 --json")
+		app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+			opts := testutil.MockGlobalData(args, &stdout)
+			opts.APIClientFactory = mock.APIClient(api)
+			return opts, nil
+		}
+		err := app.Run(args, nil)
+		testutil.AssertNoError(t, err)
+
+		var report service.ResourceReport
+		if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+		testutil.AssertString(t, "vcl", report.ServiceType)
+		testutil.AssertEqual(t, 11, len(report.Sections))
+		testutil.AssertString(t, "Domains", report.Sections[0].Name)
+		testutil.AssertString(t, "", report.Sections[0].Error)
+		testutil.AssertString(t, "Backends", report.Sections[1].Name)
+		testutil.AssertString(t, testutil.Err.Error(), report.Sections[1].Error)
+		testutil.AssertEqual(t, 1, len(report.Sections[0].Items.([]any)))
+	})
+}
+
+func TestServiceClone(t *testing.T) {
+	newAPI := func(deleted *string, backendErr error) mock.API {
+		return stubAPI(mock.API{
+			ListVersionsFn: testutil.ListVersions,
+			GetServiceDetailsFn: func(i *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+				return &fastly.ServiceDetail{
+					ServiceID: fastly.ToPointer(i.ServiceID),
+					Name:      fastly.ToPointer("Foo"),
+					Type:      fastly.ToPointer("vcl"),
+				}, nil
+			},
+			CreateServiceFn: func(i *fastly.CreateServiceInput) (*fastly.Service, error) {
+				return &fastly.Service{ServiceID: fastly.ToPointer("456"), Name: i.Name}, nil
+			},
+			DeleteServiceFn: func(i *fastly.DeleteServiceInput) error {
+				*deleted = i.ServiceID
+				return nil
+			},
+			ListDomainsFn: func(i *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+				return []*fastly.Domain{{Name: fastly.ToPointer("www.example.com")}, {Name: fastly.ToPointer("api.example.com")}}, nil
+			},
+			CreateDomainFn: func(i *fastly.CreateDomainInput) (*fastly.Domain, error) {
+				if i.ServiceID != "456" || i.ServiceVersion != 1 {
+					return nil, errors.New("domain created on the wrong service")
+				}
+				return &fastly.Domain{Name: i.Name}, nil
+			},
+			ListBackendsFn: func(i *fastly.ListBackendsInput) ([]*fastly.Backend, error) {
+				return []*fastly.Backend{{Name: fastly.ToPointer("origin"), Address: fastly.ToPointer("example.org"), Port: fastly.ToPointer(443), UseSSL: fastly.ToPointer(true)}}, nil
+			},
+			CreateBackendFn: func(i *fastly.CreateBackendInput) (*fastly.Backend, error) {
+				if backendErr != nil {
+					return nil, backendErr
+				}
+				if fastly.ToValue(i.UseSSL) != fastly.Compatibool(true) {
+					return nil, errors.New("use_ssl not copied")
+				}
+				return &fastly.Backend{Name: i.Name}, nil
+			},
+			ListDictionariesFn: func(i *fastly.ListDictionariesInput) ([]*fastly.Dictionary, error) {
+				return []*fastly.Dictionary{{Name: fastly.ToPointer("geo"), DictionaryID: fastly.ToPointer("d1")}}, nil
+			},
+			CreateDictionaryFn: func(i *fastly.CreateDictionaryInput) (*fastly.Dictionary, error) {
+				return &fastly.Dictionary{Name: i.Name, DictionaryID: fastly.ToPointer("d2")}, nil
+			},
+			ListDictionaryItemsFn: func(i *fastly.ListDictionaryItemsInput) ([]*fastly.DictionaryItem, error) {
+				return []*fastly.DictionaryItem{
+					{ItemKey: fastly.ToPointer("uk"), ItemValue: fastly.ToPointer("eu")},
+					{ItemKey: fastly.ToPointer("us"), ItemValue: fastly.ToPointer("na")},
+				}, nil
+			},
+			BatchModifyDictionaryItemsFn: func(i *fastly.BatchModifyDictionaryItemsInput) error {
+				if i.ServiceID != "456" || i.DictionaryID != "d2" || len(i.Items) != 2 {
+					return errors.New("unexpected batch")
+				}
+				return nil
+			},
+			ListS3sFn: func(i *fastly.ListS3sInput) ([]*fastly.S3, error) {
+				return []*fastly.S3{{Name: fastly.ToPointer("archive")}}, nil
+			},
+		})
+	}
+
+	scenarios := []struct {
+		name        string
+		args        string
+		backendErr  error
+		wantError   string
+		wantOutput  []string
+		wantDeleted string
+	}{
+		{
+			name: "clones every category",
+			args: "service clone --service-id 123 --version 1 --domain-suffix=-staging.example.com --copy-content",
+			wantOutput: []string{
+				"www-staging.example.com",
+				"renamed from api.example.com",
+				"example.org:443",
+				"2 item(s) copied",
+				"not cloned, recreate with `fastly logging s3 create`",
+				"Cloned service 123 (version 1) to new service 456 (version 1)",
+			},
+		},
+		{
+			name:      "domains without a new name",
+			args:      "service clone --service-id 123 --version 1",
+			wantError: "no new name for domain(s): api.example.com, www.example.com",
+		},
+		{
+			name:       "skipped categories",
+			args:       "service clone --service-id 123 --version 1 --skip domain,logging --skip dictionary",
+			wantOutput: []string{"origin"},
+		},
+		{
+			name:      "invalid skip category",
+			args:      "service clone --service-id 123 --version 1 --skip frobs",
+			wantError: `invalid --skip category "frobs"`,
+		},
+		{
+			name:        "rolls back on failure",
+			args:        "service clone --service-id 123 --version 1 --skip domain",
+			backendErr:  testutil.Err,
+			wantError:   "error cloning backend resources: origin: test error",
+			wantOutput:  []string{"Deleting partially cloned service 456"},
+			wantDeleted: "456",
+		},
+		{
+			name:       "keeps partial service",
+			args:       "service clone --service-id 123 --version 1 --skip domain --keep-partial",
+			backendErr: testutil.Err,
+			wantError:  "error cloning backend resources",
+			wantOutput: []string{"partially cloned service 456 has been kept"},
+		},
+	}
+
+	for _, testcase := range scenarios {
+		testcase := testcase
+		t.Run(testcase.name, func(t *testing.T) {
+			var (
+				deleted string
+				stdout  bytes.Buffer
+			)
+			args := testutil.Args(testcase.args)
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(args, &stdout)
+				opts.APIClientFactory = mock.APIClient(newAPI(&deleted, testcase.backendErr))
+				return opts, nil
+			}
+			err := app.Run(args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, want := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), want)
+			}
+			testutil.AssertString(t, testcase.wantDeleted, deleted)
+		})
+	}
+}
+
+func TestReadDomainMap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "domains.txt")
+	content := "# production to staging\nwww.example.com  www.staging.example.net\n\napi.example.com api.staging.example.net\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	mapping, err := service.ReadDomainMap(path)
+	testutil.AssertNoError(t, err)
+
+	domains := []*fastly.Domain{{Name: fastly.ToPointer("www.example.com")}, {Name: fastly.ToPointer("img.example.com")}}
+	renames, err := service.RenameDomains(domains, mapping, ".staging.example.org")
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, [][2]string{
+		{"www.example.com", "www.staging.example.net"},
+		{"img.example.com", "img.staging.example.org"},
+	}, renames)
+
+	if err := os.WriteFile(path, []byte("www.example.com\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = service.ReadDomainMap(path)
+	testutil.AssertErrorContains(t, err, "invalid domain map line 1")
+}
+
+func TestServiceStatus(t *testing.T) {
+	args := testutil.Args
+	forbidden := &fastly.HTTPError{StatusCode: http.StatusForbidden}
+	serviceDetails := func(active bool) func(*fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+		return func(i *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+			s := &fastly.ServiceDetail{
+				ServiceID: fastly.ToPointer(i.ServiceID),
+				Name:      fastly.ToPointer("Foo"),
+			}
+			if active {
+				s.ActiveVersion = &fastly.Version{Number: fastly.ToPointer(3), UpdatedAt: &testutil.Date}
+			}
+			return s, nil
+		}
+	}
+	listDomains := func(_ *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+		return []*fastly.Domain{{Name: fastly.ToPointer("www.example.com")}}, nil
+	}
+	listHealthChecks := func(_ *fastly.ListHealthChecksInput) ([]*fastly.HealthCheck, error) {
+		return []*fastly.HealthCheck{{
+			Name:             fastly.ToPointer("origin-health"),
+			Method:           fastly.ToPointer("HEAD"),
+			Host:             fastly.ToPointer("origin.example.com"),
+			Path:             fastly.ToPointer("/health"),
+			ExpectedResponse: fastly.ToPointer(200),
+			CheckInterval:    fastly.ToPointer(5000),
+			Timeout:          fastly.ToPointer(500),
+			Window:           fastly.ToPointer(5),
+			Threshold:        fastly.ToPointer(3),
+		}}, nil
+	}
+	traffic := &fakeRTS{response: `{"timestamp": 1, "data": [
+		{"aggregated": {"requests": 60, "errors": 3}},
+		{"aggregated": {"requests": 40, "errors": 2}}
+	]}`}
+
+	scenarios := []struct {
+		testutil.TestScenario
+		rts          *fakeRTS
+		wantOutputs  []string
+		wantOmission string
+	}{
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "healthy service",
+				API: mock.API{
+					GetServiceDetailsFn: serviceDetails(true),
+					ListDomainsFn:       listDomains,
+					ListHealthChecksFn:  listHealthChecks,
+				},
+				Args: args("service status --service-id 123"),
+			},
+			rts: traffic,
+			wantOutputs: []string{
+				"Service: Foo (123)",
+				"Active version: 3",
+				"Activated (UTC): 2021-06-15 23:00",
+				"CNAME dualstack.global.fastly.net",
+				"1 of 1 domains are pointed at Fastly",
+				"origin-health",
+				"origin.example.com",
+				"5000ms",
+				"Last 2s: 100 requests, 5 errors (error rate 5.00%)",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "sections without entitlement",
+				API: mock.API{
+					GetServiceDetailsFn: serviceDetails(true),
+					ListDomainsFn:       listDomains,
+					ListHealthChecksFn: func(_ *fastly.ListHealthChecksInput) ([]*fastly.HealthCheck, error) {
+						return nil, forbidden
+					},
+				},
+				Args: args("service status --service-id 123"),
+			},
+			rts: &fakeRTS{err: forbidden},
+			wantOutputs: []string{
+				"1 of 1 domains are pointed at Fastly",
+				"healthchecks not available: the account or token lacks access (HTTP 403)",
+				"realtime stats not available: the account or token lacks access (HTTP 403)",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "no active version",
+				API: mock.API{
+					GetServiceDetailsFn: serviceDetails(false),
+				},
+				Args: args("service status --service-id 123"),
+			},
+			rts: &fakeRTS{response: `{"timestamp": 1, "data": []}`},
+			wantOutputs: []string{
+				"Active version: none",
+				"the service has no active version",
+				"no recent traffic",
+			},
+			wantOmission: "Activated",
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "API error",
+				API: mock.API{
+					GetServiceDetailsFn: func(_ *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+						return nil, testutil.Err
+					},
+				},
+				Args:      args("service status --service-id 123"),
+				WantError: testutil.Err.Error(),
+			},
+			rts: traffic,
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(testcase.Args, &stdout)
+				opts.APIClientFactory = mock.APIClient(testcase.API)
+				opts.Resolver = fakeResolver{"www.example.com": "dualstack.global.fastly.net."}
+				opts.RTSClient = testcase.rts
+				return opts, nil
+			}
+			err := app.Run(testcase.Args, nil)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			for _, want := range testcase.wantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), want)
+			}
+			if testcase.wantOmission != "" && strings.Contains(stdout.String(), testcase.wantOmission) {
+				t.Errorf("unexpected %q in output:\n%s", testcase.wantOmission, stdout.String())
+			}
+		})
+	}
+}
+
+func TestServiceStatusJSON(t *testing.T) {
+	args := testutil.Args("service status --service-id 123 --json")
+	api := mock.API{
+		GetServiceDetailsFn: func(i *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+			return &fastly.ServiceDetail{
+				ServiceID:     fastly.ToPointer(i.ServiceID),
+				Name:          fastly.ToPointer("Foo"),
+				ActiveVersion: &fastly.Version{Number: fastly.ToPointer(3)},
+			}, nil
+		},
+		ListDomainsFn: func(_ *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+			return []*fastly.Domain{{Name: fastly.ToPointer("www.example.com")}}, nil
+		},
+		ListHealthChecksFn: func(_ *fastly.ListHealthChecksInput) ([]*fastly.HealthCheck, error) {
+			return nil, &fastly.HTTPError{StatusCode: http.StatusForbidden}
+		},
+	}
+
+	var stdout bytes.Buffer
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		opts := testutil.MockGlobalData(args, &stdout)
+		opts.APIClientFactory = mock.APIClient(api)
+		opts.Resolver = fakeResolver{}
+		opts.RTSClient = &fakeRTS{response: `{"timestamp": 1, "data": [{"aggregated": {"requests": 10, "errors": 1}}]}`}
+		return opts, nil
+	}
+	testutil.AssertNoError(t, app.Run(args, nil))
+
+	var got service.Status
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout.String())
+	}
+	testutil.AssertEqual(t, 3, fastly.ToValue(got.ActiveVersion))
+	testutil.AssertEqual(t, 1, len(got.Domains.Results))
+	testutil.AssertString(t, "fail", got.Domains.Results[0].Status)
+	testutil.AssertString(t, "healthchecks not available: the account or token lacks access (HTTP 403)", got.Healthchecks.Note)
+	testutil.AssertEqual(t, float64(10), got.Traffic.ErrorRatio)
+}
+
+// fakeResolver maps hostnames to CNAME records. Other hosts don't resolve.
+type fakeResolver map[string]string
+
+func (r fakeResolver) LookupCNAME(_ context.Context, host string) (string, error) {
+	if c, ok := r[host]; ok {
+		return c, nil
+	}
+	return "", &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func (r fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+// fakeRTS returns a canned realtime stats response.
+type fakeRTS struct {
+	response string
+	err      error
+}
+
+func (r *fakeRTS) GetRealtimeStatsJSON(_ *fastly.GetRealtimeStatsInput, o any) error {
+	if r.err != nil {
+		return r.err
+	}
+	return json.Unmarshal([]byte(r.response), o)
+}
+
+// stubAPI sets every unset function of the mock API to return zero values.
+func stubAPI(api mock.API) mock.API {
+	v := reflect.ValueOf(&api).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if f.Kind() != reflect.Func || !f.IsNil() {
+			continue
+		}
+		ft := f.Type()
+		f.Set(reflect.MakeFunc(ft, func([]reflect.Value) []reflect.Value {
+			out := make([]reflect.Value, ft.NumOut())
+			for j := range out {
+				out[j] = reflect.Zero(ft.Out(j))
+			}
+			return out
+		}))
+	}
+	return api
+}
+
 func TestServiceSearch(t *testing.T) {
 	args := testutil.Args
 	scenarios := []struct {
@@ -305,6 +1127,21 @@ func TestServiceSearch(t *testing.T) {
 			api:       mock.API{SearchServiceFn: searchServiceOK},
 			wantError: "error parsing arguments: expected argument for flag '--name'",
 		},
+		{
+			args:       args("service search --name ^ba --regex"),
+			api:        mock.API{GetServicesFn: getServicesOK},
+			wantOutput: "NAME  ID   TYPE  ACTIVE VERSION\nBar   456  wasm  1\nBaz   789  vcl   1\n",
+		},
+		{
+			args:       args("service search --name qux --regex"),
+			api:        mock.API{GetServicesFn: getServicesOK},
+			wantOutput: "INFO: No services matched 'qux'\n",
+		},
+		{
+			args:      args("service search --name ( --regex"),
+			api:       mock.API{GetServicesFn: getServicesOK},
+			wantError: "invalid --name pattern",
+		},
 	}
 	for testcaseIdx := range scenarios {
 		testcase := &scenarios[testcaseIdx]
@@ -403,32 +1240,32 @@ func TestServiceDelete(t *testing.T) {
 		expectEmptyServiceID bool
 	}{
 		{
-			args:      args("service delete"),
+			args:      args("service delete --auto-yes"),
 			api:       mock.API{DeleteServiceFn: deleteServiceOK},
 			manifest:  "fastly-no-serviceid.toml",
 			wantError: "error reading service: no service ID found",
 		},
 		{
-			args:                 args("service delete"),
+			args:                 args("service delete --auto-yes"),
 			api:                  mock.API{DeleteServiceFn: deleteServiceOK},
 			manifest:             "fastly-valid.toml",
 			wantOutput:           "Deleted service ID 123",
 			expectEmptyServiceID: true,
 		},
 		{
-			args:       args("service delete --service-id 001"),
+			args:       args("service delete --service-id 001 --auto-yes"),
 			api:        mock.API{DeleteServiceFn: deleteServiceOK},
 			wantOutput: "Deleted service ID 001",
 		},
 		{
-			args:                 args("service delete --service-id 001"),
+			args:                 args("service delete --service-id 001 --auto-yes"),
 			api:                  mock.API{DeleteServiceFn: deleteServiceOK},
 			manifest:             "fastly-valid.toml",
 			wantOutput:           "Deleted service ID 001",
 			expectEmptyServiceID: false,
 		},
 		{
-			args:      args("service delete --service-id 001"),
+			args:      args("service delete --service-id 001 --auto-yes"),
 			api:       mock.API{DeleteServiceFn: deleteServiceError},
 			manifest:  "fastly-valid.toml",
 			wantError: errTest.Error(),
@@ -795,3 +1632,33 @@ func deleteServiceOK(*fastly.DeleteServiceInput) error {
 func deleteServiceError(*fastly.DeleteServiceInput) error {
 	return errTest
 }
+
+func getServicesOK(_ *fastly.GetServicesInput) *fastly.ListPaginator[fastly.Service] {
+	return fastly.NewPaginator[fastly.Service](mock.HTTPClient{
+		Errors: []error{nil},
+		Responses: []*http.Response{
+			{
+				Body: io.NopCloser(strings.NewReader(`[
+          {
+            "name": "Foo",
+            "id": "123",
+            "type": "wasm",
+            "version": 2
+          },
+          {
+            "name": "Bar",
+            "id": "456",
+            "type": "wasm",
+            "version": 1
+          },
+          {
+            "name": "Baz",
+            "id": "789",
+            "type": "vcl",
+            "version": 1
+          }
+        ]`)),
+			},
+		},
+	}, fastly.ListOpts{}, "/example")
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/commands/domain"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	fsttime "github.com/fastly/cli/pkg/time"
+)
+
+// StatusCommand displays an overview of what is live on a service and whether
+// it is healthy.
+type StatusCommand struct {
+	argparser.Base
+	argparser.JSONOutput
+
+	serviceName argparser.OptionalServiceNameID
+}
+
+// NewStatusCommand returns a usable command registered under the parent.
+func NewStatusCommand(parent argparser.Registerer, g *global.Data) *StatusCommand {
+	c := StatusCommand{
+		Base: argparser.Base{
+			Globals: g,
+		},
+	}
+	c.CmdClause = parent.Command("status", "Show the active version, domain DNS, healthchecks and current error rate of a Fastly service")
+
+	// Optional.
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Name:        argparser.FlagServiceIDName,
+		Description: argparser.FlagServiceIDDesc,
+		Dst:         &g.Manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        argparser.FlagServiceName,
+		Description: argparser.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	return &c
+}
+
+// Status is an overview of a service. Each section has a Note explaining why
+// it is empty when its data couldn't be retrieved.
+type Status struct {
+	ServiceID     string             `json:"service_id"`
+	Name          string             `json:"name"`
+	ActiveVersion *int               `json:"active_version"`
+	ActivatedAt   *time.Time         `json:"activated_at"`
+	Domains       DomainsStatus      `json:"domains"`
+	Healthchecks  HealthchecksStatus `json:"healthchecks"`
+	Traffic       TrafficStatus      `json:"traffic"`
+}
+
+// DomainsStatus is the DNS check result of each domain on the active version.
+type DomainsStatus struct {
+	Results []domain.DNSResult `json:"results"`
+	Note    string             `json:"note,omitempty"`
+}
+
+// HealthchecksStatus lists the healthchecks defined on the active version.
+type HealthchecksStatus struct {
+	Healthchecks []*fastly.HealthCheck `json:"healthchecks"`
+	Note         string                `json:"note,omitempty"`
+}
+
+// TrafficStatus summarises the most recent realtime stats.
+type TrafficStatus struct {
+	Seconds    int     `json:"seconds"`
+	Requests   float64 `json:"requests"`
+	Errors     float64 `json:"errors"`
+	ErrorRatio float64 `json:"error_ratio"`
+	Note       string  `json:"note,omitempty"`
+}
+
+// Exec invokes the application logic for the command.
+func (c *StatusCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	serviceID, source, flag, err := argparser.ServiceID(c.serviceName, *c.Globals.Manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		argparser.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	if source == manifest.SourceUndefined && !c.serviceName.WasSet {
+		err := argparser.NoServiceIDError()
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	s, err := c.Globals.APIClient.GetServiceDetails(&fastly.GetServiceInput{ServiceID: serviceID})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+		})
+		return err
+	}
+
+	status := Status{
+		ServiceID: serviceID,
+		Name:      fastly.ToValue(s.Name),
+	}
+	status.Traffic = c.traffic(serviceID)
+
+	if s.ActiveVersion == nil || s.ActiveVersion.Number == nil {
+		note := "the service has no active version"
+		status.Domains.Note = note
+		status.Healthchecks.Note = note
+	} else {
+		status.ActiveVersion = s.ActiveVersion.Number
+		status.ActivatedAt = s.ActiveVersion.UpdatedAt
+		status.Domains = c.domains(serviceID, *s.ActiveVersion.Number)
+		status.Healthchecks = c.healthchecks(serviceID, *s.ActiveVersion.Number)
+	}
+
+	if ok, err := c.WriteJSON(out, status); ok {
+		return err
+	}
+	printStatus(out, status)
+	return nil
+}
+
+func (c *StatusCommand) domains(serviceID string, version int) DomainsStatus {
+	domains, err := c.Globals.APIClient.ListDomains(&fastly.ListDomainsInput{
+		ServiceID:      serviceID,
+		ServiceVersion: version,
+	})
+	if err != nil {
+		return DomainsStatus{Note: c.sectionNote(err, serviceID, "domains")}
+	}
+	names := make([]string, 0, len(domains))
+	for _, d := range domains {
+		names = append(names, fastly.ToValue(d.Name))
+	}
+	return DomainsStatus{Results: domain.NewDNSChecker(c.Globals).CheckAll(context.Background(), names)}
+}
+
+func (c *StatusCommand) healthchecks(serviceID string, version int) HealthchecksStatus {
+	hcs, err := c.Globals.APIClient.ListHealthChecks(&fastly.ListHealthChecksInput{
+		ServiceID:      serviceID,
+		ServiceVersion: version,
+	})
+	if err != nil {
+		return HealthchecksStatus{Note: c.sectionNote(err, serviceID, "healthchecks")}
+	}
+	return HealthchecksStatus{Healthchecks: hcs}
+}
+
+// traffic fetches the most recent realtime stats for the service.
+func (c *StatusCommand) traffic(serviceID string) TrafficStatus {
+	if c.Globals.RTSClient == nil {
+		return TrafficStatus{Note: "realtime stats client unavailable"}
+	}
+	var envelope struct {
+		Data []struct {
+			Aggregated map[string]any `json:"aggregated"`
+		} `json:"data"`
+	}
+	err := c.Globals.RTSClient.GetRealtimeStatsJSON(&fastly.GetRealtimeStatsInput{
+		ServiceID: serviceID,
+	}, &envelope)
+	if err != nil {
+		return TrafficStatus{Note: c.sectionNote(err, serviceID, "realtime stats")}
+	}
+
+	t := TrafficStatus{Seconds: len(envelope.Data)}
+	for _, record := range envelope.Data {
+		if n, ok := record.Aggregated["requests"].(float64); ok {
+			t.Requests += n
+		}
+		if n, ok := record.Aggregated["errors"].(float64); ok {
+			t.Errors += n
+		}
+	}
+	if t.Requests > 0 {
+		t.ErrorRatio = t.Errors / t.Requests * 100
+	}
+	if t.Seconds == 0 {
+		t.Note = "no recent traffic"
+	}
+	return t
+}
+
+// sectionNote records a failure to fetch part of the status and describes it.
+//
+// NOTE: A section the account isn't entitled to (or the token can't access)
+// shouldn't prevent the rest of the status from being displayed.
+func (c *StatusCommand) sectionNote(err error, serviceID, section string) string {
+	c.Globals.ErrLog.AddWithContext(err, map[string]any{
+		"Service ID": serviceID,
+		"Section":    section,
+	})
+	var he *fastly.HTTPError
+	if errors.As(err, &he) && (he.StatusCode == http.StatusForbidden || he.StatusCode == http.StatusUnauthorized) {
+		return fmt.Sprintf("%s not available: the account or token lacks access (HTTP %d)", section, he.StatusCode)
+	}
+	return fmt.Sprintf("failed to fetch %s: %s", section, err)
+}
+
+func printStatus(out io.Writer, s Status) {
+	fmt.Fprintf(out, "Service: %s (%s)\n", s.Name, s.ServiceID)
+	if s.ActiveVersion == nil {
+		fmt.Fprintf(out, "Active version: none\n")
+	} else {
+		fmt.Fprintf(out, "Active version: %d\n", *s.ActiveVersion)
+		if s.ActivatedAt != nil {
+			fmt.Fprintf(out, "%s: %s\n", text.TimeLabel("Activated"), text.FormatTimeLayout(s.ActivatedAt, fsttime.Format))
+		}
+	}
+
+	text.Break(out)
+	text.Output(out, "%s", text.Bold("Domains"))
+	switch {
+	case s.Domains.Note != "":
+		text.Info(out, "%s", s.Domains.Note)
+	case len(s.Domains.Results) == 0:
+		text.Output(out, "No domains")
+	default:
+		domain.PrintDNSResults(out, s.Domains.Results)
+	}
+
+	text.Break(out)
+	text.Output(out, "%s", text.Bold("Healthchecks"))
+	switch {
+	case s.Healthchecks.Note != "":
+		text.Info(out, "%s", s.Healthchecks.Note)
+	case len(s.Healthchecks.Healthchecks) == 0:
+		text.Output(out, "No healthchecks")
+	default:
+		tw := text.NewTable(out)
+		tw.AddHeader("NAME", "METHOD", "HOST", "PATH", "EXPECTED", "INTERVAL", "TIMEOUT", "WINDOW", "THRESHOLD")
+		for _, hc := range s.Healthchecks.Healthchecks {
+			tw.AddLine(
+				fastly.ToValue(hc.Name),
+				fastly.ToValue(hc.Method),
+				fastly.ToValue(hc.Host),
+				fastly.ToValue(hc.Path),
+				fastly.ToValue(hc.ExpectedResponse),
+				milliseconds(hc.CheckInterval),
+				milliseconds(hc.Timeout),
+				fastly.ToValue(hc.Window),
+				fastly.ToValue(hc.Threshold),
+			)
+		}
+		tw.Print()
+	}
+
+	text.Break(out)
+	text.Output(out, "%s", text.Bold("Traffic"))
+	if s.Traffic.Note != "" {
+		text.Info(out, "%s", s.Traffic.Note)
+		return
+	}
+	ratio := fmt.Sprintf("%.2f%%", s.Traffic.ErrorRatio)
+	if s.Traffic.Errors > 0 {
+		ratio = text.ErrorStyle(ratio)
+	}
+	fmt.Fprintf(out, "Last %ds: %.0f requests, %.0f errors (error rate %s)\n", s.Traffic.Seconds, s.Traffic.Requests, s.Traffic.Errors, ratio)
+}
+
+func milliseconds(n *int) string {
+	if n == nil {
+		return "-"
+	}
+	return strconv.Itoa(*n) + "ms"
+}
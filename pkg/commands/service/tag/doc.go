@@ -0,0 +1,3 @@
+// Package tag contains commands to manage service tags, which group services
+// (e.g. by team or environment) so they can be listed and updated together.
+package tag
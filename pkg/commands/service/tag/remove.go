@@ -0,0 +1,53 @@
+package tag
+
+import (
+	"io"
+	"strings"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/global"
+)
+
+// RemoveCommand removes tags from services.
+type RemoveCommand struct {
+	target
+
+	keys []string
+}
+
+// NewRemoveCommand returns a usable command registered under the parent.
+func NewRemoveCommand(parent argparser.Registerer, g *global.Data) *RemoveCommand {
+	var c RemoveCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("remove", "Remove tags from a service, or from every service matching the --tag filter")
+	c.CmdClause.Arg("keys", "Keys of the tags to remove (e.g. team env)").Required().StringsVar(&c.keys)
+	c.registerFlags(g)
+	return &c
+}
+
+// RequiredScope implements the argparser.Scoper interface, as updating a
+// service's tags isn't recognised as a mutating command by its name.
+func (c *RemoveCommand) RequiredScope() fastly.TokenScope {
+	return fastly.GlobalScope
+}
+
+// Exec invokes the application logic for the command.
+func (c *RemoveCommand) Exec(in io.Reader, out io.Writer) error {
+	for _, k := range c.keys {
+		if err := ValidateKey(k); err != nil {
+			return err
+		}
+	}
+
+	services, err := c.services("remove the tags "+strings.Join(c.keys, ", "), in, out)
+	if err != nil {
+		return err
+	}
+	return c.update(services, func(t Tags) {
+		for _, k := range c.keys {
+			delete(t, k)
+		}
+	}, out)
+}
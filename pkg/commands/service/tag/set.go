@@ -0,0 +1,56 @@
+package tag
+
+import (
+	"io"
+	"strings"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/global"
+)
+
+// SetCommand sets tags on services.
+type SetCommand struct {
+	target
+
+	tags []string
+}
+
+// NewSetCommand returns a usable command registered under the parent.
+func NewSetCommand(parent argparser.Registerer, g *global.Data) *SetCommand {
+	var c SetCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("set", "Set tags on a service, or on every service matching the --tag filter")
+	c.CmdClause.Arg("tags", "Tags to set (e.g. team=edge env=prod), replacing the value of existing tags").Required().StringsVar(&c.tags)
+	c.registerFlags(g)
+	return &c
+}
+
+// RequiredScope implements the argparser.Scoper interface, as updating a
+// service's tags isn't recognised as a mutating command by its name.
+func (c *SetCommand) RequiredScope() fastly.TokenScope {
+	return fastly.GlobalScope
+}
+
+// Exec invokes the application logic for the command.
+func (c *SetCommand) Exec(in io.Reader, out io.Writer) error {
+	tags := make(Tags, len(c.tags))
+	for _, t := range c.tags {
+		k, v, err := Parse(t)
+		if err != nil {
+			return err
+		}
+		tags[k] = v
+	}
+
+	services, err := c.services("set the tags "+strings.Join(c.tags, " "), in, out)
+	if err != nil {
+		return err
+	}
+	return c.update(services, func(t Tags) {
+		for k, v := range tags {
+			t[k] = v
+		}
+	}, out)
+}
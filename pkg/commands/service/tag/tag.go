@@ -0,0 +1,184 @@
+package tag
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// Prefix starts the line of a service's comment that records its tags.
+//
+// NOTE: The API has no field for arbitrary service metadata, so the tags are
+// kept on the last line of the comment (e.g. "fastly-tags: env=prod
+// team=edge"), where they're visible to every user of the account.
+const Prefix = "fastly-tags:"
+
+var (
+	keyRegEx   = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.\-/]*$`)
+	valueRegEx = regexp.MustCompile(`^[A-Za-z0-9_.:/@+\-]*$`)
+)
+
+// syntaxRemediation describes the syntax of tags and tag expressions.
+const syntaxRemediation = "A tag is a key and a value (e.g. team=edge). Keys start with a letter or digit and may contain letters, digits and '_', '.', '-' or '/'. Values may also contain ':', '@' and '+'. A --tag filter is one of: key=value, key!=value, key (the tag is set) or !key (the tag isn't set)."
+
+// Tags are the tags of a service, keyed by name.
+type Tags map[string]string
+
+// String returns the tags, sorted by key, e.g. "env=prod team=edge".
+func (t Tags) String() string {
+	keys := make([]string, 0, len(t))
+	for k := range t {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+t[k])
+	}
+	return strings.Join(pairs, " ")
+}
+
+// Split separates the tags from the rest of a service comment.
+func Split(comment string) (rest string, tags Tags) {
+	tags = make(Tags)
+	comment = strings.TrimRight(comment, "\r\n")
+	i := strings.LastIndex(comment, "\n")
+	line := comment[i+1:]
+	if !strings.HasPrefix(line, Prefix) {
+		return comment, tags
+	}
+	for _, pair := range strings.Fields(strings.TrimPrefix(line, Prefix)) {
+		if k, v, err := Parse(pair); err == nil {
+			tags[k] = v
+		}
+	}
+	if i < 0 {
+		return "", tags
+	}
+	return strings.TrimRight(comment[:i], "\r\n"), tags
+}
+
+// Join returns the service comment recording the tags after the rest of the
+// comment.
+func Join(rest string, tags Tags) string {
+	if len(tags) == 0 {
+		return rest
+	}
+	line := Prefix + " " + tags.String()
+	if rest == "" {
+		return line
+	}
+	return rest + "\n" + line
+}
+
+// Parse parses a key=value tag.
+func Parse(s string) (key, value string, err error) {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || !keyRegEx.MatchString(key) || !valueRegEx.MatchString(value) {
+		return "", "", fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid tag '%s'", s),
+			Remediation: syntaxRemediation,
+		}
+	}
+	return key, value, nil
+}
+
+// ValidateKey checks the key of a tag is valid.
+func ValidateKey(key string) error {
+	if !keyRegEx.MatchString(key) {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid tag key '%s'", key),
+			Remediation: syntaxRemediation,
+		}
+	}
+	return nil
+}
+
+// Operators of a tag expression.
+const (
+	OpEqual    = "="
+	OpNotEqual = "!="
+	OpExists   = "exists"
+	OpMissing  = "missing"
+)
+
+// Expr is a tag expression, which matches the tags of a service.
+type Expr struct {
+	Key   string
+	Op    string
+	Value string
+}
+
+// ParseExpr parses a tag expression: key=value, key!=value, key (the tag is
+// set, with any value) or !key (the tag isn't set).
+func ParseExpr(s string) (Expr, error) {
+	e := Expr{Key: s, Op: OpExists}
+	switch {
+	case strings.Contains(s, "!="):
+		e.Op = OpNotEqual
+		e.Key, e.Value, _ = strings.Cut(s, "!=")
+	case strings.Contains(s, "="):
+		e.Op = OpEqual
+		e.Key, e.Value, _ = strings.Cut(s, "=")
+	case strings.HasPrefix(s, "!"):
+		e.Op = OpMissing
+		e.Key = strings.TrimPrefix(s, "!")
+	}
+	if !keyRegEx.MatchString(e.Key) || !valueRegEx.MatchString(e.Value) {
+		return Expr{}, fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid --tag filter '%s'", s),
+			Remediation: syntaxRemediation,
+		}
+	}
+	return e, nil
+}
+
+// Match indicates whether the tags match the expression. A service without
+// the tag matches key!=value.
+func (e Expr) Match(tags Tags) bool {
+	v, ok := tags[e.Key]
+	switch e.Op {
+	case OpEqual:
+		return ok && v == e.Value
+	case OpNotEqual:
+		return !ok || v != e.Value
+	case OpMissing:
+		return !ok
+	default:
+		return ok
+	}
+}
+
+// Filter matches the services whose tags match every expression.
+type Filter []Expr
+
+// ParseFilter parses the --tag flag values.
+func ParseFilter(values []string) (Filter, error) {
+	f := make(Filter, 0, len(values))
+	for _, v := range values {
+		e, err := ParseExpr(v)
+		if err != nil {
+			return nil, err
+		}
+		f = append(f, e)
+	}
+	return f, nil
+}
+
+// Match indicates whether the service comment's tags match every expression
+// of the filter.
+func (f Filter) Match(comment string) bool {
+	_, tags := Split(comment)
+	for _, e := range f {
+		if !e.Match(tags) {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterFlagDesc describes the --tag flag of commands that filter services.
+const FilterFlagDesc = "Only include services whose tags match the expression: key=value, key!=value, key or !key (repeat to require every expression to match)"
@@ -0,0 +1,263 @@
+package tag_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/commands/service/tag"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestParseExpr(t *testing.T) {
+	scenarios := []struct {
+		expr      string
+		want      tag.Expr
+		wantError string
+	}{
+		{expr: "team=edge", want: tag.Expr{Key: "team", Op: tag.OpEqual, Value: "edge"}},
+		{expr: "env!=prod", want: tag.Expr{Key: "env", Op: tag.OpNotEqual, Value: "prod"}},
+		{expr: "owner", want: tag.Expr{Key: "owner", Op: tag.OpExists}},
+		{expr: "!owner", want: tag.Expr{Key: "owner", Op: tag.OpMissing}},
+		{expr: "team=", want: tag.Expr{Key: "team", Op: tag.OpEqual}},
+		{expr: "cost-center/id=eu:42", want: tag.Expr{Key: "cost-center/id", Op: tag.OpEqual, Value: "eu:42"}},
+		{expr: "", wantError: "invalid --tag filter ''"},
+		{expr: "=edge", wantError: "invalid --tag filter '=edge'"},
+		{expr: "team=edge team", wantError: "invalid --tag filter"},
+		{expr: "team==edge", wantError: "invalid --tag filter"},
+		{expr: "!team=edge", wantError: "invalid --tag filter"},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.expr, func(t *testing.T) {
+			e, err := tag.ParseExpr(testcase.expr)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if testcase.wantError == "" {
+				testutil.AssertEqual(t, testcase.want, e)
+			}
+		})
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	comment := "Public website\nfastly-tags: env=prod team=edge"
+	scenarios := []struct {
+		filter []string
+		want   bool
+	}{
+		{filter: nil, want: true},
+		{filter: []string{"team=edge"}, want: true},
+		{filter: []string{"team=edge", "env=prod"}, want: true},
+		{filter: []string{"team=edge", "env!=prod"}, want: false},
+		{filter: []string{"team=edge", "env=stage"}, want: false},
+		{filter: []string{"team=edge", "owner"}, want: false},
+		{filter: []string{"team=edge", "!owner"}, want: true},
+		{filter: []string{"owner!=alice"}, want: true}, // the tag isn't set
+		{filter: []string{"team"}, want: true},
+		{filter: []string{"!team"}, want: false},
+	}
+	for _, testcase := range scenarios {
+		t.Run(strings.Join(testcase.filter, " "), func(t *testing.T) {
+			f, err := tag.ParseFilter(testcase.filter)
+			testutil.AssertNoError(t, err)
+			testutil.AssertBool(t, testcase.want, f.Match(comment))
+		})
+	}
+}
+
+func TestSplitJoin(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		comment  string
+		wantRest string
+		wantTags tag.Tags
+	}{
+		{name: "no tags", comment: "Public website", wantRest: "Public website", wantTags: tag.Tags{}},
+		{name: "only tags", comment: "fastly-tags: team=edge", wantTags: tag.Tags{"team": "edge"}},
+		{
+			name:     "comment and tags",
+			comment:  "Public website\nserved from S3\nfastly-tags: env=prod team=edge",
+			wantRest: "Public website\nserved from S3",
+			wantTags: tag.Tags{"env": "prod", "team": "edge"},
+		},
+		{
+			name:     "tags not on the last line",
+			comment:  "fastly-tags: team=edge\nPublic website",
+			wantRest: "fastly-tags: team=edge\nPublic website",
+			wantTags: tag.Tags{},
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			rest, tags := tag.Split(testcase.comment)
+			testutil.AssertString(t, testcase.wantRest, rest)
+			testutil.AssertEqual(t, testcase.wantTags, tags)
+			testutil.AssertString(t, testcase.comment, tag.Join(rest, tags))
+		})
+	}
+}
+
+// services are the services of the account: two owned by the edge team.
+var services = []*fastly.Service{
+	{ServiceID: fastly.ToPointer("123"), Name: fastly.ToPointer("www"), Comment: fastly.ToPointer("Website\nfastly-tags: env=prod team=edge")},
+	{ServiceID: fastly.ToPointer("456"), Name: fastly.ToPointer("www-stage"), Comment: fastly.ToPointer("fastly-tags: env=stage team=edge")},
+	{ServiceID: fastly.ToPointer("789"), Name: fastly.ToPointer("api"), Comment: fastly.ToPointer("API")},
+}
+
+func getServices(_ *fastly.GetServicesInput) *fastly.ListPaginator[fastly.Service] {
+	body := `[
+		{"id": "123", "name": "www", "comment": "Website\nfastly-tags: env=prod team=edge"},
+		{"id": "456", "name": "www-stage", "comment": "fastly-tags: env=stage team=edge"},
+		{"id": "789", "name": "api", "comment": "API"}
+	]`
+	return fastly.NewPaginator[fastly.Service](mock.HTTPClient{
+		Errors:    []error{nil},
+		Responses: []*http.Response{{Body: io.NopCloser(strings.NewReader(body))}},
+	}, fastly.ListOpts{}, "/example")
+}
+
+func getService(i *fastly.GetServiceInput) (*fastly.Service, error) {
+	for _, s := range services {
+		if *s.ServiceID == i.ServiceID {
+			return s, nil
+		}
+	}
+	return nil, testutil.Err
+}
+
+func TestTagUpdate(t *testing.T) {
+	args := testutil.Args
+	scenarios := []struct {
+		name        string
+		args        []string
+		stdin       string
+		tty         bool
+		wantError   string
+		wantOutput  []string
+		wantUpdates map[string]string
+	}{
+		{
+			name: "set on a service",
+			args: args("service tag set owner=alice team=core --service-id 123"),
+			wantUpdates: map[string]string{
+				"123": "Website\nfastly-tags: env=prod owner=alice team=core",
+			},
+			wantOutput: []string{"Updated the tags of service www (123): env=prod owner=alice team=core"},
+		},
+		{
+			name:        "set unchanged",
+			args:        args("service tag set team=edge --service-id 456"),
+			wantUpdates: map[string]string{},
+			wantOutput:  []string{"The tags of service www-stage (456) are unchanged"},
+		},
+		{
+			name: "remove from a service",
+			args: args("service tag remove env team --service-id 456"),
+			wantUpdates: map[string]string{
+				"456": "",
+			},
+			wantOutput: []string{"Removed the tags of service www-stage (456)"},
+		},
+		{
+			name:      "invalid tag",
+			args:      args("service tag set team --service-id 123"),
+			wantError: "invalid tag 'team'",
+		},
+		{
+			name:      "filter with service ID",
+			args:      args("service tag set owner=alice --tag team=edge --service-id 123"),
+			wantError: "--tag cannot be used with --service-id or --service-name",
+		},
+		{
+			name: "filter matching one service",
+			args: args("service tag set owner=alice --tag team=edge --tag env!=prod"),
+			wantUpdates: map[string]string{
+				"456": "fastly-tags: env=stage owner=alice team=edge",
+			},
+		},
+		{
+			name:        "filter matching no service",
+			args:        args("service tag set owner=alice --tag team=core"),
+			wantUpdates: map[string]string{},
+			wantOutput:  []string{"No services matched the --tag filter"},
+		},
+		{
+			name:      "bulk without a terminal",
+			args:      args("service tag set owner=alice --tag team=edge"),
+			wantError: "confirmation required to set the tags owner=alice on 2 services",
+		},
+		{
+			name:      "bulk with --non-interactive",
+			args:      args("service tag set owner=alice --tag team=edge --non-interactive"),
+			tty:       true,
+			wantError: "confirmation required to set the tags owner=alice on 2 services",
+		},
+		{
+			name:      "bulk declined",
+			args:      args("service tag set owner=alice --tag team=edge"),
+			tty:       true,
+			stdin:     "no",
+			wantError: argparser.ErrNotConfirmed.Error(),
+			wantOutput: []string{
+				"This will set the tags owner=alice on 2 services:",
+				"  www (123)\n  www-stage (456)",
+			},
+		},
+		{
+			name:  "bulk confirmed",
+			args:  args("service tag remove team --tag team=edge"),
+			tty:   true,
+			stdin: "yes",
+			wantUpdates: map[string]string{
+				"123": "Website\nfastly-tags: env=prod",
+				"456": "fastly-tags: env=stage",
+			},
+			wantOutput: []string{"This will remove the tags team on 2 services:"},
+		},
+		{
+			name: "bulk with --auto-yes",
+			args: args("service tag set owner=alice --tag team=edge --auto-yes"),
+			wantUpdates: map[string]string{
+				"123": "Website\nfastly-tags: env=prod owner=alice team=edge",
+				"456": "fastly-tags: env=stage owner=alice team=edge",
+			},
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			updates := make(map[string]string)
+			api := mock.API{
+				GetServiceFn:  getService,
+				GetServicesFn: getServices,
+				UpdateServiceFn: func(i *fastly.UpdateServiceInput) (*fastly.Service, error) {
+					updates[i.ServiceID] = *i.Comment
+					return &fastly.Service{ServiceID: &i.ServiceID, Comment: i.Comment}, nil
+				},
+			}
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(testcase.args, &stdout)
+				opts.APIClientFactory = mock.APIClient(api)
+				opts.Input = strings.NewReader(testcase.stdin)
+				opts.IsTTY = func(_ any) bool { return testcase.tty }
+				return opts, nil
+			}
+			err := app.Run(testcase.args, nil)
+			t.Log(stdout.String())
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			if testcase.wantUpdates != nil {
+				testutil.AssertEqual(t, testcase.wantUpdates, updates)
+			}
+		})
+	}
+}
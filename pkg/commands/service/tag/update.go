@@ -0,0 +1,136 @@
+package tag
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// target selects the services whose tags are updated: a single service, or
+// every service matching the --tag filter.
+type target struct {
+	argparser.Base
+
+	filter      []string
+	serviceName argparser.OptionalServiceNameID
+}
+
+// registerFlags registers the flags that select the services.
+func (t *target) registerFlags(g *global.Data) {
+	t.RegisterFlag(argparser.StringFlagOpts{
+		Name:        argparser.FlagServiceIDName,
+		Description: argparser.FlagServiceIDDesc,
+		Dst:         &g.Manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	t.RegisterFlag(argparser.StringFlagOpts{
+		Action:      t.serviceName.Set,
+		Name:        argparser.FlagServiceName,
+		Description: argparser.FlagServiceDesc,
+		Dst:         &t.serviceName.Value,
+	})
+	t.CmdClause.Flag("tag", "Update every service whose tags match the expression, instead of a single service: key=value, key!=value, key or !key (repeat to require every expression to match)").StringsVar(&t.filter)
+}
+
+// services returns the selected services. The user must confirm an update of
+// more than one service matched by the --tag filter.
+func (t *target) services(action string, in io.Reader, out io.Writer) ([]*fastly.Service, error) {
+	g := t.Globals
+	if len(t.filter) == 0 {
+		serviceID, source, flag, err := argparser.ServiceID(t.serviceName, *g.Manifest, g.APIClient, g.ErrLog)
+		if err != nil {
+			return nil, err
+		}
+		if source == manifest.SourceUndefined && !t.serviceName.WasSet {
+			err := argparser.NoServiceIDError()
+			g.ErrLog.Add(err)
+			return nil, err
+		}
+		if g.Verbose() {
+			argparser.DisplayServiceID(serviceID, flag, source, out)
+		}
+		s, err := g.APIClient.GetService(&fastly.GetServiceInput{ServiceID: serviceID})
+		if err != nil {
+			g.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID": serviceID,
+			})
+			return nil, err
+		}
+		return []*fastly.Service{s}, nil
+	}
+
+	if g.Manifest.Flag.ServiceID != "" || t.serviceName.WasSet {
+		return nil, fmt.Errorf("error parsing arguments: --tag cannot be used with --service-id or --service-name")
+	}
+	filter, err := ParseFilter(t.filter)
+	if err != nil {
+		return nil, err
+	}
+	all, err := argparser.ListServices(g.APIClient)
+	if err != nil {
+		g.ErrLog.Add(err)
+		return nil, err
+	}
+	var matches []*fastly.Service
+	var names []string
+	for _, s := range all {
+		if filter.Match(fastly.ToValue(s.Comment)) {
+			matches = append(matches, s)
+			names = append(names, fmt.Sprintf("%s (%s)", fastly.ToValue(s.Name), fastly.ToValue(s.ServiceID)))
+		}
+	}
+	if len(matches) > 1 {
+		err := argparser.ConfirmBulk(argparser.ConfirmBulkOpts{
+			Action:   action,
+			Globals:  g,
+			In:       in,
+			Out:      out,
+			Services: names,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return matches, nil
+}
+
+// update applies the change to the tags of each service.
+func (t *target) update(services []*fastly.Service, change func(Tags), out io.Writer) error {
+	if len(services) == 0 {
+		text.Info(out, "No services matched the --tag filter")
+		return nil
+	}
+	for _, s := range services {
+		serviceID, name := fastly.ToValue(s.ServiceID), fastly.ToValue(s.Name)
+		comment := fastly.ToValue(s.Comment)
+		rest, tags := Split(comment)
+		change(tags)
+		updated := Join(rest, tags)
+		if updated == comment {
+			text.Info(out, "The tags of service %s (%s) are unchanged", name, serviceID)
+			continue
+		}
+		_, err := t.Globals.APIClient.UpdateService(&fastly.UpdateServiceInput{
+			ServiceID: serviceID,
+			Comment:   &updated,
+		})
+		if err != nil {
+			t.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID": serviceID,
+			})
+			return fmt.Errorf("error updating the tags of service %s (%s): %w", name, serviceID, err)
+		}
+		if len(tags) == 0 {
+			text.Success(out, "Removed the tags of service %s (%s)", name, serviceID)
+		} else {
+			text.Success(out, "Updated the tags of service %s (%s): %s", name, serviceID, tags)
+		}
+	}
+	return nil
+}
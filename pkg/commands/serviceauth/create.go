@@ -83,6 +83,6 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
-	text.Success(out, "Created service authorization %s", s.ID)
+	text.Result(out, s.ID, "Created service authorization %s", s.ID)
 	return nil
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/fastly/cli/pkg/argparser"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
 	"github.com/fastly/cli/pkg/time"
 )
 
@@ -33,7 +34,7 @@ func NewDescribeCommand(parent argparser.Registerer, g *global.Data) *DescribeCo
 	c.CmdClause.Flag("id", "ID of the service authorization to retrieve").Required().StringVar(&c.Input.ID)
 
 	// Optional.
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	return &c
 }
 
@@ -65,13 +66,13 @@ func (c *DescribeCommand) print(s *fastly.ServiceAuthorization, out io.Writer) e
 	fmt.Fprintf(out, "Permission: %s\n", s.Permission)
 
 	if s.CreatedAt != nil {
-		fmt.Fprintf(out, "Created (UTC): %s\n", s.CreatedAt.UTC().Format(time.Format))
+		fmt.Fprintf(out, "%s: %s\n", text.TimeLabel("Created"), text.FormatTimeLayout(s.CreatedAt, time.Format))
 	}
 	if s.UpdatedAt != nil {
-		fmt.Fprintf(out, "Last edited (UTC): %s\n", s.UpdatedAt.UTC().Format(time.Format))
+		fmt.Fprintf(out, "%s: %s\n", text.TimeLabel("Last edited"), text.FormatTimeLayout(s.UpdatedAt, time.Format))
 	}
 	if s.DeletedAt != nil {
-		fmt.Fprintf(out, "Deleted (UTC): %s\n", s.DeletedAt.UTC().Format(time.Format))
+		fmt.Fprintf(out, "%s: %s\n", text.TimeLabel("Deleted"), text.FormatTimeLayout(s.DeletedAt, time.Format))
 	}
 
 	return nil
@@ -16,6 +16,7 @@ import (
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 
 	input fastly.ListServiceAuthorizationsInput
 }
@@ -25,9 +26,10 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 	var c ListCommand
 	c.Globals = g
 	c.CmdClause = parent.Command("list", "List service authorizations")
+	c.EmptyState = argparser.EmptyState{Resource: "service authorizations", Create: "fastly service-auth create --user-id <USER_ID>"}
 
 	// Optional.
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	c.CmdClause.Flag("page", "Page number of data set to fetch").IntVar(&c.input.PageNumber)
 	c.CmdClause.Flag("per-page", "Number of records per page").IntVar(&c.input.PageSize)
 	return &c
@@ -52,6 +54,10 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
+	if c.PrintEmpty(out, len(o.Items)) {
+		return nil
+	}
+
 	if !c.Globals.Verbose() {
 		if len(o.Items) > 0 {
 			tw := text.NewTable(out)
@@ -73,13 +79,13 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		fmt.Fprintf(out, "Permission: %s\n", s.Permission)
 
 		if s.CreatedAt != nil {
-			fmt.Fprintf(out, "Created (UTC): %s\n", s.CreatedAt.UTC().Format(time.Format))
+			fmt.Fprintf(out, "%s: %s\n", text.TimeLabel("Created"), text.FormatTimeLayout(s.CreatedAt, time.Format))
 		}
 		if s.UpdatedAt != nil {
-			fmt.Fprintf(out, "Last edited (UTC): %s\n", s.UpdatedAt.UTC().Format(time.Format))
+			fmt.Fprintf(out, "%s: %s\n", text.TimeLabel("Last edited"), text.FormatTimeLayout(s.UpdatedAt, time.Format))
 		}
 		if s.DeletedAt != nil {
-			fmt.Fprintf(out, "Deleted (UTC): %s\n", s.DeletedAt.UTC().Format(time.Format))
+			fmt.Fprintf(out, "%s: %s\n", text.TimeLabel("Deleted"), text.FormatTimeLayout(s.DeletedAt, time.Format))
 		}
 	}
 
@@ -1,6 +1,7 @@
 package serviceversion
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/fastly/go-fastly/v9/fastly"
@@ -51,7 +52,7 @@ func NewActivateCommand(parent argparser.Registerer, g *global.Data) *ActivateCo
 }
 
 // Exec invokes the application logic for the command.
-func (c *ActivateCommand) Exec(_ io.Reader, out io.Writer) error {
+func (c *ActivateCommand) Exec(in io.Reader, out io.Writer) error {
 	serviceID, serviceVersion, err := argparser.ServiceDetails(argparser.ServiceDetailsOpts{
 		AutoCloneFlag:      c.autoClone,
 		APIClient:          c.Globals.APIClient,
@@ -72,6 +73,18 @@ func (c *ActivateCommand) Exec(_ io.Reader, out io.Writer) error {
 	c.Input.ServiceID = serviceID
 	c.Input.ServiceVersion = fastly.ToValue(serviceVersion.Number)
 
+	err = argparser.Confirm(argparser.ConfirmOpts{
+		Action:         fmt.Sprintf("activate version %d of service %s", c.Input.ServiceVersion, serviceID),
+		Globals:        c.Globals,
+		In:             in,
+		Out:            out,
+		ServiceID:      serviceID,
+		ServiceVersion: c.Input.ServiceVersion,
+	})
+	if err != nil {
+		return err
+	}
+
 	ver, err := c.Globals.APIClient.ActivateVersion(&c.Input)
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
@@ -2,6 +2,7 @@ package serviceversion
 
 import (
 	"io"
+	"strconv"
 
 	"github.com/fastly/go-fastly/v9/fastly"
 
@@ -76,6 +77,6 @@ func (c *CloneCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
-	text.Success(out, "Cloned service %s version %d to version %d", fastly.ToValue(ver.ServiceID), c.Input.ServiceVersion, fastly.ToValue(ver.Number))
+	text.Result(out, strconv.Itoa(fastly.ToValue(ver.Number)), "Cloned service %s version %d to version %d", fastly.ToValue(ver.ServiceID), c.Input.ServiceVersion, fastly.ToValue(ver.Number))
 	return nil
 }
@@ -0,0 +1,144 @@
+package serviceversion
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// annotateRemediation explains how versions are annotated with a comment.
+const annotateRemediation = "Versions are annotated with a comment (e.g. the git commit SHA) using `fastly compute deploy --comment` or `fastly service-version update --comment`."
+
+// FindCommand calls the Fastly API to find the service versions whose comment
+// contains a string, such as a git commit SHA.
+type FindCommand struct {
+	argparser.Base
+	argparser.JSONOutput
+
+	latest      bool
+	match       string
+	serviceName argparser.OptionalServiceNameID
+}
+
+// NewFindCommand returns a usable command registered under the parent.
+func NewFindCommand(parent argparser.Registerer, g *global.Data) *FindCommand {
+	c := FindCommand{
+		Base: argparser.Base{
+			Globals: g,
+		},
+	}
+	c.CmdClause = parent.Command("find", "Find the Fastly service versions whose comment contains a string, such as a git commit SHA")
+
+	// Required.
+	c.CmdClause.Flag("match", "Text to search for in version comments (case-insensitive), e.g. a git commit SHA or a prefix of one").Short('m').Required().StringVar(&c.match)
+
+	// Optional.
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.CmdClause.Flag("latest", "Only display the newest matching version (the version number alone, unless --json is set)").BoolVar(&c.latest)
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Name:        argparser.FlagServiceIDName,
+		Description: argparser.FlagServiceIDDesc,
+		Dst:         &g.Manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        argparser.FlagServiceName,
+		Description: argparser.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *FindCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	if strings.TrimSpace(c.match) == "" {
+		return fmt.Errorf("error parsing arguments: --match cannot be empty")
+	}
+
+	serviceID, source, flag, err := argparser.ServiceID(c.serviceName, *c.Globals.Manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		argparser.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	// NOTE: The API returns every version of a service in a single response.
+	versions, err := c.Globals.APIClient.ListVersions(&fastly.ListVersionsInput{
+		ServiceID: serviceID,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+		})
+		return err
+	}
+
+	matches := FindVersions(versions, c.match)
+
+	if c.latest {
+		if len(matches) == 0 {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("no version of service %s has a comment containing '%s'", serviceID, c.match),
+				Remediation: annotateRemediation,
+			}
+		}
+		if ok, err := c.WriteJSON(out, matches[0]); ok {
+			return err
+		}
+		fmt.Fprintln(out, fastly.ToValue(matches[0].Number))
+		return nil
+	}
+
+	if ok, err := c.WriteJSON(out, matches); ok {
+		return err
+	}
+
+	if len(matches) == 0 {
+		text.Info(out, "No version of service %s has a comment containing '%s'.\n\n%s", serviceID, c.match, annotateRemediation)
+		return nil
+	}
+
+	tw := text.NewTable(out)
+	tw.AddHeader("NUMBER", "ACTIVE", text.TimeLabel("CREATED"), text.TimeLabel("LAST EDITED"), "COMMENT")
+	for _, v := range matches {
+		tw.AddLine(
+			fastly.ToValue(v.Number),
+			fastly.ToValue(v.Active),
+			parseTime(v.CreatedAt),
+			parseTime(v.UpdatedAt),
+			fastly.ToValue(v.Comment),
+		)
+	}
+	tw.Print()
+	return nil
+}
+
+// FindVersions returns the versions whose comment contains match (ignoring
+// case), newest first.
+func FindVersions(versions []*fastly.Version, match string) []*fastly.Version {
+	match = strings.ToLower(match)
+	matches := []*fastly.Version{}
+	for _, v := range versions {
+		if strings.Contains(strings.ToLower(fastly.ToValue(v.Comment)), match) {
+			matches = append(matches, v)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return fastly.ToValue(matches[i].Number) > fastly.ToValue(matches[j].Number)
+	})
+	return matches
+}
@@ -31,7 +31,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 		},
 	}
 	c.CmdClause = parent.Command("list", "List Fastly service versions")
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	c.RegisterFlag(argparser.StringFlagOpts{
 		Name:        argparser.FlagServiceIDName,
 		Description: argparser.FlagServiceIDDesc,
@@ -77,7 +77,7 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 
 	if !c.Globals.Verbose() {
 		tw := text.NewTable(out)
-		tw.AddHeader("NUMBER", "ACTIVE", "LAST EDITED (UTC)")
+		tw.AddHeader("NUMBER", "ACTIVE", text.TimeLabel("LAST EDITED"))
 		for _, version := range o {
 			tw.AddLine(
 				fastly.ToValue(version.Number),
@@ -103,5 +103,5 @@ func parseTime(ua *time.Time) string {
 	if ua == nil {
 		return ""
 	}
-	return ua.UTC().Format(fsttime.Format)
+	return text.FormatTimeLayout(ua, fsttime.Format)
 }
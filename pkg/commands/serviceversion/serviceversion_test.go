@@ -2,9 +2,13 @@ package serviceversion_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"io"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/fastly/go-fastly/v9/fastly"
 
@@ -63,6 +67,119 @@ func TestVersionClone(t *testing.T) {
 	}
 }
 
+func TestVersionFind(t *testing.T) {
+	args := testutil.Args
+	scenarios := []struct {
+		args       []string
+		api        mock.API
+		wantError  string
+		wantOutput string
+	}{
+		{
+			args:      args("service-version find --service-id 123"),
+			wantError: "error parsing arguments: required flag --match not provided",
+		},
+		{
+			args:      append(args("service-version find --service-id 123 --match"), " "),
+			wantError: "error parsing arguments: --match cannot be empty",
+		},
+		{
+			args:      args("service-version find --service-id 123 --match abc123"),
+			api:       mock.API{ListVersionsFn: testutil.ListVersionsError},
+			wantError: testutil.Err.Error(),
+		},
+		{
+			args:       args("service-version find --service-id 123 --match abc123"),
+			api:        mock.API{ListVersionsFn: listFiftyVersions},
+			wantOutput: findVersionsOutput,
+		},
+		{
+			args:       args("service-version find --service-id 123 -m ABC123 --latest"),
+			api:        mock.API{ListVersionsFn: listFiftyVersions},
+			wantOutput: "50\n",
+		},
+		{
+			args:       args("service-version find --service-id 123 --match 0000019 --latest"),
+			api:        mock.API{ListVersionsFn: listFiftyVersions},
+			wantOutput: "19\n",
+		},
+		{
+			args:      args("service-version find --service-id 123 --match fedcba --latest"),
+			api:       mock.API{ListVersionsFn: listFiftyVersions},
+			wantError: "no version of service 123 has a comment containing 'fedcba'",
+		},
+		{
+			args:       args("service-version find --service-id 123 --match fedcba"),
+			api:        mock.API{ListVersionsFn: listFiftyVersions},
+			wantOutput: "INFO: No version of service 123 has a comment containing 'fedcba'.",
+		},
+		{
+			args:       args("service-version find --service-id 123 --match fedcba --json"),
+			api:        mock.API{ListVersionsFn: listFiftyVersions},
+			wantOutput: "[]\n",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(testcase.args, &stdout)
+				opts.APIClientFactory = mock.APIClient(testcase.api)
+				return opts, nil
+			}
+			err := app.Run(testcase.args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+		})
+	}
+}
+
+func TestVersionFindJSON(t *testing.T) {
+	for _, testcase := range []struct {
+		args        []string
+		wantNumbers []int
+	}{
+		{
+			args:        testutil.Args("service-version find --service-id 123 --match abc123 --json"),
+			wantNumbers: []int{50, 40, 30, 25, 20, 10},
+		},
+		{
+			args:        testutil.Args("service-version find --service-id 123 --match abc123 --latest --json"),
+			wantNumbers: []int{50},
+		},
+	} {
+		t.Run(strings.Join(testcase.args, " "), func(t *testing.T) {
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(testcase.args, &stdout)
+				opts.APIClientFactory = mock.APIClient(mock.API{ListVersionsFn: listFiftyVersions})
+				return opts, nil
+			}
+			err := app.Run(testcase.args, nil)
+			testutil.AssertNoError(t, err)
+
+			// NOTE: --latest prints a single version rather than a list, so the
+			// result can be used directly by scripts (e.g. `jq .Number`).
+			var versions []fastly.Version
+			if len(testcase.wantNumbers) == 1 {
+				var v fastly.Version
+				err = json.Unmarshal(stdout.Bytes(), &v)
+				versions = append(versions, v)
+			} else {
+				err = json.Unmarshal(stdout.Bytes(), &versions)
+			}
+			testutil.AssertNoError(t, err)
+
+			numbers := make([]int, 0, len(versions))
+			for _, v := range versions {
+				numbers = append(numbers, fastly.ToValue(v.Number))
+			}
+			testutil.AssertEqual(t, testcase.wantNumbers, numbers)
+		})
+	}
+}
+
 func TestVersionList(t *testing.T) {
 	args := testutil.Args
 	scenarios := []struct {
@@ -169,6 +286,75 @@ func TestVersionUpdate(t *testing.T) {
 	}
 }
 
+func TestVersionUpdateEdit(t *testing.T) {
+	listVersions := func(i *fastly.ListVersionsInput) ([]*fastly.Version, error) {
+		vs, err := testutil.ListVersions(i)
+		if err == nil {
+			vs[2].Comment = fastly.ToPointer("initial release")
+		}
+		return vs, err
+	}
+
+	args := testutil.Args
+	scenarios := []struct {
+		args        []string
+		edited      string
+		wantError   string
+		wantOutput  string
+		wantComment string
+	}{
+		{
+			args:        args("service-version update --service-id 123 --version 3 --edit"),
+			edited:      "initial release (abc123)\n",
+			wantOutput:  "Updated service 123 version 3",
+			wantComment: "initial release (abc123)",
+		},
+		{
+			args:      args("service-version update --service-id 123 --version 3 --edit"),
+			edited:    "initial release\n",
+			wantError: "edit cancelled",
+		},
+		{
+			args:      args("service-version update --service-id 123 --version 3 --edit --comment foo"),
+			wantError: "error parsing arguments: --comment and --edit are mutually exclusive",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(strings.Join(testcase.args, " "), func(t *testing.T) {
+			var (
+				comment string
+				stdout  bytes.Buffer
+			)
+			api := mock.API{
+				ListVersionsFn: listVersions,
+				UpdateVersionFn: func(i *fastly.UpdateVersionInput) (*fastly.Version, error) {
+					comment = fastly.ToValue(i.Comment)
+					return updateVersionOK(i)
+				},
+			}
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(testcase.args, &stdout)
+				opts.APIClientFactory = mock.APIClient(api)
+				opts.ExecuteEditor = func(editor []string) error {
+					path := editor[len(editor)-1]
+					data, err := os.ReadFile(path)
+					if err != nil {
+						return err
+					}
+					testutil.AssertString(t, "initial release\n", string(data))
+					return os.WriteFile(path, []byte(testcase.edited), 0o600)
+				}
+				return opts, nil
+			}
+			err := app.Run(testcase.args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			testutil.AssertString(t, testcase.wantComment, comment)
+		})
+	}
+}
+
 func TestVersionActivate(t *testing.T) {
 	args := testutil.Args
 	scenarios := []struct {
@@ -182,7 +368,7 @@ func TestVersionActivate(t *testing.T) {
 			wantError: "error parsing arguments: required flag --version not provided",
 		},
 		{
-			args: args("service-version activate --service-id 123 --version 1 --autoclone"),
+			args: args("service-version activate --service-id 123 --version 1 --autoclone --auto-yes"),
 			api: mock.API{
 				ListVersionsFn:    testutil.ListVersions,
 				CloneVersionFn:    testutil.CloneVersionResult(4),
@@ -191,7 +377,7 @@ func TestVersionActivate(t *testing.T) {
 			wantError: testutil.Err.Error(),
 		},
 		{
-			args: args("service-version activate --service-id 123 --version 1 --autoclone"),
+			args: args("service-version activate --service-id 123 --version 1 --autoclone --auto-yes"),
 			api: mock.API{
 				ListVersionsFn:    testutil.ListVersions,
 				CloneVersionFn:    testutil.CloneVersionResult(4),
@@ -200,7 +386,7 @@ func TestVersionActivate(t *testing.T) {
 			wantOutput: "Activated service 123 version 4",
 		},
 		{
-			args: args("service-version activate --service-id 123 --version 3 --autoclone"),
+			args: args("service-version activate --service-id 123 --version 3 --autoclone --auto-yes"),
 			api: mock.API{
 				ListVersionsFn:    testutil.ListVersions,
 				ActivateVersionFn: activateVersionOK,
@@ -224,6 +410,105 @@ func TestVersionActivate(t *testing.T) {
 	}
 }
 
+func TestVersionActivateConfirmation(t *testing.T) {
+	args := testutil.Args
+	api := mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		GetServiceDetailsFn: func(_ *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+			return &fastly.ServiceDetail{Name: fastly.ToPointer("my-service")}, nil
+		},
+		ListDomainsFn: func(_ *fastly.ListDomainsInput) ([]*fastly.Domain, error) {
+			return []*fastly.Domain{
+				{Name: fastly.ToPointer("www.example.com")},
+				{Name: fastly.ToPointer("example.com")},
+			}, nil
+		},
+	}
+	scenarios := []struct {
+		name          string
+		args          []string
+		tty           bool
+		stdin         string
+		wantError     string
+		wantOutput    []string
+		wantActivated bool
+	}{
+		{
+			name:          "TTY confirmed with service name",
+			args:          args("service-version activate --service-id 123 --version 3"),
+			tty:           true,
+			stdin:         "my-service\n",
+			wantOutput:    []string{"This will activate version 3 of service 123", "Service: my-service (123)", "Version: 3", "Domains: www.example.com, example.com", "Type the service name (my-service) or 'yes' to continue:", "Activated service 123 version 3"},
+			wantActivated: true,
+		},
+		{
+			name:          "TTY confirmed with yes",
+			args:          args("service-version activate --service-id 123 --version 3"),
+			tty:           true,
+			stdin:         "YES\n",
+			wantOutput:    []string{"Activated service 123 version 3"},
+			wantActivated: true,
+		},
+		{
+			name:      "TTY declined",
+			args:      args("service-version activate --service-id 123 --version 3"),
+			tty:       true,
+			stdin:     "y\n",
+			wantError: "operation not confirmed: no changes were made",
+		},
+		{
+			name:          "non-interactive with flag",
+			args:          args("service-version activate --service-id 123 --version 3 -y"),
+			wantOutput:    []string{"Activated service 123 version 3"},
+			wantActivated: true,
+		},
+		{
+			name:      "non-interactive without flag",
+			args:      args("service-version activate --service-id 123 --version 3"),
+			stdin:     "yes\n",
+			wantError: "confirmation required to activate version 3 of service 123",
+		},
+		{
+			name:      "--non-interactive without --auto-yes fails",
+			args:      args("service-version activate --service-id 123 --version 3 --non-interactive"),
+			tty:       true,
+			stdin:     "yes\n",
+			wantError: "confirmation required to activate version 3 of service 123",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.name, func(t *testing.T) {
+			var (
+				activated bool
+				stdout    bytes.Buffer
+			)
+			api := api
+			api.ActivateVersionFn = func(i *fastly.ActivateVersionInput) (*fastly.Version, error) {
+				activated = true
+				return activateVersionOK(i)
+			}
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(testcase.args, &stdout)
+				opts.APIClientFactory = mock.APIClient(api)
+				opts.Input = strings.NewReader(testcase.stdin)
+				opts.IsTTY = func(_ any) bool {
+					return testcase.tty
+				}
+				return opts, nil
+			}
+			err := app.Run(testcase.args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
+			if activated != testcase.wantActivated {
+				t.Errorf("want activated %t, got %t", testcase.wantActivated, activated)
+			}
+		})
+	}
+}
+
 func TestVersionDeactivate(t *testing.T) {
 	args := testutil.Args
 	scenarios := []struct {
@@ -322,6 +607,45 @@ func TestVersionLock(t *testing.T) {
 	}
 }
 
+// listFiftyVersions returns 50 versions, newest first. Every tenth version (and
+// version 25) was deployed from commit abc123def, version 40 is active, and
+// every seventh version has no comment.
+func listFiftyVersions(i *fastly.ListVersionsInput) ([]*fastly.Version, error) {
+	created := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	var versions []*fastly.Version
+	for n := 50; n > 0; n-- {
+		createdAt := created.AddDate(0, 0, n)
+		updatedAt := createdAt.Add(time.Hour)
+		v := &fastly.Version{
+			Active:    fastly.ToPointer(n == 40),
+			CreatedAt: &createdAt,
+			Number:    fastly.ToPointer(n),
+			ServiceID: fastly.ToPointer(i.ServiceID),
+			UpdatedAt: &updatedAt,
+		}
+		switch {
+		case n == 25:
+			v.Comment = fastly.ToPointer("Hotfix ABC123DEF")
+		case n%10 == 0:
+			v.Comment = fastly.ToPointer("Deploy abc123def (main)")
+		case n%7 != 0:
+			v.Comment = fastly.ToPointer(fmt.Sprintf("Deploy %07d", n))
+		}
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
+var findVersionsOutput = strings.TrimSpace(`
+NUMBER  ACTIVE  CREATED (UTC)     LAST EDITED (UTC)  COMMENT
+50      false   2024-02-20 10:00  2024-02-20 11:00   Deploy abc123def (main)
+40      true    2024-02-10 10:00  2024-02-10 11:00   Deploy abc123def (main)
+30      false   2024-01-31 10:00  2024-01-31 11:00   Deploy abc123def (main)
+25      false   2024-01-26 10:00  2024-01-26 11:00   Hotfix ABC123DEF
+20      false   2024-01-21 10:00  2024-01-21 11:00   Deploy abc123def (main)
+10      false   2024-01-11 10:00  2024-01-11 11:00   Deploy abc123def (main)
+`) + "\n"
+
 var listVersionsShortOutput = strings.TrimSpace(`
 NUMBER  ACTIVE  LAST EDITED (UTC)
 1       true    2000-01-01 01:00
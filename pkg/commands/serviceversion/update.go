@@ -21,6 +21,7 @@ type UpdateCommand struct {
 	autoClone      argparser.OptionalAutoClone
 
 	comment argparser.OptionalString
+	edit    bool
 }
 
 // NewUpdateCommand returns a usable command registered under the parent.
@@ -60,11 +61,16 @@ func NewUpdateCommand(parent argparser.Registerer, g *global.Data) *UpdateComman
 	// fields are supposed to be optional and which should be 'required'.
 	//
 	c.CmdClause.Flag("comment", "Human-readable comment").Action(c.comment.Set).StringVar(&c.comment.Value)
+	c.CmdClause.Flag("edit", "Edit the current comment in your editor ($VISUAL or $EDITOR)").BoolVar(&c.edit)
 	return &c
 }
 
 // Exec invokes the application logic for the command.
 func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.edit && c.comment.WasSet {
+		return fmt.Errorf("error parsing arguments: --comment and --edit are mutually exclusive")
+	}
+
 	serviceID, serviceVersion, err := argparser.ServiceDetails(argparser.ServiceDetailsOpts{
 		AutoCloneFlag:      c.autoClone,
 		APIClient:          c.Globals.APIClient,
@@ -84,6 +90,18 @@ func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
 
 	c.input.ServiceID = serviceID
 	c.input.ServiceVersion = fastly.ToValue(serviceVersion.Number)
+	if c.edit {
+		c.comment.Value, err = argparser.Edit(argparser.EditOpts{
+			Globals: c.Globals,
+			Initial: fastly.ToValue(serviceVersion.Comment),
+			Name:    "comment",
+		})
+		if err != nil {
+			c.Globals.ErrLog.Add(err)
+			return err
+		}
+		c.comment.WasSet = true
+	}
 	if !c.comment.WasSet {
 		return fmt.Errorf("error parsing arguments: required flag --comment not provided")
 	}
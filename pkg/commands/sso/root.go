@@ -57,7 +57,7 @@ func (c *RootCommand) Exec(in io.Reader, out io.Writer) error {
 		msg := fmt.Sprintf("We're going to authenticate the '%s' profile", profileName)
 		text.Important(out, "%s. We need to open your browser to authenticate you.", msg)
 		text.Break(out)
-		cont, err := text.AskYesNo(out, text.BoldYellow("Do you want to continue? [y/N]: "), in)
+		cont, err := text.AskYesNo(out, text.WarningStyle("Do you want to continue? [y/N]: "), in, "--auto-yes")
 		text.Break(out)
 		if err != nil {
 			return err
@@ -138,6 +138,7 @@ func TestSSO(t *testing.T) {
 		// Otherwise no OAuth flow is happening here.
 		{
 			TestScenario: testutil.TestScenario{
+				API:  mock.API{GetTokenSelfFn: testutil.WhoamiTokenSelf},
 				Args: args("whoami"),
 				WantOutputs: []string{
 					// FIXME: Put back messaging once SSO is GA.
@@ -166,6 +167,7 @@ func TestSSO(t *testing.T) {
 		// But we've mocked the request to succeed still so it doesn't matter.
 		{
 			TestScenario: testutil.TestScenario{
+				API:            mock.API{GetTokenSelfFn: testutil.WhoamiTokenSelf},
 				Args:           args("whoami"),
 				WantOutput:     "Your access token has expired and so has your refresh token.",
 				DontWantOutput: "Alice Programmer <alice@example.com>",
@@ -190,6 +192,7 @@ func TestSSO(t *testing.T) {
 		// This allows us to validate the output messages.
 		{
 			TestScenario: testutil.TestScenario{
+				API:  mock.API{GetTokenSelfFn: testutil.WhoamiTokenSelf},
 				Args: args("whoami"),
 				WantOutputs: []string{
 					"Your access token has expired and so has your refresh token.",
@@ -1,25 +1,41 @@
 package stats
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/fastly/go-fastly/v9/fastly"
 
 	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
 )
 
 const statusSuccess = "success"
 
+// defaultTableFields are the fields displayed by --format=table when no
+// --field flags are given.
+var defaultTableFields = []string{"requests", "hits", "miss", "errors", "bandwidth"}
+
 // HistoricalCommand exposes the Historical Stats API.
 type HistoricalCommand struct {
 	argparser.Base
 
 	Input       fastly.GetStatsInput
+	by          string
+	fields      []string
 	formatFlag  string
+	from        string
+	region      string
 	serviceName argparser.OptionalServiceNameID
+	to          string
 }
 
 // NewHistoricalCommand is the "stats historical" subcommand.
@@ -41,12 +57,13 @@ func NewHistoricalCommand(parent argparser.Registerer, g *global.Data) *Historic
 		Dst:         &c.serviceName.Value,
 	})
 
-	c.CmdClause.Flag("from", "From time, accepted formats at https://fastly.dev/reference/api/metrics-stats/historical-stats").StringVar(c.Input.From)
-	c.CmdClause.Flag("to", "To time").StringVar(c.Input.To)
-	c.CmdClause.Flag("by", "Aggregation period (minute/hour/day)").EnumVar(c.Input.By, "minute", "hour", "day")
-	c.CmdClause.Flag("region", "Filter by region ('stats regions' to list)").StringVar(c.Input.Region)
+	c.CmdClause.Flag("from", "From time: 'now', relative (e.g. -24h, -7d), a Unix timestamp, RFC3339 or YYYY-MM-DD (default -24h)").StringVar(&c.from)
+	c.CmdClause.Flag("to", "To time, in the same formats as --from (default now)").StringVar(&c.to)
+	c.CmdClause.Flag("by", "Aggregation period (minute/hour/day)").Default("day").EnumVar(&c.by, "minute", "hour", "day")
+	c.CmdClause.Flag("region", "Filter by region ('stats regions' to list)").StringVar(&c.region)
+	c.CmdClause.Flag("field", "Stats field to display (e.g. requests, hits, bandwidth). Can be repeated").StringsVar(&c.fields)
 
-	c.CmdClause.Flag("format", "Output format (json)").EnumVar(&c.formatFlag, "json")
+	c.CmdClause.Flag("format", "Output format (json, csv or table)").EnumVar(&c.formatFlag, "json", "csv", "table")
 
 	return &c
 }
@@ -61,7 +78,9 @@ func (c *HistoricalCommand) Exec(_ io.Reader, out io.Writer) error {
 		argparser.DisplayServiceID(serviceID, flag, source, out)
 	}
 
-	c.Input.Service = fastly.ToPointer(serviceID)
+	if err := c.constructInput(serviceID, time.Now()); err != nil {
+		return err
+	}
 
 	var envelope statsResponse
 	err = c.Globals.APIClient.GetStatsJSON(&c.Input, &envelope)
@@ -76,25 +95,73 @@ func (c *HistoricalCommand) Exec(_ io.Reader, out io.Writer) error {
 		return fmt.Errorf("non-success response: %s", envelope.Msg)
 	}
 
-	switch c.formatFlag {
+	if err := validateFields(c.fields, envelope.Data); err != nil {
+		return err
+	}
+
+	format := c.formatFlag
+	if format == "" && len(c.fields) > 0 {
+		format = "table"
+	}
+
+	switch format {
 	case "json":
-		err := writeBlocksJSON(out, serviceID, envelope.Data)
-		if err != nil {
-			c.Globals.ErrLog.AddWithContext(err, map[string]any{
-				"Service ID": serviceID,
-			})
+		err = writeBlocksJSON(out, serviceID, selectFields(envelope.Data, c.fields))
+	case "csv":
+		err = writeBlocksCSV(out, envelope.Data, c.fields)
+	case "table":
+		fields := c.fields
+		if len(fields) == 0 {
+			fields = defaultTableFields
 		}
-
+		writeBlocksTable(out, envelope.Data, fields)
 	default:
 		writeHeader(out, envelope.Meta)
-		err := writeBlocks(out, serviceID, envelope.Data)
+		err = writeBlocks(out, serviceID, envelope.Data)
+	}
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+		})
+	}
+
+	return nil
+}
+
+// constructInput validates the time range flags and populates the API input.
+func (c *HistoricalCommand) constructInput(serviceID string, now time.Time) error {
+	from, to := now.Add(-24*time.Hour), now
+	for _, f := range []struct {
+		flag  string
+		value string
+		dst   *time.Time
+	}{
+		{"from", c.from, &from},
+		{"to", c.to, &to},
+	} {
+		if f.value == "" {
+			continue
+		}
+		t, err := ParseTime(f.value, now)
 		if err != nil {
-			c.Globals.ErrLog.AddWithContext(err, map[string]any{
-				"Service ID": serviceID,
-			})
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("invalid --%s: %w", f.flag, err),
+				Remediation: timeRemediation,
+			}
 		}
+		*f.dst = t
+	}
+	if err := ValidateRange(from, to, c.by); err != nil {
+		return err
 	}
 
+	c.Input.Service = fastly.ToPointer(serviceID)
+	c.Input.From = fastly.ToPointer(strconv.FormatInt(from.Unix(), 10))
+	c.Input.To = fastly.ToPointer(strconv.FormatInt(to.Unix(), 10))
+	c.Input.By = fastly.ToPointer(c.by)
+	if c.region != "" {
+		c.Input.Region = fastly.ToPointer(c.region)
+	}
 	return nil
 }
 
@@ -116,6 +183,123 @@ func writeBlocks(out io.Writer, service string, blocks []statsResponseData) erro
 	return nil
 }
 
+func writeBlocksCSV(out io.Writer, blocks []statsResponseData, fields []string) error {
+	if len(fields) == 0 {
+		fields = allFields(blocks)
+	}
+
+	w := csv.NewWriter(out)
+	if err := w.Write(append([]string{"start_time"}, fields...)); err != nil {
+		return err
+	}
+	for _, block := range blocks {
+		record := []string{formatStartTimeCSV(block["start_time"])}
+		for _, f := range fields {
+			record = append(record, formatValue(block[f]))
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func writeBlocksTable(out io.Writer, blocks []statsResponseData, fields []string) {
+	tw := text.NewTable(out)
+	header := []any{"START TIME"}
+	for _, f := range fields {
+		header = append(header, strings.ToUpper(f))
+	}
+	tw.AddHeader(header...)
+	for _, block := range blocks {
+		row := []any{formatStartTime(block["start_time"])}
+		for _, f := range fields {
+			row = append(row, formatValue(block[f]))
+		}
+		tw.AddLine(row...)
+	}
+	tw.Print()
+}
+
+// validateFields checks each selected field exists in the returned stats.
+func validateFields(fields []string, blocks []statsResponseData) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+	for _, f := range fields {
+		if _, ok := blocks[0][f]; !ok {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("unknown stats field: %s", f),
+				Remediation: fmt.Sprintf("Available fields: %s", strings.Join(allFields(blocks), ", ")),
+			}
+		}
+	}
+	return nil
+}
+
+// selectFields returns the blocks reduced to the start time and given fields.
+func selectFields(blocks []statsResponseData, fields []string) []statsResponseData {
+	if len(fields) == 0 {
+		return blocks
+	}
+	selected := make([]statsResponseData, 0, len(blocks))
+	for _, block := range blocks {
+		b := statsResponseData{"start_time": block["start_time"]}
+		for _, f := range fields {
+			b[f] = block[f]
+		}
+		selected = append(selected, b)
+	}
+	return selected
+}
+
+// allFields returns every field (other than the start time) in sorted order.
+func allFields(blocks []statsResponseData) []string {
+	seen := make(map[string]bool)
+	var fields []string
+	for _, block := range blocks {
+		for k := range block {
+			if k != "start_time" && !seen[k] {
+				seen[k] = true
+				fields = append(fields, k)
+			}
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func formatStartTime(v any) string {
+	if st, ok := v.(float64); ok {
+		t := time.Unix(int64(st), 0)
+		return text.FormatTimeLayout(&t, time.RFC3339)
+	}
+	return formatValue(v)
+}
+
+// formatStartTimeCSV formats the start time as RFC3339 in UTC, whatever the
+// display settings, as CSV output is meant to be parsed.
+func formatStartTimeCSV(v any) string {
+	if st, ok := v.(float64); ok {
+		return time.Unix(int64(st), 0).UTC().Format(time.RFC3339)
+	}
+	return formatValue(v)
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
 func writeBlocksJSON(out io.Writer, _ string, blocks []statsResponseData) error {
 	for _, block := range blocks {
 		if err := json.NewEncoder(out).Encode(block); err != nil {
@@ -6,10 +6,12 @@ import (
 	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/fastly/go-fastly/v9/fastly"
 
 	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/stats"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/mock"
 	"github.com/fastly/cli/pkg/testutil"
@@ -38,6 +40,33 @@ func TestHistorical(t *testing.T) {
 			api:        mock.API{GetStatsJSONFn: getStatsJSONOK},
 			wantOutput: historicalJSONOK,
 		},
+		{
+			args:       args("stats historical --service-id=123 --format=csv --field=requests --field=customer"),
+			api:        mock.API{GetStatsJSONFn: getStatsJSONFields},
+			wantOutput: "start_time,requests,customer\n2021-01-01T00:00:00Z,42,\"Foo, Inc\"\n",
+		},
+		{
+			args:       args("stats historical --service-id=123 --field=requests"),
+			api:        mock.API{GetStatsJSONFn: getStatsJSONFields},
+			wantOutput: "START TIME            REQUESTS\n2021-01-01T00:00:00Z  42\n",
+		},
+		{
+			args:      args("stats historical --service-id=123 --field=nope"),
+			api:       mock.API{GetStatsJSONFn: getStatsJSONFields},
+			wantError: "unknown stats field: nope",
+		},
+		{
+			args:      args("stats historical --service-id=123 --by=minute --from=-48h"),
+			wantError: "too long for --by minute",
+		},
+		{
+			args:      args("stats historical --service-id=123 --from=-1h --to=-2h"),
+			wantError: "must be before --to",
+		},
+		{
+			args:      args("stats historical --service-id=123 --from=yesterday"),
+			wantError: "invalid --from",
+		},
 	}
 	for testcaseIdx := range scenarios {
 		testcase := &scenarios[testcaseIdx]
@@ -104,6 +133,57 @@ func getStatsJSONOK(_ *fastly.GetStatsInput, o any) error {
 	return json.Unmarshal(msg, o)
 }
 
+func getStatsJSONFields(_ *fastly.GetStatsInput, o any) error {
+	msg := []byte(`
+{
+  "status": "success",
+  "meta": {},
+  "msg": null,
+  "data": [{"start_time": 1609459200, "requests": 42, "customer": "Foo, Inc"}]
+}`)
+
+	return json.Unmarshal(msg, o)
+}
+
 func getStatsJSONError(_ *fastly.GetStatsInput, _ any) error {
 	return errTest
 }
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2021, 1, 10, 12, 0, 0, 0, time.UTC)
+	scenarios := []struct {
+		in        string
+		want      time.Time
+		wantError bool
+	}{
+		{in: "now", want: now},
+		{in: "-24h", want: now.Add(-24 * time.Hour)},
+		{in: "-1d12h", want: now.Add(-36 * time.Hour)},
+		{in: "-2w", want: now.Add(-14 * 24 * time.Hour)},
+		{in: "-0h", want: now},
+		{in: "1609459200", want: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{in: "2021-01-02T03:04:05Z", want: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{in: "2021-01-02", want: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{in: "-24", wantError: true},
+		{in: "24h", wantError: true},
+		{in: "-h", wantError: true},
+		{in: "", wantError: true},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.in, func(t *testing.T) {
+			got, err := stats.ParseTime(testcase.in, now)
+			if testcase.wantError {
+				if err == nil {
+					t.Fatalf("want error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(testcase.want) {
+				t.Errorf("want %v, got %v", testcase.want, got)
+			}
+		})
+	}
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/fastly/go-fastly/v9/fastly"
 	"github.com/mitchellh/mapstructure"
+
+	"github.com/fastly/cli/pkg/text"
 )
 
 var blockTemplate = template.Must(template.New("stats_block").Parse(
@@ -59,7 +61,7 @@ func fmtBlock(out io.Writer, service string, block statsResponseData) error {
 
 	values := map[string]string{
 		"ServiceID":   fmt.Sprintf("%30s", service),
-		"StartTime":   fmt.Sprintf("%30s", startTime),
+		"StartTime":   fmt.Sprintf("%30s", text.FormatTime(&startTime)),
 		"HitRate":     fmt.Sprintf("%29.2f%%", hitRate*100),
 		"AvgHitTime":  fmt.Sprintf("%28.2f\u00b5s", fastly.ToValue(agg.HitsTime)*1000),
 		"AvgMissTime": fmt.Sprintf("%28.2f\u00b5s", fastly.ToValue(agg.MissTime)*1000),
@@ -0,0 +1,93 @@
+package stats
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// MaxRange is the longest time range the historical stats API accepts for
+// each aggregation period. Periods not listed are unlimited.
+var MaxRange = map[string]time.Duration{
+	"minute": 24 * time.Hour,
+	"hour":   31 * 24 * time.Hour,
+}
+
+// relativeUnits are the units accepted in relative times (e.g. -7d).
+var relativeUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+var (
+	relativeTime = regexp.MustCompile(`^-((?:\d+[smhdw])+)$`)
+	relativePart = regexp.MustCompile(`(\d+)([smhdw])`)
+)
+
+// ParseTime parses an absolute or relative time.
+//
+// Accepted values are "now", a relative offset into the past made of one or
+// more units (e.g. -24h, -7d, -1d12h), a Unix timestamp in seconds, an RFC3339
+// timestamp, or a date (YYYY-MM-DD, interpreted as midnight UTC).
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "":
+		return time.Time{}, fmt.Errorf("empty time")
+	case s == "now":
+		return now, nil
+	case strings.HasPrefix(s, "-"):
+		m := relativeTime.FindStringSubmatch(s)
+		if m == nil {
+			return time.Time{}, fmt.Errorf("invalid relative time %q", s)
+		}
+		var d time.Duration
+		for _, part := range relativePart.FindAllStringSubmatch(m[1], -1) {
+			n, err := strconv.Atoi(part[1])
+			if err != nil {
+				return time.Time{}, fmt.Errorf("invalid relative time %q: %w", s, err)
+			}
+			d += time.Duration(n) * relativeUnits[part[2]]
+		}
+		return now.Add(-d), nil
+	}
+
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(n, 0).UTC(), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
+
+// timeRemediation explains the accepted time formats.
+const timeRemediation = "Use 'now', a relative time in the past such as -30m, -24h, -7d or -1d12h (units: s, m, h, d, w), a Unix timestamp, an RFC3339 timestamp (e.g. 2024-01-02T15:04:05Z) or a date (e.g. 2024-01-02)."
+
+// ValidateRange checks the range is in order and acceptable for the
+// aggregation period.
+func ValidateRange(from, to time.Time, by string) error {
+	if !from.Before(to) {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid time range: --from (%s) must be before --to (%s)", from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339)),
+			Remediation: "Swap the --from and --to values.",
+		}
+	}
+	if limit, ok := MaxRange[by]; ok && to.Sub(from) > limit {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("the time range (%s) is too long for --by %s (maximum %s)", to.Sub(from), by, limit),
+			Remediation: "Per-minute stats can be requested for at most 1 day and per-hour stats for at most 31 days. Narrow the --from/--to range, or use a coarser --by value (e.g. --by day).",
+		}
+	}
+	return nil
+}
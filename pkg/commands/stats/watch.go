@@ -0,0 +1,395 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// ThresholdMetrics are the metrics a --threshold can be set on, mapped to a
+// description of what the limit is compared against.
+var ThresholdMetrics = map[string]string{
+	"errors":      "errors per second",
+	"error_ratio": "percentage of requests that errored",
+	"p95":         "p95 miss latency in milliseconds",
+	"requests":    "requests per second",
+}
+
+// WatchCommand displays a live dashboard of realtime stats.
+type WatchCommand struct {
+	argparser.Base
+
+	duration    time.Duration
+	interval    time.Duration
+	serviceName argparser.OptionalServiceNameID
+	thresholds  []string
+}
+
+// NewWatchCommand is the "stats watch" subcommand.
+func NewWatchCommand(parent argparser.Registerer, g *global.Data) *WatchCommand {
+	var c WatchCommand
+	c.Globals = g
+
+	c.CmdClause = parent.Command("watch", "Display a live dashboard of realtime stats for a Fastly service")
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Name:        argparser.FlagServiceIDName,
+		Description: argparser.FlagServiceIDDesc,
+		Dst:         &g.Manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        argparser.FlagServiceName,
+		Description: argparser.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+
+	c.CmdClause.Flag("duration", "Stop watching after this long (e.g. 5m). Watches until interrupted when unset").DurationVar(&c.duration)
+	c.CmdClause.Flag("interval", "How often to refresh the dashboard").Default("1s").DurationVar(&c.interval)
+	c.CmdClause.Flag("threshold", "Exit with an error when a metric exceeds a limit, e.g. errors=10 (metrics: errors, error_ratio, p95, requests). Can be repeated").StringsVar(&c.thresholds)
+
+	return &c
+}
+
+// Interruptible implements the argparser.Interruptible interface. The dashboard
+// is refreshed until interrupted.
+func (c *WatchCommand) Interruptible() bool {
+	return true
+}
+
+// Exec implements the command interface.
+func (c *WatchCommand) Exec(_ io.Reader, out io.Writer) error {
+	thresholds := make([]Threshold, 0, len(c.thresholds))
+	for _, s := range c.thresholds {
+		t, err := ParseThreshold(s)
+		if err != nil {
+			return err
+		}
+		thresholds = append(thresholds, t)
+	}
+
+	serviceID, source, flag, err := argparser.ServiceID(c.serviceName, *c.Globals.Manifest, c.Globals.APIClient, c.Globals.ErrLog)
+	if err != nil {
+		return err
+	}
+	if c.Globals.Verbose() {
+		argparser.DisplayServiceID(serviceID, flag, source, out)
+	}
+
+	w := &Watcher{
+		Client:     c.Globals.RTSClient,
+		Clock:      RealClock{},
+		Duration:   c.duration,
+		Interval:   c.interval,
+		ServiceID:  serviceID,
+		Thresholds: thresholds,
+	}
+	if err := w.Run(c.Globals.Context, NewDashboard(out, serviceID, text.IsTTY(out))); err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID": serviceID,
+		})
+		return err
+	}
+	return argparser.PartialResults(c.Globals)
+}
+
+// Clock abstracts time so the watch loop can be driven by tests.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// RealClock is the Clock used outside of tests.
+type RealClock struct{}
+
+// Now implements Clock.
+func (RealClock) Now() time.Time { return time.Now() }
+
+// After implements Clock.
+func (RealClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Watcher polls the realtime stats API and renders each sample.
+type Watcher struct {
+	Client     api.RealtimeStatsInterface
+	Clock      Clock
+	Duration   time.Duration
+	Interval   time.Duration
+	ServiceID  string
+	Thresholds []Threshold
+}
+
+// Run polls until ctx is cancelled, the duration elapses or a threshold is
+// breached. Fetch errors are displayed and polling continues.
+func (w *Watcher) Run(ctx context.Context, d *Dashboard) error {
+	start := w.Clock.Now()
+	var timestamp uint64
+
+	for {
+		var envelope realtimeResponse
+		err := w.Client.GetRealtimeStatsJSON(&fastly.GetRealtimeStatsInput{
+			ServiceID: w.ServiceID,
+			Timestamp: timestamp,
+		}, &envelope)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			d.RenderError(err)
+		case len(envelope.Data) > 0:
+			timestamp = envelope.Timestamp
+			s := newSample(w.Clock.Now(), envelope.Data)
+			d.Render(s)
+			for _, t := range w.Thresholds {
+				if v, breached := t.Breached(s); breached {
+					return fsterr.RemediationError{
+						Inner:       fmt.Errorf("threshold breached: %s is %s (limit %s)", t.Metric, formatFloat(v), formatFloat(t.Limit)),
+						Remediation: "Investigate the service before continuing, or raise the --threshold limit.",
+					}
+				}
+			}
+		}
+
+		if w.Duration > 0 && w.Clock.Now().Sub(start) >= w.Duration {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-w.Clock.After(w.Interval):
+		}
+	}
+}
+
+// Sample summarises the realtime stats returned by a single poll.
+type Sample struct {
+	Time      time.Time
+	Seconds   int
+	Requests  float64
+	Hits      float64
+	Misses    float64
+	Errors    float64
+	Bandwidth float64
+	// P95 is the 95th percentile miss latency. It is zero when the API
+	// returned no latency histogram.
+	P95 time.Duration
+}
+
+// newSample aggregates the per-second records of a realtime response.
+func newSample(now time.Time, data []realtimeResponseData) Sample {
+	s := Sample{Time: now, Seconds: len(data)}
+	histogram := make(map[float64]float64)
+
+	for _, record := range data {
+		agg := record.Aggregated
+		s.Requests += number(agg["requests"])
+		s.Hits += number(agg["hits"])
+		s.Misses += number(agg["miss"])
+		s.Errors += number(agg["errors"])
+		if bw, ok := agg["bandwidth"]; ok {
+			s.Bandwidth += number(bw)
+		} else {
+			s.Bandwidth += number(agg["resp_header_bytes"]) + number(agg["resp_body_bytes"])
+		}
+		if h, ok := agg["miss_histogram"].(map[string]any); ok {
+			for bucket, count := range h {
+				ms, err := strconv.ParseFloat(bucket, 64)
+				if err != nil {
+					continue
+				}
+				histogram[ms] += number(count)
+			}
+		}
+	}
+	s.P95 = percentile(histogram, 0.95)
+
+	return s
+}
+
+// RequestRate is the number of requests per second.
+func (s Sample) RequestRate() float64 {
+	return s.perSecond(s.Requests)
+}
+
+// ErrorRate is the number of errors per second.
+func (s Sample) ErrorRate() float64 {
+	return s.perSecond(s.Errors)
+}
+
+// BandwidthRate is the number of bytes delivered per second.
+func (s Sample) BandwidthRate() float64 {
+	return s.perSecond(s.Bandwidth)
+}
+
+// HitRatio is the percentage of cacheable lookups that were hits.
+func (s Sample) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return s.Hits / (s.Hits + s.Misses) * 100
+}
+
+// ErrorRatio is the percentage of requests that errored.
+func (s Sample) ErrorRatio() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+	return s.Errors / s.Requests * 100
+}
+
+func (s Sample) perSecond(v float64) float64 {
+	if s.Seconds == 0 {
+		return 0
+	}
+	return v / float64(s.Seconds)
+}
+
+// Threshold is a limit on a metric which fails the watch when exceeded.
+type Threshold struct {
+	Metric string
+	Limit  float64
+}
+
+// ParseThreshold parses a threshold in the form <metric>=<limit>.
+func ParseThreshold(s string) (Threshold, error) {
+	metric, limit, ok := strings.Cut(s, "=")
+	if !ok {
+		return Threshold{}, fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid threshold %q", s),
+			Remediation: "Thresholds must be in the form <metric>=<limit>, e.g. --threshold errors=10.",
+		}
+	}
+	metric = strings.TrimSpace(metric)
+	if _, ok := ThresholdMetrics[metric]; !ok {
+		return Threshold{}, fsterr.RemediationError{
+			Inner:       fmt.Errorf("unknown threshold metric %q", metric),
+			Remediation: fmt.Sprintf("Supported metrics are: %s.", strings.Join(thresholdMetricNames(), ", ")),
+		}
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(limit), 64)
+	if err != nil || n < 0 {
+		return Threshold{}, fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid threshold limit %q", limit),
+			Remediation: fmt.Sprintf("The limit for %s must be a non-negative number (%s).", metric, ThresholdMetrics[metric]),
+		}
+	}
+	return Threshold{Metric: metric, Limit: n}, nil
+}
+
+// Breached returns the sample's value for the metric and whether it exceeds
+// the limit.
+func (t Threshold) Breached(s Sample) (float64, bool) {
+	var v float64
+	switch t.Metric {
+	case "errors":
+		v = s.ErrorRate()
+	case "error_ratio":
+		v = s.ErrorRatio()
+	case "p95":
+		v = float64(s.P95) / float64(time.Millisecond)
+	case "requests":
+		v = s.RequestRate()
+	}
+	return v, v > t.Limit
+}
+
+func thresholdMetricNames() []string {
+	names := make([]string, 0, len(ThresholdMetrics))
+	for name := range ThresholdMetrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Dashboard renders samples. On a terminal the screen is redrawn in place,
+// otherwise each sample is appended as a single line.
+type Dashboard struct {
+	out       io.Writer
+	serviceID string
+	tty       bool
+}
+
+// NewDashboard returns a dashboard writing to out.
+func NewDashboard(out io.Writer, serviceID string, tty bool) *Dashboard {
+	return &Dashboard{out: out, serviceID: serviceID, tty: tty}
+}
+
+// Render displays a sample.
+func (d *Dashboard) Render(s Sample) {
+	p95 := "n/a"
+	if s.P95 > 0 {
+		p95 = s.P95.String()
+	}
+	ts := s.Time.UTC().Format(time.RFC3339)
+
+	if !d.tty {
+		fmt.Fprintf(d.out, "%s requests/s=%.1f hit_ratio=%.2f%% errors/s=%.1f bandwidth=%s/s p95=%s\n",
+			ts, s.RequestRate(), s.HitRatio(), s.ErrorRate(), text.Bytes(s.BandwidthRate()), p95)
+		return
+	}
+
+	// Move the cursor home and clear the screen before redrawing.
+	fmt.Fprint(d.out, "\x1b[H\x1b[2J")
+	fmt.Fprintf(d.out, "Service ID: %s    %s    (Ctrl-C to exit)\n\n", d.serviceID, ts)
+	fmt.Fprintf(d.out, "Requests/sec:  %12.1f\n", s.RequestRate())
+	fmt.Fprintf(d.out, "Hit ratio:     %11.2f%%\n", s.HitRatio())
+	fmt.Fprintf(d.out, "Errors/sec:    %12.1f\n", s.ErrorRate())
+	fmt.Fprintf(d.out, "Bandwidth:     %10s/s\n", text.Bytes(s.BandwidthRate()))
+	fmt.Fprintf(d.out, "p95 latency:   %12s\n", p95)
+}
+
+// RenderError displays a failure to fetch stats.
+func (d *Dashboard) RenderError(err error) {
+	if d.tty {
+		fmt.Fprint(d.out, "\x1b[H\x1b[2J")
+	}
+	text.Error(d.out, "fetching stats: %s", err)
+}
+
+// percentile returns the latency below which the given fraction of the
+// histogram's requests fell. Buckets are keyed by latency in milliseconds.
+func percentile(histogram map[float64]float64, p float64) time.Duration {
+	var total float64
+	buckets := make([]float64, 0, len(histogram))
+	for bucket, count := range histogram {
+		buckets = append(buckets, bucket)
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+	sort.Float64s(buckets)
+
+	var cumulative float64
+	for _, bucket := range buckets {
+		cumulative += histogram[bucket]
+		if cumulative >= total*p {
+			return time.Duration(bucket * float64(time.Millisecond))
+		}
+	}
+	return time.Duration(buckets[len(buckets)-1] * float64(time.Millisecond))
+}
+
+func number(v any) float64 {
+	if n, ok := v.(float64); ok {
+		return n
+	}
+	return 0
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,154 @@
+package stats_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/commands/stats"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+// scriptedRTS returns one scripted response per call.
+type scriptedRTS struct {
+	responses []string
+	calls     int
+}
+
+func (s *scriptedRTS) GetRealtimeStatsJSON(_ *fastly.GetRealtimeStatsInput, o any) error {
+	if s.calls >= len(s.responses) {
+		return errTest
+	}
+	resp := s.responses[s.calls]
+	s.calls++
+	return json.Unmarshal([]byte(resp), o)
+}
+
+// fakeClock advances by the requested duration whenever After is called.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+const (
+	sampleOK = `{"timestamp": 1, "data": [
+		{"recorded": 1, "aggregated": {"requests": 100, "hits": 90, "miss": 10, "errors": 0, "bandwidth": 2000000, "miss_histogram": {"10": 90, "50": 5, "200": 5}}},
+		{"recorded": 2, "aggregated": {"requests": 100, "hits": 90, "miss": 10, "errors": 2, "bandwidth": 2000000}}
+	]}`
+	sampleErrors = `{"timestamp": 2, "data": [
+		{"recorded": 3, "aggregated": {"requests": 100, "hits": 50, "miss": 50, "errors": 40, "bandwidth": 1000}}
+	]}`
+)
+
+func TestWatch(t *testing.T) {
+	scenarios := []struct {
+		name       string
+		responses  []string
+		duration   time.Duration
+		thresholds []string
+		tty        bool
+		wantError  string
+		wantOutput []string
+		wantCalls  int
+	}{
+		{
+			name:      "stops after duration",
+			responses: []string{sampleOK, sampleOK, sampleOK, sampleOK},
+			duration:  3 * time.Second,
+			wantOutput: []string{
+				"2021-01-01T00:00:00Z requests/s=100.0 hit_ratio=90.00% errors/s=1.0 bandwidth=2.0 MB/s p95=50ms\n",
+				"2021-01-01T00:00:03Z requests/s=100.0",
+			},
+			wantCalls: 4,
+		},
+		{
+			name:       "threshold breached",
+			responses:  []string{sampleOK, sampleErrors, sampleOK},
+			thresholds: []string{"errors=10"},
+			wantError:  "threshold breached: errors is 40 (limit 10)",
+			wantCalls:  2,
+		},
+		{
+			name:       "fetch errors are displayed",
+			responses:  []string{`{"timestamp": 1, "data": []}`},
+			duration:   2 * time.Second,
+			wantOutput: []string{"ERROR: fetching stats: " + errTest.Error()},
+			wantCalls:  1,
+		},
+		{
+			name:      "tty redraws in place",
+			responses: []string{sampleOK, sampleOK},
+			duration:  time.Second,
+			tty:       true,
+			wantOutput: []string{
+				"\x1b[H\x1b[2JService ID: 123",
+				"Hit ratio:           90.00%\n",
+				"p95 latency:           50ms\n",
+			},
+			wantCalls: 2,
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			var thresholds []stats.Threshold
+			for _, s := range testcase.thresholds {
+				th, err := stats.ParseThreshold(s)
+				if err != nil {
+					t.Fatal(err)
+				}
+				thresholds = append(thresholds, th)
+			}
+
+			client := &scriptedRTS{responses: testcase.responses}
+			w := &stats.Watcher{
+				Client:     client,
+				Clock:      &fakeClock{now: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+				Duration:   testcase.duration,
+				Interval:   time.Second,
+				ServiceID:  "123",
+				Thresholds: thresholds,
+			}
+
+			var out bytes.Buffer
+			err := w.Run(context.Background(), stats.NewDashboard(&out, "123", testcase.tty))
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			for _, s := range testcase.wantOutput {
+				testutil.AssertStringContains(t, out.String(), s)
+			}
+			if client.calls != testcase.wantCalls {
+				t.Errorf("want %d calls, got %d", testcase.wantCalls, client.calls)
+			}
+			if !testcase.tty && strings.Contains(out.String(), "\x1b[H") {
+				t.Errorf("unexpected redraw in non-TTY output: %q", out.String())
+			}
+		})
+	}
+}
+
+func TestParseThreshold(t *testing.T) {
+	for _, s := range []string{"errors", "bogus=1", "errors=abc", "errors=-1"} {
+		if _, err := stats.ParseThreshold(s); err == nil {
+			t.Errorf("ParseThreshold(%q): want error", s)
+		}
+	}
+	th, err := stats.ParseThreshold("error_ratio=2.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if th.Metric != "error_ratio" || th.Limit != 2.5 {
+		t.Errorf("unexpected threshold: %+v", th)
+	}
+}
@@ -0,0 +1,376 @@
+package supportbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	toml "github.com/pelletier/go-toml"
+
+	"github.com/fastly/cli/pkg/config"
+	"github.com/fastly/cli/pkg/env"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/github"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/revision"
+)
+
+// Redacted replaces a sensitive value.
+const Redacted = "REDACTED"
+
+// IndexFile is the file of the bundle that lists what was included, and what
+// was left out and why.
+const IndexFile = "bundle.json"
+
+// MaxErrorLogRecords is the number of the most recent invocations whose error
+// log records are included.
+const MaxErrorLogRecords = 5
+
+// MaxTraceSize is the size of the largest trace file included.
+const MaxTraceSize = 1 << 20
+
+// errorLogSeparator ends the records of each invocation in the error log (see
+// fsterr.LogEntries.Persist).
+const errorLogSeparator = "------------------------------\n\n"
+
+// Entry is a file of the bundle, or an item that was left out of it.
+type Entry struct {
+	// File is the name of the file in the bundle.
+	File string `json:"file"`
+	// Description describes the contents of the file.
+	Description string `json:"description"`
+	// Size is the size of the file in bytes.
+	Size int `json:"size"`
+	// Excluded is the reason the file was left out of the bundle (if it was).
+	Excluded string `json:"excluded,omitempty"`
+
+	content []byte
+}
+
+// Bundle is the collected files, which are redacted as they're added.
+type Bundle struct {
+	Entries []Entry
+
+	// secrets are the values redacted wherever they appear.
+	secrets []string
+}
+
+// add redacts the content and adds it to the bundle.
+func (b *Bundle) add(file, description string, content []byte) {
+	content = b.redact(content)
+	b.Entries = append(b.Entries, Entry{
+		File:        file,
+		Description: description,
+		Size:        len(content),
+		content:     content,
+	})
+}
+
+// exclude records that an item was left out of the bundle.
+func (b *Bundle) exclude(file, description, reason string) {
+	b.Entries = append(b.Entries, Entry{
+		File:        file,
+		Description: description,
+		Excluded:    reason,
+	})
+}
+
+// Included returns the entries added to the bundle.
+func (b *Bundle) Included() []Entry {
+	var entries []Entry
+	for _, e := range b.Entries {
+		if e.Excluded == "" {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+var (
+	// sensitiveKeyRegEx matches a line that assigns a value to a key ending
+	// in e.g. token or secret, as in a TOML file or an env var.
+	sensitiveKeyRegEx = regexp.MustCompile(`(?im)^(\s*["']?[\w.-]*(?:token|secret|password|passphrase|key)["']?\s*[=:]\s*)(\S.*)$`)
+	// userinfoRegEx matches the credentials of a URL, e.g. a proxy.
+	userinfoRegEx = regexp.MustCompile(`(://)[^/@\s:]+:[^/@\s]+@`)
+)
+
+// redact removes sensitive values from the content: the known secrets (e.g.
+// the profiles' tokens), anything fsterr.FilterToken recognises, the values of
+// sensitive keys and the credentials of URLs.
+func (b *Bundle) redact(content []byte) []byte {
+	s := string(content)
+	for _, secret := range b.secrets {
+		s = strings.ReplaceAll(s, secret, Redacted)
+	}
+	s = fsterr.FilterToken(s)
+	s = sensitiveKeyRegEx.ReplaceAllStringFunc(s, func(line string) string {
+		m := sensitiveKeyRegEx.FindStringSubmatch(line)
+		if strings.HasPrefix(m[2], `"`) {
+			return m[1] + `"` + Redacted + `"`
+		}
+		return m[1] + Redacted
+	})
+	s = userinfoRegEx.ReplaceAllString(s, "${1}"+Redacted+"@")
+	return []byte(s)
+}
+
+// addVersion adds the CLI version and the platform it's running on.
+func (b *Bundle) addVersion() {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "Fastly CLI version %s (%s)\n", revision.AppVersion, revision.GitCommit)
+	fmt.Fprintf(&buf, "Built with %s\n", revision.GoVersion)
+	fmt.Fprintf(&buf, "Platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	b.add("version.txt", "CLI version and platform", buf.Bytes())
+}
+
+// addConfig adds the effective config, i.e. the config file as read along
+// with any changes made to it by the CLI (e.g. migrating old settings).
+func (b *Bundle) addConfig(cfg config.File) {
+	const description = "CLI config (tokens removed)"
+	// NOTE: The profiles are copied so the tokens of the config in use aren't
+	// changed.
+	profiles := make(config.Profiles, len(cfg.Profiles))
+	for name, p := range cfg.Profiles {
+		if p == nil {
+			continue
+		}
+		cp := *p
+		for _, token := range []*string{&cp.AccessToken, &cp.RefreshToken, &cp.Token} {
+			if *token != "" {
+				b.secrets = append(b.secrets, *token)
+				*token = Redacted
+			}
+		}
+		profiles[name] = &cp
+	}
+	cfg.Profiles = profiles
+	data, err := toml.Marshal(cfg)
+	if err != nil {
+		b.exclude(config.FileName, description, fmt.Sprintf("the config couldn't be encoded: %s", err))
+		return
+	}
+	b.add(config.FileName, description, data)
+}
+
+// addEnv adds the env vars read by the CLI (see env.Registry) that are set.
+func (b *Bundle) addEnv() {
+	var buf bytes.Buffer
+	for _, name := range env.Registry {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if env.Secrets[name] && value != "" {
+			b.secrets = append(b.secrets, value)
+			value = Redacted
+		}
+		fmt.Fprintf(&buf, "%s=%s\n", name, value)
+	}
+	if buf.Len() == 0 {
+		buf.WriteString("# None of the Fastly CLI environment variables are set.\n")
+	}
+	b.add("env.txt", "Fastly CLI environment variables", buf.Bytes())
+}
+
+// addErrorLog adds the error log records of the most recent invocations.
+func (b *Bundle) addErrorLog(path string) {
+	const (
+		file        = "errors.log"
+		description = "Recent error log entries"
+	)
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	//
+	// Disabling as the path is the CLI's own error log.
+	/* #nosec */
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		b.exclude(file, description, "no errors have been logged")
+		return
+	case err != nil:
+		b.exclude(file, description, fmt.Sprintf("the error log couldn't be read: %s", err))
+		return
+	}
+	records := strings.SplitAfter(string(data), errorLogSeparator)
+	if records[len(records)-1] == "" {
+		records = records[:len(records)-1]
+	}
+	if len(records) > MaxErrorLogRecords {
+		records = records[len(records)-MaxErrorLogRecords:]
+	}
+	b.add(file, description, []byte(strings.Join(records, "")))
+}
+
+// addManifest adds the project's fastly.toml manifest. The values of the
+// local server's secret stores are redacted, as read into m.
+func (b *Bundle) addManifest(path string, m *manifest.File, include bool, reason string) {
+	const description = "Compute project manifest"
+	file := filepath.Base(path)
+	if !include {
+		b.exclude(file, description, reason)
+		return
+	}
+	for _, store := range m.LocalServer.SecretStores {
+		for _, s := range store {
+			if s.Data != "" {
+				b.secrets = append(b.secrets, s.Data)
+			}
+		}
+	}
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	//
+	// Disabling as the path is the manifest in the current directory.
+	/* #nosec */
+	data, err := os.ReadFile(path)
+	if err != nil {
+		b.exclude(file, description, fmt.Sprintf("the manifest couldn't be read: %s", err))
+		return
+	}
+	b.add(file, description, data)
+}
+
+// Toolchain is a tool whose version is reported.
+type Toolchain struct {
+	// Name is the name of the tool's binary.
+	Name string
+	// Args are the arguments that print its version.
+	Args []string
+}
+
+// Toolchains are the tools used to build Compute projects, and run them
+// locally.
+var Toolchains = []Toolchain{
+	{Name: "rustc", Args: []string{"--version"}},
+	{Name: "cargo", Args: []string{"--version"}},
+	{Name: "go", Args: []string{"version"}},
+	{Name: "tinygo", Args: []string{"version"}},
+	{Name: "node", Args: []string{"--version"}},
+	{Name: "npm", Args: []string{"--version"}},
+	{Name: "viceroy", Args: []string{"--version"}},
+	{Name: "wasm-tools", Args: []string{"--version"}},
+}
+
+// ToolchainVersion returns the output of the tool's version command. It's a
+// variable so tests can fake it.
+var ToolchainVersion = func(tc Toolchain) (string, error) {
+	bin, err := exec.LookPath(tc.Name)
+	if err != nil {
+		// NOTE: The CLI installs viceroy and wasm-tools in its own directory.
+		bin = filepath.Join(github.InstallDir, tc.Name)
+		if _, statErr := os.Stat(bin); statErr != nil {
+			return "", err
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// gosec flagged this:
+	// G204 (CWE-78): Subprocess launched with variable
+	// Disabling as the binaries are from our own list of toolchains.
+	/* #nosec */
+	// nosemgrep
+	out, err := exec.CommandContext(ctx, bin, tc.Args...).CombinedOutput()
+	return strings.TrimSpace(string(out)), err
+}
+
+// addToolchains adds the versions of the Toolchains.
+func (b *Bundle) addToolchains() {
+	var buf bytes.Buffer
+	for _, tc := range Toolchains {
+		version, err := ToolchainVersion(tc)
+		switch {
+		case errors.Is(err, exec.ErrNotFound):
+			version = "not found"
+		case err != nil:
+			version = fmt.Sprintf("error: %s", err)
+		}
+		fmt.Fprintf(&buf, "%s: %s\n", tc.Name, version)
+	}
+	b.add("toolchains.txt", "Toolchain versions", buf.Bytes())
+}
+
+// addTrace adds the trace file, which records the API requests made by the
+// last command (see env.TraceFile).
+func (b *Bundle) addTrace(path string) {
+	const (
+		file        = "trace.json"
+		description = "API requests of the last command"
+	)
+	if path == "" {
+		b.exclude(file, description, fmt.Sprintf("tracing isn't enabled (see %s)", env.TraceFile))
+		return
+	}
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	//
+	// Disabling as the path is the user's own trace file.
+	/* #nosec */
+	data, err := os.ReadFile(path)
+	switch {
+	case err != nil:
+		b.exclude(file, description, fmt.Sprintf("the trace file couldn't be read: %s", err))
+	case len(data) > MaxTraceSize:
+		b.exclude(file, description, fmt.Sprintf("the trace file is larger than %d bytes", MaxTraceSize))
+	default:
+		b.add(file, description, data)
+	}
+}
+
+// Write writes the bundle to path as a .tar.gz archive, including the
+// IndexFile.
+func (b *Bundle) Write(path string, now time.Time) (err error) {
+	index, err := json.MarshalIndent(b.Entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	//
+	// Disabling as the path is provided by the user.
+	/* #nosec */
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	files := append([]Entry{{File: IndexFile, content: append(index, '\n')}}, b.Included()...)
+	for _, e := range files {
+		hdr := &tar.Header{
+			Name:    e.File,
+			Mode:    0o600,
+			Size:    int64(len(e.content)),
+			ModTime: now,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(e.content); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
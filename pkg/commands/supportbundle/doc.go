@@ -0,0 +1,4 @@
+// Package supportbundle contains a command that collects the details needed
+// to diagnose a bug report (e.g. the CLI version, its config and recent
+// errors) into a single archive, with sensitive values redacted.
+package supportbundle
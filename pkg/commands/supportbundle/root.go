@@ -0,0 +1,134 @@
+package supportbundle
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/filesystem"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// IssueURL is where a bug report (and the bundle) is filed.
+const IssueURL = "https://github.com/fastly/cli/issues/new?labels=bug&template=bug_report.md"
+
+// ErrManifestFlagCombo is returned when both --include-manifest and
+// --exclude-manifest are set.
+var ErrManifestFlagCombo = fsterr.RemediationError{
+	Inner:       errors.New("--include-manifest and --exclude-manifest are mutually exclusive"),
+	Remediation: "Set one of the flags, or neither to be prompted.",
+}
+
+// RootCommand writes a support bundle.
+type RootCommand struct {
+	argparser.Base
+
+	excludeManifest bool
+	includeManifest bool
+	output          string
+}
+
+// NewRootCommand returns a new command registered in the parent.
+func NewRootCommand(parent argparser.Registerer, g *global.Data) *RootCommand {
+	var c RootCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("support-bundle", "Write an archive of the details needed to diagnose a bug report (e.g. the CLI version, config and recent errors), with tokens and other secrets redacted")
+	c.CmdClause.Flag("exclude-manifest", "Leave the fastly.toml manifest out of the bundle without prompting").BoolVar(&c.excludeManifest)
+	c.CmdClause.Flag("include-manifest", "Include the fastly.toml manifest in the bundle without prompting").BoolVar(&c.includeManifest)
+	c.CmdClause.Flag("output", "Path of the archive to write (default: fastly-support-<timestamp>.tar.gz)").StringVar(&c.output)
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *RootCommand) Exec(in io.Reader, out io.Writer) error {
+	if c.includeManifest && c.excludeManifest {
+		return ErrManifestFlagCombo
+	}
+	now := Now()
+	path := c.output
+	if path == "" {
+		path = fmt.Sprintf("fastly-support-%s.tar.gz", now.UTC().Format("20060102T150405Z"))
+	}
+
+	var b Bundle
+	if c.Globals.Flags.Token != "" {
+		b.secrets = append(b.secrets, c.Globals.Flags.Token)
+	}
+	b.addVersion()
+	b.addConfig(c.Globals.Config)
+	b.addEnv()
+	b.addErrorLog(fsterr.LogPath)
+	include, reason, err := c.manifestChoice(in, out)
+	if err != nil {
+		return err
+	}
+	b.addManifest(manifest.Filename, &c.Globals.Manifest.File, include, reason)
+	b.addToolchains()
+	b.addTrace(c.Globals.Env.TraceFile)
+
+	text.Info(out, "The support bundle contains the following files, with tokens and other secrets redacted:")
+	text.Break(out)
+	t := text.NewTable(out)
+	t.AddHeader("FILE", "DESCRIPTION", "INCLUDED")
+	for _, e := range b.Entries {
+		included := fmt.Sprintf("yes (%d bytes)", e.Size)
+		if e.Excluded != "" {
+			included = "no: " + e.Excluded
+		}
+		t.AddLine(e.File, e.Description, included)
+	}
+	t.Print()
+	text.Break(out)
+
+	if !c.Globals.Flags.AutoYes && !c.Globals.Flags.NonInteractive {
+		label := fmt.Sprintf("Write the support bundle to %s? [y/N] ", path)
+		ok, err := text.AskYesNo(out, label, in, "--auto-yes")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		text.Break(out)
+	}
+
+	if err := b.Write(path, now); err != nil {
+		err = filesystem.Wrap("write", path, err)
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	text.Success(out, "Wrote the support bundle to %s", path)
+	text.Info(out, "Check its contents, then attach it to a new issue: %s", IssueURL)
+	return nil
+}
+
+// manifestChoice decides whether the fastly.toml manifest is included, and
+// if not why. The user is prompted unless a flag decides.
+func (c *RootCommand) manifestChoice(in io.Reader, out io.Writer) (include bool, reason string, err error) {
+	switch {
+	case !filesystem.FileExists(manifest.Filename):
+		return false, fmt.Sprintf("there's no %s in the current directory", manifest.Filename), nil
+	case c.excludeManifest:
+		return false, "--exclude-manifest was set", nil
+	case c.includeManifest, c.Globals.Flags.AutoYes, c.Globals.Flags.NonInteractive:
+		return true, "", nil
+	}
+	label := fmt.Sprintf("Include the %s manifest in the support bundle (secrets are redacted)? [y/N] ", manifest.Filename)
+	include, err = text.AskYesNo(out, label, in, "--include-manifest")
+	if err != nil {
+		return false, "", err
+	}
+	text.Break(out)
+	if !include {
+		return false, "excluded when prompted", nil
+	}
+	return true, "", nil
+}
+
+// Now is exposed so that we may mock it from our test file.
+var Now = time.Now
@@ -16,6 +16,7 @@ import (
 func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 	var c ListCommand
 	c.CmdClause = parent.Command("list", "List all TLS activations")
+	c.EmptyState = argparser.EmptyState{Resource: "TLS activations", Create: "fastly tls-custom activation enable --cert-id <CERT_ID> --id <ID>"}
 	c.Globals = g
 
 	// Optional.
@@ -23,7 +24,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 	c.CmdClause.Flag("filter-config", "Limit the returned activations to a specific TLS configuration").StringVar(&c.filterTLSConfigID)
 	c.CmdClause.Flag("filter-domain", "Limit the returned rules to a specific domain name").StringVar(&c.filterTLSDomainID)
 	c.CmdClause.Flag("include", "Include related objects (comma-separated values)").HintOptions(include...).EnumVar(&c.include, include...)
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	c.CmdClause.Flag("page", "Page number of data set to fetch").IntVar(&c.pageNumber)
 	c.CmdClause.Flag("per-page", "Number of records per page").IntVar(&c.pageSize)
 
@@ -34,6 +35,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 
 	filterTLSCertID   string
 	filterTLSConfigID string
@@ -68,6 +70,10 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
+	if c.PrintEmpty(out, len(o)) {
+		return nil
+	}
+
 	if c.Globals.Verbose() {
 		c.printVerbose(out, o)
 	} else {
@@ -112,7 +118,7 @@ func (c *ListCommand) printVerbose(out io.Writer, rs []*fastly.TLSActivation) {
 		fmt.Fprintf(out, "\nID: %s\n", r.ID)
 
 		if r.CreatedAt != nil {
-			fmt.Fprintf(out, "Created at: %s\n", r.CreatedAt)
+			fmt.Fprintf(out, "Created at: %s\n", text.FormatTime(r.CreatedAt))
 		}
 
 		fmt.Fprintf(out, "\n")
@@ -125,7 +131,7 @@ func (c *ListCommand) printSummary(out io.Writer, rs []*fastly.TLSActivation) er
 	t := text.NewTable(out)
 	t.AddHeader("ID", "CREATED_AT")
 	for _, r := range rs {
-		t.AddLine(r.ID, r.CreatedAt)
+		t.AddLine(r.ID, text.FormatTime(r.CreatedAt))
 	}
 	t.Print()
 	return nil
@@ -48,7 +48,7 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
-	text.Success(out, "Created TLS Certificate '%s'", r.ID)
+	text.Result(out, r.ID, "Created TLS Certificate '%s'", r.ID)
 	return nil
 }
 
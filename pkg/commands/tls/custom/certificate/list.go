@@ -18,13 +18,14 @@ const emptyString = ""
 func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 	var c ListCommand
 	c.CmdClause = parent.Command("list", "List all TLS certificates")
+	c.EmptyState = argparser.EmptyState{Resource: "TLS certificates", Create: "fastly tls-custom certificate create --cert-blob <CERT_BLOB>"}
 	c.Globals = g
 
 	// Optional.
 	c.CmdClause.Flag("filter-not-after", "Limit the returned certificates to those that expire prior to the specified date in UTC").StringVar(&c.filterNotAfter)
 	c.CmdClause.Flag("filter-domain", "Limit the returned certificates to those that include the specific domain").StringVar(&c.filterTLSDomainID)
 	c.CmdClause.Flag("include", "Include related objects (comma-separated values)").HintOptions("tls_activations").EnumVar(&c.include, "tls_activations")
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	c.CmdClause.Flag("page", "Page number of data set to fetch").IntVar(&c.pageNumber)
 	c.CmdClause.Flag("per-page", "Number of records per page").IntVar(&c.pageSize)
 	c.CmdClause.Flag("sort", "The order in which to list the results by creation date").StringVar(&c.sort)
@@ -36,6 +37,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 
 	filterNotAfter    string
 	filterTLSDomainID string
@@ -70,6 +72,10 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
+	if c.PrintEmpty(out, len(o)) {
+		return nil
+	}
+
 	if c.Globals.Verbose() {
 		printVerbose(out, o)
 	} else {
@@ -117,10 +123,10 @@ func printVerbose(out io.Writer, rs []*fastly.CustomTLSCertificate) {
 		fmt.Fprintf(out, "Name: %s\n", r.Name)
 
 		if r.NotAfter != nil {
-			fmt.Fprintf(out, "Not after: %s\n", r.NotAfter)
+			fmt.Fprintf(out, "Not after: %s\n", text.FormatTime(r.NotAfter))
 		}
 		if r.NotBefore != nil {
-			fmt.Fprintf(out, "Not before: %s\n", r.NotBefore)
+			fmt.Fprintf(out, "Not before: %s\n", text.FormatTime(r.NotBefore))
 		}
 
 		fmt.Fprintf(out, "Replace: %t\n", r.Replace)
@@ -128,10 +134,10 @@ func printVerbose(out io.Writer, rs []*fastly.CustomTLSCertificate) {
 		fmt.Fprintf(out, "Signature algorithm: %s\n", r.SignatureAlgorithm)
 
 		if r.CreatedAt != nil {
-			fmt.Fprintf(out, "Created at: %s\n", r.CreatedAt)
+			fmt.Fprintf(out, "Created at: %s\n", text.FormatTime(r.CreatedAt))
 		}
 		if r.UpdatedAt != nil {
-			fmt.Fprintf(out, "Updated at: %s\n", r.UpdatedAt)
+			fmt.Fprintf(out, "Updated at: %s\n", text.FormatTime(r.UpdatedAt))
 		}
 
 		fmt.Fprintf(out, "\n")
@@ -16,11 +16,12 @@ import (
 func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 	var c ListCommand
 	c.CmdClause = parent.Command("list", "List all TLS private keys")
+	c.EmptyState = argparser.EmptyState{Resource: "TLS private keys", Create: "fastly tls-custom private-key create --key <KEY> --name <NAME>"}
 	c.Globals = g
 
 	// Optional.
 	c.CmdClause.Flag("filter-in-use", "Limit the returned keys to those without any matching TLS certificates").HintOptions("false").EnumVar(&c.filterInUse, "false")
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	c.CmdClause.Flag("page", "Page number of data set to fetch").IntVar(&c.pageNumber)
 	c.CmdClause.Flag("per-page", "Number of records per page").IntVar(&c.pageSize)
 
@@ -31,6 +32,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 
 	filterInUse string
 	pageNumber  int
@@ -59,6 +61,10 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
+	if c.PrintEmpty(out, len(o)) {
+		return nil
+	}
+
 	if c.Globals.Verbose() {
 		printVerbose(out, o)
 	} else {
@@ -98,7 +104,7 @@ func printVerbose(out io.Writer, rs []*fastly.PrivateKey) {
 		fmt.Fprintf(out, "Public Key SHA1: %s\n", r.PublicKeySHA1)
 
 		if r.CreatedAt != nil {
-			fmt.Fprintf(out, "Created at: %s\n", r.CreatedAt)
+			fmt.Fprintf(out, "Created at: %s\n", text.FormatTime(r.CreatedAt))
 		}
 
 		fmt.Fprintf(out, "Replace: %t\n", r.Replace)
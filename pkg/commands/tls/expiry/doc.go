@@ -0,0 +1,3 @@
+// Package expiry contains a command to report when the TLS certificates on an
+// account expire.
+package expiry
@@ -0,0 +1,225 @@
+package expiry_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/tls/expiry"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestTLSExpiry(t *testing.T) {
+	args := testutil.Args
+	now := time.Now()
+	soon := now.Add(10*24*time.Hour + time.Hour)
+	later := now.Add(45*24*time.Hour + time.Hour)
+	expired := now.Add(-2*24*time.Hour + time.Hour)
+
+	listCustom := func(_ *fastly.ListCustomTLSCertificatesInput) ([]*fastly.CustomTLSCertificate, error) {
+		return []*fastly.CustomTLSCertificate{
+			{
+				ID:       "later",
+				Issuer:   "Let's Encrypt",
+				NotAfter: &later,
+				Domains:  []*fastly.TLSDomain{{ID: "www.example.com"}},
+			},
+			{
+				ID:       "soon",
+				Issuer:   "DigiCert",
+				NotAfter: &soon,
+				Domains:  []*fastly.TLSDomain{{ID: "api.example.com"}, {ID: "example.com"}},
+			},
+		}, nil
+	}
+	listBulk := func(_ *fastly.ListBulkCertificatesInput) ([]*fastly.BulkCertificate, error) {
+		return []*fastly.BulkCertificate{
+			{
+				ID:       "expired",
+				NotAfter: &expired,
+				Domains:  []*fastly.TLSDomain{{ID: "old.example.com"}},
+			},
+		}, nil
+	}
+	forbidden := &fastly.HTTPError{StatusCode: http.StatusForbidden}
+
+	scenarios := []struct {
+		testutil.TestScenario
+		wantOrder []string
+		dontWant  string
+	}{
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "lists all certificates soonest first",
+				API: mock.API{
+					ListCustomTLSCertificatesFn: listCustom,
+					ListBulkCertificatesFn:      listBulk,
+				},
+				Args:       args("tls-expiry"),
+				WantOutput: "api.example.com, example.com",
+			},
+			wantOrder: []string{"expired", "soon", "later"},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "filters by expiry and fails when certificates match",
+				API: mock.API{
+					ListCustomTLSCertificatesFn: listCustom,
+					ListBulkCertificatesFn:      listBulk,
+				},
+				Args:       args("tls-expiry --expiring-within 30d"),
+				WantError:  "2 TLS certificate(s) expire within 30d",
+				WantOutput: "DigiCert",
+			},
+			wantOrder: []string{"expired", "soon"},
+			dontWant:  "later",
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "no certificates within the period",
+				API: mock.API{
+					ListCustomTLSCertificatesFn: listCustom,
+					ListBulkCertificatesFn: func(_ *fastly.ListBulkCertificatesInput) ([]*fastly.BulkCertificate, error) {
+						return nil, forbidden
+					},
+				},
+				Args:       args("tls-expiry --expiring-within 5d"),
+				WantOutput: "No TLS certificates expire within 5d",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "empty account",
+				API: mock.API{
+					ListCustomTLSCertificatesFn: func(_ *fastly.ListCustomTLSCertificatesInput) ([]*fastly.CustomTLSCertificate, error) {
+						return nil, nil
+					},
+					ListBulkCertificatesFn: func(_ *fastly.ListBulkCertificatesInput) ([]*fastly.BulkCertificate, error) {
+						return nil, nil
+					},
+				},
+				Args:       args("tls-expiry --expiring-within 30d"),
+				WantOutput: "No TLS certificates expire within 30d",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "no TLS entitlement",
+				API: mock.API{
+					ListCustomTLSCertificatesFn: func(_ *fastly.ListCustomTLSCertificatesInput) ([]*fastly.CustomTLSCertificate, error) {
+						return nil, forbidden
+					},
+					ListBulkCertificatesFn: func(_ *fastly.ListBulkCertificatesInput) ([]*fastly.BulkCertificate, error) {
+						return nil, forbidden
+					},
+				},
+				Args:      args("tls-expiry"),
+				WantError: "this account does not have access to TLS certificates",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "API error",
+				API: mock.API{
+					ListCustomTLSCertificatesFn: func(_ *fastly.ListCustomTLSCertificatesInput) ([]*fastly.CustomTLSCertificate, error) {
+						return nil, testutil.Err
+					},
+				},
+				Args:      args("tls-expiry"),
+				WantError: testutil.Err.Error(),
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "invalid period",
+				Args:      args("tls-expiry --expiring-within soon"),
+				WantError: "invalid --expiring-within value",
+			},
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(testcase.Args, &stdout)
+				opts.APIClientFactory = mock.APIClient(testcase.API)
+				return opts, nil
+			}
+			err := app.Run(testcase.Args, nil)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+
+			output := stdout.String()
+			last := -1
+			for _, id := range testcase.wantOrder {
+				i := strings.Index(output, id)
+				if i <= last {
+					t.Errorf("want %q after previous certificates in:\n%s", id, output)
+				}
+				last = i
+			}
+			if testcase.dontWant != "" && strings.Contains(output, testcase.dontWant) {
+				t.Errorf("unexpected %q in output:\n%s", testcase.dontWant, output)
+			}
+		})
+	}
+}
+
+func TestTLSExpiryJSON(t *testing.T) {
+	notAfter := time.Now().Add(3*24*time.Hour + time.Hour)
+	api := mock.API{
+		ListCustomTLSCertificatesFn: func(_ *fastly.ListCustomTLSCertificatesInput) ([]*fastly.CustomTLSCertificate, error) {
+			return []*fastly.CustomTLSCertificate{{ID: "abc", Issuer: "DigiCert", NotAfter: &notAfter}}, nil
+		},
+		ListBulkCertificatesFn: func(_ *fastly.ListBulkCertificatesInput) ([]*fastly.BulkCertificate, error) {
+			return nil, nil
+		},
+	}
+	args := testutil.Args("tls-expiry --expiring-within 7d --json")
+
+	var stdout bytes.Buffer
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		opts := testutil.MockGlobalData(args, &stdout)
+		opts.APIClientFactory = mock.APIClient(api)
+		return opts, nil
+	}
+	err := app.Run(args, nil)
+	testutil.AssertErrorContains(t, err, "1 TLS certificate(s) expire within 7d")
+
+	var certs []expiry.Certificate
+	if err := json.Unmarshal(stdout.Bytes(), &certs); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout.String())
+	}
+	if len(certs) != 1 || certs[0].ID != "abc" || certs[0].DaysRemaining != 3 || certs[0].Type != "custom" {
+		t.Errorf("unexpected certificates: %+v", certs)
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	for in, want := range map[string]time.Duration{
+		"30d": 30 * 24 * time.Hour,
+		"0d":  0,
+		"72h": 72 * time.Hour,
+	} {
+		got, err := expiry.ParsePeriod(in)
+		if err != nil || got != want {
+			t.Errorf("ParsePeriod(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "d", "-1d", "30 days", "-5h"} {
+		if _, err := expiry.ParsePeriod(in); err == nil {
+			t.Errorf("ParsePeriod(%q): want error", in)
+		}
+	}
+}
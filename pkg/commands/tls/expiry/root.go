@@ -0,0 +1,267 @@
+package expiry
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// pageSize is the number of certificates requested per API call.
+const pageSize = 100
+
+// ErrNoTLSEntitlement indicates the account cannot access any TLS
+// certificates.
+var ErrNoTLSEntitlement = fsterr.RemediationError{
+	Inner:       errors.New("this account does not have access to TLS certificates"),
+	Remediation: "Managing TLS certificates requires a TLS product (e.g. Custom TLS or Platform TLS) to be enabled on the account, and an API token for a user with the TLS management permission. Contact your account manager or support@fastly.com to enable TLS.",
+}
+
+// Certificate is a TLS certificate and its expiry.
+type Certificate struct {
+	DaysRemaining int       `json:"days_remaining"`
+	Domains       []string  `json:"domains"`
+	ID            string    `json:"id"`
+	Issuer        string    `json:"issuer"`
+	Name          string    `json:"name"`
+	NotAfter      time.Time `json:"not_after"`
+	Type          string    `json:"type"`
+}
+
+// RootCommand reports on the TLS certificates on the account.
+type RootCommand struct {
+	argparser.Base
+	argparser.JSONOutput
+
+	expiringWithin string
+}
+
+// NewRootCommand returns a new command registered in the parent.
+func NewRootCommand(parent argparser.Registerer, g *global.Data) *RootCommand {
+	var c RootCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("tls-expiry", "Report when the TLS certificates on the account expire")
+	c.CmdClause.Flag("expiring-within", "Only report certificates expiring within this period (e.g. 30d, 72h) and exit with an error if there are any").StringVar(&c.expiringWithin)
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
+	var within time.Duration
+	if c.expiringWithin != "" {
+		d, err := ParsePeriod(c.expiringWithin)
+		if err != nil {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("invalid --expiring-within value %q: %w", c.expiringWithin, err),
+				Remediation: "Use a number of days (e.g. 30d) or a duration (e.g. 72h).",
+			}
+		}
+		within = d
+	}
+
+	now := time.Now()
+	certs, err := c.certificates(now)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Expiring Within": c.expiringWithin,
+		})
+		return err
+	}
+
+	if c.expiringWithin != "" {
+		certs = ExpiringWithin(certs, now, within)
+	}
+
+	if ok, err := c.WriteJSON(out, certs); ok {
+		if err == nil && c.expiringWithin != "" && len(certs) > 0 {
+			return expiringError(len(certs), c.expiringWithin)
+		}
+		return err
+	}
+
+	if len(certs) == 0 {
+		if c.expiringWithin != "" {
+			text.Success(out, "No TLS certificates expire within %s", c.expiringWithin)
+		} else {
+			text.Info(out, "No TLS certificates found")
+		}
+		return nil
+	}
+
+	t := text.NewTable(out)
+	t.AddHeader("ID", "TYPE", "DOMAINS", "ISSUER", "NOT AFTER", "DAYS REMAINING")
+	for _, cert := range certs {
+		t.AddLine(cert.ID, cert.Type, strings.Join(cert.Domains, ", "), cert.Issuer, text.FormatTimeLayout(&cert.NotAfter, time.RFC3339), cert.DaysRemaining)
+	}
+	t.Print()
+
+	if c.expiringWithin != "" {
+		return expiringError(len(certs), c.expiringWithin)
+	}
+	return nil
+}
+
+// certificates returns the custom and platform certificates on the account,
+// sorted by expiry (soonest first).
+//
+// Accounts are not required to have both products, so a permission error from
+// one of them is ignored. ErrNoTLSEntitlement is returned if neither can be
+// accessed.
+func (c *RootCommand) certificates(now time.Time) ([]Certificate, error) {
+	custom, customErr := c.customCertificates(now)
+	if customErr != nil && !isForbidden(customErr) {
+		return nil, customErr
+	}
+	platform, platformErr := c.platformCertificates(now)
+	if platformErr != nil && !isForbidden(platformErr) {
+		return nil, platformErr
+	}
+	if customErr != nil && platformErr != nil {
+		return nil, ErrNoTLSEntitlement
+	}
+
+	certs := append(custom, platform...)
+	sort.SliceStable(certs, func(i, j int) bool {
+		return certs[i].NotAfter.Before(certs[j].NotAfter)
+	})
+	return certs, nil
+}
+
+func (c *RootCommand) customCertificates(now time.Time) ([]Certificate, error) {
+	var certs []Certificate
+	for page := 1; ; page++ {
+		rs, err := c.Globals.APIClient.ListCustomTLSCertificates(&fastly.ListCustomTLSCertificatesInput{
+			Include:    "tls_domains",
+			PageNumber: page,
+			PageSize:   pageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rs {
+			if r.NotAfter == nil {
+				continue
+			}
+			certs = append(certs, Certificate{
+				DaysRemaining: DaysRemaining(*r.NotAfter, now),
+				Domains:       domainIDs(r.Domains),
+				ID:            r.ID,
+				Issuer:        r.Issuer,
+				Name:          r.Name,
+				NotAfter:      *r.NotAfter,
+				Type:          "custom",
+			})
+		}
+		if len(rs) < pageSize {
+			return certs, nil
+		}
+	}
+}
+
+func (c *RootCommand) platformCertificates(now time.Time) ([]Certificate, error) {
+	var certs []Certificate
+	for page := 1; ; page++ {
+		rs, err := c.Globals.APIClient.ListBulkCertificates(&fastly.ListBulkCertificatesInput{
+			PageNumber: page,
+			PageSize:   pageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rs {
+			if r.NotAfter == nil {
+				continue
+			}
+			certs = append(certs, Certificate{
+				DaysRemaining: DaysRemaining(*r.NotAfter, now),
+				Domains:       domainIDs(r.Domains),
+				ID:            r.ID,
+				NotAfter:      *r.NotAfter,
+				Type:          "platform",
+			})
+		}
+		if len(rs) < pageSize {
+			return certs, nil
+		}
+	}
+}
+
+// ExpiringWithin returns the certificates whose expiry is before now+d. Already
+// expired certificates are included.
+func ExpiringWithin(certs []Certificate, now time.Time, d time.Duration) []Certificate {
+	deadline := now.Add(d)
+	var matched []Certificate
+	for _, cert := range certs {
+		if cert.NotAfter.Before(deadline) {
+			matched = append(matched, cert)
+		}
+	}
+	return matched
+}
+
+// DaysRemaining returns the number of whole days until notAfter. It is
+// negative for expired certificates.
+func DaysRemaining(notAfter, now time.Time) int {
+	return int(math.Floor(notAfter.Sub(now).Hours() / 24))
+}
+
+// ParsePeriod parses a number of days (e.g. 30d) or a Go duration (e.g. 72h).
+func ParsePeriod(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid number of days")
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("period must not be negative")
+	}
+	return d, nil
+}
+
+func expiringError(n int, within string) error {
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("%d TLS certificate(s) expire within %s", n, within),
+		Remediation: "Renew or replace the listed certificates. Certificates procured through a TLS subscription are renewed automatically once domain ownership is verified.",
+	}
+}
+
+func domainIDs(domains []*fastly.TLSDomain) []string {
+	ids := make([]string, 0, len(domains))
+	for _, d := range domains {
+		if d != nil {
+			ids = append(ids, d.ID)
+		}
+	}
+	return ids
+}
+
+func isForbidden(err error) bool {
+	var he *fastly.HTTPError
+	if errors.As(err, &he) {
+		return he.StatusCode == http.StatusForbidden || he.StatusCode == http.StatusUnauthorized
+	}
+	return false
+}
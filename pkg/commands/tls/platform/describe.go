@@ -9,6 +9,7 @@ import (
 	"github.com/fastly/cli/pkg/argparser"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
 )
 
 // NewDescribeCommand returns a usable command registered under the parent.
@@ -21,7 +22,7 @@ func NewDescribeCommand(parent argparser.Registerer, g *global.Data) *DescribeCo
 	c.CmdClause.Flag("id", "Alphanumeric string identifying a TLS bulk certificate").Required().StringVar(&c.id)
 
 	// Optional.
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 
 	return &c
 }
@@ -71,16 +72,16 @@ func (c *DescribeCommand) print(out io.Writer, r *fastly.BulkCertificate) error
 	fmt.Fprintf(out, "\nID: %s\n", r.ID)
 
 	if r.NotAfter != nil {
-		fmt.Fprintf(out, "Not after: %s\n", r.NotAfter)
+		fmt.Fprintf(out, "Not after: %s\n", text.FormatTime(r.NotAfter))
 	}
 	if r.NotBefore != nil {
-		fmt.Fprintf(out, "Not before: %s\n", r.NotBefore)
+		fmt.Fprintf(out, "Not before: %s\n", text.FormatTime(r.NotBefore))
 	}
 	if r.CreatedAt != nil {
-		fmt.Fprintf(out, "Created at: %s\n", r.CreatedAt)
+		fmt.Fprintf(out, "Created at: %s\n", text.FormatTime(r.CreatedAt))
 	}
 	if r.UpdatedAt != nil {
-		fmt.Fprintf(out, "Updated at: %s\n", r.UpdatedAt)
+		fmt.Fprintf(out, "Updated at: %s\n", text.FormatTime(r.UpdatedAt))
 	}
 
 	fmt.Fprintf(out, "Replace: %t\n", r.Replace)
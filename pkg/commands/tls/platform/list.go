@@ -16,11 +16,12 @@ import (
 func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 	var c ListCommand
 	c.CmdClause = parent.Command("list", "List all certificates")
+	c.EmptyState = argparser.EmptyState{Resource: "platform TLS certificates", Create: "fastly tls-platform upload --cert-blob <CERT_BLOB> --intermediates-blob <INTERMEDIATES_BLOB>"}
 	c.Globals = g
 
 	// Optional.
 	c.CmdClause.Flag("filter-domain", "Optionally filter by the bulk attribute").StringVar(&c.filterTLSDomainID)
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	c.CmdClause.Flag("page", "Page number of data set to fetch").IntVar(&c.pageNumber)
 	c.CmdClause.Flag("per-page", "Number of records per page").IntVar(&c.pageSize)
 	c.CmdClause.Flag("sort", "The order in which to list the results by creation date").StringVar(&c.sort)
@@ -32,6 +33,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 
 	filterTLSDomainID string
 	pageNumber        int
@@ -62,6 +64,10 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
+	if c.PrintEmpty(out, len(o)) {
+		return nil
+	}
+
 	if c.Globals.Verbose() {
 		printVerbose(out, o)
 	} else {
@@ -100,16 +106,16 @@ func printVerbose(out io.Writer, rs []*fastly.BulkCertificate) {
 		fmt.Fprintf(out, "ID: %s\n", r.ID)
 
 		if r.NotAfter != nil {
-			fmt.Fprintf(out, "Not after: %s\n", r.NotAfter)
+			fmt.Fprintf(out, "Not after: %s\n", text.FormatTime(r.NotAfter))
 		}
 		if r.NotBefore != nil {
-			fmt.Fprintf(out, "Not before: %s\n", r.NotBefore)
+			fmt.Fprintf(out, "Not before: %s\n", text.FormatTime(r.NotBefore))
 		}
 		if r.CreatedAt != nil {
-			fmt.Fprintf(out, "Created at: %s\n", r.CreatedAt)
+			fmt.Fprintf(out, "Created at: %s\n", text.FormatTime(r.CreatedAt))
 		}
 		if r.UpdatedAt != nil {
-			fmt.Fprintf(out, "Updated at: %s\n", r.UpdatedAt)
+			fmt.Fprintf(out, "Updated at: %s\n", text.FormatTime(r.UpdatedAt))
 		}
 
 		fmt.Fprintf(out, "Replace: %t\n", r.Replace)
@@ -123,7 +129,7 @@ func (c *ListCommand) printSummary(out io.Writer, rs []*fastly.BulkCertificate)
 	t := text.NewTable(out)
 	t.AddHeader("ID", "REPLACE", "NOT BEFORE", "NOT AFTER", "CREATED")
 	for _, r := range rs {
-		t.AddLine(r.ID, r.Replace, r.NotBefore, r.NotAfter, r.CreatedAt)
+		t.AddLine(r.ID, r.Replace, text.FormatTime(r.NotBefore), text.FormatTime(r.NotAfter), text.FormatTime(r.CreatedAt))
 	}
 	t.Print()
 	return nil
@@ -18,6 +18,7 @@ var states = []string{"pending", "processing", "issued", "renewing"}
 func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 	var c ListCommand
 	c.CmdClause = parent.Command("list", "List all TLS subscriptions")
+	c.EmptyState = argparser.EmptyState{Resource: "TLS subscriptions", Create: "fastly tls-subscription create --domain <DOMAIN>"}
 	c.Globals = g
 
 	// Optional.
@@ -25,7 +26,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 	c.CmdClause.Flag("filter-domain", "Limit the returned subscriptions to those that include the specific domain").StringVar(&c.filterTLSDomainID)
 	c.CmdClause.Flag("filter-state", "Limit the returned subscriptions by state").HintOptions(states...).EnumVar(&c.filterState, states...)
 	c.CmdClause.Flag("include", "Include related objects (comma-separated values)").HintOptions(include...).EnumVar(&c.include, include...) // include is defined in ./describe.go
-	c.RegisterFlagBool(c.JSONFlag())                                                                                                        // --json
+	c.RegisterJSONFlags(&c.JSONOutput)                                                                                                      // --json, --field, --format
 	c.CmdClause.Flag("page", "Page number of data set to fetch").IntVar(&c.pageNumber)
 	c.CmdClause.Flag("per-page", "Number of records per page").IntVar(&c.pageSize)
 	c.CmdClause.Flag("sort", "The order in which to list the results by creation date").StringVar(&c.sort)
@@ -37,6 +38,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 
 	filterHasActiveOrder bool
 	filterState          string
@@ -73,6 +75,10 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
+	if c.PrintEmpty(out, len(o)) {
+		return nil
+	}
+
 	if c.Globals.Verbose() {
 		c.printVerbose(out, o)
 	} else {
@@ -122,10 +128,10 @@ func (c *ListCommand) printVerbose(out io.Writer, rs []*fastly.TLSSubscription)
 		fmt.Fprintf(out, "State: %s\n", r.State)
 
 		if r.CreatedAt != nil {
-			fmt.Fprintf(out, "Created at: %s\n", r.CreatedAt)
+			fmt.Fprintf(out, "Created at: %s\n", text.FormatTime(r.CreatedAt))
 		}
 		if r.UpdatedAt != nil {
-			fmt.Fprintf(out, "Updated at: %s\n", r.UpdatedAt)
+			fmt.Fprintf(out, "Updated at: %s\n", text.FormatTime(r.UpdatedAt))
 		}
 
 		fmt.Fprintf(out, "\n")
@@ -138,7 +144,7 @@ func (c *ListCommand) printSummary(out io.Writer, rs []*fastly.TLSSubscription)
 	t := text.NewTable(out)
 	t.AddHeader("ID", "CERT AUTHORITY", "STATE", "CREATED")
 	for _, r := range rs {
-		t.AddLine(r.ID, r.CertificateAuthority, r.State, r.CreatedAt)
+		t.AddLine(r.ID, r.CertificateAuthority, r.State, text.FormatTime(r.CreatedAt))
 	}
 	t.Print()
 	return nil
@@ -4,10 +4,13 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/blang/semver"
 
+	"github.com/fastly/cli/pkg/config"
 	"github.com/fastly/cli/pkg/github"
+	"github.com/fastly/cli/pkg/text"
 )
 
 // Check if the CLI can be updated.
@@ -38,36 +41,88 @@ type checkResult struct {
 	shouldUpdate bool
 }
 
+// DefaultCheckInterval is how long the CLI waits between checks for a new
+// version when the config doesn't set an interval.
+const DefaultCheckInterval = 24 * time.Hour
+
+// Now returns the current time.
+//
+// NOTE: This is a package level variable so tests can fake the clock.
+var Now = time.Now
+
+// NoticeDeadline is how long the notice waits for an unfinished check once the
+// command has completed. The notice is skipped if the check takes longer.
+var NoticeDeadline = 500 * time.Millisecond
+
+// CheckOpts configures CheckAsync.
+type CheckOpts struct {
+	// Config is where the last check time and latest seen version are
+	// persisted, along with the check interval.
+	Config *config.File
+	// ConfigPath is the location the config is written to.
+	ConfigPath string
+	// CurrentVersion is the version of the running CLI.
+	CurrentVersion string
+	// Versioner looks up the latest CLI version.
+	Versioner github.AssetVersioner
+}
+
 // CheckAsync is a helper function for running Check asynchronously.
 //
-// Launches a goroutine to perform a check for the latest CLI version using the
-// provided context and return a function that will print an informative message
-// to the writer if there is a newer version available.
+// The check runs at most once per interval (see config.UpdateCheck). When due,
+// a goroutine checks for the latest CLI version concurrently with the command
+// and the returned function prints a single line to the writer if there is a
+// newer version available. The returned function waits at most NoticeDeadline
+// for the check to complete.
 //
 // Callers should invoke CheckAsync via
 //
 //	f := CheckAsync(...)
-//	defer f()
-func CheckAsync(
-	currentVersion string,
-	av github.AssetVersioner,
-	quietMode bool,
-) (printResults func(io.Writer)) {
+//	defer f(out)
+func CheckAsync(opts CheckOpts) (printResults func(io.Writer)) {
+	now := Now()
+	if !CheckDue(opts.Config.UpdateCheck, now) {
+		return func(_ io.Writer) {
+			// no-op
+		}
+	}
+
 	results := make(chan checkResult, 1)
 	go func() {
-		current, latest, shouldUpdate := Check(currentVersion, av)
+		current, latest, shouldUpdate := Check(opts.CurrentVersion, opts.Versioner)
 		results <- checkResult{current, latest, shouldUpdate}
 	}()
 
 	return func(w io.Writer) {
-		result := <-results
-		if result.shouldUpdate && !quietMode {
-			fmt.Fprintf(w, "\n")
-			fmt.Fprintf(w, "A new version of the Fastly CLI is available.\n")
-			fmt.Fprintf(w, "Current version: %s\n", result.current)
-			fmt.Fprintf(w, "Latest version: %s\n", result.latest)
-			fmt.Fprintf(w, "Run `fastly update` to get the latest version.\n")
-			fmt.Fprintf(w, "\n")
+		var result checkResult
+		select {
+		case result = <-results:
+		case <-time.After(NoticeDeadline):
+			return
 		}
+
+		opts.Config.UpdateCheck.LastChecked = now.Format(time.RFC3339)
+		if result.latest.GT(semver.Version{}) {
+			opts.Config.UpdateCheck.LatestVersion = result.latest.String()
+		}
+		// The check is non-essential so failing to persist it is ignored.
+		_ = opts.Config.Write(opts.ConfigPath)
+
+		if result.shouldUpdate {
+			fmt.Fprintln(w, text.Faint(fmt.Sprintf("A new version of the Fastly CLI is available (%s -> %s). Run `fastly update` to upgrade.", result.current, result.latest)))
+		}
+	}
+}
+
+// CheckDue indicates if the interval since the last check has elapsed.
+func CheckDue(cfg config.UpdateCheck, now time.Time) bool {
+	interval := DefaultCheckInterval
+	if d, err := time.ParseDuration(cfg.Interval); err == nil {
+		interval = d
+	}
+	lastChecked, err := time.Parse(time.RFC3339, cfg.LastChecked)
+	if err != nil {
+		return true
 	}
+	return !now.Before(lastChecked.Add(interval))
 }
@@ -2,9 +2,8 @@ package update_test
 
 import (
 	"bytes"
-	"fmt"
-	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -67,47 +66,107 @@ func TestCheck(t *testing.T) {
 	}
 }
 
+// releaseEndpoint is a mock release endpoint counting version lookups.
+type releaseEndpoint struct {
+	mock.AssetVersioner
+	calls *int
+	delay time.Duration
+}
+
+func (r releaseEndpoint) LatestVersion() (string, error) {
+	*r.calls++
+	time.Sleep(r.delay)
+	return r.AssetVersion, nil
+}
+
 func TestCheckAsync(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	defer func(fn func() time.Time) { update.Now = fn }(update.Now)
+	update.Now = func() time.Time { return now }
+
+	notice := "A new version of the Fastly CLI is available (0.0.1 -> 0.0.2). Run `fastly update` to upgrade."
+
 	for _, testcase := range []struct {
-		name           string
-		file           config.File
-		currentVersion string
-		av             github.AssetVersioner
-		wantOutput     string
+		name            string
+		cfg             config.UpdateCheck
+		latestVersion   string
+		delay           time.Duration
+		wantCalls       int
+		wantOutput      string
+		wantLastChecked string
 	}{
 		{
-			name:           "no last_check same version",
-			currentVersion: "0.0.1",
-			av:             mock.AssetVersioner{AssetVersion: "0.0.1"},
+			name:            "no last_check same version",
+			latestVersion:   "0.0.1",
+			wantCalls:       1,
+			wantLastChecked: now.Format(time.RFC3339),
+		},
+		{
+			name:            "no last_check new version",
+			latestVersion:   "0.0.2",
+			wantCalls:       1,
+			wantOutput:      notice,
+			wantLastChecked: now.Format(time.RFC3339),
 		},
 		{
-			name:           "no last_check new version",
-			currentVersion: "0.0.1",
-			av:             mock.AssetVersioner{AssetVersion: "0.0.2"},
-			wantOutput:     "\nA new version of the Fastly CLI is available.\nCurrent version: 0.0.1\nLatest version: 0.0.2\nRun `fastly update` to get the latest version.\n\n",
+			name:            "recent last_check new version",
+			cfg:             config.UpdateCheck{LastChecked: now.Add(-time.Hour).Format(time.RFC3339)},
+			latestVersion:   "0.0.2",
+			wantLastChecked: now.Add(-time.Hour).Format(time.RFC3339),
 		},
 		{
-			name:           "recent last_check new version",
-			currentVersion: "0.0.1",
-			av:             mock.AssetVersioner{AssetVersion: "0.0.2"},
-			wantOutput:     "\nA new version of the Fastly CLI is available.\nCurrent version: 0.0.1\nLatest version: 0.0.2\nRun `fastly update` to get the latest version.\n\n",
+			name:            "stale last_check new version",
+			cfg:             config.UpdateCheck{LastChecked: now.Add(-25 * time.Hour).Format(time.RFC3339)},
+			latestVersion:   "0.0.2",
+			wantCalls:       1,
+			wantOutput:      notice,
+			wantLastChecked: now.Format(time.RFC3339),
+		},
+		{
+			name:            "configured interval",
+			cfg:             config.UpdateCheck{Interval: "1h", LastChecked: now.Add(-2 * time.Hour).Format(time.RFC3339)},
+			latestVersion:   "0.0.2",
+			wantCalls:       1,
+			wantOutput:      notice,
+			wantLastChecked: now.Format(time.RFC3339),
+		},
+		{
+			name:          "slow check doesn't delay exit",
+			latestVersion: "0.0.2",
+			delay:         time.Second,
+			wantCalls:     1,
 		},
 	} {
 		t.Run(testcase.name, func(t *testing.T) {
-			configFilePath := filepath.Join(os.TempDir(), fmt.Sprintf("fastly_TestCheckAsync_%d", time.Now().UnixNano()))
-			defer os.RemoveAll(configFilePath)
+			defer func(d time.Duration) { update.NoticeDeadline = d }(update.NoticeDeadline)
+			update.NoticeDeadline = 50 * time.Millisecond
 
+			configFilePath := filepath.Join(t.TempDir(), "config.toml")
+			cfg := config.File{UpdateCheck: testcase.cfg}
+
+			var calls int
 			var buf bytes.Buffer
-			f := update.CheckAsync(
-				testcase.currentVersion,
-				testcase.av,
-				false,
-			)
+			start := time.Now()
+			f := update.CheckAsync(update.CheckOpts{
+				Config:         &cfg,
+				ConfigPath:     configFilePath,
+				CurrentVersion: "0.0.1",
+				Versioner:      releaseEndpoint{mock.AssetVersioner{AssetVersion: testcase.latestVersion}, &calls, testcase.delay},
+			})
 			f(&buf)
 
-			if want, have := testcase.wantOutput, buf.String(); want != have {
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("notice delayed exit by %s", elapsed)
+			}
+			if testcase.delay == 0 && calls != testcase.wantCalls {
+				t.Errorf("want %d version lookups, have %d", testcase.wantCalls, calls)
+			}
+			if want, have := testcase.wantOutput, strings.TrimSpace(buf.String()); want != have {
 				t.Error(cmp.Diff(want, have))
 			}
+			if want, have := testcase.wantLastChecked, cfg.UpdateCheck.LastChecked; want != have {
+				t.Errorf("last checked: want %q, have %q", want, have)
+			}
 		})
 	}
 }
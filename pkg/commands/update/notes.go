@@ -0,0 +1,123 @@
+package update
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/blang/semver"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// ReleasesPageURL is the web page listing the CLI releases and their notes.
+const ReleasesPageURL = "https://github.com/fastly/cli/releases"
+
+// MaxReleaseNotesLines is the number of lines of release notes displayed,
+// beyond which they're truncated with a link to the full notes.
+const MaxReleaseNotesLines = 40
+
+// ReleaseNote is the notes published with a release.
+type ReleaseNote struct {
+	// Version is the semver release version (e.g. 10.1.0).
+	Version semver.Version
+	// Body is the notes, in Markdown.
+	Body string
+	// URL is the web page of the release.
+	URL string
+}
+
+// FetchReleaseNotes returns the notes of the releases newer than from, up to
+// and including to, newest first.
+//
+// Prereleases are only included when to is itself a prerelease, so updating
+// between stable releases doesn't list the betas leading up to them.
+func FetchReleaseNotes(client api.HTTPClient, from, to semver.Version) ([]ReleaseNote, error) {
+	data, err := get(client, ReleasesURL+"?per_page=100")
+	if err != nil {
+		return nil, fmt.Errorf("error fetching release notes: %w", err)
+	}
+	var releases []ghRelease
+	if err := json.Unmarshal(data, &releases); err != nil {
+		return nil, fmt.Errorf("error parsing release notes: %w", err)
+	}
+
+	var notes []ReleaseNote
+	for _, r := range releases {
+		if r.Draft {
+			continue
+		}
+		v, err := semver.Parse(strings.TrimPrefix(r.TagName, "v"))
+		if err != nil || !v.GT(from) || v.GT(to) {
+			continue
+		}
+		if (r.Prerelease || len(v.Pre) > 0) && len(to.Pre) == 0 {
+			continue
+		}
+		notes = append(notes, ReleaseNote{Version: v, Body: r.Body, URL: r.HTMLURL})
+	}
+	sort.Slice(notes, func(i, j int) bool {
+		return notes[i].Version.GT(notes[j].Version)
+	})
+	return notes, nil
+}
+
+// DisplayReleaseNotes writes the notes of each release under its version,
+// rendered with text.Markdown. Beyond maxLines the notes are truncated and
+// followed by a link to the full notes.
+func DisplayReleaseNotes(out io.Writer, notes []ReleaseNote, maxLines int) {
+	var buf bytes.Buffer
+	for i, n := range notes {
+		if i > 0 {
+			fmt.Fprintln(&buf)
+		}
+		fmt.Fprintln(&buf, text.Bold("v"+n.Version.String()))
+		if body := strings.TrimSpace(sanitiseNotes(n.Body)); body != "" {
+			fmt.Fprintln(&buf)
+			text.Markdown(&buf, body)
+		}
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) <= maxLines {
+		fmt.Fprintln(out, strings.Join(lines, "\n"))
+		return
+	}
+	fmt.Fprintln(out, strings.Join(lines[:maxLines], "\n"))
+
+	url := ReleasesPageURL
+	if len(notes) == 1 && notes[0].URL != "" {
+		url = notes[0].URL
+	}
+	text.Break(out)
+	text.Output(out, "... Full release notes at %s", url)
+}
+
+var (
+	// htmlCommentRegEx matches an HTML comment, which GitHub doesn't display
+	// (e.g. the instructions of a release template). An unterminated comment
+	// runs to the end of the notes.
+	htmlCommentRegEx = regexp.MustCompile(`(?s)<!--.*?(-->|$)`)
+	// ansiRegEx matches a terminal escape sequence.
+	ansiRegEx = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+)
+
+// sanitiseNotes removes from the notes what shouldn't be written to the
+// terminal: HTML comments, escape sequences and other control characters.
+func sanitiseNotes(body string) string {
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	body = htmlCommentRegEx.ReplaceAllString(body, "")
+	body = ansiRegEx.ReplaceAllString(body, "")
+	return strings.Map(func(r rune) rune {
+		if r != '\n' && r != '\t' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, body)
+}
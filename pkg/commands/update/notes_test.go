@@ -0,0 +1,266 @@
+package update_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/update"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/revision"
+	fstruntime "github.com/fastly/cli/pkg/runtime"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestUpdateReleaseNotes(t *testing.T) {
+	if fstruntime.Windows {
+		t.Skip("fixture archives are tar.gz")
+	}
+
+	rs := newReleaseServer(t)
+	defer func(url, version string, exe func() (string, error), smoke func(string) error) {
+		update.ReleasesURL = url
+		revision.AppVersion = version
+		update.Executable = exe
+		update.SmokeTest = smoke
+	}(update.ReleasesURL, revision.AppVersion, update.Executable, update.SmokeTest)
+	update.ReleasesURL = rs.URL + "/releases"
+	update.SmokeTest = func(string) error { return nil }
+
+	scenarios := []struct {
+		name            string
+		args            string
+		current         string
+		previous        string
+		offline         bool
+		notesDown       bool
+		wantError       string
+		wantNotes       []string // in the order they're displayed
+		dontWantOutput  []string
+		wantOutput      string
+		wantBinary      string
+		wantPreviousVer string
+	}{
+		{
+			name:            "update displays the notes of every version installed",
+			args:            "update",
+			current:         "v8.9.0",
+			wantOutput:      "What's new since 8.9.0",
+			wantNotes:       []string{"v9.2.0", "feat: release notes", "v9.1.0", "fix: **unterminated bold", "v9.0.0", "feat: first of v9"},
+			dontWantOutput:  []string{"v10.0.0-beta1", "Draft notes", "Nightly notes", "Release template", "\x1b[31m"},
+			wantBinary:      "9.2.0",
+			wantPreviousVer: "8.9.0",
+		},
+		{
+			name:            "update to a prerelease includes prereleases",
+			args:            "update --channel prerelease",
+			current:         "v9.2.0",
+			wantNotes:       []string{"v10.0.0-beta1", "Beta notes."},
+			dontWantOutput:  []string{"v9.2.0\n"},
+			wantBinary:      "10.0.0-beta1",
+			wantPreviousVer: "9.2.0",
+		},
+		{
+			name:            "offline skips the notes",
+			args:            "update",
+			current:         "v9.0.0",
+			offline:         true,
+			wantOutput:      "Updated",
+			dontWantOutput:  []string{"What's new"},
+			wantBinary:      "9.2.0",
+			wantPreviousVer: "9.0.0",
+		},
+		{
+			name:            "notes that fail to be fetched are skipped",
+			args:            "update",
+			current:         "v9.0.0",
+			notesDown:       true,
+			wantOutput:      "Updated",
+			dontWantOutput:  []string{"What's new", "unavailable"},
+			wantBinary:      "9.2.0",
+			wantPreviousVer: "9.0.0",
+		},
+		{
+			name:           "a downgrade doesn't display notes",
+			args:           "update --version 9.0.0",
+			current:        "v9.2.0",
+			dontWantOutput: []string{"What's new"},
+			wantBinary:     "9.0.0",
+		},
+		{
+			name:       "changelog displays the notes without updating",
+			args:       "update --changelog",
+			current:    "v9.0.0",
+			wantOutput: "Release notes of the versions since 9.0.0",
+			wantNotes:  []string{"v9.2.0", "v9.1.0"},
+			wantBinary: "old",
+		},
+		{
+			name:           "changelog when up to date displays the notes since the previous version",
+			args:           "update --changelog",
+			current:        "v9.2.0",
+			previous:       "9.0.0",
+			wantOutput:     "the version the last update replaced",
+			wantNotes:      []string{"v9.2.0", "v9.1.0"},
+			dontWantOutput: []string{"v9.0.0"},
+			wantBinary:     "old",
+		},
+		{
+			name:       "changelog when up to date without a previous version",
+			args:       "update --changelog",
+			current:    "v9.2.0",
+			wantOutput: "No update required. The release notes are available at https://github.com/fastly/cli/releases",
+			wantBinary: "old",
+		},
+		{
+			name:           "changelog of a downgrade displays the notes of the versions removed",
+			args:           "update --changelog --version 9.0.0",
+			current:        "v9.2.0",
+			wantOutput:     "versions installing 9.0.0 would remove",
+			wantNotes:      []string{"v9.2.0", "v9.1.0"},
+			dontWantOutput: []string{"v9.0.0\n"},
+			wantBinary:     "old",
+		},
+		{
+			name:       "changelog fails if the notes can't be fetched",
+			args:       "update --changelog",
+			current:    "v9.0.0",
+			notesDown:  true,
+			wantError:  "error fetching release notes",
+			wantBinary: "old",
+		},
+	}
+
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			dir := t.TempDir()
+			bin := filepath.Join(dir, "fastly")
+			if err := os.WriteFile(bin, []byte("old"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			configPath := filepath.Join(dir, "config.toml")
+			update.Executable = func() (string, error) { return bin, nil }
+			revision.AppVersion = testcase.current
+			rs.notesDown = testcase.notesDown
+
+			args := testutil.Args(testcase.args)
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				data := testutil.MockGlobalData(args, &stdout)
+				data.ConfigPath = configPath
+				data.Config.UpdateCheck.PreviousVersion = testcase.previous
+				if testcase.offline {
+					data.Env.Offline = "true"
+				}
+				return data, nil
+			}
+			err := app.Run(args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+
+			output := stdout.String()
+			testutil.AssertStringContains(t, output, testcase.wantOutput)
+			at := 0
+			for _, s := range testcase.wantNotes {
+				i := strings.Index(output[at:], s)
+				if i < 0 {
+					t.Fatalf("want %q after %q in output:\n%s", s, output[:at], output)
+				}
+				at += i + len(s)
+			}
+			for _, s := range testcase.dontWantOutput {
+				if strings.Contains(output, s) {
+					t.Errorf("unexpected %q in output:\n%s", s, output)
+				}
+			}
+
+			got, err := os.ReadFile(bin)
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutil.AssertStringContains(t, string(got), testcase.wantBinary)
+
+			if testcase.wantPreviousVer != "" {
+				config, err := os.ReadFile(configPath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				testutil.AssertStringContains(t, string(config), `previous_version = "`+testcase.wantPreviousVer+`"`)
+			}
+		})
+	}
+}
+
+func TestFetchReleaseNotes(t *testing.T) {
+	rs := newReleaseServer(t)
+	defer func(url string) {
+		update.ReleasesURL = url
+	}(update.ReleasesURL)
+	update.ReleasesURL = rs.URL + "/releases"
+
+	scenarios := []struct {
+		from, to     string
+		wantVersions []string
+	}{
+		{from: "8.0.0", to: "9.2.0", wantVersions: []string{"9.2.0", "9.1.0", "9.0.0"}},
+		{from: "9.0.0", to: "9.1.0", wantVersions: []string{"9.1.0"}},
+		{from: "9.0.0", to: "10.0.0-beta1", wantVersions: []string{"10.0.0-beta1", "9.2.0", "9.1.0"}},
+		{from: "9.2.0", to: "9.2.0"},
+		{from: "10.0.0", to: "11.0.0"},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.from+"-"+testcase.to, func(t *testing.T) {
+			notes, err := update.FetchReleaseNotes(nil, semver.MustParse(testcase.from), semver.MustParse(testcase.to))
+			testutil.AssertNoError(t, err)
+			var versions []string
+			for _, n := range notes {
+				versions = append(versions, n.Version.String())
+			}
+			testutil.AssertEqual(t, testcase.wantVersions, versions)
+		})
+	}
+
+	update.ReleasesURL = rs.URL + "/releases/latest"
+	_, err := update.FetchReleaseNotes(nil, semver.MustParse("9.0.0"), semver.MustParse("9.2.0"))
+	testutil.AssertErrorContains(t, err, "error parsing release notes")
+}
+
+func TestDisplayReleaseNotes(t *testing.T) {
+	body := strings.Repeat("* a change\n", 10)
+	notes := []update.ReleaseNote{
+		{Version: semver.MustParse("9.2.0"), Body: body, URL: "https://example.com/v9.2.0"},
+		{Version: semver.MustParse("9.1.0"), Body: body},
+	}
+
+	var buf bytes.Buffer
+	update.DisplayReleaseNotes(&buf, notes, 100)
+	testutil.AssertStringContains(t, buf.String(), "9.1.0")
+	if strings.Contains(buf.String(), "Full release notes") {
+		t.Errorf("unexpected footer in notes within the limit:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	update.DisplayReleaseNotes(&buf, notes, 5)
+	if strings.Contains(buf.String(), "9.1.0") {
+		t.Errorf("want notes truncated to 5 lines, got:\n%s", buf.String())
+	}
+	testutil.AssertStringContains(t, buf.String(), "Full release notes at https://github.com/fastly/cli/releases\n")
+
+	buf.Reset()
+	update.DisplayReleaseNotes(&buf, notes[:1], 5)
+	testutil.AssertStringContains(t, buf.String(), "Full release notes at https://example.com/v9.2.0\n")
+
+	buf.Reset()
+	update.DisplayReleaseNotes(&buf, []update.ReleaseNote{{
+		Version: semver.MustParse("9.0.0"),
+		Body:    "<!-- unterminated comment\n* hidden",
+	}}, 5)
+	if strings.Contains(buf.String(), "hidden") {
+		t.Errorf("unexpected commented out notes in:\n%s", buf.String())
+	}
+}
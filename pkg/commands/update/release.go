@@ -0,0 +1,372 @@
+package update
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/blang/semver"
+
+	"github.com/fastly/cli/pkg/api"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/filesystem"
+	"github.com/fastly/cli/pkg/github"
+	fstruntime "github.com/fastly/cli/pkg/runtime"
+)
+
+// Release channels.
+const (
+	ChannelStable     = "stable"
+	ChannelPrerelease = "prerelease"
+)
+
+// ReleasesURL is the GitHub API endpoint listing the CLI releases.
+//
+// NOTE: This is a package level variable as it makes testing the behaviour of
+// the package easier because the test code can replace the value when running
+// the test suite.
+var ReleasesURL = "https://api.github.com/repos/fastly/cli/releases"
+
+// Executable returns the path of the running CLI binary.
+//
+// NOTE: This is a package level variable so tests can replace the binary being
+// updated.
+var Executable = os.Executable
+
+// SmokeTest checks a newly installed binary runs.
+//
+// NOTE: This is a package level variable so tests can replace it.
+var SmokeTest = func(bin string) error {
+	// gosec flagged this:
+	// G204 (CWE-78): Subprocess launched with variable
+	// Disabling as the binary is the one we have just installed.
+	// #nosec
+	output, err := exec.Command(bin, "--version").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+var (
+	downloadRemediation = strings.Join([]string{
+		fsterr.NetworkRemediation,
+		"If a --version was given, check it exists at https://github.com/fastly/cli/releases",
+	}, " ")
+
+	checksumRemediation = strings.Join([]string{
+		"The downloaded archive does not match the checksum published with the release, so it was discarded and the CLI was not changed.",
+		"Try the update again. If the problem persists, download the release manually from https://github.com/fastly/cli/releases and report the issue:",
+		"https://github.com/fastly/cli/issues/new?labels=bug&template=bug_report.md",
+	}, " ")
+
+	permissionRemediation = strings.Join([]string{
+		"The CLI binary could not be replaced because of insufficient file permissions.",
+		"Re-run the update with elevated permissions (e.g. `sudo fastly update`),",
+		"or update the CLI using the package manager it was installed with (e.g. `brew upgrade fastly`).",
+	}, " ")
+)
+
+// Release is a CLI release published on GitHub.
+type Release struct {
+	// Version is the semver release version (e.g. 10.1.0).
+	Version semver.Version
+	// Prerelease indicates the release is not yet stable.
+	Prerelease bool
+	// ArchiveName is the file name of the archive for the current platform.
+	ArchiveName string
+	// ArchiveURL is the download URL of the archive for the current platform.
+	ArchiveURL string
+	// ChecksumsURL is the download URL of the SHA256 checksums file.
+	ChecksumsURL string
+}
+
+type ghRelease struct {
+	TagName    string `json:"tag_name"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
+	Body       string `json:"body"`
+	HTMLURL    string `json:"html_url"`
+	Assets     []struct {
+		Name               string `json:"name"`
+		BrowserDownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// FindRelease returns the release to install.
+//
+// A version, when set, is installed regardless of the channel. Otherwise the
+// newest release on the channel is returned: the stable channel only considers
+// full releases whereas the prerelease channel considers all releases.
+func FindRelease(client api.HTTPClient, channel, version string) (Release, error) {
+	var (
+		endpoint = ReleasesURL + "/latest"
+		list     bool
+	)
+	switch {
+	case version != "":
+		v, err := semver.Parse(strings.TrimPrefix(version, "v"))
+		if err != nil {
+			return Release{}, fsterr.RemediationError{
+				Inner:       fmt.Errorf("invalid --version %q: %w", version, err),
+				Remediation: "Specify a release version such as v10.8.0.",
+			}
+		}
+		endpoint = ReleasesURL + "/tags/v" + v.String()
+	case channel == ChannelPrerelease:
+		endpoint = ReleasesURL
+		list = true
+	}
+
+	data, err := get(client, endpoint)
+	if err != nil {
+		return Release{}, downloadError(fmt.Errorf("error fetching release metadata: %w", err))
+	}
+
+	var candidates []ghRelease
+	if list {
+		err = json.Unmarshal(data, &candidates)
+	} else {
+		var r ghRelease
+		err = json.Unmarshal(data, &r)
+		candidates = append(candidates, r)
+	}
+	if err != nil {
+		return Release{}, downloadError(fmt.Errorf("error parsing release metadata: %w", err))
+	}
+
+	var (
+		newest semver.Version
+		found  *ghRelease
+	)
+	for i, r := range candidates {
+		if r.Draft {
+			continue
+		}
+		v, err := semver.Parse(strings.TrimPrefix(r.TagName, "v"))
+		if err != nil {
+			continue
+		}
+		if found == nil || v.GT(newest) {
+			newest, found = v, &candidates[i]
+		}
+	}
+	if found == nil {
+		return Release{}, downloadError(fmt.Errorf("no %s release found", channel))
+	}
+
+	rel := Release{
+		Version:     newest,
+		Prerelease:  found.Prerelease,
+		ArchiveName: ArchiveName(newest),
+	}
+	sums := fmt.Sprintf("fastly_v%s_SHA256SUMS", newest)
+	for _, a := range found.Assets {
+		switch a.Name {
+		case rel.ArchiveName:
+			rel.ArchiveURL = a.BrowserDownloadURL
+		case sums:
+			rel.ChecksumsURL = a.BrowserDownloadURL
+		}
+	}
+	if rel.ArchiveURL == "" {
+		return Release{}, downloadError(fmt.Errorf("release v%s has no asset for your OS (%s) and architecture (%s)", newest, runtime.GOOS, runtime.GOARCH))
+	}
+	if rel.ChecksumsURL == "" {
+		return Release{}, fsterr.RemediationError{
+			Inner:       ChecksumError{Inner: fmt.Errorf("release v%s has no published checksums file (%s)", newest, sums)},
+			Remediation: checksumRemediation,
+		}
+	}
+	return rel, nil
+}
+
+// ArchiveName returns the name of the release archive for the current
+// platform, e.g. fastly_v10.1.0_linux-amd64.tar.gz.
+func ArchiveName(v semver.Version) string {
+	ext := "tar.gz"
+	if fstruntime.Windows {
+		ext = "zip"
+	}
+	return fmt.Sprintf("fastly_v%s_%s-%s.%s", v, runtime.GOOS, runtime.GOARCH, ext)
+}
+
+// Download fetches the release archive into dir, verifies it against the
+// published checksums and extracts the binary, returning its path.
+func Download(client api.HTTPClient, rel Release, dir string) (bin string, err error) {
+	sums, err := get(client, rel.ChecksumsURL)
+	if err != nil {
+		return "", downloadError(fmt.Errorf("error downloading checksums: %w", err))
+	}
+
+	data, err := get(client, rel.ArchiveURL)
+	if err != nil {
+		return "", downloadError(fmt.Errorf("error downloading release archive: %w", err))
+	}
+
+	if err := VerifyChecksum(data, rel.ArchiveName, sums); err != nil {
+		return "", err
+	}
+
+	archive := filepath.Join(dir, rel.ArchiveName)
+	if err := os.WriteFile(archive, data, 0o600); err != nil {
+		return "", fsterr.RemediationError{
+			Inner:       fmt.Errorf("error writing release archive: %w", err),
+			Remediation: fsterr.HostRemediation,
+		}
+	}
+
+	binary := "fastly"
+	if fstruntime.Windows {
+		binary += ".exe"
+	}
+	bin, err = github.ExtractBinary(archive, binary, dir)
+	if err != nil {
+		return "", fsterr.RemediationError{
+			Inner:       fmt.Errorf("error extracting release archive: %w", err),
+			Remediation: fsterr.HostRemediation,
+		}
+	}
+	return bin, nil
+}
+
+// ChecksumError indicates a release archive could not be verified.
+type ChecksumError struct {
+	Inner error
+}
+
+// Error implements the error interface.
+func (e ChecksumError) Error() string {
+	return fmt.Sprintf("checksum verification failed: %s", e.Inner)
+}
+
+// Unwrap returns the inner error.
+func (e ChecksumError) Unwrap() error {
+	return e.Inner
+}
+
+// VerifyChecksum checks the SHA256 of data matches the entry for name in a
+// checksums file (lines of "<hex digest>  <file name>").
+func VerifyChecksum(data []byte, name string, sums []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		got := hex.EncodeToString(sum[:])
+		if !strings.EqualFold(fields[0], got) {
+			return fsterr.RemediationError{
+				Inner:       ChecksumError{Inner: fmt.Errorf("%s has SHA256 %s, expected %s", name, got, fields[0])},
+				Remediation: checksumRemediation,
+			}
+		}
+		return nil
+	}
+	return fsterr.RemediationError{
+		Inner:       ChecksumError{Inner: fmt.Errorf("no checksum published for %s", name)},
+		Remediation: checksumRemediation,
+	}
+}
+
+// Install replaces currentBin with newBin and runs the smoke test against it.
+// If the new binary cannot be installed or fails the smoke test, the original
+// binary is restored.
+func Install(currentBin, newBin string, smoke func(bin string) error) error {
+	// Windows does not permit replacing a running executable, however it will
+	// permit it if you first move the original executable. So we first move the
+	// running executable to a new location, then we move the executable that we
+	// downloaded to the same location as the original.
+	//
+	// Reference:
+	// https://github.com/golang/go/issues/21997#issuecomment-331744930
+	backup := currentBin + ".bak"
+	if err := os.Rename(currentBin, backup); err != nil {
+		return replaceError(fmt.Errorf("error moving the current executable: %w", err))
+	}
+
+	rollback := func(cause error) error {
+		_ = os.Remove(currentBin)
+		if err := os.Rename(backup, currentBin); err != nil {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("%w (and restoring the previous executable from %s failed: %w)", cause, backup, err),
+				Remediation: fmt.Sprintf("Move %s back to %s to restore the previous version.", backup, currentBin),
+			}
+		}
+		return cause
+	}
+
+	if err := os.Rename(newBin, currentBin); err != nil {
+		renameErr := err
+		// Failing that we'll try to io.Copy downloaded binary to the current binary.
+		if err := filesystem.CopyFile(newBin, currentBin); err != nil {
+			return rollback(replaceError(fmt.Errorf("error copying new executable into place: %w (following an error moving it: %w)", err, renameErr)))
+		}
+		if err := github.SetBinPerms(currentBin); err != nil {
+			return rollback(replaceError(err))
+		}
+	}
+
+	if err := smoke(currentBin); err != nil {
+		return rollback(fsterr.RemediationError{
+			Inner:       fmt.Errorf("the new executable failed to run, so the previous version was restored: %w", err),
+			Remediation: "Try a different release with --version, and report the issue: https://github.com/fastly/cli/issues/new?labels=bug&template=bug_report.md",
+		})
+	}
+
+	// On Windows the backup cannot be removed while this process is running.
+	_ = os.Remove(backup)
+	return nil
+}
+
+func get(client api.HTTPClient, endpoint string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close() // #nosec G307
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", endpoint, res.Status)
+	}
+	return io.ReadAll(res.Body)
+}
+
+func downloadError(err error) error {
+	return fsterr.RemediationError{
+		Inner:       err,
+		Remediation: downloadRemediation,
+	}
+}
+
+func replaceError(err error) error {
+	if errors.Is(err, fs.ErrPermission) {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("permission denied: %w", err),
+			Remediation: permissionRemediation,
+		}
+	}
+	return fsterr.RemediationError{
+		Inner:       err,
+		Remediation: fsterr.HostRemediation,
+	}
+}
@@ -5,11 +5,12 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/blang/semver"
 
 	"github.com/fastly/cli/pkg/argparser"
-	"github.com/fastly/cli/pkg/filesystem"
+	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/revision"
 	"github.com/fastly/cli/pkg/text"
@@ -19,6 +20,10 @@ import (
 // It should be installed under the primary root command.
 type RootCommand struct {
 	argparser.Base
+
+	changelog bool
+	channel   string
+	version   string
 }
 
 // NewRootCommand returns a new command registered in the parent.
@@ -26,6 +31,9 @@ func NewRootCommand(parent argparser.Registerer, g *global.Data) *RootCommand {
 	var c RootCommand
 	c.Globals = g
 	c.CmdClause = parent.Command("update", "Update the CLI to the latest version")
+	c.CmdClause.Flag("changelog", "Display the release notes of the versions an update would install, without updating").BoolVar(&c.changelog)
+	c.CmdClause.Flag("channel", "Release channel to update from").Default(ChannelStable).HintOptions(ChannelStable, ChannelPrerelease).EnumVar(&c.channel, ChannelStable, ChannelPrerelease)
+	c.CmdClause.Flag("version", "Install a specific release (e.g. v10.8.0), including older releases").StringVar(&c.version)
 	return &c
 }
 
@@ -36,49 +44,72 @@ func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
-	var (
-		current, latest semver.Version
-		shouldUpdate    bool
-	)
+	current, err := semver.Parse(strings.TrimPrefix(revision.AppVersion, "v"))
+	if err != nil {
+		current = semver.Version{}
+	}
 
-	err = spinner.Process("Updating versioning information", func(_ *text.SpinnerWrapper) error {
-		current, latest, shouldUpdate = Check(revision.AppVersion, c.Globals.Versioners.CLI)
-		return nil
+	var rel Release
+	err = spinner.Process("Fetching release information", func(_ *text.SpinnerWrapper) error {
+		rel, err = FindRelease(c.Globals.HTTPClient, c.channel, c.version)
+		return err
 	})
 	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Channel": c.channel,
+			"Version": c.version,
+		})
 		return err
 	}
 
+	label := "Latest version"
+	switch {
+	case c.version != "":
+		label = "Requested version"
+	case rel.Prerelease:
+		label = "Latest version (prerelease)"
+	}
+
 	text.Break(out)
 	text.Output(out, "Current version: %s", current)
-	text.Output(out, "Latest version: %s", latest)
+	text.Output(out, "%s: %s", label, rel.Version)
 	text.Break(out)
 
-	if !shouldUpdate {
+	if c.changelog {
+		return c.displayChangelog(out, current, rel)
+	}
+
+	// A pinned version is installed even if it is older than the current one.
+	if rel.Version.EQ(current) || (c.version == "" && !rel.Version.GT(current)) {
 		text.Output(out, "No update required.")
 		return nil
 	}
 
-	var downloadedBin string
-	err = spinner.Process("Fetching latest release", func(_ *text.SpinnerWrapper) error {
-		downloadedBin, err = c.Globals.Versioners.CLI.DownloadLatest()
-		if err != nil {
-			c.Globals.ErrLog.AddWithContext(err, map[string]any{
-				"Current CLI version": current,
-				"Latest CLI version":  latest,
-			})
-			return fmt.Errorf("error downloading latest release: %w", err)
+	tmpDir, err := os.MkdirTemp("", "fastly-update")
+	if err != nil {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("error creating temporary directory: %w", err),
+			Remediation: fsterr.HostRemediation,
 		}
-		return nil
+	}
+	defer os.RemoveAll(tmpDir)
+
+	var downloadedBin string
+	err = spinner.Process(fmt.Sprintf("Downloading and verifying v%s", rel.Version), func(_ *text.SpinnerWrapper) error {
+		downloadedBin, err = Download(c.Globals.HTTPClient, rel, tmpDir)
+		return err
 	})
 	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Current CLI version": current,
+			"Target CLI version":  rel.Version,
+		})
 		return err
 	}
-	defer os.RemoveAll(downloadedBin)
 
 	var currentBin string
 	err = spinner.Process("Replacing binary", func(_ *text.SpinnerWrapper) error {
-		execPath, err := os.Executable()
+		execPath, err := Executable()
 		if err != nil {
 			c.Globals.ErrLog.Add(err)
 			return fmt.Errorf("error determining executable path: %w", err)
@@ -92,44 +123,12 @@ func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
 			return fmt.Errorf("error determining absolute target path: %w", err)
 		}
 
-		// Windows does not permit replacing a running executable, however it will
-		// permit it if you first move the original executable. So we first move the
-		// running executable to a new location, then we move the executable that we
-		// downloaded to the same location as the original.
-		// I've also tested this approach on nix systems and it works fine.
-		//
-		// Reference:
-		// https://github.com/golang/go/issues/21997#issuecomment-331744930
-
-		backup := currentBin + ".bak"
-		if err := os.Rename(currentBin, backup); err != nil {
-			c.Globals.ErrLog.AddWithContext(err, map[string]any{
-				"Executable (source)":      downloadedBin,
-				"Executable (destination)": currentBin,
-			})
-			return fmt.Errorf("error moving the current executable: %w", err)
-		}
-
-		if err = os.Remove(backup); err != nil {
-			c.Globals.ErrLog.Add(err)
-		}
-
-		// Move the downloaded binary to the same location as the current executable.
-		if err := os.Rename(downloadedBin, currentBin); err != nil {
+		if err := Install(currentBin, downloadedBin, SmokeTest); err != nil {
 			c.Globals.ErrLog.AddWithContext(err, map[string]any{
 				"Executable (source)":      downloadedBin,
 				"Executable (destination)": currentBin,
 			})
-			renameErr := err
-
-			// Failing that we'll try to io.Copy downloaded binary to the current binary.
-			if err := filesystem.CopyFile(downloadedBin, currentBin); err != nil {
-				c.Globals.ErrLog.AddWithContext(err, map[string]any{
-					"Executable (source)":      downloadedBin,
-					"Executable (destination)": currentBin,
-				})
-				return fmt.Errorf("error 'copying' latest binary in place: %w (following an error 'moving': %w)", err, renameErr)
-			}
+			return err
 		}
 		return nil
 	})
@@ -137,6 +136,73 @@ func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
-	text.Success(out, "\nUpdated %s to %s.", currentBin, latest)
+	text.Success(out, "\nUpdated %s to %s.", currentBin, rel.Version)
+
+	c.Globals.Config.UpdateCheck.PreviousVersion = current.String()
+	if err := c.Globals.Config.Write(c.Globals.ConfigPath); err != nil {
+		c.Globals.ErrLog.Add(err)
+	}
+	if rel.Version.GT(current) {
+		c.displayUpdateNotes(out, current, rel.Version)
+	}
 	return nil
 }
+
+// displayChangelog displays the release notes of the versions an update to rel
+// would install (see --changelog). Without an update, they're the notes of the
+// versions installed by the last `fastly update`, if the CLI recorded it.
+func (c *RootCommand) displayChangelog(out io.Writer, current semver.Version, rel Release) error {
+	from, to := current, rel.Version
+	intro := fmt.Sprintf("Release notes of the versions since %s:", current)
+	if !rel.Version.GT(current) {
+		switch previous, err := semver.Parse(c.Globals.Config.UpdateCheck.PreviousVersion); {
+		case rel.Version.LT(current):
+			from, to = rel.Version, current
+			intro = fmt.Sprintf("Release notes of the versions installing %s would remove:", rel.Version)
+		case err == nil && previous.LT(current):
+			from = previous
+			intro = fmt.Sprintf("No update required. Release notes of the versions since %s, the version the last update replaced:", previous)
+		default:
+			text.Output(out, "No update required. The release notes are available at %s", ReleasesPageURL)
+			return nil
+		}
+	}
+
+	notes, err := FetchReleaseNotes(c.Globals.HTTPClient, from, to)
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"From": from,
+			"To":   to,
+		})
+		return downloadError(err)
+	}
+	if len(notes) == 0 {
+		text.Output(out, "No release notes were published for the versions after %s up to %s.", from, to)
+		return nil
+	}
+	text.Info(out, intro)
+	text.Break(out)
+	DisplayReleaseNotes(out, notes, MaxReleaseNotesLines)
+	return nil
+}
+
+// displayUpdateNotes displays the release notes of the versions an update
+// installed. The notes are non-essential, so they're skipped when offline and
+// if they can't be fetched.
+func (c *RootCommand) displayUpdateNotes(out io.Writer, from, to semver.Version) {
+	if c.Globals.Offline() || text.IsQuiet() {
+		return
+	}
+	notes, err := FetchReleaseNotes(c.Globals.HTTPClient, from, to)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return
+	}
+	if len(notes) == 0 {
+		return
+	}
+	text.Break(out)
+	text.Info(out, "What's new since %s:", from)
+	text.Break(out)
+	DisplayReleaseNotes(out, notes, MaxReleaseNotesLines)
+}
@@ -0,0 +1,266 @@
+package update_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/update"
+	"github.com/fastly/cli/pkg/global"
+	fstruntime "github.com/fastly/cli/pkg/runtime"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+// releaseServer is a fake GitHub releases API serving fixture archives.
+type releaseServer struct {
+	*httptest.Server
+	// badChecksum lists versions whose published checksum is wrong.
+	badChecksum map[string]bool
+	// notes are the release notes, keyed by version.
+	notes map[string]string
+	// notesDown causes the release notes to fail to be listed.
+	notesDown bool
+	// releases are the published releases, keyed by version.
+	releases map[string]bool // version -> prerelease
+}
+
+func newReleaseServer(t *testing.T) *releaseServer {
+	t.Helper()
+	rs := &releaseServer{
+		badChecksum: map[string]bool{"9.1.0": true},
+		notes: map[string]string{
+			"9.0.0": "## What's Changed\n* feat: first of v9 by @dev in https://github.com/fastly/cli/pull/1\n",
+			// Malformed: CRLF line endings, a template comment, an escape sequence,
+			// unterminated bold and an unclosed code fence.
+			"9.1.0":        "<!-- Release template: describe the changes -->\r\n## Fixes\r\n* fix: **unterminated bold\r\n```\r\nunclosed \x1b[31mfence\r\n",
+			"9.2.0":        "## What's Changed\n* feat: release notes by @dev\n\n**Full Changelog**: https://github.com/fastly/cli/compare/v9.1.0...v9.2.0",
+			"10.0.0-beta1": "Beta notes.",
+		},
+		releases: map[string]bool{
+			"9.0.0":        false,
+			"9.1.0":        false,
+			"9.2.0":        false,
+			"10.0.0-beta1": true,
+		},
+	}
+	rs.Server = httptest.NewServer(http.HandlerFunc(rs.handle))
+	t.Cleanup(rs.Close)
+	return rs
+}
+
+func (rs *releaseServer) release(version string) map[string]any {
+	v := semver.MustParse(version)
+	return map[string]any{
+		"tag_name":   "v" + version,
+		"prerelease": rs.releases[version],
+		"body":       rs.notes[version],
+		"html_url":   "https://github.com/fastly/cli/releases/tag/v" + version,
+		"assets": []map[string]string{
+			{"name": update.ArchiveName(v), "browser_download_url": fmt.Sprintf("%s/download/%s/archive", rs.URL, version)},
+			{"name": fmt.Sprintf("fastly_v%s_SHA256SUMS", version), "browser_download_url": fmt.Sprintf("%s/download/%s/sums", rs.URL, version)},
+		},
+	}
+}
+
+func (rs *releaseServer) handle(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	switch {
+	case path == "releases":
+		// The release notes are listed a page at a time.
+		if rs.notesDown && r.URL.Query().Get("per_page") != "" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		list := []map[string]any{
+			{"tag_name": "v9.3.0", "draft": true, "body": "Draft notes."},
+			{"tag_name": "nightly", "body": "Nightly notes."},
+		}
+		for v := range rs.releases {
+			list = append(list, rs.release(v))
+		}
+		_ = json.NewEncoder(w).Encode(list)
+	case path == "releases/latest":
+		_ = json.NewEncoder(w).Encode(rs.release("9.2.0"))
+	case strings.HasPrefix(path, "releases/tags/v"):
+		v := strings.TrimPrefix(path, "releases/tags/v")
+		if _, ok := rs.releases[v]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(rs.release(v))
+	case strings.HasPrefix(path, "download/"):
+		parts := strings.Split(path, "/")
+		v := parts[1]
+		archive := fixtureArchive(v)
+		if parts[2] == "archive" {
+			_, _ = w.Write(archive)
+			return
+		}
+		sum := sha256.Sum256(archive)
+		digest := hex.EncodeToString(sum[:])
+		if rs.badChecksum[v] {
+			digest = strings.Repeat("0", len(digest))
+		}
+		fmt.Fprintf(w, "%s  %s\n", digest, update.ArchiveName(semver.MustParse(v)))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// fixtureArchive returns a tar.gz containing a fastly shell script that
+// prints the version.
+func fixtureArchive(version string) []byte {
+	script := fmt.Sprintf("#!/bin/sh\necho %s\n", version)
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	_ = tw.WriteHeader(&tar.Header{Name: "fastly", Mode: 0o755, Size: int64(len(script))})
+	_, _ = tw.Write([]byte(script))
+	_ = tw.Close()
+	_ = gz.Close()
+	return buf.Bytes()
+}
+
+func TestUpdate(t *testing.T) {
+	if fstruntime.Windows {
+		t.Skip("fixture archives are tar.gz")
+	}
+
+	rs := newReleaseServer(t)
+	defer func(url string, exe func() (string, error), smoke func(string) error) {
+		update.ReleasesURL = url
+		update.Executable = exe
+		update.SmokeTest = smoke
+	}(update.ReleasesURL, update.Executable, update.SmokeTest)
+	update.ReleasesURL = rs.URL + "/releases"
+
+	scenarios := []struct {
+		name       string
+		args       string
+		smokeErr   error
+		wantError  string
+		wantOutput string
+		wantBinary string
+	}{
+		{
+			name:       "stable channel installs the latest release",
+			args:       "update",
+			wantOutput: "Updated",
+			wantBinary: "9.2.0",
+		},
+		{
+			name:       "prerelease channel installs the newest prerelease",
+			args:       "update --channel prerelease",
+			wantOutput: "Latest version (prerelease): 10.0.0-beta1",
+			wantBinary: "10.0.0-beta1",
+		},
+		{
+			name:       "pinned version",
+			args:       "update --version v9.0.0",
+			wantOutput: "Requested version: 9.0.0",
+			wantBinary: "9.0.0",
+		},
+		{
+			name:       "unknown version",
+			args:       "update --version v1.2.3",
+			wantError:  "error fetching release metadata",
+			wantBinary: "old",
+		},
+		{
+			name:       "bad checksum",
+			args:       "update --version 9.1.0",
+			wantError:  "checksum verification failed",
+			wantBinary: "old",
+		},
+		{
+			name:       "failed smoke test rolls back",
+			args:       "update",
+			smokeErr:   errors.New("exec format error"),
+			wantError:  "the new executable failed to run, so the previous version was restored",
+			wantBinary: "old",
+		},
+	}
+
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			bin := filepath.Join(t.TempDir(), "fastly")
+			if err := os.WriteFile(bin, []byte("old"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			update.Executable = func() (string, error) { return bin, nil }
+			update.SmokeTest = func(string) error { return testcase.smokeErr }
+
+			args := testutil.Args(testcase.args)
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				return testutil.MockGlobalData(args, &stdout), nil
+			}
+			err := app.Run(args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+
+			got, err := os.ReadFile(bin)
+			if err != nil {
+				t.Fatal(err)
+			}
+			testutil.AssertStringContains(t, string(got), testcase.wantBinary)
+			if _, err := os.Stat(bin + ".bak"); err == nil {
+				t.Errorf("unexpected backup left behind")
+			}
+		})
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("archive")
+	sum := sha256.Sum256(data)
+	sums := []byte(hex.EncodeToString(sum[:]) + "  fastly.tar.gz\nabc  other.tar.gz\n")
+
+	if err := update.VerifyChecksum(data, "fastly.tar.gz", sums); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	var ce update.ChecksumError
+	if err := update.VerifyChecksum([]byte("tampered"), "fastly.tar.gz", sums); !errors.As(err, &ce) {
+		t.Errorf("want ChecksumError, got %v", err)
+	}
+	if err := update.VerifyChecksum(data, "missing.tar.gz", sums); !errors.As(err, &ce) {
+		t.Errorf("want ChecksumError, got %v", err)
+	}
+}
+
+func TestInstallPermissionDenied(t *testing.T) {
+	if fstruntime.Windows || os.Getuid() == 0 {
+		t.Skip("requires POSIX permissions and a non-root user")
+	}
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "fastly")
+	newBin := filepath.Join(t.TempDir(), "fastly")
+	for _, f := range []string{bin, newBin} {
+		if err := os.WriteFile(f, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chmod(dir, 0o500); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(dir, 0o700) // #nosec G302
+
+	err := update.Install(bin, newBin, func(string) error { return nil })
+	testutil.AssertErrorContains(t, err, "permission denied")
+}
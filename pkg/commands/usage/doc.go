@@ -0,0 +1,3 @@
+// Package usage contains a command to report resource usage against plan
+// limits.
+package usage
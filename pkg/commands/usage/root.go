@@ -0,0 +1,221 @@
+package usage
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// WarnPercent is the percentage of a limit above which usage is highlighted.
+const WarnPercent = 80
+
+// barWidth is the number of characters in a usage bar.
+const barWidth = 20
+
+// Usage is the usage of a resource against its limit.
+type Usage struct {
+	// Resource is the type of resource counted.
+	Resource string `json:"resource"`
+	// Scope is what the resource is counted within.
+	Scope string `json:"scope"`
+	// Used is the number of resources.
+	Used int `json:"used"`
+	// Limit is the maximum number of resources, or nil when unknown.
+	Limit *int `json:"limit"`
+	// Percent is the percentage of the limit used, or nil when unknown.
+	Percent *float64 `json:"percent"`
+}
+
+// RootCommand reports on resource usage against plan limits.
+type RootCommand struct {
+	argparser.Base
+	argparser.JSONOutput
+
+	failAt int
+}
+
+// NewRootCommand returns a new command registered in the parent.
+func NewRootCommand(parent argparser.Registerer, g *global.Data) *RootCommand {
+	var c RootCommand
+	c.Globals = g
+	c.CmdClause = parent.Command("usage", "Report the account's resource usage against plan limits")
+	c.CmdClause.Flag("fail-at", "Exit with an error if any resource uses at least this percentage of its limit").IntVar(&c.failAt)
+	c.RegisterFlagBool(c.JSONFlag()) // --json
+	return &c
+}
+
+// Exec implements the command interface.
+func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+	if c.failAt < 0 || c.failAt > 100 {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid --fail-at value %d", c.failAt),
+			Remediation: "Provide a percentage between 1 and 100.",
+		}
+	}
+
+	usage, err := c.usage()
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
+	if ok, err := c.WriteJSON(out, usage); ok {
+		if err != nil {
+			return err
+		}
+		return c.checkThreshold(usage)
+	}
+
+	t := text.NewTable(out)
+	t.AddHeader("RESOURCE", "SCOPE", "USED", "LIMIT", "USAGE")
+	for _, u := range usage {
+		limit := "n/a"
+		if u.Limit != nil {
+			limit = strconv.Itoa(*u.Limit)
+		}
+		t.AddLine(u.Resource, u.Scope, u.Used, limit, Bar(u.Percent))
+	}
+	t.Print()
+
+	if !hasLimits(usage) {
+		text.Break(out)
+		text.Info(out, "Plan limits aren't available from the API. Set them in the [limits] section of the CLI config file to see usage percentages.")
+	}
+
+	return c.checkThreshold(usage)
+}
+
+// usage counts the services on the account, the dictionaries on each service,
+// and the items in each dictionary.
+func (c *RootCommand) usage() ([]Usage, error) {
+	limits := c.Globals.Config.Limits
+
+	services, err := argparser.ListServices(c.Globals.APIClient)
+	if err != nil {
+		return nil, err
+	}
+	usage := []Usage{NewUsage("services", "account", len(services), limits.Services)}
+
+	for _, s := range services {
+		serviceID := fastly.ToValue(s.ServiceID)
+		version := serviceVersion(s)
+		if version == 0 {
+			continue
+		}
+		scope := fastly.ToValue(s.Name)
+		if scope == "" {
+			scope = serviceID
+		}
+
+		dictionaries, err := c.Globals.APIClient.ListDictionaries(&fastly.ListDictionariesInput{
+			ServiceID:      serviceID,
+			ServiceVersion: version,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error listing dictionaries for service %s: %w", serviceID, err)
+		}
+		usage = append(usage, NewUsage("dictionaries", scope, len(dictionaries), limits.DictionariesPerService))
+
+		for _, d := range dictionaries {
+			info, err := c.Globals.APIClient.GetDictionaryInfo(&fastly.GetDictionaryInfoInput{
+				ServiceID:      serviceID,
+				ServiceVersion: version,
+				DictionaryID:   fastly.ToValue(d.DictionaryID),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error getting dictionary info for dictionary %s: %w", fastly.ToValue(d.Name), err)
+			}
+			usage = append(usage, NewUsage("dictionary items", scope+"/"+fastly.ToValue(d.Name), fastly.ToValue(info.ItemCount), limits.DictionaryItems))
+		}
+	}
+
+	return usage, nil
+}
+
+// checkThreshold returns an error if any usage reaches the --fail-at
+// percentage.
+func (c *RootCommand) checkThreshold(usage []Usage) error {
+	if c.failAt == 0 {
+		return nil
+	}
+	var over []string
+	for _, u := range usage {
+		if u.Percent != nil && *u.Percent >= float64(c.failAt) {
+			over = append(over, fmt.Sprintf("%s (%s)", u.Resource, u.Scope))
+		}
+	}
+	if len(over) == 0 {
+		return nil
+	}
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("%d resource(s) at or above %d%% of their limit: %s", len(over), c.failAt, strings.Join(over, ", ")),
+		Remediation: "Remove unused resources, or contact support@fastly.com to discuss raising the limits on your plan.",
+	}
+}
+
+// NewUsage returns the usage of a resource. A limit of zero means the limit is
+// unknown.
+func NewUsage(resource, scope string, used, limit int) Usage {
+	u := Usage{
+		Resource: resource,
+		Scope:    scope,
+		Used:     used,
+	}
+	if limit > 0 {
+		pct := float64(used) / float64(limit) * 100
+		u.Limit = &limit
+		u.Percent = &pct
+	}
+	return u
+}
+
+// Bar renders a percentage as a bar, highlighting usage over WarnPercent.
+func Bar(percent *float64) string {
+	if percent == nil {
+		return "n/a"
+	}
+	filled := int(*percent / 100 * barWidth)
+	if filled > barWidth {
+		filled = barWidth
+	}
+	bar := fmt.Sprintf("[%s%s] %.0f%%", strings.Repeat("#", filled), strings.Repeat("-", barWidth-filled), *percent)
+	if *percent > WarnPercent {
+		return text.ErrorStyle(bar)
+	}
+	return bar
+}
+
+// serviceVersion returns the active version of a service, falling back to
+// the latest version if none is active.
+func serviceVersion(s *fastly.Service) int {
+	if v := fastly.ToValue(s.ActiveVersion); v > 0 {
+		return v
+	}
+	var latest int
+	for _, v := range s.Versions {
+		if n := fastly.ToValue(v.Number); n > latest {
+			latest = n
+		}
+	}
+	return latest
+}
+
+func hasLimits(usage []Usage) bool {
+	for _, u := range usage {
+		if u.Limit != nil {
+			return true
+		}
+	}
+	return false
+}
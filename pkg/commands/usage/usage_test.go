@@ -0,0 +1,182 @@
+package usage_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/commands/usage"
+	"github.com/fastly/cli/pkg/config"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestUsage(t *testing.T) {
+	args := testutil.Args
+	api := mock.API{
+		GetServicesFn:       getServices,
+		ListDictionariesFn:  listDictionaries,
+		GetDictionaryInfoFn: getDictionaryInfo,
+	}
+
+	scenarios := []struct {
+		testutil.TestScenario
+		limits config.Limits
+	}{
+		{
+			TestScenario: testutil.TestScenario{
+				Name:       "limits unknown",
+				API:        api,
+				Args:       args("usage"),
+				WantOutput: "n/a",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:       "limits configured",
+				API:        api,
+				Args:       args("usage"),
+				WantOutput: "[#################---] 85%",
+			},
+			limits: config.Limits{DictionaryItems: 1000, DictionariesPerService: 100, Services: 10},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "fail at threshold",
+				API:       api,
+				Args:      args("usage --fail-at 80"),
+				WantError: "1 resource(s) at or above 80% of their limit: dictionary items (Foo/geo)",
+			},
+			limits: config.Limits{DictionaryItems: 1000},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "below threshold",
+				API:  api,
+				Args: args("usage --fail-at 90"),
+			},
+			limits: config.Limits{DictionaryItems: 1000},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "threshold ignored when limits unknown",
+				API:  api,
+				Args: args("usage --fail-at 1"),
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "invalid threshold",
+				Args:      args("usage --fail-at 101"),
+				WantError: "invalid --fail-at value 101",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name: "API error",
+				API: mock.API{
+					GetServicesFn:      getServices,
+					ListDictionariesFn: func(_ *fastly.ListDictionariesInput) ([]*fastly.Dictionary, error) { return nil, testutil.Err },
+				},
+				Args:      args("usage"),
+				WantError: testutil.Err.Error(),
+			},
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(testcase.Args, &stdout)
+				opts.APIClientFactory = mock.APIClient(testcase.API)
+				opts.Config.Limits = testcase.limits
+				return opts, nil
+			}
+			err := app.Run(testcase.Args, nil)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+		})
+	}
+}
+
+func TestUsageJSON(t *testing.T) {
+	args := testutil.Args("usage --json")
+	api := mock.API{
+		GetServicesFn:       getServices,
+		ListDictionariesFn:  listDictionaries,
+		GetDictionaryInfoFn: getDictionaryInfo,
+	}
+
+	var stdout bytes.Buffer
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		opts := testutil.MockGlobalData(args, &stdout)
+		opts.APIClientFactory = mock.APIClient(api)
+		opts.Config.Limits = config.Limits{Services: 4}
+		return opts, nil
+	}
+	if err := app.Run(args, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []usage.Usage
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout.String())
+	}
+	if len(got) != 3 {
+		t.Fatalf("want 3 usage rows, got %+v", got)
+	}
+	if got[0].Resource != "services" || got[0].Used != 1 || fastly.ToValue(got[0].Limit) != 4 || fastly.ToValue(got[0].Percent) != 25 {
+		t.Errorf("unexpected services usage: %+v", got[0])
+	}
+	if got[2].Resource != "dictionary items" || got[2].Used != 850 || got[2].Limit != nil || got[2].Percent != nil {
+		t.Errorf("unexpected dictionary items usage: %+v", got[2])
+	}
+}
+
+func TestBar(t *testing.T) {
+	if got := usage.Bar(nil); got != "n/a" {
+		t.Errorf("want n/a, got %q", got)
+	}
+	for pct, want := range map[float64]string{
+		0:   "[--------------------] 0%",
+		50:  "[##########----------] 50%",
+		150: "[####################] 150%",
+	} {
+		pct := pct
+		if got := usage.Bar(&pct); !strings.Contains(got, want) {
+			t.Errorf("Bar(%v) = %q, want %q", pct, got, want)
+		}
+	}
+}
+
+func getServices(_ *fastly.GetServicesInput) *fastly.ListPaginator[fastly.Service] {
+	return fastly.NewPaginator[fastly.Service](mock.HTTPClient{
+		Errors: []error{nil},
+		Responses: []*http.Response{
+			{
+				Body: io.NopCloser(strings.NewReader(`[
+					{"name": "Foo", "id": "123", "type": "vcl", "version": 2}
+				]`)),
+			},
+		},
+	}, fastly.ListOpts{}, "/example")
+}
+
+func listDictionaries(i *fastly.ListDictionariesInput) ([]*fastly.Dictionary, error) {
+	return []*fastly.Dictionary{
+		{DictionaryID: fastly.ToPointer("abc"), Name: fastly.ToPointer("geo"), ServiceID: fastly.ToPointer(i.ServiceID)},
+	}, nil
+}
+
+func getDictionaryInfo(_ *fastly.GetDictionaryInfoInput) (*fastly.DictionaryInfo, error) {
+	return &fastly.DictionaryInfo{ItemCount: fastly.ToPointer(850)}, nil
+}
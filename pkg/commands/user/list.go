@@ -16,6 +16,7 @@ import (
 func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 	var c ListCommand
 	c.CmdClause = parent.Command("list", "List all users from a specified customer id")
+	c.EmptyState = argparser.EmptyState{Resource: "users", Create: "fastly user create --login <LOGIN> --name <NAME>"}
 	c.Globals = g
 	c.RegisterFlag(argparser.StringFlagOpts{
 		Name:        argparser.FlagCustomerIDName,
@@ -23,7 +24,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 		Dst:         &c.customerID.Value,
 		Action:      c.customerID.Set,
 	})
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	return &c
 }
 
@@ -31,6 +32,7 @@ func NewListCommand(parent argparser.Registerer, g *global.Data) *ListCommand {
 type ListCommand struct {
 	argparser.Base
 	argparser.JSONOutput
+	argparser.EmptyState
 
 	customerID argparser.OptionalCustomerID
 }
@@ -58,6 +60,10 @@ func (c *ListCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
+	if c.PrintEmpty(out, len(o)) {
+		return nil
+	}
+
 	if c.Globals.Verbose() {
 		c.printVerbose(out, o)
 	} else {
@@ -95,13 +101,13 @@ func (c *ListCommand) printVerbose(out io.Writer, us []*fastly.User) {
 		fmt.Fprintf(out, "Two Factor Setup Required: %t\n\n", fastly.ToValue(u.TwoFactorSetupRequired))
 
 		if u.CreatedAt != nil {
-			fmt.Fprintf(out, "Created at: %s\n", u.CreatedAt)
+			fmt.Fprintf(out, "Created at: %s\n", text.FormatTime(u.CreatedAt))
 		}
 		if u.UpdatedAt != nil {
-			fmt.Fprintf(out, "Updated at: %s\n", u.UpdatedAt)
+			fmt.Fprintf(out, "Updated at: %s\n", text.FormatTime(u.UpdatedAt))
 		}
 		if u.DeletedAt != nil {
-			fmt.Fprintf(out, "Deleted at: %s\n", u.DeletedAt)
+			fmt.Fprintf(out, "Deleted at: %s\n", text.FormatTime(u.DeletedAt))
 		}
 	}
 }
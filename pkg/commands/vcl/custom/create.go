@@ -22,6 +22,7 @@ func NewCreateCommand(parent argparser.Registerer, g *global.Data) *CreateComman
 
 	// Required.
 	c.CmdClause.Flag("content", "VCL passed as file path or content, e.g. $(< main.vcl)").Action(c.content.Set).StringVar(&c.content.Value)
+	c.CmdClause.Flag("content-file", "Path to a file containing the VCL ('-' for stdin)").Action(argparser.StdinPath(&c.contentFile)).StringVar(&c.contentFile)
 	c.RegisterFlag(argparser.StringFlagOpts{
 		Name:        argparser.FlagVersionName,
 		Description: argparser.FlagVersionDesc,
@@ -58,6 +59,7 @@ type CreateCommand struct {
 
 	autoClone      argparser.OptionalAutoClone
 	content        argparser.OptionalString
+	contentFile    string
 	main           argparser.OptionalBool
 	name           argparser.OptionalString
 	serviceName    argparser.OptionalServiceNameID
@@ -65,7 +67,13 @@ type CreateCommand struct {
 }
 
 // Exec invokes the application logic for the command.
-func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
+func (c *CreateCommand) Exec(in io.Reader, out io.Writer) error {
+	content, hasContent, err := argparser.ResolveContent(c.content, c.contentFile, in)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
 	serviceID, serviceVersion, err := argparser.ServiceDetails(argparser.ServiceDetailsOpts{
 		AutoCloneFlag:      c.autoClone,
 		APIClient:          c.Globals.APIClient,
@@ -84,6 +92,9 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 	}
 
 	input := c.constructInput(serviceID, fastly.ToValue(serviceVersion.Number))
+	if hasContent {
+		input.Content = &content
+	}
 
 	v, err := c.Globals.APIClient.CreateVCL(input)
 	if err != nil {
@@ -113,9 +124,6 @@ func (c *CreateCommand) constructInput(serviceID string, serviceVersion int) *fa
 	if c.name.WasSet {
 		input.Name = &c.name.Value
 	}
-	if c.content.WasSet {
-		input.Content = fastly.ToPointer(argparser.Content(c.content.Value))
-	}
 	if c.main.WasSet {
 		input.Main = fastly.ToPointer(c.main.Value)
 	}
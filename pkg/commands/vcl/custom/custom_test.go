@@ -3,6 +3,7 @@ package custom_test
 import (
 	"bytes"
 	"io"
+	"strings"
 	"testing"
 
 	"github.com/fastly/go-fastly/v9/fastly"
@@ -444,6 +445,7 @@ func TestVCLCustomUpdate(t *testing.T) {
 			Name: "validate UpdateVCL API success with --content",
 			API: mock.API{
 				ListVersionsFn: testutil.ListVersions,
+				GetVCLFn:       getVCL,
 				UpdateVCLFn: func(i *fastly.UpdateVCLInput) (*fastly.VCL, error) {
 					// Track the contents parsed
 					content = *i.Content
@@ -457,7 +459,7 @@ func TestVCLCustomUpdate(t *testing.T) {
 					}, nil
 				},
 			},
-			Args:       args("vcl custom update --content updated --name foobar --service-id 123 --version 3"),
+			Args:       args("vcl custom update --content updated --name foobar --service-id 123 --version 3 --auto-yes"),
 			WantOutput: "Updated custom VCL 'foobar' (service: 123, version: 3)",
 		},
 		{
@@ -465,6 +467,7 @@ func TestVCLCustomUpdate(t *testing.T) {
 			API: mock.API{
 				ListVersionsFn: testutil.ListVersions,
 				CloneVersionFn: testutil.CloneVersionResult(4),
+				GetVCLFn:       getVCL,
 				UpdateVCLFn: func(i *fastly.UpdateVCLInput) (*fastly.VCL, error) {
 					// Track the contents parsed
 					content = *i.Content
@@ -478,7 +481,7 @@ func TestVCLCustomUpdate(t *testing.T) {
 					}, nil
 				},
 			},
-			Args:       args("vcl custom update --autoclone --content ./testdata/example.vcl --name foo --service-id 123 --version 1"),
+			Args:       args("vcl custom update --autoclone --content ./testdata/example.vcl --name foo --service-id 123 --version 1 --auto-yes"),
 			WantOutput: "Updated custom VCL 'foo' (service: 123, version: 4)",
 		},
 	}
@@ -500,6 +503,97 @@ func TestVCLCustomUpdate(t *testing.T) {
 	}
 }
 
+func TestVCLCustomUpdateDiff(t *testing.T) {
+	args := testutil.Args
+	scenarios := []struct {
+		testutil.TestScenario
+		stdin      string
+		wantUpdate bool
+	}{
+		{
+			TestScenario: testutil.TestScenario{
+				Name:       "identical content short-circuits",
+				Args:       args("vcl custom update --name foobar --content-file ./testdata/unchanged.vcl --service-id 123 --version 3"),
+				WantOutput: "No changes: custom VCL 'foobar' is identical to the local content",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:       "diff confirmed",
+				Args:       args("vcl custom update --name foobar --content-file ./testdata/updated.vcl --service-id 123 --version 3"),
+				WantOutput: "-# some vcl content",
+			},
+			stdin:      "y\n",
+			wantUpdate: true,
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:       "diff declined",
+				Args:       args("vcl custom update --name foobar --content-file ./testdata/updated.vcl --service-id 123 --version 3"),
+				WantError:  "operation not confirmed",
+				WantOutput: "Apply these changes? [y/N]",
+			},
+			stdin: "n\n",
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:       "diff with --non-interactive",
+				Args:       args("vcl custom update --name foobar --content-file ./testdata/updated.vcl --service-id 123 --version 3 --non-interactive"),
+				WantError:  "confirmation required to update foobar",
+				WantOutput: "foobar (local)",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "content from stdin requires --auto-yes",
+				Args:      args("vcl custom update --name foobar --content-file - --service-id 123 --version 3"),
+				WantError: "confirmation required to update foobar",
+			},
+			stdin: "# new content\n",
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "content flags are mutually exclusive",
+				Args:      args("vcl custom update --name foobar --content x --content-file ./testdata/example.vcl --service-id 123 --version 3"),
+				WantError: "--content and --content-file are mutually exclusive",
+			},
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var (
+				stdout  bytes.Buffer
+				updated bool
+			)
+			api := mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				GetVCLFn:       getVCL,
+				UpdateVCLFn: func(i *fastly.UpdateVCLInput) (*fastly.VCL, error) {
+					updated = true
+					return &fastly.VCL{
+						Content:        i.Content,
+						Name:           fastly.ToPointer(i.Name),
+						ServiceID:      fastly.ToPointer(i.ServiceID),
+						ServiceVersion: fastly.ToPointer(i.ServiceVersion),
+					}, nil
+				},
+			}
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(testcase.Args, &stdout)
+				opts.APIClientFactory = mock.APIClient(api)
+				opts.Input = strings.NewReader(testcase.stdin)
+				return opts, nil
+			}
+			err := app.Run(testcase.Args, nil)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+			testutil.AssertBool(t, testcase.wantUpdate, updated)
+		})
+	}
+}
+
 func getVCL(i *fastly.GetVCLInput) (*fastly.VCL, error) {
 	t := testutil.Date
 
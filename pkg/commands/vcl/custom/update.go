@@ -1,6 +1,7 @@
 package custom
 
 import (
+	"errors"
 	"fmt"
 	"io"
 
@@ -37,6 +38,7 @@ func NewUpdateCommand(parent argparser.Registerer, g *global.Data) *UpdateComman
 	})
 	c.CmdClause.Flag("new-name", "New name for the VCL").Action(c.newName.Set).StringVar(&c.newName.Value)
 	c.CmdClause.Flag("content", "VCL passed as file path or content, e.g. $(< main.vcl)").Action(c.content.Set).StringVar(&c.content.Value)
+	c.CmdClause.Flag("content-file", "Path to a file containing the VCL ('-' for stdin). The changes are displayed and must be confirmed unless --auto-yes is set").Action(argparser.StdinPath(&c.contentFile)).StringVar(&c.contentFile)
 	c.RegisterFlag(argparser.StringFlagOpts{
 		Name:        argparser.FlagServiceIDName,
 		Description: argparser.FlagServiceIDDesc,
@@ -59,6 +61,7 @@ type UpdateCommand struct {
 
 	autoClone      argparser.OptionalAutoClone
 	content        argparser.OptionalString
+	contentFile    string
 	name           string
 	newName        argparser.OptionalString
 	serviceName    argparser.OptionalServiceNameID
@@ -66,7 +69,13 @@ type UpdateCommand struct {
 }
 
 // Exec invokes the application logic for the command.
-func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
+func (c *UpdateCommand) Exec(in io.Reader, out io.Writer) error {
+	content, hasContent, err := argparser.ResolveContent(c.content, c.contentFile, in)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
 	serviceID, serviceVersion, err := argparser.ServiceDetails(argparser.ServiceDetailsOpts{
 		AutoCloneFlag:      c.autoClone,
 		APIClient:          c.Globals.APIClient,
@@ -84,7 +93,7 @@ func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
-	input, err := c.constructInput(serviceID, fastly.ToValue(serviceVersion.Number))
+	input, err := c.constructInput(serviceID, fastly.ToValue(serviceVersion.Number), hasContent)
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
 			"Service ID":      serviceID,
@@ -93,6 +102,47 @@ func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
 		return err
 	}
 
+	if hasContent {
+		current, err := c.Globals.APIClient.GetVCL(&fastly.GetVCLInput{
+			Name:           c.name,
+			ServiceID:      serviceID,
+			ServiceVersion: fastly.ToValue(serviceVersion.Number),
+		})
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID":      serviceID,
+				"Service Version": fastly.ToValue(serviceVersion.Number),
+			})
+			return err
+		}
+
+		// NOTE: The content can't be confirmed when it was read from stdin.
+		prompt := in
+		if c.contentFile == "-" {
+			prompt = nil
+		}
+		err = argparser.ConfirmChange(argparser.ConfirmChangeOpts{
+			Globals: c.Globals,
+			In:      prompt,
+			Local:   content,
+			Name:    c.name,
+			Out:     out,
+			Remote:  fastly.ToValue(current.Content),
+		})
+		switch {
+		case errors.Is(err, argparser.ErrNoChanges):
+			if !c.newName.WasSet {
+				text.Info(out, "No changes: custom VCL '%s' is identical to the local content", c.name)
+				return nil
+			}
+		case err != nil:
+			c.Globals.ErrLog.Add(err)
+			return err
+		default:
+			input.Content = &content
+		}
+	}
+
 	v, err := c.Globals.APIClient.UpdateVCL(input)
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
@@ -122,22 +172,19 @@ func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
 }
 
 // constructInput transforms values parsed from CLI flags into an object to be used by the API client library.
-func (c *UpdateCommand) constructInput(serviceID string, serviceVersion int) (*fastly.UpdateVCLInput, error) {
+func (c *UpdateCommand) constructInput(serviceID string, serviceVersion int, hasContent bool) (*fastly.UpdateVCLInput, error) {
 	var input fastly.UpdateVCLInput
 
 	input.Name = c.name
 	input.ServiceID = serviceID
 	input.ServiceVersion = serviceVersion
 
-	if !c.newName.WasSet && !c.content.WasSet {
-		return nil, fmt.Errorf("error parsing arguments: must provide either --new-name or --content to update the VCL")
+	if !c.newName.WasSet && !hasContent {
+		return nil, fmt.Errorf("error parsing arguments: must provide either --new-name, --content or --content-file to update the VCL")
 	}
 	if c.newName.WasSet {
 		input.NewName = &c.newName.Value
 	}
-	if c.content.WasSet {
-		input.Content = fastly.ToPointer(argparser.Content(c.content.Value))
-	}
 
 	return &input, nil
 }
@@ -1,6 +1,7 @@
 package snippet
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/fastly/go-fastly/v9/fastly"
@@ -37,7 +38,9 @@ func NewCreateCommand(parent argparser.Registerer, g *global.Data) *CreateComman
 		Dst:    &c.autoClone.Value,
 	})
 	c.CmdClause.Flag("content", "VCL snippet passed as file path or content, e.g. $(< snippet.vcl)").Action(c.content.Set).StringVar(&c.content.Value)
+	c.CmdClause.Flag("content-file", "Path to a file containing the VCL snippet ('-' for stdin)").Action(argparser.StdinPath(&c.contentFile)).StringVar(&c.contentFile)
 	c.CmdClause.Flag("dynamic", "Whether the VCL snippet is dynamic or versioned").Action(c.dynamic.Set).BoolVar(&c.dynamic.Value)
+	c.CmdClause.Flag("edit", "Write the VCL snippet in your editor ($VISUAL or $EDITOR)").BoolVar(&c.edit)
 	c.CmdClause.Flag("name", "The name of the VCL snippet").Action(c.name.Set).StringVar(&c.name.Value)
 	c.CmdClause.Flag("priority", "Priority determines execution order. Lower numbers execute first").Short('p').Action(c.priority.Set).IntVar(&c.priority.Value)
 
@@ -64,7 +67,9 @@ type CreateCommand struct {
 
 	autoClone      argparser.OptionalAutoClone
 	content        argparser.OptionalString
+	contentFile    string
 	dynamic        argparser.OptionalBool
+	edit           bool
 	location       argparser.OptionalString
 	name           argparser.OptionalString
 	priority       argparser.OptionalInt
@@ -73,7 +78,21 @@ type CreateCommand struct {
 }
 
 // Exec invokes the application logic for the command.
-func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
+func (c *CreateCommand) Exec(in io.Reader, out io.Writer) error {
+	content, hasContent, err := argparser.ResolveContent(c.content, c.contentFile, in)
+	if err == nil && c.edit {
+		if hasContent {
+			err = errEditContent
+		} else {
+			content, err = editContent(c.Globals, "")
+			hasContent = err == nil
+		}
+	}
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
 	serviceID, serviceVersion, err := argparser.ServiceDetails(argparser.ServiceDetailsOpts{
 		AutoCloneFlag:      c.autoClone,
 		APIClient:          c.Globals.APIClient,
@@ -92,6 +111,9 @@ func (c *CreateCommand) Exec(_ io.Reader, out io.Writer) error {
 	}
 
 	input := c.constructInput(serviceID, fastly.ToValue(serviceVersion.Number))
+	if hasContent {
+		input.Content = &content
+	}
 
 	v, err := c.Globals.APIClient.CreateSnippet(input)
 	if err != nil {
@@ -125,9 +147,6 @@ func (c *CreateCommand) constructInput(serviceID string, serviceVersion int) *fa
 	if c.name.WasSet {
 		input.Name = &c.name.Value
 	}
-	if c.content.WasSet {
-		input.Content = fastly.ToPointer(argparser.Content(c.content.Value))
-	}
 	if c.location.WasSet {
 		sType := fastly.SnippetType(c.location.Value)
 		input.Type = &sType
@@ -141,3 +160,16 @@ func (c *CreateCommand) constructInput(serviceID string, serviceVersion int) *fa
 
 	return &input
 }
+
+// errEditContent indicates --edit was combined with another source of content.
+var errEditContent = fmt.Errorf("error parsing arguments: --edit is mutually exclusive with --content and --content-file")
+
+// editContent opens the VCL snippet content in the user's editor.
+func editContent(g *global.Data, content string) (string, error) {
+	return argparser.Edit(argparser.EditOpts{
+		Extension: ".vcl",
+		Globals:   g,
+		Initial:   content,
+		Name:      "snippet",
+	})
+}
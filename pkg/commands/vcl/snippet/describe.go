@@ -9,6 +9,7 @@ import (
 	"github.com/fastly/cli/pkg/argparser"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
 )
 
 // NewDescribeCommand returns a usable command registered under the parent.
@@ -30,7 +31,7 @@ func NewDescribeCommand(parent argparser.Registerer, g *global.Data) *DescribeCo
 
 	// Optional.
 	c.CmdClause.Flag("dynamic", "Whether the VCL snippet is dynamic or versioned").Action(c.dynamic.Set).BoolVar(&c.dynamic.Value)
-	c.RegisterFlagBool(c.JSONFlag()) // --json
+	c.RegisterJSONFlags(&c.JSONOutput) // --json, --field, --format
 	c.CmdClause.Flag("name", "The name of the VCL snippet").StringVar(&c.name)
 	c.RegisterFlag(argparser.StringFlagOpts{
 		Name:        argparser.FlagServiceIDName,
@@ -172,10 +173,10 @@ func (c *DescribeCommand) printDynamic(out io.Writer, ds *fastly.DynamicSnippet)
 	fmt.Fprintf(out, "ID: %s\n", fastly.ToValue(ds.SnippetID))
 	fmt.Fprintf(out, "Content: \n%s\n", fastly.ToValue(ds.Content))
 	if ds.CreatedAt != nil {
-		fmt.Fprintf(out, "Created at: %s\n", ds.CreatedAt)
+		fmt.Fprintf(out, "Created at: %s\n", text.FormatTime(ds.CreatedAt))
 	}
 	if ds.UpdatedAt != nil {
-		fmt.Fprintf(out, "Updated at: %s\n", ds.UpdatedAt)
+		fmt.Fprintf(out, "Updated at: %s\n", text.FormatTime(ds.UpdatedAt))
 	}
 	return nil
 }
@@ -193,13 +194,13 @@ func (c *DescribeCommand) print(out io.Writer, s *fastly.Snippet) error {
 	fmt.Fprintf(out, "Type: %s\n", fastly.ToValue(s.Type))
 	fmt.Fprintf(out, "Content: \n%s\n", fastly.ToValue(s.Content))
 	if s.CreatedAt != nil {
-		fmt.Fprintf(out, "Created at: %s\n", s.CreatedAt)
+		fmt.Fprintf(out, "Created at: %s\n", text.FormatTime(s.CreatedAt))
 	}
 	if s.UpdatedAt != nil {
-		fmt.Fprintf(out, "Updated at: %s\n", s.UpdatedAt)
+		fmt.Fprintf(out, "Updated at: %s\n", text.FormatTime(s.UpdatedAt))
 	}
 	if s.DeletedAt != nil {
-		fmt.Fprintf(out, "Deleted at: %s\n", s.DeletedAt)
+		fmt.Fprintf(out, "Deleted at: %s\n", text.FormatTime(s.DeletedAt))
 	}
 	return nil
 }
@@ -3,6 +3,8 @@ package snippet_test
 import (
 	"bytes"
 	"io"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/fastly/go-fastly/v9/fastly"
@@ -11,6 +13,7 @@ import (
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/mock"
 	"github.com/fastly/cli/pkg/testutil"
+	"net/http"
 )
 
 func TestVCLSnippetCreate(t *testing.T) {
@@ -498,17 +501,19 @@ func TestVCLSnippetUpdate(t *testing.T) {
 			Name: "validate UpdateSnippet API error",
 			API: mock.API{
 				ListVersionsFn: testutil.ListVersions,
+				GetSnippetFn:   getSnippet,
 				UpdateSnippetFn: func(i *fastly.UpdateSnippetInput) (*fastly.Snippet, error) {
 					return nil, testutil.Err
 				},
 			},
-			Args:      args("vcl snippet update --content inline_vcl --name foo --new-name bar --service-id 123 --type recv --version 3"),
+			Args:      args("vcl snippet update --content inline_vcl --name foo --new-name bar --service-id 123 --type recv --version 3 --auto-yes"),
 			WantError: testutil.Err.Error(),
 		},
 		{
 			Name: "validate UpdateSnippet API success",
 			API: mock.API{
 				ListVersionsFn: testutil.ListVersions,
+				GetSnippetFn:   getSnippet,
 				UpdateSnippetFn: func(i *fastly.UpdateSnippetInput) (*fastly.Snippet, error) {
 					// Track the contents parsed
 					content = *i.Content
@@ -523,13 +528,14 @@ func TestVCLSnippetUpdate(t *testing.T) {
 					}, nil
 				},
 			},
-			Args:       args("vcl snippet update --content inline_vcl --name foo --new-name bar --service-id 123 --type recv --version 3"),
+			Args:       args("vcl snippet update --content inline_vcl --name foo --new-name bar --service-id 123 --type recv --version 3 --auto-yes"),
 			WantOutput: "Updated VCL snippet 'bar' (previously: 'foo', service: 123, version: 3, type: recv, priority: 100)",
 		},
 		{
 			Name: "validate UpdateDynamicSnippet API success",
 			API: mock.API{
-				ListVersionsFn: testutil.ListVersions,
+				ListVersionsFn:      testutil.ListVersions,
+				GetDynamicSnippetFn: getDynamicSnippet,
 				UpdateDynamicSnippetFn: func(i *fastly.UpdateDynamicSnippetInput) (*fastly.DynamicSnippet, error) {
 					// Track the contents parsed
 					content = *i.Content
@@ -541,13 +547,14 @@ func TestVCLSnippetUpdate(t *testing.T) {
 					}, nil
 				},
 			},
-			Args:       args("vcl snippet update --content inline_vcl --dynamic --service-id 123 --snippet-id 456 --version 3"),
+			Args:       args("vcl snippet update --content inline_vcl --dynamic --service-id 123 --snippet-id 456 --version 3 --auto-yes"),
 			WantOutput: "Updated dynamic VCL snippet '456' (service: 123)",
 		},
 		{
 			Name: "validate --autoclone results in cloned service version",
 			API: mock.API{
 				ListVersionsFn: testutil.ListVersions,
+				GetSnippetFn:   getSnippet,
 				CloneVersionFn: testutil.CloneVersionResult(4),
 				UpdateSnippetFn: func(i *fastly.UpdateSnippetInput) (*fastly.Snippet, error) {
 					// Track the contents parsed
@@ -563,7 +570,7 @@ func TestVCLSnippetUpdate(t *testing.T) {
 					}, nil
 				},
 			},
-			Args:       args("vcl snippet update --autoclone --content inline_vcl --name foo --new-name bar --priority 1 --service-id 123 --type recv --version 1"),
+			Args:       args("vcl snippet update --autoclone --content inline_vcl --name foo --new-name bar --priority 1 --service-id 123 --type recv --version 1 --auto-yes"),
 			WantOutput: "Updated VCL snippet 'bar' (previously: 'foo', service: 123, version: 4, type: recv, priority: 1)",
 		},
 	}
@@ -651,3 +658,250 @@ func listSnippets(i *fastly.ListSnippetsInput) ([]*fastly.Snippet, error) {
 	}
 	return vs, nil
 }
+
+func TestVCLSnippetUpdateNoChanges(t *testing.T) {
+	args := testutil.Args("vcl snippet update --content-file ./testdata/sync/unchanged.vcl --name foo --service-id 123 --version 3")
+	api := mock.API{
+		ListVersionsFn: testutil.ListVersions,
+		GetSnippetFn:   getSnippet,
+		UpdateSnippetFn: func(i *fastly.UpdateSnippetInput) (*fastly.Snippet, error) {
+			t.Fatal("unexpected update of an unchanged snippet")
+			return nil, nil
+		},
+	}
+
+	var stdout bytes.Buffer
+	app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+		opts := testutil.MockGlobalData(args, &stdout)
+		opts.APIClientFactory = mock.APIClient(api)
+		return opts, nil
+	}
+	err := app.Run(args, nil)
+	testutil.AssertNoError(t, err)
+	testutil.AssertStringContains(t, stdout.String(), "No changes: VCL snippet 'foo' is identical to the local content")
+}
+
+func TestVCLSnippetEdit(t *testing.T) {
+	args := testutil.Args
+	scenarios := []struct {
+		name       string
+		args       []string
+		edit       func(current string) string
+		wantError  string
+		wantOutput string
+		wantSeen   string
+		wantSent   string
+	}{
+		{
+			name:       "create",
+			args:       args("vcl snippet create --edit --name foo --service-id 123 --type recv --version 3"),
+			edit:       func(string) string { return "# new vcl content\n" },
+			wantOutput: "Created VCL snippet 'foo'",
+			wantSeen:   "\n",
+			wantSent:   "# new vcl content",
+		},
+		{
+			name:      "create with --content",
+			args:      args("vcl snippet create --content inline_vcl --edit --name foo --service-id 123 --type recv --version 3"),
+			wantError: "--edit is mutually exclusive with --content and --content-file",
+		},
+		{
+			name:       "update",
+			args:       args("vcl snippet update --edit --name foo --service-id 123 --version 3"),
+			edit:       func(current string) string { return current + "set req.http.X-Edited = \"1\";\n" },
+			wantOutput: "Updated VCL snippet 'foo'",
+			wantSeen:   "# some vcl content\n",
+			wantSent:   "# some vcl content\nset req.http.X-Edited = \"1\";",
+		},
+		{
+			name:       "update dynamic",
+			args:       args("vcl snippet update --dynamic --edit --service-id 123 --snippet-id 456 --version 3"),
+			edit:       func(string) string { return "# replaced\n" },
+			wantOutput: "Updated dynamic VCL snippet '456' (service: 123)",
+			wantSeen:   "# some vcl content\n",
+			wantSent:   "# replaced",
+		},
+		{
+			name:      "update unchanged",
+			args:      args("vcl snippet update --edit --name foo --service-id 123 --version 3"),
+			edit:      func(current string) string { return current },
+			wantError: "edit cancelled",
+			wantSeen:  "# some vcl content\n",
+		},
+	}
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.name, func(t *testing.T) {
+			var (
+				seen   string
+				sent   string
+				stdout bytes.Buffer
+			)
+			api := mock.API{
+				ListVersionsFn:      testutil.ListVersions,
+				GetSnippetFn:        getSnippet,
+				GetDynamicSnippetFn: getDynamicSnippet,
+				CreateSnippetFn: func(i *fastly.CreateSnippetInput) (*fastly.Snippet, error) {
+					sent = fastly.ToValue(i.Content)
+					return &fastly.Snippet{
+						Name:           i.Name,
+						ServiceID:      fastly.ToPointer(i.ServiceID),
+						ServiceVersion: fastly.ToPointer(i.ServiceVersion),
+					}, nil
+				},
+				UpdateSnippetFn: func(i *fastly.UpdateSnippetInput) (*fastly.Snippet, error) {
+					sent = fastly.ToValue(i.Content)
+					return &fastly.Snippet{
+						Name:           fastly.ToPointer(i.Name),
+						ServiceID:      fastly.ToPointer(i.ServiceID),
+						ServiceVersion: fastly.ToPointer(i.ServiceVersion),
+					}, nil
+				},
+				UpdateDynamicSnippetFn: func(i *fastly.UpdateDynamicSnippetInput) (*fastly.DynamicSnippet, error) {
+					sent = fastly.ToValue(i.Content)
+					return &fastly.DynamicSnippet{
+						SnippetID: fastly.ToPointer(i.SnippetID),
+						ServiceID: fastly.ToPointer(i.ServiceID),
+					}, nil
+				},
+			}
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(testcase.args, &stdout)
+				opts.APIClientFactory = mock.APIClient(api)
+				opts.ExecuteEditor = func(editor []string) error {
+					path := editor[len(editor)-1]
+					data, err := os.ReadFile(path)
+					if err != nil {
+						return err
+					}
+					seen = string(data)
+					return os.WriteFile(path, []byte(testcase.edit(seen)), 0o600)
+				}
+				return opts, nil
+			}
+			err := app.Run(testcase.args, nil)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			testutil.AssertString(t, testcase.wantSeen, seen)
+			testutil.AssertString(t, testcase.wantSent, sent)
+		})
+	}
+}
+
+func TestVCLSnippetSync(t *testing.T) {
+	args := testutil.Args
+	remote := func(i *fastly.ListSnippetsInput) ([]*fastly.Snippet, error) {
+		return []*fastly.Snippet{
+			{Name: fastly.ToPointer("unchanged"), Content: fastly.ToPointer("# some vcl content"), Dynamic: fastly.ToPointer(0)},
+			{Name: fastly.ToPointer("deliver_headers"), Content: fastly.ToPointer("set resp.http.X-Env = \"production\";\n"), Dynamic: fastly.ToPointer(0)},
+		}, nil
+	}
+
+	scenarios := []struct {
+		testutil.TestScenario
+		stdin           string
+		updateErr       error
+		wantCreated     []string
+		wantUpdated     []string
+		wantRemediation string
+	}{
+		{
+			TestScenario: testutil.TestScenario{
+				Name:       "creates and updates snippets",
+				Args:       args("vcl snippet sync --dir ./testdata/sync --service-id 123 --version 3 --type recv --auto-yes"),
+				WantOutput: "1 created, 1 updated, 1 unchanged",
+			},
+			wantCreated: []string{"recv_redirect"},
+			wantUpdated: []string{"deliver_headers"},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:       "displays the diff and prompts",
+				Args:       args("vcl snippet sync --dir ./testdata/sync --service-id 123 --version 3 --type recv"),
+				WantError:  "operation not confirmed",
+				WantOutput: `+set resp.http.X-Env = "staging";`,
+			},
+			stdin: "n\n",
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "requires --auto-yes when non-interactive",
+				Args:      args("vcl snippet sync --dir ./testdata/sync --service-id 123 --version 3 --type recv --non-interactive"),
+				WantError: "confirmation required to sync 2 snippet(s)",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "version locked part-way through",
+				Args:      args("vcl snippet sync --dir ./testdata/sync --service-id 123 --version 3 --type recv --auto-yes"),
+				WantError: "service version 3 is not editable, it was locked before updating snippet 'deliver_headers'",
+			},
+			updateErr: &fastly.HTTPError{
+				StatusCode: http.StatusBadRequest,
+				Errors:     []*fastly.ErrorObject{{Title: "Bad request", Detail: "Version is locked"}},
+			},
+			wantCreated: []string{"recv_redirect"},
+			wantRemediation: `These changes were made to version 3 before it was locked:
+
+  - created snippet 'recv_redirect'
+
+To clone the version and make the changes to the clone, run:
+
+  fastly vcl snippet sync --dir ./testdata/sync --service-id 123 --version 3 --type recv --auto-yes --autoclone`,
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "requires --type to create snippets",
+				Args:      args("vcl snippet sync --dir ./testdata/sync --service-id 123 --version 3 --auto-yes"),
+				WantError: "--type is required to create snippet(s): recv_redirect",
+			},
+		},
+		{
+			TestScenario: testutil.TestScenario{
+				Name:      "empty directory",
+				Args:      args("vcl snippet sync --dir ./testdata/sync/README.txt --service-id 123 --version 3"),
+				WantError: "failed to read directory",
+			},
+		},
+	}
+
+	for testcaseIdx := range scenarios {
+		testcase := &scenarios[testcaseIdx]
+		t.Run(testcase.Name, func(t *testing.T) {
+			var (
+				created, updated []string
+				stdout           bytes.Buffer
+			)
+			api := mock.API{
+				ListVersionsFn: testutil.ListVersions,
+				ListSnippetsFn: remote,
+				CreateSnippetFn: func(i *fastly.CreateSnippetInput) (*fastly.Snippet, error) {
+					if fastly.ToValue(i.Type) != fastly.SnippetTypeRecv {
+						t.Errorf("unexpected snippet type %q", fastly.ToValue(i.Type))
+					}
+					created = append(created, fastly.ToValue(i.Name))
+					return &fastly.Snippet{Name: i.Name}, nil
+				},
+				UpdateSnippetFn: func(i *fastly.UpdateSnippetInput) (*fastly.Snippet, error) {
+					if testcase.updateErr != nil {
+						return nil, testcase.updateErr
+					}
+					updated = append(updated, i.Name)
+					return &fastly.Snippet{Name: fastly.ToPointer(i.Name)}, nil
+				},
+			}
+			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
+				opts := testutil.MockGlobalData(testcase.Args, &stdout)
+				opts.APIClientFactory = mock.APIClient(api)
+				opts.Input = strings.NewReader(testcase.stdin)
+				return opts, nil
+			}
+			err := app.Run(testcase.Args, nil)
+			testutil.AssertErrorContains(t, err, testcase.WantError)
+			testutil.AssertRemediationErrorContains(t, err, testcase.wantRemediation)
+			testutil.AssertStringContains(t, stdout.String(), testcase.WantOutput)
+			testutil.AssertEqual(t, testcase.wantCreated, created)
+			testutil.AssertEqual(t, testcase.wantUpdated, updated)
+		})
+	}
+}
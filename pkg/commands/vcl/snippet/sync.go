@@ -0,0 +1,263 @@
+package snippet
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// SyncCommand calls the Fastly API to synchronise VCL snippets with the .vcl
+// files in a local directory.
+type SyncCommand struct {
+	argparser.Base
+
+	autoClone      argparser.OptionalAutoClone
+	dir            string
+	location       argparser.OptionalString
+	priority       argparser.OptionalInt
+	serviceName    argparser.OptionalServiceNameID
+	serviceVersion argparser.OptionalServiceVersion
+}
+
+// NewSyncCommand returns a usable command registered under the parent.
+func NewSyncCommand(parent argparser.Registerer, g *global.Data) *SyncCommand {
+	c := SyncCommand{
+		Base: argparser.Base{
+			Globals: g,
+		},
+	}
+	c.CmdClause = parent.Command("sync", "Create or update a VCL snippet for each .vcl file in a directory, named after the file")
+
+	// Required.
+	c.CmdClause.Flag("dir", "Directory containing the .vcl files").Required().StringVar(&c.dir)
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Name:        argparser.FlagVersionName,
+		Description: argparser.FlagVersionDesc,
+		Dst:         &c.serviceVersion.Value,
+		Required:    true,
+	})
+
+	// Optional.
+	c.RegisterAutoCloneFlag(argparser.AutoCloneFlagOpts{
+		Action: c.autoClone.Set,
+		Dst:    &c.autoClone.Value,
+	})
+	c.CmdClause.Flag("priority", "Priority of created snippets. Lower numbers execute first").Short('p').Action(c.priority.Set).IntVar(&c.priority.Value)
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Name:        argparser.FlagServiceIDName,
+		Description: argparser.FlagServiceIDDesc,
+		Dst:         &g.Manifest.Flag.ServiceID,
+		Short:       's',
+	})
+	c.RegisterFlag(argparser.StringFlagOpts{
+		Action:      c.serviceName.Set,
+		Name:        argparser.FlagServiceName,
+		Description: argparser.FlagServiceDesc,
+		Dst:         &c.serviceName.Value,
+	})
+	c.CmdClause.Flag("type", "The location in generated VCL where created snippets should be placed (required when snippets are created)").HintOptions(Locations...).Action(c.location.Set).EnumVar(&c.location.Value, Locations...)
+
+	return &c
+}
+
+// Exec invokes the application logic for the command.
+func (c *SyncCommand) Exec(in io.Reader, out io.Writer) error {
+	local, err := ReadSnippetDir(c.dir)
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+	if len(local) == 0 {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("no .vcl files found in %s", c.dir),
+			Remediation: "Each .vcl file in the directory is uploaded as a snippet named after the file, e.g. recv_redirects.vcl becomes the 'recv_redirects' snippet.",
+		}
+	}
+
+	serviceID, serviceVersion, err := argparser.ServiceDetails(argparser.ServiceDetailsOpts{
+		AutoCloneFlag:      c.autoClone,
+		APIClient:          c.Globals.APIClient,
+		Manifest:           *c.Globals.Manifest,
+		Out:                out,
+		ServiceNameFlag:    c.serviceName,
+		ServiceVersionFlag: c.serviceVersion,
+		VerboseMode:        c.Globals.Flags.Verbose,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": fsterr.ServiceVersion(serviceVersion),
+		})
+		return err
+	}
+	serviceVersionNumber := fastly.ToValue(serviceVersion.Number)
+
+	remote, err := c.Globals.APIClient.ListSnippets(&fastly.ListSnippetsInput{
+		ServiceID:      serviceID,
+		ServiceVersion: serviceVersionNumber,
+	})
+	if err != nil {
+		c.Globals.ErrLog.AddWithContext(err, map[string]any{
+			"Service ID":      serviceID,
+			"Service Version": serviceVersionNumber,
+		})
+		return err
+	}
+	existing := make(map[string]*fastly.Snippet, len(remote))
+	for _, s := range remote {
+		existing[fastly.ToValue(s.Name)] = s
+	}
+
+	var creates, updates, unchanged []string
+	for _, name := range sortedKeys(local) {
+		s, ok := existing[name]
+		switch {
+		case !ok:
+			creates = append(creates, name)
+		case fastly.ToValue(s.Dynamic) == 1:
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("snippet '%s' is dynamic", name),
+				Remediation: "Only versioned snippets can be synchronised. Update dynamic snippets with `fastly vcl snippet update --dynamic`, or rename the file.",
+			}
+		case fastly.ToValue(s.Content) == local[name]:
+			unchanged = append(unchanged, name)
+		default:
+			updates = append(updates, name)
+		}
+	}
+
+	if len(creates) == 0 && len(updates) == 0 {
+		text.Info(out, "No changes: all %d snippet(s) in %s are identical to service %s version %d", len(unchanged), c.dir, serviceID, serviceVersionNumber)
+		return nil
+	}
+	if len(creates) > 0 && !c.location.WasSet {
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("--type is required to create snippet(s): %s", strings.Join(creates, ", ")),
+			Remediation: fmt.Sprintf("Set --type to the location of the new snippets (%s).", strings.Join(Locations, ", ")),
+		}
+	}
+
+	for _, name := range creates {
+		text.Output(out, "%s %s", text.DiffAddStyle("create"), name)
+	}
+	for _, name := range updates {
+		text.Output(out, "%s %s", text.WarningStyle("update"), name)
+		text.Diff(out, name+" (remote)", name+" (local)", fastly.ToValue(existing[name].Content), local[name])
+	}
+	text.Break(out)
+
+	if !c.Globals.Flags.AutoYes {
+		if c.Globals.Flags.NonInteractive {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("confirmation required to sync %d snippet(s)", len(creates)+len(updates)),
+				Remediation: "Run the command in an interactive terminal to confirm it, or pass --auto-yes (-y) to confirm it non-interactively.",
+			}
+		}
+		cont, err := text.AskYesNo(out, "Apply these changes? [y/N]: ", in, "--auto-yes")
+		if err != nil {
+			return err
+		}
+		if !cont {
+			return argparser.ErrNotConfirmed
+		}
+	}
+
+	// NOTE: The version was editable when it was resolved, but it can be
+	// locked (e.g. activated by someone else) part-way through the changes.
+	var done []string
+	for _, name := range creates {
+		input := &fastly.CreateSnippetInput{
+			Content:        fastly.ToPointer(local[name]),
+			Dynamic:        fastly.ToPointer(0),
+			Name:           fastly.ToPointer(name),
+			ServiceID:      serviceID,
+			ServiceVersion: serviceVersionNumber,
+			Type:           fastly.ToPointer(fastly.SnippetType(c.location.Value)),
+		}
+		if c.priority.WasSet {
+			input.Priority = &c.priority.Value
+		}
+		if _, err := c.Globals.APIClient.CreateSnippet(input); err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID":      serviceID,
+				"Service Version": serviceVersionNumber,
+				"Snippet":         name,
+			})
+			if fsterr.IsVersionLocked(err) {
+				return argparser.LockedVersionError(serviceVersionNumber, fmt.Sprintf("creating snippet '%s'", name), done, err)
+			}
+			return fmt.Errorf("error creating snippet '%s': %w", name, err)
+		}
+		done = append(done, fmt.Sprintf("created snippet '%s'", name))
+	}
+	for _, name := range updates {
+		_, err := c.Globals.APIClient.UpdateSnippet(&fastly.UpdateSnippetInput{
+			Content:        fastly.ToPointer(local[name]),
+			Name:           name,
+			ServiceID:      serviceID,
+			ServiceVersion: serviceVersionNumber,
+		})
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID":      serviceID,
+				"Service Version": serviceVersionNumber,
+				"Snippet":         name,
+			})
+			if fsterr.IsVersionLocked(err) {
+				return argparser.LockedVersionError(serviceVersionNumber, fmt.Sprintf("updating snippet '%s'", name), done, err)
+			}
+			return fmt.Errorf("error updating snippet '%s': %w", name, err)
+		}
+		done = append(done, fmt.Sprintf("updated snippet '%s'", name))
+	}
+
+	text.Success(out, "Synchronised VCL snippets from %s (service: %s, version: %d): %d created, %d updated, %d unchanged", c.dir, serviceID, serviceVersionNumber, len(creates), len(updates), len(unchanged))
+	return nil
+}
+
+// ReadSnippetDir reads the .vcl files in dir, keyed by the file name without
+// its extension.
+func ReadSnippetDir(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("failed to read directory '%s': %w", dir, err),
+			Remediation: fsterr.HostRemediation,
+		}
+	}
+	snippets := make(map[string]string)
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".vcl" {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(path) // #nosec G304 (CWE-22)
+		if err != nil {
+			return nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("failed to read '%s': %w", path, err),
+				Remediation: fsterr.HostRemediation,
+			}
+		}
+		snippets[strings.TrimSuffix(e.Name(), ".vcl")] = string(data)
+	}
+	return snippets, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,6 +1,7 @@
 package snippet
 
 import (
+	"errors"
 	"fmt"
 	"io"
 
@@ -35,7 +36,9 @@ func NewUpdateCommand(parent argparser.Registerer, g *global.Data) *UpdateComman
 		Dst:    &c.autoClone.Value,
 	})
 	c.CmdClause.Flag("content", "VCL snippet passed as file path or content, e.g. $(< snippet.vcl)").Action(c.content.Set).StringVar(&c.content.Value)
+	c.CmdClause.Flag("content-file", "Path to a file containing the VCL snippet ('-' for stdin). The changes are displayed and must be confirmed unless --auto-yes is set").Action(argparser.StdinPath(&c.contentFile)).StringVar(&c.contentFile)
 	c.CmdClause.Flag("dynamic", "Whether the VCL snippet is dynamic or versioned").Action(c.dynamic.Set).BoolVar(&c.dynamic.Value)
+	c.CmdClause.Flag("edit", "Edit the current VCL snippet in your editor ($VISUAL or $EDITOR)").BoolVar(&c.edit)
 	c.CmdClause.Flag("name", "The name of the VCL snippet to update").StringVar(&c.name)
 	c.CmdClause.Flag("new-name", "New name for the VCL snippet").Action(c.newName.Set).StringVar(&c.newName.Value)
 	c.CmdClause.Flag("priority", "Priority determines execution order. Lower numbers execute first").Short('p').Action(c.priority.Set).IntVar(&c.priority.Value)
@@ -65,7 +68,9 @@ type UpdateCommand struct {
 
 	autoClone      argparser.OptionalAutoClone
 	content        argparser.OptionalString
+	contentFile    string
 	dynamic        argparser.OptionalBool
+	edit           bool
 	location       argparser.OptionalString
 	name           string
 	newName        argparser.OptionalString
@@ -76,7 +81,16 @@ type UpdateCommand struct {
 }
 
 // Exec invokes the application logic for the command.
-func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
+func (c *UpdateCommand) Exec(in io.Reader, out io.Writer) error {
+	content, hasContent, err := argparser.ResolveContent(c.content, c.contentFile, in)
+	if err == nil && c.edit && hasContent {
+		err = errEditContent
+	}
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return err
+	}
+
 	serviceID, serviceVersion, err := argparser.ServiceDetails(argparser.ServiceDetailsOpts{
 		AllowActiveLocked:  c.dynamic.WasSet && c.dynamic.Value,
 		AutoCloneFlag:      c.autoClone,
@@ -106,6 +120,36 @@ func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
 			})
 			return err
 		}
+		if hasContent || c.edit {
+			current, err := c.Globals.APIClient.GetDynamicSnippet(&fastly.GetDynamicSnippetInput{
+				ServiceID: serviceID,
+				SnippetID: c.snippetID,
+			})
+			if err != nil {
+				c.Globals.ErrLog.AddWithContext(err, map[string]any{
+					"Service ID": serviceID,
+					"Snippet ID": c.snippetID,
+				})
+				return err
+			}
+			if c.edit {
+				content, err = editContent(c.Globals, fastly.ToValue(current.Content))
+				if err != nil {
+					c.Globals.ErrLog.Add(err)
+					return err
+				}
+			} else {
+				changed, err := c.confirmContent(in, out, c.snippetID, fastly.ToValue(current.Content), content)
+				if err != nil {
+					return err
+				}
+				if !changed {
+					text.Info(out, "No changes: dynamic VCL snippet '%s' is identical to the local content", c.snippetID)
+					return nil
+				}
+			}
+			input.Content = &content
+		}
 		v, err := c.Globals.APIClient.UpdateDynamicSnippet(input)
 		if err != nil {
 			c.Globals.ErrLog.AddWithContext(err, map[string]any{
@@ -126,6 +170,39 @@ func (c *UpdateCommand) Exec(_ io.Reader, out io.Writer) error {
 		})
 		return err
 	}
+	if hasContent || c.edit {
+		current, err := c.Globals.APIClient.GetSnippet(&fastly.GetSnippetInput{
+			Name:           c.name,
+			ServiceID:      serviceID,
+			ServiceVersion: serviceVersionNumber,
+		})
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID":      serviceID,
+				"Service Version": serviceVersionNumber,
+			})
+			return err
+		}
+		changed := true
+		if c.edit {
+			content, err = editContent(c.Globals, fastly.ToValue(current.Content))
+			if err != nil {
+				c.Globals.ErrLog.Add(err)
+				return err
+			}
+		} else {
+			changed, err = c.confirmContent(in, out, c.name, fastly.ToValue(current.Content), content)
+			if err != nil {
+				return err
+			}
+		}
+		if changed {
+			input.Content = &content
+		} else if !c.newName.WasSet && !c.priority.WasSet && !c.location.WasSet {
+			text.Info(out, "No changes: VCL snippet '%s' is identical to the local content", c.name)
+			return nil
+		}
+	}
 	v, err := c.Globals.APIClient.UpdateSnippet(input)
 	if err != nil {
 		c.Globals.ErrLog.AddWithContext(err, map[string]any{
@@ -159,9 +236,6 @@ func (c *UpdateCommand) constructDynamicInput(serviceID string, _ int) (*fastly.
 	if c.snippetID == "" {
 		return nil, fmt.Errorf("error parsing arguments: must provide --snippet-id to update a dynamic VCL snippet")
 	}
-	if c.content.WasSet {
-		input.Content = fastly.ToPointer(argparser.Content(c.content.Value))
-	}
 
 	return &input, nil
 }
@@ -186,9 +260,6 @@ func (c *UpdateCommand) constructInput(serviceID string, serviceVersion int) (*f
 	if c.priority.WasSet {
 		input.Priority = &c.priority.Value
 	}
-	if c.content.WasSet {
-		input.Content = fastly.ToPointer(argparser.Content(c.content.Value))
-	}
 	if c.location.WasSet {
 		location := fastly.SnippetType(c.location.Value)
 		input.Type = &location
@@ -196,3 +267,28 @@ func (c *UpdateCommand) constructInput(serviceID string, serviceVersion int) (*f
 
 	return &input, nil
 }
+
+// confirmContent displays the changes between the remote and local content and
+// asks the user to confirm them. changed is false if they're identical.
+func (c *UpdateCommand) confirmContent(in io.Reader, out io.Writer, name, remote, local string) (changed bool, err error) {
+	// NOTE: The content can't be confirmed when it was read from stdin.
+	if c.contentFile == "-" {
+		in = nil
+	}
+	err = argparser.ConfirmChange(argparser.ConfirmChangeOpts{
+		Globals: c.Globals,
+		In:      in,
+		Local:   local,
+		Name:    name,
+		Out:     out,
+		Remote:  remote,
+	})
+	if errors.Is(err, argparser.ErrNoChanges) {
+		return false, nil
+	}
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		return false, err
+	}
+	return true, nil
+}
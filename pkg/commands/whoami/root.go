@@ -7,17 +7,32 @@ import (
 	"net/http"
 	"sort"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fastly/go-fastly/v9/fastly"
 
 	"github.com/fastly/cli/pkg/api/undocumented"
 	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/text"
 	"github.com/fastly/cli/pkg/useragent"
 )
 
+// lookupConcurrency is the maximum number of concurrent service lookups.
+const lookupConcurrency = 5
+
+// expiryWarningPeriod is how close to expiry a token must be to trigger a
+// warning.
+const expiryWarningPeriod = 7 * 24 * time.Hour
+
 // RootCommand is the parent command for all subcommands in this package.
 // It should be installed under the primary root command.
 type RootCommand struct {
 	argparser.Base
+	argparser.JSONOutput
 }
 
 // NewRootCommand returns a new command registered in the parent.
@@ -25,11 +40,16 @@ func NewRootCommand(parent argparser.Registerer, g *global.Data) *RootCommand {
 	var c RootCommand
 	c.Globals = g
 	c.CmdClause = parent.Command("whoami", "Get information about the currently authenticated account")
+	c.RegisterFlagBool(c.JSONFlag()) // --json
 	return &c
 }
 
 // Exec implements the command interface.
 func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
+	if c.Globals.Verbose() && c.JSONOutput.Enabled {
+		return fsterr.ErrInvalidVerboseJSONCombo
+	}
+
 	debugMode, _ := strconv.ParseBool(c.Globals.Env.DebugMode)
 	token, _ := c.Globals.Token()
 	apiEndpoint, _ := c.Globals.APIEndpoint()
@@ -62,8 +82,17 @@ func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
 		return fmt.Errorf("error decoding API response: %w", err)
 	}
 
+	report := c.buildReport(response, time.Now())
+
+	if ok, err := c.WriteJSON(out, report); ok {
+		return err
+	}
+
 	if !c.Globals.Verbose() {
 		fmt.Fprintf(out, "%s <%s>\n", response.User.Name, response.User.Login)
+		for _, w := range report.Warnings {
+			text.Warning(out, "%s", w)
+		}
 		return nil
 	}
 
@@ -80,19 +109,200 @@ func (c *RootCommand) Exec(_ io.Reader, out io.Writer) error {
 	fmt.Fprintf(out, "User login: %s\n", response.User.Login)
 	fmt.Fprintf(out, "Token ID: %s\n", response.Token.ID)
 	fmt.Fprintf(out, "Token name: %s\n", response.Token.Name)
-	fmt.Fprintf(out, "Token created at: %s\n", response.Token.CreatedAt)
+	fmt.Fprintf(out, "Token created at: %s\n", formatTime(response.Token.CreatedAt))
 	if response.Token.ExpiresAt != "" {
-		fmt.Fprintf(out, "Token expires at: %s\n", response.Token.ExpiresAt)
+		fmt.Fprintf(out, "Token expires at: %s\n", formatTime(response.Token.ExpiresAt))
+	}
+	if report.Token.LastUsedAt != nil {
+		fmt.Fprintf(out, "Token last used at: %s\n", text.FormatTimeLayout(report.Token.LastUsedAt, time.RFC3339))
+	}
+	fmt.Fprintf(out, "Token scope: %s\n", report.Token.Scope)
+	if len(report.Token.RestrictedServices) > 0 {
+		fmt.Fprintf(out, "Token restricted to services: %d\n", len(report.Token.RestrictedServices))
+		for _, s := range report.Token.RestrictedServices {
+			if s.Error != "" {
+				fmt.Fprintf(out, "\t%s (name unavailable: %s)\n", s.ID, s.Error)
+				continue
+			}
+			fmt.Fprintf(out, "\t%s (%s)\n", s.Name, s.ID)
+		}
 	}
-	fmt.Fprintf(out, "Token scope: %s\n", response.Token.Scope)
 	fmt.Fprintf(out, "Service count: %d\n", len(response.Services))
 	for _, k := range keys {
 		fmt.Fprintf(out, "\t%s (%s)\n", response.Services[k], k)
 	}
+	for _, w := range report.Warnings {
+		text.Warning(out, "%s", w)
+	}
 
 	return nil
 }
 
+// buildReport combines the verify response with the token's details.
+//
+// Failing to look up the token details is not fatal, as the verify response
+// already identifies the user, so the failure is reported as a warning.
+func (c *RootCommand) buildReport(response VerifyResponse, now time.Time) Report {
+	report := Report{
+		Customer: response.Customer,
+		User:     response.User,
+		Services: response.Services,
+		Token: TokenDetails{
+			ID:        response.Token.ID,
+			Name:      response.Token.Name,
+			CreatedAt: response.Token.CreatedAt,
+			ExpiresAt: response.Token.ExpiresAt,
+			Scope:     response.Token.Scope,
+			Scopes:    strings.Fields(response.Token.Scope),
+		},
+	}
+
+	t, err := c.Globals.APIClient.GetTokenSelf()
+	if err != nil {
+		c.Globals.ErrLog.Add(err)
+		report.Warnings = append(report.Warnings, fmt.Sprintf("Unable to fetch the token's details: %s", err))
+	} else {
+		if t.Scope != nil {
+			report.Token.Scope = string(*t.Scope)
+			report.Token.Scopes = strings.Fields(report.Token.Scope)
+		}
+		report.Token.LastUsedAt = t.LastUsedAt
+		if t.ExpiresAt != nil && report.Token.ExpiresAt == "" {
+			report.Token.ExpiresAt = t.ExpiresAt.UTC().Format(time.RFC3339)
+		}
+		report.Token.RestrictedServices = c.lookupServices(t.Services, response.Services)
+	}
+
+	report.Warnings = append(report.Warnings, tokenWarnings(report.Token, now)...)
+	return report
+}
+
+// lookupServices resolves the names of the given service IDs. Names already
+// known from the verify response are reused and the rest are fetched
+// concurrently. A failed lookup is recorded against the service rather than
+// failing the command.
+func (c *RootCommand) lookupServices(ids []string, known map[string]string) []ServiceRef {
+	refs := make([]ServiceRef, len(ids))
+	errs := make([]error, len(ids))
+	sem := make(chan struct{}, lookupConcurrency)
+	var wg sync.WaitGroup
+
+	for i, id := range ids {
+		refs[i].ID = id
+		if name, ok := known[id]; ok {
+			refs[i].Name = name
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			s, err := c.Globals.APIClient.GetService(&fastly.GetServiceInput{
+				ServiceID: refs[i].ID,
+			})
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			refs[i].Name = fastly.ToValue(s.Name)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			c.Globals.ErrLog.AddWithContext(err, map[string]any{
+				"Service ID": refs[i].ID,
+			})
+			refs[i].Error = err.Error()
+		}
+	}
+
+	return refs
+}
+
+// tokenWarnings highlights token properties likely to cause permission errors.
+func tokenWarnings(t TokenDetails, now time.Time) []string {
+	var warnings []string
+
+	hasGlobal := false
+	for _, s := range t.Scopes {
+		if s == string(fastly.GlobalScope) {
+			hasGlobal = true
+		}
+	}
+	if !hasGlobal {
+		warnings = append(warnings, fmt.Sprintf("The token's scope (%s) does not include 'global', so it cannot modify service configuration (e.g. `fastly service-version activate` or `fastly compute deploy` will fail with a permission error).", t.Scope))
+	}
+
+	if len(t.RestrictedServices) > 0 {
+		warnings = append(warnings, fmt.Sprintf("The token is restricted to %d service(s); requests for any other service will fail with a permission error.", len(t.RestrictedServices)))
+	}
+
+	var failed int
+	for _, s := range t.RestrictedServices {
+		if s.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		warnings = append(warnings, fmt.Sprintf("Unable to look up the name of %d of %d restricted service(s).", failed, len(t.RestrictedServices)))
+	}
+
+	if t.ExpiresAt != "" {
+		if expires, err := time.Parse(time.RFC3339, t.ExpiresAt); err == nil {
+			switch {
+			case !expires.After(now):
+				warnings = append(warnings, fmt.Sprintf("The token expired at %s.", t.ExpiresAt))
+			case expires.Sub(now) < expiryWarningPeriod:
+				warnings = append(warnings, fmt.Sprintf("The token expires soon (%s).", t.ExpiresAt))
+			}
+		}
+	}
+
+	return warnings
+}
+
+// formatTime returns how an RFC3339 timestamp from the API is displayed (see
+// text.FormatTime), or the timestamp as-is if it can't be parsed.
+func formatTime(s string) string {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return s
+	}
+	return text.FormatTimeLayout(&t, time.RFC3339Nano)
+}
+
+// Report is the information displayed by the whoami command.
+type Report struct {
+	Customer Customer          `json:"customer"`
+	User     User              `json:"user"`
+	Token    TokenDetails      `json:"token"`
+	Services map[string]string `json:"services"`
+	Warnings []string          `json:"warnings,omitempty"`
+}
+
+// TokenDetails describes the token used by the command.
+type TokenDetails struct {
+	ID                 string       `json:"id"`
+	Name               string       `json:"name"`
+	CreatedAt          string       `json:"created_at"`
+	ExpiresAt          string       `json:"expires_at,omitempty"`
+	LastUsedAt         *time.Time   `json:"last_used_at,omitempty"`
+	Scope              string       `json:"scope"`
+	Scopes             []string     `json:"scopes"`
+	RestrictedServices []ServiceRef `json:"restricted_services,omitempty"`
+}
+
+// ServiceRef identifies a service the token is restricted to.
+type ServiceRef struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
 // VerifyResponse models the Fastly API response for the whoami command.
 type VerifyResponse struct {
 	Customer Customer          `json:"customer"`
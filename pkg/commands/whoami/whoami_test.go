@@ -10,23 +10,28 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/fastly/go-fastly/v9/fastly"
+
 	"github.com/fastly/cli/pkg/api"
 	"github.com/fastly/cli/pkg/app"
 	"github.com/fastly/cli/pkg/config"
 	"github.com/fastly/cli/pkg/env"
 	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/mock"
 	"github.com/fastly/cli/pkg/testutil"
 )
 
 func TestWhoami(t *testing.T) {
 	args := testutil.Args
 	for _, testcase := range []struct {
-		name       string
-		args       []string
-		env        config.Environment
-		client     api.HTTPClient
-		wantError  string
-		wantOutput string
+		name        string
+		args        []string
+		env         config.Environment
+		client      api.HTTPClient
+		api         mock.API
+		wantError   string
+		wantOutput  string
+		wantOutputs []string
 	}{
 		{
 			name:       "basic response",
@@ -71,12 +76,115 @@ func TestWhoami(t *testing.T) {
 				fmt.Sprintf("Fastly API endpoint (via %s): https://alternative.example.com", env.APIEndpoint),
 			),
 		},
+		{
+			name:   "scoped token",
+			args:   args("whoami -v"),
+			client: testutil.WhoamiVerifyClient(testutil.WhoamiBasicResponse),
+			api: mock.API{
+				GetTokenSelfFn: func() (*fastly.Token, error) {
+					return &fastly.Token{
+						LastUsedAt: testutil.MustParseTimeRFC3339("2021-06-15T09:30:00Z"),
+						Scope:      fastly.ToPointer(fastly.TokenScope("purge_select global:read")),
+						Services:   []string{"1xxaa", "3cccc"},
+					}, nil
+				},
+				GetServiceFn: func(i *fastly.GetServiceInput) (*fastly.Service, error) {
+					if i.ServiceID != "3cccc" {
+						return nil, fmt.Errorf("unexpected lookup of %s", i.ServiceID)
+					}
+					return &fastly.Service{Name: fastly.ToPointer("Third service")}, nil
+				},
+			},
+			wantOutputs: []string{
+				"Token last used at: 2021-06-15T09:30:00Z\n",
+				"Token scope: purge_select global:read\n",
+				"Token restricted to services: 2\n\tFirst service (1xxaa)\n\tThird service (3cccc)\n",
+				"The token's scope (purge_select global:read) does not include 'global'",
+				"The token is restricted to 2 service(s)",
+			},
+		},
+		{
+			name:   "scoped token with failed service lookups",
+			args:   args("whoami"),
+			client: testutil.WhoamiVerifyClient(testutil.WhoamiBasicResponse),
+			api: mock.API{
+				GetTokenSelfFn: func() (*fastly.Token, error) {
+					return &fastly.Token{
+						Scope:    fastly.ToPointer(fastly.GlobalScope),
+						Services: []string{"3cccc", "4dddd", "5eeee"},
+					}, nil
+				},
+				GetServiceFn: func(i *fastly.GetServiceInput) (*fastly.Service, error) {
+					if i.ServiceID == "4dddd" {
+						return nil, testutil.Err
+					}
+					return &fastly.Service{Name: fastly.ToPointer("Service " + i.ServiceID)}, nil
+				},
+			},
+			wantOutputs: []string{
+				"Alice Programmer <alice@example.com>\n",
+				"Unable to look up the name of 1 of 3 restricted service(s).",
+			},
+		},
+		{
+			name:   "scoped token with failed service lookups as JSON",
+			args:   args("whoami --json"),
+			client: testutil.WhoamiVerifyClient(testutil.WhoamiBasicResponse),
+			api: mock.API{
+				GetTokenSelfFn: func() (*fastly.Token, error) {
+					return &fastly.Token{
+						Scope:    fastly.ToPointer(fastly.GlobalScope),
+						Services: []string{"3cccc", "4dddd"},
+					}, nil
+				},
+				GetServiceFn: func(i *fastly.GetServiceInput) (*fastly.Service, error) {
+					if i.ServiceID == "4dddd" {
+						return nil, testutil.Err
+					}
+					return &fastly.Service{Name: fastly.ToPointer("Service " + i.ServiceID)}, nil
+				},
+			},
+			wantOutputs: []string{
+				`"scopes": [
+      "global"
+    ]`,
+				`"restricted_services": [
+      {
+        "id": "3cccc",
+        "name": "Service 3cccc"
+      },
+      {
+        "id": "4dddd",
+        "error": "test error"
+      }
+    ]`,
+				`"warnings": [`,
+			},
+		},
+		{
+			name:   "token details unavailable",
+			args:   args("whoami"),
+			client: testutil.WhoamiVerifyClient(testutil.WhoamiBasicResponse),
+			api: mock.API{
+				GetTokenSelfFn: func() (*fastly.Token, error) {
+					return nil, testutil.Err
+				},
+			},
+			wantOutputs: []string{
+				"Alice Programmer <alice@example.com>\n",
+				"Unable to fetch the token's details: test error",
+			},
+		},
 	} {
 		t.Run(testcase.name, func(t *testing.T) {
 			var stdout bytes.Buffer
 			opts := testutil.MockGlobalData(testcase.args, &stdout)
 			opts.Env = testcase.env
 			opts.HTTPClient = testcase.client
+			if testcase.api.GetTokenSelfFn == nil {
+				testcase.api.GetTokenSelfFn = testutil.WhoamiTokenSelf
+			}
+			opts.APIClientFactory = mock.APIClient(testcase.api)
 			app.Init = func(_ []string, _ io.Reader) (*global.Data, error) {
 				return opts, nil
 			}
@@ -85,6 +193,9 @@ func TestWhoami(t *testing.T) {
 			t.Log(stdout.String())
 			testutil.AssertErrorContains(t, err, testcase.wantError)
 			testutil.AssertStringContains(t, stdout.String(), testcase.wantOutput)
+			for _, s := range testcase.wantOutputs {
+				testutil.AssertStringContains(t, stdout.String(), s)
+			}
 		})
 	}
 }
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"bytes"
 	_ "embed"
 	"errors"
 	"fmt"
@@ -63,6 +64,55 @@ type Fastly struct {
 	AccountEndpoint string `toml:"account_endpoint"`
 }
 
+// DNS represents the DNS records domains are expected to point at.
+type DNS struct {
+	// CNAMETarget is the hostname subdomains should CNAME to.
+	CNAMETarget string `toml:"cname_target"`
+	// ApexAddresses are the IP addresses apex domains should have A records for.
+	ApexAddresses []string `toml:"apex_addresses"`
+}
+
+// HTTP represents HTTP client configuration.
+type HTTP struct {
+	// ProxyURL is the proxy used for all HTTP requests.
+	// If unset, the standard HTTPS_PROXY/HTTP_PROXY/NO_PROXY variables apply.
+	ProxyURL string `toml:"proxy_url"`
+	// RequestTimeout is the overall timeout for a HTTP request (e.g. "2m").
+	RequestTimeout string `toml:"request_timeout"`
+	// TLSHandshakeTimeout is the timeout for the TLS handshake (e.g. "10s").
+	TLSHandshakeTimeout string `toml:"tls_handshake_timeout"`
+	// MaxIdleConnsPerHost is the number of idle connections kept open per host
+	// for reuse (e.g. by the workers of a bulk import). Zero uses the default.
+	MaxIdleConnsPerHost int `toml:"max_idle_conns_per_host"`
+	// IdleConnTimeout is how long an idle connection is kept open (e.g. "90s").
+	IdleConnTimeout string `toml:"idle_conn_timeout"`
+}
+
+// Limits represents the plan limits resource usage is reported against.
+//
+// NOTE: The Fastly API doesn't expose these limits, so they're only known when
+// configured. A zero value means the limit is unknown.
+type Limits struct {
+	// DictionariesPerService is the number of dictionaries a service version
+	// can have.
+	DictionariesPerService int `toml:"dictionaries_per_service"`
+	// DictionaryItems is the number of items a dictionary can hold.
+	DictionaryItems int `toml:"dictionary_items"`
+	// Services is the number of services an account can have.
+	Services int `toml:"services"`
+}
+
+// ComputeABI represents the interface between a Compute program and the
+// platform, which a package's Wasm binary is checked against before it's
+// uploaded (see `fastly compute deploy`).
+type ComputeABI struct {
+	// Entrypoint is the function the Wasm binary must export (e.g. "_start").
+	Entrypoint string `toml:"entrypoint"`
+	// ImportModules are the import modules (hostcall namespaces) provided to a
+	// Compute program.
+	ImportModules []string `toml:"import_modules"`
+}
+
 // WasmMetadata represents what metadata will be collected.
 type WasmMetadata struct {
 	// BuildInfo represents information regarding the time taken for builds and
@@ -81,16 +131,78 @@ type WasmMetadata struct {
 	ScriptInfo string `toml:"script_info"`
 }
 
+// Audit represents the audit log configuration.
+type Audit struct {
+	// Enabled records the invocations of mutating commands to the audit log.
+	Enabled bool `toml:"enabled"`
+}
+
 // CLI represents CLI specific configuration.
 type CLI struct {
+	// Locale is the locale user-facing messages are displayed in (e.g. "fr").
+	Locale string `toml:"locale"`
+	// MassDeletionThreshold is the percentage of the existing items a sync
+	// command can delete without --allow-mass-deletion (zero for the default).
+	MassDeletionThreshold int `toml:"mass_deletion_threshold"`
 	// MetadataNoticeDisplayed indicates if the user has been notified of the
 	// metadata behaviours being enabled by default and how they can opt-out.
 	MetadataNoticeDisplayed bool `toml:"metadata_notice_displayed"`
+	// TerminalWidth overrides the detected width of the terminal, which
+	// output is wrapped to fit (zero to detect it, see text.DetectWidth).
+	TerminalWidth int `toml:"terminal_width"`
+	// Theme is the name of the color theme (e.g. "colorblind-safe").
+	Theme string `toml:"theme"`
+	// TimeFormat is how timestamps are displayed: "default", "rfc3339",
+	// "relative" or a custom layout (e.g. "02 Jan 2006 15:04").
+	TimeFormat string `toml:"time_format"`
+	// TimeZone is the timezone timestamps are displayed in: "utc", "local" or
+	// an IANA name (e.g. "Europe/London").
+	TimeZone string `toml:"time_zone"`
 	// Version indicates the CLI configuration version.
 	// It is updated each time a change is made to the config structure.
 	Version string `toml:"version"`
 }
 
+// MaxRecentServices is the number of recently used service IDs and names
+// remembered for shell completion.
+const MaxRecentServices = 20
+
+// Completion represents shell completion configuration.
+type Completion struct {
+	// RecentServiceIDs are recently used service IDs (most recent first).
+	RecentServiceIDs []string `toml:"recent_service_ids"`
+	// RecentServiceNames are recently used service names (most recent first).
+	RecentServiceNames []string `toml:"recent_service_names"`
+}
+
+// RecordService remembers a service ID and/or name for shell completion. It
+// reports whether the remembered values changed.
+func (c *Completion) RecordService(id, name string) bool {
+	var changedID, changedName bool
+	if id != "" {
+		c.RecentServiceIDs, changedID = pushRecent(c.RecentServiceIDs, id)
+	}
+	if name != "" {
+		c.RecentServiceNames, changedName = pushRecent(c.RecentServiceNames, name)
+	}
+	return changedID || changedName
+}
+
+// pushRecent moves v to the front of values, keeping at most
+// MaxRecentServices values.
+func pushRecent(values []string, v string) ([]string, bool) {
+	if len(values) > 0 && values[0] == v {
+		return values, false
+	}
+	recent := []string{v}
+	for _, existing := range values {
+		if existing != v && len(recent) < MaxRecentServices {
+			recent = append(recent, existing)
+		}
+	}
+	return recent, true
+}
+
 // Versioner represents GitHub assets configuration.
 // e.g. viceroy, wasm-tools etc.
 type Versioner struct {
@@ -98,10 +210,28 @@ type Versioner struct {
 	LastChecked string `toml:"last_checked"`
 	// LatestVersion is the latest asset version at the time it is set.
 	LatestVersion string `toml:"latest_version"`
+	// Pin is the asset version to use instead of the latest, unless a project
+	// pins a version in its manifest (only supported by viceroy).
+	Pin string `toml:"pin,omitempty"`
 	// TTL is how long the CLI waits before considering the asset version stale.
 	TTL string `toml:"ttl"`
 }
 
+// UpdateCheck represents the CLI new version check configuration.
+type UpdateCheck struct {
+	// Disabled prevents the CLI from checking for new versions.
+	Disabled bool `toml:"disabled"`
+	// Interval is how long the CLI waits between checks (e.g. "24h").
+	Interval string `toml:"interval"`
+	// LastChecked is when the CLI version was last checked.
+	LastChecked string `toml:"last_checked"`
+	// LatestVersion is the latest CLI version seen at the last check.
+	LatestVersion string `toml:"latest_version"`
+	// PreviousVersion is the CLI version `fastly update` last replaced, so the
+	// release notes since then can be displayed (see `fastly update --changelog`).
+	PreviousVersion string `toml:"previous_version"`
+}
+
 // Language represents Compute language specific configuration.
 type Language struct {
 	Go   Go   `toml:"go"`
@@ -137,6 +267,18 @@ type Rust struct {
 	WasmWasiTarget string `toml:"wasm_wasi_target"`
 }
 
+// Aliases maps an alias name to the command it expands to.
+// e.g. cbd = "compute build --verbose"
+type Aliases map[string]string
+
+// ServiceAliases maps a service alias to the ID of the service.
+// e.g. prod-api = "SU1Z0isxPaozGVKXdv0eY"
+type ServiceAliases map[string]string
+
+// CommandDefaults maps a command path to the default values of its flags.
+// e.g. "compute deploy" = { non-interactive = true, status-check-timeout = 300 }
+type CommandDefaults map[string]map[string]any
+
 // Profiles represents multiple profile accounts.
 type Profiles map[string]*Profile
 
@@ -158,6 +300,9 @@ type Profile struct {
 	RefreshTokenCreated int64 `toml:"refresh_token_created" json:"refresh_token_created"`
 	// RefreshTokenTTL indicates when the refresh token needs to be replaced.
 	RefreshTokenTTL int `toml:"refresh_token_ttl" json:"refresh_token_ttl"`
+	// ServiceID is the service used when none is given and there's no
+	// fastly.toml manifest (see manifest.Data.ServiceID).
+	ServiceID string `toml:"service_id,omitempty" json:"service_id,omitempty"`
 	// Token is a temporary token used to interact with the Fastly API.
 	Token string `toml:"token" json:"token"`
 }
@@ -188,18 +333,39 @@ func ensureConfigDirExists(path string) error {
 
 // File represents our application toml configuration.
 type File struct {
+	// Aliases represents user-defined command aliases.
+	Aliases Aliases `toml:"alias"`
+	// Audit represents the audit log configuration.
+	Audit Audit `toml:"audit"`
 	// CLI represents CLI specific configuration.
 	CLI CLI `toml:"cli"`
+	// CommandDefaults represents user-defined default flag values per command.
+	CommandDefaults CommandDefaults `toml:"command_defaults"`
+	// Completion represents shell completion configuration.
+	Completion Completion `toml:"completion"`
+	// ComputeABI represents what a Compute package is checked against before
+	// it's uploaded.
+	ComputeABI ComputeABI `toml:"compute-abi"`
 	// ConfigVersion is the version of the config.
 	ConfigVersion int `toml:"config_version"`
+	// DNS represents the DNS records domains are expected to point at.
+	DNS DNS `toml:"dns"`
 	// Fastly represents fastly specific configuration.
 	Fastly Fastly `toml:"fastly"`
+	// HTTP represents HTTP client configuration.
+	HTTP HTTP `toml:"http"`
 	// Language represents C@E language specific configuration.
 	Language Language `toml:"language"`
+	// Limits represents the plan limits resource usage is reported against.
+	Limits Limits `toml:"limits"`
 	// Profiles represents multiple profile accounts.
 	Profiles Profiles `toml:"profile"`
+	// ServiceAliases represents user-defined service aliases.
+	ServiceAliases ServiceAliases `toml:"service_alias"`
 	// StarterKitLanguages represents language specific starter kits.
 	StarterKits StarterKitLanguages `toml:"starter-kits"`
+	// UpdateCheck represents the CLI new version check configuration.
+	UpdateCheck UpdateCheck `toml:"update-check"`
 	// Viceroy represents viceroy specific configuration.
 	Viceroy Versioner `toml:"viceroy"`
 	// WasmMetadata represents what metadata will be collected.
@@ -226,6 +392,11 @@ type File struct {
 	// but it means we need to expose Setter methods.
 	autoYes        bool
 	nonInteractive bool
+
+	// newerVersion is the config_version of a config file written by a newer
+	// version of the CLI, in which case the static config is used instead and
+	// the file mustn't be overwritten (see useNewerFallback).
+	newerVersion int
 }
 
 // SetAutoYes sets the associated flag value.
@@ -253,6 +424,10 @@ var Static []byte
 // confirm whether to use the static config embedded in the CLI binary. If the
 // user local configuration is deemed to be invalid, then we'll automatically
 // switch to the static config and migrate the user's profile data (if any).
+//
+// If the user local configuration has a config_version newer than the static
+// config, then it was written by a newer version of the CLI and might not be
+// understood, so the static config is used without modifying the file.
 func (f *File) Read(
 	path string,
 	in io.Reader,
@@ -263,7 +438,7 @@ func (f *File) Read(
 	// Ensure the static config is sound. This should never happen (tm).
 	// We are checking this earlier to simplify the code later on.
 	var staticConfig File
-	err := toml.Unmarshal(Static, &staticConfig)
+	err := decode(Static, &staticConfig)
 	if err != nil {
 		errLog.Add(err)
 		return invalidStaticConfigErr(err)
@@ -282,7 +457,12 @@ func (f *File) Read(
 		data = Static
 	}
 
-	unmarshalErr := toml.Unmarshal(data, f)
+	if version, ok := schemaVersion(data); ok && version > CurrentConfigVersion {
+		f.useNewerFallback(path, data, staticConfig, version, out, errLog, verbose)
+		return nil
+	}
+
+	unmarshalErr := decode(data, f)
 	if unmarshalErr != nil {
 		errLog.Add(unmarshalErr)
 
@@ -295,7 +475,7 @@ func (f *File) Read(
 		if !f.autoYes {
 			replacement := "Replace it with a valid version? (any existing email/token data will be lost) [y/N] "
 			label := fmt.Sprintf("Your configuration file (%s) is invalid. %s", path, replacement)
-			cont, err := text.AskYesNo(out, label, in)
+			cont, err := text.AskYesNo(out, label, in, "--auto-yes")
 			if err != nil {
 				return fmt.Errorf("error reading input: %w", err)
 			}
@@ -400,10 +580,13 @@ func (f *File) NeedsUpdating(data []byte, out io.Writer, errLog fsterr.LogInterf
 //
 // NOTE: We will attempt to migrate the profile data.
 func (f *File) UseStatic(path string) error {
-	err := toml.Unmarshal(Static, f)
+	err := decode(Static, f)
 	if err != nil {
 		return invalidStaticConfigErr(err)
 	}
+	// Resetting the config is an explicit request to replace the file, even if
+	// it was written by a newer version of the CLI.
+	f.newerVersion = 0
 
 	f.CLI.Version = revision.SemVer(revision.AppVersion)
 	f.MigrateLegacy()
@@ -417,7 +600,14 @@ func (f *File) UseStatic(path string) error {
 }
 
 // Write encodes in-memory data to disk.
+//
+// NOTE: An error is returned if the config file was written by a newer
+// version of the CLI, as it would be replaced by the static config.
 func (f *File) Write(path string) error {
+	if f.newerVersion > 0 {
+		return newerConfigErr(path, f.newerVersion)
+	}
+
 	// gosec flagged this:
 	// G304 (CWE-22): Potential file inclusion via variable
 	//
@@ -426,21 +616,60 @@ func (f *File) Write(path string) error {
 	/* #nosec */
 	fp, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, FilePermissions)
 	if err != nil {
-		return fmt.Errorf("error creating config file: %w", err)
+		return filesystem.Wrap("create config file", path, err)
 	}
 	encoder := toml.NewEncoder(fp)
 	// Remove leading spaces from the TOML file.
 	encoder.Indentation("")
 	if err := encoder.Encode(f); err != nil {
-		return fmt.Errorf("error writing to config file: %w", err)
+		_ = fp.Close()
+		return filesystem.Wrap("write config file", path, err)
 	}
 	if err := fp.Close(); err != nil {
-		return fmt.Errorf("error saving config file changes: %w", err)
+		return filesystem.Wrap("save config file", path, err)
 	}
 
 	return nil
 }
 
+// WriteAtomic encodes in-memory data to disk like Write, but via a temporary
+// file that replaces the file at path, so the config is never left partly
+// written (e.g. if the CLI is interrupted).
+func (f *File) WriteAtomic(path string) (err error) {
+	if f.newerVersion > 0 {
+		return newerConfigErr(path, f.newerVersion)
+	}
+
+	var buf bytes.Buffer
+	encoder := toml.NewEncoder(&buf)
+	// Remove leading spaces from the TOML file.
+	encoder.Indentation("")
+	if err := encoder.Encode(f); err != nil {
+		return filesystem.Wrap("write config file", path, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return filesystem.Wrap("create config file", path, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	_, err = tmp.Write(buf.Bytes())
+	if err == nil {
+		err = tmp.Chmod(FilePermissions)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return filesystem.Wrap("write config file", tmp.Name(), err)
+	}
+	return filesystem.Rename(tmp.Name(), path)
+}
+
 // Environment represents all of the configuration parameters that can come
 // from environment variables.
 type Environment struct {
@@ -450,11 +679,49 @@ type Environment struct {
 	APIEndpoint string
 	// APIToken is the env var we look in for the Fastly API token.
 	APIToken string
+	// CI is the env var that identified a CI environment (see env.DetectCI).
+	CI string
+	// ConfigFile is the path of the config file to use.
+	ConfigFile string
+	// DebugHTTP indicates to the CLI it should log API requests/responses.
+	DebugHTTP string
 	// DebugMode indicates to the CLI it can display debug information.
 	DebugMode string
+	// Events is where progress events are written.
+	Events string
+	// HTTPProxy is the proxy URL to use for all HTTP requests.
+	HTTPProxy string
+	// HTTPRequestTimeout is the overall HTTP request timeout.
+	HTTPRequestTimeout string
+	// HTTPTLSHandshakeTimeout is the HTTP TLS handshake timeout.
+	HTTPTLSHandshakeTimeout string
+	// Locale is the locale user-facing messages are displayed in.
+	Locale string
+	// NoUpdateCheck disables the check for new CLI versions.
+	NoUpdateCheck string
+	// Offline disables non-essential network requests.
+	Offline string
+	// Quiet silences all output except direct command output.
+	Quiet string
+	// SourceDateEpoch is the timestamp recorded in reproducible packages.
+	SourceDateEpoch string
+	// Strict turns deprecation warnings into errors.
+	Strict string
+	// Theme is the name of the color theme.
+	Theme string
+	// TimeFormat is how timestamps are displayed.
+	TimeFormat string
+	// TimeZone is the timezone timestamps are displayed in.
+	TimeZone string
+	// TraceEndpoint is the collector endpoint command traces are sent to.
+	TraceEndpoint string
+	// TraceFile is the file command traces are written to.
+	TraceFile string
 	// UseSSO indicates if user wants to use SSO/OAuth token flow.
 	// 1: enabled, 0: disabled.
 	UseSSO string
+	// Verbosity is the level of additional output.
+	Verbosity string
 	// WasmMetadataDisable is the env var we look in to disable all data
 	// collection related to a Wasm binary.
 	// Set to "true" to disable all forms of data collection.
@@ -466,8 +733,27 @@ func (e *Environment) Read(state map[string]string) {
 	e.AccountEndpoint = state[env.AccountEndpoint]
 	e.APIEndpoint = state[env.APIEndpoint]
 	e.APIToken = state[env.APIToken]
+	e.CI = env.DetectCI(state)
+	e.ConfigFile = state[env.ConfigFile]
+	e.DebugHTTP = state[env.DebugHTTP]
 	e.DebugMode = state[env.DebugMode]
+	e.Events = state[env.Events]
+	e.HTTPProxy = state[env.HTTPProxy]
+	e.HTTPRequestTimeout = state[env.HTTPRequestTimeout]
+	e.HTTPTLSHandshakeTimeout = state[env.HTTPTLSHandshakeTimeout]
+	e.Locale = state[env.Locale]
+	e.NoUpdateCheck = state[env.NoUpdateCheck]
+	e.Offline = state[env.Offline]
+	e.Quiet = state[env.Quiet]
+	e.SourceDateEpoch = state[env.SourceDateEpoch]
+	e.Strict = state[env.Strict]
+	e.Theme = state[env.Theme]
+	e.TimeFormat = state[env.TimeFormat]
+	e.TimeZone = state[env.TimeZone]
+	e.TraceEndpoint = state[env.TraceEndpoint]
+	e.TraceFile = state[env.TraceFile]
 	e.UseSSO = state[env.UseSSO]
+	e.Verbosity = state[env.Verbosity]
 	e.WasmMetadataDisable = state[env.WasmMetadataDisable]
 }
 
@@ -475,7 +761,7 @@ func (e *Environment) Read(state map[string]string) {
 // the CLI's internal configuration.
 func invalidStaticConfigErr(err error) error {
 	return fsterr.RemediationError{
-		Inner:       fmt.Errorf("%v: %v", ErrInvalidConfig, err),
+		Inner:       fmt.Errorf("%w: %w", ErrInvalidConfig, err),
 		Remediation: fsterr.InvalidStaticConfigRemediation,
 	}
 }
@@ -493,3 +779,25 @@ var FilePath = func() string {
 	}
 	panic("unable to deduce user config dir or user home dir")
 }()
+
+// Path returns the config file to use: the override (from --config-file or
+// FASTLY_CONFIG_FILE) made absolute, otherwise FilePath.
+//
+// An error is returned if the override is a directory, as every read and
+// write of the config would otherwise fail.
+func Path(override string) (string, error) {
+	if override == "" {
+		return FilePath, nil
+	}
+	path, err := filepath.Abs(override)
+	if err != nil {
+		return "", fmt.Errorf("failed to construct absolute path to config file '%s': %w", override, err)
+	}
+	if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+		return "", fsterr.RemediationError{
+			Inner:       fmt.Errorf("config file '%s' is a directory", path),
+			Remediation: fmt.Sprintf("Set --config-file (or %s) to the path of a file, e.g. %s", env.ConfigFile, filepath.Join(override, FileName)),
+		}
+	}
+	return path, nil
+}
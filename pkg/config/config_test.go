@@ -3,6 +3,8 @@ package config_test
 import (
 	"bytes"
 	_ "embed"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -165,6 +167,136 @@ func TestConfigRead(t *testing.T) {
 	}
 }
 
+// TestConfigReadVersions validates how config files are read based on their
+// config_version.
+//
+// NOTE: The config embedded into the CLI is used as the static config.
+func TestConfigReadVersions(t *testing.T) {
+	scenarios := []struct {
+		name               string
+		userConfigFilename string
+		wantFallback       bool
+	}{
+		{
+			name:               "older config should be migrated",
+			userConfigFilename: "config-current.toml",
+		},
+		{
+			name:               "future config should fall back to the static config",
+			userConfigFilename: "config-future.toml",
+			wantFallback:       true,
+		},
+	}
+
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			original, err := os.ReadFile(filepath.Join("testdata", testcase.userConfigFilename))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rootdir := testutil.NewEnv(testutil.EnvOpts{
+				T: t,
+				Write: []testutil.FileIO{
+					{Src: string(original), Dst: "config.toml"},
+				},
+			})
+			defer os.RemoveAll(rootdir)
+			configPath := filepath.Join(rootdir, "config.toml")
+
+			var (
+				f      config.File
+				stdout bytes.Buffer
+			)
+			mockLog := fsterr.MockLog{}
+			err = f.Read(configPath, strings.NewReader(""), &stdout, mockLog, true)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			output := strings.ReplaceAll(stdout.String(), "\n", " ")
+
+			if !testcase.wantFallback {
+				testutil.AssertStringDoesntContain(t, output, "built-in configuration")
+				testutil.AssertStringContains(t, output, "incompatible with the current CLI version")
+				return
+			}
+
+			testutil.AssertStringContains(t, output, fmt.Sprintf("has config_version 99 but this version of the CLI supports up to %d", config.CurrentConfigVersion))
+			testutil.AssertStringContains(t, output, "fastly update")
+			testutil.AssertStringContains(t, output, "Your profiles were read from the configuration file.")
+
+			// The static config is used, along with the profiles.
+			if f.ConfigVersion != config.CurrentConfigVersion {
+				t.Errorf("want config_version %d, have %d", config.CurrentConfigVersion, f.ConfigVersion)
+			}
+			if p, ok := f.Profiles["user"]; !ok || p.Token != "foobar" || !p.Default {
+				t.Errorf("expected the user profile to be migrated: %+v", f.Profiles)
+			}
+			if !f.CLI.MetadataNoticeDisplayed {
+				t.Error("expected metadata_notice_displayed to be migrated")
+			}
+
+			// The config file is left for the newer CLI.
+			err = f.Write(configPath)
+			if !errors.Is(err, config.ErrNewerConfig) {
+				t.Fatalf("want %v, have %v", config.ErrNewerConfig, err)
+			}
+			data, err := os.ReadFile(configPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(data, original) {
+				t.Errorf("expected the config file to be unmodified: %s", data)
+			}
+
+			// Unless the config is explicitly reset.
+			if err := f.UseStatic(configPath); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := f.Read(configPath, strings.NewReader(""), &stdout, mockLog, false); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if f.ConfigVersion != config.CurrentConfigVersion {
+				t.Errorf("want config_version %d once reset, have %d", config.CurrentConfigVersion, f.ConfigVersion)
+			}
+		})
+	}
+}
+
+// TestConfigReadDecodeError validates a malformed config reports the
+// position of the problem.
+func TestConfigReadDecodeError(t *testing.T) {
+	backupStatic := config.Static
+	defer func() {
+		config.Static = backupStatic
+	}()
+	config.Static = staticConfigInvalid
+
+	rootdir := testutil.NewEnv(testutil.EnvOpts{T: t})
+	defer os.RemoveAll(rootdir)
+
+	var f config.File
+	err := f.Read(filepath.Join(rootdir, "config.toml"), strings.NewReader(""), &bytes.Buffer{}, fsterr.MockLog{}, false)
+
+	var re fsterr.RemediationError
+	if !errors.As(err, &re) {
+		t.Fatalf("want a RemediationError, have %T: %v", err, err)
+	}
+	if re.Remediation != fsterr.InvalidStaticConfigRemediation {
+		t.Errorf("want %q, have %q", fsterr.InvalidStaticConfigRemediation, re.Remediation)
+	}
+
+	var de *config.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("want a DecodeError, have %T: %v", err, err)
+	}
+	// The unterminated string starts on the last line of the document.
+	want := bytes.LastIndex(staticConfigInvalid, []byte("api_endpoint"))
+	if de.Line == 0 || de.Offset < want {
+		t.Errorf("want a position at or after byte %d, have line %d, byte %d", want, de.Line, de.Offset)
+	}
+	testutil.AssertErrorContains(t, err, "byte offset")
+}
+
 // TestUseStatic validates legacy user data is migrated successfully.
 func TestUseStatic(t *testing.T) {
 	// We're going to chdir to an temp environment,
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+
+	toml "github.com/pelletier/go-toml"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/revision"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// ErrNewerConfig indicates the config file was written by a newer version of
+// the CLI, and so the embedded config is being used instead.
+var ErrNewerConfig = errors.New("the configuration file was written by a newer version of the CLI")
+
+// DecodeError is a config document that couldn't be decoded, along with the
+// position of the problem.
+type DecodeError struct {
+	// Err is the error returned by the TOML decoder.
+	Err error
+	// Line and Column are the 1-indexed position of the problem (zero if
+	// unknown).
+	Line, Column int
+	// Offset is the 0-indexed byte offset of the problem (-1 if unknown).
+	Offset int
+}
+
+// Error implements the error interface.
+func (e *DecodeError) Error() string {
+	if e.Offset < 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("line %d, column %d (byte offset %d): %v", e.Line, e.Column, e.Offset, e.Err)
+}
+
+// Unwrap returns the error returned by the TOML decoder.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// decodePosition matches the position the TOML decoder prefixes errors with.
+var decodePosition = regexp.MustCompile(`^\((\d+), (\d+)\): `)
+
+// decode unmarshals a config document into v, returning a *DecodeError if the
+// document is malformed or doesn't match the shape of v.
+func decode(data []byte, v any) error {
+	err := toml.Unmarshal(data, v)
+	if err == nil {
+		return nil
+	}
+	de := &DecodeError{Err: err, Offset: -1}
+	if m := decodePosition.FindStringSubmatch(err.Error()); m != nil {
+		de.Line, _ = strconv.Atoi(m[1])
+		de.Column, _ = strconv.Atoi(m[2])
+		de.Err = errors.New(err.Error()[len(m[0]):])
+		de.Offset = byteOffset(data, de.Line, de.Column)
+	}
+	return de
+}
+
+// byteOffset returns the byte offset of a 1-indexed line and column, where
+// the column counts characters rather than bytes. It returns -1 if the
+// position is outside of the document.
+func byteOffset(data []byte, line, column int) int {
+	if line < 1 || column < 1 {
+		return -1
+	}
+	offset := 0
+	for l := 1; l < line; l++ {
+		i := bytes.IndexByte(data[offset:], '\n')
+		if i < 0 {
+			return -1
+		}
+		offset += i + 1
+	}
+	for c := 1; c < column; c++ {
+		if offset >= len(data) || data[offset] == '\n' {
+			break
+		}
+		_, size := utf8.DecodeRune(data[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// schemaVersion returns the config_version of a config document without
+// decoding the rest of it, as a newer document may have a shape this version
+// of the CLI can't decode. The boolean is false if the document is malformed
+// or doesn't have a config_version.
+func schemaVersion(data []byte) (int, bool) {
+	tree, err := toml.LoadBytes(data)
+	if err != nil {
+		return 0, false
+	}
+	v, ok := tree.Get("config_version").(int64)
+	if !ok {
+		return 0, false
+	}
+	return int(v), true
+}
+
+// useNewerFallback switches the in-memory configuration with the static
+// config, as the config file at path has a config_version newer than the CLI
+// supports. Unlike UseStatic the config file isn't modified, so that it's
+// still usable by the newer CLI, and Write refuses to overwrite it.
+//
+// NOTE: The profiles (and whether the metadata notice was displayed) are
+// migrated if they can be decoded.
+func (f *File) useNewerFallback(path string, data []byte, staticConfig File, version int, out io.Writer, errLog fsterr.LogInterface, verbose bool) {
+	errLog.Add(fmt.Errorf("%w: config_version %d is newer than %d", ErrNewerConfig, version, CurrentConfigVersion))
+
+	autoYes, nonInteractive := f.autoYes, f.nonInteractive
+	*f = staticConfig
+	f.autoYes, f.nonInteractive = autoYes, nonInteractive
+	f.CLI.Version = revision.SemVer(revision.AppVersion)
+	f.newerVersion = version
+
+	var newer struct {
+		CLI struct {
+			MetadataNoticeDisplayed bool `toml:"metadata_notice_displayed"`
+		} `toml:"cli"`
+		Profiles Profiles `toml:"profile"`
+	}
+	migrated := true
+	if err := decode(data, &newer); err != nil {
+		errLog.Add(err)
+		migrated = false
+	} else {
+		f.CLI.MetadataNoticeDisplayed = newer.CLI.MetadataNoticeDisplayed
+		f.Profiles = newer.Profiles
+	}
+
+	text.Warning(out, "Your configuration file (%s) has config_version %d but this version of the CLI supports up to %d, so the built-in configuration is being used. %s", path, version, CurrentConfigVersion, fsterr.NewerConfigRemediation)
+	if verbose {
+		if migrated {
+			text.Info(out, "Your profiles were read from the configuration file. The configuration file won't be modified.")
+		} else {
+			text.Info(out, "Your profiles couldn't be read from the configuration file, so no profiles are available. The configuration file won't be modified.")
+		}
+	}
+}
+
+// newerConfigErr generates an error to prevent a config file written by a
+// newer version of the CLI from being overwritten.
+func newerConfigErr(path string, version int) error {
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("%w (config_version %d), refusing to overwrite %s", ErrNewerConfig, version, path),
+		Remediation: fsterr.NewerConfigRemediation,
+	}
+}
@@ -1,2 +1,3 @@
-// Package debug contains functions to ease development of the Fastly CLI.
+// Package debug contains functions to ease development of the Fastly CLI and
+// to help users diagnose API issues (e.g. logging HTTP requests/responses).
 package debug
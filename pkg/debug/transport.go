@@ -0,0 +1,196 @@
+package debug
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/httpclient"
+)
+
+// HTTPPrefix is written at the start of every line logged by Transport so the
+// output can't be confused with regular command output.
+const HTTPPrefix = "[fastly-debug-http]"
+
+// DefaultMaxBodySize is the number of body bytes logged before truncating.
+const DefaultMaxBodySize = 4096
+
+// TruncationMarker is appended to a logged body that exceeded MaxBodySize.
+const TruncationMarker = "...[truncated]"
+
+// RedactedHeaders are request/response headers whose values are never logged.
+var RedactedHeaders = []string{"Authorization", "Cookie", "Fastly-Key", "Set-Cookie"}
+
+// Transport is a http.RoundTripper that logs the details of every request and
+// response that passes through it.
+type Transport struct {
+	// Base is the underlying http.RoundTripper (http.DefaultTransport if nil).
+	Base http.RoundTripper
+	// Bodies enables logging of request and response bodies.
+	Bodies bool
+	// MaxBodySize is the maximum number of body bytes to log.
+	MaxBodySize int
+	// Output is where the log lines are written (typically os.Stderr).
+	// Nothing is logged when nil.
+	Output io.Writer
+	// Summary logs a single line per request (method, path, status and
+	// duration) instead of the full request/response details.
+	Summary bool
+}
+
+// NewHTTPClient returns a copy of the given client with its transport wrapped
+// by a debug Transport that writes to out.
+func NewHTTPClient(c *http.Client, out io.Writer, bodies bool) *http.Client {
+	var client http.Client
+	if c != nil {
+		client = *c
+	}
+	client.Transport = &Transport{
+		Base:        client.Transport,
+		Bodies:      bodies,
+		MaxBodySize: DefaultMaxBodySize,
+		Output:      out,
+	}
+	return &client
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if t.Output == nil {
+		return base.RoundTrip(req)
+	}
+	if t.Summary {
+		return t.summarise(base, req)
+	}
+
+	t.logf("--> %s %s", req.Method, fsterr.FilterToken(req.URL.String()))
+	t.logHeaders(req.Header)
+	if t.Bodies && req.GetBody != nil {
+		// NOTE: GetBody returns a fresh copy so the request isn't consumed.
+		if body, err := req.GetBody(); err == nil {
+			t.logBody(body)
+			_ = body.Close()
+		}
+	}
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	duration := time.Since(start).Round(time.Millisecond)
+
+	if err != nil {
+		t.logf("<-- %s %s failed after %s: %s", req.Method, fsterr.FilterToken(req.URL.String()), duration, fsterr.FilterToken(err.Error()))
+		return resp, err
+	}
+
+	t.logf("<-- %s (%s)%s", resp.Status, duration, requestID(resp.Header))
+	t.logHeaders(resp.Header)
+	if t.Bodies && resp.Body != nil {
+		// NOTE: A failure to read the body is the caller's to handle, so it's
+		// logged and returned when the caller reads past the logged bytes, and
+		// the response is otherwise returned unchanged.
+		var rest io.Reader = resp.Body
+		peek, rerr := io.ReadAll(io.LimitReader(resp.Body, int64(t.maxBodySize()+1)))
+		t.logBody(io.NopCloser(bytes.NewReader(peek)))
+		if rerr != nil {
+			t.logf("    body: error reading: %s", fsterr.FilterToken(rerr.Error()))
+			rest = errReader{rerr}
+		}
+		resp.Body = readCloser{
+			Reader: io.MultiReader(bytes.NewReader(peek), rest),
+			Closer: resp.Body,
+		}
+	}
+
+	return resp, nil
+}
+
+// summarise sends the request and logs a single line describing it.
+func (t *Transport) summarise(base http.RoundTripper, req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	duration := time.Since(start).Round(time.Millisecond)
+	uri := fsterr.FilterToken(req.URL.RequestURI())
+	if err != nil {
+		t.logf("%s %s failed (%s): %s", req.Method, uri, duration, fsterr.FilterToken(err.Error()))
+		return resp, err
+	}
+	t.logf("%s %s %d (%s)", req.Method, uri, resp.StatusCode, duration)
+	return resp, nil
+}
+
+// RedactHeader returns the header value suitable for logging.
+func RedactHeader(key, value string) string {
+	for _, h := range RedactedHeaders {
+		if strings.EqualFold(h, key) {
+			return "REDACTED"
+		}
+	}
+	return fsterr.FilterToken(value)
+}
+
+func (t *Transport) logf(format string, args ...any) {
+	fmt.Fprintf(t.Output, "%s %s\n", HTTPPrefix, fmt.Sprintf(format, args...))
+}
+
+func (t *Transport) logHeaders(h http.Header) {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		t.logf("    %s: %s", k, RedactHeader(k, strings.Join(h.Values(k), ", ")))
+	}
+}
+
+func (t *Transport) logBody(body io.Reader) {
+	limit := t.maxBodySize()
+	b, err := io.ReadAll(io.LimitReader(body, int64(limit+1)))
+	if err != nil || len(b) == 0 {
+		return
+	}
+	s := string(b)
+	if len(b) > limit {
+		s = string(b[:limit]) + TruncationMarker
+	}
+	t.logf("    body: %s", fsterr.FilterToken(s))
+}
+
+func (t *Transport) maxBodySize() int {
+	if t.MaxBodySize > 0 {
+		return t.MaxBodySize
+	}
+	return DefaultMaxBodySize
+}
+
+// requestID returns a formatted request ID if one is found in the headers.
+func requestID(h http.Header) string {
+	if v := httpclient.RequestID(h); v != "" {
+		return fmt.Sprintf(" [request-id: %s]", v)
+	}
+	return ""
+}
+
+// readCloser pairs a replacement body reader with the original body closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// errReader is a reader that always fails with err.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
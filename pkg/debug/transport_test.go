@@ -0,0 +1,210 @@
+package debug_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/fastly/cli/pkg/debug"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestTransportRedaction(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Fastly-Request-Id", "abc123")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer ts.Close()
+
+	var log bytes.Buffer
+	client := debug.NewHTTPClient(ts.Client(), &log, true)
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/service?token=supersecret&page=1", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Fastly-Key", "supersecret")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The response body must still be readable by the caller.
+	testutil.AssertString(t, `{"ok":true}`, string(body))
+
+	output := log.String()
+	testutil.AssertStringDoesntContain(t, output, "supersecret")
+	testutil.AssertStringContains(t, output, "Fastly-Key: REDACTED")
+	testutil.AssertStringContains(t, output, "token=REDACTED&page=1")
+	testutil.AssertStringContains(t, output, "--> POST")
+	testutil.AssertStringContains(t, output, "<-- 200 OK")
+	testutil.AssertStringContains(t, output, "[request-id: abc123]")
+	testutil.AssertStringContains(t, output, "body: hello")
+	testutil.AssertStringContains(t, output, `body: {"ok":true}`)
+
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if !strings.HasPrefix(line, debug.HTTPPrefix) {
+			t.Fatalf("line missing %q prefix: %q", debug.HTTPPrefix, line)
+		}
+	}
+}
+
+func TestTransportBodyTruncation(t *testing.T) {
+	payload := strings.Repeat("x", 20)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(payload))
+	}))
+	defer ts.Close()
+
+	var log bytes.Buffer
+	client := &http.Client{
+		Transport: &debug.Transport{
+			Base:        ts.Client().Transport,
+			Bodies:      true,
+			MaxBodySize: 10,
+			Output:      &log,
+		},
+	}
+
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutil.AssertString(t, payload, string(body))
+	testutil.AssertStringContains(t, log.String(), "body: "+strings.Repeat("x", 10)+debug.TruncationMarker)
+}
+
+func TestTransportBodyReadError(t *testing.T) {
+	errRead := errors.New("connection reset")
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(io.MultiReader(strings.NewReader("partial"), iotest.ErrReader(errRead))),
+			Request:    req,
+		}, nil
+	})
+
+	var log bytes.Buffer
+	client := &http.Client{Transport: &debug.Transport{Base: base, Bodies: true, Output: &log}}
+
+	resp, err := client.Get("https://api.example.com/")
+	if err != nil {
+		t.Fatalf("want the response despite the body failing to be read, have %v", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	// The caller reads the body up to the failure, as without the transport.
+	testutil.AssertString(t, "partial", string(body))
+	if !errors.Is(err, errRead) {
+		t.Errorf("want error %v reading the body, have %v", errRead, err)
+	}
+	testutil.AssertStringContains(t, log.String(), "body: partial")
+	testutil.AssertStringContains(t, log.String(), "body: error reading: connection reset")
+}
+
+// roundTripFunc is a http.RoundTripper backed by a function.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestTransportWithoutBodies(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("response"))
+	}))
+	defer ts.Close()
+
+	var log bytes.Buffer
+	client := debug.NewHTTPClient(ts.Client(), &log, false)
+
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	testutil.AssertStringContains(t, log.String(), "<-- 200 OK")
+	testutil.AssertStringDoesntContain(t, log.String(), "body:")
+}
+
+func TestTransportRegisteredSecrets(t *testing.T) {
+	const secret = "my-plaintext-secret"
+	fsterr.RegisterSecret(secret)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"echo":"` + secret + `"}`))
+	}))
+	defer ts.Close()
+
+	var log bytes.Buffer
+	client := debug.NewHTTPClient(ts.Client(), &log, true)
+
+	resp, err := client.Post(ts.URL, "text/plain", strings.NewReader(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	testutil.AssertStringDoesntContain(t, log.String(), secret)
+	testutil.AssertStringContains(t, log.String(), `body: {"echo":"REDACTED"}`)
+}
+
+func TestTransportSummary(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"msg":"not found"}`))
+	}))
+	defer ts.Close()
+
+	var log bytes.Buffer
+	client := &http.Client{
+		Transport: &debug.Transport{
+			Base:    ts.Client().Transport,
+			Bodies:  true,
+			Output:  &log,
+			Summary: true,
+		},
+	}
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/service/123?token=supersecret", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Fastly-Key", "supersecret")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	output := log.String()
+	testutil.AssertStringDoesntContain(t, output, "supersecret")
+	testutil.AssertStringDoesntContain(t, output, "Fastly-Key")
+	testutil.AssertStringDoesntContain(t, output, "body:")
+	testutil.AssertStringContains(t, output, debug.HTTPPrefix+" GET /service/123?token=REDACTED 404 (")
+	if n := strings.Count(output, "\n"); n != 1 {
+		t.Errorf("want a single line, have %d: %q", n, output)
+	}
+}
@@ -0,0 +1,254 @@
+package deprecation
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fastly/cli/pkg/env"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// Kind is the kind of a deprecated item.
+type Kind string
+
+// The kinds of deprecated items.
+const (
+	KindEnvVar      Kind = "env var"
+	KindFlag        Kind = "flag"
+	KindManifestKey Kind = "manifest key"
+)
+
+// Item is a flag, manifest key or env var that's deprecated.
+type Item struct {
+	// Kind is the kind of item.
+	Kind Kind
+	// Name is the flag (without the leading dashes), the dotted manifest key
+	// (e.g. setup.dictionaries) or the env var.
+	Name string
+	// Command is the command that accepts a flag (e.g. "backend create"), or
+	// empty for a global flag.
+	Command string
+	// Replacement is the item of the same kind to use instead (if any).
+	Replacement string
+	// Hint describes how to migrate from the item, if naming the replacement
+	// isn't enough.
+	Hint string
+	// RemovedIn is the CLI version that removes the item.
+	RemovedIn string
+}
+
+// key identifies the item in the registry and a tracker.
+func (i Item) key() string {
+	return strings.Join([]string{string(i.Kind), i.Command, i.Name}, "\x00")
+}
+
+// String describes the item, e.g. "the --ssl-check-cert flag of `fastly
+// backend create`".
+func (i Item) String() string {
+	switch i.Kind {
+	case KindFlag:
+		if i.Command == "" {
+			return fmt.Sprintf("the --%s flag", i.Name)
+		}
+		return fmt.Sprintf("the --%s flag of `fastly %s`", i.Name, i.Command)
+	case KindManifestKey:
+		return fmt.Sprintf("the `%s` key of the fastly.toml manifest", i.Name)
+	default:
+		return fmt.Sprintf("the %s env var", i.Name)
+	}
+}
+
+// Reference formats the name (or replacement) the way it's used, e.g. a flag
+// with its leading dashes.
+func (i Item) Reference(name string) string {
+	switch i.Kind {
+	case KindFlag:
+		return "--" + name
+	case KindManifestKey:
+		return "`" + name + "`"
+	default:
+		return name
+	}
+}
+
+// Message is the warning displayed when the item is used.
+func (i Item) Message() string {
+	msg := fmt.Sprintf("%s is deprecated", i)
+	if i.RemovedIn != "" {
+		msg += fmt.Sprintf(" and will be removed in %s", i.RemovedIn)
+	}
+	msg += "."
+	if i.Replacement != "" {
+		msg += fmt.Sprintf(" Use %s instead.", i.Reference(i.Replacement))
+	}
+	if i.Hint != "" {
+		msg += " " + i.Hint
+	}
+	return strings.ToUpper(msg[:1]) + msg[1:]
+}
+
+var (
+	mu       sync.Mutex
+	registry = make(map[string]Item)
+)
+
+// Register records a deprecated flag or manifest key so it's listed by
+// `fastly deprecations`, returning the item. It's expected to be called when
+// a package is initialised, e.g.
+//
+//	var dictionariesKey = deprecation.Register(deprecation.Item{...})
+//
+// The deprecated env vars are registered in env.Deprecated instead.
+func Register(i Item) Item {
+	mu.Lock()
+	defer mu.Unlock()
+	registry[i.key()] = i
+	return i
+}
+
+// Items returns every deprecated item, ordered by kind, command and name.
+func Items() []Item {
+	mu.Lock()
+	items := make([]Item, 0, len(registry)+len(env.Deprecated))
+	for _, i := range registry {
+		items = append(items, i)
+	}
+	mu.Unlock()
+	for _, v := range env.Deprecated {
+		items = append(items, envItem(v))
+	}
+	sort.Slice(items, func(a, b int) bool {
+		return items[a].key() < items[b].key()
+	})
+	return items
+}
+
+// envItem returns the item of a deprecated env var.
+func envItem(v env.DeprecatedVar) Item {
+	return Item{Kind: KindEnvVar, Name: v.Name, Replacement: v.Replacement, RemovedIn: v.RemovedIn}
+}
+
+// Tracker records the deprecated items used by an invocation of the CLI and
+// warns about each of them once.
+//
+// Items used before Start is called (e.g. while the arguments are parsed, and
+// before the --strict flag is known) are reported by Start.
+type Tracker struct {
+	mu      sync.Mutex
+	out     io.Writer
+	pending []Item
+	seen    map[string]bool
+	started bool
+	strict  bool
+	used    []Item
+}
+
+// NewTracker returns a tracker for an invocation of the CLI.
+func NewTracker() *Tracker {
+	return &Tracker{seen: make(map[string]bool)}
+}
+
+// Use records the use of a deprecated item. Once the tracker is started the
+// item is warned about, unless it's in strict mode in which case an error is
+// returned. Using an item more than once has no further effect.
+//
+// NOTE: A nil tracker ignores the item, so commands can be tested without one.
+func (t *Tracker) Use(i Item) error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.seen[i.key()] {
+		return nil
+	}
+	t.seen[i.key()] = true
+	t.used = append(t.used, i)
+	if !t.started {
+		t.pending = append(t.pending, i)
+		return nil
+	}
+	return t.report([]Item{i})
+}
+
+// Start reports the items used so far to out, and any used later as they're
+// used. In strict mode an error listing the items is returned instead.
+func (t *Tracker) Start(out io.Writer, strict bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.out = out
+	t.strict = strict
+	t.started = true
+	pending := t.pending
+	t.pending = nil
+	return t.report(pending)
+}
+
+// report warns about the items, or returns an error in strict mode.
+func (t *Tracker) report(items []Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+	if t.strict {
+		return StrictError(items)
+	}
+	for _, i := range items {
+		text.Deprecated(t.out, "%s\n\n", i.Message())
+	}
+	return nil
+}
+
+// Used returns the items used so far, in the order they were first used.
+func (t *Tracker) Used() []Item {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Item(nil), t.used...)
+}
+
+// IsUsed indicates whether the item has been used.
+func (t *Tracker) IsUsed(i Item) bool {
+	if t == nil {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.seen[i.key()]
+}
+
+// Environ records the use of the deprecated env vars set in state (see
+// env.Parse). The value of each is copied to its replacement, unless the
+// replacement is also set, so the deprecated names keep working.
+func (t *Tracker) Environ(state map[string]string) {
+	for _, v := range env.Deprecated {
+		value, ok := state[v.Name]
+		if !ok {
+			continue
+		}
+		// NOTE: The error is reported by Start, as the tracker isn't started yet.
+		_ = t.Use(envItem(v))
+		if _, ok := state[v.Replacement]; !ok && v.Replacement != "" {
+			state[v.Replacement] = value
+		}
+	}
+}
+
+// StrictError is the error returned in strict mode when deprecated items are
+// used.
+func StrictError(items []Item) error {
+	lines := make([]string, 0, len(items))
+	for _, i := range items {
+		lines = append(lines, i.Message())
+	}
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("deprecated items used in strict mode:\n\n  %s", strings.Join(lines, "\n  ")),
+		Remediation: fsterr.DeprecatedRemediation,
+		Code:        fsterr.CodeDeprecated,
+	}
+}
@@ -0,0 +1,126 @@
+package deprecation_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/deprecation"
+	"github.com/fastly/cli/pkg/env"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+var (
+	oldFlag = deprecation.Item{
+		Kind:        deprecation.KindFlag,
+		Name:        "old-name",
+		Command:     "backend create",
+		Replacement: "name",
+		RemovedIn:   "v11.0.0",
+	}
+	oldKey = deprecation.Item{
+		Kind:        deprecation.KindManifestKey,
+		Name:        "setup.dictionaries",
+		Replacement: "setup.config_stores",
+		Hint:        "Refer to the documentation.",
+	}
+)
+
+func TestMessage(t *testing.T) {
+	testutil.AssertString(t, "The --old-name flag of `fastly backend create` is deprecated and will be removed in v11.0.0. Use --name instead.", oldFlag.Message())
+	testutil.AssertString(t, "The `setup.dictionaries` key of the fastly.toml manifest is deprecated. Use `setup.config_stores` instead. Refer to the documentation.", oldKey.Message())
+}
+
+func TestTracker(t *testing.T) {
+	var out bytes.Buffer
+	tr := deprecation.NewTracker()
+
+	// Items used before the tracker is started are reported by Start.
+	testutil.AssertNoError(t, tr.Use(oldFlag))
+	testutil.AssertNoError(t, tr.Use(oldFlag))
+	testutil.AssertString(t, "", out.String())
+	testutil.AssertNoError(t, tr.Start(&out, false))
+	testutil.AssertStringContains(t, out.String(), "DEPRECATED: "+oldFlag.Message())
+
+	// Items used once started are reported as they're used, once.
+	testutil.AssertNoError(t, tr.Use(oldKey))
+	testutil.AssertNoError(t, tr.Use(oldKey))
+	testutil.AssertNoError(t, tr.Use(oldFlag))
+	testutil.AssertEqual(t, 1, strings.Count(out.String(), oldFlag.Message()))
+	testutil.AssertEqual(t, 1, strings.Count(out.String(), oldKey.Message()))
+	testutil.AssertEqual(t, []deprecation.Item{oldFlag, oldKey}, tr.Used())
+}
+
+func TestTrackerStrict(t *testing.T) {
+	var out bytes.Buffer
+	tr := deprecation.NewTracker()
+
+	testutil.AssertNoError(t, tr.Use(oldFlag))
+	err := tr.Start(&out, true)
+	testutil.AssertErrorContains(t, err, "deprecated items used in strict mode:\n\n  "+oldFlag.Message())
+	var re fsterr.RemediationError
+	testutil.AssertBool(t, true, errors.As(err, &re))
+	testutil.AssertEqual(t, fsterr.CodeDeprecated, re.Code)
+
+	err = tr.Use(oldKey)
+	testutil.AssertErrorContains(t, err, oldKey.Message())
+	testutil.AssertNoError(t, tr.Use(oldKey))
+	testutil.AssertString(t, "", out.String())
+}
+
+func TestTrackerNil(t *testing.T) {
+	var tr *deprecation.Tracker
+	testutil.AssertNoError(t, tr.Use(oldFlag))
+	testutil.AssertBool(t, false, tr.IsUsed(oldFlag))
+}
+
+func TestEnviron(t *testing.T) {
+	defer func(vars []env.DeprecatedVar) { env.Deprecated = vars }(env.Deprecated)
+	env.Deprecated = []env.DeprecatedVar{
+		{Name: "FASTLY_OLD", Replacement: "FASTLY_NEW", RemovedIn: "v11.0.0"},
+		{Name: "FASTLY_OTHER", Replacement: "FASTLY_NEWER"},
+	}
+
+	scenarios := []struct {
+		name      string
+		state     map[string]string
+		wantState map[string]string
+		wantUsed  int
+	}{
+		{
+			name:      "deprecated name",
+			state:     map[string]string{"FASTLY_OLD": "1"},
+			wantState: map[string]string{"FASTLY_OLD": "1", "FASTLY_NEW": "1"},
+			wantUsed:  1,
+		},
+		{
+			name:      "both names",
+			state:     map[string]string{"FASTLY_OLD": "1", "FASTLY_NEW": "2"},
+			wantState: map[string]string{"FASTLY_OLD": "1", "FASTLY_NEW": "2"},
+			wantUsed:  1,
+		},
+		{
+			name:      "replacement",
+			state:     map[string]string{"FASTLY_NEW": "2"},
+			wantState: map[string]string{"FASTLY_NEW": "2"},
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			tr := deprecation.NewTracker()
+			tr.Environ(testcase.state)
+			testutil.AssertEqual(t, testcase.wantState, testcase.state)
+			testutil.AssertEqual(t, testcase.wantUsed, len(tr.Used()))
+		})
+	}
+
+	var found bool
+	for _, i := range deprecation.Items() {
+		if i.Kind == deprecation.KindEnvVar && i.Name == "FASTLY_OLD" {
+			found = true
+		}
+	}
+	testutil.AssertBool(t, true, found)
+}
@@ -0,0 +1,4 @@
+// Package deprecation warns about the deprecated flags, fastly.toml manifest
+// keys and env vars used by an invocation of the CLI, naming what replaces
+// them, or rejects them in strict mode (see --strict).
+package deprecation
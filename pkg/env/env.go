@@ -24,6 +24,10 @@ const (
 	// #nosec
 	APIToken = "FASTLY_API_TOKEN"
 
+	// ConfigFile is the env var we look in for the path of the CLI config file
+	// to use instead of the default location (see --config-file).
+	ConfigFile = "FASTLY_CONFIG_FILE"
+
 	// CustomerID is the env var we look in for a Customer ID.
 	CustomerID = "FASTLY_CUSTOMER_ID"
 
@@ -31,21 +35,177 @@ const (
 	// Set to "true" to enable debug mode.
 	DebugMode = "FASTLY_DEBUG_MODE"
 
+	// DebugHTTP enables logging of every API request/response to stderr.
+	// Set to "true" to enable (bodies are also logged when --verbose is set).
+	DebugHTTP = "FASTLY_DEBUG_HTTP"
+
+	// Events is a file descriptor (e.g. 3) or unix socket path that the CLI
+	// writes newline-delimited JSON progress events to (see --events).
+	Events = "FASTLY_EVENTS"
+
+	// HTTPProxy is the env var we look in for a proxy URL to use for all HTTP
+	// requests. It takes precedence over the standard HTTPS_PROXY variable.
+	// e.g. http://proxy.example.com:8080
+	HTTPProxy = "FASTLY_HTTP_PROXY"
+
+	// HTTPRequestTimeout is the env var we look in for the overall HTTP
+	// request timeout. e.g. 30s, 2m
+	HTTPRequestTimeout = "FASTLY_HTTP_REQUEST_TIMEOUT"
+
+	// HTTPTLSHandshakeTimeout is the env var we look in for the HTTP TLS
+	// handshake timeout. e.g. 10s
+	HTTPTLSHandshakeTimeout = "FASTLY_HTTP_TLS_HANDSHAKE_TIMEOUT"
+
+	// NoUpdateCheck disables the check for new versions of the CLI.
+	// Set to "true" to disable the check.
+	NoUpdateCheck = "FASTLY_NO_UPDATE_CHECK"
+
+	// Offline disables non-essential network requests (e.g. checking for new
+	// versions of the CLI). Set to "true" to enable offline mode.
+	Offline = "FASTLY_OFFLINE"
+
+	// Quiet silences all output except direct command output (see --quiet).
+	// Set to "true" to enable quiet mode.
+	Quiet = "FASTLY_QUIET"
+
+	// SourceDateEpoch is the timestamp (seconds since the Unix epoch) recorded
+	// for every file in a reproducible package (see `compute build --reproducible`).
+	SourceDateEpoch = "SOURCE_DATE_EPOCH"
+
 	// ServiceID is the env var we look in for the required Service ID.
 	ServiceID = "FASTLY_SERVICE_ID"
 
+	// Locale is the locale user-facing messages are displayed in, taking
+	// precedence over the config file. e.g. en, fr, fr_FR.UTF-8
+	Locale = "FASTLY_LOCALE"
+
+	// Theme is the name of the color theme, taking precedence over the config
+	// file. e.g. default, colorblind-safe, monochrome-bold
+	Theme = "FASTLY_THEME"
+
+	// TimeFormat is how timestamps are displayed, taking precedence over the
+	// config file. e.g. default, rfc3339, relative, "02 Jan 2006 15:04"
+	TimeFormat = "FASTLY_TIME_FORMAT"
+
+	// TimeZone is the timezone timestamps are displayed in, taking precedence
+	// over the config file. e.g. utc, local, Europe/London
+	TimeZone = "FASTLY_TIME_ZONE"
+
+	// Strict turns the warnings about deprecated flags, manifest keys and env
+	// vars into errors (see --strict). Set to "true" to enable strict mode.
+	Strict = "FASTLY_STRICT"
+
+	// TraceEndpoint is the OTLP/HTTP endpoint of an OpenTelemetry collector the
+	// trace of each command is sent to. e.g. http://localhost:4318
+	TraceEndpoint = "FASTLY_TRACE_ENDPOINT"
+
+	// TraceFile is the path of a file the trace of each command is written to,
+	// as an OTLP/JSON document.
+	TraceFile = "FASTLY_TRACE_FILE"
+
 	// UseSSO enables the CLI to validate the token as an OAuth token.
 	// These tokens aren't traditional tokens generated by the UI.
 	// Instead they generated via an OAuth flow (producing access/refresh tokens).
 	// Assigned value should be a boolean 1/0 (enable/disable).
 	UseSSO = "FASTLY_USE_SSO"
 
+	// Verbosity is the level of additional output (see --verbosity).
+	// e.g. 1 (provenance and timings), 2 (API requests), 3 (API headers/bodies)
+	Verbosity = "FASTLY_VERBOSITY"
+
 	// WasmMetadataDisable is the env var we look in to disable all data
 	// collection related to a Wasm binary.
 	// Set to "true" to disable all forms of data collection.
 	WasmMetadataDisable = "FASTLY_WASM_METADATA_DISABLE"
 )
 
+// Registry lists the env vars read by the CLI (e.g. so `fastly support-bundle`
+// can report those that are set).
+//
+// NOTE: When an env var is added above, add it here too.
+var Registry = []string{
+	AccountEndpoint,
+	APIEndpoint,
+	APIToken,
+	ConfigFile,
+	CustomerID,
+	DebugHTTP,
+	DebugMode,
+	Events,
+	HTTPProxy,
+	HTTPRequestTimeout,
+	HTTPTLSHandshakeTimeout,
+	Locale,
+	NoUpdateCheck,
+	Offline,
+	Quiet,
+	ServiceID,
+	SourceDateEpoch,
+	Strict,
+	Theme,
+	TimeFormat,
+	TimeZone,
+	TraceEndpoint,
+	TraceFile,
+	UseSSO,
+	Verbosity,
+	WasmMetadataDisable,
+}
+
+// Secrets are the env vars in Registry whose values must never be displayed.
+var Secrets = map[string]bool{
+	APIToken: true,
+}
+
+// DeprecatedVar is an env var that has been renamed. The CLI still reads it
+// (when the replacement isn't set) but warns that it's deprecated.
+type DeprecatedVar struct {
+	// Name is the deprecated name.
+	Name string
+	// Replacement is the env var to set instead.
+	Replacement string
+	// RemovedIn is the CLI version that stops reading the deprecated name.
+	RemovedIn string
+}
+
+// Deprecated are the renamed env vars (see the deprecation package).
+//
+// NOTE: When an env var is renamed, add its old name here rather than reading
+// both names where the env var is used.
+var Deprecated = []DeprecatedVar{}
+
+// CIVars are the env vars set by common CI providers. When any of them is set
+// (to a value other than false or 0) prompts are disabled as if the
+// --non-interactive flag was provided, unless --interactive is provided.
+var CIVars = []string{
+	"CI",
+	"BITBUCKET_BUILD_NUMBER",
+	"BUILDKITE",
+	"CIRCLECI",
+	"CODEBUILD_BUILD_ID",
+	"CONTINUOUS_INTEGRATION",
+	"DRONE",
+	"GITHUB_ACTIONS",
+	"GITLAB_CI",
+	"JENKINS_URL",
+	"TEAMCITY_VERSION",
+	"TF_BUILD",
+	"TRAVIS",
+}
+
+// DetectCI returns the first of the CIVars set in the environment, or an
+// empty string if the CLI isn't running in a CI environment.
+func DetectCI(state map[string]string) string {
+	for _, name := range CIVars {
+		switch strings.ToLower(strings.TrimSpace(state[name])) {
+		case "", "0", "false":
+			continue
+		}
+		return name
+	}
+	return ""
+}
+
 // Parse transforms the local environment data structure into a map type.
 func Parse(environ []string) map[string]string {
 	env := map[string]string{}
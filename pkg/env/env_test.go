@@ -41,3 +41,42 @@ func TestVars(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectCI(t *testing.T) {
+	tcs := []struct {
+		name     string
+		state    map[string]string
+		expected string
+	}{
+		{
+			name:  "not a CI environment",
+			state: map[string]string{"HOME": "/home/user"},
+		},
+		{
+			name:     "generic CI variable",
+			state:    map[string]string{"CI": "true"},
+			expected: "CI",
+		},
+		{
+			name:     "provider variable",
+			state:    map[string]string{"GITHUB_ACTIONS": "true"},
+			expected: "GITHUB_ACTIONS",
+		},
+		{
+			name:     "provider variable with any value",
+			state:    map[string]string{"JENKINS_URL": "https://jenkins.example.com"},
+			expected: "JENKINS_URL",
+		},
+		{
+			name:  "CI variable set to false",
+			state: map[string]string{"CI": "false", "TRAVIS": "0"},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if have := DetectCI(tc.state); have != tc.expected {
+				t.Errorf("want %q, have %q", tc.expected, have)
+			}
+		})
+	}
+}
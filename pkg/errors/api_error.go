@@ -0,0 +1,46 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// APIResponse describes the unsuccessful Fastly API response that caused an
+// error. It's useful to Fastly support when investigating API failures.
+type APIResponse struct {
+	// Method is the HTTP method of the request.
+	Method string `json:"method,omitempty"`
+	// Path is the endpoint path of the request.
+	Path string `json:"path,omitempty"`
+	// RequestID is the ID the API assigned to the request (may be empty).
+	RequestID string `json:"request_id,omitempty"`
+	// StatusCode is the HTTP status of the response.
+	StatusCode int `json:"status,omitempty"`
+	// Body is the response body, which is only recorded for responses whose
+	// body is parsed by the CLI (see ParseValidationError).
+	Body string `json:"-"`
+}
+
+// Endpoint returns the method and path of the request, along with the status.
+// e.g. "GET /service/123 (404 Not Found)"
+func (r APIResponse) Endpoint() string {
+	return fmt.Sprintf("%s %s (%d %s)", r.Method, r.Path, r.StatusCode, http.StatusText(r.StatusCode))
+}
+
+// APIError wraps an error with the API response that caused it.
+type APIError struct {
+	// Err is the underlying error (e.g. a fastly.HTTPError).
+	Err error
+	// Response is the API response.
+	Response APIResponse
+}
+
+// Error implements the error interface.
+func (e APIError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e APIError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,302 @@
+package errors
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/fastly/cli/pkg/env"
+)
+
+// Code identifies a class of error, so it can be looked up in the catalog
+// (see `fastly explain`).
+type Code string
+
+// CodePrefix is the prefix of every error code.
+const CodePrefix = "FASTLY_ERR_"
+
+// The codes of the errors in the catalog.
+const (
+	CodeAuth            Code = CodePrefix + "AUTH"
+	CodeBug             Code = CodePrefix + "BUG"
+	CodeClockSkew       Code = CodePrefix + "CLOCK_SKEW"
+	CodeDeprecated      Code = CodePrefix + "DEPRECATED"
+	CodeHost            Code = CodePrefix + "HOST"
+	CodeManifestVersion Code = CodePrefix + "MANIFEST_VERSION"
+	CodeMaxTime         Code = CodePrefix + "MAX_TIME"
+	CodeNetwork         Code = CodePrefix + "NETWORK"
+	CodeNonInteractive  Code = CodePrefix + "NON_INTERACTIVE"
+	CodePackageSize     Code = CodePrefix + "PACKAGE_SIZE"
+	CodeProfile         Code = CodePrefix + "PROFILE"
+	CodeServiceID       Code = CodePrefix + "SERVICE_ID"
+	CodeTokenScope      Code = CodePrefix + "TOKEN_SCOPE"
+	CodeValidation      Code = CodePrefix + "VALIDATION"
+	CodeVersionLocked   Code = CodePrefix + "VERSION_LOCKED"
+)
+
+// CatalogEntry documents an error code.
+type CatalogEntry struct {
+	// Code identifies the error.
+	Code Code `json:"code"`
+	// Summary is a one line description of the error.
+	Summary string `json:"summary"`
+	// Description is the long-form explanation of the error, written in the
+	// subset of Markdown supported by text.Markdown.
+	Description string `json:"description"`
+	// Remediation is how to fix the error.
+	Remediation string `json:"remediation"`
+	// EnvVars are the environment variables related to the error.
+	EnvVars []string `json:"env_vars,omitempty"`
+	// Links are the documentation pages related to the error.
+	Links []string `json:"links,omitempty"`
+}
+
+// catalog is the documentation of every error code.
+var catalog = []CatalogEntry{
+	{
+		Code:    CodeAuth,
+		Summary: "The Fastly API rejected the API token",
+		Description: `The Fastly API responded with 401 Unauthorized. The API token sent with the request is missing, incorrect, expired or has been revoked.
+
+The token is read from the --token flag, then the ` + env.APIToken + ` environment variable, then the profile in the CLI config file (see ` + "`fastly profile list`" + `). Use ` + "`fastly whoami`" + ` to see who the token belongs to.`,
+		Remediation: AuthRemediation,
+		EnvVars:     []string{env.APIToken},
+		Links: []string{
+			"https://docs.fastly.com/en/guides/using-api-tokens",
+			"https://developer.fastly.com/reference/cli/profile/",
+		},
+	},
+	{
+		Code:    CodeBug,
+		Summary: "An unexpected error occurred",
+		Description: `The CLI doesn't know what caused the error, or how it can be fixed. This is the case for unexpected API responses (e.g. 500 Internal Server Error) as well as bugs in the CLI.
+
+Running the command again with --verbose displays more detail, and --debug-mode displays the API requests and responses.`,
+		Remediation: BugRemediation,
+		EnvVars:     []string{env.DebugMode, env.DebugHTTP},
+		Links:       []string{"https://github.com/fastly/cli/issues"},
+	},
+	{
+		Code:    CodeClockSkew,
+		Summary: "The system clock is out of sync with the Fastly API",
+		Description: `The Date header of the Fastly API's response differs from the system clock by more than a few minutes.
+
+TLS certificates and API tokens are only valid for a period of time, so a wrong clock can cause them to be rejected even though they're valid.`,
+		Remediation: ClockSkewRemediation,
+	},
+	{
+		Code:    CodeDeprecated,
+		Summary: "A deprecated flag, manifest key or environment variable was used in strict mode",
+		Description: `Flags, fastly.toml manifest keys and environment variables that have been renamed or replaced keep working until the version of the CLI that removes them, but each use displays a warning naming the replacement.
+
+In strict mode (the --strict flag, or the ` + env.Strict + ` environment variable) the warnings are errors instead, so scripts and CI pipelines can be migrated before the deprecated names stop working. The error lists every deprecated item that was used.`,
+		Remediation: DeprecatedRemediation,
+		EnvVars:     []string{env.Strict},
+	},
+	{
+		Code:    CodeHost,
+		Summary: "A file or directory on the local host couldn't be used",
+		Description: `The CLI failed to read or write a local file, for example because it doesn't exist, a file is in the way, its permissions are too restrictive or the disk is full.
+
+The path is given in the error message.`,
+		Remediation: HostRemediation,
+	},
+	{
+		Code:    CodeManifestVersion,
+		Summary: "The fastly.toml manifest is newer than this version of the CLI",
+		Description: `The manifest_version in fastly.toml is higher than the versions supported by the installed CLI, so the manifest may contain settings this CLI doesn't understand.
+
+This usually happens when a project was created by a newer version of the CLI.`,
+		Remediation: UnrecognisedManifestVersionRemediation,
+		Links:       []string{"https://developer.fastly.com/reference/fastly-toml/"},
+	},
+	{
+		Code:    CodeMaxTime,
+		Summary: "The command was stopped by the --max-time deadline",
+		Description: `The command didn't complete within the duration given by --max-time, so it was stopped and exited with status 124.
+
+Listings display the results gathered before the deadline, which may be incomplete. A change that had been sent to the API when the deadline was reached is allowed to complete.`,
+		Remediation: MaxTimeRemediation,
+	},
+	{
+		Code:    CodeNetwork,
+		Summary: "The CLI couldn't connect to the Fastly API",
+		Description: `A request failed because of a network problem, such as a DNS failure, a refused connection or a timeout.
+
+Requests are sent through the proxy set in the environment (if any), and the timeouts can be configured in the [http] section of the CLI config file or via the environment.`,
+		Remediation: NetworkRemediation,
+		EnvVars: []string{
+			env.APIEndpoint,
+			env.HTTPProxy,
+			env.HTTPRequestTimeout,
+			env.HTTPTLSHandshakeTimeout,
+		},
+		Links: []string{"https://www.fastlystatus.com/"},
+	},
+	{
+		Code:    CodeNonInteractive,
+		Summary: "A prompt needed an answer but the CLI isn't running interactively",
+		Description: `The command needed input, but standard input isn't a terminal (e.g. it's a pipe) or non-interactive mode is enabled, so rather than waiting for an answer that would never arrive the command stopped.
+
+Non-interactive mode is enabled by --non-interactive, and when a CI environment is detected (e.g. the ` + "`CI`" + ` environment variable is set). Unlike --non-interactive, a CI environment doesn't answer the prompts (e.g. as --auto-yes would).
+
+- Pass the flag named in the error to supply the value instead.
+- Pass --interactive to display prompts in a CI environment, or to answer them from a pipe.`,
+		Remediation: remediation("non-interactive", "Pass the flag named in the error to supply the value, or run the command in a terminal."),
+		EnvVars:     env.CIVars,
+	},
+	{
+		Code:    CodePackageSize,
+		Summary: "The Compute package is larger than the size limit",
+		Description: `The package uploaded by ` + "`fastly compute deploy`" + ` (the Wasm binary and any static files) is larger than the limit for the service.
+
+Build in release mode, and remove any files that aren't needed from the package.`,
+		Remediation: PackageSizeRemediation,
+		Links:       []string{"https://developer.fastly.com/learning/compute/#limitations-and-constraints"},
+	},
+	{
+		Code:    CodeProfile,
+		Summary: "No profile is configured",
+		Description: `A profile names an API token stored in the CLI config file. The CLI uses the default profile unless --profile is given, and there's no profile to use.
+
+A token given by --token or the ` + env.APIToken + ` environment variable is used instead of a profile.`,
+		Remediation: ProfileRemediation,
+		EnvVars:     []string{env.APIToken},
+		Links:       []string{"https://developer.fastly.com/reference/cli/profile/"},
+	},
+	{
+		Code:        CodeServiceID,
+		Summary:     "The command needs a service but none was given",
+		Description: `The command acts on a service, which is identified by --service-id or --service-name, the ` + env.ServiceID + ` environment variable, or the service_id in fastly.toml (in that order).`,
+		Remediation: ServiceIDRemediation,
+		EnvVars:     []string{env.ServiceID},
+		Links:       []string{"https://developer.fastly.com/reference/fastly-toml/"},
+	},
+	{
+		Code:    CodeTokenScope,
+		Summary: "The API token's scope doesn't allow the command",
+		Description: `Before a command that makes changes runs, the CLI looks up the scope of the API token (once per invocation) and checks it allows the command. Most commands need a token with the global scope, whereas ` + "`fastly purge`" + ` only needs purge_select (or purge_all with --all). A token with the global:read scope can only run commands that don't make changes.
+
+Checking first means a command fails before it has made any changes (e.g. cloned a service version), rather than part way through. If the token's scope can't be looked up, --skip-scope-check skips the check.`,
+		Remediation: TokenScopeRemediation,
+		Links:       []string{"https://docs.fastly.com/en/guides/using-api-tokens"},
+	},
+	{
+		Code:    CodeValidation,
+		Summary: "The Fastly API rejected the value of one or more fields",
+		Description: `The Fastly API responded with 422 Unprocessable Entity, listing the fields of the request whose values were invalid (e.g. a name that's already used, or a number that's out of range).
+
+The error names the flag that supplied each value where it's known, otherwise the name of the API field. Running the command again with --verbose displays the API's response as it was received.`,
+		Remediation: ValidationRemediation,
+	},
+	{
+		Code:    CodeVersionLocked,
+		Summary: "The command would change a service version that's locked or active",
+		Description: `A service version can only be changed until it's activated or locked. Commands that change a version (e.g. ` + "`fastly backend create`" + `) check the version before making any changes, and the Fastly API also rejects a change to a version that was locked (or activated) in the meantime.
+
+- Pass --autoclone to clone the version and change the clone instead. The error displays the command to run.
+- Pass --version to select a version that's still editable (see ` + "`fastly service-version list`" + `).
+
+If the version was locked part-way through a command that makes several changes, the error lists the changes that were made before it was locked.`,
+		Remediation: AutoCloneRemediation,
+		Links:       []string{"https://docs.fastly.com/en/guides/working-with-services#working-with-service-versions"},
+	},
+}
+
+// Catalog returns the documentation of every error code, ordered by code.
+func Catalog() []CatalogEntry {
+	entries := append([]CatalogEntry(nil), catalog...)
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Code < entries[j].Code
+	})
+	return entries
+}
+
+// normalizeCode returns a code without the CodePrefix, in upper case with
+// underscores (e.g. 'max-time' is MAX_TIME).
+func normalizeCode(s string) string {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	s = strings.NewReplacer("-", "_", " ", "_").Replace(s)
+	return strings.TrimPrefix(s, CodePrefix)
+}
+
+// LookupCode returns the catalog entry for the code. The CodePrefix is
+// optional and the case is ignored.
+func LookupCode(code string) (CatalogEntry, bool) {
+	want := normalizeCode(code)
+	for _, e := range Catalog() {
+		if normalizeCode(string(e.Code)) == want {
+			return e, true
+		}
+	}
+	return CatalogEntry{}, false
+}
+
+// SearchCodes returns the catalog entries whose code contains the partial
+// code (e.g. 'TIME' matches FASTLY_ERR_MAX_TIME).
+func SearchCodes(partial string) []CatalogEntry {
+	want := normalizeCode(partial)
+	if want == "" {
+		return nil
+	}
+	var matches []CatalogEntry
+	for _, e := range Catalog() {
+		if strings.Contains(normalizeCode(string(e.Code)), want) {
+			matches = append(matches, e)
+		}
+	}
+	return matches
+}
+
+// SuggestCodes returns up to n catalog entries with the codes closest to an
+// unknown code (e.g. a misspelling), closest first.
+func SuggestCodes(code string, n int) []CatalogEntry {
+	want := normalizeCode(code)
+	type scored struct {
+		entry    CatalogEntry
+		distance int
+	}
+	var candidates []scored
+	for _, e := range Catalog() {
+		have := normalizeCode(string(e.Code))
+		d := editDistance(want, have)
+		// A suggestion needs to share at least half of the code, otherwise
+		// every code is as good a suggestion as any other.
+		if d > (max(len(want), len(have))+1)/2 {
+			continue
+		}
+		candidates = append(candidates, scored{e, d})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	var suggestions []CatalogEntry
+	for _, c := range candidates {
+		if len(suggestions) == n {
+			break
+		}
+		suggestions = append(suggestions, c.entry)
+	}
+	return suggestions
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
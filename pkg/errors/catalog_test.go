@@ -0,0 +1,90 @@
+package errors_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+// TestCatalog validates every error code is documented.
+func TestCatalog(t *testing.T) {
+	seen := make(map[errors.Code]bool)
+	for _, e := range errors.Catalog() {
+		t.Run(string(e.Code), func(t *testing.T) {
+			if !strings.HasPrefix(string(e.Code), errors.CodePrefix) {
+				t.Errorf("want the code to start with %s", errors.CodePrefix)
+			}
+			if seen[e.Code] {
+				t.Errorf("duplicate code")
+			}
+			seen[e.Code] = true
+			if strings.TrimSpace(e.Summary) == "" {
+				t.Errorf("want a summary")
+			}
+			if strings.TrimSpace(e.Description) == "" {
+				t.Errorf("want a description")
+			}
+			if strings.TrimSpace(e.Remediation) == "" {
+				t.Errorf("want a remediation")
+			}
+		})
+	}
+}
+
+func TestLookupCode(t *testing.T) {
+	for _, code := range []string{"FASTLY_ERR_MAX_TIME", "fastly_err_max_time", "MAX_TIME", "max-time", " max time "} {
+		e, ok := errors.LookupCode(code)
+		if !ok {
+			t.Fatalf("%q wasn't found", code)
+		}
+		testutil.AssertEqual(t, errors.CodeMaxTime, e.Code)
+	}
+	if _, ok := errors.LookupCode("TIME"); ok {
+		t.Errorf("want a partial code not to be found")
+	}
+}
+
+func TestSearchCodes(t *testing.T) {
+	for _, testcase := range []struct {
+		partial string
+		want    []errors.Code
+	}{
+		{partial: "time", want: []errors.Code{errors.CodeMaxTime}},
+		{partial: "FASTLY_ERR_SERVICE", want: []errors.Code{errors.CodeServiceID}},
+		{partial: "ion", want: []errors.Code{errors.CodeManifestVersion, errors.CodeValidation, errors.CodeVersionLocked}},
+		{partial: "_i", want: []errors.Code{errors.CodeNonInteractive, errors.CodeServiceID}},
+		{partial: "nothing"},
+		{partial: ""},
+	} {
+		t.Run(testcase.partial, func(t *testing.T) {
+			testutil.AssertEqual(t, testcase.want, codes(errors.SearchCodes(testcase.partial)))
+		})
+	}
+}
+
+func TestSuggestCodes(t *testing.T) {
+	for _, testcase := range []struct {
+		code string
+		want []errors.Code
+	}{
+		{code: "AUHT", want: []errors.Code{errors.CodeAuth}},
+		{code: "FASTLY_ERR_NETWRK", want: []errors.Code{errors.CodeNetwork}},
+		{code: "max_tme", want: []errors.Code{errors.CodeMaxTime}},
+		{code: "profiles", want: []errors.Code{errors.CodeProfile}},
+		{code: "completely-unrelated"},
+	} {
+		t.Run(testcase.code, func(t *testing.T) {
+			testutil.AssertEqual(t, testcase.want, codes(errors.SuggestCodes(testcase.code, 3)))
+		})
+	}
+}
+
+func codes(entries []errors.CatalogEntry) []errors.Code {
+	var codes []errors.Code
+	for _, e := range entries {
+		codes = append(codes, e.Code)
+	}
+	return codes
+}
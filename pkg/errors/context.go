@@ -0,0 +1,89 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ContextError wraps an error with a frame describing what the CLI was doing
+// when the error occurred (see PrefixContext).
+type ContextError struct {
+	// Frame is the context frame (e.g. "while uploading package pkg/app.tar.gz").
+	Frame string
+	// Inner is the wrapped error.
+	Inner error
+}
+
+// Unwrap returns the inner error.
+func (ce ContextError) Unwrap() error {
+	return ce.Inner
+}
+
+// Error returns the inner error string, the frame is only displayed when the
+// error is printed (see RemediationError.Print).
+func (ce ContextError) Error() string {
+	if ce.Inner == nil {
+		return ""
+	}
+	return ce.Inner.Error()
+}
+
+// PrefixContext returns a context frame formatted consistently: starting with
+// "while" and without trailing punctuation, e.g.
+//
+//	fsterr.PrefixContext("while deploying service %s version %d", serviceID, version)
+func PrefixContext(format string, args ...any) string {
+	frame := strings.TrimRight(strings.TrimSpace(fmt.Sprintf(format, args...)), ".:;")
+	if !strings.HasPrefix(frame, "while ") {
+		frame = "while " + frame
+	}
+	return frame
+}
+
+// WithContext adds a context frame to err, which becomes its outermost frame.
+// Like wrapping errors, frames are chained by calling WithContext as the error
+// is returned up the call stack. A nil err returns nil.
+//
+// NOTE: A RemediationError keeps its type (the frame is added to its Context)
+// so that callers checking for one aren't affected.
+func WithContext(err error, frame string) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case RemediationError:
+		e.Context = append([]string{frame}, e.Context...)
+		return e
+	default:
+		return ContextError{Frame: frame, Inner: err}
+	}
+}
+
+// Context returns the context frames of err, outermost first, with duplicate
+// frames removed (the outermost is kept).
+func Context(err error) []string {
+	var frames []string
+	for ; err != nil; err = errors.Unwrap(err) {
+		switch e := err.(type) {
+		case ContextError:
+			frames = append(frames, e.Frame)
+		case RemediationError:
+			frames = append(frames, e.Context...)
+		}
+	}
+	return uniqueFrames(frames)
+}
+
+// uniqueFrames removes duplicate frames, keeping the first (outermost).
+func uniqueFrames(frames []string) []string {
+	var unique []string
+	seen := make(map[string]bool, len(frames))
+	for _, f := range frames {
+		if f == "" || seen[f] {
+			continue
+		}
+		seen[f] = true
+		unique = append(unique, f)
+	}
+	return unique
+}
@@ -0,0 +1,112 @@
+package errors_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+	"github.com/fatih/color"
+
+	"github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestPrefixContext(t *testing.T) {
+	for _, testcase := range []struct {
+		format string
+		args   []any
+		want   string
+	}{
+		{
+			format: "while deploying service %s version %d",
+			args:   []any{"123", 4},
+			want:   "while deploying service 123 version 4",
+		},
+		{
+			format: "uploading package %s:\n",
+			args:   []any{"pkg/app.tar.gz"},
+			want:   "while uploading package pkg/app.tar.gz",
+		},
+	} {
+		testutil.AssertString(t, testcase.want, errors.PrefixContext(testcase.format, testcase.args...))
+	}
+}
+
+// TestContext validates context frames are rendered as a stack (outermost
+// first) with duplicate frames removed, and the frames don't change the
+// error's string or its remediation.
+func TestContext(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() {
+		color.NoColor = noColor
+	}()
+
+	var (
+		deploying = errors.PrefixContext("while deploying service %s version %d", "123", 4)
+		uploading = errors.PrefixContext("while uploading package %s", "pkg/app.tar.gz")
+		http401   = &fastly.HTTPError{StatusCode: http.StatusUnauthorized}
+	)
+
+	for _, testcase := range []struct {
+		name            string
+		err             error
+		wantFrames      []string
+		wantError       string
+		wantRemediation string
+	}{
+		{
+			name:       "no context",
+			err:        fmt.Errorf("boom"),
+			wantFrames: nil,
+			wantError:  "boom",
+		},
+		{
+			name:       "chained frames",
+			err:        errors.WithContext(fmt.Errorf("error uploading package: %w", errors.WithContext(fmt.Errorf("boom"), uploading)), deploying),
+			wantFrames: []string{deploying, uploading},
+			wantError:  "error uploading package: boom",
+		},
+		{
+			name:            "RemediationError keeps its type",
+			err:             errors.WithContext(errors.WithContext(errors.RemediationError{Inner: fmt.Errorf("boom"), Remediation: "Try again."}, uploading), deploying),
+			wantFrames:      []string{deploying, uploading},
+			wantError:       "boom",
+			wantRemediation: "Try again.",
+		},
+		{
+			name:            "duplicate frames",
+			err:             errors.WithContext(errors.WithContext(errors.WithContext(http401, deploying), uploading), deploying),
+			wantFrames:      []string{deploying, uploading},
+			wantError:       "the Fastly API returned 401 Unauthorized",
+			wantRemediation: errors.AuthRemediation,
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			testutil.AssertEqual(t, testcase.wantFrames, errors.Context(testcase.err))
+
+			re := errors.Deduce(testcase.err)
+			testutil.AssertString(t, testcase.wantError, re.Error())
+			if testcase.wantRemediation != "" {
+				testutil.AssertString(t, testcase.wantRemediation, re.Remediation)
+			}
+
+			var buf bytes.Buffer
+			re.Print(&buf)
+			var stack []string
+			for i, f := range testcase.wantFrames {
+				stack = append(stack, strings.Repeat("  ", i)+f+"\n")
+			}
+			want := strings.Join(stack, "")
+			if want != "" {
+				want += "\n"
+			}
+			testutil.AssertString(t, want+"ERROR: "+testcase.wantError+".\n", strings.SplitAfter(buf.String(), ".\n")[0])
+		})
+	}
+
+	testutil.AssertEqual(t, nil, errors.WithContext(nil, deploying))
+}
@@ -8,41 +8,119 @@ import (
 	"strings"
 
 	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/text"
 )
 
 // Deduce attempts to deduce a RemediationError from a plain error. If the error
 // is already a RemediationError it is returned directly. Certain deep error
 // types, like a Fastly SDK HTTPError, are detected and converted in appropriate
 // cases to e.g. AuthRemediation. If no specific remediation can be suggested, a
-// remediation to file a bug is used.
+// remediation to file a bug is used. The context frames of the error (see
+// WithContext) are collected into the RemediationError.
 func Deduce(err error) RemediationError {
+	re := deduce(err)
+	re.Context = Context(err)
+	return re
+}
+
+func deduce(err error) RemediationError {
+	var api *APIResponse
+	var apiError APIError
+	if errors.As(err, &apiError) {
+		api = &apiError.Response
+	}
+
+	// NOTE: This is checked first because the deadline is what the user needs
+	// to address, even if the error it caused has its own remediation.
+	var mte MaxTimeError
+	if errors.As(err, &mte) {
+		remediation := MaxTimeRemediation
+		var inner RemediationError
+		if errors.As(err, &inner) && inner.Remediation != "" {
+			remediation += "\n\n" + inner.Remediation
+		}
+		return RemediationError{Inner: err, Remediation: remediation, API: api, Code: CodeMaxTime}
+	}
+
+	// NOTE: This is checked before a RemediationError wrapping it, as the flag
+	// that supplies the missing value is what the user needs to address.
+	var nie text.NonInteractiveError
+	if errors.As(err, &nie) {
+		return RemediationError{Inner: err, Remediation: nie.Remediation(), API: api, Code: CodeNonInteractive}
+	}
+
 	var re RemediationError
 	if errors.As(err, &re) {
+		if re.API == nil {
+			re.API = api
+		}
 		return re // assume the useful suggestion is already baked-in
 	}
 
 	var httpError *fastly.HTTPError
 	if errors.As(err, &httpError) {
-		remediation := BugRemediation
+		remediation, code := BugRemediation, CodeBug
 
 		if httpError.StatusCode == http.StatusUnauthorized {
-			remediation = AuthRemediation
+			remediation, code = AuthRemediation, CodeAuth
+		}
+		if versionLocked(httpError) {
+			remediation, code = AutoCloneRemediation, CodeVersionLocked
+		} else {
+			var body string
+			if api != nil {
+				body = api.Body
+			}
+			if ve, ok := ParseValidationError(httpError, body); ok {
+				return RemediationError{Inner: ve, Remediation: ValidationRemediation, API: api, Code: CodeValidation}
+			}
 		}
 
-		return RemediationError{Inner: SimplifyFastlyError(*httpError), Remediation: remediation}
+		return RemediationError{Inner: SimplifyFastlyError(*httpError), Remediation: remediation, API: api, Code: code}
 	}
 
 	if errors.Is(err, os.ErrNotExist) {
-		return RemediationError{Inner: err, Remediation: HostRemediation}
+		return RemediationError{Inner: err, Remediation: HostRemediation, Code: CodeHost}
 	}
 
 	if t, ok := err.(interface{ Temporary() bool }); ok && t.Temporary() {
-		return RemediationError{Inner: err, Remediation: NetworkRemediation}
+		return RemediationError{Inner: err, Remediation: NetworkRemediation, Code: CodeNetwork}
 	}
 
 	return RemediationError{Inner: err, Remediation: BugRemediation}
 }
 
+// IsVersionLocked reports whether err was caused by changing a service version
+// that's locked or active, as detected before the change was made (see
+// CodeVersionLocked) or reported by the Fastly API.
+func IsVersionLocked(err error) bool {
+	var re RemediationError
+	if errors.As(err, &re) && re.Code == CodeVersionLocked {
+		return true
+	}
+	var httpError *fastly.HTTPError
+	return errors.As(err, &httpError) && versionLocked(httpError)
+}
+
+// versionLocked reports whether the API rejected a change because the service
+// version is locked or active.
+//
+// NOTE: The API doesn't use a distinct status (or error code) for this, so
+// the error messages are checked.
+func versionLocked(httpError *fastly.HTTPError) bool {
+	if httpError.StatusCode < http.StatusBadRequest || httpError.StatusCode >= http.StatusInternalServerError {
+		return false
+	}
+	for _, e := range httpError.Errors {
+		msg := strings.ToLower(e.Title + " " + e.Detail)
+		if strings.Contains(msg, "version is locked") || strings.Contains(msg, "version locked") || strings.Contains(msg, "active version") {
+			return true
+		}
+	}
+	return false
+}
+
 // SimplifyFastlyError reduces the potentially complex and multi-line Error
 // rendering of a fastly.HTTPError to something more palatable for a CLI.
 func SimplifyFastlyError(httpError fastly.HTTPError) error {
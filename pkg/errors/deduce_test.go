@@ -1,13 +1,16 @@
 package errors_test
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/text"
 	"github.com/fastly/go-fastly/v9/fastly"
 )
 
@@ -17,7 +20,11 @@ func TestDeduce(t *testing.T) {
 		re2             = errors.RemediationError{Inner: fmt.Errorf("bar"), Remediation: "Reticulate your splines."}
 		http503         = &fastly.HTTPError{StatusCode: http.StatusInternalServerError}
 		http401         = &fastly.HTTPError{StatusCode: http.StatusUnauthorized}
+		httpLocked      = &fastly.HTTPError{StatusCode: http.StatusBadRequest, Errors: []*fastly.ErrorObject{{Title: "Bad request", Detail: "Version is locked"}}}
 		wrappedNotExist = fmt.Errorf("couldn't do the thing: %w", os.ErrNotExist)
+		maxTime         = errors.MaxTimeError{MaxTime: time.Second, Partial: true}
+		maxTimeRe2      = errors.MaxTimeError{MaxTime: time.Second, Err: re2}
+		nonInteractive  = fmt.Errorf("error reading input: %w", text.NonInteractiveError{Flag: "--name", Reason: "standard input isn't a terminal"})
 	)
 
 	for _, testcase := range []struct {
@@ -38,28 +45,162 @@ func TestDeduce(t *testing.T) {
 		{
 			name:  "fastly.HTTPError 503",
 			input: http503,
-			want:  errors.RemediationError{Inner: errors.SimplifyFastlyError(*http503), Remediation: errors.BugRemediation},
+			want:  errors.RemediationError{Inner: errors.SimplifyFastlyError(*http503), Remediation: errors.BugRemediation, Code: errors.CodeBug},
 		},
 		{
 			name:  "fastly.HTTPError 401",
 			input: http401,
-			want:  errors.RemediationError{Inner: errors.SimplifyFastlyError(*http401), Remediation: errors.AuthRemediation},
+			want:  errors.RemediationError{Inner: errors.SimplifyFastlyError(*http401), Remediation: errors.AuthRemediation, Code: errors.CodeAuth},
+		},
+		{
+			name:  "fastly.HTTPError for a locked version",
+			input: fmt.Errorf("error creating backend: %w", httpLocked),
+			want:  errors.RemediationError{Inner: errors.SimplifyFastlyError(*httpLocked), Remediation: errors.AutoCloneRemediation, Code: errors.CodeVersionLocked},
 		},
 		{
 			name:  "wrapped os.ErrNotExist",
 			input: wrappedNotExist,
-			want:  errors.RemediationError{Inner: wrappedNotExist, Remediation: errors.HostRemediation},
+			want:  errors.RemediationError{Inner: wrappedNotExist, Remediation: errors.HostRemediation, Code: errors.CodeHost},
 		},
 		{
 			name:  "temporary network error",
 			input: isTemporary{fmt.Errorf("baz")},
-			want:  errors.RemediationError{Inner: fmt.Errorf("baz"), Remediation: errors.NetworkRemediation},
+			want:  errors.RemediationError{Inner: fmt.Errorf("baz"), Remediation: errors.NetworkRemediation, Code: errors.CodeNetwork},
+		},
+		{
+			name:  "MaxTimeError",
+			input: maxTime,
+			want:  errors.RemediationError{Inner: maxTime, Remediation: errors.MaxTimeRemediation, Code: errors.CodeMaxTime},
+		},
+		{
+			name:  "MaxTimeError wrapping RemediationError",
+			input: maxTimeRe2,
+			want:  errors.RemediationError{Inner: maxTimeRe2, Remediation: errors.MaxTimeRemediation + "\n\n" + re2.Remediation, Code: errors.CodeMaxTime},
+		},
+		{
+			name:  "wrapped NonInteractiveError",
+			input: nonInteractive,
+			want:  errors.RemediationError{Inner: nonInteractive, Remediation: "Pass --name to supply the value, or run the command in a terminal.", Code: errors.CodeNonInteractive},
 		},
 	} {
 		t.Run(testcase.name, func(t *testing.T) {
 			have := errors.Deduce(testcase.input)
 			testutil.AssertString(t, testcase.want.Error(), have.Error())
 			testutil.AssertString(t, testcase.want.Remediation, have.Remediation)
+			testutil.AssertEqual(t, testcase.want.Code, have.Code)
+		})
+	}
+}
+
+func TestIsVersionLocked(t *testing.T) {
+	locked := func(status int, title, detail string) error {
+		return fmt.Errorf("error updating snippet: %w", &fastly.HTTPError{
+			StatusCode: status,
+			Errors:     []*fastly.ErrorObject{{Title: title, Detail: detail}},
+		})
+	}
+	for _, testcase := range []struct {
+		name  string
+		input error
+		want  bool
+	}{
+		{name: "locked", input: locked(http.StatusBadRequest, "Bad request", "Version is locked"), want: true},
+		{name: "active", input: locked(http.StatusConflict, "Cannot modify the active version", ""), want: true},
+		{name: "detected before the change", input: errors.RemediationError{Inner: fmt.Errorf("service version 1 is not editable"), Code: errors.CodeVersionLocked}, want: true},
+		{name: "other client error", input: locked(http.StatusBadRequest, "Bad request", "Name is required")},
+		{name: "server error", input: locked(http.StatusServiceUnavailable, "Version is locked", "")},
+		{name: "not an API error", input: fmt.Errorf("version is locked")},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			testutil.AssertBool(t, testcase.want, errors.IsVersionLocked(testcase.input))
+		})
+	}
+}
+
+func TestDeduceAPIError(t *testing.T) {
+	response := errors.APIResponse{
+		Method:     http.MethodGet,
+		Path:       "/service/123",
+		RequestID:  "abc123",
+		StatusCode: http.StatusNotFound,
+	}
+	httpError := &fastly.HTTPError{StatusCode: http.StatusNotFound}
+
+	for _, testcase := range []struct {
+		name       string
+		input      error
+		wantOutput string
+		wantJSON   string
+	}{
+		{
+			name:  "with request ID",
+			input: fmt.Errorf("error: %w", errors.APIError{Err: httpError, Response: response}),
+			wantOutput: `ERROR: the Fastly API returned 404 Not Found.
+
+Fastly request ID: abc123
+Fastly API endpoint: GET /service/123 (404 Not Found)
+
+` + errors.BugRemediation + `
+
+For more information, run ` + "`fastly explain FASTLY_ERR_BUG`.\n",
+			wantJSON: `{
+  "error": "the Fastly API returned 404 Not Found",
+  "remediation": "` + errors.BugRemediation + `",
+  "code": "FASTLY_ERR_BUG",
+  "method": "GET",
+  "path": "/service/123",
+  "request_id": "abc123",
+  "status": 404
+}
+`,
+		},
+		{
+			name: "without request ID",
+			input: errors.RemediationError{
+				Inner:       errors.APIError{Err: fmt.Errorf("error fetching service"), Response: errors.APIResponse{Method: http.MethodGet, Path: "/service/123", StatusCode: http.StatusBadGateway}},
+				Remediation: "Try again.",
+			},
+			wantOutput: `ERROR: error fetching service.
+
+Fastly API endpoint: GET /service/123 (502 Bad Gateway)
+
+Try again.
+`,
+			wantJSON: `{
+  "error": "error fetching service",
+  "remediation": "Try again.",
+  "method": "GET",
+  "path": "/service/123",
+  "status": 502
+}
+`,
+		},
+		{
+			name:  "network failure",
+			input: isTemporary{fmt.Errorf("connection reset")},
+			wantOutput: `ERROR: connection reset.
+
+` + errors.NetworkRemediation + `
+
+For more information, run ` + "`fastly explain FASTLY_ERR_NETWORK`.\n",
+			wantJSON: `{
+  "error": "connection reset",
+  "remediation": "` + errors.NetworkRemediation + `",
+  "code": "FASTLY_ERR_NETWORK"
+}
+`,
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			re := errors.Deduce(testcase.input)
+
+			var out bytes.Buffer
+			re.Print(&out)
+			testutil.AssertString(t, testcase.wantOutput, out.String())
+
+			out.Reset()
+			testutil.AssertNoError(t, re.PrintJSON(&out))
+			testutil.AssertString(t, testcase.wantJSON, out.String())
 		})
 	}
 }
@@ -67,3 +208,83 @@ func TestDeduce(t *testing.T) {
 type isTemporary struct{ error }
 
 func (isTemporary) Temporary() bool { return true }
+
+func TestParseValidationError(t *testing.T) {
+	undecoded := func(status int, body string) *fastly.HTTPError {
+		return &fastly.HTTPError{StatusCode: status, Errors: []*fastly.ErrorObject{{Title: "Undefined error", Detail: body}}}
+	}
+	for _, testcase := range []struct {
+		name       string
+		httpError  *fastly.HTTPError
+		body       string
+		wantFields []errors.FieldError
+	}{
+		{
+			name:      "errors by field",
+			httpError: undecoded(http.StatusUnprocessableEntity, `{"msg":"Record invalid","errors":{"port":["is not a number","must be less than 65536"],"name":"has already been taken"}}`),
+			wantFields: []errors.FieldError{
+				{Field: "name", Message: "has already been taken"},
+				{Field: "port", Message: "is not a number"},
+				{Field: "port", Message: "must be less than 65536"},
+			},
+		},
+		{
+			name:      "JSON:API error objects",
+			httpError: &fastly.HTTPError{StatusCode: http.StatusUnprocessableEntity, Errors: []*fastly.ErrorObject{{Title: "Invalid value"}}},
+			body:      `{"errors":[{"title":"Invalid value","detail":"must be a valid hostname","source":{"pointer":"/data/attributes/ssl_cert_hostname"}},{"title":"Invalid request"},{"detail":"is required","source":{"parameter":"item_key"}}]}`,
+			wantFields: []errors.FieldError{
+				{Field: "item_key", Message: "is required"},
+				{Field: "ssl_cert_hostname", Message: "must be a valid hostname"},
+			},
+		},
+		{
+			name:      "legacy errors",
+			httpError: undecoded(http.StatusUnprocessableEntity, `{"msg":"Bad request","detail":"Name is required"}`),
+		},
+		{
+			name:      "errors without fields",
+			httpError: undecoded(http.StatusUnprocessableEntity, `{"errors":[{"title":"Invalid request"}]}`),
+		},
+		{
+			name:      "not JSON",
+			httpError: undecoded(http.StatusUnprocessableEntity, `Unprocessable Entity`),
+		},
+		{
+			name:      "not a 422",
+			httpError: undecoded(http.StatusBadRequest, `{"errors":{"name":["can't be blank"]}}`),
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			ve, ok := errors.ParseValidationError(testcase.httpError, testcase.body)
+			testutil.AssertBool(t, testcase.wantFields != nil, ok)
+			testutil.AssertEqual(t, testcase.wantFields, ve.Fields)
+
+			// A body that can't be parsed falls back to the API's error.
+			re := errors.Deduce(fmt.Errorf("error creating backend: %w", errors.APIError{Err: testcase.httpError, Response: errors.APIResponse{StatusCode: testcase.httpError.StatusCode, Body: testcase.body}}))
+			if !ok {
+				testutil.AssertString(t, errors.SimplifyFastlyError(*testcase.httpError).Error(), re.Error())
+				testutil.AssertEqual(t, errors.CodeBug, re.Code)
+				return
+			}
+			testutil.AssertEqual(t, errors.CodeValidation, re.Code)
+			testutil.AssertString(t, errors.ValidationRemediation, re.Remediation)
+		})
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	ve := errors.ValidationError{Fields: []errors.FieldError{
+		{Field: "name", Message: "has already been taken"},
+		{Field: "shield", Message: "is not a valid POP"},
+	}}
+	ve = ve.WithFlags(func(field string) string {
+		if field == "name" {
+			return "new-name"
+		}
+		return ""
+	})
+	testutil.AssertString(t, `the Fastly API returned 422 Unprocessable Entity:
+
+  invalid value for --new-name: has already been taken
+  invalid value for field 'shield': is not a valid POP`, ve.Error())
+}
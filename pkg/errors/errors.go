@@ -3,6 +3,8 @@ package errors
 import (
 	"errors"
 	"fmt"
+
+	"github.com/fastly/cli/pkg/text"
 )
 
 // ErrSignalInterrupt means a SIGINT was received.
@@ -15,6 +17,11 @@ var ErrSignalKilled = fmt.Errorf("a SIGTERM was received")
 // file modification noticed while running `compute serve --watch`.
 var ErrViceroyRestart = fmt.Errorf("a RESTART was initiated")
 
+// ErrNonInteractive means a prompt needed an answer but the CLI isn't running
+// interactively. The error returned by the prompt is a text.NonInteractiveError
+// naming the flag to use instead, which Deduce turns into a RemediationError.
+var ErrNonInteractive = text.ErrNonInteractive
+
 // ErrDontContinue means the user said "NO" when prompted whether to continue.
 var ErrDontContinue = fmt.Errorf("will not continue")
 
@@ -31,6 +38,15 @@ var ErrIncompatibleServeFlags = RemediationError{
 var ErrNoToken = RemediationError{
 	Inner:       fmt.Errorf("no token provided"),
 	Remediation: AuthRemediation,
+	Code:        CodeAuth,
+}
+
+// ErrNoProfile means there's no API token, and no profile to take one from
+// (e.g. the CLI is run for the first time, but not in a terminal).
+var ErrNoProfile = RemediationError{
+	Inner:       fmt.Errorf("no token provided, and no profile is configured"),
+	Remediation: ProfileRemediation,
+	Code:        CodeProfile,
 }
 
 // ErrNoServiceID means no --service-id or service_id fastly.toml value has
@@ -38,6 +54,7 @@ var ErrNoToken = RemediationError{
 var ErrNoServiceID = RemediationError{
 	Inner:       fmt.Errorf("error reading service: no service ID found"),
 	Remediation: ServiceIDRemediation,
+	Code:        CodeServiceID,
 }
 
 // ErrNoCustomerID means no --customer-id or FASTLY_CUSTOMER_ID environment
@@ -58,6 +75,7 @@ var ErrMissingManifestVersion = RemediationError{
 var ErrUnrecognisedManifestVersion = RemediationError{
 	Inner:       fmt.Errorf("unrecognised manifest_version found in the fastly.toml"),
 	Remediation: UnrecognisedManifestVersionRemediation,
+	Code:        CodeManifestVersion,
 }
 
 // ErrIncompatibleManifestVersion means the manifest_version defined is no
@@ -103,6 +121,13 @@ var ErrPostInitStopped = RemediationError{
 	Remediation: "Check the [scripts.post_init] in the fastly.toml manifest is safe to execute or skip this prompt using either `--auto-yes` or `--non-interactive`.",
 }
 
+// ErrPreBuildStopped means the user stopped the build because they were unhappy
+// with the custom pre_build defined in the fastly.toml manifest file.
+var ErrPreBuildStopped = RemediationError{
+	Inner:       fmt.Errorf("build process stopped by user"),
+	Remediation: "Check the [scripts.pre_build] in the fastly.toml manifest is safe to execute or skip this prompt using either `--auto-yes` or `--non-interactive`.",
+}
+
 // ErrPostBuildStopped means the user stopped the build because they were unhappy
 // with the custom build defined in the fastly.toml manifest file.
 var ErrPostBuildStopped = RemediationError{
@@ -171,3 +196,18 @@ var ErrInvalidEnableDisableFlagCombo = RemediationError{
 	Inner:       fmt.Errorf("invalid flag combination: --enable and --disable"),
 	Remediation: "Use either --enable or --disable, not both.",
 }
+
+// ErrInvalidAcceptRemotePushLocalCombo means the user provided both a
+// --accept-remote and --push-local flag which are mutually exclusive
+// behaviours.
+var ErrInvalidAcceptRemotePushLocalCombo = RemediationError{
+	Inner:       fmt.Errorf("invalid flag combination: --accept-remote and --push-local"),
+	Remediation: "Use either --accept-remote or --push-local, not both.",
+}
+
+// ErrInvalidInteractiveCombo means the user provided both the --interactive
+// and --non-interactive flags which are mutually exclusive behaviours.
+var ErrInvalidInteractiveCombo = RemediationError{
+	Inner:       fmt.Errorf("invalid flag combination: --interactive and --non-interactive"),
+	Remediation: "Use either --interactive or --non-interactive, not both.",
+}
@@ -113,7 +113,7 @@ func (l LogEntries) Persist(logPath string, args []string) error {
 	/* #nosec */
 	defer f.Close()
 
-	cmd = "\nCOMMAND:\n" + cmd + "\n\n"
+	cmd = "\nCOMMAND:\n" + FilterToken(cmd) + "\n\n"
 	if _, err := f.Write([]byte(cmd)); err != nil {
 		return err
 	}
@@ -133,10 +133,14 @@ ERROR:
 `
 	t := template.Must(template.New("record").Parse(record))
 	for _, entry := range l {
-		err := t.Execute(f, entry)
+		var buf strings.Builder
+		err := t.Execute(&buf, entry)
 		if err != nil {
 			return err
 		}
+		if _, err := f.Write([]byte(FilterToken(buf.String()))); err != nil {
+			return err
+		}
 	}
 
 	if _, err := f.Write([]byte("------------------------------\n\n")); err != nil {
@@ -149,16 +153,50 @@ ERROR:
 var (
 	// TokenRegEx matches a Token as part of the error output (https://regex101.com/r/ulIw1m/1)
 	TokenRegEx = regexp.MustCompile(`Token ([\w-]+)`)
-	// TokenFlagRegEx matches the token flag (https://regex101.com/r/YNr78Q/1)
-	TokenFlagRegEx = regexp.MustCompile(`(-t|--token)(\s*=?\s*['"]?)([\w-]+)(['"]?)`)
+	// TokenFlagRegEx matches the token flag (https://regex101.com/r/YNr78Q/1),
+	// but not a word containing it (e.g. auth-token).
+	TokenFlagRegEx = regexp.MustCompile(`(^|[^\w-])(-t|--token)(\s*=?\s*['"]?)([\w-]+)(['"]?)`)
+	// TokenQueryRegEx matches a token passed as a URL query parameter.
+	TokenQueryRegEx = regexp.MustCompile(`(?i)([?&](?:token|access_token|api_key)=)([^&\s"]+)`)
+)
+
+// secrets are sensitive values (e.g. Secret Store values) registered at
+// runtime that must never appear in any logged output.
+var (
+	secrets   []string
+	secretsMu sync.RWMutex
 )
 
-// FilterToken replaces any matched patterns with "REDACTED".
+// minSecretLength is the length of the shortest value RegisterSecret records.
+const minSecretLength = 8
+
+// RegisterSecret records a sensitive value so that FilterToken will redact it.
+//
+// Values shorter than 8 characters are ignored, as every occurrence of them is
+// replaced and a short value (e.g. "abc") would corrupt unrelated output.
+func RegisterSecret(secret string) {
+	if len(strings.TrimSpace(secret)) < minSecretLength {
+		return
+	}
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	secrets = append(secrets, secret)
+}
+
+// FilterToken replaces any matched patterns, and any values registered via
+// RegisterSecret, with "REDACTED".
 //
 // EXAMPLE: https://go.dev/play/p/cT4BwIh9Asa
 func FilterToken(input string) (inputFiltered string) {
 	inputFiltered = TokenRegEx.ReplaceAllString(input, "Token REDACTED")
-	inputFiltered = TokenFlagRegEx.ReplaceAllString(inputFiltered, "${1}${2}REDACTED${4}")
+	inputFiltered = TokenFlagRegEx.ReplaceAllString(inputFiltered, "${1}${2}${3}REDACTED${5}")
+	inputFiltered = TokenQueryRegEx.ReplaceAllString(inputFiltered, "${1}REDACTED")
+
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+	for _, s := range secrets {
+		inputFiltered = strings.ReplaceAll(inputFiltered, s, "REDACTED")
+	}
 	return inputFiltered
 }
 
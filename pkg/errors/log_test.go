@@ -214,3 +214,35 @@ func TestLogPersistLogRotation(t *testing.T) {
 
 	testutil.AssertEqual(t, wanttrim, havetrim)
 }
+
+func TestFilterTokenRegisteredSecret(t *testing.T) {
+	const secret = "hunter2-registered-secret"
+
+	input := fmt.Sprintf("creating secret with value %q", secret)
+	testutil.AssertStringContains(t, errors.FilterToken(input), secret)
+
+	errors.RegisterSecret(secret)
+	errors.RegisterSecret("   ") // whitespace-only values are ignored
+
+	testutil.AssertString(t, `creating secret with value "REDACTED"`, errors.FilterToken(input))
+	testutil.AssertString(t, "nothing to see here", errors.FilterToken("nothing to see here"))
+}
+
+func TestFilterTokenShortSecret(t *testing.T) {
+	errors.RegisterSecret("see")
+	errors.RegisterSecret("  here  ")
+
+	testutil.AssertString(t, "nothing to see here", errors.FilterToken("nothing to see here"))
+}
+
+func TestFilterTokenFlag(t *testing.T) {
+	for input, want := range map[string]string{
+		"fastly service list --token abc123":     "fastly service list --token REDACTED",
+		"fastly service list --token=abc123":     "fastly service list --token=REDACTED",
+		"fastly service list -t 'abc123'":        "fastly service list -t 'REDACTED'",
+		"fastly auth-token rotate":               "fastly auth-token rotate",
+		"fastly auth-token delete --id some-tok": "fastly auth-token delete --id some-tok",
+	} {
+		testutil.AssertString(t, want, errors.FilterToken(input))
+	}
+}
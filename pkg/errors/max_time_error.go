@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxTimeExitCode is the exit status used when the --max-time deadline is
+// reached. It's the status used by the coreutils `timeout` command, so that CI
+// scripts can tell a timeout apart from a failure.
+const MaxTimeExitCode = 124
+
+// MaxTimeError indicates the invocation was stopped by the --max-time deadline.
+type MaxTimeError struct {
+	// MaxTime is the --max-time value.
+	MaxTime time.Duration
+	// Partial indicates the command displayed what it gathered before the
+	// deadline, which may be incomplete.
+	Partial bool
+	// Err is the error caused by the deadline (if any).
+	Err error
+}
+
+// Unwrap returns the inner error.
+func (e MaxTimeError) Unwrap() error {
+	return e.Err
+}
+
+// Error describes the timeout.
+func (e MaxTimeError) Error() string {
+	s := fmt.Sprintf("timed out after %s", e.MaxTime)
+	if e.Partial {
+		s += ", results may be partial"
+	}
+	if e.Err != nil {
+		s += ": " + e.Err.Error()
+	}
+	return s
+}
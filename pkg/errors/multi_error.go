@@ -0,0 +1,36 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MultiError is a list of errors reported together, so the user can fix every
+// problem (e.g. each invalid entry of a batch) before trying again.
+type MultiError []error
+
+// Error lists each error on its own line.
+func (m MultiError) Error() string {
+	if len(m) == 1 {
+		return m[0].Error()
+	}
+	lines := make([]string, 0, len(m)+1)
+	lines = append(lines, fmt.Sprintf("%d problems found:", len(m)))
+	for _, err := range m {
+		lines = append(lines, "\t- "+err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap returns the errors, for use with errors.Is and errors.As.
+func (m MultiError) Unwrap() []error {
+	return m
+}
+
+// ErrorOrNil returns nil when the list is empty, otherwise the MultiError.
+func (m MultiError) ErrorOrNil() error {
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
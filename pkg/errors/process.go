@@ -25,7 +25,10 @@ func Process(err error, args []string, out io.Writer) (skipExit bool) {
 
 	// IMPORTANT: Deduce/Print needs to happen before checking for Skip.
 	// This is so the help output can be printed.
-	Deduce(err).Print(color.Error)
+	re := Deduce(err)
+	if !jsonOutput(args) || re.PrintJSON(color.Error) != nil {
+		re.Print(color.Error)
+	}
 
 	exitError := SkipExitError{}
 	if errors.As(err, &exitError) {
@@ -33,3 +36,29 @@ func Process(err error, args []string, out io.Writer) (skipExit bool) {
 	}
 	return false
 }
+
+// ExitCode reports err (see Process) and returns the CLI's exit code for it.
+// A nil error or one that skips the exit (e.g. --help) results in zero, and
+// reaching the --max-time deadline results in MaxTimeExitCode.
+func ExitCode(err error, args []string, out io.Writer) int {
+	if err == nil {
+		return 0
+	}
+	if skipExit := Process(err, args, out); skipExit {
+		return 0
+	}
+	if errors.As(err, &MaxTimeError{}) {
+		return MaxTimeExitCode
+	}
+	return 1
+}
+
+// jsonOutput reports whether the user requested JSON output (i.e. --json).
+func jsonOutput(args []string) bool {
+	for _, a := range args {
+		if a == "--json" || a == "-j" {
+			return true
+		}
+	}
+	return false
+}
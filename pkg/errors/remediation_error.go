@@ -1,11 +1,13 @@
 package errors
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
 
 	"github.com/fastly/cli/pkg/env"
+	"github.com/fastly/cli/pkg/i18n"
 	"github.com/fastly/cli/pkg/text"
 )
 
@@ -13,10 +15,17 @@ import (
 type RemediationError struct {
 	// Prefix is a custom message displayed without modification.
 	Prefix string
+	// Context is the stack of frames (outermost first) describing what the CLI
+	// was doing when the error occurred (see PrefixContext and WithContext).
+	Context []string
 	// Inner is the root error.
 	Inner error
 	// Remediation provides more context and helpful references.
 	Remediation string
+	// API is the unsuccessful API response that caused the error (if any).
+	API *APIResponse
+	// Code identifies the error in the catalog (if it's documented there).
+	Code Code
 }
 
 // Unwrap returns the inner error.
@@ -33,135 +42,243 @@ func (re RemediationError) Error() string {
 }
 
 // Print the error to the io.Writer for human consumption. If a prefix is
-// provided, it will be written without modification. The inner error is always
-// printed via text.Output with an "Error: " prefix and a "." suffix. If a
-// remediation is provided, it's printed via text.Output.
+// provided, it will be written without modification. Any context frames follow
+// as an indented "while ..." stack. The inner error is always
+// printed via text.Output with an "Error: " prefix and a "." suffix. If the
+// error was caused by an API response, the request ID (if the API returned one)
+// and endpoint are printed. If a remediation is provided, it's printed via
+// text.Output. If the error has a Code, it's followed by the command that
+// explains it.
+//
+// The output is written using text.LineDiscipline so it never starts mid-line
+// or contains consecutive blank lines.
 func (re RemediationError) Print(w io.Writer) {
+	w = text.LineDiscipline(w)
 	if re.Prefix != "" {
 		fmt.Fprintf(w, "%s\n\n", strings.TrimRight(re.Prefix, "\r\n"))
 	}
+	if frames := uniqueFrames(re.Context); len(frames) > 0 {
+		for i, f := range frames {
+			fmt.Fprintf(w, "%s%s\n", strings.Repeat("  ", i), f)
+		}
+		fmt.Fprintln(w)
+	}
 	if re.Inner != nil {
 		text.Error(w, "%s.\n\n", re.Inner.Error()) // single "\n" ensured by text.Error
 	}
+	if re.API != nil {
+		if re.API.RequestID != "" {
+			fmt.Fprint(w, i18n.Sprintf(requestIDFormat, re.API.RequestID))
+		}
+		fmt.Fprint(w, i18n.Sprintf(endpointFormat, re.API.Endpoint()))
+	}
 	if re.Remediation != "" {
-		fmt.Fprintf(w, "%s\n", strings.TrimRight(re.Remediation, "\r\n"))
+		fmt.Fprintf(w, "%s\n", strings.TrimRight(i18n.Localize(re.Remediation), "\r\n"))
+	}
+	if re.Code != "" {
+		fmt.Fprint(w, i18n.Sprintf(explainFormat, re.Code))
+	}
+}
+
+// PrintJSON writes the error to the io.Writer as a JSON object.
+// Used instead of Print when the user has requested JSON output.
+func (re RemediationError) PrintJSON(w io.Writer) error {
+	v := struct {
+		Error       string   `json:"error"`
+		Context     []string `json:"context,omitempty"`
+		Remediation string   `json:"remediation,omitempty"`
+		Code        Code     `json:"code,omitempty"`
+		*APIResponse
+	}{
+		Error:       re.Error(),
+		Context:     uniqueFrames(re.Context),
+		Remediation: i18n.Localize(re.Remediation),
+		Code:        re.Code,
+		APIResponse: re.API,
 	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// Formats of the lines Print adds to the error.
+var (
+	requestIDFormat = i18n.Register("error.request-id", "Fastly request ID: %s\n")
+	endpointFormat  = i18n.Register("error.endpoint", "Fastly API endpoint: %s\n\n")
+	explainFormat   = i18n.Register("error.explain", "\nFor more information, run `fastly explain %s`.\n")
+)
+
+// remediation registers the English text of a remediation with the i18n
+// package, and returns it, so Print can display it in the selected locale.
+func remediation(id, text string) string {
+	return i18n.Register("remediation."+id, text)
 }
 
 // FormatTemplate represents a generic error message prefix.
-var FormatTemplate = "To fix this error, run the following command:\n\n\t$ %s"
+var FormatTemplate = remediation("format-template", "To fix this error, run the following command:\n\n\t$ %s")
 
 // AuthRemediation suggests checking the provided --token.
-var AuthRemediation = fmt.Sprintf(strings.Join([]string{
+var AuthRemediation = remediation("auth", fmt.Sprintf(strings.Join([]string{
 	"This error may be caused by a missing, incorrect, or expired Fastly API token.",
 	"Check that you're supplying a valid token, either via --token,",
 	"through the environment variable %s, or through the config file via `fastly profile`.",
 	"Verify that the token is still valid via `fastly whoami`.",
-}, " "), env.APIToken)
+}, " "), env.APIToken))
 
 // NetworkRemediation suggests, somewhat unhelpfully, to try again later.
-var NetworkRemediation = strings.Join([]string{
+var NetworkRemediation = remediation("network", strings.Join([]string{
 	"This error may be caused by transient network issues.",
 	"Please verify your network connection and DNS configuration, and try again.",
-}, " ")
+}, " "))
 
 // HostRemediation suggests there might be an issue with the local host.
-var HostRemediation = strings.Join([]string{
+var HostRemediation = remediation("host", strings.Join([]string{
 	"This error may be caused by a problem with your host environment, for example",
 	"too-restrictive file permissions, files that already exist, or a full disk.",
-}, " ")
+}, " "))
 
 // BugRemediation suggests filing a bug on the GitHub repo. It's good to include
 // as the final suggested remediation in many errors.
-var BugRemediation = strings.Join([]string{
-	"If you believe this error is the result of a bug, please file an issue:",
+var BugRemediation = remediation("bug", strings.Join([]string{
+	"If you believe this error is the result of a bug, please file an issue (attaching the archive written by `fastly support-bundle`):",
 	"https://github.com/fastly/cli/issues/new?labels=bug&template=bug_report.md",
-}, " ")
+}, " "))
 
 // ConfigRemediation informs the user that an error with loading the config
 // isn't a breaking error and the CLI can still be used.
-var ConfigRemediation = strings.Join([]string{
+var ConfigRemediation = remediation("config", strings.Join([]string{
 	"There is a fallback version of the configuration provided with the CLI install",
 	"(run `fastly config` to view the config) which enables the CLI to continue to be usable even though the config couldn't be updated.",
-}, " ")
+}, " "))
 
 // ServiceIDRemediation suggests provide a service ID via --service-id flag or
 // fastly.toml.
-var ServiceIDRemediation = strings.Join([]string{
+var ServiceIDRemediation = remediation("service-id", strings.Join([]string{
 	"Please provide one via the --service-id or --service-name flag, or by setting the FASTLY_SERVICE_ID environment variable, or within your fastly.toml",
-}, " ")
+}, " "))
 
 // CustomerIDRemediation suggests provide a customer ID via --customer-id flag
 // or via environment variable.
-var CustomerIDRemediation = strings.Join([]string{
+var CustomerIDRemediation = remediation("customer-id", strings.Join([]string{
 	"Please provide one via the --customer-id flag, or by setting the FASTLY_CUSTOMER_ID environment variable",
-}, " ")
+}, " "))
 
 // ExistingDirRemediation suggests moving to another directory and retrying.
-var ExistingDirRemediation = strings.Join([]string{
+var ExistingDirRemediation = remediation("existing-dir", strings.Join([]string{
 	"Please create a new directory and initialize a new project using:",
 	"`fastly compute init`.",
-}, " ")
+}, " "))
+
+// InitConflictRemediation suggests how to initialize a project whose directory
+// has files the package template would overwrite.
+var InitConflictRemediation = remediation("init-conflict", strings.Join([]string{
+	"Nothing was written. Use --on-conflict skip to keep the existing files and only create the others,",
+	"or --on-conflict overwrite to replace them with the package template's.",
+	"Alternatively, initialize the project in an empty directory (see --directory).",
+}, " "))
 
 // AutoCloneRemediation suggests provide an --autoclone flag.
-var AutoCloneRemediation = strings.Join([]string{
+var AutoCloneRemediation = remediation("auto-clone", strings.Join([]string{
 	"Repeat the command with the --autoclone flag to allow the version to be cloned",
-}, " ")
+}, " "))
 
 // IDRemediation suggests an ID via --id flag should be provided.
-var IDRemediation = strings.Join([]string{
+var IDRemediation = remediation("id", strings.Join([]string{
 	"Please provide one via the --id flag",
-}, " ")
+}, " "))
 
 // PackageSizeRemediation suggests checking the resources documentation for the
 // current package size limit.
-var PackageSizeRemediation = strings.Join([]string{
+var PackageSizeRemediation = remediation("package-size", strings.Join([]string{
 	"Please check our Compute resource limits:",
 	"https://developer.fastly.com/learning/compute/#limitations-and-constraints",
-}, " ")
+}, " "))
 
 // UnrecognisedManifestVersionRemediation suggests steps to resolve an issue
 // where the project contains a manifest_version that is larger than what the
 // current CLI version supports.
-var UnrecognisedManifestVersionRemediation = strings.Join([]string{
+var UnrecognisedManifestVersionRemediation = remediation("unrecognised-manifest-version", strings.Join([]string{
 	"Please try updating the installed CLI version using: `fastly update`.",
 	"See also https://developer.fastly.com/reference/fastly-toml/ to check your fastly.toml manifest is up-to-date with the latest data model.",
 	BugRemediation,
-}, " ")
+}, " "))
+
+// NewerConfigRemediation suggests steps to resolve an issue where the CLI
+// config file has a config_version that is larger than what the current CLI
+// version supports.
+var NewerConfigRemediation = remediation("newer-config", "Run `fastly update` to upgrade your current CLI version, or `fastly config --reset` to replace the configuration file with one this version supports.")
 
 // ComputeInitRemediation suggests re-running `compute init` to resolve
 // manifest issue.
-var ComputeInitRemediation = strings.Join([]string{
+var ComputeInitRemediation = remediation("compute-init", strings.Join([]string{
 	"Run `fastly compute init` to ensure a correctly configured manifest.",
 	"See more at https://developer.fastly.com/reference/fastly-toml/",
-}, " ")
+}, " "))
 
 // ComputeServeRemediation suggests re-running `compute serve` with one of the
 // incompatible flags removed.
-var ComputeServeRemediation = strings.Join([]string{
+var ComputeServeRemediation = remediation("compute-serve", strings.Join([]string{
 	"The --watch flag enables hot reloading of your project to support a faster feedback loop during local development, and subsequently conflicts with the --skip-build flag which avoids rebuilding your project altogether.",
 	"Remove one of the flags based on the outcome you require.",
-}, " ")
+}, " "))
 
 // ComputeBuildRemediation suggests configuring a `[scripts.build]` setting in
 // the fastly.toml manifest.
-var ComputeBuildRemediation = strings.Join([]string{
+var ComputeBuildRemediation = remediation("compute-build", strings.Join([]string{
 	"Add a [scripts] section with `build = \"%s\"`.",
 	"See more at https://developer.fastly.com/reference/fastly-toml/",
-}, " ")
+}, " "))
 
 // ComputeTrialRemediation suggests contacting customer manager to enable the
 // free trial feature flag.
-var ComputeTrialRemediation = "For more help with this error see fastly.help/cli/ecp-feature"
+var ComputeTrialRemediation = remediation("compute-trial", "For more help with this error see fastly.help/cli/ecp-feature")
+
+// ClockSkewRemediation suggests the local clock is wrong, which can cause TLS
+// certificates and API tokens to be rejected.
+var ClockSkewRemediation = remediation("clock-skew", strings.Join([]string{
+	"Your system clock appears to be out of sync with the Fastly API, which can cause TLS and authentication failures.",
+	"Sync your clock (e.g. enable automatic date and time) and try again.",
+}, " "))
+
+// DeprecatedRemediation suggests migrating from the deprecated items that
+// strict mode rejected.
+var DeprecatedRemediation = remediation("deprecated", "Replace the deprecated items listed above as described, or run the command without --strict (and with "+env.Strict+" unset) to only warn about them.")
+
+// MaxTimeRemediation suggests allowing the command longer than the --max-time
+// deadline.
+var MaxTimeRemediation = remediation("max-time", "Increase the --max-time flag (or remove it) to give the command longer to complete.")
+
+// TokenScopeRemediation suggests using an API token with the scope a command
+// needs.
+var TokenScopeRemediation = remediation("token-scope", strings.Join([]string{
+	"Use an API token with the scope named above, via --token or a profile (see `fastly profile update`).",
+	"A token can be created with `fastly auth-token create --scope <SCOPE>` or at https://manage.fastly.com/account/personal/tokens.",
+	"If the token's scope can't be looked up, run the command with --skip-scope-check.",
+}, " "))
+
+// ValidationRemediation suggests correcting the values the API rejected.
+var ValidationRemediation = remediation("validation", "Correct the values listed above and run the command again. Run the command with --verbose to display the API's response.")
+
+// DictionaryLimitsRemediation explains the edge dictionary limits, which
+// accounts may have raised.
+var DictionaryLimitsRemediation = remediation("dictionary-limits", strings.Join([]string{
+	"Edge dictionary items are limited in number and size, see https://docs.fastly.com/en/guides/resource-limits.",
+	"No items were written. Fix the listed items and try again, or if the limits for your account have been raised set --ignore-limits.",
+}, " "))
+
+// FieldRemediation suggests inspecting the JSON output when --field or
+// --format refers to a field it doesn't have.
+var FieldRemediation = remediation("field", "Fields are selected from the command's JSON output, run it with --json to see them. Nested fields are separated by dots and array elements are selected by index (e.g. --field versions[0].number).")
 
 // ProfileRemediation suggests no profiles exist.
-var ProfileRemediation = "Run `fastly profile create <NAME>` to create a profile, or `fastly profile list` to view available profiles (at least one profile should be set as 'default')."
+var ProfileRemediation = remediation("profile", "Run `fastly profile create <NAME>` to create a profile, or `fastly profile list` to view available profiles (at least one profile should be set as 'default').")
 
 // InvalidStaticConfigRemediation indicates an unexpected error occurred when
 // deserialising the CLI's internal configuration.
-var InvalidStaticConfigRemediation = strings.Join([]string{
+var InvalidStaticConfigRemediation = remediation("invalid-static-config", strings.Join([]string{
 	"The Fastly CLI attempted to parse an internal configuration file but failed.",
 	"Run `fastly update` to upgrade your current CLI version.",
 	"If this does not resolve the issue, then please file an issue:",
 	"https://github.com/fastly/cli/issues/new?labels=bug&template=bug_report.md",
-}, " ")
+}, " "))
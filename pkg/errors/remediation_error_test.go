@@ -0,0 +1,71 @@
+package errors_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/i18n"
+)
+
+// TestRemediationsRegistered validates every remediation has a message ID, so
+// it can be translated (see i18n.Register).
+func TestRemediationsRegistered(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "remediation_error.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if !strings.HasSuffix(name.Name, "Remediation") {
+					continue
+				}
+				names = append(names, name.Name)
+				if !isRemediationCall(vs.Values[i]) {
+					t.Errorf("%s: want the value to be registered with remediation()", name.Name)
+				}
+			}
+		}
+	}
+	if len(names) == 0 {
+		t.Fatal("no remediations were found")
+	}
+
+	for _, e := range errors.Catalog() {
+		if i18n.ID(e.Remediation) == "" {
+			t.Errorf("%s: the remediation has no message ID", e.Code)
+		}
+	}
+	for _, name := range []string{
+		errors.RemediationClockSkew,
+		errors.RemediationManifestVersion,
+		errors.RemediationPackageSize,
+		errors.RemediationServiceID,
+	} {
+		// The raw text is returned when the data is missing.
+		if i18n.ID(errors.RenderRemediation(name, nil)) == "" {
+			t.Errorf("%s: the fallback remediation has no message ID", name)
+		}
+	}
+}
+
+// isRemediationCall reports whether the expression is a remediation() call.
+func isRemediationCall(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	fn, ok := call.Fun.(*ast.Ident)
+	return ok && fn.Name == "remediation"
+}
@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/fastly/cli/pkg/i18n"
+)
+
+// Names of the remediations that interpolate runtime values (see
+// RenderRemediation).
+const (
+	RemediationClockSkew       = "clock-skew"
+	RemediationManifestVersion = "manifest-version"
+	RemediationPackageSize     = "package-size"
+	RemediationServiceID       = "service-id"
+)
+
+// remediationTemplate is a remediation with named placeholders (using
+// text/template syntax) and the raw text to use if values are missing.
+type remediationTemplate struct {
+	text     string
+	fallback string
+}
+
+var remediationTemplates = map[string]remediationTemplate{
+	RemediationClockSkew: {
+		text:     remediation("template.clock-skew", "Your system clock is {{.Skew}} {{.Direction}} the Fastly API's clock, which can cause TLS and authentication failures. Sync your clock (e.g. enable automatic date and time) and try again."),
+		fallback: ClockSkewRemediation,
+	},
+	RemediationManifestVersion: {
+		text:     remediation("template.manifest-version", "The manifest ({{.Path}}) has manifest_version {{.Version}} but this version of the CLI supports up to {{.Supported}}. "+UnrecognisedManifestVersionRemediation),
+		fallback: UnrecognisedManifestVersionRemediation,
+	},
+	RemediationPackageSize: {
+		text:     remediation("template.package-size", "The package is {{.Size}} but the limit is {{.Limit}}. "+PackageSizeRemediation),
+		fallback: PackageSizeRemediation,
+	},
+	RemediationServiceID: {
+		text:     remediation("template.service-id", ServiceIDRemediation+" (no service_id was found in {{.ManifestPath}})"),
+		fallback: ServiceIDRemediation,
+	},
+}
+
+// RenderRemediation returns the named remediation with the data values
+// interpolated, e.g. {{.Size}} is replaced by data["Size"].
+//
+// The raw remediation text is returned if a placeholder has no value in data.
+// The remediation is rendered in the selected locale (see i18n.SetLocale).
+// An unknown name returns an empty string.
+func RenderRemediation(name string, data map[string]any) string {
+	rt, ok := remediationTemplates[name]
+	if !ok {
+		return ""
+	}
+
+	// NOTE: A nil value is treated the same as a missing key.
+	values := make(map[string]any, len(data))
+	for k, v := range data {
+		if v != nil {
+			values[k] = v
+		}
+	}
+
+	// NOTE: A translation that fails to render (e.g. it has a typo in a
+	// placeholder) falls back to the English text.
+	for _, text := range []string{i18n.Localize(rt.text), rt.text} {
+		if s, ok := render(name, text, values); ok {
+			return s
+		}
+	}
+	return i18n.Localize(rt.fallback)
+}
+
+// render executes the template text with the values.
+func render(name, text string, values map[string]any) (string, bool) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", false
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, values); err != nil {
+		return "", false
+	}
+	return b.String(), true
+}
@@ -0,0 +1,63 @@
+package errors_test
+
+import (
+	"testing"
+
+	"github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestRenderRemediation(t *testing.T) {
+	scenarios := []struct {
+		name        string
+		remediation string
+		data        map[string]any
+		want        string
+	}{
+		{
+			name:        "interpolated",
+			remediation: errors.RemediationPackageSize,
+			data:        map[string]any{"Size": "120.0 MB", "Limit": "100.0 MB"},
+			want:        "The package is 120.0 MB but the limit is 100.0 MB. " + errors.PackageSizeRemediation,
+		},
+		{
+			name:        "interpolated with extra data",
+			remediation: errors.RemediationServiceID,
+			data:        map[string]any{"ManifestPath": "/app/fastly.toml", "Unused": true},
+			want:        errors.ServiceIDRemediation + " (no service_id was found in /app/fastly.toml)",
+		},
+		{
+			name:        "interpolated integers",
+			remediation: errors.RemediationManifestVersion,
+			data:        map[string]any{"Path": "fastly.toml", "Version": 99, "Supported": 3},
+			want:        "The manifest (fastly.toml) has manifest_version 99 but this version of the CLI supports up to 3. " + errors.UnrecognisedManifestVersionRemediation,
+		},
+		{
+			name:        "fallback when a value is missing",
+			remediation: errors.RemediationPackageSize,
+			data:        map[string]any{"Size": "120.0 MB"},
+			want:        errors.PackageSizeRemediation,
+		},
+		{
+			name:        "fallback when a value is nil",
+			remediation: errors.RemediationManifestVersion,
+			data:        map[string]any{"Path": "fastly.toml", "Version": nil, "Supported": 3},
+			want:        errors.UnrecognisedManifestVersionRemediation,
+		},
+		{
+			name:        "fallback without data",
+			remediation: errors.RemediationServiceID,
+			want:        errors.ServiceIDRemediation,
+		},
+		{
+			name:        "unknown remediation",
+			remediation: "unknown",
+			data:        map[string]any{"Size": "1 B"},
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			testutil.AssertString(t, testcase.want, errors.RenderRemediation(testcase.remediation, testcase.data))
+		})
+	}
+}
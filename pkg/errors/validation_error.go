@@ -0,0 +1,157 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+)
+
+// FieldError is a message the Fastly API returned about the value of a field.
+type FieldError struct {
+	// Field is the name of the API field (e.g. ssl_cert_hostname).
+	Field string
+	// Flag is the name of the flag that supplied the field, without the
+	// leading dashes (empty if it isn't known).
+	Flag string
+	// Message describes what's wrong with the value (e.g. "can't be blank").
+	Message string
+}
+
+// String returns the message, naming the flag that supplied the value if it's
+// known, e.g. "invalid value for --name: can't be blank".
+func (f FieldError) String() string {
+	name := fmt.Sprintf("field '%s'", f.Field)
+	if f.Flag != "" {
+		name = "--" + f.Flag
+	}
+	return fmt.Sprintf("invalid value for %s: %s", name, f.Message)
+}
+
+// ValidationError is a 422 Unprocessable Entity response from the Fastly API
+// whose body lists the fields that were rejected.
+type ValidationError struct {
+	// Body is the raw response body.
+	Body string
+	// Fields are the rejected fields, ordered by name.
+	Fields []FieldError
+}
+
+// Error implements the error interface.
+func (e ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		lines = append(lines, f.String())
+	}
+	return fmt.Sprintf("the Fastly API returned %d %s:\n\n  %s", http.StatusUnprocessableEntity, http.StatusText(http.StatusUnprocessableEntity), strings.Join(lines, "\n  "))
+}
+
+// WithFlags returns the error with the flag that supplied each field, as
+// returned by flag (which returns an empty string if the field wasn't supplied
+// by a flag).
+func (e ValidationError) WithFlags(flag func(field string) string) ValidationError {
+	fields := make([]FieldError, len(e.Fields))
+	for i, f := range e.Fields {
+		f.Flag = flag(f.Field)
+		fields[i] = f
+	}
+	e.Fields = fields
+	return e
+}
+
+// ParseValidationError extracts the rejected fields of a 422 response from
+// its body. If body is empty, the body the API client failed to decode (if
+// any) is used instead.
+//
+// The API describes the fields in one of two shapes:
+//
+//	{"msg": "...", "errors": {"name": ["can't be blank"]}}
+//	{"errors": [{"detail": "can't be blank", "source": {"pointer": "/data/attributes/name"}}]}
+//
+// A response that isn't a 422, or whose body isn't one of these shapes,
+// returns false.
+func ParseValidationError(httpError *fastly.HTTPError, body string) (ValidationError, bool) {
+	if httpError.StatusCode != http.StatusUnprocessableEntity {
+		return ValidationError{}, false
+	}
+	if body == "" && len(httpError.Errors) == 1 && httpError.Errors[0].Title == "Undefined error" {
+		body = httpError.Errors[0].Detail
+	}
+
+	var v struct {
+		Errors json.RawMessage `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(body), &v); err != nil || len(v.Errors) == 0 {
+		return ValidationError{}, false
+	}
+	fields := fieldsByName(v.Errors)
+	if len(fields) == 0 {
+		fields = fieldsBySource(v.Errors)
+	}
+	if len(fields) == 0 {
+		return ValidationError{}, false
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].Field < fields[j].Field
+	})
+	return ValidationError{Body: body, Fields: fields}, true
+}
+
+// fieldsByName returns the messages of an object keyed by field name, whose
+// values are a message or a list of them.
+func fieldsByName(data json.RawMessage) []FieldError {
+	var byName map[string]any
+	if err := json.Unmarshal(data, &byName); err != nil {
+		return nil
+	}
+	var fields []FieldError
+	for name, v := range byName {
+		switch v := v.(type) {
+		case string:
+			fields = append(fields, FieldError{Field: name, Message: v})
+		case []any:
+			for _, m := range v {
+				if m, ok := m.(string); ok {
+					fields = append(fields, FieldError{Field: name, Message: m})
+				}
+			}
+		}
+	}
+	return fields
+}
+
+// fieldsBySource returns the messages of a list of JSON:API error objects
+// whose source identifies the field, ignoring errors about the request as a
+// whole.
+func fieldsBySource(data json.RawMessage) []FieldError {
+	var objects []struct {
+		Detail string `json:"detail"`
+		Source struct {
+			Parameter string `json:"parameter"`
+			Pointer   string `json:"pointer"`
+		} `json:"source"`
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return nil
+	}
+	var fields []FieldError
+	for _, o := range objects {
+		name := o.Source.Parameter
+		if name == "" && strings.HasPrefix(o.Source.Pointer, "/data/attributes/") {
+			name = strings.TrimPrefix(o.Source.Pointer, "/data/attributes/")
+		}
+		msg := o.Detail
+		if msg == "" {
+			msg = o.Title
+		}
+		if name == "" || msg == "" {
+			continue
+		}
+		fields = append(fields, FieldError{Field: name, Message: msg})
+	}
+	return fields
+}
@@ -0,0 +1,4 @@
+// Package events emits machine-readable progress events (e.g. build started,
+// package uploaded) for editors and other tools driving the CLI, without them
+// having to parse the human-readable output.
+package events
@@ -0,0 +1,199 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event types. These are part of the schema consumed by external tools, and so
+// must not be renamed.
+const (
+	BuildStarted      = "build.started"
+	BuildFinished     = "build.finished"
+	BuildFailed       = "build.failed"
+	DeployStarted     = "deploy.started"
+	UploadStarted     = "deploy.upload.started"
+	UploadFinished    = "deploy.upload.finished"
+	ActivationStarted = "deploy.activation.started"
+	ActivationDone    = "deploy.activation.finished"
+	DeployFinished    = "deploy.finished"
+	DeployFailed      = "deploy.failed"
+	ServeStarted      = "serve.started"
+	ServeRestarted    = "serve.restarted"
+	ServeStopped      = "serve.stopped"
+)
+
+// BufferSize is the number of events queued for a slow consumer. Events
+// emitted while the queue is full are dropped.
+const BufferSize = 256
+
+// CloseTimeout is how long Close waits for queued events to be written.
+//
+// NOTE: This is a variable so the test suite can use a much smaller value.
+var CloseTimeout = 2 * time.Second
+
+// Redacted replaces the value of a field that could contain a secret.
+const Redacted = "REDACTED"
+
+// sensitiveFields are substrings of field names whose values are redacted.
+var sensitiveFields = []string{"authorization", "credential", "password", "secret", "token"}
+
+// Fields are the event specific data.
+type Fields map[string]any
+
+// Event is a single line of the event stream.
+type Event struct {
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Fields    Fields    `json:"fields"`
+}
+
+// Emitter writes newline-delimited JSON events. Emit never blocks: events are
+// queued and written in the background, and dropped if the consumer can't keep
+// up or has gone away. A nil Emitter is a no-op.
+type Emitter struct {
+	mu      sync.Mutex
+	closed  bool
+	dropped int
+	done    chan struct{}
+	now     func() time.Time
+	queue   chan Event
+	w       io.Writer
+}
+
+// New returns an Emitter writing to w, which is closed by Close if it's an
+// io.Closer. It uses now to read the time (time.Now if nil).
+func New(w io.Writer, now func() time.Time) *Emitter {
+	if now == nil {
+		now = time.Now
+	}
+	e := &Emitter{
+		done:  make(chan struct{}),
+		now:   now,
+		queue: make(chan Event, BufferSize),
+		w:     w,
+	}
+	go e.write()
+	return e
+}
+
+// Open returns an Emitter for target, which is either a file descriptor
+// inherited from the parent process (e.g. 3) or the path of a unix socket.
+func Open(target string) (*Emitter, error) {
+	if fd, err := strconv.Atoi(target); err == nil {
+		if fd < 3 {
+			return nil, fmt.Errorf("invalid event file descriptor %d: stdin, stdout and stderr are reserved", fd)
+		}
+		// #nosec G115 (CWE-190) the descriptor is validated as positive.
+		f := os.NewFile(uintptr(fd), "events")
+		if f == nil {
+			return nil, fmt.Errorf("invalid event file descriptor %d", fd)
+		}
+		return New(f, nil), nil
+	}
+	conn, err := net.DialTimeout("unix", strings.TrimPrefix(target, "unix:"), CloseTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to event socket: %w", err)
+	}
+	return New(conn, nil), nil
+}
+
+// Emit queues an event. Field values whose name suggests a secret (e.g. token)
+// are redacted.
+func (e *Emitter) Emit(eventType string, fields Fields) {
+	if e == nil {
+		return
+	}
+	ev := Event{Type: eventType, Timestamp: e.now().UTC(), Fields: redact(fields)}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return
+	}
+	select {
+	case e.queue <- ev:
+	default:
+		e.dropped++
+	}
+}
+
+// Dropped returns the number of events that were discarded because the queue
+// was full.
+func (e *Emitter) Dropped() int {
+	if e == nil {
+		return 0
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.dropped
+}
+
+// Close writes any queued events (waiting at most CloseTimeout) and closes the
+// underlying writer. Calling Close more than once has no effect.
+func (e *Emitter) Close() error {
+	if e == nil {
+		return nil
+	}
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return nil
+	}
+	e.closed = true
+	close(e.queue)
+	e.mu.Unlock()
+
+	select {
+	case <-e.done:
+	case <-time.After(CloseTimeout):
+	}
+	if c, ok := e.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// write drains the queue. Once a write fails (e.g. the consumer closed its end
+// of the pipe) the remaining events are discarded.
+func (e *Emitter) write() {
+	defer close(e.done)
+	var failed bool
+	for ev := range e.queue {
+		if failed {
+			continue
+		}
+		b, err := json.Marshal(ev)
+		if err != nil {
+			continue
+		}
+		if _, err := e.w.Write(append(b, '\n')); err != nil {
+			failed = true
+		}
+	}
+}
+
+func redact(fields Fields) Fields {
+	if fields == nil {
+		return Fields{}
+	}
+	out := make(Fields, len(fields))
+	for k, v := range fields {
+		out[k] = v
+		name := strings.ToLower(k)
+		for _, s := range sensitiveFields {
+			if strings.Contains(name, s) {
+				out[k] = Redacted
+				break
+			}
+		}
+	}
+	return out
+}
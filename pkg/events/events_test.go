@@ -0,0 +1,162 @@
+package events_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fastly/cli/pkg/events"
+)
+
+var now = func() time.Time { return time.Date(2021, time.June, 15, 23, 0, 0, 0, time.UTC) }
+
+func TestEmitter(t *testing.T) {
+	var buf bytes.Buffer
+	e := events.New(&buf, now)
+	e.Emit(events.BuildStarted, nil)
+	e.Emit(events.UploadStarted, events.Fields{"size_bytes": 45, "api_token": "abc", "Secret-Key": "def"})
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	e.Emit(events.BuildFinished, nil) // ignored once closed
+
+	want := `{"type":"build.started","timestamp":"2021-06-15T23:00:00Z","fields":{}}
+{"type":"deploy.upload.started","timestamp":"2021-06-15T23:00:00Z","fields":{"Secret-Key":"REDACTED","api_token":"REDACTED","size_bytes":45}}
+`
+	if buf.String() != want {
+		t.Errorf("want:\n%s\nhave:\n%s", want, buf.String())
+	}
+}
+
+func TestEmitterNil(t *testing.T) {
+	var e *events.Emitter
+	e.Emit(events.BuildStarted, nil)
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// blockingWriter never completes a write until it's closed.
+type blockingWriter struct {
+	once   sync.Once
+	closed chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.closed
+	return 0, io.ErrClosedPipe
+}
+
+func (w *blockingWriter) Close() error {
+	w.once.Do(func() { close(w.closed) })
+	return nil
+}
+
+func TestEmitterSlowConsumer(t *testing.T) {
+	defer func(d time.Duration) { events.CloseTimeout = d }(events.CloseTimeout)
+	events.CloseTimeout = 10 * time.Millisecond
+
+	w := &blockingWriter{closed: make(chan struct{})}
+	e := events.New(w, now)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < events.BufferSize*2; i++ {
+			e.Emit(events.UploadStarted, events.Fields{"size_bytes": i})
+		}
+		_ = e.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("emitting events blocked on a slow consumer")
+	}
+	if e.Dropped() == 0 {
+		t.Error("expected events to be dropped")
+	}
+}
+
+func TestEmitterClosedConsumer(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = r.Close()
+
+	e := events.New(w, now)
+	for i := 0; i < 10; i++ {
+		e.Emit(events.BuildStarted, nil)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	t.Run("unix socket", func(t *testing.T) {
+		dir, err := os.MkdirTemp("", "events")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "events.sock")
+		l, err := net.Listen("unix", path)
+		if err != nil {
+			t.Skipf("unix sockets unavailable: %v", err)
+		}
+		defer l.Close()
+
+		lines := make(chan string, 1)
+		go func() {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			s := bufio.NewScanner(conn)
+			if s.Scan() {
+				lines <- s.Text()
+			}
+		}()
+
+		e, err := events.Open("unix:" + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e.Emit(events.ServeStarted, events.Fields{"addr": "127.0.0.1:7676"})
+		defer e.Close()
+
+		select {
+		case line := <-lines:
+			var ev events.Event
+			if err := json.Unmarshal([]byte(line), &ev); err != nil {
+				t.Fatal(err)
+			}
+			if ev.Type != events.ServeStarted || ev.Fields["addr"] != "127.0.0.1:7676" {
+				t.Errorf("unexpected event: %s", line)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("no event received")
+		}
+	})
+
+	t.Run("reserved file descriptor", func(t *testing.T) {
+		if _, err := events.Open("1"); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+
+	t.Run("missing socket", func(t *testing.T) {
+		if _, err := events.Open(filepath.Join(t.TempDir(), "missing.sock")); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
@@ -25,8 +25,14 @@ const divider = "---------------------------------------------------------------
 type Streaming struct {
 	// Args are the command positional arguments.
 	Args []string
+	// Capture, when set, is also written the command's output (without the
+	// dividers written around it).
+	Capture io.Writer
 	// Command is the command to be executed.
 	Command string
+	// Context, when done, kills the process group and waits for it to exit.
+	// e.g. the user interrupted the CLI with Ctrl-C.
+	Context context.Context
 	// Env is the environment variables to set.
 	Env []string
 	// ForceOutput ensures output is displayed (default: only display on error).
@@ -54,7 +60,20 @@ func (s *Streaming) MonitorSignals() {
 }
 
 // MonitorSignalsAsync configures the signal notifications.
+//
+// When a Context is set, signals aren't listened for directly as Exec() kills
+// the process once the Context is done. A message sent to SignalCh still kills
+// the process.
 func (s *Streaming) MonitorSignalsAsync() {
+	if s.Context != nil {
+		select {
+		case <-s.Context.Done():
+		case <-s.SignalCh:
+			_ = s.Signal(os.Kill)
+		}
+		return
+	}
+
 	signals := []os.Signal{
 		syscall.SIGINT,
 		syscall.SIGTERM,
@@ -78,26 +97,33 @@ func (s *Streaming) MonitorSignalsAsync() {
 // cleanly or returns an error.
 func (s *Streaming) Exec() error {
 	// Construct the command with given arguments and environment.
-	var cmd *exec.Cmd
+	ctx := s.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	if s.Timeout > 0 {
-		ctx, cancel := context.WithTimeout(context.Background(), s.Timeout)
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
 		defer cancel()
-		// gosec flagged this:
-		// G204 (CWE-78): Subprocess launched with variable
-		// Disabling as the variables come from trusted sources.
-		// #nosec
-		// nosemgrep
-		cmd = exec.CommandContext(ctx, s.Command, s.Args...)
-	} else {
-		// gosec flagged this:
-		// G204 (CWE-78): Subprocess launched with variable
-		// Disabling as the variables come from trusted sources.
-		// #nosec
-		// nosemgrep
-		cmd = exec.Command(s.Command, s.Args...)
 	}
+	// gosec flagged this:
+	// G204 (CWE-78): Subprocess launched with variable
+	// Disabling as the variables come from trusted sources.
+	// #nosec
+	// nosemgrep
+	cmd := exec.CommandContext(ctx, s.Command, s.Args...)
 	cmd.Env = append(os.Environ(), s.Env...)
 
+	// The process is placed into its own process group so that, when the
+	// Context is done, any processes it spawned are killed along with it.
+	// cmd.Wait() then waits for the process to exit.
+	if s.Context != nil {
+		setProcessGroup(cmd)
+		cmd.Cancel = func() error {
+			return killProcessGroup(cmd.Process)
+		}
+	}
+
 	// We store all output in a buffer to hide it unless there was an error.
 	var buf threadsafe.Buffer
 	var output io.Writer
@@ -118,6 +144,10 @@ func (s *Streaming) Exec() error {
 
 	cmd.Stdout = output
 	cmd.Stderr = output
+	if s.Capture != nil {
+		cmd.Stdout = io.MultiWriter(output, s.Capture)
+		cmd.Stderr = cmd.Stdout
+	}
 
 	if err := cmd.Start(); err != nil {
 		text.Output(output, divider)
@@ -161,6 +191,9 @@ func (s *Streaming) Exec() error {
 // Signal enables spawned subprocess to accept given signal.
 func (s *Streaming) Signal(sig os.Signal) error {
 	if s.Process != nil {
+		if s.Context != nil && sig == os.Kill {
+			return killProcessGroup(s.Process)
+		}
 		err := s.Process.Signal(sig)
 		if err != nil {
 			return err
@@ -173,8 +206,13 @@ func (s *Streaming) Signal(sig os.Signal) error {
 type CommandOpts struct {
 	// Args are the command positional arguments.
 	Args []string
+	// Capture, when set, is also written the command's output (without the
+	// dividers written around it).
+	Capture io.Writer
 	// Command is the command to be executed.
 	Command string
+	// Context, when done, kills the command.
+	Context context.Context
 	// Env is the environment variables to set.
 	Env []string
 	// ErrLog provides an interface for recording errors to disk.
@@ -196,6 +234,7 @@ type CommandOpts struct {
 func Command(opts CommandOpts) error {
 	s := Streaming{
 		Command:        opts.Command,
+		Context:        opts.Context,
 		Args:           opts.Args,
 		Env:            opts.Env,
 		Output:         opts.Output,
@@ -215,3 +254,21 @@ func Command(opts CommandOpts) error {
 	}
 	return nil
 }
+
+// Interactive runs a command attached to the user's terminal (e.g. a text
+// editor) and waits for it to exit.
+func Interactive(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no command to run")
+	}
+	// gosec flagged this:
+	// G204 (CWE-78): Subprocess launched with variable
+	// Disabling as the command is chosen by the user (e.g. $EDITOR).
+	// #nosec
+	// nosemgrep
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
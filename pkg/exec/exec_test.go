@@ -0,0 +1,46 @@
+package exec_test
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	fstexec "github.com/fastly/cli/pkg/exec"
+	"github.com/fastly/cli/pkg/threadsafe"
+)
+
+func TestStreamingContextCancelled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var out threadsafe.Buffer
+	s := &fstexec.Streaming{
+		// The shell spawns a child process, which must also be killed.
+		Args:        []string{"-c", "sleep 30 & wait"},
+		Command:     "sh",
+		Context:     ctx,
+		ForceOutput: true,
+		Output:      &out,
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Exec()
+	}()
+	time.Sleep(200 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected an error from the killed process")
+		}
+	case <-time.After(10 * time.Second):
+		// NOTE: If only the shell was killed, the orphaned sleep process keeps
+		// the output pipe open and so Exec() doesn't return.
+		t.Fatal("process group wasn't killed when the context was cancelled")
+	}
+}
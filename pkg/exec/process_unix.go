@@ -0,0 +1,22 @@
+//go:build !windows
+
+package exec
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup configures the command to run in a new process group.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killProcessGroup kills the process and every process in its group.
+func killProcessGroup(p *os.Process) error {
+	if err := syscall.Kill(-p.Pid, syscall.SIGKILL); err != nil {
+		return p.Kill()
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package exec
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup is a no-op as Windows doesn't support process groups in the
+// same way as Unix systems.
+func setProcessGroup(_ *exec.Cmd) {}
+
+// killProcessGroup kills the process.
+func killProcessGroup(p *os.Process) error {
+	return p.Kill()
+}
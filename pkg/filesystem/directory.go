@@ -100,7 +100,7 @@ func MakeDirectoryIfNotExists(path string) error {
 	case err == nil && !fi.IsDir():
 		return fmt.Errorf("%s already exists as a regular file", path)
 	case errors.Is(err, fs.ErrNotExist):
-		return os.MkdirAll(path, 0o750)
+		return MkdirAll(path, 0o750)
 	case err != nil:
 		return err
 	}
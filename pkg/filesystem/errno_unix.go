@@ -0,0 +1,24 @@
+//go:build !windows
+
+package filesystem
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isDiskFull reports whether err was caused by a full disk (or quota).
+func isDiskFull(err error) bool {
+	return errors.Is(err, syscall.ENOSPC) || errors.Is(err, syscall.EDQUOT)
+}
+
+// isReadOnly reports whether err was caused by a read-only filesystem.
+func isReadOnly(err error) bool {
+	return errors.Is(err, syscall.EROFS)
+}
+
+// isSharingViolation reports whether err was caused by another process
+// holding the file open. Unix systems don't have mandatory file locking.
+func isSharingViolation(_ error) bool {
+	return false
+}
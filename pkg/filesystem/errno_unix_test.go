@@ -0,0 +1,36 @@
+//go:build !windows
+
+package filesystem_test
+
+import (
+	"io/fs"
+	"syscall"
+	"testing"
+
+	"github.com/fastly/cli/pkg/filesystem"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestWrapErrno(t *testing.T) {
+	for _, testcase := range []struct {
+		name            string
+		errno           syscall.Errno
+		wantRemediation string
+	}{
+		{name: "ENOSPC", errno: syscall.ENOSPC, wantRemediation: "The disk is full"},
+		{name: "EDQUOT", errno: syscall.EDQUOT, wantRemediation: "The disk is full"},
+		{name: "EROFS", errno: syscall.EROFS, wantRemediation: "The filesystem is read-only"},
+		{name: "EACCES", errno: syscall.EACCES, wantRemediation: "Permission was denied"},
+		{name: "EPERM", errno: syscall.EPERM, wantRemediation: "Permission was denied"},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			err := filesystem.Wrap("write config file", "/tmp/config.toml", &fs.PathError{
+				Op:   "write",
+				Path: "/tmp/config.toml",
+				Err:  testcase.errno,
+			})
+			testutil.AssertErrorContains(t, err, "failed to write config file /tmp/config.toml: "+testcase.errno.Error())
+			testutil.AssertRemediationErrorContains(t, err, testcase.wantRemediation)
+		})
+	}
+}
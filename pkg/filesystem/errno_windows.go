@@ -0,0 +1,34 @@
+//go:build windows
+
+package filesystem
+
+import (
+	"errors"
+	"syscall"
+)
+
+// Windows system error codes not defined by the syscall package.
+// https://learn.microsoft.com/en-us/windows/win32/debug/system-error-codes--0-499-
+const (
+	errorWriteProtect     syscall.Errno = 19
+	errorSharingViolation syscall.Errno = 32
+	errorLockViolation    syscall.Errno = 33
+	errorHandleDiskFull   syscall.Errno = 39
+	errorDiskFull         syscall.Errno = 112
+)
+
+// isDiskFull reports whether err was caused by a full disk.
+func isDiskFull(err error) bool {
+	return errors.Is(err, errorDiskFull) || errors.Is(err, errorHandleDiskFull)
+}
+
+// isReadOnly reports whether err was caused by write-protected media.
+func isReadOnly(err error) bool {
+	return errors.Is(err, errorWriteProtect)
+}
+
+// isSharingViolation reports whether err was caused by another process
+// holding the file open (or locked).
+func isSharingViolation(err error) bool {
+	return errors.Is(err, errorSharingViolation) || errors.Is(err, errorLockViolation)
+}
@@ -0,0 +1,35 @@
+//go:build windows
+
+package filesystem_test
+
+import (
+	"io/fs"
+	"syscall"
+	"testing"
+
+	"github.com/fastly/cli/pkg/filesystem"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestWrapErrno(t *testing.T) {
+	for _, testcase := range []struct {
+		name            string
+		errno           syscall.Errno
+		wantRemediation string
+	}{
+		{name: "ERROR_DISK_FULL", errno: 112, wantRemediation: "The disk is full"},
+		{name: "ERROR_WRITE_PROTECT", errno: 19, wantRemediation: "The filesystem is read-only"},
+		{name: "ERROR_SHARING_VIOLATION", errno: 32, wantRemediation: "in use by another process"},
+		{name: "ERROR_LOCK_VIOLATION", errno: 33, wantRemediation: "in use by another process"},
+		{name: "ERROR_ACCESS_DENIED", errno: syscall.ERROR_ACCESS_DENIED, wantRemediation: "Permission was denied"},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			err := filesystem.Wrap("rename", `C:\fastly.toml`, &fs.PathError{
+				Op:   "rename",
+				Path: `C:\fastly.toml`,
+				Err:  testcase.errno,
+			})
+			testutil.AssertRemediationErrorContains(t, err, testcase.wantRemediation)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// CreateFile creates (or truncates) the named file.
+// On failure it returns a RemediationError describing the path and cause.
+func CreateFile(path string) (*os.File, error) {
+	// gosec flagged this:
+	// G304 (CWE-22): Potential file inclusion via variable
+	//
+	// Disabling as the caller determines the path.
+	/* #nosec */
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, Wrap("create file", path, err)
+	}
+	return f, nil
+}
+
+// MkdirAll creates a directory, along with any necessary parents.
+// On failure it returns a RemediationError describing the path and cause.
+func MkdirAll(path string, perm fs.FileMode) error {
+	if err := os.MkdirAll(path, perm); err != nil {
+		return Wrap("create directory", path, err)
+	}
+	return nil
+}
+
+// Rename renames (moves) oldpath to newpath.
+// On failure it returns a RemediationError describing both paths and cause.
+func Rename(oldpath, newpath string) error {
+	if err := os.Rename(oldpath, newpath); err != nil {
+		return wrap("rename", fmt.Sprintf("%s to %s", abs(oldpath), abs(newpath)), err)
+	}
+	return nil
+}
+
+// Remove removes the named file or (empty) directory.
+// On failure it returns a RemediationError describing the path and cause.
+func Remove(path string) error {
+	if err := os.Remove(path); err != nil {
+		return Wrap("remove", path, err)
+	}
+	return nil
+}
+
+// Wrap annotates err, returned by the filesystem operation op on path, with
+// the absolute path and a remediation specific to the cause where possible.
+func Wrap(op, path string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return wrap(op, abs(path), err)
+}
+
+// wrap implements Wrap where target is the already resolved subject of op.
+func wrap(op, target string, err error) error {
+	// The *PathError/*LinkError message repeats the (relative) path, which is
+	// noise once we've added the absolute path, so only keep the cause.
+	cause := err
+	var pe *fs.PathError
+	var le *os.LinkError
+	switch {
+	case errors.As(err, &pe):
+		cause = pe.Err
+	case errors.As(err, &le):
+		cause = le.Err
+	}
+
+	remediation := fsterr.HostRemediation
+	if hint := hint(err); hint != "" {
+		remediation = strings.Join([]string{hint, remediation}, " ")
+	}
+
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("failed to %s %s: %w", op, target, cause),
+		Remediation: remediation,
+	}
+}
+
+// hint returns a remediation sentence specific to the cause of err, or an
+// empty string if the cause isn't one we recognise.
+func hint(err error) string {
+	switch {
+	case isDiskFull(err):
+		return "The disk is full: free up some space and try again."
+	case isReadOnly(err):
+		return "The filesystem is read-only: run the command from a writable location."
+	case isSharingViolation(err):
+		return "The file is in use by another process (e.g. an editor, antivirus or a running program): close it and try again."
+	case errors.Is(err, fs.ErrPermission):
+		return "Permission was denied: check the ownership and permissions of the path (and its parent directory)."
+	}
+	return ""
+}
+
+// abs returns the absolute representation of path, or path if it can't be
+// resolved.
+func abs(path string) string {
+	if p, err := filepath.Abs(path); err == nil {
+		return p
+	}
+	return path
+}
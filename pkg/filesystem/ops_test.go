@@ -0,0 +1,102 @@
+package filesystem_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/filesystem"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestWrap(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "fastly.toml")
+
+	for _, testcase := range []struct {
+		name            string
+		err             error
+		wantError       string
+		wantRemediation string
+	}{
+		{
+			name:            "permission denied",
+			err:             &fs.PathError{Op: "open", Path: "fastly.toml", Err: fs.ErrPermission},
+			wantError:       "failed to create file " + path + ": permission denied",
+			wantRemediation: "Permission was denied",
+		},
+		{
+			name:            "unrecognised cause",
+			err:             &fs.PathError{Op: "open", Path: "fastly.toml", Err: fs.ErrNotExist},
+			wantError:       "failed to create file " + path + ": file does not exist",
+			wantRemediation: fsterr.HostRemediation,
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			err := filesystem.Wrap("create file", path, testcase.err)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertRemediationErrorContains(t, err, testcase.wantRemediation)
+			testutil.AssertBool(t, true, errors.Is(err, testcase.err.(*fs.PathError).Err))
+		})
+	}
+}
+
+func TestWrapNil(t *testing.T) {
+	testutil.AssertNoError(t, filesystem.Wrap("remove", "fastly.toml", nil))
+}
+
+func TestWrapRelativePath(t *testing.T) {
+	wd, err := os.Getwd()
+	testutil.AssertNoError(t, err)
+
+	err = filesystem.Wrap("remove", "fastly.toml", fs.ErrPermission)
+	testutil.AssertErrorContains(t, err, "failed to remove "+filepath.Join(wd, "fastly.toml"))
+}
+
+func TestOperations(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a", "b")
+
+	testutil.AssertNoError(t, filesystem.MkdirAll(path, 0o700))
+
+	f, err := filesystem.CreateFile(filepath.Join(path, "file"))
+	testutil.AssertNoError(t, err)
+	testutil.AssertNoError(t, f.Close())
+
+	renamed := filepath.Join(path, "renamed")
+	testutil.AssertNoError(t, filesystem.Rename(f.Name(), renamed))
+	testutil.AssertNoError(t, filesystem.Remove(renamed))
+
+	err = filesystem.Remove(renamed)
+	testutil.AssertErrorContains(t, err, "failed to remove "+renamed)
+	testutil.AssertBool(t, true, errors.Is(err, fs.ErrNotExist))
+
+	err = filesystem.Rename(renamed, f.Name())
+	testutil.AssertErrorContains(t, err, "failed to rename "+renamed+" to "+f.Name())
+}
+
+func TestCreateFileReadOnlyDirectory(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("directory permissions aren't enforced on Windows")
+	}
+	if os.Geteuid() == 0 {
+		t.Skip("directory permissions aren't enforced for root")
+	}
+
+	dir := t.TempDir()
+	testutil.AssertNoError(t, os.Chmod(dir, 0o500))
+	defer os.Chmod(dir, 0o700) // #nosec G302
+
+	path := filepath.Join(dir, "file")
+	_, err := filesystem.CreateFile(path)
+	testutil.AssertErrorContains(t, err, "failed to create file "+path+": permission denied")
+	testutil.AssertRemediationErrorContains(t, err, "Permission was denied")
+
+	err = filesystem.MkdirAll(filepath.Join(dir, "sub"), 0o700)
+	testutil.AssertErrorContains(t, err, "failed to create directory")
+	testutil.AssertRemediationErrorContains(t, err, fsterr.HostRemediation)
+}
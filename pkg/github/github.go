@@ -286,6 +286,8 @@ type AssetVersioner interface {
 	DownloadVersion(version string) (bin string, err error)
 	// InstallPath returns the location of where the binary should be installed.
 	InstallPath() string
+	// InstallVersion downloads, verifies and installs the specified version.
+	InstallVersion(version, checksum string) (bin string, err error)
 	// RequestedVersion returns the version defined in the fastly.toml file.
 	RequestedVersion() (version string)
 	// SetRequestedVersion sets the version of the asset to be downloaded.
@@ -321,6 +323,12 @@ func createArchive(assetBase, tmpDir string, data io.ReadCloser) (path string, e
 	return archive.Name(), nil
 }
 
+// ExtractBinary extracts the named executable binary from the root of the
+// archive into dst, sets executable permissions and returns its path.
+func ExtractBinary(archive, binaryName, dst string) (bin string, err error) {
+	return extractBinary(archive, binaryName, dst, filepath.Base(archive), false)
+}
+
 // extractBinary extracts the executable binary (e.g. fastly, viceroy,
 // wasm-tools) from the specified archive file, modifies its permissions and
 // returns the path.
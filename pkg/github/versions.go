@@ -0,0 +1,186 @@
+package github
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// ErrChecksumMismatch means a downloaded release asset doesn't match its
+// expected SHA-256 checksum.
+var ErrChecksumMismatch = fmt.Errorf("checksum mismatch")
+
+// InstalledVersion is a version of a binary installed with InstallVersion.
+type InstalledVersion struct {
+	// Version is the release version (e.g. 0.9.4).
+	Version string `json:"version"`
+	// Path is the location of the executable binary.
+	Path string `json:"path"`
+}
+
+// VersionsDir returns the directory where specific versions of the binary are
+// installed, one sub-directory per version (e.g. viceroy-versions/0.9.4).
+//
+// NOTE: This is separate from InstallPath, which is the version of the binary
+// the CLI keeps up-to-date.
+func VersionsDir(binary string) string {
+	return filepath.Join(InstallDir, strings.TrimSuffix(binary, ".exe")+"-versions")
+}
+
+// VersionPath returns where the version of the binary is installed.
+func VersionPath(binary, version string) string {
+	return filepath.Join(VersionsDir(binary), version, binary)
+}
+
+// InstalledVersions returns the versions of the binary installed with
+// InstallVersion, newest first.
+func InstalledVersions(binary string) ([]InstalledVersion, error) {
+	entries, err := os.ReadDir(VersionsDir(binary))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read installed versions: %w", err)
+	}
+
+	var (
+		installed []InstalledVersion
+		versions  = make(map[string]semver.Version)
+	)
+	for _, e := range entries {
+		v, err := semver.Parse(e.Name())
+		if err != nil || !e.IsDir() {
+			continue
+		}
+		path := VersionPath(binary, e.Name())
+		if _, err := os.Stat(path); err != nil {
+			continue // e.g. an interrupted install
+		}
+		versions[e.Name()] = v
+		installed = append(installed, InstalledVersion{Version: e.Name(), Path: path})
+	}
+	sort.Slice(installed, func(i, j int) bool {
+		return versions[installed[i].Version].GT(versions[installed[j].Version])
+	})
+	return installed, nil
+}
+
+// InstallVersion downloads the specified binary version, verifies the release
+// asset against its SHA-256 checksum and installs the binary at VersionPath.
+//
+// The checksum is a hex-encoded SHA-256 digest. If empty, the checksum
+// published alongside the release asset (the asset URL with a .sha256 suffix)
+// is used instead.
+func (g *Asset) InstallVersion(version, checksum string) (bin string, err error) {
+	if _, err := semver.Parse(version); err != nil {
+		return "", err
+	}
+
+	endpoint, err := g.URL()
+	if err != nil {
+		return "", err
+	}
+	endpoint = strings.ReplaceAll(endpoint, g.version, version)
+
+	if checksum == "" {
+		checksum, err = g.publishedChecksum(endpoint)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	res, err := g.get(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("failed to request GitHub release asset: %w", err)
+	}
+	defer res.Body.Close() // #nosec G307
+
+	tmpDir, err := os.MkdirTemp("", "fastly-download")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp release directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	h := sha256.New()
+	assetBase := filepath.Base(endpoint)
+	archive, err := createArchive(assetBase, tmpDir, io.NopCloser(io.TeeReader(res.Body, h)))
+	if err != nil {
+		return "", err
+	}
+
+	if have := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(have, checksum) {
+		return "", fsterr.RemediationError{
+			Inner:       fmt.Errorf("%w for %s: expected %s, got %s", ErrChecksumMismatch, assetBase, strings.ToLower(checksum), have),
+			Remediation: "The download may be corrupt or incomplete. Try again, and if the problem persists check the checksum matches the one on the release page.",
+		}
+	}
+
+	extractedBinary, err := extractBinary(archive, g.binary, tmpDir, assetBase, g.nested)
+	if err != nil {
+		return "", err
+	}
+
+	bin = VersionPath(g.binary, version)
+	if err := os.MkdirAll(filepath.Dir(bin), 0o750); err != nil {
+		return "", fmt.Errorf("failed to create version directory: %w", err)
+	}
+	if err := os.Rename(extractedBinary, bin); err != nil {
+		return "", fmt.Errorf("failed to move binary to %s: %w", bin, err)
+	}
+	return bin, SetBinPerms(bin)
+}
+
+// publishedChecksum returns the checksum published alongside the release
+// asset, in the format of the sha256sum tool (the digest, optionally followed
+// by the file name).
+func (g *Asset) publishedChecksum(endpoint string) (string, error) {
+	res, err := g.get(endpoint + ".sha256")
+	if err != nil {
+		return "", fsterr.RemediationError{
+			Inner:       fmt.Errorf("failed to request the release asset checksum: %w", err),
+			Remediation: "Provide the SHA-256 checksum from the release page with the --checksum flag.",
+		}
+	}
+	defer res.Body.Close() // #nosec G307
+
+	s := bufio.NewScanner(io.LimitReader(res.Body, 1024))
+	if s.Scan() {
+		if fields := strings.Fields(s.Text()); len(fields) > 0 {
+			if _, err := hex.DecodeString(fields[0]); err == nil && len(fields[0]) == sha256.Size*2 {
+				return fields[0], nil
+			}
+		}
+	}
+	return "", fmt.Errorf("failed to parse the release asset checksum: expected a hex-encoded SHA-256 digest")
+}
+
+// get requests the endpoint, returning an error unless the response is 200 OK.
+func (g *Asset) get(endpoint string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a HTTP request: %w", err)
+	}
+	if g.httpClient == nil {
+		g.httpClient = http.DefaultClient
+	}
+	res, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("%s", res.Status)
+	}
+	return res, nil
+}
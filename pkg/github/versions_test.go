@@ -0,0 +1,136 @@
+package github_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastly/cli/pkg/github"
+	"github.com/fastly/cli/pkg/runtime"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestInstallVersion(t *testing.T) {
+	if runtime.Windows {
+		t.Skip("the fake release asset contains a shell script")
+	}
+
+	for _, testcase := range []struct {
+		name         string
+		version      string
+		checksum     string
+		badChecksum  bool
+		noChecksum   bool
+		wantError    string
+		wantMismatch bool
+	}{
+		{
+			name:    "published checksum",
+			version: "0.9.3",
+		},
+		{
+			name:     "provided checksum",
+			version:  "0.9.3",
+			checksum: "provided",
+		},
+		{
+			name:         "published checksum mismatch",
+			version:      "0.9.3",
+			badChecksum:  true,
+			wantError:    "checksum mismatch for viceroy_v0.9.3_",
+			wantMismatch: true,
+		},
+		{
+			name:         "provided checksum mismatch",
+			version:      "0.9.3",
+			checksum:     "ABCDEF",
+			wantError:    "expected abcdef, got ",
+			wantMismatch: true,
+		},
+		{
+			name:       "no published checksum",
+			version:    "0.9.3",
+			noChecksum: true,
+			wantError:  "failed to request the release asset checksum: 404 Not Found",
+		},
+		{
+			name:      "invalid version",
+			version:   "nope",
+			wantError: "No Major.Minor.Patch elements found",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			installDir := github.InstallDir
+			github.InstallDir = t.TempDir()
+			defer func() {
+				github.InstallDir = installDir
+			}()
+
+			server := testutil.NewReleaseServer(t, "viceroy", "0.9.4")
+			server.BadChecksum[testcase.version] = testcase.badChecksum
+			server.NoChecksum[testcase.version] = testcase.noChecksum
+			checksum := testcase.checksum
+			if checksum == "provided" {
+				checksum = server.Checksum(testcase.version)
+			}
+
+			a := github.New(github.Opts{
+				HTTPClient: server.Client(),
+				Org:        "fastly",
+				Repo:       "viceroy",
+				Binary:     "viceroy",
+			})
+			bin, err := a.InstallVersion(testcase.version, checksum)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertBool(t, testcase.wantMismatch, errors.Is(err, github.ErrChecksumMismatch))
+
+			installed, lerr := github.InstalledVersions("viceroy")
+			testutil.AssertNoError(t, lerr)
+			if err != nil {
+				// Nothing is installed when the download can't be verified.
+				testutil.AssertEqual(t, 0, len(installed))
+				return
+			}
+
+			testutil.AssertString(t, filepath.Join(github.InstallDir, "viceroy-versions", "0.9.3", "viceroy"), bin)
+			testutil.AssertEqual(t, []github.InstalledVersion{{Version: "0.9.3", Path: bin}}, installed)
+			output, err := exec.Command(bin, "--version").Output()
+			testutil.AssertNoError(t, err)
+			testutil.AssertString(t, "viceroy 0.9.3\n", string(output))
+		})
+	}
+}
+
+func TestInstalledVersions(t *testing.T) {
+	installDir := github.InstallDir
+	github.InstallDir = t.TempDir()
+	defer func() {
+		github.InstallDir = installDir
+	}()
+
+	for _, v := range []string{"0.9.3", "0.10.0", "0.9.10"} {
+		path := github.VersionPath("viceroy", v)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Neither an interrupted install nor an unrelated directory is listed.
+	for _, dir := range []string{"0.8.0", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(github.VersionsDir("viceroy"), dir), 0o750); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	installed, err := github.InstalledVersions("viceroy")
+	testutil.AssertNoError(t, err)
+	var versions []string
+	for _, i := range installed {
+		versions = append(versions, i.Version)
+	}
+	testutil.AssertEqual(t, []string{"0.10.0", "0.9.10", "0.9.3"}, versions)
+}
@@ -1,16 +1,28 @@
 package global
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"strconv"
+	"time"
+
+	"github.com/fastly/go-fastly/v9/fastly"
 
 	"github.com/fastly/cli/pkg/api"
 	"github.com/fastly/cli/pkg/auth"
 	"github.com/fastly/cli/pkg/config"
+	"github.com/fastly/cli/pkg/debug"
+	"github.com/fastly/cli/pkg/deprecation"
 	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/events"
 	"github.com/fastly/cli/pkg/github"
+	"github.com/fastly/cli/pkg/httpclient"
 	"github.com/fastly/cli/pkg/lookup"
 	"github.com/fastly/cli/pkg/manifest"
+	"github.com/fastly/cli/pkg/text"
+	"github.com/fastly/cli/pkg/timing"
+	"github.com/fastly/cli/pkg/tracing"
 )
 
 // DefaultAPIEndpoint is the default Fastly API endpoint.
@@ -47,43 +59,108 @@ type Versioners struct {
 // (e.g. an email address). Otherwise, parameters should be defined in specific
 // command structs, and parsed as flags.
 type Data struct {
+	// APICache caches service and version listings between invocations.
+	APICache *httpclient.Cache
 	// APIClient is a Fastly API client instance.
 	APIClient api.Interface
 	// APIClientFactory is a factory function for creating an api.Interface type.
 	APIClientFactory APIClientFactory
+	// APIClock measures the skew between the local clock and the API's clock.
+	APIClock *httpclient.ClockSkew
+	// APICompression requests compressed API responses and counts the bytes
+	// received.
+	APICompression *httpclient.Compression
+	// APIConns counts the connections opened and reused by bulk operations.
+	APIConns *httpclient.ConnTracker
+	// APIDeadline bounds API requests by the --max-time deadline.
+	APIDeadline *httpclient.Deadline
+	// APIMemo memoizes idempotent API requests for the current invocation.
+	APIMemo *httpclient.Memo
+	// APIResponses records unsuccessful API responses so the request ID can be
+	// attached to the resulting error.
+	APIResponses *httpclient.Recorder
+	// APITrace logs API requests at the higher verbosity levels.
+	APITrace *debug.Transport
+	// APITracing records API requests in the command's trace (if tracing is
+	// enabled, see env.TraceFile).
+	APITracing *tracing.Transport
 	// Args are the command line arguments provided by the user.
 	Args []string
+	// AuditLogPath is the path to the CLI's audit log of mutating commands.
+	AuditLogPath string
 	// AuthServer is an instance of the authentication server type.
 	// Used for interacting with Fastly's SSO/OAuth authentication provider.
 	AuthServer auth.Runner
+	// CommandFlags returns the long flags accepted by a command (e.g.
+	// "backend create"), including the global flags. It's set by app.Exec.
+	CommandFlags func(command string) ([]string, error)
+	// Context is cancelled when the user interrupts the CLI (e.g. Ctrl-C).
+	// Long-running operations should stop and clean up when it's done.
+	Context context.Context
 	// Config is an instance of the CLI configuration data.
 	Config config.File
 	// ConfigPath is the path to the CLI's application configuration.
 	ConfigPath string
+	// Deprecations records the deprecated flags, manifest keys and env vars
+	// used by the invocation (see --strict).
+	Deprecations *deprecation.Tracker
 	// Env is all the data that is provided by the environment.
 	Env config.Environment
 	// ErrLog provides an interface for recording errors to disk.
 	ErrLog fsterr.LogInterface
+	// ErrOutput is the output for diagnostics that mustn't be mixed with a
+	// command's output (typically os.Stderr).
+	ErrOutput io.Writer
+	// Events emits machine-readable progress events (nil unless requested via
+	// --events or the FASTLY_EVENTS environment variable).
+	Events *events.Emitter
+	// ExecuteEditor runs a text editor attached to the terminal. args are the
+	// editor command (and its arguments) followed by the file to edit.
+	ExecuteEditor func(args []string) error
+	// ExecuteGit runs git with the arguments in the current directory,
+	// returning its combined output.
+	ExecuteGit func(args ...string) ([]byte, error)
 	// ExecuteWasmTools is a function that executes the wasm-tools binary.
 	ExecuteWasmTools func(bin string, args []string) error
+	// FirstRun indicates there was no config file when the CLI started, i.e.
+	// it's the first time it has been run.
+	FirstRun bool
 	// Flags are all the global CLI flags.
 	Flags Flags
 	// HTTPClient is a HTTP client.
 	HTTPClient api.HTTPClient
 	// Input is the standard input for accepting input from the user.
 	Input io.Reader
+	// IsTTY reports whether the given file is a terminal.
+	IsTTY func(fd any) bool
 	// Manifest represents the fastly.toml manifest file and associated flags.
 	Manifest *manifest.Data
 	// Opener is a function that can open a browser window.
 	Opener func(string) error
 	// Output is the output for displaying information (typically os.Stdout)
 	Output io.Writer
+	// PrepareCommand parses the arguments of a command to be run within this
+	// invocation (e.g. a step of a plan), returning a function that runs it
+	// with the given output. The command shares the invocation's API client.
+	// It's set by app.Exec.
+	PrepareCommand func(args []string) (func(out io.Writer) error, error)
+	// Resolver performs DNS lookups.
+	Resolver api.Resolver
 	// RTSClient is a Fastly API client instance for the Real Time Stats endpoints.
 	RTSClient api.RealtimeStatsInterface
 	// SkipAuthPrompt is used to indicate to the `sso` command that the
 	// interactive prompt can be skipped. This is for scenarios where the command
 	// is executed directly by the user.
 	SkipAuthPrompt bool
+	// TerminalWidth is the output width decided for the invocation (see
+	// text.DetectWidth). It's nil when output isn't sized to the terminal
+	// (e.g. in tests), so prose is wrapped at text.DefaultTextWidth.
+	TerminalWidth *text.Width
+	// Timings records the duration of a command's phases (e.g. upload).
+	Timings *timing.Registry
+	// TokenSelf returns the description of the API token (e.g. its scope),
+	// requesting it at most once per invocation. It's set by app.Exec.
+	TokenSelf func() (*fastly.Token, error)
 	// Versioners contains multiple software versioning checkers.
 	// e.g. Check for latest CLI or Viceroy version.
 	Versioners Versioners
@@ -169,9 +246,53 @@ func (d *Data) Token() (string, lookup.Source) {
 	return "", lookup.SourceUndefined
 }
 
-// Verbose yields the verbose flag, which can only be set via flags.
+// Verbosity levels, each of which includes the output of the lower levels.
+const (
+	// VerbosityInfo adds provenance (e.g. where a value was read from) and
+	// timing summaries.
+	VerbosityInfo = 1
+	// VerbosityRequests adds a line for each API request (method, path, status
+	// and duration).
+	VerbosityRequests = 2
+	// VerbosityTrace adds the (redacted) headers and bodies of API requests.
+	VerbosityTrace = 3
+)
+
+// Verbose indicates if additional output should be displayed.
 func (d *Data) Verbose() bool {
-	return d.Flags.Verbose
+	return d.Flags.Verbose || d.Verbosity() >= VerbosityInfo
+}
+
+// Strict indicates if warnings should be errors instead, e.g. about the use of
+// deprecated flags (see --strict and env.Strict).
+func (d *Data) Strict() bool {
+	if d.Flags.Strict {
+		return true
+	}
+	strict, _ := strconv.ParseBool(d.Env.Strict)
+	return strict
+}
+
+// Offline indicates if non-essential network requests should be avoided (see
+// env.Offline).
+func (d *Data) Offline() bool {
+	offline, _ := strconv.ParseBool(d.Env.Offline)
+	return offline
+}
+
+// Verbosity yields the verbosity level (0 if not verbose).
+//
+// The --verbose/-v and --verbosity flags take precedence over the environment.
+// Levels above VerbosityTrace are treated as VerbosityTrace.
+func (d *Data) Verbosity() int {
+	level := d.Flags.Verbosity
+	if level == 0 && d.Flags.Verbose {
+		level = VerbosityInfo
+	}
+	if level == 0 {
+		level, _ = strconv.Atoi(d.Env.Verbosity)
+	}
+	return max(0, min(level, VerbosityTrace))
 }
 
 // APIEndpoint yields the API endpoint.
@@ -223,18 +344,41 @@ type Flags struct {
 	APIEndpoint string
 	// AutoYes auto-resolves Yes/No prompts by answering "Yes".
 	AutoYes bool
+	// ConfigFile is an alternative CLI config file (see Data.ConfigPath).
+	ConfigFile string
 	// Debug enables the CLI's debug mode.
 	Debug bool
+	// Events is a file descriptor or unix socket path for progress events.
+	Events string
+	// Interactive displays prompts even in a CI environment or when standard
+	// input isn't a terminal (see text.SetInteractive).
+	Interactive bool
+	// MaxTime bounds the duration of the whole invocation (zero disables).
+	MaxTime time.Duration
+	// NoCache stops API listings cached by earlier invocations being reused
+	// (see httpclient.Cache).
+	NoCache bool
 	// NonInteractive auto-resolves all prompts.
 	NonInteractive bool
 	// Profile indicates the profile to use (consequently the 'token' used).
 	Profile string
 	// Quiet silences all output except direct command output.
 	Quiet bool
+	// SkipNameValidation sends resource names to the API without checking
+	// them against the naming rules first (see naming.Validate).
+	SkipNameValidation bool
+	// SkipScopeCheck skips checking the API token has the scope a command
+	// needs before it runs (see scope.Check).
+	SkipScopeCheck bool
 	// SSO enables to SSO authentication tokens for the current profile.
 	SSO bool
+	// Strict turns the warnings about deprecated items into errors.
+	Strict bool
 	// Token is an override for a profile (when passed SSO is disabled).
 	Token string
 	// Verbose prints additional output.
 	Verbose bool
+	// Verbosity is the level of additional output (see VerbosityInfo etc).
+	// It's set by repeating --verbose (e.g. -vv) or via --verbosity.
+	Verbosity int
 }
@@ -0,0 +1,231 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// DefaultCacheTTL is how long a response cached by Cache is reused for.
+const DefaultCacheTTL = time.Minute
+
+// cachedPaths are the API listings cached by Cache: the services (which are
+// also listed to resolve a service name to its ID) and a service's versions.
+var cachedPaths = []*regexp.Regexp{
+	regexp.MustCompile(`^/service$`),
+	regexp.MustCompile(`^/service/search$`),
+	regexp.MustCompile(`^/service/[^/]+/version$`),
+}
+
+// Cache is a http.RoundTripper that caches service and version listings on
+// disk, so they're reused by later invocations of the CLI for a short time
+// (e.g. by scripts that run several commands against the same service).
+//
+// A cached response is only reused by the same API token, and is reported if
+// an output is set (see SetOutput). Any other request method (e.g. POST, PUT,
+// DELETE) may create, rename or delete a service, so it clears the cache.
+//
+// NOTE: The fields are set once the command is known (see app.Exec), before
+// any request is sent.
+type Cache struct {
+	// Base is the underlying http.RoundTripper (http.DefaultTransport if nil).
+	Base http.RoundTripper
+	// Dir is the directory the responses are cached in.
+	Dir string
+	// Disabled stops responses being cached or reused (e.g. --no-cache). The
+	// cache is still cleared by changes.
+	Disabled bool
+	// MaxBodySize is the largest body cached (DefaultMemoMaxBodySize if 0).
+	MaxBodySize int64
+	// Now returns the current time (time.Now if nil).
+	Now func() time.Time
+	// Revalidate stops cached responses being reused, while still caching the
+	// fresh responses. It's set for mutating commands, so their decisions
+	// (e.g. which service to update) aren't based on stale data.
+	Revalidate bool
+	// TTL is how long a response is reused for (DefaultCacheTTL if 0).
+	TTL time.Duration
+
+	out io.Writer
+}
+
+// cacheEntry is a response cached on disk.
+type cacheEntry struct {
+	FetchedAt time.Time   `json:"fetched_at"`
+	Header    http.Header `json:"header"`
+	Status    int         `json:"status"`
+	Body      []byte      `json:"body"`
+}
+
+// SetOutput enables reporting of cached responses (e.g. in verbose mode).
+func (c *Cache) SetOutput(w io.Writer) {
+	if c == nil {
+		return
+	}
+	c.out = w
+}
+
+// Clear removes every cached response.
+func (c *Cache) Clear() error {
+	if c == nil || c.Dir == "" {
+		return nil
+	}
+	return os.RemoveAll(c.Dir)
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (c *Cache) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := c.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		// NOTE: The cache is cleared before the change is sent, as a change
+		// that fails may still have been applied.
+		_ = c.Clear()
+		return base.RoundTrip(req)
+	}
+	if c.Disabled || c.Dir == "" || !cacheable(req) {
+		return base.RoundTrip(req)
+	}
+
+	path := filepath.Join(c.Dir, cacheKey(req)+".json")
+	if !c.Revalidate {
+		if e, ok := c.read(path); ok {
+			if c.out != nil {
+				fmt.Fprintf(c.out, "Using cached API response (fetched %s ago): %s %s\n", c.now().Sub(e.FetchedAt).Round(time.Second), req.Method, req.URL.Path)
+			}
+			return e.response(req), nil
+		}
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil || resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp, err
+	}
+	limit := c.MaxBodySize
+	if limit <= 0 {
+		limit = DefaultMemoMaxBodySize
+	}
+	if resp.ContentLength > limit {
+		return resp, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		resp.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), resp.Body), Closer: resp.Body}
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	c.write(path, cacheEntry{
+		FetchedAt: c.now(),
+		Header:    resp.Header.Clone(),
+		Status:    resp.StatusCode,
+		Body:      body,
+	})
+	return resp, nil
+}
+
+func (c *Cache) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+func (c *Cache) ttl() time.Duration {
+	if c.TTL > 0 {
+		return c.TTL
+	}
+	return DefaultCacheTTL
+}
+
+// read returns the cached response at path, unless it has expired.
+func (c *Cache) read(path string) (e cacheEntry, ok bool) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return e, false
+	}
+	if err := json.Unmarshal(b, &e); err != nil {
+		return e, false
+	}
+	age := c.now().Sub(e.FetchedAt)
+	return e, age >= 0 && age < c.ttl()
+}
+
+// write caches the response at path.
+//
+// NOTE: The cache is best effort, so a response that can't be written (e.g.
+// the directory is read-only) is simply not cached. It's written to a
+// temporary file first so a concurrent invocation never reads part of it.
+func (c *Cache) write(path string, e cacheEntry) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.Dir, 0o700); err != nil {
+		return
+	}
+	f, err := os.CreateTemp(c.Dir, "tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+}
+
+// response returns the cached response.
+func (e cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Header:        e.Header.Clone(),
+		Proto:         "HTTP/1.1",
+		Request:       req,
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+	}
+}
+
+// cacheable reports whether the request is for one of the cachedPaths.
+func cacheable(req *http.Request) bool {
+	for _, re := range cachedPaths {
+		if re.MatchString(req.URL.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheKey identifies a request by its method, URL and credentials. It's
+// hashed so the API token isn't written to disk.
+func cacheKey(req *http.Request) string {
+	sum := sha256.Sum256([]byte(memoKey(req)))
+	return hex.EncodeToString(sum[:])
+}
+
+// readCloser reads a body that was partly read already, closing the original.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,97 @@
+package httpclient_test
+
+import (
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fastly/cli/pkg/httpclient"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestCache(t *testing.T) {
+	ct := &countingTransport{}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := &httpclient.Cache{
+		Base: ct,
+		Dir:  t.TempDir(),
+		Now:  func() time.Time { return now },
+		TTL:  time.Minute,
+	}
+	client := &http.Client{Transport: cache}
+
+	// Listings are cached, other requests aren't.
+	testutil.AssertString(t, "/service", get(t, client, http.MethodGet, "http://example.com/service"))
+	testutil.AssertString(t, "/service", get(t, client, http.MethodGet, "http://example.com/service"))
+	get(t, client, http.MethodGet, "http://example.com/service/123/version")
+	get(t, client, http.MethodGet, "http://example.com/service/123/version")
+	get(t, client, http.MethodGet, "http://example.com/service/123/details")
+	get(t, client, http.MethodGet, "http://example.com/service/123/details")
+	testutil.AssertEqual(t, 1, ct.count("GET /service"))
+	testutil.AssertEqual(t, 1, ct.count("GET /service/123/version"))
+	testutil.AssertEqual(t, 2, ct.count("GET /service/123/details"))
+
+	// A new cache (i.e. a later invocation) reuses the cached responses.
+	later := &httpclient.Cache{Base: ct, Dir: cache.Dir, Now: cache.Now, TTL: time.Minute}
+	get(t, &http.Client{Transport: later}, http.MethodGet, "http://example.com/service")
+	testutil.AssertEqual(t, 1, ct.count("GET /service"))
+
+	// The responses expire after the TTL.
+	now = now.Add(time.Minute)
+	get(t, client, http.MethodGet, "http://example.com/service")
+	get(t, client, http.MethodGet, "http://example.com/service")
+	testutil.AssertEqual(t, 2, ct.count("GET /service"))
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	ct := &countingTransport{}
+	cache := &httpclient.Cache{Base: ct, Dir: t.TempDir()}
+	client := &http.Client{Transport: cache}
+
+	get(t, client, http.MethodGet, "http://example.com/service")
+	get(t, client, http.MethodPost, "http://example.com/service")
+	if _, err := os.Stat(cache.Dir); !os.IsNotExist(err) {
+		t.Errorf("want the cache to be cleared, have: %v", err)
+	}
+	get(t, client, http.MethodGet, "http://example.com/service")
+	testutil.AssertEqual(t, 2, ct.count("GET /service"))
+
+	// Renaming or deleting a service, or any other change, also clears it.
+	get(t, client, http.MethodPut, "http://example.com/service/123")
+	get(t, client, http.MethodGet, "http://example.com/service")
+	get(t, client, http.MethodDelete, "http://example.com/service/123")
+	get(t, client, http.MethodGet, "http://example.com/service")
+	testutil.AssertEqual(t, 4, ct.count("GET /service"))
+}
+
+func TestCacheRevalidate(t *testing.T) {
+	ct := &countingTransport{}
+	dir := t.TempDir()
+	get(t, &http.Client{Transport: &httpclient.Cache{Base: ct, Dir: dir}}, http.MethodGet, "http://example.com/service")
+
+	// A mutating command doesn't reuse the cached response, but its fresh
+	// response is cached for later invocations.
+	revalidate := &httpclient.Cache{Base: ct, Dir: dir, Revalidate: true}
+	get(t, &http.Client{Transport: revalidate}, http.MethodGet, "http://example.com/service")
+	testutil.AssertEqual(t, 2, ct.count("GET /service"))
+
+	ct.status = http.StatusServiceUnavailable
+	get(t, &http.Client{Transport: &httpclient.Cache{Base: ct, Dir: dir}}, http.MethodGet, "http://example.com/service")
+	testutil.AssertEqual(t, 2, ct.count("GET /service"))
+}
+
+func TestCacheDisabled(t *testing.T) {
+	ct := &countingTransport{}
+	cache := &httpclient.Cache{Base: ct, Dir: t.TempDir(), Disabled: true}
+	client := &http.Client{Transport: cache}
+
+	get(t, client, http.MethodGet, "http://example.com/service")
+	get(t, client, http.MethodGet, "http://example.com/service")
+	testutil.AssertEqual(t, 2, ct.count("GET /service"))
+	entries, err := os.ReadDir(cache.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertEqual(t, 0, len(entries))
+}
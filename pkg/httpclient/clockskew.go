@@ -0,0 +1,125 @@
+package httpclient
+
+import (
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// DefaultClockSkewThreshold is the clock skew tolerated before errors are
+// attributed to it.
+const DefaultClockSkewThreshold = 5 * time.Minute
+
+// ClockSkew is a http.RoundTripper that measures the difference between the
+// local clock and the clock of the server (using the Date header of the first
+// response) so TLS and authentication failures caused by a wrong system clock
+// can be explained to the user.
+//
+// NOTE: The measurement piggybacks on requests the CLI makes anyway.
+type ClockSkew struct {
+	// Base is the underlying http.RoundTripper (http.DefaultTransport if nil).
+	Base http.RoundTripper
+	// Now returns the local time (time.Now if nil).
+	Now func() time.Time
+	// Threshold is the tolerated skew (DefaultClockSkewThreshold if zero).
+	Threshold time.Duration
+
+	mu       sync.Mutex
+	measured bool
+	skew     time.Duration
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (c *ClockSkew) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := c.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.measured {
+		return resp, err
+	}
+	date, perr := http.ParseTime(resp.Header.Get("Date"))
+	if perr != nil {
+		return resp, err
+	}
+	now := time.Now
+	if c.Now != nil {
+		now = c.Now
+	}
+	c.skew = now().Sub(date).Round(time.Second)
+	c.measured = true
+	return resp, err
+}
+
+// Skew returns the measured skew (positive when the local clock is ahead) and
+// whether a measurement has been made.
+func (c *ClockSkew) Skew() (time.Duration, bool) {
+	if c == nil {
+		return 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.skew, c.measured
+}
+
+// Exceeded indicates if the measured skew is beyond the threshold.
+func (c *ClockSkew) Exceeded() bool {
+	skew, ok := c.Skew()
+	if !ok {
+		return false
+	}
+	threshold := c.Threshold
+	if threshold == 0 {
+		threshold = DefaultClockSkewThreshold
+	}
+	return skew.Abs() > threshold
+}
+
+// Annotate replaces the remediation of a TLS certificate or authentication
+// error with one describing the clock skew, if the skew exceeds the threshold.
+// Other errors are returned unmodified.
+func (c *ClockSkew) Annotate(err error) error {
+	if err == nil || !c.Exceeded() || !clockSensitive(err) {
+		return err
+	}
+	skew, _ := c.Skew()
+	direction := "ahead of"
+	if skew < 0 {
+		direction = "behind"
+	}
+	re := fsterr.Deduce(err)
+	re.Code = fsterr.CodeClockSkew
+	re.Remediation = fsterr.RenderRemediation(fsterr.RemediationClockSkew, map[string]any{
+		"Skew":      skew.Abs().String(),
+		"Direction": direction,
+	})
+	return re
+}
+
+// clockSensitive indicates if err could have been caused by a wrong clock,
+// i.e. a certificate that appears expired (or not yet valid), or a rejected
+// API token.
+func clockSensitive(err error) bool {
+	var certErr x509.CertificateInvalidError
+	if errors.As(err, &certErr) && certErr.Reason == x509.Expired {
+		return true
+	}
+	var httpErr *fastly.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusUnauthorized || httpErr.StatusCode == http.StatusForbidden
+	}
+	return false
+}
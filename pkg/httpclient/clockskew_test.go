@@ -0,0 +1,174 @@
+package httpclient_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/httpclient"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+// dateTransport responds with the given Date header values in turn.
+type dateTransport struct {
+	dates []string
+}
+
+func (t *dateTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	h := http.Header{}
+	if len(t.dates) > 0 {
+		if t.dates[0] != "" {
+			h.Set("Date", t.dates[0])
+		}
+		t.dates = t.dates[1:]
+	}
+	return &http.Response{
+		Body:       io.NopCloser(strings.NewReader("")),
+		Header:     h,
+		Request:    req,
+		StatusCode: http.StatusOK,
+	}, nil
+}
+
+func TestClockSkew(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	serverTime := func(d time.Duration) string {
+		return now.Add(d).Format(http.TimeFormat)
+	}
+
+	authErr := &fastly.HTTPError{StatusCode: http.StatusUnauthorized}
+	tlsErr := &url.Error{
+		Op:  "Get",
+		URL: "https://api.fastly.com/service",
+		Err: &tls.CertificateVerificationError{
+			Err: x509.CertificateInvalidError{Reason: x509.Expired},
+		},
+	}
+	notFoundErr := &fastly.HTTPError{StatusCode: http.StatusNotFound}
+
+	for _, testcase := range []struct {
+		name            string
+		dates           []string
+		err             error
+		wantSkew        time.Duration
+		wantMeasured    bool
+		wantRemediation string
+	}{
+		{
+			name:         "within threshold",
+			dates:        []string{serverTime(-4 * time.Minute)},
+			err:          authErr,
+			wantSkew:     4 * time.Minute,
+			wantMeasured: true,
+		},
+		{
+			name:            "auth error with local clock ahead",
+			dates:           []string{serverTime(-10 * time.Minute)},
+			err:             authErr,
+			wantSkew:        10 * time.Minute,
+			wantMeasured:    true,
+			wantRemediation: "Your system clock is 10m0s ahead of the Fastly API's clock",
+		},
+		{
+			name:            "TLS error with local clock behind",
+			dates:           []string{serverTime(48 * time.Hour)},
+			err:             tlsErr,
+			wantSkew:        -48 * time.Hour,
+			wantMeasured:    true,
+			wantRemediation: "Your system clock is 48h0m0s behind the Fastly API's clock",
+		},
+		{
+			name:         "unrelated error",
+			dates:        []string{serverTime(-10 * time.Minute)},
+			err:          notFoundErr,
+			wantSkew:     10 * time.Minute,
+			wantMeasured: true,
+		},
+		{
+			name:            "only the first response is measured",
+			dates:           []string{serverTime(-10 * time.Minute), serverTime(0)},
+			err:             authErr,
+			wantSkew:        10 * time.Minute,
+			wantMeasured:    true,
+			wantRemediation: "10m0s ahead of",
+		},
+		{
+			name:            "responses without a Date header are skipped",
+			dates:           []string{"", serverTime(-10 * time.Minute)},
+			err:             authErr,
+			wantSkew:        10 * time.Minute,
+			wantMeasured:    true,
+			wantRemediation: "10m0s ahead of",
+		},
+		{
+			name:  "not measured",
+			dates: []string{"", ""},
+			err:   authErr,
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			cs := &httpclient.ClockSkew{
+				Base: &dateTransport{dates: testcase.dates},
+				Now:  func() time.Time { return now },
+			}
+			client := &http.Client{Transport: cs}
+			for range testcase.dates {
+				resp, err := client.Get("https://api.fastly.com/service")
+				if err != nil {
+					t.Fatal(err)
+				}
+				_ = resp.Body.Close()
+			}
+
+			skew, measured := cs.Skew()
+			testutil.AssertBool(t, testcase.wantMeasured, measured)
+			testutil.AssertEqual(t, testcase.wantSkew, skew)
+
+			err := cs.Annotate(testcase.err)
+			if testcase.wantRemediation == "" {
+				if err != testcase.err {
+					t.Fatalf("want error unmodified, have %#v", err)
+				}
+				return
+			}
+			testutil.AssertRemediationErrorContains(t, err, testcase.wantRemediation)
+		})
+	}
+}
+
+func TestClockSkewKeepsAPIResponse(t *testing.T) {
+	cs := &httpclient.ClockSkew{
+		Base: &dateTransport{dates: []string{time.Now().Add(-time.Hour).Format(http.TimeFormat)}},
+	}
+	resp, err := (&http.Client{Transport: cs}).Get("https://api.fastly.com/tokens/self")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	apiErr := fsterr.APIError{
+		Err: &fastly.HTTPError{StatusCode: http.StatusUnauthorized},
+		Response: fsterr.APIResponse{
+			Method:     http.MethodGet,
+			Path:       "/tokens/self",
+			RequestID:  "abc123",
+			StatusCode: http.StatusUnauthorized,
+		},
+	}
+	var re fsterr.RemediationError
+	if !errors.As(cs.Annotate(apiErr), &re) {
+		t.Fatal("want a RemediationError")
+	}
+	if re.API == nil || re.API.RequestID != "abc123" {
+		t.Errorf("want the API response to be retained, have %#v", re.API)
+	}
+}
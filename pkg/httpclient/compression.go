@@ -0,0 +1,142 @@
+package httpclient
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// Compression is a http.RoundTripper that requests gzip compressed responses
+// and decompresses them as they're read, recording the size of each response
+// on the wire and once decoded.
+//
+// NOTE: http.Transport only decompresses responses when it sets the
+// Accept-Encoding header itself, and it doesn't expose the compressed size.
+// Requests that already have an Accept-Encoding header are sent unmodified,
+// and their responses are returned as is.
+type Compression struct {
+	// Base is the underlying http.RoundTripper (http.DefaultTransport if nil).
+	Base http.RoundTripper
+
+	decoded   atomic.Int64
+	responses atomic.Int64
+	wire      atomic.Int64
+}
+
+// Transfer is the amount of data received in responses.
+type Transfer struct {
+	// Responses is the number of responses received.
+	Responses int64
+	// WireBytes is the number of body bytes received from the server.
+	WireBytes int64
+	// DecodedBytes is the number of body bytes once decompressed.
+	DecodedBytes int64
+}
+
+// Transfer returns the amount of data received so far. Only the body bytes
+// that have been read are counted.
+func (c *Compression) Transfer() Transfer {
+	if c == nil {
+		return Transfer{}
+	}
+	return Transfer{
+		Responses:    c.responses.Load(),
+		WireBytes:    c.wire.Load(),
+		DecodedBytes: c.decoded.Load(),
+	}
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (c *Compression) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := c.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	negotiate := req.Header.Get("Accept-Encoding") == "" && req.Header.Get("Range") == ""
+	if negotiate {
+		// NOTE: A RoundTripper mustn't modify the caller's request.
+		req = req.Clone(req.Context())
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	c.responses.Add(1)
+
+	wire := &countingReader{r: resp.Body, n: &c.wire}
+	if negotiate && strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+		resp.Body = &gzipBody{wire: wire, body: resp.Body, decoded: &c.decoded}
+		return resp, nil
+	}
+
+	// The server ignored the Accept-Encoding header (or wasn't asked), so the
+	// body is read as is.
+	resp.Body = &countingBody{
+		Reader: &countingReader{r: wire, n: &c.decoded},
+		Closer: resp.Body,
+	}
+	return resp, nil
+}
+
+// countingReader adds the number of bytes read from r to n.
+type countingReader struct {
+	r io.Reader
+	n *atomic.Int64
+}
+
+// Read implements the io.Reader interface.
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n.Add(int64(n))
+	return n, err
+}
+
+// countingBody pairs a countingReader with the original body closer.
+type countingBody struct {
+	io.Reader
+	io.Closer
+}
+
+// gzipBody decompresses a gzip encoded response body as it's read.
+type gzipBody struct {
+	body    io.ReadCloser
+	decoded *atomic.Int64
+	err     error
+	wire    io.Reader
+	zr      *gzip.Reader
+}
+
+// Read implements the io.Reader interface.
+func (b *gzipBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	// NOTE: The gzip reader is created on the first read (rather than in
+	// RoundTrip) as it reads the gzip header from the body.
+	if b.zr == nil {
+		zr, err := gzip.NewReader(b.wire)
+		if err != nil {
+			// An empty body (e.g. a HEAD request) has no gzip header.
+			b.err = err
+			return 0, err
+		}
+		b.zr = zr
+	}
+	n, err := b.zr.Read(p)
+	b.decoded.Add(int64(n))
+	return n, err
+}
+
+// Close implements the io.Closer interface.
+func (b *gzipBody) Close() error {
+	return b.body.Close()
+}
@@ -0,0 +1,181 @@
+package httpclient_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/httpclient"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+// gzipServer serves body, gzip compressed when the client accepts it (unless
+// ignoreEncoding is set), recording the Accept-Encoding header of the last
+// request.
+type gzipServer struct {
+	body           []byte
+	ignoreEncoding bool
+	acceptEncoding string
+}
+
+func (s *gzipServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.acceptEncoding = r.Header.Get("Accept-Encoding")
+	w.Header().Set("Content-Type", "application/json")
+	if s.ignoreEncoding || !strings.Contains(s.acceptEncoding, "gzip") {
+		_, _ = w.Write(s.body)
+		return
+	}
+	w.Header().Set("Content-Encoding", "gzip")
+	if r.Method == http.MethodHead {
+		return
+	}
+	zw := gzip.NewWriter(w)
+	_, _ = zw.Write(s.body)
+	_ = zw.Close()
+}
+
+// items returns a JSON array of n synthetic dictionary items.
+func items(n int) []byte {
+	var b bytes.Buffer
+	b.WriteString("[")
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `{"dictionary_id":"dict123","item_key":"key-%d","item_value":"value-%d","service_id":"svc123"}`, i, i)
+	}
+	b.WriteString("]")
+	return b.Bytes()
+}
+
+func TestCompression(t *testing.T) {
+	body := items(1000)
+
+	for _, testcase := range []struct {
+		name               string
+		acceptEncoding     string
+		ignoreEncoding     bool
+		method             string
+		wantAcceptEncoding string
+		wantBody           []byte
+		wantCompressed     bool
+		wantUncompressed   bool
+	}{
+		{
+			name:               "compressed response is decoded",
+			wantAcceptEncoding: "gzip",
+			wantBody:           body,
+			wantCompressed:     true,
+			wantUncompressed:   true,
+		},
+		{
+			name:               "server ignores Accept-Encoding",
+			ignoreEncoding:     true,
+			wantAcceptEncoding: "gzip",
+			wantBody:           body,
+		},
+		{
+			name:               "caller's Accept-Encoding is respected",
+			acceptEncoding:     "identity",
+			wantAcceptEncoding: "identity",
+			wantBody:           body,
+		},
+		{
+			name:               "compressed response without a body",
+			method:             http.MethodHead,
+			wantAcceptEncoding: "gzip",
+			wantUncompressed:   true,
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			s := &gzipServer{body: body, ignoreEncoding: testcase.ignoreEncoding}
+			srv := httptest.NewServer(s)
+			defer srv.Close()
+
+			c := &httpclient.Compression{Base: srv.Client().Transport}
+			client := &http.Client{Transport: c}
+
+			method := testcase.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req, err := http.NewRequest(method, srv.URL, nil)
+			testutil.AssertNoError(t, err)
+			if testcase.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", testcase.acceptEncoding)
+			}
+			resp, err := client.Do(req)
+			testutil.AssertNoError(t, err)
+			have, err := io.ReadAll(resp.Body)
+			testutil.AssertNoError(t, err)
+			testutil.AssertNoError(t, resp.Body.Close())
+
+			testutil.AssertString(t, testcase.wantAcceptEncoding, s.acceptEncoding)
+			if !bytes.Equal(testcase.wantBody, have) {
+				t.Fatalf("want a %d byte body, have %d bytes", len(testcase.wantBody), len(have))
+			}
+			testutil.AssertString(t, "", resp.Header.Get("Content-Encoding"))
+			testutil.AssertBool(t, testcase.wantUncompressed, resp.Uncompressed)
+
+			transfer := c.Transfer()
+			testutil.AssertEqual(t, int64(1), transfer.Responses)
+			testutil.AssertEqual(t, int64(len(testcase.wantBody)), transfer.DecodedBytes)
+			if testcase.wantCompressed {
+				if transfer.WireBytes == 0 || transfer.WireBytes >= transfer.DecodedBytes {
+					t.Fatalf("want fewer bytes on the wire than decoded, have %+v", transfer)
+				}
+			} else {
+				testutil.AssertEqual(t, transfer.DecodedBytes, transfer.WireBytes)
+			}
+		})
+	}
+}
+
+// BenchmarkLargeResponse measures decoding a large compressed listing as the
+// API client does (see Memo), comparing a body that's copied so it can be
+// memoized with one that's only streamed to the decoder.
+func BenchmarkLargeResponse(b *testing.B) {
+	srv := httptest.NewServer(&gzipServer{body: items(10000)})
+	defer srv.Close()
+
+	type item struct {
+		Key   string `json:"item_key"`
+		Value string `json:"item_value"`
+	}
+	for _, bm := range []struct {
+		name        string
+		maxBodySize int64
+	}{
+		{name: "memoized", maxBodySize: 100 << 20},
+		{name: "streamed", maxBodySize: 1},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				memo := &httpclient.Memo{
+					Base:        &httpclient.Compression{Base: srv.Client().Transport},
+					MaxBodySize: bm.maxBodySize,
+				}
+				resp, err := (&http.Client{Transport: memo}).Get(srv.URL)
+				if err != nil {
+					b.Fatal(err)
+				}
+				var v []item
+				err = json.NewDecoder(resp.Body).Decode(&v)
+				_ = resp.Body.Close()
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(v) != 10000 {
+					b.Fatalf("want 10000 items, have %d", len(v))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"sync/atomic"
+)
+
+// ConnTracker is a http.RoundTripper that counts the connections used by the
+// requests of an operation (see Track), so bulk commands are able to report
+// how many connections they opened and how many they reused.
+//
+// A response that closes its connection (i.e. Connection: close) although the
+// request didn't ask for it is counted too, as it means every request pays for
+// a new connection (e.g. a proxy that doesn't support keep-alive).
+//
+// NOTE: Connections are counted as the transport gets them (via httptrace),
+// so a request served by Memo or Cache isn't counted. Over HTTP/2 requests
+// share a single connection, so they're all counted as reusing it.
+type ConnTracker struct {
+	// Base is the underlying http.RoundTripper (http.DefaultTransport if nil).
+	Base http.RoundTripper
+	// MaxIdleConnsPerHost is the number of idle connections the transport keeps
+	// per host (see Opts). The connections of a worker pool larger than it
+	// can't all be reused.
+	MaxIdleConnsPerHost int
+	// Proxy selects the proxy for a request (see Opts.Proxy), so a connection
+	// that's closed can be attributed to it.
+	Proxy func(*http.Request) (*url.URL, error)
+
+	active atomic.Pointer[ConnStats]
+}
+
+// Track starts counting the connections of an operation whose requests are
+// sent by poolSize concurrent workers, replacing any operation already being
+// tracked. It's safe to call on a nil ConnTracker (e.g. in tests), in which
+// case nothing is counted.
+func (t *ConnTracker) Track(poolSize int) *ConnStats {
+	s := &ConnStats{poolSize: poolSize}
+	if t == nil {
+		return s
+	}
+	s.t = t
+	s.maxIdle = t.MaxIdleConnsPerHost
+	t.active.Store(s)
+	return s
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *ConnTracker) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	s := t.active.Load()
+	if s == nil {
+		return base.RoundTrip(req)
+	}
+
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				s.reused.Add(1)
+			} else {
+				s.opened.Add(1)
+			}
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+	resp, err := base.RoundTrip(req)
+	if err == nil && resp.Close && !req.Close {
+		s.closed.Add(1)
+		if t.Proxy != nil {
+			if u, err := t.Proxy(req); err == nil && u != nil {
+				s.proxy.CompareAndSwap(nil, u)
+			}
+		}
+	}
+	return resp, err
+}
+
+// ConnStats counts the connections of an operation (see ConnTracker.Track).
+type ConnStats struct {
+	poolSize int
+	maxIdle  int
+	t        *ConnTracker
+
+	opened atomic.Int64
+	reused atomic.Int64
+	closed atomic.Int64
+	proxy  atomic.Pointer[url.URL]
+}
+
+// Stop stops counting. It's safe to call more than once.
+func (s *ConnStats) Stop() {
+	if s.t != nil {
+		s.t.active.CompareAndSwap(s, nil)
+	}
+}
+
+// Summary returns the connections counted so far.
+func (s *ConnStats) Summary() ConnSummary {
+	return ConnSummary{
+		Closed:              s.closed.Load(),
+		MaxIdleConnsPerHost: s.maxIdle,
+		Opened:              s.opened.Load(),
+		PoolSize:            s.poolSize,
+		Proxy:               s.proxy.Load(),
+		Reused:              s.reused.Load(),
+	}
+}
+
+// ConnSummary is the connections used by an operation.
+type ConnSummary struct {
+	// Closed is the number of responses that closed their connection although
+	// the request didn't ask for it.
+	Closed int64
+	// MaxIdleConnsPerHost is the number of idle connections kept per host (zero
+	// if the connections weren't tracked).
+	MaxIdleConnsPerHost int
+	// Opened is the number of connections opened.
+	Opened int64
+	// PoolSize is the number of workers that sent the requests concurrently.
+	PoolSize int
+	// Proxy is the proxy the closed connections went through (nil if none).
+	Proxy *url.URL
+	// Reused is the number of requests that reused an idle connection.
+	Reused int64
+}
+
+// Requests returns the number of requests that got a connection.
+func (s ConnSummary) Requests() int64 {
+	return s.Opened + s.Reused
+}
@@ -0,0 +1,121 @@
+package httpclient_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/fastly/cli/pkg/config"
+	"github.com/fastly/cli/pkg/httpclient"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+// bulkRun sends requests to url from a pool of workers (like the workers of
+// `kv-store import`), reading and closing each response body so its
+// connection can be reused.
+func bulkRun(t *testing.T, client *http.Client, url string, requests, workers int) {
+	t.Helper()
+	jobs := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range jobs {
+				resp, err := client.Post(url, "text/plain", nil)
+				if err != nil {
+					t.Error(err)
+					continue
+				}
+				_, _ = io.Copy(io.Discard, resp.Body)
+				_ = resp.Body.Close()
+			}
+		}()
+	}
+	for i := 0; i < requests; i++ {
+		jobs <- struct{}{}
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// TestConnTrackerReuse validates the connections of a bulk run are reused
+// with the default settings, i.e. at most one connection is opened per worker.
+func TestConnTrackerReuse(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	opts, err := httpclient.ParseOpts(config.Environment{}, config.HTTP{})
+	testutil.AssertNoError(t, err)
+	client := httpclient.New(opts)
+	tracker := &httpclient.ConnTracker{
+		Base:                client.Transport,
+		MaxIdleConnsPerHost: opts.MaxIdleConnsPerHost,
+		Proxy:               opts.Proxy(),
+	}
+	client = &http.Client{Transport: tracker}
+
+	// Requests sent outside of a tracked operation aren't counted.
+	bulkRun(t, client, ts.URL, 5, 1)
+
+	const requests, workers = 50, 10
+	conns := tracker.Track(workers)
+	bulkRun(t, client, ts.URL, requests, workers)
+	conns.Stop()
+	bulkRun(t, client, ts.URL, 5, 1)
+
+	s := conns.Summary()
+	testutil.AssertEqual(t, int64(requests), s.Requests())
+	if s.Opened > workers {
+		t.Errorf("want at most %d connections opened, have %d (%d reused)", workers, s.Opened, s.Reused)
+	}
+	testutil.AssertEqual(t, int64(requests)-s.Opened, s.Reused)
+	testutil.AssertEqual(t, int64(0), s.Closed)
+	testutil.AssertEqual(t, workers, s.PoolSize)
+	testutil.AssertEqual(t, httpclient.DefaultMaxIdleConnsPerHost, s.MaxIdleConnsPerHost)
+}
+
+// TestConnTrackerClose validates responses that close their connection are
+// counted, and attributed to the proxy the requests were sent through.
+func TestConnTrackerClose(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Connection", "close")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	proxy := &url.URL{Scheme: "http", Host: "proxy.example.com:8080"}
+	tracker := &httpclient.ConnTracker{
+		Base: httpclient.New(httpclient.Opts{}).Transport,
+		// NOTE: The requests aren't sent through the proxy, it's only used to
+		// attribute the closed connections.
+		Proxy: func(*http.Request) (*url.URL, error) { return proxy, nil },
+	}
+	client := &http.Client{Transport: tracker}
+
+	conns := tracker.Track(1)
+	defer conns.Stop()
+	bulkRun(t, client, ts.URL, 5, 1)
+
+	s := conns.Summary()
+	testutil.AssertEqual(t, int64(5), s.Opened)
+	testutil.AssertEqual(t, int64(0), s.Reused)
+	testutil.AssertEqual(t, int64(5), s.Closed)
+	testutil.AssertString(t, proxy.String(), s.Proxy.String())
+}
+
+// TestConnTrackerNil validates tracking is a no-op without a tracker (e.g.
+// when the API client is mocked).
+func TestConnTrackerNil(t *testing.T) {
+	var tracker *httpclient.ConnTracker
+	conns := tracker.Track(50)
+	conns.Stop()
+	s := conns.Summary()
+	testutil.AssertEqual(t, int64(0), s.Requests())
+	testutil.AssertEqual(t, 50, s.PoolSize)
+}
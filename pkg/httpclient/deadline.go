@@ -0,0 +1,80 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+)
+
+// Deadline is a http.RoundTripper that bounds API requests by the deadline of
+// the whole CLI invocation (see the --max-time flag).
+//
+// When the deadline is reached, in-flight GET and HEAD requests are cancelled.
+// Any other request is a mutation, so one that's in flight is left to
+// complete (its outcome would otherwise be unknown), and a new one is refused
+// with the deadline's cause unless a mutation was already sent. This stops a
+// command before it has side effects, while still allowing a command that has
+// already changed something to finish (or clean up) consistently.
+type Deadline struct {
+	// Base is the underlying http.RoundTripper (http.DefaultTransport if nil).
+	Base http.RoundTripper
+	// Context carries the deadline (nil disables the behaviour).
+	Context context.Context
+
+	mutated atomic.Bool
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (d *Deadline) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := d.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if d.Context == nil {
+		return base.RoundTrip(req)
+	}
+
+	read := req.Method == http.MethodGet || req.Method == http.MethodHead
+	if d.Context.Err() != nil {
+		if !read && !d.mutated.Load() {
+			return nil, context.Cause(d.Context)
+		}
+		return base.RoundTrip(req)
+	}
+	if !read {
+		d.mutated.Store(true)
+		return base.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	stop := context.AfterFunc(d.Context, cancel)
+	resp, err := base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		stop()
+		cancel()
+		if d.Context.Err() != nil {
+			err = context.Cause(d.Context)
+		}
+		return nil, err
+	}
+	// The context must remain valid until the caller has finished reading the
+	// response body, so it's only cancelled once the body is closed.
+	resp.Body = &deadlineBody{ReadCloser: resp.Body, release: func() {
+		stop()
+		cancel()
+	}}
+	return resp, nil
+}
+
+// deadlineBody releases the request context once the response body is closed.
+type deadlineBody struct {
+	io.ReadCloser
+	release func()
+}
+
+// Close implements the io.Closer interface.
+func (b *deadlineBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,95 @@
+package httpclient_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fastly/cli/pkg/httpclient"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+var errDeadline = errors.New("deadline reached")
+
+func TestDeadline(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []string
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received = append(received, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		if r.URL.Path == "/slow" {
+			select {
+			case <-time.After(200 * time.Millisecond):
+			case <-r.Context().Done():
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	do := func(t *testing.T, client *http.Client, method, path string) error {
+		t.Helper()
+		req, err := http.NewRequest(method, ts.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+	newClient := func() (*http.Client, context.CancelFunc) {
+		ctx, cancel := context.WithTimeoutCause(context.Background(), 50*time.Millisecond, errDeadline)
+		return &http.Client{Transport: &httpclient.Deadline{Base: ts.Client().Transport, Context: ctx}}, cancel
+	}
+
+	t.Run("read in flight is cancelled", func(t *testing.T) {
+		client, cancel := newClient()
+		defer cancel()
+		if err := do(t, client, http.MethodGet, "/slow"); !errors.Is(err, errDeadline) {
+			t.Fatalf("want %v, have %v", errDeadline, err)
+		}
+	})
+
+	t.Run("mutation in flight completes", func(t *testing.T) {
+		client, cancel := newClient()
+		defer cancel()
+		testutil.AssertNoError(t, do(t, client, http.MethodPut, "/slow"))
+
+		// Once a mutation has been sent, later ones (e.g. clean-up) are too.
+		testutil.AssertNoError(t, do(t, client, http.MethodDelete, "/cleanup"))
+	})
+
+	t.Run("mutation after the deadline is refused", func(t *testing.T) {
+		client, cancel := newClient()
+		defer cancel()
+		mu.Lock()
+		received = nil
+		mu.Unlock()
+		if err := do(t, client, http.MethodGet, "/slow"); !errors.Is(err, errDeadline) {
+			t.Fatalf("want %v, have %v", errDeadline, err)
+		}
+		if err := do(t, client, http.MethodPost, "/create"); !errors.Is(err, errDeadline) {
+			t.Fatalf("want %v, have %v", errDeadline, err)
+		}
+
+		// Reads are still sent, e.g. to display what was gathered.
+		testutil.AssertNoError(t, do(t, client, http.MethodGet, "/read"))
+		mu.Lock()
+		defer mu.Unlock()
+		testutil.AssertEqual(t, []string{"GET /slow", "GET /read"}, received)
+	})
+
+	t.Run("no deadline", func(t *testing.T) {
+		client := &http.Client{Transport: &httpclient.Deadline{Base: ts.Client().Transport}}
+		testutil.AssertNoError(t, do(t, client, http.MethodGet, "/read"))
+	})
+}
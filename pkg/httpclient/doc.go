@@ -0,0 +1,3 @@
+// Package httpclient constructs the HTTP client used for all network requests
+// made by the CLI, applying the user's timeout and proxy configuration.
+package httpclient
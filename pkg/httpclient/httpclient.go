@@ -0,0 +1,295 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/fastly/cli/pkg/config"
+	"github.com/fastly/cli/pkg/env"
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// DefaultRequestTimeout is the overall timeout for a HTTP request (including
+// reading the response body).
+const DefaultRequestTimeout = 2 * time.Minute
+
+// DefaultTLSHandshakeTimeout is the timeout for the TLS handshake.
+const DefaultTLSHandshakeTimeout = 10 * time.Second
+
+// DefaultMaxIdleConnsPerHost is the number of idle connections kept open per
+// host so later requests can reuse them.
+//
+// NOTE: Go's default is 2, so a bulk command whose workers send requests
+// concurrently (e.g. `kv-store import` with its default pool of 50) would
+// close most connections after a single request and open a new one for the
+// next. The default matches the largest default worker pool (that of
+// `kv-store-entry delete --all`), and only connections that were opened are
+// kept, so a smaller pool keeps fewer.
+const DefaultMaxIdleConnsPerHost = 100
+
+// DefaultIdleConnTimeout is how long an idle connection is kept open.
+const DefaultIdleConnTimeout = 90 * time.Second
+
+// The names of the [http] config settings.
+const (
+	SettingIdleConnTimeout     = "idle_conn_timeout"
+	SettingMaxIdleConnsPerHost = "max_idle_conns_per_host"
+	SettingProxyURL            = "proxy_url"
+	SettingRequestTimeout      = "request_timeout"
+	SettingTLSHandshakeTimeout = "tls_handshake_timeout"
+)
+
+// Opts represents the resolved HTTP client configuration.
+type Opts struct {
+	// Context, when done, cancels any in-flight requests.
+	// e.g. the user interrupted the CLI with Ctrl-C.
+	Context context.Context
+	// ProxyURL is the proxy to use for all requests.
+	// If nil, the standard HTTPS_PROXY/HTTP_PROXY/NO_PROXY variables apply.
+	ProxyURL *url.URL
+	// RequestTimeout is the overall timeout for a request.
+	RequestTimeout time.Duration
+	// TLSHandshakeTimeout is the timeout for the TLS handshake.
+	TLSHandshakeTimeout time.Duration
+	// MaxIdleConnsPerHost is the number of idle connections kept per host.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept open.
+	IdleConnTimeout time.Duration
+}
+
+// ParseOpts resolves the HTTP client configuration from the environment and
+// the CLI config file (environment variables take precedence).
+func ParseOpts(e config.Environment, c config.HTTP) (opts Opts, err error) {
+	opts.RequestTimeout, err = parseDuration(SettingRequestTimeout, env.HTTPRequestTimeout, e.HTTPRequestTimeout, c.RequestTimeout, DefaultRequestTimeout)
+	if err != nil {
+		return opts, err
+	}
+
+	opts.TLSHandshakeTimeout, err = parseDuration(SettingTLSHandshakeTimeout, env.HTTPTLSHandshakeTimeout, e.HTTPTLSHandshakeTimeout, c.TLSHandshakeTimeout, DefaultTLSHandshakeTimeout)
+	if err != nil {
+		return opts, err
+	}
+
+	// NOTE: The connection settings are tuning knobs rather than something
+	// that varies between environments, so they're only read from the config.
+	opts.IdleConnTimeout, err = parseDuration(SettingIdleConnTimeout, "", "", c.IdleConnTimeout, DefaultIdleConnTimeout)
+	if err != nil {
+		return opts, err
+	}
+
+	switch n := c.MaxIdleConnsPerHost; {
+	case n < 0:
+		return opts, invalidSettingErr(SettingMaxIdleConnsPerHost, "", fmt.Sprint(n), false, "a positive number of connections (e.g. 100)")
+	case n == 0:
+		opts.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	default:
+		opts.MaxIdleConnsPerHost = n
+	}
+
+	value, fromEnv := e.HTTPProxy, true
+	if value == "" {
+		value, fromEnv = c.ProxyURL, false
+	}
+	if value != "" {
+		u, err := url.Parse(value)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return opts, invalidSettingErr(SettingProxyURL, env.HTTPProxy, value, fromEnv, `a URL including a scheme and host (e.g. "http://proxy.example.com:8080")`)
+		}
+		opts.ProxyURL = u
+	}
+
+	return opts, nil
+}
+
+// Proxy returns the function that selects the proxy for a request.
+func (opts Opts) Proxy() func(*http.Request) (*url.URL, error) {
+	if opts.ProxyURL != nil {
+		return http.ProxyURL(opts.ProxyURL)
+	}
+	return http.ProxyFromEnvironment
+}
+
+// New returns a HTTP client configured with the given options.
+func New(opts Opts) *http.Client {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.Proxy = opts.Proxy()
+	if opts.TLSHandshakeTimeout > 0 {
+		base.TLSHandshakeTimeout = opts.TLSHandshakeTimeout
+	}
+	if opts.MaxIdleConnsPerHost > 0 {
+		base.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+		base.MaxIdleConns = max(base.MaxIdleConns, opts.MaxIdleConnsPerHost)
+	}
+	if opts.IdleConnTimeout > 0 {
+		base.IdleConnTimeout = opts.IdleConnTimeout
+	}
+	return &http.Client{
+		Transport: &Transport{
+			Base:                base,
+			Context:             opts.Context,
+			RequestTimeout:      opts.RequestTimeout,
+			TLSHandshakeTimeout: base.TLSHandshakeTimeout,
+		},
+	}
+}
+
+// Transport is a http.RoundTripper that enforces the overall request timeout
+// and converts timeout errors into a TimeoutError identifying which of the
+// configured timeouts fired.
+//
+// NOTE: The request timeout is applied via the request context (rather than
+// http.Client.Timeout) so that we're able to distinguish it from other errors.
+type Transport struct {
+	// Base is the underlying http.RoundTripper (http.DefaultTransport if nil).
+	Base http.RoundTripper
+	// Context, when done, cancels any in-flight requests.
+	//
+	// NOTE: Requests started after Context is done are sent as normal so that
+	// commands are able to clean up (e.g. delete a partially created service).
+	Context context.Context
+	// RequestTimeout is the overall timeout for a request (zero disables).
+	RequestTimeout time.Duration
+	// TLSHandshakeTimeout is the value configured on the Base transport.
+	// It's only used for reporting purposes.
+	TLSHandshakeTimeout time.Duration
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ctx, cancel := req.Context(), context.CancelFunc(func() {})
+	if t.RequestTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.RequestTimeout)
+		req = req.WithContext(ctx)
+	}
+	if t.Context != nil && t.Context.Err() == nil {
+		var cancelReq context.CancelFunc
+		ctx, cancelReq = context.WithCancel(ctx)
+		stop := context.AfterFunc(t.Context, cancelReq)
+		cancelTimeout := cancel
+		cancel = func() {
+			stop()
+			cancelReq()
+			cancelTimeout()
+		}
+		req = req.WithContext(ctx)
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		cancel()
+		return nil, t.wrapErr(ctx, err)
+	}
+
+	// The context must remain valid until the caller has finished reading the
+	// response body, so we only cancel it once the body is closed.
+	resp.Body = &body{ReadCloser: resp.Body, cancel: cancel, ctx: ctx, t: t}
+	return resp, nil
+}
+
+// wrapErr converts timeout errors into a TimeoutError.
+func (t *Transport) wrapErr(ctx context.Context, err error) error {
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() && strings.Contains(err.Error(), "TLS handshake timeout") {
+		return TimeoutError{Setting: SettingTLSHandshakeTimeout, EnvVar: env.HTTPTLSHandshakeTimeout, Value: t.TLSHandshakeTimeout, Err: err}
+	}
+	if t.RequestTimeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return TimeoutError{Setting: SettingRequestTimeout, EnvVar: env.HTTPRequestTimeout, Value: t.RequestTimeout, Err: err}
+	}
+	return err
+}
+
+// body cancels the request context once the response body is closed.
+type body struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+	ctx    context.Context
+	t      *Transport
+}
+
+// Read implements the io.Reader interface.
+func (b *body) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		err = b.t.wrapErr(b.ctx, err)
+	}
+	return n, err
+}
+
+// Close implements the io.Closer interface.
+func (b *body) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// TimeoutError indicates one of the configured HTTP timeouts was exceeded.
+type TimeoutError struct {
+	// Setting is the name of the [http] config setting.
+	Setting string
+	// EnvVar is the environment variable that overrides the setting.
+	EnvVar string
+	// Value is the configured timeout.
+	Value time.Duration
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e TimeoutError) Error() string {
+	return fmt.Sprintf("the HTTP %s of %s was exceeded (configure via the [http] section of the CLI config or %s)", e.Setting, e.Value, e.EnvVar)
+}
+
+// Unwrap returns the underlying error.
+func (e TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Timeout implements the net.Error interface.
+func (e TimeoutError) Timeout() bool {
+	return true
+}
+
+// Temporary indicates the error is likely to be transient.
+func (e TimeoutError) Temporary() bool {
+	return true
+}
+
+// parseDuration resolves a duration setting from the environment, then the
+// config file, and finally the given fallback.
+func parseDuration(setting, envVar, envValue, cfgValue string, fallback time.Duration) (time.Duration, error) {
+	value, fromEnv := envValue, true
+	if value == "" {
+		value, fromEnv = cfgValue, false
+	}
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, invalidSettingErr(setting, envVar, value, fromEnv, `a positive duration (e.g. "30s" or "2m")`)
+	}
+	return d, nil
+}
+
+// invalidSettingErr returns an error naming the offending setting.
+func invalidSettingErr(setting, envVar, value string, fromEnv bool, want string) error {
+	source := fmt.Sprintf("the `%s` setting in the [http] section of the CLI config", setting)
+	if fromEnv {
+		source = fmt.Sprintf("the %s environment variable", envVar)
+	}
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("invalid HTTP configuration: %q is not a valid value for %s", value, source),
+		Remediation: fmt.Sprintf("Update %s to %s. Run `fastly config --location` to locate the CLI config.", source, want),
+	}
+}
@@ -0,0 +1,242 @@
+package httpclient_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fastly/cli/pkg/config"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/httpclient"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestParseOpts(t *testing.T) {
+	for _, testcase := range []struct {
+		name                    string
+		env                     config.Environment
+		cfg                     config.HTTP
+		wantError               string
+		wantRemediation         string
+		wantProxy               string
+		wantRequestTimeout      time.Duration
+		wantTLSHandshakeTimeout time.Duration
+		wantMaxIdleConnsPerHost int
+		wantIdleConnTimeout     time.Duration
+	}{
+		{
+			name:                    "defaults",
+			wantRequestTimeout:      httpclient.DefaultRequestTimeout,
+			wantTLSHandshakeTimeout: httpclient.DefaultTLSHandshakeTimeout,
+			wantMaxIdleConnsPerHost: httpclient.DefaultMaxIdleConnsPerHost,
+			wantIdleConnTimeout:     httpclient.DefaultIdleConnTimeout,
+		},
+		{
+			name: "config values",
+			cfg: config.HTTP{
+				ProxyURL:            "http://proxy.example.com:8080",
+				RequestTimeout:      "30s",
+				TLSHandshakeTimeout: "5s",
+				MaxIdleConnsPerHost: 200,
+				IdleConnTimeout:     "30s",
+			},
+			wantProxy:               "http://proxy.example.com:8080",
+			wantRequestTimeout:      30 * time.Second,
+			wantTLSHandshakeTimeout: 5 * time.Second,
+			wantMaxIdleConnsPerHost: 200,
+			wantIdleConnTimeout:     30 * time.Second,
+		},
+		{
+			name: "environment overrides config",
+			env: config.Environment{
+				HTTPProxy:          "http://env.example.com:3128",
+				HTTPRequestTimeout: "1m",
+			},
+			cfg: config.HTTP{
+				ProxyURL:       "http://proxy.example.com:8080",
+				RequestTimeout: "30s",
+			},
+			wantProxy:               "http://env.example.com:3128",
+			wantRequestTimeout:      time.Minute,
+			wantTLSHandshakeTimeout: httpclient.DefaultTLSHandshakeTimeout,
+			wantMaxIdleConnsPerHost: httpclient.DefaultMaxIdleConnsPerHost,
+			wantIdleConnTimeout:     httpclient.DefaultIdleConnTimeout,
+		},
+		{
+			name:            "invalid config timeout",
+			cfg:             config.HTTP{RequestTimeout: "soon"},
+			wantError:       "the `request_timeout` setting in the [http] section",
+			wantRemediation: "request_timeout",
+		},
+		{
+			name:            "invalid env timeout",
+			env:             config.Environment{HTTPTLSHandshakeTimeout: "-1s"},
+			wantError:       "FASTLY_HTTP_TLS_HANDSHAKE_TIMEOUT",
+			wantRemediation: "FASTLY_HTTP_TLS_HANDSHAKE_TIMEOUT",
+		},
+		{
+			name:            "invalid idle connections",
+			cfg:             config.HTTP{MaxIdleConnsPerHost: -1},
+			wantError:       "the `max_idle_conns_per_host` setting in the [http] section",
+			wantRemediation: "a positive number of connections",
+		},
+		{
+			name:            "invalid idle timeout",
+			cfg:             config.HTTP{IdleConnTimeout: "0s"},
+			wantError:       "the `idle_conn_timeout` setting in the [http] section",
+			wantRemediation: "idle_conn_timeout",
+		},
+		{
+			name:            "invalid proxy",
+			cfg:             config.HTTP{ProxyURL: "proxy.example.com"},
+			wantError:       "the `proxy_url` setting in the [http] section",
+			wantRemediation: "proxy_url",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			opts, err := httpclient.ParseOpts(testcase.env, testcase.cfg)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if err != nil {
+				var re fsterr.RemediationError
+				if !errors.As(err, &re) {
+					t.Fatalf("want RemediationError, have %T", err)
+				}
+				testutil.AssertStringContains(t, re.Remediation, testcase.wantRemediation)
+				return
+			}
+			var proxy string
+			if opts.ProxyURL != nil {
+				proxy = opts.ProxyURL.String()
+			}
+			testutil.AssertString(t, testcase.wantProxy, proxy)
+			testutil.AssertEqual(t, testcase.wantRequestTimeout, opts.RequestTimeout)
+			testutil.AssertEqual(t, testcase.wantTLSHandshakeTimeout, opts.TLSHandshakeTimeout)
+			testutil.AssertEqual(t, testcase.wantMaxIdleConnsPerHost, opts.MaxIdleConnsPerHost)
+			testutil.AssertEqual(t, testcase.wantIdleConnTimeout, opts.IdleConnTimeout)
+		})
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	client := httpclient.New(httpclient.Opts{
+		RequestTimeout:      50 * time.Millisecond,
+		TLSHandshakeTimeout: time.Second,
+	})
+
+	_, err := client.Get(ts.URL)
+	assertTimeout(t, err, "request_timeout", "50ms")
+}
+
+func TestContextCancellation(t *testing.T) {
+	started := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			close(started)
+			select {
+			case <-time.After(5 * time.Second):
+			case <-r.Context().Done():
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := httpclient.New(httpclient.Opts{
+		Context:        ctx,
+		RequestTimeout: 10 * time.Second,
+	})
+
+	go func() {
+		<-started
+		cancel()
+	}()
+	_, err := client.Get(ts.URL + "/slow")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("want %v, have %v", context.Canceled, err)
+	}
+
+	// Requests made once the context is done (e.g. clean-up) are still sent.
+	resp, err := client.Get(ts.URL + "/cleanup")
+	testutil.AssertNoError(t, err)
+	_ = resp.Body.Close()
+	testutil.AssertEqual(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestTLSHandshakeTimeout(t *testing.T) {
+	// The listener accepts connections but never completes a TLS handshake.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				<-done
+				_ = conn.Close()
+			}()
+		}
+	}()
+
+	client := httpclient.New(httpclient.Opts{
+		RequestTimeout:      5 * time.Second,
+		TLSHandshakeTimeout: 50 * time.Millisecond,
+	})
+
+	_, err = client.Get("https://" + l.Addr().String())
+	assertTimeout(t, err, "tls_handshake_timeout", "50ms")
+}
+
+func TestProxyURL(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	opts, err := httpclient.ParseOpts(config.Environment{HTTPProxy: proxy.URL}, config.HTTP{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := httpclient.New(opts).Get("http://api.example.invalid/service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	testutil.AssertString(t, "http://api.example.invalid/service", proxied)
+}
+
+func assertTimeout(t *testing.T, err error, setting, value string) {
+	t.Helper()
+	var te httpclient.TimeoutError
+	if !errors.As(err, &te) {
+		t.Fatalf("want TimeoutError, have %T: %v", err, err)
+	}
+	testutil.AssertString(t, setting, te.Setting)
+	testutil.AssertStringContains(t, err.Error(), setting)
+	testutil.AssertStringContains(t, err.Error(), value)
+}
@@ -0,0 +1,201 @@
+package httpclient
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// DefaultMemoMaxBodySize is the largest response body memoized by Memo.
+const DefaultMemoMaxBodySize = 1 << 20
+
+// Memo is a http.RoundTripper that memoizes GET and HEAD responses for the
+// lifetime of a single CLI invocation.
+//
+// Commands often request the same resource via different code paths (e.g.
+// resolving a service name to an ID, then fetching the service details). Memo
+// ensures each unique request is sent once: concurrent identical requests
+// share a single round trip, and later identical requests reuse the response.
+//
+// The response body is streamed to the first caller, and copied as it's read
+// so it can be reused. Concurrent identical requests wait until the first
+// caller has read (or closed) the body. A body larger than MaxBodySize isn't
+// memoized, so a large listing isn't held in memory twice.
+//
+// NOTE: Any other request method (e.g. POST, PUT, DELETE) may modify the
+// resources previously fetched, so it clears all memoized responses.
+type Memo struct {
+	// Base is the underlying http.RoundTripper (http.DefaultTransport if nil).
+	Base http.RoundTripper
+	// MaxBodySize is the largest body memoized (DefaultMemoMaxBodySize if 0).
+	MaxBodySize int64
+
+	mu    sync.Mutex
+	calls map[string]*memoCall
+	out   io.Writer
+}
+
+// memoCall is a request that's either in-flight or complete.
+type memoCall struct {
+	done chan struct{}
+
+	body   []byte
+	err    error
+	header http.Header
+	proto  string
+	status int
+	// memoized is set once the whole body has been copied.
+	memoized bool
+}
+
+// SetOutput enables reporting of memoized responses (e.g. in verbose mode).
+func (m *Memo) SetOutput(w io.Writer) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.out = w
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (m *Memo) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := m.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		m.mu.Lock()
+		m.calls = nil
+		m.mu.Unlock()
+		return base.RoundTrip(req)
+	}
+
+	key := memoKey(req)
+	m.mu.Lock()
+	if c, ok := m.calls[key]; ok {
+		out := m.out
+		m.mu.Unlock()
+		<-c.done
+		if c.err != nil {
+			return nil, c.err
+		}
+		if !c.memoized {
+			return base.RoundTrip(req)
+		}
+		if out != nil {
+			fmt.Fprintf(out, "Reusing API response: %s %s\n", req.Method, req.URL.Path)
+		}
+		return c.response(req), nil
+	}
+	c := &memoCall{done: make(chan struct{})}
+	if m.calls == nil {
+		m.calls = make(map[string]*memoCall)
+	}
+	m.calls[key] = c
+	m.mu.Unlock()
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		c.err = err
+		m.complete(key, c, false)
+		return nil, err
+	}
+	c.header = resp.Header.Clone()
+	c.proto = resp.Proto
+	c.status = resp.StatusCode
+
+	limit := m.MaxBodySize
+	if limit <= 0 {
+		limit = DefaultMemoMaxBodySize
+	}
+	if resp.ContentLength > limit {
+		m.complete(key, c, false)
+		return resp, nil
+	}
+	resp.Body = &memoBody{ReadCloser: resp.Body, c: c, key: key, limit: limit, m: m}
+	return resp, nil
+}
+
+// complete releases the callers waiting on c. The response is reused by later
+// requests only if it was successful and its body was memoized.
+func (m *Memo) complete(key string, c *memoCall, memoized bool) {
+	c.memoized = memoized
+	close(c.done)
+	if memoized && c.status >= 200 && c.status <= 299 {
+		return
+	}
+	m.mu.Lock()
+	if m.calls[key] == c {
+		delete(m.calls, key)
+	}
+	m.mu.Unlock()
+}
+
+// memoBody copies the response body as it's read, completing the memoCall
+// once the body has been read to the end or closed.
+type memoBody struct {
+	io.ReadCloser
+	buf   bytes.Buffer
+	c     *memoCall
+	done  bool
+	key   string
+	limit int64
+	m     *Memo
+	// overflow is set once the body exceeds the limit.
+	overflow bool
+}
+
+// Read implements the io.Reader interface.
+func (b *memoBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if !b.overflow {
+		if int64(b.buf.Len()+n) > b.limit {
+			b.overflow = true
+			b.buf = bytes.Buffer{}
+		} else {
+			b.buf.Write(p[:n])
+		}
+	}
+	if err == io.EOF {
+		b.finish(!b.overflow)
+	}
+	return n, err
+}
+
+// Close implements the io.Closer interface.
+func (b *memoBody) Close() error {
+	b.finish(false)
+	return b.ReadCloser.Close()
+}
+
+func (b *memoBody) finish(memoized bool) {
+	if b.done {
+		return
+	}
+	b.done = true
+	if memoized {
+		b.c.body = b.buf.Bytes()
+	}
+	b.m.complete(b.key, b.c, memoized)
+}
+
+// response returns a copy of the memoized response.
+func (c *memoCall) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Body:          io.NopCloser(bytes.NewReader(c.body)),
+		ContentLength: int64(len(c.body)),
+		Header:        c.header.Clone(),
+		Proto:         c.proto,
+		Request:       req,
+		Status:        fmt.Sprintf("%d %s", c.status, http.StatusText(c.status)),
+		StatusCode:    c.status,
+	}
+}
+
+// memoKey identifies a request by its method, URL and credentials.
+func memoKey(req *http.Request) string {
+	return req.Method + " " + req.URL.String() + " " + req.Header.Get("Fastly-Key") + " " + req.Header.Get("Authorization")
+}
@@ -0,0 +1,213 @@
+package httpclient_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/fastly/cli/pkg/httpclient"
+)
+
+// countingTransport responds to every request with its URL path, counting the
+// round trips for each method and path. Responses are held until release is
+// closed (if set) so concurrent requests overlap.
+type countingTransport struct {
+	mu      sync.Mutex
+	counts  map[string]int
+	release chan struct{}
+	status  int
+	total   atomic.Int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.total.Add(1)
+	t.mu.Lock()
+	if t.counts == nil {
+		t.counts = make(map[string]int)
+	}
+	t.counts[req.Method+" "+req.URL.Path]++
+	t.mu.Unlock()
+	if t.release != nil {
+		<-t.release
+	}
+	status := t.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return &http.Response{
+		Body:       io.NopCloser(strings.NewReader(req.URL.Path)),
+		Header:     http.Header{"Content-Type": []string{"text/plain"}},
+		Request:    req,
+		StatusCode: status,
+	}, nil
+}
+
+func (t *countingTransport) count(key string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.counts[key]
+}
+
+func get(t *testing.T, client *http.Client, method, url string) string {
+	t.Helper()
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Error(err)
+		return ""
+	}
+	req.Header.Set("Fastly-Key", "123")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Error(err)
+		return ""
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Error(err)
+	}
+	return string(body)
+}
+
+func TestMemoConcurrent(t *testing.T) {
+	ct := &countingTransport{release: make(chan struct{})}
+	client := &http.Client{Transport: &httpclient.Memo{Base: ct}}
+
+	paths := []string{"/service/123", "/service/123/details", "/service/123/version"}
+	const callers = 10
+
+	var wg sync.WaitGroup
+	bodies := make([][]string, len(paths))
+	for i, p := range paths {
+		bodies[i] = make([]string, callers)
+		for j := 0; j < callers; j++ {
+			wg.Add(1)
+			go func(i, j int, p string) {
+				defer wg.Done()
+				bodies[i][j] = get(t, client, http.MethodGet, "https://api.fastly.com"+p)
+			}(i, j, p)
+		}
+	}
+	// NOTE: Wait for the first request of each path to reach the transport so
+	// the remaining callers are waiting on the in-flight round trip.
+	for int(ct.total.Load()) < len(paths) {
+		runtime.Gosched()
+	}
+	close(ct.release)
+	wg.Wait()
+
+	for i, p := range paths {
+		if n := ct.count("GET " + p); n != 1 {
+			t.Errorf("%s: want 1 round trip, have %d", p, n)
+		}
+		for j := range bodies[i] {
+			if bodies[i][j] != p {
+				t.Errorf("%s: caller %d received %q", p, j, bodies[i][j])
+			}
+		}
+	}
+
+	// Later requests reuse the response.
+	get(t, client, http.MethodGet, "https://api.fastly.com/service/123")
+	if n := ct.count("GET /service/123"); n != 1 {
+		t.Errorf("want 1 round trip, have %d", n)
+	}
+	// The query string is part of the request's identity.
+	get(t, client, http.MethodGet, "https://api.fastly.com/service/123?page=2")
+	if n := ct.count("GET /service/123"); n != 2 {
+		t.Errorf("want 2 round trips, have %d", n)
+	}
+}
+
+func TestMemoMutation(t *testing.T) {
+	ct := &countingTransport{}
+	client := &http.Client{Transport: &httpclient.Memo{Base: ct}}
+
+	get(t, client, http.MethodGet, "https://api.fastly.com/service/123/version")
+	get(t, client, http.MethodPut, "https://api.fastly.com/service/123/version/1/activate")
+	get(t, client, http.MethodPut, "https://api.fastly.com/service/123/version/1/activate")
+	get(t, client, http.MethodGet, "https://api.fastly.com/service/123/version")
+	get(t, client, http.MethodGet, "https://api.fastly.com/service/123/version")
+
+	if n := ct.count("PUT /service/123/version/1/activate"); n != 2 {
+		t.Errorf("mutating requests: want 2 round trips, have %d", n)
+	}
+	if n := ct.count("GET /service/123/version"); n != 2 {
+		t.Errorf("want the mutation to clear memoized responses (2 round trips), have %d", n)
+	}
+}
+
+func TestMemoUnsuccessful(t *testing.T) {
+	ct := &countingTransport{status: http.StatusInternalServerError}
+	client := &http.Client{Transport: &httpclient.Memo{Base: ct}}
+
+	get(t, client, http.MethodGet, "https://api.fastly.com/service/123")
+	get(t, client, http.MethodGet, "https://api.fastly.com/service/123")
+	if n := ct.count("GET /service/123"); n != 2 {
+		t.Errorf("want unsuccessful responses to be retried (2 round trips), have %d", n)
+	}
+}
+
+func TestMemoOutput(t *testing.T) {
+	var buf bytes.Buffer
+	memo := &httpclient.Memo{Base: &countingTransport{}}
+	memo.SetOutput(&buf)
+	client := &http.Client{Transport: memo}
+
+	get(t, client, http.MethodGet, "https://api.fastly.com/service/123")
+	get(t, client, http.MethodGet, "https://api.fastly.com/service/123")
+	if want := "Reusing API response: GET /service/123\n"; buf.String() != want {
+		t.Errorf("want %q, have %q", want, buf.String())
+	}
+}
+
+// TestMemoStream validates the response body is returned before it has been
+// received, and that large bodies aren't memoized.
+func TestMemoStream(t *testing.T) {
+	pr, pw := io.Pipe()
+	var calls int
+	memo := &httpclient.Memo{
+		Base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			body := io.ReadCloser(pr)
+			if calls > 1 {
+				body = io.NopCloser(strings.NewReader("0123456789"))
+			}
+			return &http.Response{Body: body, Request: req, StatusCode: http.StatusOK}, nil
+		}),
+		MaxBodySize: 5,
+	}
+	client := &http.Client{Transport: memo}
+
+	resp, err := client.Get("https://api.fastly.com/service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_, _ = pw.Write([]byte("0123456789"))
+		_ = pw.Close()
+	}()
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil || string(body) != "0123456789" {
+		t.Fatalf("want the streamed body, have %q (%v)", body, err)
+	}
+
+	if have := get(t, client, http.MethodGet, "https://api.fastly.com/service"); have != "0123456789" {
+		t.Errorf("want the body, have %q", have)
+	}
+	if calls != 2 {
+		t.Errorf("want a body larger than MaxBodySize to be requested again (2 round trips), have %d", calls)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
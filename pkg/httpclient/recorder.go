@@ -0,0 +1,100 @@
+package httpclient
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// RequestIDHeaders are the response headers checked for a request ID.
+var RequestIDHeaders = []string{"Fastly-Request-Id", "X-Request-Id"}
+
+// RequestID returns the request ID from the response headers (if any).
+func RequestID(h http.Header) string {
+	for _, k := range RequestIDHeaders {
+		if v := h.Get(k); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// MaxRecordedBody is the size of the largest response body recorded.
+const MaxRecordedBody = 64 * 1024
+
+// Recorder is a http.RoundTripper that records the most recent unsuccessful
+// response so it can be attached to the resulting error. The body of a 422
+// Unprocessable Entity response is also recorded, as it describes the fields
+// the API rejected (see fsterr.ParseValidationError).
+//
+// NOTE: The Fastly API client doesn't expose the response headers of a failed
+// request, which is where the API's request ID is found.
+type Recorder struct {
+	// Base is the underlying http.RoundTripper (http.DefaultTransport if nil).
+	Base http.RoundTripper
+
+	mu   sync.Mutex
+	last *fsterr.APIResponse
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := r.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err == nil && resp.StatusCode >= http.StatusBadRequest {
+		last := &fsterr.APIResponse{
+			Method:     req.Method,
+			Path:       req.URL.Path,
+			RequestID:  RequestID(resp.Header),
+			StatusCode: resp.StatusCode,
+		}
+		if resp.StatusCode == http.StatusUnprocessableEntity && resp.Body != nil {
+			// NOTE: The body is read ahead of the API client, so it's replaced
+			// with a reader that returns it again.
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, MaxRecordedBody))
+			last.Body = string(body)
+			resp.Body = replayedBody{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		}
+		r.mu.Lock()
+		r.last = last
+		r.mu.Unlock()
+	}
+	return resp, err
+}
+
+// Annotate wraps a Fastly API error with the recorded response that caused it,
+// so the request ID, status and endpoint can be displayed to the user.
+//
+// Errors that weren't caused by an API response (e.g. network failures) are
+// returned unmodified.
+func (r *Recorder) Annotate(err error) error {
+	if r == nil || err == nil {
+		return err
+	}
+	var httpError *fastly.HTTPError
+	if !errors.As(err, &httpError) {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.last == nil || r.last.StatusCode != httpError.StatusCode {
+		return err
+	}
+	return fsterr.APIError{Err: err, Response: *r.last}
+}
+
+// replayedBody pairs the recorded body, followed by anything not recorded,
+// with the original body closer.
+type replayedBody struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,113 @@
+package httpclient_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/httpclient"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestRecorder(t *testing.T) {
+	for _, testcase := range []struct {
+		name         string
+		handler      http.HandlerFunc
+		wantResponse *fsterr.APIResponse
+	}{
+		{
+			name: "request ID",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Fastly-Request-Id", "abc123")
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"msg":"Record not found","detail":"Cannot find service '123'"}`))
+			},
+			wantResponse: &fsterr.APIResponse{
+				Method:     http.MethodGet,
+				Path:       "/service/123/details",
+				RequestID:  "abc123",
+				StatusCode: http.StatusNotFound,
+			},
+		},
+		{
+			name: "no request ID",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			wantResponse: &fsterr.APIResponse{
+				Method:     http.MethodGet,
+				Path:       "/service/123/details",
+				StatusCode: http.StatusInternalServerError,
+			},
+		},
+		{
+			name: "validation failure",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				_, _ = w.Write([]byte(`{"msg":"Record invalid","errors":{"name":["can't be blank"]}}`))
+			},
+			wantResponse: &fsterr.APIResponse{
+				Method:     http.MethodGet,
+				Path:       "/service/123/details",
+				StatusCode: http.StatusUnprocessableEntity,
+				Body:       `{"msg":"Record invalid","errors":{"name":["can't be blank"]}}`,
+			},
+		},
+		{
+			name: "network failure",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				// Drop the connection without sending a response.
+				conn, _, err := w.(http.Hijacker).Hijack()
+				if err == nil {
+					_ = conn.Close()
+				}
+			},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			ts := httptest.NewServer(testcase.handler)
+			defer ts.Close()
+
+			recorder := &httpclient.Recorder{}
+			client, err := fastly.NewClientForEndpoint("123", ts.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			client.HTTPClient = &http.Client{Transport: recorder}
+
+			_, err = client.GetServiceDetails(&fastly.GetServiceInput{ServiceID: "123"})
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			err = recorder.Annotate(err)
+			var apiError fsterr.APIError
+			if testcase.wantResponse == nil {
+				if errors.As(err, &apiError) {
+					t.Fatalf("unexpected API response: %#v", apiError.Response)
+				}
+				testutil.AssertEqual(t, (*fsterr.APIResponse)(nil), fsterr.Deduce(err).API)
+				return
+			}
+			if !errors.As(err, &apiError) {
+				t.Fatalf("expected an APIError, have %T", err)
+			}
+			testutil.AssertEqual(t, *testcase.wantResponse, apiError.Response)
+			testutil.AssertEqual(t, testcase.wantResponse, fsterr.Deduce(err).API)
+		})
+	}
+}
+
+func TestRecorderNil(t *testing.T) {
+	var recorder *httpclient.Recorder
+	err := &fastly.HTTPError{StatusCode: http.StatusNotFound}
+	if have := recorder.Annotate(err); have != err {
+		t.Fatalf("want %v, have %v", err, have)
+	}
+}
@@ -0,0 +1,89 @@
+package i18n_test
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/i18n"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// TestEnglishCatalog keeps locales/en.toml (the reference for translators) in
+// sync with the registered messages. Run the test with -update to regenerate it.
+func TestEnglishCatalog(t *testing.T) {
+	messages := i18n.Messages()
+	ids := make([]string, 0, len(messages))
+	for id := range messages {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var b strings.Builder
+	b.WriteString("# The English text of every message, generated by `go test ./pkg/i18n -update`.\n\n")
+	for _, id := range ids {
+		fmt.Fprintf(&b, "%q = %s\n", id, strconv.Quote(messages[id]))
+	}
+	testutil.AssertGolden(t, filepath.Join("locales", "en.toml"), b.String())
+
+	catalog, err := i18n.Catalog(i18n.DefaultLocale)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, messages, catalog)
+}
+
+// TestCatalogs validates every catalog only translates registered messages.
+func TestCatalogs(t *testing.T) {
+	messages := i18n.Messages()
+	for _, locale := range i18n.Locales() {
+		t.Run(locale, func(t *testing.T) {
+			catalog, err := i18n.Catalog(locale)
+			testutil.AssertNoError(t, err)
+			for id := range catalog {
+				if _, ok := messages[id]; !ok {
+					t.Errorf("%s isn't a registered message ID", id)
+				}
+			}
+		})
+	}
+}
+
+func TestLocale(t *testing.T) {
+	testutil.AssertNoError(t, i18n.SetLocale("fr_FR.UTF-8"))
+	defer func() {
+		_ = i18n.SetLocale("")
+	}()
+
+	var buf bytes.Buffer
+	fsterr.RemediationError{
+		Inner:       fmt.Errorf("connection refused"),
+		Remediation: fsterr.NetworkRemediation,
+		API:         &fsterr.APIResponse{RequestID: "abc123"},
+		Code:        fsterr.CodeNetwork,
+	}.Print(&buf)
+	out := buf.String()
+	testutil.AssertStringContains(t, out, "ERREUR: connection refused.")
+	testutil.AssertStringContains(t, out, "ID de requête Fastly : abc123")
+	testutil.AssertStringContains(t, out, "Cette erreur peut être causée par des problèmes réseau passagers.")
+	testutil.AssertStringContains(t, out, "Pour plus d'informations, exécutez `fastly explain FASTLY_ERR_NETWORK`.")
+
+	// Untranslated messages are displayed in English.
+	buf.Reset()
+	fsterr.RemediationError{Remediation: fsterr.AuthRemediation}.Print(&buf)
+	testutil.AssertString(t, fsterr.AuthRemediation+"\n", buf.String())
+
+	// The placeholders of a remediation template can be reordered.
+	testutil.AssertStringContains(t,
+		fsterr.RenderRemediation(fsterr.RemediationPackageSize, map[string]any{"Size": "120.0 MB", "Limit": "100.0 MB"}),
+		"La limite est de 100.0 MB, mais le paquet fait 120.0 MB.",
+	)
+
+	buf.Reset()
+	text.NextSteps(&buf, []text.NextStep{{Label: "Deploy", Command: "fastly compute deploy"}})
+	testutil.AssertStringContains(t, buf.String(), "Prochaines étapes :")
+}
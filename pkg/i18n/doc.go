@@ -0,0 +1,3 @@
+// Package i18n translates the CLI's user-facing messages, using the catalog
+// of the selected locale with the English text as the fallback.
+package i18n
@@ -0,0 +1,232 @@
+package i18n
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	toml "github.com/pelletier/go-toml"
+)
+
+// DefaultLocale is the locale messages are registered in, which is used for
+// any message a catalog doesn't translate.
+const DefaultLocale = "en"
+
+// ErrUnknownLocale means there's no catalog for the locale.
+var ErrUnknownLocale = errors.New("unknown locale")
+
+//go:embed locales/*.toml
+var locales embed.FS
+
+var (
+	mu sync.RWMutex
+	// english is the registered English text of each message, by ID.
+	english = make(map[string]string)
+	// ids is the ID of each registered English text.
+	ids = make(map[string]string)
+	// locale is the selected locale.
+	locale = DefaultLocale
+	// translations is the catalog of the selected locale, by ID.
+	translations map[string]string
+)
+
+// Register records the English text of a user-facing message under its ID,
+// and returns the English text. This lets package level strings register
+// themselves where they're declared, e.g.
+//
+//	var ExampleRemediation = i18n.Register("remediation.example", "Try again.")
+//
+// The string is then translated where it's displayed (see Localize and
+// Sprintf). Register panics if the ID, or the English text, is already
+// registered for another message (the text must identify the message).
+func Register(id, text string) string {
+	mu.Lock()
+	defer mu.Unlock()
+	if existing, ok := english[id]; ok && existing != text {
+		panic(fmt.Sprintf("i18n: message ID %q is already registered", id))
+	}
+	if existing, ok := ids[text]; ok && existing != id {
+		panic(fmt.Sprintf("i18n: the text of message %q is already registered as %q", id, existing))
+	}
+	english[id] = text
+	ids[text] = id
+	return text
+}
+
+// ID returns the ID of the message registered with the English text, or an
+// empty string if there isn't one.
+func ID(text string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return ids[text]
+}
+
+// Messages returns the English text of every registered message, by ID.
+func Messages() map[string]string {
+	mu.RLock()
+	defer mu.RUnlock()
+	m := make(map[string]string, len(english))
+	for id, text := range english {
+		m[id] = text
+	}
+	return m
+}
+
+// Locales returns the locales with an embedded catalog (e.g. "en", "fr").
+func Locales() []string {
+	entries, _ := locales.ReadDir("locales")
+	var names []string
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), path.Ext(e.Name())))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Catalog returns the translations of the embedded catalog for the locale, by
+// message ID.
+//
+// A catalog is a TOML file of message IDs and their translation, either with
+// quoted keys ("remediation.auth" = "...") or as tables ([remediation] auth =
+// "..."). Translations can reorder format arguments with explicit argument
+// indexes (e.g. "%[2]s ... %[1]s").
+func Catalog(name string) (map[string]string, error) {
+	data, err := locales.ReadFile(path.Join("locales", name+".toml"))
+	if err != nil {
+		return nil, fmt.Errorf("%w '%s'", ErrUnknownLocale, name)
+	}
+	tree, err := toml.LoadBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the '%s' catalog: %w", name, err)
+	}
+	catalog := make(map[string]string)
+	if err := flatten(catalog, "", tree.ToMap()); err != nil {
+		return nil, fmt.Errorf("failed to parse the '%s' catalog: %w", name, err)
+	}
+	return catalog, nil
+}
+
+// flatten adds the values of the (possibly nested) TOML tables to catalog,
+// keyed by their dotted path.
+func flatten(catalog map[string]string, prefix string, m map[string]any) error {
+	for k, v := range m {
+		key := prefix + k
+		switch v := v.(type) {
+		case string:
+			catalog[key] = v
+		case map[string]any:
+			if err := flatten(catalog, key+".", v); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("the value of '%s' isn't a string", key)
+		}
+	}
+	return nil
+}
+
+// SetLocale selects the locale messages are displayed in. An empty name
+// selects DefaultLocale. POSIX locale names are accepted (e.g. "fr_FR.UTF-8"),
+// falling back to the language's catalog ("fr") if there's no catalog for the
+// region.
+//
+// An error is returned for a locale without a catalog, in which case
+// DefaultLocale is selected.
+func SetLocale(name string) error {
+	catalog, selected, err := loadLocale(name)
+
+	mu.Lock()
+	defer mu.Unlock()
+	locale, translations = selected, catalog
+	return err
+}
+
+// loadLocale returns the catalog of the locale and its name.
+func loadLocale(name string) (catalog map[string]string, selected string, err error) {
+	name, _, _ = strings.Cut(name, ".") // e.g. fr_FR.UTF-8
+	name = strings.ReplaceAll(name, "_", "-")
+	if name == "" || strings.EqualFold(name, DefaultLocale) {
+		return nil, DefaultLocale, nil
+	}
+	candidates := []string{name}
+	if language, _, ok := strings.Cut(name, "-"); ok {
+		candidates = append(candidates, language)
+	}
+	for _, c := range candidates {
+		c = strings.ToLower(c)
+		if c == DefaultLocale {
+			return nil, DefaultLocale, nil
+		}
+		catalog, err := Catalog(c)
+		if err == nil {
+			return catalog, c, nil
+		}
+		if !errors.Is(err, ErrUnknownLocale) {
+			return nil, DefaultLocale, err
+		}
+	}
+	return nil, DefaultLocale, fmt.Errorf("%w '%s'", ErrUnknownLocale, name)
+}
+
+// Locale returns the selected locale.
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return locale
+}
+
+// Text returns the message with the ID in the selected locale, falling back to
+// its English text if the locale's catalog doesn't translate it. An
+// unregistered ID is returned as is.
+func Text(id string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if t, ok := translations[id]; ok && t != "" {
+		return t
+	}
+	if text, ok := english[id]; ok {
+		return text
+	}
+	return id
+}
+
+// Localize returns the registered English text in the selected locale. Text
+// that isn't registered (e.g. an error message built at runtime) is returned
+// as is.
+func Localize(text string) string {
+	id := ID(text)
+	if id == "" {
+		return text
+	}
+	return Text(id)
+}
+
+// Sprintf formats the registered English format string in the selected
+// locale, like fmt.Sprintf.
+//
+// NOTE: If the translation doesn't consume the arguments like the English
+// format does (e.g. it's missing an argument, or an explicit argument index is
+// out of range) the English format is used instead, so a bad translation can't
+// lose or misplace a value.
+func Sprintf(format string, args ...any) string {
+	want := fmt.Sprintf(format, args...)
+	translated := Localize(format)
+	if translated == format {
+		return want
+	}
+	have := fmt.Sprintf(translated, args...)
+	if badVerbs(have) > badVerbs(want) {
+		return want
+	}
+	return have
+}
+
+// badVerbs counts the formatting errors fmt reports in the output, e.g.
+// "%!s(MISSING)", "%!(EXTRA string=...)" or "%!(BADINDEX)".
+func badVerbs(s string) int {
+	return strings.Count(s, "%!")
+}
@@ -0,0 +1,161 @@
+package i18n
+
+import (
+	"errors"
+	"testing"
+)
+
+// withMessages replaces the registered messages, and the selected locale's
+// translations, for the duration of the test.
+func withMessages(t *testing.T, messages, catalog map[string]string) {
+	t.Helper()
+	mu.Lock()
+	prevEnglish, prevIDs, prevLocale, prevTranslations := english, ids, locale, translations
+	english, ids, locale, translations = make(map[string]string), make(map[string]string), "test", catalog
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		english, ids, locale, translations = prevEnglish, prevIDs, prevLocale, prevTranslations
+	})
+	for id, text := range messages {
+		Register(id, text)
+	}
+}
+
+func TestText(t *testing.T) {
+	withMessages(t, map[string]string{
+		"translated":   "Translated",
+		"untranslated": "Untranslated",
+		"empty":        "Empty",
+	}, map[string]string{
+		"translated": "Traduit",
+		"empty":      "",
+	})
+
+	for _, testcase := range []struct {
+		id   string
+		want string
+	}{
+		{id: "translated", want: "Traduit"},
+		{id: "untranslated", want: "Untranslated"},
+		{id: "empty", want: "Empty"},
+		{id: "unregistered", want: "unregistered"},
+	} {
+		if have := Text(testcase.id); have != testcase.want {
+			t.Errorf("Text(%q): want %q, have %q", testcase.id, testcase.want, have)
+		}
+	}
+
+	for _, testcase := range []struct {
+		text string
+		want string
+	}{
+		{text: "Translated", want: "Traduit"},
+		{text: "Untranslated", want: "Untranslated"},
+		{text: "Not registered", want: "Not registered"},
+	} {
+		if have := Localize(testcase.text); have != testcase.want {
+			t.Errorf("Localize(%q): want %q, have %q", testcase.text, testcase.want, have)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	withMessages(t, map[string]string{"example": "Example"}, nil)
+
+	// Registering the same message again is allowed.
+	if have := Register("example", "Example"); have != "Example" {
+		t.Errorf("want the English text, have %q", have)
+	}
+	for _, testcase := range []struct {
+		name     string
+		id, text string
+	}{
+		{name: "duplicate ID", id: "example", text: "Another example"},
+		{name: "duplicate text", id: "another", text: "Example"},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("want a panic")
+				}
+			}()
+			Register(testcase.id, testcase.text)
+		})
+	}
+}
+
+func TestSprintf(t *testing.T) {
+	withMessages(t, map[string]string{
+		"reordered":  "Copied %s to %s",
+		"missing":    "Deleted %s from %s",
+		"bad-index":  "Moved %s to %s",
+		"untouched":  "Renamed %s to %s",
+		"no-verbs":   "Done",
+		"extra-verb": "Created %s",
+	}, map[string]string{
+		"reordered":  "Vers %[2]s, copié %[1]s",
+		"missing":    "Supprimé %s",
+		"bad-index":  "Déplacé %[3]s vers %[1]s",
+		"no-verbs":   "Terminé",
+		"extra-verb": "Créé %s (%s)",
+	})
+
+	for _, testcase := range []struct {
+		format string
+		want   string
+	}{
+		{format: "Copied %s to %s", want: "Vers b, copié a"},
+		{format: "Deleted %s from %s", want: "Deleted a from b"},
+		{format: "Moved %s to %s", want: "Moved a to b"},
+		{format: "Renamed %s to %s", want: "Renamed a to b"},
+		{format: "Unregistered %s to %s", want: "Unregistered a to b"},
+	} {
+		if have := Sprintf(testcase.format, "a", "b"); have != testcase.want {
+			t.Errorf("Sprintf(%q): want %q, have %q", testcase.format, testcase.want, have)
+		}
+	}
+	if have := Sprintf("Done"); have != "Terminé" {
+		t.Errorf("want the translation without arguments, have %q", have)
+	}
+	if have := Sprintf("Created %s", "a"); have != "Created a" {
+		t.Errorf("want the English text for a translation with an extra verb, have %q", have)
+	}
+}
+
+func TestSetLocale(t *testing.T) {
+	defer func() {
+		_ = SetLocale("")
+	}()
+
+	for _, testcase := range []struct {
+		name        string
+		want        string
+		wantErr     bool
+		wantCatalog bool
+	}{
+		{name: "", want: DefaultLocale},
+		{name: "EN", want: DefaultLocale},
+		{name: "en_GB.UTF-8", want: DefaultLocale},
+		{name: "fr", want: "fr", wantCatalog: true},
+		{name: "fr_FR.UTF-8", want: "fr", wantCatalog: true},
+		{name: "fr-CA", want: "fr", wantCatalog: true},
+		{name: "xx", want: DefaultLocale, wantErr: true},
+		{name: "xx_YY", want: DefaultLocale, wantErr: true},
+	} {
+		err := SetLocale(testcase.name)
+		if testcase.wantErr != errors.Is(err, ErrUnknownLocale) {
+			t.Errorf("SetLocale(%q): unexpected error: %v", testcase.name, err)
+		}
+		if have := Locale(); have != testcase.want {
+			t.Errorf("SetLocale(%q): want locale %q, have %q", testcase.name, testcase.want, have)
+		}
+		mu.RLock()
+		hasCatalog := translations != nil
+		mu.RUnlock()
+		if hasCatalog != testcase.wantCatalog {
+			t.Errorf("SetLocale(%q): want a catalog %t, have %t", testcase.name, testcase.wantCatalog, hasCatalog)
+		}
+	}
+}
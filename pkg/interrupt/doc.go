@@ -0,0 +1,3 @@
+// Package interrupt provides a context that's cancelled when the user
+// interrupts the CLI (e.g. Ctrl-C) so long-running operations can clean up.
+package interrupt
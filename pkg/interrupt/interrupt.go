@@ -0,0 +1,74 @@
+package interrupt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+)
+
+// ExitCode is the exit status used when the user forces the CLI to exit.
+// It's the conventional status for a process terminated by SIGINT.
+const ExitCode = 130
+
+// Signals are the signals that interrupt the CLI.
+var Signals = []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGHUP}
+
+// Exit terminates the process when a second signal is received.
+//
+// NOTE: This is a variable so the test suite can stub it.
+var Exit = os.Exit
+
+// graceful indicates the running command stops by itself when its context is
+// cancelled (see SetGraceful).
+var graceful atomic.Bool
+
+// SetGraceful controls what the first signal does. When v is true the context
+// is cancelled, so the command can stop and clean up, and a second signal is
+// needed to force an exit. Otherwise, as most commands never check their
+// context, the first signal calls Exit.
+func SetGraceful(v bool) {
+	graceful.Store(v)
+}
+
+// NotifyContext returns a copy of parent that's cancelled when the first of
+// Signals is received (see SetGraceful). A second signal calls Exit to force
+// the CLI to stop without waiting for any clean-up to complete.
+//
+// The returned stop function unregisters the signal handling.
+func NotifyContext(parent context.Context, out io.Writer) (ctx context.Context, stop context.CancelFunc) {
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, Signals...)
+	ctx, cancel := Watch(parent, sigs, out)
+	return ctx, func() {
+		signal.Stop(sigs)
+		cancel()
+	}
+}
+
+// Watch returns a copy of parent that's cancelled when the first signal is
+// received from sigs, or calls Exit unless SetGraceful is enabled. A second
+// signal calls Exit.
+func Watch(parent context.Context, sigs <-chan os.Signal, out io.Writer) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	go func() {
+		select {
+		case <-sigs:
+		case <-ctx.Done():
+			return
+		}
+		if !graceful.Load() {
+			fmt.Fprintln(out)
+			Exit(ExitCode)
+			return
+		}
+		fmt.Fprintln(out, "\nInterrupted: cleaning up (press Ctrl-C again to force exit)")
+		cancel()
+		<-sigs
+		Exit(ExitCode)
+	}()
+	return ctx, cancel
+}
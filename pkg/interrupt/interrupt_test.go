@@ -0,0 +1,94 @@
+package interrupt_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fastly/cli/pkg/interrupt"
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/threadsafe"
+)
+
+func TestWatch(t *testing.T) {
+	interrupt.SetGraceful(true)
+	defer interrupt.SetGraceful(false)
+
+	exited := make(chan int, 1)
+	defer func(fn func(int)) {
+		interrupt.Exit = fn
+	}(interrupt.Exit)
+	interrupt.Exit = func(code int) {
+		exited <- code
+	}
+
+	var out threadsafe.Buffer
+	sigs := make(chan os.Signal, 2)
+	ctx, cancel := interrupt.Watch(context.Background(), sigs, &out)
+	defer cancel()
+
+	sigs <- os.Interrupt
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context not cancelled by first signal")
+	}
+	testutil.AssertStringContains(t, out.String(), "press Ctrl-C again to force exit")
+
+	select {
+	case code := <-exited:
+		t.Fatalf("unexpected exit (%d) after first signal", code)
+	default:
+	}
+
+	sigs <- os.Interrupt
+	select {
+	case code := <-exited:
+		testutil.AssertEqual(t, interrupt.ExitCode, code)
+	case <-time.After(5 * time.Second):
+		t.Fatal("second signal didn't force exit")
+	}
+}
+
+func TestWatchNotGraceful(t *testing.T) {
+	exited := make(chan int, 1)
+	defer func(fn func(int)) {
+		interrupt.Exit = fn
+	}(interrupt.Exit)
+	interrupt.Exit = func(code int) {
+		exited <- code
+	}
+
+	var out threadsafe.Buffer
+	sigs := make(chan os.Signal, 2)
+	ctx, cancel := interrupt.Watch(context.Background(), sigs, &out)
+	defer cancel()
+
+	sigs <- os.Interrupt
+	select {
+	case code := <-exited:
+		testutil.AssertEqual(t, interrupt.ExitCode, code)
+	case <-time.After(5 * time.Second):
+		t.Fatal("first signal didn't exit")
+	}
+	if ctx.Err() != nil {
+		t.Error("context cancelled by a signal that exited")
+	}
+	if strings.Contains(out.String(), "cleaning up") {
+		t.Errorf("unexpected clean-up message: %q", out.String())
+	}
+}
+
+func TestWatchParentCancelled(t *testing.T) {
+	var out bytes.Buffer
+	parent, cancelParent := context.WithCancel(context.Background())
+	ctx, cancel := interrupt.Watch(parent, make(chan os.Signal), &out)
+	defer cancel()
+
+	cancelParent()
+	<-ctx.Done()
+	testutil.AssertString(t, "", out.String())
+}
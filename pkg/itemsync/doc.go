@@ -0,0 +1,4 @@
+// Package itemsync calculates and displays the changes required to bring a
+// remote key/value resource (e.g. an edge dictionary or config store) in line
+// with a local source of truth, and deletes the items matching a pattern.
+package itemsync
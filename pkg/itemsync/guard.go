@@ -0,0 +1,74 @@
+package itemsync
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fastly/kingpin"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// DefaultMassDeletionThreshold is the percentage of the remote items a sync
+// can delete without --allow-mass-deletion.
+const DefaultMassDeletionThreshold = 50
+
+// MassDeletionFlags are the flags that stop a sync deleting most of the remote
+// items, which is more likely to be a mistake (e.g. syncing with the wrong
+// file) than a deliberate attempt to empty the resource.
+type MassDeletionFlags struct {
+	allow     bool
+	noun      string
+	threshold argparser.OptionalInt
+}
+
+// NewMassDeletionFlags registers the --allow-mass-deletion and
+// --mass-deletion-threshold flags on cmd. The noun is what's deleted (e.g.
+// 'key' or 'entry').
+func NewMassDeletionFlags(cmd *kingpin.CmdClause, noun string) *MassDeletionFlags {
+	f := &MassDeletionFlags{noun: noun}
+	cmd.Flag("allow-mass-deletion", fmt.Sprintf("Allow the sync to delete more than the --mass-deletion-threshold of the existing %s", plural(noun, 2))).BoolVar(&f.allow)
+	cmd.Flag("mass-deletion-threshold", fmt.Sprintf("Percentage of the existing %s the sync can delete without --allow-mass-deletion (default %d)", plural(noun, 2), DefaultMassDeletionThreshold)).Action(f.threshold.Set).IntVar(&f.threshold.Value)
+	return f
+}
+
+// Check returns an error if deleting the keys would remove more than the
+// threshold percentage of the remote items, unless --allow-mass-deletion is
+// set. The error displays the counts and a sample of the keys.
+//
+// The threshold is set by --mass-deletion-threshold, otherwise by config (the
+// mass_deletion_threshold in the config file) if it's set, otherwise it's
+// DefaultMassDeletionThreshold.
+func (f *MassDeletionFlags) Check(deletes []string, remote, config int) error {
+	threshold := DefaultMassDeletionThreshold
+	switch {
+	case f.threshold.WasSet:
+		if f.threshold.Value < 0 || f.threshold.Value > 100 {
+			return fsterr.RemediationError{
+				Inner:       fmt.Errorf("invalid --mass-deletion-threshold: %d", f.threshold.Value),
+				Remediation: "Set --mass-deletion-threshold to a percentage between 0 and 100.",
+			}
+		}
+		threshold = f.threshold.Value
+	case config > 0 && config <= 100:
+		threshold = config
+	}
+
+	if f.allow || len(deletes) == 0 || len(deletes)*100 <= threshold*remote {
+		return nil
+	}
+
+	sample := deletes
+	if len(sample) > SampleSize {
+		sample = sample[:SampleSize]
+	}
+	msg := fmt.Sprintf("the sync would delete %d of the %d existing %s (%d%%), more than the %d%% threshold:\n\t%s", len(deletes), remote, plural(f.noun, remote), len(deletes)*100/remote, threshold, strings.Join(sample, "\n\t"))
+	if n := len(deletes) - len(sample); n > 0 {
+		msg += fmt.Sprintf("\n\t(and %d more)", n)
+	}
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("%s", msg),
+		Remediation: fmt.Sprintf("Check the file is the one you meant to sync. To delete the %s anyway, pass --allow-mass-deletion, or raise the threshold with --mass-deletion-threshold (or mass_deletion_threshold in the [cli] section of the config file).", plural(f.noun, 2)),
+	}
+}
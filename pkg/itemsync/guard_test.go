@@ -0,0 +1,99 @@
+package itemsync_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/fastly/kingpin"
+
+	"github.com/fastly/cli/pkg/itemsync"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestMassDeletionCheck(t *testing.T) {
+	keys := func(n int) []string {
+		var k []string
+		for i := 0; i < n; i++ {
+			k = append(k, fmt.Sprintf("key-%02d", i))
+		}
+		return k
+	}
+
+	for _, testcase := range []struct {
+		name      string
+		args      string
+		deletes   int
+		remote    int
+		config    int
+		wantError string
+	}{
+		{
+			name:    "just below the default threshold",
+			deletes: 5,
+			remote:  10,
+		},
+		{
+			name:      "just above the default threshold",
+			deletes:   6,
+			remote:    10,
+			wantError: "the sync would delete 6 of the 10 existing items (60%), more than the 50% threshold:\n\tkey-00\n\tkey-01",
+		},
+		{
+			name:    "override",
+			args:    "--allow-mass-deletion",
+			deletes: 10,
+			remote:  10,
+		},
+		{
+			name:      "threshold flag",
+			args:      "--mass-deletion-threshold 10",
+			deletes:   2,
+			remote:    10,
+			wantError: "more than the 10% threshold",
+		},
+		{
+			name:    "threshold flag over config",
+			args:    "--mass-deletion-threshold 80",
+			deletes: 8,
+			remote:  10,
+			config:  10,
+		},
+		{
+			name:      "config threshold",
+			deletes:   3,
+			remote:    10,
+			config:    20,
+			wantError: "more than the 20% threshold",
+		},
+		{
+			name:      "zero threshold refuses any deletion",
+			args:      "--mass-deletion-threshold 0",
+			deletes:   1,
+			remote:    100,
+			wantError: "the sync would delete 1 of the 100 existing items (1%)",
+		},
+		{
+			name:      "sample of keys",
+			deletes:   25,
+			remote:    25,
+			wantError: "key-09\n\t(and 15 more)",
+		},
+		{
+			name:      "invalid threshold",
+			args:      "--mass-deletion-threshold 101",
+			wantError: "invalid --mass-deletion-threshold: 101",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			app := kingpin.New("fastly", "")
+			cmd := app.Command("sync", "")
+			flags := itemsync.NewMassDeletionFlags(cmd, "item")
+			if _, err := app.Parse(append([]string{"sync"}, strings.Fields(testcase.args)...)); err != nil {
+				t.Fatal(err)
+			}
+			err := flags.Check(keys(testcase.deletes), testcase.remote, testcase.config)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+		})
+	}
+}
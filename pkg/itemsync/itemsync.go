@@ -0,0 +1,207 @@
+package itemsync
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// Remediation is displayed when the local file can't be parsed.
+const Remediation = "The file must contain either a JSON object of string keys to string values (e.g. {\"key\": \"value\"}) or a two-column CSV of key,value rows."
+
+// Plan describes the changes needed to synchronise the remote items.
+type Plan struct {
+	// Create contains the keys missing from the remote.
+	Create []string
+	// Update contains the keys whose remote value differs.
+	Update []string
+	// Delete contains the keys missing from the local file.
+	Delete []string
+	// Values contains the local value for every key to create or update.
+	Values map[string]string
+}
+
+// Op is a single batch operation.
+type Op struct {
+	Operation fastly.BatchOperation
+	Key       string
+	Value     string
+}
+
+// Diff compares the local items against the remote items.
+func Diff(local, remote map[string]string) Plan {
+	p := Plan{Values: make(map[string]string)}
+	for k, v := range local {
+		rv, ok := remote[k]
+		switch {
+		case !ok:
+			p.Create = append(p.Create, k)
+		case rv != v:
+			p.Update = append(p.Update, k)
+		default:
+			continue
+		}
+		p.Values[k] = v
+	}
+	for k := range remote {
+		if _, ok := local[k]; !ok {
+			p.Delete = append(p.Delete, k)
+		}
+	}
+	sort.Strings(p.Create)
+	sort.Strings(p.Update)
+	sort.Strings(p.Delete)
+	return p
+}
+
+// Empty indicates there is nothing to change.
+func (p Plan) Empty() bool {
+	return len(p.Create) == 0 && len(p.Update) == 0 && len(p.Delete) == 0
+}
+
+// Len returns the total number of changes.
+func (p Plan) Len() int {
+	return len(p.Create) + len(p.Update) + len(p.Delete)
+}
+
+// Ops returns the batch operations for the plan, split into batches of at most
+// size operations.
+func (p Plan) Ops(size int) [][]Op {
+	ops := make([]Op, 0, p.Len())
+	for _, k := range p.Create {
+		ops = append(ops, Op{Operation: fastly.CreateBatchOperation, Key: k, Value: p.Values[k]})
+	}
+	for _, k := range p.Update {
+		ops = append(ops, Op{Operation: fastly.UpdateBatchOperation, Key: k, Value: p.Values[k]})
+	}
+	for _, k := range p.Delete {
+		ops = append(ops, Op{Operation: fastly.DeleteBatchOperation, Key: k})
+	}
+
+	var batches [][]Op
+	for size > 0 && len(ops) > 0 {
+		n := size
+		if n > len(ops) {
+			n = len(ops)
+		}
+		batches = append(batches, ops[:n])
+		ops = ops[n:]
+	}
+	return batches
+}
+
+// ErrDeleteNotConfirmed indicates the user didn't confirm the deletion of
+// remote keys, and so no changes were made.
+var ErrDeleteNotConfirmed = fsterr.RemediationError{
+	Inner:       errors.New("deleting keys was not confirmed: no changes were made"),
+	Remediation: "Re-run the command and confirm the prompt, or pass the --auto-yes flag to delete keys missing from the file.",
+}
+
+// ConfirmDeletes prompts the user to confirm the deletion of remote keys.
+//
+// Confirmation is skipped when there is nothing to delete or autoYes is set.
+// If in is nil (e.g. the file was read from stdin) the user can't be prompted
+// and so ErrDeleteNotConfirmed is returned.
+func (p Plan) ConfirmDeletes(out io.Writer, in io.Reader, autoYes bool) error {
+	if len(p.Delete) == 0 || autoYes {
+		return nil
+	}
+	if in == nil {
+		return ErrDeleteNotConfirmed
+	}
+	text.Warning(out, "\n%d key(s) will be permanently deleted.\n\n", len(p.Delete))
+	cont, err := text.AskYesNo(out, "Are you sure you want to continue? [y/N]: ", in, "--auto-yes")
+	if err != nil {
+		return err
+	}
+	if !cont {
+		return ErrDeleteNotConfirmed
+	}
+	return nil
+}
+
+// Print displays the plan as a table of operations.
+func (p Plan) Print(out io.Writer) {
+	t := text.NewTable(out)
+	t.AddHeader("OPERATION", "KEY")
+	for _, k := range p.Create {
+		t.AddLine("create", k)
+	}
+	for _, k := range p.Update {
+		t.AddLine("update", k)
+	}
+	for _, k := range p.Delete {
+		t.AddLine("delete", k)
+	}
+	t.Print()
+	text.Break(out)
+	text.Output(out, "Plan: %d to create, %d to update, %d to delete.", len(p.Create), len(p.Update), len(p.Delete))
+}
+
+// ReadFile reads the local items from path, or from in when path is "-".
+func ReadFile(path string, in io.Reader) (map[string]string, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(in)
+	} else {
+		// gosec flagged this:
+		// G304 (CWE-22): Potential file inclusion via variable
+		// Disabling as we need to read a user specified file.
+		/* #nosec */
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes the local items from either a JSON object or a two-column CSV.
+func Parse(data []byte) (map[string]string, error) {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		items := make(map[string]string)
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("failed to parse JSON: %w", err),
+				Remediation: Remediation,
+			}
+		}
+		return items, nil
+	}
+
+	r := csv.NewReader(bytes.NewReader(trimmed))
+	r.FieldsPerRecord = 2
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fsterr.RemediationError{
+			Inner:       fmt.Errorf("failed to parse CSV: %w", err),
+			Remediation: Remediation,
+		}
+	}
+
+	items := make(map[string]string, len(records))
+	for i, record := range records {
+		if _, ok := items[record[0]]; ok {
+			return nil, fsterr.RemediationError{
+				Inner:       fmt.Errorf("duplicate key '%s' on line %d", record[0], i+1),
+				Remediation: "Ensure each key appears only once in the file.",
+			}
+		}
+		items[record[0]] = record[1]
+	}
+	return items, nil
+}
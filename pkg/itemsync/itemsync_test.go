@@ -0,0 +1,118 @@
+package itemsync_test
+
+import (
+	"testing"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/itemsync"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestDiff(t *testing.T) {
+	for _, testcase := range []struct {
+		name       string
+		local      map[string]string
+		remote     map[string]string
+		wantCreate []string
+		wantUpdate []string
+		wantDelete []string
+	}{
+		{
+			name:       "add, update and delete",
+			local:      map[string]string{"a": "1", "b": "changed", "c": "3", "e": "5"},
+			remote:     map[string]string{"b": "2", "c": "3", "d": "4"},
+			wantCreate: []string{"a", "e"},
+			wantUpdate: []string{"b"},
+			wantDelete: []string{"d"},
+		},
+		{
+			name:       "empty remote",
+			local:      map[string]string{"b": "2", "a": "1"},
+			remote:     map[string]string{},
+			wantCreate: []string{"a", "b"},
+		},
+		{
+			name:       "empty local",
+			local:      map[string]string{},
+			remote:     map[string]string{"a": "1"},
+			wantDelete: []string{"a"},
+		},
+		{
+			name:   "in sync",
+			local:  map[string]string{"a": "1"},
+			remote: map[string]string{"a": "1"},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			plan := itemsync.Diff(testcase.local, testcase.remote)
+			testutil.AssertEqual(t, testcase.wantCreate, plan.Create)
+			testutil.AssertEqual(t, testcase.wantUpdate, plan.Update)
+			testutil.AssertEqual(t, testcase.wantDelete, plan.Delete)
+			testutil.AssertBool(t, testcase.wantCreate == nil && testcase.wantUpdate == nil && testcase.wantDelete == nil, plan.Empty())
+		})
+	}
+}
+
+func TestPlanOps(t *testing.T) {
+	plan := itemsync.Diff(
+		map[string]string{"a": "1", "b": "2", "c": "new"},
+		map[string]string{"c": "old", "d": "4"},
+	)
+
+	batches := plan.Ops(3)
+	if len(batches) != 2 {
+		t.Fatalf("want 2 batches, have %d", len(batches))
+	}
+	testutil.AssertEqual(t, []itemsync.Op{
+		{Operation: fastly.CreateBatchOperation, Key: "a", Value: "1"},
+		{Operation: fastly.CreateBatchOperation, Key: "b", Value: "2"},
+		{Operation: fastly.UpdateBatchOperation, Key: "c", Value: "new"},
+	}, batches[0])
+	testutil.AssertEqual(t, []itemsync.Op{
+		{Operation: fastly.DeleteBatchOperation, Key: "d"},
+	}, batches[1])
+}
+
+func TestParse(t *testing.T) {
+	for _, testcase := range []struct {
+		name      string
+		data      string
+		want      map[string]string
+		wantError string
+	}{
+		{
+			name: "json",
+			data: `{"a": "1", "b": "two"}`,
+			want: map[string]string{"a": "1", "b": "two"},
+		},
+		{
+			name: "csv",
+			data: "a,1\nb,\"two, with comma\"\n",
+			want: map[string]string{"a": "1", "b": "two, with comma"},
+		},
+		{
+			name:      "invalid json",
+			data:      `{"a": 1}`,
+			wantError: "failed to parse JSON",
+		},
+		{
+			name:      "wrong number of csv columns",
+			data:      "a,1,extra\n",
+			wantError: "failed to parse CSV",
+		},
+		{
+			name:      "duplicate csv key",
+			data:      "a,1\na,2\n",
+			wantError: "duplicate key 'a' on line 2",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			got, err := itemsync.Parse([]byte(testcase.data))
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if err == nil {
+				testutil.AssertEqual(t, testcase.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,349 @@
+package itemsync
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fastly/kingpin"
+
+	"github.com/fastly/cli/pkg/argparser"
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// SampleSize is the number of matches displayed before they're deleted.
+const SampleSize = 10
+
+// Pattern selects keys by a glob (e.g. 'temp:*') or a regular expression.
+type Pattern struct {
+	all    bool
+	re     *regexp.Regexp
+	source string
+}
+
+// String returns the pattern as given by the user.
+func (p Pattern) String() string {
+	return p.source
+}
+
+// Match reports whether key matches the pattern.
+func (p Pattern) Match(key string) bool {
+	return p.re.MatchString(key)
+}
+
+// MatchesAll reports whether the pattern matches every key (e.g. '*').
+func (p Pattern) MatchesAll() bool {
+	return p.all
+}
+
+// Filter returns the keys matching the pattern, sorted.
+func (p Pattern) Filter(keys []string) []string {
+	var matches []string
+	for _, k := range keys {
+		if p.Match(k) {
+			matches = append(matches, k)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// CompileGlob returns a Pattern matching the whole key against glob.
+//
+// A '*' matches any sequence of characters (including none), a '?' matches a
+// single character, and '[...]' matches one of the characters in the class
+// (e.g. '[a-z]', or '[!0-9]' to negate it). A '\' matches the next character
+// literally.
+func CompileGlob(glob string) (Pattern, error) {
+	expr, err := GlobToRegexp(glob)
+	if err != nil {
+		return Pattern{}, err
+	}
+	return Pattern{
+		all:    strings.Trim(glob, "*") == "",
+		re:     regexp.MustCompile(expr),
+		source: glob,
+	}, nil
+}
+
+// CompileRegexp returns a Pattern matching keys against the regular expression
+// expr. As with grep, the expression matches any part of the key unless it's
+// anchored with '^' and '$'.
+func CompileRegexp(expr string) (Pattern, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return Pattern{}, fmt.Errorf("invalid regular expression '%s': %w", expr, err)
+	}
+	body := strings.TrimSuffix(strings.TrimPrefix(expr, "^"), "$")
+	return Pattern{
+		all:    body == "" || body == ".*" || body == ".+",
+		re:     re,
+		source: expr,
+	}, nil
+}
+
+// GlobToRegexp translates a glob (see CompileGlob) into an anchored regular
+// expression.
+func GlobToRegexp(glob string) (string, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '\\':
+			if i == len(glob)-1 {
+				return "", fmt.Errorf("invalid glob '%s': trailing '\\'", glob)
+			}
+			i++
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		case '[':
+			// NOTE: A ']' straight after the '[' (or '[!') is part of the class.
+			j := i + 1
+			if j < len(glob) && (glob[j] == '!' || glob[j] == '^') {
+				j++
+			}
+			if j < len(glob) && glob[j] == ']' {
+				j++
+			}
+			for j < len(glob) && glob[j] != ']' {
+				j++
+			}
+			if j == len(glob) {
+				return "", fmt.Errorf("invalid glob '%s': unterminated '['", glob)
+			}
+			class := glob[i+1 : j]
+			i = j
+			b.WriteString("[")
+			if strings.HasPrefix(class, "!") || strings.HasPrefix(class, "^") {
+				b.WriteString("^")
+				class = class[1:]
+			}
+			b.WriteString(strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`).Replace(class))
+			b.WriteString("]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	if _, err := regexp.Compile(b.String()); err != nil {
+		return "", fmt.Errorf("invalid glob '%s': %w", glob, err)
+	}
+	return b.String(), nil
+}
+
+// PatternFlags are the flags that select keys to delete by pattern.
+type PatternFlags struct {
+	// DryRun displays the matching keys without deleting them.
+	DryRun bool
+
+	glob     argparser.OptionalString
+	matchAll bool
+	name     string
+	noun     string
+	regex    argparser.OptionalString
+}
+
+// NewPatternFlags registers the --<name>-glob, --<name>-regex, --match-all and
+// --dry-run flags on cmd (e.g. --key-glob for a name of 'key'). The noun is
+// what's deleted (e.g. 'key' or 'entry'), and example is a glob.
+func NewPatternFlags(cmd *kingpin.CmdClause, name, noun, example string) *PatternFlags {
+	f := &PatternFlags{name: name, noun: noun}
+	cmd.Flag(name+"-glob", fmt.Sprintf("Delete every %s matching the glob (e.g. '%s')", noun, example)).Action(f.glob.Set).StringVar(&f.glob.Value)
+	cmd.Flag(name+"-regex", fmt.Sprintf("Delete every %s matching the regular expression", noun)).Action(f.regex.Set).StringVar(&f.regex.Value)
+	cmd.Flag("match-all", fmt.Sprintf("Allow a pattern that matches every %s (e.g. '*')", noun)).BoolVar(&f.matchAll)
+	cmd.Flag("dry-run", "Display the matches without deleting them").BoolVar(&f.DryRun)
+	return f
+}
+
+// WasSet reports whether a pattern was given.
+func (f *PatternFlags) WasSet() bool {
+	return f.glob.WasSet || f.regex.WasSet
+}
+
+// Validate checks the pattern flags are used as an alternative to the flag
+// that names a single key (e.g. --key), and that one of them was given.
+func (f *PatternFlags) Validate(flag string, set bool) error {
+	patterns := fmt.Sprintf("--%s-glob or --%s-regex", f.name, f.name)
+	switch {
+	case f.WasSet() && set:
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid flag combination, %s and %s", flag, patterns),
+			Remediation: fmt.Sprintf("Use either %s to delete a single %s, or %s to delete every matching %s.", flag, f.noun, patterns, f.noun),
+		}
+	case !f.WasSet() && (f.DryRun || f.matchAll):
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid flag combination, --dry-run and --match-all require %s", patterns),
+			Remediation: fmt.Sprintf("Pass %s to select the %s to delete.", patterns, f.plural(2)),
+		}
+	case !f.WasSet() && !set:
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid command, one of %s, --%s-glob or --%s-regex is required", flag, f.name, f.name),
+			Remediation: fmt.Sprintf("Provide either %s or %s.", flag, patterns),
+		}
+	}
+	return nil
+}
+
+// Pattern compiles the pattern given by the flags.
+//
+// A pattern matching every key is rejected unless --match-all is also set, as
+// it's more likely to be a mistake (e.g. an unquoted glob expanded by the
+// shell) than a deliberate attempt to empty the resource.
+func (f *PatternFlags) Pattern() (Pattern, error) {
+	if f.glob.WasSet && f.regex.WasSet {
+		return Pattern{}, fsterr.RemediationError{
+			Inner:       fmt.Errorf("invalid flag combination, --%s-glob and --%s-regex", f.name, f.name),
+			Remediation: fmt.Sprintf("Use either --%s-glob or --%s-regex, not both.", f.name, f.name),
+		}
+	}
+
+	var (
+		p   Pattern
+		err error
+	)
+	if f.glob.WasSet {
+		p, err = CompileGlob(f.glob.Value)
+	} else {
+		p, err = CompileRegexp(f.regex.Value)
+	}
+	if err != nil {
+		return Pattern{}, fsterr.RemediationError{
+			Inner:       err,
+			Remediation: "In a glob '*' matches any characters, '?' matches a single character and '[a-z]' matches a range. Quote the pattern so the shell doesn't expand it.",
+		}
+	}
+
+	if p.MatchesAll() && !f.matchAll {
+		return Pattern{}, fsterr.RemediationError{
+			Inner:       fmt.Errorf("the pattern '%s' matches every %s", p, f.noun),
+			Remediation: fmt.Sprintf("Pass --match-all to delete every %s, or use a more specific pattern.", f.noun),
+		}
+	}
+	return p, nil
+}
+
+// plural returns the noun for n matches.
+func (f *PatternFlags) plural(n int) string {
+	return plural(f.noun, n)
+}
+
+// plural returns the noun for n items (e.g. 'entry' or 'entries').
+func plural(noun string, n int) string {
+	switch {
+	case n == 1:
+		return noun
+	case strings.HasSuffix(noun, "y") && !strings.ContainsAny(noun[len(noun)-2:len(noun)-1], "aeiou"):
+		return strings.TrimSuffix(noun, "y") + "ies"
+	default:
+		return noun + "s"
+	}
+}
+
+// PrintMatches displays the number of matches of the pattern, followed by
+// either every match (for a dry run) or a sample of them.
+func (f *PatternFlags) PrintMatches(out io.Writer, p Pattern, matches []string) {
+	text.Output(out, "%d %s match '%s'.", len(matches), f.plural(len(matches)), p)
+	if len(matches) == 0 {
+		return
+	}
+	text.Break(out)
+	shown := matches
+	if !f.DryRun && len(shown) > SampleSize {
+		shown = shown[:SampleSize]
+	}
+	for _, k := range shown {
+		fmt.Fprintf(out, "  %s\n", k)
+	}
+	if n := len(matches) - len(shown); n > 0 {
+		fmt.Fprintf(out, "  ... and %d more\n", n)
+	}
+}
+
+// ErrPatternDeleteNotConfirmed indicates the user didn't confirm the deletion
+// of the matches of a pattern, and so no changes were made.
+var ErrPatternDeleteNotConfirmed = fsterr.RemediationError{
+	Inner:       errors.New("deleting the matches was not confirmed: no changes were made"),
+	Remediation: "Re-run the command and confirm the prompt, or pass the --auto-yes flag to delete the matches.",
+}
+
+// ConfirmMatches prompts the user to confirm the deletion of n matches.
+// Confirmation is skipped when autoYes is set.
+func (f *PatternFlags) ConfirmMatches(out io.Writer, in io.Reader, n int, autoYes bool) error {
+	if autoYes {
+		return nil
+	}
+	text.Warning(out, "\n%d %s will be permanently deleted.\n\n", n, f.plural(n))
+	cont, err := text.AskYesNo(out, "Are you sure you want to continue? [y/N]: ", in, "--auto-yes")
+	if err != nil {
+		return err
+	}
+	if !cont {
+		return ErrPatternDeleteNotConfirmed
+	}
+	text.Break(out)
+	return nil
+}
+
+// DeleteResult is the outcome of deleting the matches of a pattern.
+type DeleteResult struct {
+	// Deleted contains the matches that were deleted.
+	Deleted []string `json:"deleted"`
+	// Failed contains the matches that couldn't be deleted.
+	Failed []string `json:"failed"`
+}
+
+// Err returns an error listing the matches that couldn't be deleted (if any).
+func (r DeleteResult) Err() error {
+	if len(r.Failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("failed to delete %d of %d matches: %s", len(r.Failed), len(r.Failed)+len(r.Deleted), strings.Join(r.Failed, ", "))
+}
+
+// DeleteBatches deletes the matches in batches of at most size, displaying
+// the progress. The del function deletes a batch and returns the matches that
+// couldn't be deleted, and a failed batch doesn't stop the remaining batches
+// from being deleted.
+func DeleteBatches(out io.Writer, matches []string, size int, del func(batch []string) (failed []string)) (DeleteResult, error) {
+	var result DeleteResult
+
+	spinner, err := text.NewSpinner(out)
+	if err != nil {
+		return result, err
+	}
+	if err := spinner.Start(); err != nil {
+		return result, err
+	}
+	msg := "Deleting matches"
+	for i := 0; i < len(matches); i += size {
+		spinner.Message(fmt.Sprintf("%s (%d/%d)...", msg, i, len(matches)))
+		batch := matches[i:min(i+size, len(matches))]
+		failed := del(batch)
+		isFailed := make(map[string]bool, len(failed))
+		for _, k := range failed {
+			isFailed[k] = true
+		}
+		for _, k := range batch {
+			if isFailed[k] {
+				result.Failed = append(result.Failed, k)
+			} else {
+				result.Deleted = append(result.Deleted, k)
+			}
+		}
+	}
+
+	summary := fmt.Sprintf("%s: %d deleted, %d failed", msg, len(result.Deleted), len(result.Failed))
+	if len(result.Failed) > 0 {
+		spinner.StopFailMessage(summary)
+		return result, spinner.StopFail()
+	}
+	spinner.StopMessage(summary)
+	return result, spinner.Stop()
+}
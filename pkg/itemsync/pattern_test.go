@@ -0,0 +1,109 @@
+package itemsync_test
+
+import (
+	"testing"
+
+	"github.com/fastly/cli/pkg/itemsync"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestGlobToRegexp(t *testing.T) {
+	for _, testcase := range []struct {
+		glob      string
+		want      string
+		wantError string
+	}{
+		{glob: "temp:*", want: `^temp:.*$`},
+		{glob: "user-??", want: `^user-..$`},
+		{glob: "a.b+c", want: `^a\.b\+c$`},
+		{glob: "[a-c]*", want: `^[a-c].*$`},
+		{glob: "[!0-9]x", want: `^[^0-9]x$`},
+		{glob: "[]a]", want: `^[\]a]$`},
+		{glob: `literal\*`, want: `^literal\*$`},
+		{glob: "", want: `^$`},
+		{glob: "temp[", wantError: "unterminated '['"},
+		{glob: `temp\`, wantError: `trailing '\'`},
+		{glob: "[z-a]", wantError: "invalid glob '[z-a]'"},
+	} {
+		t.Run(testcase.glob, func(t *testing.T) {
+			have, err := itemsync.GlobToRegexp(testcase.glob)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertString(t, testcase.want, have)
+		})
+	}
+}
+
+func TestPatternFilter(t *testing.T) {
+	keys := []string{"temp:2", "temp:10", "temp", "user:1", "x-temp:1", "temp:a"}
+
+	for _, testcase := range []struct {
+		name           string
+		glob           string
+		regex          string
+		want           []string
+		wantMatchesAll bool
+	}{
+		{
+			name: "glob prefix",
+			glob: "temp:*",
+			want: []string{"temp:10", "temp:2", "temp:a"},
+		},
+		{
+			name: "glob is anchored",
+			glob: "temp",
+			want: []string{"temp"},
+		},
+		{
+			name: "glob character class",
+			glob: "temp:[0-9]",
+			want: []string{"temp:2"},
+		},
+		{
+			name:  "regex matches any part of the key",
+			regex: "temp:[0-9]+",
+			want:  []string{"temp:10", "temp:2", "x-temp:1"},
+		},
+		{
+			name:  "anchored regex",
+			regex: "^temp:[0-9]+$",
+			want:  []string{"temp:10", "temp:2"},
+		},
+		{
+			name: "no matches",
+			glob: "nope*",
+		},
+		{
+			name:           "glob matching every key",
+			glob:           "**",
+			want:           []string{"temp", "temp:10", "temp:2", "temp:a", "user:1", "x-temp:1"},
+			wantMatchesAll: true,
+		},
+		{
+			name:           "regex matching every key",
+			regex:          "^.*$",
+			want:           []string{"temp", "temp:10", "temp:2", "temp:a", "user:1", "x-temp:1"},
+			wantMatchesAll: true,
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var (
+				p   itemsync.Pattern
+				err error
+			)
+			if testcase.glob != "" {
+				p, err = itemsync.CompileGlob(testcase.glob)
+			} else {
+				p, err = itemsync.CompileRegexp(testcase.regex)
+			}
+			testutil.AssertNoError(t, err)
+			testutil.AssertEqual(t, testcase.want, p.Filter(keys))
+			testutil.AssertBool(t, testcase.wantMatchesAll, p.MatchesAll())
+		})
+	}
+}
+
+func TestDeleteResult(t *testing.T) {
+	testutil.AssertNoError(t, itemsync.DeleteResult{Deleted: []string{"a"}}.Err())
+	err := itemsync.DeleteResult{Deleted: []string{"a"}, Failed: []string{"b", "c"}}.Err()
+	testutil.AssertErrorContains(t, err, "failed to delete 2 of 3 matches: b, c")
+}
@@ -16,6 +16,12 @@ import (
 type Data struct {
 	File File
 	Flag Flag
+
+	// ProfileServiceID is the default service of the current profile (see
+	// config.Profile). It's the lowest priority source of the service ID, and
+	// isn't used when there's a manifest file (which describes its own
+	// service, or one that's yet to be created).
+	ProfileServiceID string
 }
 
 // Authors yields an Authors.
@@ -63,5 +69,9 @@ func (d *Data) ServiceID() (string, Source) {
 		return d.File.ServiceID, SourceFile
 	}
 
+	if d.ProfileServiceID != "" && !d.File.Exists() {
+		return d.ProfileServiceID, SourceProfile
+	}
+
 	return "", SourceUndefined
 }
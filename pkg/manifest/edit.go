@@ -0,0 +1,418 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	toml "github.com/pelletier/go-toml"
+)
+
+// SetFields updates top-level string fields (e.g. "name") in the manifest at
+// path, adding any that are missing.
+//
+// Unlike File.Write, the file is edited in place so that comments, ordering
+// and formatting are preserved. Only the value of each field is replaced, so a
+// trailing comment on the same line is kept.
+func SetFields(path string, fields map[string]string) error {
+	// G304 (CWE-22): Potential file inclusion via variable
+	// #nosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		lines, err = setField(lines, k, fields[k])
+		if err != nil {
+			return fmt.Errorf("failed to update '%s' in %s: %w", k, path, err)
+		}
+	}
+
+	// The edit is verified before anything is written, so that a manifest the
+	// editor doesn't understand is never left corrupted.
+	updated := strings.Join(lines, "")
+	tree, err := toml.Load(updated)
+	if err != nil {
+		return fmt.Errorf("failed to update %s: the edited manifest is invalid: %w", path, err)
+	}
+	for _, k := range keys {
+		if v, _ := tree.Get(k).(string); v != fields[k] {
+			return fmt.Errorf("failed to update '%s' in %s: the edited value doesn't match", k, path)
+		}
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(updated), fi.Mode().Perm())
+}
+
+// setField replaces the value of a top-level key, or inserts the key after the
+// last top-level key if it's missing.
+func setField(lines []string, key, value string) ([]string, error) {
+	insertAt := -1
+	for i := 0; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), "[") {
+			break // the top-level keys end at the first table
+		}
+		k, start, ok := splitKey(lines[i])
+		if !ok {
+			continue
+		}
+		last, end, err := valueEnd(lines, i, start)
+		if err != nil {
+			return nil, err
+		}
+		if k != key {
+			i = last
+			insertAt = last + 1
+			continue
+		}
+
+		replacement := lines[i][:start] + quote(value) + lines[last][end:]
+		updated := append([]string{}, lines[:i]...)
+		updated = append(updated, replacement)
+		return append(updated, lines[last+1:]...), nil
+	}
+
+	if insertAt == -1 {
+		insertAt = 0
+		// Keep the field below the header comment (e.g. the spec reference).
+		for insertAt < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[insertAt]), "#") {
+			insertAt++
+		}
+	} else if !strings.HasSuffix(lines[insertAt-1], "\n") {
+		lines[insertAt-1] += "\n"
+	}
+	updated := append([]string{}, lines[:insertAt]...)
+	updated = append(updated, key+" = "+quote(value)+"\n")
+	return append(updated, lines[insertAt:]...), nil
+}
+
+// splitKey returns the unquoted key of a `key = value` line, and the index in
+// the line where the value starts.
+func splitKey(line string) (key string, start int, ok bool) {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return "", 0, false
+	}
+	eq := strings.Index(line, "=")
+	if eq == -1 {
+		return "", 0, false
+	}
+	key = strings.TrimSpace(line[:eq])
+	if len(key) >= 2 && (key[0] == '"' || key[0] == '\'') && key[len(key)-1] == key[0] {
+		key = key[1 : len(key)-1]
+	}
+	start = eq + 1
+	for start < len(line) && (line[start] == ' ' || line[start] == '\t') {
+		start++
+	}
+	return key, start, true
+}
+
+// valueEnd finds the end of the value starting at lines[i][start]. It returns
+// the index of the line the value ends on (a multi-line string can span
+// several) and the index in that line just after the value, so that whatever
+// follows (e.g. a comment) can be kept.
+func valueEnd(lines []string, i, start int) (last, end int, err error) {
+	value := lines[i][start:]
+	switch {
+	case strings.HasPrefix(value, `"""`), strings.HasPrefix(value, `'''`):
+		delim := value[:3]
+		offset := start + 3
+		for j := i; j < len(lines); j++ {
+			if idx := closingDelim(lines[j][offset:], delim); idx != -1 {
+				return j, offset + idx + 3, nil
+			}
+			offset = 0
+		}
+		return 0, 0, fmt.Errorf("unterminated multi-line string")
+	case strings.HasPrefix(value, `"`), strings.HasPrefix(value, `'`):
+		if idx := closingDelim(value[1:], value[:1]); idx != -1 {
+			return i, start + idx + 2, nil
+		}
+		return 0, 0, fmt.Errorf("unterminated string")
+	}
+	// Other values (e.g. manifest_version) can't span lines, and are replaced
+	// up to any comment.
+	end = len(strings.TrimRight(lines[i], "\r\n"))
+	if idx := strings.Index(value, "#"); idx != -1 {
+		end = start + idx
+	}
+	return i, end, nil
+}
+
+// closingDelim returns the index of the closing delimiter in s, skipping
+// escaped quotes in basic strings.
+func closingDelim(s, delim string) int {
+	for i := 0; i+len(delim) <= len(s); i++ {
+		if delim[0] == '"' && s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i:i+len(delim)] == delim {
+			// A multi-line string can end with up to two extra quotes.
+			for len(delim) == 3 && i+3 < len(s) && s[i+3] == delim[0] {
+				i++
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// quote returns s as a TOML basic string.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f || r == utf8.RuneError {
+				fmt.Fprintf(&b, `\u%04X`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// SetTables replaces the tables under prefix (e.g. "setup.backends") in the
+// manifest at path with content, a TOML document of tables under the same
+// prefix (see EncodeTables). Tables that don't exist are appended.
+//
+// As with SetFields, the rest of the file (including its comments) is left
+// as it is.
+func SetTables(path, prefix, content string) error {
+	// G304 (CWE-22): Potential file inclusion via variable
+	// #nosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	want, err := toml.Load(content)
+	if err != nil {
+		return fmt.Errorf("failed to update [%s] in %s: %w", prefix, path, err)
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	var (
+		kept, dropped []string
+		insertAt      = -1
+		owned         bool
+	)
+	for _, line := range lines {
+		if name, ok := tableName(line); ok {
+			wasOwned := owned
+			owned = name == prefix || strings.HasPrefix(name, prefix+".")
+			if owned && insertAt == -1 {
+				insertAt = len(kept)
+			}
+			// Comments at the end of a replaced table are assumed to belong to
+			// the table that follows.
+			if wasOwned && !owned {
+				kept = append(kept, trailingComments(dropped)...)
+			}
+			dropped = nil
+		}
+		if owned {
+			dropped = append(dropped, line)
+		} else {
+			kept = append(kept, line)
+		}
+	}
+
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if insertAt == -1 {
+		insertAt = len(kept)
+		if n := len(kept); n > 0 && !strings.HasSuffix(kept[n-1], "\n") {
+			kept[n-1] += "\n"
+		}
+		if n := len(kept); n > 0 && strings.TrimSpace(kept[n-1]) != "" {
+			content = "\n" + content
+		}
+	} else if insertAt < len(kept) && strings.TrimSpace(kept[insertAt]) != "" {
+		content += "\n"
+	}
+	updated := strings.Join(kept[:insertAt], "") + content + strings.Join(kept[insertAt:], "")
+
+	// The edit is verified before anything is written (see SetFields).
+	tree, err := toml.Load(updated)
+	if err != nil {
+		return fmt.Errorf("failed to update [%s] in %s: the edited manifest is invalid: %w", prefix, path, err)
+	}
+	if !reflect.DeepEqual(treeMap(tree.Get(prefix)), treeMap(want.Get(prefix))) {
+		return fmt.Errorf("failed to update [%s] in %s: the edited tables don't match (e.g. they're also defined with dotted keys)", prefix, path)
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(updated), fi.Mode().Perm())
+}
+
+// trailingComments returns the comments (and blank lines) at the end of lines.
+func trailingComments(lines []string) []string {
+	i := len(lines)
+	for i > 0 {
+		trimmed := strings.TrimSpace(lines[i-1])
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			break
+		}
+		i--
+	}
+	return lines[i:]
+}
+
+// tableName returns the name of the table (or array of tables) a header line
+// opens, with any quotes and whitespace around its keys removed.
+func tableName(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "[") {
+		return "", false
+	}
+	trimmed = strings.TrimLeft(trimmed, "[")
+	end := strings.Index(trimmed, "]")
+	if end == -1 {
+		return "", false
+	}
+	var keys []string
+	for _, k := range strings.Split(trimmed[:end], ".") {
+		k = strings.TrimSpace(k)
+		if len(k) >= 2 && (k[0] == '"' || k[0] == '\'') && k[len(k)-1] == k[0] {
+			k = k[1 : len(k)-1]
+		}
+		keys = append(keys, k)
+	}
+	return strings.Join(keys, "."), true
+}
+
+// treeMap returns v as a map if it's a table.
+func treeMap(v any) map[string]any {
+	if t, ok := v.(*toml.Tree); ok {
+		return t.ToMap()
+	}
+	return nil
+}
+
+// EncodeTables encodes v (e.g. a map of setup backends) as TOML tables under
+// prefix (e.g. "setup.backends"), with keys in order and each table's values
+// before its sub-tables. Empty values are omitted as the manifest encoding
+// does.
+func EncodeTables(prefix string, v any) (string, error) {
+	data, err := toml.Marshal(struct {
+		V any `toml:"v"`
+	}{v})
+	if err != nil {
+		return "", err
+	}
+	tree, err := toml.LoadBytes(data)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	m := treeMap(tree.Get("v"))
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if t, ok := m[k].(map[string]any); ok {
+			encodeTable(&b, prefix+"."+tableKey(k), t, true)
+		}
+	}
+	return b.String(), nil
+}
+
+// encodeTable writes a table, and then its sub-tables. The header of a table
+// that only has sub-tables is omitted (unless it's required to define the
+// table), as the sub-tables define it.
+func encodeTable(b *strings.Builder, name string, t map[string]any, required bool) {
+	var values, tables []string
+	for k, v := range t {
+		if _, ok := v.(map[string]any); ok {
+			tables = append(tables, k)
+		} else {
+			values = append(values, k)
+		}
+	}
+	sort.Strings(values)
+	sort.Strings(tables)
+
+	if len(values) > 0 || len(tables) == 0 || required {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(b, "[%s]\n", name)
+		for _, k := range values {
+			fmt.Fprintf(b, "%s = %s\n", tableKey(k), encodeValue(t[k]))
+		}
+	}
+	for _, k := range tables {
+		encodeTable(b, name+"."+tableKey(k), t[k].(map[string]any), false)
+	}
+}
+
+// encodeValue encodes a value decoded by toml.Load.
+func encodeValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return quote(v)
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, encodeValue(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// tableKey returns k as a bare key, or quoted if it isn't valid as one.
+func tableKey(k string) string {
+	if k == "" {
+		return `""`
+	}
+	for _, r := range k {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return quote(k)
+		}
+	}
+	return k
+}
@@ -10,6 +10,7 @@ import (
 
 	toml "github.com/pelletier/go-toml"
 
+	"github.com/fastly/cli/pkg/deprecation"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/text"
 )
@@ -28,6 +29,8 @@ type File struct {
 	Description string `toml:"description"`
 	// Language is the programming language used for the project.
 	Language string `toml:"language"`
+	// Package describes customisation options for the package archive.
+	Package Package `toml:"package,omitempty"`
 	// Profile is the name of the profile account the Fastly CLI should use to make API requests.
 	Profile string `toml:"profile,omitempty"`
 	// LocalServer describes the configuration for the local server built into the Fastly CLI.
@@ -43,11 +46,23 @@ type File struct {
 	// Setup describes a set of service configuration that works with the code in the package.
 	Setup Setup `toml:"setup,omitempty"`
 
-	quiet     bool
-	errLog    fsterr.LogInterface
-	exists    bool
-	output    io.Writer
-	readError error
+	quiet        bool
+	deprecations *deprecation.Tracker
+	errLog       fsterr.LogInterface
+	exists       bool
+	output       io.Writer
+	readError    error
+}
+
+func init() {
+	// NOTE: Read checks the manifest for every deprecated key registered.
+	deprecation.Register(deprecation.Item{
+		Kind:        deprecation.KindManifestKey,
+		Name:        "setup.dictionaries",
+		Replacement: "setup.config_stores",
+		Hint:        "Refer to the documentation at https://developer.fastly.com/reference/compute/fastly-toml/",
+		RemovedIn:   "v11.0.0",
+	})
 }
 
 // Exists yields whether the manifest exists.
@@ -89,7 +104,13 @@ func (f *File) Read(path string) (err error) {
 		// remediation information we pass back will be lost and a generic 'bug'
 		// remediation (which is set by logic in main.go) is used instead.
 		if strings.Contains(err.Error(), fsterr.ErrUnrecognisedManifestVersion.Inner.Error()) {
-			err = fsterr.ErrUnrecognisedManifestVersion
+			re := fsterr.ErrUnrecognisedManifestVersion
+			re.Remediation = fsterr.RenderRemediation(fsterr.RemediationManifestVersion, map[string]any{
+				"Path":      path,
+				"Version":   tree.Get("manifest_version"),
+				"Supported": ManifestLatestVersion,
+			})
+			err = re
 		}
 		f.logErr(err)
 		return err
@@ -115,8 +136,13 @@ func (f *File) Read(path string) (err error) {
 		}
 	}
 
-	if dt := tree.Get("setup.dictionaries"); dt != nil {
-		text.Warning(f.output, "Your fastly.toml manifest contains `[setup.dictionaries]`, which should be updated to `[setup.config_stores]`. Refer to the documentation at https://developer.fastly.com/reference/compute/fastly-toml/\n\n")
+	for _, i := range deprecation.Items() {
+		if i.Kind != deprecation.KindManifestKey || !tree.Has(i.Name) {
+			continue
+		}
+		if err := f.deprecations.Use(i); err != nil {
+			return err
+		}
 	}
 
 	f.exists = true
@@ -169,6 +195,11 @@ func (f *File) ReadError() error {
 	return f.readError
 }
 
+// SetDeprecations sets the tracker the use of deprecated keys is recorded by.
+func (f *File) SetDeprecations(t *deprecation.Tracker) {
+	f.deprecations = t
+}
+
 // SetErrLog sets an instance of errors.LogInterface.
 func (f *File) SetErrLog(errLog fsterr.LogInterface) {
 	f.errLog = errLog
@@ -252,6 +283,8 @@ type Scripts struct {
 	PostBuild string `toml:"post_build,omitempty"`
 	// PostInit is executed after the init step.
 	PostInit string `toml:"post_init,omitempty"`
+	// PreBuild is executed before the build step.
+	PreBuild string `toml:"pre_build,omitempty"`
 
 	// Private field used to revert modifications to EnvVars from EnvFile.
 	// See File.ParseEnvFile() and File.Write() methods for details.
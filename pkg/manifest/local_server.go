@@ -2,9 +2,14 @@ package manifest
 
 // LocalServer represents a list of mocked Viceroy resources.
 type LocalServer struct {
-	Backends       map[string]LocalBackend       `toml:"backends"`
-	ConfigStores   map[string]LocalConfigStore   `toml:"config_stores,omitempty"`
-	KVStores       map[string][]LocalKVStore     `toml:"kv_stores,omitempty"`
+	Backends     map[string]LocalBackend     `toml:"backends"`
+	ConfigStores map[string]LocalConfigStore `toml:"config_stores,omitempty"`
+	// Env are values for the guest, exposed through a config store by
+	// `compute serve` (which --env-var overrides).
+	Env      map[string]string         `toml:"env,omitempty"`
+	KVStores map[string][]LocalKVStore `toml:"kv_stores,omitempty"`
+	// SecretEnv are the names of Env values redacted from output.
+	SecretEnv      []string                      `toml:"secret_env,omitempty"`
 	SecretStores   map[string][]LocalSecretStore `toml:"secret_stores,omitempty"`
 	ViceroyVersion string                        `toml:"viceroy_version,omitempty"`
 }
@@ -31,6 +31,10 @@ const (
 	// SourceFlag indicates the parameter came from an explicit flag.
 	SourceFlag
 
+	// SourceProfile indicates the parameter came from the current profile of
+	// the CLI config (e.g. its default service).
+	SourceProfile
+
 	// SpecIntro informs the user of what the manifest file is for.
 	SpecIntro = "This file describes a Fastly Compute package. To learn more visit:"
 
@@ -10,6 +10,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	toml "github.com/pelletier/go-toml"
 
+	"github.com/fastly/cli/pkg/deprecation"
 	"github.com/fastly/cli/pkg/env"
 	fsterr "github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/manifest"
@@ -24,6 +25,7 @@ func TestManifest(t *testing.T) {
 		expectedError        error
 		wantRemediationError string
 		expectedOutput       string
+		strict               bool
 	}{
 		"valid: semver": {
 			manifest: "fastly-valid-semver.toml",
@@ -43,14 +45,21 @@ func TestManifest(t *testing.T) {
 			expectedError: fmt.Errorf("error parsing manifest_version 'abc'"),
 		},
 		"unrecognised: manifest_version exceeded limit": {
-			manifest:      "fastly-invalid-version-exceeded.toml",
-			valid:         false,
-			expectedError: fsterr.ErrUnrecognisedManifestVersion,
+			manifest:             "fastly-invalid-version-exceeded.toml",
+			valid:                false,
+			expectedError:        fsterr.ErrUnrecognisedManifestVersion,
+			wantRemediationError: fmt.Sprintf("has manifest_version 99.0.0 but this version of the CLI supports up to %d", manifest.ManifestLatestVersion),
 		},
 		"warning: dictionaries now replaced with config_stores": {
 			manifest:       "fastly-warning-dictionaries.toml",
 			valid:          true, // we display a warning but we don't exit command execution
-			expectedOutput: "WARNING: Your fastly.toml manifest contains `[setup.dictionaries]`",
+			expectedOutput: "DEPRECATED: The `setup.dictionaries` key of the fastly.toml manifest is deprecated and will be removed in v11.0.0. Use `setup.config_stores` instead.",
+		},
+		"strict: dictionaries now replaced with config_stores": {
+			manifest:      "fastly-warning-dictionaries.toml",
+			valid:         false,
+			expectedError: fmt.Errorf("deprecated items used in strict mode"),
+			strict:        true,
 		},
 	}
 
@@ -94,6 +103,9 @@ func TestManifest(t *testing.T) {
 			)
 			m.SetErrLog(fsterr.Log)
 			m.SetOutput(&stdout)
+			deprecations := deprecation.NewTracker()
+			_ = deprecations.Start(&stdout, tc.strict)
+			m.SetDeprecations(deprecations)
 
 			path, err := filepath.Abs(filepath.Join(prefix, tc.manifest))
 			if err != nil {
@@ -108,6 +120,9 @@ func TestManifest(t *testing.T) {
 			// If we expect an invalid config, then assert we get the right error.
 			if !tc.valid {
 				testutil.AssertErrorContains(t, err, tc.expectedError.Error())
+				if tc.wantRemediationError != "" {
+					testutil.AssertRemediationErrorContains(t, err, tc.wantRemediationError)
+				}
 				return
 			}
 
@@ -308,3 +323,196 @@ func TestManifestPersistsLocalServerSection(t *testing.T) {
 		t.Fatalf("testing section between original and updated fastly.toml do not match (-want +got):\n%s", diff)
 	}
 }
+
+func TestSetFields(t *testing.T) {
+	scenarios := []struct {
+		name      string
+		manifest  string
+		fields    map[string]string
+		want      string
+		wantError string
+	}{
+		{
+			name: "replace keeping comments",
+			manifest: `# This file describes a Fastly Compute package.
+
+# The package name.
+name = "old"   # set by init
+description = 'literal'
+manifest_version = 3
+
+[scripts]
+  build = "cargo build" # name = "not top-level"
+`,
+			fields: map[string]string{"name": "new", "description": `quote " and \ backslash`},
+			want: `# This file describes a Fastly Compute package.
+
+# The package name.
+name = "new"   # set by init
+description = "quote \" and \\ backslash"
+manifest_version = 3
+
+[scripts]
+  build = "cargo build" # name = "not top-level"
+`,
+		},
+		{
+			name: "replace multi-line string",
+			manifest: `name = "pkg"
+description = """
+first "line"
+second""" # keep me
+service_id = "123"
+`,
+			fields: map[string]string{"description": "one\ntwo"},
+			want: `name = "pkg"
+description = "one\ntwo" # keep me
+service_id = "123"
+`,
+		},
+		{
+			name: "insert missing after the last top-level key",
+			manifest: `name = "pkg"
+manifest_version = 3
+
+[local_server]
+  [local_server.backends]
+`,
+			fields: map[string]string{"service_id": "abc"},
+			want: `name = "pkg"
+manifest_version = 3
+service_id = "abc"
+
+[local_server]
+  [local_server.backends]
+`,
+		},
+		{
+			name:     "insert missing below the header comment",
+			manifest: "# header\n\n[scripts]\n",
+			fields:   map[string]string{"name": "pkg"},
+			want:     "# header\nname = \"pkg\"\n\n[scripts]\n",
+		},
+		{
+			name:      "unterminated string",
+			manifest:  "name = \"pkg\ndescription = \"ok\"\n",
+			fields:    map[string]string{"name": "new"},
+			wantError: "unterminated string",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), manifest.Filename)
+			if err := os.WriteFile(path, []byte(testcase.manifest), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			err := manifest.SetFields(path, testcase.fields)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := testcase.want
+			if testcase.wantError != "" {
+				want = testcase.manifest
+			}
+			testutil.AssertString(t, want, string(data))
+		})
+	}
+}
+
+func TestSetTables(t *testing.T) {
+	const content = "[setup.backends.origin]\naddress = \"example.com\"\n"
+	scenarios := []struct {
+		name      string
+		manifest  string
+		content   string
+		want      string
+		wantError string
+	}{
+		{
+			name:     "append",
+			manifest: "name = \"pkg\" # the name\n",
+			content:  content,
+			want:     "name = \"pkg\" # the name\n\n" + content,
+		},
+		{
+			name: "replace keeping comments",
+			manifest: `name = "pkg"
+
+[setup.backends.legacy] # old
+address = "legacy.example.com"
+
+# Settings for the local server.
+[local_server]
+`,
+			content: content,
+			want: `name = "pkg"
+
+` + content + `
+# Settings for the local server.
+[local_server]
+`,
+		},
+		{
+			name:     "leave other prefixes",
+			manifest: "[setup.backends_extra]\nx = 1\n[setup.backends]\n[setup.backends.a]\naddress = \"a\"\n",
+			content:  content,
+			want:     "[setup.backends_extra]\nx = 1\n" + content,
+		},
+		{
+			name:      "dotted keys",
+			manifest:  "setup.backends.legacy.address = \"legacy.example.com\"\n",
+			content:   content,
+			wantError: "the edited",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), manifest.Filename)
+			if err := os.WriteFile(path, []byte(testcase.manifest), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			err := manifest.SetTables(path, "setup.backends", testcase.content)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := testcase.want
+			if testcase.wantError != "" {
+				want = testcase.manifest
+			}
+			testutil.AssertString(t, want, string(data))
+		})
+	}
+}
+
+func TestEncodeTables(t *testing.T) {
+	backends := map[string]*manifest.SetupBackend{
+		"origin": {Address: "example.com", Port: 443, Description: "the \"main\" origin"},
+		"my api": {Address: "127.0.0.1"},
+	}
+	have, err := manifest.EncodeTables("setup.backends", backends)
+	testutil.AssertNoError(t, err)
+	testutil.AssertString(t, `[setup.backends."my api"]
+address = "127.0.0.1"
+
+[setup.backends.origin]
+address = "example.com"
+description = "the \"main\" origin"
+port = 443
+`, have)
+
+	var m manifest.File
+	path := filepath.Join(t.TempDir(), manifest.Filename)
+	if err := os.WriteFile(path, []byte("manifest_version = 3\n\n"+have), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertNoError(t, m.Read(path))
+	testutil.AssertEqual(t, backends, m.Setup.Backends)
+}
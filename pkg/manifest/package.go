@@ -0,0 +1,19 @@
+package manifest
+
+// Package represents customisation options for the package archive.
+type Package struct {
+	// Include lists extra files to add to the package.
+	Include []PackageInclude `toml:"include,omitempty"`
+}
+
+// PackageInclude maps the files matching a glob pattern to a path inside the
+// package.
+type PackageInclude struct {
+	// Source is a glob pattern, relative to the project directory, matching
+	// the files to include (e.g. "assets/*.bin").
+	Source string `toml:"source"`
+	// Destination is a path relative to the root of the package. A trailing
+	// slash indicates a directory (e.g. "data/"), in which case each matching
+	// file keeps its name. Otherwise the source must match exactly one file.
+	Destination string `toml:"destination"`
+}
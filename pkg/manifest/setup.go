@@ -42,6 +42,9 @@ type SetupBackend struct {
 type SetupConfigStore struct {
 	Items       map[string]SetupConfigStoreItems `toml:"items,omitempty"`
 	Description string                           `toml:"description,omitempty"`
+	// Optional indicates the deploy should continue (with a warning) if the
+	// store can't be created, e.g. the account isn't entitled to the feature.
+	Optional bool `toml:"optional,omitempty"`
 }
 
 // SetupConfigStoreItems represents a '[setup.dictionaries.<T>.items]' instance.
@@ -59,6 +62,8 @@ type SetupLogger struct {
 type SetupKVStore struct {
 	Items       map[string]SetupKVStoreItems `toml:"items,omitempty"`
 	Description string                       `toml:"description,omitempty"`
+	// Optional (see SetupConfigStore).
+	Optional bool `toml:"optional,omitempty"`
 }
 
 // SetupKVStoreItems represents a '[setup.kv_stores.<T>.items]' instance.
@@ -72,6 +77,8 @@ type SetupKVStoreItems struct {
 type SetupSecretStore struct {
 	Entries     map[string]SetupSecretStoreEntry `toml:"entries,omitempty"`
 	Description string                           `toml:"description,omitempty"`
+	// Optional (see SetupConfigStore).
+	Optional bool `toml:"optional,omitempty"`
 }
 
 // SetupSecretStoreEntry represents a '[setup.secret_stores.<T>.entries]' instance.
@@ -336,11 +336,12 @@ type API struct {
 	ListConfigStoreServicesFn func(i *fastly.ListConfigStoreServicesInput) ([]*fastly.Service, error)
 	UpdateConfigStoreFn       func(i *fastly.UpdateConfigStoreInput) (*fastly.ConfigStore, error)
 
-	CreateConfigStoreItemFn func(i *fastly.CreateConfigStoreItemInput) (*fastly.ConfigStoreItem, error)
-	DeleteConfigStoreItemFn func(i *fastly.DeleteConfigStoreItemInput) error
-	GetConfigStoreItemFn    func(i *fastly.GetConfigStoreItemInput) (*fastly.ConfigStoreItem, error)
-	ListConfigStoreItemsFn  func(i *fastly.ListConfigStoreItemsInput) ([]*fastly.ConfigStoreItem, error)
-	UpdateConfigStoreItemFn func(i *fastly.UpdateConfigStoreItemInput) (*fastly.ConfigStoreItem, error)
+	BatchModifyConfigStoreItemsFn func(i *fastly.BatchModifyConfigStoreItemsInput) error
+	CreateConfigStoreItemFn       func(i *fastly.CreateConfigStoreItemInput) (*fastly.ConfigStoreItem, error)
+	DeleteConfigStoreItemFn       func(i *fastly.DeleteConfigStoreItemInput) error
+	GetConfigStoreItemFn          func(i *fastly.GetConfigStoreItemInput) (*fastly.ConfigStoreItem, error)
+	ListConfigStoreItemsFn        func(i *fastly.ListConfigStoreItemsInput) ([]*fastly.ConfigStoreItem, error)
+	UpdateConfigStoreItemFn       func(i *fastly.UpdateConfigStoreItemInput) (*fastly.ConfigStoreItem, error)
 
 	CreateKVStoreFn         func(i *fastly.CreateKVStoreInput) (*fastly.KVStore, error)
 	GetKVStoreFn            func(i *fastly.GetKVStoreInput) (*fastly.KVStore, error)
@@ -381,9 +382,15 @@ type API struct {
 	ListConditionsFn  func(i *fastly.ListConditionsInput) ([]*fastly.Condition, error)
 	UpdateConditionFn func(i *fastly.UpdateConditionInput) (*fastly.Condition, error)
 
+	ListCacheSettingsFn func(i *fastly.ListCacheSettingsInput) ([]*fastly.CacheSetting, error)
+	ListGzipsFn         func(i *fastly.ListGzipsInput) ([]*fastly.Gzip, error)
+	ListHeadersFn       func(i *fastly.ListHeadersInput) ([]*fastly.Header, error)
+
 	GetProductFn     func(i *fastly.ProductEnablementInput) (*fastly.ProductEnablement, error)
 	EnableProductFn  func(i *fastly.ProductEnablementInput) (*fastly.ProductEnablement, error)
 	DisableProductFn func(i *fastly.ProductEnablementInput) error
+
+	GetAPIEventsFn func(i *fastly.GetAPIEventsFilterInput) (fastly.GetAPIEventsResponse, error)
 }
 
 // AllDatacenters implements Interface.
@@ -1741,6 +1748,11 @@ func (m API) UpdateConfigStore(i *fastly.UpdateConfigStoreInput) (*fastly.Config
 	return m.UpdateConfigStoreFn(i)
 }
 
+// BatchModifyConfigStoreItems implements Interface.
+func (m API) BatchModifyConfigStoreItems(i *fastly.BatchModifyConfigStoreItemsInput) error {
+	return m.BatchModifyConfigStoreItemsFn(i)
+}
+
 // CreateConfigStoreItem implements Interface.
 func (m API) CreateConfigStoreItem(i *fastly.CreateConfigStoreItemInput) (*fastly.ConfigStoreItem, error) {
 	return m.CreateConfigStoreItemFn(i)
@@ -1950,3 +1962,23 @@ func (m API) EnableProduct(i *fastly.ProductEnablementInput) (*fastly.ProductEna
 func (m API) DisableProduct(i *fastly.ProductEnablementInput) error {
 	return m.DisableProductFn(i)
 }
+
+// ListCacheSettings implements Interface.
+func (m API) ListCacheSettings(i *fastly.ListCacheSettingsInput) ([]*fastly.CacheSetting, error) {
+	return m.ListCacheSettingsFn(i)
+}
+
+// ListGzips implements Interface.
+func (m API) ListGzips(i *fastly.ListGzipsInput) ([]*fastly.Gzip, error) {
+	return m.ListGzipsFn(i)
+}
+
+// ListHeaders implements Interface.
+func (m API) ListHeaders(i *fastly.ListHeadersInput) ([]*fastly.Header, error) {
+	return m.ListHeadersFn(i)
+}
+
+// GetAPIEvents implements Interface.
+func (m API) GetAPIEvents(i *fastly.GetAPIEventsFilterInput) (fastly.GetAPIEventsResponse, error) {
+	return m.GetAPIEventsFn(i)
+}
@@ -29,6 +29,11 @@ func (av AssetVersioner) DownloadVersion(_ string) (string, error) {
 	return "", nil
 }
 
+// InstallVersion implements github.Versioner interface.
+func (av AssetVersioner) InstallVersion(_, _ string) (string, error) {
+	return "", fmt.Errorf("not implemented")
+}
+
 // Download implements github.Versioner interface.
 func (av AssetVersioner) Download(_ string) (string, error) {
 	return "", nil
@@ -0,0 +1,3 @@
+// Package naming validates the names of resources against the rules of the
+// Fastly API, so an invalid name is reported before a request is made.
+package naming
@@ -0,0 +1,247 @@
+package naming
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// Resource is a type of resource with a naming rule.
+type Resource string
+
+// The resources with naming rules.
+const (
+	ACL         Resource = "acl"
+	Backend     Resource = "backend"
+	ConfigStore Resource = "config-store"
+	Dictionary  Resource = "dictionary"
+	KVStore     Resource = "kv-store"
+	SecretStore Resource = "secret-store"
+	Service     Resource = "service"
+)
+
+// Rule describes the names the API accepts for a resource.
+type Rule struct {
+	// Noun is how the resource is described in messages.
+	Noun string
+	// MaxLength is the maximum number of characters.
+	MaxLength int
+	// Charset is the set of characters allowed besides ASCII letters and
+	// digits. It's ignored if AnyCharacter is set.
+	Charset string
+	// AnyCharacter allows any printable character.
+	AnyCharacter bool
+	// StartWithLetter requires the first character to be a letter (rather
+	// than a letter or digit). It's ignored if AnyCharacter is set.
+	StartWithLetter bool
+	// Reserved are names that can't be used, regardless of case.
+	Reserved []string
+}
+
+// vclReserved are the VCL keywords and variable namespaces that ACLs and
+// dictionaries can't be named after, as they're referenced by name in VCL.
+var vclReserved = []string{
+	"acl", "backend", "bereq", "beresp", "client", "director", "fastly",
+	"import", "include", "obj", "penaltybox", "ratecounter", "req", "resp",
+	"server", "sub", "table",
+}
+
+// Rules are the naming rules of each resource.
+//
+// NOTE: The rules are enforced by the API. If they're relaxed before they're
+// updated here, --skip-name-validation sends a name as it is.
+var Rules = map[Resource]Rule{
+	ACL: {
+		Noun:      "ACL",
+		MaxLength: 255,
+		Charset:   "_ ",
+		Reserved:  vclReserved,
+	},
+	Backend: {
+		Noun:         "backend",
+		MaxLength:    255,
+		AnyCharacter: true,
+	},
+	ConfigStore: {
+		Noun:      "config store",
+		MaxLength: 255,
+		Charset:   "_-.",
+	},
+	Dictionary: {
+		Noun:            "dictionary",
+		MaxLength:       255,
+		Charset:         "_-",
+		StartWithLetter: true,
+		Reserved:        vclReserved,
+	},
+	KVStore: {
+		Noun:      "KV store",
+		MaxLength: 255,
+		Charset:   "_-.",
+	},
+	SecretStore: {
+		Noun:      "secret store",
+		MaxLength: 255,
+		Charset:   "_-.",
+	},
+	Service: {
+		Noun:         "service",
+		MaxLength:    255,
+		AnyCharacter: true,
+	},
+}
+
+// Validate checks name against the rule of the resource. The error is a
+// RemediationError that suggests a valid name (see Sanitize).
+func Validate(r Resource, name string) error {
+	rule, ok := Rules[r]
+	if !ok {
+		return nil
+	}
+	violation := rule.violation(name)
+	if violation == "" {
+		return nil
+	}
+	remediation := "Pass --skip-name-validation to send the name as it is, if the API accepts it."
+	if s := Sanitize(r, name); s != "" {
+		remediation = fmt.Sprintf("Use a name such as %q instead. %s", s, remediation)
+	}
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("invalid %s name %q: %s", rule.Noun, name, violation),
+		Remediation: remediation,
+	}
+}
+
+// violation describes the first part of the rule name breaks, or returns an
+// empty string.
+func (rule Rule) violation(name string) string {
+	if name == "" {
+		return "the name must not be empty"
+	}
+	if n := utf8.RuneCountInString(name); n > rule.MaxLength {
+		return fmt.Sprintf("the name must be at most %d characters (it has %d)", rule.MaxLength, n)
+	}
+	for _, r := range name {
+		if !rule.allowed(r) {
+			return fmt.Sprintf("%q isn't allowed, the name can contain %s", r, rule.charsetDescription())
+		}
+	}
+	if first, _ := utf8.DecodeRuneInString(name); !rule.start(first) {
+		if rule.StartWithLetter {
+			return "the name must start with a letter"
+		}
+		return "the name must start with a letter or digit"
+	}
+	for _, w := range rule.Reserved {
+		if strings.EqualFold(name, w) {
+			return fmt.Sprintf("%q is reserved", w)
+		}
+	}
+	return ""
+}
+
+func (rule Rule) allowed(r rune) bool {
+	if rule.AnyCharacter {
+		return unicode.IsPrint(r)
+	}
+	return isASCIIAlnum(r) || strings.ContainsRune(rule.Charset, r)
+}
+
+func (rule Rule) start(r rune) bool {
+	switch {
+	case rule.AnyCharacter:
+		return true
+	case rule.StartWithLetter:
+		return isASCIILetter(r)
+	default:
+		return isASCIIAlnum(r)
+	}
+}
+
+func (rule Rule) charsetDescription() string {
+	if rule.AnyCharacter {
+		return "only printable characters"
+	}
+	parts := []string{"letters", "digits"}
+	for _, r := range rule.Charset {
+		switch r {
+		case '_':
+			parts = append(parts, "underscores")
+		case '-':
+			parts = append(parts, "dashes")
+		case '.':
+			parts = append(parts, "periods")
+		case ' ':
+			parts = append(parts, "spaces")
+		default:
+			parts = append(parts, fmt.Sprintf("%q", r))
+		}
+	}
+	return "only " + strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+}
+
+// Sanitize returns a name derived from name that's valid for the resource,
+// or an empty string if there's nothing to derive it from.
+//
+// Disallowed characters are replaced by an underscore (or removed, if the
+// rule doesn't allow underscores) and leading punctuation is removed. A name
+// that then doesn't start with a valid character is prefixed with the
+// resource type, and a reserved name is suffixed with it.
+func Sanitize(r Resource, name string) string {
+	rule, ok := Rules[r]
+	if !ok {
+		return name
+	}
+	replacement := ""
+	if rule.allowed('_') {
+		replacement = "_"
+	}
+
+	var b strings.Builder
+	for _, c := range name {
+		switch {
+		case rule.allowed(c):
+			b.WriteRune(c)
+		case !strings.HasSuffix(b.String(), replacement):
+			b.WriteString(replacement)
+		}
+	}
+	s := strings.Trim(b.String(), replacement+" ")
+	if !rule.AnyCharacter {
+		s = strings.TrimLeftFunc(s, func(c rune) bool { return !isASCIIAlnum(c) })
+	}
+	if s == "" {
+		return ""
+	}
+
+	affix := strings.Map(func(c rune) rune {
+		if rule.allowed(c) {
+			return c
+		}
+		return -1
+	}, strings.ReplaceAll(string(r), "-", replacement))
+	if first, _ := utf8.DecodeRuneInString(s); !rule.start(first) {
+		s = affix + replacement + s
+	}
+	for _, w := range rule.Reserved {
+		if strings.EqualFold(s, w) {
+			s = s + replacement + affix
+			break
+		}
+	}
+	if utf8.RuneCountInString(s) > rule.MaxLength {
+		s = strings.TrimRight(string([]rune(s)[:rule.MaxLength]), replacement+" ")
+	}
+	return s
+}
+
+func isASCIILetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+func isASCIIAlnum(r rune) bool {
+	return isASCIILetter(r) || (r >= '0' && r <= '9')
+}
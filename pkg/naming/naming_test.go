@@ -0,0 +1,76 @@
+package naming_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/naming"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestValidate(t *testing.T) {
+	long := strings.Repeat("a", 256)
+	scenarios := []struct {
+		resource  naming.Resource
+		name      string
+		wantError string
+		// wantSuggestion is the name suggested by the remediation.
+		wantSuggestion string
+	}{
+		{resource: naming.ACL, name: "my acl_1"},
+		{resource: naming.ACL, name: "1st"},
+		{resource: naming.ACL, name: strings.Repeat("a", 255)},
+		{resource: naming.ACL, name: long, wantError: "at most 255 characters (it has 256)", wantSuggestion: strings.Repeat("a", 255)},
+		{resource: naming.ACL, name: " blocked", wantError: "must start with a letter or digit", wantSuggestion: "blocked"},
+		{resource: naming.ACL, name: "Req", wantError: `"req" is reserved`, wantSuggestion: "Req_acl"},
+		{resource: naming.ACL, name: "", wantError: "must not be empty"},
+
+		{resource: naming.Backend, name: "origin: (primary) #1"},
+		{resource: naming.Backend, name: "tab\there", wantError: `'\t' isn't allowed, the name can contain only printable characters`, wantSuggestion: "tab_here"},
+
+		{resource: naming.ConfigStore, name: "flags-v1.2_prod"},
+		{resource: naming.ConfigStore, name: "-flags", wantError: "must start with a letter or digit", wantSuggestion: "flags"},
+		{resource: naming.ConfigStore, name: "my flags", wantError: "' ' isn't allowed, the name can contain only letters, digits, underscores, dashes and periods", wantSuggestion: "my_flags"},
+
+		{resource: naming.Dictionary, name: "geo_ip-v2"},
+		{resource: naming.Dictionary, name: "geo ip", wantError: "' ' isn't allowed", wantSuggestion: "geo_ip"},
+		{resource: naming.Dictionary, name: "2geo", wantError: "must start with a letter", wantSuggestion: "dictionary_2geo"},
+		{resource: naming.Dictionary, name: "geo  // ip!", wantError: "' ' isn't allowed", wantSuggestion: "geo_ip"},
+		{resource: naming.Dictionary, name: "TABLE", wantError: `"table" is reserved`, wantSuggestion: "TABLE_dictionary"},
+		{resource: naming.Dictionary, name: "!!!", wantError: "'!' isn't allowed"},
+		{resource: naming.Dictionary, name: "géo", wantError: "'é' isn't allowed", wantSuggestion: "g_o"},
+
+		{resource: naming.KVStore, name: "assets.v2"},
+		{resource: naming.KVStore, name: "assets/v2", wantError: "'/' isn't allowed", wantSuggestion: "assets_v2"},
+
+		{resource: naming.SecretStore, name: "_creds", wantError: "must start with a letter or digit", wantSuggestion: "creds"},
+
+		{resource: naming.Service, name: "My Service (staging)"},
+		{resource: naming.Service, name: long, wantError: "at most 255 characters", wantSuggestion: strings.Repeat("a", 255)},
+	}
+	for _, testcase := range scenarios {
+		t.Run(string(testcase.resource)+"/"+testcase.name, func(t *testing.T) {
+			err := naming.Validate(testcase.resource, testcase.name)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			if err == nil {
+				return
+			}
+
+			var re fsterr.RemediationError
+			if !errors.As(err, &re) {
+				t.Fatalf("want a RemediationError, have %T", err)
+			}
+			testutil.AssertStringContains(t, re.Remediation, "--skip-name-validation")
+			if testcase.wantSuggestion == "" {
+				if strings.Contains(re.Remediation, "Use a name such as") {
+					t.Errorf("want no suggestion, have: %s", re.Remediation)
+				}
+				return
+			}
+			testutil.AssertEqual(t, testcase.wantSuggestion, naming.Sanitize(testcase.resource, testcase.name))
+			testutil.AssertNoError(t, naming.Validate(testcase.resource, testcase.wantSuggestion))
+		})
+	}
+}
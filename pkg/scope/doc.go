@@ -0,0 +1,4 @@
+// Package scope checks that the API token has the scope a command needs (e.g.
+// global, or purge_select) before the command runs, so a read-only token fails
+// fast rather than after the command has made some of its changes.
+package scope
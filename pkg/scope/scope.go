@@ -0,0 +1,81 @@
+package scope
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/argparser"
+	"github.com/fastly/cli/pkg/audit"
+	fsterr "github.com/fastly/cli/pkg/errors"
+)
+
+// Required returns the scope the token needs to run the command (e.g.
+// "service create"), or an empty scope if the command isn't checked.
+//
+// Mutating commands (see audit.Mutating) need the global scope, apart from
+// purge which needs purge_select. A command that implements argparser.Scoper
+// declares its own scope instead.
+func Required(command string, cmd argparser.Command) fastly.TokenScope {
+	if s, ok := cmd.(argparser.Scoper); ok {
+		return s.RequiredScope()
+	}
+	segs := strings.Fields(command)
+	switch {
+	case !audit.Mutating(command):
+		return ""
+	// NOTE: Tokens are created with a username and password, and any token
+	// can revoke itself.
+	case segs[0] == "auth-token":
+		return ""
+	case segs[0] == "purge":
+		return fastly.PurgeSelectScope
+	}
+	return fastly.GlobalScope
+}
+
+// Grants reports whether a token with the scopes (a space-separated list, as
+// returned by the API) allows the needed scope. The global scope allows them
+// all.
+func Grants(scopes fastly.TokenScope, need fastly.TokenScope) bool {
+	for _, s := range strings.Fields(string(scopes)) {
+		if s == string(need) || s == string(fastly.GlobalScope) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns an error if the token, described by self, lacks the scope
+// the command needs. An empty scope isn't checked.
+func Check(command string, need fastly.TokenScope, self func() (*fastly.Token, error)) error {
+	if need == "" {
+		return nil
+	}
+	t, err := self()
+	if err != nil {
+		// NOTE: An invalid token is reported as such, rather than suggesting
+		// the check is skipped.
+		var he *fastly.HTTPError
+		if errors.As(err, &he) && he.StatusCode == http.StatusUnauthorized {
+			return err
+		}
+		return fsterr.RemediationError{
+			Inner:       fmt.Errorf("failed to look up the scope of the API token: %w", err),
+			Remediation: fsterr.TokenScopeRemediation,
+			Code:        fsterr.CodeTokenScope,
+		}
+	}
+	scopes := fastly.ToValue(t.Scope)
+	if Grants(scopes, need) {
+		return nil
+	}
+	return fsterr.RemediationError{
+		Inner:       fmt.Errorf("`fastly %s` requires an API token with the '%s' scope (the token's scope is '%s')", command, need, scopes),
+		Remediation: fsterr.TokenScopeRemediation,
+		Code:        fsterr.CodeTokenScope,
+	}
+}
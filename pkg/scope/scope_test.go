@@ -0,0 +1,87 @@
+package scope_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	fsterr "github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/scope"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestGrants(t *testing.T) {
+	testutil.AssertBool(t, true, scope.Grants("global", fastly.PurgeAllScope))
+	testutil.AssertBool(t, true, scope.Grants("global:read purge_select", fastly.PurgeSelectScope))
+	testutil.AssertBool(t, false, scope.Grants("global:read purge_select", fastly.PurgeAllScope))
+	testutil.AssertBool(t, false, scope.Grants("global:read", fastly.GlobalScope))
+	testutil.AssertBool(t, false, scope.Grants("", fastly.GlobalScope))
+}
+
+func TestCheck(t *testing.T) {
+	token := func(s fastly.TokenScope) func() (*fastly.Token, error) {
+		return func() (*fastly.Token, error) {
+			return &fastly.Token{Scope: fastly.ToPointer(s)}, nil
+		}
+	}
+	unauthorized := &fastly.HTTPError{StatusCode: http.StatusUnauthorized}
+
+	scenarios := []struct {
+		name      string
+		need      fastly.TokenScope
+		self      func() (*fastly.Token, error)
+		wantError string
+		wantCode  fsterr.Code
+	}{
+		{
+			name: "not checked",
+			self: func() (*fastly.Token, error) {
+				t.Fatal("unexpected token lookup")
+				return nil, nil
+			},
+		},
+		{
+			name: "granted",
+			need: fastly.GlobalScope,
+			self: token(fastly.GlobalScope),
+		},
+		{
+			name:      "missing",
+			need:      fastly.GlobalScope,
+			self:      token(fastly.GlobalReadScope),
+			wantError: "`fastly service create` requires an API token with the 'global' scope (the token's scope is 'global:read')",
+			wantCode:  fsterr.CodeTokenScope,
+		},
+		{
+			name: "lookup failed",
+			need: fastly.GlobalScope,
+			self: func() (*fastly.Token, error) {
+				return nil, errors.New("connection refused")
+			},
+			wantError: "failed to look up the scope of the API token: connection refused",
+			wantCode:  fsterr.CodeTokenScope,
+		},
+		{
+			name: "invalid token",
+			need: fastly.GlobalScope,
+			self: func() (*fastly.Token, error) {
+				return nil, unauthorized
+			},
+			wantError: unauthorized.Error(),
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			err := scope.Check("service create", testcase.need, testcase.self)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			var re fsterr.RemediationError
+			if errors.As(err, &re) {
+				testutil.AssertEqual(t, testcase.wantCode, re.Code)
+			} else if testcase.wantCode != "" {
+				t.Errorf("want a remediation error with code %s, have: %v", testcase.wantCode, err)
+			}
+		})
+	}
+}
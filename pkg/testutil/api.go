@@ -100,3 +100,14 @@ var WhoamiBasicResponse = whoami.VerifyResponse{
 		Scope: "global",
 	},
 }
+
+// WhoamiTokenSelf is used by `whoami` and `sso` tests to mock the lookup of
+// the token details matching WhoamiBasicResponse. It's also the default
+// lookup of MockGlobalData, as its global scope passes the scope preflight.
+func WhoamiTokenSelf() (*fastly.Token, error) {
+	return &fastly.Token{
+		TokenID: fastly.ToPointer("abcdefg"),
+		Name:    fastly.ToPointer("Token name"),
+		Scope:   fastly.ToPointer(fastly.GlobalScope),
+	}, nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/fastly/cli/pkg/auth"
 	"github.com/fastly/cli/pkg/config"
+	"github.com/fastly/cli/pkg/deprecation"
 	"github.com/fastly/cli/pkg/errors"
 	"github.com/fastly/cli/pkg/global"
 	"github.com/fastly/cli/pkg/manifest"
@@ -89,10 +90,13 @@ func (s MockAuthServer) Start() error {
 //
 // TODO: Move this and other mocks into mocks package.
 func MockGlobalData(args []string, stdout io.Writer) *global.Data {
+	deprecations := deprecation.NewTracker()
+
 	var md manifest.Data
 	md.File.Args = args
 	md.File.SetErrLog(errors.Log)
 	md.File.SetOutput(stdout)
+	md.File.SetDeprecations(deprecations)
 	_ = md.File.Read(manifest.Filename)
 
 	configPath := "/dev/null"
@@ -107,21 +111,32 @@ func MockGlobalData(args []string, stdout io.Writer) *global.Data {
 		Config: config.File{
 			Profiles: TokenProfile(),
 		},
-		ConfigPath: configPath,
-		Env:        config.Environment{},
-		ErrLog:     errors.Log,
+		ConfigPath:   configPath,
+		Deprecations: deprecations,
+		Env:          config.Environment{},
+		ErrLog:       errors.Log,
+		ExecuteEditor: func(args []string) error {
+			return fmt.Errorf("unexpected editor invocation: %v", args)
+		},
+		ExecuteGit: func(args ...string) ([]byte, error) {
+			return nil, fmt.Errorf("unexpected git invocation: %v", args)
+		},
 		ExecuteWasmTools: func(bin string, args []string) error {
 			fmt.Printf("bin: %s\n", bin)
 			fmt.Printf("args: %#v\n", args)
 			return nil
 		},
 		HTTPClient: &http.Client{Timeout: time.Second * 5},
-		Manifest:   &md,
+		IsTTY: func(_ any) bool {
+			return false
+		},
+		Manifest: &md,
 		Opener: func(input string) error {
 			fmt.Printf("%s\n", input)
 			return nil // no-op
 		},
-		Output: stdout,
+		Output:    stdout,
+		TokenSelf: WhoamiTokenSelf,
 	}
 }
 
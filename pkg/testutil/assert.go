@@ -26,6 +26,14 @@ func AssertBool(t *testing.T, want, have bool) {
 	}
 }
 
+// AssertExitCode fatals a test if the exit codes aren't equal.
+func AssertExitCode(t *testing.T, want, have int) {
+	t.Helper()
+	if want != have {
+		t.Fatalf("want exit code %d, have %d", want, have)
+	}
+}
+
 // AssertString fatals a test if the parameters aren't equal.
 func AssertString(t *testing.T, want, have string) {
 	t.Helper()
@@ -0,0 +1,78 @@
+package testutil
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/fatih/color"
+
+	"github.com/fastly/cli/pkg/app"
+	"github.com/fastly/cli/pkg/config"
+	"github.com/fastly/cli/pkg/errors"
+	"github.com/fastly/cli/pkg/global"
+	"github.com/fastly/cli/pkg/mock"
+)
+
+// CLIOpts customises the environment of the CLI invoked by RunCLI.
+type CLIOpts struct {
+	// API is the mocked Fastly API (ignored when APIClientFactory is set).
+	API mock.API
+	// APIClientFactory overrides the Fastly API client factory.
+	APIClientFactory global.APIClientFactory
+	// ConfigPath is the CLI config file to read and write.
+	// The mocked config from MockGlobalData is used when empty.
+	ConfigPath string
+	// Env is the environment the CLI reads (e.g. FASTLY_QUIET).
+	Env map[string]string
+	// Stdin is the input for interactive prompts.
+	Stdin io.Reader
+}
+
+// RunCLI runs the CLI with args (excluding the binary name) the same way as
+// the fastly binary, from argument parsing to the exit code, but in-process.
+//
+// Errors are reported on stderr and mapped to an exit code just as they are
+// for the real binary, although os.Exit is never called. Global state touched
+// along the way (e.g. app.Init and the error log) is restored afterwards.
+func RunCLI(t *testing.T, args []string, opts CLIOpts) (stdout, stderr string, exitCode int) {
+	t.Helper()
+
+	var outBuf, errBuf bytes.Buffer
+
+	defer func(fn func([]string, io.Reader) (*global.Data, error)) { app.Init = fn }(app.Init)
+	defer func(w io.Writer) { color.Error = w }(color.Error)
+	defer func(path string) { errors.LogPath = path }(errors.LogPath)
+	defer func(entries errors.LogEntries) { *errors.Log = entries }(*errors.Log)
+
+	color.Error = &errBuf
+	errors.LogPath = filepath.Join(t.TempDir(), "errors.log")
+	*errors.Log = nil
+
+	app.Init = func(_ []string, stdin io.Reader) (*global.Data, error) {
+		data := MockGlobalData(args, &outBuf)
+		data.APIClientFactory = mock.APIClient(opts.API)
+		if opts.APIClientFactory != nil {
+			data.APIClientFactory = opts.APIClientFactory
+		}
+		data.Env.Read(opts.Env)
+		data.ErrOutput = &errBuf
+		data.Input = stdin
+		if opts.ConfigPath != "" {
+			var cfg config.File
+			cfg.SetAutoYes(true)
+			if err := cfg.Read(opts.ConfigPath, stdin, &outBuf, errors.Log, false); err != nil {
+				return nil, err
+			}
+			data.Config = cfg
+			data.ConfigPath = opts.ConfigPath
+		}
+		return data, nil
+	}
+
+	// NOTE: The binary name is expected as the first argument (as in os.Args).
+	argv := append([]string{"fastly"}, args...)
+	exitCode = errors.ExitCode(app.Run(argv, opts.Stdin), argv, &outBuf)
+	return outBuf.String(), errBuf.String(), exitCode
+}
@@ -0,0 +1,70 @@
+package testutil_test
+
+import (
+	"testing"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestRunCLI(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		args           string
+		opts           testutil.CLIOpts
+		wantExitCode   int
+		wantStdout     string
+		wantStderr     string
+		dontWantStderr string
+	}{
+		{
+			name:       "help",
+			args:       "--help",
+			wantStderr: "USAGE",
+		},
+		{
+			name:         "unknown command",
+			args:         "foobar",
+			wantExitCode: 1,
+			wantStderr:   "error parsing arguments: expected command but got foobar",
+		},
+		{
+			name: "mocked command",
+			args: "service describe --service-id 123",
+			opts: testutil.CLIOpts{
+				API: mock.API{
+					GetServiceDetailsFn: func(i *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+						return &fastly.ServiceDetail{
+							ServiceID: fastly.ToPointer(i.ServiceID),
+							Name:      fastly.ToPointer("Foo"),
+						}, nil
+					},
+				},
+			},
+			wantStdout:     "Name: Foo",
+			dontWantStderr: "Error",
+		},
+		{
+			name: "environment",
+			args: "service describe --service-id 123 --verbose",
+			opts: testutil.CLIOpts{
+				Env: map[string]string{"FASTLY_QUIET": "true"},
+			},
+			wantExitCode: 1,
+			wantStderr:   "--verbose flag provided while FASTLY_QUIET is set",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			stdout, stderr, exitCode := testutil.RunCLI(t, testutil.Args(testcase.args), testcase.opts)
+			testutil.AssertExitCode(t, testcase.wantExitCode, exitCode)
+			testutil.AssertStringContains(t, stdout, testcase.wantStdout)
+			testutil.AssertStringContains(t, stderr, testcase.wantStderr)
+			if testcase.dontWantStderr != "" {
+				testutil.AssertStringDoesntContain(t, stderr, testcase.dontWantStderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package testutil
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// UpdateGolden indicates the golden files should be rewritten with the output
+// of the test rather than compared against it (go test ./pkg/... -update).
+var UpdateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+// AssertGolden fatals a test if have doesn't match the contents of the golden
+// file at path. The golden file is written instead when -update is set.
+func AssertGolden(t *testing.T, path, have string) {
+	t.Helper()
+	if *UpdateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(have), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// G304 (CWE-22): Potential file inclusion via variable
+	// #nosec
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run the test with -update to create it): %v", err)
+	}
+	AssertString(t, string(want), have)
+}
+
+// AssertCmdGolden fatals a test if the output of a command doesn't match the
+// golden file at path. Both streams are recorded in the one file, under a
+// '-- stdout --' and '-- stderr --' header.
+//
+// The DefaultNormalizers are applied to the output, followed by normalizers
+// in the order given, so that values which change between runs (e.g. the
+// time) don't fail the comparison.
+func AssertCmdGolden(t *testing.T, path, stdout, stderr string, normalizers ...Normalizer) {
+	t.Helper()
+	normalize := Normalizers(append(DefaultNormalizers(), normalizers...)...)
+	AssertGolden(t, path, "-- stdout --\n"+normalize(stdout)+"-- stderr --\n"+normalize(stderr))
+}
+
+// Normalizer rewrites the volatile parts of command output as a fixed
+// placeholder.
+type Normalizer func(string) string
+
+// Normalizers composes normalizers into one that applies each in order.
+func Normalizers(normalizers ...Normalizer) Normalizer {
+	return func(s string) string {
+		for _, n := range normalizers {
+			s = n(s)
+		}
+		return s
+	}
+}
+
+// RegexpNormalizer returns a normalizer that replaces the matches of pattern
+// with replacement (which can refer to submatches, as in
+// regexp.Regexp.ReplaceAllString).
+func RegexpNormalizer(pattern, replacement string) Normalizer {
+	re := regexp.MustCompile(pattern)
+	return func(s string) string {
+		return re.ReplaceAllString(s, replacement)
+	}
+}
+
+// DefaultNormalizers returns the normalizers applied by AssertCmdGolden. The
+// order matters: a timestamp is replaced before its time of day can be taken
+// for a duration or version.
+func DefaultNormalizers() []Normalizer {
+	return []Normalizer{
+		NormalizeTimestamps,
+		NormalizeDurations,
+		NormalizeTempDirs,
+		NormalizeVersions,
+	}
+}
+
+var (
+	// NormalizeTimestamps replaces RFC 3339 timestamps with <TIMESTAMP>, as
+	// well as those with a space rather than a 'T' and without seconds (e.g.
+	// the time.Format used by the CLI).
+	NormalizeTimestamps = RegexpNormalizer(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}(:\d{2}(\.\d+)?)?(Z|[+-]\d{2}:?\d{2})?`, "<TIMESTAMP>")
+
+	// NormalizeDurations replaces durations formatted by time.Duration (e.g.
+	// '1.2s' or '1m30s') with X.
+	NormalizeDurations = RegexpNormalizer(`\b(\d+(\.\d+)?(ns|us|µs|ms|h|m|s))+\b`, "X")
+)
+
+// NormalizeTempDirs replaces the paths of temporary directories (e.g. those
+// created by t.TempDir or os.MkdirTemp) with <TMPDIR>. The rest of a path
+// within the directory is kept.
+func NormalizeTempDirs(s string) string {
+	for _, dir := range tempDirs() {
+		// A temporary directory is created in dir with a random suffix, and
+		// t.TempDir adds a numbered subdirectory.
+		re := regexp.MustCompile(regexp.QuoteMeta(dir) + `[/\\][^/\\\s"']+([/\\]\d{3})?`)
+		s = re.ReplaceAllString(s, "<TMPDIR>")
+	}
+	return s
+}
+
+// tempDirs returns the directory for temporary files, and the directory it
+// resolves to if it's a symlink (e.g. /var is /private/var on macOS).
+func tempDirs() []string {
+	dir := filepath.Clean(os.TempDir())
+	dirs := []string{dir}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil && resolved != dir {
+		// The longer path is replaced first, as it can contain the other.
+		dirs = []string{resolved, dir}
+		if len(dir) > len(resolved) {
+			dirs = []string{dir, resolved}
+		}
+	}
+	return dirs
+}
+
+var versionPattern = regexp.MustCompile(`\bv?\d+\.\d+\.\d+(-[0-9A-Za-z]+(\.[0-9A-Za-z]+)*)?`)
+
+// NormalizeVersions replaces semantic versions (e.g. 'v10.8.1' or
+// '1.2.3-beta.1') with <VERSION>. The first three numbers of a longer dotted
+// sequence, such as an IP address, aren't replaced.
+func NormalizeVersions(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range versionPattern.FindAllStringIndex(s, -1) {
+		start, end := m[0], m[1]
+		if (start > 0 && s[start-1] == '.') || (end < len(s) && s[end] == '.' && end+1 < len(s) && isDigit(s[end+1])) {
+			continue
+		}
+		b.WriteString(s[last:start])
+		b.WriteString("<VERSION>")
+		last = end
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
@@ -0,0 +1,108 @@
+package testutil_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fastly/go-fastly/v9/fastly"
+
+	"github.com/fastly/cli/pkg/mock"
+	"github.com/fastly/cli/pkg/testutil"
+)
+
+func TestNormalizers(t *testing.T) {
+	tmp := t.TempDir()
+	scenarios := []struct {
+		name       string
+		normalizer testutil.Normalizer
+		input      string
+		want       string
+	}{
+		{
+			name:       "timestamp",
+			normalizer: testutil.NormalizeTimestamps,
+			input:      "Created: 2021-06-15T23:00:00Z, Updated: 2024-01-02 03:04:05.123+01:00, Deleted: 2024-01-02 03:04",
+			want:       "Created: <TIMESTAMP>, Updated: <TIMESTAMP>, Deleted: <TIMESTAMP>",
+		},
+		{
+			name:       "duration",
+			normalizer: testutil.NormalizeDurations,
+			input:      "Built in 1.2s, deployed in 1m30.5s (waited 350ms)",
+			want:       "Built in X, deployed in X (waited X)",
+		},
+		{
+			name:       "duration in a word",
+			normalizer: testutil.NormalizeDurations,
+			input:      "service 12ms34 has 3 versions",
+			want:       "service 12ms34 has 3 versions",
+		},
+		{
+			name:       "temp dir",
+			normalizer: testutil.NormalizeTempDirs,
+			input:      fmt.Sprintf("Wrote %s", filepath.Join(tmp, "fastly.toml")),
+			want:       fmt.Sprintf("Wrote %s", filepath.Join("<TMPDIR>", "fastly.toml")),
+		},
+		{
+			name:       "version",
+			normalizer: testutil.NormalizeVersions,
+			input:      "Fastly CLI version v10.8.1 (go1.22.0), Viceroy 0.9.4-beta.1",
+			want:       "Fastly CLI version <VERSION> (go1.22.0), Viceroy <VERSION>",
+		},
+		{
+			name:       "IP address",
+			normalizer: testutil.NormalizeVersions,
+			input:      "Address: 127.0.0.1:8080",
+			want:       "Address: 127.0.0.1:8080",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			testutil.AssertString(t, testcase.want, testcase.normalizer(testcase.input))
+		})
+	}
+}
+
+func TestNormalizersOrder(t *testing.T) {
+	upper := testutil.Normalizer(strings.ToUpper)
+	redact := testutil.RegexpNormalizer(`secret`, "<REDACTED>")
+
+	// The normalizers are applied in the order given.
+	testutil.AssertString(t, "A <REDACTED>", testutil.Normalizers(redact, upper)("a secret"))
+	testutil.AssertString(t, "A SECRET", testutil.Normalizers(upper, redact)("a secret"))
+
+	// A timestamp mustn't be mistaken for a duration or version.
+	normalize := testutil.Normalizers(testutil.DefaultNormalizers()...)
+	testutil.AssertString(t, "at <TIMESTAMP> in X", normalize("at 2021-06-15T23:00:00.5Z in 2s"))
+}
+
+// TestAssertCmdGolden runs the same command at two different times, in
+// different directories, and compares both against the one golden file.
+func TestAssertCmdGolden(t *testing.T) {
+	golden := filepath.Join("testdata", "golden", "service-describe.golden")
+	for _, now := range []time.Time{testutil.Date, time.Now()} {
+		created, updated := now.Add(-time.Hour), now
+		api := mock.API{
+			GetServiceDetailsFn: func(i *fastly.GetServiceInput) (*fastly.ServiceDetail, error) {
+				return &fastly.ServiceDetail{
+					ServiceID: fastly.ToPointer(i.ServiceID),
+					Name:      fastly.ToPointer("Foo"),
+					CreatedAt: &created,
+					UpdatedAt: &updated,
+				}, nil
+			},
+		}
+		stdout, stderr, exitCode := testutil.RunCLI(t, testutil.Args("service describe --service-id 123"), testutil.CLIOpts{API: api})
+		testutil.AssertExitCode(t, 0, exitCode)
+
+		// Output such as a build report, which differs on every run.
+		elapsed := time.Since(now.Truncate(time.Minute)).Round(time.Millisecond)
+		stdout += fmt.Sprintf("Wrote %s in %s\n", filepath.Join(t.TempDir(), "bin", "main.wasm"), elapsed)
+		stderr += fmt.Sprintf("WARNING: version %s of the CLI is available.\n", fmt.Sprintf("v10.%d.0", now.Minute()))
+
+		redactID := testutil.RegexpNormalizer(`ID: \d+`, "ID: <ID>")
+		testutil.AssertCmdGolden(t, golden, stdout, stderr, redactID)
+	}
+}
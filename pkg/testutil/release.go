@@ -0,0 +1,133 @@
+package testutil
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// ReleaseServer is a fake release server for commands that download binaries
+// (see github.Asset). It serves the DevHub metadata of the latest version, and
+// the release asset and its published checksum for any version.
+//
+// The asset contains a shell script, as the binary, which prints
+// `<binary> <version>` (so it can be executed with --version).
+type ReleaseServer struct {
+	// BadChecksum lists the versions whose published checksum doesn't match
+	// the release asset.
+	BadChecksum map[string]bool
+	// NoChecksum lists the versions without a published checksum.
+	NoChecksum map[string]bool
+
+	binary string
+	latest string
+	mu     sync.Mutex
+	paths  []string
+	server *httptest.Server
+}
+
+// NewReleaseServer returns a started release server for the binary, with
+// latest as the latest version. The server is closed when the test ends.
+func NewReleaseServer(t *testing.T, binary, latest string) *ReleaseServer {
+	t.Helper()
+	s := &ReleaseServer{
+		BadChecksum: make(map[string]bool),
+		NoChecksum:  make(map[string]bool),
+		binary:      binary,
+		latest:      latest,
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+// Client returns an HTTP client that sends every request to the server,
+// whatever the host of the request URL.
+func (s *ReleaseServer) Client() *http.Client {
+	return &http.Client{Transport: rewriteTransport{host: strings.TrimPrefix(s.server.URL, "http://")}}
+}
+
+// Paths returns the path of each request the server received.
+func (s *ReleaseServer) Paths() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.paths...)
+}
+
+// Checksum returns the hex-encoded SHA-256 checksum of the release asset.
+func (s *ReleaseServer) Checksum(version string) string {
+	sum := sha256.Sum256(s.asset(version))
+	return hex.EncodeToString(sum[:])
+}
+
+// assetName returns the file name of the release asset.
+func (s *ReleaseServer) assetName(version string) string {
+	return fmt.Sprintf("%s_v%s_%s-%s.tar.gz", s.binary, version, runtime.GOOS, runtime.GOARCH)
+}
+
+// asset returns the release asset, a .tar.gz containing the binary.
+func (s *ReleaseServer) asset(version string) []byte {
+	script := fmt.Sprintf("#!/bin/sh\necho '%s %s'\n", s.binary, version)
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	_ = tw.WriteHeader(&tar.Header{Name: s.binary, Mode: 0o755, Size: int64(len(script))})
+	_, _ = tw.Write([]byte(script))
+	_ = tw.Close()
+	_ = gw.Close()
+	return buf.Bytes()
+}
+
+func (s *ReleaseServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.paths = append(s.paths, r.URL.Path)
+	s.mu.Unlock()
+
+	// The release asset path is /<binary>/v<version>/<asset name>.
+	version := strings.TrimPrefix(path.Base(path.Dir(r.URL.Path)), "v")
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/api/internal/releases/meta/"):
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"url":     fmt.Sprintf("https://releases.example.com/%s/v%s/%s", s.binary, s.latest, s.assetName(s.latest)),
+			"version": s.latest,
+		})
+	case strings.HasSuffix(r.URL.Path, ".tar.gz.sha256"):
+		if s.NoChecksum[version] {
+			http.NotFound(w, r)
+			return
+		}
+		checksum := s.Checksum(version)
+		if s.BadChecksum[version] {
+			checksum = strings.Repeat("0", len(checksum))
+		}
+		fmt.Fprintf(w, "%s  %s\n", checksum, s.assetName(version))
+	case strings.HasSuffix(r.URL.Path, ".tar.gz"):
+		_, _ = w.Write(s.asset(version))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// rewriteTransport sends requests to host (over HTTP).
+type rewriteTransport struct {
+	host string
+}
+
+func (rt rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme = "http"
+	r.URL.Host = rt.host
+	r.Host = rt.host
+	return http.DefaultTransport.RoundTrip(r)
+}
@@ -19,15 +19,18 @@ var BoldYellow = color.New(color.Bold, color.FgYellow).SprintFunc()
 // BoldGreen is a Sprint-class function that makes the arguments bold and green.
 var BoldGreen = color.New(color.Bold, color.FgGreen).SprintFunc()
 
+// Faint is a Sprint-class function that dims the arguments.
+var Faint = color.New(color.Faint).SprintFunc()
+
 // Reset is a Sprint-class function that resets the color for the arguments.
 var Reset = color.New(color.Reset).SprintFunc()
 
-// Prompt is a Sprint-class function that makes the arguments bold and uses the
-// default colour for the terminal.
+// Prompt is a Sprint-class function that styles the arguments as the prefix of
+// an interactive prompt (see RolePrefix).
 //
-// IMPORTANT: Be careful modifying with Black or White as this can break themes.
-// e.g. Black with Solarized Dark makes the text invisible!
-var Prompt = color.New(color.Bold).SprintFunc()
+// IMPORTANT: Be careful styling prompts with Black or White as this can break
+// terminal themes. e.g. Black with Solarized Dark makes the text invisible!
+var Prompt = PrefixStyle
 
 // ColorFn is a function returned from a color.SprintFunc() call.
 type ColorFn func(a ...any) string
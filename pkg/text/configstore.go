@@ -15,7 +15,7 @@ import (
 // PrintConfigStoresTbl displays store data in a table format.
 func PrintConfigStoresTbl(out io.Writer, stores []*fastly.ConfigStore) {
 	tbl := NewTable(out)
-	tbl.AddHeader("Name", "ID", "Created (UTC)", "Updated (UTC)")
+	tbl.AddHeader("Name", "ID", TimeLabel("Created"), TimeLabel("Updated"))
 
 	if stores == nil {
 		tbl.Print()
@@ -36,8 +36,8 @@ func PrintConfigStore(out io.Writer, cs *fastly.ConfigStore, csm *fastly.ConfigS
 
 	fmt.Fprintf(out, "Name: %s\n", cs.Name)
 	fmt.Fprintf(out, "ID: %s\n", cs.StoreID)
-	fmt.Fprintf(out, "Created (UTC): %s\n", fmtConfigStoreTime(cs.CreatedAt))
-	fmt.Fprintf(out, "Updated (UTC): %s\n", fmtConfigStoreTime(cs.UpdatedAt))
+	fmt.Fprintf(out, "%s: %s\n", TimeLabel("Created"), fmtConfigStoreTime(cs.CreatedAt))
+	fmt.Fprintf(out, "%s: %s\n", TimeLabel("Updated"), fmtConfigStoreTime(cs.UpdatedAt))
 	if csm != nil {
 		fmt.Fprintf(out, "Item Count: %d\n", csm.ItemCount)
 	}
@@ -57,13 +57,13 @@ func fmtConfigStoreTime(t *time.Time) string {
 	if t == nil {
 		return "n/a"
 	}
-	return t.UTC().Format(fsttime.Format)
+	return FormatTimeLayout(t, fsttime.Format)
 }
 
 // PrintConfigStoreItemsTbl displays store item data in a table format.
 func PrintConfigStoreItemsTbl(out io.Writer, items []*fastly.ConfigStoreItem) {
 	tbl := NewTable(out)
-	tbl.AddHeader("Key", "Value", "Created (UTC)", "Updated (UTC)")
+	tbl.AddHeader("Key", "Value", TimeLabel("Created"), TimeLabel("Updated"))
 
 	if items == nil {
 		tbl.Print()
@@ -100,9 +100,9 @@ func PrintConfigStoreItem(out io.Writer, prefix string, csi *fastly.ConfigStoreI
 	fmt.Fprintf(out, "StoreID: %s\n", csi.StoreID)
 	fmt.Fprintf(out, "Key: %s\n", csi.Key)
 	fmt.Fprintf(out, "Value: %s\n", csi.Value)
-	fmt.Fprintf(out, "Created (UTC): %s\n", fmtConfigStoreTime(csi.CreatedAt))
-	fmt.Fprintf(out, "Updated (UTC): %s\n", fmtConfigStoreTime(csi.UpdatedAt))
+	fmt.Fprintf(out, "%s: %s\n", TimeLabel("Created"), fmtConfigStoreTime(csi.CreatedAt))
+	fmt.Fprintf(out, "%s: %s\n", TimeLabel("Updated"), fmtConfigStoreTime(csi.UpdatedAt))
 	if csi.DeletedAt != nil {
-		fmt.Fprintf(out, "Deleted (UTC): %s\n", fmtConfigStoreTime(csi.DeletedAt))
+		fmt.Fprintf(out, "%s: %s\n", TimeLabel("Deleted"), fmtConfigStoreTime(csi.DeletedAt))
 	}
 }
@@ -19,9 +19,9 @@ func PrintDictionary(out io.Writer, prefix string, d *fastly.Dictionary) {
 	fmt.Fprintf(out, "ID: %s\n", fastly.ToValue(d.DictionaryID))
 	fmt.Fprintf(out, "Name: %s\n", fastly.ToValue(d.Name))
 	fmt.Fprintf(out, "Write Only: %t\n", fastly.ToValue(d.WriteOnly))
-	fmt.Fprintf(out, "Created (UTC): %s\n", d.CreatedAt.UTC().Format(time.Format))
-	fmt.Fprintf(out, "Last edited (UTC): %s\n", d.UpdatedAt.UTC().Format(time.Format))
+	fmt.Fprintf(out, "%s: %s\n", TimeLabel("Created"), FormatTimeLayout(d.CreatedAt, time.Format))
+	fmt.Fprintf(out, "%s: %s\n", TimeLabel("Last edited"), FormatTimeLayout(d.UpdatedAt, time.Format))
 	if d.DeletedAt != nil {
-		fmt.Fprintf(out, "Deleted (UTC): %s\n", d.DeletedAt.UTC().Format(time.Format))
+		fmt.Fprintf(out, "%s: %s\n", TimeLabel("Deleted"), FormatTimeLayout(d.DeletedAt, time.Format))
 	}
 }
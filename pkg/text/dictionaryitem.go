@@ -20,13 +20,13 @@ func PrintDictionaryItem(out io.Writer, prefix string, d *fastly.DictionaryItem)
 	fmt.Fprintf(out, "Item Key: %s\n", fastly.ToValue(d.ItemKey))
 	fmt.Fprintf(out, "Item Value: %s\n", fastly.ToValue(d.ItemValue))
 	if d.CreatedAt != nil {
-		fmt.Fprintf(out, "Created (UTC): %s\n", d.CreatedAt.UTC().Format(time.Format))
+		fmt.Fprintf(out, "%s: %s\n", TimeLabel("Created"), FormatTimeLayout(d.CreatedAt, time.Format))
 	}
 	if d.UpdatedAt != nil {
-		fmt.Fprintf(out, "Last edited (UTC): %s\n", d.UpdatedAt.UTC().Format(time.Format))
+		fmt.Fprintf(out, "%s: %s\n", TimeLabel("Last edited"), FormatTimeLayout(d.UpdatedAt, time.Format))
 	}
 	if d.DeletedAt != nil {
-		fmt.Fprintf(out, "Deleted (UTC): %s\n", d.DeletedAt.UTC().Format(time.Format))
+		fmt.Fprintf(out, "%s: %s\n", TimeLabel("Deleted"), FormatTimeLayout(d.DeletedAt, time.Format))
 	}
 }
 
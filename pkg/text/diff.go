@@ -0,0 +1,132 @@
+package text
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DiffContext is the number of unchanged lines displayed around each change.
+const DiffContext = 3
+
+// diffOp is a single line of a diff.
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	line string
+}
+
+// Diff writes a unified diff of from and to, with removed and added lines
+// styled by the theme (see RoleDiffDel and RoleDiffAdd). Nothing is written if
+// the content is identical.
+func Diff(w io.Writer, fromLabel, toLabel, from, to string) {
+	if from == to {
+		return
+	}
+	ops := diffLines(splitLines(from), splitLines(to))
+
+	fmt.Fprintln(w, DiffDelStyle("--- "+fromLabel))
+	fmt.Fprintln(w, DiffAddStyle("+++ "+toLabel))
+
+	// NOTE: Each hunk spans the changed lines plus DiffContext unchanged
+	// lines either side. Hunks whose context overlaps are merged.
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		start := max(i-DiffContext, 0)
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*DiffContext {
+				end = min(end+DiffContext, len(ops))
+				break
+			}
+			end = next
+		}
+
+		fromStart, toStart := lineNumbers(ops[:start])
+		fromLen, toLen := lineNumbers(ops[start:end])
+		fmt.Fprintln(w, InfoStyle(fmt.Sprintf("@@ -%d,%d +%d,%d @@", fromStart+1, fromLen, toStart+1, toLen)))
+		for _, op := range ops[start:end] {
+			switch op.kind {
+			case '-':
+				fmt.Fprintln(w, DiffDelStyle("-"+op.line))
+			case '+':
+				fmt.Fprintln(w, DiffAddStyle("+"+op.line))
+			default:
+				fmt.Fprintln(w, " "+op.line)
+			}
+		}
+		i = end
+	}
+}
+
+// diffLines returns the operations transforming a into b, using the longest
+// common subsequence of lines.
+func diffLines(a, b []string) []diffOp {
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, max(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+// lineNumbers counts the lines of the original and new content in ops.
+func lineNumbers(ops []diffOp) (from, to int) {
+	for _, op := range ops {
+		if op.kind != '+' {
+			from++
+		}
+		if op.kind != '-' {
+			to++
+		}
+	}
+	return from, to
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
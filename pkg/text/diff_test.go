@@ -0,0 +1,73 @@
+package text_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/text"
+)
+
+func TestDiff(t *testing.T) {
+	lines := func(n int) []string {
+		var ls []string
+		for i := 1; i <= n; i++ {
+			ls = append(ls, "line "+strings.Repeat("x", i))
+		}
+		return ls
+	}
+	from := lines(12)
+	to := append([]string{}, from...)
+	to[1] = "changed"
+	to = append(to, "added")
+
+	for _, testcase := range []struct {
+		name       string
+		from       string
+		to         string
+		wantOutput string
+	}{
+		{
+			name: "identical",
+			from: "a\nb\n",
+			to:   "a\nb\n",
+		},
+		{
+			name: "separate hunks",
+			from: strings.Join(from, "\n") + "\n",
+			to:   strings.Join(to, "\n") + "\n",
+			wantOutput: `--- remote
++++ local
+@@ -1,5 +1,5 @@
+ line x
+-line xx
++changed
+ line xxx
+ line xxxx
+ line xxxxx
+@@ -10,3 +10,4 @@
+ line xxxxxxxxxx
+ line xxxxxxxxxxx
+ line xxxxxxxxxxxx
++added
+`,
+		},
+		{
+			name: "from empty",
+			from: "",
+			to:   "a\n",
+			wantOutput: `--- remote
++++ local
+@@ -1,0 +1,1 @@
++a
+`,
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			text.Diff(&buf, "remote", "local", testcase.from, testcase.to)
+			testutil.AssertString(t, testcase.wantOutput, buf.String())
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package text
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+
+	"golang.org/x/term"
+)
+
+// ErrNonInteractive means a prompt needed an answer but the CLI isn't running
+// interactively, so it wasn't displayed.
+var ErrNonInteractive = errors.New("input required but the CLI isn't running interactively")
+
+// NonInteractiveError is returned by a prompt that can't be answered, rather
+// than waiting for input that will never arrive (e.g. when standard input is a
+// pipe that's never closed).
+type NonInteractiveError struct {
+	// Flag supplies the value the prompt asked for (e.g. --name), if any.
+	Flag string
+	// Reason describes why the CLI isn't running interactively.
+	Reason string
+}
+
+// Error implements the error interface.
+func (e NonInteractiveError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrNonInteractive, e.Reason)
+}
+
+// Unwrap returns ErrNonInteractive.
+func (e NonInteractiveError) Unwrap() error {
+	return ErrNonInteractive
+}
+
+// Remediation describes how to supply the value without a prompt.
+func (e NonInteractiveError) Remediation() string {
+	if e.Flag == "" {
+		return "Run the command in a terminal, or pass --interactive to answer the prompts from standard input."
+	}
+	return fmt.Sprintf("Pass %s to supply the value, or run the command in a terminal.", e.Flag)
+}
+
+// InteractiveMode controls when prompts read from a file (e.g. standard
+// input). Prompts reading from any other io.Reader are always scripted, so
+// they're unaffected.
+type InteractiveMode int32
+
+const (
+	// InteractiveAuto prompts when the input is a terminal or a regular file.
+	InteractiveAuto InteractiveMode = iota
+	// InteractiveNever prompts only when the input is a regular file (e.g.
+	// with --non-interactive or in a CI environment).
+	InteractiveNever
+	// InteractiveAlways prompts whatever the input is (e.g. with
+	// --interactive, to answer the prompts from a pipe).
+	InteractiveAlways
+)
+
+// interactive is the current InteractiveMode (see SetInteractive).
+var interactive atomic.Int32
+
+// SetInteractive controls when prompts read from a file. It's set from the
+// global --interactive and --non-interactive flags.
+func SetInteractive(m InteractiveMode) {
+	interactive.Store(int32(m))
+}
+
+// checkInteractive returns a NonInteractiveError if a prompt can't be answered
+// from r, naming the flag that supplies the value instead.
+func checkInteractive(r io.Reader, flag string) error {
+	f, ok := r.(*os.File)
+	if !ok {
+		return nil
+	}
+	mode := InteractiveMode(interactive.Load())
+	if mode == InteractiveAlways {
+		return nil
+	}
+	if fi, err := f.Stat(); err == nil && fi.Mode().IsRegular() {
+		return nil // the answers are read from a file
+	}
+	if mode == InteractiveNever {
+		return NonInteractiveError{Flag: flag, Reason: "non-interactive mode is enabled"}
+	}
+	if !term.IsTerminal(int(f.Fd())) {
+		return NonInteractiveError{Flag: flag, Reason: "standard input isn't a terminal"}
+	}
+	return nil
+}
+
+// promptContext is the context prompts stop waiting on (see SetContext).
+var promptContext atomic.Pointer[context.Context]
+
+// SetContext sets the context prompts are answered within. When it's cancelled
+// (e.g. on Ctrl-C) a prompt waiting for input returns its cause as an error.
+func SetContext(ctx context.Context) {
+	promptContext.Store(&ctx)
+}
+
+// currentContext returns the context set by SetContext, or a context that's
+// never cancelled if there isn't one.
+func currentContext() context.Context {
+	if ctx := promptContext.Load(); ctx != nil && *ctx != nil {
+		return *ctx
+	}
+	return context.Background()
+}
+
+// scanLine reads the next line from s, giving up if the prompt context is
+// cancelled first. The read is left running in that case, as the CLI is about
+// to exit. ok is false when no line could be read.
+func scanLine(s *bufio.Scanner) (line string, ok bool, err error) {
+	ctx := currentContext()
+	if ctx.Done() == nil {
+		if !s.Scan() {
+			return "", false, s.Err()
+		}
+		return s.Text(), true, nil
+	}
+
+	type result struct {
+		line string
+		ok   bool
+		err  error
+	}
+	c := make(chan result, 1)
+	go func() {
+		if !s.Scan() {
+			c <- result{err: s.Err()}
+			return
+		}
+		c <- result{line: s.Text(), ok: true}
+	}()
+
+	select {
+	case r := <-c:
+		return r.line, r.ok, r.err
+	case <-ctx.Done():
+		return "", false, context.Cause(ctx)
+	}
+}
+
+// readPassword reads a line from the terminal fd without echoing it, giving up
+// if the prompt context is cancelled first. The terminal's state is restored in
+// that case, so the user's shell isn't left with echo disabled.
+func readPassword(fd int) ([]byte, error) {
+	ctx := currentContext()
+	if ctx.Done() == nil {
+		return term.ReadPassword(fd)
+	}
+
+	state, err := term.GetState(fd)
+	if err != nil {
+		return nil, err
+	}
+	type result struct {
+		p   []byte
+		err error
+	}
+	c := make(chan result, 1)
+	go func() {
+		p, err := term.ReadPassword(fd)
+		c <- result{p, err}
+	}()
+
+	select {
+	case r := <-c:
+		return r.p, r.err
+	case <-ctx.Done():
+		_ = term.Restore(fd, state)
+		return nil, context.Cause(ctx)
+	}
+}
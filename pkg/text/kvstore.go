@@ -18,8 +18,8 @@ func PrintKVStore(out io.Writer, prefix string, k *fastly.KVStore) {
 
 	fmt.Fprintf(out, "\nID: %s\n", k.StoreID)
 	fmt.Fprintf(out, "Name: %s\n", k.Name)
-	fmt.Fprintf(out, "Created (UTC): %s\n", k.CreatedAt.UTC().Format(time.Format))
-	fmt.Fprintf(out, "Last edited (UTC): %s\n", k.UpdatedAt.UTC().Format(time.Format))
+	fmt.Fprintf(out, "%s: %s\n", TimeLabel("Created"), FormatTimeLayout(k.CreatedAt, time.Format))
+	fmt.Fprintf(out, "%s: %s\n", TimeLabel("Last edited"), FormatTimeLayout(k.UpdatedAt, time.Format))
 }
 
 // PrintKVStoreKeys pretty prints a list of kv store keys in verbose
@@ -0,0 +1,152 @@
+package text
+
+import (
+	"io"
+	"sync"
+)
+
+// ansiReset is the ANSI escape sequence that resets all colors/attributes.
+const ansiReset = "\x1b[0m"
+
+// LineWriter is an io.Writer that keeps the output tidy, regardless of which
+// helpers (or raw fmt.Fprintf calls) produced it. It:
+//
+//   - tracks whether the last byte written was a newline (see EnsureNewline).
+//   - collapses runs of more than one blank line into a single blank line.
+//   - tracks whether an ANSI color was left open (see Finish).
+//
+// The CLI wraps its standard output with a LineWriter, and the output helpers
+// in this package (e.g. Error, Output, Success, Warning) use it when given one.
+type LineWriter struct {
+	// W is the underlying writer.
+	W io.Writer
+
+	mu sync.Mutex
+	// colored indicates an ANSI color sequence hasn't yet been reset.
+	colored bool
+	// esc buffers an ANSI escape sequence that spans multiple writes.
+	esc []byte
+	// newlines is the number of consecutive newlines last written.
+	newlines int
+	// written indicates something has been written.
+	written bool
+}
+
+// NewLineWriter returns a LineWriter wrapping w.
+func NewLineWriter(w io.Writer) *LineWriter {
+	return &LineWriter{W: w}
+}
+
+// LineDiscipline returns w if it's already a LineWriter, otherwise a new
+// LineWriter that wraps w (and so only tidies the output of the current call).
+func LineDiscipline(w io.Writer) *LineWriter {
+	if lw, ok := w.(*LineWriter); ok {
+		return lw
+	}
+	return NewLineWriter(w)
+}
+
+// Write implements the io.Writer interface.
+//
+// NOTE: The returned byte count is always len(p) (on success) even when
+// newlines are dropped, as the caller's data was handled as intended.
+func (lw *LineWriter) Write(p []byte) (int, error) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+
+	out := make([]byte, 0, len(p))
+	for _, b := range p {
+		switch {
+		case lw.esc != nil:
+			lw.esc = append(lw.esc, b)
+			if b >= 0x40 && b <= 0x7e && len(lw.esc) > 2 {
+				lw.trackColor(lw.esc)
+				lw.esc = nil
+			}
+		case b == 0x1b:
+			lw.esc = []byte{b}
+		case b == '\n':
+			// A run of three newlines is two blank lines.
+			if lw.newlines >= 2 {
+				continue
+			}
+			lw.newlines++
+		default:
+			lw.newlines = 0
+		}
+		out = append(out, b)
+	}
+	if len(out) == 0 {
+		return len(p), nil
+	}
+	lw.written = true
+	if _, err := lw.W.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// echoed records that the terminal has moved to a new line without anything
+// being written (e.g. the user pressed Enter at a prompt).
+func (lw *LineWriter) echoed() {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	if lw.newlines == 0 {
+		lw.newlines = 1
+	}
+}
+
+// trackColor records whether the SGR escape sequence seq (e.g. "\x1b[1;31m")
+// opens or resets a color.
+func (lw *LineWriter) trackColor(seq []byte) {
+	if seq[1] != '[' || seq[len(seq)-1] != 'm' {
+		return
+	}
+	params := string(seq[2 : len(seq)-1])
+	lw.colored = params != "" && params != "0"
+}
+
+// EnsureNewline writes a newline if anything has been written and the last
+// byte written wasn't a newline (i.e. output was left mid-line).
+func (lw *LineWriter) EnsureNewline() {
+	lw.mu.Lock()
+	needed := lw.written && lw.newlines == 0
+	lw.mu.Unlock()
+	if needed {
+		_, _ = lw.Write([]byte("\n"))
+	}
+}
+
+// ResetColor writes an ANSI reset if a color was left open (e.g. a helper was
+// interrupted part way through writing colored output).
+func (lw *LineWriter) ResetColor() {
+	lw.mu.Lock()
+	needed := lw.colored
+	lw.mu.Unlock()
+	if needed {
+		_, _ = lw.Write([]byte(ansiReset))
+	}
+}
+
+// Finish restores the terminal state once all output is written, so that the
+// user's shell prompt isn't left mid-line or colored.
+func (lw *LineWriter) Finish() {
+	lw.ResetColor()
+	lw.EnsureNewline()
+}
+
+// EnsureNewline writes a newline to w if it's a LineWriter whose output was
+// left mid-line. It's a no-op for any other io.Writer.
+func EnsureNewline(w io.Writer) {
+	if lw, ok := w.(*LineWriter); ok {
+		lw.EnsureNewline()
+	}
+}
+
+// Finish calls Finish on w if it's a LineWriter.
+// It's a no-op for any other io.Writer.
+func Finish(w io.Writer) {
+	if lw, ok := w.(*LineWriter); ok {
+		lw.Finish()
+	}
+}
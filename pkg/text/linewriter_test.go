@@ -0,0 +1,95 @@
+package text_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/text"
+)
+
+func TestLineWriter(t *testing.T) {
+	for _, testcase := range []struct {
+		name       string
+		write      func(w *text.LineWriter)
+		wantOutput string
+	}{
+		{
+			name: "helpers start on a new line",
+			write: func(w *text.LineWriter) {
+				fmt.Fprint(w, "Uploading...")
+				text.Error(w, "upload failed")
+				fmt.Fprint(w, "partial")
+				text.Output(w, "done")
+				fmt.Fprint(w, "partial")
+				text.Warning(w, "careful")
+			},
+			wantOutput: "Uploading...\nERROR: upload failed\npartial\ndone\npartial\nWARNING: careful\n",
+		},
+		{
+			name: "blank lines collapse",
+			write: func(w *text.LineWriter) {
+				text.Output(w, "one\n\n")
+				text.Break(w)
+				text.Info(w, "\ntwo\n\n")
+				text.BreakN(w, 3)
+				text.Success(w, "three")
+			},
+			wantOutput: "one\n\nINFO: two\n\nSUCCESS: three\n",
+		},
+		{
+			name: "ensure newline",
+			write: func(w *text.LineWriter) {
+				w.EnsureNewline() // nothing written yet
+				fmt.Fprint(w, "a")
+				w.EnsureNewline()
+				w.EnsureNewline()
+				fmt.Fprint(w, "b\n")
+				text.EnsureNewline(w)
+			},
+			wantOutput: "a\nb\n",
+		},
+		{
+			name: "prompt answered",
+			write: func(w *text.LineWriter) {
+				_, _ = text.Input(w, "Name: ", strings.NewReader("foo\n"), "--name")
+				text.Output(w, "Hello")
+			},
+			wantOutput: "Name: Hello\n",
+		},
+		{
+			name: "color left open is reset",
+			write: func(w *text.LineWriter) {
+				fmt.Fprint(w, "\x1b[1;31mERR")
+				w.Finish()
+			},
+			wantOutput: "\x1b[1;31mERR\x1b[0m\n",
+		},
+		{
+			name: "color sequence split across writes",
+			write: func(w *text.LineWriter) {
+				fmt.Fprint(w, "\x1b[3")
+				fmt.Fprint(w, "2mok\x1b[")
+				fmt.Fprint(w, "0m\n")
+				w.Finish()
+			},
+			wantOutput: "\x1b[32mok\x1b[0m\n",
+		},
+		{
+			name: "reset color",
+			write: func(w *text.LineWriter) {
+				fmt.Fprint(w, "\x1b[1mbold\x1b[0m\n")
+				text.Finish(w)
+			},
+			wantOutput: "\x1b[1mbold\x1b[0m\n",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			testcase.write(text.NewLineWriter(&buf))
+			testutil.AssertString(t, testcase.wantOutput, buf.String())
+		})
+	}
+}
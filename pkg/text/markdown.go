@@ -0,0 +1,104 @@
+package text
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Markdown writes src to the writer, rendering a small subset of Markdown for
+// the terminal. It's intended for long-form text bundled with the CLI (e.g.
+// the explanations displayed by `fastly explain`), not arbitrary documents.
+//
+// The supported syntax is:
+//
+//   - headings ('# Title' and '## Section'), displayed in bold
+//   - paragraphs, which are wrapped at the prose width (see SetWidth)
+//   - list items ('- item' or '* item'), wrapped with a hanging indent
+//   - numbered list items ('1. item'), wrapped with a hanging indent
+//   - fenced code blocks, which are indented and not wrapped
+//   - inline `code` and **bold** spans
+//
+// Anything else is displayed as a paragraph.
+func Markdown(w io.Writer, src string) {
+	var (
+		paragraph []string
+		code      bool
+		prev      string // the kind of the previous block
+	)
+
+	// block starts a block of the given kind, separating it from the previous
+	// block with a blank line (apart from consecutive list items).
+	block := func(kind string) {
+		if prev != "" && (kind != "item" || prev != "item") {
+			fmt.Fprintln(w)
+		}
+		prev = kind
+	}
+	flush := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		block("paragraph")
+		fmt.Fprintln(w, markdownInline(Wrap(strings.Join(paragraph, "\n"), proseWidth())))
+		paragraph = nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			flush()
+			if !code {
+				block("code")
+			}
+			code = !code
+			continue
+		}
+		if code {
+			fmt.Fprintf(w, "    %s\n", strings.TrimRight(line, " \t"))
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "#"):
+			flush()
+			block("heading")
+			fmt.Fprintln(w, Bold(strings.TrimSpace(strings.TrimLeft(trimmed, "#"))))
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			flush()
+			block("item")
+			item := WrapIndent(strings.TrimSpace(trimmed[2:]), proseWidth(), 4)
+			fmt.Fprintf(w, "  - %s\n", markdownInline(strings.TrimPrefix(item, "    ")))
+		case markdownNumbered.MatchString(trimmed):
+			flush()
+			block("item")
+			marker, item, _ := strings.Cut(trimmed, " ")
+			indent := len(marker) + 3
+			item = WrapIndent(strings.TrimSpace(item), proseWidth(), uint(indent))
+			fmt.Fprintf(w, "  %s %s\n", marker, markdownInline(strings.TrimPrefix(item, strings.Repeat(" ", indent))))
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+}
+
+var (
+	markdownNumbered = regexp.MustCompile(`^\d+\. `)
+	markdownCode     = regexp.MustCompile("`([^`]+)`")
+	markdownBold     = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+)
+
+// markdownInline renders the inline spans of a block.
+func markdownInline(s string) string {
+	s = markdownCode.ReplaceAllStringFunc(s, func(m string) string {
+		return BoldCyan(m[1 : len(m)-1])
+	})
+	return markdownBold.ReplaceAllStringFunc(s, func(m string) string {
+		return Bold(m[2 : len(m)-2])
+	})
+}
@@ -0,0 +1,65 @@
+package text_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/text"
+)
+
+func TestMarkdown(t *testing.T) {
+	for _, testcase := range []struct {
+		name       string
+		src        string
+		wantOutput string
+	}{
+		{
+			name:       "heading and paragraphs",
+			src:        "# Title\nFirst paragraph\nwhich continues.\n\n\nSecond paragraph.",
+			wantOutput: "Title\n\nFirst paragraph which continues.\n\nSecond paragraph.\n",
+		},
+		{
+			name:       "list",
+			src:        "Intro:\n- one\n* two\n\nAfter.",
+			wantOutput: "Intro:\n\n  - one\n  - two\n\nAfter.\n",
+		},
+		{
+			name:       "numbered list",
+			src:        "Next:\n1. one\n2. `two`\n\nAfter.",
+			wantOutput: "Next:\n\n  1. one\n  2. two\n\nAfter.\n",
+		},
+		{
+			name:       "wrapped numbered list item",
+			src:        "10. " + strings.Repeat("word ", 30),
+			wantOutput: "  10. " + strings.TrimSpace(strings.Repeat("word ", 23)) + "\n      " + strings.TrimSpace(strings.Repeat("word ", 7)) + "\n",
+		},
+		{
+			name:       "inline spans",
+			src:        "Run `fastly whoami` **now**.",
+			wantOutput: "Run fastly whoami now.\n",
+		},
+		{
+			name:       "code block",
+			src:        "Run:\n```\nfastly compute serve \\\n  --watch\n```\nDone.",
+			wantOutput: "Run:\n\n    fastly compute serve \\\n      --watch\n\nDone.\n",
+		},
+		{
+			name:       "wrapped paragraph",
+			src:        strings.Repeat("word ", 30),
+			wantOutput: strings.TrimSpace(strings.Repeat("word ", 24)) + "\n" + strings.TrimSpace(strings.Repeat("word ", 6)) + "\n",
+		},
+		{
+			name:       "wrapped list item",
+			src:        "- " + strings.Repeat("word ", 30),
+			wantOutput: "  - " + strings.TrimSpace(strings.Repeat("word ", 23)) + "\n    " + strings.TrimSpace(strings.Repeat("word ", 7)) + "\n",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			text.Markdown(&buf, testcase.src)
+			testutil.AssertString(t, testcase.wantOutput, buf.String())
+		})
+	}
+}
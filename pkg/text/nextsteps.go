@@ -0,0 +1,46 @@
+package text
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fastly/cli/pkg/i18n"
+)
+
+// NextStep is a follow-up action suggested once a command succeeds.
+type NextStep struct {
+	// Label describes the action (e.g. "Build the package").
+	Label string `json:"label"`
+	// Command is the command that performs the action (if any).
+	Command string `json:"command,omitempty"`
+	// URL is where to find out more (if anywhere).
+	URL string `json:"url,omitempty"`
+}
+
+// nextStepsHeading heads the list of next steps.
+var nextStepsHeading = i18n.Register("text.next-steps", "Next steps:")
+
+// NextSteps displays the steps as a compact numbered list (see Markdown).
+// Nothing is displayed if there are no steps, or decorative output is
+// suppressed (see SetQuiet).
+func NextSteps(w io.Writer, steps []NextStep) {
+	if len(steps) == 0 || IsQuiet() {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s**\n", i18n.Localize(nextStepsHeading))
+	for i, s := range steps {
+		fmt.Fprintf(&b, "%d. %s", i+1, s.Label)
+		switch {
+		case s.Command != "" && s.URL != "":
+			fmt.Fprintf(&b, ": `%s` (see %s)", s.Command, s.URL)
+		case s.Command != "":
+			fmt.Fprintf(&b, ": `%s`", s.Command)
+		case s.URL != "":
+			fmt.Fprintf(&b, ": %s", s.URL)
+		}
+		b.WriteString("\n")
+	}
+	Markdown(w, b.String())
+}
@@ -0,0 +1,31 @@
+package text_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/text"
+)
+
+func TestNextSteps(t *testing.T) {
+	steps := []text.NextStep{
+		{Label: "Build the package", Command: "fastly compute build"},
+		{Label: "Deploy the package", Command: "fastly compute deploy", URL: "https://www.fastly.com/documentation/reference/cli/compute/deploy/"},
+		{Label: "View the service", URL: "https://example.edgecompute.app"},
+	}
+
+	var buf bytes.Buffer
+	text.NextSteps(&buf, steps)
+	testutil.AssertGolden(t, filepath.Join("testdata", "nextsteps.txt"), buf.String())
+
+	buf.Reset()
+	text.NextSteps(&buf, nil)
+	testutil.AssertString(t, "", buf.String())
+
+	text.SetQuiet(true)
+	defer text.SetQuiet(false)
+	text.NextSteps(&buf, steps)
+	testutil.AssertString(t, "", buf.String())
+}
@@ -27,12 +27,12 @@ func PrintResource(out io.Writer, prefix string, r *fastly.Resource) {
 	fmt.Fprintf(out, "Resource Type: %s\n", fastly.ToValue(r.ResourceType))
 
 	if r.CreatedAt != nil {
-		fmt.Fprintf(out, "Created (UTC): %s\n", r.CreatedAt.UTC().Format(time.Format))
+		fmt.Fprintf(out, "%s: %s\n", TimeLabel("Created"), FormatTimeLayout(r.CreatedAt, time.Format))
 	}
 	if r.UpdatedAt != nil {
-		fmt.Fprintf(out, "Last edited (UTC): %s\n", r.UpdatedAt.UTC().Format(time.Format))
+		fmt.Fprintf(out, "%s: %s\n", TimeLabel("Last edited"), FormatTimeLayout(r.UpdatedAt, time.Format))
 	}
 	if r.DeletedAt != nil {
-		fmt.Fprintf(out, "Deleted (UTC): %s\n", r.DeletedAt.UTC().Format(time.Format))
+		fmt.Fprintf(out, "%s: %s\n", TimeLabel("Deleted"), FormatTimeLayout(r.DeletedAt, time.Format))
 	}
 }
@@ -32,13 +32,13 @@ func PrintService(out io.Writer, prefix string, s *fastly.Service) {
 		fmt.Fprintf(out, "Customer ID: %s\n", *s.CustomerID)
 	}
 	if s.CreatedAt != nil {
-		fmt.Fprintf(out, "Created (UTC): %s\n", s.CreatedAt.UTC().Format(time.Format))
+		fmt.Fprintf(out, "%s: %s\n", TimeLabel("Created"), FormatTimeLayout(s.CreatedAt, time.Format))
 	}
 	if s.UpdatedAt != nil {
-		fmt.Fprintf(out, "Last edited (UTC): %s\n", s.UpdatedAt.UTC().Format(time.Format))
+		fmt.Fprintf(out, "%s: %s\n", TimeLabel("Last edited"), FormatTimeLayout(s.UpdatedAt, time.Format))
 	}
 	if s.DeletedAt != nil {
-		fmt.Fprintf(out, "Deleted (UTC): %s\n", s.DeletedAt.UTC().Format(time.Format))
+		fmt.Fprintf(out, "%s: %s\n", TimeLabel("Deleted"), FormatTimeLayout(s.DeletedAt, time.Format))
 	}
 	if s.ActiveVersion != nil {
 		fmt.Fprintf(out, "Active version: %d\n", *s.ActiveVersion)
@@ -81,12 +81,12 @@ func PrintVersion(out io.Writer, indent string, v *fastly.Version) {
 		fmt.Fprintf(out, "Testing: %v\n", *v.Testing)
 	}
 	if v.CreatedAt != nil {
-		fmt.Fprintf(out, "Created (UTC): %s\n", v.CreatedAt.UTC().Format(time.Format))
+		fmt.Fprintf(out, "%s: %s\n", TimeLabel("Created"), FormatTimeLayout(v.CreatedAt, time.Format))
 	}
 	if v.UpdatedAt != nil {
-		fmt.Fprintf(out, "Last edited (UTC): %s\n", v.UpdatedAt.UTC().Format(time.Format))
+		fmt.Fprintf(out, "%s: %s\n", TimeLabel("Last edited"), FormatTimeLayout(v.UpdatedAt, time.Format))
 	}
 	if v.DeletedAt != nil {
-		fmt.Fprintf(out, "Deleted (UTC): %s\n", v.DeletedAt.UTC().Format(time.Format))
+		fmt.Fprintf(out, "%s: %s\n", TimeLabel("Deleted"), FormatTimeLayout(v.DeletedAt, time.Format))
 	}
 }
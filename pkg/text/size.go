@@ -0,0 +1,14 @@
+package text
+
+import "fmt"
+
+// Bytes formats a number of bytes using decimal units (e.g. 1.5 MB).
+func Bytes(n float64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	i := 0
+	for n >= 1000 && i < len(units)-1 {
+		n /= 1000
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", n, units[i])
+}
@@ -60,14 +60,19 @@ func (sp *SpinnerWrapper) Process(msg string, fn SpinnerProcess) error {
 }
 
 // NewSpinner returns a new instance of a terminal prompt spinner.
+//
+// NOTE: The spinner output is discarded in quiet mode.
 func NewSpinner(out io.Writer) (Spinner, error) {
+	if IsQuiet() {
+		out = io.Discard
+	}
 	spinner, err := yacspin.New(yacspin.Config{
 		CharSet:           yacspin.CharSets[9],
 		Frequency:         100 * time.Millisecond,
 		StopCharacter:     "✓",
-		StopColors:        []string{"fgGreen"},
+		StopColors:        spinnerStyle(RoleSuccess),
 		StopFailCharacter: "✗",
-		StopFailColors:    []string{"fgRed"},
+		StopFailColors:    spinnerStyle(RoleError),
 		Suffix:            " ",
 		Writer:            out,
 	})
@@ -14,6 +14,9 @@ var (
 
 // Table wraps an instance of a tabwriter and provides helper methods to easily
 // create a table, add a header, add rows and print to the writer.
+//
+// NOTE: Cells are never truncated to fit the terminal (see Width), as the
+// values (e.g. IDs) are often copied from the output.
 type Table struct {
 	writer *tabwriter.Writer
 }
@@ -6,18 +6,37 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync/atomic"
 	"syscall"
 
 	"github.com/mitchellh/go-wordwrap"
 	"golang.org/x/term"
 
+	"github.com/fastly/cli/pkg/i18n"
 	"github.com/fastly/cli/pkg/sync"
 )
 
-// DefaultTextWidth is the width that should be passed to Wrap for most
-// general-purpose blocks of text intended for the user.
+// DefaultTextWidth is the widest general-purpose blocks of text intended for
+// the user are wrapped at. They're wrapped at the width of the terminal if
+// it's narrower (see SetWidth).
 const DefaultTextWidth = 120
 
+// quiet indicates decorative output should be suppressed (see SetQuiet).
+var quiet atomic.Bool
+
+// SetQuiet controls whether decorative output (line breaks, descriptions, info
+// and success messages, spinners) is written. It's enabled by the global
+// --quiet flag so that only a command's essential output (see Result) and any
+// warnings or errors are displayed.
+func SetQuiet(v bool) {
+	quiet.Store(v)
+}
+
+// IsQuiet reports whether decorative output is suppressed.
+func IsQuiet() bool {
+	return quiet.Load()
+}
+
 // Wrap a string at word boundaries with a maximum line length of width. Each
 // newline-delimited line in the text is trimmed of whitespace before being
 // added to the block for wrapping, which means strings can be declared in the
@@ -64,23 +83,25 @@ func WrapIndent(s string, limit uint, indent uint) string {
 	return strings.Join(result, "\n")
 }
 
-// Indent writes the help text to the writer using WrapIndent with
-// DefaultTextWidth, suffixed by a newlines. It's intended to be used to provide
-// detailed information, context, or help to the user.
+// Indent writes the help text to the writer using WrapIndent with the prose
+// width (see SetWidth), suffixed by a newlines. It's intended to be used to
+// provide detailed information, context, or help to the user.
 func Indent(w io.Writer, indent uint, format string, args ...any) {
 	text := fmt.Sprintf(format, args...)
-	fmt.Fprintf(w, "%s\n", WrapIndent(text, DefaultTextWidth, indent))
+	fmt.Fprintf(w, "%s\n", WrapIndent(text, proseWidth(), indent))
 }
 
-// Output writes the help text to the writer using Wrap with DefaultTextWidth,
-// suffixed by a newline. It's intended to be used to provide detailed
-// information, context, or help to the user.
+// Output writes the help text to the writer using Wrap with the prose width
+// (see SetWidth), suffixed by a newline. It's intended to be used to provide
+// detailed information, context, or help to the user.
 func Output(w io.Writer, format string, args ...any) {
 	prefix, suffix, txt := ParseBreaks(format)
 	if suffix == 0 {
 		suffix++
 	}
-	fmt.Fprintf(w, strings.Repeat("\n", prefix)+Wrap(txt, DefaultTextWidth)+strings.Repeat("\n", suffix), args...)
+	lw := LineDiscipline(w)
+	lw.EnsureNewline()
+	fmt.Fprintf(lw, strings.Repeat("\n", prefix)+Wrap(txt, proseWidth())+strings.Repeat("\n", suffix), args...)
 }
 
 // Input prints the prefix to the writer, and then reads a single line from the
@@ -89,18 +110,31 @@ func Output(w io.Writer, format string, args ...any) {
 // writer, and the input process happens again. Otherwise, the line is returned
 // to the caller.
 //
-// Input is intended to be used to take interactive input from the user.
-func Input(w io.Writer, prefix string, r io.Reader, validators ...func(string) error) (string, error) {
+// Input is intended to be used to take interactive input from the user. If the
+// prompt can't be answered from r (see SetInteractive), a NonInteractiveError
+// naming flag is returned instead. The flag is the one that supplies the value
+// without a prompt (e.g. --name), or empty if there isn't one. The prompt is
+// abandoned if the context set by SetContext is cancelled.
+func Input(w io.Writer, prefix string, r io.Reader, flag string, validators ...func(string) error) (string, error) {
+	if err := checkInteractive(r, flag); err != nil {
+		return "", err
+	}
 	s := bufio.NewScanner(r)
 
 outer:
 	for {
-		fmt.Fprint(w, Bold(prefix))
-		if ok := s.Scan(); !ok {
-			return "", s.Err()
+		fmt.Fprint(w, PrefixStyle(prefix))
+		input, ok, err := scanLine(s)
+		if !ok {
+			return "", err
 		}
 
-		line := strings.TrimSpace(s.Text())
+		// The terminal echoes the user's Enter key.
+		if lw, ok := w.(*LineWriter); ok {
+			lw.echoed()
+		}
+
+		line := strings.TrimSpace(input)
 		for _, validate := range validators {
 			if err := validate(line); err != nil {
 				fmt.Fprintln(w, err.Error())
@@ -126,6 +160,9 @@ func IsStdin(r io.Reader) bool {
 // Provide STDOUT as a way to determine whether formatting and/or
 // prompting is acceptable output.
 func IsTTY(fd any) bool {
+	if lw, ok := fd.(*LineWriter); ok {
+		fd = lw.W
+	}
 	if s, ok := fd.(*sync.Writer); ok {
 		// STDOUT is commonly wrapped in a sync.Writer, so here
 		// we unwrap it to gain access to the underlying Writer/STDOUT.
@@ -139,13 +176,16 @@ func IsTTY(fd any) bool {
 
 // InputSecure is like Input but doesn't echo input back to the terminal,
 // if and only if r is os.Stdin.
-func InputSecure(w io.Writer, prefix string, r io.Reader, validators ...func(string) error) (string, error) {
+func InputSecure(w io.Writer, prefix string, r io.Reader, flag string, validators ...func(string) error) (string, error) {
 	if !IsStdin(r) {
-		return Input(w, prefix, r, validators...)
+		return Input(w, prefix, r, flag, validators...)
+	}
+	if err := checkInteractive(r, flag); err != nil {
+		return "", err
 	}
 
 	read := func() (string, error) {
-		fmt.Fprint(w, Bold(prefix))
+		fmt.Fprint(w, PrefixStyle(prefix))
 		// IMPORTANT: Windows will fail if you remove the `int()` conversion.
 		//
 		// cannot use syscall.Stdin (variable of type syscall.Handle) as int value in argument to term.ReadPassword)
@@ -153,7 +193,7 @@ func InputSecure(w io.Writer, prefix string, r io.Reader, validators ...func(str
 		// This is because on *nix systems syscall.Stdin is already an int.
 		// But on Windows it's a Handle type:
 		// https://github.com/golang/go/blob/8d2eb290f83bca7d3b5154c6a7b3ac7546df5e8a/src/syscall/syscall_windows.go#L522
-		p, err := term.ReadPassword(int(syscall.Stdin))
+		p, err := readPassword(int(syscall.Stdin))
 		if err != nil {
 			return "", err
 		}
@@ -181,9 +221,9 @@ outer:
 
 // AskYesNo is similar to Input, but the line read is coerced to
 // one of true (yes and its variants) or false (no, its variants and
-// anything else) on success.
-func AskYesNo(w io.Writer, prompt string, r io.Reader) (bool, error) {
-	answer, err := Input(w, Prompt(prompt), r)
+// anything else) on success. The flag is typically --auto-yes.
+func AskYesNo(w io.Writer, prompt string, r io.Reader, flag string) (bool, error) {
+	answer, err := Input(w, Prompt(prompt), r, flag)
 	if err != nil {
 		return false, fmt.Errorf("error reading input %w", err)
 	}
@@ -197,13 +237,16 @@ func AskYesNo(w io.Writer, prompt string, r io.Reader) (bool, error) {
 // Break simply writes a newline to the writer. It's intended to be used between
 // blocks of text that would otherwise be adjacent, a sort of semantic markup.
 func Break(w io.Writer) {
+	if IsQuiet() {
+		return
+	}
 	fmt.Fprintln(w)
 }
 
 // BreakN writes n newlines to the writer. It's intended to be used between
 // blocks of text that would otherwise be adjacent, a sort of semantic markup.
 func BreakN(w io.Writer, n int) {
-	if n == 0 {
+	if n == 0 || IsQuiet() {
 		return
 	}
 	for i := 1; i <= n; i++ {
@@ -211,64 +254,107 @@ func BreakN(w io.Writer, n int) {
 	}
 }
 
-// Deprecated is a wrapper for fmt.Fprintf with a bold red "DEPRECATED: " prefix.
+// Labels prefixed to messages by the helpers below.
+var (
+	labelDeprecated = i18n.Register("text.label.deprecated", "DEPRECATED")
+	labelError      = i18n.Register("text.label.error", "ERROR")
+	labelImportant  = i18n.Register("text.label.important", "IMPORTANT")
+	labelInfo       = i18n.Register("text.label.info", "INFO")
+	labelSuccess    = i18n.Register("text.label.success", "SUCCESS")
+	labelWarning    = i18n.Register("text.label.warning", "WARNING")
+)
+
+// Deprecated is a wrapper for fmt.Fprintf with a "DEPRECATED: " prefix styled
+// as an error (see Theme).
 func Deprecated(w io.Writer, format string, args ...any) {
 	prefix, suffix, txt := ParseBreaks(format)
 	if suffix == 0 {
 		suffix++
 	}
-	fmt.Fprintf(w, WrapString(BoldRed, "DEPRECATED", txt, prefix, suffix), args...)
+	writeLabelled(w, WrapString(ErrorStyle, i18n.Localize(labelDeprecated), txt, prefix, suffix), args...)
 }
 
-// Error is a wrapper for fmt.Fprintf with a bold red "ERROR: " prefix.
+// Error is a wrapper for fmt.Fprintf with an "ERROR: " prefix styled as an
+// error (see Theme).
 func Error(w io.Writer, format string, args ...any) {
 	prefix, suffix, txt := ParseBreaks(format)
 	if suffix == 0 {
 		suffix++
 	}
-	fmt.Fprintf(w, WrapString(BoldRed, "ERROR", txt, prefix, suffix), args...)
+	writeLabelled(w, WrapString(ErrorStyle, i18n.Localize(labelError), txt, prefix, suffix), args...)
 }
 
-// Important is a wrapper for fmt.Fprintf with a bold yellow "IMPORTANT: " prefix.
+// Important is a wrapper for fmt.Fprintf with an "IMPORTANT: " prefix styled
+// as a warning (see Theme).
 func Important(w io.Writer, format string, args ...any) {
 	prefix, suffix, txt := ParseBreaks(format)
 	if suffix == 0 {
 		suffix++
 	}
-	fmt.Fprintf(w, WrapString(BoldYellow, "IMPORTANT", txt, prefix, suffix), args...)
+	writeLabelled(w, WrapString(WarningStyle, i18n.Localize(labelImportant), txt, prefix, suffix), args...)
 }
 
-// Info is a wrapper for fmt.Fprintf with a bold "INFO: " prefix.
+// Info is a wrapper for fmt.Fprintf with an "INFO: " prefix styled as
+// information (see Theme).
 func Info(w io.Writer, format string, args ...any) {
+	if IsQuiet() {
+		return
+	}
 	prefix, suffix, txt := ParseBreaks(format)
 	if suffix == 0 {
 		suffix++
 	}
-	fmt.Fprintf(w, WrapString(BoldCyan, "INFO", txt, prefix, suffix), args...)
+	writeLabelled(w, WrapString(InfoStyle, i18n.Localize(labelInfo), txt, prefix, suffix), args...)
 }
 
-// Success is a wrapper for fmt.Fprintf with a bold green "SUCCESS: " prefix.
+// Success is a wrapper for fmt.Fprintf with a "SUCCESS: " prefix styled as a
+// success (see Theme).
 func Success(w io.Writer, format string, args ...any) {
+	if IsQuiet() {
+		return
+	}
 	prefix, suffix, txt := ParseBreaks(format)
 	if suffix == 0 {
 		suffix++
 	}
-	fmt.Fprintf(w, WrapString(BoldGreen, "SUCCESS", txt, prefix, suffix), args...)
+	writeLabelled(w, WrapString(SuccessStyle, i18n.Localize(labelSuccess), txt, prefix, suffix), args...)
 }
 
-// Warning is a wrapper for fmt.Fprintf with a bold yellow "WARNING: " prefix.
+// Result writes the essential output of a command. By default it's a wrapper
+// for Success, but in quiet mode only the result value is written so that it
+// can be consumed by scripts. For example:
+//
+//	Result(out, serviceID, "Created service %s", serviceID)
+func Result(w io.Writer, result, format string, args ...any) {
+	if IsQuiet() {
+		fmt.Fprintln(w, result)
+		return
+	}
+	Success(w, format, args...)
+}
+
+// Warning is a wrapper for fmt.Fprintf with a "WARNING: " prefix styled as a
+// warning (see Theme).
 func Warning(w io.Writer, format string, args ...any) {
 	prefix, suffix, txt := ParseBreaks(format)
 	if suffix == 0 {
 		suffix++
 	}
-	fmt.Fprintf(w, WrapString(BoldYellow, "WARNING", txt, prefix, suffix), args...)
+	writeLabelled(w, WrapString(WarningStyle, i18n.Localize(labelWarning), txt, prefix, suffix), args...)
+}
+
+// writeLabelled writes the output of a labelled helper (e.g. Error) using
+// LineDiscipline, ensuring the label starts on a new line.
+func writeLabelled(w io.Writer, format string, args ...any) {
+	lw := LineDiscipline(w)
+	lw.EnsureNewline()
+	fmt.Fprintf(lw, format, args...)
 }
 
 // WrapString produces string with correct wrapping and prefix/suffix linebreaks.
 func WrapString(fn ColorFn, msg, txt string, prefix, suffix int) string {
 	msg = fmt.Sprintf("%s: ", msg)
-	return strings.Repeat("\n", prefix) + Wrap(fn(msg)+txt, DefaultTextWidth) + strings.Repeat("\n", suffix)
+	return strings.Repeat("\n", prefix) + Wrap(fn(msg)+txt, proseWidth()) + strings.Repeat("\n", suffix)
 }
 
 // Description formats the output of a description item. A description item
@@ -278,6 +364,9 @@ func WrapString(fn ColorFn, msg, txt string, prefix, suffix int) string {
 //	To compile the package, run:
 //	    fastly compute build
 func Description(w io.Writer, intro, description string) {
+	if IsQuiet() {
+		return
+	}
 	fmt.Fprintf(w, "%s:\n\t%s\n\n", intro, Bold(description))
 }
 
@@ -2,11 +2,15 @@ package text_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
@@ -62,13 +66,13 @@ func TestInput(t *testing.T) {
 	} {
 		t.Run(testcase.name, func(t *testing.T) {
 			var buf bytes.Buffer
-			result, err := text.Input(&buf, testcase.prefix, strings.NewReader(testcase.in), testcase.validators...)
+			result, err := text.Input(&buf, testcase.prefix, strings.NewReader(testcase.in), "", testcase.validators...)
 			testutil.AssertNoError(t, err)
 			testutil.AssertString(t, testcase.wantOutput, buf.String())
 			testutil.AssertString(t, testcase.wantResult, result)
 
 			buf.Reset()
-			result, err = text.InputSecure(&buf, testcase.prefix, strings.NewReader(testcase.in), testcase.validators...)
+			result, err = text.InputSecure(&buf, testcase.prefix, strings.NewReader(testcase.in), "", testcase.validators...)
 			testutil.AssertNoError(t, err)
 			testutil.AssertString(t, testcase.wantOutput, buf.String())
 			testutil.AssertString(t, testcase.wantResult, result)
@@ -115,7 +119,7 @@ func TestAskYesNo(t *testing.T) {
 	} {
 		t.Run(testcase.name, func(t *testing.T) {
 			var buf bytes.Buffer
-			result, err := text.AskYesNo(&buf, "", strings.NewReader(testcase.in))
+			result, err := text.AskYesNo(&buf, "", strings.NewReader(testcase.in), "--auto-yes")
 			testutil.AssertNoError(t, err)
 			testutil.AssertBool(t, testcase.wantResult, result)
 		})
@@ -190,6 +194,32 @@ func TestPrefixes(t *testing.T) {
 	}
 }
 
+func TestQuiet(t *testing.T) {
+	text.SetQuiet(true)
+	defer text.SetQuiet(false)
+
+	var buf bytes.Buffer
+	text.Break(&buf)
+	text.BreakN(&buf, 2)
+	text.Description(&buf, "To compile the package, run", "fastly compute build")
+	text.Info(&buf, "Test string %d.", 123)
+	text.Success(&buf, "Test string %d.", 123)
+	text.Result(&buf, "12345", "Created service %s", "12345")
+	text.Warning(&buf, "Test string %d.", 123)
+
+	want := "12345\nWARNING: Test string 123.\n"
+	if have := buf.String(); want != have {
+		t.Error(cmp.Diff(want, have))
+	}
+
+	buf.Reset()
+	text.SetQuiet(false)
+	text.Result(&buf, "12345", "Created service %s", "12345")
+	if want, have := "SUCCESS: Created service 12345\n", buf.String(); want != have {
+		t.Error(cmp.Diff(want, have))
+	}
+}
+
 func TestWrap(t *testing.T) {
 	for i, testcase := range []struct {
 		text, want string
@@ -311,3 +341,136 @@ func TestParseBreaks(t *testing.T) {
 		})
 	}
 }
+
+// TestInputNonInteractive validates a prompt reading from a pipe fails fast
+// rather than waiting for input, unless prompts are forced with --interactive.
+func TestInputNonInteractive(t *testing.T) {
+	defer text.SetInteractive(text.InteractiveAuto)
+
+	answers := filepath.Join(t.TempDir(), "answers")
+	if err := os.WriteFile(answers, []byte("foo\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, testcase := range []struct {
+		name       string
+		mode       text.InteractiveMode
+		input      func(t *testing.T) io.Reader
+		wantResult string
+		wantReason string
+	}{
+		{
+			name:       "pipe",
+			mode:       text.InteractiveAuto,
+			input:      pipe,
+			wantReason: "standard input isn't a terminal",
+		},
+		{
+			name:       "pipe in non-interactive mode",
+			mode:       text.InteractiveNever,
+			input:      pipe,
+			wantReason: "non-interactive mode is enabled",
+		},
+		{
+			name:       "pipe in interactive mode",
+			mode:       text.InteractiveAlways,
+			input:      pipe,
+			wantResult: "foo",
+		},
+		{
+			name: "regular file in non-interactive mode",
+			mode: text.InteractiveNever,
+			input: func(t *testing.T) io.Reader {
+				f, err := os.Open(answers)
+				if err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() { _ = f.Close() })
+				return f
+			},
+			wantResult: "foo",
+		},
+		{
+			name: "scripted input in non-interactive mode",
+			mode: text.InteractiveNever,
+			input: func(_ *testing.T) io.Reader {
+				return strings.NewReader("foo\n")
+			},
+			wantResult: "foo",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			text.SetInteractive(testcase.mode)
+			r := testcase.input(t)
+
+			var buf bytes.Buffer
+			result, err := text.Input(&buf, "Name: ", r, "--name")
+			if testcase.wantReason == "" {
+				testutil.AssertNoError(t, err)
+				testutil.AssertString(t, testcase.wantResult, result)
+				return
+			}
+
+			var nie text.NonInteractiveError
+			if !errors.As(err, &nie) {
+				t.Fatalf("want a text.NonInteractiveError, have %#v", err)
+			}
+			if !errors.Is(err, text.ErrNonInteractive) {
+				t.Errorf("want the error to wrap text.ErrNonInteractive")
+			}
+			testutil.AssertString(t, "--name", nie.Flag)
+			testutil.AssertString(t, testcase.wantReason, nie.Reason)
+			testutil.AssertStringContains(t, nie.Remediation(), "Pass --name")
+			testutil.AssertString(t, "", buf.String()) // the prompt isn't displayed
+
+			_, err = text.AskYesNo(&buf, "Continue? [y/N] ", r, "--auto-yes")
+			testutil.AssertErrorContains(t, err, text.ErrNonInteractive.Error())
+		})
+	}
+}
+
+func TestInputCancelled(t *testing.T) {
+	defer text.SetContext(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	text.SetContext(ctx)
+
+	// The write end is never written to, so the prompt waits for input until
+	// the context is cancelled.
+	r, w := io.Pipe()
+	defer w.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		var buf bytes.Buffer
+		_, err := text.AskYesNo(&buf, "Continue? [y/N] ", r, "--auto-yes")
+		done <- err
+	}()
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("want context.Canceled, have %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("prompt didn't return when the context was cancelled")
+	}
+}
+
+// pipe returns the read end of a pipe with an answer written to it. The write
+// end is left open, so reading past the answer would block.
+func pipe(t *testing.T) io.Reader {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = r.Close()
+		_ = w.Close()
+	})
+	if _, err := w.WriteString("foo\n"); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
@@ -0,0 +1,179 @@
+package text
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/fatih/color"
+)
+
+// Role is the semantic purpose of styled output. A Theme maps each role to
+// the style it's displayed with, so output is never hard-coded to a color.
+type Role string
+
+// The roles a Theme styles.
+const (
+	// RoleError styles errors and failures (e.g. the "ERROR" label).
+	RoleError Role = "error"
+	// RoleSuccess styles successes (e.g. the "SUCCESS" label).
+	RoleSuccess Role = "success"
+	// RoleWarning styles warnings and cautions (e.g. the "WARNING" label).
+	RoleWarning Role = "warning"
+	// RoleInfo styles information (e.g. the "INFO" label).
+	RoleInfo Role = "info"
+	// RoleDiffAdd styles lines added in a diff.
+	RoleDiffAdd Role = "diff-add"
+	// RoleDiffDel styles lines deleted in a diff.
+	RoleDiffDel Role = "diff-del"
+	// RolePrefix styles the prefix of an interactive prompt.
+	RolePrefix Role = "prefix"
+)
+
+// Roles is every Role, in the order they're documented.
+var Roles = []Role{RoleError, RoleSuccess, RoleWarning, RoleInfo, RoleDiffAdd, RoleDiffDel, RolePrefix}
+
+// Theme is a set of styles for the output roles.
+type Theme struct {
+	// Name is how the theme is selected (e.g. "colorblind-safe").
+	Name string
+	// Styles are the SGR attributes of each role. A role without attributes
+	// is displayed unstyled.
+	Styles map[Role][]color.Attribute
+}
+
+// DefaultThemeName is the name of the theme used unless another is selected.
+const DefaultThemeName = "default"
+
+// Themes are the built-in themes.
+var Themes = []Theme{
+	{
+		Name: DefaultThemeName,
+		Styles: map[Role][]color.Attribute{
+			RoleError:   {color.Bold, color.FgRed},
+			RoleSuccess: {color.Bold, color.FgGreen},
+			RoleWarning: {color.Bold, color.FgYellow},
+			RoleInfo:    {color.Bold, color.FgCyan},
+			RoleDiffAdd: {color.Bold, color.FgGreen},
+			RoleDiffDel: {color.Bold, color.FgRed},
+			RolePrefix:  {color.Bold},
+		},
+	},
+	{
+		// Blue and yellow remain distinct with the common forms of color
+		// blindness (unlike red and green), and errors are also underlined.
+		Name: "colorblind-safe",
+		Styles: map[Role][]color.Attribute{
+			RoleError:   {color.Bold, color.Underline, color.FgMagenta},
+			RoleSuccess: {color.Bold, color.FgBlue},
+			RoleWarning: {color.Bold, color.FgYellow},
+			RoleInfo:    {color.Bold, color.FgCyan},
+			RoleDiffAdd: {color.Bold, color.FgBlue},
+			RoleDiffDel: {color.Bold, color.FgYellow},
+			RolePrefix:  {color.Bold},
+		},
+	},
+	{
+		Name: "monochrome-bold",
+		Styles: map[Role][]color.Attribute{
+			RoleError:   {color.Bold, color.Underline},
+			RoleSuccess: {color.Bold},
+			RoleWarning: {color.Bold},
+			RoleInfo:    {color.Bold},
+			RoleDiffAdd: {color.Bold},
+			RoleDiffDel: {color.Faint},
+			RolePrefix:  {color.Bold},
+		},
+	},
+}
+
+// ThemeNames returns the names of the built-in themes.
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for _, t := range Themes {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// theme is the current theme (see SetTheme). It's nil until a theme is set,
+// in which case the default theme is current.
+var theme atomic.Pointer[Theme]
+
+// currentTheme returns the current theme.
+func currentTheme() *Theme {
+	if t := theme.Load(); t != nil {
+		return t
+	}
+	return &Themes[0]
+}
+
+// SetTheme selects the built-in theme with the given name. An empty name
+// selects the default theme, as does an unknown name, in which case an error
+// is returned so the caller can warn about it.
+func SetTheme(name string) error {
+	if name == "" {
+		name = DefaultThemeName
+	}
+	for i := range Themes {
+		if strings.EqualFold(Themes[i].Name, name) {
+			theme.Store(&Themes[i])
+			return nil
+		}
+	}
+	theme.Store(&Themes[0])
+	return fmt.Errorf("unknown theme '%s'", name)
+}
+
+// CurrentTheme returns the theme selected by SetTheme.
+func CurrentTheme() Theme {
+	return *currentTheme()
+}
+
+// Style returns a Sprint-class function that styles the arguments for the
+// role, using the theme that's current when it's called.
+func Style(r Role) ColorFn {
+	return func(a ...any) string {
+		attrs := currentTheme().Styles[r]
+		if len(attrs) == 0 {
+			return fmt.Sprint(a...)
+		}
+		return color.New(attrs...).Sprint(a...)
+	}
+}
+
+// Sprint-class functions for each role (see Style).
+var (
+	ErrorStyle   = Style(RoleError)
+	SuccessStyle = Style(RoleSuccess)
+	WarningStyle = Style(RoleWarning)
+	InfoStyle    = Style(RoleInfo)
+	DiffAddStyle = Style(RoleDiffAdd)
+	DiffDelStyle = Style(RoleDiffDel)
+	PrefixStyle  = Style(RolePrefix)
+)
+
+// spinnerColors are the yacspin names of the attributes used by the themes.
+var spinnerColors = map[color.Attribute]string{
+	color.Bold:      "bold",
+	color.Faint:     "faint",
+	color.Underline: "underline",
+	color.FgRed:     "fgRed",
+	color.FgGreen:   "fgGreen",
+	color.FgYellow:  "fgYellow",
+	color.FgBlue:    "fgBlue",
+	color.FgMagenta: "fgMagenta",
+	color.FgCyan:    "fgCyan",
+}
+
+// spinnerStyle returns the role's style in the current theme as yacspin
+// colors (see NewSpinner).
+func spinnerStyle(r Role) []string {
+	var colors []string
+	for _, a := range currentTheme().Styles[r] {
+		if name, ok := spinnerColors[a]; ok {
+			colors = append(colors, name)
+		}
+	}
+	return colors
+}
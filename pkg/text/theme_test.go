@@ -0,0 +1,109 @@
+package text_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fatih/color"
+
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// TestThemes renders the same output under each theme, validating every role
+// starts with the theme's SGR sequence.
+func TestThemes(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() {
+		color.NoColor = noColor
+		_ = text.SetTheme(text.DefaultThemeName)
+	}()
+
+	render := func() map[text.Role]string {
+		var errOut, successOut, warningOut, infoOut, diffOut bytes.Buffer
+		text.Error(&errOut, "boom")
+		text.Success(&successOut, "done")
+		text.Warning(&warningOut, "careful")
+		text.Info(&infoOut, "fyi")
+		text.Diff(&diffOut, "a", "b", "old\n", "new\n")
+		return map[text.Role]string{
+			text.RoleError:   errOut.String(),
+			text.RoleSuccess: successOut.String(),
+			text.RoleWarning: warningOut.String(),
+			text.RoleInfo:    infoOut.String(),
+			text.RoleDiffAdd: diffOut.String(),
+			text.RoleDiffDel: diffOut.String(),
+			text.RolePrefix:  text.Prompt("name: "),
+		}
+	}
+
+	for _, testcase := range []struct {
+		theme string
+		want  map[text.Role]string
+	}{
+		{
+			theme: "default",
+			want: map[text.Role]string{
+				text.RoleError:   "\x1b[1;31mERROR: ",
+				text.RoleSuccess: "\x1b[1;32mSUCCESS: ",
+				text.RoleWarning: "\x1b[1;33mWARNING: ",
+				text.RoleInfo:    "\x1b[1;36mINFO: ",
+				text.RoleDiffAdd: "\x1b[1;32m+new",
+				text.RoleDiffDel: "\x1b[1;31m-old",
+				text.RolePrefix:  "\x1b[1mname: ",
+			},
+		},
+		{
+			theme: "colorblind-safe",
+			want: map[text.Role]string{
+				text.RoleError:   "\x1b[1;4;35mERROR: ",
+				text.RoleSuccess: "\x1b[1;34mSUCCESS: ",
+				text.RoleWarning: "\x1b[1;33mWARNING: ",
+				text.RoleInfo:    "\x1b[1;36mINFO: ",
+				text.RoleDiffAdd: "\x1b[1;34m+new",
+				text.RoleDiffDel: "\x1b[1;33m-old",
+				text.RolePrefix:  "\x1b[1mname: ",
+			},
+		},
+		{
+			theme: "monochrome-bold",
+			want: map[text.Role]string{
+				text.RoleError:   "\x1b[1;4mERROR: ",
+				text.RoleSuccess: "\x1b[1mSUCCESS: ",
+				text.RoleWarning: "\x1b[1mWARNING: ",
+				text.RoleInfo:    "\x1b[1mINFO: ",
+				text.RoleDiffAdd: "\x1b[1m+new",
+				text.RoleDiffDel: "\x1b[2m-old",
+				text.RolePrefix:  "\x1b[1mname: ",
+			},
+		},
+	} {
+		t.Run(testcase.theme, func(t *testing.T) {
+			testutil.AssertNoError(t, text.SetTheme(testcase.theme))
+			testutil.AssertString(t, testcase.theme, text.CurrentTheme().Name)
+
+			have := render()
+			for _, r := range text.Roles {
+				want, ok := testcase.want[r]
+				if !ok {
+					t.Fatalf("want a sequence for role %s", r)
+				}
+				testutil.AssertStringContains(t, have[r], want)
+			}
+		})
+	}
+}
+
+func TestSetThemeUnknown(t *testing.T) {
+	defer func() {
+		_ = text.SetTheme(text.DefaultThemeName)
+	}()
+
+	testutil.AssertNoError(t, text.SetTheme("monochrome-bold"))
+	testutil.AssertErrorContains(t, text.SetTheme("neon"), "unknown theme 'neon'")
+	testutil.AssertString(t, text.DefaultThemeName, text.CurrentTheme().Name)
+
+	testutil.AssertNoError(t, text.SetTheme(""))
+	testutil.AssertString(t, text.DefaultThemeName, text.CurrentTheme().Name)
+}
@@ -0,0 +1,191 @@
+package text
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	// NOTE: The timezone database is embedded as it isn't available on every
+	// system (e.g. Windows), and a named zone can be selected (see SetTimeZone).
+	_ "time/tzdata"
+)
+
+// The time formats accepted by SetTimeFormat. Any other format is a custom
+// layout (see time.Layout).
+const (
+	// TimeFormatDefault displays each timestamp as the command always has,
+	// e.g. "2021-06-15 23:00:00 +0000 UTC" (see FormatTimeLayout).
+	TimeFormatDefault = "default"
+	// TimeFormatRFC3339 displays timestamps in RFC3339 format
+	// (e.g. "2021-06-15T23:00:00Z").
+	TimeFormatRFC3339 = "rfc3339"
+	// TimeFormatRelative displays how long ago (or until) a timestamp is
+	// (e.g. "3 hours ago").
+	TimeFormatRelative = "relative"
+)
+
+// TimeFormats are the names of the formats accepted by SetTimeFormat.
+var TimeFormats = []string{TimeFormatDefault, TimeFormatRFC3339, TimeFormatRelative}
+
+// The timezones accepted by SetTimeZone. Any other timezone is an IANA name
+// (e.g. "Europe/London").
+const (
+	// TimeZoneUTC displays timestamps in UTC.
+	TimeZoneUTC = "utc"
+	// TimeZoneLocal displays timestamps in the system's timezone.
+	TimeZoneLocal = "local"
+)
+
+// DefaultTimeLayout is the layout of a timestamp displayed by FormatTime with
+// the default format, which is the layout of time.Time's String method.
+const DefaultTimeLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+// timeLayout is the layout timestamps are displayed with (see SetTimeFormat).
+// It's empty for TimeFormatRelative, and nil for TimeFormatDefault.
+var timeLayout atomic.Pointer[string]
+
+// timeZone is the timezone timestamps are displayed in (see SetTimeZone). It's
+// nil until a timezone is set, in which case UTC is used.
+var timeZone atomic.Pointer[time.Location]
+
+// SetTimeFormat selects how FormatTime displays timestamps: one of TimeFormats
+// or a custom layout (e.g. "02 Jan 2006 15:04"). An empty format selects the
+// default format, as does an invalid layout, in which case an error is
+// returned so the caller can warn about it.
+//
+// NOTE: A layout is invalid if it doesn't contain any of the elements of the
+// reference time (e.g. "YYYY-MM-DD"), as every timestamp would be displayed
+// as the layout itself.
+func SetTimeFormat(format string) error {
+	var layout string
+	switch strings.ToLower(format) {
+	case "", TimeFormatDefault:
+		timeLayout.Store(nil)
+		return nil
+	case TimeFormatRFC3339:
+		layout = time.RFC3339
+	case TimeFormatRelative:
+		// NOTE: The empty layout selects relative timestamps (see FormatTimeLayout).
+	default:
+		if time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC).Format(format) == format {
+			timeLayout.Store(nil)
+			return fmt.Errorf("invalid time format '%s'", format)
+		}
+		layout = format
+	}
+	timeLayout.Store(&layout)
+	return nil
+}
+
+// SetTimeZone selects the timezone FormatTime displays timestamps in: "utc",
+// "local" or an IANA name (e.g. "Europe/London"). An empty name selects UTC,
+// as does an unknown name, in which case an error is returned so the caller
+// can warn about it.
+func SetTimeZone(name string) error {
+	switch strings.ToLower(name) {
+	case "", TimeZoneUTC:
+		timeZone.Store(time.UTC)
+	case TimeZoneLocal:
+		timeZone.Store(time.Local)
+	default:
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			timeZone.Store(time.UTC)
+			return fmt.Errorf("unknown time zone '%s'", name)
+		}
+		timeZone.Store(loc)
+	}
+	return nil
+}
+
+// FormatTime returns how a timestamp is displayed, as FormatTimeLayout does
+// with DefaultTimeLayout.
+func FormatTime(t *time.Time) string {
+	return FormatTimeLayout(t, DefaultTimeLayout)
+}
+
+// FormatTimeLayout returns how a timestamp is displayed, using the format and
+// timezone selected by SetTimeFormat and SetTimeZone. With the default format
+// the timestamp is formatted with the given layout, so the output of a command
+// is unchanged unless a format is set. A nil timestamp is displayed as an empty
+// string.
+//
+// NOTE: It's for display only. JSON output (see argparser.JSONOutput) encodes
+// timestamps as RFC3339 in UTC, whatever the display settings.
+func FormatTimeLayout(t *time.Time, layout string) string {
+	if t == nil {
+		return ""
+	}
+	if l := timeLayout.Load(); l != nil {
+		layout = *l
+	}
+	if layout == "" {
+		return relativeTime(*t, time.Now())
+	}
+	return t.In(displayZone()).Format(layout)
+}
+
+// TimeLabel returns the label of a timestamp displayed with a layout that
+// doesn't include the timezone (e.g. "Created (UTC)"). The timezone is only
+// appended with the default format, as the other formats either include it or
+// are relative.
+func TimeLabel(label string) string {
+	if timeLayout.Load() != nil {
+		return label
+	}
+	loc := displayZone()
+	name := loc.String()
+	if loc == time.Local {
+		name = "local"
+	}
+	return fmt.Sprintf("%s (%s)", label, name)
+}
+
+// displayZone returns the timezone selected by SetTimeZone.
+func displayZone() *time.Location {
+	if z := timeZone.Load(); z != nil {
+		return z
+	}
+	return time.UTC
+}
+
+// relativeTime returns how long before (or after) now t is, in the largest
+// whole unit (e.g. "3 hours ago", "in 2 days").
+func relativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	past := d >= 0
+	if !past {
+		d = -d
+	}
+	if d < time.Minute {
+		return "just now"
+	}
+
+	const day = 24 * time.Hour
+	units := []struct {
+		name string
+		size time.Duration
+	}{
+		{"year", 365 * day},
+		{"month", 30 * day},
+		{"day", day},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+	}
+	for _, u := range units {
+		n := int(d / u.size)
+		if n < 1 {
+			continue
+		}
+		s := fmt.Sprintf("%d %ss", n, u.name)
+		if n == 1 {
+			s = "1 " + u.name
+		}
+		if past {
+			return s + " ago"
+		}
+		return "in " + s
+	}
+	return "just now"
+}
@@ -0,0 +1,129 @@
+package text_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// TestFormatTime validates a timestamp is displayed in each format and
+// timezone, and an invalid format or timezone falls back to the default, which
+// is the layout given by the caller (with the label of the timezone).
+func TestFormatTime(t *testing.T) {
+	defer func() {
+		_ = text.SetTimeFormat("")
+		_ = text.SetTimeZone("")
+	}()
+
+	ts := time.Date(2021, 6, 15, 23, 0, 0, 0, time.UTC)
+	scenarios := []struct {
+		name      string
+		format    string
+		zone      string
+		want      string
+		wantAt    string
+		wantLabel string
+		wantError string
+	}{
+		{
+			name:      "default",
+			want:      "2021-06-15 23:00:00 +0000 UTC",
+			wantAt:    "2021-06-15 23:00",
+			wantLabel: "Created (UTC)",
+		},
+		{
+			name:      "rfc3339",
+			format:    "RFC3339",
+			want:      "2021-06-15T23:00:00Z",
+			wantAt:    "2021-06-15T23:00:00Z",
+			wantLabel: "Created",
+		},
+		{
+			name:      "custom layout",
+			format:    "02 Jan 2006 15:04",
+			want:      "15 Jun 2021 23:00",
+			wantAt:    "15 Jun 2021 23:00",
+			wantLabel: "Created",
+		},
+		{
+			name:      "invalid layout",
+			format:    "YYYY-MM-DD",
+			want:      "2021-06-15 23:00:00 +0000 UTC",
+			wantAt:    "2021-06-15 23:00",
+			wantLabel: "Created (UTC)",
+			wantError: "invalid time format 'YYYY-MM-DD'",
+		},
+		{
+			name:      "named timezone",
+			zone:      "Asia/Tokyo",
+			want:      "2021-06-16 08:00:00 +0900 JST",
+			wantAt:    "2021-06-16 08:00",
+			wantLabel: "Created (Asia/Tokyo)",
+		},
+		{
+			name:      "named timezone in rfc3339",
+			format:    "rfc3339",
+			zone:      "Europe/London",
+			want:      "2021-06-16T00:00:00+01:00",
+			wantAt:    "2021-06-16T00:00:00+01:00",
+			wantLabel: "Created",
+		},
+		{
+			name:      "local timezone",
+			zone:      "local",
+			want:      ts.Local().Format(text.DefaultTimeLayout),
+			wantAt:    ts.Local().Format("2006-01-02 15:04"),
+			wantLabel: "Created (local)",
+		},
+		{
+			name:      "unknown timezone",
+			zone:      "Mars/Olympus",
+			want:      "2021-06-15 23:00:00 +0000 UTC",
+			wantAt:    "2021-06-15 23:00",
+			wantLabel: "Created (UTC)",
+			wantError: "unknown time zone 'Mars/Olympus'",
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			err := text.SetTimeFormat(testcase.format)
+			if zerr := text.SetTimeZone(testcase.zone); zerr != nil {
+				err = zerr
+			}
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+			testutil.AssertString(t, testcase.want, text.FormatTime(&ts))
+			testutil.AssertString(t, testcase.wantAt, text.FormatTimeLayout(&ts, "2006-01-02 15:04"))
+			testutil.AssertString(t, testcase.wantLabel, text.TimeLabel("Created"))
+		})
+	}
+
+	testutil.AssertString(t, "", text.FormatTime(nil))
+}
+
+// TestFormatTimeRelative validates timestamps in the past and future are
+// displayed relative to now, in the largest whole unit.
+func TestFormatTimeRelative(t *testing.T) {
+	defer func() {
+		_ = text.SetTimeFormat("")
+	}()
+	testutil.AssertNoError(t, text.SetTimeFormat("relative"))
+
+	now := time.Now()
+	for _, testcase := range []struct {
+		offset time.Duration
+		want   string
+	}{
+		{offset: -10 * time.Second, want: "just now"},
+		{offset: -90 * time.Second, want: "1 minute ago"},
+		{offset: -3*time.Hour - time.Minute, want: "3 hours ago"},
+		{offset: -49 * time.Hour, want: "2 days ago"},
+		{offset: -400 * 24 * time.Hour, want: "1 year ago"},
+		{offset: 2*time.Hour + time.Minute, want: "in 2 hours"},
+		{offset: 61 * 24 * time.Hour, want: "in 2 months"},
+	} {
+		ts := now.Add(testcase.offset)
+		testutil.AssertString(t, testcase.want, text.FormatTime(&ts))
+	}
+}
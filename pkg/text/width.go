@@ -0,0 +1,141 @@
+package text
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/term"
+)
+
+// FallbackTextWidth is the width prose is wrapped at when the width of the
+// terminal isn't known (e.g. the output is piped, or detection failed).
+const FallbackTextWidth = 80
+
+// The sources of a Width, in order of precedence.
+const (
+	// WidthSourceConfig is the terminal_width setting of the config file.
+	WidthSourceConfig = "config file"
+	// WidthSourceColumns is the COLUMNS environment variable.
+	WidthSourceColumns = "COLUMNS"
+	// WidthSourceTerminal is the size reported by the terminal.
+	WidthSourceTerminal = "terminal"
+	// WidthSourceUnknown means none of the other sources gave a width.
+	WidthSourceUnknown = "unknown"
+)
+
+// TerminalWidth returns the number of columns of the terminal the output is
+// displayed in. It's a variable so tests can simulate a terminal, or the ways
+// detection fails (an error, or a terminal that reports zero columns).
+var TerminalWidth = func() (int, error) {
+	w, _, err := term.GetSize(int(os.Stdout.Fd()))
+	return w, err
+}
+
+// Width is the output width decided for an invocation (see DetectWidth).
+type Width struct {
+	// Columns is the number of columns (zero if unknown).
+	Columns int
+	// Source is where Columns came from (e.g. WidthSourceTerminal).
+	Source string
+	// Err is why the width of the terminal couldn't be detected (if it was
+	// needed and couldn't).
+	Err error
+}
+
+// DetectWidth decides the output width. In order of precedence:
+//
+//   - override, i.e. the terminal_width setting of the config file (if
+//     positive).
+//   - columns, i.e. the COLUMNS environment variable (if a positive integer).
+//   - The size reported by TerminalWidth.
+//
+// A width that can't be detected (an error or zero columns) is unknown, so
+// prose is wrapped at FallbackTextWidth. Tables are never truncated (see
+// Table), so they're unbounded whatever the width.
+func DetectWidth(override int, columns string) Width {
+	if override > 0 {
+		return Width{Columns: override, Source: WidthSourceConfig}
+	}
+	var errs []error
+	if columns = strings.TrimSpace(columns); columns != "" {
+		n, err := strconv.Atoi(columns)
+		if err == nil && n > 0 {
+			return Width{Columns: n, Source: WidthSourceColumns}
+		}
+		errs = append(errs, fmt.Errorf("invalid COLUMNS '%s'", columns))
+	}
+	n, err := TerminalWidth()
+	switch {
+	case err != nil:
+		errs = append(errs, err)
+	case n <= 0:
+		errs = append(errs, fmt.Errorf("the terminal reported %d columns", n))
+	default:
+		return Width{Columns: n, Source: WidthSourceTerminal}
+	}
+	return Width{Source: WidthSourceUnknown, Err: errors.Join(errs...)}
+}
+
+// Known reports whether the width was detected.
+func (w Width) Known() bool {
+	return w.Columns > 0
+}
+
+// Usage returns the width the usage (help) output is formatted to fit.
+func (w Width) Usage() int {
+	if !w.Known() {
+		return FallbackTextWidth
+	}
+	return w.Columns
+}
+
+// Prose returns the width prose is wrapped at: the width of the terminal, up
+// to DefaultTextWidth (longer lines are harder to read).
+func (w Width) Prose() uint {
+	if !w.Known() {
+		return FallbackTextWidth
+	}
+	return uint(min(w.Columns, DefaultTextWidth))
+}
+
+// String describes the width and where it came from, for verbose output.
+func (w Width) String() string {
+	if !w.Known() {
+		s := fmt.Sprintf("unknown (wrapping at %d columns, tables unbounded)", FallbackTextWidth)
+		if w.Err != nil {
+			s += ": " + w.Err.Error()
+		}
+		return s
+	}
+	return fmt.Sprintf("%d columns (via %s)", w.Columns, w.Source)
+}
+
+// width is the current Width (see SetWidth). It's nil until a width is set,
+// in which case prose is wrapped at DefaultTextWidth.
+var width atomic.Pointer[Width]
+
+// SetWidth sets the output width for the invocation. A nil width restores
+// the default, i.e. prose wrapped at DefaultTextWidth.
+func SetWidth(w *Width) {
+	width.Store(w)
+}
+
+// CurrentWidth returns the width set by SetWidth, if any.
+func CurrentWidth() (Width, bool) {
+	if w := width.Load(); w != nil {
+		return *w, true
+	}
+	return Width{}, false
+}
+
+// proseWidth returns the width prose is wrapped at.
+func proseWidth() uint {
+	if w, ok := CurrentWidth(); ok {
+		return w.Prose()
+	}
+	return DefaultTextWidth
+}
@@ -0,0 +1,150 @@
+package text_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/text"
+)
+
+// TestDetectWidth validates the width is decided from the config override,
+// COLUMNS or the terminal, and that each way detection fails falls back to
+// wrapping prose at text.FallbackTextWidth.
+func TestDetectWidth(t *testing.T) {
+	defer func(fn func() (int, error)) { text.TerminalWidth = fn }(text.TerminalWidth)
+	defer text.SetWidth(nil)
+
+	errNotTerminal := errors.New("inappropriate ioctl for device")
+	scenarios := []struct {
+		name        string
+		override    int
+		columns     string
+		terminal    int
+		terminalErr error
+		wantColumns int
+		wantSource  string
+		wantProse   uint
+		wantUsage   int
+		wantError   string
+	}{
+		{
+			name:        "terminal",
+			terminal:    100,
+			wantColumns: 100,
+			wantSource:  text.WidthSourceTerminal,
+			wantProse:   100,
+			wantUsage:   100,
+		},
+		{
+			name:        "wide terminal",
+			terminal:    200,
+			wantColumns: 200,
+			wantSource:  text.WidthSourceTerminal,
+			wantProse:   text.DefaultTextWidth,
+			wantUsage:   200,
+		},
+		{
+			name:        "detection error",
+			terminalErr: errNotTerminal,
+			wantSource:  text.WidthSourceUnknown,
+			wantProse:   text.FallbackTextWidth,
+			wantUsage:   text.FallbackTextWidth,
+			wantError:   "inappropriate ioctl",
+		},
+		{
+			name:       "zero columns",
+			wantSource: text.WidthSourceUnknown,
+			wantProse:  text.FallbackTextWidth,
+			wantUsage:  text.FallbackTextWidth,
+			wantError:  "the terminal reported 0 columns",
+		},
+		{
+			name:       "negative columns",
+			terminal:   -1,
+			wantSource: text.WidthSourceUnknown,
+			wantProse:  text.FallbackTextWidth,
+			wantUsage:  text.FallbackTextWidth,
+			wantError:  "the terminal reported -1 columns",
+		},
+		{
+			name:        "COLUMNS",
+			columns:     "60",
+			terminalErr: errNotTerminal,
+			wantColumns: 60,
+			wantSource:  text.WidthSourceColumns,
+			wantProse:   60,
+			wantUsage:   60,
+		},
+		{
+			name:        "invalid COLUMNS",
+			columns:     "wide",
+			terminal:    90,
+			wantColumns: 90,
+			wantSource:  text.WidthSourceTerminal,
+			wantProse:   90,
+			wantUsage:   90,
+		},
+		{
+			name:        "invalid COLUMNS and detection error",
+			columns:     "0",
+			terminalErr: errNotTerminal,
+			wantSource:  text.WidthSourceUnknown,
+			wantProse:   text.FallbackTextWidth,
+			wantUsage:   text.FallbackTextWidth,
+			wantError:   "invalid COLUMNS '0'",
+		},
+		{
+			name:        "config override",
+			override:    70,
+			columns:     "60",
+			terminal:    100,
+			wantColumns: 70,
+			wantSource:  text.WidthSourceConfig,
+			wantProse:   70,
+			wantUsage:   70,
+		},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			text.TerminalWidth = func() (int, error) {
+				return testcase.terminal, testcase.terminalErr
+			}
+			w := text.DetectWidth(testcase.override, testcase.columns)
+			testutil.AssertEqual(t, testcase.wantColumns, w.Columns)
+			testutil.AssertString(t, testcase.wantSource, w.Source)
+			testutil.AssertEqual(t, testcase.wantProse, w.Prose())
+			testutil.AssertEqual(t, testcase.wantUsage, w.Usage())
+			testutil.AssertErrorContains(t, w.Err, testcase.wantError)
+			if testcase.wantError != "" {
+				testutil.AssertStringContains(t, w.String(), testcase.wantError)
+			}
+
+			// Prose is wrapped at the decided width (the last line is shorter).
+			text.SetWidth(&w)
+			var buf bytes.Buffer
+			text.Output(&buf, strings.Repeat("word ", 50))
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			for _, line := range lines[:len(lines)-1] {
+				if len(line) > int(testcase.wantProse) || len(line) < int(testcase.wantProse)-len("word ") {
+					t.Errorf("want lines of at most %d columns, have %d: %q", testcase.wantProse, len(line), line)
+				}
+			}
+		})
+	}
+}
+
+// TestWidthDefault validates prose is wrapped at text.DefaultTextWidth when
+// no width was decided (e.g. in tests).
+func TestWidthDefault(t *testing.T) {
+	text.SetWidth(nil)
+	if _, ok := text.CurrentWidth(); ok {
+		t.Fatal("want no width")
+	}
+	var buf bytes.Buffer
+	text.Output(&buf, strings.Repeat("word ", 50))
+	line, _, _ := strings.Cut(buf.String(), "\n")
+	testutil.AssertEqual(t, text.DefaultTextWidth-1, len(line))
+}
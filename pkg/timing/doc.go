@@ -0,0 +1,3 @@
+// Package timing records how long the phases of a command take (e.g. build,
+// upload, activation) so a summary can be displayed in verbose mode.
+package timing
@@ -0,0 +1,238 @@
+package timing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fastly/cli/pkg/text"
+)
+
+// Registry records the duration of named phases. It's safe for concurrent use
+// and a nil Registry is a no-op.
+type Registry struct {
+	mu    sync.Mutex
+	now   func() time.Time
+	roots []*Span
+	start time.Time
+}
+
+// New returns a Registry that uses now to read the time (time.Now if nil).
+// The total duration is measured from when New is called.
+func New(now func() time.Time) *Registry {
+	if now == nil {
+		now = time.Now
+	}
+	return &Registry{now: now, start: now()}
+}
+
+// Span is a phase of a command. Spans can be nested (see Span.Start).
+type Span struct {
+	children []*Span
+	end      time.Time
+	name     string
+	r        *Registry
+	start    time.Time
+}
+
+// Start begins a top-level phase. The caller must call End on the returned
+// Span once the phase is complete.
+func (r *Registry) Start(name string) *Span {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := &Span{name: name, r: r, start: r.now()}
+	r.roots = append(r.roots, s)
+	return s
+}
+
+// Start begins a phase nested within s.
+func (s *Span) Start(name string) *Span {
+	if s == nil {
+		return nil
+	}
+	s.r.mu.Lock()
+	defer s.r.mu.Unlock()
+	child := &Span{name: name, r: s.r, start: s.r.now()}
+	s.children = append(s.children, child)
+	return child
+}
+
+// End completes the phase. Calling End more than once has no effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.r.mu.Lock()
+	defer s.r.mu.Unlock()
+	if s.end.IsZero() {
+		s.end = s.r.now()
+	}
+}
+
+// Current returns the innermost phase that hasn't ended (the most recently
+// started one at each level), or nil if no phase is in progress.
+func (r *Registry) Current() *Span {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var current *Span
+	spans := r.roots
+	for {
+		var open *Span
+		for i := len(spans) - 1; i >= 0; i-- {
+			if spans[i].end.IsZero() {
+				open = spans[i]
+				break
+			}
+		}
+		if open == nil {
+			return current
+		}
+		current, spans = open, open.children
+	}
+}
+
+// Record is a snapshot of a recorded phase.
+type Record struct {
+	// Span is the phase (e.g. to compare with the result of Current).
+	Span *Span
+	// Parent is the phase it's nested within (nil for a top-level phase).
+	Parent *Span
+	// Name is the name of the phase.
+	Name string
+	// Start is when the phase started.
+	Start time.Time
+	// End is when the phase ended (zero if it hasn't ended).
+	End time.Time
+}
+
+// Records returns a snapshot of the recorded phases, with every phase listed
+// before the phases nested within it.
+func (r *Registry) Records() []Record {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var records []Record
+	var add func(spans []*Span, parent *Span)
+	add = func(spans []*Span, parent *Span) {
+		for _, s := range spans {
+			records = append(records, Record{Span: s, Parent: parent, Name: s.name, Start: s.start, End: s.end})
+			add(s.children, s)
+		}
+	}
+	add(r.roots, nil)
+	return records
+}
+
+// Empty reports whether no phases were recorded.
+func (r *Registry) Empty() bool {
+	if r == nil {
+		return true
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.roots) == 0
+}
+
+// Phase is the summary of a completed (or in progress) phase.
+type Phase struct {
+	Name       string  `json:"name"`
+	DurationMS int64   `json:"duration_ms"`
+	Phases     []Phase `json:"phases,omitempty"`
+
+	duration time.Duration
+}
+
+// Summary is the summary of all recorded phases.
+type Summary struct {
+	Phases  []Phase `json:"phases"`
+	TotalMS int64   `json:"total_ms"`
+
+	total time.Duration
+}
+
+// Summary returns the durations of the recorded phases. Phases that haven't
+// ended are measured up until now.
+func (r *Registry) Summary() Summary {
+	if r == nil {
+		return Summary{}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	total := now.Sub(r.start)
+	return Summary{
+		Phases:  phases(r.roots, now),
+		TotalMS: total.Milliseconds(),
+		total:   total,
+	}
+}
+
+func phases(spans []*Span, now time.Time) []Phase {
+	var ps []Phase
+	for _, s := range spans {
+		end := s.end
+		if end.IsZero() {
+			end = now
+		}
+		d := end.Sub(s.start)
+		ps = append(ps, Phase{
+			Name:       s.name,
+			DurationMS: d.Milliseconds(),
+			Phases:     phases(s.children, now),
+			duration:   d,
+		})
+	}
+	return ps
+}
+
+// Print writes a table of the phase durations, with nested phases indented,
+// followed by the total duration.
+func (r *Registry) Print(w io.Writer) {
+	s := r.Summary()
+	text.Output(w, "Timings:")
+	text.Break(w)
+	t := text.NewTable(w)
+	t.AddHeader("PHASE", "DURATION")
+	var add func(ps []Phase, depth int)
+	add = func(ps []Phase, depth int) {
+		for _, p := range ps {
+			t.AddLine(strings.Repeat("  ", depth)+p.Name, format(p.duration))
+			add(p.Phases, depth+1)
+		}
+	}
+	add(s.Phases, 0)
+	t.AddLine("TOTAL", format(s.total))
+	t.Print()
+}
+
+// WriteJSON writes the summary as a JSON object with a "timings" key.
+func (r *Registry) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Timings Summary `json:"timings"`
+	}{r.Summary()})
+}
+
+// format renders d with a precision suited to its magnitude.
+func format(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(10 * time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(time.Millisecond).String()
+	default:
+		return fmt.Sprintf("%dµs", d.Microseconds())
+	}
+}
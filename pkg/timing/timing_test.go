@@ -0,0 +1,189 @@
+package timing_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/timing"
+)
+
+// fakeClock only moves forward when advanced.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newRegistry() (*timing.Registry, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	return timing.New(clock.Now), clock
+}
+
+func TestRegistry(t *testing.T) {
+	r, clock := newRegistry()
+
+	build := r.Start("build")
+	clock.Advance(2500 * time.Millisecond)
+	build.End()
+
+	deploy := r.Start("deploy")
+	hash := deploy.Start("package hash")
+	clock.Advance(15 * time.Millisecond)
+	hash.End()
+	upload := deploy.Start("upload")
+	clock.Advance(time.Second)
+	upload.End()
+	upload.End() // no-op
+	activation := deploy.Start("activation")
+	poll := activation.Start("poll")
+	clock.Advance(300 * time.Millisecond)
+	poll.End()
+	activation.End()
+	deploy.End()
+	clock.Advance(5 * time.Millisecond)
+
+	var out bytes.Buffer
+	r.Print(&out)
+	want := `Timings:
+
+PHASE           DURATION
+build           2.5s
+deploy          1.32s
+  package hash  15ms
+  upload        1s
+  activation    300ms
+    poll        300ms
+TOTAL           3.82s
+`
+	testutil.AssertString(t, want, out.String())
+
+	out.Reset()
+	testutil.AssertNoError(t, r.WriteJSON(&out))
+	wantJSON := `{
+  "timings": {
+    "phases": [
+      {
+        "name": "build",
+        "duration_ms": 2500
+      },
+      {
+        "name": "deploy",
+        "duration_ms": 1315,
+        "phases": [
+          {
+            "name": "package hash",
+            "duration_ms": 15
+          },
+          {
+            "name": "upload",
+            "duration_ms": 1000
+          },
+          {
+            "name": "activation",
+            "duration_ms": 300,
+            "phases": [
+              {
+                "name": "poll",
+                "duration_ms": 300
+              }
+            ]
+          }
+        ]
+      }
+    ],
+    "total_ms": 3820
+  }
+}
+`
+	testutil.AssertString(t, wantJSON, out.String())
+}
+
+func TestRegistryUnfinishedPhase(t *testing.T) {
+	r, clock := newRegistry()
+	r.Start("upload")
+	clock.Advance(time.Second)
+
+	s := r.Summary()
+	testutil.AssertEqual(t, int64(1000), s.Phases[0].DurationMS)
+	testutil.AssertEqual(t, int64(1000), s.TotalMS)
+}
+
+func TestRegistryRecords(t *testing.T) {
+	r, clock := newRegistry()
+	testutil.AssertBool(t, true, r.Current() == nil)
+
+	build := r.Start("build")
+	clock.Advance(time.Second)
+	build.End()
+	deploy := r.Start("deploy")
+	upload := deploy.Start("upload")
+	testutil.AssertBool(t, true, r.Current() == upload)
+	clock.Advance(time.Second)
+	upload.End()
+	testutil.AssertBool(t, true, r.Current() == deploy)
+
+	records := r.Records()
+	testutil.AssertEqual(t, 3, len(records))
+	for i, want := range []struct {
+		name   string
+		span   *timing.Span
+		parent *timing.Span
+		ended  bool
+	}{
+		{name: "build", span: build, ended: true},
+		{name: "deploy", span: deploy},
+		{name: "upload", span: upload, parent: deploy, ended: true},
+	} {
+		testutil.AssertString(t, want.name, records[i].Name)
+		testutil.AssertBool(t, true, records[i].Span == want.span)
+		testutil.AssertBool(t, true, records[i].Parent == want.parent)
+		testutil.AssertBool(t, want.ended, !records[i].End.IsZero())
+	}
+	testutil.AssertEqual(t, time.Second, records[2].End.Sub(records[2].Start))
+}
+
+func TestRegistryConcurrent(t *testing.T) {
+	r, clock := newRegistry()
+	parent := r.Start("parent")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s := parent.Start("child")
+			clock.Advance(time.Millisecond)
+			s.End()
+		}()
+	}
+	wg.Wait()
+	parent.End()
+
+	s := r.Summary()
+	testutil.AssertEqual(t, 10, len(s.Phases[0].Phases))
+	testutil.AssertEqual(t, int64(10), s.Phases[0].DurationMS)
+}
+
+func TestNilRegistry(t *testing.T) {
+	var r *timing.Registry
+	s := r.Start("build")
+	s.Start("compile").End()
+	s.End()
+	testutil.AssertBool(t, true, r.Empty())
+	testutil.AssertBool(t, true, r.Current() == nil)
+	testutil.AssertEqual(t, 0, len(r.Records()))
+}
@@ -0,0 +1,4 @@
+// Package tracing records a trace of a command (its phases and API requests)
+// and exports it in the OpenTelemetry (OTLP/JSON) format, so slow or failing
+// invocations can be inspected with standard tracing tools.
+package tracing
@@ -0,0 +1,385 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fastly/cli/pkg/api"
+	"github.com/fastly/cli/pkg/timing"
+)
+
+// TraceparentHeader is the W3C Trace Context header that propagates the trace
+// and span IDs of an API request.
+const TraceparentHeader = "traceparent"
+
+// ScopeName identifies the CLI as the instrumentation scope of the spans.
+const ScopeName = "github.com/fastly/cli"
+
+// ServiceName is the OpenTelemetry service name of the CLI.
+const ServiceName = "fastly-cli"
+
+// TracesPath is the path of the OTLP/HTTP traces endpoint, used when the
+// collector endpoint doesn't specify one.
+const TracesPath = "/v1/traces"
+
+// ExportTimeout bounds sending a trace to a collector.
+const ExportTimeout = 5 * time.Second
+
+// Span kinds and status codes, as defined by the OTLP protobuf schema.
+const (
+	kindInternal = 1
+	kindClient   = 3
+
+	statusUnset = 0
+	statusError = 2
+)
+
+type (
+	traceID [16]byte
+	spanID  [8]byte
+)
+
+// call is an API request made during the command.
+type call struct {
+	end    time.Time
+	err    error
+	host   string
+	id     spanID
+	method string
+	parent *timing.Span
+	path   string
+	start  time.Time
+	status int
+}
+
+// Tracer records the spans of a single command invocation: the command
+// itself, the phases recorded by the timing registry, and the API requests
+// sent via a Transport. Only the request method, path and status are
+// recorded (never the headers or bodies). A nil Tracer is a no-op.
+type Tracer struct {
+	mu      sync.Mutex
+	calls   []call
+	end     time.Time
+	err     error
+	name    string
+	now     func() time.Time
+	root    spanID
+	start   time.Time
+	timings *timing.Registry
+	trace   traceID
+	version string
+}
+
+// New starts the trace of the named command (e.g. "compute deploy"). The
+// phases recorded by timings are exported as children of the command's span,
+// and now is used to read the time (time.Now if nil).
+func New(name, version string, timings *timing.Registry, now func() time.Time) *Tracer {
+	if now == nil {
+		now = time.Now
+	}
+	t := &Tracer{name: name, now: now, start: now(), timings: timings, version: version}
+	random(t.trace[:])
+	random(t.root[:])
+	return t
+}
+
+// TraceID returns the hex encoded trace ID.
+func (t *Tracer) TraceID() string {
+	if t == nil {
+		return ""
+	}
+	return hex.EncodeToString(t.trace[:])
+}
+
+// End completes the command's span, recording the error (if any) it
+// returned. Calling End more than once has no effect.
+func (t *Tracer) End(err error) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.end.IsZero() {
+		t.end = t.now()
+		t.err = err
+	}
+}
+
+// Transport is a http.RoundTripper that records a span for each request and
+// propagates it to the API via the traceparent header. It's a no-op until
+// Tracer is set.
+type Transport struct {
+	// Base is the underlying http.RoundTripper (http.DefaultTransport if nil).
+	Base http.RoundTripper
+	// Tracer records the spans of the requests.
+	Tracer *Tracer
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if t.Tracer == nil {
+		return base.RoundTrip(req)
+	}
+
+	c := call{
+		host:   req.URL.Host,
+		method: req.Method,
+		parent: t.Tracer.timings.Current(),
+		path:   req.URL.Path,
+		start:  t.Tracer.now(),
+	}
+	random(c.id[:])
+
+	// NOTE: A RoundTripper mustn't modify the caller's request.
+	req = req.Clone(req.Context())
+	req.Header.Set(TraceparentHeader, fmt.Sprintf("00-%x-%x-01", t.Tracer.trace, c.id))
+
+	resp, err := base.RoundTrip(req)
+	c.end = t.Tracer.now()
+	c.err = err
+	if err == nil {
+		c.status = resp.StatusCode
+	}
+	t.Tracer.mu.Lock()
+	t.Tracer.calls = append(t.Tracer.calls, c)
+	t.Tracer.mu.Unlock()
+	return resp, err
+}
+
+// WriteFile writes the trace to path as an OTLP/JSON document.
+func (t *Tracer) WriteFile(path string) error {
+	data, err := t.JSON()
+	if err != nil {
+		return err
+	}
+	// #nosec G306
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("error writing trace to %s: %w", path, err)
+	}
+	return nil
+}
+
+// Send posts the trace to the OTLP/HTTP endpoint of a collector (e.g.
+// http://localhost:4318). TracesPath is used if the endpoint has no path.
+func (t *Tracer) Send(ctx context.Context, client api.HTTPClient, endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid trace endpoint '%s': the URL of a collector's OTLP/HTTP endpoint is expected (e.g. http://localhost:4318)", endpoint)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = TracesPath
+	}
+	data, err := t.JSON()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ExportTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("error creating trace export request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending trace to %s: %w", u, err)
+	}
+	defer resp.Body.Close() // #nosec G307
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("error sending trace to %s: unexpected status: %s", u, resp.Status)
+	}
+	return nil
+}
+
+// JSON returns the trace as an OTLP/JSON document. A command that hasn't
+// ended, and phases that haven't ended, are exported as ending now.
+func (t *Tracer) JSON() ([]byte, error) {
+	if t == nil {
+		return nil, fmt.Errorf("no trace was recorded")
+	}
+	records := t.timings.Records()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	end := t.end
+	if end.IsZero() {
+		end = now
+	}
+
+	root := otlpSpan{
+		TraceID:    hex.EncodeToString(t.trace[:]),
+		SpanID:     hex.EncodeToString(t.root[:]),
+		Name:       t.name,
+		Kind:       kindInternal,
+		Start:      nanos(t.start),
+		End:        nanos(end),
+		Attributes: []attribute{stringAttribute("cli.command", t.name)},
+		Status:     errStatus(t.err),
+	}
+	spans := []otlpSpan{root}
+
+	// The phases are assigned span IDs in the order they were recorded, and
+	// a phase is always recorded after the phase it's nested within.
+	ids := make(map[*timing.Span]spanID, len(records))
+	parentID := func(s *timing.Span) string {
+		if id, ok := ids[s]; ok {
+			return hex.EncodeToString(id[:])
+		}
+		return root.SpanID
+	}
+	for _, r := range records {
+		var id spanID
+		random(id[:])
+		ids[r.Span] = id
+		phaseEnd := r.End
+		if phaseEnd.IsZero() {
+			phaseEnd = now
+		}
+		spans = append(spans, otlpSpan{
+			TraceID:      root.TraceID,
+			SpanID:       hex.EncodeToString(id[:]),
+			ParentSpanID: parentID(r.Parent),
+			Name:         r.Name,
+			Kind:         kindInternal,
+			Start:        nanos(r.Start),
+			End:          nanos(phaseEnd),
+		})
+	}
+
+	for _, c := range t.calls {
+		attributes := []attribute{
+			stringAttribute("http.request.method", c.method),
+			stringAttribute("server.address", c.host),
+			stringAttribute("url.path", c.path),
+		}
+		status := errStatus(c.err)
+		if c.err == nil {
+			attributes = append(attributes, intAttribute("http.response.status_code", c.status))
+			if c.status >= http.StatusBadRequest {
+				status = spanStatus{Code: statusError, Message: http.StatusText(c.status)}
+			}
+		}
+		spans = append(spans, otlpSpan{
+			TraceID:      root.TraceID,
+			SpanID:       hex.EncodeToString(c.id[:]),
+			ParentSpanID: parentID(c.parent),
+			Name:         c.method + " " + c.path,
+			Kind:         kindClient,
+			Start:        nanos(c.start),
+			End:          nanos(c.end),
+			Attributes:   attributes,
+			Status:       status,
+		})
+	}
+
+	return json.Marshal(document{ResourceSpans: []resourceSpans{{
+		Resource: resource{Attributes: []attribute{
+			stringAttribute("service.name", ServiceName),
+			stringAttribute("service.version", t.version),
+		}},
+		ScopeSpans: []scopeSpans{{
+			Scope: scope{Name: ScopeName, Version: t.version},
+			Spans: spans,
+		}},
+	}}})
+}
+
+// document is the OTLP/JSON encoding of an ExportTraceServiceRequest.
+type document struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []attribute `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type scope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// otlpSpan is the OTLP/JSON encoding of a span. IDs are hex encoded and, as
+// with every 64-bit integer in the protobuf JSON mapping, times are strings.
+type otlpSpan struct {
+	TraceID      string      `json:"traceId"`
+	SpanID       string      `json:"spanId"`
+	ParentSpanID string      `json:"parentSpanId,omitempty"`
+	Name         string      `json:"name"`
+	Kind         int         `json:"kind"`
+	Start        string      `json:"startTimeUnixNano"`
+	End          string      `json:"endTimeUnixNano"`
+	Attributes   []attribute `json:"attributes,omitempty"`
+	Status       spanStatus  `json:"status"`
+}
+
+type spanStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type attribute struct {
+	Key   string         `json:"key"`
+	Value attributeValue `json:"value"`
+}
+
+type attributeValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+}
+
+func stringAttribute(key, value string) attribute {
+	return attribute{Key: key, Value: attributeValue{StringValue: &value}}
+}
+
+func intAttribute(key string, value int) attribute {
+	v := strconv.Itoa(value)
+	return attribute{Key: key, Value: attributeValue{IntValue: &v}}
+}
+
+// errStatus returns the status of a span that returned err.
+func errStatus(err error) spanStatus {
+	if err == nil {
+		return spanStatus{Code: statusUnset}
+	}
+	return spanStatus{Code: statusError, Message: err.Error()}
+}
+
+func nanos(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// random fills b with random bytes.
+//
+// NOTE: crypto/rand doesn't fail on supported platforms, and an ID that isn't
+// random only makes the trace harder to tell apart from others.
+func random(b []byte) {
+	_, _ = rand.Read(b)
+}
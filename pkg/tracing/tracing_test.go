@@ -0,0 +1,232 @@
+package tracing_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/timing"
+	"github.com/fastly/cli/pkg/tracing"
+)
+
+// fakeClock only moves forward when advanced.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// mockTransport records the requests it receives and responds with the given
+// status (or error).
+type mockTransport struct {
+	clock    *fakeClock
+	err      error
+	requests []*http.Request
+	status   int
+}
+
+func (m *mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	m.requests = append(m.requests, req)
+	m.clock.Advance(100 * time.Millisecond)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &http.Response{StatusCode: m.status, Body: http.NoBody, Header: http.Header{}, Request: req}, nil
+}
+
+type span struct {
+	TraceID      string `json:"traceId"`
+	SpanID       string `json:"spanId"`
+	ParentSpanID string `json:"parentSpanId"`
+	Name         string `json:"name"`
+	Kind         int    `json:"kind"`
+	Start        string `json:"startTimeUnixNano"`
+	End          string `json:"endTimeUnixNano"`
+	Attributes   []struct {
+		Key   string `json:"key"`
+		Value struct {
+			StringValue string `json:"stringValue"`
+			IntValue    string `json:"intValue"`
+		} `json:"value"`
+	} `json:"attributes"`
+	Status struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"status"`
+}
+
+func (s span) attribute(key string) string {
+	for _, a := range s.Attributes {
+		if a.Key == key {
+			return a.Value.StringValue + a.Value.IntValue
+		}
+	}
+	return ""
+}
+
+func decode(t *testing.T, data []byte) []span {
+	t.Helper()
+	var doc struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []span `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc.ResourceSpans[0].ScopeSpans[0].Spans
+}
+
+var traceparent = regexp.MustCompile(`^00-([0-9a-f]{32})-([0-9a-f]{16})-01$`)
+
+func TestTransport(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	timings := timing.New(clock.Now)
+	tracer := tracing.New("compute deploy", "1.0.0", timings, clock.Now)
+	mock := &mockTransport{clock: clock, status: http.StatusOK}
+	transport := &tracing.Transport{Base: mock, Tracer: tracer}
+
+	// A request outside of any phase, and a failing request within a phase.
+	req := httptest.NewRequest(http.MethodGet, "https://api.fastly.com/service/123?page=2", nil)
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertString(t, "", req.Header.Get(tracing.TraceparentHeader))
+
+	deploy := timings.Start("deploy")
+	upload := deploy.Start("upload")
+	mock.status = http.StatusUnprocessableEntity
+	if _, err := transport.RoundTrip(httptest.NewRequest(http.MethodPut, "https://api.fastly.com/service/123/version/4/package", nil)); err != nil {
+		t.Fatal(err)
+	}
+	upload.End()
+	mock.err = errors.New("connection refused")
+	if _, err := transport.RoundTrip(httptest.NewRequest(http.MethodPut, "https://api.fastly.com/service/123/version/4/activate", nil)); err == nil {
+		t.Fatal("want the transport error")
+	}
+	deploy.End()
+	tracer.End(errors.New("failed to activate"))
+
+	var sent []string
+	for _, r := range mock.requests {
+		m := traceparent.FindStringSubmatch(r.Header.Get(tracing.TraceparentHeader))
+		if m == nil {
+			t.Fatalf("invalid traceparent header: %q", r.Header.Get(tracing.TraceparentHeader))
+		}
+		testutil.AssertString(t, tracer.TraceID(), m[1])
+		sent = append(sent, m[2])
+	}
+
+	data, err := tracer.JSON()
+	testutil.AssertNoError(t, err)
+	spans := decode(t, data)
+	testutil.AssertEqual(t, 6, len(spans))
+	byName := make(map[string]span)
+	for _, s := range spans {
+		testutil.AssertString(t, tracer.TraceID(), s.TraceID)
+		byName[s.Name] = s
+	}
+
+	root := byName["compute deploy"]
+	testutil.AssertString(t, "", root.ParentSpanID)
+	testutil.AssertEqual(t, 2, root.Status.Code)
+	testutil.AssertString(t, "failed to activate", root.Status.Message)
+	testutil.AssertString(t, root.SpanID, byName["deploy"].ParentSpanID)
+	testutil.AssertString(t, byName["deploy"].SpanID, byName["upload"].ParentSpanID)
+
+	// The span of each request has the ID propagated in its header, and is
+	// nested within the phase it was sent during.
+	for i, want := range []struct {
+		name   string
+		parent string
+		status string
+		code   int
+	}{
+		{name: "GET /service/123", parent: root.SpanID, status: "200"},
+		{name: "PUT /service/123/version/4/package", parent: byName["upload"].SpanID, status: "422", code: 2},
+		{name: "PUT /service/123/version/4/activate", parent: byName["deploy"].SpanID, code: 2},
+	} {
+		s, ok := byName[want.name]
+		if !ok {
+			t.Fatalf("no span named %q", want.name)
+		}
+		testutil.AssertString(t, sent[i], s.SpanID)
+		testutil.AssertString(t, want.parent, s.ParentSpanID)
+		testutil.AssertEqual(t, 3, s.Kind)
+		testutil.AssertString(t, want.status, s.attribute("http.response.status_code"))
+		testutil.AssertString(t, "api.fastly.com", s.attribute("server.address"))
+		testutil.AssertEqual(t, want.code, s.Status.Code)
+		testutil.AssertStringDoesntContain(t, s.attribute("url.path"), "page")
+	}
+	testutil.AssertString(t, "1704067200100000000", byName["GET /service/123"].End)
+}
+
+func TestTransportWithoutTracer(t *testing.T) {
+	mock := &mockTransport{clock: &fakeClock{}, status: http.StatusOK}
+	transport := &tracing.Transport{Base: mock}
+	if _, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "https://api.fastly.com/service", nil)); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertString(t, "", mock.requests[0].Header.Get(tracing.TraceparentHeader))
+}
+
+func TestExport(t *testing.T) {
+	tracer := tracing.New("service list", "1.0.0", nil, nil)
+	tracer.End(nil)
+
+	path := filepath.Join(t.TempDir(), "trace.json")
+	testutil.AssertNoError(t, tracer.WriteFile(path))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spans := decode(t, data)
+	testutil.AssertEqual(t, 1, len(spans))
+	testutil.AssertString(t, "service list", spans[0].Name)
+
+	var (
+		gotPath string
+		gotBody []byte
+		status  = http.StatusOK
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	testutil.AssertNoError(t, tracer.Send(context.Background(), srv.Client(), srv.URL))
+	testutil.AssertString(t, tracing.TracesPath, gotPath)
+	testutil.AssertString(t, string(data), string(gotBody))
+
+	testutil.AssertNoError(t, tracer.Send(context.Background(), srv.Client(), srv.URL+"/custom/traces"))
+	testutil.AssertString(t, "/custom/traces", gotPath)
+
+	status = http.StatusBadRequest
+	testutil.AssertErrorContains(t, tracer.Send(context.Background(), srv.Client(), srv.URL), "unexpected status: 400 Bad Request")
+	testutil.AssertErrorContains(t, tracer.Send(context.Background(), srv.Client(), "localhost:4318"), "invalid trace endpoint")
+}
@@ -0,0 +1,3 @@
+// Package wasm parses the sections of a WebAssembly module without executing
+// it, so a built binary can be inspected.
+package wasm
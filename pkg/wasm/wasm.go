@@ -0,0 +1,329 @@
+package wasm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Magic is the magic number a module starts with (the string '\0asm').
+var Magic = []byte{0x00, 0x61, 0x73, 0x6d}
+
+// version is the binary format version of a core module.
+var version = []byte{0x01, 0x00, 0x00, 0x00}
+
+// headerSize is the size of the magic number and version.
+const headerSize = 8
+
+// The IDs of the sections used when inspecting a module.
+//
+// Reference:
+// https://webassembly.github.io/spec/core/binary/modules.html#sections
+const (
+	SectionCustom byte = 0
+	SectionImport byte = 2
+	SectionExport byte = 7
+)
+
+// sectionNames are the names of the known section IDs.
+var sectionNames = []string{
+	"custom", "type", "import", "function", "table", "memory", "global",
+	"export", "start", "element", "code", "data", "datacount", "tag",
+}
+
+// The kinds of an import or export.
+const (
+	kindFunc   byte = 0x00
+	kindTable  byte = 0x01
+	kindMemory byte = 0x02
+	kindGlobal byte = 0x03
+	kindTag    byte = 0x04
+)
+
+// ErrComponent indicates the binary is a component rather than a core module.
+var ErrComponent = errors.New("the binary is a WebAssembly component, only core modules are supported")
+
+// Section is a section of a module.
+type Section struct {
+	// ID is the section ID (SectionCustom for a custom section).
+	ID byte
+	// Name is the name of a custom section, or the name of the section ID
+	// (e.g. "code").
+	Name string
+	// Offset is where the section starts in the module.
+	Offset int
+	// Size is the size of the section, including its ID and size fields.
+	Size int
+}
+
+// Custom indicates the section is a custom section.
+func (s Section) Custom() bool {
+	return s.ID == SectionCustom
+}
+
+// Debug indicates the section is a custom section holding debug information
+// (DWARF, function names or source maps), which isn't needed to run the
+// module.
+func (s Section) Debug() bool {
+	if !s.Custom() {
+		return false
+	}
+	return strings.HasPrefix(s.Name, ".debug_") ||
+		s.Name == "name" ||
+		s.Name == "sourceMappingURL" ||
+		s.Name == "external_debug_info"
+}
+
+// Import is a function imported by a module.
+type Import struct {
+	// Module is the import module (e.g. "wasi_snapshot_preview1").
+	Module string
+	// Name is the function name within the import module.
+	Name string
+}
+
+// Module is the result of parsing a module.
+type Module struct {
+	// Size is the size of the module.
+	Size int
+	// Sections are the sections of the module, in order.
+	Sections []Section
+	// Imports are the imported functions, in order.
+	Imports []Import
+	// Exports are the names of the exported functions, in order.
+	Exports []string
+}
+
+// DebugSize returns the total size of the debug sections.
+func (m *Module) DebugSize() int {
+	var n int
+	for _, s := range m.Sections {
+		if s.Debug() {
+			n += s.Size
+		}
+	}
+	return n
+}
+
+// Parse parses the sections of a module, along with the contents of the
+// import and export sections. Other sections aren't decoded.
+func Parse(data []byte) (*Module, error) {
+	if len(data) < headerSize || !bytes.Equal(data[:4], Magic) {
+		return nil, errors.New("not a WebAssembly binary (unexpected magic number)")
+	}
+	if !bytes.Equal(data[4:headerSize], version) {
+		// A component uses a different version and 'layer' field.
+		if data[6] == 0x01 && data[7] == 0x00 {
+			return nil, ErrComponent
+		}
+		return nil, fmt.Errorf("unsupported WebAssembly version %#v", data[4:headerSize])
+	}
+
+	m := &Module{Size: len(data)}
+	r := &reader{data: data, pos: headerSize}
+	for !r.done() {
+		offset := r.pos
+		id, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		size, err := r.u32()
+		if err != nil {
+			return nil, fmt.Errorf("section at offset %d: %w", offset, err)
+		}
+		payload, err := r.bytes(int(size))
+		if err != nil {
+			return nil, fmt.Errorf("section at offset %d: %w", offset, err)
+		}
+		s := Section{ID: id, Offset: offset, Size: r.pos - offset}
+
+		p := &reader{data: payload}
+		switch {
+		case id == SectionCustom:
+			s.Name, err = p.name()
+		case int(id) < len(sectionNames):
+			s.Name = sectionNames[id]
+		default:
+			err = fmt.Errorf("unknown section ID %d", id)
+		}
+		switch id {
+		case SectionImport:
+			m.Imports, err = p.imports()
+		case SectionExport:
+			m.Exports, err = p.exports()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s section at offset %d: %w", s.Name, offset, err)
+		}
+		m.Sections = append(m.Sections, s)
+	}
+	return m, nil
+}
+
+// StripDebug returns the module without its debug sections (see
+// Section.Debug), along with the sections that were removed.
+func StripDebug(data []byte) ([]byte, []Section, error) {
+	m, err := Parse(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	stripped := make([]byte, 0, len(data)-m.DebugSize())
+	stripped = append(stripped, data[:headerSize]...)
+	var removed []Section
+	for _, s := range m.Sections {
+		if s.Debug() {
+			removed = append(removed, s)
+			continue
+		}
+		stripped = append(stripped, data[s.Offset:s.Offset+s.Size]...)
+	}
+	return stripped, removed, nil
+}
+
+// reader decodes the values of the binary format.
+type reader struct {
+	data []byte
+	pos  int
+}
+
+var errUnexpectedEnd = errors.New("unexpected end of data")
+
+func (r *reader) done() bool {
+	return r.pos >= len(r.data)
+}
+
+func (r *reader) byte() (byte, error) {
+	if r.done() {
+		return 0, errUnexpectedEnd
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *reader) bytes(n int) ([]byte, error) {
+	if n < 0 || n > len(r.data)-r.pos {
+		return nil, errUnexpectedEnd
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+// u32 decodes an unsigned LEB128 integer of up to 32 bits.
+func (r *reader) u32() (uint32, error) {
+	var v uint32
+	for shift := 0; shift < 35; shift += 7 {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		v |= uint32(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return v, nil
+		}
+	}
+	return 0, errors.New("integer is too large")
+}
+
+// name decodes a length-prefixed UTF-8 string.
+func (r *reader) name() (string, error) {
+	n, err := r.u32()
+	if err != nil {
+		return "", err
+	}
+	b, err := r.bytes(int(n))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// limits skips the limits of a table or memory type.
+func (r *reader) limits() error {
+	flags, err := r.byte()
+	if err != nil {
+		return err
+	}
+	if _, err := r.u32(); err != nil {
+		return err
+	}
+	if flags&0x01 != 0 {
+		_, err = r.u32()
+	}
+	return err
+}
+
+// imports decodes an import section, returning the function imports.
+func (r *reader) imports() ([]Import, error) {
+	count, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	var imports []Import
+	for i := uint32(0); i < count; i++ {
+		module, err := r.name()
+		if err != nil {
+			return nil, err
+		}
+		name, err := r.name()
+		if err != nil {
+			return nil, err
+		}
+		kind, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case kindFunc:
+			_, err = r.u32() // type index
+			imports = append(imports, Import{Module: module, Name: name})
+		case kindTable:
+			if _, err = r.byte(); err == nil { // reference type
+				err = r.limits()
+			}
+		case kindMemory:
+			err = r.limits()
+		case kindGlobal:
+			_, err = r.bytes(2) // value type and mutability
+		case kindTag:
+			if _, err = r.byte(); err == nil { // attribute
+				_, err = r.u32()
+			}
+		default:
+			err = fmt.Errorf("unknown import kind %#x", kind)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return imports, nil
+}
+
+// exports decodes an export section, returning the names of the exported
+// functions.
+func (r *reader) exports() ([]string, error) {
+	count, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	var exports []string
+	for i := uint32(0); i < count; i++ {
+		name, err := r.name()
+		if err != nil {
+			return nil, err
+		}
+		kind, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := r.u32(); err != nil { // index
+			return nil, err
+		}
+		if kind == kindFunc {
+			exports = append(exports, name)
+		}
+	}
+	return exports, nil
+}
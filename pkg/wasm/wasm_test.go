@@ -0,0 +1,38 @@
+package wasm_test
+
+import (
+	"testing"
+
+	"github.com/fastly/cli/pkg/testutil"
+	"github.com/fastly/cli/pkg/wasm"
+)
+
+func TestParseInvalid(t *testing.T) {
+	header := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	scenarios := []struct {
+		name      string
+		data      []byte
+		wantError string
+	}{
+		{name: "empty", wantError: "not a WebAssembly binary"},
+		{name: "magic", data: []byte("#!/bin/sh"), wantError: "not a WebAssembly binary"},
+		{name: "version", data: []byte{0x00, 0x61, 0x73, 0x6d, 0x02, 0x00, 0x00, 0x00}, wantError: "unsupported WebAssembly version"},
+		{name: "truncated section", data: append(header, 0x0a, 0x05, 0x01), wantError: "section at offset 8: unexpected end of data"},
+		{name: "unknown section", data: append(header, 0x20, 0x00), wantError: "unknown section ID 32"},
+		{name: "truncated import", data: append(header, 0x02, 0x02, 0x01, 0x03), wantError: "import section at offset 8: unexpected end of data"},
+	}
+	for _, testcase := range scenarios {
+		t.Run(testcase.name, func(t *testing.T) {
+			_, err := wasm.Parse(testcase.data)
+			testutil.AssertErrorContains(t, err, testcase.wantError)
+		})
+	}
+}
+
+func TestStripDebugWithoutDebugSections(t *testing.T) {
+	data := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, 0x01, 0x04, 0x01, 0x60, 0x00, 0x00}
+	stripped, removed, err := wasm.StripDebug(data)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, data, stripped)
+	testutil.AssertEqual(t, 0, len(removed))
+}